@@ -11,7 +11,7 @@ import (
 
 // newTestChainBlockContext obtains a new vm.BlockContext that is tailored to provide data from a TestChain.
 func newTestChainBlockContext(testChain *TestChain, header *types.Header) vm.BlockContext {
-	return vm.BlockContext{
+	blockContext := vm.BlockContext{
 		CanTransfer: core.CanTransfer,
 		Transfer:    core.Transfer,
 		GetHash: func(n uint64) common.Hash {
@@ -26,6 +26,11 @@ func newTestChainBlockContext(testChain *TestChain, header *types.Header) vm.Blo
 		Difficulty:  new(big.Int).Set(header.Difficulty),
 		BaseFee:     new(big.Int).Set(testChain.Head().Header.BaseFee),
 		GasLimit:    header.GasLimit,
-		Random:      &header.MixDigest,
 	}
+
+	// Blocks only provide prevrandao after the merge. Its presence is what enables post-merge rules in the EVM.
+	if testChain.chainConfig.TerminalTotalDifficultyPassed {
+		blockContext.Random = &header.MixDigest
+	}
+	return blockContext
 }
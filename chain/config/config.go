@@ -14,9 +14,33 @@ type TestChainConfig struct {
 	// CheatCodeConfig indicates the configuration for EVM cheat codes to use.
 	CheatCodeConfig CheatCodeConfig `json:"cheatCodes"`
 
+	// HardFork describes the Ethereum hard fork whose rules the chain applies from genesis, including its opcodes and
+	// gas accounting (e.g. cold and warm access costs and capped gas refunds).
+	HardFork HardFork `json:"hardFork"`
+
+	// Coinbase describes the coinbase address of the blocks produced by the chain.
+	Coinbase common.Address `json:"coinbase"`
+
+	// PrevrandaoMode describes how the prevrandao value of the blocks produced by the chain is derived. This only takes
+	// effect if the HardFork succeeds the merge.
+	PrevrandaoMode PrevrandaoMode `json:"prevrandaoMode"`
+
+	// Prevrandao describes the prevrandao value of the blocks produced by the chain if PrevrandaoMode is
+	// PrevrandaoModeFixed, or the seed the values are derived from if it is PrevrandaoModePseudoRandom.
+	Prevrandao common.Hash `json:"prevrandao"`
+
 	// SkipAccountChecks skips account pre-checks like nonce validation and disallowing non-EOA tx senders (this is done in eth_call, for instance).
 	SkipAccountChecks bool `json:"skipAccountChecks"`
 
+	// TransactionTimeout describes the maximum wall-clock time, in milliseconds, which a transaction may spend
+	// executing before it is aborted. Aborted transactions are not included in the chain and their state changes are
+	// discarded. If zero, transactions are never aborted.
+	TransactionTimeout uint64 `json:"transactionTimeout"`
+
+	// StopSequenceOnTransactionTimeout describes whether a call sequence should stop executing when one of its calls
+	// is aborted due to TransactionTimeout. If false, the aborted call is skipped and the call sequence continues.
+	StopSequenceOnTransactionTimeout bool `json:"stopSequenceOnTransactionTimeout"`
+
 	// ContractAddressOverrides describes contracts that are going to be deployed at deterministic addresses
 	ContractAddressOverrides map[common.Hash]common.Address `json:"contractAddressOverrides,omitempty"`
 
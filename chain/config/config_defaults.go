@@ -1,5 +1,7 @@
 package config
 
+import "github.com/ethereum/go-ethereum/common"
+
 // DefaultTestChainConfig obtains a default configuration for a chain.TestChain.
 // Returns a TestChainConfig populated with default values.
 func DefaultTestChainConfig() (*TestChainConfig, error) {
@@ -10,7 +12,13 @@ func DefaultTestChainConfig() (*TestChainConfig, error) {
 			CheatCodesEnabled: true,
 			EnableFFI:         false,
 		},
-		SkipAccountChecks: true,
+		HardFork:                         HardForkCancun,
+		Coinbase:                         common.Address{},
+		PrevrandaoMode:                   PrevrandaoModeParentHash,
+		Prevrandao:                       common.Hash{},
+		SkipAccountChecks:                true,
+		TransactionTimeout:               0,
+		StopSequenceOnTransactionTimeout: false,
 		ForkConfig: ForkConfig{
 			ForkModeEnabled: false,
 			RpcUrl:          "",
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/params"
+	"golang.org/x/exp/slices"
+)
+
+// HardFork describes an Ethereum hard fork whose rules the test chain applies, such as its opcodes and gas accounting.
+type HardFork string
+
+const (
+	// HardForkIstanbul describes the Istanbul hard fork, which predates access lists (EIP-2929) and reduced gas refunds
+	// (EIP-3529).
+	HardForkIstanbul HardFork = "istanbul"
+
+	// HardForkBerlin describes the Berlin hard fork, which introduced cold and warm account and storage access costs
+	// (EIP-2929).
+	HardForkBerlin HardFork = "berlin"
+
+	// HardForkLondon describes the London hard fork, which introduced base fees (EIP-1559) and capped gas refunds to a
+	// fifth of the gas used (EIP-3529).
+	HardForkLondon HardFork = "london"
+
+	// HardForkParis describes the Paris hard fork (the merge), which replaced block difficulty with prevrandao.
+	HardForkParis HardFork = "paris"
+
+	// HardForkShanghai describes the Shanghai hard fork, which introduced the PUSH0 opcode.
+	HardForkShanghai HardFork = "shanghai"
+
+	// HardForkCancun describes the Cancun hard fork, which introduced transient storage and blobs.
+	HardForkCancun HardFork = "cancun"
+)
+
+// supportedHardForks describes the hard forks the test chain can be configured with, in chronological order.
+var supportedHardForks = []HardFork{
+	HardForkIstanbul,
+	HardForkBerlin,
+	HardForkLondon,
+	HardForkParis,
+	HardForkShanghai,
+	HardForkCancun,
+}
+
+// IsSupported indicates whether the HardFork can be applied to the test chain.
+func (f HardFork) IsSupported() bool {
+	return slices.Contains(supportedHardForks, f)
+}
+
+// IsMerged indicates whether the HardFork succeeds the merge, meaning blocks provide prevrandao rather than a
+// difficulty.
+func (f HardFork) IsMerged() bool {
+	return slices.Index(supportedHardForks, f) >= slices.Index(supportedHardForks, HardForkParis)
+}
+
+// Apply activates the rules of the HardFork, and every hard fork preceding it, from genesis in the provided
+// params.ChainConfig, deactivating the rules of any hard fork succeeding it.
+// Returns an error if the HardFork is not supported.
+func (f HardFork) Apply(chainConfig *params.ChainConfig) error {
+	index := slices.Index(supportedHardForks, f)
+	if index < 0 {
+		return fmt.Errorf("unsupported hard fork %q, supported hard forks are %v", f, supportedHardForks)
+	}
+
+	// Determine whether each hard fork is active, and set its activation block or time accordingly.
+	isActive := func(hardFork HardFork) bool {
+		return index >= slices.Index(supportedHardForks, hardFork)
+	}
+	activationBlock := func(hardFork HardFork) *big.Int {
+		if isActive(hardFork) {
+			return big.NewInt(0)
+		}
+		return nil
+	}
+	activationTime := func(hardFork HardFork) *uint64 {
+		if isActive(hardFork) {
+			return new(uint64)
+		}
+		return nil
+	}
+	chainConfig.IstanbulBlock = activationBlock(HardForkIstanbul)
+	chainConfig.MuirGlacierBlock = activationBlock(HardForkIstanbul)
+	chainConfig.BerlinBlock = activationBlock(HardForkBerlin)
+	chainConfig.LondonBlock = activationBlock(HardForkLondon)
+	chainConfig.ArrowGlacierBlock = activationBlock(HardForkLondon)
+	chainConfig.GrayGlacierBlock = activationBlock(HardForkLondon)
+	chainConfig.ShanghaiTime = activationTime(HardForkShanghai)
+	chainConfig.CancunTime = activationTime(HardForkCancun)
+	chainConfig.PragueTime = nil
+	chainConfig.VerkleTime = nil
+	if isActive(HardForkParis) {
+		chainConfig.TerminalTotalDifficulty = big.NewInt(0)
+		chainConfig.TerminalTotalDifficultyPassed = true
+	} else {
+		chainConfig.TerminalTotalDifficulty = nil
+		chainConfig.TerminalTotalDifficultyPassed = false
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/exp/slices"
+)
+
+// PrevrandaoMode describes how the test chain derives the prevrandao value of each block it produces.
+type PrevrandaoMode string
+
+const (
+	// PrevrandaoModeParentHash describes blocks providing the hash of their parent block as their prevrandao value.
+	PrevrandaoModeParentHash PrevrandaoMode = "parentHash"
+
+	// PrevrandaoModeFixed describes blocks providing the configured prevrandao value.
+	PrevrandaoModeFixed PrevrandaoMode = "fixed"
+
+	// PrevrandaoModePseudoRandom describes blocks providing a pseudo-random prevrandao value, derived from the
+	// configured prevrandao value as a seed and the block number, so it differs between blocks but is reproducible.
+	PrevrandaoModePseudoRandom PrevrandaoMode = "pseudoRandom"
+)
+
+// supportedPrevrandaoModes describes the prevrandao modes the test chain can be configured with.
+var supportedPrevrandaoModes = []PrevrandaoMode{
+	PrevrandaoModeParentHash,
+	PrevrandaoModeFixed,
+	PrevrandaoModePseudoRandom,
+}
+
+// IsSupported indicates whether the PrevrandaoMode can be applied to the test chain.
+func (m PrevrandaoMode) IsSupported() bool {
+	return slices.Contains(supportedPrevrandaoModes, m)
+}
+
+// BlockPrevrandao obtains the prevrandao value a block with the provided block number and parent block hash should
+// provide, as described by the PrevrandaoMode and Prevrandao of the TestChainConfig. An unset mode is treated as
+// PrevrandaoModeParentHash.
+func (t *TestChainConfig) BlockPrevrandao(blockNumber uint64, parentHash common.Hash) common.Hash {
+	switch t.PrevrandaoMode {
+	case PrevrandaoModeFixed:
+		return t.Prevrandao
+	case PrevrandaoModePseudoRandom:
+		var blockNumberData [8]byte
+		binary.BigEndian.PutUint64(blockNumberData[:], blockNumber)
+		return crypto.Keccak256Hash(t.Prevrandao[:], blockNumberData[:])
+	default:
+		return parentHash
+	}
+}
@@ -191,11 +191,25 @@ func getStandardCheatCodeContract(tracer *cheatCodeTracer) (*CheatCodeContract,
 		},
 	)
 
-	// Difficulty: Updates difficulty. Since we do not allow users to choose the fork that
-	// they are using (for now), and we are using a post-Paris fork, the difficulty cheatcode is a no-op.
+	// Difficulty: Updates difficulty. Post-Paris forks replace difficulty with prevrandao, so the difficulty cheatcode
+	// is a no-op unless the chain is configured with a pre-Paris fork.
 	contract.addMethod(
 		"difficulty", abi.Arguments{{Type: typeUint256}}, abi.Arguments{},
 		func(tracer *cheatCodeTracer, inputs []any) ([]any, *cheatCodeRawReturnData) {
+			if tracer.chain.pendingBlockContext.Random != nil {
+				return nil, nil
+			}
+
+			// Store our original difficulty
+			originalDifficulty := tracer.chain.pendingBlockContext.Difficulty
+
+			// Update the pending block context difficulty
+			tracer.chain.pendingBlockContext.Difficulty = new(big.Int).Set(inputs[0].(*big.Int))
+
+			// Restore the original difficulty when top frame exits
+			tracer.CurrentCallFrame().onTopFrameExitRestoreHooks.Push(func() {
+				tracer.chain.pendingBlockContext.Difficulty = originalDifficulty
+			})
 			return nil, nil
 		},
 	)
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/crytic/medusa/chain/state"
 	"golang.org/x/net/context"
@@ -29,6 +30,11 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 )
 
+// ErrTransactionTimeout is returned when adding a transaction to a pending block if its execution exceeded the
+// configured config.TestChainConfig TransactionTimeout and was aborted. The transaction is not added to the block, and
+// its state changes are discarded.
+var ErrTransactionTimeout = errors.New("transaction execution exceeded the configured timeout and was aborted")
+
 // TestChain represents a simulated Ethereum chain used for testing. It maintains blocks in-memory and strips away
 // typical consensus/chain objects to allow for more specialized testing closer to the EVM.
 type TestChain struct {
@@ -146,14 +152,15 @@ func newTestChainWithStateFactory(
 		return nil, err
 	}
 
-	// TODO: go-ethereum doesn't set cancun start time for THEIR test `ChainConfig` struct.
-	//   Note: We have our own `TestChainConfig` definition that is different (second argument in this function).
-	//  We should allow the user to provide a go-ethereum `ChainConfig` to do custom fork selection, inside of our
-	//  `TestChainConfig` definition. Or we should wrap it in our own struct to simplify the options and not pollute
-	//  our overall medusa project config.
-	cancunTime := uint64(0)
-	chainConfig.ShanghaiTime = &cancunTime
-	chainConfig.CancunTime = &cancunTime
+	// Activate the rules of our configured hard fork from genesis. An unset hard fork defaults to Cancun.
+	hardFork := testChainConfig.HardFork
+	if hardFork == "" {
+		hardFork = config.HardForkCancun
+	}
+	err = hardFork.Apply(chainConfig)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create our genesis definition with our default chain config.
 	genesisDefinition := &core.Genesis{
@@ -165,8 +172,8 @@ func newTestChainWithStateFactory(
 		},
 		GasLimit:   0,
 		Difficulty: common.Big0,
-		Mixhash:    common.Hash{},
-		Coinbase:   common.Address{},
+		Mixhash:    testChainConfig.BlockPrevrandao(0, common.Hash{}),
+		Coinbase:   testChainConfig.Coinbase,
 		Alloc:      maps.Clone(genesisAlloc), // cloned to avoid concurrent access issues across cloned chains
 		Number:     0,
 		GasUsed:    0,
@@ -339,6 +346,11 @@ func (t *TestChain) GenesisDefinition() *core.Genesis {
 	return t.genesisDefinition
 }
 
+// TestChainConfig returns the configuration used by this TestChain.
+func (t *TestChain) TestChainConfig() *config.TestChainConfig {
+	return t.testChainConfig
+}
+
 // State returns the current state.StateDB of the chain.
 func (t *TestChain) State() types.MedusaStateDB {
 	return t.state
@@ -605,7 +617,7 @@ func (t *TestChain) PendingBlockCreateWithBaseBlockContext(baseBlockContext *typ
 	// - Other hashes will populate as we apply transactions
 	// - Bloom is aggregated for each transaction in the block (for now empty).
 	// - GasUsed is aggregated for each transaction in the block (for now zero).
-	// - We don't care too much about difficulty and mix digest so setting them to random things
+	// - Difficulty is zero, and the mix digest provides the block's prevrandao value.
 	header := &gethTypes.Header{
 		ParentHash:  parentBlockHash,
 		UncleHash:   gethTypes.EmptyUncleHash,
@@ -621,7 +633,7 @@ func (t *TestChain) PendingBlockCreateWithBaseBlockContext(baseBlockContext *typ
 		Difficulty:  common.Big0,
 		Number:      new(big.Int).Set(baseBlockContext.Number),
 		Time:        baseBlockContext.Time,
-		MixDigest:   parentBlockHash,
+		MixDigest:   baseBlockContext.Prevrandao,
 		BaseFee:     new(big.Int).Set(baseBlockContext.BaseFee),
 	}
 
@@ -646,15 +658,24 @@ func (t *TestChain) PendingBlockCreateWithBaseBlockContext(baseBlockContext *typ
 }
 
 // PendingBlockCreateWithParameters constructs an empty block which is pending addition to the chain, using the block number
-// and timestamp provided. Returns the constructed block, or an error if one occurred.
+// and timestamp provided. The block's prevrandao value is derived as the chain is configured to.
+// Returns the constructed block, or an error if one occurred.
 func (t *TestChain) PendingBlockCreateWithParameters(blockNumber uint64, blockTime uint64, blockGasLimit *uint64) (*types.Block, error) {
+	prevrandao := t.testChainConfig.BlockPrevrandao(blockNumber, t.Head().Hash)
+	return t.PendingBlockCreateWithPrevrandao(blockNumber, blockTime, prevrandao, blockGasLimit)
+}
+
+// PendingBlockCreateWithPrevrandao constructs an empty block which is pending addition to the chain, using the block
+// number, timestamp, and prevrandao value provided. Returns the constructed block, or an error if one occurred.
+func (t *TestChain) PendingBlockCreateWithPrevrandao(blockNumber uint64, blockTime uint64, prevrandao common.Hash, blockGasLimit *uint64) (*types.Block, error) {
 	// We will create a base block context with the provided parameters in addition to using the current head block.
-	// All values that are not the block number and timestamp are taken from the current head block.
+	// All values that are not the block number, timestamp, and prevrandao are taken from the current head block.
 	baseBlockContext := types.NewBaseBlockContext(
 		blockNumber,
 		blockTime,
 		t.Head().Header.BaseFee,
 		t.Head().Header.Coinbase,
+		prevrandao,
 	)
 
 	return t.PendingBlockCreateWithBaseBlockContext(baseBlockContext, blockGasLimit)
@@ -708,11 +729,24 @@ func (t *TestChain) PendingBlockAddTx(message *core.Message, additionalTracers .
 	t.pendingBlockContext = &evm.Context
 	t.pendingBlockChainConfig = evm.ChainConfig()
 
+	// If a transaction timeout is configured, cancel execution once it is exceeded. The EVM halts at the next jump
+	// once cancelled, so this bounds the time spent in loops which burn through large gas limits.
+	var timeoutTimer *time.Timer
+	if t.testChainConfig.TransactionTimeout > 0 {
+		timeoutTimer = time.AfterFunc(time.Duration(t.testChainConfig.TransactionTimeout)*time.Millisecond, evm.Cancel)
+	}
+
 	// Apply our transaction
 	var usedGas uint64
 	receipt, executionResult, err := vendored.EVMApplyTransaction(message, t.chainConfig, t.testChainConfig, &t.pendingBlock.Header.Coinbase, gasPool, t.state, t.pendingBlock.Header.Number, t.pendingBlock.Hash, tx, &usedGas, evm)
+	if timeoutTimer != nil {
+		timeoutTimer.Stop()
+	}
 	if err != nil {
-		return fmt.Errorf("test chain state write error when adding tx to pending block: %v", err)
+		if errors.Is(err, vendored.ErrExecutionCancelled) {
+			return ErrTransactionTimeout
+		}
+		return fmt.Errorf("test chain state write error when adding tx to pending block: %w", err)
 	}
 
 	// Create our message result
@@ -855,11 +889,7 @@ func (t *TestChain) emitContractChangeEvents(reverting bool, messageResults ...*
 				// We emit the relevant event depending on the contract deployment change, as a block with
 				// this execution result is being committed to chain.
 				if deploymentChange.Creation {
-					err = t.Events.ContractDeploymentAddedEventEmitter.Publish(ContractDeploymentsAddedEvent{
-						Chain:             t,
-						Contract:          deploymentChange.Contract,
-						DynamicDeployment: deploymentChange.DynamicCreation,
-					})
+					err = t.Events.ContractDeploymentAddedEventEmitter.Publish(newContractDeploymentsAddedEvent(t, messageResults[i], deploymentChange))
 				} else if deploymentChange.Destroyed {
 					err = t.Events.ContractDeploymentRemovedEventEmitter.Publish(ContractDeploymentsRemovedEvent{
 						Chain:    t,
@@ -887,11 +917,7 @@ func (t *TestChain) emitContractChangeEvents(reverting bool, messageResults ...*
 						Contract: deploymentChange.Contract,
 					})
 				} else if deploymentChange.Destroyed {
-					err = t.Events.ContractDeploymentAddedEventEmitter.Publish(ContractDeploymentsAddedEvent{
-						Chain:             t,
-						Contract:          deploymentChange.Contract,
-						DynamicDeployment: deploymentChange.DynamicCreation,
-					})
+					err = t.Events.ContractDeploymentAddedEventEmitter.Publish(newContractDeploymentsAddedEvent(t, result, deploymentChange))
 				}
 				if err != nil {
 					return err
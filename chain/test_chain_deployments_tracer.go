@@ -101,6 +101,7 @@ func (t *testChainDeploymentsTracer) OnEnter(depth int, typ byte, from common.Ad
 			},
 			Creation:        true,
 			DynamicCreation: !isTopLevelFrame, // If we're not at the top level, this is a dynamic creation.
+			Creator:         from,
 			SelfDestructed:  false,
 			Destroyed:       false,
 		})
@@ -3,6 +3,8 @@ package chain
 import (
 	"github.com/crytic/medusa/chain/types"
 	"github.com/crytic/medusa/events"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // TestChainEvents defines event emitters for a TestChain.
@@ -97,6 +99,42 @@ type ContractDeploymentsAddedEvent struct {
 	// DynamicDeployment describes whether this contract deployment was dynamic (e.g. `c = new MyContract()`) or was
 	// because of a traditional transaction
 	DynamicDeployment bool
+
+	// Creator describes the address of the account or contract which created the contract.
+	Creator common.Address
+
+	// InitBytecodeHash describes the keccak256 hash of the init bytecode used to create the contract. For deployments
+	// made by a transaction, this includes any constructor arguments.
+	InitBytecodeHash common.Hash
+
+	// BlockNumber describes the number of the block containing the transaction which created the contract.
+	BlockNumber uint64
+
+	// TransactionIndex describes the index of the transaction which created the contract, within its block.
+	TransactionIndex uint
+
+	// TransactionHash describes the hash of the transaction which created the contract.
+	TransactionHash common.Hash
+}
+
+// newContractDeploymentsAddedEvent creates a ContractDeploymentsAddedEvent for the provided contract deployment
+// change, populating its creation context from the message results of the transaction which made the change.
+func newContractDeploymentsAddedEvent(chain *TestChain, messageResults *types.MessageResults, deploymentChange types.DeployedContractBytecodeChange) ContractDeploymentsAddedEvent {
+	event := ContractDeploymentsAddedEvent{
+		Chain:             chain,
+		Contract:          deploymentChange.Contract,
+		DynamicDeployment: deploymentChange.DynamicCreation,
+		Creator:           deploymentChange.Creator,
+		InitBytecodeHash:  crypto.Keccak256Hash(deploymentChange.Contract.InitBytecode),
+	}
+	if messageResults.Receipt != nil {
+		if messageResults.Receipt.BlockNumber != nil {
+			event.BlockNumber = messageResults.Receipt.BlockNumber.Uint64()
+		}
+		event.TransactionIndex = messageResults.Receipt.TransactionIndex
+		event.TransactionHash = messageResults.Receipt.TxHash
+	}
+	return event
 }
 
 // ContractDeploymentsRemovedEvent describes an event where a contract has become unavailable on the TestChain, either
@@ -6,6 +6,7 @@ import (
 	"math/rand"
 	"testing"
 
+	"github.com/crytic/medusa/chain/config"
 	"github.com/crytic/medusa/compilation/platforms"
 	"github.com/crytic/medusa/utils"
 	"github.com/crytic/medusa/utils/testutils"
@@ -13,6 +14,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -213,8 +216,10 @@ func TestChainDynamicDeployments(t *testing.T) {
 					if len(contract.Abi.Constructor.Inputs) == 0 {
 						// Listen for contract changes
 						deployedContracts := 0
+						addedEvents := make([]ContractDeploymentsAddedEvent, 0)
 						chain.Events.ContractDeploymentAddedEventEmitter.Subscribe(func(event ContractDeploymentsAddedEvent) error {
 							deployedContracts++
+							addedEvents = append(addedEvents, event)
 							return nil
 						})
 						chain.Events.ContractDeploymentRemovedEventEmitter.Subscribe(func(event ContractDeploymentsRemovedEvent) error {
@@ -260,6 +265,21 @@ func TestChainDynamicDeployments(t *testing.T) {
 						assert.EqualValues(t, 1, len(block.MessageResults))
 						assert.EqualValues(t, 2, deployedContracts)
 
+						// Verify the creation context of both deployments. The outer deployment was created by our
+						// sender, while the inner deployment was created dynamically by the outer one.
+						outerAddress := block.MessageResults[0].Receipt.ContractAddress
+						assert.EqualValues(t, 2, len(addedEvents))
+						for i, event := range addedEvents {
+							assert.EqualValues(t, i == 1, event.DynamicDeployment)
+							assert.EqualValues(t, crypto.Keccak256Hash(event.Contract.InitBytecode), event.InitBytecodeHash)
+							assert.EqualValues(t, block.Header.Number.Uint64(), event.BlockNumber)
+							assert.EqualValues(t, 0, event.TransactionIndex)
+							assert.EqualValues(t, block.MessageResults[0].Receipt.TxHash, event.TransactionHash)
+						}
+						assert.EqualValues(t, senders[0], addedEvents[0].Creator)
+						assert.EqualValues(t, outerAddress, addedEvents[0].Contract.Address)
+						assert.EqualValues(t, outerAddress, addedEvents[1].Creator)
+
 						// Ensure we could get our state
 						_, err = chain.StateAfterBlockNumber(chain.HeadBlockNumber())
 						assert.NoError(t, err)
@@ -628,3 +648,179 @@ func TestChainCallSequenceReplayMatchSimple(t *testing.T) {
 		assert.EqualValues(t, chain.Head().Header.Root, recreatedChain.Head().Header.Root)
 	})
 }
+
+// TestChainHardForkGasAccounting executes crafted transactions which access cold and warm accounts and storage slots,
+// and clear storage for a refund, on chains configured with different hard forks. It asserts the gas used reported in
+// receipts (which is what fuzzing metrics record) matches the gas schedule of the configured hard fork, as defined by
+// go-ethereum.
+func TestChainHardForkGasAccounting(t *testing.T) {
+	sender := common.HexToAddress("0x0707")
+	contractAddress := common.HexToAddress("0x1234")
+
+	// Define our crafted contracts and the gas we expect a call to them to use under each hard fork. Each expected
+	// value is the intrinsic transaction gas plus the cost of each opcode executed, less any refund.
+	testCases := []struct {
+		name     string
+		code     []byte
+		storage  map[common.Hash]common.Hash
+		expected map[config.HardFork]uint64
+	}{
+		{
+			// PUSH1 0x00, SLOAD, POP, PUSH1 0x00, SLOAD, POP, STOP
+			name: "cold and warm storage reads",
+			code: []byte{0x60, 0x00, 0x54, 0x50, 0x60, 0x00, 0x54, 0x50, 0x00},
+			expected: map[config.HardFork]uint64{
+				config.HardForkIstanbul: params.TxGas + 2*(3+params.SloadGasEIP2200+2),
+				config.HardForkBerlin:   params.TxGas + 2*(3+2) + params.ColdSloadCostEIP2929 + params.WarmStorageReadCostEIP2929,
+				config.HardForkLondon:   params.TxGas + 2*(3+2) + params.ColdSloadCostEIP2929 + params.WarmStorageReadCostEIP2929,
+				config.HardForkCancun:   params.TxGas + 2*(3+2) + params.ColdSloadCostEIP2929 + params.WarmStorageReadCostEIP2929,
+			},
+		},
+		{
+			// PUSH1 0x42, BALANCE, POP, PUSH1 0x42, BALANCE, POP, STOP
+			name: "cold and warm account reads",
+			code: []byte{0x60, 0x42, 0x31, 0x50, 0x60, 0x42, 0x31, 0x50, 0x00},
+			expected: map[config.HardFork]uint64{
+				config.HardForkIstanbul: params.TxGas + 2*(3+params.BalanceGasEIP1884+2),
+				config.HardForkBerlin:   params.TxGas + 2*(3+2) + params.ColdAccountAccessCostEIP2929 + params.WarmStorageReadCostEIP2929,
+				config.HardForkLondon:   params.TxGas + 2*(3+2) + params.ColdAccountAccessCostEIP2929 + params.WarmStorageReadCostEIP2929,
+				config.HardForkCancun:   params.TxGas + 2*(3+2) + params.ColdAccountAccessCostEIP2929 + params.WarmStorageReadCostEIP2929,
+			},
+		},
+		{
+			// PUSH1 0x00, PUSH1 0x00, SSTORE, STOP, clearing a slot which was set at genesis.
+			name:    "cleared storage refund",
+			code:    []byte{0x60, 0x00, 0x60, 0x00, 0x55, 0x00},
+			storage: map[common.Hash]common.Hash{{}: common.BigToHash(big.NewInt(1))},
+			expected: map[config.HardFork]uint64{
+				// Prior to London, refunds are capped to half of the gas used, which the refund exceeds.
+				config.HardForkIstanbul: (params.TxGas + 2*3 + params.SstoreResetGasEIP2200) - (params.TxGas+2*3+params.SstoreResetGasEIP2200)/params.RefundQuotient,
+				config.HardForkBerlin:   (params.TxGas + 2*3 + params.SstoreResetGasEIP2200) - (params.TxGas+2*3+params.SstoreResetGasEIP2200)/params.RefundQuotient,
+				// From London, refunds are reduced and capped to a fifth of the gas used, which the refund does not exceed.
+				config.HardForkLondon: (params.TxGas + 2*3 + params.SstoreResetGasEIP2200) - params.SstoreClearsScheduleRefundEIP3529,
+				config.HardForkCancun: (params.TxGas + 2*3 + params.SstoreResetGasEIP2200) - params.SstoreClearsScheduleRefundEIP3529,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		for hardFork, expectedGasUsed := range testCase.expected {
+			// Create a chain with our crafted contract and a funded sender, configured with the hard fork.
+			testChainConfig, err := config.DefaultTestChainConfig()
+			assert.NoError(t, err)
+			testChainConfig.HardFork = hardFork
+			genesisAlloc := types.GenesisAlloc{
+				sender:          {Balance: new(big.Int).Div(abi.MaxInt256, big.NewInt(2))},
+				contractAddress: {Balance: big.NewInt(0), Code: testCase.code, Storage: testCase.storage},
+			}
+			chain, err := NewTestChain(context.Background(), genesisAlloc, testChainConfig)
+			assert.NoError(t, err)
+
+			// Call our contract in a new block.
+			msg := core.Message{
+				To:                &contractAddress,
+				From:              sender,
+				Nonce:             chain.State().GetNonce(sender),
+				Value:             big.NewInt(0),
+				GasLimit:          chain.BlockGasLimit,
+				GasPrice:          big.NewInt(1),
+				GasFeeCap:         big.NewInt(0),
+				GasTipCap:         big.NewInt(0),
+				Data:              nil,
+				AccessList:        nil,
+				SkipAccountChecks: false,
+			}
+			block, err := chain.PendingBlockCreate()
+			assert.NoError(t, err)
+			err = chain.PendingBlockAddTx(&msg)
+			assert.NoError(t, err)
+			err = chain.PendingBlockCommit()
+			assert.NoError(t, err)
+
+			// Verify the gas used matches the gas schedule of the hard fork.
+			assert.EqualValues(t, types.ReceiptStatusSuccessful, block.MessageResults[0].Receipt.Status, "%s (%s): call returned a failed status", testCase.name, hardFork)
+			assert.EqualValues(t, expectedGasUsed, block.MessageResults[0].Receipt.GasUsed, "%s (%s): unexpected gas used", testCase.name, hardFork)
+			assert.EqualValues(t, expectedGasUsed, block.MessageResults[0].ExecutionResult.UsedGas, "%s (%s): unexpected gas used", testCase.name, hardFork)
+			chain.Close()
+		}
+	}
+}
+
+// TestChainBlockParameters executes a crafted transaction which stores the block's coinbase and prevrandao values, on
+// chains configured with each prevrandao mode and a coinbase address. It asserts the stored values match the
+// configuration, and that they are preserved when the chain is cloned.
+func TestChainBlockParameters(t *testing.T) {
+	sender := common.HexToAddress("0x0707")
+	contractAddress := common.HexToAddress("0x1234")
+	coinbase := common.HexToAddress("0xc0ffee")
+	prevrandao := common.HexToHash("0x2a")
+
+	// PREVRANDAO, PUSH1 0x00, SSTORE, COINBASE, PUSH1 0x01, SSTORE, STOP
+	code := []byte{0x44, 0x60, 0x00, 0x55, 0x41, 0x60, 0x01, 0x55, 0x00}
+
+	for _, prevrandaoMode := range []config.PrevrandaoMode{config.PrevrandaoModeParentHash, config.PrevrandaoModeFixed, config.PrevrandaoModePseudoRandom} {
+		// Create a chain with our crafted contract and a funded sender, configured with our block parameters.
+		testChainConfig, err := config.DefaultTestChainConfig()
+		assert.NoError(t, err)
+		testChainConfig.Coinbase = coinbase
+		testChainConfig.PrevrandaoMode = prevrandaoMode
+		testChainConfig.Prevrandao = prevrandao
+		genesisAlloc := types.GenesisAlloc{
+			sender:          {Balance: new(big.Int).Div(abi.MaxInt256, big.NewInt(2))},
+			contractAddress: {Balance: big.NewInt(0), Code: code},
+		}
+		chain, err := NewTestChain(context.Background(), genesisAlloc, testChainConfig)
+		assert.NoError(t, err)
+
+		// Call our contract in a few new blocks, verifying the values it stored each time.
+		for i := 0; i < 3; i++ {
+			parentHash := chain.Head().Hash
+			msg := core.Message{
+				To:                &contractAddress,
+				From:              sender,
+				Nonce:             chain.State().GetNonce(sender),
+				Value:             big.NewInt(0),
+				GasLimit:          chain.BlockGasLimit,
+				GasPrice:          big.NewInt(1),
+				GasFeeCap:         big.NewInt(0),
+				GasTipCap:         big.NewInt(0),
+				Data:              nil,
+				AccessList:        nil,
+				SkipAccountChecks: false,
+			}
+			block, err := chain.PendingBlockCreate()
+			assert.NoError(t, err)
+			err = chain.PendingBlockAddTx(&msg)
+			assert.NoError(t, err)
+			err = chain.PendingBlockCommit()
+			assert.NoError(t, err)
+
+			// Determine the prevrandao value we expect the block to provide.
+			var expectedPrevrandao common.Hash
+			switch prevrandaoMode {
+			case config.PrevrandaoModeParentHash:
+				expectedPrevrandao = parentHash
+			case config.PrevrandaoModeFixed:
+				expectedPrevrandao = prevrandao
+			case config.PrevrandaoModePseudoRandom:
+				var blockNumberData [8]byte
+				block.Header.Number.FillBytes(blockNumberData[:])
+				expectedPrevrandao = crypto.Keccak256Hash(prevrandao[:], blockNumberData[:])
+			}
+			assert.EqualValues(t, expectedPrevrandao, block.Header.MixDigest, "%s: unexpected block prevrandao", prevrandaoMode)
+			assert.EqualValues(t, expectedPrevrandao, chain.State().GetState(contractAddress, common.Hash{}), "%s: unexpected stored prevrandao", prevrandaoMode)
+			assert.EqualValues(t, common.BytesToHash(coinbase.Bytes()), chain.State().GetState(contractAddress, common.BigToHash(big.NewInt(1))), "%s: unexpected stored coinbase", prevrandaoMode)
+		}
+
+		// Clone our chain and verify each block provides the same values.
+		clonedChain, err := chain.Clone(nil)
+		assert.NoError(t, err)
+		for i, block := range chain.CommittedBlocks() {
+			clonedBlock := clonedChain.CommittedBlocks()[i]
+			assert.EqualValues(t, block.Header.MixDigest, clonedBlock.Header.MixDigest, "%s: cloned block prevrandao differs", prevrandaoMode)
+			assert.EqualValues(t, block.Header.Coinbase, clonedBlock.Header.Coinbase, "%s: cloned block coinbase differs", prevrandaoMode)
+		}
+		clonedChain.Close()
+		chain.Close()
+	}
+}
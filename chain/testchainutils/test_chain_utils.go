@@ -0,0 +1,94 @@
+package testchainutils
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"math/big"
+	"testing"
+
+	"github.com/crytic/medusa/chain"
+	"github.com/crytic/medusa/chain/config"
+	chainTypes "github.com/crytic/medusa/chain/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// FundedSender describes the address of the account funded by NewFundedTestChain.
+var FundedSender = common.HexToAddress("0x10000")
+
+// NewFundedTestChain creates a TestChain with a default config and a funded sender account, attaching the provided
+// tracers to every transaction. The chain is closed once the test completes.
+// Returns the chain and the funded sender's address.
+func NewFundedTestChain(tb testing.TB, tracers ...*chain.TestChainTracer) (*chain.TestChain, common.Address) {
+	return NewFundedTestChainWithAlloc(tb, nil, nil, tracers...)
+}
+
+// NewFundedTestChainWithAlloc creates a TestChain with the provided config (or a default one if nil), the accounts
+// described by the provided genesis allocation, and a funded sender account, attaching the provided tracers to every
+// transaction. If the genesis allocation already describes the sender, it is used as provided. The chain is closed
+// once the test completes.
+// Returns the chain and the funded sender's address.
+func NewFundedTestChainWithAlloc(tb testing.TB, genesisAlloc types.GenesisAlloc, testChainConfig *config.TestChainConfig, tracers ...*chain.TestChainTracer) (*chain.TestChain, common.Address) {
+	genesisAlloc = maps.Clone(genesisAlloc)
+	if genesisAlloc == nil {
+		genesisAlloc = make(types.GenesisAlloc)
+	}
+	if _, ok := genesisAlloc[FundedSender]; !ok {
+		genesisAlloc[FundedSender] = types.Account{Balance: new(big.Int).Div(abi.MaxInt256, big.NewInt(2))}
+	}
+	testChain, err := chain.NewTestChain(context.Background(), genesisAlloc, testChainConfig)
+	if !assert.NoError(tb, err) {
+		tb.FailNow()
+	}
+	tb.Cleanup(testChain.Close)
+	for _, tracer := range tracers {
+		testChain.AddTracer(tracer, true, false)
+	}
+	return testChain, FundedSender
+}
+
+// SendMessage sends a message in a new block on the provided chain, using the block gas limit as its gas limit.
+// Returns the message results.
+func SendMessage(tb testing.TB, testChain *chain.TestChain, from common.Address, to *common.Address, data []byte) *chainTypes.MessageResults {
+	return SendMessageWithGasLimit(tb, testChain, from, to, data, testChain.BlockGasLimit)
+}
+
+// SendMessageWithGasLimit sends a message with the provided gas limit in a new block on the provided chain.
+// Returns the message results.
+func SendMessageWithGasLimit(tb testing.TB, testChain *chain.TestChain, from common.Address, to *common.Address, data []byte, gasLimit uint64) *chainTypes.MessageResults {
+	msg := core.Message{
+		To:                to,
+		From:              from,
+		Nonce:             testChain.State().GetNonce(from),
+		Value:             big.NewInt(0),
+		GasLimit:          gasLimit,
+		GasPrice:          big.NewInt(1),
+		GasFeeCap:         big.NewInt(0),
+		GasTipCap:         big.NewInt(0),
+		Data:              data,
+		SkipAccountChecks: false,
+	}
+	block, err := testChain.PendingBlockCreate()
+	assert.NoError(tb, err)
+	assert.NoError(tb, testChain.PendingBlockAddTx(&msg))
+	assert.NoError(tb, testChain.PendingBlockCommit())
+	return block.MessageResults[0]
+}
+
+// DeployRuntimeBytecode deploys the provided hex-encoded runtime bytecode on the provided chain, using init bytecode
+// which copies it into memory and returns it.
+// Returns the address of the deployed contract.
+func DeployRuntimeBytecode(tb testing.TB, testChain *chain.TestChain, from common.Address, runtimeBytecode string) common.Address {
+	runtimeLength := len(runtimeBytecode) / 2
+	initBytecode := fmt.Sprintf("60%02x600c60003960%02x6000f3", runtimeLength, runtimeLength) + runtimeBytecode
+	data, err := hex.DecodeString(initBytecode)
+	assert.NoError(tb, err)
+	results := SendMessage(tb, testChain, from, nil, data)
+	assert.EqualValues(tb, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	return results.Receipt.ContractAddress
+}
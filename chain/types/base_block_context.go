@@ -20,14 +20,17 @@ type BaseBlockContext struct {
 	BaseFee *big.Int
 	// Coinbase represents the coinbase of the block when it was first created.
 	Coinbase common.Address
+	// Prevrandao represents the prevrandao value (mix digest) of the block when it was first created.
+	Prevrandao common.Hash
 }
 
 // NewBaseBlockContext returns a new BaseBlockContext with the provided parameters.
-func NewBaseBlockContext(number uint64, time uint64, baseFee *big.Int, coinbase common.Address) *BaseBlockContext {
+func NewBaseBlockContext(number uint64, time uint64, baseFee *big.Int, coinbase common.Address, prevrandao common.Hash) *BaseBlockContext {
 	return &BaseBlockContext{
-		Number:   new(big.Int).SetUint64(number),
-		Time:     time,
-		BaseFee:  new(big.Int).Set(baseFee),
-		Coinbase: coinbase,
+		Number:     new(big.Int).SetUint64(number),
+		Time:       time,
+		BaseFee:    new(big.Int).Set(baseFee),
+		Coinbase:   coinbase,
+		Prevrandao: prevrandao,
 	}
 }
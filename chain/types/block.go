@@ -43,6 +43,7 @@ func NewBlock(header *types.Header) *Block {
 			header.Time,
 			header.BaseFee,
 			header.Coinbase,
+			header.MixDigest,
 		),
 	}
 	return block
@@ -20,6 +20,10 @@ type DeployedContractBytecodeChange struct {
 	// Creation is false.
 	DynamicCreation bool
 
+	// Creator describes the address of the account or contract which created the contract. This is only set if
+	// Creation is true.
+	Creator common.Address
+
 	// SelfDestructed indicates whether the change made was due to a self-destruct instruction being executed. This
 	// cannot be true if Creation is true.
 	// Note: This may not be indicative of contract removal (as is the case with Destroyed), as proposed changes to
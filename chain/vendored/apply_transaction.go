@@ -17,6 +17,8 @@
 package vendored
 
 import (
+	"errors"
+
 	"github.com/crytic/medusa/chain/config"
 	"github.com/crytic/medusa/chain/types"
 	"github.com/ethereum/go-ethereum/common"
@@ -28,6 +30,9 @@ import (
 	"math/big"
 )
 
+// ErrExecutionCancelled is returned by EVMApplyTransaction if the EVM was cancelled while executing the transaction.
+var ErrExecutionCancelled = errors.New("transaction execution was cancelled")
+
 // EVMApplyTransaction is a vendored version of go-ethereum's unexported applyTransaction method (not to be confused
 // with the exported method ApplyTransaction). This method was vendored to simply be exposed/exported, so it can be
 // used by the test chain. This method offers greater control of parameters over the exposed ApplyTransaction
@@ -36,6 +41,7 @@ import (
 // This executes on an underlying EVM and returns a transaction receipt, or an error if one occurs.
 // Additional changes:
 // - Exposed core.ExecutionResult as a return value.
+// - If the EVM was cancelled during execution, state changes are reverted and ErrExecutionCancelled is returned.
 func EVMApplyTransaction(msg *Message, config *params.ChainConfig, testChainConfig *config.TestChainConfig, author *common.Address, gp *GasPool, statedb types.MedusaStateDB, blockNumber *big.Int, blockHash common.Hash, tx *gethtypes.Transaction, usedGas *uint64, evm *vm.EVM) (receipt *gethtypes.Receipt, result *ExecutionResult, err error) {
 	// Apply the OnTxStart and OnTxEnd hooks
 	if evm.Config.Tracer != nil && evm.Config.Tracer.OnTxStart != nil {
@@ -51,11 +57,18 @@ func EVMApplyTransaction(msg *Message, config *params.ChainConfig, testChainConf
 	evm.Reset(txContext, statedb)
 
 	// Apply the transaction to the current state (included in the env).
+	snapshot := statedb.Snapshot()
 	result, err = ApplyMessage(evm, msg, gp)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	// If execution was cancelled, it was halted at an arbitrary point, so we discard its state changes entirely.
+	if evm.Cancelled() {
+		statedb.RevertToSnapshot(snapshot)
+		return nil, nil, ErrExecutionCancelled
+	}
+
 	// Update the state with pending changes.
 	var root []byte
 	if config.IsByzantium(blockNumber) {
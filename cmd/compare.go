@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/crytic/medusa/cmd/exitcodes"
+	"github.com/crytic/medusa/fuzzing"
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/spf13/cobra"
+)
+
+// compareCmd represents the command provider for comparing two fuzzing campaigns
+var compareCmd = &cobra.Command{
+	Use:               "compare",
+	Short:             "Runs two fuzzing campaigns with different configurations and compares their results",
+	Long:              `Compiles the project once, then runs a baseline and a candidate fuzzing campaign with the same seed, one after the other, and reports the differences in coverage, corpus size, failures found, and throughput between them`,
+	Args:              cmdValidateCompareArgs,
+	ValidArgsFunction: cmdValidFuzzArgs,
+	RunE:              cmdRunCompare,
+	SilenceUsage:      true,
+	SilenceErrors:     true,
+}
+
+func init() {
+	// Add all the flags allowed for the compare command
+	err := addCompareFlags()
+	if err != nil {
+		cmdLogger.Panic("Failed to initialize the compare command", err)
+	}
+
+	// Add the compare command and its associated flags to the root command
+	rootCmd.AddCommand(compareCmd)
+}
+
+// cmdValidateCompareArgs makes sure that there are no positional arguments provided to the compare command
+func cmdValidateCompareArgs(cmd *cobra.Command, args []string) error {
+	// Make sure we have no positional args
+	if err := cobra.NoArgs(cmd, args); err != nil {
+		err = fmt.Errorf("compare does not accept any positional arguments, only flags and their associated values")
+		cmdLogger.Error("Failed to validate args to the compare command", err)
+		return err
+	}
+	return nil
+}
+
+// cmdRunCompare executes the CLI compare command. The baseline project configuration is read with readProjectConfig,
+// and the candidate project configuration from its own file if one is provided, otherwise it is a copy of the baseline.
+// Both are updated with any flags provided, then both campaigns are run and their comparison is written.
+func cmdRunCompare(cmd *cobra.Command, args []string) error {
+	// Read our baseline project configuration
+	baselineConfig, configPath, err := readProjectConfig(cmd, "compare")
+	if err != nil {
+		return err
+	}
+
+	// Read our candidate project configuration, or copy the baseline if none was provided. The copy is deep, so
+	// candidate-only flags do not affect the baseline through shared slices or maps.
+	clonedConfig, err := baselineConfig.Clone()
+	if err != nil {
+		cmdLogger.Error("Failed to run the compare command", err)
+		return err
+	}
+	candidateConfig := *clonedConfig
+	if cmd.Flags().Changed("candidate-config") {
+		candidateConfigPath, err := cmd.Flags().GetString("candidate-config")
+		if err != nil {
+			cmdLogger.Error("Failed to run the compare command", err)
+			return err
+		}
+		cmdLogger.Info("Reading the candidate configuration file at: ", colors.Bold, candidateConfigPath, colors.Reset)
+		readConfig, err := config.ReadProjectConfigFromFile(candidateConfigPath, DefaultCompilationPlatform)
+		if err != nil {
+			cmdLogger.Error("Failed to run the compare command", err)
+			return err
+		}
+		candidateConfig = *readConfig
+	}
+
+	// Update both project configurations given whatever flags were set using the CLI
+	for _, projectConfig := range []*config.ProjectConfig{baselineConfig, &candidateConfig} {
+		err = updateProjectConfigWithCompareFlags(cmd, projectConfig)
+		if err != nil {
+			cmdLogger.Error("Failed to run the compare command", err)
+			return err
+		}
+	}
+	if cmd.Flags().Changed("candidate-seq-len") {
+		candidateConfig.Fuzzing.CallSequenceLength, err = cmd.Flags().GetInt("candidate-seq-len")
+		if err != nil {
+			cmdLogger.Error("Failed to run the compare command", err)
+			return err
+		}
+	}
+
+	// Resolve our output directory before changing our working directory, so it is relative to where we were invoked.
+	outputDirectory, err := cmd.Flags().GetString("output-dir")
+	if err != nil {
+		cmdLogger.Error("Failed to run the compare command", err)
+		return err
+	}
+	outputDirectory, err = filepath.Abs(outputDirectory)
+	if err != nil {
+		cmdLogger.Error("Failed to run the compare command", err)
+		return err
+	}
+
+	// Change our working directory to the parent directory of the project configuration file, so that paths in the
+	// configuration are resolved as they are when fuzzing.
+	err = os.Chdir(filepath.Dir(configPath))
+	if err != nil {
+		cmdLogger.Error("Failed to run the compare command", err)
+		return err
+	}
+
+	// Compile our targets and run both campaigns
+	cmdLogger.Info("Compiling targets with ", colors.Bold, baselineConfig.Compilation.Platform, colors.Reset)
+	comparison, err := fuzzing.RunCampaignComparison(*baselineConfig, candidateConfig, outputDirectory)
+	if err != nil {
+		cmdLogger.Error("Failed to compare fuzzing campaigns", err)
+		return exitcodes.NewErrorWithExitCode(err, exitcodes.ExitCodeHandledError)
+	}
+	cmdLogger.Info("Campaign comparison (seed ", comparison.Seed, ") saved to: ", filepath.Join(outputDirectory, "comparison.md"),
+		" and ", filepath.Join(outputDirectory, "comparison.json"))
+	return nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/spf13/cobra"
+)
+
+// addCompareFlags adds the various flags for the compare command
+func addCompareFlags() error {
+	// Get the default project config and throw an error if we cant
+	defaultConfig, err := config.GetDefaultProjectConfig(DefaultCompilationPlatform)
+	if err != nil {
+		return err
+	}
+
+	// Prevent alphabetical sorting of usage message
+	compareCmd.Flags().SortFlags = false
+
+	// Config file
+	compareCmd.Flags().String("config", "", "path to the config file of the baseline campaign")
+
+	// Candidate config file
+	compareCmd.Flags().String("candidate-config", "",
+		"path to the config file of the candidate campaign (default is the config file of the baseline campaign)")
+
+	// Compilation Target
+	compareCmd.Flags().String("compilation-target", "", TargetFlagDescription)
+
+	// Timeout
+	compareCmd.Flags().Int("timeout", 0,
+		fmt.Sprintf("number of seconds to run each campaign for (unless a config file is provided, default is %d). 0 means that timeout is not enforced", defaultConfig.Fuzzing.Timeout))
+
+	// Test limit
+	compareCmd.Flags().Uint64("test-limit", 0,
+		fmt.Sprintf("number of transactions to test in each campaign before exiting (unless a config file is provided, default is %d). 0 means that test limit is not enforced", defaultConfig.Fuzzing.TestLimit))
+
+	// Seed
+	compareCmd.Flags().Int64("seed", 0, "seed to run both campaigns with (unless a config file is provided, a random seed is chosen)")
+
+	// Candidate tx sequence length
+	compareCmd.Flags().Int("candidate-seq-len", 0, "maximum transactions to run in sequence in the candidate campaign (default is the candidate config's value)")
+
+	// Target contracts
+	compareCmd.Flags().StringSlice("target-contracts", []string{},
+		fmt.Sprintf("target contracts for fuzz testing (unless a config file is provided, default is %v)", defaultConfig.Fuzzing.TargetContracts))
+
+	// Output directory
+	compareCmd.Flags().String("output-dir", "comparison", "directory to write each campaign's corpus and the comparison reports to")
+
+	// Logging color
+	compareCmd.Flags().Bool("no-color", false, "disables colored terminal output")
+	return nil
+}
+
+// updateProjectConfigWithCompareFlags will update the given projectConfig with any CLI arguments that were provided to
+// the compare command which apply to both campaigns
+func updateProjectConfigWithCompareFlags(cmd *cobra.Command, projectConfig *config.ProjectConfig) error {
+	var err error
+
+	// If --compilation-target was used
+	if cmd.Flags().Changed("compilation-target") {
+		// Get the new target
+		newTarget, err := cmd.Flags().GetString("compilation-target")
+		if err != nil {
+			return err
+		}
+		err = projectConfig.Compilation.SetTarget(newTarget)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update timeout
+	if cmd.Flags().Changed("timeout") {
+		projectConfig.Fuzzing.Timeout, err = cmd.Flags().GetInt("timeout")
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update test limit
+	if cmd.Flags().Changed("test-limit") {
+		projectConfig.Fuzzing.TestLimit, err = cmd.Flags().GetUint64("test-limit")
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update seed
+	if cmd.Flags().Changed("seed") {
+		projectConfig.Fuzzing.Seed, err = cmd.Flags().GetInt64("seed")
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update target contracts
+	if cmd.Flags().Changed("target-contracts") {
+		projectConfig.Fuzzing.TargetContracts, err = cmd.Flags().GetStringSlice("target-contracts")
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update logging color mode
+	if cmd.Flags().Changed("no-color") {
+		projectConfig.Logging.NoColor, err = cmd.Flags().GetBool("no-color")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/crytic/medusa/cmd/exitcodes"
+	"github.com/crytic/medusa/fuzzing"
+	"github.com/spf13/cobra"
+)
+
+// coverageCmd represents the command provider for regenerating coverage reports
+var coverageCmd = &cobra.Command{
+	Use:               "coverage",
+	Short:             "Regenerates coverage reports from an existing corpus",
+	Long:              `Regenerates coverage reports by replaying an existing corpus, without fuzzing`,
+	Args:              cmdValidateCoverageArgs,
+	ValidArgsFunction: cmdValidFuzzArgs,
+	RunE:              cmdRunCoverage,
+	SilenceUsage:      true,
+	SilenceErrors:     true,
+}
+
+func init() {
+	// Add all the flags allowed for the coverage command
+	err := addCoverageFlags()
+	if err != nil {
+		cmdLogger.Panic("Failed to initialize the coverage command", err)
+	}
+
+	// Add the coverage command and its associated flags to the root command
+	rootCmd.AddCommand(coverageCmd)
+}
+
+// cmdValidateCoverageArgs makes sure that there are no positional arguments provided to the coverage command
+func cmdValidateCoverageArgs(cmd *cobra.Command, args []string) error {
+	// Make sure we have no positional args
+	if err := cobra.NoArgs(cmd, args); err != nil {
+		err = fmt.Errorf("coverage does not accept any positional arguments, only flags and their associated values")
+		cmdLogger.Error("Failed to validate args to the coverage command", err)
+		return err
+	}
+	return nil
+}
+
+// cmdRunCoverage executes the CLI coverage command. The project configuration is read with readProjectConfig and
+// updated with any flags provided, then the corpus is replayed to write the requested coverage reports.
+func cmdRunCoverage(cmd *cobra.Command, args []string) error {
+	// Read our project configuration
+	projectConfig, configPath, err := readProjectConfig(cmd, "coverage")
+	if err != nil {
+		return err
+	}
+
+	// Update the project configuration given whatever flags were set using the CLI
+	err = updateProjectConfigWithCoverageFlags(cmd, projectConfig)
+	if err != nil {
+		cmdLogger.Error("Failed to run the coverage command", err)
+		return err
+	}
+
+	// We are only replaying the corpus, so we disable testing to avoid registering any test case providers.
+	projectConfig.Fuzzing.Testing.Enabled = false
+	projectConfig.Fuzzing.Testing.StopOnFailedTest = false
+	projectConfig.Fuzzing.Testing.StopOnNoTests = false
+
+	// Change our working directory to the parent directory of the project configuration file, so that paths in the
+	// configuration are resolved as they are when fuzzing.
+	err = os.Chdir(filepath.Dir(configPath))
+	if err != nil {
+		cmdLogger.Error("Failed to run the coverage command", err)
+		return err
+	}
+
+	// Create our fuzzer, which compiles our targets
+	fuzzer, err := fuzzing.NewFuzzer(*projectConfig)
+	if err != nil {
+		return exitcodes.NewErrorWithExitCode(err, exitcodes.ExitCodeHandledError)
+	}
+
+	// Replay the corpus and write our coverage reports
+	_, err = fuzzer.RegenerateCoverageReports()
+	if err != nil {
+		cmdLogger.Error("Failed to regenerate coverage reports", err)
+		return exitcodes.NewErrorWithExitCode(err, exitcodes.ExitCodeHandledError)
+	}
+	return nil
+}
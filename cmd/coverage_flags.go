@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/spf13/cobra"
+)
+
+// addCoverageFlags adds the various flags for the coverage command
+func addCoverageFlags() error {
+	// Get the default project config and throw an error if we cant
+	defaultConfig, err := config.GetDefaultProjectConfig(DefaultCompilationPlatform)
+	if err != nil {
+		return err
+	}
+
+	// Prevent alphabetical sorting of usage message
+	coverageCmd.Flags().SortFlags = false
+
+	// Config file
+	coverageCmd.Flags().String("config", "", "path to config file")
+
+	// Compilation Target
+	coverageCmd.Flags().String("compilation-target", "", TargetFlagDescription)
+
+	// Corpus directory
+	coverageCmd.Flags().String("corpus-dir", "",
+		fmt.Sprintf("directory path for corpus items to replay and coverage reports (unless a config file is provided, default is %q)", defaultConfig.Fuzzing.CorpusDirectory))
+
+	// Coverage formats
+	coverageCmd.Flags().StringSlice("formats", []string{},
+		fmt.Sprintf("coverage report formats to generate: lcov, html, or json (unless a config file is provided, default is %v)", defaultConfig.Fuzzing.CoverageFormats))
+
+	// Coverage exclusions
+	coverageCmd.Flags().StringSlice("exclude", []string{},
+		"source file paths, directories, or glob patterns to omit from coverage reports, replacing those in the config file")
+
+	// Coverage base path
+	coverageCmd.Flags().String("base-path", "",
+		"path which source file paths are made relative to in the JSON coverage report")
+
+	// Exclude setup coverage
+	coverageCmd.Flags().Bool("exclude-setup", false,
+		fmt.Sprintf("exclude lines only covered during deployment and setup from covered line counts (unless a config file is provided, default is %t)", defaultConfig.Fuzzing.ExcludeSetupCoverage))
+
+	// Logging color
+	coverageCmd.Flags().Bool("no-color", false, "disables colored terminal output")
+	return nil
+}
+
+// updateProjectConfigWithCoverageFlags will update the given projectConfig with any CLI arguments that were provided to
+// the coverage command
+func updateProjectConfigWithCoverageFlags(cmd *cobra.Command, projectConfig *config.ProjectConfig) error {
+	var err error
+
+	// If --compilation-target was used
+	if cmd.Flags().Changed("compilation-target") {
+		// Get the new target
+		newTarget, err := cmd.Flags().GetString("compilation-target")
+		if err != nil {
+			return err
+		}
+		err = projectConfig.Compilation.SetTarget(newTarget)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update corpus directory
+	if cmd.Flags().Changed("corpus-dir") {
+		projectConfig.Fuzzing.CorpusDirectory, err = cmd.Flags().GetString("corpus-dir")
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update coverage formats
+	if cmd.Flags().Changed("formats") {
+		projectConfig.Fuzzing.CoverageFormats, err = cmd.Flags().GetStringSlice("formats")
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update coverage exclusions
+	if cmd.Flags().Changed("exclude") {
+		projectConfig.Fuzzing.CoverageExclusions, err = cmd.Flags().GetStringSlice("exclude")
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update coverage base path
+	if cmd.Flags().Changed("base-path") {
+		projectConfig.Fuzzing.CoverageBasePath, err = cmd.Flags().GetString("base-path")
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update exclude setup coverage
+	if cmd.Flags().Changed("exclude-setup") {
+		projectConfig.Fuzzing.ExcludeSetupCoverage, err = cmd.Flags().GetBool("exclude-setup")
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update logging color mode
+	if cmd.Flags().Changed("no-color") {
+		projectConfig.Logging.NoColor, err = cmd.Flags().GetBool("no-color")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
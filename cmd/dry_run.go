@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/crytic/medusa/cmd/exitcodes"
+	"github.com/crytic/medusa/fuzzing"
+	"github.com/spf13/cobra"
+)
+
+// dryRunCmd represents the command provider for checking what a fuzzing campaign would fuzz
+var dryRunCmd = &cobra.Command{
+	Use:               "dry-run",
+	Short:             "Sets up a fuzzing campaign and reports what would be fuzzed",
+	Long:              `Compiles the project and sets up its deployments as a fuzzing campaign would, then reports the contracts, methods, tests, and accounts which would be fuzzed, without fuzzing`,
+	Args:              cmdValidateDryRunArgs,
+	ValidArgsFunction: cmdValidFuzzArgs,
+	RunE:              cmdRunDryRun,
+	SilenceUsage:      true,
+	SilenceErrors:     true,
+}
+
+func init() {
+	// Add all the flags allowed for the dry-run command
+	err := addDryRunFlags()
+	if err != nil {
+		cmdLogger.Panic("Failed to initialize the dry-run command", err)
+	}
+
+	// Add the dry-run command and its associated flags to the root command
+	rootCmd.AddCommand(dryRunCmd)
+}
+
+// cmdValidateDryRunArgs makes sure that there are no positional arguments provided to the dry-run command
+func cmdValidateDryRunArgs(cmd *cobra.Command, args []string) error {
+	// Make sure we have no positional args
+	if err := cobra.NoArgs(cmd, args); err != nil {
+		err = fmt.Errorf("dry-run does not accept any positional arguments, only flags and their associated values")
+		cmdLogger.Error("Failed to validate args to the dry-run command", err)
+		return err
+	}
+	return nil
+}
+
+// cmdRunDryRun executes the CLI dry-run command. The project configuration is read with readProjectConfig and updated
+// with any flags provided, then the campaign is set up and the report of what would be fuzzed is printed and written.
+func cmdRunDryRun(cmd *cobra.Command, args []string) error {
+	// Read our project configuration
+	projectConfig, configPath, err := readProjectConfig(cmd, "dry-run")
+	if err != nil {
+		return err
+	}
+
+	// Update the project configuration given whatever flags were set using the CLI
+	err = updateProjectConfigWithDryRunFlags(cmd, projectConfig)
+	if err != nil {
+		cmdLogger.Error("Failed to run the dry-run command", err)
+		return err
+	}
+
+	// Resolve our output path before changing our working directory, so it is relative to where we were invoked.
+	outputPath, err := cmd.Flags().GetString("output")
+	if err != nil {
+		cmdLogger.Error("Failed to run the dry-run command", err)
+		return err
+	}
+	outputPath, err = filepath.Abs(outputPath)
+	if err != nil {
+		cmdLogger.Error("Failed to run the dry-run command", err)
+		return err
+	}
+
+	// Change our working directory to the parent directory of the project configuration file, so that paths in the
+	// configuration are resolved as they are when fuzzing.
+	err = os.Chdir(filepath.Dir(configPath))
+	if err != nil {
+		cmdLogger.Error("Failed to run the dry-run command", err)
+		return err
+	}
+
+	// Create our fuzzer, which compiles our targets
+	fuzzer, err := fuzzing.NewFuzzer(*projectConfig)
+	if err != nil {
+		return exitcodes.NewErrorWithExitCode(err, exitcodes.ExitCodeHandledError)
+	}
+
+	// Set up the campaign and report what would be fuzzed
+	report, err := fuzzer.DryRun()
+	if err != nil {
+		cmdLogger.Error("Failed to set up the fuzzing campaign", err)
+		return exitcodes.NewErrorWithExitCode(err, exitcodes.ExitCodeHandledError)
+	}
+	cmdLogger.Info(report.Log().Elements()...)
+
+	// Write the report
+	err = report.WriteToFile(outputPath)
+	if err != nil {
+		cmdLogger.Error("Failed to write the dry-run report", err)
+		return exitcodes.NewErrorWithExitCode(err, exitcodes.ExitCodeHandledError)
+	}
+	cmdLogger.Info("Dry-run report saved to: ", outputPath)
+	return nil
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/spf13/cobra"
+)
+
+// addDryRunFlags adds the various flags for the dry-run command
+func addDryRunFlags() error {
+	// Get the default project config and throw an error if we cant
+	defaultConfig, err := config.GetDefaultProjectConfig(DefaultCompilationPlatform)
+	if err != nil {
+		return err
+	}
+
+	// Prevent alphabetical sorting of usage message
+	dryRunCmd.Flags().SortFlags = false
+
+	// Config file
+	dryRunCmd.Flags().String("config", "", "path to config file")
+
+	// Compilation Target
+	dryRunCmd.Flags().String("compilation-target", "", TargetFlagDescription)
+
+	// Target contracts
+	dryRunCmd.Flags().StringSlice("target-contracts", []string{},
+		fmt.Sprintf("target contracts for fuzz testing (unless a config file is provided, default is %v)", defaultConfig.Fuzzing.TargetContracts))
+
+	// Output path
+	dryRunCmd.Flags().String("output", "dry_run.json", "path to write the JSON dry-run report to")
+
+	// Logging color
+	dryRunCmd.Flags().Bool("no-color", false, "disables colored terminal output")
+	return nil
+}
+
+// updateProjectConfigWithDryRunFlags will update the given projectConfig with any CLI arguments that were provided to
+// the dry-run command
+func updateProjectConfigWithDryRunFlags(cmd *cobra.Command, projectConfig *config.ProjectConfig) error {
+	var err error
+
+	// If --compilation-target was used
+	if cmd.Flags().Changed("compilation-target") {
+		// Get the new target
+		newTarget, err := cmd.Flags().GetString("compilation-target")
+		if err != nil {
+			return err
+		}
+		err = projectConfig.Compilation.SetTarget(newTarget)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update target contracts
+	if cmd.Flags().Changed("target-contracts") {
+		projectConfig.Fuzzing.TargetContracts, err = cmd.Flags().GetStringSlice("target-contracts")
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update logging color mode
+	if cmd.Flags().Changed("no-color") {
+		projectConfig.Logging.NoColor, err = cmd.Flags().GetBool("no-color")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
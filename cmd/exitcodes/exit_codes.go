@@ -22,4 +22,7 @@ const (
 
 	// ExitCodeTestFailed indicates a test case had failed.
 	ExitCodeTestFailed = 7
+
+	// ExitCodeStuckCampaign indicates the fuzzing campaign was stopped because nearly every call it tested reverted.
+	ExitCodeStuckCampaign = 8
 )
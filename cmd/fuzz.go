@@ -70,28 +70,30 @@ func cmdValidateFuzzArgs(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// cmdRunFuzz executes the CLI fuzz command and navigates through the following possibilities:
+// readProjectConfig reads the project configuration for the provided command, navigating through the following
+// possibilities:
 // #1: We will search for either a custom config file (via --config) or the default (medusa.json).
 // If we find it, read it. If we can't read it, throw an error.
 // #2: If a custom file was provided (--config was used), and we can't find the file, throw an error.
 // #3: If medusa.json can't be found, use the default project configuration.
-func cmdRunFuzz(cmd *cobra.Command, args []string) error {
+// Returns the project configuration and the path of the configuration file, or an error if one occurs.
+func readProjectConfig(cmd *cobra.Command, commandName string) (*config.ProjectConfig, string, error) {
 	var projectConfig *config.ProjectConfig
 
 	// Check to see if --config flag was used and store the value of --config flag
 	configFlagUsed := cmd.Flags().Changed("config")
 	configPath, err := cmd.Flags().GetString("config")
 	if err != nil {
-		cmdLogger.Error("Failed to run the fuzz command", err)
-		return err
+		cmdLogger.Error("Failed to run the "+commandName+" command", err)
+		return nil, "", err
 	}
 
 	// If --config was not used, look for `medusa.json` in the current work directory
 	if !configFlagUsed {
 		workingDirectory, err := os.Getwd()
 		if err != nil {
-			cmdLogger.Error("Failed to run the fuzz command", err)
-			return err
+			cmdLogger.Error("Failed to run the "+commandName+" command", err)
+			return nil, "", err
 		}
 		configPath = filepath.Join(workingDirectory, DefaultProjectConfigFilename)
 	}
@@ -106,15 +108,15 @@ func cmdRunFuzz(cmd *cobra.Command, args []string) error {
 		// Use the default compilation platform if the config file doesn't specify one
 		projectConfig, err = config.ReadProjectConfigFromFile(configPath, DefaultCompilationPlatform)
 		if err != nil {
-			cmdLogger.Error("Failed to run the fuzz command", err)
-			return err
+			cmdLogger.Error("Failed to run the "+commandName+" command", err)
+			return nil, "", err
 		}
 	}
 
 	// Possibility #2: If the --config flag was used, and we couldn't find the file, we'll throw an error
 	if configFlagUsed && existenceError != nil {
-		cmdLogger.Error("Failed to run the fuzz command", err)
-		return existenceError
+		cmdLogger.Error("Failed to run the "+commandName+" command", existenceError)
+		return nil, "", existenceError
 	}
 
 	// Possibility #3: --config flag was not used and medusa.json was not found, so use the default project config
@@ -124,11 +126,23 @@ func cmdRunFuzz(cmd *cobra.Command, args []string) error {
 
 		projectConfig, err = config.GetDefaultProjectConfig(DefaultCompilationPlatform)
 		if err != nil {
-			cmdLogger.Error("Failed to run the fuzz command", err)
-			return err
+			cmdLogger.Error("Failed to run the "+commandName+" command", err)
+			return nil, "", err
 		}
 	}
 
+	return projectConfig, configPath, nil
+}
+
+// cmdRunFuzz executes the CLI fuzz command, reading the project configuration with readProjectConfig and updating it
+// with any flags provided, then running a fuzzing campaign.
+func cmdRunFuzz(cmd *cobra.Command, args []string) error {
+	// Read our project configuration
+	projectConfig, configPath, err := readProjectConfig(cmd, "fuzz")
+	if err != nil {
+		return err
+	}
+
 	// Update the project configuration given whatever flags were set using the CLI
 	err = updateProjectConfigWithFuzzFlags(cmd, projectConfig)
 	if err != nil {
@@ -175,5 +189,10 @@ func cmdRunFuzz(cmd *cobra.Command, args []string) error {
 		return exitcodes.NewErrorWithExitCode(fuzzErr, exitcodes.ExitCodeTestFailed)
 	}
 
+	// If the campaign was stopped because it was stuck, we'll want to return a special exit code
+	if fuzzer.StuckCampaignAborted() {
+		return exitcodes.NewErrorWithExitCode(fuzzErr, exitcodes.ExitCodeStuckCampaign)
+	}
+
 	return fuzzErr
 }
@@ -195,6 +195,7 @@ func updateProjectConfigWithFuzzFlags(cmd *cobra.Command, projectConfig *config.
 			return err
 		}
 		if explore {
+			projectConfig.Fuzzing.Testing.Enabled = false
 			projectConfig.Fuzzing.Testing.StopOnFailedTest = false
 			projectConfig.Fuzzing.Testing.StopOnNoTests = false
 			projectConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
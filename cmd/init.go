@@ -130,6 +130,33 @@ func cmdRunInit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// If an echidna config was provided, the projectConfig will be converted from it, using the default project
+	// config for the selected compilation platform as a base.
+	if cmd.Flags().Changed("from-echidna") {
+		echidnaConfigPath, err := cmd.Flags().GetString("from-echidna")
+		if err != nil {
+			cmdLogger.Error("Failed to run the init command", err)
+			return err
+		}
+
+		platform := DefaultCompilationPlatform
+		if len(args) == 1 {
+			platform = args[0]
+		}
+
+		var unsupportedFields []string
+		projectConfig, unsupportedFields, err = config.ReadEchidnaConfigFromFile(echidnaConfigPath, platform)
+		if err != nil {
+			cmdLogger.Error("Failed to run the init command", err)
+			return err
+		}
+
+		// Warn the user about any fields we could not carry over
+		for _, field := range unsupportedFields {
+			cmdLogger.Warn("The echidna config field ", colors.Bold, field, colors.Reset, " has no medusa equivalent and was ignored")
+		}
+	}
+
 	// Update the project configuration given whatever flags were set using the CLI
 	err = updateProjectConfigWithInitFlags(cmd, projectConfig)
 	if err != nil {
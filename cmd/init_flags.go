@@ -13,6 +13,9 @@ func addInitFlags() error {
 	// Target file / directory for compilation
 	initCmd.Flags().String("compilation-target", "", TargetFlagDescription)
 
+	// Echidna config to convert
+	initCmd.Flags().String("from-echidna", "", "path to an echidna config file to convert into the new project configuration")
+
 	return nil
 }
 
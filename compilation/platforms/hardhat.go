@@ -0,0 +1,130 @@
+package platforms
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/crytic/medusa/compilation/types"
+	"github.com/crytic/medusa/logging"
+)
+
+// HardhatCompilationConfig represents the various configuration options that can be provided by the user
+// while using the `hardhat` platform. This platform does not invoke a compiler, it ingests the build-info artifacts
+// produced by a previous `npx hardhat compile`.
+type HardhatCompilationConfig struct {
+	// Target is the root directory of the Hardhat project.
+	Target string `json:"target"`
+
+	// BuildInfoDirectory is the location to search for Hardhat build-info files, relative to the Target. By default,
+	// we look in `artifacts/build-info`.
+	BuildInfoDirectory string `json:"buildInfoDirectory"`
+}
+
+// hardhatBuildInfo describes the structure of a Hardhat build-info file, which wraps the solc standard JSON input
+// and output for a single compiler invocation.
+type hardhatBuildInfo struct {
+	// Format describes the version of the build-info format (e.g. "hh-sol-build-info-1").
+	Format string `json:"_format"`
+
+	// SolcVersion describes the version of solc used to produce the build-info.
+	SolcVersion string `json:"solcVersion"`
+
+	// Input describes the solc standard JSON input provided to the compiler.
+	Input standardJSONInput `json:"input"`
+
+	// Output describes the solc standard JSON output returned by the compiler.
+	Output standardJSONOutput `json:"output"`
+}
+
+// Platform returns the platform type
+func (h *HardhatCompilationConfig) Platform() string {
+	return "hardhat"
+}
+
+// GetTarget returns the target for compilation
+func (h *HardhatCompilationConfig) GetTarget() string {
+	return h.Target
+}
+
+// SetTarget sets the new target for compilation
+func (h *HardhatCompilationConfig) SetTarget(newTarget string) {
+	h.Target = newTarget
+}
+
+// NewHardhatCompilationConfig returns the default configuration options while using the `hardhat` platform
+func NewHardhatCompilationConfig(target string) *HardhatCompilationConfig {
+	return &HardhatCompilationConfig{
+		Target:             target,
+		BuildInfoDirectory: "",
+	}
+}
+
+// getBuildInfoDirectory returns the directory which build-info files should be read from.
+func (h *HardhatCompilationConfig) getBuildInfoDirectory() string {
+	buildInfoDirectory := h.BuildInfoDirectory
+	if buildInfoDirectory == "" {
+		buildInfoDirectory = filepath.Join("artifacts", "build-info")
+	}
+	if filepath.IsAbs(buildInfoDirectory) {
+		return buildInfoDirectory
+	}
+	return filepath.Join(h.Target, buildInfoDirectory)
+}
+
+// Compile reads the Hardhat build-info files for the HardhatCompilationConfig target and parses them into a list of
+// types.Compilation. Each build-info file corresponds to a single compiler invocation, so projects using multiple
+// compiler versions will produce a separate compilation per build-info file.
+func (h *HardhatCompilationConfig) Compile() ([]types.Compilation, string, error) {
+	// Find all build-info files in our build-info directory
+	buildInfoDirectory := h.getBuildInfoDirectory()
+	matches, err := filepath.Glob(filepath.Join(buildInfoDirectory, "*.json"))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(matches) == 0 {
+		return nil, "", fmt.Errorf("could not find any hardhat build-info files in '%s', ensure `npx hardhat compile` was run prior to fuzzing", buildInfoDirectory)
+	}
+
+	// Create a slice to track all our compilations parsed.
+	var compilationList []types.Compilation
+
+	// Loop through each build-info file.
+	for _, match := range matches {
+		// Read the build-info file data
+		b, err := os.ReadFile(match)
+		if err != nil {
+			return nil, "", fmt.Errorf("could not read hardhat build-info file at path '%s', error: %v", match, err)
+		}
+
+		// Parse the JSON
+		var buildInfo hardhatBuildInfo
+		err = json.Unmarshal(b, &buildInfo)
+		if err != nil {
+			return nil, "", fmt.Errorf("could not parse hardhat build-info file at path '%s', error: %v", match, err)
+		}
+
+		// If the compiler reported errors, the artifacts are not usable.
+		if errorMessages := buildInfo.Output.errorMessages(); len(errorMessages) > 0 {
+			return nil, "", fmt.Errorf("hardhat build-info file at path '%s' contains compilation errors:\n%s", match, strings.Join(errorMessages, "\n"))
+		}
+
+		// The build-info input contains all source code provided to the compiler, so we use it to populate our source
+		// code cache. Source paths are relative to the project root.
+		sourceCode := buildInfo.Input.sourceCode(h.Target)
+
+		// Parse the standard JSON output into a compilation.
+		compilation, err := parseStandardJSONOutput(&buildInfo.Output, sourceCode)
+		if err != nil {
+			return nil, "", fmt.Errorf("could not parse hardhat build-info file at path '%s', error: %v", match, err)
+		}
+		logging.GlobalLogger.Debug("Loaded hardhat build-info file ", match, " (solc ", buildInfo.SolcVersion, ")")
+
+		compilationList = append(compilationList, *compilation)
+	}
+
+	// Return the compilationList
+	return compilationList, "", nil
+}
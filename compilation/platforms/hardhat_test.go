@@ -0,0 +1,72 @@
+package platforms
+
+import (
+	"testing"
+
+	"github.com/crytic/medusa/compilation/types"
+	"github.com/crytic/medusa/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHardhatBuildInfoMultipleCompilers tests loading a Hardhat project whose build-info directory contains multiple
+// build-info files (one per compiler version). Each should be parsed into its own compilation.
+func TestHardhatBuildInfoMultipleCompilers(t *testing.T) {
+	// Copy our testdata over to our testing directory
+	projectDirectory := testutils.CopyToTestDirectory(t, "testdata/hardhat/build_info_project/")
+
+	// Execute our tests in the given test path
+	testutils.ExecuteInDirectory(t, projectDirectory, func() {
+		// Create our platform configuration
+		config := NewHardhatCompilationConfig(".")
+
+		// Load the build-info files
+		compilations, _, err := config.Compile()
+		assert.NoError(t, err)
+
+		// Two compilations, as two compiler versions were used.
+		assert.EqualValues(t, 2, len(compilations))
+
+		// Each compilation should contain a single source with a single contract.
+		contractNames := make(map[string]bool)
+		for _, compilation := range compilations {
+			assert.EqualValues(t, 1, len(compilation.SourcePathToArtifact))
+			for sourcePath, source := range compilation.SourcePathToArtifact {
+				// The source unit ID should be resolvable to the source path.
+				assert.EqualValues(t, sourcePath, compilation.SourceIdToPath[source.SourceUnitId])
+				assert.NotNil(t, source.Ast)
+
+				// Source code should be cached from the build-info input, for use in coverage reports.
+				assert.NotEmpty(t, compilation.SourceCode[sourcePath])
+
+				assert.EqualValues(t, 1, len(source.Contracts))
+				for contractName, contract := range source.Contracts {
+					contractNames[contractName] = true
+					assert.EqualValues(t, types.ContractKindContract, contract.Kind)
+					assert.NotEmpty(t, contract.InitBytecode)
+					assert.NotEmpty(t, contract.RuntimeBytecode)
+					assert.NotEmpty(t, contract.SrcMapsInit)
+					assert.NotEmpty(t, contract.SrcMapsRuntime)
+					assert.NotNil(t, contract.Abi.Methods["value"])
+				}
+			}
+		}
+		assert.True(t, contractNames["FirstContract"])
+		assert.True(t, contractNames["SecondContract"])
+	})
+}
+
+// TestHardhatBuildInfoMissing tests that loading a Hardhat project without any build-info files results in an error.
+func TestHardhatBuildInfoMissing(t *testing.T) {
+	// Copy our testdata over to our testing directory
+	projectDirectory := testutils.CopyToTestDirectory(t, "testdata/hardhat/basic_project/")
+
+	// Execute our tests in the given test path
+	testutils.ExecuteInDirectory(t, projectDirectory, func() {
+		// Create our platform configuration
+		config := NewHardhatCompilationConfig(".")
+
+		// The project was never compiled, so there are no build-info files to load.
+		_, _, err := config.Compile()
+		assert.Error(t, err)
+	})
+}
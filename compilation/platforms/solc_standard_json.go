@@ -0,0 +1,126 @@
+package platforms
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/crytic/medusa/compilation/types"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/utils"
+)
+
+// SolcStandardJSONCompilationConfig represents the various configuration options that can be provided by the user
+// while using the `solc-standard-json` platform. This platform compiles a hand-crafted solc standard JSON input, so
+// that compiler settings such as optimizer options or remappings are used exactly as provided.
+type SolcStandardJSONCompilationConfig struct {
+	// Target is the path to the solc standard JSON input file.
+	Target string `json:"target"`
+
+	// SolcPath is the path to the solc binary used to compile the standard JSON input. By default, `solc` is resolved
+	// from the system path.
+	SolcPath string `json:"solcPath"`
+
+	// SolcVersion is the version of `solc` that will be installed prior to compiling. If empty, no special version is
+	// installed prior to compilation.
+	SolcVersion string `json:"solcVersion"`
+}
+
+// Platform returns the platform type
+func (s *SolcStandardJSONCompilationConfig) Platform() string {
+	return "solc-standard-json"
+}
+
+// GetTarget returns the target for compilation
+func (s *SolcStandardJSONCompilationConfig) GetTarget() string {
+	return s.Target
+}
+
+// SetTarget sets the new target for compilation
+func (s *SolcStandardJSONCompilationConfig) SetTarget(newTarget string) {
+	s.Target = newTarget
+}
+
+// NewSolcStandardJSONCompilationConfig returns the default configuration options while using the
+// `solc-standard-json` platform
+func NewSolcStandardJSONCompilationConfig(target string) *SolcStandardJSONCompilationConfig {
+	return &SolcStandardJSONCompilationConfig{
+		Target:      target,
+		SolcPath:    "",
+		SolcVersion: "",
+	}
+}
+
+// Compile uses the SolcStandardJSONCompilationConfig provided to compile the standard JSON input target with solc,
+// and parses the standard JSON output into a list of types.Compilation.
+func (s *SolcStandardJSONCompilationConfig) Compile() ([]types.Compilation, string, error) {
+	// Read and parse our standard JSON input
+	b, err := os.ReadFile(s.Target)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not read solc standard JSON input at path '%s', error: %v", s.Target, err)
+	}
+	var input standardJSONInput
+	err = json.Unmarshal(b, &input)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not parse solc standard JSON input at path '%s', error: %v", s.Target, err)
+	}
+
+	// Ensure the outputs we rely upon are selected, warning the user if we had to add any.
+	addedOutputs := input.ensureOutputSelection()
+	if len(addedOutputs) > 0 {
+		logging.GlobalLogger.Warn("The solc standard JSON input did not select all outputs required by medusa, the following were added: ", strings.Join(addedOutputs, ", "))
+	}
+	b, err = json.Marshal(input)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not encode solc standard JSON input, error: %v", err)
+	}
+
+	// Install a specific `solc` version if requested in the config
+	if s.SolcVersion != "" {
+		out, err := exec.Command("solc-select", "install", s.SolcVersion).CombinedOutput()
+		if err != nil {
+			return nil, "", fmt.Errorf("error while executing `solc-select install`:\nOUTPUT:\n%s\nERROR: %s\n", string(out), err.Error())
+		}
+		out, err = exec.Command("solc-select", "use", s.SolcVersion).CombinedOutput()
+		if err != nil {
+			return nil, "", fmt.Errorf("error while executing `solc-select use`:\nOUTPUT:\n%s\nERROR: %s\n", string(out), err.Error())
+		}
+	}
+
+	// Create our command. Source paths in the standard JSON input are relative to the input file, so we compile from
+	// its directory.
+	solcPath := s.SolcPath
+	if solcPath == "" {
+		solcPath = "solc"
+	}
+	baseDirectory := filepath.Dir(s.Target)
+	cmd := exec.Command(solcPath, "--standard-json", "--allow-paths", ".")
+	cmd.Dir = baseDirectory
+	cmd.Stdin = bytes.NewReader(b)
+	logging.GlobalLogger.Info("Running command:\n", cmd.String())
+	cmdStdout, _, cmdCombined, err := utils.RunCommandWithOutputAndError(cmd)
+	if err != nil {
+		return nil, "", fmt.Errorf("error while executing solc:\n%s\n\nCommand Output:\n%s\n", err.Error(), string(cmdCombined))
+	}
+
+	// Parse the standard JSON output
+	var output standardJSONOutput
+	err = json.Unmarshal(cmdStdout, &output)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not parse solc standard JSON output, error: %v", err)
+	}
+	if errorMessages := output.errorMessages(); len(errorMessages) > 0 {
+		return nil, "", fmt.Errorf("solc reported compilation errors:\n%s", strings.Join(errorMessages, "\n"))
+	}
+
+	// Parse our compilation, caching the source code provided in the standard JSON input.
+	compilation, err := parseStandardJSONOutput(&output, input.sourceCode(baseDirectory))
+	if err != nil {
+		return nil, "", err
+	}
+	return []types.Compilation{*compilation}, string(cmdCombined), nil
+}
@@ -0,0 +1,96 @@
+package platforms
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/crytic/medusa/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSolcStandardJSONOutputSelection tests that outputs required by medusa are added to a standard JSON input's
+// output selection only if they are not already covered by it.
+func TestSolcStandardJSONOutputSelection(t *testing.T) {
+	tests := []struct {
+		// settings describes the standard JSON input settings prior to ensuring the output selection.
+		settings map[string]any
+
+		// expectedAdded describes the outputs which are expected to be added.
+		expectedAdded []string
+	}{
+		{
+			settings:      nil,
+			expectedAdded: append([]string{"ast"}, standardJSONRequiredOutputs...),
+		},
+		{
+			settings: map[string]any{
+				"outputSelection": map[string]any{
+					"*": map[string]any{"*": []any{"abi"}},
+				},
+			},
+			expectedAdded: append([]string{"ast"}, standardJSONRequiredOutputs[1:]...),
+		},
+		{
+			settings: map[string]any{
+				"outputSelection": map[string]any{
+					"*": map[string]any{"*": []any{"abi", "evm.bytecode", "evm.deployedBytecode"}, "": []any{"ast"}},
+				},
+			},
+			expectedAdded: nil,
+		},
+		{
+			settings: map[string]any{
+				"outputSelection": map[string]any{
+					"*": map[string]any{"*": []any{"*"}, "": []any{"*"}},
+				},
+			},
+			expectedAdded: nil,
+		},
+	}
+
+	for _, test := range tests {
+		input := standardJSONInput{Settings: test.settings}
+		added := input.ensureOutputSelection()
+		assert.EqualValues(t, test.expectedAdded, added)
+
+		// Optional outputs should be selected, without being reported as added.
+		contractSelection := input.Settings["outputSelection"].(map[string]any)["*"].(map[string]any)["*"].([]any)
+		for _, optionalOutput := range standardJSONOptionalOutputs {
+			assert.True(t, slices.ContainsFunc(contractSelection, func(output any) bool {
+				return output == optionalOutput || output == "*"
+			}))
+		}
+
+		// Ensuring the output selection again should not add anything further.
+		assert.Empty(t, input.ensureOutputSelection())
+	}
+}
+
+// TestSolcStandardJSONCompilation tests that a standard JSON input can be compiled, producing the artifacts needed
+// for deployment and coverage even though the input did not select them.
+func TestSolcStandardJSONCompilation(t *testing.T) {
+	// Copy our testdata over to our testing directory
+	directory := testutils.CopyToTestDirectory(t, "testdata/solc_standard_json/")
+
+	// Execute our tests in the given test path
+	testutils.ExecuteInDirectory(t, directory, func() {
+		// Create our platform configuration
+		config := NewSolcStandardJSONCompilationConfig("standard_input.json")
+
+		// Compile the standard JSON input
+		compilations, _, err := config.Compile()
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, len(compilations))
+
+		// Verify the contract artifacts and source code were populated
+		source, ok := compilations[0].SourcePathToArtifact["contracts/StandardJSONContract.sol"]
+		assert.True(t, ok)
+		assert.NotNil(t, source.Ast)
+		assert.NotEmpty(t, compilations[0].SourceCode["contracts/StandardJSONContract.sol"])
+		contract, ok := source.Contracts["StandardJSONContract"]
+		assert.True(t, ok)
+		assert.NotEmpty(t, contract.InitBytecode)
+		assert.NotEmpty(t, contract.RuntimeBytecode)
+		assert.NotEmpty(t, contract.SrcMapsRuntime)
+	})
+}
@@ -0,0 +1,291 @@
+package platforms
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/crytic/medusa/compilation/types"
+)
+
+// standardJSONInput describes the subset of solc's standard JSON input format which medusa relies upon.
+type standardJSONInput struct {
+	// Language describes the language of the sources being compiled (e.g. "Solidity").
+	Language string `json:"language"`
+
+	// Sources describes a mapping of source paths to their source unit descriptors.
+	Sources map[string]standardJSONInputSource `json:"sources"`
+
+	// Settings describes the compiler settings provided in the standard JSON input.
+	Settings map[string]any `json:"settings"`
+}
+
+// standardJSONInputSource describes a single source unit provided in solc's standard JSON input format.
+type standardJSONInputSource struct {
+	// Content describes the source code for the source unit, if it was provided inline.
+	Content *string `json:"content,omitempty"`
+
+	// Urls describes the paths or URLs the source unit may be loaded from, if it was not provided inline.
+	Urls []string `json:"urls,omitempty"`
+}
+
+// standardJSONRequiredOutputs describes the contract-level outputs medusa requires solc to emit in its standard JSON
+// output.
+var standardJSONRequiredOutputs = []string{
+	"abi",
+	"evm.bytecode.object",
+	"evm.bytecode.sourceMap",
+	"evm.deployedBytecode.object",
+	"evm.deployedBytecode.sourceMap",
+}
+
+// standardJSONOptionalOutputs describes the contract-level outputs medusa makes use of if solc emits them in its
+// standard JSON output, but does not require. These are added to the output selection without being reported.
+var standardJSONOptionalOutputs = []string{
+	"storageLayout",
+}
+
+// sourceCode obtains the source code for each source unit in the standard JSON input. Sources provided inline are
+// used directly, while sources provided through urls are read relative to the provided base directory.
+// Returns a mapping of source paths to source code for all sources which could be resolved.
+func (i *standardJSONInput) sourceCode(baseDirectory string) map[string][]byte {
+	sourceCode := make(map[string][]byte)
+	for sourcePath, source := range i.Sources {
+		if source.Content != nil {
+			sourceCode[sourcePath] = []byte(*source.Content)
+			continue
+		}
+
+		// Try the source path itself, followed by any urls provided for the source.
+		candidates := append([]string{sourcePath}, source.Urls...)
+		for _, candidate := range candidates {
+			if !filepath.IsAbs(candidate) {
+				candidate = filepath.Join(baseDirectory, candidate)
+			}
+			if code, err := os.ReadFile(candidate); err == nil {
+				sourceCode[sourcePath] = code
+				break
+			}
+		}
+	}
+	return sourceCode
+}
+
+// ensureOutputSelection ensures the standard JSON input's output selection settings request all outputs medusa
+// requires, adding any which are missing to the wildcard selection. Optional outputs are added in the same way.
+// Returns a list of required outputs which were added.
+func (i *standardJSONInput) ensureOutputSelection() []string {
+	// Obtain our output selection, creating any missing levels of it along the way.
+	if i.Settings == nil {
+		i.Settings = make(map[string]any)
+	}
+	outputSelection, ok := i.Settings["outputSelection"].(map[string]any)
+	if !ok {
+		outputSelection = make(map[string]any)
+		i.Settings["outputSelection"] = outputSelection
+	}
+	fileSelection, ok := outputSelection["*"].(map[string]any)
+	if !ok {
+		fileSelection = make(map[string]any)
+		outputSelection["*"] = fileSelection
+	}
+
+	// addOutputs adds the provided outputs to the selection for a given contract name if they are not already
+	// covered by it, recording those added if they should be reported.
+	var addedOutputs []string
+	addOutputs := func(contractName string, requiredOutputs []string, report bool) {
+		selected, _ := fileSelection[contractName].([]any)
+		for _, requiredOutput := range requiredOutputs {
+			covered := false
+			for _, output := range selected {
+				outputStr, _ := output.(string)
+				if outputStr == "*" || outputStr == requiredOutput || strings.HasPrefix(requiredOutput, outputStr+".") {
+					covered = true
+					break
+				}
+			}
+			if !covered {
+				selected = append(selected, requiredOutput)
+				if report {
+					addedOutputs = append(addedOutputs, requiredOutput)
+				}
+			}
+		}
+		fileSelection[contractName] = selected
+	}
+
+	// Source-level outputs are selected with an empty contract name.
+	addOutputs("", []string{"ast"}, true)
+	addOutputs("*", standardJSONRequiredOutputs, true)
+	addOutputs("*", standardJSONOptionalOutputs, false)
+	return addedOutputs
+}
+
+// standardJSONOutput describes the subset of solc's standard JSON output format which medusa relies upon.
+type standardJSONOutput struct {
+	// Errors describes any errors or warnings emitted by the compiler.
+	Errors []standardJSONOutputError `json:"errors"`
+
+	// Sources describes a mapping of source paths to their source unit identifiers and ASTs.
+	Sources map[string]standardJSONOutputSource `json:"sources"`
+
+	// Contracts describes a mapping of source paths to contract names to compiled contract artifacts.
+	Contracts map[string]map[string]standardJSONOutputContract `json:"contracts"`
+}
+
+// standardJSONOutputError describes an error or warning emitted by the compiler in solc's standard JSON output format.
+type standardJSONOutputError struct {
+	// Severity describes the severity of the message (e.g. "error", "warning", "info").
+	Severity string `json:"severity"`
+
+	// FormattedMessage describes the message, formatted with source location information.
+	FormattedMessage string `json:"formattedMessage"`
+
+	// Message describes the message without source location information.
+	Message string `json:"message"`
+}
+
+// standardJSONOutputSource describes a single source unit in solc's standard JSON output format.
+type standardJSONOutputSource struct {
+	// Id describes the source unit identifier, referenced by source maps.
+	Id int `json:"id"`
+
+	// Ast describes the abstract syntax tree for the source unit.
+	Ast any `json:"ast"`
+}
+
+// standardJSONOutputContract describes a single compiled contract in solc's standard JSON output format.
+type standardJSONOutputContract struct {
+	// Abi describes the contract's ABI definition.
+	Abi any `json:"abi"`
+
+	// Evm describes the EVM-related compilation artifacts for the contract.
+	Evm struct {
+		// Bytecode describes the init bytecode artifacts for the contract.
+		Bytecode standardJSONOutputBytecode `json:"bytecode"`
+
+		// DeployedBytecode describes the runtime bytecode artifacts for the contract.
+		DeployedBytecode standardJSONOutputBytecode `json:"deployedBytecode"`
+	} `json:"evm"`
+
+	// StorageLayout describes the layout of the contract's state variables in storage, if it was selected as an
+	// output.
+	StorageLayout *types.StorageLayout `json:"storageLayout"`
+}
+
+// standardJSONOutputBytecode describes a bytecode object and its source map in solc's standard JSON output format.
+type standardJSONOutputBytecode struct {
+	// Object describes the hex-encoded bytecode.
+	Object string `json:"object"`
+
+	// SourceMap describes the source map for the bytecode.
+	SourceMap string `json:"sourceMap"`
+}
+
+// errorMessages returns a list of formatted messages for all compiler messages with an "error" severity.
+func (o *standardJSONOutput) errorMessages() []string {
+	var messages []string
+	for _, outputErr := range o.Errors {
+		if outputErr.Severity == "error" {
+			message := outputErr.FormattedMessage
+			if message == "" {
+				message = outputErr.Message
+			}
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// parseStandardJSONOutput parses the provided solc standard JSON output into a types.Compilation. If source code is
+// provided, it is used to populate the types.Compilation.SourceCode cache for any matching source paths.
+// Returns the resulting compilation, or an error if one occurs.
+func parseStandardJSONOutput(output *standardJSONOutput, sourceCode map[string][]byte) (*types.Compilation, error) {
+	// Create a compilation object that will store the contracts and source information.
+	compilation := types.NewCompilation()
+
+	// Create a map of contract names to their kinds
+	contractKinds := make(map[string]types.ContractKind)
+
+	// Loop through all sources and parse them into our types.
+	for sourcePath, source := range output.Sources {
+		// Convert the AST into our version of the AST (types.AST)
+		var ast types.AST
+		b, err := json.Marshal(source.Ast)
+		if err != nil {
+			return nil, fmt.Errorf("could not encode AST from sources: %v", err)
+		}
+		err = json.Unmarshal(b, &ast)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse AST from sources: %v", err)
+		}
+
+		// From the AST, extract the contract kinds where the contract definition could be for a contract, library,
+		// or interface
+		for _, node := range ast.Nodes {
+			if node.GetNodeType() == "ContractDefinition" {
+				contractDefinition := node.(types.ContractDefinition)
+				contractKinds[contractDefinition.CanonicalName] = contractDefinition.Kind
+			}
+		}
+
+		// Add our source artifact. The standard JSON output provides the source unit ID directly.
+		compilation.SourcePathToArtifact[sourcePath] = types.SourceArtifact{
+			Ast:          source.Ast,
+			Contracts:    make(map[string]types.CompiledContract),
+			SourceUnitId: source.Id,
+		}
+		compilation.SourceIdToPath[source.Id] = sourcePath
+
+		// If we were provided the source code for this source, cache it now.
+		if code, ok := sourceCode[sourcePath]; ok {
+			compilation.SourceCode[sourcePath] = code
+		}
+	}
+
+	// Loop through all contracts and parse them into our types.
+	for sourcePath, contracts := range output.Contracts {
+		// Ensure a source exists for this, or create one if our path somehow differed from any
+		// path not existing in the "sources" key at the root of the output.
+		if _, ok := compilation.SourcePathToArtifact[sourcePath]; !ok {
+			compilation.SourcePathToArtifact[sourcePath] = types.SourceArtifact{
+				Ast:       nil,
+				Contracts: make(map[string]types.CompiledContract),
+			}
+		}
+
+		for contractName, contract := range contracts {
+			// Parse the ABI
+			contractAbi, err := types.ParseABIFromInterface(contract.Abi)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse ABI for contract '%s'\n", contractName)
+			}
+
+			// Decode our init and runtime bytecode
+			initBytecode, err := hex.DecodeString(strings.TrimPrefix(contract.Evm.Bytecode.Object, "0x"))
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse init bytecode for contract '%s'\n", contractName)
+			}
+			runtimeBytecode, err := hex.DecodeString(strings.TrimPrefix(contract.Evm.DeployedBytecode.Object, "0x"))
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse runtime bytecode for contract '%s'\n", contractName)
+			}
+
+			// Add contract details
+			compilation.SourcePathToArtifact[sourcePath].Contracts[contractName] = types.CompiledContract{
+				Abi:             *contractAbi,
+				InitBytecode:    initBytecode,
+				RuntimeBytecode: runtimeBytecode,
+				SrcMapsInit:     contract.Evm.Bytecode.SourceMap,
+				SrcMapsRuntime:  contract.Evm.DeployedBytecode.SourceMap,
+				Kind:            contractKinds[contractName],
+				StorageLayout:   contract.StorageLayout,
+			}
+		}
+	}
+
+	return compilation, nil
+}
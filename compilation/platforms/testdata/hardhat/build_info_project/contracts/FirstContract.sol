@@ -0,0 +1,8 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.10;
+
+contract FirstContract {
+    function value() external returns (uint256) {
+        return 42;
+    }
+}
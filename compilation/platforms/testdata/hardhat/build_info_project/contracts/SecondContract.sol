@@ -0,0 +1,8 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.7.1;
+
+contract SecondContract {
+    function value() external returns (uint256) {
+        return 7;
+    }
+}
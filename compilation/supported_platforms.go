@@ -18,6 +18,8 @@ func init() {
 	generators := []func() platforms.PlatformConfig{
 		func() platforms.PlatformConfig { return platforms.NewSolcCompilationConfig("contract.sol") },
 		func() platforms.PlatformConfig { return platforms.NewCryticCompilationConfig(".") },
+		func() platforms.PlatformConfig { return platforms.NewHardhatCompilationConfig(".") },
+		func() platforms.PlatformConfig { return platforms.NewSolcStandardJSONCompilationConfig("input.json") },
 	}
 
 	// Initialize our platform config generator.
@@ -0,0 +1,163 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// fixedPointTypeRegex matches the base of a fixed-point ABI type (e.g. "fixed128x18", "ufixed64x10", or the "fixed"
+// and "ufixed" aliases), capturing the signedness prefix and the bit width.
+var fixedPointTypeRegex = regexp.MustCompile(`^(u?)fixed(?:(\d+)x\d+)?`)
+
+const (
+	// defaultFixedPointBitWidth describes the bit width of the "fixed" and "ufixed" aliases.
+	defaultFixedPointBitWidth = "128"
+
+	// defaultFixedPointTypeSuffix describes the bit width and decimal places of the "fixed" and "ufixed" aliases.
+	defaultFixedPointTypeSuffix = defaultFixedPointBitWidth + "x18"
+)
+
+// fixedPointTypeToIntegerType converts a fixed-point ABI type string to the integer type sharing its encoding (e.g.
+// "ufixed64x10[]" becomes "uint64[]"). Returns the converted type and a boolean indicating whether the provided type
+// was a fixed-point type.
+func fixedPointTypeToIntegerType(typ string) (string, bool) {
+	match := fixedPointTypeRegex.FindStringSubmatch(typ)
+	if match == nil {
+		return typ, false
+	}
+	bitWidth := match[2]
+	if bitWidth == "" {
+		bitWidth = defaultFixedPointBitWidth
+	}
+	return match[1] + "int" + bitWidth + typ[len(match[0]):], true
+}
+
+// canonicalAbiArgumentType obtains the canonical type string of a JSON ABI argument definition, as used in method,
+// event, and error signatures.
+func canonicalAbiArgumentType(argument map[string]any) string {
+	typ, _ := argument["type"].(string)
+
+	// Tuples are described by their component types.
+	if strings.HasPrefix(typ, "tuple") {
+		components, _ := argument["components"].([]any)
+		componentTypes := make([]string, 0, len(components))
+		for _, component := range components {
+			if componentMap, ok := component.(map[string]any); ok {
+				componentTypes = append(componentTypes, canonicalAbiArgumentType(componentMap))
+			}
+		}
+		return fmt.Sprintf("(%s)%s", strings.Join(componentTypes, ","), typ[len("tuple"):])
+	}
+
+	// The fixed-point aliases are expanded to their full type.
+	if typ == "fixed" || typ == "ufixed" || strings.HasPrefix(typ, "fixed[") || strings.HasPrefix(typ, "ufixed[") {
+		baseLength := strings.Index(typ, "fixed") + len("fixed")
+		return typ[:baseLength] + defaultFixedPointTypeSuffix + typ[baseLength:]
+	}
+	return typ
+}
+
+// canonicalAbiSignature obtains the signature of a JSON ABI method, event, or error definition.
+func canonicalAbiSignature(entry map[string]any) string {
+	name, _ := entry["name"].(string)
+	inputs, _ := entry["inputs"].([]any)
+	inputTypes := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		if inputMap, ok := input.(map[string]any); ok {
+			inputTypes = append(inputTypes, canonicalAbiArgumentType(inputMap))
+		}
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(inputTypes, ","))
+}
+
+// rewriteFixedPointAbiArguments converts all fixed-point types in the provided JSON ABI argument definitions,
+// including those nested in tuple components, to their equivalent integer types. Returns a boolean indicating
+// whether any argument was rewritten.
+func rewriteFixedPointAbiArguments(arguments []any) bool {
+	rewritten := false
+	for _, argument := range arguments {
+		argumentMap, ok := argument.(map[string]any)
+		if !ok {
+			continue
+		}
+		if typ, ok := argumentMap["type"].(string); ok {
+			if integerType, isFixedPoint := fixedPointTypeToIntegerType(typ); isFixedPoint {
+				argumentMap["type"] = integerType
+				rewritten = true
+			}
+		}
+		if components, ok := argumentMap["components"].([]any); ok {
+			rewritten = rewriteFixedPointAbiArguments(components) || rewritten
+		}
+	}
+	return rewritten
+}
+
+// parseABIWithFixedPointTypes parses a JSON ABI which may contain fixed-point types, which go-ethereum does not
+// support. Fixed-point values are ABI encoded as integers of the same bit width, so such types are parsed as their
+// integer equivalent, while method, event, and error signatures and identifiers are kept as the original types
+// describe, so calls are still dispatched to the correct method.
+func parseABIWithFixedPointTypes(abiJson []byte) (abi.ABI, error) {
+	// If the ABI is not a list of definitions, we parse it as-is and let the parser report any error.
+	var entries []map[string]any
+	if err := json.Unmarshal(abiJson, &entries); err != nil {
+		return abi.JSON(strings.NewReader(string(abiJson)))
+	}
+
+	// Rewrite any fixed-point types, recording the original signature of any definition whose signature changes.
+	originalSignatures := make(map[string]string)
+	rewritten := false
+	for _, entry := range entries {
+		originalSignature := canonicalAbiSignature(entry)
+		inputs, _ := entry["inputs"].([]any)
+		inputsRewritten := rewriteFixedPointAbiArguments(inputs)
+		outputs, _ := entry["outputs"].([]any)
+		outputsRewritten := rewriteFixedPointAbiArguments(outputs)
+		if inputsRewritten {
+			originalSignatures[canonicalAbiSignature(entry)] = originalSignature
+		}
+		rewritten = rewritten || inputsRewritten || outputsRewritten
+	}
+
+	// If no fixed-point types were found, we parse the ABI as-is.
+	if !rewritten {
+		return abi.JSON(strings.NewReader(string(abiJson)))
+	}
+	rewrittenAbiJson, err := json.Marshal(entries)
+	if err != nil {
+		return abi.ABI{}, err
+	}
+	result, err := abi.JSON(strings.NewReader(string(rewrittenAbiJson)))
+	if err != nil {
+		return abi.ABI{}, err
+	}
+
+	// Restore the original signatures and identifiers.
+	for name, method := range result.Methods {
+		if originalSignature, ok := originalSignatures[method.Sig]; ok {
+			method.Sig = originalSignature
+			method.ID = crypto.Keccak256([]byte(originalSignature))[:4]
+			result.Methods[name] = method
+		}
+	}
+	for name, event := range result.Events {
+		if originalSignature, ok := originalSignatures[event.Sig]; ok {
+			event.Sig = originalSignature
+			event.ID = crypto.Keccak256Hash([]byte(originalSignature))
+			result.Events[name] = event
+		}
+	}
+	for name, abiError := range result.Errors {
+		if originalSignature, ok := originalSignatures[abiError.Sig]; ok {
+			abiError.Sig = originalSignature
+			abiError.ID = crypto.Keccak256Hash([]byte(originalSignature))
+			result.Errors[name] = abiError
+		}
+	}
+	return result, nil
+}
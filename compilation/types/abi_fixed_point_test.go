@@ -0,0 +1,95 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFixedPointTypeToIntegerType tests that fixed-point ABI types are converted to the integer types sharing their
+// encoding, and that other types are left untouched.
+func TestFixedPointTypeToIntegerType(t *testing.T) {
+	cases := map[string]string{
+		"fixed128x18":     "int128",
+		"ufixed128x18":    "uint128",
+		"fixed8x1":        "int8",
+		"ufixed256x80":    "uint256",
+		"fixed":           "int128",
+		"ufixed":          "uint128",
+		"fixed64x10[]":    "int64[]",
+		"ufixed32x2[3][]": "uint32[3][]",
+		"ufixed[2]":       "uint128[2]",
+	}
+	for fixedPointType, expectedType := range cases {
+		integerType, isFixedPoint := fixedPointTypeToIntegerType(fixedPointType)
+		assert.True(t, isFixedPoint, fixedPointType)
+		assert.EqualValues(t, expectedType, integerType, fixedPointType)
+	}
+
+	for _, otherType := range []string{"uint256", "int8[]", "bytes32", "function", "tuple", "address"} {
+		integerType, isFixedPoint := fixedPointTypeToIntegerType(otherType)
+		assert.False(t, isFixedPoint, otherType)
+		assert.EqualValues(t, otherType, integerType)
+	}
+}
+
+// TestParseABIWithFixedPointTypes tests that ABIs containing fixed-point types can be parsed, with their arguments
+// parsed as integers of the same bit width, while signatures and identifiers describe the original types.
+func TestParseABIWithFixedPointTypes(t *testing.T) {
+	abiJson := `[
+		{"type": "function", "name": "setPrice", "stateMutability": "nonpayable",
+			"inputs": [{"name": "price", "type": "ufixed64x10"}, {"name": "deltas", "type": "fixed[]"}], "outputs": []},
+		{"type": "function", "name": "setQuote", "stateMutability": "nonpayable",
+			"inputs": [{"name": "quote", "type": "tuple", "components": [{"name": "rate", "type": "fixed32x4"}, {"name": "owner", "type": "address"}]}],
+			"outputs": [{"name": "", "type": "ufixed"}]},
+		{"type": "function", "name": "price", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "ufixed64x10"}]},
+		{"type": "function", "name": "plain", "stateMutability": "nonpayable", "inputs": [{"name": "x", "type": "uint256"}], "outputs": []},
+		{"type": "event", "name": "PriceSet", "anonymous": false, "inputs": [{"name": "price", "type": "ufixed64x10", "indexed": false}]},
+		{"type": "error", "name": "BadPrice", "inputs": [{"name": "price", "type": "fixed128x18"}]}
+	]`
+
+	// The ABI should be parsed, even though go-ethereum does not support fixed-point types.
+	parsedAbi, err := ParseABIFromInterface(abiJson)
+	assert.NoError(t, err)
+
+	// Arguments should be parsed as integers of the same bit width.
+	setPrice := parsedAbi.Methods["setPrice"]
+	assert.EqualValues(t, abi.UintTy, setPrice.Inputs[0].Type.T)
+	assert.EqualValues(t, 64, setPrice.Inputs[0].Type.Size)
+	assert.EqualValues(t, abi.SliceTy, setPrice.Inputs[1].Type.T)
+	assert.EqualValues(t, abi.IntTy, setPrice.Inputs[1].Type.Elem.T)
+	assert.EqualValues(t, 128, setPrice.Inputs[1].Type.Elem.Size)
+	setQuote := parsedAbi.Methods["setQuote"]
+	assert.EqualValues(t, abi.IntTy, setQuote.Inputs[0].Type.TupleElems[0].T)
+	assert.EqualValues(t, 32, setQuote.Inputs[0].Type.TupleElems[0].Size)
+	assert.EqualValues(t, abi.UintTy, setQuote.Outputs[0].Type.T)
+	assert.EqualValues(t, 128, setQuote.Outputs[0].Type.Size)
+
+	// Signatures and identifiers should describe the original types.
+	expectedSignatures := map[string]string{
+		"setPrice": "setPrice(ufixed64x10,fixed128x18[])",
+		"setQuote": "setQuote((fixed32x4,address))",
+		"price":    "price()",
+		"plain":    "plain(uint256)",
+	}
+	for name, expectedSignature := range expectedSignatures {
+		method := parsedAbi.Methods[name]
+		assert.EqualValues(t, expectedSignature, method.Sig)
+		assert.EqualValues(t, crypto.Keccak256([]byte(expectedSignature))[:4], method.ID)
+	}
+	assert.EqualValues(t, "PriceSet(ufixed64x10)", parsedAbi.Events["PriceSet"].Sig)
+	assert.EqualValues(t, crypto.Keccak256Hash([]byte("PriceSet(ufixed64x10)")), parsedAbi.Events["PriceSet"].ID)
+	assert.EqualValues(t, "BadPrice(fixed128x18)", parsedAbi.Errors["BadPrice"].Sig)
+	assert.EqualValues(t, crypto.Keccak256Hash([]byte("BadPrice(fixed128x18)")), parsedAbi.Errors["BadPrice"].ID)
+
+	// Packed calls should use the original method identifier.
+	data, err := parsedAbi.Pack("plain", abi.MaxUint256)
+	assert.NoError(t, err)
+	assert.EqualValues(t, crypto.Keccak256([]byte("plain(uint256)"))[:4], data[:4])
+	data, err = parsedAbi.Pack("setPrice", uint64(7), []*big.Int{big.NewInt(-1)})
+	assert.NoError(t, err)
+	assert.EqualValues(t, crypto.Keccak256([]byte("setPrice(ufixed64x10,fixed128x18[])"))[:4], data[:4])
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 // ContractKind represents the kind of contract definition represented by an AST node
@@ -31,6 +32,10 @@ type FunctionDefinition struct {
 	// Src is the source file for this AST
 	Src  string `json:"src"`
 	Name string `json:"name,omitempty"`
+	// FunctionSelector is the hex-encoded selector of the function, if it is public or external
+	FunctionSelector string `json:"functionSelector,omitempty"`
+	// Documentation is the NatSpec documentation attached to the function, if any
+	Documentation *StructuredDocumentation `json:"documentation,omitempty"`
 }
 
 func (s FunctionDefinition) GetNodeType() string {
@@ -49,6 +54,11 @@ type ContractDefinition struct {
 	CanonicalName string `json:"canonicalName,omitempty"`
 	// Kind is a ContractKind that represents what type of contract definition this is (contract, interface, or library)
 	Kind ContractKind `json:"contractKind,omitempty"`
+	// Id is the unique identifier of the contract definition node
+	Id int `json:"id"`
+	// LinearizedBaseContracts is the list of contract definition node identifiers this contract inherits from, ordered
+	// from most derived (the contract itself) to most base
+	LinearizedBaseContracts []int `json:"linearizedBaseContracts,omitempty"`
 }
 
 func (s ContractDefinition) GetNodeType() string {
@@ -98,6 +108,71 @@ func (c *ContractDefinition) UnmarshalJSON(data []byte) error {
 
 }
 
+// StructuredDocumentation is the NatSpec documentation node attached to a definition
+type StructuredDocumentation struct {
+	// Text is the raw documentation text, with comment delimiters removed
+	Text string `json:"text"`
+}
+
+func (d *StructuredDocumentation) UnmarshalJSON(data []byte) error {
+	// Older versions of solc represent documentation as a plain string rather than a node
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		d.Text = text
+		return nil
+	}
+
+	type Alias StructuredDocumentation
+	return json.Unmarshal(data, (*Alias)(d))
+}
+
+// Notice returns the NatSpec `@notice` description from the documentation. Untagged documentation is treated as a
+// notice, per the NatSpec format. Multi-line descriptions are joined into a single line.
+func (d *StructuredDocumentation) Notice() string {
+	var notice []string
+	inNotice := true
+	for _, line := range strings.Split(d.Text, "\n") {
+		// Remove any leading comment decorations from the line
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "*"))
+
+		// A tag ends the previous description, and may begin a notice
+		if strings.HasPrefix(line, "@") {
+			tag, content, _ := strings.Cut(line, " ")
+			inNotice = tag == "@notice"
+			line = strings.TrimSpace(content)
+		}
+		if inNotice && line != "" {
+			notice = append(notice, line)
+		}
+	}
+	return strings.Join(notice, " ")
+}
+
+// CustomTag returns the content of each NatSpec `@custom:<name>` tag in the documentation, in the order they appear.
+// Multi-line content is joined into a single line.
+func (d *StructuredDocumentation) CustomTag(name string) []string {
+	var contents []string
+	inTag := false
+	for _, line := range strings.Split(d.Text, "\n") {
+		// Remove any leading comment decorations from the line
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "*"))
+
+		// A tag ends the previous content, and may begin a new one
+		if strings.HasPrefix(line, "@") {
+			tag, content, _ := strings.Cut(line, " ")
+			inTag = tag == "@custom:"+name
+			if inTag {
+				contents = append(contents, "")
+			}
+			line = strings.TrimSpace(content)
+		}
+		if inTag && line != "" {
+			contents[len(contents)-1] = strings.TrimSpace(contents[len(contents)-1] + " " + line)
+		}
+	}
+	return contents
+}
+
 // AST is the abstract syntax tree
 type AST struct {
 	// NodeType represents the node type (currently we only evaluate source unit node types)
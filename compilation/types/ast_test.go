@@ -0,0 +1,60 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStructuredDocumentationCustomTag tests that the content of NatSpec custom tags is obtained from documentation,
+// regardless of comment decorations, multi-line content, or other tags.
+func TestStructuredDocumentationCustomTag(t *testing.T) {
+	documentation := &StructuredDocumentation{Text: "@notice Sets the value.\n * @custom:medusa ignore\n@custom:other target\n@custom:medusa target\n   another\n@param value The value."}
+	assert.EqualValues(t, []string{"ignore", "target another"}, documentation.CustomTag("medusa"))
+	assert.EqualValues(t, []string{"target"}, documentation.CustomTag("other"))
+	assert.Empty(t, documentation.CustomTag("missing"))
+	assert.EqualValues(t, "Sets the value.", documentation.Notice())
+}
+
+// TestMethodDirectives tests that the directives of the NatSpec `@custom:medusa` tags of each method are obtained
+// from the AST, inheriting them from base contracts unless a method provides its own.
+func TestMethodDirectives(t *testing.T) {
+	astJson := `{
+		"nodeType": "SourceUnit",
+		"src": "0:100:0",
+		"nodes": [
+			{"nodeType": "ContractDefinition", "src": "0:50:0", "canonicalName": "Base", "contractKind": "contract", "id": 1,
+				"linearizedBaseContracts": [1],
+				"nodes": [
+					{"nodeType": "FunctionDefinition", "src": "1:10:0", "name": "f", "functionSelector": "26121ff0",
+						"documentation": {"nodeType": "StructuredDocumentation", "text": "@custom:medusa ignore"}},
+					{"nodeType": "FunctionDefinition", "src": "11:10:0", "name": "g", "functionSelector": "e2179b8e",
+						"documentation": {"nodeType": "StructuredDocumentation", "text": "@custom:medusa target unknown"}},
+					{"nodeType": "FunctionDefinition", "src": "21:10:0", "name": "h", "functionSelector": "b8c9d365",
+						"documentation": {"nodeType": "StructuredDocumentation", "text": "@notice Not a directive."}}
+				]},
+			{"nodeType": "ContractDefinition", "src": "50:50:0", "canonicalName": "Derived", "contractKind": "contract", "id": 2,
+				"linearizedBaseContracts": [2, 1],
+				"nodes": [
+					{"nodeType": "FunctionDefinition", "src": "51:10:0", "name": "g", "functionSelector": "e2179b8e",
+						"documentation": "@custom:medusa ignore"}
+				]}
+		]
+	}`
+	var ast any
+	assert.NoError(t, json.Unmarshal([]byte(astJson), &ast))
+	compilation := NewCompilation()
+	compilation.SourcePathToArtifact["test.sol"] = SourceArtifact{Ast: ast}
+
+	directives, err := compilation.MethodDirectives()
+	assert.NoError(t, err)
+	assert.EqualValues(t, map[string][]string{
+		"26121ff0": {"ignore"},
+		"e2179b8e": {"target", "unknown"},
+	}, directives["test.sol"]["Base"])
+	assert.EqualValues(t, map[string][]string{
+		"26121ff0": {"ignore"},
+		"e2179b8e": {"ignore"},
+	}, directives["test.sol"]["Derived"])
+}
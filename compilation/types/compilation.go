@@ -1,9 +1,11 @@
 package types
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 )
 
 // Compilation represents the artifacts of a smart contract compilation.
@@ -17,6 +19,11 @@ type Compilation struct {
 	// SourceCode is a lookup of a source file path from SourceList to source code. This is populated by
 	// CacheSourceCode.
 	SourceCode map[string][]byte
+
+	// SourcePathRemappings describes remappings applied to source file paths when their source code is read by
+	// CacheSourceCode, and when they are reported in source coverage analysis. Source file paths are otherwise keyed by
+	// the path the compiler reported them with.
+	SourcePathRemappings []SourcePathRemapping
 }
 
 // NewCompilation returns a new, empty Compilation object.
@@ -34,15 +41,19 @@ func NewCompilation() *Compilation {
 
 // CacheSourceCode caches source code for each CompiledSource in the compilation in the CompiledSource.SourceCode field.
 // This method will attempt to populate each CompiledSource.SourceCode which has not yet been populated (is nil) before
-// returning an error, if one occurs.
+// returning an error, if one occurs. Source files are read from their remapped paths (see SourcePathRemappings), and
+// those which could not be read are left uncached.
 func (c *Compilation) CacheSourceCode() error {
 	// Loop through each source file, try to read it, and collect errors in an aggregated string if we encounter any.
 	var errStr string
 	for sourcePath := range c.SourcePathToArtifact {
 		if _, ok := c.SourceCode[sourcePath]; !ok {
-			sourceCodeBytes, sourceReadErr := os.ReadFile(sourcePath)
+			// Read the source file from its remapped path, but cache it under the path the compiler reported.
+			remappedPath := c.RemappedSourcePath(sourcePath)
+			sourceCodeBytes, sourceReadErr := os.ReadFile(remappedPath)
 			if sourceReadErr != nil {
-				errStr += fmt.Sprintf("source file '%v' could not be cached due to error: '%v'\n", sourcePath, sourceReadErr)
+				errStr += fmt.Sprintf("source file '%v' could not be cached due to error: '%v'\n", remappedPath, sourceReadErr)
+				continue
 			}
 			c.SourceCode[sourcePath] = sourceCodeBytes
 		}
@@ -55,3 +66,100 @@ func (c *Compilation) CacheSourceCode() error {
 
 	return nil
 }
+
+// RemappedSourcePath obtains the path of the provided source file path after applying the SourcePathRemappings of
+// the compilation to it.
+func (c *Compilation) RemappedSourcePath(sourcePath string) string {
+	return RemapSourcePath(sourcePath, c.SourcePathRemappings)
+}
+
+// MethodDescriptions obtains the NatSpec `@notice` descriptions for the public and external methods of each contract
+// in the compilation. Descriptions are inherited from base contracts if a method does not document them itself.
+// Returns a mapping of source paths to contract names to hex-encoded method selectors to descriptions, or an error if
+// one occurs.
+func (c *Compilation) MethodDescriptions() (map[string]map[string]map[string]string, error) {
+	return resolveMethodDocumentation(c, func(documentation *StructuredDocumentation) (string, bool) {
+		notice := documentation.Notice()
+		return notice, notice != ""
+	})
+}
+
+// MethodDirectives obtains the directives provided through the NatSpec `@custom:medusa` tags (e.g.
+// `@custom:medusa ignore`) for the public and external methods of each contract in the compilation. Directives are
+// inherited from base contracts if a method does not provide any itself.
+// Returns a mapping of source paths to contract names to hex-encoded method selectors to directives, or an error if
+// one occurs.
+func (c *Compilation) MethodDirectives() (map[string]map[string]map[string][]string, error) {
+	return resolveMethodDocumentation(c, func(documentation *StructuredDocumentation) ([]string, bool) {
+		var directives []string
+		for _, content := range documentation.CustomTag("medusa") {
+			directives = append(directives, strings.Fields(content)...)
+		}
+		return directives, len(directives) > 0
+	})
+}
+
+// resolveMethodDocumentation obtains a value from the NatSpec documentation of the public and external methods of
+// each contract in the compilation, using the provided function, which returns the value and a boolean indicating
+// whether the documentation provided one. Values are inherited from base contracts if a method does not provide one
+// itself.
+// Returns a mapping of source paths to contract names to hex-encoded method selectors to values, or an error if one
+// occurs.
+func resolveMethodDocumentation[T any](c *Compilation, resolve func(*StructuredDocumentation) (T, bool)) (map[string]map[string]map[string]T, error) {
+	// Parse the AST for each source, recording every contract definition by identifier.
+	contractDefinitionsById := make(map[int]ContractDefinition)
+	contractDefinitionsBySource := make(map[string][]ContractDefinition)
+	for sourcePath, source := range c.SourcePathToArtifact {
+		if source.Ast == nil {
+			continue
+		}
+		b, err := json.Marshal(source.Ast)
+		if err != nil {
+			return nil, fmt.Errorf("could not encode AST for source '%s': %v", sourcePath, err)
+		}
+		var ast AST
+		err = json.Unmarshal(b, &ast)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse AST for source '%s': %v", sourcePath, err)
+		}
+		for _, node := range ast.Nodes {
+			if contractDefinition, ok := node.(ContractDefinition); ok {
+				contractDefinitionsById[contractDefinition.Id] = contractDefinition
+				contractDefinitionsBySource[sourcePath] = append(contractDefinitionsBySource[sourcePath], contractDefinition)
+			}
+		}
+	}
+
+	// For each contract, resolve method values from its most derived definition to its most base.
+	values := make(map[string]map[string]map[string]T)
+	for sourcePath, contractDefinitions := range contractDefinitionsBySource {
+		values[sourcePath] = make(map[string]map[string]T)
+		for _, contractDefinition := range contractDefinitions {
+			methodValues := make(map[string]T)
+			baseContracts := contractDefinition.LinearizedBaseContracts
+			if len(baseContracts) == 0 {
+				baseContracts = []int{contractDefinition.Id}
+			}
+			for _, baseContractId := range baseContracts {
+				baseContract, ok := contractDefinitionsById[baseContractId]
+				if !ok {
+					continue
+				}
+				for _, node := range baseContract.Nodes {
+					functionDefinition, ok := node.(FunctionDefinition)
+					if !ok || functionDefinition.FunctionSelector == "" || functionDefinition.Documentation == nil {
+						continue
+					}
+					if _, exists := methodValues[functionDefinition.FunctionSelector]; exists {
+						continue
+					}
+					if value, ok := resolve(functionDefinition.Documentation); ok {
+						methodValues[functionDefinition.FunctionSelector] = value
+					}
+				}
+			}
+			values[sourcePath][contractDefinition.CanonicalName] = methodValues
+		}
+	}
+	return values, nil
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"golang.org/x/exp/slices"
@@ -32,6 +31,10 @@ type CompiledContract struct {
 
 	// Kind describes the kind of contract, i.e. contract, library, interface.
 	Kind ContractKind
+
+	// StorageLayout describes the layout of the contract's state variables in storage. This is nil if the compilation
+	// platform did not provide it.
+	StorageLayout *StorageLayout
 }
 
 // IsMatch returns a boolean indicating whether provided contract bytecode is a match to this compiled contract
@@ -89,6 +92,7 @@ func (c *CompiledContract) IsMatch(initBytecode []byte, runtimeBytecode []byte)
 }
 
 // ParseABIFromInterface parses a generic object into an abi.ABI and returns it, or an error if one occurs.
+// Fixed-point types are parsed as the integer types sharing their encoding, as go-ethereum does not support them.
 func ParseABIFromInterface(i any) (*abi.ABI, error) {
 	var (
 		result abi.ABI
@@ -97,7 +101,7 @@ func ParseABIFromInterface(i any) (*abi.ABI, error) {
 
 	// If it's a string, just parse it. Otherwise, we assume it's an interface and serialize it into a string.
 	if s, ok := i.(string); ok {
-		result, err = abi.JSON(strings.NewReader(s))
+		result, err = parseABIWithFixedPointTypes([]byte(s))
 		if err != nil {
 			return nil, err
 		}
@@ -107,7 +111,7 @@ func ParseABIFromInterface(i any) (*abi.ABI, error) {
 		if err != nil {
 			return nil, err
 		}
-		result, err = abi.JSON(strings.NewReader(string(b)))
+		result, err = parseABIWithFixedPointTypes(b)
 		if err != nil {
 			return nil, err
 		}
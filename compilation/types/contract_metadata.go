@@ -63,6 +63,34 @@ func RemoveContractMetadata(bytecode []byte) []byte {
 	return bytecode
 }
 
+// MaskContractMetadataHashes takes bytecode and returns a copy of it in which the bytecode hashes of any embedded
+// contract metadata are zeroed out. Unlike RemoveContractMetadata, every occurrence of contract metadata is masked
+// (e.g. that of contracts created by the bytecode), and the length of the bytecode is preserved, so that any
+// constructor arguments which follow remain in place.
+func MaskContractMetadataHashes(bytecode []byte) []byte {
+	masked := bytes.Clone(bytecode)
+	for _, metadataHashPrefix := range metadataHashPrefixes {
+		// The final byte of each prefix describes the length of the bytecode hash which follows it.
+		hashLength := int(metadataHashPrefix[len(metadataHashPrefix)-1])
+		for searchOffset := 0; searchOffset < len(masked); {
+			prefixOffset := bytes.Index(masked[searchOffset:], metadataHashPrefix)
+			if prefixOffset == -1 {
+				break
+			}
+			hashStart := searchOffset + prefixOffset + len(metadataHashPrefix)
+			hashEnd := hashStart + hashLength
+			if hashEnd > len(masked) {
+				break
+			}
+			for i := hashStart; i < hashEnd; i++ {
+				masked[i] = 0
+			}
+			searchOffset = hashEnd
+		}
+	}
+	return masked
+}
+
 // ExtractBytecodeHash extracts the bytecode hash from given contract metadata and returns the bytes representing the
 // hash. If it could not be detected or extracted, nil is returned.
 func (m ContractMetadata) ExtractBytecodeHash() []byte {
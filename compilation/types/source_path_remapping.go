@@ -0,0 +1,42 @@
+package types
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// SourcePathRemapping describes a remapping of a source path prefix to another, used to locate source files which were
+// compiled under a different root directory than the one they reside in (e.g. within a container).
+type SourcePathRemapping struct {
+	// From describes the source path prefix which is remapped.
+	From string `json:"from"`
+
+	// To describes the path prefix which replaces From.
+	To string `json:"to"`
+}
+
+// RemapSourcePath remaps the provided source path using the remapping whose From prefix matches the longest leading
+// portion of it. Prefixes only match whole path components, so "/app" remaps "/app/src/A.sol" but not
+// "/application/A.sol".
+// Returns the remapped source path, or the provided source path if no remapping matches it.
+func RemapSourcePath(sourcePath string, remappings []SourcePathRemapping) string {
+	// Find the remapping with the longest matching prefix.
+	normalizedPath := filepath.ToSlash(sourcePath)
+	matchedIndex, matchedLength := -1, -1
+	for i, remapping := range remappings {
+		from := strings.TrimSuffix(filepath.ToSlash(remapping.From), "/")
+		if normalizedPath != from && !strings.HasPrefix(normalizedPath, from+"/") {
+			continue
+		}
+		if len(from) > matchedLength {
+			matchedIndex, matchedLength = i, len(from)
+		}
+	}
+	if matchedIndex == -1 {
+		return sourcePath
+	}
+
+	// Replace the matched prefix with its remapped prefix.
+	remainder := strings.TrimPrefix(normalizedPath[matchedLength:], "/")
+	return filepath.Join(remappings[matchedIndex].To, filepath.FromSlash(remainder))
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRemapSourcePath tests that source paths are remapped by the longest matching prefix, that prefixes only match
+// whole path components, and that source paths which match no prefix are left unchanged.
+func TestRemapSourcePath(t *testing.T) {
+	remappings := []SourcePathRemapping{
+		{From: "/app", To: "project"},
+		{From: "/app/lib/", To: filepath.Join("deps", "lib")},
+		{From: "/app/lib/solmate", To: "solmate"},
+	}
+	cases := map[string]string{
+		"/app/src/Vault.sol":                  filepath.Join("project", "src", "Vault.sol"),
+		"/app/lib/forge-std/src/Test.sol":     filepath.Join("deps", "lib", "forge-std", "src", "Test.sol"),
+		"/app/lib/solmate/src/tokens/ERC.sol": filepath.Join("solmate", "src", "tokens", "ERC.sol"),
+		"/app":                                "project",
+		"/application/Vault.sol":              "/application/Vault.sol",
+		"src/Vault.sol":                       "src/Vault.sol",
+	}
+	for sourcePath, expectedPath := range cases {
+		assert.EqualValues(t, expectedPath, RemapSourcePath(sourcePath, remappings), sourcePath)
+	}
+	assert.EqualValues(t, "/app/src/Vault.sol", RemapSourcePath("/app/src/Vault.sol", nil))
+}
+
+// TestCacheSourceCodeRemapped tests that source code is read from remapped source paths but cached under the paths
+// reported by the compiler, and that source files which cannot be read are left uncached.
+func TestCacheSourceCodeRemapped(t *testing.T) {
+	// Write a single source file to a directory which differs from the one it was compiled in.
+	sourceDirectory := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(sourceDirectory, "Vault.sol"), []byte("contract Vault {}\n"), 0644))
+
+	compilation := NewCompilation()
+	compilation.SourcePathToArtifact["/app/src/Vault.sol"] = SourceArtifact{}
+	compilation.SourcePathToArtifact["/app/src/Missing.sol"] = SourceArtifact{}
+	compilation.SourcePathRemappings = []SourcePathRemapping{{From: "/app/src", To: sourceDirectory}}
+
+	// Caching should fail for the missing file, while caching the remapped one.
+	err := compilation.CacheSourceCode()
+	assert.ErrorContains(t, err, filepath.Join(sourceDirectory, "Missing.sol"))
+	assert.EqualValues(t, "contract Vault {}\n", string(compilation.SourceCode["/app/src/Vault.sol"]))
+	assert.NotContains(t, compilation.SourceCode, "/app/src/Missing.sol")
+	assert.EqualValues(t, filepath.Join(sourceDirectory, "Vault.sol"), compilation.RemappedSourcePath("/app/src/Vault.sol"))
+}
@@ -0,0 +1,351 @@
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// storageLayoutMaxDynamicIndex describes the exclusive upper bound of slot offsets from the start of a dynamic array or
+// long bytes value's data for which a slot is attributed to it. Slots further away are assumed to be unrelated.
+var storageLayoutMaxDynamicIndex = new(big.Int).Lsh(big.NewInt(1), 64)
+
+// StorageLayout describes the layout of a contract's state variables in storage, as emitted by solc's storageLayout
+// output.
+type StorageLayout struct {
+	// Storage describes the state variables of the contract, in the order they are laid out in storage.
+	Storage []StorageLayoutVariable `json:"storage"`
+
+	// Types describes a mapping of type identifiers referenced by state variables to their definitions.
+	Types map[string]StorageLayoutType `json:"types"`
+}
+
+// StorageLayoutVariable describes a state variable, or a member of a struct, in a StorageLayout.
+type StorageLayoutVariable struct {
+	// AstId describes the identifier of the variable's declaration in the AST.
+	AstId int `json:"astId"`
+
+	// Contract describes the fully qualified name of the contract the variable was declared in.
+	Contract string `json:"contract"`
+
+	// Label describes the name of the variable.
+	Label string `json:"label"`
+
+	// Offset describes the offset of the variable in bytes within its first storage slot, counted from the right.
+	Offset int `json:"offset"`
+
+	// Slot describes the decimal storage slot the variable begins at. For struct members, this is relative to the
+	// slot the struct begins at.
+	Slot string `json:"slot"`
+
+	// Type describes the identifier of the variable's type in StorageLayout.Types.
+	Type string `json:"type"`
+}
+
+// StorageLayoutType describes a type referenced by variables in a StorageLayout.
+type StorageLayoutType struct {
+	// Encoding describes how the type is encoded in storage: "inplace", "mapping", "dynamic_array" or "bytes".
+	Encoding string `json:"encoding"`
+
+	// Label describes the canonical name of the type (e.g. "uint256", "mapping(address => uint256)").
+	Label string `json:"label"`
+
+	// NumberOfBytes describes the decimal amount of bytes the type occupies in place.
+	NumberOfBytes string `json:"numberOfBytes"`
+
+	// Key describes the identifier of the key type, for mappings.
+	Key string `json:"key,omitempty"`
+
+	// Value describes the identifier of the value type, for mappings.
+	Value string `json:"value,omitempty"`
+
+	// Base describes the identifier of the element type, for static and dynamic arrays.
+	Base string `json:"base,omitempty"`
+
+	// Members describes the members of the type, for structs.
+	Members []StorageLayoutVariable `json:"members,omitempty"`
+}
+
+// StorageSlotVariable describes a variable, or an element or member of one, which is stored within a given storage
+// slot, as resolved by StorageLayout.SlotVariables.
+type StorageSlotVariable struct {
+	// Name describes a displayable path to the variable (e.g. "balances[0x...]", "config.owner", "values[3]").
+	Name string
+
+	// Type describes the type of the variable. This is nil if the slot holds part of the data of a long bytes or
+	// string value, rather than the variable itself.
+	Type *StorageLayoutType
+
+	// Offset describes the offset of the variable in bytes within the slot, counted from the right.
+	Offset int
+
+	// Size describes the amount of bytes the variable occupies within the slot.
+	Size int
+}
+
+// uint256StorageLayoutType describes the type of the length of a dynamic array, which is stored in the slot of the
+// array itself.
+var uint256StorageLayoutType = &StorageLayoutType{Encoding: "inplace", Label: "uint256", NumberOfBytes: "32"}
+
+// size returns the amount of bytes the type occupies in place, or zero if it could not be parsed.
+func (t *StorageLayoutType) size() int {
+	size, err := strconv.Atoi(t.NumberOfBytes)
+	if err != nil || size < 0 {
+		return 0
+	}
+	return size
+}
+
+// slotCount returns the amount of storage slots the type occupies in place, which is at least one.
+func (t *StorageLayoutType) slotCount() *big.Int {
+	return big.NewInt(int64(max(1, (t.size()+31)/32)))
+}
+
+// parseStorageSlot parses a decimal storage slot, as provided in a StorageLayout.
+func parseStorageSlot(slot string) (*big.Int, bool) {
+	return new(big.Int).SetString(slot, 10)
+}
+
+// SlotVariables resolves the variables stored within the provided storage slot. Variables of value types, along with
+// the elements of static arrays and members of structs, are resolved from their declared slots. Mapping entries and
+// the elements of dynamic arrays are resolved through the provided preimages of hashes computed during execution,
+// where each preimage is keyed by its keccak256 hash, as their slots are derived by hashing.
+// Returns the variables stored within the slot, in the order they are laid out, or nil if none could be resolved.
+func (l *StorageLayout) SlotVariables(slot common.Hash, preimages map[common.Hash][]byte) []StorageSlotVariable {
+	slotValue := new(big.Int).SetBytes(slot.Bytes())
+	var variables []StorageSlotVariable
+	for _, variable := range l.Storage {
+		variableSlot, ok := parseStorageSlot(variable.Slot)
+		if !ok {
+			continue
+		}
+		variables = append(variables, l.slotVariables(slotValue, variableSlot, variable.Label, variable.Offset, variable.Type, preimages)...)
+	}
+	return variables
+}
+
+// slotVariables resolves the variables stored within the provided storage slot, which belong to the variable with the
+// provided name and type identifier, located at the provided base slot and offset.
+// Returns the variables stored within the slot, or nil if the variable is not stored within it.
+func (l *StorageLayout) slotVariables(slot *big.Int, baseSlot *big.Int, name string, offset int, typeId string, preimages map[common.Hash][]byte) []StorageSlotVariable {
+	variableType, ok := l.Types[typeId]
+	if !ok {
+		return nil
+	}
+
+	switch variableType.Encoding {
+	case "inplace":
+		// Verify the slot is within the slots the variable occupies.
+		relativeSlot := new(big.Int).Sub(slot, baseSlot)
+		if relativeSlot.Sign() < 0 || relativeSlot.Cmp(variableType.slotCount()) >= 0 {
+			return nil
+		}
+
+		// Structs are resolved to the members stored within the slot.
+		if len(variableType.Members) > 0 {
+			var variables []StorageSlotVariable
+			for _, member := range variableType.Members {
+				memberSlot, ok := parseStorageSlot(member.Slot)
+				if !ok {
+					continue
+				}
+				memberSlot.Add(memberSlot, baseSlot)
+				variables = append(variables, l.slotVariables(slot, memberSlot, name+"."+member.Label, member.Offset, member.Type, preimages)...)
+			}
+			return variables
+		}
+
+		// Static arrays are resolved to the elements stored within the slot.
+		if variableType.Base != "" {
+			length := l.staticArrayLength(&variableType)
+			return l.arrayElementVariables(slot, baseSlot, name, variableType.Base, length, preimages)
+		}
+
+		// Otherwise, the slot stores the variable itself.
+		return []StorageSlotVariable{{Name: name, Type: &variableType, Offset: offset, Size: variableType.size()}}
+	case "mapping":
+		// Mapping entries are stored at keccak256(key . slot), so we search for a preimage ending in our base slot.
+		baseSlotHash := common.BigToHash(baseSlot)
+		for hash, preimage := range preimages {
+			if len(preimage) < common.HashLength || !bytes.Equal(preimage[len(preimage)-common.HashLength:], baseSlotHash[:]) {
+				continue
+			}
+			keyName := fmt.Sprintf("%s[%s]", name, l.formatMappingKey(preimage[:len(preimage)-common.HashLength], variableType.Key))
+			if variables := l.slotVariables(slot, hash.Big(), keyName, 0, variableType.Value, preimages); len(variables) > 0 {
+				return variables
+			}
+		}
+		return nil
+	case "dynamic_array":
+		// The length of the array is stored in its own slot, while its elements are stored from keccak256(slot).
+		if slot.Cmp(baseSlot) == 0 {
+			return []StorageSlotVariable{{Name: name + ".length", Type: uint256StorageLayoutType, Size: common.HashLength}}
+		}
+		dataSlot := crypto.Keccak256Hash(common.BigToHash(baseSlot).Bytes()).Big()
+		return l.arrayElementVariables(slot, dataSlot, name, variableType.Base, nil, preimages)
+	case "bytes":
+		// Short values are stored in the slot of the variable itself, while long values store their length there and
+		// their data from keccak256(slot).
+		if slot.Cmp(baseSlot) == 0 {
+			return []StorageSlotVariable{{Name: name, Type: &variableType, Size: common.HashLength}}
+		}
+		dataSlot := crypto.Keccak256Hash(common.BigToHash(baseSlot).Bytes()).Big()
+		relativeSlot := new(big.Int).Sub(slot, dataSlot)
+		if relativeSlot.Sign() < 0 || relativeSlot.Cmp(storageLayoutMaxDynamicIndex) >= 0 {
+			return nil
+		}
+		return []StorageSlotVariable{{Name: fmt.Sprintf("%s (data slot %s)", name, relativeSlot), Size: common.HashLength}}
+	}
+	return nil
+}
+
+// staticArrayLength returns the length of the provided static array type, derived from its size and the size of its
+// elements, or nil if it could not be derived.
+func (l *StorageLayout) staticArrayLength(arrayType *StorageLayoutType) *big.Int {
+	elementType, ok := l.Types[arrayType.Base]
+	if !ok || elementType.size() == 0 {
+		return nil
+	}
+
+	// Elements smaller than a slot are packed together, while larger elements begin at a new slot.
+	elementSize := elementType.size()
+	if elementSize >= common.HashLength {
+		return new(big.Int).Div(arrayType.slotCount(), elementType.slotCount())
+	}
+	elementsPerSlot := int64(common.HashLength / elementSize)
+	return new(big.Int).Mul(arrayType.slotCount(), big.NewInt(elementsPerSlot))
+}
+
+// arrayElementVariables resolves the elements of an array stored within the provided storage slot, where the array
+// stores elements of the provided type identifier from the provided data slot onwards. If a length is provided,
+// elements beyond it are not resolved.
+// Returns the elements stored within the slot, or nil if the array does not store any within it.
+func (l *StorageLayout) arrayElementVariables(slot *big.Int, dataSlot *big.Int, name string, elementTypeId string, length *big.Int, preimages map[common.Hash][]byte) []StorageSlotVariable {
+	elementType, ok := l.Types[elementTypeId]
+	if !ok || elementType.size() == 0 {
+		return nil
+	}
+	relativeSlot := new(big.Int).Sub(slot, dataSlot)
+	if relativeSlot.Sign() < 0 || relativeSlot.Cmp(storageLayoutMaxDynamicIndex) >= 0 {
+		return nil
+	}
+
+	// Elements which occupy a slot or more are resolved to the element the slot belongs to.
+	elementSize := elementType.size()
+	if elementSize >= common.HashLength {
+		elementSlots := elementType.slotCount()
+		index := new(big.Int).Div(relativeSlot, elementSlots)
+		if length != nil && index.Cmp(length) >= 0 {
+			return nil
+		}
+		elementSlot := new(big.Int).Add(dataSlot, new(big.Int).Mul(index, elementSlots))
+		return l.slotVariables(slot, elementSlot, fmt.Sprintf("%s[%s]", name, index), 0, elementTypeId, preimages)
+	}
+
+	// Smaller elements are packed together, so we resolve each element stored within the slot.
+	elementsPerSlot := common.HashLength / elementSize
+	firstIndex := new(big.Int).Mul(relativeSlot, big.NewInt(int64(elementsPerSlot)))
+	var variables []StorageSlotVariable
+	for i := 0; i < elementsPerSlot; i++ {
+		index := new(big.Int).Add(firstIndex, big.NewInt(int64(i)))
+		if length != nil && index.Cmp(length) >= 0 {
+			break
+		}
+		variables = append(variables, StorageSlotVariable{
+			Name:   fmt.Sprintf("%s[%s]", name, index),
+			Type:   &elementType,
+			Offset: i * elementSize,
+			Size:   elementSize,
+		})
+	}
+	return variables
+}
+
+// formatMappingKey formats the provided key of a mapping entry, as it was hashed to derive the entry's slot, according
+// to the provided key type identifier.
+func (l *StorageLayout) formatMappingKey(key []byte, keyTypeId string) string {
+	keyType, ok := l.Types[keyTypeId]
+	if !ok {
+		return "0x" + hex.EncodeToString(key)
+	}
+
+	// Keys of string and bytes types are hashed unpadded.
+	if keyType.Encoding == "bytes" {
+		if keyType.Label == "string" {
+			return strconv.Quote(string(key))
+		}
+		return "0x" + hex.EncodeToString(key)
+	}
+
+	// Keys of value types are padded to a word, where fixed-size byte arrays are left-aligned and all other types are
+	// right-aligned.
+	size := keyType.size()
+	if len(key) != common.HashLength || size == 0 || size > common.HashLength {
+		return "0x" + hex.EncodeToString(key)
+	}
+	if strings.HasPrefix(keyType.Label, "bytes") {
+		return formatStorageValue(keyType.Label, key[:size])
+	}
+	return formatStorageValue(keyType.Label, key[common.HashLength-size:])
+}
+
+// Value extracts the bytes of the variable from the provided value of the slot it is stored within.
+func (v StorageSlotVariable) Value(slotValue common.Hash) []byte {
+	end := common.HashLength - v.Offset
+	start := end - v.Size
+	if v.Offset < 0 || v.Size <= 0 || start < 0 {
+		return slotValue.Bytes()
+	}
+	return slotValue[start:end]
+}
+
+// FormatValue formats the variable according to its type, extracting it from the provided value of the slot it is
+// stored within.
+func (v StorageSlotVariable) FormatValue(slotValue common.Hash) string {
+	value := v.Value(slotValue)
+	if v.Type == nil {
+		return "0x" + hex.EncodeToString(value)
+	}
+
+	// Values of string and bytes types store short data along with its length in the slot, or only the length of long
+	// data, which is stored elsewhere.
+	if v.Type.Encoding == "bytes" {
+		if value[len(value)-1]&1 == 1 {
+			length := new(big.Int).Rsh(new(big.Int).SetBytes(value), 1)
+			return fmt.Sprintf("(%s bytes)", length)
+		}
+		data := value[:min(int(value[len(value)-1]/2), len(value)-1)]
+		if v.Type.Label == "string" {
+			return strconv.Quote(string(data))
+		}
+		return "0x" + hex.EncodeToString(data)
+	}
+	return formatStorageValue(v.Type.Label, value)
+}
+
+// formatStorageValue formats the provided bytes of a value type with the provided type label.
+func formatStorageValue(label string, value []byte) string {
+	switch {
+	case label == "bool":
+		return strconv.FormatBool(new(big.Int).SetBytes(value).Sign() != 0)
+	case label == "address" || label == "address payable" || strings.HasPrefix(label, "contract "):
+		return common.BytesToAddress(value).String()
+	case strings.HasPrefix(label, "uint") || strings.HasPrefix(label, "enum "):
+		return new(big.Int).SetBytes(value).String()
+	case strings.HasPrefix(label, "int"):
+		// Signed integers are stored in two's complement, so we sign extend from the size of the value.
+		signedValue := new(big.Int).SetBytes(value)
+		if len(value) > 0 && value[0]&0x80 != 0 {
+			signedValue.Sub(signedValue, new(big.Int).Lsh(big.NewInt(1), uint(len(value)*8)))
+		}
+		return signedValue.String()
+	default:
+		return "0x" + hex.EncodeToString(value)
+	}
+}
@@ -0,0 +1,145 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// testStorageLayoutJSON describes a storage layout, as emitted by solc, for a contract declaring the following state
+// variables:
+//
+//	address owner; bool paused; uint256 counter; mapping(address => uint256) balances; uint256[] values;
+//	uint128[4] fixedValues; Config config; string name;
+//
+// where Config is a struct declaring "int64 delta; address admin;".
+const testStorageLayoutJSON = `{
+	"storage": [
+		{"astId": 1, "contract": "Test.sol:Test", "label": "owner", "offset": 0, "slot": "0", "type": "t_address"},
+		{"astId": 2, "contract": "Test.sol:Test", "label": "paused", "offset": 20, "slot": "0", "type": "t_bool"},
+		{"astId": 3, "contract": "Test.sol:Test", "label": "counter", "offset": 0, "slot": "1", "type": "t_uint256"},
+		{"astId": 4, "contract": "Test.sol:Test", "label": "balances", "offset": 0, "slot": "2", "type": "t_mapping(t_address,t_uint256)"},
+		{"astId": 5, "contract": "Test.sol:Test", "label": "values", "offset": 0, "slot": "3", "type": "t_array(t_uint256)dyn_storage"},
+		{"astId": 6, "contract": "Test.sol:Test", "label": "fixedValues", "offset": 0, "slot": "4", "type": "t_array(t_uint128)4_storage"},
+		{"astId": 7, "contract": "Test.sol:Test", "label": "config", "offset": 0, "slot": "6", "type": "t_struct(Config)1_storage"},
+		{"astId": 8, "contract": "Test.sol:Test", "label": "name", "offset": 0, "slot": "8", "type": "t_string_storage"}
+	],
+	"types": {
+		"t_address": {"encoding": "inplace", "label": "address", "numberOfBytes": "20"},
+		"t_bool": {"encoding": "inplace", "label": "bool", "numberOfBytes": "1"},
+		"t_int64": {"encoding": "inplace", "label": "int64", "numberOfBytes": "8"},
+		"t_uint128": {"encoding": "inplace", "label": "uint128", "numberOfBytes": "16"},
+		"t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"},
+		"t_string_storage": {"encoding": "bytes", "label": "string", "numberOfBytes": "32"},
+		"t_mapping(t_address,t_uint256)": {"encoding": "mapping", "key": "t_address", "label": "mapping(address => uint256)", "numberOfBytes": "32", "value": "t_uint256"},
+		"t_array(t_uint256)dyn_storage": {"base": "t_uint256", "encoding": "dynamic_array", "label": "uint256[]", "numberOfBytes": "32"},
+		"t_array(t_uint128)4_storage": {"base": "t_uint128", "encoding": "inplace", "label": "uint128[4]", "numberOfBytes": "64"},
+		"t_struct(Config)1_storage": {"encoding": "inplace", "label": "struct Test.Config", "numberOfBytes": "64", "members": [
+			{"astId": 9, "contract": "Test.sol:Test", "label": "delta", "offset": 0, "slot": "0", "type": "t_int64"},
+			{"astId": 10, "contract": "Test.sol:Test", "label": "admin", "offset": 0, "slot": "1", "type": "t_address"}
+		]}
+	}
+}`
+
+// TestStorageLayoutSlotVariables tests that storage slots are resolved to the variables stored within them, including
+// packed variables, struct members, array elements and mapping entries, and that their values are formatted according
+// to their types.
+func TestStorageLayoutSlotVariables(t *testing.T) {
+	var layout StorageLayout
+	assert.NoError(t, json.Unmarshal([]byte(testStorageLayoutJSON), &layout))
+
+	// Create the preimage used to derive the slot of a mapping entry.
+	holder := common.HexToAddress("0x1234")
+	mappingPreimage := append(common.LeftPadBytes(holder.Bytes(), 32), common.BigToHash(big.NewInt(2)).Bytes()...)
+	mappingSlot := crypto.Keccak256Hash(mappingPreimage)
+	preimages := map[common.Hash][]byte{mappingSlot: mappingPreimage}
+
+	// Derive the slots where the elements of our dynamic array and the data of long strings are stored.
+	arrayDataSlot := crypto.Keccak256Hash(common.BigToHash(big.NewInt(3)).Bytes()).Big()
+	stringDataSlot := crypto.Keccak256Hash(common.BigToHash(big.NewInt(8)).Bytes()).Big()
+
+	tests := []struct {
+		// slot describes the storage slot to resolve.
+		slot common.Hash
+
+		// slotValue describes the value of the storage slot to format the resolved variables with.
+		slotValue common.Hash
+
+		// expected describes the expected names of the resolved variables, mapped to their formatted values.
+		expected map[string]string
+	}{
+		{
+			slot:      common.BigToHash(big.NewInt(0)),
+			slotValue: common.BigToHash(new(big.Int).Or(new(big.Int).Lsh(big.NewInt(1), 160), holder.Big())),
+			expected:  map[string]string{"owner": holder.String(), "paused": "true"},
+		},
+		{
+			slot:      common.BigToHash(big.NewInt(1)),
+			slotValue: common.BigToHash(big.NewInt(5)),
+			expected:  map[string]string{"counter": "5"},
+		},
+		{
+			slot:      mappingSlot,
+			slotValue: common.BigToHash(big.NewInt(100)),
+			expected:  map[string]string{"balances[" + holder.String() + "]": "100"},
+		},
+		{
+			slot:      common.BigToHash(big.NewInt(3)),
+			slotValue: common.BigToHash(big.NewInt(2)),
+			expected:  map[string]string{"values.length": "2"},
+		},
+		{
+			slot:      common.BigToHash(new(big.Int).Add(arrayDataSlot, big.NewInt(1))),
+			slotValue: common.BigToHash(big.NewInt(9)),
+			expected:  map[string]string{"values[1]": "9"},
+		},
+		{
+			slot:      common.BigToHash(big.NewInt(5)),
+			slotValue: common.HexToHash("0x0000000000000000000000000000000400000000000000000000000000000003"),
+			expected:  map[string]string{"fixedValues[2]": "3", "fixedValues[3]": "4"},
+		},
+		{
+			slot:      common.BigToHash(big.NewInt(6)),
+			slotValue: common.HexToHash("0x000000000000000000000000000000000000000000000000fffffffffffffffe"),
+			expected:  map[string]string{"config.delta": "-2"},
+		},
+		{
+			slot:      common.BigToHash(big.NewInt(7)),
+			slotValue: common.BytesToHash(holder.Bytes()),
+			expected:  map[string]string{"config.admin": holder.String()},
+		},
+		{
+			slot:      common.BigToHash(big.NewInt(8)),
+			slotValue: common.BytesToHash(append(common.RightPadBytes([]byte("medusa"), 31), 12)),
+			expected:  map[string]string{"name": `"medusa"`},
+		},
+		{
+			slot:      common.BigToHash(big.NewInt(8)),
+			slotValue: common.BigToHash(big.NewInt(81)),
+			expected:  map[string]string{"name": "(40 bytes)"},
+		},
+		{
+			slot:      common.BigToHash(new(big.Int).Add(stringDataSlot, big.NewInt(1))),
+			slotValue: common.BigToHash(big.NewInt(1)),
+			expected:  map[string]string{"name (data slot 1)": common.BigToHash(big.NewInt(1)).Hex()},
+		},
+		{
+			slot:      common.BigToHash(big.NewInt(9)),
+			slotValue: common.BigToHash(big.NewInt(1)),
+			expected:  map[string]string{},
+		},
+	}
+
+	for _, test := range tests {
+		variables := layout.SlotVariables(test.slot, preimages)
+		resolved := make(map[string]string)
+		for _, variable := range variables {
+			resolved[variable.Name] = variable.FormatValue(test.slotValue)
+		}
+		assert.EqualValues(t, test.expected, resolved, "slot %s", test.slot.Hex())
+	}
+}
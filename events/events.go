@@ -1,6 +1,8 @@
 package events
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
 	"sync"
 )
@@ -13,6 +15,48 @@ func (e *EventHandler[T]) EventType() reflect.Type {
 	return reflect.TypeOf((*T)(nil)).Elem()
 }
 
+// SubscriberError describes an error returned by an EventHandler when an event was published, identifying the
+// subscriber which returned it and the type of event it was handling.
+type SubscriberError struct {
+	// Subscriber describes the name the EventHandler was subscribed with, or an empty string if it was not named.
+	Subscriber string
+
+	// EventType describes the type of event the EventHandler was handling.
+	EventType string
+
+	// Err describes the error returned by the EventHandler.
+	Err error
+}
+
+// Error returns a description of the error, identifying the subscriber which returned it.
+func (e *SubscriberError) Error() string {
+	if e.Subscriber == "" {
+		return fmt.Sprintf("unnamed event handler failed to handle %s event: %v", e.EventType, e.Err)
+	}
+	return fmt.Sprintf("event handler %q failed to handle %s event: %v", e.Subscriber, e.EventType, e.Err)
+}
+
+// Unwrap returns the error returned by the EventHandler.
+func (e *SubscriberError) Unwrap() error {
+	return e.Err
+}
+
+// wrapSubscriberError wraps the provided error returned by the subscriber with the provided name, when handling an
+// event of the provided type, in a SubscriberError. Errors which already wrap a SubscriberError, such as those
+// returned by a subscriber which published the event to another emitter, are returned as-is, as they identify the
+// subscriber which originally failed.
+func wrapSubscriberError(subscriber string, eventType reflect.Type, err error) error {
+	var subscriberErr *SubscriberError
+	if errors.As(err, &subscriberErr) {
+		return err
+	}
+	return &SubscriberError{
+		Subscriber: subscriber,
+		EventType:  eventType.String(),
+		Err:        err,
+	}
+}
+
 // globalEventHandlers describes a mapping of event types to EventHandler objects. These callbacks are called
 // any time any EventEmitter publishes an event of that type.
 var globalEventHandlers map[string][]any
@@ -52,7 +96,16 @@ func SubscribeAny[T any](callback EventHandler[T]) {
 type EventEmitter[T any] struct {
 	// subscriptions defines the EventHandler methods which should be invoked when a new event is published to this
 	// emitter.
-	subscriptions []EventHandler[T]
+	subscriptions []eventSubscription[T]
+}
+
+// eventSubscription describes an EventHandler subscribed to an EventEmitter, alongside the name identifying it.
+type eventSubscription[T any] struct {
+	// name describes the name the EventHandler was subscribed with, or an empty string if it was not named.
+	name string
+
+	// handler describes the EventHandler to invoke when an event is published.
+	handler EventHandler[T]
 }
 
 // EventType returns the event type given an EventEmitter object
@@ -61,13 +114,12 @@ func (e *EventEmitter[T]) EventType() reflect.Type {
 }
 
 // Publish emits the provided event by calling every EventHandler subscribed.
+// Returns a SubscriberError identifying the first EventHandler which returned an error, if any.
 func (e *EventEmitter[T]) Publish(event T) error {
 	// Call every subscribed EventHandler
-	for _, subscription := range e.subscriptions {
-		err := subscription(event)
-		if err != nil {
-			return err
-		}
+	err := e.PublishToSubscribers(event)
+	if err != nil {
+		return err
 	}
 
 	// Determine the event type
@@ -85,15 +137,35 @@ func (e *EventEmitter[T]) Publish(event T) error {
 			callback := callbacks[i].(EventHandler[T])
 			err := callback(event)
 			if err != nil {
-				return err
+				return wrapSubscriberError("", e.EventType(), err)
 			}
 		}
 	}
 	return nil
 }
 
+// PublishToSubscribers emits the provided event by calling every EventHandler subscribed to this emitter, without
+// calling the global EventHandler objects subscribed with SubscribeAny. This should be used to forward an event which
+// was already published through another emitter, so global EventHandler objects are not called for it twice.
+// Returns a SubscriberError identifying the first EventHandler which returned an error, if any.
+func (e *EventEmitter[T]) PublishToSubscribers(event T) error {
+	for _, subscription := range e.subscriptions {
+		err := subscription.handler(event)
+		if err != nil {
+			return wrapSubscriberError(subscription.name, e.EventType(), err)
+		}
+	}
+	return nil
+}
+
 // Subscribe adds an EventHandler to the list of subscribed EventHandler objects for this emitter. When an event is
 // published, the callback will be triggered with the event data.
 func (e *EventEmitter[T]) Subscribe(callback EventHandler[T]) {
-	e.subscriptions = append(e.subscriptions, callback)
+	e.SubscribeNamed("", callback)
+}
+
+// SubscribeNamed adds an EventHandler to the list of subscribed EventHandler objects for this emitter, as Subscribe
+// does, with a name identifying the subscriber (e.g. its owner) in any error it returns.
+func (e *EventEmitter[T]) SubscribeNamed(name string, callback EventHandler[T]) {
+	e.subscriptions = append(e.subscriptions, eventSubscription[T]{name: name, handler: callback})
 }
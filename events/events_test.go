@@ -1,8 +1,10 @@
 package events
 
 import (
-	"github.com/stretchr/testify/assert"
+	"errors"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 // TestEventPublishingAndSubscribing creates EventEmitter objects, subscribes EventHandler callbacks to them, and
@@ -84,3 +86,41 @@ func TestEventPublishingAndSubscribing(t *testing.T) {
 	assert.EqualValues(t, expectedEventAEmitter1PublishCount+expectedEventAEmitter2PublishCount, eventAEmitterGlobalPublishCount)
 	assert.EqualValues(t, expectedEventBEmitter1PublishCount+expectedEventBEmitter2PublishCount, eventBEmitterGlobalPublishCount)
 }
+
+// TestEventSubscriberErrors ensures that errors returned by EventHandler callbacks are wrapped in a SubscriberError
+// identifying the subscriber which returned them, and that errors forwarded through other emitters retain the identity
+// of the subscriber which originally failed.
+func TestEventSubscriberErrors(t *testing.T) {
+	// Define some event types
+	type TestEventC struct{}
+	type TestEventD struct{}
+	handlerErr := errors.New("handler failed")
+
+	// A named subscriber should be identified in the returned error.
+	namedEmitter := EventEmitter[TestEventC]{}
+	namedEmitter.SubscribeNamed("test subscriber", func(event TestEventC) error {
+		return handlerErr
+	})
+	err := namedEmitter.Publish(TestEventC{})
+	var subscriberErr *SubscriberError
+	assert.ErrorAs(t, err, &subscriberErr)
+	assert.ErrorIs(t, err, handlerErr)
+	assert.EqualValues(t, "test subscriber", subscriberErr.Subscriber)
+	assert.EqualValues(t, "events.TestEventC", subscriberErr.EventType)
+	assert.EqualError(t, err, "event handler \"test subscriber\" failed to handle events.TestEventC event: handler failed")
+
+	// An unnamed subscriber should be described as such.
+	unnamedEmitter := EventEmitter[TestEventD]{}
+	unnamedEmitter.Subscribe(func(event TestEventD) error {
+		return handlerErr
+	})
+	err = unnamedEmitter.Publish(TestEventD{})
+	assert.EqualError(t, err, "unnamed event handler failed to handle events.TestEventD event: handler failed")
+
+	// An error forwarded from another emitter should identify the subscriber which originally failed.
+	forwardingEmitter := EventEmitter[TestEventC]{}
+	forwardingEmitter.Subscribe(namedEmitter.Publish)
+	err = forwardingEmitter.Publish(TestEventC{})
+	assert.ErrorAs(t, err, &subscriberErr)
+	assert.EqualValues(t, "test subscriber", subscriberErr.Subscriber)
+}
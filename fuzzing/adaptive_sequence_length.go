@@ -0,0 +1,92 @@
+package fuzzing
+
+import (
+	"math"
+	"time"
+
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/crytic/medusa/utils"
+)
+
+const (
+	// adaptiveSequenceLengthPercentile describes the fraction of recent discoveries which the length of generated call
+	// sequences is adjusted to fit.
+	adaptiveSequenceLengthPercentile = 0.95
+
+	// adaptiveSequenceLengthMinDiscoveries describes the minimum count of recent discoveries required to adjust the
+	// length of generated call sequences, so that it is not adjusted based on too few samples.
+	adaptiveSequenceLengthMinDiscoveries = 10
+
+	// adaptiveSequenceLengthHeadroom describes the factor by which the call sequence length by which most recent
+	// discoveries were made is multiplied to obtain the length generated call sequences are shrunk toward.
+	adaptiveSequenceLengthHeadroom = 1.5
+
+	// adaptiveSequenceLengthGrowthThreshold describes the fraction of the current length of generated call sequences
+	// beyond which most recent discoveries must have been made for the length to grow.
+	adaptiveSequenceLengthGrowthThreshold = 0.9
+)
+
+// adaptSequenceLength determines the length generated call sequences should be adjusted to, given their current
+// length, the bounds they can be adjusted within, and the count of discoveries recently made at each call sequence
+// length (where the count at index i describes call sequences of length i+1).
+//
+// If most recent discoveries were made well within the current length, the length is shrunk halfway toward 1.5 times
+// the length by which they were made. If most recent discoveries were made near the end of call sequences, the length
+// is grown by half. Otherwise, or if too few discoveries were made recently, the length is unchanged.
+// Returns the adjusted length, within the provided bounds.
+func adaptSequenceLength(currentLength int, minLength int, maxLength int, recentDiscoveries []uint64) int {
+	// If too few discoveries were made, we do not have enough data to adjust the length.
+	var discoveryCount uint64
+	for _, count := range recentDiscoveries {
+		discoveryCount += count
+	}
+	if discoveryCount < adaptiveSequenceLengthMinDiscoveries {
+		return currentLength
+	}
+
+	// Determine the length by which most discoveries were made, and adjust our length accordingly.
+	discoveryLength := sequenceLengthPercentile(recentDiscoveries, adaptiveSequenceLengthPercentile)
+	adjustedLength := currentLength
+	if float64(discoveryLength) >= adaptiveSequenceLengthGrowthThreshold*float64(currentLength) {
+		adjustedLength = currentLength + int(math.Ceil(float64(currentLength)/2))
+	} else if targetLength := int(math.Ceil(adaptiveSequenceLengthHeadroom * float64(discoveryLength))); targetLength < currentLength {
+		adjustedLength = targetLength + (currentLength-targetLength)/2
+	}
+
+	// Ensure our adjusted length is within our bounds.
+	return utils.Max(minLength, utils.Min(maxLength, adjustedLength))
+}
+
+// SequenceLength returns the length of the call sequences currently being generated by the Fuzzer's workers. This is
+// the configured call sequence length, unless it is being adjusted while fuzzing.
+func (f *Fuzzer) SequenceLength() int {
+	if !f.config.Fuzzing.AdaptiveSequenceLength.Enabled {
+		return f.config.Fuzzing.CallSequenceLength
+	}
+	return int(f.sequenceLength.Load())
+}
+
+// adaptiveSequenceLengthLoop periodically adjusts the length of generated call sequences based on the call sequence
+// lengths at which new coverage and failures were recently discovered, or returns when ctx signals a stopped
+// operation.
+func (f *Fuzzer) adaptiveSequenceLengthLoop() {
+	adaptiveConfig := f.config.Fuzzing.AdaptiveSequenceLength
+	ticker := time.NewTicker(time.Duration(adaptiveConfig.AdjustmentInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Adjust the length of generated call sequences based on the discoveries made since the last adjustment.
+		currentLength := f.SequenceLength()
+		adjustedLength := adaptSequenceLength(currentLength, adaptiveConfig.MinLength, adaptiveConfig.MaxLength, f.sequenceLengths.takeRecentDiscoveries())
+		if adjustedLength != currentLength {
+			f.sequenceLength.Store(int64(adjustedLength))
+			f.logger.Info("Adjusted the call sequence length from ", currentLength, " to ", colors.Bold, adjustedLength, colors.Reset, " call(s)")
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package fuzzing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newDiscoveryHistogram creates a histogram of discoveries, as described by SequenceLengthHistogram, with the provided
+// count of discoveries at each provided call sequence length.
+func newDiscoveryHistogram(countsByLength map[int]uint64) []uint64 {
+	histogram := make([]uint64, 0)
+	for length, count := range countsByLength {
+		for i := uint64(0); i < count; i++ {
+			histogram = incrementSequenceLengthCount(histogram, length)
+		}
+	}
+	return histogram
+}
+
+// TestAdaptSequenceLength tests that the length of generated call sequences is shrunk toward the length by which most
+// recent discoveries were made, grown if discoveries are made near its end, and kept within its bounds.
+func TestAdaptSequenceLength(t *testing.T) {
+	tests := []struct {
+		name              string
+		currentLength     int
+		recentDiscoveries []uint64
+		expectedLength    int
+	}{
+		{
+			name:              "no discoveries",
+			currentLength:     100,
+			recentDiscoveries: nil,
+			expectedLength:    100,
+		},
+		{
+			name:              "too few discoveries",
+			currentLength:     100,
+			recentDiscoveries: newDiscoveryHistogram(map[int]uint64{2: 9}),
+			expectedLength:    100,
+		},
+		{
+			// 95% of discoveries were made by call #20, so we shrink halfway from 100 toward 30.
+			name:              "shrink toward discoveries",
+			currentLength:     100,
+			recentDiscoveries: newDiscoveryHistogram(map[int]uint64{5: 50, 20: 45, 90: 5}),
+			expectedLength:    65,
+		},
+		{
+			// Repeatedly shrinking converges on 1.5x the discovery length.
+			name:              "shrink converges",
+			currentLength:     31,
+			recentDiscoveries: newDiscoveryHistogram(map[int]uint64{20: 100}),
+			expectedLength:    30,
+		},
+		{
+			name:              "shrink within bounds",
+			currentLength:     100,
+			recentDiscoveries: newDiscoveryHistogram(map[int]uint64{1: 100}),
+			expectedLength:    51,
+		},
+		{
+			name:              "shrink to minimum",
+			currentLength:     15,
+			recentDiscoveries: newDiscoveryHistogram(map[int]uint64{1: 100}),
+			expectedLength:    10,
+		},
+		{
+			// Discoveries within the headroom of the current length do not change it.
+			name:              "unchanged",
+			currentLength:     100,
+			recentDiscoveries: newDiscoveryHistogram(map[int]uint64{70: 100}),
+			expectedLength:    100,
+		},
+		{
+			name:              "grow near limit",
+			currentLength:     100,
+			recentDiscoveries: newDiscoveryHistogram(map[int]uint64{10: 50, 95: 50}),
+			expectedLength:    150,
+		},
+		{
+			// Corpus call sequences may be longer than the current length, and should be considered near its limit.
+			name:              "grow beyond limit",
+			currentLength:     100,
+			recentDiscoveries: newDiscoveryHistogram(map[int]uint64{180: 100}),
+			expectedLength:    150,
+		},
+		{
+			name:              "grow to maximum",
+			currentLength:     180,
+			recentDiscoveries: newDiscoveryHistogram(map[int]uint64{180: 100}),
+			expectedLength:    200,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.EqualValues(t, test.expectedLength, adaptSequenceLength(test.currentLength, 10, 200, test.recentDiscoveries))
+		})
+	}
+}
+
+// TestSequenceLengthTrackerRecentDiscoveries tests that recent discoveries include both new coverage and failures,
+// and are reset once taken, while the overall histogram is not.
+func TestSequenceLengthTrackerRecentDiscoveries(t *testing.T) {
+	tracker := newSequenceLengthTracker()
+	tracker.recordNewCoverage(2)
+	tracker.recordFailure(3)
+	assert.EqualValues(t, []uint64{0, 1, 1}, tracker.takeRecentDiscoveries())
+	assert.Empty(t, tracker.takeRecentDiscoveries())
+
+	tracker.recordNewCoverage(1)
+	assert.EqualValues(t, []uint64{1}, tracker.takeRecentDiscoveries())
+	assert.EqualValues(t, []uint64{1, 1}, tracker.snapshot().NewCoverage)
+	assert.EqualValues(t, []uint64{0, 0, 1}, tracker.snapshot().Failures)
+}
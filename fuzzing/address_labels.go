@@ -0,0 +1,87 @@
+package fuzzing
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/exp/maps"
+)
+
+// AddressLabels is a registry of human-readable labels for addresses used in a fuzzing campaign, which are displayed
+// alongside the addresses in execution traces, call sequences, and test results. Deployed contracts are labeled with
+// the name of their contract definition, while senders, the deployer, and any addresses given an alias in the project
+// configuration are labeled on creation.
+type AddressLabels struct {
+	// labels describes the label for each labeled address.
+	labels map[common.Address]string
+
+	// contractNames describes the name of the contract definition each contract address was labeled with.
+	contractNames map[common.Address]string
+
+	// contractCounts describes the number of contract addresses labeled with each contract definition name.
+	contractCounts map[string]int
+
+	// labelsLock provides thread-synchronization, as labels are added by every FuzzerWorker.
+	labelsLock sync.Mutex
+}
+
+// newAddressLabels creates an AddressLabels registry which labels the provided deployer and senders, and the addresses
+// in the provided aliases, which take precedence over any other label.
+func newAddressLabels(deployer common.Address, senders []common.Address, aliases map[common.Address]string) *AddressLabels {
+	l := &AddressLabels{
+		labels:         make(map[common.Address]string),
+		contractNames:  make(map[common.Address]string),
+		contractCounts: make(map[string]int),
+	}
+	for i, sender := range senders {
+		l.labels[sender] = fmt.Sprintf("sender[%d]", i)
+	}
+	if _, labeled := l.labels[deployer]; !labeled {
+		l.labels[deployer] = "deployer"
+	}
+	for address, alias := range aliases {
+		l.labels[address] = alias
+	}
+	return l
+}
+
+// Label obtains the label for the provided address, or an empty string if it is not labeled.
+func (l *AddressLabels) Label(address common.Address) string {
+	l.labelsLock.Lock()
+	defer l.labelsLock.Unlock()
+	return l.labels[address]
+}
+
+// Labels obtains a copy of the mapping of addresses to their labels.
+func (l *AddressLabels) Labels() map[common.Address]string {
+	l.labelsLock.Lock()
+	defer l.labelsLock.Unlock()
+	return maps.Clone(l.labels)
+}
+
+// addContract labels the provided contract address with the name of its contract definition, if it was not already
+// labeled. Every contract after the first with the same name is labeled with an index suffix (e.g. "Vault[1]").
+// Returns the label for the contract address.
+func (l *AddressLabels) addContract(address common.Address, contractName string) string {
+	l.labelsLock.Lock()
+	defer l.labelsLock.Unlock()
+
+	// If the address is already labeled, we keep its label, unless it was labeled as a different contract (e.g. a
+	// contract was deployed to the same address with different code in another worker).
+	if label, labeled := l.labels[address]; labeled {
+		if existingName, isContract := l.contractNames[address]; !isContract || existingName == contractName {
+			return label
+		}
+	}
+
+	// Label the contract with its name, and an index suffix if contracts were already labeled with the name.
+	label := contractName
+	if count := l.contractCounts[contractName]; count > 0 {
+		label = fmt.Sprintf("%s[%d]", contractName, count)
+	}
+	l.contractCounts[contractName]++
+	l.contractNames[address] = contractName
+	l.labels[address] = label
+	return label
+}
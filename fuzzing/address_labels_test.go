@@ -0,0 +1,53 @@
+package fuzzing
+
+import (
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAddressLabels tests that the AddressLabels registry labels senders, the deployer, and deployed contracts, and
+// that configured aliases take precedence over them.
+func TestAddressLabels(t *testing.T) {
+	senders := []common.Address{common.HexToAddress("0x10000"), common.HexToAddress("0x20000"), common.HexToAddress("0x30000")}
+	aliases := map[common.Address]string{
+		common.HexToAddress("0x20000"): "Alice",
+		common.HexToAddress("0x50000"): "Treasury",
+	}
+	labels := newAddressLabels(common.HexToAddress("0x40000"), senders, aliases)
+
+	// Senders and the deployer should be labeled, unless an alias was provided for them.
+	assert.EqualValues(t, "sender[0]", labels.Label(senders[0]))
+	assert.EqualValues(t, "Alice", labels.Label(senders[1]))
+	assert.EqualValues(t, "sender[2]", labels.Label(senders[2]))
+	assert.EqualValues(t, "deployer", labels.Label(common.HexToAddress("0x40000")))
+	assert.EqualValues(t, "Treasury", labels.Label(common.HexToAddress("0x50000")))
+	assert.EqualValues(t, "", labels.Label(common.HexToAddress("0x60000")))
+
+	// A deployer which is also a sender keeps its sender label.
+	labels = newAddressLabels(senders[2], senders, nil)
+	assert.EqualValues(t, "sender[2]", labels.Label(senders[2]))
+
+	// Contracts should be labeled with their name, with an index suffix for every instance after the first.
+	firstVault, secondVault, token := common.HexToAddress("0xA1"), common.HexToAddress("0xA2"), common.HexToAddress("0xA3")
+	assert.EqualValues(t, "Vault", labels.addContract(firstVault, "Vault"))
+	assert.EqualValues(t, "Vault[1]", labels.addContract(secondVault, "Vault"))
+	assert.EqualValues(t, "Token", labels.addContract(token, "Token"))
+
+	// Re-adding a contract should keep its label, unless a different contract was deployed to its address.
+	assert.EqualValues(t, "Vault", labels.addContract(firstVault, "Vault"))
+	assert.EqualValues(t, "Token[1]", labels.addContract(secondVault, "Token"))
+
+	// Contracts should not replace existing labels of accounts.
+	assert.EqualValues(t, "sender[0]", labels.addContract(senders[0], "Vault"))
+	assert.Len(t, labels.Labels(), len(senders)+3)
+}
+
+// TestFormatTestAddress tests that addresses in test results are displayed on their own if no labels are known.
+func TestFormatTestAddress(t *testing.T) {
+	address := common.HexToAddress("0x10000")
+	assert.EqualValues(t, "0x10000", formatTestAddress(nil, address))
+	assert.EqualValues(t, "0x10000", formatTestAddress(&calls.CallSequence{}, address))
+}
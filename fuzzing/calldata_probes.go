@@ -0,0 +1,125 @@
+package fuzzing
+
+import (
+	"math/big"
+	"math/rand"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// calldataProbeMaxBytes describes the maximum amount of bytes a calldata probe removes from or appends to call data.
+const calldataProbeMaxBytes = 32
+
+// newCalldataProbe creates a random calls.CalldataProbe to malform the provided ABI encoded call data for the provided
+// method. The call data is either truncated, extended with random bytes, or has the offset word of one of its dynamic
+// arguments corrupted. Truncation is only chosen if the call data has arguments, and offset corruption is only chosen
+// if the method has top-level dynamic arguments.
+// Returns the calldata probe.
+func newCalldataProbe(randomProvider *rand.Rand, method *abi.Method, data []byte) *calls.CalldataProbe {
+	// Determine which kinds of probes can be applied to this call data.
+	kinds := []calls.CalldataProbeKind{calls.CalldataProbeExtended}
+	if len(data) > 4 {
+		kinds = append(kinds, calls.CalldataProbeTruncated)
+	}
+	offsetPositions := dynamicArgumentOffsetPositions(method, data)
+	if len(offsetPositions) > 0 {
+		kinds = append(kinds, calls.CalldataProbeCorruptedOffset)
+	}
+
+	// Create a probe of a random kind.
+	probe := &calls.CalldataProbe{Kind: kinds[randomProvider.Intn(len(kinds))]}
+	switch probe.Kind {
+	case calls.CalldataProbeTruncated:
+		probe.TruncatedBytes = 1 + randomProvider.Intn(min(calldataProbeMaxBytes, len(data)-4))
+	case calls.CalldataProbeExtended:
+		probe.AppendedData = make([]byte, 1+randomProvider.Intn(calldataProbeMaxBytes))
+		randomProvider.Read(probe.AppendedData)
+	case calls.CalldataProbeCorruptedOffset:
+		probe.OffsetPosition = offsetPositions[randomProvider.Intn(len(offsetPositions))]
+		probe.OffsetWord = corruptOffsetWord(randomProvider, new(big.Int).SetBytes(data[probe.OffsetPosition:probe.OffsetPosition+32]), len(data)-4)
+	}
+	return probe
+}
+
+// corruptOffsetWord creates a 32-byte word to replace the provided offset of a dynamic argument with, given the length
+// of the encoded arguments the offset is relative to. The offset is either shifted slightly, zeroed, pointed to the
+// end of the arguments, or set to a value far beyond them.
+// Returns the corrupted offset word.
+func corruptOffsetWord(randomProvider *rand.Rand, offset *big.Int, argumentsLength int) []byte {
+	var corrupted *big.Int
+	switch randomProvider.Intn(4) {
+	case 0:
+		delta := big.NewInt(int64(1 + randomProvider.Intn(calldataProbeMaxBytes)))
+		if randomProvider.Intn(2) == 0 && offset.Cmp(delta) >= 0 {
+			corrupted = new(big.Int).Sub(offset, delta)
+		} else {
+			corrupted = new(big.Int).Add(offset, delta)
+		}
+	case 1:
+		corrupted = big.NewInt(0)
+	case 2:
+		corrupted = big.NewInt(int64(argumentsLength))
+	default:
+		corrupted = new(big.Int).Set(math.MaxBig256)
+	}
+	return common.BigToHash(corrupted).Bytes()
+}
+
+// dynamicArgumentOffsetPositions determines the positions in the provided ABI encoded call data of the offset words
+// of the top-level dynamic arguments of the provided method. Positions which fall outside the call data are omitted.
+// Returns the positions of the offset words.
+func dynamicArgumentOffsetPositions(method *abi.Method, data []byte) []int {
+	if method == nil {
+		return nil
+	}
+	positions := make([]int, 0)
+	headPosition := 4
+	for _, input := range method.Inputs {
+		if isDynamicAbiType(input.Type) {
+			if headPosition+32 <= len(data) {
+				positions = append(positions, headPosition)
+			}
+			headPosition += 32
+		} else {
+			headPosition += abiStaticSize(input.Type)
+		}
+	}
+	return positions
+}
+
+// isDynamicAbiType determines whether the provided ABI type is dynamic, meaning it is encoded as an offset in the head
+// of its enclosing arguments, with its contents encoded in their tail.
+func isDynamicAbiType(t abi.Type) bool {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy:
+		return true
+	case abi.ArrayTy:
+		return isDynamicAbiType(*t.Elem)
+	case abi.TupleTy:
+		for _, elem := range t.TupleElems {
+			if isDynamicAbiType(*elem) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// abiStaticSize determines the size of the provided static ABI type when it is encoded in the head of its enclosing
+// arguments.
+func abiStaticSize(t abi.Type) int {
+	switch t.T {
+	case abi.ArrayTy:
+		return t.Size * abiStaticSize(*t.Elem)
+	case abi.TupleTy:
+		size := 0
+		for _, elem := range t.TupleElems {
+			size += abiStaticSize(*elem)
+		}
+		return size
+	}
+	return 32
+}
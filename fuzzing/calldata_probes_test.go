@@ -0,0 +1,51 @@
+package fuzzing
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCalldataProbeOffsetPositions tests that the offset words of top-level dynamic arguments are located in the
+// head of ABI encoded call data, accounting for the size of static arrays and tuples which precede them.
+func TestCalldataProbeOffsetPositions(t *testing.T) {
+	newType := func(typeName string, components []abi.ArgumentMarshaling) abi.Type {
+		abiType, err := abi.NewType(typeName, "", components)
+		assert.NoError(t, err)
+		return abiType
+	}
+	inputs := abi.Arguments{
+		{Name: "a", Type: newType("uint256", nil)},
+		{Name: "b", Type: newType("bytes", nil)},
+		{Name: "c", Type: newType("uint256[2]", nil)},
+		{Name: "d", Type: newType("tuple", []abi.ArgumentMarshaling{{Name: "x", Type: "address"}, {Name: "y", Type: "bool"}})},
+		{Name: "e", Type: newType("string", nil)},
+		{Name: "f", Type: newType("uint8[][2]", nil)},
+	}
+	method := abi.NewMethod("probe", "probe", abi.Function, "nonpayable", false, false, inputs, nil)
+
+	// The head consists of a, offset(b), c[0], c[1], d.x, d.y, offset(e), offset(f).
+	data := make([]byte, 4+32*8)
+	for _, position := range []int{4 + 32, 4 + 32*6, 4 + 32*7} {
+		data[position+31] = 0xff
+	}
+	assert.EqualValues(t, []int{4 + 32, 4 + 32*6, 4 + 32*7}, dynamicArgumentOffsetPositions(&method, data))
+
+	// Offsets which fall outside the call data are omitted.
+	assert.EqualValues(t, []int{4 + 32}, dynamicArgumentOffsetPositions(&method, data[:4+32*6]))
+
+	// Probes created for the call data must retain its selector.
+	randomProvider := rand.New(rand.NewSource(1))
+	kinds := make(map[calls.CalldataProbeKind]bool)
+	for i := 0; i < 100; i++ {
+		probe := newCalldataProbe(randomProvider, &method, data)
+		kinds[probe.Kind] = true
+		malformed := probe.Apply(data)
+		assert.EqualValues(t, data[:4], malformed[:4])
+		assert.NotEqualValues(t, data, malformed)
+	}
+	assert.Len(t, kinds, 3)
+}
@@ -0,0 +1,93 @@
+package calls
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"golang.org/x/exp/slices"
+)
+
+// CalldataProbeKind describes how a CalldataProbe malforms ABI encoded call data.
+type CalldataProbeKind string
+
+const (
+	// CalldataProbeTruncated describes call data which had bytes removed from its end.
+	CalldataProbeTruncated CalldataProbeKind = "truncated"
+
+	// CalldataProbeExtended describes call data which had random bytes appended to it.
+	CalldataProbeExtended CalldataProbeKind = "extended"
+
+	// CalldataProbeCorruptedOffset describes call data which had the offset word of a dynamic argument replaced.
+	CalldataProbeCorruptedOffset CalldataProbeKind = "corruptedOffset"
+)
+
+// CalldataProbe describes a malformation applied to the ABI encoded call data of a CallMessage, used to probe how
+// contracts decode truncated, over-long, or otherwise malformed call data. The method selector is always retained.
+// Probes are applied to call data after it is packed from its ABI values, so they can be re-applied whenever the call
+// data is re-encoded, or dropped to restore the clean encoding.
+type CalldataProbe struct {
+	// Kind describes how the call data is malformed.
+	Kind CalldataProbeKind `json:"kind"`
+
+	// TruncatedBytes describes the amount of bytes removed from the end of the call data, if Kind is
+	// CalldataProbeTruncated.
+	TruncatedBytes int `json:"truncatedBytes,omitempty"`
+
+	// AppendedData describes the bytes appended to the call data, if Kind is CalldataProbeExtended.
+	AppendedData hexutil.Bytes `json:"appendedData,omitempty"`
+
+	// OffsetPosition describes the position in the call data of the offset word which is replaced, if Kind is
+	// CalldataProbeCorruptedOffset.
+	OffsetPosition int `json:"offsetPosition,omitempty"`
+
+	// OffsetWord describes the 32-byte word the offset word is replaced with, if Kind is
+	// CalldataProbeCorruptedOffset.
+	OffsetWord hexutil.Bytes `json:"offsetWord,omitempty"`
+}
+
+// Apply returns a copy of the provided ABI encoded call data, malformed as described by the CalldataProbe. The first
+// four bytes of the call data (the method selector) are never altered.
+func (p *CalldataProbe) Apply(data []byte) []byte {
+	data = slices.Clone(data)
+	switch p.Kind {
+	case CalldataProbeTruncated:
+		if len(data) > 4 {
+			data = data[:max(4, len(data)-p.TruncatedBytes)]
+		}
+	case CalldataProbeExtended:
+		data = append(data, p.AppendedData...)
+	case CalldataProbeCorruptedOffset:
+		if p.OffsetPosition >= 4 && p.OffsetPosition+32 <= len(data) && len(p.OffsetWord) == 32 {
+			copy(data[p.OffsetPosition:], p.OffsetWord)
+		}
+	}
+	return data
+}
+
+// Clone creates a copy of the CalldataProbe. A nil CalldataProbe is cloned as nil.
+func (p *CalldataProbe) Clone() *CalldataProbe {
+	if p == nil {
+		return nil
+	}
+	return &CalldataProbe{
+		Kind:           p.Kind,
+		TruncatedBytes: p.TruncatedBytes,
+		AppendedData:   slices.Clone(p.AppendedData),
+		OffsetPosition: p.OffsetPosition,
+		OffsetWord:     slices.Clone(p.OffsetWord),
+	}
+}
+
+// String returns a displayable string describing the CalldataProbe.
+func (p *CalldataProbe) String() string {
+	switch p.Kind {
+	case CalldataProbeTruncated:
+		return fmt.Sprintf("calldata truncated by %d byte(s)", p.TruncatedBytes)
+	case CalldataProbeExtended:
+		return fmt.Sprintf("calldata extended by %d byte(s)", len(p.AppendedData))
+	case CalldataProbeCorruptedOffset:
+		return fmt.Sprintf("calldata offset at byte %d corrupted to %s", p.OffsetPosition, p.OffsetWord.String())
+	default:
+		return fmt.Sprintf("calldata probe %s", p.Kind)
+	}
+}
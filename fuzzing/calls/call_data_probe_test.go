@@ -0,0 +1,82 @@
+package calls
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCalldataProbeApply tests that each kind of calldata probe malforms call data as described, without altering the
+// method selector or the original call data.
+func TestCalldataProbeApply(t *testing.T) {
+	data := append([]byte{0xaa, 0xbb, 0xcc, 0xdd}, common.BigToHash(big.NewInt(0x20)).Bytes()...)
+	data = append(data, common.BigToHash(big.NewInt(1)).Bytes()...)
+	original := common.CopyBytes(data)
+
+	// Truncation removes bytes from the end, but never the selector.
+	truncated := (&CalldataProbe{Kind: CalldataProbeTruncated, TruncatedBytes: 3}).Apply(data)
+	assert.EqualValues(t, original[:len(original)-3], truncated)
+	truncated = (&CalldataProbe{Kind: CalldataProbeTruncated, TruncatedBytes: 1000}).Apply(data)
+	assert.EqualValues(t, original[:4], truncated)
+
+	// Extension appends bytes to the end.
+	extended := (&CalldataProbe{Kind: CalldataProbeExtended, AppendedData: []byte{1, 2}}).Apply(data)
+	assert.EqualValues(t, append(common.CopyBytes(original), 1, 2), extended)
+
+	// Offset corruption replaces a word, unless it falls outside the call data.
+	corruptWord := common.BigToHash(big.NewInt(0x1337)).Bytes()
+	corrupted := (&CalldataProbe{Kind: CalldataProbeCorruptedOffset, OffsetPosition: 4, OffsetWord: corruptWord}).Apply(data)
+	assert.EqualValues(t, original[:4], corrupted[:4])
+	assert.EqualValues(t, corruptWord, corrupted[4:36])
+	assert.EqualValues(t, original[36:], corrupted[36:])
+	corrupted = (&CalldataProbe{Kind: CalldataProbeCorruptedOffset, OffsetPosition: 60, OffsetWord: corruptWord}).Apply(data)
+	assert.EqualValues(t, original, corrupted)
+
+	// The original call data must be left untouched.
+	assert.EqualValues(t, original, data)
+}
+
+// TestCallSequenceElementCalldataProbe tests that a call sequence element's calldata probe is re-applied when its call
+// data is re-encoded, cloned independently, and serialized with the element.
+func TestCallSequenceElementCalldataProbe(t *testing.T) {
+	// Create a method and a call to it with ABI values.
+	uint256Type, err := abi.NewType("uint256", "", nil)
+	assert.NoError(t, err)
+	method := abi.NewMethod("setLimit", "setLimit", abi.Function, "nonpayable", false, false, abi.Arguments{{Name: "limit", Type: uint256Type}}, nil)
+	recipient := common.HexToAddress("0x20000")
+	msg := NewCallMessageWithAbiValueData(common.HexToAddress("0x10000"), &recipient, 0, big.NewInt(0), 100_000, big.NewInt(1), big.NewInt(1), big.NewInt(1), &CallMessageDataAbiValues{
+		Method:      &method,
+		InputValues: []any{big.NewInt(7)},
+	})
+	cleanData := common.CopyBytes(msg.Data)
+	element := NewCallSequenceElement(nil, msg, 0, 0)
+
+	// Re-encoding without a probe yields the clean encoding.
+	element.WithDataAbiValues(element.Call.DataAbiValues)
+	assert.EqualValues(t, cleanData, element.Call.Data)
+
+	// Re-encoding with a probe re-applies it.
+	element.CalldataProbe = &CalldataProbe{Kind: CalldataProbeExtended, AppendedData: []byte{0xff}}
+	element.WithDataAbiValues(element.Call.DataAbiValues)
+	assert.EqualValues(t, append(common.CopyBytes(cleanData), 0xff), element.Call.Data)
+
+	// Clones must not share probe data with the original.
+	clone, err := element.Clone()
+	assert.NoError(t, err)
+	assert.EqualValues(t, element.CalldataProbe, clone.CalldataProbe)
+	clone.CalldataProbe.AppendedData[0] = 0x00
+	assert.EqualValues(t, 0xff, element.CalldataProbe.AppendedData[0])
+
+	// The probe must be serialized with the element.
+	b, err := json.Marshal(element)
+	assert.NoError(t, err)
+	var deserialized CallSequenceElement
+	assert.NoError(t, json.Unmarshal(b, &deserialized))
+	assert.EqualValues(t, element.CalldataProbe, deserialized.CalldataProbe)
+	assert.EqualValues(t, element.Call.Data, deserialized.Call.Data)
+	assert.EqualValues(t, "calldata extended by 1 byte(s)", element.CalldataProbe.String())
+}
@@ -168,6 +168,35 @@ func (m *CallMessage) FillFromTestChainProperties(chain *chain.TestChain) {
 	m.GasTipCap = big.NewInt(0)
 }
 
+// SpendableBalance returns the balance of the message sender on the provided chain which remains after paying for the
+// message's gas limit at its gas price. FillFromTestChainProperties should be called first so the gas price is set.
+func (m *CallMessage) SpendableBalance(chain *chain.TestChain) *big.Int {
+	spendable := chain.State().GetBalance(m.From).ToBig()
+	if m.GasPrice != nil {
+		gasCost := new(big.Int).Mul(new(big.Int).SetUint64(m.GasLimit), m.GasPrice)
+		spendable.Sub(spendable, gasCost)
+	}
+	if spendable.Sign() < 0 {
+		spendable.SetUint64(0)
+	}
+	return spendable
+}
+
+// ClampValue constrains the message's value to the balance its sender can spend on the provided chain, and to the
+// provided maximum value if it is non-nil, so the message is not rejected for insufficient funds before execution.
+// FillFromTestChainProperties should be called first so the gas price is set.
+func (m *CallMessage) ClampValue(chain *chain.TestChain, maxValue *big.Int) {
+	if m.Value == nil || m.Value.Sign() <= 0 {
+		return
+	}
+	if maxValue != nil && m.Value.Cmp(maxValue) > 0 {
+		m.Value = new(big.Int).Set(maxValue)
+	}
+	if spendable := m.SpendableBalance(chain); m.Value.Cmp(spendable) > 0 {
+		m.Value = spendable
+	}
+}
+
 // Clone creates a copy of the given message and its underlying components, or an error if one occurs.
 func (m *CallMessage) Clone() (*CallMessage, error) {
 	// Clone our underlying ABI values data if we have any.
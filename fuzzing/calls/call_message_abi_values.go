@@ -1,8 +1,10 @@
 package calls
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+
 	"github.com/crytic/medusa/fuzzing/valuegeneration"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
@@ -34,9 +36,23 @@ type CallMessageDataAbiValues struct {
 	// The Method will be resolved using this internal reference when Resolve is called.
 	methodSignature string
 
+	// methodID stores the selector of the method when decoding from JSON, if one was provided. The Method will be
+	// resolved using it when Resolve is called, if no methodSignature was provided. Otherwise, it must match the
+	// selector of the method resolved.
+	methodID []byte
+
 	// encodedInputValues stores the raw encoded input values when decoding from JSON. The actual InputValues will be
 	// decoded using this and the resolved Method once Resolve is called.
 	encodedInputValues []any
+
+	// encodedInputTypes stores the ABI type tag of each of the encodedInputValues when decoding from JSON, if they were
+	// provided. They must match the input types of the resolved Method when Resolve is called. Empty type tags are not
+	// checked.
+	encodedInputTypes []string
+
+	// encodedInputNames stores the name of each of the encodedInputValues when decoding from JSON, if they were
+	// provided, so that values which were not resolved are encoded as they were decoded.
+	encodedInputNames []string
 }
 
 // callMessageDataAbiValuesMarshal is used as an internal struct to represent JSON serialized data for
@@ -55,7 +71,10 @@ func (m *CallMessageDataAbiValues) Clone() (*CallMessageDataAbiValues, error) {
 		InputValues:        nil, // set lower
 		methodName:         m.methodName,
 		methodSignature:    m.methodSignature,
+		methodID:           m.methodID,
 		encodedInputValues: m.encodedInputValues,
+		encodedInputTypes:  m.encodedInputTypes,
+		encodedInputNames:  m.encodedInputNames,
 	}
 
 	// If we have a method, clone our input values by packing/unpacking them.
@@ -88,6 +107,15 @@ func (d *CallMessageDataAbiValues) Resolve(contractAbi abi.ABI) error {
 		}
 	}
 
+	// If we only have a method selector, try to resolve the method it identifies.
+	if d.Method == nil && d.methodSignature == "" && len(d.methodID) > 0 {
+		if resolvedMethod, err := contractAbi.MethodById(d.methodID); err == nil {
+			d.Method = resolvedMethod
+		} else {
+			return fmt.Errorf("could not resolve method selector '%#x'", d.methodID)
+		}
+	}
+
 	// TODO: Deprecated old way of resolving methods. This is left for compatibility with old corpuses, but should be
 	//  removed at a later date in favor of methodSignature resolution. It resolves a method by name if it has not been.
 	if d.Method == nil {
@@ -99,6 +127,22 @@ func (d *CallMessageDataAbiValues) Resolve(contractAbi abi.ABI) error {
 	}
 	d.methodSignature = d.Method.Sig
 
+	// If we were provided a selector alongside the method signature, or type tags for our encoded input values, they
+	// must describe the method we resolved.
+	if len(d.methodID) > 0 && !bytes.Equal(d.methodID, d.Method.ID) {
+		return fmt.Errorf("method selector '%#x' does not match method signature '%v'", d.methodID, d.methodSignature)
+	}
+	if len(d.encodedInputTypes) > 0 {
+		if len(d.encodedInputTypes) != len(d.Method.Inputs) {
+			return fmt.Errorf("method '%v' describes %d input arguments, but %d were provided", d.methodSignature, len(d.Method.Inputs), len(d.encodedInputTypes))
+		}
+		for i, inputType := range d.encodedInputTypes {
+			if inputType != "" && inputType != d.Method.Inputs[i].Type.String() {
+				return fmt.Errorf("argument %d of method '%v' has type '%v', but was provided as type '%v'", i, d.methodSignature, d.Method.Inputs[i].Type.String(), inputType)
+			}
+		}
+	}
+
 	// Now that we've resolved the method, decode our encoded input values.
 	decodedArguments, err := valuegeneration.DecodeJSONArgumentsFromSlice(d.Method.Inputs, d.encodedInputValues, make(map[string]common.Address))
 	if err != nil {
@@ -107,7 +151,10 @@ func (d *CallMessageDataAbiValues) Resolve(contractAbi abi.ABI) error {
 
 	// If we've decoded arguments successfully, set them and clear our encoded arguments as they're no longer needed.
 	d.InputValues = decodedArguments
+	d.methodID = nil
 	d.encodedInputValues = nil
+	d.encodedInputTypes = nil
+	d.encodedInputNames = nil
 	return nil
 }
 
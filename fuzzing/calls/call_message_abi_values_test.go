@@ -0,0 +1,72 @@
+package calls
+
+import (
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/valuegeneration"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCallMessageMutatedCompositeAbiValues ensures that call messages whose arguments are tuples or nested arrays can
+// be re-encoded via WithDataAbiValues after their arguments are mutated or shrunk.
+func TestCallMessageMutatedCompositeAbiValues(t *testing.T) {
+	// Define a method which takes a struct with mixed field types and a slice of fixed-size arrays.
+	methodAbi, err := abi.JSON(strings.NewReader(`[{"type":"function","name":"test","stateMutability":"nonpayable","outputs":[],"inputs":[
+		{"name":"s","type":"tuple","components":[{"name":"amount","type":"uint256"},{"name":"owner","type":"address"},{"name":"enabled","type":"bool"},{"name":"name","type":"string"},{"name":"id","type":"bytes32"}]},
+		{"name":"values","type":"uint256[3][]"}
+	]}]`))
+	assert.NoError(t, err)
+	method := methodAbi.Methods["test"]
+
+	// Create our value generator and mutators.
+	randomProvider := rand.New(rand.NewSource(0))
+	generator := valuegeneration.NewMutationalValueGenerator(&valuegeneration.MutationalValueGeneratorConfig{
+		MinMutationRounds:        0,
+		MaxMutationRounds:        1,
+		MutateIntegerProbability: 0.8,
+		MutateBoolProbability:    0.8,
+		MutateStringProbability:  0.8,
+		RandomValueGeneratorConfig: &valuegeneration.RandomValueGeneratorConfig{
+			GenerateRandomArrayMinSize:  1,
+			GenerateRandomArrayMaxSize:  4,
+			GenerateRandomStringMinSize: 1,
+			GenerateRandomStringMaxSize: 10,
+		},
+	}, valuegeneration.NewValueSet(), randomProvider)
+	shrinker := valuegeneration.NewShrinkingValueMutator(&valuegeneration.ShrinkingValueMutatorConfig{
+		ShrinkValueProbability: 0.5,
+	}, valuegeneration.NewValueSet(), randomProvider)
+
+	// Generate a call message, then repeatedly mutate and shrink its arguments, re-encoding it each time.
+	inputValues := make([]any, len(method.Inputs))
+	for i, input := range method.Inputs {
+		inputValues[i] = valuegeneration.GenerateAbiValue(generator, &input.Type)
+	}
+	to := common.HexToAddress("0x20000")
+	msg := NewCallMessageWithAbiValueData(common.HexToAddress("0x10000"), &to, 0, big.NewInt(0), 100_000, big.NewInt(1), big.NewInt(0), big.NewInt(0), &CallMessageDataAbiValues{
+		Method:      &method,
+		InputValues: inputValues,
+	})
+	for i := 0; i < 50; i++ {
+		mutator := valuegeneration.ValueMutator(generator)
+		if i%2 == 1 {
+			mutator = shrinker
+		}
+		abiValues := msg.DataAbiValues
+		for j := range abiValues.InputValues {
+			abiValues.InputValues[j], err = valuegeneration.MutateAbiValue(generator, mutator, &method.Inputs[j].Type, abiValues.InputValues[j])
+			assert.NoError(t, err)
+		}
+		msg.WithDataAbiValues(abiValues)
+
+		// Ensure the call data was re-encoded with our mutated values.
+		encodedValues, err := method.Inputs.Pack(abiValues.InputValues...)
+		assert.NoError(t, err)
+		assert.EqualValues(t, encodedValues, msg.Data[4:])
+	}
+}
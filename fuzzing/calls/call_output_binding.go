@@ -0,0 +1,80 @@
+package calls
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/slices"
+)
+
+// CallOutputBinding describes an argument of a call which is bound to a return value of the call executed prior to it
+// in the same call sequence, e.g. an identifier returned by one call and consumed by the next. Bindings are resolved
+// when the call is executed, by decoding the return data of the prior call, so they are preserved when a call
+// sequence is replayed or shrunk. The generated value of the argument is used if a binding cannot be resolved.
+type CallOutputBinding struct {
+	// ArgumentIndex describes the index of the argument of the call which is bound.
+	ArgumentIndex int `json:"argumentIndex"`
+
+	// OutputIndex describes the index of the return value of the prior call the argument is bound to.
+	OutputIndex int `json:"outputIndex"`
+}
+
+// Clone creates a copy of the CallOutputBinding. A nil CallOutputBinding is cloned as nil.
+func (b *CallOutputBinding) Clone() *CallOutputBinding {
+	if b == nil {
+		return nil
+	}
+	return &CallOutputBinding{
+		ArgumentIndex: b.ArgumentIndex,
+		OutputIndex:   b.OutputIndex,
+	}
+}
+
+// String returns a displayable string describing the CallOutputBinding.
+func (b *CallOutputBinding) String() string {
+	return fmt.Sprintf("argument %d bound to output %d of the prior call", b.ArgumentIndex, b.OutputIndex)
+}
+
+// resolve sets the bound argument of the provided call sequence element to the bound return value of the provided
+// source element, which was executed prior to it, and re-encodes the element's call data. Bindings cannot be resolved
+// if the source call failed, or its bound return value does not exist or differs in type from the bound argument, in
+// which case the element is left unchanged.
+// Returns a boolean indicating whether the binding was resolved.
+func (b *CallOutputBinding) resolve(element *CallSequenceElement, source *CallSequenceElement) bool {
+	// Verify the bound argument exists.
+	abiValues := element.Call.DataAbiValues
+	if abiValues == nil || abiValues.Method == nil || b.ArgumentIndex < 0 || b.ArgumentIndex >= len(abiValues.Method.Inputs) || b.ArgumentIndex >= len(abiValues.InputValues) {
+		return false
+	}
+
+	// Verify the source call succeeded, and the bound return value exists with the same type as the bound argument.
+	if source.ChainReference == nil {
+		return false
+	}
+	executionResult := source.ChainReference.MessageResults().ExecutionResult
+	if executionResult == nil || executionResult.Failed() {
+		return false
+	}
+	sourceMethod, err := source.Method()
+	if err != nil || sourceMethod == nil || b.OutputIndex < 0 || b.OutputIndex >= len(sourceMethod.Outputs) {
+		return false
+	}
+	if sourceMethod.Outputs[b.OutputIndex].Type.String() != abiValues.Method.Inputs[b.ArgumentIndex].Type.String() {
+		return false
+	}
+
+	// Decode the return value and re-encode our call data with it.
+	outputValues, err := sourceMethod.Outputs.Unpack(executionResult.ReturnData)
+	if err != nil || b.OutputIndex >= len(outputValues) {
+		return false
+	}
+	resolvedAbiValues := &CallMessageDataAbiValues{
+		Method:      abiValues.Method,
+		InputValues: slices.Clone(abiValues.InputValues),
+	}
+	resolvedAbiValues.InputValues[b.ArgumentIndex] = outputValues[b.OutputIndex]
+	if _, err = resolvedAbiValues.Pack(); err != nil {
+		return false
+	}
+	element.WithDataAbiValues(resolvedAbiValues)
+	return true
+}
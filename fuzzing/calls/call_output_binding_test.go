@@ -0,0 +1,103 @@
+package calls
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/crytic/medusa/chain/testchainutils"
+	compilationTypes "github.com/crytic/medusa/compilation/types"
+	"github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCallOutputBinding tests that an argument bound to the output of the prior call is resolved when a call sequence
+// is executed, that the generated value is retained if the binding cannot be resolved, and that bindings are retained
+// when call sequence elements are cloned or serialized.
+func TestCallOutputBinding(t *testing.T) {
+	// Create a test chain with a funded sender, a contract which returns 42, a contract which reverts, and a contract
+	// which accepts any call.
+	opener := common.HexToAddress("0x30000")
+	reverter := common.HexToAddress("0x30001")
+	closer := common.HexToAddress("0x30002")
+	genesisAlloc := types.GenesisAlloc{
+		opener:   types.Account{Code: common.Hex2Bytes("602a60005260206000f3")},
+		reverter: types.Account{Code: common.Hex2Bytes("60006000fd")},
+		closer:   types.Account{Code: []byte{0x00}},
+	}
+	testChain, sender := testchainutils.NewFundedTestChainWithAlloc(t, genesisAlloc, nil)
+
+	contractAbi, err := abi.JSON(strings.NewReader(`[
+		{"type":"function","name":"open","inputs":[],"outputs":[{"name":"id","type":"uint256"}],"stateMutability":"nonpayable"},
+		{"type":"function","name":"openAddress","inputs":[],"outputs":[{"name":"id","type":"address"}],"stateMutability":"nonpayable"},
+		{"type":"function","name":"close","inputs":[{"name":"id","type":"uint256"}],"outputs":[],"stateMutability":"nonpayable"}
+	]`))
+	assert.NoError(t, err)
+	contract := contracts.NewContract("TestContract", "", &compilationTypes.CompiledContract{Abi: contractAbi}, nil)
+	newElement := func(to common.Address, methodName string, args ...any) *CallSequenceElement {
+		method := contractAbi.Methods[methodName]
+		msg := NewCallMessageWithAbiValueData(sender, &to, 0, big.NewInt(0), 100_000, nil, nil, nil, &CallMessageDataAbiValues{
+			Method:      &method,
+			InputValues: args,
+		})
+		return NewCallSequenceElement(contract, msg, 1, 1)
+	}
+
+	tests := []struct {
+		source        *CallSequenceElement
+		expectedValue int64
+	}{
+		// The binding should be resolved to the value returned by the prior call.
+		{source: newElement(opener, "open"), expectedValue: 42},
+		// The binding cannot be resolved if the prior call failed.
+		{source: newElement(reverter, "open"), expectedValue: 7},
+		// The binding cannot be resolved if the prior call's output differs in type from the bound argument.
+		{source: newElement(opener, "openAddress"), expectedValue: 7},
+	}
+	for _, test := range tests {
+		// Execute the prior call, followed by a call with an argument bound to its output.
+		boundElement := newElement(closer, "close", big.NewInt(7))
+		boundElement.OutputBinding = &CallOutputBinding{ArgumentIndex: 0, OutputIndex: 0}
+		callSequence := CallSequence{test.source, boundElement}
+		fetchElementFunc := func(currentIndex int) (*CallSequenceElement, error) {
+			if currentIndex >= len(callSequence) {
+				return nil, nil
+			}
+			callSequence[currentIndex].Call.FillFromTestChainProperties(testChain)
+			return callSequence[currentIndex], nil
+		}
+		executedSequence, err := ExecuteCallSequenceIteratively(testChain, fetchElementFunc, nil)
+		assert.NoError(t, err)
+		assert.Len(t, executedSequence, 2)
+
+		// Verify the bound argument and the call data it was encoded into.
+		assert.EqualValues(t, test.expectedValue, boundElement.Call.DataAbiValues.InputValues[0].(*big.Int).Int64())
+		expectedData, err := contractAbi.Pack("close", big.NewInt(test.expectedValue))
+		assert.NoError(t, err)
+		assert.EqualValues(t, expectedData, boundElement.Call.Data)
+	}
+
+	// Bindings should be retained when an element is cloned, or serialized and deserialized.
+	boundElement := newElement(closer, "close", big.NewInt(7))
+	boundElement.Call.FillFromTestChainProperties(testChain)
+	boundElement.OutputBinding = &CallOutputBinding{ArgumentIndex: 0, OutputIndex: 1}
+	clonedElement, err := boundElement.Clone()
+	assert.NoError(t, err)
+	assert.EqualValues(t, boundElement.OutputBinding, clonedElement.OutputBinding)
+	assert.NotSame(t, boundElement.OutputBinding, clonedElement.OutputBinding)
+
+	b, err := json.Marshal(boundElement)
+	assert.NoError(t, err)
+	var deserializedElement CallSequenceElement
+	assert.NoError(t, json.Unmarshal(b, &deserializedElement))
+	assert.EqualValues(t, boundElement.OutputBinding, deserializedElement.OutputBinding)
+
+	// Elements without bindings should not serialize them.
+	b, err = json.Marshal(newElement(closer, "close", big.NewInt(7)))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "outputBinding")
+}
@@ -30,6 +30,11 @@ func (cs CallSequence) Log() *logging.LogBuffer {
 		return buffer
 	}
 
+	// If the sequence was executed, describe the block it started executing from, so its schedule can be reconstructed.
+	if schedule := cs.Schedule(); schedule != nil {
+		buffer.Append(fmt.Sprintf("Starting from block=%d, time=%d\n", schedule.StartBlockNumber, schedule.StartBlockTimestamp))
+	}
+
 	// Construct the buffer for each call made in the sequence
 	for i := 0; i < len(cs); i++ {
 		// Add the string representing the call
@@ -90,6 +95,23 @@ func (cs CallSequence) Hash() (common.Hash, error) {
 			return common.Hash{}, err
 		}
 
+		// Hash the empty block count, if any, so the hashes of sequences without empty blocks are unchanged.
+		if cse.EmptyBlocks > 0 {
+			binary.LittleEndian.PutUint64(temp[:], cse.EmptyBlocks)
+			_, err = hashProvider.Write(temp[:])
+			if err != nil {
+				return common.Hash{}, err
+			}
+		}
+
+		// Hash the prevrandao value, if any, so the hashes of sequences without one are unchanged.
+		if cse.Prevrandao != nil {
+			_, err = hashProvider.Write(cse.Prevrandao[:])
+			if err != nil {
+				return common.Hash{}, err
+			}
+		}
+
 		// Try to pack the call message and obtain a hash for it.
 		// This may panic if the ABI changed and the ABI method/function targeted does not resolve or the call
 		// could otherwise not be packed/serialized. If it does, we use fixed hash data instead.
@@ -143,13 +165,45 @@ type CallSequenceElement struct {
 	// value will not be used.
 	BlockTimestampDelay uint64 `json:"blockTimestampDelay"`
 
+	// EmptyBlocks defines how many empty "filler" blocks should be mined before executing this transaction. Any pending
+	// block is committed first, then each empty block advances the block number by one and the block timestamp by
+	// EmptyBlockTimestampDelay, before the BlockNumberDelay and BlockTimestampDelay are applied to the block including
+	// this transaction. This exercises logic which depends on blocks being produced without interacting with it.
+	EmptyBlocks uint64 `json:"emptyBlocks,omitempty"`
+
+	// Prevrandao defines the prevrandao value of the block created to include this transaction, overriding the value
+	// the chain is configured to derive. It is only used if executing this transaction creates a new block, and is nil
+	// if the chain's value should be used.
+	Prevrandao *common.Hash `json:"prevrandao,omitempty"`
+
 	// ChainReference describes the inclusion of the Call as a transaction in a block. This block may not yet be
 	// committed to its underlying chain if this is a CallSequenceElement was just executed. Additional transactions
 	// may be included before the block is committed. This reference will remain compatible after the block finalizes.
 	ChainReference *CallSequenceElementChainReference `json:"-"`
 
+	// ExecutedBlock describes the block number and timestamp the Call was last executed at. It is recorded whenever
+	// the element is executed, so serialized call sequences describe the schedule they executed with. It is nil if the
+	// element was never executed, and is not used when executing the element.
+	ExecutedBlock *CallSequenceElementExecutedBlock `json:"executedBlock,omitempty"`
+
 	// ExecutionTrace represents a verbose execution trace collected. Nil if an execution trace was not collected.
 	ExecutionTrace *executiontracer.ExecutionTrace `json:"-"`
+
+	// CalldataProbe describes the malformation applied to the Call data after it was packed from its ABI values, if
+	// the element probes how its target decodes malformed call data. Nil if the call data is cleanly encoded.
+	CalldataProbe *CalldataProbe `json:"calldataProbe,omitempty"`
+
+	// OutputBinding describes an argument of the Call which is bound to a return value of the call executed prior to
+	// it, and is resolved when the element is executed. Nil if none of the Call arguments are bound.
+	OutputBinding *CallOutputBinding `json:"outputBinding,omitempty"`
+
+	// Setup indicates whether the element is a setup call which the rest of the call sequence structurally depends on,
+	// such that shrinking should never remove it. Its arguments may still be shrunk, unless Frozen is also set.
+	Setup bool `json:"setup,omitempty"`
+
+	// Frozen indicates whether the element should be left unchanged by shrinking, including its arguments, the empty
+	// blocks mined before it, and any malformation of its call data.
+	Frozen bool `json:"frozen,omitempty"`
 }
 
 // NewCallSequenceElement returns a new CallSequenceElement struct to track a single call made within a CallSequence.
@@ -160,6 +214,7 @@ func NewCallSequenceElement(contract *fuzzingTypes.Contract, call *CallMessage,
 		BlockNumberDelay:    blockNumberDelay,
 		BlockTimestampDelay: blockTimestampDelay,
 		ChainReference:      nil,
+		ExecutedBlock:       nil,
 		ExecutionTrace:      nil,
 	}
 	return callSequenceElement
@@ -179,12 +234,28 @@ func (cse *CallSequenceElement) Clone() (*CallSequenceElement, error) {
 		Call:                clonedCall,
 		BlockNumberDelay:    cse.BlockNumberDelay,
 		BlockTimestampDelay: cse.BlockTimestampDelay,
+		EmptyBlocks:         cse.EmptyBlocks,
+		Prevrandao:          cse.Prevrandao,
 		ChainReference:      cse.ChainReference,
+		ExecutedBlock:       cse.ExecutedBlock,
 		ExecutionTrace:      cse.ExecutionTrace,
+		CalldataProbe:       cse.CalldataProbe.Clone(),
+		OutputBinding:       cse.OutputBinding.Clone(),
+		Setup:               cse.Setup,
+		Frozen:              cse.Frozen,
 	}
 	return clone, nil
 }
 
+// WithDataAbiValues resets the element's call data and ABI values, as CallMessage.WithDataAbiValues does, then
+// re-applies the element's CalldataProbe to the packed call data, if it has one.
+func (cse *CallSequenceElement) WithDataAbiValues(abiData *CallMessageDataAbiValues) {
+	cse.Call.WithDataAbiValues(abiData)
+	if cse.CalldataProbe != nil {
+		cse.Call.Data = cse.CalldataProbe.Apply(cse.Call.Data)
+	}
+}
+
 // Method obtains the abi.Method targeted by the CallSequenceElement.Call, or an error if one occurred while obtaining
 // it.
 func (cse *CallSequenceElement) Method() (*abi.Method, error) {
@@ -246,8 +317,14 @@ func (cse *CallSequenceElement) String() string {
 	// Get our labels that we can use to make the string look better
 	labels := chain.GetLabels(cse.ChainReference.MessageResults())
 
-	// Next decode our arguments (we jump four bytes to skip the function selector)
-	args, err := method.Inputs.Unpack(cse.Call.Data[4:])
+	// Next decode our arguments (we jump four bytes to skip the function selector). If the call data was malformed
+	// by a probe, we display the ABI values it was packed from instead.
+	var args []any
+	if cse.CalldataProbe != nil && cse.Call.DataAbiValues != nil {
+		args = cse.Call.DataAbiValues.InputValues
+	} else {
+		args, err = method.Inputs.Unpack(cse.Call.Data[4:])
+	}
 	argsText := "<unable to unpack args>"
 	if err == nil {
 		argsText, err = valuegeneration.EncodeABIArgumentsToString(method.Inputs, args, labels)
@@ -259,7 +336,10 @@ func (cse *CallSequenceElement) String() string {
 	// If we have runtime info, populate it
 	blockNumberStr := "n/a"
 	blockTimeStr := "n/a"
-	if cse.ChainReference != nil {
+	if cse.ExecutedBlock != nil {
+		blockNumberStr = strconv.FormatUint(cse.ExecutedBlock.BlockNumber, 10)
+		blockTimeStr = strconv.FormatUint(cse.ExecutedBlock.BlockTimestamp, 10)
+	} else if cse.ChainReference != nil {
 		blockNumberStr = cse.ChainReference.Block.Header.Number.String()
 		blockTimeStr = strconv.FormatUint(cse.ChainReference.Block.Header.Time, 10)
 	}
@@ -267,18 +347,37 @@ func (cse *CallSequenceElement) String() string {
 	// Trim the leading zeros and use the labels
 	fromAddress := utils.AttachLabelToAddress(cse.Call.From, labels[cse.Call.From])
 
+	// If empty blocks were mined before the call, its block's prevrandao value was overridden, its call data was
+	// malformed by a probe, or an argument was bound to the output of the prior call, describe it.
+	extraText := ""
+	if cse.EmptyBlocks > 0 {
+		extraText += fmt.Sprintf(", emptyBlocks=%d", cse.EmptyBlocks)
+	}
+	if cse.Prevrandao != nil {
+		extraText += fmt.Sprintf(", prevrandao=%s", cse.Prevrandao.Hex())
+	}
+	if cse.CalldataProbe != nil {
+		extraText += fmt.Sprintf(", probe=%s", cse.CalldataProbe.String())
+	}
+	if cse.OutputBinding != nil {
+		extraText += fmt.Sprintf(", binding=%s", cse.OutputBinding.String())
+	}
+
 	// Return a formatted string representing this element.
 	return fmt.Sprintf(
-		"%s.%s(%s) (block=%s, time=%s, gas=%d, gasprice=%s, value=%s, sender=%s)",
+		"%s.%s(%s) (block=%s, time=%s, blockDelay=%d, timeDelay=%d, gas=%d, gasprice=%s, value=%s, sender=%s%s)",
 		contractName,
 		methodName,
 		argsText,
 		blockNumberStr,
 		blockTimeStr,
+		cse.BlockNumberDelay,
+		cse.BlockTimestampDelay,
 		cse.Call.GasLimit,
 		cse.Call.GasPrice.String(),
 		cse.Call.Value.String(),
 		fromAddress,
+		extraText,
 	)
 }
 
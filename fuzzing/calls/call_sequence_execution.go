@@ -1,14 +1,27 @@
 package calls
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/crytic/medusa/chain"
+	chainTypes "github.com/crytic/medusa/chain/types"
 	"github.com/crytic/medusa/fuzzing/contracts"
 	"github.com/crytic/medusa/fuzzing/executiontracer"
+	"github.com/crytic/medusa/logging"
 	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	coretypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/tracers"
 )
 
+// EmptyBlockTimestampDelay describes how much the block timestamp advances with each empty block mined before
+// executing a CallSequenceElement, as described by CallSequenceElement.EmptyBlocks.
+const EmptyBlockTimestampDelay uint64 = 12
+
 // ExecuteCallSequenceFetchElementFunc describes a function that is called to obtain the next call sequence element to
 // execute. It is given the current call index in the sequence.
 // Returns the call sequence element to execute, or an error if one occurs. If the call sequence element is nil,
@@ -25,6 +38,10 @@ type ExecuteCallSequenceExecutionCheckFunc func(currentExecutedSequence CallSequ
 // A "fetch next call" function is provided to fetch the next element to execute.
 // A "post element executed check" function is provided to check whether execution should stop after each element is
 // executed.
+// If a call exceeds the chain's configured transaction timeout, it is aborted without affecting the chain state, and
+// is omitted from the executed call sequence. Execution then continues with the next element, or stops if the chain
+// is configured to stop call sequences on transaction timeouts. Calls which are rejected because their sender cannot
+// afford them are similarly omitted, and execution continues.
 // Returns the call sequence which was executed and an error if one occurs.
 func ExecuteCallSequenceIteratively(chain *chain.TestChain, fetchElementFunc ExecuteCallSequenceFetchElementFunc, executionCheckFunc ExecuteCallSequenceExecutionCheckFunc, additionalTracers ...*chain.TestChainTracer) (CallSequence, error) {
 	// If there is no fetch element function provided, throw an error
@@ -51,6 +68,22 @@ func ExecuteCallSequenceIteratively(chain *chain.TestChain, fetchElementFunc Exe
 			break
 		}
 
+		// If the element binds an argument to the output of the call executed prior to it, resolve it now.
+		if callSequenceElement.OutputBinding != nil && len(callSequenceExecuted) > 0 {
+			callSequenceElement.OutputBinding.resolve(callSequenceElement, callSequenceExecuted[len(callSequenceExecuted)-1])
+		}
+
+		// If the element requests empty blocks be mined before its call, mine them now.
+		if callSequenceElement.EmptyBlocks > 0 {
+			err = mineEmptyBlocks(chain, callSequenceElement.EmptyBlocks)
+			if err != nil {
+				return callSequenceExecuted, err
+			}
+		}
+
+		// Track whether this call was aborted due to exceeding the transaction timeout.
+		transactionTimedOut := false
+
 		// We try to add the transaction with our call more than once. If the pending block is too full, we may hit a
 		// block gas limit, which we handle by committing the pending block without this tx, and creating a new pending
 		// block that is empty to try adding this tx there instead.
@@ -81,15 +114,46 @@ func ExecuteCallSequenceIteratively(chain *chain.TestChain, fetchElementFunc Exe
 				if numberDelay > timeDelay {
 					numberDelay = timeDelay
 				}
-				_, err := chain.PendingBlockCreateWithParameters(chain.Head().Header.Number.Uint64()+numberDelay, chain.Head().Header.Time+timeDelay, nil)
+				// If the call defines the prevrandao value of its block, we use it rather than the chain's own.
+				var err error
+				blockNumber := chain.Head().Header.Number.Uint64() + numberDelay
+				blockTime := chain.Head().Header.Time + timeDelay
+				if callSequenceElement.Prevrandao != nil {
+					_, err = chain.PendingBlockCreateWithPrevrandao(blockNumber, blockTime, *callSequenceElement.Prevrandao, nil)
+				} else {
+					_, err = chain.PendingBlockCreateWithParameters(blockNumber, blockTime, nil)
+				}
 				if err != nil {
 					return callSequenceExecuted, err
 				}
 			}
 
+			// Record the block our transaction executes at, prior to execution, as cheat codes may alter the block
+			// header during execution.
+			executedBlock := &CallSequenceElementExecutedBlock{
+				BlockNumber:          chain.PendingBlock().Header.Number.Uint64(),
+				BlockTimestamp:       chain.PendingBlock().Header.Time,
+				ParentBlockNumber:    chain.Head().Header.Number.Uint64(),
+				ParentBlockTimestamp: chain.Head().Header.Time,
+			}
+
 			// Try to add our transaction to this block.
 			err = chain.PendingBlockAddTx(callSequenceElement.Call.ToCoreMessage(), additionalTracers...)
 
+			// If the call exceeded the transaction timeout, it was aborted and the chain state is unchanged, so we
+			// report it and move on without retrying it.
+			if isTransactionTimeoutError(err) {
+				logTransactionTimeout(callSequenceElement, chain.TestChainConfig().TransactionTimeout)
+				transactionTimedOut = true
+				break
+			}
+
+			// If the sender could not afford the call, it was rejected before execution and the chain state is
+			// unchanged. Retrying it in another block would not help, so we skip it.
+			if errors.Is(err, core.ErrInsufficientFunds) {
+				break
+			}
+
 			if err != nil {
 				// If we encountered a block gas limit error, this tx is too expensive to fit in this block.
 				// If there are other transactions in the block, this makes sense. The block is "full".
@@ -115,6 +179,7 @@ func ExecuteCallSequenceIteratively(chain *chain.TestChain, fetchElementFunc Exe
 				Block:            chain.PendingBlock(),
 				TransactionIndex: len(chain.PendingBlock().Messages) - 1,
 			}
+			callSequenceElement.ExecutedBlock = executedBlock
 
 			// Add to our executed call sequence
 			callSequenceExecuted = append(callSequenceExecuted, callSequenceElement)
@@ -138,6 +203,11 @@ func ExecuteCallSequenceIteratively(chain *chain.TestChain, fetchElementFunc Exe
 			break
 		}
 
+		// If the call timed out and we are configured to stop the sequence when this occurs, stop executing it.
+		if transactionTimedOut && chain.TestChainConfig().StopSequenceOnTransactionTimeout {
+			break
+		}
+
 		// If post-execution check requested we break execution, break out of our "execute next call sequence loop"
 		if execCheckFuncRequestedBreak {
 			break
@@ -154,6 +224,51 @@ func ExecuteCallSequenceIteratively(chain *chain.TestChain, fetchElementFunc Exe
 	return callSequenceExecuted, nil
 }
 
+// mineEmptyBlocks commits the provided chain's pending block, if it has one, then commits the provided amount of
+// empty blocks, each advancing the block number by one and the block timestamp by EmptyBlockTimestampDelay.
+// Returns an error if one occurs.
+func mineEmptyBlocks(chain *chain.TestChain, count uint64) error {
+	if chain.PendingBlock() != nil {
+		err := chain.PendingBlockCommit()
+		if err != nil {
+			return err
+		}
+	}
+	for i := uint64(0); i < count; i++ {
+		_, err := chain.PendingBlockCreateWithParameters(chain.Head().Header.Number.Uint64()+1, chain.Head().Header.Time+EmptyBlockTimestampDelay, nil)
+		if err != nil {
+			return err
+		}
+		err = chain.PendingBlockCommit()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isTransactionTimeoutError indicates whether the provided error was returned because a transaction exceeded the
+// chain's configured transaction timeout.
+func isTransactionTimeoutError(err error) bool {
+	return errors.Is(err, chain.ErrTransactionTimeout)
+}
+
+// logTransactionTimeout logs a warning indicating the call made by the provided call sequence element was aborted after
+// exceeding the provided transaction timeout, in milliseconds.
+func logTransactionTimeout(callSequenceElement *CallSequenceElement, transactionTimeout uint64) {
+	// Identify the called method as best we can.
+	contractName := "<unresolved contract>"
+	if callSequenceElement.Contract != nil {
+		contractName = callSequenceElement.Contract.Name()
+	}
+	methodName := "<unresolved method>"
+	if method, err := callSequenceElement.Method(); err == nil && method != nil {
+		methodName = method.Sig
+	}
+	logging.GlobalLogger.Warn("Call to ", contractName, ".", methodName, " exceeded the transaction timeout of ",
+		transactionTimeout, "ms and was aborted")
+}
+
 // ExecuteCallSequence executes a provided CallSequence on the provided chain.
 // It returns the slice of the call sequence which was tested, and an error if one occurred.
 // If no error occurred, it can be expected that the returned call sequence contains all elements originally provided.
@@ -170,9 +285,14 @@ func ExecuteCallSequence(chain *chain.TestChain, callSequence CallSequence) (Cal
 }
 
 // ExecuteCallSequenceWithExecutionTracer attaches an executiontracer.ExecutionTracer to ExecuteCallSequenceIteratively and attaches execution traces to the call sequence elements.
-func ExecuteCallSequenceWithExecutionTracer(testChain *chain.TestChain, contractDefinitions contracts.Contracts, callSequence CallSequence, verboseTracing bool) (CallSequence, error) {
+// By default, only the last element is traced. If verboseTracing is enabled, every element is traced, and each trace
+// is rendered and released as soon as its element executes, so the recorded call frames of the entire sequence are
+// never held at once. If a trace artifact is provided, each rendered trace is also written to it and released from
+// memory, so only the traces currently being rendered are held. The provided trace limits bound the size of each trace.
+func ExecuteCallSequenceWithExecutionTracer(testChain *chain.TestChain, contractDefinitions contracts.Contracts, callSequence CallSequence, verboseTracing bool, traceLimits executiontracer.TraceLimits, traceArtifact *executiontracer.TraceArtifact) (CallSequence, error) {
 	// Create a new execution tracer
 	executionTracer := executiontracer.NewExecutionTracer(contractDefinitions, testChain)
+	executionTracer.SetLimits(traceLimits)
 	defer executionTracer.Close()
 
 	// Execute our sequence with a simple fetch operation provided to obtain each element.
@@ -183,22 +303,127 @@ func ExecuteCallSequenceWithExecutionTracer(testChain *chain.TestChain, contract
 		return nil, nil
 	}
 
+	// After each element executes, take its trace from the tracer so it is not held any longer than needed. By
+	// default, we only trace the last element in the call sequence. If verbose tracing is enabled, we trace all
+	// elements, releasing the recorded call frames of each once it is rendered.
+	executionCheckFunc := func(currentExecutedSequence CallSequence) (bool, error) {
+		callSequenceElement := currentExecutedSequence[len(currentExecutedSequence)-1]
+		hash := utils.MessageToTransaction(callSequenceElement.Call.ToCoreMessage()).Hash()
+		trace := executionTracer.GetTrace(hash)
+		executionTracer.ReleaseTrace(hash)
+		if verboseTracing {
+			if trace != nil && traceArtifact != nil {
+				if err := trace.ReleaseTo(traceArtifact); err != nil {
+					return true, fmt.Errorf("could not write execution trace to %s: %v", traceArtifact.Path(), err)
+				}
+			} else if trace != nil {
+				trace.Release()
+			}
+			callSequenceElement.ExecutionTrace = trace
+		} else if callSequenceElement == callSequence[len(callSequence)-1] {
+			callSequenceElement.ExecutionTrace = trace
+		}
+		return false, nil
+	}
+
 	// Execute the call sequence and attach the execution tracer
-	executedCallSeq, err := ExecuteCallSequenceIteratively(testChain, fetchElementFunc, nil, executionTracer.NativeTracer())
+	return ExecuteCallSequenceIteratively(testChain, fetchElementFunc, executionCheckFunc, executionTracer.NativeTracer())
+}
+
+// AttachExecutionTraces re-executes the provided call sequence on the provided chain, starting from its current head,
+// and attaches execution traces to the call sequence elements at the provided indices. The execution tracer is only
+// attached while the requested elements execute, and any tracers already attached to the chain (e.g. coverage
+// tracers) remain in effect for all elements. The chain is reverted to its original head once execution completes.
+// Returns an error if one occurs.
+func AttachExecutionTraces(testChain *chain.TestChain, contractDefinitions contracts.Contracts, callSequence CallSequence, indices []int) error {
+	// Determine which elements we should trace, ensuring they exist in the sequence.
+	traceIndices := make(map[int]bool, len(indices))
+	for _, index := range indices {
+		if index < 0 || index >= len(callSequence) {
+			return fmt.Errorf("could not attach execution trace to call sequence element %d as the sequence only has %d elements", index, len(callSequence))
+		}
+		traceIndices[index] = true
+	}
+
+	// Record our base block so we can revert to it after execution.
+	if testChain.PendingBlock() != nil {
+		return fmt.Errorf("could not attach execution traces to call sequence as the chain has a pending block")
+	}
+	baseBlockIndex := uint64(len(testChain.CommittedBlocks()))
+
+	// Create a new execution tracer, which is only enabled while the requested elements execute.
+	executionTracer := executiontracer.NewExecutionTracer(contractDefinitions, testChain)
+	defer executionTracer.Close()
+	tracingEnabled := false
+	conditionalTracer := newConditionalTracer(executionTracer.NativeTracer(), &tracingEnabled)
 
-	// By default, we only trace the last element in the call sequence.
-	traceFrom := len(callSequence) - 1
-	// If verbose tracing is enabled, we want to trace all elements in the call sequence.
-	if verboseTracing {
-		traceFrom = 0
+	// Execute our sequence with a simple fetch operation provided to obtain each element, enabling tracing for the
+	// elements requested.
+	fetchElementFunc := func(currentIndex int) (*CallSequenceElement, error) {
+		if currentIndex < len(callSequence) {
+			tracingEnabled = traceIndices[currentIndex]
+			return callSequence[currentIndex], nil
+		}
+		return nil, nil
+	}
+	_, execErr := ExecuteCallSequenceIteratively(testChain, fetchElementFunc, nil, conditionalTracer)
+
+	// Revert our chain to its original state, regardless of whether execution succeeded.
+	err := testChain.RevertToBlockIndex(baseBlockIndex)
+	if err != nil {
+		return fmt.Errorf("could not revert chain after attaching execution traces to call sequence: %v", err)
+	}
+	if execErr != nil {
+		return fmt.Errorf("could not attach execution traces to call sequence due to an error during execution: %v", execErr)
 	}
 
 	// Attach the execution trace for each requested call sequence element
-	for ; traceFrom < len(callSequence); traceFrom++ {
-		callSequenceElement := callSequence[traceFrom]
+	for index := range traceIndices {
+		callSequenceElement := callSequence[index]
 		hash := utils.MessageToTransaction(callSequenceElement.Call.ToCoreMessage()).Hash()
 		callSequenceElement.ExecutionTrace = executionTracer.GetTrace(hash)
 	}
+	return nil
+}
 
-	return executedCallSeq, err
+// newConditionalTracer wraps the provided tracer's hooks so that they are only invoked while the provided enabled
+// flag is set. The flag should only be changed between transactions.
+// Returns the wrapping tracer.
+func newConditionalTracer(tracer *chain.TestChainTracer, enabled *bool) *chain.TestChainTracer {
+	hooks := tracer.Hooks
+	conditionalHooks := &tracing.Hooks{
+		OnTxStart: func(vm *tracing.VMContext, tx *coretypes.Transaction, from common.Address) {
+			if *enabled && hooks.OnTxStart != nil {
+				hooks.OnTxStart(vm, tx, from)
+			}
+		},
+		OnTxEnd: func(receipt *coretypes.Receipt, err error) {
+			if *enabled && hooks.OnTxEnd != nil {
+				hooks.OnTxEnd(receipt, err)
+			}
+		},
+		OnEnter: func(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+			if *enabled && hooks.OnEnter != nil {
+				hooks.OnEnter(depth, typ, from, to, input, gas, value)
+			}
+		},
+		OnExit: func(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+			if *enabled && hooks.OnExit != nil {
+				hooks.OnExit(depth, output, gasUsed, err, reverted)
+			}
+		},
+		OnOpcode: func(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+			if *enabled && hooks.OnOpcode != nil {
+				hooks.OnOpcode(pc, op, gas, cost, scope, rData, depth, err)
+			}
+		},
+	}
+	return &chain.TestChainTracer{
+		Tracer: &tracers.Tracer{Hooks: conditionalHooks},
+		CaptureTxEndSetAdditionalResults: func(results *chainTypes.MessageResults) {
+			if *enabled && tracer.CaptureTxEndSetAdditionalResults != nil {
+				tracer.CaptureTxEndSetAdditionalResults(results)
+			}
+		},
+	}
 }
@@ -0,0 +1,283 @@
+package calls
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crytic/medusa/chain/config"
+	"github.com/crytic/medusa/chain/testchainutils"
+	"github.com/crytic/medusa/fuzzing/executiontracer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExecuteCallSequenceTransactionTimeout tests that calls which exceed the chain's transaction timeout are aborted
+// without affecting the chain state, and that call sequence execution continues or stops according to the chain
+// configuration.
+func TestExecuteCallSequenceTransactionTimeout(t *testing.T) {
+	for _, stopSequence := range []bool{false, true} {
+		// Create a test chain with a funded sender and a contract which loops until it runs out of gas.
+		// JUMPDEST, PUSH1 0x00, JUMP
+		gasBurner := common.HexToAddress("0x30000")
+		genesisAlloc := types.GenesisAlloc{
+			gasBurner: types.Account{Code: []byte{0x5b, 0x60, 0x00, 0x56}},
+		}
+		testChainConfig, err := config.DefaultTestChainConfig()
+		assert.NoError(t, err)
+		testChainConfig.TransactionTimeout = 1
+		testChainConfig.StopSequenceOnTransactionTimeout = stopSequence
+		testChain, sender := testchainutils.NewFundedTestChainWithAlloc(t, genesisAlloc, testChainConfig)
+		testChain.BlockGasLimit = 10_000_000_000
+
+		// Create a call sequence which burns gas between two transfers.
+		recipient := common.HexToAddress("0x20000")
+		callSequence := CallSequence{
+			NewCallSequenceElement(nil, NewCallMessage(sender, &recipient, 0, big.NewInt(1), 100_000, nil, nil, nil, nil), 0, 0),
+			NewCallSequenceElement(nil, NewCallMessage(sender, &gasBurner, 0, big.NewInt(0), testChain.BlockGasLimit, nil, nil, nil, nil), 0, 0),
+			NewCallSequenceElement(nil, NewCallMessage(sender, &recipient, 0, big.NewInt(1), 100_000, nil, nil, nil, nil), 0, 0),
+		}
+		fetchElementFunc := func(currentIndex int) (*CallSequenceElement, error) {
+			if currentIndex >= len(callSequence) {
+				return nil, nil
+			}
+			callSequence[currentIndex].Call.FillFromTestChainProperties(testChain)
+			return callSequence[currentIndex], nil
+		}
+
+		// Execute the sequence, verifying the gas burning call was aborted rather than executed to completion.
+		start := time.Now()
+		executedSequence, err := ExecuteCallSequenceIteratively(testChain, fetchElementFunc, nil)
+		assert.NoError(t, err)
+		assert.Less(t, time.Since(start), 5*time.Second)
+		assert.Nil(t, callSequence[1].ChainReference)
+
+		// The aborted call should not have affected the chain state, so only the transfers were applied.
+		expectedTransfers := 2
+		if stopSequence {
+			expectedTransfers = 1
+		}
+		assert.Len(t, executedSequence, expectedTransfers)
+		assert.EqualValues(t, expectedTransfers, testChain.State().GetNonce(sender))
+		assert.EqualValues(t, expectedTransfers, testChain.State().GetBalance(recipient).Uint64())
+		for _, element := range executedSequence {
+			assert.EqualValues(t, types.ReceiptStatusSuccessful, element.ChainReference.MessageResults().Receipt.Status)
+		}
+	}
+}
+
+// TestExecuteCallSequenceInsufficientFunds tests that calls which are rejected because their sender cannot afford them
+// are skipped without affecting the chain state, and that clamping a call's value makes it affordable.
+func TestExecuteCallSequenceInsufficientFunds(t *testing.T) {
+	// Create a test chain with a sender which can afford a limited amount of value.
+	genesisAlloc := types.GenesisAlloc{
+		testchainutils.FundedSender: types.Account{Balance: big.NewInt(1_000_000_000)},
+	}
+	testChain, sender := testchainutils.NewFundedTestChainWithAlloc(t, genesisAlloc, nil)
+
+	// Create a call sequence where the second call sends more than the sender's balance.
+	recipient := common.HexToAddress("0x20000")
+	callSequence := CallSequence{
+		NewCallSequenceElement(nil, NewCallMessage(sender, &recipient, 0, big.NewInt(1), 100_000, nil, nil, nil, nil), 0, 0),
+		NewCallSequenceElement(nil, NewCallMessage(sender, &recipient, 0, big.NewInt(2_000_000_000), 100_000, nil, nil, nil, nil), 0, 0),
+		NewCallSequenceElement(nil, NewCallMessage(sender, &recipient, 0, big.NewInt(1), 100_000, nil, nil, nil, nil), 0, 0),
+	}
+	fetchElementFunc := func(currentIndex int) (*CallSequenceElement, error) {
+		if currentIndex >= len(callSequence) {
+			return nil, nil
+		}
+		callSequence[currentIndex].Call.FillFromTestChainProperties(testChain)
+		return callSequence[currentIndex], nil
+	}
+
+	// The unaffordable call should be skipped, while the others are executed.
+	executedSequence, err := ExecuteCallSequenceIteratively(testChain, fetchElementFunc, nil)
+	assert.NoError(t, err)
+	assert.Len(t, executedSequence, 2)
+	assert.Nil(t, callSequence[1].ChainReference)
+	assert.EqualValues(t, 2, testChain.State().GetNonce(sender))
+	assert.EqualValues(t, 2, testChain.State().GetBalance(recipient).Uint64())
+
+	// Clamping the value of the unaffordable call should limit it to the sender's spendable balance, and to the
+	// maximum value provided.
+	msg := callSequence[1].Call
+	msg.FillFromTestChainProperties(testChain)
+	msg.ClampValue(testChain, big.NewInt(500))
+	assert.EqualValues(t, 500, msg.Value.Uint64())
+	msg.Value = big.NewInt(2_000_000_000)
+	msg.ClampValue(testChain, nil)
+	assert.EqualValues(t, msg.SpendableBalance(testChain), msg.Value)
+	assert.Less(t, msg.Value.Uint64(), uint64(1_000_000_000))
+}
+
+// TestExecuteCallSequenceEmptyBlocks tests that empty blocks requested by a call sequence element are mined before
+// its call, after committing any pending block, and that they are accounted for in the sequence's schedule.
+func TestExecuteCallSequenceEmptyBlocks(t *testing.T) {
+	// Create a test chain with a funded sender.
+	testChain, sender := testchainutils.NewFundedTestChain(t)
+	startBlockNumber := testChain.Head().Header.Number.Uint64()
+	startBlockTimestamp := testChain.Head().Header.Time
+
+	// Create a call sequence which mines empty blocks before its first call, and between two calls which would
+	// otherwise be included in the same block.
+	recipient := common.HexToAddress("0x20000")
+	callSequence := CallSequence{
+		NewCallSequenceElement(nil, NewCallMessage(sender, &recipient, 0, big.NewInt(1), 100_000, nil, nil, nil, nil), 0, 0),
+		NewCallSequenceElement(nil, NewCallMessage(sender, &recipient, 0, big.NewInt(1), 100_000, nil, nil, nil, nil), 0, 0),
+	}
+	callSequence[0].EmptyBlocks = 2
+	callSequence[1].EmptyBlocks = 5
+	fetchElementFunc := func(currentIndex int) (*CallSequenceElement, error) {
+		if currentIndex >= len(callSequence) {
+			return nil, nil
+		}
+		callSequence[currentIndex].Call.FillFromTestChainProperties(testChain)
+		return callSequence[currentIndex], nil
+	}
+	executedSequence, err := ExecuteCallSequenceIteratively(testChain, fetchElementFunc, nil)
+	assert.NoError(t, err)
+	assert.Len(t, executedSequence, 2)
+
+	// Each call is included in a block after its empty blocks, and every intermediate block is committed.
+	assert.EqualValues(t, startBlockNumber+3, callSequence[0].ExecutedBlock.BlockNumber)
+	assert.EqualValues(t, startBlockTimestamp+2*EmptyBlockTimestampDelay+1, callSequence[0].ExecutedBlock.BlockTimestamp)
+	assert.EqualValues(t, startBlockNumber+9, callSequence[1].ExecutedBlock.BlockNumber)
+	assert.EqualValues(t, callSequence[0].ExecutedBlock.BlockTimestamp+5*EmptyBlockTimestampDelay+1, callSequence[1].ExecutedBlock.BlockTimestamp)
+	for blockNumber := startBlockNumber; blockNumber <= startBlockNumber+9; blockNumber++ {
+		_, err = testChain.BlockFromNumber(blockNumber)
+		assert.NoError(t, err)
+	}
+
+	// The schedule should start from the block before the first empty block, and describe the empty blocks.
+	schedule := callSequence.Schedule()
+	assert.NotNil(t, schedule)
+	assert.EqualValues(t, startBlockNumber, schedule.StartBlockNumber)
+	assert.EqualValues(t, startBlockTimestamp, schedule.StartBlockTimestamp)
+	assert.EqualValues(t, 5, schedule.Elements[1].EmptyBlocks)
+	assert.Contains(t, schedule.String(), "emptyBlocks=5")
+}
+
+// TestExecuteCallSequenceWithExecutionTracerLimits tests that execution traces attached to call sequence elements are
+// bounded by the provided trace limits, and that when every element is traced, each trace is released as soon as its
+// element executes, retaining only its rendered output.
+func TestExecuteCallSequenceWithExecutionTracerLimits(t *testing.T) {
+	for _, verboseTracing := range []bool{false, true} {
+		// Create a test chain with a funded sender and a contract which emits an event and calls itself with all
+		// available gas, recursing until it runs out of gas.
+		// PUSH1 0x00, PUSH1 0x00, LOG0, PUSH1 0x00 (x5), ADDRESS, GAS, CALL, STOP
+		recursiveContract := common.HexToAddress("0x30000")
+		genesisAlloc := types.GenesisAlloc{
+			recursiveContract: types.Account{Code: common.FromHex("60006000a060006000600060006000305af100")},
+		}
+		testChain, sender := testchainutils.NewFundedTestChainWithAlloc(t, genesisAlloc, nil)
+
+		// Create a call sequence which calls the recursive contract multiple times, and execute it with tracing.
+		callSequence := make(CallSequence, 3)
+		for i := 0; i < len(callSequence); i++ {
+			callSequence[i] = NewCallSequenceElement(nil, NewCallMessage(sender, &recursiveContract, 0, big.NewInt(0), 1_000_000, nil, nil, nil, nil), 0, 0)
+			callSequence[i].Call.FillFromTestChainProperties(testChain)
+			callSequence[i].Call.Nonce = uint64(i)
+		}
+		executedSequence, err := ExecuteCallSequenceWithExecutionTracer(testChain, nil, callSequence, verboseTracing, executiontracer.TraceLimits{MaxDepth: 3}, nil)
+		assert.NoError(t, err)
+		assert.Len(t, executedSequence, len(callSequence))
+
+		// Only the last element should be traced, unless verbose tracing is enabled. Every trace should be truncated
+		// at the depth limit, and traces attached when verbose tracing should have been released.
+		for i, element := range callSequence {
+			if !verboseTracing && i < len(callSequence)-1 {
+				assert.Nil(t, element.ExecutionTrace)
+				continue
+			}
+			if assert.NotNil(t, element.ExecutionTrace) {
+				assert.Equal(t, verboseTracing, element.ExecutionTrace.TopLevelCallFrame == nil)
+				traceMessage := element.ExecutionTrace.String()
+				assert.Contains(t, traceMessage, "[truncated] 1 operation(s) omitted due to trace limits")
+				assert.Equal(t, 4, strings.Count(traceMessage, "[call]"))
+			}
+		}
+	}
+}
+
+// TestExecuteCallSequenceWithExecutionTracerArtifact tests that when every element of a call sequence is traced with
+// a trace artifact, each rendered trace is written to the artifact as its element executes, rather than being held in
+// memory, while it can still be logged.
+func TestExecuteCallSequenceWithExecutionTracerArtifact(t *testing.T) {
+	// executeTracedSequence executes a long call sequence against a contract which emits an event and calls itself
+	// until it runs out of gas on a new test chain, tracing every element, and returns the executed sequence. The
+	// chain is created in a subtest, so it is closed and released before the sequence is returned.
+	// PUSH1 0x00, PUSH1 0x00, LOG0, PUSH1 0x00 (x5), ADDRESS, GAS, CALL, STOP
+	executeTracedSequence := func(traceArtifact *executiontracer.TraceArtifact) CallSequence {
+		callSequence := make(CallSequence, 20)
+		t.Run("execute", func(t *testing.T) {
+			recursiveContract := common.HexToAddress("0x30000")
+			genesisAlloc := types.GenesisAlloc{
+				recursiveContract: types.Account{Code: common.FromHex("60006000a060006000600060006000305af100")},
+			}
+			testChain, sender := testchainutils.NewFundedTestChainWithAlloc(t, genesisAlloc, nil)
+			for i := 0; i < len(callSequence); i++ {
+				callSequence[i] = NewCallSequenceElement(nil, NewCallMessage(sender, &recursiveContract, uint64(i), big.NewInt(0), 1_000_000, nil, nil, nil, nil), 1, 1)
+				callSequence[i].Call.FillFromTestChainProperties(testChain)
+				callSequence[i].Call.Nonce = uint64(i)
+			}
+
+			_, err := ExecuteCallSequenceWithExecutionTracer(testChain, nil, callSequence, true, executiontracer.TraceLimits{MaxOperations: 100}, traceArtifact)
+			assert.NoError(t, err)
+		})
+
+		// Drop the references to the chain, so the traces are the only data retained differently depending on
+		// whether an artifact is used.
+		for _, element := range callSequence {
+			element.ChainReference = nil
+		}
+		return callSequence
+	}
+
+	// measureRetainedHeap returns the call sequence returned by the provided function, and the amount of heap memory
+	// which remains allocated after it returned.
+	measureRetainedHeap := func(execute func() CallSequence) (CallSequence, uint64) {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		callSequence := execute()
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		if after.HeapAlloc < before.HeapAlloc {
+			return callSequence, 0
+		}
+		return callSequence, after.HeapAlloc - before.HeapAlloc
+	}
+
+	// Trace our sequence once to warm up any caches, then trace it with the rendered traces held in memory.
+	executeTracedSequence(nil)
+	inMemorySequence, inMemoryRetained := measureRetainedHeap(func() CallSequence { return executeTracedSequence(nil) })
+	inMemoryTraces := make([]string, len(inMemorySequence))
+	for i, element := range inMemorySequence {
+		inMemoryTraces[i] = element.ExecutionTrace.String()
+	}
+
+	// Trace our sequence again, writing the rendered traces to an artifact.
+	traceArtifact, err := executiontracer.NewTraceArtifact(filepath.Join(t.TempDir(), "traces", "trace.log"))
+	assert.NoError(t, err)
+	artifactSequence, artifactRetained := measureRetainedHeap(func() CallSequence { return executeTracedSequence(traceArtifact) })
+	assert.NoError(t, traceArtifact.Close())
+
+	// Far less memory should have been retained with the artifact, while the same traces are logged, and they should
+	// have been written to the artifact, marking the operations omitted due to trace limits.
+	assert.Less(t, artifactRetained*4, inMemoryRetained)
+	artifactData, err := os.ReadFile(traceArtifact.Path())
+	assert.NoError(t, err)
+	for i, element := range artifactSequence {
+		assert.Nil(t, element.ExecutionTrace.TopLevelCallFrame)
+		trace := element.ExecutionTrace.String()
+		assert.EqualValues(t, inMemoryTraces[i], trace)
+		assert.Contains(t, trace, "[truncated]")
+		assert.Contains(t, string(artifactData), trace)
+	}
+	runtime.KeepAlive(inMemorySequence)
+}
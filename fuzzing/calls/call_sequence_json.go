@@ -0,0 +1,428 @@
+package calls
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/crytic/medusa/fuzzing/valuegeneration"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	coreTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// CallSequenceSchemaVersion describes the version of the JSON schema call sequences are written with, as defined by
+	// CallSequenceJSON. It is incremented whenever the schema changes in a way which requires consumers to be updated.
+	CallSequenceSchemaVersion = 1
+
+	// CallSequenceLegacySchemaVersion describes the version of call sequences written before the JSON schema was
+	// versioned, as a JSON array of call sequence elements which each wrap a serialized CallMessage. Call sequences in
+	// this format are still read, but are never written.
+	CallSequenceLegacySchemaVersion = 0
+)
+
+// CallSequenceJSON describes the versioned JSON schema of a CallSequence. Call sequences are read from and written in
+// this schema by CallSequence.UnmarshalJSON and CallSequence.MarshalJSON.
+type CallSequenceJSON struct {
+	// Version describes the version of the schema the call sequence was written with.
+	Version int `json:"version"`
+
+	// Calls describes each call in the call sequence, in the order they are executed.
+	Calls []*CallSequenceElementJSON `json:"calls"`
+}
+
+// CallSequenceElementJSON describes the versioned JSON schema of a CallSequenceElement.
+type CallSequenceElementJSON struct {
+	// Sender describes the address the call is sent from.
+	Sender common.Address `json:"sender"`
+
+	// To describes the address of the contract called, or nil if the call deploys a contract.
+	To *common.Address `json:"to"`
+
+	// Nonce describes the nonce of the sender.
+	Nonce uint64 `json:"nonce"`
+
+	// Value describes the amount of wei sent with the call.
+	Value *hexutil.Big `json:"value"`
+
+	// GasLimit describes the maximum amount of gas the call may use.
+	GasLimit uint64 `json:"gasLimit"`
+
+	// GasPrice describes the price paid for each unit of gas used.
+	GasPrice *hexutil.Big `json:"gasPrice"`
+
+	// GasFeeCap describes the EIP-1559 fee cap of the call, or nil if the gas price oracle should be relied on.
+	GasFeeCap *hexutil.Big `json:"gasFeeCap"`
+
+	// GasTipCap describes the EIP-1559 tip cap of the call, or nil if the gas price oracle should be relied on.
+	GasTipCap *hexutil.Big `json:"gasTipCap"`
+
+	// Selector describes the 4-byte selector of the method called, if the call data is produced from ABI values. When
+	// reading a call sequence, either it or the MethodSignature must be provided for such calls.
+	Selector hexutil.Bytes `json:"selector,omitempty"`
+
+	// MethodSignature describes the signature of the method called (e.g. "transfer(address,uint256)"), if the call
+	// data is produced from ABI values. When reading a call sequence, the method is resolved by its signature rather
+	// than its Selector, if both are provided.
+	MethodSignature string `json:"methodSignature,omitempty"`
+
+	// Args describes the arguments the method is called with, if the call data is produced from ABI values.
+	Args []CallArgumentJSON `json:"args,omitempty"`
+
+	// Data describes the call data sent with the call. If the call data is produced from ABI values, it is re-packed
+	// from them only when they are resolved or mutated, so it describes the call data last packed from them, and is
+	// not kept consistent with Args by the execution of the call.
+	Data hexutil.Bytes `json:"data,omitempty"`
+
+	// AccessList describes the storage slots and contracts the call declares it will access.
+	AccessList coreTypes.AccessList `json:"accessList,omitempty"`
+
+	// SkipAccountChecks describes whether the nonce and EOA checks of the sender are skipped.
+	SkipAccountChecks bool `json:"skipAccountChecks,omitempty"`
+
+	// BlockNumberDelay describes how much the block number should advance when executing this call, compared to the
+	// last executed call.
+	BlockNumberDelay uint64 `json:"blockNumberDelay"`
+
+	// BlockTimestampDelay describes how much the block timestamp should advance when executing this call, compared to
+	// the last executed call.
+	BlockTimestampDelay uint64 `json:"blockTimestampDelay"`
+
+	// EmptyBlocks describes how many empty blocks are mined before executing this call.
+	EmptyBlocks uint64 `json:"emptyBlocks,omitempty"`
+
+	// Prevrandao describes the prevrandao value of the block created to include this call, or nil if the chain's
+	// value should be used.
+	Prevrandao *common.Hash `json:"prevrandao,omitempty"`
+
+	// Metadata describes optional information about the call, or nil if it has none.
+	Metadata *CallSequenceElementMetadataJSON `json:"metadata,omitempty"`
+}
+
+// CallArgumentJSON describes the versioned JSON schema of an ABI argument value a method is called with.
+type CallArgumentJSON struct {
+	// Name describes the name of the argument, if it is named in the method's ABI.
+	Name string `json:"name,omitempty"`
+
+	// Type describes the canonical ABI type of the argument (e.g. "uint256", "(address,bytes)[]"). It is always written,
+	// but may be omitted when reading a call sequence, in which case it is not checked against the method's ABI.
+	Type string `json:"type,omitempty"`
+
+	// Value describes the value of the argument. Integers are written as decimal strings, addresses as hex strings,
+	// bytes as hex strings without a prefix, arrays as JSON arrays, and structs as JSON objects keyed by field name.
+	Value any `json:"value"`
+}
+
+// CallSequenceElementMetadataJSON describes the versioned JSON schema of the optional information recorded for a
+// CallSequenceElement.
+type CallSequenceElementMetadataJSON struct {
+	// ExecutedBlock describes the block the call was last executed in, if it was executed.
+	ExecutedBlock *CallSequenceElementExecutedBlock `json:"executedBlock,omitempty"`
+
+	// CalldataProbe describes the malformation applied to the call data after it was packed from its ABI values, if
+	// any.
+	CalldataProbe *CalldataProbe `json:"calldataProbe,omitempty"`
+
+	// OutputBinding describes an argument of the call which is bound to a return value of the prior call, if any.
+	OutputBinding *CallOutputBinding `json:"outputBinding,omitempty"`
+
+	// Setup indicates whether the call is a setup call which shrinking should never remove.
+	Setup bool `json:"setup,omitempty"`
+
+	// Frozen indicates whether the call should be left unchanged by shrinking.
+	Frozen bool `json:"frozen,omitempty"`
+}
+
+// legacyCallSequenceElement is used as an internal type to read a CallSequenceElement written in the legacy schema,
+// without invoking the custom JSON unmarshalling of CallSequenceElement.
+type legacyCallSequenceElement CallSequenceElement
+
+// DetectCallSequenceSchemaVersion returns the version of the JSON schema the provided serialized call sequence was
+// written with, which is CallSequenceLegacySchemaVersion if it was written before the schema was versioned.
+// Returns an error if the data is not a serialized call sequence.
+func DetectCallSequenceSchemaVersion(b []byte) (int, error) {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '[' {
+		return CallSequenceLegacySchemaVersion, nil
+	}
+	var versioned struct {
+		Version *int `json:"version"`
+	}
+	if err := json.Unmarshal(b, &versioned); err != nil {
+		return 0, err
+	}
+	if versioned.Version == nil {
+		return 0, errors.New("call sequence does not describe the version of its schema")
+	}
+	return *versioned.Version, nil
+}
+
+// ToJSONSchema converts the CallSequence into its versioned JSON schema.
+// Returns the converted call sequence, or an error if an element could not be converted.
+func (cs CallSequence) ToJSONSchema() (*CallSequenceJSON, error) {
+	schema := &CallSequenceJSON{
+		Version: CallSequenceSchemaVersion,
+		Calls:   make([]*CallSequenceElementJSON, len(cs)),
+	}
+	for i, element := range cs {
+		if element == nil {
+			continue
+		}
+		var err error
+		schema.Calls[i], err = element.ToJSONSchema()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert call %d of the call sequence: %w", i+1, err)
+		}
+	}
+	return schema, nil
+}
+
+// NewCallSequenceFromJSONSchema converts a call sequence in its versioned JSON schema into a CallSequence. As with any
+// deserialized call sequence, the ABI values of each element must be resolved (see CallMessageDataAbiValues.Resolve)
+// before the call sequence is used.
+// Returns the converted call sequence, or an error if the schema version is not supported.
+func NewCallSequenceFromJSONSchema(schema *CallSequenceJSON) (CallSequence, error) {
+	if schema.Version <= CallSequenceLegacySchemaVersion || schema.Version > CallSequenceSchemaVersion {
+		return nil, fmt.Errorf("call sequence schema version %d is not supported, expected a version up to %d", schema.Version, CallSequenceSchemaVersion)
+	}
+	cs := make(CallSequence, len(schema.Calls))
+	for i, element := range schema.Calls {
+		if element != nil {
+			cs[i] = NewCallSequenceElementFromJSONSchema(element)
+		}
+	}
+	return cs, nil
+}
+
+// MarshalJSON provides custom JSON marshalling for the CallSequence, always writing the versioned JSON schema
+// described by CallSequenceJSON.
+// Returns the JSON marshalled data, or an error if one occurs.
+func (cs CallSequence) MarshalJSON() ([]byte, error) {
+	if cs == nil {
+		return []byte("null"), nil
+	}
+	schema, err := cs.ToJSONSchema()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(schema)
+}
+
+// UnmarshalJSON provides custom JSON unmarshalling for the CallSequence, reading either the versioned JSON schema
+// described by CallSequenceJSON, or the legacy schema call sequences were written with before it.
+// Returns an error if one occurs.
+func (cs *CallSequence) UnmarshalJSON(b []byte) error {
+	// Determine the version of the schema the call sequence was written with.
+	if string(bytes.TrimSpace(b)) == "null" {
+		*cs = nil
+		return nil
+	}
+	version, err := DetectCallSequenceSchemaVersion(b)
+	if err != nil {
+		return err
+	}
+
+	// Legacy call sequences are an array of elements, which are each read in the legacy schema.
+	if version == CallSequenceLegacySchemaVersion {
+		var elements []*CallSequenceElement
+		if err = json.Unmarshal(b, &elements); err != nil {
+			return err
+		}
+		*cs = elements
+		return nil
+	}
+
+	// Otherwise, read the versioned schema and convert it.
+	var schema CallSequenceJSON
+	if err = json.Unmarshal(b, &schema); err != nil {
+		return err
+	}
+	*cs, err = NewCallSequenceFromJSONSchema(&schema)
+	return err
+}
+
+// ToJSONSchema converts the CallSequenceElement into its versioned JSON schema. If the element's ABI values were not
+// resolved, they are converted as they were read, which requires them to identify their method by signature or
+// selector.
+// Returns the converted element, or an error if its ABI values could not be encoded.
+func (cse *CallSequenceElement) ToJSONSchema() (*CallSequenceElementJSON, error) {
+	if cse.Call == nil {
+		return nil, errors.New("call sequence element does not have a call")
+	}
+	call := cse.Call
+	schema := &CallSequenceElementJSON{
+		Sender:              call.From,
+		To:                  call.To,
+		Nonce:               call.Nonce,
+		Value:               (*hexutil.Big)(call.Value),
+		GasLimit:            call.GasLimit,
+		GasPrice:            (*hexutil.Big)(call.GasPrice),
+		GasFeeCap:           (*hexutil.Big)(call.GasFeeCap),
+		GasTipCap:           (*hexutil.Big)(call.GasTipCap),
+		Data:                call.Data,
+		AccessList:          call.AccessList,
+		SkipAccountChecks:   call.SkipAccountChecks,
+		BlockNumberDelay:    cse.BlockNumberDelay,
+		BlockTimestampDelay: cse.BlockTimestampDelay,
+		EmptyBlocks:         cse.EmptyBlocks,
+		Prevrandao:          cse.Prevrandao,
+	}
+
+	// Encode the method and arguments the call data is produced from, if any.
+	if abiValues := call.DataAbiValues; abiValues != nil {
+		var err error
+		schema.MethodSignature, schema.Selector, schema.Args, err = abiValues.toJSONSchema()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Only write metadata if the element has any.
+	metadata := CallSequenceElementMetadataJSON{
+		ExecutedBlock: cse.ExecutedBlock,
+		CalldataProbe: cse.CalldataProbe,
+		OutputBinding: cse.OutputBinding,
+		Setup:         cse.Setup,
+		Frozen:        cse.Frozen,
+	}
+	if metadata != (CallSequenceElementMetadataJSON{}) {
+		schema.Metadata = &metadata
+	}
+	return schema, nil
+}
+
+// NewCallSequenceElementFromJSONSchema converts a call sequence element in its versioned JSON schema into a
+// CallSequenceElement. If the call data is produced from ABI values, they must be resolved (see
+// CallMessageDataAbiValues.Resolve) before the element is used.
+func NewCallSequenceElementFromJSONSchema(schema *CallSequenceElementJSON) *CallSequenceElement {
+	call := &CallMessage{
+		From:              schema.Sender,
+		To:                schema.To,
+		Nonce:             schema.Nonce,
+		Value:             (*big.Int)(schema.Value),
+		GasLimit:          schema.GasLimit,
+		GasPrice:          (*big.Int)(schema.GasPrice),
+		GasFeeCap:         (*big.Int)(schema.GasFeeCap),
+		GasTipCap:         (*big.Int)(schema.GasTipCap),
+		Data:              schema.Data,
+		AccessList:        schema.AccessList,
+		SkipAccountChecks: schema.SkipAccountChecks,
+	}
+
+	// If the call identifies a method, its call data is produced from ABI values, which are resolved later.
+	if schema.MethodSignature != "" || len(schema.Selector) > 0 {
+		abiValues := &CallMessageDataAbiValues{
+			methodSignature:    schema.MethodSignature,
+			methodID:           schema.Selector,
+			encodedInputValues: make([]any, len(schema.Args)),
+			encodedInputTypes:  make([]string, len(schema.Args)),
+			encodedInputNames:  make([]string, len(schema.Args)),
+		}
+		for i, arg := range schema.Args {
+			abiValues.encodedInputValues[i] = arg.Value
+			abiValues.encodedInputTypes[i] = arg.Type
+			abiValues.encodedInputNames[i] = arg.Name
+		}
+		call.DataAbiValues = abiValues
+	}
+
+	element := NewCallSequenceElement(nil, call, schema.BlockNumberDelay, schema.BlockTimestampDelay)
+	element.EmptyBlocks = schema.EmptyBlocks
+	element.Prevrandao = schema.Prevrandao
+	if schema.Metadata != nil {
+		element.ExecutedBlock = schema.Metadata.ExecutedBlock
+		element.CalldataProbe = schema.Metadata.CalldataProbe
+		element.OutputBinding = schema.Metadata.OutputBinding
+		element.Setup = schema.Metadata.Setup
+		element.Frozen = schema.Metadata.Frozen
+	}
+	return element
+}
+
+// MarshalJSON provides custom JSON marshalling for the CallSequenceElement, always writing the versioned JSON schema
+// described by CallSequenceElementJSON.
+// Returns the JSON marshalled data, or an error if one occurs.
+func (cse *CallSequenceElement) MarshalJSON() ([]byte, error) {
+	schema, err := cse.ToJSONSchema()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(schema)
+}
+
+// UnmarshalJSON provides custom JSON unmarshalling for the CallSequenceElement, reading either the versioned JSON
+// schema described by CallSequenceElementJSON, or the legacy schema, which wraps a serialized CallMessage.
+// Returns an error if one occurs.
+func (cse *CallSequenceElement) UnmarshalJSON(b []byte) error {
+	// Legacy elements are identified by the serialized CallMessage they wrap.
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	if _, isLegacy := fields["call"]; isLegacy {
+		var legacy legacyCallSequenceElement
+		if err := json.Unmarshal(b, &legacy); err != nil {
+			return err
+		}
+		*cse = CallSequenceElement(legacy)
+		return nil
+	}
+
+	// Otherwise, read the versioned schema and convert it.
+	var schema CallSequenceElementJSON
+	if err := json.Unmarshal(b, &schema); err != nil {
+		return err
+	}
+	*cse = *NewCallSequenceElementFromJSONSchema(&schema)
+	return nil
+}
+
+// toJSONSchema encodes the method and arguments the call data is produced from in the versioned JSON schema of a
+// CallSequenceElement. If the values were not resolved, they are encoded as they were read.
+// Returns the method signature, selector, and arguments, or an error if the arguments could not be encoded, or the
+// method is only identified by name.
+func (d *CallMessageDataAbiValues) toJSONSchema() (string, hexutil.Bytes, []CallArgumentJSON, error) {
+	// If our values were not resolved, encode them as they were read.
+	if d.Method == nil {
+		if d.methodSignature == "" && len(d.methodID) == 0 {
+			return "", nil, nil, fmt.Errorf("ABI call data JSON marshaling failed, method '%v' must be resolved to determine its signature", d.methodName)
+		}
+		selector := d.methodID
+		if len(selector) == 0 {
+			selector = crypto.Keccak256([]byte(d.methodSignature))[:4]
+		}
+		args := make([]CallArgumentJSON, len(d.encodedInputValues))
+		for i, value := range d.encodedInputValues {
+			args[i].Value = value
+			if i < len(d.encodedInputTypes) {
+				args[i].Type = d.encodedInputTypes[i]
+			}
+			if i < len(d.encodedInputNames) {
+				args[i].Name = d.encodedInputNames[i]
+			}
+		}
+		return d.methodSignature, selector, args, nil
+	}
+
+	// Otherwise encode our input values, tagging them with the types of the method inputs.
+	if len(d.Method.Inputs) != len(d.InputValues) {
+		return "", nil, nil, fmt.Errorf("ABI call data JSON marshaling failed, method definition describes %d input arguments, but %d were provided", len(d.Method.Inputs), len(d.InputValues))
+	}
+	encodedValues, err := valuegeneration.EncodeJSONArgumentsToSlice(d.Method.Inputs, d.InputValues)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	args := make([]CallArgumentJSON, len(encodedValues))
+	for i, value := range encodedValues {
+		args[i] = CallArgumentJSON{
+			Name:  d.Method.Inputs[i].Name,
+			Type:  d.Method.Inputs[i].Type.String(),
+			Value: value,
+		}
+	}
+	return d.Method.Sig, d.Method.ID, args, nil
+}
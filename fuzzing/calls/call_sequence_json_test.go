@@ -0,0 +1,291 @@
+package calls
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/valuegeneration"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// callSequenceJSONTestAbi describes a contract whose methods take arguments of every kind of ABI type, including
+// structs, nested arrays, bytes, negative integers, and addresses.
+const callSequenceJSONTestAbi = `[
+	{"type": "function", "name": "deposit", "stateMutability": "payable", "outputs": [], "inputs": [
+		{"name": "order", "type": "tuple", "components": [
+			{"name": "amount", "type": "int256"},
+			{"name": "owner", "type": "address"},
+			{"name": "payload", "type": "bytes"},
+			{"name": "ids", "type": "uint8[2][]"}
+		]},
+		{"name": "grid", "type": "int16[][2]"},
+		{"name": "data", "type": "bytes"},
+		{"name": "id", "type": "bytes32"},
+		{"name": "delta", "type": "int64"},
+		{"name": "recipients", "type": "address[]"},
+		{"name": "enabled", "type": "bool"},
+		{"name": "memo", "type": "string"}
+	]},
+	{"type": "function", "name": "poke", "stateMutability": "nonpayable", "inputs": [], "outputs": []}
+]`
+
+// callSequenceJSONTestArgs describes the arguments of the deposit method of callSequenceJSONTestAbi, as they are
+// encoded in JSON.
+const callSequenceJSONTestArgs = `[
+	{"amount": "-57896044618658097711785492504343953926634992332820282019728792003956564819968", "owner": "0x00000000000000000000000000000000DeaDBeef", "payload": "00ff10", "ids": [["1", "255"], ["0", "7"]]},
+	[["-32768", "32767", "-1"], []],
+	"",
+	"0102030000000000000000000000000000000000000000000000000000000000",
+	"-9223372036854775808",
+	["0x0000000000000000000000000000000000010000", "0x0000000000000000000000000000000000020000"],
+	true,
+	"hello | world"
+]`
+
+// newCallSequenceJSONTestSequence creates a call sequence which calls each method of the provided contract ABI, sends
+// raw call data, and deploys a contract, with every optional element field set on at least one element.
+func newCallSequenceJSONTestSequence(t *testing.T) (CallSequence, abi.ABI) {
+	contractAbi, err := abi.JSON(strings.NewReader(callSequenceJSONTestAbi))
+	assert.NoError(t, err)
+	deposit, poke := contractAbi.Methods["deposit"], contractAbi.Methods["poke"]
+
+	// Decode our deposit arguments from JSON, so they have the types the ABI packs.
+	encodedArgs, err := valuegeneration.DecodeJSONArgumentsFromSlice(deposit.Inputs, mustUnmarshalJSONSlice(t, callSequenceJSONTestArgs), nil)
+	assert.NoError(t, err)
+
+	sender, to := common.HexToAddress("0x10000"), common.HexToAddress("0x20000")
+	depositCall := NewCallSequenceElement(nil, NewCallMessageWithAbiValueData(sender, &to, 3, big.NewInt(1000), 12_500_000, big.NewInt(1), big.NewInt(2), big.NewInt(3), &CallMessageDataAbiValues{
+		Method:      &deposit,
+		InputValues: encodedArgs,
+	}), 1, 12)
+	depositCall.Setup = true
+	depositCall.ExecutedBlock = &CallSequenceElementExecutedBlock{BlockNumber: 2, BlockTimestamp: 13, ParentBlockNumber: 1, ParentBlockTimestamp: 1}
+
+	pokeCall := NewCallSequenceElement(nil, NewCallMessageWithAbiValueData(sender, &to, 4, big.NewInt(0), 12_500_000, big.NewInt(1), big.NewInt(0), big.NewInt(0), &CallMessageDataAbiValues{
+		Method:      &poke,
+		InputValues: []any{},
+	}), 0, 0)
+	prevrandao := common.HexToHash("0x1234")
+	pokeCall.EmptyBlocks = 2
+	pokeCall.Prevrandao = &prevrandao
+	pokeCall.Frozen = true
+	pokeCall.CalldataProbe = &CalldataProbe{Kind: CalldataProbeExtended, AppendedData: []byte{0xca, 0xfe}}
+	pokeCall.Call.Data = pokeCall.CalldataProbe.Apply(pokeCall.Call.Data)
+
+	rawCall := NewCallSequenceElement(nil, NewCallMessage(sender, &to, 5, big.NewInt(0), 100_000, big.NewInt(1), big.NewInt(0), big.NewInt(0), []byte{0xde, 0xad, 0xbe, 0xef}), 3, 30)
+	deployment := NewCallSequenceElement(nil, NewCallMessage(sender, nil, 6, big.NewInt(0), 1_000_000, big.NewInt(1), big.NewInt(0), big.NewInt(0), []byte{0x60, 0x80, 0x60, 0x40}), 0, 0)
+	return CallSequence{depositCall, pokeCall, rawCall, deployment}, contractAbi
+}
+
+// mustUnmarshalJSONSlice unmarshals the provided JSON array into a slice of generic JSON values.
+func mustUnmarshalJSONSlice(t *testing.T, s string) []any {
+	var values []any
+	assert.NoError(t, json.Unmarshal([]byte(s), &values))
+	return values
+}
+
+// resolveCallSequence resolves the ABI values of each element of the provided deserialized call sequence using the
+// provided contract ABI.
+func resolveCallSequence(t *testing.T, cs CallSequence, contractAbi abi.ABI) {
+	for _, element := range cs {
+		if element.Call.DataAbiValues != nil {
+			assert.NoError(t, element.Call.DataAbiValues.Resolve(contractAbi))
+		}
+	}
+}
+
+// assertCallSequencesEqual asserts that the provided call sequences describe the same calls, executed with the same
+// schedule and metadata. ABI values are compared by the call data they pack into.
+func assertCallSequencesEqual(t *testing.T, expected CallSequence, actual CallSequence) {
+	assert.Len(t, actual, len(expected))
+	for i := 0; i < len(expected) && i < len(actual); i++ {
+		e, a := expected[i], actual[i]
+		assert.EqualValues(t, e.Call.From, a.Call.From)
+		assert.EqualValues(t, e.Call.To, a.Call.To)
+		assert.EqualValues(t, e.Call.Nonce, a.Call.Nonce)
+		assert.EqualValues(t, e.Call.GasLimit, a.Call.GasLimit)
+		for _, values := range [][2]*big.Int{
+			{e.Call.Value, a.Call.Value},
+			{e.Call.GasPrice, a.Call.GasPrice},
+			{e.Call.GasFeeCap, a.Call.GasFeeCap},
+			{e.Call.GasTipCap, a.Call.GasTipCap},
+		} {
+			assert.Zero(t, values[0].Cmp(values[1]), "expected %v, got %v", values[0], values[1])
+		}
+		assert.EqualValues(t, e.Call.Data, a.Call.Data)
+		assert.EqualValues(t, e.Call.SkipAccountChecks, a.Call.SkipAccountChecks)
+		assert.Equal(t, e.Call.DataAbiValues == nil, a.Call.DataAbiValues == nil)
+		if e.Call.DataAbiValues != nil && a.Call.DataAbiValues != nil {
+			assert.EqualValues(t, e.Call.DataAbiValues.Method.Sig, a.Call.DataAbiValues.Method.Sig)
+			expectedData, err := e.Call.DataAbiValues.Pack()
+			assert.NoError(t, err)
+			actualData, err := a.Call.DataAbiValues.Pack()
+			assert.NoError(t, err)
+			assert.EqualValues(t, expectedData, actualData)
+		}
+		assert.EqualValues(t, e.BlockNumberDelay, a.BlockNumberDelay)
+		assert.EqualValues(t, e.BlockTimestampDelay, a.BlockTimestampDelay)
+		assert.EqualValues(t, e.EmptyBlocks, a.EmptyBlocks)
+		assert.EqualValues(t, e.Prevrandao, a.Prevrandao)
+		assert.EqualValues(t, e.ExecutedBlock, a.ExecutedBlock)
+		assert.EqualValues(t, e.CalldataProbe, a.CalldataProbe)
+		assert.EqualValues(t, e.OutputBinding, a.OutputBinding)
+		assert.EqualValues(t, e.Setup, a.Setup)
+		assert.EqualValues(t, e.Frozen, a.Frozen)
+	}
+}
+
+// TestCallSequenceJSONRoundTrip tests that call sequences are written in the versioned schema, with every argument
+// tagged with its ABI type, and are read back unchanged, including structs, nested arrays, bytes, negative integers,
+// and addresses.
+func TestCallSequenceJSONRoundTrip(t *testing.T) {
+	cs, contractAbi := newCallSequenceJSONTestSequence(t)
+	cs[2].OutputBinding = &CallOutputBinding{ArgumentIndex: 0, OutputIndex: 1}
+	cs[3].Call.SkipAccountChecks = true
+
+	// The call sequence should be written in the current version of the schema.
+	b, err := json.Marshal(cs)
+	assert.NoError(t, err)
+	version, err := DetectCallSequenceSchemaVersion(b)
+	assert.NoError(t, err)
+	assert.EqualValues(t, CallSequenceSchemaVersion, version)
+
+	// Each argument should be written with its name and ABI type, identifying the method by signature and selector.
+	var schema CallSequenceJSON
+	assert.NoError(t, json.Unmarshal(b, &schema))
+	assert.Len(t, schema.Calls, len(cs))
+	deposit := schema.Calls[0]
+	assert.EqualValues(t, contractAbi.Methods["deposit"].Sig, deposit.MethodSignature)
+	assert.EqualValues(t, contractAbi.Methods["deposit"].ID, deposit.Selector)
+	expectedArgs := []struct{ name, typ string }{
+		{"order", "(int256,address,bytes,uint8[2][])"},
+		{"grid", "int16[][2]"},
+		{"data", "bytes"},
+		{"id", "bytes32"},
+		{"delta", "int64"},
+		{"recipients", "address[]"},
+		{"enabled", "bool"},
+		{"memo", "string"},
+	}
+	assert.Len(t, deposit.Args, len(expectedArgs))
+	for i, expectedArg := range expectedArgs {
+		assert.EqualValues(t, expectedArg.name, deposit.Args[i].Name)
+		assert.EqualValues(t, expectedArg.typ, deposit.Args[i].Type)
+	}
+	assert.EqualValues(t, mustUnmarshalJSONSlice(t, callSequenceJSONTestArgs)[4], deposit.Args[4].Value)
+	assert.True(t, deposit.Metadata.Setup)
+	assert.Empty(t, schema.Calls[1].Args)
+	assert.True(t, schema.Calls[1].Metadata.Frozen)
+	assert.Empty(t, schema.Calls[2].MethodSignature)
+	assert.Empty(t, schema.Calls[2].Selector)
+	assert.Nil(t, schema.Calls[3].To)
+
+	// Reading the call sequence back should produce the same calls, and writing it again the same data.
+	var decoded CallSequence
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	resolveCallSequence(t, decoded, contractAbi)
+	assertCallSequencesEqual(t, cs, decoded)
+	reencoded, err := json.Marshal(decoded)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(b), string(reencoded))
+
+	// Call sequences which were read but not yet resolved should be written unchanged.
+	var unresolved CallSequence
+	assert.NoError(t, json.Unmarshal(b, &unresolved))
+	reencoded, err = json.Marshal(unresolved)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(b), string(reencoded))
+
+	// Individual elements should round trip as well, as worker processes report them one at a time.
+	for i, element := range cs {
+		b, err = json.Marshal(element)
+		assert.NoError(t, err)
+		var decodedElement CallSequenceElement
+		assert.NoError(t, json.Unmarshal(b, &decodedElement))
+		resolveCallSequence(t, CallSequence{&decodedElement}, contractAbi)
+		assertCallSequencesEqual(t, cs[i:i+1], CallSequence{&decodedElement})
+	}
+
+	// Nil call sequences should be written as null.
+	b, err = json.Marshal(CallSequence(nil))
+	assert.NoError(t, err)
+	assert.EqualValues(t, "null", string(b))
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Nil(t, decoded)
+}
+
+// TestCallSequenceJSONLegacyCompatibility tests that a call sequence written in the legacy schema, before it was
+// versioned, is read transparently, and is written in the versioned schema.
+func TestCallSequenceJSONLegacyCompatibility(t *testing.T) {
+	expected, contractAbi := newCallSequenceJSONTestSequence(t)
+	b, err := os.ReadFile(filepath.Join("testdata", "legacy_call_sequence.json"))
+	assert.NoError(t, err)
+	version, err := DetectCallSequenceSchemaVersion(b)
+	assert.NoError(t, err)
+	assert.EqualValues(t, CallSequenceLegacySchemaVersion, version)
+
+	// The legacy call sequence should be read as the call sequence it was written from.
+	var legacy CallSequence
+	assert.NoError(t, json.Unmarshal(b, &legacy))
+	resolveCallSequence(t, legacy, contractAbi)
+	assertCallSequencesEqual(t, expected, legacy)
+
+	// Writing it should upgrade it to the versioned schema, which reads back the same call sequence.
+	b, err = json.Marshal(legacy)
+	assert.NoError(t, err)
+	version, err = DetectCallSequenceSchemaVersion(b)
+	assert.NoError(t, err)
+	assert.EqualValues(t, CallSequenceSchemaVersion, version)
+	var upgraded CallSequence
+	assert.NoError(t, json.Unmarshal(b, &upgraded))
+	resolveCallSequence(t, upgraded, contractAbi)
+	assertCallSequencesEqual(t, expected, upgraded)
+}
+
+// TestCallSequenceJSONResolution tests that calls in the versioned schema may identify their method by selector
+// alone, and that selectors, argument type tags, or schema versions which do not match are reported.
+func TestCallSequenceJSONResolution(t *testing.T) {
+	cs, contractAbi := newCallSequenceJSONTestSequence(t)
+	schema, err := cs.ToJSONSchema()
+	assert.NoError(t, err)
+
+	// resolveSchema converts the schema and resolves the ABI values of its first element.
+	resolveSchema := func(schema *CallSequenceJSON) error {
+		b, err := json.Marshal(schema)
+		assert.NoError(t, err)
+		var decoded CallSequence
+		if err = json.Unmarshal(b, &decoded); err != nil {
+			return err
+		}
+		return decoded[0].Call.DataAbiValues.Resolve(contractAbi)
+	}
+
+	// Calls identified only by selector, or without argument type tags, should be resolved.
+	schema.Calls[0].MethodSignature = ""
+	for i := range schema.Calls[0].Args {
+		schema.Calls[0].Args[i].Type = ""
+	}
+	assert.NoError(t, resolveSchema(schema))
+
+	// Selectors which do not match the method signature should be reported.
+	schema.Calls[0].MethodSignature = contractAbi.Methods["deposit"].Sig
+	schema.Calls[0].Selector = contractAbi.Methods["poke"].ID
+	assert.ErrorContains(t, resolveSchema(schema), "does not match method signature")
+
+	// Argument type tags which do not match the method's ABI should be reported.
+	schema.Calls[0].Selector = nil
+	schema.Calls[0].Args[4].Type = "uint64"
+	assert.ErrorContains(t, resolveSchema(schema), "argument 4 of method")
+
+	// Unknown schema versions should be reported.
+	schema.Version = CallSequenceSchemaVersion + 1
+	assert.ErrorContains(t, resolveSchema(schema), "is not supported")
+	_, err = DetectCallSequenceSchemaVersion([]byte(`{"calls": []}`))
+	assert.Error(t, err)
+}
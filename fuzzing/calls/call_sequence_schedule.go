@@ -0,0 +1,101 @@
+package calls
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CallSequenceElementExecutedBlock describes the block a CallSequenceElement was executed in, along with the block
+// it was built upon.
+type CallSequenceElementExecutedBlock struct {
+	// BlockNumber describes the block number the element was executed at.
+	BlockNumber uint64 `json:"blockNumber"`
+
+	// BlockTimestamp describes the block timestamp the element was executed at.
+	BlockTimestamp uint64 `json:"blockTimestamp"`
+
+	// ParentBlockNumber describes the block number of the chain head the executing block was built upon.
+	ParentBlockNumber uint64 `json:"parentBlockNumber"`
+
+	// ParentBlockTimestamp describes the block timestamp of the chain head the executing block was built upon.
+	ParentBlockTimestamp uint64 `json:"parentBlockTimestamp"`
+}
+
+// CallSequenceSchedule describes the block numbers and timestamps an executed CallSequence was executed at, along
+// with the block it started executing from. This allows the timing of the sequence to be reconstructed elsewhere,
+// e.g. by advancing the block number and timestamp to the values of each element before executing it.
+type CallSequenceSchedule struct {
+	// StartBlockNumber describes the block number of the chain head before the first element was executed.
+	StartBlockNumber uint64 `json:"startBlockNumber"`
+
+	// StartBlockTimestamp describes the block timestamp of the chain head before the first element was executed.
+	StartBlockTimestamp uint64 `json:"startBlockTimestamp"`
+
+	// Elements describes the schedule of each element in the CallSequence, in order.
+	Elements []CallSequenceScheduleElement `json:"elements"`
+}
+
+// CallSequenceScheduleElement describes when a single CallSequenceElement was executed.
+type CallSequenceScheduleElement struct {
+	// BlockNumber describes the block number the element was executed at.
+	BlockNumber uint64 `json:"blockNumber"`
+
+	// BlockTimestamp describes the block timestamp the element was executed at.
+	BlockTimestamp uint64 `json:"blockTimestamp"`
+
+	// BlockNumberDelay describes the block number delay the element was configured with. The actual delay may
+	// differ, e.g. if the block number delay exceeded the block timestamp delay.
+	BlockNumberDelay uint64 `json:"blockNumberDelay"`
+
+	// BlockTimestampDelay describes the block timestamp delay the element was configured with. The actual delay may
+	// differ, e.g. if the element was included in an existing block.
+	BlockTimestampDelay uint64 `json:"blockTimestampDelay"`
+
+	// EmptyBlocks describes the amount of empty blocks mined before the element was executed.
+	EmptyBlocks uint64 `json:"emptyBlocks,omitempty"`
+}
+
+// Schedule obtains the CallSequenceSchedule describing the blocks the CallSequence was executed at.
+// Returns the schedule, or nil if the sequence is empty or any of its elements was not executed.
+func (cs CallSequence) Schedule() *CallSequenceSchedule {
+	if len(cs) == 0 || cs[0].ExecutedBlock == nil {
+		return nil
+	}
+
+	// The first element's block was built upon the chain head the sequence started executing from, after any empty
+	// blocks it requested were mined.
+	schedule := &CallSequenceSchedule{
+		StartBlockNumber:    cs[0].ExecutedBlock.ParentBlockNumber - cs[0].EmptyBlocks,
+		StartBlockTimestamp: cs[0].ExecutedBlock.ParentBlockTimestamp - cs[0].EmptyBlocks*EmptyBlockTimestampDelay,
+		Elements:            make([]CallSequenceScheduleElement, 0, len(cs)),
+	}
+	for _, element := range cs {
+		if element.ExecutedBlock == nil {
+			return nil
+		}
+		schedule.Elements = append(schedule.Elements, CallSequenceScheduleElement{
+			BlockNumber:         element.ExecutedBlock.BlockNumber,
+			BlockTimestamp:      element.ExecutedBlock.BlockTimestamp,
+			BlockNumberDelay:    element.BlockNumberDelay,
+			BlockTimestampDelay: element.BlockTimestampDelay,
+			EmptyBlocks:         element.EmptyBlocks,
+		})
+	}
+	return schedule
+}
+
+// String returns a displayable string representing the CallSequenceSchedule, with one line describing the block
+// the sequence started from, followed by one line for each element.
+func (s *CallSequenceSchedule) String() string {
+	lines := make([]string, 0, len(s.Elements)+1)
+	lines = append(lines, fmt.Sprintf("start: block=%d, time=%d", s.StartBlockNumber, s.StartBlockTimestamp))
+	for i, element := range s.Elements {
+		line := fmt.Sprintf("%d) block=%d, time=%d, blockDelay=%d, timeDelay=%d", i+1,
+			element.BlockNumber, element.BlockTimestamp, element.BlockNumberDelay, element.BlockTimestampDelay)
+		if element.EmptyBlocks > 0 {
+			line += fmt.Sprintf(", emptyBlocks=%d", element.EmptyBlocks)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
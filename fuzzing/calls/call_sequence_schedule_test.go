@@ -0,0 +1,88 @@
+package calls
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/crytic/medusa/chain/testchainutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCallSequenceSchedule tests that the schedule of an executed call sequence with non-zero delays matches the
+// blocks its calls were included in, and that it is rendered and serialized with the call sequence.
+func TestCallSequenceSchedule(t *testing.T) {
+	// Create a test chain with a funded sender.
+	testChain, sender := testchainutils.NewFundedTestChain(t)
+	startBlockNumber := testChain.Head().Header.Number.Uint64()
+	startBlockTimestamp := testChain.Head().Header.Time
+
+	// Create a call sequence with a mix of delays, including calls which are included in the same block, and a block
+	// number delay which exceeds its timestamp delay.
+	recipient := common.HexToAddress("0x20000")
+	delays := [][2]uint64{{0, 0}, {2, 50}, {0, 0}, {0, 7}, {5, 3}}
+	callSequence := make(CallSequence, 0, len(delays))
+	for _, delay := range delays {
+		msg := NewCallMessage(sender, &recipient, 0, big.NewInt(1), 100_000, nil, nil, nil, nil)
+		callSequence = append(callSequence, NewCallSequenceElement(nil, msg, delay[0], delay[1]))
+	}
+
+	// Execute the sequence, filling in each call's properties as it is executed.
+	fetchElementFunc := func(currentIndex int) (*CallSequenceElement, error) {
+		if currentIndex >= len(callSequence) {
+			return nil, nil
+		}
+		callSequence[currentIndex].Call.FillFromTestChainProperties(testChain)
+		return callSequence[currentIndex], nil
+	}
+	_, err := ExecuteCallSequenceIteratively(testChain, fetchElementFunc, nil)
+	assert.NoError(t, err)
+
+	// Verify the schedule matches the receipts and blocks of each call.
+	schedule := callSequence.Schedule()
+	assert.NotNil(t, schedule)
+	assert.EqualValues(t, startBlockNumber, schedule.StartBlockNumber)
+	assert.EqualValues(t, startBlockTimestamp, schedule.StartBlockTimestamp)
+	assert.Len(t, schedule.Elements, len(callSequence))
+	for i, element := range callSequence {
+		messageResults := element.ChainReference.MessageResults()
+		assert.EqualValues(t, types.ReceiptStatusSuccessful, messageResults.Receipt.Status)
+		assert.EqualValues(t, messageResults.Receipt.BlockNumber.Uint64(), schedule.Elements[i].BlockNumber)
+		assert.EqualValues(t, element.ChainReference.Block.Header.Time, schedule.Elements[i].BlockTimestamp)
+		assert.EqualValues(t, delays[i][0], schedule.Elements[i].BlockNumberDelay)
+		assert.EqualValues(t, delays[i][1], schedule.Elements[i].BlockTimestampDelay)
+	}
+
+	// Calls without a block number delay are included in the pending block regardless of their timestamp delay, and
+	// block number delays are capped to timestamp delays.
+	assert.EqualValues(t, []uint64{1, 3, 3, 3, 6}, []uint64{
+		schedule.Elements[0].BlockNumber - startBlockNumber,
+		schedule.Elements[1].BlockNumber - startBlockNumber,
+		schedule.Elements[2].BlockNumber - startBlockNumber,
+		schedule.Elements[3].BlockNumber - startBlockNumber,
+		schedule.Elements[4].BlockNumber - startBlockNumber,
+	})
+	assert.EqualValues(t, schedule.Elements[1].BlockTimestamp, schedule.Elements[3].BlockTimestamp)
+	assert.EqualValues(t, schedule.Elements[3].BlockTimestamp+3, schedule.Elements[4].BlockTimestamp)
+
+	// Verify the rendered schedule describes the start block and each call's block.
+	renderedSchedule := strings.Split(schedule.String(), "\n")
+	assert.Len(t, renderedSchedule, len(callSequence)+1)
+	assert.EqualValues(t, fmt.Sprintf("start: block=%d, time=%d", startBlockNumber, startBlockTimestamp), renderedSchedule[0])
+	for i, element := range callSequence {
+		assert.EqualValues(t, fmt.Sprintf("%d) block=%d, time=%d, blockDelay=%d, timeDelay=%d", i+1,
+			element.ChainReference.MessageResults().Receipt.BlockNumber.Uint64(), element.ChainReference.Block.Header.Time,
+			delays[i][0], delays[i][1]), renderedSchedule[i+1])
+	}
+
+	// Verify the schedule survives serialization, as it would in a corpus file.
+	b, err := json.Marshal(callSequence)
+	assert.NoError(t, err)
+	var decodedCallSequence CallSequence
+	assert.NoError(t, json.Unmarshal(b, &decodedCallSequence))
+	assert.EqualValues(t, schedule, decodedCallSequence.Schedule())
+}
@@ -0,0 +1,437 @@
+package fuzzing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	compilationTypes "github.com/crytic/medusa/compilation/types"
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/crytic/medusa/fuzzing/coverage"
+	"github.com/crytic/medusa/utils"
+)
+
+const (
+	// campaignComparisonBaseline describes the name of the leg of a campaign comparison run with the baseline
+	// configuration, which is also the name of the directory its corpus is written to.
+	campaignComparisonBaseline = "baseline"
+
+	// campaignComparisonCandidate describes the name of the leg of a campaign comparison run with the candidate
+	// configuration, which is also the name of the directory its corpus is written to.
+	campaignComparisonCandidate = "candidate"
+)
+
+// CampaignComparison describes the results of two fuzzing campaigns run sequentially on the same compilation targets
+// with the same seed, but different configurations, and the differences between them.
+type CampaignComparison struct {
+	// Seed describes the seed both campaigns were run with.
+	Seed int64 `json:"seed"`
+
+	// Baseline describes the results of the campaign run with the baseline configuration.
+	Baseline CampaignLegReport `json:"baseline"`
+
+	// Candidate describes the results of the campaign run with the candidate configuration.
+	Candidate CampaignLegReport `json:"candidate"`
+
+	// Delta describes the differences between the results of the candidate and baseline campaigns.
+	Delta CampaignComparisonDelta `json:"delta"`
+}
+
+// CampaignLegReport describes the results of a single fuzzing campaign run as part of a CampaignComparison.
+type CampaignLegReport struct {
+	// Name describes the name of the campaign, either "baseline" or "candidate".
+	Name string `json:"name"`
+
+	// CorpusDirectory describes the directory the campaign's corpus and coverage reports were written to.
+	CorpusDirectory string `json:"corpusDirectory"`
+
+	// CallSequenceLength describes the call sequence length the campaign was configured with.
+	CallSequenceLength int `json:"callSequenceLength"`
+
+	// ElapsedSeconds describes the time spent fuzzing, from when workers began fuzzing until the campaign stopped.
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+
+	// CallsTested describes the count of calls tested by the campaign.
+	CallsTested uint64 `json:"callsTested"`
+
+	// SequencesTested describes the count of call sequences tested by the campaign.
+	SequencesTested uint64 `json:"sequencesTested"`
+
+	// CallsPerSecond describes the average count of calls tested per second spent fuzzing.
+	CallsPerSecond float64 `json:"callsPerSecond"`
+
+	// CorpusSize describes the count of call sequences in the corpus when the campaign stopped.
+	CorpusSize int `json:"corpusSize"`
+
+	// UniquePCs describes the count of unique program counters covered by the campaign.
+	UniquePCs uint64 `json:"uniquePCs"`
+
+	// ActiveLines describes the count of source lines which are active (executable) across all source files.
+	ActiveLines int `json:"activeLines"`
+
+	// CoveredLines describes the count of active source lines covered by the campaign across all source files.
+	CoveredLines int `json:"coveredLines"`
+
+	// Files describes the line coverage totals of each source file, ordered by path.
+	Files []CampaignFileCoverage `json:"files"`
+
+	// Failures describes the test cases which failed during the campaign, ordered by the time they were discovered.
+	Failures []CampaignFailure `json:"failures"`
+}
+
+// CampaignFileCoverage describes the line coverage totals of a source file at the end of a fuzzing campaign.
+type CampaignFileCoverage struct {
+	// Path describes the path of the source file, as it is reported in the JSON coverage report.
+	Path string `json:"path"`
+
+	// ActiveLines describes the count of active (executable) lines in the source file.
+	ActiveLines int `json:"activeLines"`
+
+	// CoveredLines describes the count of active lines in the source file which were covered.
+	CoveredLines int `json:"coveredLines"`
+}
+
+// CampaignFailure describes a test case which failed during a fuzzing campaign.
+type CampaignFailure struct {
+	// ID describes the unique identifier of the test case.
+	ID string `json:"id"`
+
+	// Name describes the human-readable name of the test case.
+	Name string `json:"name"`
+
+	// TimeToDiscoverySeconds describes the time from when workers began fuzzing until a call sequence was first found
+	// to cause the failure, which excludes the time spent shrinking it. If the failure was reported without being
+	// found by a worker's main loop (e.g. by a worker process), the time it was reported is used instead.
+	TimeToDiscoverySeconds float64 `json:"timeToDiscoverySeconds"`
+}
+
+// CampaignComparisonDelta describes the differences between the results of the candidate and baseline campaigns of a
+// CampaignComparison. Each delta is the candidate value less the baseline value.
+type CampaignComparisonDelta struct {
+	// CallsPerSecond describes the difference in calls tested per second.
+	CallsPerSecond float64 `json:"callsPerSecond"`
+
+	// CorpusSize describes the difference in corpus size.
+	CorpusSize int `json:"corpusSize"`
+
+	// UniquePCs describes the difference in unique program counters covered.
+	UniquePCs int64 `json:"uniquePCs"`
+
+	// CoveredLines describes the difference in source lines covered across all source files.
+	CoveredLines int `json:"coveredLines"`
+
+	// Files describes the difference in lines covered for every source file covered by either campaign, ordered by
+	// path.
+	Files []CampaignFileCoverageDelta `json:"files"`
+
+	// Failures describes every test case which failed in either campaign, and when each campaign discovered it,
+	// ordered by test case ID.
+	Failures []CampaignFailureDelta `json:"failures"`
+}
+
+// CampaignFileCoverageDelta describes the difference in lines of a source file covered by the candidate and baseline
+// campaigns of a CampaignComparison.
+type CampaignFileCoverageDelta struct {
+	// Path describes the path of the source file, as it is reported in the JSON coverage report.
+	Path string `json:"path"`
+
+	// BaselineCoveredLines describes the count of lines in the source file covered by the baseline campaign.
+	BaselineCoveredLines int `json:"baselineCoveredLines"`
+
+	// CandidateCoveredLines describes the count of lines in the source file covered by the candidate campaign.
+	CandidateCoveredLines int `json:"candidateCoveredLines"`
+
+	// CoveredLines describes the difference in lines covered.
+	CoveredLines int `json:"coveredLines"`
+}
+
+// CampaignFailureDelta describes a test case which failed in at least one campaign of a CampaignComparison.
+type CampaignFailureDelta struct {
+	// ID describes the unique identifier of the test case.
+	ID string `json:"id"`
+
+	// Name describes the human-readable name of the test case.
+	Name string `json:"name"`
+
+	// BaselineTimeToDiscoverySeconds describes the time the baseline campaign took to discover the failure, or nil if
+	// it did not.
+	BaselineTimeToDiscoverySeconds *float64 `json:"baselineTimeToDiscoverySeconds"`
+
+	// CandidateTimeToDiscoverySeconds describes the time the candidate campaign took to discover the failure, or nil
+	// if it did not.
+	CandidateTimeToDiscoverySeconds *float64 `json:"candidateTimeToDiscoverySeconds"`
+}
+
+// RunCampaignComparison runs a fuzzing campaign with the provided baseline project configuration, followed by another
+// with the provided candidate project configuration, and compares their results. The compilation targets are compiled
+// once, using the baseline compilation config, and both campaigns are run with the same seed, which is chosen randomly
+// if the baseline configuration does not specify one. Each campaign writes its corpus and coverage reports to its own
+// directory within the provided output directory, which must not contain a previous comparison. The comparison is
+// written to the output directory as "comparison.json" and "comparison.md".
+// Returns the comparison, or an error if either campaign could not be run or the comparison could not be written.
+func RunCampaignComparison(baseline config.ProjectConfig, candidate config.ProjectConfig, outputDirectory string) (*CampaignComparison, error) {
+	if baseline.Compilation == nil {
+		return nil, errors.New("cannot compare campaigns without a compilation config")
+	}
+
+	// Each campaign must start from an empty corpus, otherwise the comparison would be biased toward the campaign
+	// which inherits more progress.
+	for _, name := range []string{campaignComparisonBaseline, campaignComparisonCandidate} {
+		entries, err := os.ReadDir(filepath.Join(outputDirectory, name))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if len(entries) > 0 {
+			return nil, fmt.Errorf("cannot compare campaigns, the %s campaign directory %v is not empty", name, filepath.Join(outputDirectory, name))
+		}
+	}
+
+	// Compile our targets once, so both campaigns fuzz the same compilations.
+	compilations, _, err := baseline.Compilation.Compile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile targets: %w", err)
+	}
+	candidate.Compilation = baseline.Compilation
+
+	// Run both campaigns with the same seed.
+	seed := baseline.Fuzzing.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	comparison := &CampaignComparison{Seed: seed}
+	for _, leg := range []struct {
+		name   string
+		config config.ProjectConfig
+		report *CampaignLegReport
+	}{
+		{campaignComparisonBaseline, baseline, &comparison.Baseline},
+		{campaignComparisonCandidate, candidate, &comparison.Candidate},
+	} {
+		leg.config.Fuzzing.Seed = seed
+		leg.config.Fuzzing.CorpusDirectory = filepath.Join(outputDirectory, leg.name, "corpus")
+		report, err := runCampaignComparisonLeg(leg.name, leg.config, compilations)
+		if err != nil {
+			return nil, fmt.Errorf("failed to run the %s campaign: %w", leg.name, err)
+		}
+		*leg.report = *report
+	}
+	comparison.Delta = newCampaignComparisonDelta(comparison.Baseline, comparison.Candidate)
+
+	// Write our comparison in both formats.
+	b, err := json.MarshalIndent(comparison, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(filepath.Join(outputDirectory, "comparison.json"), b, 0644); err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(filepath.Join(outputDirectory, "comparison.md"), []byte(comparison.Markdown()), 0644); err != nil {
+		return nil, err
+	}
+	return comparison, nil
+}
+
+// runCampaignComparisonLeg runs a fuzzing campaign with the provided project configuration on the provided
+// compilations, and reports its results under the provided name.
+// Returns the report, or an error if the campaign could not be run.
+func runCampaignComparisonLeg(name string, projectConfig config.ProjectConfig, compilations []compilationTypes.Compilation) (*CampaignLegReport, error) {
+	if err := utils.MakeDirectory(projectConfig.Fuzzing.CorpusDirectory); err != nil {
+		return nil, err
+	}
+	fuzzer, err := newFuzzer(projectConfig, compilations)
+	if err != nil {
+		return nil, err
+	}
+
+	// Measure time from when the campaign is set up and workers begin fuzzing.
+	var startTime time.Time
+	fuzzer.Events.FuzzerStarting.SubscribeNamed("campaign comparison", func(event FuzzerStartingEvent) error {
+		startTime = time.Now()
+		return nil
+	})
+	err = fuzzer.Start()
+	if err != nil {
+		return nil, err
+	}
+	elapsed := time.Since(startTime)
+
+	report := &CampaignLegReport{
+		Name:               name,
+		CorpusDirectory:    projectConfig.Fuzzing.CorpusDirectory,
+		CallSequenceLength: projectConfig.Fuzzing.CallSequenceLength,
+		ElapsedSeconds:     elapsed.Seconds(),
+		CallsTested:        fuzzer.metrics.CallsTested().Uint64(),
+		SequencesTested:    fuzzer.metrics.SequencesTested().Uint64(),
+		CorpusSize:         fuzzer.corpus.ActiveMutableSequenceCount(),
+		UniquePCs:          fuzzer.corpus.CoverageMaps().UniquePCs(),
+		Files:              make([]CampaignFileCoverage, 0),
+	}
+	if elapsed > 0 {
+		report.CallsPerSecond = float64(report.CallsTested) / elapsed.Seconds()
+	}
+
+	// Summarize the coverage of each source file as it is reported in the JSON coverage report.
+	sourceAnalysis, err := coverage.AnalyzeSourceCoverage(compilations, fuzzer.corpus.CoverageMaps())
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range coverage.NewCoverageReport(sourceAnalysis, projectConfig.Fuzzing.CoverageBasePath).Files {
+		report.ActiveLines += file.Totals.Active
+		report.CoveredLines += file.Totals.Covered
+		report.Files = append(report.Files, CampaignFileCoverage{
+			Path:         file.Path,
+			ActiveLines:  file.Totals.Active,
+			CoveredLines: file.Totals.Covered,
+		})
+	}
+
+	report.Failures = campaignFailures(fuzzer, startTime)
+	return report, nil
+}
+
+// campaignFailures describes each test case the provided Fuzzer reported as failed, along with the time it took to
+// discover from the provided start time, which may be zero if it was reproduced by the corpus before workers began
+// fuzzing. Failures found by a worker's main loop are timed from when they were first detected, rather than from when
+// they were reported after shrinking. Failures are sorted by the time they took to discover.
+func campaignFailures(fuzzer *Fuzzer, startTime time.Time) []CampaignFailure {
+	failures := make([]CampaignFailure, 0)
+	fuzzer.testCasesLock.Lock()
+	for _, testCase := range fuzzer.testCasesFinished {
+		if testCase.Status() != TestCaseStatusFailed {
+			continue
+		}
+		discoveredTime, ok := fuzzer.testCasesDiscoveredTimes[testCase.Name()]
+		if !ok {
+			discoveredTime = fuzzer.testCasesFinishedTimes[testCase.ID()]
+		}
+		failures = append(failures, CampaignFailure{
+			ID:                     testCase.ID(),
+			Name:                   testCase.Name(),
+			TimeToDiscoverySeconds: max(discoveredTime.Sub(startTime), 0).Seconds(),
+		})
+	}
+	fuzzer.testCasesLock.Unlock()
+	slices.SortFunc(failures, func(a, b CampaignFailure) int {
+		if a.TimeToDiscoverySeconds != b.TimeToDiscoverySeconds {
+			if a.TimeToDiscoverySeconds < b.TimeToDiscoverySeconds {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return failures
+}
+
+// newCampaignComparisonDelta computes the differences between the results of the provided candidate and baseline
+// campaigns.
+func newCampaignComparisonDelta(baseline CampaignLegReport, candidate CampaignLegReport) CampaignComparisonDelta {
+	delta := CampaignComparisonDelta{
+		CallsPerSecond: candidate.CallsPerSecond - baseline.CallsPerSecond,
+		CorpusSize:     candidate.CorpusSize - baseline.CorpusSize,
+		UniquePCs:      int64(candidate.UniquePCs) - int64(baseline.UniquePCs),
+		CoveredLines:   candidate.CoveredLines - baseline.CoveredLines,
+		Files:          make([]CampaignFileCoverageDelta, 0),
+		Failures:       make([]CampaignFailureDelta, 0),
+	}
+
+	// Compare the coverage of every source file reported by either campaign.
+	files := make(map[string]*CampaignFileCoverageDelta)
+	fileDelta := func(path string) *CampaignFileCoverageDelta {
+		if _, ok := files[path]; !ok {
+			files[path] = &CampaignFileCoverageDelta{Path: path}
+		}
+		return files[path]
+	}
+	for _, file := range baseline.Files {
+		fileDelta(file.Path).BaselineCoveredLines = file.CoveredLines
+	}
+	for _, file := range candidate.Files {
+		fileDelta(file.Path).CandidateCoveredLines = file.CoveredLines
+	}
+	for _, file := range files {
+		file.CoveredLines = file.CandidateCoveredLines - file.BaselineCoveredLines
+		delta.Files = append(delta.Files, *file)
+	}
+	slices.SortFunc(delta.Files, func(a, b CampaignFileCoverageDelta) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	// Compare when every failure found by either campaign was discovered.
+	failures := make(map[string]*CampaignFailureDelta)
+	failureDelta := func(failure CampaignFailure) *CampaignFailureDelta {
+		if _, ok := failures[failure.ID]; !ok {
+			failures[failure.ID] = &CampaignFailureDelta{ID: failure.ID, Name: failure.Name}
+		}
+		return failures[failure.ID]
+	}
+	for _, failure := range baseline.Failures {
+		failureDelta(failure).BaselineTimeToDiscoverySeconds = &failure.TimeToDiscoverySeconds
+	}
+	for _, failure := range candidate.Failures {
+		failureDelta(failure).CandidateTimeToDiscoverySeconds = &failure.TimeToDiscoverySeconds
+	}
+	for _, failure := range failures {
+		delta.Failures = append(delta.Failures, *failure)
+	}
+	slices.SortFunc(delta.Failures, func(a, b CampaignFailureDelta) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return delta
+}
+
+// Markdown returns the comparison as a Markdown document, with a table comparing the totals of each campaign, followed
+// by tables comparing the coverage of each source file and when each failure was discovered.
+func (c *CampaignComparison) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("# Campaign comparison\n\n")
+	sb.WriteString(fmt.Sprintf("Both campaigns were run with seed `%d`.\n\n", c.Seed))
+
+	// Write our totals.
+	sb.WriteString("| | baseline | candidate | delta |\n")
+	sb.WriteString("|---|---:|---:|---:|\n")
+	sb.WriteString(fmt.Sprintf("| call sequence length | %d | %d | %+d |\n", c.Baseline.CallSequenceLength, c.Candidate.CallSequenceLength, c.Candidate.CallSequenceLength-c.Baseline.CallSequenceLength))
+	sb.WriteString(fmt.Sprintf("| elapsed (s) | %.1f | %.1f | %+.1f |\n", c.Baseline.ElapsedSeconds, c.Candidate.ElapsedSeconds, c.Candidate.ElapsedSeconds-c.Baseline.ElapsedSeconds))
+	sb.WriteString(fmt.Sprintf("| calls tested | %d | %d | %+d |\n", c.Baseline.CallsTested, c.Candidate.CallsTested, int64(c.Candidate.CallsTested)-int64(c.Baseline.CallsTested)))
+	sb.WriteString(fmt.Sprintf("| calls/s | %.1f | %.1f | %+.1f |\n", c.Baseline.CallsPerSecond, c.Candidate.CallsPerSecond, c.Delta.CallsPerSecond))
+	sb.WriteString(fmt.Sprintf("| corpus size | %d | %d | %+d |\n", c.Baseline.CorpusSize, c.Candidate.CorpusSize, c.Delta.CorpusSize))
+	sb.WriteString(fmt.Sprintf("| unique PCs | %d | %d | %+d |\n", c.Baseline.UniquePCs, c.Candidate.UniquePCs, c.Delta.UniquePCs))
+	sb.WriteString(fmt.Sprintf("| covered lines | %d/%d | %d/%d | %+d |\n", c.Baseline.CoveredLines, c.Baseline.ActiveLines, c.Candidate.CoveredLines, c.Candidate.ActiveLines, c.Delta.CoveredLines))
+	sb.WriteString(fmt.Sprintf("| failures | %d | %d | %+d |\n", len(c.Baseline.Failures), len(c.Candidate.Failures), len(c.Candidate.Failures)-len(c.Baseline.Failures)))
+
+	// Write the coverage of each source file.
+	sb.WriteString("\n## Covered lines by file\n\n")
+	if len(c.Delta.Files) == 0 {
+		sb.WriteString("No source files were covered.\n")
+	} else {
+		sb.WriteString("| file | baseline | candidate | delta |\n")
+		sb.WriteString("|---|---:|---:|---:|\n")
+		for _, file := range c.Delta.Files {
+			sb.WriteString(fmt.Sprintf("| %s | %d | %d | %+d |\n", file.Path, file.BaselineCoveredLines, file.CandidateCoveredLines, file.CoveredLines))
+		}
+	}
+
+	// Write when each failure was discovered.
+	sb.WriteString("\n## Failures\n\n")
+	if len(c.Delta.Failures) == 0 {
+		sb.WriteString("No failures were found.\n")
+	} else {
+		timeToDiscovery := func(seconds *float64) string {
+			if seconds == nil {
+				return "not found"
+			}
+			return fmt.Sprintf("%.1fs", *seconds)
+		}
+		sb.WriteString("| test | baseline | candidate |\n")
+		sb.WriteString("|---|---:|---:|\n")
+		for _, failure := range c.Delta.Failures {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", strings.ReplaceAll(failure.Name, "|", "\\|"), timeToDiscovery(failure.BaselineTimeToDiscoverySeconds), timeToDiscovery(failure.CandidateTimeToDiscoverySeconds)))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,196 @@
+package fuzzing
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRunCampaignComparison runs two short campaigns on our pre-compiled Hardhat project which differ only in their
+// call sequence length, and ensures each campaign is isolated in its own directory and the comparison reports the
+// totals of each campaign and the differences between them.
+func TestRunCampaignComparison(t *testing.T) {
+	runHardhatProjectTest(t, func(baseline *config.ProjectConfig) {
+		// Create our baseline and candidate project configurations, which only differ in their call sequence length.
+		baseline.Fuzzing.TestLimit = 500
+		baseline.Fuzzing.CallSequenceLength = 10
+		baseline.Fuzzing.CoverageFormats = []string{"json"}
+		baseline.Fuzzing.Testing.StopOnNoTests = false
+		candidate := *baseline
+		candidate.Fuzzing.CallSequenceLength = 50
+
+		comparison, err := RunCampaignComparison(*baseline, candidate, "comparison")
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+
+		// Both campaigns should have run with the same seed and their own configured call sequence length.
+		assert.NotZero(t, comparison.Seed)
+		assert.EqualValues(t, 10, comparison.Baseline.CallSequenceLength)
+		assert.EqualValues(t, 50, comparison.Candidate.CallSequenceLength)
+		for _, leg := range []CampaignLegReport{comparison.Baseline, comparison.Candidate} {
+			assert.GreaterOrEqual(t, leg.CallsTested, uint64(500))
+			assert.Positive(t, leg.CallsPerSecond)
+			assert.Positive(t, leg.CorpusSize)
+			assert.Positive(t, leg.UniquePCs)
+			assert.Positive(t, leg.CoveredLines)
+			assert.NotEmpty(t, leg.Files)
+			assert.Empty(t, leg.Failures)
+
+			// Each campaign should have written its corpus and coverage report to its own directory.
+			assert.EqualValues(t, filepath.Join("comparison", leg.Name, "corpus"), leg.CorpusDirectory)
+			assert.FileExists(t, filepath.Join(leg.CorpusDirectory, "coverage", "coverage.json"))
+		}
+		assert.NotEqualValues(t, comparison.Baseline.CorpusDirectory, comparison.Candidate.CorpusDirectory)
+
+		// The delta should describe the difference between the campaigns for every covered source file.
+		assert.EqualValues(t, comparison.Candidate.CoveredLines-comparison.Baseline.CoveredLines, comparison.Delta.CoveredLines)
+		assert.EqualValues(t, comparison.Candidate.CorpusSize-comparison.Baseline.CorpusSize, comparison.Delta.CorpusSize)
+		assert.EqualValues(t, int64(comparison.Candidate.UniquePCs)-int64(comparison.Baseline.UniquePCs), comparison.Delta.UniquePCs)
+		assert.Len(t, comparison.Delta.Files, len(comparison.Baseline.Files))
+		for _, file := range comparison.Delta.Files {
+			assert.EqualValues(t, file.CandidateCoveredLines-file.BaselineCoveredLines, file.CoveredLines)
+		}
+		assert.Empty(t, comparison.Delta.Failures)
+
+		// The comparison should have been written as JSON and Markdown.
+		b, err := os.ReadFile(filepath.Join("comparison", "comparison.json"))
+		assert.NoError(t, err)
+		var written CampaignComparison
+		assert.NoError(t, json.Unmarshal(b, &written))
+		assert.EqualValues(t, *comparison, written)
+		b, err = os.ReadFile(filepath.Join("comparison", "comparison.md"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(b), "| call sequence length | 10 | 50 | +40 |")
+		assert.Contains(t, string(b), "## Covered lines by file")
+
+		// Running another comparison in the same directory should be refused, as the campaigns would not start from an
+		// empty corpus.
+		_, err = RunCampaignComparison(*baseline, candidate, "comparison")
+		assert.ErrorContains(t, err, "is not empty")
+	})
+}
+
+// TestCampaignFailureTimeToDiscovery runs a campaign on our pre-compiled Hardhat project in which a hook reports a
+// failure whose shrinking is slow, and ensures its time to discovery is measured until it was first detected, excluding
+// the time spent shrinking it.
+func TestCampaignFailureTimeToDiscovery(t *testing.T) {
+	runHardhatProjectTest(t, func(projectConfig *config.ProjectConfig) {
+		projectConfig.Fuzzing.Workers = 1
+		projectConfig.Fuzzing.TestLimit = 10_000
+		projectConfig.Fuzzing.CallSequenceLength = 5
+		projectConfig.Fuzzing.ShrinkLimit = 5
+		projectConfig.Fuzzing.Testing.StopOnNoTests = false
+		executeFuzzerTestMethodInternal(t, projectConfig, func(f *fuzzerTestContext) {
+			// Report a failure at the end of the first sequence, which takes a while to shrink as every shrunken call
+			// sequence (including the replay confirming the failure) is verified slowly.
+			const shrinkDelay = 50 * time.Millisecond
+			failed := false
+			testCase := &workerProcessTestCase{TestID: "slow shrink", TestName: "slow shrink", TestStatus: TestCaseStatusFailed}
+			f.fuzzer.Hooks.SequenceCompletedTestFuncs = append(f.fuzzer.Hooks.SequenceCompletedTestFuncs, func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+				if failed {
+					return nil, nil
+				}
+				failed = true
+				return []ShrinkCallSequenceRequest{{
+					TestName:             testCase.Name(),
+					CallSequenceToShrink: callSequence,
+					VerifierFunction: func(worker *FuzzerWorker, callSequence calls.CallSequence) (bool, error) {
+						time.Sleep(shrinkDelay)
+						return true, nil
+					},
+					FinishedCallback: func(worker *FuzzerWorker, callSequence calls.CallSequence, verboseTracing bool) error {
+						worker.Fuzzer().ReportTestCaseFinished(testCase)
+						worker.Fuzzer().Stop()
+						return nil
+					},
+				}}, nil
+			})
+
+			var startTime time.Time
+			f.fuzzer.Events.FuzzerStarting.Subscribe(func(event FuzzerStartingEvent) error {
+				startTime = time.Now()
+				return nil
+			})
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+			assert.True(t, failed)
+
+			// The failure should be timed from when it was detected, before the slow shrinking began.
+			failures := campaignFailures(f.fuzzer, startTime)
+			assert.Len(t, failures, 1)
+			if len(failures) == 0 {
+				return
+			}
+			reportedSeconds := f.fuzzer.testCasesFinishedTimes[testCase.ID()].Sub(startTime).Seconds()
+			assert.EqualValues(t, "slow shrink", failures[0].Name)
+			assert.Less(t, failures[0].TimeToDiscoverySeconds, reportedSeconds-shrinkDelay.Seconds())
+		})
+	})
+}
+
+// TestCampaignComparisonDelta tests that the delta between two campaigns includes every source file and failure from
+// either campaign, recording which campaigns discovered each failure.
+func TestCampaignComparisonDelta(t *testing.T) {
+	baseline := CampaignLegReport{
+		CallsPerSecond: 100,
+		CorpusSize:     3,
+		UniquePCs:      50,
+		CoveredLines:   10,
+		Files: []CampaignFileCoverage{
+			{Path: "b.sol", ActiveLines: 10, CoveredLines: 6},
+			{Path: "a.sol", ActiveLines: 5, CoveredLines: 4},
+		},
+		Failures: []CampaignFailure{
+			{ID: "PROPERTY-x", Name: "Property Test: C.property_x()", TimeToDiscoverySeconds: 2},
+			{ID: "PROPERTY-y", Name: "Property Test: C.property_y()", TimeToDiscoverySeconds: 5},
+		},
+	}
+	candidate := CampaignLegReport{
+		CallsPerSecond: 80,
+		CorpusSize:     5,
+		UniquePCs:      45,
+		CoveredLines:   12,
+		Files: []CampaignFileCoverage{
+			{Path: "b.sol", ActiveLines: 10, CoveredLines: 8},
+			{Path: "c.sol", ActiveLines: 2, CoveredLines: 2},
+		},
+		Failures: []CampaignFailure{
+			{ID: "PROPERTY-z", Name: "Property Test: C.property_z()", TimeToDiscoverySeconds: 1},
+			{ID: "PROPERTY-x", Name: "Property Test: C.property_x()", TimeToDiscoverySeconds: 3},
+		},
+	}
+
+	delta := newCampaignComparisonDelta(baseline, candidate)
+	assert.EqualValues(t, -20, delta.CallsPerSecond)
+	assert.EqualValues(t, 2, delta.CorpusSize)
+	assert.EqualValues(t, -5, delta.UniquePCs)
+	assert.EqualValues(t, 2, delta.CoveredLines)
+	assert.EqualValues(t, []CampaignFileCoverageDelta{
+		{Path: "a.sol", BaselineCoveredLines: 4, CandidateCoveredLines: 0, CoveredLines: -4},
+		{Path: "b.sol", BaselineCoveredLines: 6, CandidateCoveredLines: 8, CoveredLines: 2},
+		{Path: "c.sol", BaselineCoveredLines: 0, CandidateCoveredLines: 2, CoveredLines: 2},
+	}, delta.Files)
+
+	assert.Len(t, delta.Failures, 3)
+	assert.EqualValues(t, "PROPERTY-x", delta.Failures[0].ID)
+	assert.EqualValues(t, 2, *delta.Failures[0].BaselineTimeToDiscoverySeconds)
+	assert.EqualValues(t, 3, *delta.Failures[0].CandidateTimeToDiscoverySeconds)
+	assert.EqualValues(t, "PROPERTY-y", delta.Failures[1].ID)
+	assert.Nil(t, delta.Failures[1].CandidateTimeToDiscoverySeconds)
+	assert.EqualValues(t, "PROPERTY-z", delta.Failures[2].ID)
+	assert.Nil(t, delta.Failures[2].BaselineTimeToDiscoverySeconds)
+
+	// Failures found by only one campaign should be reported as not found by the other.
+	comparison := CampaignComparison{Baseline: baseline, Candidate: candidate, Delta: delta}
+	markdown := comparison.Markdown()
+	assert.Contains(t, markdown, "| Property Test: C.property_y() | 5.0s | not found |")
+	assert.Contains(t, markdown, "| a.sol | 4 | 0 | -4 |")
+}
@@ -0,0 +1,45 @@
+package fuzzing
+
+import (
+	"math/big"
+)
+
+var (
+	// chainContextTimestampOffsets defines the offsets from the chain head's timestamp which are used as chain context
+	// values. As each generated call is executed in a new block whose timestamp may have advanced, offsets ahead of
+	// the current timestamp are included.
+	chainContextTimestampOffsets = []uint64{0, 1, 60 * 60, 24 * 60 * 60, 7 * 24 * 60 * 60}
+
+	// chainContextBlockNumberOffsets defines the offsets from the chain head's block number which are used as chain
+	// context values.
+	chainContextBlockNumberOffsets = []uint64{0, 1}
+)
+
+// chainContextTimestamps obtains the chain context timestamps relative to the provided chain head timestamp.
+func chainContextTimestamps(timestamp uint64) []*big.Int {
+	timestamps := make([]*big.Int, 0, len(chainContextTimestampOffsets))
+	for _, offset := range chainContextTimestampOffsets {
+		timestamps = append(timestamps, new(big.Int).Add(new(big.Int).SetUint64(timestamp), new(big.Int).SetUint64(offset)))
+	}
+	return timestamps
+}
+
+// chainContextIntegers obtains every chain context value relative to the provided chain head block number and
+// timestamp.
+func chainContextIntegers(blockNumber *big.Int, timestamp uint64) []*big.Int {
+	integers := chainContextTimestamps(timestamp)
+	for _, offset := range chainContextBlockNumberOffsets {
+		integers = append(integers, new(big.Int).Add(blockNumber, new(big.Int).SetUint64(offset)))
+	}
+	return integers
+}
+
+// refreshChainContextValues replaces the chain context values in the FuzzerWorker's value set with those relative to
+// the head of its chain, if chain context values are enabled.
+func (fw *FuzzerWorker) refreshChainContextValues() {
+	if !fw.fuzzer.config.Fuzzing.ChainContextValues {
+		return
+	}
+	header := fw.chain.Head().Header
+	fw.valueSet.SetChainContextIntegers(chainContextIntegers(header.Number, header.Time))
+}
@@ -0,0 +1,77 @@
+package fuzzing
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/crytic/medusa/fuzzing/valuegeneration"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestChainContextIntegers tests that chain context values are derived from the provided block number and timestamp,
+// and that refreshing them in a value set replaces the previous values rather than accumulating them.
+func TestChainContextIntegers(t *testing.T) {
+	integers := chainContextIntegers(big.NewInt(100), 1_700_000_000)
+	assert.EqualValues(t, []*big.Int{
+		big.NewInt(1_700_000_000),
+		big.NewInt(1_700_000_001),
+		big.NewInt(1_700_003_600),
+		big.NewInt(1_700_086_400),
+		big.NewInt(1_700_604_800),
+		big.NewInt(100),
+		big.NewInt(101),
+	}, integers)
+
+	// Timestamps near the maximum value should not overflow.
+	maxTimestamps := chainContextTimestamps(^uint64(0))
+	assert.EqualValues(t, 1, maxTimestamps[1].Cmp(new(big.Int).SetUint64(^uint64(0))))
+
+	// Chain context values should be included alongside other integers, without duplicates.
+	valueSet := valuegeneration.NewValueSet()
+	valueSet.AddInteger(big.NewInt(100))
+	valueSet.SetChainContextIntegers(integers)
+	assert.Len(t, valueSet.Integers(), len(integers))
+	assert.Len(t, valueSet.ChainContextIntegers(), len(integers))
+
+	// Refreshing them should replace the previous values, while other integers remain.
+	clonedValueSet := valueSet.Clone()
+	valueSet.SetChainContextIntegers(chainContextIntegers(big.NewInt(200), 1_800_000_000))
+	assert.Len(t, valueSet.Integers(), len(integers)+1)
+	assert.NotContains(t, valueSet.Integers(), big.NewInt(101))
+	assert.Contains(t, valueSet.Integers(), big.NewInt(100))
+	assert.Contains(t, valueSet.Integers(), big.NewInt(201))
+
+	// Restoring a previous value set should restore its chain context values too.
+	valueSet.CopyFrom(clonedValueSet)
+	assert.Contains(t, valueSet.ChainContextIntegers(), big.NewInt(101))
+	assert.NotContains(t, valueSet.ChainContextIntegers(), big.NewInt(201))
+}
+
+// TestParameterHintsContextTimestamps tests that the timestamp parameter hint sometimes selects the chain context
+// timestamps exactly, if they are provided.
+func TestParameterHintsContextTimestamps(t *testing.T) {
+	projectConfig, err := config.GetDefaultProjectConfig("")
+	assert.NoError(t, err)
+	projectConfig.Fuzzing.ParameterNameHints.Probability = 1
+	hints, err := newParameterHints(projectConfig.Fuzzing.ParameterNameHints)
+	assert.NoError(t, err)
+
+	hintContext := &parameterHintContext{
+		randomProvider:    rand.New(rand.NewSource(1)),
+		timestamp:         1_700_000_000,
+		contextTimestamps: chainContextTimestamps(1_700_000_000),
+		maxTimestampDelay: projectConfig.Fuzzing.MaxBlockTimestampDelay,
+	}
+	argument := newParameterHintsTestArgument(t, "deadline", "uint256")
+	contextTimestampCount := 0
+	for i := 0; i < 1000; i++ {
+		value, hinted := hints.generate(hintContext, &argument)
+		assert.True(t, hinted)
+		if assert.ObjectsAreEqual(big.NewInt(1_700_003_600), value) {
+			contextTimestampCount++
+		}
+	}
+	assert.Greater(t, contextTimestampCount, 50)
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"path"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -15,6 +17,8 @@ import (
 	"github.com/crytic/medusa/compilation"
 	"github.com/crytic/medusa/logging"
 	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/rs/zerolog"
 )
 
@@ -45,32 +49,157 @@ type FuzzingConfig struct {
 	// zero value will result in no timeout.
 	Timeout int `json:"timeout"`
 
+	// StopOnCoveragePlateau describes a time threshold in seconds after which the fuzzing operation should stop if no
+	// new coverage was achieved by any worker. This requires coverage to be enabled. Providing a zero value will result
+	// in the fuzzing operation not stopping on a coverage plateau.
+	StopOnCoveragePlateau int `json:"stopOnCoveragePlateau"`
+
+	// Seed describes the seed used to derive all random decisions made by the fuzzer. A zero value indicates a seed
+	// should be chosen at random when fuzzing begins.
+	Seed int64 `json:"seed"`
+
+	// Deterministic describes whether workers should fuzz independently of one another, so that the failures found
+	// depend only on the Seed, the corpus, and the configuration. Each worker only mutates the call sequences in the
+	// corpus when fuzzing begins and those it discovered itself, and tests an equal share of the TestLimit. Call
+	// sequences discovered by each worker are added to the corpus when fuzzing ends.
+	Deterministic bool `json:"deterministic"`
+
 	// TestLimit describes a threshold for the number of transactions to test, after which it will exit. This number
 	// must be non-negative. A zero value indicates the test limit should not be enforced.
 	TestLimit uint64 `json:"testLimit"`
 
+	// CorpusReplayCountsTowardTestLimit describes whether calls replayed from the corpus when fuzzing begins count
+	// toward the TestLimit. If false, only calls in newly generated call sequences are counted.
+	CorpusReplayCountsTowardTestLimit bool `json:"corpusReplayCountsTowardTestLimit"`
+
+	// CorpusReplayLimit describes a threshold for the number of calls to replay from the corpus when fuzzing begins.
+	// Once reached, any remaining corpus call sequences are deferred (not replayed) and generation of new call
+	// sequences begins. A zero value indicates the corpus replay limit should not be enforced.
+	CorpusReplayLimit uint64 `json:"corpusReplayLimit"`
+
+	// CorpusSlowReplayThreshold describes a threshold, in milliseconds, for the time taken to replay a corpus call
+	// sequence when fuzzing begins. Call sequences which exceed it have their weight for selection in mutations reduced
+	// in proportion to how far they exceeded it. A zero value indicates call sequences should not be deprioritized.
+	CorpusSlowReplayThreshold uint64 `json:"corpusSlowReplayThreshold"`
+
 	// ShrinkLimit describes a threshold for the iterations (call sequence tests) which shrinking should perform.
 	ShrinkLimit uint64 `json:"shrinkLimit"`
 
+	// MaxShrinkingWorkers describes the maximum number of workers which may shrink call sequences concurrently. Shrink
+	// requests beyond this limit are queued and shrunk once a worker finishes shrinking, while the worker which made
+	// them continues fuzzing. A zero value indicates half of the workers (at least one) may shrink concurrently.
+	MaxShrinkingWorkers int `json:"maxShrinkingWorkers"`
+
 	// CallSequenceLength describes the maximum length a transaction sequence can be generated as.
 	CallSequenceLength int `json:"callSequenceLength"`
 
+	// AdaptiveSequenceLength describes the configuration used to periodically adjust the length of generated call
+	// sequences, based on the call sequence lengths at which new coverage and failures are discovered.
+	AdaptiveSequenceLength AdaptiveSequenceLengthConfig `json:"adaptiveSequenceLength"`
+
+	// StuckCampaignDetection describes the configuration used to detect fuzzing campaigns in which nearly every
+	// generated call reverts, which typically indicates the target contracts were not deployed or set up correctly.
+	StuckCampaignDetection StuckCampaignDetectionConfig `json:"stuckCampaignDetection"`
+
+	// WorkerWatchdog describes the configuration used to detect workers which stopped making progress (e.g. stuck in
+	// an EVM execution or an event handler) while the rest of the campaign continues, and optionally recreate them.
+	WorkerWatchdog WorkerWatchdogConfig `json:"workerWatchdog"`
+
+	// WorkerProcesses describes the configuration used to run each worker in a separate OS process, so a worker which
+	// crashes (e.g. due to a panic or running out of memory) does not bring down the fuzzing campaign.
+	WorkerProcesses WorkerProcessesConfig `json:"workerProcesses"`
+
+	// SubscriberErrorPolicy describes how errors returned by non-critical event subscribers (e.g. supplementary test
+	// case providers, or subscribers registered by users of the fuzzer as a library) are handled. It is one of
+	// "failFast" (return the error, stopping the worker or fuzzer which published the event) or "logAndContinue" (log
+	// the error and continue, summarizing suppressed errors when the campaign ends). Errors returned by critical
+	// subscribers, such as the corpus and coverage tracking, are always returned.
+	SubscriberErrorPolicy string `json:"subscriberErrorPolicy"`
+
+	// ContractMetricsInterval describes how often a table describing how thoroughly each contract was exercised is
+	// printed, in periodic metric updates. The table is printed every ContractMetricsInterval updates. Providing a zero
+	// value disables the table.
+	ContractMetricsInterval int `json:"contractMetricsInterval"`
+
 	// CorpusDirectory describes the name for the folder that will hold the corpus and the coverage files. If empty,
 	// the in-memory corpus will be used, but not flush to disk.
 	CorpusDirectory string `json:"corpusDirectory"`
 
+	// CorpusDropOutdatedCalls describes whether calls in corpus call sequences which reference outdated contract ABIs
+	// (e.g. a method which no longer exists, or arguments which no longer decode) should be dropped from their call
+	// sequence when the corpus is loaded. If false, call sequences containing such calls are disabled entirely.
+	CorpusDropOutdatedCalls bool `json:"corpusDropOutdatedCalls"`
+
+	// CorpusFingerprintMismatch describes how a corpus recorded with a different campaign configuration is handled,
+	// as detected by comparing the fingerprint stored in the corpus directory to the current one. It is one of "warn"
+	// (report the differences and continue), "revalidate" (report the differences, and remove corpus call sequences
+	// which fail to replay), or "refuse" (report the differences and refuse to start).
+	CorpusFingerprintMismatch string `json:"corpusFingerprintMismatch"`
+
+	// CorpusElementMetadata describes whether metadata describing each call of a call sequence (e.g. the contract and
+	// method it targeted, whether it reverted, and the gas it used) should be recorded alongside the call sequence when
+	// it is added to the corpus. The metadata is intended for external analysis, and is not used by the fuzzer.
+	CorpusElementMetadata bool `json:"corpusElementMetadata"`
+
+	// CorpusTagAllowlist describes the tags a corpus call sequence must have at least one of to be mutated. Tags are
+	// derived from the names of the contracts a call sequence calls when it is added to the corpus, and may be replaced
+	// in its metadata by external tooling. Call sequences without an allowed tag are still replayed for coverage. If
+	// empty, every call sequence is mutated.
+	CorpusTagAllowlist []string `json:"corpusTagAllowlist"`
+
+	// CorpusFlushInterval describes the interval in milliseconds at which call sequences added to the corpus are
+	// written to disk in the background. Call sequences are also written early if many are pending a write.
+	CorpusFlushInterval int `json:"corpusFlushInterval"`
+
+	// SynchronousCorpusWrites describes whether call sequences added to the corpus are written to disk immediately by
+	// the worker which added them, rather than in the background. This prioritizes durability over throughput.
+	SynchronousCorpusWrites bool `json:"synchronousCorpusWrites"`
+
 	// CoverageEnabled describes whether to use coverage-guided fuzzing
 	CoverageEnabled bool `json:"coverageEnabled"`
 
+	// InitCoverageEnabled describes whether coverage should be collected for init (constructor) bytecode executed
+	// while fuzzing, in addition to runtime bytecode. Disabling it reduces the cost of tracing contract deployments.
+	InitCoverageEnabled bool `json:"initCoverageEnabled"`
+
 	// LiveReport enables periodic generation of coverage reports during fuzzing
 	LiveReport bool `json:"liveReport"`
 
 	// LiveReportInterval is the interval in seconds between live coverage report generation
 	LiveReportInterval int `json:"liveReportInterval"`
 
-	// CoverageFormats indicate which reports to generate: "lcov" and "html" are supported.
+	// CoverageFormats indicate which reports to generate: "lcov", "html", and "json" are supported.
 	CoverageFormats []string `json:"coverageFormats"`
 
+	// CoverageBasePath describes the path which source file paths are made relative to in the JSON coverage report,
+	// so that reports generated on different machines are comparable. If empty, source file paths are not modified.
+	CoverageBasePath string `json:"coverageBasePath"`
+
+	// ExcludeSetupCoverage describes whether lines which were only covered while deploying contracts and setting up
+	// the chain prior to fuzzing are excluded from the covered line counts and percentages in coverage reports.
+	ExcludeSetupCoverage bool `json:"excludeSetupCoverage"`
+
+	// CoverageExclusions describes source file paths, directories, or glob patterns, relative to the working
+	// directory, whose source files are omitted from coverage reports.
+	CoverageExclusions []string `json:"coverageExclusions"`
+
+	// CoverageSourceRemappings describes source path prefixes which are remapped to others when reading source files
+	// for coverage reports, and when reporting their paths, so that reports can be generated for artifacts compiled
+	// under a different root directory. If multiple prefixes match a source path, the longest one is used.
+	CoverageSourceRemappings []types.SourcePathRemapping `json:"coverageSourceRemappings"`
+
+	// CoverageHTMLTemplate describes the path of a custom Go HTML template the HTML coverage report is rendered with.
+	// If empty, the default template is used.
+	CoverageHTMLTemplate string `json:"coverageHTMLTemplate"`
+
+	// ResultsPath describes the path of a file the results of the campaign are written to as JSON when fuzzing stops,
+	// so they can be consumed by other tooling. If empty, the results are not written.
+	ResultsPath string `json:"resultsPath"`
+
+	// SARIFPath describes the path of a file the test results of the campaign are written to as a SARIF log when
+	// fuzzing stops, so they can be displayed by tools which support the format. If empty, no SARIF log is written.
+	SARIFPath string `json:"sarifPath"`
+
 	// TargetContracts are the target contracts for fuzz testing
 	TargetContracts []string `json:"targetContracts"`
 
@@ -82,17 +211,29 @@ type FuzzingConfig struct {
 	// TargetContracts
 	TargetContractsBalances []*ContractBalance `json:"targetContractsBalances"`
 
-	// ConstructorArgs holds the constructor arguments for TargetContracts deployments. It is available via the project
-	// configuration
+	// ConstructorArgs holds the constructor arguments for TargetContracts and PredeployedContracts deployments. It is
+	// available via the project configuration
 	ConstructorArgs map[string]map[string]any `json:"constructorArgs"`
 
+	// DeploymentValues maps contract names to the amount of wei that should be sent to their constructor during
+	// deployment. If provided for a contract, it takes precedence over any value in TargetContractsBalances.
+	DeploymentValues map[string]*ContractBalance `json:"deploymentValues"`
+
 	// DeployerAddress describe the account address to be used to deploy contracts.
 	DeployerAddress string `json:"deployerAddress"`
 
+	// ContractDeployers maps contract names to the account address which should deploy them, for contracts which
+	// should not be deployed by DeployerAddress.
+	ContractDeployers map[string]string `json:"contractDeployers"`
+
 	// SenderAddresses describe a set of account addresses to be used to send state-changing txs (calls) in fuzzing
 	// campaigns.
 	SenderAddresses []string `json:"senderAddresses"`
 
+	// AddressLabels maps account or contract addresses to the labels displayed alongside them in execution traces,
+	// call sequences, and test results, overriding the labels given to senders, the deployer, and deployed contracts.
+	AddressLabels map[string]string `json:"addressLabels"`
+
 	// MaxBlockNumberDelay describes the maximum distance in block numbers the fuzzer will use when generating blocks
 	// compared to the previous.
 	MaxBlockNumberDelay uint64 `json:"blockNumberDelayMax"`
@@ -101,6 +242,31 @@ type FuzzingConfig struct {
 	// compared to the previous.
 	MaxBlockTimestampDelay uint64 `json:"blockTimestampDelayMax"`
 
+	// BlockDelayMode describes how the block number and timestamp delays of generated calls are generated. Values
+	// include "correlated" (generate them jointly, so time advances by at least one second per block) and "legacy"
+	// (generate them independently, capping the block number delay by the timestamp delay).
+	BlockDelayMode string `json:"blockDelayMode"`
+
+	// EmptyBlockProbability describes the probability that the fuzzer mines empty "filler" blocks before a generated
+	// call, so logic which depends on blocks being produced without interacting with it is exercised. A zero value
+	// disables empty blocks.
+	EmptyBlockProbability float32 `json:"emptyBlockProbability"`
+
+	// MaxEmptyBlocks describes the maximum amount of empty blocks the fuzzer mines before a generated call.
+	MaxEmptyBlocks uint64 `json:"emptyBlocksMax"`
+
+	// GeneratePrevrandao describes whether the fuzzer should generate the prevrandao value of each block it creates to
+	// include a generated call, rather than using the value the chain is configured to derive. Generated values are
+	// derived from the fuzzer's random provider, and are stored with call sequences in the corpus so they are
+	// reproduced when replayed.
+	GeneratePrevrandao bool `json:"generatePrevrandao"`
+
+	// PreserveFragmentDelays describes whether corpus mutations which recombine a fragment sliced from the middle of a
+	// corpus call sequence should adjust the block number and timestamp delays of the fragment's first call to carry
+	// those of the calls dropped before it, capped by MaxBlockNumberDelay and MaxBlockTimestampDelay. This approximates
+	// the schedule the fragment originally executed with, rather than recombining calls verbatim.
+	PreserveFragmentDelays bool `json:"preserveFragmentDelays"`
+
 	// BlockGasLimit describes the maximum amount of gas that can be used in a block by transactions. This defines
 	// limits for how many transactions can be included per block.
 	BlockGasLimit uint64 `json:"blockGasLimit"`
@@ -108,6 +274,44 @@ type FuzzingConfig struct {
 	// TransactionGasLimit describes the maximum amount of gas that will be used by the fuzzer generated transactions.
 	TransactionGasLimit uint64 `json:"transactionGasLimit"`
 
+	// MaxTransactionValue describes the maximum amount of wei which fuzzer generated transactions to payable methods
+	// will send. Values are additionally limited to the balance their sender can afford. If nil, values are only
+	// limited by the sender's balance.
+	MaxTransactionValue *ContractBalance `json:"maxTransactionValue"`
+
+	// ChainContextValues describes whether values derived from the chain being fuzzed, such as the current block
+	// number and timestamp and offsets from them, should be refreshed into the value set before each call is
+	// generated, so arguments which are compared against them (e.g. deadlines) are generated more often.
+	ChainContextValues bool `json:"chainContextValues"`
+
+	// PreimageHashesMax describes the maximum amount of keccak256 hashes derived from the strings and bytes harvested
+	// from the compilation targets, and from well-known preimages such as role identifiers, which are added to the
+	// value set so bytes32 arguments compared against them can be generated. A zero value disables derived hashes.
+	PreimageHashesMax int `json:"preimageHashesMax"`
+
+	// CalldataProbeProbability describes the probability that the ABI encoded call data of a generated call is
+	// malformed (truncated, extended with random bytes, or with a dynamic argument's offset corrupted) while retaining
+	// its method selector, to probe how contracts decode malformed call data. A zero value disables such probes.
+	CalldataProbeProbability float32 `json:"calldataProbeProbability"`
+
+	// OutputBindingProbability describes the probability that an argument of a generated call is bound to a return
+	// value of the same type of the call executed prior to it, such that the argument receives the exact value returned
+	// (e.g. an identifier returned by one call and consumed by the next). A zero value disables such bindings.
+	OutputBindingProbability float32 `json:"outputBindingProbability"`
+
+	// AccessControlRevertThreshold describes the count of consecutive calls from a sender to a method which must revert
+	// with an access control error (e.g. "Ownable: caller is not the owner" or AccessControlUnauthorizedAccount) before
+	// the sender is selected less often to call the method. A zero value disables this, selecting senders uniformly.
+	AccessControlRevertThreshold int `json:"accessControlRevertThreshold"`
+
+	// ParameterNameHints describes the configuration used to bias generated method arguments by the names of their
+	// parameters.
+	ParameterNameHints ParameterNameHintsConfig `json:"parameterNameHints"`
+
+	// ValueMutation describes the configuration used to mutate the arguments of calls in call sequences derived from
+	// the corpus.
+	ValueMutation ValueMutationConfig `json:"valueMutation"`
+
 	// Testing describes the configuration used for different testing strategies.
 	Testing TestingConfig `json:"testing"`
 
@@ -115,6 +319,12 @@ type FuzzingConfig struct {
 	TestChainConfig config.TestChainConfig `json:"chainConfig"`
 }
 
+// InitialAccountBalance returns the amount of wei each sender and deployer account is funded with in the genesis block
+// of the test chain.
+func InitialAccountBalance() *big.Int {
+	return new(big.Int).Div(abi.MaxInt256, big.NewInt(2))
+}
+
 // ContractBalance wraps big.Int to provide custom JSON marshaling/unmarshaling
 // for contract balance values in different numeric formats
 type ContractBalance struct {
@@ -124,7 +334,8 @@ type ContractBalance struct {
 // UnmarshalJSON parses JSON data into big.Int from empty strings, hex ("0x"),
 // scientific notation (e/E), and base-10 formats
 func (cb *ContractBalance) UnmarshalJSON(data []byte) error {
-	s := string(data)
+	// Values may be provided as JSON numbers or strings
+	s := strings.Trim(string(data), "\"")
 
 	// Empty string handling
 	if s == "" {
@@ -165,8 +376,146 @@ func (cb ContractBalance) MarshalJSON() ([]byte, error) {
 	return []byte(cb.Int.String()), nil
 }
 
+// AdaptiveSequenceLengthConfig describes the configuration options used to adjust the length of generated call
+// sequences while fuzzing. Generated call sequences start at FuzzingConfig.CallSequenceLength calls, and their length
+// is periodically adjusted within the configured bounds to fit the call sequence lengths at which new coverage and
+// failures were recently discovered.
+type AdaptiveSequenceLengthConfig struct {
+	// Enabled describes whether the length of generated call sequences should be adjusted while fuzzing.
+	Enabled bool `json:"enabled"`
+
+	// MinLength describes the minimum length generated call sequences can be adjusted to.
+	MinLength int `json:"minLength"`
+
+	// MaxLength describes the maximum length generated call sequences can be adjusted to.
+	MaxLength int `json:"maxLength"`
+
+	// AdjustmentInterval describes the interval, in seconds, at which the length of generated call sequences is
+	// adjusted.
+	AdjustmentInterval int `json:"adjustmentInterval"`
+}
+
+// StuckCampaignDetectionConfig describes the configuration options used to detect fuzzing campaigns in which nearly
+// every generated call reverts. Once the configured number of calls were tested, the campaign is considered stuck if
+// the rate of calls which did not revert is below the configured threshold, and no call sequence which did not revert
+// was added to the corpus.
+type StuckCampaignDetectionConfig struct {
+	// CallThreshold describes the amount of calls which must be tested before the campaign is checked. Providing a zero
+	// value will disable stuck campaign detection.
+	CallThreshold uint64 `json:"callThreshold"`
+
+	// SuccessRateThreshold describes the fraction of tested calls which must not revert for the campaign to not be
+	// considered stuck.
+	SuccessRateThreshold float64 `json:"successRateThreshold"`
+
+	// AbortCampaign describes whether the fuzzing campaign should be stopped if it is considered stuck, rather than
+	// only reporting it.
+	AbortCampaign bool `json:"abortCampaign"`
+}
+
+// WorkerWatchdogConfig describes the configuration options used to detect stalled workers. A worker is considered
+// stalled if it did not execute a call, while fuzzing or shrinking, within the configured timeout.
+type WorkerWatchdogConfig struct {
+	// StallTimeout describes the amount of seconds a worker may go without executing a call before it is considered
+	// stalled. Providing a zero value will disable the watchdog.
+	StallTimeout int `json:"stallTimeout"`
+
+	// RecreateWorkers describes whether stalled workers should be abandoned and recreated, rather than only reported.
+	RecreateWorkers bool `json:"recreateWorkers"`
+}
+
+// WorkerProcessesConfig describes the configuration options used to run workers in separate OS processes.
+type WorkerProcessesConfig struct {
+	// Enabled describes whether each worker should run in a separate OS process, rather than in a goroutine of the
+	// fuzzer's process.
+	Enabled bool `json:"enabled"`
+
+	// MaxRespawns describes the maximum amount of times worker processes which crashed are respawned over the course
+	// of the fuzzing campaign. Once exceeded, crashed worker processes are no longer replaced.
+	MaxRespawns int `json:"maxRespawns"`
+}
+
+// ParameterNameHintsConfig describes the configuration options used to bias generated method arguments by the names of
+// their parameters, e.g. generating timestamps near the current block timestamp for a parameter named "deadline".
+type ParameterNameHintsConfig struct {
+	// Enabled describes whether parameter names should be used to bias generated method arguments.
+	Enabled bool `json:"enabled"`
+
+	// Probability describes the probability that an argument whose parameter name matches a hint is generated using
+	// the hint, rather than as usual.
+	Probability float32 `json:"probability"`
+
+	// Patterns maps hint kinds to additional regular expressions which parameter names are matched against
+	// (case-insensitively) to determine whether the hint applies to them, extending the default patterns.
+	Patterns map[string][]string `json:"patterns"`
+
+	// DisabledHints is a list of hint kinds which should not be applied.
+	DisabledHints []string `json:"disabledHints"`
+}
+
+// ValueMutationConfig describes the configuration options used to mutate the arguments of calls in call sequences
+// derived from the corpus.
+type ValueMutationConfig struct {
+	// MinMutatedLeaves describes the minimum amount of leaf values (arguments, or values within tuple and array
+	// arguments, which are not tuples or arrays themselves) selected for mutation when a call is mutated. It is capped
+	// at the amount of leaf values in the call, and at least one is always selected.
+	MinMutatedLeaves int `json:"minMutatedLeaves"`
+
+	// MaxMutatedLeaves describes the maximum amount of leaf values selected for mutation when a call is mutated. A
+	// zero value places no limit.
+	MaxMutatedLeaves int `json:"maxMutatedLeaves"`
+
+	// IntegerOperatorWeights describes the weights of the operators applied in each round of integer mutation.
+	IntegerOperatorWeights IntegerMutationWeightsConfig `json:"integerOperatorWeights"`
+
+	// BytesOperatorWeights describes the weights of the operators applied in each round of byte array mutation.
+	BytesOperatorWeights BytesMutationWeightsConfig `json:"bytesOperatorWeights"`
+}
+
+// IntegerMutationWeightsConfig describes the weights of the operators applied in each round of integer mutation.
+type IntegerMutationWeightsConfig struct {
+	// Arithmetic describes the weight of adding, subtracting, multiplying, dividing, or modulo dividing the integer
+	// by a value from the value set or a bound of its type.
+	Arithmetic uint64 `json:"arithmetic"`
+
+	// BitFlip describes the weight of flipping a single random bit of the integer.
+	BitFlip uint64 `json:"bitFlip"`
+
+	// ByteSwap describes the weight of swapping two random bytes of the integer.
+	ByteSwap uint64 `json:"byteSwap"`
+
+	// Endianness describes the weight of reversing the order of the bytes of the integer.
+	Endianness uint64 `json:"endianness"`
+
+	// SignFlip describes the weight of negating the integer.
+	SignFlip uint64 `json:"signFlip"`
+
+	// OffByOne describes the weight of adding or subtracting one from the integer.
+	OffByOne uint64 `json:"offByOne"`
+}
+
+// BytesMutationWeightsConfig describes the weights of the operators applied in each round of byte array mutation.
+type BytesMutationWeightsConfig struct {
+	// RandomByte describes the weight of replacing, inserting, or removing a random byte.
+	RandomByte uint64 `json:"randomByte"`
+
+	// BitFlip describes the weight of flipping a single random bit.
+	BitFlip uint64 `json:"bitFlip"`
+
+	// ByteSwap describes the weight of swapping two random bytes.
+	ByteSwap uint64 `json:"byteSwap"`
+
+	// InterestingBytes describes the weight of copying a byte array from the value set over a random position.
+	InterestingBytes uint64 `json:"interestingBytes"`
+}
+
 // TestingConfig describes the configuration options used for testing
 type TestingConfig struct {
+	// Enabled describes whether call sequences should be tested at all. If disabled, the fuzzer runs in exploration
+	// mode: no test case providers are registered, no call sequence testing hooks are invoked, no shrinking occurs, and
+	// the campaign is purely coverage-guided corpus building.
+	Enabled bool `json:"enabled"`
+
 	// StopOnFailedTest describes whether the fuzzing.Fuzzer should stop after detecting the first failed test.
 	StopOnFailedTest bool `json:"stopOnFailedTest"`
 
@@ -174,14 +523,35 @@ type TestingConfig struct {
 	// to determine which contract a deployed contract is.
 	StopOnFailedContractMatching bool `json:"stopOnFailedContractMatching"`
 
+	// ContractMatchingMode describes how the bytecode of deployed contracts is matched to contract definitions.
+	// "strict" requires the bytecode to be identical, aside from constructor arguments. "ignoreMetadata" additionally
+	// matches bytecode which only differs in the bytecode hashes of its embedded contract metadata.
+	ContractMatchingMode string `json:"contractMatchingMode"`
+
 	// StopOnNoTests describes whether the fuzzing.Fuzzer should stop the fuzzer from starting if no tests (property,
 	// assertion, optimization, custom) are found.
 	StopOnNoTests bool `json:"stopOnNoTests"`
 
+	// FailOnMalformedTestMethods describes whether the fuzzing.Fuzzer should stop the fuzzer from starting if a method
+	// named like a property or optimization test does not have the shape of one (e.g. it takes arguments or returns
+	// an unexpected type). If false, a warning is logged for each such method instead.
+	FailOnMalformedTestMethods bool `json:"failOnMalformedTestMethods"`
+
 	// TestAllContracts indicates whether all contracts should be tested (including dynamically deployed ones), rather
 	// than just the contracts specified in the project configuration's deployment order.
 	TestAllContracts bool `json:"testAllContracts"`
 
+	// DynamicDeploymentTargetLimit describes the maximum number of dynamically deployed contracts of each contract
+	// definition which are registered as fuzzing targets when TestAllContracts is enabled. Contracts deployed beyond
+	// this limit are still tracked and their addresses used as argument values, but their methods are not called
+	// directly. A zero value indicates no limit should be enforced.
+	DynamicDeploymentTargetLimit int `json:"dynamicDeploymentTargetLimit"`
+
+	// WaitForDynamicTargets describes whether workers which have no methods to call once the initial deployments are
+	// complete should test corpus call sequences until they deploy contracts which add methods to call, rather than
+	// stopping the fuzzer with an error describing why every candidate method was excluded.
+	WaitForDynamicTargets bool `json:"waitForDynamicTargets"`
+
 	// TestViewMethods dictates whether constant/pure/view methods should be called and tested.
 	TestViewMethods bool `json:"testViewMethods"`
 
@@ -190,6 +560,34 @@ type TestingConfig struct {
 	// even if this option is not enabled.
 	TraceAll bool `json:"traceAll"`
 
+	// TraceDepthLimit describes the maximum depth of call frames recorded in an execution trace attached to a call,
+	// where the call itself has a depth of zero. Deeper call frames are omitted, and the omission is marked in the
+	// trace. A zero value indicates no limit should be enforced.
+	TraceDepthLimit int `json:"traceDepthLimit"`
+
+	// TraceOperationLimit describes the maximum amount of call frames and events recorded in an execution trace
+	// attached to a call. Further call frames and events are omitted, and the omission is marked in the trace. A zero
+	// value indicates no limit should be enforced.
+	TraceOperationLimit int `json:"traceOperationLimit"`
+
+	// StorageDiffs describes whether the report of a failed property or assertion test should describe the changes the
+	// shrunken call sequence made to storage. Changed storage slots are attributed to variables where the storage layout
+	// of the contract was provided by the compilation platform, and are otherwise reported as raw values.
+	StorageDiffs bool `json:"storageDiffs"`
+
+	// DeduplicateFailures describes whether a failure which was already discovered, e.g. the same property test
+	// violated by a different call sequence, should be counted rather than shrunk and reported again.
+	DeduplicateFailures bool `json:"deduplicateFailures"`
+
+	// ConfirmFailures describes whether a call sequence which caused a failure should be replayed from the base state
+	// before it is shrunk, to confirm the failure reproduces. Failures which do not reproduce are logged as
+	// unreproducible, along with the receipts of both executions.
+	ConfirmFailures bool `json:"confirmFailures"`
+
+	// ReportUnconfirmedFailures describes whether failures which did not reproduce when ConfirmFailures is enabled
+	// should still be shrunk and reported. If false, they are discarded after being logged.
+	ReportUnconfirmedFailures bool `json:"reportUnconfirmedFailures"`
+
 	// AssertionTesting describes the configuration used for assertion testing.
 	AssertionTesting AssertionTestingConfig `json:"assertionTesting"`
 
@@ -199,6 +597,18 @@ type TestingConfig struct {
 	// OptimizationTesting describes the configuration used for optimization testing.
 	OptimizationTesting OptimizationTestingConfig `json:"optimizationTesting"`
 
+	// ReentrancyTesting describes the configuration used for reentrancy testing.
+	ReentrancyTesting ReentrancyTestingConfig `json:"reentrancyTesting"`
+
+	// UntrustedDelegateCallTesting describes the configuration used for untrusted delegate call testing.
+	UntrustedDelegateCallTesting UntrustedDelegateCallTestingConfig `json:"untrustedDelegateCallTesting"`
+
+	// TokenTesting describes the configuration used for testing invariants of ERC20 and ERC721 token contracts.
+	TokenTesting TokenTestingConfig `json:"tokenTesting"`
+
+	// ScriptedOracleTesting describes the configuration used for testing calls against scripted oracles.
+	ScriptedOracleTesting ScriptedOracleTestingConfig `json:"scriptedOracleTesting"`
+
 	// TargetFunctionSignatures is a list function signatures call the fuzzer should exclusively target by omitting calls to other signatures.
 	// The signatures should specify the contract name and signature in the ABI format like `Contract.func(uint256,bytes32)`.
 	TargetFunctionSignatures []string `json:"targetFunctionSignatures"`
@@ -206,6 +616,16 @@ type TestingConfig struct {
 	// ExcludeFunctionSignatures is a list of function signatures that will be excluded from call sequences.
 	// The signatures should specify the contract name and signature in the ABI format like `Contract.func(uint256,bytes32)`.
 	ExcludeFunctionSignatures []string `json:"excludeFunctionSignatures"`
+
+	// ExcludeContracts is a list of contract names which are deployed and can be interacted with by other contracts,
+	// but whose methods are never called directly in call sequences and whose tests are not registered.
+	ExcludeContracts []string `json:"excludeContracts"`
+
+	// SpecContracts is a list of contract names which are deployed after the target contracts to define tests over
+	// them, e.g. by referencing the target contracts' addresses through constructor arguments. Their tests are
+	// registered, but their remaining methods are never called directly in call sequences, and their addresses are
+	// not used as values in generated calls.
+	SpecContracts []string `json:"specContracts"`
 }
 
 // Validate validates that the TestingConfig meets certain requirements.
@@ -215,6 +635,31 @@ func (testCfg *TestingConfig) Validate() error {
 		return errors.New("project configuration must specify only one of blacklist or whitelist at a time")
 	}
 
+	// Verify the contract matching mode is a known one.
+	if testCfg.ContractMatchingMode != "strict" && testCfg.ContractMatchingMode != "ignoreMetadata" {
+		return fmt.Errorf("project configuration must specify a valid contract matching mode (strict, ignoreMetadata): %s", testCfg.ContractMatchingMode)
+	}
+
+	// Verify we do not expect to stop on failed tests if testing is disabled, as no tests can fail.
+	if !testCfg.Enabled && testCfg.StopOnFailedTest {
+		return errors.New("project configuration must not enable stopping on failed tests if testing is disabled (exploration mode)")
+	}
+
+	// Verify the dynamic deployment target limit is non-negative.
+	if testCfg.DynamicDeploymentTargetLimit < 0 {
+		return errors.New("project configuration must specify a non-negative dynamic deployment target limit")
+	}
+
+	// Verify the execution trace limits are non-negative.
+	if testCfg.TraceDepthLimit < 0 || testCfg.TraceOperationLimit < 0 {
+		return errors.New("project configuration must specify non-negative execution trace limits")
+	}
+
+	// Verify the view method check cadence is non-negative.
+	if testCfg.AssertionTesting.CheckViewMethodsEvery < 0 {
+		return errors.New("project configuration must specify a non-negative cadence for checking view methods")
+	}
+
 	// Verify property testing fields.
 	if testCfg.PropertyTesting.Enabled {
 		// Test prefixes must be supplied if property testing is enabled.
@@ -222,6 +667,9 @@ func (testCfg *TestingConfig) Validate() error {
 			return errors.New("project configuration must specify test name prefixes if property testing is enabled")
 		}
 	}
+	if !slices.Contains([]string{"fail", "warn", "ignore"}, testCfg.PropertyTesting.RevertHandling) {
+		return fmt.Errorf("project configuration must specify a property test revert handling of fail, warn, or ignore: %s", testCfg.PropertyTesting.RevertHandling)
+	}
 
 	if testCfg.OptimizationTesting.Enabled {
 		// Test prefixes must be supplied if optimization testing is enabled.
@@ -239,6 +687,22 @@ func (testCfg *TestingConfig) Validate() error {
 		}
 	}
 
+	// Verify token testing fields.
+	if testCfg.TokenTesting.TransferFeeTolerance < 0 || testCfg.TokenTesting.TransferFeeTolerance > 1 {
+		return errors.New("project configuration must specify a transfer fee tolerance between 0 and 1 for token testing")
+	}
+	if _, err := utils.HexStringsToAddresses(testCfg.TokenTesting.MintAddresses); err != nil {
+		return errors.New("project configuration must specify only well-formed mint address(es) for token testing")
+	}
+	if _, err := utils.HexStringsToAddresses(testCfg.TokenTesting.BurnAddresses); err != nil {
+		return errors.New("project configuration must specify only well-formed burn address(es) for token testing")
+	}
+
+	// Scripts must be supplied if scripted oracle testing is enabled.
+	if testCfg.ScriptedOracleTesting.Enabled && len(testCfg.ScriptedOracleTesting.Scripts) == 0 {
+		return errors.New("project configuration must specify scripts if scripted oracle testing is enabled")
+	}
+
 	return nil
 }
 
@@ -249,6 +713,34 @@ type AssertionTestingConfig struct {
 
 	// PanicCodeConfig describes the various panic codes that can be enabled and be treated as a "failing case"
 	PanicCodeConfig PanicCodeConfig `json:"panicCodeConfig"`
+
+	// DetectInnerCallPanics describes whether panics originating in inner call frames (calls made by contracts) should
+	// be treated as a "failing case" even if the calling contract handled them (e.g. through try/catch or a low-level
+	// call) and the top-level call did not fail. This requires tracing every call frame's revert data.
+	DetectInnerCallPanics bool `json:"detectInnerCallPanics"`
+
+	// IncludeContracts is a list of contract names which assertion failures are reported for, matched against the
+	// contract in whose code the panic originated. If empty, failures originating in any contract are reported, unless
+	// excluded by ExcludeContracts.
+	IncludeContracts []string `json:"includeContracts"`
+
+	// ExcludeContracts is a list of contract names which assertion failures are not reported for, matched against the
+	// contract in whose code the panic originated (e.g. libraries whose assertions fail by design).
+	ExcludeContracts []string `json:"excludeContracts"`
+
+	// ReportUnresolvedContracts describes whether assertion failures should be reported if IncludeContracts or
+	// ExcludeContracts are specified, but the contract in whose code the panic originated cannot be resolved.
+	ReportUnresolvedContracts bool `json:"reportUnresolvedContracts"`
+
+	// CheckViewMethodsEvery describes the number of calls in a call sequence after which every view method to test is
+	// explicitly called and checked for assertion failures, rather than waiting for it to be randomly selected. View
+	// methods are only tested if TestingConfig.TestViewMethods is enabled. If zero, view methods are not explicitly
+	// called.
+	CheckViewMethodsEvery int `json:"checkViewMethodsEvery"`
+
+	// SpecTestPrefixes dictates what method name prefixes will determine if a method of a spec contract is an
+	// assertion test. Other methods of spec contracts are never called directly.
+	SpecTestPrefixes []string `json:"specTestPrefixes"`
 }
 
 // PanicCodeConfig describes the various panic codes that can be enabled and be treated as a failing assertion test
@@ -291,6 +783,16 @@ type PropertyTestingConfig struct {
 
 	// TestPrefixes dictates what method name prefixes will determine if a contract method is a property test.
 	TestPrefixes []string `json:"testPrefixes"`
+
+	// RevertHandling describes how a property test method which reverts when evaluated, rather than returning a
+	// result, is handled: "fail" fails the test, "warn" warns once per property test and continues, and "ignore"
+	// continues silently. Property tests which reverted are listed in the campaign summary regardless.
+	RevertHandling string `json:"revertHandling"`
+
+	// CheckAtSequenceEnd describes whether property tests are only checked once a call sequence has completed, rather
+	// than after every call. This is suited for properties which only need to hold at the end of a sequence, and
+	// avoids the cost of checking them after every call.
+	CheckAtSequenceEnd bool `json:"checkAtSequenceEnd"`
 }
 
 // OptimizationTestingConfig describes the configuration options used for optimization testing
@@ -302,6 +804,75 @@ type OptimizationTestingConfig struct {
 	TestPrefixes []string `json:"testPrefixes"`
 }
 
+// ReentrancyTestingConfig describes the configuration options used for reentrancy testing
+type ReentrancyTestingConfig struct {
+	// Enabled describes whether testing is enabled.
+	Enabled bool `json:"enabled"`
+
+	// AllowedReentrancy is a list of contract names, or contract function signatures in the ABI format like
+	// `Contract.func(uint256,bytes32)`, where reentrancy is expected and should not be reported (e.g. token hooks in
+	// mock contracts). Function signatures match either the reentered function, or the function it re-entered.
+	AllowedReentrancy []string `json:"allowedReentrancy"`
+}
+
+// UntrustedDelegateCallTestingConfig describes the configuration options used for untrusted delegate call testing
+type UntrustedDelegateCallTestingConfig struct {
+	// Enabled describes whether testing is enabled.
+	Enabled bool `json:"enabled"`
+
+	// AllowedDelegateCalls is a list of contract names, or contract function signatures in the ABI format like
+	// `Contract.func(uint256,bytes32)`, where delegate calls to addresses provided by the caller are expected and should
+	// not be reported (e.g. a multicall helper). Contract names match the contract whose storage the delegate call
+	// executed against, while function signatures match the function which made the delegate call.
+	AllowedDelegateCalls []string `json:"allowedDelegateCalls"`
+}
+
+// ScriptedOracleTestingConfig describes the configuration options used for testing calls against scripted oracles
+type ScriptedOracleTestingConfig struct {
+	// Enabled describes whether testing is enabled.
+	Enabled bool `json:"enabled"`
+
+	// Scripts is a list of paths to Starlark scripts, each defining a `check(call)` function which is evaluated after
+	// every call in a call sequence and returns whether the call passed, optionally along with a message.
+	Scripts []string `json:"scripts"`
+
+	// MaxExecutionSteps describes the maximum amount of Starlark computation steps a script may execute to check a
+	// single call before it is aborted. If zero, scripts are not limited in steps.
+	MaxExecutionSteps uint64 `json:"maxExecutionSteps"`
+
+	// Timeout describes the maximum wall-clock time, in milliseconds, which a script may spend checking a single call
+	// before it is aborted. If zero, scripts are not limited in time.
+	Timeout uint64 `json:"timeout"`
+}
+
+// TokenTestingConfig describes the configuration options used for testing invariants of ERC20 and ERC721 token
+// contracts
+type TokenTestingConfig struct {
+	// Enabled describes whether testing is enabled.
+	Enabled bool `json:"enabled"`
+
+	// ERC20Contracts is a list of contract names whose deployments should be tested against ERC20 invariants: the
+	// total supply equals the sum of tracked balances, and balances only change as described by Transfer events.
+	ERC20Contracts []string `json:"erc20Contracts"`
+
+	// ERC721Contracts is a list of contract names whose deployments should be tested against ERC721 invariants: each
+	// token is owned by the recipient of its last Transfer event, and balances equal the count of tokens owned.
+	ERC721Contracts []string `json:"erc721Contracts"`
+
+	// MintAddresses is a list of addresses which Transfer events are emitted from when tokens are minted. The zero
+	// address is always treated as a mint address.
+	MintAddresses []string `json:"mintAddresses"`
+
+	// BurnAddresses is a list of addresses which Transfer events are emitted to when tokens are burned. The zero
+	// address is always treated as a burn address.
+	BurnAddresses []string `json:"burnAddresses"`
+
+	// TransferFeeTolerance describes the fraction of the value of an ERC20 transfer, between 0 and 1, which the balances
+	// of its sender and recipient may additionally lose (e.g. to fees charged by fee-on-transfer tokens) without
+	// being reported.
+	TransferFeeTolerance float64 `json:"transferFeeTolerance"`
+}
+
 // LoggingConfig describes the configuration options for logging to console and file
 type LoggingConfig struct {
 	// Level describes whether logs of certain severity levels (eg info, warning, etc.) will be emitted or discarded.
@@ -373,6 +944,23 @@ func (p *ProjectConfig) WriteToFile(path string) error {
 	return nil
 }
 
+// Clone creates a deep copy of the ProjectConfig, so that the copy can be updated without affecting the original
+// (e.g. its slices, maps, and compilation platform configuration).
+// Returns the copy, or an error if one occurs.
+func (p *ProjectConfig) Clone() (*ProjectConfig, error) {
+	// Serialize the configuration and parse it into a new one, which shares no references with the original.
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	var clone ProjectConfig
+	err = json.Unmarshal(b, &clone)
+	if err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
+
 // Validate validates that the ProjectConfig meets certain requirements.
 // Returns an error if one occurs.
 func (p *ProjectConfig) Validate() error {
@@ -387,16 +975,94 @@ func (p *ProjectConfig) Validate() error {
 		return err
 	}
 
+	// Verify that spec contracts are not also target contracts, as they are deployed separately.
+	for _, contractName := range p.Fuzzing.Testing.SpecContracts {
+		if slices.Contains(p.Fuzzing.TargetContracts, contractName) {
+			return fmt.Errorf("project configuration must not specify %s as both a target contract and a spec contract", contractName)
+		}
+	}
+
 	// Verify the worker count is a positive number.
 	if p.Fuzzing.Workers <= 0 {
 		return errors.New("project configuration must specify a positive number for the worker count")
 	}
 
+	// Verify the maximum number of shrinking workers is a non-negative number.
+	if p.Fuzzing.MaxShrinkingWorkers < 0 {
+		return errors.New("project configuration must specify a non-negative number for the maximum shrinking workers")
+	}
+
 	// Verify that the sequence length is a positive number
 	if p.Fuzzing.CallSequenceLength <= 0 {
 		return errors.New("project configuration must specify a positive number for the transaction sequence length")
 	}
 
+	// Verify that the adaptive sequence length bounds contain the sequence length
+	if p.Fuzzing.AdaptiveSequenceLength.Enabled {
+		if p.Fuzzing.AdaptiveSequenceLength.MinLength <= 0 {
+			return errors.New("project configuration must specify a positive number for the minimum adaptive sequence length")
+		}
+		if p.Fuzzing.CallSequenceLength < p.Fuzzing.AdaptiveSequenceLength.MinLength || p.Fuzzing.CallSequenceLength > p.Fuzzing.AdaptiveSequenceLength.MaxLength {
+			return errors.New("project configuration must specify a transaction sequence length between the minimum and maximum adaptive sequence lengths")
+		}
+		if p.Fuzzing.AdaptiveSequenceLength.AdjustmentInterval <= 0 {
+			return errors.New("project configuration must specify a positive number for the adaptive sequence length adjustment interval")
+		}
+	}
+
+	// Verify deterministic fuzzing is bounded by a test limit, and does not adjust the sequence length over time.
+	if p.Fuzzing.Deterministic {
+		if p.Fuzzing.TestLimit == 0 {
+			return errors.New("project configuration must specify a test limit to fuzz deterministically")
+		}
+		if p.Fuzzing.AdaptiveSequenceLength.Enabled {
+			return errors.New("project configuration must not enable an adaptive sequence length to fuzz deterministically")
+		}
+	}
+
+	// Verify the contract metrics interval is non-negative
+	if p.Fuzzing.ContractMetricsInterval < 0 {
+		return errors.New("project configuration must specify a non-negative contract metrics interval")
+	}
+
+	// Verify the stuck campaign success rate threshold is a valid fraction
+	if p.Fuzzing.StuckCampaignDetection.SuccessRateThreshold < 0 || p.Fuzzing.StuckCampaignDetection.SuccessRateThreshold > 1 {
+		return errors.New("project configuration must specify a stuck campaign success rate threshold between 0 and 1")
+	}
+
+	// Verify the block delay mode is a valid one
+	if !slices.Contains([]string{"correlated", "legacy"}, p.Fuzzing.BlockDelayMode) {
+		return fmt.Errorf("project configuration must specify a valid block delay mode (correlated, legacy): %s", p.Fuzzing.BlockDelayMode)
+	}
+
+	// Verify the subscriber error policy is a valid one
+	if !slices.Contains([]string{"failFast", "logAndContinue"}, p.Fuzzing.SubscriberErrorPolicy) {
+		return fmt.Errorf("project configuration must specify a valid subscriber error policy (failFast, logAndContinue): %s", p.Fuzzing.SubscriberErrorPolicy)
+	}
+
+	// Verify the corpus fingerprint mismatch mode is a valid one
+	if !slices.Contains([]string{"warn", "revalidate", "refuse"}, p.Fuzzing.CorpusFingerprintMismatch) {
+		return fmt.Errorf("project configuration must specify a valid corpus fingerprint mismatch mode (warn, revalidate, refuse): %s", p.Fuzzing.CorpusFingerprintMismatch)
+	}
+
+	// Verify the corpus is written to disk at a positive interval, unless it is written synchronously.
+	if !p.Fuzzing.SynchronousCorpusWrites && p.Fuzzing.CorpusFlushInterval <= 0 {
+		return errors.New("project configuration must specify a positive corpus flush interval if corpus writes are not synchronous")
+	}
+
+	// Verify the worker stall timeout is a non-negative number
+	if p.Fuzzing.WorkerWatchdog.StallTimeout < 0 {
+		return errors.New("project configuration must specify a non-negative worker stall timeout")
+	}
+
+	// Verify worker processes are not combined with deterministic fuzzing, and respawns are bounded sensibly.
+	if p.Fuzzing.WorkerProcesses.Enabled && p.Fuzzing.Deterministic {
+		return errors.New("project configuration must not enable worker processes to fuzz deterministically")
+	}
+	if p.Fuzzing.WorkerProcesses.MaxRespawns < 0 {
+		return errors.New("project configuration must specify a non-negative number for the maximum worker process respawns")
+	}
+
 	// Verify the worker reset limit is a positive number
 	if p.Fuzzing.WorkerResetLimit <= 0 {
 		return errors.New("project configuration must specify a positive number for the worker reset limit")
@@ -407,6 +1073,31 @@ func (p *ProjectConfig) Validate() error {
 		return errors.New("project configuration must specify a positive number for the timeout")
 	}
 
+	// Verify the coverage plateau threshold, which can only be tracked if coverage is enabled.
+	if p.Fuzzing.StopOnCoveragePlateau < 0 {
+		return errors.New("project configuration must specify a non-negative number for the coverage plateau threshold")
+	}
+	if p.Fuzzing.StopOnCoveragePlateau > 0 && !p.Fuzzing.CoverageEnabled {
+		return errors.New("project configuration must enable coverage to stop on a coverage plateau")
+	}
+
+	// Verify the chain hard fork is supported, if one is specified.
+	if hardFork := p.Fuzzing.TestChainConfig.HardFork; hardFork != "" && !hardFork.IsSupported() {
+		return fmt.Errorf("project configuration must specify a supported chain hard fork, but %q is not supported", hardFork)
+	}
+
+	// Verify the chain prevrandao mode is supported, if one is specified, and that blocks provide prevrandao values if
+	// they are configured or generated.
+	prevrandaoMode := p.Fuzzing.TestChainConfig.PrevrandaoMode
+	if prevrandaoMode != "" && !prevrandaoMode.IsSupported() {
+		return fmt.Errorf("project configuration must specify a supported chain prevrandao mode, but %q is not supported", prevrandaoMode)
+	}
+	if hardFork := p.Fuzzing.TestChainConfig.HardFork; hardFork != "" && !hardFork.IsMerged() {
+		if (prevrandaoMode != "" && prevrandaoMode != config.PrevrandaoModeParentHash) || p.Fuzzing.GeneratePrevrandao {
+			return errors.New("project configuration must specify a chain hard fork succeeding the merge to configure or generate prevrandao values")
+		}
+	}
+
 	// Verify gas limits are appropriate
 	if p.Fuzzing.BlockGasLimit < p.Fuzzing.TransactionGasLimit {
 		return errors.New("project configuration must specify a block gas limit which is not less than the transaction gas limit")
@@ -415,6 +1106,11 @@ func (p *ProjectConfig) Validate() error {
 		return errors.New("project configuration must specify a block and transaction gas limit which are non-zero")
 	}
 
+	// Verify the maximum transaction value is non-negative
+	if p.Fuzzing.MaxTransactionValue != nil && p.Fuzzing.MaxTransactionValue.Sign() < 0 {
+		return errors.New("project configuration must specify a non-negative maximum transaction value")
+	}
+
 	// Log warning if max block delay is zero
 	if p.Fuzzing.MaxBlockNumberDelay == 0 {
 		logger.Warn("The maximum block number delay is set to zero. Please be aware that transactions will " +
@@ -433,27 +1129,165 @@ func (p *ProjectConfig) Validate() error {
 		return errors.New("project configuration must specify only well-formed sender address(es)")
 	}
 
+	// Verify that labeled addresses are well-formed
+	for addr, label := range p.Fuzzing.AddressLabels {
+		if _, err := utils.HexStringToAddress(addr); err != nil {
+			return fmt.Errorf("project configuration must specify a well-formed address for label %s", label)
+		}
+	}
+
 	// Verify that deployer is a well-formed address
 	if _, err := utils.HexStringToAddress(p.Fuzzing.DeployerAddress); err != nil {
 		return errors.New("project configuration must specify only a well-formed deployer address")
 	}
 
-	// Verify that addresses of predeployed contracts are well-formed
-	for _, addr := range p.Fuzzing.PredeployedContracts {
-		if _, err := utils.HexStringToAddress(addr); err != nil {
+	// Verify that the values sent to constructors during deployment are non-negative and can be funded by their
+	// deployers. Predeployed contracts are only sent a value if one is provided in the deployment values.
+	deploymentValues := make(map[string]*big.Int)
+	for contractName := range p.Fuzzing.PredeployedContracts {
+		deploymentValues[contractName] = new(big.Int)
+	}
+	for i, contractName := range p.Fuzzing.TargetContracts {
+		if len(p.Fuzzing.TargetContractsBalances) > i && p.Fuzzing.TargetContractsBalances[i] != nil {
+			deploymentValues[contractName] = &p.Fuzzing.TargetContractsBalances[i].Int
+		} else {
+			deploymentValues[contractName] = new(big.Int)
+		}
+	}
+	for _, contractName := range p.Fuzzing.Testing.SpecContracts {
+		deploymentValues[contractName] = new(big.Int)
+	}
+	deployerValues := make(map[string]*big.Int)
+	for contractName, value := range deploymentValues {
+		if deploymentValue, ok := p.Fuzzing.DeploymentValues[contractName]; ok && deploymentValue != nil {
+			value = &deploymentValue.Int
+		}
+		if value.Sign() < 0 {
+			return fmt.Errorf("project configuration must specify a non-negative deployment value for %s", contractName)
+		}
+		deployerAddr := p.Fuzzing.DeployerAddress
+		if contractDeployer, ok := p.Fuzzing.ContractDeployers[contractName]; ok {
+			deployerAddr = contractDeployer
+		}
+		if _, ok := deployerValues[deployerAddr]; !ok {
+			deployerValues[deployerAddr] = new(big.Int)
+		}
+		deployerValues[deployerAddr].Add(deployerValues[deployerAddr], value)
+		if deployerValues[deployerAddr].Cmp(InitialAccountBalance()) > 0 {
+			return fmt.Errorf("project configuration specifies deployment values which exceed the balance of deployer %s", deployerAddr)
+		}
+	}
+
+	// Verify that per-contract deployers are well-formed addresses, and warn if they are also used as senders
+	for contractName, addr := range p.Fuzzing.ContractDeployers {
+		deployerAddr, err := utils.HexStringToAddress(addr)
+		if err != nil {
+			return fmt.Errorf("project configuration must specify a well-formed deployer address for %s", contractName)
+		}
+		if senders, err := utils.HexStringsToAddresses(p.Fuzzing.SenderAddresses); err == nil && slices.Contains(senders, deployerAddr) {
+			logger.Warn("The deployer address for ", contractName, " is also a sender address. Please be aware that ",
+				"the fuzzer may call privileged methods of the contract as its deployer.")
+		}
+	}
+
+	// Verify that addresses of predeployed contracts are well-formed, unique, and do not conflict with the accounts
+	// used to deploy contracts (including per-contract deployers) or send transactions.
+	senderAndDeployerAddresses := append([]string{p.Fuzzing.DeployerAddress}, p.Fuzzing.SenderAddresses...)
+	accountAddresses, _ := utils.HexStringsToAddresses(senderAndDeployerAddresses)
+	contractDeployerNames := make(map[common.Address]string)
+	for contractName, addr := range p.Fuzzing.ContractDeployers {
+		deployerAddr, _ := utils.HexStringToAddress(addr)
+		if existingName, ok := contractDeployerNames[deployerAddr]; !ok || contractName < existingName {
+			contractDeployerNames[deployerAddr] = contractName
+		}
+	}
+	predeployedContractNames := make(map[common.Address]string)
+	for contractName, addr := range p.Fuzzing.PredeployedContracts {
+		contractAddr, err := utils.HexStringToAddress(addr)
+		if err != nil {
 			return errors.New("project configuration must specify only well-formed predeployed contract address(es)")
 		}
+		if existingName, ok := predeployedContractNames[contractAddr]; ok {
+			return fmt.Errorf("project configuration specifies the same predeployed contract address for %s and %s", existingName, contractName)
+		}
+		if slices.Contains(accountAddresses, contractAddr) {
+			return fmt.Errorf("project configuration specifies a predeployed contract address for %s which is also a sender or deployer address", contractName)
+		}
+		if deployedContractName, ok := contractDeployerNames[contractAddr]; ok {
+			return fmt.Errorf("project configuration specifies a predeployed contract address for %s which is also the deployer address of %s", contractName, deployedContractName)
+		}
+		predeployedContractNames[contractAddr] = contractName
+	}
+
+	// Ensure the parameter name hint probability is a valid probability
+	if p.Fuzzing.ParameterNameHints.Probability < 0 || p.Fuzzing.ParameterNameHints.Probability > 1 {
+		return errors.New("project configuration must specify a parameter name hint probability between 0 and 1")
+	}
+
+	// Ensure the value mutation intensity is a valid range, and each kind of value has a mutation operator to apply
+	valueMutation := p.Fuzzing.ValueMutation
+	if valueMutation.MinMutatedLeaves < 0 || valueMutation.MaxMutatedLeaves < 0 {
+		return errors.New("project configuration must specify a non-negative minimum and maximum amount of mutated leaf values")
+	}
+	if valueMutation.MaxMutatedLeaves > 0 && valueMutation.MaxMutatedLeaves < valueMutation.MinMutatedLeaves {
+		return errors.New("project configuration must specify a maximum amount of mutated leaf values which is zero or no less than the minimum")
+	}
+	if valueMutation.IntegerOperatorWeights == (IntegerMutationWeightsConfig{}) {
+		return errors.New("project configuration must specify a positive weight for at least one integer mutation operator")
+	}
+	if valueMutation.BytesOperatorWeights == (BytesMutationWeightsConfig{}) {
+		return errors.New("project configuration must specify a positive weight for at least one bytes mutation operator")
+	}
+
+	// Ensure the empty block probability is a valid probability, and empty blocks can be mined if it is non-zero
+	if p.Fuzzing.EmptyBlockProbability < 0 || p.Fuzzing.EmptyBlockProbability > 1 {
+		return errors.New("project configuration must specify an empty block probability between 0 and 1")
+	}
+	if p.Fuzzing.EmptyBlockProbability > 0 && p.Fuzzing.MaxEmptyBlocks == 0 {
+		return errors.New("project configuration must specify a positive maximum amount of empty blocks if the empty block probability is non-zero")
+	}
+
+	// Ensure the maximum amount of preimage hashes is non-negative
+	if p.Fuzzing.PreimageHashesMax < 0 {
+		return errors.New("project configuration must specify a non-negative maximum amount of preimage hashes")
+	}
+
+	// Ensure the calldata probe probability is a valid probability
+	if p.Fuzzing.CalldataProbeProbability < 0 || p.Fuzzing.CalldataProbeProbability > 1 {
+		return errors.New("project configuration must specify a calldata probe probability between 0 and 1")
+	}
+
+	// Ensure the output binding probability is a valid probability
+	if p.Fuzzing.OutputBindingProbability < 0 || p.Fuzzing.OutputBindingProbability > 1 {
+		return errors.New("project configuration must specify an output binding probability between 0 and 1")
 	}
 
-	// The coverage report format must be either "lcov" or "html"
+	// Ensure the access control revert threshold is non-negative
+	if p.Fuzzing.AccessControlRevertThreshold < 0 {
+		return errors.New("project configuration must specify a non-negative access control revert threshold")
+	}
+
+	// The coverage report format must be either "lcov", "html", or "json"
 	if p.Fuzzing.CoverageFormats != nil {
 		for _, report := range p.Fuzzing.CoverageFormats {
-			if report != "lcov" && report != "html" {
-				return fmt.Errorf("project configuration must specify only valid coverage reports (lcov, html): %s", report)
+			if report != "lcov" && report != "html" && report != "json" {
+				return fmt.Errorf("project configuration must specify only valid coverage reports (lcov, html, json): %s", report)
 			}
 		}
 	}
 
+	// Ensure any coverage exclusions are valid glob patterns
+	for _, exclusion := range p.Fuzzing.CoverageExclusions {
+		if _, err := path.Match(exclusion, ""); err != nil {
+			return fmt.Errorf("project configuration must specify valid coverage exclusion patterns: %s", exclusion)
+		}
+	}
+	for _, remapping := range p.Fuzzing.CoverageSourceRemappings {
+		if remapping.From == "" {
+			return errors.New("project configuration must specify a source path prefix to remap for each coverage source remapping")
+		}
+	}
+
 	// Ensure that the log level is a valid one
 	level, err := zerolog.ParseLevel(p.Logging.Level.String())
 	if err != nil || level == zerolog.FatalLevel {
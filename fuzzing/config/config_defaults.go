@@ -40,51 +40,153 @@ func GetDefaultProjectConfig(platform string) (*ProjectConfig, error) {
 	// Create a project configuration
 	projectConfig := &ProjectConfig{
 		Fuzzing: FuzzingConfig{
-			Workers:                 10,
-			WorkerResetLimit:        50,
-			Timeout:                 0,
-			TestLimit:               0,
-			ShrinkLimit:             5_000,
-			CallSequenceLength:      100,
-			TargetContracts:         []string{},
-			TargetContractsBalances: []*ContractBalance{},
-			PredeployedContracts:    map[string]string{},
-			ConstructorArgs:         map[string]map[string]any{},
-			CorpusDirectory:         "",
-			CoverageEnabled:         true,
-			LiveReport:              false,
-			LiveReportInterval:      10,
-			CoverageFormats:         []string{"html", "lcov"},
+			Workers:                           10,
+			WorkerResetLimit:                  50,
+			Timeout:                           0,
+			StopOnCoveragePlateau:             0,
+			Seed:                              0,
+			Deterministic:                     false,
+			TestLimit:                         0,
+			CorpusReplayCountsTowardTestLimit: true,
+			CorpusReplayLimit:                 0,
+			CorpusSlowReplayThreshold:         0,
+			ShrinkLimit:                       5_000,
+			MaxShrinkingWorkers:               0,
+			CallSequenceLength:                100,
+			TargetContracts:                   []string{},
+			TargetContractsBalances:           []*ContractBalance{},
+			PredeployedContracts:              map[string]string{},
+			ContractDeployers:                 map[string]string{},
+			ConstructorArgs:                   map[string]map[string]any{},
+			DeploymentValues:                  map[string]*ContractBalance{},
+			CorpusDirectory:                   "",
+			CorpusDropOutdatedCalls:           false,
+			CorpusFingerprintMismatch:         "warn",
+			CorpusElementMetadata:             false,
+			CorpusTagAllowlist:                []string{},
+			CorpusFlushInterval:               1000,
+			SynchronousCorpusWrites:           false,
+			CoverageEnabled:                   true,
+			InitCoverageEnabled:               true,
+			LiveReport:                        false,
+			LiveReportInterval:                10,
+			CoverageFormats:                   []string{"html", "lcov"},
+			CoverageBasePath:                  "",
+			ExcludeSetupCoverage:              false,
+			CoverageExclusions:                []string{},
+			CoverageSourceRemappings:          []types.SourcePathRemapping{},
+			CoverageHTMLTemplate:              "",
+			ResultsPath:                       "",
+			SARIFPath:                         "",
 			SenderAddresses: []string{
 				"0x10000",
 				"0x20000",
 				"0x30000",
 			},
-			DeployerAddress:        "0x30000",
-			MaxBlockNumberDelay:    60480,
-			MaxBlockTimestampDelay: 604800,
-			BlockGasLimit:          125_000_000,
-			TransactionGasLimit:    12_500_000,
+			AddressLabels:                map[string]string{},
+			DeployerAddress:              "0x30000",
+			MaxBlockNumberDelay:          60480,
+			MaxBlockTimestampDelay:       604800,
+			BlockDelayMode:               "correlated",
+			EmptyBlockProbability:        0,
+			MaxEmptyBlocks:               100,
+			GeneratePrevrandao:           false,
+			PreserveFragmentDelays:       false,
+			BlockGasLimit:                125_000_000,
+			TransactionGasLimit:          12_500_000,
+			MaxTransactionValue:          nil,
+			ChainContextValues:           false,
+			PreimageHashesMax:            1024,
+			CalldataProbeProbability:     0,
+			OutputBindingProbability:     0,
+			AccessControlRevertThreshold: 0,
+			AdaptiveSequenceLength: AdaptiveSequenceLengthConfig{
+				Enabled:            false,
+				MinLength:          10,
+				MaxLength:          200,
+				AdjustmentInterval: 30,
+			},
+			StuckCampaignDetection: StuckCampaignDetectionConfig{
+				CallThreshold:        50_000,
+				SuccessRateThreshold: 0.01,
+				AbortCampaign:        false,
+			},
+			WorkerWatchdog: WorkerWatchdogConfig{
+				StallTimeout:    0,
+				RecreateWorkers: false,
+			},
+			WorkerProcesses: WorkerProcessesConfig{
+				Enabled:     false,
+				MaxRespawns: 10,
+			},
+			SubscriberErrorPolicy:   "failFast",
+			ContractMetricsInterval: 20,
+			ParameterNameHints: ParameterNameHintsConfig{
+				Enabled:       false,
+				Probability:   0.8,
+				Patterns:      map[string][]string{},
+				DisabledHints: []string{},
+			},
+			ValueMutation: ValueMutationConfig{
+				MinMutatedLeaves: 1,
+				MaxMutatedLeaves: 0,
+				IntegerOperatorWeights: IntegerMutationWeightsConfig{
+					Arithmetic: 50,
+					BitFlip:    10,
+					ByteSwap:   5,
+					Endianness: 5,
+					SignFlip:   10,
+					OffByOne:   20,
+				},
+				BytesOperatorWeights: BytesMutationWeightsConfig{
+					RandomByte:       30,
+					BitFlip:          10,
+					ByteSwap:         10,
+					InterestingBytes: 20,
+				},
+			},
 			Testing: TestingConfig{
+				Enabled:                      true,
 				StopOnFailedTest:             true,
 				StopOnFailedContractMatching: false,
+				ContractMatchingMode:         "strict",
 				StopOnNoTests:                true,
+				FailOnMalformedTestMethods:   true,
 				TestViewMethods:              true,
 				TestAllContracts:             false,
+				DynamicDeploymentTargetLimit: 0,
+				WaitForDynamicTargets:        false,
 				TraceAll:                     false,
+				TraceDepthLimit:              0,
+				TraceOperationLimit:          10_000,
+				StorageDiffs:                 false,
+				DeduplicateFailures:          true,
+				ConfirmFailures:              false,
+				ReportUnconfirmedFailures:    false,
 				TargetFunctionSignatures:     []string{},
 				ExcludeFunctionSignatures:    []string{},
+				ExcludeContracts:             []string{},
+				SpecContracts:                []string{},
 				AssertionTesting: AssertionTestingConfig{
 					Enabled: true,
 					PanicCodeConfig: PanicCodeConfig{
 						FailOnAssertion: true,
 					},
+					IncludeContracts:          []string{},
+					ExcludeContracts:          []string{},
+					ReportUnresolvedContracts: true,
+					CheckViewMethodsEvery:     0,
+					SpecTestPrefixes: []string{
+						"assert_",
+					},
 				},
 				PropertyTesting: PropertyTestingConfig{
 					Enabled: true,
 					TestPrefixes: []string{
 						"property_",
 					},
+					RevertHandling:     "fail",
+					CheckAtSequenceEnd: false,
 				},
 				OptimizationTesting: OptimizationTestingConfig{
 					Enabled: true,
@@ -92,6 +194,28 @@ func GetDefaultProjectConfig(platform string) (*ProjectConfig, error) {
 						"optimize_",
 					},
 				},
+				ReentrancyTesting: ReentrancyTestingConfig{
+					Enabled:           false,
+					AllowedReentrancy: []string{},
+				},
+				UntrustedDelegateCallTesting: UntrustedDelegateCallTestingConfig{
+					Enabled:              false,
+					AllowedDelegateCalls: []string{},
+				},
+				TokenTesting: TokenTestingConfig{
+					Enabled:              false,
+					ERC20Contracts:       []string{},
+					ERC721Contracts:      []string{},
+					MintAddresses:        []string{},
+					BurnAddresses:        []string{},
+					TransferFeeTolerance: 0,
+				},
+				ScriptedOracleTesting: ScriptedOracleTestingConfig{
+					Enabled:           false,
+					Scripts:           []string{},
+					MaxExecutionSteps: 1_000_000,
+					Timeout:           1000,
+				},
 			},
 			TestChainConfig: *chainConfig,
 		},
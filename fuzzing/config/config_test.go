@@ -0,0 +1,28 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProjectConfigClone tests that a cloned ProjectConfig equals the original, but shares no slices, maps, or
+// compilation platform configuration with it.
+func TestProjectConfigClone(t *testing.T) {
+	projectConfig, err := GetDefaultProjectConfig("crytic-compile")
+	assert.NoError(t, err)
+	projectConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+	projectConfig.Fuzzing.ConstructorArgs = map[string]map[string]any{"TestContract": {"x": "1"}}
+
+	clone, err := projectConfig.Clone()
+	assert.NoError(t, err)
+	assert.EqualValues(t, projectConfig, clone)
+
+	// Updating the clone should not affect the original.
+	clone.Fuzzing.TargetContracts[0] = "OtherContract"
+	clone.Fuzzing.ConstructorArgs["TestContract"]["x"] = "2"
+	(*clone.Compilation.PlatformConfig)[0] = ' '
+	assert.EqualValues(t, []string{"TestContract"}, projectConfig.Fuzzing.TargetContracts)
+	assert.EqualValues(t, "1", projectConfig.Fuzzing.ConstructorArgs["TestContract"]["x"])
+	assert.NotEqualValues(t, ' ', (*projectConfig.Compilation.PlatformConfig)[0])
+}
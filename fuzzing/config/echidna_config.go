@@ -0,0 +1,326 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/crytic/medusa/compilation/platforms"
+	"gopkg.in/yaml.v3"
+)
+
+// echidnaTestModes describes a mapping of Echidna test modes to functions which update a ProjectConfig's testing
+// configuration to reflect the equivalent medusa test modes.
+var echidnaTestModes = map[string]func(testingConfig *TestingConfig){
+	"property": func(testingConfig *TestingConfig) {
+		testingConfig.PropertyTesting.Enabled = true
+		testingConfig.AssertionTesting.Enabled = false
+		testingConfig.OptimizationTesting.Enabled = false
+	},
+	"assertion": func(testingConfig *TestingConfig) {
+		testingConfig.PropertyTesting.Enabled = false
+		testingConfig.AssertionTesting.Enabled = true
+		testingConfig.OptimizationTesting.Enabled = false
+	},
+	"overflow": func(testingConfig *TestingConfig) {
+		testingConfig.PropertyTesting.Enabled = false
+		testingConfig.AssertionTesting.Enabled = true
+		testingConfig.AssertionTesting.PanicCodeConfig.FailOnArithmeticUnderflow = true
+		testingConfig.OptimizationTesting.Enabled = false
+	},
+	"optimization": func(testingConfig *TestingConfig) {
+		testingConfig.PropertyTesting.Enabled = false
+		testingConfig.AssertionTesting.Enabled = false
+		testingConfig.OptimizationTesting.Enabled = true
+	},
+	"exploration": func(testingConfig *TestingConfig) {
+		testingConfig.Enabled = false
+		testingConfig.PropertyTesting.Enabled = false
+		testingConfig.AssertionTesting.Enabled = false
+		testingConfig.OptimizationTesting.Enabled = false
+		testingConfig.StopOnNoTests = false
+	},
+}
+
+// ReadEchidnaConfigFromFile reads an Echidna YAML configuration file from the provided path and converts it into an
+// equivalent ProjectConfig, using the default configuration for the provided compilation platform as a base.
+// Returns the converted ProjectConfig, a list of Echidna fields which have no medusa equivalent, or an error if one
+// occurs.
+func ReadEchidnaConfigFromFile(path string, platform string) (*ProjectConfig, []string, error) {
+	// Read our project configuration file data
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Parse the YAML data
+	echidnaConfig, err := ParseEchidnaConfig(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ConvertEchidnaConfig(echidnaConfig, platform)
+}
+
+// ParseEchidnaConfig parses the provided Echidna YAML configuration data into a mapping of field names to values.
+// Returns the parsed configuration, or an error if one occurs.
+func ParseEchidnaConfig(data []byte) (map[string]any, error) {
+	echidnaConfig := make(map[string]any)
+	err := yaml.Unmarshal(data, &echidnaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse echidna config: %v", err)
+	}
+	return echidnaConfig, nil
+}
+
+// ConvertEchidnaConfig converts a parsed Echidna configuration into an equivalent ProjectConfig, using the default
+// configuration for the provided compilation platform as a base.
+// Returns the converted ProjectConfig, a sorted list of Echidna fields which have no medusa equivalent, or an error
+// if a field has an unexpected type or value.
+func ConvertEchidnaConfig(echidnaConfig map[string]any, platform string) (*ProjectConfig, []string, error) {
+	// Obtain our base project configuration
+	projectConfig, err := GetDefaultProjectConfig(platform)
+	if err != nil {
+		return nil, nil, err
+	}
+	fuzzingConfig := &projectConfig.Fuzzing
+	testingConfig := &projectConfig.Fuzzing.Testing
+
+	// Echidna identifies property and optimization tests using the "echidna_" prefix unless another is provided.
+	prefix := "echidna_"
+	testMode := "property"
+
+	// Echidna applies filterFunctions as a blacklist by default, so we resolve this flag before handling any fields.
+	filterBlacklist := true
+	if value, ok := echidnaConfig["filterBlacklist"]; ok {
+		filterBlacklist, err = echidnaBool("filterBlacklist", value)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Loop through all the fields and convert them to their medusa equivalent.
+	var unsupportedFields []string
+	for field, value := range echidnaConfig {
+		switch field {
+		case "testLimit":
+			fuzzingConfig.TestLimit, err = echidnaUint("testLimit", value)
+		case "seqLen":
+			var seqLen uint64
+			seqLen, err = echidnaUint("seqLen", value)
+			fuzzingConfig.CallSequenceLength = int(seqLen)
+		case "shrinkLimit":
+			fuzzingConfig.ShrinkLimit, err = echidnaUint("shrinkLimit", value)
+		case "workers":
+			var workers uint64
+			workers, err = echidnaUint("workers", value)
+			fuzzingConfig.Workers = int(workers)
+		case "timeout":
+			var timeout uint64
+			timeout, err = echidnaUint("timeout", value)
+			fuzzingConfig.Timeout = int(timeout)
+		case "seed":
+			var seed uint64
+			seed, err = echidnaUint("seed", value)
+			fuzzingConfig.Seed = int64(seed)
+		case "maxTimeDelay":
+			fuzzingConfig.MaxBlockTimestampDelay, err = echidnaUint("maxTimeDelay", value)
+		case "maxBlockDelay":
+			fuzzingConfig.MaxBlockNumberDelay, err = echidnaUint("maxBlockDelay", value)
+		case "testMaxGas":
+			fuzzingConfig.TransactionGasLimit, err = echidnaUint("testMaxGas", value)
+		case "sender":
+			var senders []any
+			senders, err = echidnaList("sender", value)
+			if err == nil {
+				fuzzingConfig.SenderAddresses = make([]string, 0)
+				for _, sender := range senders {
+					var senderAddress string
+					senderAddress, err = echidnaAddress("sender", sender)
+					if err != nil {
+						break
+					}
+					fuzzingConfig.SenderAddresses = append(fuzzingConfig.SenderAddresses, senderAddress)
+				}
+			}
+		case "deployer":
+			fuzzingConfig.DeployerAddress, err = echidnaAddress("deployer", value)
+		case "prefix":
+			prefix, err = echidnaString("prefix", value)
+		case "corpusDir":
+			fuzzingConfig.CorpusDirectory, err = echidnaString("corpusDir", value)
+		case "coverage":
+			fuzzingConfig.CoverageEnabled, err = echidnaBool("coverage", value)
+		case "coverageFormats":
+			var formats []any
+			formats, err = echidnaList("coverageFormats", value)
+			if err == nil {
+				fuzzingConfig.CoverageFormats = make([]string, 0)
+				for _, format := range formats {
+					var formatStr string
+					formatStr, err = echidnaString("coverageFormats", format)
+					if err != nil {
+						break
+					}
+					if formatStr == "html" || formatStr == "lcov" {
+						fuzzingConfig.CoverageFormats = append(fuzzingConfig.CoverageFormats, formatStr)
+					} else {
+						unsupportedFields = append(unsupportedFields, fmt.Sprintf("coverageFormats (%s)", formatStr))
+					}
+				}
+			}
+		case "filterFunctions":
+			var functions []any
+			functions, err = echidnaList("filterFunctions", value)
+			if err == nil {
+				signatures := make([]string, 0)
+				for _, function := range functions {
+					var signature string
+					signature, err = echidnaString("filterFunctions", function)
+					if err != nil {
+						break
+					}
+					signatures = append(signatures, signature)
+				}
+				if filterBlacklist {
+					testingConfig.ExcludeFunctionSignatures = signatures
+				} else {
+					testingConfig.TargetFunctionSignatures = signatures
+				}
+			}
+		case "filterBlacklist":
+			// This field was already handled prior to the loop.
+		case "testMode":
+			testMode, err = echidnaString("testMode", value)
+			if updateTestingConfig, ok := echidnaTestModes[testMode]; ok {
+				updateTestingConfig(testingConfig)
+			} else if err == nil {
+				unsupportedFields = append(unsupportedFields, fmt.Sprintf("testMode (%s)", testMode))
+			}
+		case "stopOnFail":
+			testingConfig.StopOnFailedTest, err = echidnaBool("stopOnFail", value)
+		case "allContracts":
+			testingConfig.TestAllContracts, err = echidnaBool("allContracts", value)
+		case "allowFFI":
+			fuzzingConfig.TestChainConfig.CheatCodeConfig.EnableFFI, err = echidnaBool("allowFFI", value)
+		case "rpcUrl":
+			fuzzingConfig.TestChainConfig.ForkConfig.RpcUrl, err = echidnaString("rpcUrl", value)
+			fuzzingConfig.TestChainConfig.ForkConfig.ForkModeEnabled = true
+		case "rpcBlock":
+			fuzzingConfig.TestChainConfig.ForkConfig.RpcBlock, err = echidnaUint("rpcBlock", value)
+		case "cryticArgs", "solcArgs":
+			// These arguments can only be provided if we are compiling with crytic-compile.
+			var cryticConfig *platforms.CryticCompilationConfig
+			if projectConfig.Compilation != nil {
+				if platformConfig, platformErr := projectConfig.Compilation.GetPlatformConfig(); platformErr == nil {
+					cryticConfig, _ = platformConfig.(*platforms.CryticCompilationConfig)
+				}
+			}
+			if cryticConfig == nil {
+				unsupportedFields = append(unsupportedFields, field)
+				continue
+			}
+
+			// Append the arguments to our crytic-compile arguments.
+			if field == "cryticArgs" {
+				var args []any
+				args, err = echidnaList("cryticArgs", value)
+				for i := 0; err == nil && i < len(args); i++ {
+					var arg string
+					arg, err = echidnaString("cryticArgs", args[i])
+					cryticConfig.Args = append(cryticConfig.Args, arg)
+				}
+			} else {
+				var solcArgs string
+				solcArgs, err = echidnaString("solcArgs", value)
+				cryticConfig.Args = append(cryticConfig.Args, "--solc-args", solcArgs)
+			}
+			if err == nil {
+				err = projectConfig.Compilation.SetPlatformConfig(cryticConfig)
+			}
+		default:
+			unsupportedFields = append(unsupportedFields, field)
+		}
+
+		// If we encountered an error converting this field, return it.
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Echidna does not stop on failed tests by default. In exploration mode no tests can fail, so unless stopping was
+	// explicitly requested (which is invalid in exploration mode), we disable it.
+	if _, ok := echidnaConfig["stopOnFail"]; !ok && !testingConfig.Enabled {
+		testingConfig.StopOnFailedTest = false
+	}
+
+	// Echidna uses the same prefix for property and optimization tests, but only one is tested in a given test mode.
+	// We apply the prefix to the relevant medusa test mode, as prefixes must be unique across them.
+	if testMode == "optimization" {
+		testingConfig.OptimizationTesting.TestPrefixes = []string{prefix}
+	} else {
+		testingConfig.PropertyTesting.TestPrefixes = []string{prefix}
+	}
+
+	// Sort our unsupported fields so results are deterministic.
+	sort.Strings(unsupportedFields)
+	return projectConfig, unsupportedFields, nil
+}
+
+// echidnaUint converts the provided Echidna config value to an unsigned integer.
+// Returns the converted value, or an error if the value is not a non-negative integer.
+func echidnaUint(field string, value any) (uint64, error) {
+	switch v := value.(type) {
+	case int:
+		if v >= 0 {
+			return uint64(v), nil
+		}
+	case uint64:
+		return v, nil
+	}
+	return 0, fmt.Errorf("echidna config field '%s' must be a non-negative integer, got '%v'", field, value)
+}
+
+// echidnaBool converts the provided Echidna config value to a boolean.
+// Returns the converted value, or an error if the value is not a boolean.
+func echidnaBool(field string, value any) (bool, error) {
+	if v, ok := value.(bool); ok {
+		return v, nil
+	}
+	return false, fmt.Errorf("echidna config field '%s' must be a boolean, got '%v'", field, value)
+}
+
+// echidnaString converts the provided Echidna config value to a string.
+// Returns the converted value, or an error if the value is not a string.
+func echidnaString(field string, value any) (string, error) {
+	if v, ok := value.(string); ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("echidna config field '%s' must be a string, got '%v'", field, value)
+}
+
+// echidnaList converts the provided Echidna config value to a list.
+// Returns the converted value, or an error if the value is not a list.
+func echidnaList(field string, value any) ([]any, error) {
+	if v, ok := value.([]any); ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("echidna config field '%s' must be a list, got '%v'", field, value)
+}
+
+// echidnaAddress converts the provided Echidna config value to a hex address string. Echidna addresses which are
+// not quoted in YAML are parsed as integers, so both integers and strings are accepted.
+// Returns the converted value, or an error if the value is not an address.
+func echidnaAddress(field string, value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int:
+		if v >= 0 {
+			return fmt.Sprintf("0x%x", v), nil
+		}
+	case uint64:
+		return fmt.Sprintf("0x%x", v), nil
+	}
+	return "", fmt.Errorf("echidna config field '%s' must be an address, got '%v'", field, value)
+}
@@ -0,0 +1,269 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/crytic/medusa/compilation/platforms"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConvertEchidnaConfig tests the conversion of various Echidna configuration fields into their medusa equivalents.
+func TestConvertEchidnaConfig(t *testing.T) {
+	tests := []struct {
+		// name describes the test case.
+		name string
+
+		// yaml describes the Echidna configuration to convert.
+		yaml string
+
+		// expectError describes whether the conversion is expected to fail.
+		expectError bool
+
+		// expectUnsupported describes the fields expected to be reported as having no medusa equivalent.
+		expectUnsupported []string
+
+		// verify checks the resulting project configuration.
+		verify func(t *testing.T, projectConfig *ProjectConfig)
+	}{
+		{
+			name: "empty config uses echidna prefix",
+			yaml: "",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.EqualValues(t, []string{"echidna_"}, projectConfig.Fuzzing.Testing.PropertyTesting.TestPrefixes)
+				assert.EqualValues(t, []string{"optimize_"}, projectConfig.Fuzzing.Testing.OptimizationTesting.TestPrefixes)
+			},
+		},
+		{
+			name: "limits",
+			yaml: "testLimit: 100000\nseqLen: 50\nshrinkLimit: 2000\nworkers: 4\ntimeout: 3600\nseed: 1234",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.EqualValues(t, 100000, projectConfig.Fuzzing.TestLimit)
+				assert.EqualValues(t, 50, projectConfig.Fuzzing.CallSequenceLength)
+				assert.EqualValues(t, 2000, projectConfig.Fuzzing.ShrinkLimit)
+				assert.EqualValues(t, 4, projectConfig.Fuzzing.Workers)
+				assert.EqualValues(t, 3600, projectConfig.Fuzzing.Timeout)
+				assert.EqualValues(t, 1234, projectConfig.Fuzzing.Seed)
+			},
+		},
+		{
+			name: "delays and gas",
+			yaml: "maxTimeDelay: 100\nmaxBlockDelay: 10\ntestMaxGas: 8000000",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.EqualValues(t, 100, projectConfig.Fuzzing.MaxBlockTimestampDelay)
+				assert.EqualValues(t, 10, projectConfig.Fuzzing.MaxBlockNumberDelay)
+				assert.EqualValues(t, 8000000, projectConfig.Fuzzing.TransactionGasLimit)
+			},
+		},
+		{
+			name: "quoted addresses",
+			yaml: "sender: [\"0x10000\", \"0x20000\"]\ndeployer: \"0x40000\"",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.EqualValues(t, []string{"0x10000", "0x20000"}, projectConfig.Fuzzing.SenderAddresses)
+				assert.EqualValues(t, "0x40000", projectConfig.Fuzzing.DeployerAddress)
+			},
+		},
+		{
+			name: "unquoted addresses",
+			yaml: "sender: [0x10000, 0x20000]\ndeployer: 0x40000",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.EqualValues(t, []string{"0x10000", "0x20000"}, projectConfig.Fuzzing.SenderAddresses)
+				assert.EqualValues(t, "0x40000", projectConfig.Fuzzing.DeployerAddress)
+			},
+		},
+		{
+			name: "prefix and corpus",
+			yaml: "prefix: \"invariant_\"\ncorpusDir: \"corpus\"",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.EqualValues(t, []string{"invariant_"}, projectConfig.Fuzzing.Testing.PropertyTesting.TestPrefixes)
+				assert.EqualValues(t, "corpus", projectConfig.Fuzzing.CorpusDirectory)
+			},
+		},
+		{
+			name: "optimization test mode uses prefix",
+			yaml: "testMode: optimization\nprefix: \"maximize_\"",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.True(t, projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled)
+				assert.False(t, projectConfig.Fuzzing.Testing.PropertyTesting.Enabled)
+				assert.EqualValues(t, []string{"maximize_"}, projectConfig.Fuzzing.Testing.OptimizationTesting.TestPrefixes)
+				assert.NoError(t, projectConfig.Validate())
+			},
+		},
+		{
+			name: "filter functions blacklist by default",
+			yaml: "filterFunctions: [\"TestContract.f()\", \"TestContract.g(uint256)\"]",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.EqualValues(t, []string{"TestContract.f()", "TestContract.g(uint256)"}, projectConfig.Fuzzing.Testing.ExcludeFunctionSignatures)
+				assert.Empty(t, projectConfig.Fuzzing.Testing.TargetFunctionSignatures)
+			},
+		},
+		{
+			name: "filter functions whitelist",
+			yaml: "filterBlacklist: false\nfilterFunctions: [\"TestContract.f()\"]",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.EqualValues(t, []string{"TestContract.f()"}, projectConfig.Fuzzing.Testing.TargetFunctionSignatures)
+				assert.Empty(t, projectConfig.Fuzzing.Testing.ExcludeFunctionSignatures)
+			},
+		},
+		{
+			name: "assertion test mode",
+			yaml: "testMode: assertion",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.True(t, projectConfig.Fuzzing.Testing.AssertionTesting.Enabled)
+				assert.False(t, projectConfig.Fuzzing.Testing.PropertyTesting.Enabled)
+				assert.False(t, projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled)
+			},
+		},
+		{
+			name: "overflow test mode",
+			yaml: "testMode: overflow",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.True(t, projectConfig.Fuzzing.Testing.AssertionTesting.Enabled)
+				assert.True(t, projectConfig.Fuzzing.Testing.AssertionTesting.PanicCodeConfig.FailOnArithmeticUnderflow)
+			},
+		},
+		{
+			name: "exploration test mode",
+			yaml: "testMode: exploration",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.False(t, projectConfig.Fuzzing.Testing.AssertionTesting.Enabled)
+				assert.False(t, projectConfig.Fuzzing.Testing.PropertyTesting.Enabled)
+				assert.False(t, projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled)
+				assert.False(t, projectConfig.Fuzzing.Testing.StopOnNoTests)
+				assert.False(t, projectConfig.Fuzzing.Testing.Enabled)
+				assert.False(t, projectConfig.Fuzzing.Testing.StopOnFailedTest)
+				assert.NoError(t, projectConfig.Validate())
+			},
+		},
+		{
+			name: "exploration test mode stopping on failed tests",
+			yaml: "testMode: exploration\nstopOnFail: true",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.False(t, projectConfig.Fuzzing.Testing.Enabled)
+				assert.True(t, projectConfig.Fuzzing.Testing.StopOnFailedTest)
+				assert.Error(t, projectConfig.Validate())
+			},
+		},
+		{
+			name:              "unsupported test mode",
+			yaml:              "testMode: dapptest",
+			expectUnsupported: []string{"testMode (dapptest)"},
+		},
+		{
+			name: "coverage",
+			yaml: "coverage: false\ncoverageFormats: [\"txt\", \"html\", \"lcov\"]",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.False(t, projectConfig.Fuzzing.CoverageEnabled)
+				assert.EqualValues(t, []string{"html", "lcov"}, projectConfig.Fuzzing.CoverageFormats)
+			},
+			expectUnsupported: []string{"coverageFormats (txt)"},
+		},
+		{
+			name: "testing flags",
+			yaml: "stopOnFail: false\nallContracts: true\nallowFFI: true",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.False(t, projectConfig.Fuzzing.Testing.StopOnFailedTest)
+				assert.True(t, projectConfig.Fuzzing.Testing.TestAllContracts)
+				assert.True(t, projectConfig.Fuzzing.TestChainConfig.CheatCodeConfig.EnableFFI)
+			},
+		},
+		{
+			name: "fork mode",
+			yaml: "rpcUrl: \"http://localhost:8545\"\nrpcBlock: 17000000",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.True(t, projectConfig.Fuzzing.TestChainConfig.ForkConfig.ForkModeEnabled)
+				assert.EqualValues(t, "http://localhost:8545", projectConfig.Fuzzing.TestChainConfig.ForkConfig.RpcUrl)
+				assert.EqualValues(t, 17000000, projectConfig.Fuzzing.TestChainConfig.ForkConfig.RpcBlock)
+			},
+		},
+		{
+			name: "crytic args",
+			yaml: "cryticArgs: [\"--foundry-compile-all\"]\nsolcArgs: \"--via-ir\"",
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				platformConfig, err := projectConfig.Compilation.GetPlatformConfig()
+				assert.NoError(t, err)
+				cryticConfig := platformConfig.(*platforms.CryticCompilationConfig)
+				assert.Contains(t, cryticConfig.Args, "--foundry-compile-all")
+				assert.Contains(t, cryticConfig.Args, "--via-ir")
+			},
+		},
+		{
+			name:              "fields without medusa equivalents",
+			yaml:              "contractAddr: \"0x00a329c0648769A73afAc7F9381E08FB43dBEA72\"\ntestLimit: 10",
+			expectUnsupported: []string{"contractAddr"},
+			verify: func(t *testing.T, projectConfig *ProjectConfig) {
+				assert.EqualValues(t, 10, projectConfig.Fuzzing.TestLimit)
+			},
+		},
+		{
+			name:        "invalid integer",
+			yaml:        "testLimit: \"many\"",
+			expectError: true,
+		},
+		{
+			name:        "negative integer",
+			yaml:        "seqLen: -1",
+			expectError: true,
+		},
+		{
+			name:        "invalid list",
+			yaml:        "sender: \"0x10000\"",
+			expectError: true,
+		},
+		{
+			name:        "invalid boolean",
+			yaml:        "filterBlacklist: \"no\"",
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Parse the echidna config
+			echidnaConfig, err := ParseEchidnaConfig([]byte(test.yaml))
+			assert.NoError(t, err)
+
+			// Convert it and verify the results
+			projectConfig, unsupportedFields, err := ConvertEchidnaConfig(echidnaConfig, "crytic-compile")
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.EqualValues(t, test.expectUnsupported, unsupportedFields)
+			if test.verify != nil {
+				test.verify(t, projectConfig)
+			}
+		})
+	}
+}
+
+// TestReadEchidnaConfigFromFile tests that an Echidna configuration file can be converted and written as a medusa
+// project configuration which can be read back and validated.
+func TestReadEchidnaConfigFromFile(t *testing.T) {
+	// Write an echidna config to our test directory
+	directory := t.TempDir()
+	echidnaConfigPath := filepath.Join(directory, "echidna.yaml")
+	err := os.WriteFile(echidnaConfigPath, []byte("testMode: property\ntestLimit: 5000\nseqLen: 20\nprefix: \"invariant_\"\n"), 0644)
+	assert.NoError(t, err)
+
+	// Convert the echidna config
+	projectConfig, unsupportedFields, err := ReadEchidnaConfigFromFile(echidnaConfigPath, "crytic-compile")
+	assert.NoError(t, err)
+	assert.Empty(t, unsupportedFields)
+
+	// Write the project configuration and read it back
+	projectConfigPath := filepath.Join(directory, "medusa.json")
+	err = projectConfig.WriteToFile(projectConfigPath)
+	assert.NoError(t, err)
+	readConfig, err := ReadProjectConfigFromFile(projectConfigPath, "crytic-compile")
+	assert.NoError(t, err)
+	assert.NoError(t, readConfig.Validate())
+
+	// Verify the converted fields survived the round trip
+	assert.EqualValues(t, 5000, readConfig.Fuzzing.TestLimit)
+	assert.EqualValues(t, 20, readConfig.Fuzzing.CallSequenceLength)
+	assert.EqualValues(t, []string{"invariant_"}, readConfig.Fuzzing.Testing.PropertyTesting.TestPrefixes)
+	assert.False(t, readConfig.Fuzzing.Testing.AssertionTesting.Enabled)
+}
@@ -0,0 +1,196 @@
+package fuzzing
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+)
+
+// ContractMetric describes how thoroughly a single contract was exercised during a fuzzing campaign. Every deployment
+// of a contract definition, including dynamic deployments, is grouped under the name of its definition.
+type ContractMetric struct {
+	// Contract describes the name of the contract definition.
+	Contract string `json:"contract"`
+
+	// MethodsCalled describes the amount of the contract's fuzzable methods which were called at least once.
+	MethodsCalled int `json:"methodsCalled"`
+
+	// MethodsTotal describes the amount of the contract's methods which the fuzzer generates calls to. This is zero
+	// if the contract is excluded from call generation, or has no methods to call.
+	MethodsTotal int `json:"methodsTotal"`
+
+	// Calls describes the amount of calls executed which targeted the contract.
+	Calls uint64 `json:"calls"`
+
+	// CorpusSequences describes the amount of call sequences added to the corpus which called the contract.
+	CorpusSequences uint64 `json:"corpusSequences"`
+}
+
+// contractMetricsEntry describes the metrics tracked for a single contract definition by contractMetrics.
+type contractMetricsEntry struct {
+	// fuzzableMethods describes the selectors of the contract's methods which the fuzzer generates calls to.
+	fuzzableMethods map[[4]byte]struct{}
+
+	// calledMethods describes the selectors of the contract's methods which were called at least once.
+	calledMethods map[[4]byte]struct{}
+
+	// calls describes the amount of calls executed which targeted the contract.
+	calls uint64
+
+	// corpusSequences describes the amount of call sequences added to the corpus which called the contract.
+	corpusSequences uint64
+}
+
+// contractMetrics tracks how thoroughly each contract was exercised by a single FuzzerWorker, keyed by the name of
+// the contract definition. It is safe for concurrent use.
+type contractMetrics struct {
+	// lock is used to synchronize access to contracts, as metrics are read while the worker is executing.
+	lock sync.Mutex
+
+	// contracts describes the metrics tracked for each contract definition name.
+	contracts map[string]*contractMetricsEntry
+}
+
+// newContractMetrics returns a new contractMetrics with no contracts tracked.
+func newContractMetrics() *contractMetrics {
+	return &contractMetrics{
+		contracts: make(map[string]*contractMetricsEntry),
+	}
+}
+
+// entry obtains the metrics tracked for the contract definition with the provided name, creating them if they do not
+// exist. The lock must be held by the caller.
+func (m *contractMetrics) entry(contractName string) *contractMetricsEntry {
+	entry, exists := m.contracts[contractName]
+	if !exists {
+		entry = &contractMetricsEntry{
+			fuzzableMethods: make(map[[4]byte]struct{}),
+			calledMethods:   make(map[[4]byte]struct{}),
+		}
+		m.contracts[contractName] = entry
+	}
+	return entry
+}
+
+// addContract records a deployment of the provided contract definition, along with the methods of it which the
+// fuzzer generates calls to. The provided methods may be empty if the contract is excluded from call generation.
+func (m *contractMetrics) addContract(contract *fuzzerTypes.Contract, fuzzableMethods []fuzzerTypes.DeployedContractMethod) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	entry := m.entry(contract.Name())
+	for _, method := range fuzzableMethods {
+		entry.fuzzableMethods[[4]byte(method.Method.ID)] = struct{}{}
+	}
+}
+
+// addCall records an executed call to the provided resolved method.
+func (m *contractMetrics) addCall(method *fuzzerTypes.DeployedContractMethod) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	entry := m.entry(method.Contract.Name())
+	entry.calledMethods[[4]byte(method.Method.ID)] = struct{}{}
+	entry.calls++
+}
+
+// addCorpusSequence records a call sequence being added to the corpus, which called each of the contracts with the
+// provided names.
+func (m *contractMetrics) addCorpusSequence(contractNames map[string]struct{}) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for contractName := range contractNames {
+		m.entry(contractName).corpusSequences++
+	}
+}
+
+// recordContractCall records the last call executed in the provided call sequence in the worker's contract metrics,
+// if the method it targeted can be resolved.
+func (fw *FuzzerWorker) recordContractCall(callSequence calls.CallSequence) {
+	element := callSequence[len(callSequence)-1]
+	if element.Call.To == nil || len(element.Call.Data) < 4 {
+		return
+	}
+	if method := fw.ResolveMethod(*element.Call.To, element.Call.Data[:4]); method != nil {
+		fw.workerMetrics().contracts.addCall(method)
+	}
+}
+
+// recordContractCorpusSequence records the provided call sequence, which was just added to the corpus, in the
+// worker's contract metrics for each contract it called.
+func (fw *FuzzerWorker) recordContractCorpusSequence(callSequence calls.CallSequence) {
+	contractNames := make(map[string]struct{})
+	for _, element := range callSequence {
+		if element.Call.To == nil {
+			continue
+		}
+		if contract := fw.DeployedContract(*element.Call.To); contract != nil {
+			contractNames[contract.Name()] = struct{}{}
+		}
+	}
+	fw.workerMetrics().contracts.addCorpusSequence(contractNames)
+}
+
+// ContractMetrics returns metrics describing how thoroughly each contract was exercised, across all workers. The
+// results are sorted by contract name.
+func (m *FuzzerMetrics) ContractMetrics() []ContractMetric {
+	// Aggregate the metrics from all workers.
+	aggregated := newContractMetrics()
+	for _, workerMetrics := range m.workerMetrics {
+		workerMetrics.contracts.lock.Lock()
+		for contractName, workerEntry := range workerMetrics.contracts.contracts {
+			entry := aggregated.entry(contractName)
+			for selector := range workerEntry.fuzzableMethods {
+				entry.fuzzableMethods[selector] = struct{}{}
+			}
+			for selector := range workerEntry.calledMethods {
+				entry.calledMethods[selector] = struct{}{}
+			}
+			entry.calls += workerEntry.calls
+			entry.corpusSequences += workerEntry.corpusSequences
+		}
+		workerMetrics.contracts.lock.Unlock()
+	}
+
+	// Resolve each entry into a displayable metric. Only fuzzable methods count as called, so the amount of methods
+	// called never exceeds the total.
+	metrics := make([]ContractMetric, 0, len(aggregated.contracts))
+	for contractName, entry := range aggregated.contracts {
+		metric := ContractMetric{
+			Contract:        contractName,
+			MethodsTotal:    len(entry.fuzzableMethods),
+			Calls:           entry.calls,
+			CorpusSequences: entry.corpusSequences,
+		}
+		for selector := range entry.calledMethods {
+			if _, fuzzable := entry.fuzzableMethods[selector]; fuzzable {
+				metric.MethodsCalled++
+			}
+		}
+		metrics = append(metrics, metric)
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Contract < metrics[j].Contract
+	})
+	return metrics
+}
+
+// appendContractMetrics appends a compact table describing the provided ContractMetric list to the provided log
+// buffer, with one row for each contract.
+func appendContractMetrics(buffer *logging.LogBuffer, metrics []ContractMetric) {
+	// Determine the width of our contract name column.
+	nameWidth := len("contract")
+	for _, metric := range metrics {
+		nameWidth = max(nameWidth, len(metric.Contract))
+	}
+
+	// Append our header, followed by a row for each contract.
+	buffer.Append(colors.Bold, fmt.Sprintf("\n\t%-*s  %-9s  %-12s  %s", nameWidth, "contract", "methods", "calls", "corpus seqs"), colors.Reset)
+	for _, metric := range metrics {
+		methods := fmt.Sprintf("%d/%d", metric.MethodsCalled, metric.MethodsTotal)
+		buffer.Append(fmt.Sprintf("\n\t%-*s  %-9s  %-12d  %d", nameWidth, metric.Contract, methods, metric.Calls, metric.CorpusSequences))
+	}
+}
@@ -1,6 +1,8 @@
 package contracts
 
 import (
+	"bytes"
+	"encoding/hex"
 	"golang.org/x/exp/slices"
 	"strings"
 
@@ -11,9 +13,30 @@ import (
 // Contracts describes an array of contracts
 type Contracts []*Contract
 
+// BytecodeMatchingMode describes how deployed contract bytecode is matched to a contract definition.
+type BytecodeMatchingMode string
+
+const (
+	// BytecodeMatchingModeStrict matches deployed bytecode to a contract definition only if it is identical, aside
+	// from any constructor arguments appended to the init bytecode.
+	BytecodeMatchingModeStrict BytecodeMatchingMode = "strict"
+
+	// BytecodeMatchingModeIgnoreMetadata matches deployed bytecode as BytecodeMatchingModeStrict does, but falls back
+	// to comparing the bytecode with the bytecode hashes in its embedded contract metadata masked out. This allows
+	// contracts compiled with slightly different metadata (e.g. by a factory's compilation) to be matched.
+	BytecodeMatchingModeIgnoreMetadata BytecodeMatchingMode = "ignoreMetadata"
+)
+
 // MatchBytecode takes init and/or runtime bytecode and attempts to match it to a contract definition in the
 // current list of contracts. It returns the contract definition if found. Otherwise, it returns nil.
 func (c Contracts) MatchBytecode(initBytecode []byte, runtimeBytecode []byte) *Contract {
+	return c.MatchBytecodeWithMode(initBytecode, runtimeBytecode, BytecodeMatchingModeStrict)
+}
+
+// MatchBytecodeWithMode takes init and/or runtime bytecode and attempts to match it to a contract definition in the
+// current list of contracts, using the provided BytecodeMatchingMode. Exact matches are always preferred over
+// lenient ones. It returns the contract definition if found. Otherwise, it returns nil.
+func (c Contracts) MatchBytecodeWithMode(initBytecode []byte, runtimeBytecode []byte, mode BytecodeMatchingMode) *Contract {
 	// Loop through all our contract definitions to find a match.
 	for i := 0; i < len(c); i++ {
 		// If we have a match, register the deployed contract.
@@ -22,6 +45,17 @@ func (c Contracts) MatchBytecode(initBytecode []byte, runtimeBytecode []byte) *C
 		}
 	}
 
+	// If we are permitted to, try matching again with the contract metadata hashes masked out.
+	if mode == BytecodeMatchingModeIgnoreMetadata {
+		maskedInitBytecode := types.MaskContractMetadataHashes(initBytecode)
+		maskedRuntimeBytecode := types.MaskContractMetadataHashes(runtimeBytecode)
+		for i := 0; i < len(c); i++ {
+			if c[i].isMatchIgnoringMetadata(maskedInitBytecode, maskedRuntimeBytecode) {
+				return c[i]
+			}
+		}
+	}
+
 	// If we found no definition, return nil.
 	return nil
 }
@@ -50,8 +84,34 @@ type Contract struct {
 	// If configured, the methods will be targeted or excluded based on the targetFunctionSignatures
 	// and excludedFunctionSignatures, respectively.
 	AssertionTestMethods []abi.Method
+
+	// MethodDescriptions maps hex-encoded method selectors to the NatSpec descriptions of the methods, if they were
+	// documented.
+	MethodDescriptions map[string]string
+
+	// MethodDirectives maps hex-encoded method selectors to the directives provided through the NatSpec
+	// `@custom:medusa` tags of the methods, if any were provided.
+	MethodDirectives map[string][]string
 }
 
+const (
+	// MethodDirectiveIgnore describes the `@custom:medusa` NatSpec directive which prevents a method from being called
+	// directly by the fuzzer. The method may still be called indirectly, by other methods.
+	MethodDirectiveIgnore = "ignore"
+
+	// MethodDirectiveTarget describes the `@custom:medusa` NatSpec directive which restricts the methods called
+	// directly by the fuzzer to those providing it.
+	MethodDirectiveTarget = "target"
+
+	// MethodDirectiveSetup describes the `@custom:medusa` NatSpec directive which marks calls to a method as setup
+	// calls the rest of a call sequence depends on, so shrinking never removes them.
+	MethodDirectiveSetup = "setup"
+
+	// MethodDirectiveFrozen describes the `@custom:medusa` NatSpec directive which marks calls to a method as setup
+	// calls which shrinking leaves entirely unchanged, including their arguments.
+	MethodDirectiveFrozen = "frozen"
+)
+
 // NewContract returns a new Contract instance with the provided information.
 func NewContract(name string, sourcePath string, compiledContract *types.CompiledContract, compilation *types.Compilation) *Contract {
 	return &Contract{
@@ -88,6 +148,56 @@ func (c *Contract) WithExcludedAssertionMethods(excludedMethods []string) *Contr
 	return c
 }
 
+// WithPrefixedAssertionMethods filters the assertion test methods to those whose names start with any of the provided
+// prefixes.
+func (c *Contract) WithPrefixedAssertionMethods(prefixes []string) *Contract {
+	var candidateMethods []abi.Method
+	for _, method := range c.AssertionTestMethods {
+		if slices.ContainsFunc(prefixes, func(prefix string) bool { return strings.HasPrefix(method.Name, prefix) }) {
+			candidateMethods = append(candidateMethods, method)
+		}
+	}
+	c.AssertionTestMethods = candidateMethods
+	return c
+}
+
+// WithAnnotatedAssertionMethods filters the assertion test methods using their `@custom:medusa` NatSpec directives.
+// Methods providing MethodDirectiveIgnore are removed and, if targeted is true, so are methods not providing
+// MethodDirectiveTarget. Methods in the provided list of targeted methods are never removed, as configured method
+// lists take precedence over directives.
+func (c *Contract) WithAnnotatedAssertionMethods(targeted bool, targetedMethods []string) *Contract {
+	var candidateMethods []abi.Method
+	for _, method := range c.AssertionTestMethods {
+		canonicalSig := strings.Join([]string{c.name, method.Sig}, ".")
+		if !slices.Contains(targetedMethods, canonicalSig) {
+			if c.HasMethodDirective(method, MethodDirectiveIgnore) {
+				continue
+			}
+			if targeted && !c.HasMethodDirective(method, MethodDirectiveTarget) {
+				continue
+			}
+		}
+		candidateMethods = append(candidateMethods, method)
+	}
+	c.AssertionTestMethods = candidateMethods
+	return c
+}
+
+// HasMethodDirective returns a boolean indicating whether the provided method provides the given directive through
+// its `@custom:medusa` NatSpec tags.
+func (c *Contract) HasMethodDirective(method abi.Method, directive string) bool {
+	return slices.Contains(c.MethodDirectives[hex.EncodeToString(method.ID)], directive)
+}
+
+// MethodDescription returns the NatSpec description of the provided method. If the method was not documented, the
+// method signature is returned instead.
+func (c *Contract) MethodDescription(method abi.Method) string {
+	if description, ok := c.MethodDescriptions[hex.EncodeToString(method.ID)]; ok {
+		return description
+	}
+	return method.Sig
+}
+
 // Name returns the name of the contract.
 func (c *Contract) Name() string {
 	return c.name
@@ -103,6 +213,25 @@ func (c *Contract) CompiledContract() *types.CompiledContract {
 	return c.compiledContract
 }
 
+// isMatchIgnoringMetadata returns a boolean indicating whether the provided init and/or runtime bytecode, whose
+// contract metadata hashes were masked by types.MaskContractMetadataHashes, is a match to this contract definition.
+func (c *Contract) isMatchIgnoringMetadata(maskedInitBytecode []byte, maskedRuntimeBytecode []byte) bool {
+	// Compare the init bytecode first, anticipating it may have constructor arguments appended.
+	definitionInitBytecode := c.compiledContract.InitBytecode
+	if len(maskedInitBytecode) > 0 && len(definitionInitBytecode) > 0 && len(definitionInitBytecode) <= len(maskedInitBytecode) {
+		if bytes.Equal(maskedInitBytecode[:len(definitionInitBytecode)], types.MaskContractMetadataHashes(definitionInitBytecode)) {
+			return true
+		}
+	}
+
+	// Fall back to comparing the whole runtime bytecode.
+	definitionRuntimeBytecode := c.compiledContract.RuntimeBytecode
+	if len(maskedRuntimeBytecode) > 0 && len(definitionRuntimeBytecode) > 0 {
+		return bytes.Equal(maskedRuntimeBytecode, types.MaskContractMetadataHashes(definitionRuntimeBytecode))
+	}
+	return false
+}
+
 // Compilation returns the compilation which contains the CompiledContract.
 func (c *Contract) Compilation() *types.Compilation {
 	return c.compilation
@@ -0,0 +1,54 @@
+package contracts
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// ContractMethod describes a method declared by a contract definition.
+type ContractMethod struct {
+	// Contract describes the contract definition which declares the method.
+	Contract *Contract
+
+	// Method describes the method declared by the contract definition.
+	Method abi.Method
+}
+
+// ByName returns the contract definitions with the provided name. Multiple contract definitions may share a name if
+// they were declared in different source files or compilations.
+func (c Contracts) ByName(name string) Contracts {
+	matches := make(Contracts, 0)
+	for _, contract := range c {
+		if contract.Name() == name {
+			matches = append(matches, contract)
+		}
+	}
+	return matches
+}
+
+// MethodsBySelector returns the methods with the provided selector across all contract definitions, along with the
+// contract definition declaring each, in the order of the contract definitions.
+func (c Contracts) MethodsBySelector(selector []byte) []ContractMethod {
+	matches := make([]ContractMethod, 0)
+	for _, contract := range c {
+		if method := contract.MethodBySelector(selector); method != nil {
+			matches = append(matches, ContractMethod{Contract: contract, Method: *method})
+		}
+	}
+	return matches
+}
+
+// MethodBySelector returns the method of the contract definition with the provided selector, or nil if it declares
+// no such method.
+func (c *Contract) MethodBySelector(selector []byte) *abi.Method {
+	if len(selector) != 4 {
+		return nil
+	}
+	for _, method := range c.CompiledContract().Abi.Methods {
+		if bytes.Equal(method.ID, selector) {
+			return &method
+		}
+	}
+	return nil
+}
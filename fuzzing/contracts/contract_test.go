@@ -0,0 +1,128 @@
+package contracts
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/crytic/medusa/compilation/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestBytecode creates bytecode consisting of the provided code, followed by CBOR-encoded contract metadata as
+// emitted by solc >= 0.6.0, with an IPFS bytecode hash made up of the provided hash byte.
+func newTestBytecode(code []byte, hashByte byte) []byte {
+	bytecode := append(bytes.Clone(code), 0xfe)
+	bytecode = append(bytecode, 0xa2, 0x64, 'i', 'p', 'f', 's', 0x58, 0x22)
+	bytecode = append(bytecode, bytes.Repeat([]byte{hashByte}, 34)...)
+	bytecode = append(bytecode, 0x64, 's', 'o', 'l', 'c', 0x43, 0x00, 0x08, 0x13)
+	return append(bytecode, 0x00, 0x33)
+}
+
+// TestMatchBytecodeIgnoringMetadata tests that bytecode which only differs from a contract definition in its
+// contract metadata hash is only matched when the matching mode ignores metadata, and that bytecode with differing
+// code is never matched.
+func TestMatchBytecodeIgnoringMetadata(t *testing.T) {
+	runtimeCode := []byte{0x60, 0x80, 0x60, 0x40, 0x52, 0x00}
+	initCode := append([]byte{0x60, 0x80, 0x60, 0x40, 0x52, 0x34, 0x80, 0x15}, newTestBytecode(runtimeCode, 0x11)...)
+	definitions := Contracts{
+		NewContract("TestContract", "TestContract.sol", &types.CompiledContract{
+			InitBytecode:    newTestBytecode(initCode, 0x22),
+			RuntimeBytecode: newTestBytecode(runtimeCode, 0x11),
+		}, nil),
+	}
+
+	// Create deployed bytecode which differs only in its metadata hashes, with constructor arguments appended to the
+	// init bytecode.
+	deployedInitCode := append([]byte{0x60, 0x80, 0x60, 0x40, 0x52, 0x34, 0x80, 0x15}, newTestBytecode(runtimeCode, 0x33)...)
+	deployedInitBytecode := append(newTestBytecode(deployedInitCode, 0x44), bytes.Repeat([]byte{0x01}, 32)...)
+	deployedRuntimeBytecode := newTestBytecode(runtimeCode, 0x33)
+
+	// The bytecode should only match when metadata is ignored, whether init or runtime bytecode is provided.
+	assert.Nil(t, definitions.MatchBytecode(deployedInitBytecode, deployedRuntimeBytecode))
+	assert.Nil(t, definitions.MatchBytecodeWithMode(deployedInitBytecode, deployedRuntimeBytecode, BytecodeMatchingModeStrict))
+	assert.Equal(t, definitions[0], definitions.MatchBytecodeWithMode(deployedInitBytecode, deployedRuntimeBytecode, BytecodeMatchingModeIgnoreMetadata))
+	assert.Equal(t, definitions[0], definitions.MatchBytecodeWithMode(deployedInitBytecode, nil, BytecodeMatchingModeIgnoreMetadata))
+	assert.Equal(t, definitions[0], definitions.MatchBytecodeWithMode(nil, deployedRuntimeBytecode, BytecodeMatchingModeIgnoreMetadata))
+
+	// Bytecode which differs outside its metadata should never match.
+	differentRuntimeBytecode := newTestBytecode([]byte{0x60, 0x80, 0x60, 0x40, 0x52, 0x01}, 0x55)
+	assert.Nil(t, definitions.MatchBytecodeWithMode(nil, differentRuntimeBytecode, BytecodeMatchingModeIgnoreMetadata))
+}
+
+// TestWithAnnotatedAssertionMethods tests that assertion test methods are filtered by their NatSpec directives, and
+// that methods in the configured targets are never removed.
+func TestWithAnnotatedAssertionMethods(t *testing.T) {
+	contractAbi, err := abi.JSON(strings.NewReader(`[
+		{"type": "function", "name": "ignored", "stateMutability": "nonpayable", "inputs": [], "outputs": []},
+		{"type": "function", "name": "targeted", "stateMutability": "nonpayable", "inputs": [], "outputs": []},
+		{"type": "function", "name": "plain", "stateMutability": "nonpayable", "inputs": [], "outputs": []}
+	]`))
+	assert.NoError(t, err)
+	newAnnotatedContract := func() *Contract {
+		contract := NewContract("TestContract", "TestContract.sol", &types.CompiledContract{Abi: contractAbi}, nil)
+		contract.AssertionTestMethods = []abi.Method{contractAbi.Methods["ignored"], contractAbi.Methods["targeted"], contractAbi.Methods["plain"]}
+		contract.MethodDirectives = map[string][]string{
+			hex.EncodeToString(contractAbi.Methods["ignored"].ID):  {MethodDirectiveIgnore},
+			hex.EncodeToString(contractAbi.Methods["targeted"].ID): {MethodDirectiveTarget},
+		}
+		return contract
+	}
+	methodNames := func(contract *Contract) []string {
+		var names []string
+		for _, method := range contract.AssertionTestMethods {
+			names = append(names, method.Name)
+		}
+		return names
+	}
+
+	// Ignored methods should be removed, and if targeted, so should methods which are not targeted.
+	assert.EqualValues(t, []string{"targeted", "plain"}, methodNames(newAnnotatedContract().WithAnnotatedAssertionMethods(false, nil)))
+	assert.EqualValues(t, []string{"targeted"}, methodNames(newAnnotatedContract().WithAnnotatedAssertionMethods(true, nil)))
+
+	// Configured targets should take precedence over directives.
+	configuredTargets := []string{"TestContract.ignored()", "TestContract.plain()"}
+	assert.EqualValues(t, []string{"ignored", "targeted", "plain"}, methodNames(newAnnotatedContract().WithAnnotatedAssertionMethods(false, configuredTargets)))
+}
+
+// TestContractLookups tests that contract definitions can be looked up by name, and that their methods can be
+// resolved by selector, across all contract definitions.
+func TestContractLookups(t *testing.T) {
+	newTestContract := func(name string, sourcePath string, abiJson string) *Contract {
+		parsedAbi, err := abi.JSON(strings.NewReader(abiJson))
+		assert.NoError(t, err)
+		return NewContract(name, sourcePath, &types.CompiledContract{Abi: parsedAbi}, nil)
+	}
+	transferAbi := `[{"type": "function", "name": "transfer", "stateMutability": "nonpayable", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": []}]`
+	mintAbi := `[{"type": "function", "name": "mint", "stateMutability": "nonpayable", "inputs": [{"name": "amount", "type": "uint256"}], "outputs": []}]`
+	contracts := Contracts{
+		newTestContract("Token", "a.sol", transferAbi),
+		newTestContract("Minter", "b.sol", mintAbi),
+		newTestContract("Token", "c.sol", transferAbi),
+	}
+
+	// Contract definitions sharing a name should all be returned.
+	assert.EqualValues(t, Contracts{contracts[0], contracts[2]}, contracts.ByName("Token"))
+	assert.EqualValues(t, Contracts{contracts[1]}, contracts.ByName("Minter"))
+	assert.Empty(t, contracts.ByName("Unknown"))
+
+	// Methods should be resolved by selector in every contract definition declaring them.
+	transferSelector := contracts[0].CompiledContract().Abi.Methods["transfer"].ID
+	transferMethods := contracts.MethodsBySelector(transferSelector)
+	assert.Len(t, transferMethods, 2)
+	for i, contract := range []*Contract{contracts[0], contracts[2]} {
+		assert.Same(t, contract, transferMethods[i].Contract)
+		assert.EqualValues(t, "transfer(address,uint256)", transferMethods[i].Method.Sig)
+	}
+	mintMethod := contracts[1].MethodBySelector(contracts[1].CompiledContract().Abi.Methods["mint"].ID)
+	assert.NotNil(t, mintMethod)
+	assert.EqualValues(t, "mint", mintMethod.Name)
+
+	// Unknown or malformed selectors should not resolve to any method.
+	assert.Empty(t, contracts.MethodsBySelector([]byte{0xde, 0xad, 0xbe, 0xef}))
+	assert.Empty(t, contracts.MethodsBySelector(transferSelector[:3]))
+	assert.Nil(t, contracts[1].MethodBySelector(transferSelector))
+	assert.Nil(t, contracts[1].MethodBySelector(nil))
+}
@@ -1,15 +1,17 @@
 package corpus
 
 import (
-	"bytes"
 	"fmt"
 	"math/big"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/crytic/medusa/chain"
+	compilationTypes "github.com/crytic/medusa/compilation/types"
 	"github.com/crytic/medusa/fuzzing/calls"
 	"github.com/crytic/medusa/fuzzing/coverage"
 	"github.com/crytic/medusa/logging"
@@ -32,6 +34,10 @@ type Corpus struct {
 	// coverageMaps describes the total code coverage known to be achieved across all corpus call sequences.
 	coverageMaps *coverage.CoverageMaps
 
+	// fuzzingCoverageMaps describes the code coverage achieved by call sequences alone, excluding any coverage only
+	// achieved while deploying contracts and setting up the chain prior to fuzzing.
+	fuzzingCoverageMaps *coverage.CoverageMaps
+
 	// callSequenceFiles represents a corpus directory with files that should be used for mutations.
 	callSequenceFiles *corpusDirectory[calls.CallSequence]
 
@@ -39,34 +45,97 @@ type Corpus struct {
 	// to be saved by a test case provider. These are not used in mutations.
 	testResultSequenceFiles *corpusDirectory[calls.CallSequence]
 
+	// callSequenceMetadataFiles represents a corpus directory with files which describe CallSequenceMetadata for
+	// call sequences in callSequenceFiles and testResultSequenceFiles. Each metadata file shares the file name of the
+	// call sequence it describes.
+	callSequenceMetadataFiles *corpusDirectory[CallSequenceMetadata]
+
+	// recordElementMetadata indicates whether CallSequenceMetadata.Elements should be recorded for call sequences
+	// added to the corpus.
+	recordElementMetadata bool
+
+	// removeInvalidSequences indicates whether call sequences which fail to replay when the corpus is initialized
+	// should be removed from the corpus, rather than only disabled.
+	removeInvalidSequences bool
+
+	// readOnly indicates whether the Corpus should never write changes to disk, e.g. as it is loaded by a worker
+	// process whose discoveries are recorded by the fuzzer which spawned it.
+	readOnly bool
+
+	// callExecutionHandler describes a function called with each call of a corpus call sequence before it is executed
+	// when the Corpus is initialized, along with its index within the call sequence, or nil if none was set.
+	callExecutionHandler func(callIndex int, element *calls.CallSequenceElement) error
+
+	// contractLookupHashes maps coverage map lookup hashes for the init and runtime bytecode of each contract
+	// definition to the contract they refer to. This is used to resolve contract names for coverage deltas.
+	contractLookupHashes map[common.Hash]contractLookupHashTarget
+
 	// unexecutedCallSequences defines the callSequences which have not yet been executed by the fuzzer. As each item
 	// is selected for execution by the fuzzer on startup, it is removed. This way, all call sequences loaded from disk
 	// are executed to check for test failures.
-	unexecutedCallSequences []calls.CallSequence
+	unexecutedCallSequences []unexecutedCallSequence
+
+	// unexecutedCallsReturned describes the amount of calls in the call sequences returned by
+	// UnexecutedCallSequence so far. This is used to enforce a corpus replay limit.
+	unexecutedCallsReturned uint64
+
+	// deferredCallSequences defines the callSequences which were not yet executed by the fuzzer when the corpus replay
+	// limit was reached. These are not replayed, though they remain available for mutations.
+	deferredCallSequences []unexecutedCallSequence
 
 	// mutationTargetSequenceChooser is a provider that allows for weighted random selection of callSequences. If a
 	// call sequence was not found to be compatible with this run, it is not added to the chooser.
 	mutationTargetSequenceChooser *randomutils.WeightedRandomChooser[calls.CallSequence]
 
+	// mutationTagAllowlist describes the tags a call sequence must have at least one of to be added to the
+	// mutationTargetSequenceChooser, or nil if every call sequence is added.
+	mutationTagAllowlist map[string]struct{}
+
+	// mutationTargetChoicesByFileName maps the file names of call sequences loaded from disk to their choice in the
+	// mutationTargetSequenceChooser, so their weight can be updated once they are replayed.
+	mutationTargetChoicesByFileName map[string]*randomutils.WeightedRandomChoice[calls.CallSequence]
+
 	// callSequencesLock provides thread synchronization to prevent concurrent access errors into
 	// callSequences.
 	callSequencesLock sync.Mutex
 
+	// backgroundWriter describes the goroutine which writes corpus changes to disk, if background writes were started
+	// with StartBackgroundWrites.
+	backgroundWriter *backgroundWriter
+
+	// pendingWrites describes the count of call sequences added to the corpus since it was last written to disk.
+	pendingWrites atomic.Int64
+
+	// writeLock provides thread synchronization to prevent corpus changes from being written to disk concurrently.
+	writeLock sync.Mutex
+
 	// logger describes the Corpus's log object that can be used to log important events
 	logger *logging.Logger
 }
 
+// unexecutedCallSequence describes a call sequence loaded from disk which has not yet been executed by the fuzzer.
+type unexecutedCallSequence struct {
+	// sequence describes the call sequence to execute.
+	sequence calls.CallSequence
+
+	// fileName describes the name of the corpus file the call sequence was loaded from.
+	fileName string
+}
+
 // NewCorpus initializes a new Corpus object, reading artifacts from the provided directory. If the directory refers
 // to an empty path, artifacts will not be persistently stored.
 func NewCorpus(corpusDirectory string) (*Corpus, error) {
 	var err error
 	corpus := &Corpus{
-		storageDirectory:        corpusDirectory,
-		coverageMaps:            coverage.NewCoverageMaps(),
-		callSequenceFiles:       newCorpusDirectory[calls.CallSequence](""),
-		testResultSequenceFiles: newCorpusDirectory[calls.CallSequence](""),
-		unexecutedCallSequences: make([]calls.CallSequence, 0),
-		logger:                  logging.GlobalLogger.NewSubLogger("module", "corpus"),
+		storageDirectory:          corpusDirectory,
+		coverageMaps:              coverage.NewCoverageMaps(),
+		fuzzingCoverageMaps:       coverage.NewCoverageMaps(),
+		callSequenceFiles:         newHashedCorpusDirectory[calls.CallSequence]("", calls.CallSequence.Hash),
+		testResultSequenceFiles:   newHashedCorpusDirectory[calls.CallSequence]("", calls.CallSequence.Hash),
+		callSequenceMetadataFiles: newCorpusDirectory[CallSequenceMetadata](""),
+		contractLookupHashes:      make(map[common.Hash]contractLookupHashTarget),
+		unexecutedCallSequences:   make([]unexecutedCallSequence, 0),
+		logger:                    logging.GlobalLogger.NewSubLogger("module", "corpus"),
 	}
 
 	// If we have a corpus directory set, parse our call sequences.
@@ -92,11 +161,38 @@ func NewCorpus(corpusDirectory string) (*Corpus, error) {
 		if err != nil {
 			return nil, err
 		}
+
+		// Read metadata for coverage-increasing call sequences.
+		corpus.callSequenceMetadataFiles.path = filepath.Join(corpus.storageDirectory, "call_sequence_metadata")
+		err = corpus.callSequenceMetadataFiles.readFiles("*.json")
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return corpus, nil
 }
 
+// SetRemoveInvalidSequences sets whether call sequences which fail to replay when the Corpus is initialized should be
+// removed from it, deleting them from disk, rather than only being disabled. This should be called before the Corpus
+// is initialized.
+func (c *Corpus) SetRemoveInvalidSequences(enabled bool) {
+	c.removeInvalidSequences = enabled
+}
+
+// SetReadOnly sets whether the Corpus should never write changes to disk. Call sequences may still be added to a
+// read-only Corpus, but they are only kept in memory.
+func (c *Corpus) SetReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
+
+// SetCallExecutionHandler sets a function to call with each call of a corpus call sequence before it is executed when
+// the Corpus is initialized, along with its index within the call sequence. If the function returns an error,
+// initialization fails with it. This should be called before the Corpus is initialized.
+func (c *Corpus) SetCallExecutionHandler(handler func(callIndex int, element *calls.CallSequenceElement) error) {
+	c.callExecutionHandler = handler
+}
+
 // migrateLegacyCorpus is used to read in the legacy corpus standard where call sequences were stored in two separate
 // directories (mutable/immutable).
 func (c *Corpus) migrateLegacyCorpus() error {
@@ -177,6 +273,47 @@ func (c *Corpus) CoverageMaps() *coverage.CoverageMaps {
 	return c.coverageMaps
 }
 
+// FuzzingCoverageMaps exposes coverage details for all call sequences known to the corpus, excluding coverage which
+// was only achieved during contract deployment and chain setup.
+func (c *Corpus) FuzzingCoverageMaps() *coverage.CoverageMaps {
+	return c.fuzzingCoverageMaps
+}
+
+// AnalyzeSourceCoverage measures the source coverage achieved by the call sequences stored in the provided corpus
+// directory, without requiring a Fuzzer. The call sequences are replayed on a clone of the provided base test chain,
+// which must have the contracts in the provided compilations deployed as they were when the corpus was collected (e.g.
+// by the same deployer, in the same order), so the calls in the corpus resolve to the same contracts. Lines which were
+// only covered while deploying contracts on the base test chain are marked as covered during setup only.
+// Returns the source analysis, or an error if one occurs.
+func AnalyzeSourceCoverage(corpusDirectory string, baseTestChain *chain.TestChain, compilations []compilationTypes.Compilation) (*coverage.SourceAnalysis, error) {
+	// Create contract definitions for every contract in our compilations, so that corpus calls can be resolved to the
+	// contracts they target. Interfaces are never deployed, so we skip them.
+	contractDefinitions := make(contracts.Contracts, 0)
+	for i := 0; i < len(compilations); i++ {
+		compilation := &compilations[i]
+		for sourcePath, source := range compilation.SourcePathToArtifact {
+			for contractName := range source.Contracts {
+				contract := source.Contracts[contractName]
+				if contract.Kind == compilationTypes.ContractKindInterface {
+					continue
+				}
+				contractDefinitions = append(contractDefinitions, contracts.NewContract(contractName, sourcePath, &contract, compilation))
+			}
+		}
+	}
+
+	// Load the corpus and replay its call sequences to measure their coverage.
+	corpus, err := NewCorpus(corpusDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("could not analyze corpus source coverage, the corpus could not be loaded: %v", err)
+	}
+	_, _, err = corpus.Initialize(baseTestChain, contractDefinitions, contracts.BytecodeMatchingModeStrict, false)
+	if err != nil {
+		return nil, fmt.Errorf("could not analyze corpus source coverage, the corpus could not be replayed: %v", err)
+	}
+	return coverage.AnalyzeSourceCoverageWithSetup(compilations, corpus.CoverageMaps(), corpus.FuzzingCoverageMaps(), false)
+}
+
 // CallSequenceEntryCount returns the total number of call sequences that increased coverage and also any test results
 // that led to a failure.
 func (c *Corpus) CallSequenceEntryCount() (int, int) {
@@ -192,15 +329,16 @@ func (c *Corpus) ActiveMutableSequenceCount() int {
 	return c.mutationTargetSequenceChooser.ChoiceCount()
 }
 
-// RandomMutationTargetSequence returns a weighted random call sequence from the Corpus, or an error if one occurs.
-func (c *Corpus) RandomMutationTargetSequence() (calls.CallSequence, error) {
+// RandomMutationTargetSequence returns a weighted random call sequence from the Corpus, selected using the provided
+// random provider, or an error if one occurs.
+func (c *Corpus) RandomMutationTargetSequence(randomProvider *rand.Rand) (calls.CallSequence, error) {
 	// If we didn't initialize a chooser, return an error
 	if c.mutationTargetSequenceChooser == nil {
 		return nil, fmt.Errorf("corpus could not return a random call sequence because the corpus was not initialized")
 	}
 
 	// Pick a random call sequence, then clone it before returning it, so the original is untainted.
-	seq, err := c.mutationTargetSequenceChooser.Choose()
+	seq, err := c.mutationTargetSequenceChooser.ChooseWithRand(randomProvider)
 	if seq == nil || err != nil {
 		return nil, err
 	}
@@ -211,51 +349,86 @@ func (c *Corpus) RandomMutationTargetSequence() (calls.CallSequence, error) {
 // chain, using the map of deployed contracts (e.g. to check for non-existent method called, due to code changes).
 // Valid call sequences are added to the list of un-executed sequences the fuzzer should execute first.
 // If this sequence list being initialized is for use with mutations, it is added to the mutationTargetSequenceChooser.
-// Returns an error if one occurs.
-func (c *Corpus) initializeSequences(sequenceFiles *corpusDirectory[calls.CallSequence], testChain *chain.TestChain, deployedContracts map[common.Address]*contracts.Contract, useInMutations bool) error {
+// Otherwise, only sequences recorded with a weight multiplier in their metadata are added to it, weighted by it.
+// Sequences without a tag in the mutation tag allowlist are never added to it, though they are still executed.
+// Calls which reference outdated ABIs disable the sequence they belong to, unless dropOutdatedCalls is set, in which
+// case they are dropped from the sequence instead.
+// Returns the number of calls found to reference outdated ABIs, the total number of calls across all sequences, or
+// an error if one occurs.
+func (c *Corpus) initializeSequences(sequenceFiles *corpusDirectory[calls.CallSequence], testChain *chain.TestChain, deployedContracts map[common.Address]*contracts.Contract, useInMutations bool, dropOutdatedCalls bool) (int, int, error) {
 	// Cache the base block index so that you can reset back to it after every sequence
 	baseBlockIndex := uint64(len(testChain.CommittedBlocks()))
 
 	// Loop for each sequence
 	var err error
+	totalOutdatedCalls, totalCalls := 0, 0
+	invalidFileNames := make([]string, 0)
 	for _, sequenceFileData := range sequenceFiles.files {
 		// Unwrap the underlying sequence.
 		sequence := sequenceFileData.data
+		totalCalls += len(sequence)
 
 		// Define a variable to track whether we should disable this sequence (if it is no longer applicable in some
 		// way).
 		sequenceInvalidError := error(nil)
-		fetchElementFunc := func(currentIndex int) (*calls.CallSequenceElement, error) {
-			// If we are at the end of our sequence, return nil indicating we should stop executing.
-			if currentIndex >= len(sequence) {
-				return nil, nil
-			}
 
-			// If we are deploying a contract and not targeting one with this call, there should be no work to do.
-			currentSequenceElement := sequence[currentIndex]
-			if currentSequenceElement.Call.To == nil {
-				return currentSequenceElement, nil
-			}
+		// Define variables to track calls which reference outdated ABIs. If we are dropping such calls, the remaining
+		// calls are collected into a new sequence. The delays of any dropped calls are carried over to the next
+		// remaining call, so it executes in the same block it would have otherwise, and the nonces of the remaining
+		// calls are renumbered to account for the calls dropped from each sender.
+		outdatedCalls := 0
+		outdatedCallError := error(nil)
+		remainingSequence := make(calls.CallSequence, 0, len(sequence))
+		sequenceIndex := 0
+		var droppedBlockNumberDelay, droppedBlockTimestampDelay uint64
+		droppedSenderCalls := make(map[common.Address]uint64)
 
-			// We are calling a contract with this call, ensure we can resolve the contract call is targeting.
-			resolvedContract, resolvedContractExists := deployedContracts[*currentSequenceElement.Call.To]
-			if !resolvedContractExists {
-				sequenceInvalidError = fmt.Errorf("contract at address '%v' could not be resolved", currentSequenceElement.Call.To.String())
-				return nil, nil
-			}
-			currentSequenceElement.Contract = resolvedContract
-
-			// Next, if our sequence element uses ABI values to produce call data, our deserialized data is not yet
-			// sufficient for runtime use, until we use it to resolve runtime references.
-			callAbiValues := currentSequenceElement.Call.DataAbiValues
-			if callAbiValues != nil {
-				sequenceInvalidError = callAbiValues.Resolve(currentSequenceElement.Contract.CompiledContract().Abi)
-				if sequenceInvalidError != nil {
-					sequenceInvalidError = fmt.Errorf("error resolving method in contract '%v': %v", currentSequenceElement.Contract.Name(), sequenceInvalidError)
+		fetchElementFunc := func(currentIndex int) (*calls.CallSequenceElement, error) {
+			for ; sequenceIndex < len(sequence); sequenceIndex++ {
+				// Resolve our element, checking whether it references an outdated ABI.
+				currentSequenceElement := sequence[sequenceIndex]
+				outdatedAbi, err := c.resolveCallSequenceElement(currentSequenceElement, deployedContracts)
+				if err != nil && !outdatedAbi {
+					sequenceInvalidError = err
 					return nil, nil
 				}
+
+				// If the element references an outdated ABI, we either drop it and move on to the next element, or
+				// disable the sequence. If we disable the sequence, we still count the outdated calls remaining in it.
+				if outdatedAbi {
+					outdatedCalls++
+					if outdatedCallError == nil {
+						outdatedCallError = err
+					}
+					if !dropOutdatedCalls {
+						outdatedCalls += c.countOutdatedCalls(sequence[sequenceIndex+1:], deployedContracts)
+						sequenceInvalidError = err
+						return nil, nil
+					}
+					droppedBlockNumberDelay += currentSequenceElement.BlockNumberDelay
+					droppedBlockTimestampDelay += currentSequenceElement.BlockTimestampDelay
+					droppedSenderCalls[currentSequenceElement.Call.From]++
+					continue
+				}
+
+				// Carry over the delays and nonces of any elements dropped before this one, then return it for
+				// execution.
+				currentSequenceElement.BlockNumberDelay += droppedBlockNumberDelay
+				currentSequenceElement.BlockTimestampDelay += droppedBlockTimestampDelay
+				currentSequenceElement.Call.Nonce -= droppedSenderCalls[currentSequenceElement.Call.From]
+				droppedBlockNumberDelay, droppedBlockTimestampDelay = 0, 0
+				remainingSequence = append(remainingSequence, currentSequenceElement)
+				sequenceIndex++
+				if c.callExecutionHandler != nil {
+					if err = c.callExecutionHandler(len(remainingSequence)-1, currentSequenceElement); err != nil {
+						return nil, err
+					}
+				}
+				return currentSequenceElement, nil
 			}
-			return currentSequenceElement, nil
+
+			// If we are at the end of our sequence, return nil indicating we should stop executing.
+			return nil, nil
 		}
 
 		// Define actions to perform after executing each call in the sequence.
@@ -272,6 +445,10 @@ func (c *Corpus) initializeSequences(sequenceFiles *corpusDirectory[calls.CallSe
 			if covErr != nil {
 				return true, covErr
 			}
+			_, _, covErr = c.fuzzingCoverageMaps.Update(covMaps)
+			if covErr != nil {
+				return true, covErr
+			}
 			return false, nil
 		}
 
@@ -280,43 +457,163 @@ func (c *Corpus) initializeSequences(sequenceFiles *corpusDirectory[calls.CallSe
 
 		// If we failed to replay a sequence and measure coverage due to an unexpected error, report it.
 		if err != nil {
-			return fmt.Errorf("failed to initialize coverage maps from corpus, encountered an error while executing call sequence: %v", err)
+			return 0, 0, fmt.Errorf("failed to initialize coverage maps from corpus, encountered an error while executing call sequence: %v", err)
+		}
+
+		// If we dropped outdated calls, the remaining calls form our sequence. If none remain, we disable it.
+		if sequenceInvalidError == nil && outdatedCalls > 0 {
+			if len(remainingSequence) == 0 {
+				sequenceInvalidError = fmt.Errorf("all calls were dropped: %v", outdatedCallError)
+			} else {
+				sequence = remainingSequence
+				sequenceFileData.data = sequence
+			}
+		}
+
+		// Warn about any calls which referenced outdated ABIs in this corpus item.
+		if outdatedCalls > 0 {
+			totalOutdatedCalls += outdatedCalls
+			if sequenceInvalidError == nil {
+				c.logger.Warn("Corpus item ", colors.Bold, sequenceFileData.fileName, colors.Reset, " had ", outdatedCalls, "/", len(sequenceFileData.data)+outdatedCalls, " calls dropped as they reference outdated ABIs", outdatedCallError)
+			} else {
+				c.logger.Warn("Corpus item ", colors.Bold, sequenceFileData.fileName, colors.Reset, " disabled, ", outdatedCalls, "/", len(sequence), " of its calls reference outdated ABIs", sequenceInvalidError)
+			}
 		}
 
 		// If the sequence was replayed successfully, we add it. If it was not, we exclude it with a warning.
 		if sequenceInvalidError == nil {
-			if useInMutations && c.mutationTargetSequenceChooser != nil {
-				c.mutationTargetSequenceChooser.AddChoices(randomutils.NewWeightedRandomChoice[calls.CallSequence](sequence, big.NewInt(1)))
+			if c.mutationTargetSequenceChooser != nil && c.callSequenceFileMutationAllowed(sequenceFileData.fileName, sequence) {
+				var mutationChoice *randomutils.WeightedRandomChoice[calls.CallSequence]
+				if useInMutations {
+					mutationChoice = randomutils.NewWeightedRandomChoice[calls.CallSequence](sequence, big.NewInt(1))
+				} else if weightMultiplier := c.testResultWeightMultiplier(sequenceFileData.fileName); weightMultiplier != nil {
+					mutationChoice = randomutils.NewWeightedRandomChoice[calls.CallSequence](sequence, scaleMutationChooserWeight(nil, weightMultiplier))
+				}
+				if mutationChoice != nil {
+					c.mutationTargetSequenceChooser.AddChoices(mutationChoice)
+					c.mutationTargetChoicesByFileName[sequenceFileData.fileName] = mutationChoice
+				}
 			}
-			c.unexecutedCallSequences = append(c.unexecutedCallSequences, sequence)
+			c.unexecutedCallSequences = append(c.unexecutedCallSequences, unexecutedCallSequence{sequence: sequence, fileName: sequenceFileData.fileName})
 		} else {
-			c.logger.Debug("Corpus item ", colors.Bold, sequenceFileData.fileName, colors.Reset, " disabled due to error when replaying it", sequenceInvalidError)
+			if outdatedCalls == 0 {
+				c.logger.Debug("Corpus item ", colors.Bold, sequenceFileData.fileName, colors.Reset, " disabled due to error when replaying it", sequenceInvalidError)
+			}
+			invalidFileNames = append(invalidFileNames, sequenceFileData.fileName)
 		}
 
 		// Revert chain state to our starting point to test the next sequence.
 		if err := testChain.RevertToBlockIndex(baseBlockIndex); err != nil {
-			return fmt.Errorf("failed to reset the chain while seeding coverage: %v", err)
+			return 0, 0, fmt.Errorf("failed to reset the chain while seeding coverage: %v", err)
 		}
 	}
-	return nil
+
+	// If configured, remove the call sequences which failed to replay, along with their metadata.
+	if c.removeInvalidSequences {
+		for _, fileName := range invalidFileNames {
+			if err = sequenceFiles.deleteFile(fileName); err != nil {
+				return 0, 0, fmt.Errorf("failed to remove corpus item which failed to replay: %v", err)
+			}
+			if err = c.callSequenceMetadataFiles.deleteFile(fileName); err != nil {
+				return 0, 0, fmt.Errorf("failed to remove metadata of corpus item which failed to replay: %v", err)
+			}
+			c.logger.Warn("Corpus item ", colors.Bold, fileName, colors.Reset, " removed as it failed to replay")
+		}
+	}
+	return totalOutdatedCalls, totalCalls, nil
+}
+
+// resolveCallSequenceElement is a helper method for initializeSequences. It resolves the contract a call sequence
+// element targets using the map of deployed contracts, and resolves any ABI values used to produce its call data
+// against that contract's ABI.
+// Returns an error if the element could not be resolved, along with a boolean indicating whether this was because
+// the element references an outdated ABI.
+func (c *Corpus) resolveCallSequenceElement(element *calls.CallSequenceElement, deployedContracts map[common.Address]*contracts.Contract) (bool, error) {
+	// If we are deploying a contract and not targeting one with this call, there should be no work to do.
+	if element.Call.To == nil {
+		return false, nil
+	}
+
+	// We are calling a contract with this call, ensure we can resolve the contract call is targeting.
+	resolvedContract, resolvedContractExists := deployedContracts[*element.Call.To]
+	if !resolvedContractExists {
+		return false, fmt.Errorf("contract at address '%v' could not be resolved", element.Call.To.String())
+	}
+	element.Contract = resolvedContract
+
+	// Next, if our sequence element uses ABI values to produce call data, our deserialized data is not yet
+	// sufficient for runtime use, until we use it to resolve runtime references.
+	callAbiValues := element.Call.DataAbiValues
+	if callAbiValues != nil {
+		err := callAbiValues.Resolve(element.Contract.CompiledContract().Abi)
+		if err != nil {
+			return true, fmt.Errorf("error resolving method in contract '%v': %v", element.Contract.Name(), err)
+		}
+	}
+	return false, nil
+}
+
+// countOutdatedCalls is a helper method for initializeSequences. It counts the call sequence elements which reference
+// outdated ABIs without resolving or executing them, so that they can be reported for a sequence which is disabled.
+// Elements targeting contracts which cannot be resolved using the map of deployed contracts are not counted.
+// Returns the number of elements which reference outdated ABIs.
+func (c *Corpus) countOutdatedCalls(sequence calls.CallSequence, deployedContracts map[common.Address]*contracts.Contract) int {
+	outdatedCalls := 0
+	for _, element := range sequence {
+		if element.Call.To == nil || element.Call.DataAbiValues == nil {
+			continue
+		}
+		resolvedContract, resolvedContractExists := deployedContracts[*element.Call.To]
+		if !resolvedContractExists {
+			continue
+		}
+
+		// Resolve a copy of the ABI values, so the element itself is left untouched.
+		callAbiValues, err := element.Call.DataAbiValues.Clone()
+		if err == nil {
+			err = callAbiValues.Resolve(resolvedContract.CompiledContract().Abi)
+		}
+		if err != nil {
+			outdatedCalls++
+		}
+	}
+	return outdatedCalls
 }
 
 // Initialize initializes any runtime data needed for a Corpus on startup. Call sequences are replayed on the post-setup
-// (deployment) test chain to calculate coverage, while resolving references to compiled contracts.
+// (deployment) test chain to calculate coverage, while resolving references to compiled contracts. Deployed contracts
+// are matched to contract definitions using the provided contracts.BytecodeMatchingMode.
 // Returns the active number of corpus items, total number of corpus items, or an error if one occurred. If an error
 // is returned, then the corpus counts returned will always be zero.
-func (c *Corpus) Initialize(baseTestChain *chain.TestChain, contractDefinitions contracts.Contracts) (int, int, error) {
+func (c *Corpus) Initialize(baseTestChain *chain.TestChain, contractDefinitions contracts.Contracts, contractMatchingMode contracts.BytecodeMatchingMode, dropOutdatedCalls bool) (int, int, error) {
 	// Acquire our call sequences lock during the duration of this method.
 	c.callSequencesLock.Lock()
 	defer c.callSequencesLock.Unlock()
 
 	// Initialize our call sequence structures.
 	c.mutationTargetSequenceChooser = randomutils.NewWeightedRandomChooser[calls.CallSequence]()
-	c.unexecutedCallSequences = make([]calls.CallSequence, 0)
+	c.mutationTargetChoicesByFileName = make(map[string]*randomutils.WeightedRandomChoice[calls.CallSequence])
+	c.unexecutedCallSequences = make([]unexecutedCallSequence, 0)
+	c.unexecutedCallsReturned = 0
+	c.deferredCallSequences = make([]unexecutedCallSequence, 0)
+
+	// Record the coverage map lookup hashes for each contract definition, so we can resolve coverage deltas to
+	// contract names.
+	c.contractLookupHashes = make(map[common.Hash]contractLookupHashTarget)
+	for _, contract := range contractDefinitions {
+		compiledContract := contract.CompiledContract()
+		if len(compiledContract.InitBytecode) > 0 {
+			c.contractLookupHashes[coverage.GetContractCoverageMapHash(compiledContract.InitBytecode, true)] = contractLookupHashTarget{name: contract.Name(), init: true}
+		}
+		if len(compiledContract.RuntimeBytecode) > 0 {
+			c.contractLookupHashes[coverage.GetContractCoverageMapHash(compiledContract.RuntimeBytecode, false)] = contractLookupHashTarget{name: contract.Name(), init: false}
+		}
+	}
 
-	// Create a coverage tracer to track coverage across all blocks.
+	// Create a coverage tracer to track coverage across all blocks. Corpus call sequences are only replayed once, so
+	// we always record init bytecode coverage here.
 	c.coverageMaps = coverage.NewCoverageMaps()
-	coverageTracer := coverage.NewCoverageTracer()
+	coverageTracer := coverage.NewCoverageTracer(true)
 
 	// Create our structure and event listeners to track deployed contracts
 	deployedContracts := make(map[common.Address]*contracts.Contract, 0)
@@ -328,14 +625,14 @@ func (c *Corpus) Initialize(baseTestChain *chain.TestChain, contractDefinitions
 
 		// We also track any contract deployments, so we can resolve contract/method definitions for corpus call
 		// sequences.
-		newChain.Events.ContractDeploymentAddedEventEmitter.Subscribe(func(event chain.ContractDeploymentsAddedEvent) error {
-			matchedContract := contractDefinitions.MatchBytecode(event.Contract.InitBytecode, event.Contract.RuntimeBytecode)
+		newChain.Events.ContractDeploymentAddedEventEmitter.SubscribeNamed("corpus", func(event chain.ContractDeploymentsAddedEvent) error {
+			matchedContract := contractDefinitions.MatchBytecodeWithMode(event.Contract.InitBytecode, event.Contract.RuntimeBytecode, contractMatchingMode)
 			if matchedContract != nil {
 				deployedContracts[event.Contract.Address] = matchedContract
 			}
 			return nil
 		})
-		newChain.Events.ContractDeploymentRemovedEventEmitter.Subscribe(func(event chain.ContractDeploymentsRemovedEvent) error {
+		newChain.Events.ContractDeploymentRemovedEventEmitter.SubscribeNamed("corpus", func(event chain.ContractDeploymentsRemovedEvent) error {
 			delete(deployedContracts, event.Contract.Address)
 			return nil
 		})
@@ -345,8 +642,10 @@ func (c *Corpus) Initialize(baseTestChain *chain.TestChain, contractDefinitions
 		return 0, 0, fmt.Errorf("failed to initialize coverage maps, base test chain cloning encountered error: %v", err)
 	}
 
-	// Set our coverage maps to those collected when replaying all blocks when cloning.
+	// Set our coverage maps to those collected when replaying all blocks when cloning. These blocks deploy contracts
+	// and set up the chain prior to fuzzing, so they do not contribute to our fuzzing coverage maps.
 	c.coverageMaps = coverage.NewCoverageMaps()
+	c.fuzzingCoverageMaps = coverage.NewCoverageMaps()
 	for _, block := range testChain.CommittedBlocks() {
 		for _, messageResults := range block.MessageResults {
 			// Grab the coverage maps
@@ -369,16 +668,27 @@ func (c *Corpus) Initialize(baseTestChain *chain.TestChain, contractDefinitions
 	// The order of initializations here is important, as it determines the order of "unexecuted sequences" to replay
 	// when the fuzzer's worker starts up. We want to replay test results first, so that other corpus items
 	// do not trigger the same test failures instead.
-	err = c.initializeSequences(c.testResultSequenceFiles, testChain, deployedContracts, false)
+	testResultOutdatedCalls, testResultCalls, err := c.initializeSequences(c.testResultSequenceFiles, testChain, deployedContracts, false, dropOutdatedCalls)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	err = c.initializeSequences(c.callSequenceFiles, testChain, deployedContracts, true)
+	callSequenceOutdatedCalls, callSequenceCalls, err := c.initializeSequences(c.callSequenceFiles, testChain, deployedContracts, true, dropOutdatedCalls)
 	if err != nil {
 		return 0, 0, err
 	}
 
+	// Summarize any calls which referenced outdated ABIs, as these are likely the result of contract changes since
+	// the corpus was recorded.
+	if outdatedCalls := testResultOutdatedCalls + callSequenceOutdatedCalls; outdatedCalls > 0 {
+		totalCalls := testResultCalls + callSequenceCalls
+		if dropOutdatedCalls {
+			c.logger.Warn(outdatedCalls, "/", totalCalls, " corpus calls reference outdated ABIs and were dropped")
+		} else {
+			c.logger.Warn(outdatedCalls, "/", totalCalls, " corpus calls reference outdated ABIs, the corpus items containing them were disabled")
+		}
+	}
+
 	// Calculate corpus health metrics
 	corpusSequencesTotal := len(c.callSequenceFiles.files) + len(c.testResultSequenceFiles.files)
 	corpusSequencesActive := len(c.unexecutedCallSequences)
@@ -386,73 +696,209 @@ func (c *Corpus) Initialize(baseTestChain *chain.TestChain, contractDefinitions
 	return corpusSequencesActive, corpusSequencesTotal, nil
 }
 
-// addCallSequence adds a call sequence to the corpus in a given corpus directory.
-// Returns an error, if one occurs.
-func (c *Corpus) addCallSequence(sequenceFiles *corpusDirectory[calls.CallSequence], sequence calls.CallSequence, useInMutations bool, mutationChooserWeight *big.Int, flushImmediately bool) error {
+// addCallSequence adds a call sequence to the corpus in a given corpus directory. If metadata is provided, it is
+// stored alongside the call sequence.
+// Returns a boolean indicating whether the call sequence was added (false if it already existed), or an error, if one
+// occurs.
+func (c *Corpus) addCallSequence(sequenceFiles *corpusDirectory[calls.CallSequence], sequence calls.CallSequence, metadata *CallSequenceMetadata, useInMutations bool, mutationChooserWeight *big.Int, flushImmediately bool) (bool, error) {
+	// Stage the call sequence and add it immediately.
+	entry, err := newStagedCallSequence(sequenceFiles, sequence, c.withElementMetadata(metadata, sequence), useInMutations, mutationChooserWeight)
+	if err != nil {
+		return false, err
+	}
+	addedCount, err := c.addCallSequences([]stagedCallSequence{entry})
+	if err != nil {
+		return false, err
+	}
+
+	// Flush changes to disk if requested.
+	if addedCount > 0 && flushImmediately {
+		return true, c.requestFlush()
+	}
+	return addedCount > 0, nil
+}
+
+// addCallSequences adds a batch of staged call sequences to the corpus, skipping any which already exist in their
+// corpus directory (including those added earlier in the same batch). The corpus is only locked once for the batch,
+// and the mutation chooser is updated with all added call sequences at once.
+// Returns the count of call sequences which were added, or an error if one occurs.
+func (c *Corpus) addCallSequences(entries []stagedCallSequence) (int, error) {
 	// Acquire a thread lock during modification of call sequence lists.
 	c.callSequencesLock.Lock()
+	defer c.callSequencesLock.Unlock()
 
-	// Check if call sequence has been added before, if so, exit without any action.
-	seqHash, err := sequence.Hash()
-	if err != nil {
-		return err
-	}
+	mutationChoices := make([]*randomutils.WeightedRandomChoice[calls.CallSequence], 0)
+	addedCount := 0
+	for _, entry := range entries {
+		// Verify it is unique, if it is not, we skip it to avoid duplicate sequences being added. The corpus directory
+		// tracks the hashes of its call sequences as they are added, so they are not hashed again.
+		if entry.sequenceFiles.containsHash(entry.hash) {
+			continue
+		}
 
-	// Verify no existing corpus item hash this same hash.
-	for _, existingSeq := range sequenceFiles.files {
-		// Calculate the existing sequence hash
-		existingSeqHash, err := existingSeq.data.Hash()
+		// Update our corpus directory with the new entry.
+		fileName := fmt.Sprintf("%v-%v.json", time.Now().UnixNano(), uuid.New().String())
+		err := entry.sequenceFiles.addFile(fileName, entry.sequence)
 		if err != nil {
-			c.callSequencesLock.Unlock()
-			return err
+			return addedCount, err
 		}
+		addedCount++
 
-		// Verify it is unique, if it is not, we quit immediately to avoid duplicate sequences being added.
-		if bytes.Equal(existingSeqHash[:], seqHash[:]) {
-			c.callSequencesLock.Unlock()
-			return nil
+		// If we have metadata for this entry, store it under the same file name.
+		if entry.metadata != nil {
+			err = c.callSequenceMetadataFiles.addFile(fileName, *entry.metadata)
+			if err != nil {
+				return addedCount, err
+			}
 		}
-	}
 
-	// Update our corpus directory with the new entry.
-	fileName := fmt.Sprintf("%v-%v.json", time.Now().UnixNano(), uuid.New().String())
-	err = sequenceFiles.addFile(fileName, sequence)
-	if err != nil {
-		return err
-	}
-
-	// If we want to use this sequence in mutations and initialized a chooser, add our call sequence item to it.
-	if useInMutations && c.mutationTargetSequenceChooser != nil {
-		if mutationChooserWeight == nil {
-			mutationChooserWeight = big.NewInt(1)
+		// If we want to use this sequence in mutations, initialized a chooser, and its tags are allowed, we'll add our
+		// call sequence item to it once the batch is processed.
+		if entry.useInMutations && c.mutationTargetSequenceChooser != nil && c.mutationTagsAllowed(entry.tags) {
+			mutationChooserWeight := entry.mutationChooserWeight
+			if mutationChooserWeight == nil {
+				mutationChooserWeight = big.NewInt(1)
+			}
+			mutationChoices = append(mutationChoices, randomutils.NewWeightedRandomChoice[calls.CallSequence](entry.sequence, mutationChooserWeight))
 		}
-		c.mutationTargetSequenceChooser.AddChoices(randomutils.NewWeightedRandomChoice[calls.CallSequence](sequence, mutationChooserWeight))
 	}
 
-	// Unlock now, as flushing will lock on its own.
-	c.callSequencesLock.Unlock()
-
-	// Flush changes to disk if requested.
-	if flushImmediately {
-		return c.Flush()
-	} else {
-		return nil
+	// Add all new mutation targets to our chooser at once.
+	if len(mutationChoices) > 0 {
+		c.mutationTargetSequenceChooser.AddChoices(mutationChoices...)
 	}
+	c.pendingWrites.Add(int64(addedCount))
+	return addedCount, nil
 }
 
 // AddTestResultCallSequence adds a call sequence recorded to the corpus due to a test case provider flagging it to be
 // recorded.
 // Returns an error, if one occurs.
 func (c *Corpus) AddTestResultCallSequence(callSequence calls.CallSequence, mutationChooserWeight *big.Int, flushImmediately bool) error {
-	return c.addCallSequence(c.testResultSequenceFiles, callSequence, false, mutationChooserWeight, flushImmediately)
+	_, err := c.addCallSequence(c.testResultSequenceFiles, callSequence, nil, false, mutationChooserWeight, flushImmediately)
+	return err
+}
+
+// AddWeightedTestResultCallSequence adds a call sequence recorded to the corpus due to a test case provider flagging
+// it to be recorded, which should also be used as a mutation target. Its mutation chooser weight is scaled by the
+// provided multiplier (e.g. to favor call sequences which achieved higher optimization values), where a nil
+// multiplier is treated as 1.
+// Returns an error, if one occurs.
+func (c *Corpus) AddWeightedTestResultCallSequence(callSequence calls.CallSequence, mutationChooserWeight *big.Int, weightMultiplier *big.Int, flushImmediately bool) error {
+	metadata := &CallSequenceMetadata{WeightMultiplier: scaleMutationChooserWeight(nil, weightMultiplier)}
+	_, err := c.addCallSequence(c.testResultSequenceFiles, callSequence, metadata, true, scaleMutationChooserWeight(mutationChooserWeight, weightMultiplier), flushImmediately)
+	return err
+}
+
+// TestResultCallSequenceFilePath obtains the path of the file on disk which records the provided call sequence as a
+// test result.
+// Returns the file path, or an empty string if the corpus is not written to disk, or the call sequence has not been
+// recorded and written to disk yet. Returns an error if one occurs.
+func (c *Corpus) TestResultCallSequenceFilePath(callSequence calls.CallSequence) (string, error) {
+	// If our corpus directory is empty, no test results are written to disk.
+	sequenceFiles := c.testResultSequenceFiles
+	if sequenceFiles.path == "" {
+		return "", nil
+	}
+
+	// Search for a written file recording an equivalent call sequence.
+	hash, err := callSequence.Hash()
+	if err != nil {
+		return "", err
+	}
+	sequenceFiles.filesLock.Lock()
+	defer sequenceFiles.filesLock.Unlock()
+	for _, file := range sequenceFiles.files {
+		if file.writtenToDisk && file.hash == hash {
+			return filepath.Join(sequenceFiles.path, file.fileName), nil
+		}
+	}
+	return "", nil
 }
 
 // CheckSequenceCoverageAndUpdate checks if the most recent call executed in the provided call sequence achieved
 // coverage the Corpus did not with any of its call sequences. If it did, the call sequence is added to the corpus
-// and the Corpus coverage maps are updated accordingly.
-// Returns an error if one occurs.
-func (c *Corpus) CheckSequenceCoverageAndUpdate(callSequence calls.CallSequence, mutationChooserWeight *big.Int, flushImmediately bool) error {
-	// If we have coverage-guided fuzzing disabled or no calls in our sequence, there is nothing to do.
+// and the Corpus coverage maps are updated accordingly. The coverage markers newly achieved by the call are recorded
+// in the metadata of the new corpus entry.
+// Returns the coverage.CoverageDelta describing the new coverage if the call sequence was added to the corpus, or nil
+// if it was not. Returns an error if one occurs.
+func (c *Corpus) CheckSequenceCoverageAndUpdate(callSequence calls.CallSequence, mutationChooserWeight *big.Int, flushImmediately bool) (*coverage.CoverageDelta, error) {
+	// Check whether the call sequence achieved new coverage.
+	coverageDelta, err := c.checkSequenceCoverage(callSequence)
+	if err != nil || coverageDelta == nil {
+		return nil, err
+	}
+
+	// If we achieved new coverage, save this sequence for mutation purposes.
+	added, err := c.addCallSequence(c.callSequenceFiles, callSequence, &CallSequenceMetadata{CoverageDelta: coverageDelta}, true, mutationChooserWeight, flushImmediately)
+	if err != nil {
+		return nil, err
+	}
+	if added {
+		return coverageDelta, nil
+	}
+	return nil, nil
+}
+
+// AddCallSequenceWithCoverageDelta adds a call sequence which achieved the provided new coverage elsewhere (e.g. in a
+// worker process with its own Corpus) to the Corpus, merging the coverage into the Corpus coverage maps. The coverage
+// is recorded in the metadata of the new corpus entry.
+// Returns a boolean indicating whether the call sequence was added, as it may already exist in the Corpus, or an error
+// if one occurs.
+func (c *Corpus) AddCallSequenceWithCoverageDelta(callSequence calls.CallSequence, coverageDelta *coverage.CoverageDelta, mutationChooserWeight *big.Int, flushImmediately bool) (bool, error) {
+	// Merge the coverage into our total and fuzzing coverage maps.
+	coverageMaps := coverageDelta.CoverageMaps()
+	if _, _, err := c.coverageMaps.Update(coverageMaps); err != nil {
+		return false, err
+	}
+	if _, _, err := c.fuzzingCoverageMaps.Update(coverageMaps); err != nil {
+		return false, err
+	}
+
+	// Save this sequence for mutation purposes.
+	return c.addCallSequence(c.callSequenceFiles, callSequence, &CallSequenceMetadata{CoverageDelta: coverageDelta}, true, mutationChooserWeight, flushImmediately)
+}
+
+// checkSequenceCoverage checks if the most recent call executed in the provided call sequence achieved coverage the
+// Corpus did not with any of its call sequences, updating the Corpus coverage maps accordingly. The call sequence is
+// not added to the corpus.
+// Returns the coverage.CoverageDelta describing the new coverage, or nil if there was none. Returns an error if one
+// occurs.
+func (c *Corpus) checkSequenceCoverage(callSequence calls.CallSequence) (*coverage.CoverageDelta, error) {
+	// Obtain our coverage maps for our last call. If we have none, there is nothing to do.
+	lastMessageCoverageMaps := takeLastCallCoverageMaps(callSequence)
+	if lastMessageCoverageMaps == nil {
+		return nil, nil
+	}
+
+	// Merge the coverage maps into our total coverage maps and check if we had an update.
+	coverageUpdated, revertedCoverageUpdated, coverageDelta, err := c.coverageMaps.UpdateWithDelta(lastMessageCoverageMaps)
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge the coverage maps into our fuzzing coverage maps as well. The two may share underlying coverage data
+	// after merging, but this is safe, as every update to our fuzzing coverage maps is also applied to our total
+	// coverage maps.
+	_, _, err = c.fuzzingCoverageMaps.Update(lastMessageCoverageMaps)
+	if err != nil {
+		return nil, err
+	}
+
+	// If we had an increase in non-reverted or reverted coverage, resolve the contracts which achieved new coverage,
+	// so the delta is human-readable.
+	if (coverageUpdated || revertedCoverageUpdated) && !coverageDelta.Empty() {
+		coverageDelta.ResolveContractNames(c.resolveLookupHash)
+		return coverageDelta, nil
+	}
+	return nil, nil
+}
+
+// takeLastCallCoverageMaps obtains the coverage maps collected for the most recent call executed in the provided call
+// sequence, removing them from its message results to free memory.
+// Returns the coverage maps, or nil if the call sequence has no calls or no coverage was collected for its last call
+// (e.g. coverage-guided fuzzing is disabled).
+func takeLastCallCoverageMaps(callSequence calls.CallSequence) *coverage.CoverageMaps {
 	if len(callSequence) == 0 {
 		return nil
 	}
@@ -463,41 +909,33 @@ func (c *Corpus) CheckSequenceCoverageAndUpdate(callSequence calls.CallSequence,
 	lastMessageResult := lastCallChainReference.Block.MessageResults[lastCallChainReference.TransactionIndex]
 	lastMessageCoverageMaps := coverage.GetCoverageTracerResults(lastMessageResult)
 
-	// If we have none, because a coverage tracer wasn't attached when processing this call, we can stop.
-	if lastMessageCoverageMaps == nil {
-		return nil
-	}
-
 	// Memory optimization: Remove them from the results now that we obtained them, to free memory later.
-	coverage.RemoveCoverageTracerResults(lastMessageResult)
-
-	// Merge the coverage maps into our total coverage maps and check if we had an update.
-	coverageUpdated, revertedCoverageUpdated, err := c.coverageMaps.Update(lastMessageCoverageMaps)
-	if err != nil {
-		return err
+	if lastMessageCoverageMaps != nil {
+		coverage.RemoveCoverageTracerResults(lastMessageResult)
 	}
-
-	// If we had an increase in non-reverted or reverted coverage, we save the sequence.
-	if coverageUpdated || revertedCoverageUpdated {
-		// If we achieved new coverage, save this sequence for mutation purposes.
-		err = c.addCallSequence(c.callSequenceFiles, callSequence, true, mutationChooserWeight, flushImmediately)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	return lastMessageCoverageMaps
 }
 
 // UnexecutedCallSequence returns a call sequence loaded from disk which has not yet been returned by this method.
 // It is intended to be used by the fuzzer to run all un-executed call sequences (without mutations) to check for test
 // failures. If a call sequence is returned, it will not be returned by this method again.
+// If callLimit is non-zero, no further call sequences are returned once the call sequences returned contain at least
+// callLimit calls in total. Any remaining un-executed call sequences are then deferred (see DeferredCallSequenceCount).
 // Returns a call sequence loaded from disk which has not yet been executed, to check for test failures. If all
-// sequences in the corpus have been executed, this will return nil.
-func (c *Corpus) UnexecutedCallSequence() *calls.CallSequence {
+// sequences in the corpus have been executed or deferred, this will return nil.
+func (c *Corpus) UnexecutedCallSequence(callLimit uint64) *calls.CallSequence {
+	sequence, _ := c.UnexecutedCallSequenceWithFileName(callLimit)
+	return sequence
+}
+
+// UnexecutedCallSequenceWithFileName behaves as UnexecutedCallSequence, while also returning the name of the corpus
+// file the call sequence was loaded from, so it can be identified in reports or deprioritized once executed.
+// Returns the call sequence and its file name, or nil and an empty string if no call sequence should be executed.
+func (c *Corpus) UnexecutedCallSequenceWithFileName(callLimit uint64) (*calls.CallSequence, string) {
 	// Prior to thread locking, if we have no un-executed call sequences, quit.
 	// This is a speed optimization, as thread locking on a central component affects performance.
 	if len(c.unexecutedCallSequences) == 0 {
-		return nil
+		return nil, ""
 	}
 
 	// Acquire a thread lock for the duration of this method.
@@ -507,27 +945,84 @@ func (c *Corpus) UnexecutedCallSequence() *calls.CallSequence {
 	// Check that we have an item now that the thread is locked. This must be performed again as an item could've
 	// been removed between time of check (the prior exit condition) and time of use (thread locked operations).
 	if len(c.unexecutedCallSequences) == 0 {
-		return nil
+		return nil, ""
+	}
+
+	// If we have reached our replay limit, defer all remaining sequences so new sequences can be generated instead.
+	if callLimit > 0 && c.unexecutedCallsReturned >= callLimit {
+		c.logger.Info("Corpus replay limit of ", colors.Bold, callLimit, colors.Reset, " calls reached, deferring ", colors.Bold, len(c.unexecutedCallSequences), colors.Reset, " remaining call sequence(s)")
+		c.deferredCallSequences = append(c.deferredCallSequences, c.unexecutedCallSequences...)
+		c.unexecutedCallSequences = make([]unexecutedCallSequence, 0)
+		return nil, ""
 	}
 
 	// Otherwise obtain the first item and remove it from the slice.
 	firstSequence := c.unexecutedCallSequences[0]
 	c.unexecutedCallSequences = c.unexecutedCallSequences[1:]
+	c.unexecutedCallsReturned += uint64(len(firstSequence.sequence))
 
 	// Return the first sequence
-	return &firstSequence
+	return &firstSequence.sequence, firstSequence.fileName
 }
 
-// Flush writes corpus changes to disk. Returns an error if one occurs.
+// DeprioritizeSlowCallSequence reduces the mutation chooser weight of the call sequence loaded from the corpus file
+// with the provided name, as its replay took the provided duration, exceeding the provided threshold. The weight is
+// divided by how many times over the threshold the replay took, so call sequences with a weight of one are no
+// longer selected for mutations at all, unless no other call sequence could be selected.
+// Returns a boolean indicating whether the weight of the call sequence was reduced.
+func (c *Corpus) DeprioritizeSlowCallSequence(fileName string, duration time.Duration, threshold time.Duration) bool {
+	if threshold <= 0 || duration <= threshold {
+		return false
+	}
+
+	// Acquire a thread lock for the duration of this method, so weights are not updated concurrently.
+	c.callSequencesLock.Lock()
+	defer c.callSequencesLock.Unlock()
+
+	// Obtain the choice for this call sequence. Call sequences which are not mutation targets have none.
+	mutationChoice, ok := c.mutationTargetChoicesByFileName[fileName]
+	if !ok {
+		return false
+	}
+
+	// Divide the weight of the call sequence, rounding the factor up, so it is reduced for any excess duration.
+	weight := c.mutationTargetSequenceChooser.ChoiceWeight(mutationChoice)
+	factor := int64((duration + threshold - 1) / threshold)
+	reducedWeight := new(big.Int).Div(weight, big.NewInt(factor))
+
+	// If this would leave no call sequence with a non-zero weight, we keep a weight of one, so mutations remain
+	// possible.
+	if reducedWeight.Sign() == 0 && new(big.Int).Sub(c.mutationTargetSequenceChooser.TotalWeight(), weight).Sign() == 0 {
+		reducedWeight.SetInt64(1)
+	}
+	if reducedWeight.Cmp(weight) == 0 {
+		return false
+	}
+	c.mutationTargetSequenceChooser.SetChoiceWeight(mutationChoice, reducedWeight)
+	delete(c.mutationTargetChoicesByFileName, fileName)
+	return true
+}
+
+// DeferredCallSequenceCount returns the count of call sequences loaded from disk which were not executed because the
+// corpus replay limit was reached.
+func (c *Corpus) DeferredCallSequenceCount() int {
+	c.callSequencesLock.Lock()
+	defer c.callSequencesLock.Unlock()
+	return len(c.deferredCallSequences)
+}
+
+// Flush writes corpus changes to disk, on the calling goroutine, even if background writes were started. Call
+// sequences may still be added to the corpus while it is being written. Returns an error if one occurs.
 func (c *Corpus) Flush() error {
 	// If our corpus directory is empty, it indicates we do not want to write corpus artifacts to persistent storage.
-	if c.storageDirectory == "" {
+	if c.storageDirectory == "" || c.readOnly {
 		return nil
 	}
 
-	// Lock while flushing the corpus items to avoid concurrent access issues.
-	c.callSequencesLock.Lock()
-	defer c.callSequencesLock.Unlock()
+	// Lock while flushing the corpus items to avoid writing them concurrently.
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	c.pendingWrites.Store(0)
 
 	// Write all coverage-increasing call sequences.
 	err := c.callSequenceFiles.writeFiles()
@@ -541,5 +1036,11 @@ func (c *Corpus) Flush() error {
 		return err
 	}
 
+	// Write metadata for coverage-increasing call sequences.
+	err = c.callSequenceMetadataFiles.writeFiles()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
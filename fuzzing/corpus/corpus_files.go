@@ -2,8 +2,10 @@ package corpus
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/common"
 	"os"
 	"path/filepath"
 	"strings"
@@ -18,9 +20,28 @@ type corpusFile[T any] struct {
 	// data describes an object whose data should be written to the file.
 	data T
 
+	// hash describes the hash of data, if the corpusDirectory hashes its files.
+	hash common.Hash
+
 	// writtenToDisk indicates whether the corpus item has been flushed to disk yet. If this is false, it signals that
 	// the data should be written or overwritten on disk.
 	writtenToDisk bool
+
+	// version describes the count of times the data of the file was overwritten. This is used to determine whether the
+	// data was overwritten while it was being written to disk, in which case it must be written again.
+	version uint64
+}
+
+// pendingCorpusFileWrite describes a corpusFile which is being written to disk, and the data being written.
+type pendingCorpusFileWrite[T any] struct {
+	// file describes the corpusFile being written.
+	file *corpusFile[T]
+
+	// data describes the data of the file at the time the write began.
+	data T
+
+	// version describes the version of the file at the time the write began.
+	version uint64
 }
 
 // corpusDirectory is a provider for corpusFile items in a given directory, offering read/write operations to
@@ -33,6 +54,16 @@ type corpusDirectory[T any] struct {
 	// files represents the corpusFile items stored/to be stored in the specified directory.
 	files []*corpusFile[T]
 
+	// filesByName indexes the corpusFile items in files by their lower-cased file name.
+	filesByName map[string]*corpusFile[T]
+
+	// hashData describes the function used to hash the data of each corpusFile as it is added, so the directory can
+	// be checked for files with equivalent data without hashing every file again. If nil, files are not hashed.
+	hashData func(data T) (common.Hash, error)
+
+	// fileHashes counts the corpusFile items in files by the hash of their data, if hashData is set.
+	fileHashes map[common.Hash]int
+
 	// filesLock represents a thread lock used when editing files.
 	filesLock sync.Mutex
 }
@@ -41,9 +72,73 @@ type corpusDirectory[T any] struct {
 // If the directory path is an empty string, then files will not be read from, or written to disk.
 func newCorpusDirectory[T any](path string) *corpusDirectory[T] {
 	return &corpusDirectory[T]{
-		path:  path,
-		files: make([]*corpusFile[T], 0),
+		path:        path,
+		files:       make([]*corpusFile[T], 0),
+		filesByName: make(map[string]*corpusFile[T]),
+	}
+}
+
+// newHashedCorpusDirectory returns a new corpusDirectory with the provided directory path set, which hashes the data
+// of each file with the provided function as it is added, so it can be checked for files with equivalent data.
+// If the directory path is an empty string, then files will not be read from, or written to disk.
+func newHashedCorpusDirectory[T any](path string, hashData func(data T) (common.Hash, error)) *corpusDirectory[T] {
+	cd := newCorpusDirectory[T](path)
+	cd.hashData = hashData
+	cd.fileHashes = make(map[common.Hash]int)
+	return cd
+}
+
+// containsHash checks if a corpusFile with data of the provided hash exists. It always returns false if the
+// corpusDirectory does not hash its files.
+func (cd *corpusDirectory[T]) containsHash(hash common.Hash) bool {
+	// Lock to avoid concurrency issues when accessing the file hashes
+	cd.filesLock.Lock()
+	defer cd.filesLock.Unlock()
+
+	return cd.fileHashes[hash] > 0
+}
+
+// setFileData sets the data of the provided corpusFile, updating the hash recorded for it, if the corpusDirectory
+// hashes its files. The file must not be counted by the file hashes yet.
+// Returns an error, if one occurred.
+func (cd *corpusDirectory[T]) setFileData(file *corpusFile[T], data T) error {
+	file.data = data
+	if cd.hashData == nil {
+		return nil
+	}
+	hash, err := cd.hashData(data)
+	if err != nil {
+		return err
+	}
+	file.hash = hash
+	cd.fileHashes[hash]++
+	return nil
+}
+
+// forgetFileHash removes the provided corpusFile from the file hashes, if the corpusDirectory hashes its files.
+func (cd *corpusDirectory[T]) forgetFileHash(file *corpusFile[T]) {
+	if cd.hashData == nil {
+		return
 	}
+	cd.fileHashes[file.hash]--
+	if cd.fileHashes[file.hash] <= 0 {
+		delete(cd.fileHashes, file.hash)
+	}
+}
+
+// fileData returns the data of the corpusFile with the provided file name.
+// Returns the data, and a boolean indicating whether a corpusFile with the provided file name was found.
+func (cd *corpusDirectory[T]) fileData(fileName string) (T, bool) {
+	// Lock to avoid concurrency issues when accessing the files list
+	cd.filesLock.Lock()
+	defer cd.filesLock.Unlock()
+
+	file, ok := cd.filesByName[strings.ToLower(fileName)]
+	if !ok {
+		var data T
+		return data, false
+	}
+	return file.data, true
 }
 
 // addFile adds a given file to the file list (to later be written to the directory if a path was provided).
@@ -57,20 +152,24 @@ func (cd *corpusDirectory[T]) addFile(fileName string, data T) error {
 
 	// First we make sure this file doesn't already exist, if it does, we overwrite its data and mark it unwritten.
 	lowerFileName := strings.ToLower(fileName)
-	for i := 0; i < len(cd.files); i++ {
-		if lowerFileName == strings.ToLower(cd.files[i].fileName) {
-			cd.files[i].data = data
-			cd.files[i].writtenToDisk = false
-			return nil
-		}
+	if file, ok := cd.filesByName[lowerFileName]; ok {
+		cd.forgetFileHash(file)
+		file.writtenToDisk = false
+		file.version++
+		return cd.setFileData(file, data)
 	}
 
 	// If the file otherwise did not exist, we add it.
-	cd.files = append(cd.files, &corpusFile[T]{
+	file := &corpusFile[T]{
 		fileName:      fileName,
-		data:          data,
 		writtenToDisk: false,
-	})
+	}
+	err := cd.setFileData(file, data)
+	if err != nil {
+		return err
+	}
+	cd.files = append(cd.files, file)
+	cd.filesByName[lowerFileName] = file
 	return nil
 }
 
@@ -83,13 +182,32 @@ func (cd *corpusDirectory[T]) removeFile(fileName string) bool {
 
 	// If we find the filename, remove it from our list of files.
 	lowerFileName := strings.ToLower(fileName)
+	file, ok := cd.filesByName[lowerFileName]
+	if !ok {
+		return false
+	}
+	delete(cd.filesByName, lowerFileName)
+	cd.forgetFileHash(file)
 	for i := 0; i < len(cd.files); i++ {
-		if lowerFileName == strings.ToLower(cd.files[i].fileName) {
+		if cd.files[i] == file {
 			cd.files = append(cd.files[:i], cd.files[i+1:]...)
-			return true
+			break
 		}
 	}
-	return false
+	return true
+}
+
+// deleteFile removes a given file from the file list, and deletes it from disk if a path was provided.
+// Returns an error, if one occurred.
+func (cd *corpusDirectory[T]) deleteFile(fileName string) error {
+	if !cd.removeFile(fileName) || cd.path == "" {
+		return nil
+	}
+	err := os.Remove(filepath.Join(cd.path, fileName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
 }
 
 // readFiles takes a provided glob pattern representing files to parse within the corpusDirectory.path.
@@ -109,6 +227,10 @@ func (cd *corpusDirectory[T]) readFiles(filePattern string) error {
 
 	// Refresh our files list
 	cd.files = make([]*corpusFile[T], 0)
+	cd.filesByName = make(map[string]*corpusFile[T])
+	if cd.hashData != nil {
+		cd.fileHashes = make(map[common.Hash]int)
+	}
 
 	// Loop for every file path provided
 	for _, filePath := range filePaths {
@@ -126,29 +248,42 @@ func (cd *corpusDirectory[T]) readFiles(filePattern string) error {
 		}
 
 		// Add entry to corpus
-		cd.files = append(cd.files, &corpusFile[T]{
+		file := &corpusFile[T]{
 			fileName:      filepath.Base(filePath),
-			data:          fileData,
 			writtenToDisk: true,
-		})
+		}
+		err = cd.setFileData(file, fileData)
+		if err != nil {
+			return err
+		}
+		cd.files = append(cd.files, file)
+		cd.filesByName[strings.ToLower(file.fileName)] = file
 	}
 	return nil
 }
 
 // writeFiles flushes all corpusDirectory.files to disk, if they have corpusFile.writtenToDisk set as false.
-// It then sets corpusFile.writtenToDisk as true for each flushed to disk.
+// It then sets corpusFile.writtenToDisk as true for each flushed to disk, unless it was overwritten while being written.
+// Files are written without holding the files lock, so files may still be added while writing to a slow filesystem.
 // Returns an error, if one occurred.
 func (cd *corpusDirectory[T]) writeFiles() error {
-	// TODO: This can be optimized by storing/indexing unwritten sequences separately and only iterating over those.
-
 	// If our directory path is empty, we do not write anything.
 	if cd.path == "" {
 		return nil
 	}
 
-	// Lock to avoid concurrency issues when accessing the files list
+	// Collect the files which have not been written to disk yet, while holding the lock.
 	cd.filesLock.Lock()
-	defer cd.filesLock.Unlock()
+	pendingWrites := make([]pendingCorpusFileWrite[T], 0)
+	for _, file := range cd.files {
+		if !file.writtenToDisk {
+			pendingWrites = append(pendingWrites, pendingCorpusFileWrite[T]{file: file, data: file.data, version: file.version})
+		}
+	}
+	cd.filesLock.Unlock()
+	if len(pendingWrites) == 0 {
+		return nil
+	}
 
 	// Ensure the corpus directory path exists.
 	err := utils.MakeDirectory(cd.path)
@@ -156,32 +291,34 @@ func (cd *corpusDirectory[T]) writeFiles() error {
 		return err
 	}
 
-	// For each file which does not have an assigned file path yet, we flush it to disk.
-	for _, file := range cd.files {
-		if !file.writtenToDisk {
-			// If we don't have a filename, throw an error.
-			if len(file.fileName) == 0 {
-				return fmt.Errorf("failed to flush corpus item to disk as it does not have a filename")
-			}
-
-			// Determine the file path to write this to.
-			filePath := filepath.Join(cd.path, file.fileName)
-
-			// Marshal the data
-			jsonEncodedData, err := json.MarshalIndent(file.data, "", " ")
-			if err != nil {
-				return err
-			}
-
-			// Write the JSON encoded data.
-			err = os.WriteFile(filePath, jsonEncodedData, os.ModePerm)
-			if err != nil {
-				return fmt.Errorf("An error occurred while writing corpus data to file: %v\n", err)
-			}
-
-			// Update our written to disk status.
-			file.writtenToDisk = true
+	// Flush each file to disk.
+	for _, pendingWrite := range pendingWrites {
+		// If we don't have a filename, throw an error.
+		if len(pendingWrite.file.fileName) == 0 {
+			return fmt.Errorf("failed to flush corpus item to disk as it does not have a filename")
+		}
+
+		// Determine the file path to write this to.
+		filePath := filepath.Join(cd.path, pendingWrite.file.fileName)
+
+		// Marshal the data
+		jsonEncodedData, err := json.MarshalIndent(pendingWrite.data, "", " ")
+		if err != nil {
+			return err
+		}
+
+		// Write the JSON encoded data.
+		err = writeCorpusFile(filePath, jsonEncodedData, os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("An error occurred while writing corpus data to file: %v\n", err)
+		}
+
+		// Update our written to disk status, unless the file was overwritten while we were writing it.
+		cd.filesLock.Lock()
+		if pendingWrite.file.version == pendingWrite.version {
+			pendingWrite.file.writtenToDisk = true
 		}
+		cd.filesLock.Unlock()
 	}
 	return nil
 }
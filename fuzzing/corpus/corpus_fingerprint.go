@@ -0,0 +1,140 @@
+package corpus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/crytic/medusa/utils"
+)
+
+// fingerprintFileName describes the name of the file within the corpus directory which stores the
+// CampaignFingerprint the corpus was recorded with.
+const fingerprintFileName = "fingerprint.json"
+
+// CampaignFingerprint describes the subset of a fuzzing campaign's configuration and compilation artifacts which
+// determines how the call sequences in a Corpus execute. If it changes between campaigns, call sequences recorded
+// previously may no longer replay as they did when they were recorded.
+type CampaignFingerprint struct {
+	// DeploymentOrder describes the names of the contracts deployed prior to fuzzing, in the order they are deployed.
+	DeploymentOrder []string `json:"deploymentOrder"`
+
+	// ConstructorArgsHash describes a hash of the constructor arguments provided to deployed contracts.
+	ConstructorArgsHash string `json:"constructorArgsHash"`
+
+	// SenderAddresses describes the addresses which calls are sent from.
+	SenderAddresses []string `json:"senderAddresses"`
+
+	// DeployerAddress describes the address which contracts are deployed from.
+	DeployerAddress string `json:"deployerAddress"`
+
+	// HardFork describes the hard fork the test chain runs with.
+	HardFork string `json:"hardFork"`
+
+	// ForkModeEnabled describes whether the test chain is forked from a remote chain.
+	ForkModeEnabled bool `json:"forkModeEnabled"`
+
+	// ForkRpcUrl describes the RPC URL of the remote chain the test chain is forked from, if fork mode is enabled.
+	ForkRpcUrl string `json:"forkRpcUrl"`
+
+	// ForkRpcBlock describes the block number the test chain is forked from, if fork mode is enabled.
+	ForkRpcBlock uint64 `json:"forkRpcBlock"`
+
+	// BlockGasLimit describes the maximum amount of gas a block may use.
+	BlockGasLimit uint64 `json:"blockGasLimit"`
+
+	// TransactionGasLimit describes the maximum amount of gas a call may use.
+	TransactionGasLimit uint64 `json:"transactionGasLimit"`
+
+	// ContractHashes maps the names of compiled contracts to a hash of their init and runtime bytecode.
+	ContractHashes map[string]string `json:"contractHashes"`
+}
+
+// Diff describes how the provided previous CampaignFingerprint differs from this one.
+// Returns a list of displayable descriptions of each difference, which is empty if the fingerprints match.
+func (f *CampaignFingerprint) Diff(previous *CampaignFingerprint) []string {
+	differences := make([]string, 0)
+
+	// Compare each field, other than our contract hashes, by value.
+	compare := func(name string, previousValue any, currentValue any) {
+		if !reflect.DeepEqual(previousValue, currentValue) {
+			differences = append(differences, fmt.Sprintf("%s changed from %v to %v", name, previousValue, currentValue))
+		}
+	}
+	compare("deploymentOrder", previous.DeploymentOrder, f.DeploymentOrder)
+	compare("constructorArgsHash", previous.ConstructorArgsHash, f.ConstructorArgsHash)
+	compare("senderAddresses", previous.SenderAddresses, f.SenderAddresses)
+	compare("deployerAddress", previous.DeployerAddress, f.DeployerAddress)
+	compare("hardFork", previous.HardFork, f.HardFork)
+	compare("forkModeEnabled", previous.ForkModeEnabled, f.ForkModeEnabled)
+	compare("forkRpcUrl", previous.ForkRpcUrl, f.ForkRpcUrl)
+	compare("forkRpcBlock", previous.ForkRpcBlock, f.ForkRpcBlock)
+	compare("blockGasLimit", previous.BlockGasLimit, f.BlockGasLimit)
+	compare("transactionGasLimit", previous.TransactionGasLimit, f.TransactionGasLimit)
+
+	// Compare our contract hashes, describing each contract which was added, removed, or changed, sorted by name.
+	contractNames := make([]string, 0, len(f.ContractHashes)+len(previous.ContractHashes))
+	for contractName := range f.ContractHashes {
+		contractNames = append(contractNames, contractName)
+	}
+	for contractName := range previous.ContractHashes {
+		if _, exists := f.ContractHashes[contractName]; !exists {
+			contractNames = append(contractNames, contractName)
+		}
+	}
+	sort.Strings(contractNames)
+	for _, contractName := range contractNames {
+		currentHash, currentExists := f.ContractHashes[contractName]
+		previousHash, previousExists := previous.ContractHashes[contractName]
+		switch {
+		case !previousExists:
+			differences = append(differences, fmt.Sprintf("contract %s was added", contractName))
+		case !currentExists:
+			differences = append(differences, fmt.Sprintf("contract %s was removed", contractName))
+		case currentHash != previousHash:
+			differences = append(differences, fmt.Sprintf("contract %s bytecode changed", contractName))
+		}
+	}
+	return differences
+}
+
+// ReadFingerprint reads the CampaignFingerprint the corpus was recorded with from the corpus directory.
+// Returns the fingerprint, or nil if the corpus is not stored on disk or no fingerprint was recorded, or an error if
+// one occurs.
+func (c *Corpus) ReadFingerprint() (*CampaignFingerprint, error) {
+	if c.storageDirectory == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(filepath.Join(c.storageDirectory, fingerprintFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var fingerprint CampaignFingerprint
+	if err = json.Unmarshal(b, &fingerprint); err != nil {
+		return nil, fmt.Errorf("could not parse corpus fingerprint: %v", err)
+	}
+	return &fingerprint, nil
+}
+
+// WriteFingerprint writes the provided CampaignFingerprint to the corpus directory, recording that the corpus is
+// being recorded with it. This does nothing if the corpus is not stored on disk.
+// Returns an error if one occurs.
+func (c *Corpus) WriteFingerprint(fingerprint *CampaignFingerprint) error {
+	if c.storageDirectory == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(fingerprint, "", " ")
+	if err != nil {
+		return err
+	}
+	if err = utils.MakeDirectory(c.storageDirectory); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.storageDirectory, fingerprintFileName), b, 0644)
+}
@@ -0,0 +1,147 @@
+package corpus
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/crytic/medusa/chain/testchainutils"
+	compilationTypes "github.com/crytic/medusa/compilation/types"
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// getMockCampaignFingerprint creates a mock CampaignFingerprint for testing.
+func getMockCampaignFingerprint() *CampaignFingerprint {
+	return &CampaignFingerprint{
+		DeploymentOrder:     []string{"TokenA", "TokenB"},
+		ConstructorArgsHash: "0x01",
+		SenderAddresses:     []string{"0x10000", "0x20000"},
+		DeployerAddress:     "0x30000",
+		HardFork:            "cancun",
+		BlockGasLimit:       125_000_000,
+		TransactionGasLimit: 12_500_000,
+		ContractHashes:      map[string]string{"TokenA": "0x0a", "TokenB": "0x0b"},
+	}
+}
+
+// TestCampaignFingerprintDiff ensures that matching fingerprints produce no differences, and that each difference
+// between mismatching fingerprints is described.
+func TestCampaignFingerprintDiff(t *testing.T) {
+	previous := getMockCampaignFingerprint()
+	current := getMockCampaignFingerprint()
+	assert.Empty(t, current.Diff(previous))
+
+	// Change some settings and contracts, and verify each change is described.
+	current.DeploymentOrder = []string{"TokenB", "TokenA"}
+	current.BlockGasLimit = 30_000_000
+	current.ContractHashes = map[string]string{"TokenA": "0x0c", "TokenC": "0x0d"}
+	assert.EqualValues(t, []string{
+		"deploymentOrder changed from [TokenA TokenB] to [TokenB TokenA]",
+		"blockGasLimit changed from 125000000 to 30000000",
+		"contract TokenA bytecode changed",
+		"contract TokenB was removed",
+		"contract TokenC was added",
+	}, current.Diff(previous))
+}
+
+// TestCorpusFingerprintReadWrite ensures that a CampaignFingerprint written to a corpus can be read back, and that
+// reading a fingerprint from a corpus which has none returns nil.
+func TestCorpusFingerprintReadWrite(t *testing.T) {
+	corpus, err := NewCorpus(t.TempDir())
+	assert.NoError(t, err)
+
+	// A corpus without a fingerprint should return nil.
+	fingerprint, err := corpus.ReadFingerprint()
+	assert.NoError(t, err)
+	assert.Nil(t, fingerprint)
+
+	// Write a fingerprint and verify it round trips.
+	expected := getMockCampaignFingerprint()
+	assert.NoError(t, corpus.WriteFingerprint(expected))
+	fingerprint, err = corpus.ReadFingerprint()
+	assert.NoError(t, err)
+	assert.EqualValues(t, expected, fingerprint)
+	assert.Empty(t, fingerprint.Diff(expected))
+}
+
+// TestCorpusRemoveInvalidSequences ensures that corpus call sequences which fail to replay are only disabled by
+// default, but are deleted from disk if the corpus is configured to remove them.
+func TestCorpusRemoveInvalidSequences(t *testing.T) {
+	// Create a test chain with a funded sender and a deployed contract whose runtime bytecode simply stops.
+	testChain, sender := testchainutils.NewFundedTestChain(t)
+	initBytecode := common.Hex2Bytes("6001600c60003960016000f300")
+	deployMsg := calls.NewCallMessage(sender, nil, 0, big.NewInt(0), 1_000_000, big.NewInt(1), big.NewInt(0), big.NewInt(0), initBytecode)
+	_, err := testChain.PendingBlockCreate()
+	assert.NoError(t, err)
+	assert.NoError(t, testChain.PendingBlockAddTx(deployMsg.ToCoreMessage()))
+	assert.NoError(t, testChain.PendingBlockCommit())
+	contractAddress := crypto.CreateAddress(sender, 0)
+
+	// Define a contract whose current ABI no longer has the method our invalid call sequence calls.
+	oldAbi, err := abi.JSON(strings.NewReader(`[
+		{"type":"function","name":"increment","inputs":[],"outputs":[],"stateMutability":"nonpayable"},
+		{"type":"function","name":"removed","inputs":[],"outputs":[],"stateMutability":"nonpayable"}
+	]`))
+	assert.NoError(t, err)
+	currentAbi, err := abi.JSON(strings.NewReader(`[
+		{"type":"function","name":"increment","inputs":[],"outputs":[],"stateMutability":"nonpayable"}
+	]`))
+	assert.NoError(t, err)
+	contractDefinitions := contracts.Contracts{
+		contracts.NewContract("TestContract", "", &compilationTypes.CompiledContract{
+			Abi:             currentAbi,
+			InitBytecode:    initBytecode,
+			RuntimeBytecode: common.Hex2Bytes("00"),
+		}, nil),
+	}
+	createSequence := func(methodName string) calls.CallSequence {
+		method := oldAbi.Methods[methodName]
+		msg := calls.NewCallMessageWithAbiValueData(sender, &contractAddress, 1, big.NewInt(0), 100_000, big.NewInt(1), big.NewInt(0), big.NewInt(0), &calls.CallMessageDataAbiValues{
+			Method:      &method,
+			InputValues: []any{},
+		})
+		return calls.CallSequence{calls.NewCallSequenceElement(nil, msg, 1, 1)}
+	}
+
+	// Write a valid and an invalid call sequence to a corpus directory.
+	corpusDirectory := t.TempDir()
+	corpus, err := NewCorpus(corpusDirectory)
+	assert.NoError(t, err)
+	assert.NoError(t, corpus.callSequenceFiles.addFile("valid.json", createSequence("increment")))
+	assert.NoError(t, corpus.callSequenceFiles.addFile("invalid.json", createSequence("removed")))
+	assert.NoError(t, corpus.callSequenceFiles.writeFiles())
+	invalidFilePath := filepath.Join(corpusDirectory, "call_sequences", "invalid.json")
+
+	// Load and initialize the corpus, returning the amount of active and total call sequences.
+	initializeCorpus := func(removeInvalidSequences bool) (int, int) {
+		corpus, err := NewCorpus(corpusDirectory)
+		assert.NoError(t, err)
+		corpus.SetRemoveInvalidSequences(removeInvalidSequences)
+		active, total, err := corpus.Initialize(testChain, contractDefinitions, contracts.BytecodeMatchingModeStrict, false)
+		assert.NoError(t, err)
+		return active, total
+	}
+
+	// By default, the invalid call sequence should only be disabled.
+	active, total := initializeCorpus(false)
+	assert.EqualValues(t, 1, active)
+	assert.EqualValues(t, 2, total)
+	assert.FileExists(t, invalidFilePath)
+
+	// If configured, it should be removed from the corpus and from disk, so it is not loaded again.
+	active, total = initializeCorpus(true)
+	assert.EqualValues(t, 1, active)
+	assert.EqualValues(t, 1, total)
+	_, err = os.Stat(invalidFilePath)
+	assert.True(t, os.IsNotExist(err))
+	active, total = initializeCorpus(false)
+	assert.EqualValues(t, 1, active)
+	assert.EqualValues(t, 1, total)
+}
@@ -0,0 +1,176 @@
+package corpus
+
+import (
+	"math/big"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/coverage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// CallSequenceMetadata describes additional information recorded for a call sequence when it was added to the Corpus.
+// It is stored alongside the call sequence, under the same file name.
+type CallSequenceMetadata struct {
+	// CoverageDelta describes the coverage markers which were newly achieved by the call sequence when it was added to
+	// the Corpus.
+	CoverageDelta *coverage.CoverageDelta `json:"coverageDelta,omitempty"`
+
+	// WeightMultiplier describes the multiplier applied to the mutation chooser weight of a test result call sequence
+	// which was recorded for use in mutations (e.g. by an optimization test, scaled by the value it achieved). It is
+	// used to restore the relative weight of the call sequence when the Corpus is initialized again.
+	WeightMultiplier *big.Int `json:"weightMultiplier,omitempty"`
+
+	// Tags describes labels for the call sequence, used to restrict which call sequences are used as mutation targets
+	// (see Corpus.SetMutationTagAllowlist). When the call sequence is added to the Corpus, the names of the contracts
+	// it calls are merged into any tags it was added with. They may be set by external tooling with
+	// SetCallSequenceTags, e.g. to label call sequences by the subsystem they exercise, and are merged with the derived
+	// tags when the Corpus is initialized again.
+	Tags []string `json:"tags,omitempty"`
+
+	// Elements describes metadata for each call in the call sequence, in order, as observed when the call sequence
+	// was added to the Corpus. This is only recorded if element metadata recording was enabled with
+	// Corpus.SetRecordElementMetadata, and is never used when replaying or mutating the call sequence.
+	Elements []CallSequenceElementMetadata `json:"elements,omitempty"`
+}
+
+// CallSequenceElementMetadata describes a single call in a call sequence, as observed when the call sequence was
+// added to the Corpus. It is intended for external analysis of the corpus, e.g. to derive features of each call.
+type CallSequenceElementMetadata struct {
+	// Contract describes the name of the contract definition the call targeted, or an empty string if it could not be
+	// resolved.
+	Contract string `json:"contract,omitempty"`
+
+	// Method describes the signature of the method the call targeted (e.g. "transfer(address,uint256)"), or an empty
+	// string if it could not be resolved.
+	Method string `json:"method,omitempty"`
+
+	// Executed indicates whether the call was executed before the call sequence was added to the Corpus. If it was
+	// not, Reverted and GasUsed are not meaningful.
+	Executed bool `json:"executed"`
+
+	// Reverted indicates whether the call reverted when it was executed.
+	Reverted bool `json:"reverted"`
+
+	// GasUsed describes the amount of gas used by the call when it was executed.
+	GasUsed uint64 `json:"gasUsed"`
+
+	// Value describes the amount of wei transferred by the call.
+	Value *big.Int `json:"value,omitempty"`
+}
+
+// newCallSequenceElementMetadata creates CallSequenceElementMetadata for each call in the provided call sequence,
+// using the receipts of the calls which were executed.
+func newCallSequenceElementMetadata(callSequence calls.CallSequence) []CallSequenceElementMetadata {
+	elementsMetadata := make([]CallSequenceElementMetadata, len(callSequence))
+	for i, element := range callSequence {
+		elementMetadata := &elementsMetadata[i]
+		if element.Call == nil {
+			continue
+		}
+		if element.Call.Value != nil {
+			elementMetadata.Value = new(big.Int).Set(element.Call.Value)
+		}
+
+		// Resolve the targeted contract and method, preferring the method the call was packed with.
+		if element.Contract != nil {
+			elementMetadata.Contract = element.Contract.Name()
+		}
+		if element.Call.DataAbiValues != nil && element.Call.DataAbiValues.Method != nil {
+			elementMetadata.Method = element.Call.DataAbiValues.Method.Sig
+		} else if element.Contract != nil && len(element.Call.Data) >= 4 {
+			if method, err := element.Contract.CompiledContract().Abi.MethodById(element.Call.Data[:4]); err == nil {
+				elementMetadata.Method = method.Sig
+			}
+		}
+
+		// Record the results of the call, if it was executed.
+		if element.ChainReference != nil {
+			receipt := element.ChainReference.MessageResults().Receipt
+			elementMetadata.Executed = true
+			elementMetadata.Reverted = receipt.Status == types.ReceiptStatusFailed
+			elementMetadata.GasUsed = receipt.GasUsed
+		}
+	}
+	return elementsMetadata
+}
+
+// withElementMetadata returns the provided call sequence metadata with metadata for each call in the provided call
+// sequence added, if element metadata recording is enabled. The provided metadata is not modified, and may be nil.
+func (c *Corpus) withElementMetadata(metadata *CallSequenceMetadata, callSequence calls.CallSequence) *CallSequenceMetadata {
+	if !c.recordElementMetadata {
+		return metadata
+	}
+	var metadataWithElements CallSequenceMetadata
+	if metadata != nil {
+		metadataWithElements = *metadata
+	}
+	metadataWithElements.Elements = newCallSequenceElementMetadata(callSequence)
+	return &metadataWithElements
+}
+
+// SetRecordElementMetadata sets whether CallSequenceMetadata.Elements should be recorded for call sequences added to
+// the Corpus from now on. This should be called before any call sequences are added.
+func (c *Corpus) SetRecordElementMetadata(enabled bool) {
+	c.recordElementMetadata = enabled
+}
+
+// scaleMutationChooserWeight returns the provided mutation chooser weight scaled by the provided multiplier. A nil
+// weight or multiplier is treated as 1.
+func scaleMutationChooserWeight(mutationChooserWeight *big.Int, weightMultiplier *big.Int) *big.Int {
+	scaledWeight := big.NewInt(1)
+	if mutationChooserWeight != nil {
+		scaledWeight.Set(mutationChooserWeight)
+	}
+	if weightMultiplier != nil {
+		scaledWeight.Mul(scaledWeight, weightMultiplier)
+	}
+	return scaledWeight
+}
+
+// testResultWeightMultiplier returns the weight multiplier recorded in the metadata of the test result call sequence
+// with the provided file name, or nil if the call sequence was not recorded for use in mutations.
+func (c *Corpus) testResultWeightMultiplier(fileName string) *big.Int {
+	metadata, ok := c.callSequenceMetadataFiles.fileData(fileName)
+	if !ok {
+		return nil
+	}
+	return metadata.WeightMultiplier
+}
+
+// contractLookupHashTarget describes the contract bytecode a coverage map lookup hash refers to.
+type contractLookupHashTarget struct {
+	// name describes the name of the contract the lookup hash refers to.
+	name string
+
+	// init indicates whether the lookup hash refers to the contract's init bytecode, rather than its runtime bytecode.
+	init bool
+}
+
+// resolveLookupHash resolves a coverage map lookup hash to the name of the contract definition it refers to, and
+// whether it refers to the contract's init bytecode.
+// Returns the contract name, the init bytecode indicator, and a boolean indicating whether the hash was resolved.
+func (c *Corpus) resolveLookupHash(lookupHash common.Hash) (string, bool, bool) {
+	target, ok := c.contractLookupHashes[lookupHash]
+	if !ok {
+		return "", false, false
+	}
+	return target.name, target.init, true
+}
+
+// CallSequenceMetadata returns the metadata recorded for each coverage-increasing call sequence in the Corpus, as well
+// as each test result call sequence recorded for use in mutations (or every test result call sequence, if element
+// metadata was recorded), keyed by the file name of the call sequence. Call sequences for which no metadata was
+// recorded (e.g. those added by older versions of the fuzzer) are omitted.
+func (c *Corpus) CallSequenceMetadata() map[string]*CallSequenceMetadata {
+	// Lock to avoid concurrency issues when accessing the files list
+	c.callSequenceMetadataFiles.filesLock.Lock()
+	defer c.callSequenceMetadataFiles.filesLock.Unlock()
+
+	metadata := make(map[string]*CallSequenceMetadata, len(c.callSequenceMetadataFiles.files))
+	for _, file := range c.callSequenceMetadataFiles.files {
+		fileData := file.data
+		metadata[file.fileName] = &fileData
+	}
+	return metadata
+}
@@ -0,0 +1,245 @@
+package corpus
+
+import (
+	"math/big"
+	"math/rand"
+	"sync"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/coverage"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/crytic/medusa/utils/randomutils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Partition describes a view of a Corpus which is private to a single fuzzer worker. It is used to fuzz
+// deterministically with many workers, as the call sequences a worker mutates do not depend on the progress of other
+// workers: a worker only mutates the call sequences which were in the Corpus when it was partitioned, and those the
+// worker discovered itself.
+//
+// Coverage is checked against the coverage achieved by the call sequences in the Partition, rather than those in the
+// Corpus. The Corpus coverage maps are still updated as coverage is achieved, so it can be reported while fuzzing.
+// Call sequences added to a Partition are only added to the Corpus (and written to disk) once the Partition is merged
+// through Corpus.MergePartitions.
+type Partition struct {
+	// corpus describes the Corpus which the Partition was created from, and is merged into.
+	corpus *Corpus
+
+	// coverageMaps describes the coverage achieved by the call sequences in the Partition.
+	coverageMaps *coverage.CoverageMaps
+
+	// mutationTargetSequenceChooser is a provider that allows for weighted random selection of the call sequences in
+	// the Partition which are used for mutations.
+	mutationTargetSequenceChooser *randomutils.WeightedRandomChooser[calls.CallSequence]
+
+	// unexecutedCallSequences describes the call sequences loaded from disk which the worker owning the Partition
+	// should execute, and which it has not yet executed.
+	unexecutedCallSequences []unexecutedCallSequence
+
+	// discovered describes the call sequences added to the Partition, in the order they were added, which are
+	// pending addition to the Corpus.
+	discovered []stagedCallSequence
+
+	// discoveredHashes describes the hashes of the call sequences discovered in the Partition for each corpus
+	// directory, used alongside the hashes tracked by each corpus directory to avoid adding duplicate call sequences.
+	discoveredHashes map[*corpusDirectory[calls.CallSequence]]map[common.Hash]struct{}
+
+	// lock provides thread synchronization, as the Partition is merged and measured by the fuzzer while it is used
+	// by a worker.
+	lock sync.Mutex
+}
+
+// NewPartitions splits the Corpus into the provided amount of Partitions, each to be used by a single fuzzer worker.
+// Every Partition starts with the coverage and mutation targets of the Corpus. The call sequences loaded from disk
+// which were not yet executed are distributed across the Partitions in turn, so each is replayed by exactly one
+// worker. If callLimit is non-zero, call sequences are only distributed until those distributed contain at least
+// callLimit calls in total, and the remaining call sequences are deferred (see DeferredCallSequenceCount).
+// This should be called once the Corpus is initialized, prior to fuzzing.
+// Returns the Partitions.
+func (c *Corpus) NewPartitions(count int, callLimit uint64) []*Partition {
+	c.callSequencesLock.Lock()
+	defer c.callSequencesLock.Unlock()
+
+	// Create our partitions from the current state of the corpus.
+	partitions := make([]*Partition, count)
+	for i := 0; i < count; i++ {
+		partitions[i] = &Partition{
+			corpus:                        c,
+			coverageMaps:                  c.coverageMaps.Clone(),
+			mutationTargetSequenceChooser: c.mutationTargetSequenceChooser.Clone(),
+			unexecutedCallSequences:       make([]unexecutedCallSequence, 0),
+			discovered:                    make([]stagedCallSequence, 0),
+			discoveredHashes:              make(map[*corpusDirectory[calls.CallSequence]]map[common.Hash]struct{}),
+		}
+	}
+
+	// Distribute our un-executed call sequences in order, deferring any beyond our replay limit.
+	for i, sequence := range c.unexecutedCallSequences {
+		if callLimit > 0 && c.unexecutedCallsReturned >= callLimit {
+			c.logger.Info("Corpus replay limit of ", colors.Bold, callLimit, colors.Reset, " calls reached, deferring ", colors.Bold, len(c.unexecutedCallSequences)-i, colors.Reset, " remaining call sequence(s)")
+			c.deferredCallSequences = append(c.deferredCallSequences, c.unexecutedCallSequences[i:]...)
+			break
+		}
+		partition := partitions[i%count]
+		partition.unexecutedCallSequences = append(partition.unexecutedCallSequences, sequence)
+		c.unexecutedCallsReturned += uint64(len(sequence.sequence))
+	}
+	c.unexecutedCallSequences = make([]unexecutedCallSequence, 0)
+	return partitions
+}
+
+// MergePartitions adds the call sequences discovered in each of the provided Partitions to the Corpus, in the order
+// the Partitions are provided, and clears them. Call sequences which already exist in the Corpus are skipped.
+// Returns the count of call sequences which were added, or an error if one occurs.
+func (c *Corpus) MergePartitions(partitions []*Partition) (int, error) {
+	addedCount := 0
+	for _, partition := range partitions {
+		partition.lock.Lock()
+		partitionAddedCount, err := c.addCallSequences(partition.discovered)
+		partition.discovered = make([]stagedCallSequence, 0)
+		partition.lock.Unlock()
+		addedCount += partitionAddedCount
+		if err != nil {
+			return addedCount, err
+		}
+	}
+	return addedCount, nil
+}
+
+// NewStagingBuffer creates a new, empty StagingBuffer which adds call sequences to this Partition when flushed, and
+// checks coverage against the coverage of the Partition.
+func (p *Partition) NewStagingBuffer() *StagingBuffer {
+	return &StagingBuffer{
+		corpus:    p.corpus,
+		partition: p,
+		staged:    make([]stagedCallSequence, 0),
+	}
+}
+
+// DiscoveredCount returns the count of call sequences added to the Partition which are pending addition to the
+// Corpus.
+func (p *Partition) DiscoveredCount() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.discovered)
+}
+
+// ActiveMutableSequenceCount returns the count of call sequences in the Partition which are ready for use in
+// mutations.
+func (p *Partition) ActiveMutableSequenceCount() int {
+	return p.mutationTargetSequenceChooser.ChoiceCount()
+}
+
+// RandomMutationTargetSequence returns a weighted random call sequence from the Partition, selected using the provided
+// random provider, or an error if one occurs.
+func (p *Partition) RandomMutationTargetSequence(randomProvider *rand.Rand) (calls.CallSequence, error) {
+	// Pick a random call sequence, then clone it before returning it, so the original is untainted.
+	seq, err := p.mutationTargetSequenceChooser.ChooseWithRand(randomProvider)
+	if seq == nil || err != nil {
+		return nil, err
+	}
+	return seq.Clone()
+}
+
+// UnexecutedCallSequenceWithFileName returns a call sequence loaded from disk which was distributed to this Partition
+// and has not yet been returned by this method, along with the name of the corpus file it was loaded from.
+// Returns the call sequence and its file name, or nil and an empty string if all call sequences distributed to this
+// Partition were returned.
+func (p *Partition) UnexecutedCallSequenceWithFileName() (*calls.CallSequence, string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.unexecutedCallSequences) == 0 {
+		return nil, ""
+	}
+	firstSequence := p.unexecutedCallSequences[0]
+	p.unexecutedCallSequences = p.unexecutedCallSequences[1:]
+	return &firstSequence.sequence, firstSequence.fileName
+}
+
+// checkSequenceCoverage checks if the most recent call executed in the provided call sequence achieved coverage the
+// Partition did not with any of its call sequences, updating the Partition and Corpus coverage maps accordingly. The
+// call sequence is not added to the Partition.
+// Returns the coverage.CoverageDelta describing the new coverage, or nil if there was none. Returns an error if one
+// occurs.
+func (p *Partition) checkSequenceCoverage(callSequence calls.CallSequence) (*coverage.CoverageDelta, error) {
+	// Obtain our coverage maps for our last call. If we have none, there is nothing to do.
+	lastMessageCoverageMaps := takeLastCallCoverageMaps(callSequence)
+	if lastMessageCoverageMaps == nil {
+		return nil, nil
+	}
+
+	// Merge a copy of the coverage maps into the Partition coverage maps, as merging may take ownership of the
+	// provided maps, which must not be shared with the Corpus.
+	coverageUpdated, revertedCoverageUpdated, coverageDelta, err := p.coverageMaps.UpdateWithDelta(lastMessageCoverageMaps.Clone())
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge the coverage maps into the Corpus coverage maps, so coverage can be reported while fuzzing.
+	_, _, err = p.corpus.coverageMaps.Update(lastMessageCoverageMaps)
+	if err != nil {
+		return nil, err
+	}
+	_, _, err = p.corpus.fuzzingCoverageMaps.Update(lastMessageCoverageMaps)
+	if err != nil {
+		return nil, err
+	}
+
+	// If we had an increase in non-reverted or reverted coverage, resolve the contracts which achieved new coverage,
+	// so the delta is human-readable.
+	if (coverageUpdated || revertedCoverageUpdated) && !coverageDelta.Empty() {
+		coverageDelta.ResolveContractNames(p.corpus.resolveLookupHash)
+		return coverageDelta, nil
+	}
+	return nil, nil
+}
+
+// addCallSequences adds a batch of staged call sequences to the Partition, skipping any which already exist in their
+// corpus directory or were already added to the Partition.
+// Returns the count of call sequences which were added, or an error if one occurs.
+func (p *Partition) addCallSequences(entries []stagedCallSequence) (int, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	mutationChoices := make([]*randomutils.WeightedRandomChoice[calls.CallSequence], 0)
+	addedCount := 0
+	for _, entry := range entries {
+		// Verify it is unique, if it is not, we skip it to avoid duplicate sequences being added.
+		if p.containsHash(entry.sequenceFiles, entry.hash) {
+			continue
+		}
+		discoveredHashes, ok := p.discoveredHashes[entry.sequenceFiles]
+		if !ok {
+			discoveredHashes = make(map[common.Hash]struct{})
+			p.discoveredHashes[entry.sequenceFiles] = discoveredHashes
+		}
+		discoveredHashes[entry.hash] = struct{}{}
+		p.discovered = append(p.discovered, entry)
+		addedCount++
+
+		// If we want to use this sequence in mutations and its tags are allowed, we'll add it to our chooser once the
+		// batch is processed.
+		if entry.useInMutations && p.corpus.mutationTagsAllowed(entry.tags) {
+			mutationChooserWeight := entry.mutationChooserWeight
+			if mutationChooserWeight == nil {
+				mutationChooserWeight = big.NewInt(1)
+			}
+			mutationChoices = append(mutationChoices, randomutils.NewWeightedRandomChoice[calls.CallSequence](entry.sequence, mutationChooserWeight))
+		}
+	}
+
+	// Add all new mutation targets to our chooser at once.
+	if len(mutationChoices) > 0 {
+		p.mutationTargetSequenceChooser.AddChoices(mutationChoices...)
+	}
+	return addedCount, nil
+}
+
+// containsHash checks if a call sequence with the provided hash exists in the provided corpus directory, or was
+// discovered in the Partition for it. The Partition lock must be held by the caller.
+func (p *Partition) containsHash(sequenceFiles *corpusDirectory[calls.CallSequence], hash common.Hash) bool {
+	if _, exists := p.discoveredHashes[sequenceFiles][hash]; exists {
+		return true
+	}
+	return sequenceFiles.containsHash(hash)
+}
@@ -0,0 +1,102 @@
+package corpus
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// corpusSequenceHashes returns the hashes of the call sequences in the provided corpus directory, in order.
+func corpusSequenceHashes(t *testing.T, sequenceFiles *corpusDirectory[calls.CallSequence]) []common.Hash {
+	hashes := make([]common.Hash, 0, len(sequenceFiles.files))
+	for _, file := range sequenceFiles.files {
+		hash, err := file.data.Hash()
+		assert.NoError(t, err)
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+// TestPartitionIsolation tests that call sequences added to a Partition are only visible to mutations from that
+// Partition, and are added to the corpus exactly once, in Partition order, when the Partitions are merged.
+func TestPartitionIsolation(t *testing.T) {
+	corpus := newStagingTestCorpus(t)
+
+	// Add an initial call sequence to the corpus, which every partition should start with.
+	buffer := corpus.NewStagingBuffer()
+	stageMutableCallSequence(t, buffer, getMockCallSequence(2))
+	_, err := buffer.Flush(false)
+	assert.NoError(t, err)
+
+	// Add a call sequence to each partition, along with one shared by both.
+	partitions := corpus.NewPartitions(2, 0)
+	sharedSequence := getMockCallSequence(2)
+	ownSequences := []calls.CallSequence{getMockCallSequence(2), getMockCallSequence(2)}
+	for i, partition := range partitions {
+		assert.EqualValues(t, 1, partition.ActiveMutableSequenceCount())
+		buffer := partition.NewStagingBuffer()
+		stageMutableCallSequence(t, buffer, ownSequences[i])
+		stageMutableCallSequence(t, buffer, sharedSequence)
+		stageMutableCallSequence(t, buffer, sharedSequence)
+		addedCount, err := buffer.Flush(true)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 2, addedCount)
+	}
+
+	// Each partition should only see its own call sequences, and the corpus should be unchanged until merged.
+	for _, partition := range partitions {
+		assert.EqualValues(t, 3, partition.ActiveMutableSequenceCount())
+		assert.EqualValues(t, 2, partition.DiscoveredCount())
+	}
+	assert.EqualValues(t, 1, corpus.ActiveMutableSequenceCount())
+
+	// Merging should add every call sequence once, in partition order, and clear the partitions.
+	addedCount, err := corpus.MergePartitions(partitions)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, addedCount)
+	assert.EqualValues(t, 4, corpus.ActiveMutableSequenceCount())
+	for _, partition := range partitions {
+		assert.EqualValues(t, 0, partition.DiscoveredCount())
+	}
+	expectedHashes := make([]common.Hash, 0)
+	for _, sequence := range []calls.CallSequence{ownSequences[0], sharedSequence, ownSequences[1]} {
+		hash, err := sequence.Hash()
+		assert.NoError(t, err)
+		expectedHashes = append(expectedHashes, hash)
+	}
+	assert.EqualValues(t, expectedHashes, corpusSequenceHashes(t, corpus.callSequenceFiles)[1:])
+}
+
+// TestPartitionUnexecutedCallSequences tests that call sequences loaded from disk are distributed across Partitions
+// in turn, and that those beyond the replay call limit are deferred.
+func TestPartitionUnexecutedCallSequences(t *testing.T) {
+	corpus := newStagingTestCorpus(t)
+	for i := 0; i < 5; i++ {
+		corpus.unexecutedCallSequences = append(corpus.unexecutedCallSequences, unexecutedCallSequence{
+			sequence: getMockCallSequence(2),
+			fileName: fmt.Sprintf("%d.json", i),
+		})
+	}
+
+	// With a limit of five calls, the first three call sequences are distributed, and the rest deferred.
+	partitions := corpus.NewPartitions(2, 5)
+	assert.EqualValues(t, 2, corpus.DeferredCallSequenceCount())
+	expectedFileNames := [][]string{{"0.json", "2.json"}, {"1.json"}}
+	for i, partition := range partitions {
+		for _, expectedFileName := range expectedFileNames[i] {
+			sequence, fileName := partition.UnexecutedCallSequenceWithFileName()
+			assert.NotNil(t, sequence)
+			assert.EqualValues(t, expectedFileName, fileName)
+		}
+		sequence, fileName := partition.UnexecutedCallSequenceWithFileName()
+		assert.Nil(t, sequence)
+		assert.Empty(t, fileName)
+	}
+
+	// The corpus should not return any call sequences once they were distributed.
+	sequence, _ := corpus.UnexecutedCallSequenceWithFileName(0)
+	assert.Nil(t, sequence)
+}
@@ -0,0 +1,73 @@
+package corpus
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+)
+
+// CallSequenceRecord describes a call sequence stored in a corpus directory, along with the metadata recorded for it.
+// It is intended for external tooling which analyzes a corpus without running the fuzzer.
+type CallSequenceRecord struct {
+	// FileName describes the name of the file the call sequence is stored in.
+	FileName string
+
+	// TestResult indicates whether the call sequence was recorded by a test case provider (e.g. as it caused a test
+	// failure), rather than for achieving new coverage.
+	TestResult bool
+
+	// CallSequence describes the stored call sequence. As it is not resolved against any contract definitions, the
+	// Contract of each element is nil, and the ABI values of each call are not decoded.
+	CallSequence calls.CallSequence
+
+	// Metadata describes the metadata recorded for the call sequence, or nil if none was recorded (e.g. if it was added
+	// by an older version of the fuzzer).
+	Metadata *CallSequenceMetadata
+}
+
+// ReadCallSequenceRecords reads every call sequence stored in the provided corpus directory, along with any metadata
+// recorded for it, without modifying the directory. Records are sorted by file name, which orders them by the time
+// they were added to the corpus.
+// Returns the records, or an error if one occurs.
+func ReadCallSequenceRecords(directory string) ([]CallSequenceRecord, error) {
+	// Read the call sequences and metadata.
+	callSequenceFiles := newCorpusDirectory[calls.CallSequence](filepath.Join(directory, "call_sequences"))
+	err := callSequenceFiles.readFiles("*.json")
+	if err != nil {
+		return nil, err
+	}
+	testResultSequenceFiles := newCorpusDirectory[calls.CallSequence](filepath.Join(directory, "test_results"))
+	err = testResultSequenceFiles.readFiles("*.json")
+	if err != nil {
+		return nil, err
+	}
+	callSequenceMetadataFiles := newCorpusDirectory[CallSequenceMetadata](filepath.Join(directory, "call_sequence_metadata"))
+	err = callSequenceMetadataFiles.readFiles("*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	// Index the metadata by the file name of the call sequence it describes.
+	metadataByFileName := make(map[string]*CallSequenceMetadata, len(callSequenceMetadataFiles.files))
+	for _, file := range callSequenceMetadataFiles.files {
+		metadataByFileName[file.fileName] = &file.data
+	}
+
+	// Create a record for each call sequence.
+	records := make([]CallSequenceRecord, 0, len(callSequenceFiles.files)+len(testResultSequenceFiles.files))
+	for _, sequenceFiles := range []*corpusDirectory[calls.CallSequence]{callSequenceFiles, testResultSequenceFiles} {
+		for _, file := range sequenceFiles.files {
+			records = append(records, CallSequenceRecord{
+				FileName:     file.fileName,
+				TestResult:   sequenceFiles == testResultSequenceFiles,
+				CallSequence: file.data,
+				Metadata:     metadataByFileName[file.fileName],
+			})
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].FileName < records[j].FileName
+	})
+	return records, nil
+}
@@ -0,0 +1,193 @@
+package corpus
+
+import (
+	"math/big"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/coverage"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// stagedCallSequence describes a call sequence which is pending addition to a corpus directory of a Corpus.
+type stagedCallSequence struct {
+	// sequenceFiles describes the corpus directory the call sequence should be added to.
+	sequenceFiles *corpusDirectory[calls.CallSequence]
+
+	// sequence describes the call sequence to add.
+	sequence calls.CallSequence
+
+	// hash describes the hash of the call sequence, used to avoid adding duplicate call sequences.
+	hash common.Hash
+
+	// metadata describes the metadata to store alongside the call sequence, if any.
+	metadata *CallSequenceMetadata
+
+	// tags describes the tags of the call sequence, derived from the contracts it touches and merged with any tags
+	// its metadata was provided with.
+	tags []string
+
+	// useInMutations indicates whether the call sequence should be added to the mutation target chooser.
+	useInMutations bool
+
+	// mutationChooserWeight describes the weight of the call sequence in the mutation target chooser.
+	mutationChooserWeight *big.Int
+}
+
+// newStagedCallSequence creates a stagedCallSequence with the provided data, hashing the call sequence and deriving
+// its tags. If metadata is provided, the derived tags are merged into its tags and recorded with it.
+// Returns the stagedCallSequence, or an error if one occurs.
+func newStagedCallSequence(sequenceFiles *corpusDirectory[calls.CallSequence], sequence calls.CallSequence, metadata *CallSequenceMetadata, useInMutations bool, mutationChooserWeight *big.Int) (stagedCallSequence, error) {
+	hash, err := sequence.Hash()
+	if err != nil {
+		return stagedCallSequence{}, err
+	}
+	tags := callSequenceTags(sequence)
+	metadata = withTags(metadata, tags)
+	if metadata != nil {
+		tags = metadata.Tags
+	}
+	return stagedCallSequence{
+		sequenceFiles:         sequenceFiles,
+		sequence:              sequence,
+		hash:                  hash,
+		metadata:              metadata,
+		tags:                  tags,
+		useInMutations:        useInMutations,
+		mutationChooserWeight: mutationChooserWeight,
+	}, nil
+}
+
+// StagingBuffer collects call sequences to be added to a Corpus by a single fuzzer worker, so that they can be added
+// to the Corpus in batches rather than individually. This reduces contention on the Corpus when many workers achieve
+// new coverage at once.
+//
+// Coverage is still checked against (and merged into) the Corpus coverage maps immediately, so new coverage is never
+// attributed to more than one call sequence. Only the addition of call sequences is delayed: staged call sequences
+// are not written to disk, returned by Corpus.RandomMutationTargetSequence, or counted by
+// Corpus.ActiveMutableSequenceCount until the StagingBuffer is flushed.
+// A StagingBuffer created from a Partition checks coverage against, and adds call sequences to, the Partition instead.
+// A StagingBuffer is not thread-safe, and should only be used by the worker which owns it.
+type StagingBuffer struct {
+	// corpus describes the Corpus which staged call sequences are added to.
+	corpus *Corpus
+
+	// partition describes the Partition which staged call sequences are added to instead of the corpus, if any.
+	partition *Partition
+
+	// staged describes the call sequences which are pending addition to the corpus.
+	staged []stagedCallSequence
+}
+
+// NewStagingBuffer creates a new, empty StagingBuffer which adds call sequences to this Corpus when flushed.
+func (c *Corpus) NewStagingBuffer() *StagingBuffer {
+	return &StagingBuffer{
+		corpus: c,
+		staged: make([]stagedCallSequence, 0),
+	}
+}
+
+// StagedCount returns the count of call sequences pending addition to the corpus.
+func (b *StagingBuffer) StagedCount() int {
+	return len(b.staged)
+}
+
+// AddTestResultCallSequence stages a call sequence to be recorded in the corpus due to a test case provider flagging
+// it to be recorded.
+// Returns an error, if one occurs.
+func (b *StagingBuffer) AddTestResultCallSequence(callSequence calls.CallSequence, mutationChooserWeight *big.Int) error {
+	entry, err := newStagedCallSequence(b.corpus.testResultSequenceFiles, callSequence, b.corpus.withElementMetadata(nil, callSequence), false, mutationChooserWeight)
+	if err != nil {
+		return err
+	}
+	b.staged = append(b.staged, entry)
+	return nil
+}
+
+// AddWeightedTestResultCallSequence stages a call sequence to be recorded in the corpus due to a test case provider
+// flagging it to be recorded, which should also be used as a mutation target. Its mutation chooser weight is scaled
+// by the provided multiplier (e.g. to favor call sequences which achieved higher optimization values), where a nil
+// multiplier is treated as 1.
+// Returns an error, if one occurs.
+func (b *StagingBuffer) AddWeightedTestResultCallSequence(callSequence calls.CallSequence, mutationChooserWeight *big.Int, weightMultiplier *big.Int) error {
+	metadata := &CallSequenceMetadata{WeightMultiplier: scaleMutationChooserWeight(nil, weightMultiplier)}
+	entry, err := newStagedCallSequence(b.corpus.testResultSequenceFiles, callSequence, b.corpus.withElementMetadata(metadata, callSequence), true, scaleMutationChooserWeight(mutationChooserWeight, weightMultiplier))
+	if err != nil {
+		return err
+	}
+	b.staged = append(b.staged, entry)
+	return nil
+}
+
+// CheckSequenceCoverageAndUpdate checks if the most recent call executed in the provided call sequence achieved
+// coverage the Corpus did not with any of its call sequences, updating the Corpus coverage maps accordingly. If it
+// did, the call sequence is staged to be added to the corpus, with the coverage markers newly achieved by the call
+// recorded in its metadata. A call sequence which already exists in the corpus, or is already staged, is not staged
+// again, as it would not be added when the StagingBuffer is flushed.
+// Returns the coverage.CoverageDelta describing the new coverage if the call sequence was staged, or nil if it was
+// not. Returns an error if one occurs.
+func (b *StagingBuffer) CheckSequenceCoverageAndUpdate(callSequence calls.CallSequence, mutationChooserWeight *big.Int) (*coverage.CoverageDelta, error) {
+	var coverageDelta *coverage.CoverageDelta
+	var err error
+	if b.partition != nil {
+		coverageDelta, err = b.partition.checkSequenceCoverage(callSequence)
+	} else {
+		coverageDelta, err = b.corpus.checkSequenceCoverage(callSequence)
+	}
+	if err != nil || coverageDelta == nil {
+		return nil, err
+	}
+
+	entry, err := newStagedCallSequence(b.corpus.callSequenceFiles, callSequence, b.corpus.withElementMetadata(&CallSequenceMetadata{CoverageDelta: coverageDelta}, callSequence), true, mutationChooserWeight)
+	if err != nil || b.contains(entry) {
+		return nil, err
+	}
+	b.staged = append(b.staged, entry)
+	return coverageDelta, nil
+}
+
+// contains checks if a call sequence equivalent to the provided staged call sequence is already staged, or exists in
+// the corpus directory (or Partition) it would be added to.
+func (b *StagingBuffer) contains(entry stagedCallSequence) bool {
+	for _, staged := range b.staged {
+		if staged.sequenceFiles == entry.sequenceFiles && staged.hash == entry.hash {
+			return true
+		}
+	}
+	if b.partition != nil {
+		b.partition.lock.Lock()
+		defer b.partition.lock.Unlock()
+		return b.partition.containsHash(entry.sequenceFiles, entry.hash)
+	}
+	return entry.sequenceFiles.containsHash(entry.hash)
+}
+
+// Flush adds all staged call sequences to the corpus in a single batch, skipping any which already exist in it, and
+// clears the StagingBuffer. If flushToDisk is true and any call sequences were added, the corpus is then written to
+// disk, or left to its background writer if background writes were started. If the StagingBuffer was created from a Partition, call sequences are added to the Partition instead, and
+// are not written to disk until the Partition is merged.
+// Returns the count of call sequences which were added, or an error if one occurs.
+func (b *StagingBuffer) Flush(flushToDisk bool) (int, error) {
+	if len(b.staged) == 0 {
+		return 0, nil
+	}
+
+	// If we are adding to a partition, add our staged call sequences to it and clear them.
+	if b.partition != nil {
+		addedCount, err := b.partition.addCallSequences(b.staged)
+		b.staged = make([]stagedCallSequence, 0)
+		return addedCount, err
+	}
+
+	// Add our staged call sequences and clear them, even if an error occurred, so they are not added twice.
+	addedCount, err := b.corpus.addCallSequences(b.staged)
+	b.staged = b.staged[:0]
+	if err != nil {
+		return addedCount, err
+	}
+
+	// Flush changes to disk if requested.
+	if addedCount > 0 && flushToDisk {
+		return addedCount, b.corpus.requestFlush()
+	}
+	return addedCount, nil
+}
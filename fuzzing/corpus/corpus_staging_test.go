@@ -0,0 +1,253 @@
+package corpus
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/utils/randomutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// newStagingTestCorpus creates an in-memory corpus with a mutation target chooser, as if it were initialized.
+func newStagingTestCorpus(t testing.TB) *Corpus {
+	corpus, err := NewCorpus("")
+	assert.NoError(t, err)
+	corpus.mutationTargetSequenceChooser = randomutils.NewWeightedRandomChooser[calls.CallSequence]()
+	return corpus
+}
+
+// stageMutableCallSequence stages a call sequence to be added to the corpus as a mutation target, as
+// StagingBuffer.CheckSequenceCoverageAndUpdate does when new coverage is achieved.
+func stageMutableCallSequence(t testing.TB, buffer *StagingBuffer, sequence calls.CallSequence) {
+	entry, err := newStagedCallSequence(buffer.corpus.callSequenceFiles, sequence, &CallSequenceMetadata{}, true, nil)
+	assert.NoError(t, err)
+	buffer.staged = append(buffer.staged, entry)
+}
+
+// TestStagingBufferConcurrentFlush tests that call sequences staged by many workers concurrently are all added to the
+// corpus exactly once, including call sequences staged by more than one worker.
+func TestStagingBufferConcurrentFlush(t *testing.T) {
+	corpus := newStagingTestCorpus(t)
+
+	// Create call sequences which every worker will stage, in addition to their own.
+	sharedSequences := make([]calls.CallSequence, 10)
+	for i := 0; i < len(sharedSequences); i++ {
+		sharedSequences[i] = getMockCallSequence(2)
+	}
+
+	// Stage and flush call sequences from many workers at once, flushing at varying intervals.
+	const workerCount = 16
+	const sequencesPerWorker = 50
+	var wg sync.WaitGroup
+	for workerIndex := 0; workerIndex < workerCount; workerIndex++ {
+		wg.Add(1)
+		go func(workerIndex int) {
+			defer wg.Done()
+			buffer := corpus.NewStagingBuffer()
+			for i := 0; i < sequencesPerWorker; i++ {
+				stageMutableCallSequence(t, buffer, getMockCallSequence(2))
+				assert.NoError(t, buffer.AddTestResultCallSequence(getMockCallSequence(1), nil))
+				if i < len(sharedSequences) {
+					stageMutableCallSequence(t, buffer, sharedSequences[i])
+				}
+				if i%(workerIndex+1) == 0 {
+					_, err := buffer.Flush(false)
+					assert.NoError(t, err)
+				}
+			}
+			_, err := buffer.Flush(false)
+			assert.NoError(t, err)
+			assert.EqualValues(t, 0, buffer.StagedCount())
+		}(workerIndex)
+	}
+	wg.Wait()
+
+	// Every call sequence should have been added exactly once.
+	expectedSequenceCount := workerCount*sequencesPerWorker + len(sharedSequences)
+	sequenceCount, testResultCount := corpus.CallSequenceEntryCount()
+	assert.EqualValues(t, expectedSequenceCount, sequenceCount)
+	assert.EqualValues(t, workerCount*sequencesPerWorker, testResultCount)
+	assert.EqualValues(t, expectedSequenceCount, corpus.ActiveMutableSequenceCount())
+	assert.Len(t, corpus.callSequenceMetadataFiles.files, expectedSequenceCount)
+
+	// Verify no call sequence was added more than once.
+	hashes := make(map[common.Hash]struct{})
+	for _, file := range corpus.callSequenceFiles.files {
+		hash, err := file.data.Hash()
+		assert.NoError(t, err)
+		hashes[hash] = struct{}{}
+	}
+	assert.Len(t, hashes, expectedSequenceCount)
+}
+
+// TestStagingBufferDelayedVisibility tests that staged call sequences are only visible to mutations once flushed, and
+// that call sequences already in the corpus are not added again.
+func TestStagingBufferDelayedVisibility(t *testing.T) {
+	corpus := newStagingTestCorpus(t)
+	buffer := corpus.NewStagingBuffer()
+
+	// Staged call sequences should not be visible until flushed.
+	sequence := getMockCallSequence(3)
+	stageMutableCallSequence(t, buffer, sequence)
+	assert.EqualValues(t, 1, buffer.StagedCount())
+	assert.EqualValues(t, 0, corpus.ActiveMutableSequenceCount())
+
+	addedCount, err := buffer.Flush(false)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, addedCount)
+	assert.EqualValues(t, 1, corpus.ActiveMutableSequenceCount())
+	mutationTarget, err := corpus.mutationTargetSequenceChooser.ChooseWithRand(rand.New(rand.NewSource(1)))
+	assert.NoError(t, err)
+	expectedHash, err := sequence.Hash()
+	assert.NoError(t, err)
+	mutationTargetHash, err := mutationTarget.Hash()
+	assert.NoError(t, err)
+	assert.EqualValues(t, expectedHash, mutationTargetHash)
+
+	// Staging the same call sequence again should not add it twice.
+	stageMutableCallSequence(t, buffer, sequence)
+	addedCount, err = buffer.Flush(false)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, addedCount)
+	assert.EqualValues(t, 1, corpus.ActiveMutableSequenceCount())
+}
+
+// TestStagingBufferKnownCallSequences tests that call sequences which are already staged, or already exist in the
+// corpus, are recognized as known without hashing the corpus again, as files are added to and removed from it.
+func TestStagingBufferKnownCallSequences(t *testing.T) {
+	corpus := newStagingTestCorpus(t)
+	buffer := corpus.NewStagingBuffer()
+
+	// A staged call sequence should be known before it is flushed, and after it was added to the corpus.
+	sequence := getMockCallSequence(2)
+	entry, err := newStagedCallSequence(corpus.callSequenceFiles, sequence, &CallSequenceMetadata{}, true, nil)
+	assert.NoError(t, err)
+	assert.False(t, buffer.contains(entry))
+	buffer.staged = append(buffer.staged, entry)
+	assert.True(t, buffer.contains(entry))
+	_, err = buffer.Flush(false)
+	assert.NoError(t, err)
+	assert.True(t, buffer.contains(entry))
+
+	// The same call sequence is not known to the test result directory.
+	testResultEntry, err := newStagedCallSequence(corpus.testResultSequenceFiles, sequence, &CallSequenceMetadata{}, true, nil)
+	assert.NoError(t, err)
+	assert.False(t, buffer.contains(testResultEntry))
+
+	// Overwriting the file with another call sequence should replace its hash, and removing it should forget it.
+	fileName := corpus.callSequenceFiles.files[0].fileName
+	otherSequence := getMockCallSequence(2)
+	otherHash, err := otherSequence.Hash()
+	assert.NoError(t, err)
+	assert.NoError(t, corpus.callSequenceFiles.addFile(fileName, otherSequence))
+	assert.False(t, buffer.contains(entry))
+	assert.True(t, corpus.callSequenceFiles.containsHash(otherHash))
+	assert.True(t, corpus.callSequenceFiles.removeFile(fileName))
+	assert.False(t, corpus.callSequenceFiles.containsHash(otherHash))
+	assert.Empty(t, corpus.callSequenceFiles.fileHashes)
+}
+
+// TestStagingBufferWeightedTestResult tests that a test result call sequence recorded with a weight multiplier is used
+// as a mutation target, and is chosen proportionally more often than call sequences with a lower weight, while test
+// results recorded without one are not used in mutations.
+func TestStagingBufferWeightedTestResult(t *testing.T) {
+	corpus := newStagingTestCorpus(t)
+	buffer := corpus.NewStagingBuffer()
+
+	// Stage an ordinary coverage-increasing call sequence, a test result which is not used in mutations, and a test
+	// result which achieved a high optimization value, all with the same base weight.
+	coverageSequence := getMockCallSequence(2)
+	stageMutableCallSequence(t, buffer, coverageSequence)
+	assert.NoError(t, buffer.AddTestResultCallSequence(getMockCallSequence(2), big.NewInt(1)))
+	highValueSequence := getMockCallSequence(2)
+	assert.NoError(t, buffer.AddWeightedTestResultCallSequence(highValueSequence, big.NewInt(1), big.NewInt(9)))
+	addedCount, err := buffer.Flush(false)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, addedCount)
+	assert.EqualValues(t, 2, corpus.ActiveMutableSequenceCount())
+
+	// The weight multiplier should be recorded, so it can be restored when the corpus is initialized again.
+	assert.EqualValues(t, big.NewInt(9), corpus.testResultWeightMultiplier(corpus.testResultSequenceFiles.files[1].fileName))
+	assert.Nil(t, corpus.testResultWeightMultiplier(corpus.testResultSequenceFiles.files[0].fileName))
+
+	// The high value call sequence should be chosen roughly nine times as often as the coverage call sequence.
+	highValueHash, err := highValueSequence.Hash()
+	assert.NoError(t, err)
+	randomProvider := rand.New(rand.NewSource(1))
+	const choiceCount = 10000
+	highValueChoices := 0
+	for i := 0; i < choiceCount; i++ {
+		mutationTarget, err := corpus.mutationTargetSequenceChooser.ChooseWithRand(randomProvider)
+		assert.NoError(t, err)
+		mutationTargetHash, err := mutationTarget.Hash()
+		assert.NoError(t, err)
+		if mutationTargetHash == highValueHash {
+			highValueChoices++
+		}
+	}
+	assert.InDelta(t, 0.9, float64(highValueChoices)/choiceCount, 0.02)
+}
+
+// TestScaleMutationChooserWeight tests that mutation chooser weights are scaled by their multiplier, with nil values
+// treated as 1, so callers which do not provide a multiplier keep their existing weights.
+func TestScaleMutationChooserWeight(t *testing.T) {
+	assert.EqualValues(t, big.NewInt(1), scaleMutationChooserWeight(nil, nil))
+	assert.EqualValues(t, big.NewInt(7), scaleMutationChooserWeight(big.NewInt(7), nil))
+	assert.EqualValues(t, big.NewInt(3), scaleMutationChooserWeight(nil, big.NewInt(3)))
+	assert.EqualValues(t, big.NewInt(21), scaleMutationChooserWeight(big.NewInt(7), big.NewInt(3)))
+
+	// The provided weight should not be modified.
+	weight := big.NewInt(7)
+	scaleMutationChooserWeight(weight, big.NewInt(3))
+	assert.EqualValues(t, big.NewInt(7), weight)
+}
+
+// BenchmarkCorpusAdditionContention measures the cost of many workers adding call sequences to the corpus at once,
+// either directly or through a StagingBuffer flushed at every few additions.
+func BenchmarkCorpusAdditionContention(b *testing.B) {
+	const workerCount = 16
+	const sequencesPerWorker = 16
+	const stagedPerFlush = 4
+
+	// Generate our call sequences ahead of time, so generation is not measured.
+	sequences := make([][]calls.CallSequence, workerCount)
+	for i := 0; i < workerCount; i++ {
+		sequences[i] = make([]calls.CallSequence, sequencesPerWorker)
+		for j := 0; j < sequencesPerWorker; j++ {
+			sequences[i][j] = getMockCallSequence(4)
+		}
+	}
+
+	for _, staged := range []bool{false, true} {
+		b.Run(fmt.Sprintf("staged=%v", staged), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				corpus := newStagingTestCorpus(b)
+				var wg sync.WaitGroup
+				for i := 0; i < workerCount; i++ {
+					wg.Add(1)
+					go func(workerSequences []calls.CallSequence) {
+						defer wg.Done()
+						buffer := corpus.NewStagingBuffer()
+						for j, sequence := range workerSequences {
+							if !staged {
+								_, _ = corpus.addCallSequence(corpus.callSequenceFiles, sequence, &CallSequenceMetadata{}, true, nil, false)
+								continue
+							}
+							stageMutableCallSequence(b, buffer, sequence)
+							if (j+1)%stagedPerFlush == 0 {
+								_, _ = buffer.Flush(false)
+							}
+						}
+						_, _ = buffer.Flush(false)
+					}(sequences[i])
+				}
+				wg.Wait()
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package corpus
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// CorpusStats describes a snapshot of the composition of a Corpus, including the distribution of weights with which
+// call sequences are selected as mutation targets.
+type CorpusStats struct {
+	// TotalSequences describes the amount of call sequences recorded in the corpus, including test results.
+	TotalSequences int `json:"totalSequences"`
+
+	// MutableSequences describes the amount of call sequences which are active and may be selected as mutation
+	// targets.
+	MutableSequences int `json:"mutableSequences"`
+
+	// TestResultSequences describes the amount of call sequences recorded in the corpus because they triggered a test
+	// failure.
+	TestResultSequences int `json:"testResultSequences"`
+
+	// UnexecutedSequences describes the amount of call sequences loaded from disk which have not yet been replayed by
+	// the fuzzer, including those deferred by the corpus replay limit.
+	UnexecutedSequences int `json:"unexecutedSequences"`
+
+	// TotalWeight describes the sum of the weights of all mutation targets.
+	TotalWeight *big.Int `json:"totalWeight"`
+
+	// MinWeight describes the lowest weight of any mutation target, or zero if there are none.
+	MinWeight *big.Int `json:"minWeight"`
+
+	// MedianWeight describes the median weight of the mutation targets, or zero if there are none.
+	MedianWeight *big.Int `json:"medianWeight"`
+
+	// MaxWeight describes the highest weight of any mutation target, or zero if there are none.
+	MaxWeight *big.Int `json:"maxWeight"`
+}
+
+// Stats returns a CorpusStats snapshot describing the current composition of the corpus. Locks are only held while
+// counts and weights are copied, so the snapshot does not block the fuzzer while the weight distribution is computed.
+func (c *Corpus) Stats() CorpusStats {
+	// Copy our counts and weights.
+	c.callSequencesLock.Lock()
+	stats := CorpusStats{
+		TotalSequences:      len(c.callSequenceFiles.files) + len(c.testResultSequenceFiles.files),
+		TestResultSequences: len(c.testResultSequenceFiles.files),
+		UnexecutedSequences: len(c.unexecutedCallSequences) + len(c.deferredCallSequences),
+	}
+	var weights []*big.Int
+	if c.mutationTargetSequenceChooser != nil {
+		weights = c.mutationTargetSequenceChooser.Weights()
+	}
+	c.callSequencesLock.Unlock()
+
+	// Compute the distribution of our weights.
+	stats.MutableSequences = len(weights)
+	stats.TotalWeight = big.NewInt(0)
+	stats.MinWeight, stats.MedianWeight, stats.MaxWeight = big.NewInt(0), big.NewInt(0), big.NewInt(0)
+	if len(weights) == 0 {
+		return stats
+	}
+	sort.Slice(weights, func(i, j int) bool {
+		return weights[i].Cmp(weights[j]) < 0
+	})
+	for _, weight := range weights {
+		stats.TotalWeight.Add(stats.TotalWeight, weight)
+	}
+	stats.MinWeight.Set(weights[0])
+	stats.MedianWeight.Set(weights[(len(weights)-1)/2])
+	stats.MaxWeight.Set(weights[len(weights)-1])
+	return stats
+}
+
+// String returns a displayable one-line summary of the CorpusStats.
+func (s CorpusStats) String() string {
+	formatWeight := func(weight *big.Int) string {
+		return new(big.Float).SetInt(weight).Text('g', 3)
+	}
+	return fmt.Sprintf(
+		"%d sequences: %d mutable, %d failures, %d unexecuted, total weight %s (min %s, median %s, max %s)",
+		s.TotalSequences, s.MutableSequences, s.TestResultSequences, s.UnexecutedSequences,
+		formatWeight(s.TotalWeight), formatWeight(s.MinWeight), formatWeight(s.MedianWeight), formatWeight(s.MaxWeight),
+	)
+}
@@ -0,0 +1,54 @@
+package corpus
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/utils/randomutils"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCorpusStats ensures that a CorpusStats snapshot accurately describes the composition of a small synthetic
+// corpus, including the distribution of its mutation target weights.
+func TestCorpusStats(t *testing.T) {
+	corpus, err := NewCorpus("")
+	assert.NoError(t, err)
+
+	// An uninitialized corpus should report no sequences or weights.
+	stats := corpus.Stats()
+	assert.EqualValues(t, 0, stats.TotalSequences)
+	assert.EqualValues(t, 0, stats.MutableSequences)
+	assert.EqualValues(t, 0, stats.TotalWeight.Int64())
+	assert.EqualValues(t, 0, stats.MedianWeight.Int64())
+
+	// Add mutable call sequences with varying weights, a test result, and some sequences which were not yet executed.
+	corpus.mutationTargetSequenceChooser = randomutils.NewWeightedRandomChooser[calls.CallSequence]()
+	choices := make([]*randomutils.WeightedRandomChoice[calls.CallSequence], 0)
+	for i, weight := range []int64{5, 1, 100, 3} {
+		sequence := getMockCallSequence(1)
+		assert.NoError(t, corpus.callSequenceFiles.addFile(fmt.Sprintf("%d.json", i), sequence))
+		choices = append(choices, randomutils.NewWeightedRandomChoice(sequence, big.NewInt(weight)))
+	}
+	corpus.mutationTargetSequenceChooser.AddChoices(choices...)
+	assert.NoError(t, corpus.testResultSequenceFiles.addFile("failure.json", getMockCallSequence(1)))
+	corpus.unexecutedCallSequences = append(corpus.unexecutedCallSequences, unexecutedCallSequence{fileName: "0.json"})
+	corpus.deferredCallSequences = append(corpus.deferredCallSequences, unexecutedCallSequence{fileName: "1.json"})
+
+	stats = corpus.Stats()
+	assert.EqualValues(t, 5, stats.TotalSequences)
+	assert.EqualValues(t, 4, stats.MutableSequences)
+	assert.EqualValues(t, 1, stats.TestResultSequences)
+	assert.EqualValues(t, 2, stats.UnexecutedSequences)
+	assert.EqualValues(t, 109, stats.TotalWeight.Int64())
+	assert.EqualValues(t, 1, stats.MinWeight.Int64())
+	assert.EqualValues(t, 3, stats.MedianWeight.Int64())
+	assert.EqualValues(t, 100, stats.MaxWeight.Int64())
+	assert.Equal(t, "5 sequences: 4 mutable, 1 failures, 2 unexecuted, total weight 109 (min 1, median 3, max 100)", stats.String())
+
+	// Updating a weight should be reflected in later snapshots, without affecting earlier ones.
+	corpus.mutationTargetSequenceChooser.SetChoiceWeight(choices[2], big.NewInt(2))
+	assert.EqualValues(t, 100, stats.MaxWeight.Int64())
+	assert.EqualValues(t, 5, corpus.Stats().MaxWeight.Int64())
+}
@@ -0,0 +1,128 @@
+package corpus
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+)
+
+// callSequenceTags derives the tags of a call sequence from the contracts it touches, which are the names of the
+// contract definitions its calls were resolved to target, sorted and without duplicates.
+func callSequenceTags(callSequence calls.CallSequence) []string {
+	tags := make([]string, 0)
+	for _, element := range callSequence {
+		if element == nil || element.Contract == nil {
+			continue
+		}
+		if name := element.Contract.Name(); !slices.Contains(tags, name) {
+			tags = append(tags, name)
+		}
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// mergeTags returns the union of the provided sets of tags, sorted and without duplicates.
+func mergeTags(tagSets ...[]string) []string {
+	tags := make([]string, 0)
+	for _, tagSet := range tagSets {
+		for _, tag := range tagSet {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// withTags returns the provided call sequence metadata with the provided tags merged into any tags it was created
+// with (e.g. by the caller adding the call sequence). The provided metadata is not modified. If the metadata is nil,
+// nil is returned, as tags are only recorded alongside other metadata.
+func withTags(metadata *CallSequenceMetadata, tags []string) *CallSequenceMetadata {
+	if metadata == nil {
+		return nil
+	}
+	metadataWithTags := *metadata
+	metadataWithTags.Tags = mergeTags(metadata.Tags, tags)
+	return &metadataWithTags
+}
+
+// SetCallSequenceTags sets the tags recorded in the metadata of the call sequence stored with the provided file name
+// in the provided corpus directory, replacing any tags recorded for it before. It is intended for external tooling
+// which labels call sequences without running the fuzzer (e.g. by the subsystem they exercise). When the Corpus is
+// initialized again, the tags derived from the contracts the call sequence touches are merged into the recorded tags,
+// so they never need to be repeated. The corpus directory should not be in use by a fuzzer while this is called.
+// Returns an error if the call sequence does not exist or the metadata could not be written.
+func SetCallSequenceTags(directory string, fileName string, tags []string) error {
+	// Ensure the call sequence exists, so we do not record metadata which is never read.
+	found := false
+	for _, sequenceDirectory := range []string{"call_sequences", "test_results"} {
+		sequenceFiles := newCorpusDirectory[calls.CallSequence](filepath.Join(directory, sequenceDirectory))
+		if err := sequenceFiles.readFiles("*.json"); err != nil {
+			return err
+		}
+		if _, ok := sequenceFiles.fileData(fileName); ok {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("could not set tags of call sequence %s, as it does not exist in the corpus directory", fileName)
+	}
+
+	// Update the tags of the call sequence's metadata, creating the metadata if none was recorded, and write it.
+	callSequenceMetadataFiles := newCorpusDirectory[CallSequenceMetadata](filepath.Join(directory, "call_sequence_metadata"))
+	if err := callSequenceMetadataFiles.readFiles("*.json"); err != nil {
+		return err
+	}
+	metadata, _ := callSequenceMetadataFiles.fileData(fileName)
+	metadata.Tags = mergeTags(tags)
+	if err := callSequenceMetadataFiles.addFile(fileName, metadata); err != nil {
+		return err
+	}
+	return callSequenceMetadataFiles.writeFiles()
+}
+
+// SetMutationTagAllowlist sets the tags a call sequence must have at least one of to be used as a mutation target.
+// Call sequences without an allowed tag are still replayed when the Corpus is initialized, so their coverage is
+// measured, but are never returned by RandomMutationTargetSequence. If no tags are provided, every call sequence is
+// used as a mutation target. This should be called before the Corpus is initialized.
+func (c *Corpus) SetMutationTagAllowlist(tags []string) {
+	if len(tags) == 0 {
+		c.mutationTagAllowlist = nil
+		return
+	}
+	c.mutationTagAllowlist = make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		c.mutationTagAllowlist[tag] = struct{}{}
+	}
+}
+
+// mutationTagsAllowed determines whether a call sequence with the provided tags may be used as a mutation target,
+// given the allowlist set with SetMutationTagAllowlist.
+func (c *Corpus) mutationTagsAllowed(tags []string) bool {
+	if c.mutationTagAllowlist == nil {
+		return true
+	}
+	for _, tag := range tags {
+		if _, ok := c.mutationTagAllowlist[tag]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// callSequenceFileMutationAllowed determines whether the call sequence loaded from disk with the provided file name
+// may be used as a mutation target, given the allowlist set with SetMutationTagAllowlist. Tags recorded in its
+// metadata (e.g. by external tooling, see SetCallSequenceTags) are merged with the tags derived from the contracts the
+// call sequence touches, which requires its calls to have been resolved.
+func (c *Corpus) callSequenceFileMutationAllowed(fileName string, callSequence calls.CallSequence) bool {
+	if c.mutationTagAllowlist == nil {
+		return true
+	}
+	metadata, _ := c.callSequenceMetadataFiles.fileData(fileName)
+	return c.mutationTagsAllowed(mergeTags(metadata.Tags, callSequenceTags(callSequence)))
+}
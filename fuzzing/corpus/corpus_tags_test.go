@@ -0,0 +1,161 @@
+package corpus
+
+import (
+	"math/rand"
+	"testing"
+
+	compilationTypes "github.com/crytic/medusa/compilation/types"
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/coverage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// getMockTaggedCallSequence creates a mock call sequence which calls each of the provided contracts in turn. Its calls
+// have empty ABI values, so it can be cloned when returned as a mutation target.
+func getMockTaggedCallSequence(contractDefinitions ...*contracts.Contract) calls.CallSequence {
+	sequence := getMockCallSequence(len(contractDefinitions))
+	for i, contract := range contractDefinitions {
+		sequence[i].Contract = contract
+		sequence[i].Call.DataAbiValues = &calls.CallMessageDataAbiValues{}
+	}
+	return sequence
+}
+
+// TestCorpusTagAllowlist builds a corpus of call sequences touching different contracts, and tests that only those
+// tagged with an allowlisted contract are ever returned as mutation targets, by the corpus and its partitions, while
+// the tags of every call sequence are recorded in its metadata.
+func TestCorpusTagAllowlist(t *testing.T) {
+	lending := contracts.NewContract("LendingPool", "", &compilationTypes.CompiledContract{}, nil)
+	oracle := contracts.NewContract("PriceOracle", "", &compilationTypes.CompiledContract{}, nil)
+	token := contracts.NewContract("Token", "", &compilationTypes.CompiledContract{}, nil)
+
+	corpus := newStagingTestCorpus(t)
+	corpus.SetMutationTagAllowlist([]string{"LendingPool"})
+
+	// Add call sequences with mixed tags. Only those touching the lending pool should be mutated.
+	allowedSequences := []calls.CallSequence{
+		getMockTaggedCallSequence(lending),
+		getMockTaggedCallSequence(token, lending, token),
+	}
+	disallowedSequences := []calls.CallSequence{
+		getMockTaggedCallSequence(oracle),
+		getMockTaggedCallSequence(token, oracle),
+		getMockCallSequence(2),
+	}
+	buffer := corpus.NewStagingBuffer()
+	for _, sequence := range append(append([]calls.CallSequence{}, allowedSequences...), disallowedSequences...) {
+		stageMutableCallSequence(t, buffer, sequence)
+	}
+	addedCount, err := buffer.Flush(false)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, addedCount)
+	assert.EqualValues(t, 2, corpus.ActiveMutableSequenceCount())
+
+	// Every call sequence should be recorded with its tags, whether or not it is mutated.
+	tags := make([][]string, 0)
+	for _, metadata := range corpus.CallSequenceMetadata() {
+		tags = append(tags, metadata.Tags)
+	}
+	assert.ElementsMatch(t, [][]string{{"LendingPool"}, {"LendingPool", "Token"}, {"PriceOracle"}, {"PriceOracle", "Token"}, {}}, tags)
+
+	// Add further call sequences through a partition, which should be filtered the same way.
+	partition := corpus.NewPartitions(1, 0)[0]
+	partitionBuffer := partition.NewStagingBuffer()
+	allowedSequences = append(allowedSequences, getMockTaggedCallSequence(oracle, lending))
+	stageMutableCallSequence(t, partitionBuffer, allowedSequences[len(allowedSequences)-1])
+	stageMutableCallSequence(t, partitionBuffer, getMockTaggedCallSequence(token))
+	_, err = partitionBuffer.Flush(false)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, partition.ActiveMutableSequenceCount())
+
+	// Only allowlisted call sequences should ever be returned as mutation targets.
+	allowedHashes := make(map[common.Hash]struct{})
+	for _, sequence := range allowedSequences {
+		hash, err := sequence.Hash()
+		assert.NoError(t, err)
+		allowedHashes[hash] = struct{}{}
+	}
+	randomProvider := rand.New(rand.NewSource(1234))
+	for i := 0; i < 1000; i++ {
+		for _, chooser := range []func(*rand.Rand) (calls.CallSequence, error){corpus.RandomMutationTargetSequence, partition.RandomMutationTargetSequence} {
+			sequence, err := chooser(randomProvider)
+			assert.NoError(t, err)
+			hash, err := sequence.Hash()
+			assert.NoError(t, err)
+			assert.Contains(t, allowedHashes, hash)
+		}
+	}
+
+	// Without an allowlist, every call sequence is mutated.
+	unfilteredCorpus := newStagingTestCorpus(t)
+	unfilteredCorpus.SetMutationTagAllowlist(nil)
+	buffer = unfilteredCorpus.NewStagingBuffer()
+	for _, sequence := range disallowedSequences {
+		stageMutableCallSequence(t, buffer, sequence)
+	}
+	_, err = buffer.Flush(false)
+	assert.NoError(t, err)
+	assert.EqualValues(t, len(disallowedSequences), unfilteredCorpus.ActiveMutableSequenceCount())
+}
+
+// TestCorpusTagsMerged tests that tags a call sequence is added with, or which are set by external tooling, are merged
+// with the tags derived from the contracts it calls, rather than replaced by them.
+func TestCorpusTagsMerged(t *testing.T) {
+	token := contracts.NewContract("Token", "", &compilationTypes.CompiledContract{}, nil)
+	sequence := getMockTaggedCallSequence(token)
+
+	// Tags provided with the metadata of a call sequence should be kept alongside the derived tags, and used to filter
+	// mutation targets.
+	corpus := newStagingTestCorpus(t)
+	corpus.SetMutationTagAllowlist([]string{"Lending"})
+	buffer := corpus.NewStagingBuffer()
+	entry, err := newStagedCallSequence(corpus.callSequenceFiles, sequence, &CallSequenceMetadata{Tags: []string{"Lending"}}, true, nil)
+	assert.NoError(t, err)
+	buffer.staged = append(buffer.staged, entry)
+	_, err = buffer.Flush(false)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, corpus.ActiveMutableSequenceCount())
+	for _, metadata := range corpus.CallSequenceMetadata() {
+		assert.EqualValues(t, []string{"Lending", "Token"}, metadata.Tags)
+	}
+
+	// Write a call sequence to a corpus directory, so its tags can be set externally. Its calls have ABI values which
+	// can be serialized, and its contract is resolved as it would be when the corpus is initialized.
+	sequence = getMockCallSequence(1)
+	sequence[0].Contract = token
+	directory := t.TempDir()
+	corpus, err = NewCorpus(directory)
+	assert.NoError(t, err)
+	_, err = corpus.AddCallSequenceWithCoverageDelta(sequence, &coverage.CoverageDelta{}, nil, true)
+	assert.NoError(t, err)
+	assert.NoError(t, corpus.Flush())
+	var sequenceFileName string
+	for name := range corpus.CallSequenceMetadata() {
+		sequenceFileName = name
+	}
+	assert.NotEmpty(t, sequenceFileName)
+
+	// Setting tags of a call sequence which does not exist should fail.
+	err = SetCallSequenceTags(directory, "missing.json", []string{"Lending"})
+	assert.Error(t, err)
+
+	// Set the tags externally, then load the corpus again. The set tags should be recorded, and merged with the derived
+	// tags when determining whether the call sequence is mutated.
+	err = SetCallSequenceTags(directory, sequenceFileName, []string{"Lending", "Lending"})
+	assert.NoError(t, err)
+	records, err := ReadCallSequenceRecords(directory)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.EqualValues(t, []string{"Lending"}, records[0].Metadata.Tags)
+
+	corpus, err = NewCorpus(directory)
+	assert.NoError(t, err)
+	for _, allowlist := range [][]string{{"Lending"}, {"Token"}} {
+		corpus.SetMutationTagAllowlist(allowlist)
+		assert.True(t, corpus.callSequenceFileMutationAllowed(sequenceFileName, sequence))
+	}
+	corpus.SetMutationTagAllowlist([]string{"PriceOracle"})
+	assert.False(t, corpus.callSequenceFileMutationAllowed(sequenceFileName, sequence))
+}
@@ -2,14 +2,27 @@ package corpus
 
 import (
 	"encoding/json"
-	"github.com/crytic/medusa/fuzzing/calls"
-	"github.com/crytic/medusa/utils/testutils"
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/stretchr/testify/assert"
+	"fmt"
 	"math/big"
 	"math/rand"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/crytic/medusa/chain/testchainutils"
+	compilationTypes "github.com/crytic/medusa/compilation/types"
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/coverage"
+	"github.com/crytic/medusa/utils/randomutils"
+	"github.com/crytic/medusa/utils/testutils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
 )
 
 // getMockSimpleCorpus creates a mock corpus with numEntries callSequencesByFilePath for testing
@@ -23,7 +36,7 @@ func getMockSimpleCorpus(minSequences int, maxSequences, minBlocks int, maxBlock
 	// Add the requested number of entries.
 	numSequences := minSequences + (rand.Int() % (maxSequences - minSequences))
 	for i := 0; i < numSequences; i++ {
-		err := corpus.addCallSequence(corpus.callSequenceFiles, getMockCallSequence(minBlocks+(rand.Int()%(maxBlocks-minBlocks))), true, nil, false)
+		_, err := corpus.addCallSequence(corpus.callSequenceFiles, getMockCallSequence(minBlocks+(rand.Int()%(maxBlocks-minBlocks))), nil, true, nil, false)
 		if err != nil {
 			return nil, err
 		}
@@ -48,6 +61,8 @@ func getMockCallSequenceElement() *calls.CallSequenceElement {
 		BlockNumberDelay:    rand.Uint64(),
 		BlockTimestampDelay: rand.Uint64(),
 		ChainReference:      nil,
+		Setup:               rand.Intn(2) == 0,
+		Frozen:              rand.Intn(2) == 0,
 	}
 }
 
@@ -87,6 +102,10 @@ func testCorpusCallSequenceElementsEqual(t *testing.T, expected calls.CallSequen
 	// Make sure delays are equal
 	assert.EqualValues(t, expected.BlockNumberDelay, actual.BlockNumberDelay)
 	assert.EqualValues(t, expected.BlockTimestampDelay, actual.BlockTimestampDelay)
+
+	// Make sure shrinking flags are equal
+	assert.EqualValues(t, expected.Setup, actual.Setup)
+	assert.EqualValues(t, expected.Frozen, actual.Frozen)
 }
 
 // TestCorpusReadWrite first writes the corpus to disk and then reads it back from the disk and ensures integrity.
@@ -114,6 +133,80 @@ func TestCorpusReadWrite(t *testing.T) {
 	})
 }
 
+// TestCorpusTestResultCallSequenceFilePath ensures the file path of a test result call sequence is only resolved once
+// it was written to disk, and that the file records the call sequence.
+func TestCorpusTestResultCallSequenceFilePath(t *testing.T) {
+	testutils.ExecuteInDirectory(t, t.TempDir(), func() {
+		corpus, err := NewCorpus("corpus")
+		assert.NoError(t, err)
+
+		// Record a test result without flushing it, so it has no file path yet.
+		sequence := getMockCallSequence(3)
+		err = corpus.AddTestResultCallSequence(sequence, nil, false)
+		assert.NoError(t, err)
+		filePath, err := corpus.TestResultCallSequenceFilePath(sequence)
+		assert.NoError(t, err)
+		assert.Empty(t, filePath)
+
+		// Once flushed, the file path should resolve to a file recording the call sequence.
+		err = corpus.Flush()
+		assert.NoError(t, err)
+		filePath, err = corpus.TestResultCallSequenceFilePath(sequence)
+		assert.NoError(t, err)
+		assert.Equal(t, corpus.testResultSequenceFiles.path, filepath.Dir(filePath))
+		b, err := os.ReadFile(filePath)
+		assert.NoError(t, err)
+		var writtenSequence calls.CallSequence
+		err = json.Unmarshal(b, &writtenSequence)
+		assert.NoError(t, err)
+		testCorpusCallSequencesEqual(t, sequence, writtenSequence)
+
+		// Call sequences which were not recorded should not resolve.
+		filePath, err = corpus.TestResultCallSequenceFilePath(getMockCallSequence(3))
+		assert.NoError(t, err)
+		assert.Empty(t, filePath)
+	})
+}
+
+// TestCorpusAddCallSequenceWithCoverageDelta ensures call sequences which achieved coverage elsewhere are added with
+// their coverage merged into the corpus coverage maps, and that a read-only corpus does not write them to disk.
+func TestCorpusAddCallSequenceWithCoverageDelta(t *testing.T) {
+	testutils.ExecuteInDirectory(t, t.TempDir(), func() {
+		corpus, err := NewCorpus("corpus")
+		assert.NoError(t, err)
+		corpus.SetReadOnly(true)
+
+		// Add a call sequence with a delta covering three program counters.
+		coverageDelta := &coverage.CoverageDelta{
+			Contracts: []*coverage.ContractCoverageDelta{
+				{LookupHash: common.HexToHash("0x1234"), SuccessfulPCs: []int{0, 2}, RevertedPCs: []int{5}},
+			},
+		}
+		sequence := getMockCallSequence(3)
+		added, err := corpus.AddCallSequenceWithCoverageDelta(sequence, coverageDelta, big.NewInt(1), true)
+		assert.NoError(t, err)
+		assert.True(t, added)
+		assert.EqualValues(t, 3, corpus.CoverageMaps().UniquePCs())
+		assert.EqualValues(t, 3, corpus.FuzzingCoverageMaps().UniquePCs())
+		callSequences, _ := corpus.CallSequenceEntryCount()
+		assert.EqualValues(t, 1, callSequences)
+		metadata := corpus.CallSequenceMetadata()
+		assert.Len(t, metadata, 1)
+		for _, sequenceMetadata := range metadata {
+			assert.EqualValues(t, coverageDelta, sequenceMetadata.CoverageDelta)
+		}
+
+		// Adding the same call sequence again should not duplicate it.
+		added, err = corpus.AddCallSequenceWithCoverageDelta(sequence, coverageDelta, big.NewInt(1), true)
+		assert.NoError(t, err)
+		assert.False(t, added)
+
+		// As the corpus is read-only, nothing should have been written to disk.
+		_, err = os.Stat("corpus")
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
 // TestCorpusCallSequenceMarshaling ensures that a corpus entry that is round trip serialized retains its original
 // values.
 func TestCorpusCallSequenceMarshaling(t *testing.T) {
@@ -145,3 +238,296 @@ func TestCorpusCallSequenceMarshaling(t *testing.T) {
 		assert.Empty(t, corpus.callSequenceFiles.files)
 	})
 }
+
+// TestCorpusUnexecutedCallSequenceReplayLimit ensures that un-executed call sequences stop being returned once the
+// corpus replay limit is reached, with the remaining call sequences being deferred.
+func TestCorpusUnexecutedCallSequenceReplayLimit(t *testing.T) {
+	// Create a mock corpus with four un-executed call sequences of three calls each.
+	corpus, err := NewCorpus("")
+	assert.NoError(t, err)
+	for i := 0; i < 4; i++ {
+		corpus.unexecutedCallSequences = append(corpus.unexecutedCallSequences, unexecutedCallSequence{sequence: getMockCallSequence(3)})
+	}
+
+	// With a limit of four calls, two sequences should be returned (the second exceeding the limit), after which the
+	// remaining sequences are deferred.
+	assert.NotNil(t, corpus.UnexecutedCallSequence(4))
+	assert.NotNil(t, corpus.UnexecutedCallSequence(4))
+	assert.Nil(t, corpus.UnexecutedCallSequence(4))
+	assert.Nil(t, corpus.UnexecutedCallSequence(4))
+	assert.EqualValues(t, 2, corpus.DeferredCallSequenceCount())
+
+	// Without a limit, all sequences should be returned.
+	corpus, err = NewCorpus("")
+	assert.NoError(t, err)
+	for i := 0; i < 4; i++ {
+		corpus.unexecutedCallSequences = append(corpus.unexecutedCallSequences, unexecutedCallSequence{sequence: getMockCallSequence(3)})
+	}
+	for i := 0; i < 4; i++ {
+		assert.NotNil(t, corpus.UnexecutedCallSequence(0))
+	}
+	assert.Nil(t, corpus.UnexecutedCallSequence(0))
+	assert.EqualValues(t, 0, corpus.DeferredCallSequenceCount())
+}
+
+// TestCorpusDeprioritizeSlowCallSequence ensures that call sequences whose replay exceeded the threshold have their
+// mutation chooser weight reduced in proportion to the excess, without leaving no call sequence to select.
+func TestCorpusDeprioritizeSlowCallSequence(t *testing.T) {
+	// Create a mock corpus with two mutation targets loaded from disk.
+	corpus, err := NewCorpus("")
+	assert.NoError(t, err)
+	corpus.mutationTargetSequenceChooser = randomutils.NewWeightedRandomChooser[calls.CallSequence]()
+	corpus.mutationTargetChoicesByFileName = make(map[string]*randomutils.WeightedRandomChoice[calls.CallSequence])
+	for fileName, weight := range map[string]int64{"slow.json": 10, "fast.json": 1} {
+		choice := randomutils.NewWeightedRandomChoice[calls.CallSequence](getMockCallSequence(3), big.NewInt(weight))
+		corpus.mutationTargetSequenceChooser.AddChoices(choice)
+		corpus.mutationTargetChoicesByFileName[fileName] = choice
+	}
+	slowChoice := corpus.mutationTargetChoicesByFileName["slow.json"]
+	fastChoice := corpus.mutationTargetChoicesByFileName["fast.json"]
+
+	// Call sequences within the threshold, or which are not mutation targets, should not be deprioritized.
+	assert.False(t, corpus.DeprioritizeSlowCallSequence("slow.json", time.Second, time.Second))
+	assert.False(t, corpus.DeprioritizeSlowCallSequence("unknown.json", 10*time.Second, time.Second))
+	assert.False(t, corpus.DeprioritizeSlowCallSequence("slow.json", 10*time.Second, 0))
+	assert.EqualValues(t, 11, corpus.mutationTargetSequenceChooser.TotalWeight().Int64())
+
+	// A call sequence which took between two and three times the threshold should have its weight divided by three,
+	// and should only be deprioritized once.
+	assert.True(t, corpus.DeprioritizeSlowCallSequence("slow.json", 2500*time.Millisecond, time.Second))
+	assert.EqualValues(t, 3, corpus.mutationTargetSequenceChooser.ChoiceWeight(slowChoice).Int64())
+	assert.EqualValues(t, 4, corpus.mutationTargetSequenceChooser.TotalWeight().Int64())
+	assert.False(t, corpus.DeprioritizeSlowCallSequence("slow.json", 10*time.Second, time.Second))
+
+	// A call sequence with a weight of one should no longer be selected once deprioritized.
+	assert.True(t, corpus.DeprioritizeSlowCallSequence("fast.json", 2*time.Second, time.Second))
+	assert.EqualValues(t, 0, corpus.mutationTargetSequenceChooser.ChoiceWeight(fastChoice).Int64())
+	for i := 0; i < 10; i++ {
+		sequence, err := corpus.mutationTargetSequenceChooser.ChooseWithRand(rand.New(rand.NewSource(int64(i))))
+		assert.NoError(t, err)
+		assert.Same(t, slowChoice.Data[0], (*sequence)[0])
+	}
+
+	// The last call sequence with a non-zero weight should keep a weight of one, so mutations remain possible.
+	corpus.mutationTargetSequenceChooser = randomutils.NewWeightedRandomChooser[calls.CallSequence]()
+	corpus.mutationTargetChoicesByFileName = make(map[string]*randomutils.WeightedRandomChoice[calls.CallSequence])
+	onlyChoice := randomutils.NewWeightedRandomChoice[calls.CallSequence](getMockCallSequence(3), big.NewInt(4))
+	corpus.mutationTargetSequenceChooser.AddChoices(onlyChoice)
+	corpus.mutationTargetChoicesByFileName["only.json"] = onlyChoice
+	assert.True(t, corpus.DeprioritizeSlowCallSequence("only.json", 10*time.Second, time.Second))
+	assert.EqualValues(t, 1, corpus.mutationTargetSequenceChooser.ChoiceWeight(onlyChoice).Int64())
+}
+
+// TestCorpusOutdatedAbiCalls ensures that a corpus call sequence containing a call which references an outdated ABI
+// is disabled by default, or has the call dropped (with the remaining calls' delays and nonces adjusted) if configured.
+func TestCorpusOutdatedAbiCalls(t *testing.T) {
+	// Create a test chain with a funded sender.
+	testChain, sender := testchainutils.NewFundedTestChain(t)
+
+	// Deploy a contract whose runtime bytecode simply stops, so any call to it succeeds.
+	initBytecode := common.Hex2Bytes("6001600c60003960016000f300")
+	deployMsg := calls.NewCallMessage(sender, nil, 0, big.NewInt(0), 1_000_000, big.NewInt(1), big.NewInt(0), big.NewInt(0), initBytecode)
+	_, err := testChain.PendingBlockCreate()
+	assert.NoError(t, err)
+	assert.NoError(t, testChain.PendingBlockAddTx(deployMsg.ToCoreMessage()))
+	assert.NoError(t, testChain.PendingBlockCommit())
+	contractAddress := crypto.CreateAddress(sender, 0)
+
+	// Define the ABI the corpus was recorded with, and the current ABI, which no longer has the "removed" method.
+	oldAbi, err := abi.JSON(strings.NewReader(`[
+		{"type":"function","name":"setValue","inputs":[{"name":"x","type":"uint256"}],"outputs":[],"stateMutability":"nonpayable"},
+		{"type":"function","name":"increment","inputs":[],"outputs":[],"stateMutability":"nonpayable"},
+		{"type":"function","name":"removed","inputs":[{"name":"x","type":"uint256"}],"outputs":[],"stateMutability":"nonpayable"}
+	]`))
+	assert.NoError(t, err)
+	currentAbi, err := abi.JSON(strings.NewReader(`[
+		{"type":"function","name":"setValue","inputs":[{"name":"x","type":"uint256"}],"outputs":[],"stateMutability":"nonpayable"},
+		{"type":"function","name":"increment","inputs":[],"outputs":[],"stateMutability":"nonpayable"}
+	]`))
+	assert.NoError(t, err)
+	contractDefinitions := contracts.Contracts{
+		contracts.NewContract("TestContract", "", &compilationTypes.CompiledContract{
+			Abi:             currentAbi,
+			InitBytecode:    initBytecode,
+			RuntimeBytecode: common.Hex2Bytes("00"),
+		}, nil),
+	}
+
+	// Create the serialized corpus call sequences using the old ABI: one valid sequence, and one with a stale call
+	// among valid ones.
+	createSerializedSequence := func(methodNames []string, args [][]any, delays [][2]uint64) []byte {
+		sequence := make(calls.CallSequence, 0, len(methodNames))
+		for i, methodName := range methodNames {
+			method := oldAbi.Methods[methodName]
+			msg := calls.NewCallMessageWithAbiValueData(sender, &contractAddress, uint64(i+1), big.NewInt(0), 100_000, big.NewInt(1), big.NewInt(0), big.NewInt(0), &calls.CallMessageDataAbiValues{
+				Method:      &method,
+				InputValues: args[i],
+			})
+			sequence = append(sequence, calls.NewCallSequenceElement(nil, msg, delays[i][0], delays[i][1]))
+		}
+		b, err := json.Marshal(sequence)
+		assert.NoError(t, err)
+		return b
+	}
+	validSequenceData := createSerializedSequence(
+		[]string{"setValue", "increment"},
+		[][]any{{big.NewInt(1)}, {}},
+		[][2]uint64{{1, 1}, {1, 1}},
+	)
+	staleSequenceData := createSerializedSequence(
+		[]string{"setValue", "removed", "increment", "setValue"},
+		[][]any{{big.NewInt(1)}, {big.NewInt(2)}, {}, {big.NewInt(3)}},
+		[][2]uint64{{1, 1}, {3, 10}, {1, 1}, {0, 0}},
+	)
+
+	// Create a corpus with both sequences deserialized from disk and initialize it.
+	initializeCorpus := func(dropOutdatedCalls bool) *Corpus {
+		corpus, err := NewCorpus("")
+		assert.NoError(t, err)
+		for fileName, sequenceData := range map[string][]byte{"valid.json": validSequenceData, "stale.json": staleSequenceData} {
+			var sequence calls.CallSequence
+			assert.NoError(t, json.Unmarshal(sequenceData, &sequence))
+			assert.NoError(t, corpus.callSequenceFiles.addFile(fileName, sequence))
+		}
+		active, total, err := corpus.Initialize(testChain, contractDefinitions, contracts.BytecodeMatchingModeStrict, dropOutdatedCalls)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 2, total)
+		assert.EqualValues(t, active, len(corpus.unexecutedCallSequences))
+		return corpus
+	}
+
+	// By default, the sequence with the stale call should be disabled entirely.
+	corpus := initializeCorpus(false)
+	assert.Len(t, corpus.unexecutedCallSequences, 1)
+	assert.Len(t, corpus.unexecutedCallSequences[0].sequence, 2)
+
+	// If we drop outdated calls, both sequences should be active, with the stale call dropped from its sequence.
+	corpus = initializeCorpus(true)
+	assert.Len(t, corpus.unexecutedCallSequences, 2)
+	var staleSequence calls.CallSequence
+	for _, unexecuted := range corpus.unexecutedCallSequences {
+		if len(unexecuted.sequence) != 2 {
+			staleSequence = unexecuted.sequence
+		}
+	}
+	assert.Len(t, staleSequence, 3)
+	assert.EqualValues(t, []string{"setValue", "increment", "setValue"}, []string{
+		staleSequence[0].Call.DataAbiValues.Method.Name,
+		staleSequence[1].Call.DataAbiValues.Method.Name,
+		staleSequence[2].Call.DataAbiValues.Method.Name,
+	})
+
+	// The dropped call's delays should be carried over to the next call, and nonces should be renumbered, so that
+	// the remaining calls execute successfully in the blocks they would have otherwise.
+	assert.EqualValues(t, 4, staleSequence[1].BlockNumberDelay)
+	assert.EqualValues(t, 11, staleSequence[1].BlockTimestampDelay)
+	for i, element := range staleSequence {
+		assert.EqualValues(t, i+1, element.Call.Nonce)
+		assert.NotNil(t, element.ChainReference)
+		assert.EqualValues(t, types.ReceiptStatusSuccessful, element.ChainReference.MessageResults().Receipt.Status)
+	}
+	assert.EqualValues(t, staleSequence[0].ExecutedBlock.BlockNumber+4, staleSequence[1].ExecutedBlock.BlockNumber)
+	assert.EqualValues(t, staleSequence[1].ExecutedBlock.BlockNumber, staleSequence[2].ExecutedBlock.BlockNumber)
+}
+
+// TestCorpusElementMetadata ensures that metadata describing each call of a call sequence is recorded alongside the
+// call sequence if enabled, that it round trips through ReadCallSequenceRecords, and that it does not affect the call
+// sequence which is stored and replayed.
+func TestCorpusElementMetadata(t *testing.T) {
+	// Create a test chain with a funded sender.
+	testChain, sender := testchainutils.NewFundedTestChain(t)
+
+	// Deploy a contract whose runtime bytecode simply stops, and one whose runtime bytecode always reverts.
+	contractAbi, err := abi.JSON(strings.NewReader(`[
+		{"type":"function","name":"setValue","inputs":[{"name":"x","type":"uint256"}],"outputs":[],"stateMutability":"payable"}
+	]`))
+	assert.NoError(t, err)
+	contractDefinitions := make(contracts.Contracts, 0)
+	contractAddresses := make([]common.Address, 0)
+	for i, runtimeBytecode := range []string{"00", "60006000fd"} {
+		initBytecode := common.Hex2Bytes(fmt.Sprintf("60%02x600c60003960%02x6000f3", len(runtimeBytecode)/2, len(runtimeBytecode)/2) + runtimeBytecode)
+		deployMsg := calls.NewCallMessage(sender, nil, uint64(i), big.NewInt(0), 1_000_000, big.NewInt(1), big.NewInt(0), big.NewInt(0), initBytecode)
+		_, err = testChain.PendingBlockCreate()
+		assert.NoError(t, err)
+		assert.NoError(t, testChain.PendingBlockAddTx(deployMsg.ToCoreMessage()))
+		assert.NoError(t, testChain.PendingBlockCommit())
+		contractDefinitions = append(contractDefinitions, contracts.NewContract(fmt.Sprintf("Contract%d", i), "", &compilationTypes.CompiledContract{
+			Abi:             contractAbi,
+			InitBytecode:    initBytecode,
+			RuntimeBytecode: common.Hex2Bytes(runtimeBytecode),
+		}, nil))
+		contractAddresses = append(contractAddresses, crypto.CreateAddress(sender, uint64(i)))
+	}
+
+	// Create and execute a call sequence which calls the successful contract with value, then the reverting one.
+	method := contractAbi.Methods["setValue"]
+	sequence := make(calls.CallSequence, 0)
+	for i, value := range []int64{7, 0} {
+		msg := calls.NewCallMessageWithAbiValueData(sender, &contractAddresses[i], uint64(i+2), big.NewInt(value), 100_000, big.NewInt(1), big.NewInt(0), big.NewInt(0), &calls.CallMessageDataAbiValues{
+			Method:      &method,
+			InputValues: []any{big.NewInt(int64(i))},
+		})
+		sequence = append(sequence, calls.NewCallSequenceElement(contractDefinitions[i], msg, 1, 1))
+	}
+	baseBlockIndex := uint64(len(testChain.CommittedBlocks()))
+	_, err = calls.ExecuteCallSequence(testChain, sequence)
+	assert.NoError(t, err)
+	assert.NoError(t, testChain.RevertToBlockIndex(baseBlockIndex))
+
+	testutils.ExecuteInDirectory(t, t.TempDir(), func() {
+		// Record the call sequence in a corpus with element metadata enabled, and in one without.
+		for _, recordElementMetadata := range []bool{true, false} {
+			corpus, err := NewCorpus(fmt.Sprintf("corpus-%v", recordElementMetadata))
+			assert.NoError(t, err)
+			corpus.SetRecordElementMetadata(recordElementMetadata)
+			_, err = corpus.NewStagingBuffer().CheckSequenceCoverageAndUpdate(sequence, big.NewInt(1))
+			assert.NoError(t, err)
+			assert.NoError(t, corpus.AddTestResultCallSequence(sequence, big.NewInt(1), true))
+		}
+
+		// The metadata should round trip, describing each call.
+		records, err := ReadCallSequenceRecords("corpus-true")
+		assert.NoError(t, err)
+		assert.Len(t, records, 1)
+		assert.True(t, records[0].TestResult)
+		if assert.NotNil(t, records[0].Metadata) && assert.Len(t, records[0].Metadata.Elements, 2) {
+			elements := records[0].Metadata.Elements
+			assert.Equal(t, "Contract0", elements[0].Contract)
+			assert.Equal(t, "setValue(uint256)", elements[0].Method)
+			assert.True(t, elements[0].Executed)
+			assert.False(t, elements[0].Reverted)
+			assert.Positive(t, elements[0].GasUsed)
+			assert.EqualValues(t, 7, elements[0].Value.Int64())
+			assert.Equal(t, "Contract1", elements[1].Contract)
+			assert.True(t, elements[1].Executed)
+			assert.True(t, elements[1].Reverted)
+			assert.EqualValues(t, 0, elements[1].Value.Int64())
+		}
+
+		// Without element metadata, no metadata should be recorded for the test result.
+		leanRecords, err := ReadCallSequenceRecords("corpus-false")
+		assert.NoError(t, err)
+		assert.Len(t, leanRecords, 1)
+		assert.Nil(t, leanRecords[0].Metadata)
+
+		// The stored call sequence should be identical regardless of whether element metadata was recorded.
+		recordedSequence, err := os.ReadFile(filepath.Join("corpus-true", "test_results", records[0].FileName))
+		assert.NoError(t, err)
+		leanSequence, err := os.ReadFile(filepath.Join("corpus-false", "test_results", leanRecords[0].FileName))
+		assert.NoError(t, err)
+		assert.Equal(t, string(leanSequence), string(recordedSequence))
+
+		// Replaying the corpus should ignore the metadata, executing the call sequence as it was recorded.
+		corpus, err := NewCorpus("corpus-true")
+		assert.NoError(t, err)
+		_, total, err := corpus.Initialize(testChain, contractDefinitions, contracts.BytecodeMatchingModeStrict, false)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, total)
+		replayedSequence := corpus.UnexecutedCallSequence(0)
+		if assert.NotNil(t, replayedSequence) {
+			replayedJson, err := json.MarshalIndent(*replayedSequence, "", " ")
+			assert.NoError(t, err)
+			assert.Equal(t, string(leanSequence), string(replayedJson))
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package corpus
+
+import (
+	"os"
+	"time"
+)
+
+// maxPendingCorpusWrites describes the count of call sequences pending a write to disk at which the background writer
+// is woken to write them early, rather than waiting for its flush interval. This bounds the amount of corpus changes
+// which may be lost if the process is killed during a burst of discoveries.
+const maxPendingCorpusWrites = 256
+
+// writeCorpusFile writes the data of a corpus file to disk. It is a variable so that tests can simulate slow or
+// failing filesystems.
+var writeCorpusFile = os.WriteFile
+
+// backgroundWriter describes a goroutine which writes corpus changes to disk on behalf of a Corpus.
+type backgroundWriter struct {
+	// wake is signalled to have the writer write pending corpus changes before its flush interval elapses.
+	wake chan struct{}
+
+	// stop is closed to signal the writer to stop.
+	stop chan struct{}
+
+	// done is closed by the writer once it has stopped.
+	done chan struct{}
+}
+
+// StartBackgroundWrites starts a goroutine which writes corpus changes to disk every flushInterval, and whenever many
+// call sequences are pending a write. Until StopBackgroundWrites is called, call sequences added to the corpus which
+// were requested to be flushed to disk are written by it, rather than by the caller, so workers are not stalled by
+// slow filesystems. Call sequences are still added to the corpus in memory immediately. Writes which fail are logged,
+// and retried on the next flush. This does nothing if the corpus is not written to disk.
+func (c *Corpus) StartBackgroundWrites(flushInterval time.Duration) {
+	if c.storageDirectory == "" || c.readOnly || c.backgroundWriter != nil {
+		return
+	}
+	writer := &backgroundWriter{
+		wake: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	c.backgroundWriter = writer
+	go c.runBackgroundWriter(writer, flushInterval)
+}
+
+// runBackgroundWriter writes corpus changes to disk every flushInterval, or when woken, until the provided writer is
+// stopped.
+func (c *Corpus) runBackgroundWriter(writer *backgroundWriter, flushInterval time.Duration) {
+	defer close(writer.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-writer.stop:
+			return
+		case <-ticker.C:
+		case <-writer.wake:
+		}
+		if err := c.Flush(); err != nil {
+			c.logger.Error("Failed to write the corpus to disk, the write will be retried", err)
+		}
+	}
+}
+
+// StopBackgroundWrites stops the goroutine started by StartBackgroundWrites, then writes any corpus changes which are
+// still pending to disk. This does nothing if background writes were not started.
+// Returns an error if the remaining changes could not be written.
+func (c *Corpus) StopBackgroundWrites() error {
+	writer := c.backgroundWriter
+	if writer == nil {
+		return nil
+	}
+	close(writer.stop)
+	<-writer.done
+	c.backgroundWriter = nil
+	return c.Flush()
+}
+
+// requestFlush writes corpus changes to disk. If background writes were started, the changes are instead left to the
+// background writer, which is woken early if many call sequences are pending a write.
+// Returns an error if one occurs.
+func (c *Corpus) requestFlush() error {
+	writer := c.backgroundWriter
+	if writer == nil {
+		return c.Flush()
+	}
+	if c.pendingWrites.Load() >= maxPendingCorpusWrites {
+		select {
+		case writer.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package corpus
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/utils/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+// setCorpusFileWriter replaces the function used to write corpus files to disk for the duration of the test.
+func setCorpusFileWriter(t *testing.T, writer func(name string, data []byte, perm os.FileMode) error) {
+	originalWriter := writeCorpusFile
+	writeCorpusFile = writer
+	t.Cleanup(func() {
+		writeCorpusFile = originalWriter
+	})
+}
+
+// countCorpusFiles returns the count of files written to the provided corpus directory.
+func countCorpusFiles(t *testing.T, directory *corpusDirectory[calls.CallSequence]) int {
+	matches, err := filepath.Glob(filepath.Join(directory.path, "*.json"))
+	assert.NoError(t, err)
+	return len(matches)
+}
+
+// TestCorpusBackgroundWrites simulates a slow filesystem, and tests that adding call sequences to a corpus written in
+// the background is not slowed by it, while every call sequence is still written to disk.
+func TestCorpusBackgroundWrites(t *testing.T) {
+	const writeDelay = 50 * time.Millisecond
+	const sequenceCount = 20
+	setCorpusFileWriter(t, func(name string, data []byte, perm os.FileMode) error {
+		time.Sleep(writeDelay)
+		return os.WriteFile(name, data, perm)
+	})
+
+	testutils.ExecuteInDirectory(t, t.TempDir(), func() {
+		corpus, err := NewCorpus("corpus")
+		assert.NoError(t, err)
+		corpus.StartBackgroundWrites(10 * time.Millisecond)
+
+		// Add our call sequences, requesting each is flushed. As they are written in the background, adding them
+		// should take far less time than writing them would.
+		start := time.Now()
+		for i := 0; i < sequenceCount; i++ {
+			assert.NoError(t, corpus.AddTestResultCallSequence(getMockCallSequence(2), nil, true))
+		}
+		assert.Less(t, time.Since(start), sequenceCount*writeDelay/2)
+
+		// The call sequences should be in the corpus immediately, and be written to disk in the background.
+		_, testResultCount := corpus.CallSequenceEntryCount()
+		assert.EqualValues(t, sequenceCount, testResultCount)
+		assert.Eventually(t, func() bool {
+			return countCorpusFiles(t, corpus.testResultSequenceFiles) == sequenceCount
+		}, 10*time.Second, 10*time.Millisecond)
+
+		// Call sequences added after the background writer is stopped should be written by it when stopping.
+		assert.NoError(t, corpus.AddTestResultCallSequence(getMockCallSequence(2), nil, true))
+		assert.NoError(t, corpus.StopBackgroundWrites())
+		assert.EqualValues(t, sequenceCount+1, countCorpusFiles(t, corpus.testResultSequenceFiles))
+	})
+}
+
+// TestCorpusBackgroundWriteRetries simulates a filesystem which fails the first writes to it, and tests that the
+// background writer retries writing the corpus until every call sequence is written.
+func TestCorpusBackgroundWriteRetries(t *testing.T) {
+	var failedWrites atomic.Int32
+	setCorpusFileWriter(t, func(name string, data []byte, perm os.FileMode) error {
+		if failedWrites.Load() < 3 {
+			failedWrites.Add(1)
+			return errors.New("simulated write failure")
+		}
+		return os.WriteFile(name, data, perm)
+	})
+
+	testutils.ExecuteInDirectory(t, t.TempDir(), func() {
+		corpus, err := NewCorpus("corpus")
+		assert.NoError(t, err)
+		corpus.StartBackgroundWrites(10 * time.Millisecond)
+		for i := 0; i < 5; i++ {
+			assert.NoError(t, corpus.AddTestResultCallSequence(getMockCallSequence(2), nil, true))
+		}
+
+		// Every call sequence should eventually be written, despite the failed writes.
+		assert.Eventually(t, func() bool {
+			return countCorpusFiles(t, corpus.testResultSequenceFiles) == 5
+		}, 10*time.Second, 10*time.Millisecond)
+		assert.EqualValues(t, 3, failedWrites.Load())
+		assert.NoError(t, corpus.StopBackgroundWrites())
+	})
+}
@@ -0,0 +1,97 @@
+package fuzzing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/crytic/medusa/fuzzing/corpus"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// campaignFingerprint creates a corpus.CampaignFingerprint describing the current campaign configuration and
+// compilation artifacts.
+// Returns the fingerprint, or an error if one occurs.
+func (f *Fuzzer) campaignFingerprint() (*corpus.CampaignFingerprint, error) {
+	// Hash our constructor arguments. Maps are encoded with sorted keys, so the hash is deterministic.
+	constructorArgsData, err := json.Marshal(f.config.Fuzzing.ConstructorArgs)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode constructor arguments: %v", err)
+	}
+
+	fingerprint := &corpus.CampaignFingerprint{
+		DeploymentOrder:     slices.Clone(f.config.Fuzzing.TargetContracts),
+		ConstructorArgsHash: crypto.Keccak256Hash(constructorArgsData).Hex(),
+		SenderAddresses:     make([]string, 0, len(f.senders)),
+		DeployerAddress:     f.deployer.Hex(),
+		HardFork:            string(f.config.Fuzzing.TestChainConfig.HardFork),
+		ForkModeEnabled:     f.config.Fuzzing.TestChainConfig.ForkConfig.ForkModeEnabled,
+		BlockGasLimit:       f.config.Fuzzing.BlockGasLimit,
+		TransactionGasLimit: f.config.Fuzzing.TransactionGasLimit,
+		ContractHashes:      make(map[string]string, len(f.contractDefinitions)),
+	}
+	if fingerprint.DeploymentOrder == nil {
+		fingerprint.DeploymentOrder = make([]string, 0)
+	}
+	if fingerprint.ForkModeEnabled {
+		fingerprint.ForkRpcUrl = f.config.Fuzzing.TestChainConfig.ForkConfig.RpcUrl
+		fingerprint.ForkRpcBlock = f.config.Fuzzing.TestChainConfig.ForkConfig.RpcBlock
+	}
+	for _, sender := range f.senders {
+		fingerprint.SenderAddresses = append(fingerprint.SenderAddresses, sender.Hex())
+	}
+	for _, contract := range f.contractDefinitions {
+		compiledContract := contract.CompiledContract()
+		fingerprint.ContractHashes[contract.Name()] = crypto.Keccak256Hash(compiledContract.InitBytecode, compiledContract.RuntimeBytecode).Hex()
+	}
+	return fingerprint, nil
+}
+
+// checkCorpusFingerprint compares the fingerprint the corpus was recorded with to the current campaign fingerprint,
+// reporting any differences and handling them as configured, then records the current fingerprint in the corpus.
+// Returns an error if one occurs, or if the corpus was recorded with a different configuration and the fuzzer was
+// configured to refuse to start in that case.
+func (f *Fuzzer) checkCorpusFingerprint() error {
+	fingerprint, err := f.campaignFingerprint()
+	if err != nil {
+		return err
+	}
+	previousFingerprint, err := f.corpus.ReadFingerprint()
+	if err != nil {
+		return err
+	}
+
+	// Determine whether the corpus was recorded with a different configuration. If the corpus has call sequences but
+	// no fingerprint, we cannot tell.
+	mode := f.config.Fuzzing.CorpusFingerprintMismatch
+	if previousFingerprint == nil {
+		if callSequences, testResults := f.corpus.CallSequenceEntryCount(); callSequences > 0 || testResults > 0 {
+			f.logger.Warn("Corpus has no fingerprint, so it cannot be verified that it was recorded with the current configuration")
+			f.corpus.SetRemoveInvalidSequences(mode == "revalidate")
+		}
+	} else if differences := fingerprint.Diff(previousFingerprint); len(differences) > 0 {
+		// Report each difference, then handle them as configured.
+		buffer := logging.NewLogBuffer()
+		buffer.Append(colors.Bold, "Corpus was recorded with a different campaign configuration:", colors.Reset)
+		for _, difference := range differences {
+			buffer.Append("\n - ", difference)
+		}
+		switch mode {
+		case "refuse":
+			f.logger.Warn(buffer.Elements()...)
+			return errors.New("corpus was recorded with a different campaign configuration, refusing to start")
+		case "revalidate":
+			buffer.Append("\nCorpus call sequences which fail to replay will be removed.")
+			f.corpus.SetRemoveInvalidSequences(true)
+		default:
+			buffer.Append("\nCorpus call sequences which fail to replay will be disabled.")
+		}
+		f.logger.Warn(buffer.Elements()...)
+	}
+
+	// Record the current fingerprint, as the corpus is recorded with it from now on.
+	return f.corpus.WriteFingerprint(fingerprint)
+}
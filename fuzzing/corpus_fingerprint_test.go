@@ -0,0 +1,78 @@
+package fuzzing
+
+import (
+	"testing"
+
+	compilationTypes "github.com/crytic/medusa/compilation/types"
+	"github.com/crytic/medusa/fuzzing/config"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/corpus"
+	"github.com/crytic/medusa/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCheckCorpusFingerprint ensures that the campaign fingerprint is recorded with the corpus, and that a corpus
+// recorded with a different campaign configuration is handled as configured.
+func TestCheckCorpusFingerprint(t *testing.T) {
+	projectConfig, err := config.GetDefaultProjectConfig("crytic-compile")
+	assert.NoError(t, err)
+	projectConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+	corpusDirectory := t.TempDir()
+
+	// Create a fuzzer with the provided fingerprint mismatch mode and block gas limit, with a corpus loaded from our
+	// corpus directory.
+	createFuzzer := func(mode string, blockGasLimit uint64) *Fuzzer {
+		fuzzerConfig := *projectConfig
+		fuzzerConfig.Fuzzing.CorpusFingerprintMismatch = mode
+		fuzzerConfig.Fuzzing.BlockGasLimit = blockGasLimit
+		fuzzerCorpus, err := corpus.NewCorpus(corpusDirectory)
+		assert.NoError(t, err)
+		return &Fuzzer{
+			config:   fuzzerConfig,
+			logger:   logging.GlobalLogger.NewSubLogger("module", "fuzzer"),
+			senders:  []common.Address{common.HexToAddress("0x10000")},
+			deployer: common.HexToAddress("0x30000"),
+			contractDefinitions: fuzzerTypes.Contracts{
+				fuzzerTypes.NewContract("TestContract", "", &compilationTypes.CompiledContract{
+					InitBytecode:    common.Hex2Bytes("6001600c60003960016000f300"),
+					RuntimeBytecode: common.Hex2Bytes("00"),
+				}, nil),
+			},
+			corpus: fuzzerCorpus,
+		}
+	}
+
+	// readFingerprint reads the fingerprint currently recorded with the corpus.
+	readFingerprint := func() *corpus.CampaignFingerprint {
+		fuzzerCorpus, err := corpus.NewCorpus(corpusDirectory)
+		assert.NoError(t, err)
+		fingerprint, err := fuzzerCorpus.ReadFingerprint()
+		assert.NoError(t, err)
+		return fingerprint
+	}
+
+	// A missing fingerprint should be recorded.
+	fuzzer := createFuzzer("refuse", 30_000_000)
+	assert.NoError(t, fuzzer.checkCorpusFingerprint())
+	expected, err := fuzzer.campaignFingerprint()
+	assert.NoError(t, err)
+	assert.EqualValues(t, expected, readFingerprint())
+
+	// A matching fingerprint should be accepted in any mode.
+	assert.NoError(t, createFuzzer("refuse", 30_000_000).checkCorpusFingerprint())
+	assert.EqualValues(t, expected, readFingerprint())
+
+	// A mismatching fingerprint should be refused if configured, leaving the recorded fingerprint unchanged.
+	assert.Error(t, createFuzzer("refuse", 60_000_000).checkCorpusFingerprint())
+	assert.EqualValues(t, expected, readFingerprint())
+
+	// Otherwise, it should be accepted and the new fingerprint recorded.
+	for _, mode := range []string{"warn", "revalidate"} {
+		fuzzer = createFuzzer(mode, expected.BlockGasLimit+1)
+		assert.NoError(t, fuzzer.checkCorpusFingerprint())
+		expected, err = fuzzer.campaignFingerprint()
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, readFingerprint())
+	}
+}
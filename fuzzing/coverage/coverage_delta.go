@@ -0,0 +1,272 @@
+package coverage
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/crytic/medusa/compilation/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CoverageDelta describes the coverage markers which were newly achieved when merging some coverage maps into another,
+// grouped by the lookup hash of the bytecode they were achieved in. As coverage is tracked per deployed address, markers
+// achieved by a newly deployed contract are considered new, even if another deployment of the same bytecode covered
+// them before.
+type CoverageDelta struct {
+	// Contracts describes the new coverage markers for each contract bytecode which achieved new coverage, sorted by
+	// lookup hash.
+	Contracts []*ContractCoverageDelta `json:"contracts"`
+}
+
+// ContractCoverageDelta describes the coverage markers which were newly achieved for a given contract's init or
+// runtime bytecode.
+type ContractCoverageDelta struct {
+	// LookupHash describes the hash used to look up the ContractCoverageMap for the bytecode which achieved new
+	// coverage. See GetContractCoverageMapHash for more information.
+	LookupHash common.Hash `json:"lookupHash"`
+
+	// ContractName describes the name of the contract the lookup hash was resolved to. This is empty if the lookup
+	// hash could not be resolved to a known contract definition.
+	ContractName string `json:"contractName,omitempty"`
+
+	// Init indicates whether the lookup hash was resolved to the init bytecode of the contract, rather than its
+	// runtime bytecode. This is only meaningful if the ContractName is not empty.
+	Init bool `json:"init,omitempty"`
+
+	// SuccessfulPCs describes the program counters which were newly covered without reverting, sorted in ascending
+	// order.
+	SuccessfulPCs []int `json:"successfulPCs"`
+
+	// RevertedPCs describes the program counters which were newly covered before reverting, sorted in ascending
+	// order.
+	RevertedPCs []int `json:"revertedPCs"`
+
+	// OutOfGasPCs describes the program counters which were newly covered in a call frame which ran out of gas, sorted
+	// in ascending order.
+	OutOfGasPCs []int `json:"outOfGasPCs"`
+}
+
+// MarkerCount returns the amount of new coverage markers recorded for this contract bytecode.
+func (d *ContractCoverageDelta) MarkerCount() int {
+	return len(d.SuccessfulPCs) + len(d.RevertedPCs) + len(d.OutOfGasPCs)
+}
+
+// MarkerCount returns the amount of new coverage markers recorded across all contract bytecode in the CoverageDelta.
+func (d *CoverageDelta) MarkerCount() int {
+	if d == nil {
+		return 0
+	}
+	count := 0
+	for _, contractDelta := range d.Contracts {
+		count += contractDelta.MarkerCount()
+	}
+	return count
+}
+
+// Empty indicates whether the CoverageDelta records no new coverage markers.
+func (d *CoverageDelta) Empty() bool {
+	return d.MarkerCount() == 0
+}
+
+// ResolveContractNames sets the ContractName and Init fields for each ContractCoverageDelta whose lookup hash can be
+// resolved using the provided resolver. The resolver returns the contract name, whether the lookup hash refers to
+// init bytecode, and a boolean indicating whether the lookup hash was resolved.
+func (d *CoverageDelta) ResolveContractNames(resolver func(lookupHash common.Hash) (string, bool, bool)) {
+	if d == nil {
+		return
+	}
+	for _, contractDelta := range d.Contracts {
+		if name, init, ok := resolver(contractDelta.LookupHash); ok {
+			contractDelta.ContractName = name
+			contractDelta.Init = init
+		}
+	}
+}
+
+// String returns a human-readable summary of the CoverageDelta, listing the amount of new coverage markers for each
+// contract bytecode.
+func (d *CoverageDelta) String() string {
+	if d == nil {
+		return ""
+	}
+	summaries := make([]string, 0, len(d.Contracts))
+	for _, contractDelta := range d.Contracts {
+		name := contractDelta.LookupHash.String()
+		if contractDelta.ContractName != "" {
+			name = contractDelta.ContractName
+			if contractDelta.Init {
+				name += " (init)"
+			}
+		}
+		summaries = append(summaries, fmt.Sprintf("%v: %d new markers", name, contractDelta.MarkerCount()))
+	}
+	return strings.Join(summaries, ", ")
+}
+
+// CoverageMaps returns a CoverageMaps object which contains only the coverage markers recorded in the CoverageDelta.
+// This allows the delta to be used with existing coverage analysis, such as AnalyzeSourceCoverage.
+func (d *CoverageDelta) CoverageMaps() *CoverageMaps {
+	coverageMaps := NewCoverageMaps()
+	if d == nil {
+		return coverageMaps
+	}
+	for _, contractDelta := range d.Contracts {
+		coverageMaps.maps[contractDelta.LookupHash] = map[common.Address]*ContractCoverageMap{
+			{}: {
+				successfulCoverage: newCoverageMapBytecodeDataFromPCs(contractDelta.SuccessfulPCs),
+				revertedCoverage:   newCoverageMapBytecodeDataFromPCs(contractDelta.RevertedPCs),
+				outOfGasCoverage:   newCoverageMapBytecodeDataFromPCs(contractDelta.OutOfGasPCs),
+			},
+		}
+	}
+	return coverageMaps
+}
+
+// newCoverageMapBytecodeDataFromPCs creates a CoverageMapBytecodeData with a single hit recorded for each of the
+// provided program counters.
+func newCoverageMapBytecodeDataFromPCs(pcs []int) *CoverageMapBytecodeData {
+	data := &CoverageMapBytecodeData{}
+	if len(pcs) == 0 {
+		return data
+	}
+	data.executedFlags = make([]uint, slices.Max(pcs)+1)
+	for _, pc := range pcs {
+		data.executedFlags[pc] = 1
+	}
+	return data
+}
+
+// SourceLines resolves the coverage markers in the CoverageDelta to the source lines they correspond to, using the
+// source maps of the provided compilations. Markers which cannot be resolved to a source line are omitted.
+// Returns a SourceAnalysis whose covered lines are those newly covered in the delta, or an error if one occurs.
+func (d *CoverageDelta) SourceLines(compilations []types.Compilation) (*SourceAnalysis, error) {
+	return AnalyzeSourceCoverage(compilations, d.CoverageMaps())
+}
+
+// FormatSourceLines resolves the coverage markers in the CoverageDelta to the source lines they correspond to, using
+// the source maps of the provided compilations, and formats them as one "path:line: contents" entry per line.
+// Lines which only achieved new coverage before reverting are suffixed with "(reverted)", and lines which only achieved
+// new coverage in call frames which ran out of gas are suffixed with "(out of gas)".
+// Returns the formatted source lines, or an error if one occurs.
+func (d *CoverageDelta) FormatSourceLines(compilations []types.Compilation) (string, error) {
+	sourceAnalysis, err := d.SourceLines(compilations)
+	if err != nil {
+		return "", err
+	}
+
+	var buffer bytes.Buffer
+	for _, file := range sourceAnalysis.SortedFiles() {
+		for i, line := range file.Lines {
+			if !line.IsCovered && !line.IsCoveredReverted && !line.IsCoveredOutOfGas {
+				continue
+			}
+			buffer.WriteString(fmt.Sprintf("%v:%d: %v", file.Path, i+1, strings.TrimSpace(string(line.Contents))))
+			if !line.IsCovered {
+				if line.IsCoveredReverted {
+					buffer.WriteString(" (reverted)")
+				} else {
+					buffer.WriteString(" (out of gas)")
+				}
+			}
+			buffer.WriteString("\n")
+		}
+	}
+	return buffer.String(), nil
+}
+
+// newCoveragePCs determines the program counters which would be newly covered if the provided
+// CoverageMapBytecodeData was merged into the current one. This mirrors the merging semantics of
+// CoverageMapBytecodeData.update without modifying either map.
+// Returns the newly covered program counters in ascending order.
+func (cm *CoverageMapBytecodeData) newCoveragePCs(coverageMap *CoverageMapBytecodeData) []int {
+	// If the coverage map execution data provided is nil, there is nothing new.
+	if coverageMap == nil || coverageMap.executedFlags == nil {
+		return nil
+	}
+
+	// Collect every program counter which was covered in the provided map but not in the current one.
+	pcs := make([]int, 0)
+	for i, hits := range coverageMap.executedFlags {
+		if hits == 0 {
+			continue
+		}
+		if cm == nil || cm.executedFlags == nil {
+			pcs = append(pcs, i)
+		} else if i < len(cm.executedFlags) && cm.executedFlags[i] == 0 {
+			pcs = append(pcs, i)
+		}
+	}
+	return pcs
+}
+
+// delta computes the CoverageDelta which would result from merging the provided coverage maps into the current ones,
+// without modifying either. The caller is expected to hold the update lock.
+// Returns the CoverageDelta, which is empty if no new coverage would be achieved.
+func (cm *CoverageMaps) delta(coverageMaps *CoverageMaps) *CoverageDelta {
+	// Collect the new program counters for each lookup hash, deduplicating them across addresses.
+	successfulPCsByHash := make(map[common.Hash]map[int]struct{})
+	revertedPCsByHash := make(map[common.Hash]map[int]struct{})
+	outOfGasPCsByHash := make(map[common.Hash]map[int]struct{})
+	addPCs := func(pcsByHash map[common.Hash]map[int]struct{}, codeHash common.Hash, pcs []int) {
+		if len(pcs) == 0 {
+			return
+		}
+		if _, ok := pcsByHash[codeHash]; !ok {
+			pcsByHash[codeHash] = make(map[int]struct{})
+		}
+		for _, pc := range pcs {
+			pcsByHash[codeHash][pc] = struct{}{}
+		}
+	}
+	for codeHash, mapsByAddressToMerge := range coverageMaps.maps {
+		for codeAddress, coverageMapToMerge := range mapsByAddressToMerge {
+			var existingSuccessfulCoverage, existingRevertedCoverage, existingOutOfGasCoverage *CoverageMapBytecodeData
+			if existingCoverageMap, ok := cm.maps[codeHash][codeAddress]; ok {
+				existingSuccessfulCoverage = existingCoverageMap.successfulCoverage
+				existingRevertedCoverage = existingCoverageMap.revertedCoverage
+				existingOutOfGasCoverage = existingCoverageMap.outOfGasCoverage
+			}
+			addPCs(successfulPCsByHash, codeHash, existingSuccessfulCoverage.newCoveragePCs(coverageMapToMerge.successfulCoverage))
+			addPCs(revertedPCsByHash, codeHash, existingRevertedCoverage.newCoveragePCs(coverageMapToMerge.revertedCoverage))
+			addPCs(outOfGasPCsByHash, codeHash, existingOutOfGasCoverage.newCoveragePCs(coverageMapToMerge.outOfGasCoverage))
+		}
+	}
+
+	// Create our delta, sorting contracts by lookup hash and program counters in ascending order.
+	sortedPCs := func(pcs map[int]struct{}) []int {
+		result := make([]int, 0, len(pcs))
+		for pc := range pcs {
+			result = append(result, pc)
+		}
+		slices.Sort(result)
+		return result
+	}
+	delta := &CoverageDelta{
+		Contracts: make([]*ContractCoverageDelta, 0),
+	}
+	codeHashes := make(map[common.Hash]struct{})
+	for codeHash := range successfulPCsByHash {
+		codeHashes[codeHash] = struct{}{}
+	}
+	for codeHash := range revertedPCsByHash {
+		codeHashes[codeHash] = struct{}{}
+	}
+	for codeHash := range outOfGasPCsByHash {
+		codeHashes[codeHash] = struct{}{}
+	}
+	for codeHash := range codeHashes {
+		delta.Contracts = append(delta.Contracts, &ContractCoverageDelta{
+			LookupHash:    codeHash,
+			SuccessfulPCs: sortedPCs(successfulPCsByHash[codeHash]),
+			RevertedPCs:   sortedPCs(revertedPCsByHash[codeHash]),
+			OutOfGasPCs:   sortedPCs(outOfGasPCsByHash[codeHash]),
+		})
+	}
+	slices.SortFunc(delta.Contracts, func(a, b *ContractCoverageDelta) int {
+		return bytes.Compare(a.LookupHash[:], b.LookupHash[:])
+	})
+	return delta
+}
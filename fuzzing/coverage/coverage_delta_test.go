@@ -0,0 +1,113 @@
+package coverage
+
+import (
+	"testing"
+
+	"github.com/crytic/medusa/chain/testchainutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCoverageMapsUpdateWithDelta tests that the CoverageDelta computed when updating coverage maps is non-empty
+// exactly when the update achieved new coverage, and that it describes the markers which were newly covered.
+func TestCoverageMapsUpdateWithDelta(t *testing.T) {
+	testChain, sender, factoryAddress := newFactoryTestChain(t, NewCoverageTracer(true))
+
+	// Collect the coverage for our factory deployment.
+	totalCoverage := NewCoverageMaps()
+	for _, block := range testChain.CommittedBlocks() {
+		for _, messageResults := range block.MessageResults {
+			if results := GetCoverageTracerResults(messageResults); results != nil {
+				successChanged, revertedChanged, delta, err := totalCoverage.UpdateWithDelta(results)
+				assert.NoError(t, err)
+				assert.EqualValues(t, successChanged || revertedChanged, !delta.Empty())
+				assert.False(t, delta.Empty())
+
+				// Only the factory init bytecode was executed, so the delta should describe it alone.
+				assert.Len(t, delta.Contracts, 1)
+				assert.EqualValues(t, GetContractCoverageMapHash(decodeBytecode(factoryInitBytecode), true), delta.Contracts[0].LookupHash)
+				assert.Empty(t, delta.Contracts[0].RevertedPCs)
+			}
+		}
+	}
+
+	// Call our factory, which should achieve new coverage in the factory runtime and the child init bytecode. Every
+	// child is deployed to a new address, but its markers should only be reported once.
+	baseBlockIndex := uint64(len(testChain.CommittedBlocks()))
+	results := testchainutils.SendMessage(t, testChain, sender, &factoryAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	successChanged, _, delta, err := totalCoverage.UpdateWithDelta(GetCoverageTracerResults(results))
+	assert.NoError(t, err)
+	assert.True(t, successChanged)
+	assert.False(t, delta.Empty())
+	deltaHashes := make(map[common.Hash]*ContractCoverageDelta)
+	for _, contractDelta := range delta.Contracts {
+		deltaHashes[contractDelta.LookupHash] = contractDelta
+	}
+	assert.Len(t, deltaHashes, 2)
+	childInitDelta := deltaHashes[GetContractCoverageMapHash(decodeBytecode(childInitBytecode), true)]
+	assert.NotNil(t, childInitDelta)
+	assert.EqualValues(t, []int{0, 2, 4, 6, 7, 9, 11}, childInitDelta.SuccessfulPCs)
+	factoryRuntimeDelta := deltaHashes[GetContractCoverageMapHash(decodeBytecode(factoryRuntimeBytecode), false)]
+	assert.NotNil(t, factoryRuntimeDelta)
+
+	// Converting the delta to coverage maps should yield exactly the markers it describes.
+	assert.EqualValues(t, uint64(childInitDelta.MarkerCount()+factoryRuntimeDelta.MarkerCount()), delta.CoverageMaps().UniquePCs())
+
+	// Resolve contract names for our delta, only resolving the child init bytecode.
+	delta.ResolveContractNames(func(lookupHash common.Hash) (string, bool, bool) {
+		if lookupHash == childInitDelta.LookupHash {
+			return "Child", true, true
+		}
+		return "", false, false
+	})
+	assert.EqualValues(t, "Child", childInitDelta.ContractName)
+	assert.True(t, childInitDelta.Init)
+	assert.Empty(t, factoryRuntimeDelta.ContractName)
+
+	// Repeating the same call from the same chain state (so children are deployed to the same addresses) should
+	// achieve no new coverage, yielding an empty delta.
+	assert.NoError(t, testChain.RevertToBlockIndex(baseBlockIndex))
+	results = testchainutils.SendMessage(t, testChain, sender, &factoryAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	successChanged, revertedChanged, delta, err := totalCoverage.UpdateWithDelta(GetCoverageTracerResults(results))
+	assert.NoError(t, err)
+	assert.EqualValues(t, successChanged || revertedChanged, !delta.Empty())
+	assert.True(t, delta.Empty())
+}
+
+// TestCoverageMapsClone tests that a clone of coverage maps describes the same coverage as the original, and that
+// updating either does not affect the other.
+func TestCoverageMapsClone(t *testing.T) {
+	testChain, sender, factoryAddress := newFactoryTestChain(t, NewCoverageTracer(true))
+
+	// Collect the coverage for our factory deployment, and clone it.
+	original := NewCoverageMaps()
+	for _, block := range testChain.CommittedBlocks() {
+		for _, messageResults := range block.MessageResults {
+			if results := GetCoverageTracerResults(messageResults); results != nil {
+				_, _, err := original.Update(results)
+				assert.NoError(t, err)
+			}
+		}
+	}
+	clone := original.Clone()
+	assert.EqualValues(t, original.UniquePCs(), clone.UniquePCs())
+	deploymentPCs := clone.UniquePCs()
+
+	// Updating the original with new coverage should not affect the clone.
+	results := testchainutils.SendMessage(t, testChain, sender, &factoryAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	successChanged, _, err := original.Update(GetCoverageTracerResults(results))
+	assert.NoError(t, err)
+	assert.True(t, successChanged)
+	assert.Greater(t, original.UniquePCs(), deploymentPCs)
+	assert.EqualValues(t, deploymentPCs, clone.UniquePCs())
+
+	// The clone should still consider the same coverage new, and achieve the same coverage as the original from it.
+	successChanged, _, err = clone.Update(original.Clone())
+	assert.NoError(t, err)
+	assert.True(t, successChanged)
+	assert.EqualValues(t, original.UniquePCs(), clone.UniquePCs())
+}
@@ -1,6 +1,9 @@
 package coverage
 
 import (
+	"bytes"
+	"encoding/binary"
+
 	"golang.org/x/exp/slices"
 
 	"sync"
@@ -50,6 +53,22 @@ func (cm *CoverageMaps) Reset() {
 	cm.cachedMap = nil
 }
 
+// Clone creates a deep copy of the CoverageMaps, which can be updated independently of the original.
+func (cm *CoverageMaps) Clone() *CoverageMaps {
+	cm.updateLock.Lock()
+	defer cm.updateLock.Unlock()
+
+	clone := NewCoverageMaps()
+	for codeHash, mapsByAddress := range cm.maps {
+		clonedMapsByAddress := make(map[common.Address]*ContractCoverageMap, len(mapsByAddress))
+		for codeAddress, coverageMap := range mapsByAddress {
+			clonedMapsByAddress[codeAddress] = coverageMap.clone()
+		}
+		clone.maps[codeHash] = clonedMapsByAddress
+	}
+	return clone
+}
+
 // Equal checks whether two coverage maps are the same. Equality is determined if the keys and values are all the same.
 func (cm *CoverageMaps) Equal(b *CoverageMaps) bool {
 	// Iterate through all maps
@@ -75,12 +94,54 @@ func (cm *CoverageMaps) Equal(b *CoverageMaps) bool {
 	return true
 }
 
-// getContractCoverageMapHash obtain the hash used to look up a given contract's ContractCoverageMap.
+// Hash returns a hash of the coverage data in the CoverageMaps, including the hit count of every program counter for
+// each coverage marker status. It is cheap to compare, so it can be used to determine whether separate executions
+// followed the same path.
+func (cm *CoverageMaps) Hash() common.Hash {
+	cm.updateLock.Lock()
+	defer cm.updateLock.Unlock()
+
+	// Sort our lookup hashes and addresses, so the hash does not depend on map iteration order.
+	codeHashes := make([]common.Hash, 0, len(cm.maps))
+	for codeHash := range cm.maps {
+		codeHashes = append(codeHashes, codeHash)
+	}
+	slices.SortFunc(codeHashes, func(a, b common.Hash) int {
+		return bytes.Compare(a[:], b[:])
+	})
+
+	hasher := crypto.NewKeccakState()
+	for _, codeHash := range codeHashes {
+		codeAddresses := make([]common.Address, 0, len(cm.maps[codeHash]))
+		for codeAddress := range cm.maps[codeHash] {
+			codeAddresses = append(codeAddresses, codeAddress)
+		}
+		slices.SortFunc(codeAddresses, func(a, b common.Address) int {
+			return a.Cmp(b)
+		})
+		for _, codeAddress := range codeAddresses {
+			hasher.Write(codeHash[:])
+			hasher.Write(codeAddress[:])
+			coverageMap := cm.maps[codeHash][codeAddress]
+			for _, coverageData := range []*CoverageMapBytecodeData{coverageMap.successfulCoverage, coverageMap.revertedCoverage, coverageMap.outOfGasCoverage} {
+				hasher.Write(binary.BigEndian.AppendUint64(nil, uint64(len(coverageData.executedFlags))))
+				for _, hits := range coverageData.executedFlags {
+					hasher.Write(binary.BigEndian.AppendUint64(nil, uint64(hits)))
+				}
+			}
+		}
+	}
+	var hash common.Hash
+	_, _ = hasher.Read(hash[:])
+	return hash
+}
+
+// GetContractCoverageMapHash obtain the hash used to look up a given contract's ContractCoverageMap.
 // If this is init bytecode, metadata and abi arguments will attempt to be stripped, then a hash is computed.
 // If this is runtime bytecode, the metadata ipfs/swarm hash will be used if available, otherwise the bytecode
 // is hashed.
 // Returns the resulting lookup hash.
-func getContractCoverageMapHash(bytecode []byte, init bool) common.Hash {
+func GetContractCoverageMapHash(bytecode []byte, init bool) common.Hash {
 	// If available, the metadata code hash should be unique and reliable to use above all (for runtime bytecode).
 	if !init {
 		metadata := compilationTypes.ExtractContractMetadata(bytecode)
@@ -102,7 +163,7 @@ func getContractCoverageMapHash(bytecode []byte, init bool) common.Hash {
 // Returns the total coverage map, or an error if one occurs.
 func (cm *CoverageMaps) GetContractCoverageMap(bytecode []byte, init bool) (*ContractCoverageMap, error) {
 	// Obtain the lookup hash
-	hash := getContractCoverageMapHash(bytecode, init)
+	hash := GetContractCoverageMapHash(bytecode, init)
 
 	// Acquire our thread lock and defer our unlocking for when we exit this method
 	cm.updateLock.Lock()
@@ -124,7 +185,8 @@ func (cm *CoverageMaps) GetContractCoverageMap(bytecode []byte, init bool) (*Con
 }
 
 // Update updates the current coverage maps with the provided ones.
-// Returns two booleans indicating whether successful or reverted coverage changed, or an error if one occurred.
+// Returns two booleans indicating whether successful or reverted (including out-of-gas) coverage changed, or an error
+// if one occurred.
 func (cm *CoverageMaps) Update(coverageMaps *CoverageMaps) (bool, bool, error) {
 	// If our maps provided are nil, do nothing
 	if coverageMaps == nil {
@@ -135,6 +197,32 @@ func (cm *CoverageMaps) Update(coverageMaps *CoverageMaps) (bool, bool, error) {
 	cm.updateLock.Lock()
 	defer cm.updateLock.Unlock()
 
+	return cm.update(coverageMaps)
+}
+
+// UpdateWithDelta updates the current coverage maps with the provided ones, while also computing the CoverageDelta
+// describing which coverage markers were newly achieved by the update.
+// Returns two booleans indicating whether successful or reverted (including out-of-gas) coverage changed, the
+// CoverageDelta, or an error if one occurred.
+func (cm *CoverageMaps) UpdateWithDelta(coverageMaps *CoverageMaps) (bool, bool, *CoverageDelta, error) {
+	// If our maps provided are nil, do nothing
+	if coverageMaps == nil {
+		return false, false, &CoverageDelta{Contracts: make([]*ContractCoverageDelta, 0)}, nil
+	}
+
+	// Acquire our thread lock and defer our unlocking for when we exit this method
+	cm.updateLock.Lock()
+	defer cm.updateLock.Unlock()
+
+	// Compute our delta prior to merging, as merging may take ownership of the provided maps.
+	delta := cm.delta(coverageMaps)
+	successCoverageChanged, revertedCoverageChanged, err := cm.update(coverageMaps)
+	return successCoverageChanged, revertedCoverageChanged, delta, err
+}
+
+// update updates the current coverage maps with the provided ones. The caller is expected to hold the update lock.
+// Returns two booleans indicating whether successful or reverted coverage changed, or an error if one occurred.
+func (cm *CoverageMaps) update(coverageMaps *CoverageMaps) (bool, bool, error) {
 	// Create a boolean indicating whether we achieved new coverage
 	successCoverageChanged := false
 	revertedCoverageChanged := false
@@ -161,7 +249,7 @@ func (cm *CoverageMaps) Update(coverageMaps *CoverageMaps) (bool, bool, error) {
 			} else {
 				mapsByAddress[codeAddress] = coverageMapToMerge
 				successCoverageChanged = coverageMapToMerge.successfulCoverage != nil
-				revertedCoverageChanged = coverageMapToMerge.revertedCoverage != nil
+				revertedCoverageChanged = coverageMapToMerge.revertedCoverage != nil || coverageMapToMerge.outOfGasCoverage != nil
 			}
 		}
 	}
@@ -221,28 +309,47 @@ func (cm *CoverageMaps) UpdateAt(codeAddress common.Address, codeLookupHash comm
 // coverage, the successful coverage is cleared.
 // Returns a boolean indicating whether reverted coverage increased, and an error if one occurred.
 func (cm *CoverageMaps) RevertAll() (bool, error) {
+	return cm.moveSuccessfulCoverage(func(coverageMap *ContractCoverageMap) *CoverageMapBytecodeData {
+		return coverageMap.revertedCoverage
+	})
+}
+
+// OutOfGasAll sets all coverage in the coverage map as out-of-gas coverage. Out-of-gas coverage is updated with
+// successful coverage, the successful coverage is cleared. This is used in place of RevertAll when a call frame
+// exhausted its gas, as such code was attempted but could not complete, rather than being rejected by the contract.
+// Returns a boolean indicating whether out-of-gas coverage increased, and an error if one occurred.
+func (cm *CoverageMaps) OutOfGasAll() (bool, error) {
+	return cm.moveSuccessfulCoverage(func(coverageMap *ContractCoverageMap) *CoverageMapBytecodeData {
+		return coverageMap.outOfGasCoverage
+	})
+}
+
+// moveSuccessfulCoverage updates the coverage data selected by the provided function with the successful coverage of
+// each contract coverage map, then clears the successful coverage.
+// Returns a boolean indicating whether the selected coverage increased, and an error if one occurred.
+func (cm *CoverageMaps) moveSuccessfulCoverage(selectCoverage func(*ContractCoverageMap) *CoverageMapBytecodeData) (bool, error) {
 	// Acquire our thread lock and defer our unlocking for when we exit this method
 	cm.updateLock.Lock()
 	defer cm.updateLock.Unlock()
 
-	// Define a variable to track if our reverted coverage changed.
-	revertedCoverageChanged := false
+	// Define a variable to track if our selected coverage changed.
+	coverageChanged := false
 
 	// Loop for each coverage map provided
 	for _, mapsByAddressToMerge := range cm.maps {
 		for _, contractCoverageMap := range mapsByAddressToMerge {
-			// Update our reverted coverage with the (previously thought to be) successful coverage.
-			changed, err := contractCoverageMap.revertedCoverage.update(contractCoverageMap.successfulCoverage)
-			revertedCoverageChanged = revertedCoverageChanged || changed
+			// Update our selected coverage with the (previously thought to be) successful coverage.
+			changed, err := selectCoverage(contractCoverageMap).update(contractCoverageMap.successfulCoverage)
+			coverageChanged = coverageChanged || changed
 			if err != nil {
-				return revertedCoverageChanged, err
+				return coverageChanged, err
 			}
 
-			// Clear our successful coverage, as these maps were marked as reverted.
+			// Clear our successful coverage, as these maps were marked as unsuccessful.
 			contractCoverageMap.successfulCoverage.Reset()
 		}
 	}
-	return revertedCoverageChanged, nil
+	return coverageChanged, nil
 }
 
 // UniquePCs is a function that returns the total number of unique program counters (PCs)
@@ -259,24 +366,17 @@ func (cm *CoverageMaps) UniquePCs() uint64 {
 		uniquePCsForHash := make(map[int]struct{})
 
 		for _, contractCoverageMap := range mapsByAddress {
-			// TODO: Note we are not checking for nil dereference here because we are guaranteed that the successful
-			//  coverage and reverted coverage arrays have been instantiated if we are iterating over it
-
-			// Iterate across each PC in the successful coverage array
-			// We do not separately iterate over the reverted coverage array because if there is no data about a
-			// successful PC execution, then it is not possible for that PC to have ever reverted either
-			for i, hits := range contractCoverageMap.successfulCoverage.executedFlags {
-				// If we hit the PC at least once, we have a unique PC hit
-				if hits != 0 {
-					uniquePCsForHash[i] = struct{}{}
-
-					// Do not count both success and revert
-					continue
-				}
-
-				// This is only executed if the PC was not executed successfully
-				if contractCoverageMap.revertedCoverage.executedFlags != nil && i < len(contractCoverageMap.revertedCoverage.executedFlags) && contractCoverageMap.revertedCoverage.executedFlags[i] != 0 {
-					uniquePCsForHash[i] = struct{}{}
+			// TODO: Note we are not checking for nil dereference here because we are guaranteed that the successful,
+			//  reverted, and out-of-gas coverage arrays have been instantiated if we are iterating over it
+
+			// Iterate across each PC in the successful, reverted, and out-of-gas coverage arrays. Successful coverage
+			// is cleared when a call frame reverts or runs out of gas, so each must be checked separately.
+			for _, coverageData := range []*CoverageMapBytecodeData{contractCoverageMap.successfulCoverage, contractCoverageMap.revertedCoverage, contractCoverageMap.outOfGasCoverage} {
+				for i, hits := range coverageData.executedFlags {
+					// If we hit the PC at least once, we have a unique PC hit
+					if hits != 0 {
+						uniquePCsForHash[i] = struct{}{}
+					}
 				}
 			}
 		}
@@ -294,6 +394,10 @@ type ContractCoverageMap struct {
 
 	// revertedCoverage represents coverage for the contract bytecode, which encountered a revert.
 	revertedCoverage *CoverageMapBytecodeData
+
+	// outOfGasCoverage represents coverage for the contract bytecode, which was executed in a call frame that ran out
+	// of gas. Such code was attempted but could not complete, so it is tracked apart from reverted coverage.
+	outOfGasCoverage *CoverageMapBytecodeData
 }
 
 // newContractCoverageMap creates and returns a new ContractCoverageMap.
@@ -301,6 +405,16 @@ func newContractCoverageMap() *ContractCoverageMap {
 	return &ContractCoverageMap{
 		successfulCoverage: &CoverageMapBytecodeData{},
 		revertedCoverage:   &CoverageMapBytecodeData{},
+		outOfGasCoverage:   &CoverageMapBytecodeData{},
+	}
+}
+
+// clone creates a deep copy of the ContractCoverageMap.
+func (cm *ContractCoverageMap) clone() *ContractCoverageMap {
+	return &ContractCoverageMap{
+		successfulCoverage: cm.successfulCoverage.clone(),
+		revertedCoverage:   cm.revertedCoverage.clone(),
+		outOfGasCoverage:   cm.outOfGasCoverage.clone(),
 	}
 }
 
@@ -308,11 +422,13 @@ func newContractCoverageMap() *ContractCoverageMap {
 // Returns a boolean indicating whether the two maps match.
 func (cm *ContractCoverageMap) Equal(b *ContractCoverageMap) bool {
 	// Compare both our underlying bytecode coverage maps.
-	return cm.successfulCoverage.Equal(b.successfulCoverage) && cm.revertedCoverage.Equal(b.revertedCoverage)
+	return cm.successfulCoverage.Equal(b.successfulCoverage) && cm.revertedCoverage.Equal(b.revertedCoverage) &&
+		cm.outOfGasCoverage.Equal(b.outOfGasCoverage)
 }
 
 // update updates the current ContractCoverageMap with the provided one.
-// Returns two booleans indicating whether successful or reverted coverage changed, or an error if one was encountered.
+// Returns two booleans indicating whether successful or reverted (including out-of-gas) coverage changed, or an error
+// if one was encountered.
 func (cm *ContractCoverageMap) update(coverageMap *ContractCoverageMap) (bool, bool, error) {
 	// Update our success coverage data
 	successfulCoverageChanged, err := cm.successfulCoverage.update(coverageMap.successfulCoverage)
@@ -326,7 +442,25 @@ func (cm *ContractCoverageMap) update(coverageMap *ContractCoverageMap) (bool, b
 		return successfulCoverageChanged, false, err
 	}
 
-	return successfulCoverageChanged, revertedCoverageChanged, nil
+	// Update our out-of-gas coverage data
+	outOfGasCoverageChanged, err := cm.outOfGasCoverage.update(coverageMap.outOfGasCoverage)
+	if err != nil {
+		return successfulCoverageChanged, revertedCoverageChanged, err
+	}
+
+	return successfulCoverageChanged, revertedCoverageChanged || outOfGasCoverageChanged, nil
+}
+
+// coverageWithStatus returns the coverage data tracking coverage with the provided coverage marker status.
+func (cm *ContractCoverageMap) coverageWithStatus(status coverageMarkerStatus) *CoverageMapBytecodeData {
+	switch status {
+	case coverageMarkerReverted:
+		return cm.revertedCoverage
+	case coverageMarkerOutOfGas:
+		return cm.outOfGasCoverage
+	default:
+		return cm.successfulCoverage
+	}
 }
 
 // updateCoveredAt updates the hit counter at a given program counter location within a ContractCoverageMap used for
@@ -348,6 +482,11 @@ func (cm *CoverageMapBytecodeData) Reset() {
 	cm.executedFlags = nil
 }
 
+// clone creates a deep copy of the CoverageMapBytecodeData.
+func (cm *CoverageMapBytecodeData) clone() *CoverageMapBytecodeData {
+	return &CoverageMapBytecodeData{executedFlags: slices.Clone(cm.executedFlags)}
+}
+
 // Equal checks whether the provided CoverageMapBytecodeData contains the same data as the current one.
 // Returns a boolean indicating whether the two maps match.
 func (cm *CoverageMapBytecodeData) Equal(b *CoverageMapBytecodeData) bool {
@@ -5,7 +5,7 @@ import (
 
 	"github.com/crytic/medusa/chain"
 	"github.com/crytic/medusa/chain/types"
-	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	coretypes "github.com/ethereum/go-ethereum/core/types"
@@ -38,27 +38,50 @@ func RemoveCoverageTracerResults(messageResults *types.MessageResults) {
 
 // CoverageTracer implements tracers.Tracer to collect information such as coverage maps
 // for fuzzing campaigns from EVM execution traces.
+//
+// Rather than maintaining CoverageMaps for every call frame and merging them up the call stack as frames exit, the
+// tracer records coverage into a single list of coverage markers for the transaction. Each call frame's delta list is
+// the suffix of the markers list starting at its first marker, so committing a successful call frame's coverage to its
+// parent requires no work, while a failed call frame flips its markers to reverted or out-of-gas markers. The markers
+// are only materialized into CoverageMaps once the transaction ends.
 type CoverageTracer struct {
-	// coverageMaps describes the execution coverage recorded. Call frames which errored are not recorded.
-	coverageMaps *CoverageMaps
+	// callFrames describes the state tracked by the tracer for each call frame entered during the current
+	// transaction, in the order they were entered.
+	callFrames []coverageTracerCallFrameState
 
-	// callFrameStates describes the state tracked by the tracer per call frame.
-	callFrameStates []*coverageTracerCallFrameState
+	// callFrameStack describes the indexes into callFrames of the call frames which are currently executing, the last
+	// of which is the current call frame.
+	callFrameStack []int
 
-	// callDepth refers to the current EVM depth during tracing.
-	callDepth int
+	// exitedCallFrames describes the indexes into callFrames of the call frames which exited during the current
+	// transaction, in the order they exited.
+	exitedCallFrames []int
+
+	// coverageKeys describes the contract coverage maps which coverage was recorded for during the current
+	// transaction. Coverage markers refer to them by index.
+	coverageKeys []coverageTracerKey
+
+	// coverageKeyIndexes maps a contract coverage map's lookup hash and address to its index in coverageKeys.
+	coverageKeyIndexes map[coverageTracerKeyId]int
+
+	// coverageMarkers describes the coverage recorded during the current transaction, in the order it was recorded.
+	coverageMarkers []coverageTracerMarker
 
 	evmContext *tracing.VMContext
 
 	// nativeTracer is the underlying tracer used to capture EVM execution.
 	nativeTracer *chain.TestChainTracer
 
-	// codeHashCache is a cache for values returned by getContractCoverageMapHash,
+	// codeHashCache is a cache for values returned by GetContractCoverageMapHash,
 	// so that this expensive calculation doesn't need to be done every opcode.
 	// The [2] array is to differentiate between contract init (0) vs runtime (1),
-	// since init vs runtime produces different results from getContractCoverageMapHash.
+	// since init vs runtime produces different results from GetContractCoverageMapHash.
 	// The Hash key is a contract's codehash, which uniquely identifies it.
 	codeHashCache [2]map[common.Hash]common.Hash
+
+	// initCoverageEnabled indicates whether coverage should be recorded for call frames executing init bytecode
+	// (contract deployments). Runtime bytecode coverage is always recorded.
+	initCoverageEnabled bool
 }
 
 // coverageTracerCallFrameState tracks state across call frames in the tracer.
@@ -66,19 +89,93 @@ type coverageTracerCallFrameState struct {
 	// create indicates whether the current call frame is executing on init bytecode (deploying a contract).
 	create bool
 
-	// pendingCoverageMap describes the coverage maps recorded for this call frame.
-	pendingCoverageMap *CoverageMaps
+	// parent describes the index of the parent call frame in CoverageTracer.callFrames, or -1 if this is a top level
+	// call frame.
+	parent int
+
+	// markersStart describes the index of the first coverage marker recorded by this call frame or its children.
+	markersStart int
+
+	// markersEnd describes the amount of coverage markers which were recorded once this call frame exited.
+	markersEnd int
+
+	// status describes the status this call frame exited with.
+	status coverageMarkerStatus
+
+	// coverageKey describes the index of the coverage key this call frame records coverage for, or -1 if it has not
+	// recorded any coverage yet.
+	coverageKey int
+
+	// codeSize describes the size of the code executed in this call frame.
+	codeSize int
+}
+
+// coverageTracerKeyId identifies a contract coverage map by its lookup hash and address.
+type coverageTracerKeyId struct {
+	lookupHash common.Hash
+	address    common.Address
+}
+
+// coverageTracerKey describes a contract coverage map which coverage was recorded for during a transaction.
+type coverageTracerKey struct {
+	coverageTracerKeyId
+
+	// codeSize describes the size of the code executed by the first call frame which recorded coverage for this key.
+	codeSize int
+
+	// callFrame describes the index of the first call frame which recorded coverage for this key.
+	callFrame int
+
+	// multipleCallFrames indicates whether more than one call frame recorded coverage for this key.
+	multipleCallFrames bool
+
+	// markerIndexes maps each program counter to the index of the latest coverage marker recorded for it, offset by
+	// one, so that a call frame hitting the same program counter repeatedly increments a single marker.
+	markerIndexes []int
+}
+
+// coverageMarkerStatus describes whether a coverage marker describes successful, reverted, or out-of-gas coverage.
+type coverageMarkerStatus uint8
 
-	// lookupHash describes the hash used to look up the ContractCoverageMap being updated in this frame.
-	lookupHash *common.Hash
+const (
+	// coverageMarkerSuccessful describes coverage from a call frame which has not failed.
+	coverageMarkerSuccessful coverageMarkerStatus = iota
+	// coverageMarkerReverted describes coverage from a call frame which reverted, or whose ancestor reverted.
+	coverageMarkerReverted
+	// coverageMarkerOutOfGas describes coverage from a call frame which ran out of gas, or whose ancestor did.
+	coverageMarkerOutOfGas
+)
+
+// coverageTracerMarker describes hits of a program counter recorded by a given call frame.
+type coverageTracerMarker struct {
+	// coverageKey describes the index of the coverage key the program counter belongs to.
+	coverageKey int
+
+	// callFrame describes the index of the call frame which recorded the marker.
+	callFrame int
+
+	// pc describes the program counter which was hit.
+	pc uint64
+
+	// count describes the amount of times the program counter was hit.
+	count uint
+
+	// status describes whether the hits should be recorded as successful, reverted, or out-of-gas coverage.
+	status coverageMarkerStatus
 }
 
-// NewCoverageTracer returns a new CoverageTracer.
-func NewCoverageTracer() *CoverageTracer {
+// NewCoverageTracer returns a new CoverageTracer. If initCoverageEnabled is false, coverage is only recorded for
+// runtime bytecode, skipping call frames which execute init bytecode.
+func NewCoverageTracer(initCoverageEnabled bool) *CoverageTracer {
 	tracer := &CoverageTracer{
-		coverageMaps:    NewCoverageMaps(),
-		callFrameStates: make([]*coverageTracerCallFrameState, 0),
-		codeHashCache:   [2]map[common.Hash]common.Hash{make(map[common.Hash]common.Hash), make(map[common.Hash]common.Hash)},
+		callFrames:          make([]coverageTracerCallFrameState, 0),
+		callFrameStack:      make([]int, 0),
+		exitedCallFrames:    make([]int, 0),
+		coverageKeys:        make([]coverageTracerKey, 0),
+		coverageKeyIndexes:  make(map[coverageTracerKeyId]int),
+		coverageMarkers:     make([]coverageTracerMarker, 0),
+		codeHashCache:       [2]map[common.Hash]common.Hash{make(map[common.Hash]common.Hash), make(map[common.Hash]common.Hash)},
+		initCoverageEnabled: initCoverageEnabled,
 	}
 	nativeTracer := &tracers.Tracer{
 		Hooks: &tracing.Hooks{
@@ -100,105 +197,271 @@ func (t *CoverageTracer) NativeTracer() *chain.TestChainTracer {
 
 // OnTxStart is called upon the start of transaction execution, as defined by tracers.Tracer.
 func (t *CoverageTracer) OnTxStart(vm *tracing.VMContext, tx *coretypes.Transaction, from common.Address) {
-	// Reset our call frame states
-	t.callDepth = 0
-	t.coverageMaps = NewCoverageMaps()
-	t.callFrameStates = make([]*coverageTracerCallFrameState, 0)
+	// Reset our call frame states and coverage, reusing their underlying storage.
+	t.callFrames = t.callFrames[:0]
+	t.callFrameStack = t.callFrameStack[:0]
+	t.exitedCallFrames = t.exitedCallFrames[:0]
+	t.coverageKeys = t.coverageKeys[:0]
+	clear(t.coverageKeyIndexes)
+	t.coverageMarkers = t.coverageMarkers[:0]
 	t.evmContext = vm
 }
 
 // OnEnter initializes the tracing operation for the top of a call frame, as defined by tracers.Tracer.
 func (t *CoverageTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
-	// Check to see if this is the top level call frame
-	isTopLevelFrame := depth == 0
-
-	// Increment call frame depth if it is not the top level call frame
-	if !isTopLevelFrame {
-		t.callDepth++
+	// Determine our parent call frame, if this is not the top level call frame.
+	parent := -1
+	if len(t.callFrameStack) > 0 {
+		parent = t.callFrameStack[len(t.callFrameStack)-1]
 	}
 
-	// Create our state tracking struct for this frame.
-	t.callFrameStates = append(t.callFrameStates, &coverageTracerCallFrameState{
-		create:             typ == byte(vm.CREATE) || typ == byte(vm.CREATE2),
-		pendingCoverageMap: NewCoverageMaps(),
+	// Create our state tracking struct for this frame. Its delta list starts at the end of the current markers.
+	t.callFrames = append(t.callFrames, coverageTracerCallFrameState{
+		create:       typ == byte(vm.CREATE) || typ == byte(vm.CREATE2),
+		parent:       parent,
+		markersStart: len(t.coverageMarkers),
+		coverageKey:  -1,
 	})
+	t.callFrameStack = append(t.callFrameStack, len(t.callFrames)-1)
 }
 
 // OnExit is called after a call to finalize tracing completes for the top of a call frame, as defined by tracers.Tracer.
 func (t *CoverageTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
-	// Check to see if this is the top level call frame
-	isTopLevelFrame := depth == 0
-
-	// If we encountered an error in this call frame, mark all coverage as reverted.
+	// Pop the state tracking struct for this call frame off the stack.
+	callFrameIndex := t.callFrameStack[len(t.callFrameStack)-1]
+	t.callFrameStack = t.callFrameStack[:len(t.callFrameStack)-1]
+	callFrameState := &t.callFrames[callFrameIndex]
+
+	// If we encountered an error in this call frame, mark all coverage recorded by it and its children as reverted. If
+	// the call frame ran out of gas, its coverage is marked as out-of-gas instead, as the code was attempted but could
+	// not complete. Coverage already marked by a failed child call frame keeps its status. Coverage of a successful
+	// call frame is already part of its parent's delta list, so it needs no update.
 	if err != nil {
-		_, revertCoverageErr := t.callFrameStates[t.callDepth].pendingCoverageMap.RevertAll()
-		if revertCoverageErr != nil {
-			logging.GlobalLogger.Panic("Coverage tracer failed to update revert coverage map during capture end", revertCoverageErr)
+		callFrameState.status = coverageMarkerReverted
+		if utils.IsOutOfGasError(err) {
+			callFrameState.status = coverageMarkerOutOfGas
+		}
+		for i := callFrameState.markersStart; i < len(t.coverageMarkers); i++ {
+			if t.coverageMarkers[i].status == coverageMarkerSuccessful {
+				t.coverageMarkers[i].status = callFrameState.status
+			}
 		}
 	}
+	callFrameState.markersEnd = len(t.coverageMarkers)
+	t.exitedCallFrames = append(t.exitedCallFrames, callFrameIndex)
+}
 
-	// Commit all our coverage maps up one call frame.
-	var coverageUpdateErr error
-	if isTopLevelFrame {
-		// Update the final coverage map if this is the top level call frame
-		_, _, coverageUpdateErr = t.coverageMaps.Update(t.callFrameStates[t.callDepth].pendingCoverageMap)
-	} else {
-		// Move coverage up one call frame
-		_, _, coverageUpdateErr = t.callFrameStates[t.callDepth-1].pendingCoverageMap.Update(t.callFrameStates[t.callDepth].pendingCoverageMap)
+// OnOpcode records data from an EVM state update, as defined by tracers.Tracer.
+func (t *CoverageTracer) OnOpcode(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	// Obtain our call frame state tracking struct
+	callFrameIndex := t.callFrameStack[len(t.callFrameStack)-1]
+	callFrameState := &t.callFrames[callFrameIndex]
 
-		// Pop the state tracking struct for this call frame off the stack and decrement the call depth
-		t.callFrameStates = t.callFrameStates[:t.callDepth]
-		t.callDepth--
+	// If we are not recording init bytecode coverage, skip deployment call frames entirely.
+	if callFrameState.create && !t.initCoverageEnabled {
+		return
 	}
-	if coverageUpdateErr != nil {
-		logging.GlobalLogger.Panic("Coverage tracer failed to update coverage map during capture end", coverageUpdateErr)
+
+	// If this call frame has not recorded coverage yet, obtain the key for the coverage map it records coverage for.
+	if callFrameState.coverageKey < 0 {
+		// We can cast OpContext to ScopeContext because that is the type passed to OnOpcode.
+		scopeContext := scope.(*vm.ScopeContext)
+		code := scopeContext.Contract.Code
+		codeSize := len(code)
+
+		// If there is no code we're executing, there is no coverage to collect.
+		if codeSize == 0 {
+			return
+		}
+		callFrameState.codeSize = codeSize
+		callFrameState.coverageKey = t.coverageKeyIndex(scope.Address(), t.lookupHash(scopeContext, callFrameState.create), codeSize, callFrameIndex)
 	}
 
-}
+	// Record coverage for this location. If this call frame already recorded a marker for this program counter, we
+	// increment it rather than appending another.
+	key := &t.coverageKeys[callFrameState.coverageKey]
+	if pc < uint64(len(key.markerIndexes)) {
+		if markerIndex := key.markerIndexes[pc] - 1; markerIndex >= 0 && t.coverageMarkers[markerIndex].callFrame == callFrameIndex {
+			t.coverageMarkers[markerIndex].count++
+			return
+		}
+		key.markerIndexes[pc] = len(t.coverageMarkers) + 1
+	}
 
-// OnOpcode records data from an EVM state update, as defined by tracers.Tracer.
-func (t *CoverageTracer) OnOpcode(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
-	// Obtain our call frame state tracking struct
-	callFrameState := t.callFrameStates[t.callDepth]
+	// Program counters beyond the code size are not counted, but are still recorded, as they still create a coverage
+	// map for the contract.
+	count := uint(0)
+	if pc < uint64(callFrameState.codeSize) {
+		count = 1
+	}
+	t.coverageMarkers = append(t.coverageMarkers, coverageTracerMarker{
+		coverageKey: callFrameState.coverageKey,
+		callFrame:   callFrameIndex,
+		pc:          pc,
+		count:       count,
+		status:      coverageMarkerSuccessful,
+	})
+}
 
-	// If there is code we're executing, collect coverage.
-	address := scope.Address()
-	// We can cast OpContext to ScopeContext because that is the type passed to OnOpcode.
-	scopeContext := scope.(*vm.ScopeContext)
+// lookupHash obtains the contract coverage map lookup hash for the code executed in the provided scope. Init bytecode
+// executed through CREATE does not have its code hash computed by the EVM, so we cannot cache lookup hashes for it.
+func (t *CoverageTracer) lookupHash(scopeContext *vm.ScopeContext, isCreate bool) common.Hash {
 	code := scopeContext.Contract.Code
-	codeSize := len(code)
-	isCreate := callFrameState.create
 	gethCodeHash := scopeContext.Contract.CodeHash
+	if gethCodeHash == (common.Hash{}) {
+		return GetContractCoverageMapHash(code, isCreate)
+	}
 
 	cacheArrayKey := 1
 	if isCreate {
 		cacheArrayKey = 0
 	}
+	lookupHash, cacheHit := t.codeHashCache[cacheArrayKey][gethCodeHash]
+	if !cacheHit {
+		lookupHash = GetContractCoverageMapHash(code, isCreate)
+		t.codeHashCache[cacheArrayKey][gethCodeHash] = lookupHash
+	}
+	return lookupHash
+}
 
-	if codeSize > 0 {
+// coverageKeyIndex obtains the index of the coverage key for the provided address and lookup hash, creating it if it
+// does not exist yet, and tracking whether it was recorded by more than one call frame.
+func (t *CoverageTracer) coverageKeyIndex(address common.Address, lookupHash common.Hash, codeSize int, callFrameIndex int) int {
+	keyId := coverageTracerKeyId{lookupHash: lookupHash, address: address}
+	if keyIndex, ok := t.coverageKeyIndexes[keyId]; ok {
+		t.coverageKeys[keyIndex].multipleCallFrames = true
+		return keyIndex
+	}
 
-		// Obtain our contract coverage map lookup hash.
-		if callFrameState.lookupHash == nil {
-			lookupHash, cacheHit := t.codeHashCache[cacheArrayKey][gethCodeHash]
-			if !cacheHit {
-				lookupHash = getContractCoverageMapHash(code, isCreate)
-				t.codeHashCache[cacheArrayKey][gethCodeHash] = lookupHash
+	// Reuse the marker indexes of a key from a previous transaction if we can, as they are sized by the code.
+	var markerIndexes []int
+	if len(t.coverageKeys) < cap(t.coverageKeys) {
+		markerIndexes = t.coverageKeys[:len(t.coverageKeys)+1][len(t.coverageKeys)].markerIndexes
+	}
+	if cap(markerIndexes) >= codeSize {
+		markerIndexes = markerIndexes[:codeSize]
+		clear(markerIndexes)
+	} else {
+		markerIndexes = make([]int, codeSize)
+	}
+	t.coverageKeys = append(t.coverageKeys, coverageTracerKey{
+		coverageTracerKeyId: keyId,
+		codeSize:            codeSize,
+		callFrame:           callFrameIndex,
+		markerIndexes:       markerIndexes,
+	})
+	t.coverageKeyIndexes[keyId] = len(t.coverageKeys) - 1
+	return len(t.coverageKeys) - 1
+}
+
+// coverageMaps materializes the coverage markers recorded during the current transaction into CoverageMaps.
+func (t *CoverageTracer) coverageMaps() *CoverageMaps {
+	// Coverage for keys recorded by a single call frame is simply the sum of its markers by status. Coverage maps
+	// merged up the call stack only take hit counts for program counters which were not yet hit, so keys recorded by
+	// multiple call frames have their coverage replayed through each call frame instead.
+	contractCoverageMaps := make([]*ContractCoverageMap, len(t.coverageKeys))
+	replayedMarkers := make(map[int][]int)
+	for i, marker := range t.coverageMarkers {
+		key := &t.coverageKeys[marker.coverageKey]
+		if key.multipleCallFrames {
+			replayedMarkers[marker.coverageKey] = append(replayedMarkers[marker.coverageKey], i)
+			continue
+		}
+		contractCoverageMap := contractCoverageMaps[marker.coverageKey]
+		if contractCoverageMap == nil {
+			contractCoverageMap = newContractCoverageMap()
+			contractCoverageMaps[marker.coverageKey] = contractCoverageMap
+		}
+		coverageData := contractCoverageMap.coverageWithStatus(marker.status)
+		if coverageData.executedFlags == nil {
+			coverageData.executedFlags = make([]uint, key.codeSize)
+		}
+		if marker.pc < uint64(len(coverageData.executedFlags)) {
+			coverageData.executedFlags[marker.pc] += marker.count
+		}
+	}
+	for keyIndex, markerIndexes := range replayedMarkers {
+		contractCoverageMaps[keyIndex] = t.replayCoverageMap(markerIndexes)
+	}
+
+	// Add every coverage map to our results.
+	coverageMaps := NewCoverageMaps()
+	for keyIndex, contractCoverageMap := range contractCoverageMaps {
+		if contractCoverageMap == nil {
+			continue
+		}
+		key := t.coverageKeys[keyIndex]
+		mapsByAddress, ok := coverageMaps.maps[key.lookupHash]
+		if !ok {
+			mapsByAddress = make(map[common.Address]*ContractCoverageMap)
+			coverageMaps.maps[key.lookupHash] = mapsByAddress
+		}
+		mapsByAddress[key.address] = contractCoverageMap
+	}
+	return coverageMaps
+}
+
+// replayCoverageMap materializes the ContractCoverageMap for a key recorded by multiple call frames, given the
+// indexes of its coverage markers. The coverage of each call frame is merged into its parent as it exits, in the
+// order call frames exited, so that program counters hit in multiple call frames obtain the same hit counts as they
+// would if each call frame tracked its own CoverageMaps.
+func (t *CoverageTracer) replayCoverageMap(markerIndexes []int) *ContractCoverageMap {
+	var result *ContractCoverageMap
+	callFrameMaps := make(map[int]*ContractCoverageMap)
+	for _, callFrameIndex := range t.exitedCallFrames {
+		callFrameState := &t.callFrames[callFrameIndex]
+
+		// Record all hits which occurred before this call frame exited.
+		for len(markerIndexes) > 0 && markerIndexes[0] < callFrameState.markersEnd {
+			marker := t.coverageMarkers[markerIndexes[0]]
+			markerIndexes = markerIndexes[1:]
+			callFrameMap, ok := callFrameMaps[marker.callFrame]
+			if !ok {
+				callFrameMap = newContractCoverageMap()
+				callFrameMaps[marker.callFrame] = callFrameMap
+			}
+			successfulCoverage := callFrameMap.successfulCoverage
+			if successfulCoverage.executedFlags == nil {
+				successfulCoverage.executedFlags = make([]uint, t.callFrames[marker.callFrame].codeSize)
+			}
+			if marker.pc < uint64(len(successfulCoverage.executedFlags)) {
+				successfulCoverage.executedFlags[marker.pc] += marker.count
 			}
-			callFrameState.lookupHash = &lookupHash
 		}
 
-		// Record coverage for this location in our map.
-		_, coverageUpdateErr := callFrameState.pendingCoverageMap.UpdateAt(address, *callFrameState.lookupHash, codeSize, pc)
-		if coverageUpdateErr != nil {
-			logging.GlobalLogger.Panic("Coverage tracer failed to update coverage map while tracing state", coverageUpdateErr)
+		// If this call frame recorded no coverage for this key, there is nothing to merge.
+		callFrameMap, ok := callFrameMaps[callFrameIndex]
+		if !ok {
+			continue
+		}
+		delete(callFrameMaps, callFrameIndex)
+
+		// If this call frame failed, move its successful coverage to its failed coverage.
+		if callFrameState.status != coverageMarkerSuccessful {
+			_, _ = callFrameMap.coverageWithStatus(callFrameState.status).update(callFrameMap.successfulCoverage)
+			callFrameMap.successfulCoverage.Reset()
+		}
+
+		// Merge this call frame's coverage into its parent.
+		if callFrameState.parent < 0 {
+			if result == nil {
+				result = callFrameMap
+			} else {
+				_, _, _ = result.update(callFrameMap)
+			}
+		} else if parentMap, ok := callFrameMaps[callFrameState.parent]; ok {
+			_, _, _ = parentMap.update(callFrameMap)
+		} else {
+			callFrameMaps[callFrameState.parent] = callFrameMap
 		}
 	}
+	return result
 }
 
 // CaptureTxEndSetAdditionalResults can be used to set additional results captured from execution tracing. If this
 // tracer is used during transaction execution (block creation), the results can later be queried from the block.
 // This method will only be called on the added tracer if it implements the extended TestChainTracer interface.
 func (t *CoverageTracer) CaptureTxEndSetAdditionalResults(results *types.MessageResults) {
-	// Store our tracer results.
-	results.AdditionalResults[coverageTracerResultsKey] = t.coverageMaps
+	// Materialize and store our tracer results.
+	results.AdditionalResults[coverageTracerResultsKey] = t.coverageMaps()
 }
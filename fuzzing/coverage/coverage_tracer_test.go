@@ -0,0 +1,451 @@
+package coverage
+
+import (
+	"encoding/hex"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/crytic/medusa/chain"
+	"github.com/crytic/medusa/chain/testchainutils"
+	chainTypes "github.com/crytic/medusa/chain/types"
+	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	// childRuntimeBytecode describes runtime bytecode which returns the value 42.
+	childRuntimeBytecode = "602a60005260206000f3"
+
+	// childInitBytecode describes init bytecode which deploys childRuntimeBytecode.
+	childInitBytecode = "600a600c600039600a6000f3" + childRuntimeBytecode
+
+	// factoryRuntimeBytecode describes runtime bytecode which deploys ten children using childInitBytecode every time
+	// it is called.
+	factoryRuntimeBytecode = "75" + childInitBytecode + "600052" + strings.Repeat("6016600a6000f050", 10) + "00"
+
+	// factoryInitBytecode describes init bytecode which deploys factoryRuntimeBytecode.
+	factoryInitBytecode = "606b600c600039606b6000f3" + factoryRuntimeBytecode
+
+	// loopRuntimeBytecode describes runtime bytecode which loops forever, until it runs out of gas.
+	loopRuntimeBytecode = "5b600056"
+
+	// loopInitBytecode describes init bytecode which deploys loopRuntimeBytecode.
+	loopInitBytecode = "6004600c60003960046000f3" + loopRuntimeBytecode
+
+	// revertRuntimeBytecode describes runtime bytecode which reverts immediately.
+	revertRuntimeBytecode = "60006000fd"
+
+	// revertInitBytecode describes init bytecode which deploys revertRuntimeBytecode.
+	revertInitBytecode = "6005600c60003960056000f3" + revertRuntimeBytecode
+
+	// forwarderRuntimeBytecode describes runtime bytecode which treats its calldata as a list of addresses, calling the
+	// first address with the remaining addresses as calldata, or stopping if the list is empty. This allows arbitrarily
+	// deep call stacks to be created, through distinct or recurring addresses. The code is padded to 8KB, so that it
+	// is sized like a typical contract.
+	forwarderRuntimeBytecode = "3615601f57602036038060206000376000600091600060006000355af150005b00" + strings.Repeat("00", 0x2000-33)
+
+	// forwarderInitBytecode describes init bytecode which deploys forwarderRuntimeBytecode.
+	forwarderInitBytecode = "61200080600c6000396000f3" + forwarderRuntimeBytecode
+)
+
+// decodeBytecode decodes the provided hex-encoded bytecode, panicking if it is invalid.
+func decodeBytecode(bytecode string) []byte {
+	b, err := hex.DecodeString(bytecode)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// newFactoryTestChain creates a TestChain with a CoverageTracer attached, deploys a factory contract which deploys
+// child contracts when called, and returns the chain, the sending account, and the factory's address. The chain is
+// closed once the test completes.
+func newFactoryTestChain(tb testing.TB, tracer *CoverageTracer) (*chain.TestChain, common.Address, common.Address) {
+	// Create a test chain with a funded sender, attaching our coverage tracer.
+	testChain, sender := testchainutils.NewFundedTestChain(tb, tracer.NativeTracer())
+
+	// Deploy our factory.
+	results := testchainutils.SendMessage(tb, testChain, sender, nil, decodeBytecode(factoryInitBytecode))
+	assert.EqualValues(tb, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	return testChain, sender, results.Receipt.ContractAddress
+}
+
+// TestCoverageTracerInitCoverageToggle tests that disabling init bytecode coverage skips recording coverage for
+// contract deployments, while runtime coverage remains unaffected.
+func TestCoverageTracerInitCoverageToggle(t *testing.T) {
+	// Collect coverage for the factory deployment and a call to it, with and without init coverage enabled.
+	collectCoverage := func(initCoverageEnabled bool) *CoverageMaps {
+		testChain, sender, factoryAddress := newFactoryTestChain(t, NewCoverageTracer(initCoverageEnabled))
+		coverageMaps := NewCoverageMaps()
+		for _, block := range testChain.CommittedBlocks() {
+			for _, messageResults := range block.MessageResults {
+				if results := GetCoverageTracerResults(messageResults); results != nil {
+					_, _, err := coverageMaps.Update(results)
+					assert.NoError(t, err)
+				}
+			}
+		}
+		results := testchainutils.SendMessage(t, testChain, sender, &factoryAddress, nil)
+		assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+		_, _, err := coverageMaps.Update(GetCoverageTracerResults(results))
+		assert.NoError(t, err)
+		return coverageMaps
+	}
+	withInitCoverage := collectCoverage(true)
+	withoutInitCoverage := collectCoverage(false)
+
+	// Init coverage should only be recorded if it was enabled.
+	for _, initBytecode := range []string{factoryInitBytecode, childInitBytecode} {
+		initCoverage, err := withInitCoverage.GetContractCoverageMap(decodeBytecode(initBytecode), true)
+		assert.NoError(t, err)
+		assert.NotNil(t, initCoverage, initBytecode)
+		initCoverage, err = withoutInitCoverage.GetContractCoverageMap(decodeBytecode(initBytecode), true)
+		assert.NoError(t, err)
+		assert.Nil(t, initCoverage)
+	}
+
+	// Runtime coverage should be identical regardless.
+	runtimeCoverage, err := withInitCoverage.GetContractCoverageMap(decodeBytecode(factoryRuntimeBytecode), false)
+	assert.NoError(t, err)
+	assert.NotNil(t, runtimeCoverage)
+	runtimeCoverageWithoutInit, err := withoutInitCoverage.GetContractCoverageMap(decodeBytecode(factoryRuntimeBytecode), false)
+	assert.NoError(t, err)
+	assert.True(t, runtimeCoverage.Equal(runtimeCoverageWithoutInit))
+	uniqueCoverage := withInitCoverage.UniquePCs()
+	uniqueCoverageWithoutInit := withoutInitCoverage.UniquePCs()
+	assert.Greater(t, uniqueCoverage, uniqueCoverageWithoutInit)
+}
+
+// TestCoverageTracerOutOfGasCoverage tests that coverage of call frames which ran out of gas is recorded as out-of-gas
+// coverage, distinct from the reverted coverage of call frames which reverted.
+func TestCoverageTracerOutOfGasCoverage(t *testing.T) {
+	// Deploy a contract which loops until it runs out of gas, and one which reverts immediately.
+	testChain, sender, _ := newFactoryTestChain(t, NewCoverageTracer(false))
+	results := testchainutils.SendMessage(t, testChain, sender, nil, decodeBytecode(loopInitBytecode))
+	assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	loopAddress := results.Receipt.ContractAddress
+	results = testchainutils.SendMessage(t, testChain, sender, nil, decodeBytecode(revertInitBytecode))
+	assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	revertAddress := results.Receipt.ContractAddress
+
+	// Call both contracts, collecting their coverage.
+	coverageMaps := NewCoverageMaps()
+	results = testchainutils.SendMessageWithGasLimit(t, testChain, sender, &loopAddress, nil, 100_000)
+	assert.EqualValues(t, types.ReceiptStatusFailed, results.Receipt.Status)
+	_, revertedChanged, delta, err := coverageMaps.UpdateWithDelta(GetCoverageTracerResults(results))
+	assert.NoError(t, err)
+	assert.True(t, revertedChanged)
+	assert.Len(t, delta.Contracts, 1)
+	assert.Empty(t, delta.Contracts[0].SuccessfulPCs)
+	assert.Empty(t, delta.Contracts[0].RevertedPCs)
+	assert.EqualValues(t, []int{0, 1, 3}, delta.Contracts[0].OutOfGasPCs)
+	results = testchainutils.SendMessage(t, testChain, sender, &revertAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusFailed, results.Receipt.Status)
+	_, _, err = coverageMaps.Update(GetCoverageTracerResults(results))
+	assert.NoError(t, err)
+
+	// The looping contract's coverage should only be out-of-gas coverage.
+	loopCoverage, err := coverageMaps.GetContractCoverageMap(decodeBytecode(loopRuntimeBytecode), false)
+	assert.NoError(t, err)
+	assert.NotNil(t, loopCoverage)
+	assert.Nil(t, loopCoverage.successfulCoverage.executedFlags)
+	assert.Nil(t, loopCoverage.revertedCoverage.executedFlags)
+	assert.Greater(t, loopCoverage.outOfGasCoverage.HitCount(0), uint(1))
+	assert.Zero(t, loopCoverage.outOfGasCoverage.HitCount(2))
+
+	// The reverting contract's coverage should only be reverted coverage.
+	revertCoverage, err := coverageMaps.GetContractCoverageMap(decodeBytecode(revertRuntimeBytecode), false)
+	assert.NoError(t, err)
+	assert.NotNil(t, revertCoverage)
+	assert.Nil(t, revertCoverage.successfulCoverage.executedFlags)
+	assert.Nil(t, revertCoverage.outOfGasCoverage.executedFlags)
+	assert.EqualValues(t, 1, revertCoverage.revertedCoverage.HitCount(4))
+
+	// Both should count toward unique coverage.
+	assert.EqualValues(t, 3+3, coverageMaps.UniquePCs())
+}
+
+// BenchmarkCoverageTracerFactoryDeployments measures the cost of tracing calls to a factory contract which deploys
+// many child contracts, with and without init bytecode coverage enabled.
+func BenchmarkCoverageTracerFactoryDeployments(b *testing.B) {
+	for _, initCoverageEnabled := range []bool{true, false} {
+		name := "InitCoverageEnabled"
+		if !initCoverageEnabled {
+			name = "InitCoverageDisabled"
+		}
+		b.Run(name, func(b *testing.B) {
+			testChain, sender, factoryAddress := newFactoryTestChain(b, NewCoverageTracer(initCoverageEnabled))
+			baseBlockIndex := uint64(len(testChain.CommittedBlocks()))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				testchainutils.SendMessage(b, testChain, sender, &factoryAddress, nil)
+				if err := testChain.RevertToBlockIndex(baseBlockIndex); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// perFrameCoverageTracer is a reference implementation of CoverageTracer which tracks CoverageMaps for every call
+// frame, merging them into their parent's CoverageMaps as they exit. It is used to verify that CoverageTracer records
+// identical coverage.
+type perFrameCoverageTracer struct {
+	// coverageMaps describes the execution coverage recorded for the current transaction.
+	coverageMaps *CoverageMaps
+
+	// callFrameStates describes the state tracked by the tracer per call frame.
+	callFrameStates []*perFrameCoverageTracerCallFrameState
+
+	// initCoverageEnabled indicates whether coverage should be recorded for call frames executing init bytecode.
+	initCoverageEnabled bool
+}
+
+// perFrameCoverageTracerCallFrameState tracks state across call frames in the perFrameCoverageTracer.
+type perFrameCoverageTracerCallFrameState struct {
+	// create indicates whether the current call frame is executing on init bytecode (deploying a contract).
+	create bool
+
+	// pendingCoverageMap describes the coverage maps recorded for this call frame.
+	pendingCoverageMap *CoverageMaps
+
+	// lookupHash describes the hash used to look up the ContractCoverageMap being updated in this frame.
+	lookupHash *common.Hash
+}
+
+// newPerFrameCoverageTracer returns a new perFrameCoverageTracer.
+func newPerFrameCoverageTracer(initCoverageEnabled bool) *perFrameCoverageTracer {
+	return &perFrameCoverageTracer{coverageMaps: NewCoverageMaps(), initCoverageEnabled: initCoverageEnabled}
+}
+
+// NativeTracer returns a TestChainTracer which traces execution using the perFrameCoverageTracer.
+func (t *perFrameCoverageTracer) NativeTracer() *chain.TestChainTracer {
+	return &chain.TestChainTracer{Tracer: &tracers.Tracer{Hooks: t.hooks()}}
+}
+
+// hooks returns the tracing hooks of the perFrameCoverageTracer.
+func (t *perFrameCoverageTracer) hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnTxStart: func(vm *tracing.VMContext, tx *types.Transaction, from common.Address) {
+			t.coverageMaps = NewCoverageMaps()
+			t.callFrameStates = nil
+		},
+		OnEnter: func(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+			t.callFrameStates = append(t.callFrameStates, &perFrameCoverageTracerCallFrameState{
+				create:             typ == byte(vm.CREATE) || typ == byte(vm.CREATE2),
+				pendingCoverageMap: NewCoverageMaps(),
+			})
+		},
+		OnExit: func(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+			callFrameState := t.callFrameStates[len(t.callFrameStates)-1]
+			t.callFrameStates = t.callFrameStates[:len(t.callFrameStates)-1]
+			if err != nil {
+				if utils.IsOutOfGasError(err) {
+					_, _ = callFrameState.pendingCoverageMap.OutOfGasAll()
+				} else {
+					_, _ = callFrameState.pendingCoverageMap.RevertAll()
+				}
+			}
+			if len(t.callFrameStates) == 0 {
+				_, _, _ = t.coverageMaps.Update(callFrameState.pendingCoverageMap)
+			} else {
+				_, _, _ = t.callFrameStates[len(t.callFrameStates)-1].pendingCoverageMap.Update(callFrameState.pendingCoverageMap)
+			}
+		},
+		OnOpcode: func(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+			callFrameState := t.callFrameStates[len(t.callFrameStates)-1]
+			if callFrameState.create && !t.initCoverageEnabled {
+				return
+			}
+			code := scope.(*vm.ScopeContext).Contract.Code
+			if len(code) > 0 {
+				if callFrameState.lookupHash == nil {
+					lookupHash := GetContractCoverageMapHash(code, callFrameState.create)
+					callFrameState.lookupHash = &lookupHash
+				}
+				_, _ = callFrameState.pendingCoverageMap.UpdateAt(scope.Address(), *callFrameState.lookupHash, len(code), pc)
+			}
+		},
+	}
+}
+
+// assertCoverageMapsIdentical asserts that the provided CoverageMaps contain the same contract coverage maps, with
+// the same hit counts, and the same coverage data left unset.
+func assertCoverageMapsIdentical(tb testing.TB, expected *CoverageMaps, actual *CoverageMaps) {
+	assert.Len(tb, actual.maps, len(expected.maps))
+	for codeHash, expectedMapsByAddress := range expected.maps {
+		actualMapsByAddress := actual.maps[codeHash]
+		assert.Len(tb, actualMapsByAddress, len(expectedMapsByAddress))
+		for codeAddress, expectedMap := range expectedMapsByAddress {
+			actualMap, ok := actualMapsByAddress[codeAddress]
+			if !assert.True(tb, ok, "missing coverage map for %v at %v", codeHash, codeAddress) {
+				continue
+			}
+			assert.Equal(tb, expectedMap.successfulCoverage.executedFlags, actualMap.successfulCoverage.executedFlags)
+			assert.Equal(tb, expectedMap.revertedCoverage.executedFlags, actualMap.revertedCoverage.executedFlags)
+			assert.Equal(tb, expectedMap.outOfGasCoverage.executedFlags, actualMap.outOfGasCoverage.executedFlags)
+		}
+	}
+}
+
+// TestCoverageTracerMatchesPerFrameCoverageMaps tests that CoverageTracer records identical coverage to tracking
+// CoverageMaps for every call frame, for randomly generated call trees in which contracts are re-entered, call frames
+// fail at different depths, and program counters beyond the code size are executed.
+func TestCoverageTracerMatchesPerFrameCoverageMaps(t *testing.T) {
+	randomProvider := rand.New(rand.NewSource(0))
+	codes := [][]byte{decodeBytecode(childRuntimeBytecode), decodeBytecode(revertRuntimeBytecode), decodeBytecode(loopRuntimeBytecode)}
+	addresses := []common.Address{common.HexToAddress("0x1000"), common.HexToAddress("0x2000"), common.HexToAddress("0x3000")}
+	errs := []error{nil, nil, vm.ErrExecutionReverted, vm.ErrOutOfGas}
+
+	for _, initCoverageEnabled := range []bool{true, false} {
+		tracer := NewCoverageTracer(initCoverageEnabled)
+		reference := newPerFrameCoverageTracer(initCoverageEnabled)
+		allHooks := []*tracing.Hooks{tracer.NativeTracer().Hooks, reference.hooks()}
+
+		// Simulates a call frame with random opcodes and child call frames on both tracers.
+		var simulateCallFrame func(depth int)
+		simulateCallFrame = func(depth int) {
+			code := codes[randomProvider.Intn(len(codes))]
+			contract := vm.NewContract(vm.AccountRef(addresses[0]), vm.AccountRef(addresses[randomProvider.Intn(len(addresses))]), uint256.NewInt(0), 0)
+			contract.Code = code
+			typ := vm.CALL
+			if randomProvider.Intn(4) == 0 {
+				typ = vm.CREATE
+			} else if randomProvider.Intn(2) == 0 {
+				contract.CodeHash = crypto.Keccak256Hash(code)
+			}
+			scope := &vm.ScopeContext{Contract: contract}
+
+			for _, hooks := range allHooks {
+				hooks.OnEnter(depth, byte(typ), addresses[0], contract.Address(), nil, 0, big.NewInt(0))
+			}
+			steps := randomProvider.Intn(16)
+			for i := 0; i < steps; i++ {
+				if depth < 5 && randomProvider.Intn(4) == 0 {
+					simulateCallFrame(depth + 1)
+					continue
+				}
+				pc := uint64(randomProvider.Intn(len(code) + 2))
+				for _, hooks := range allHooks {
+					hooks.OnOpcode(pc, 0, 0, 0, scope, nil, depth, nil)
+				}
+			}
+			err := errs[randomProvider.Intn(len(errs))]
+			for _, hooks := range allHooks {
+				hooks.OnExit(depth, nil, 0, err, err != nil)
+			}
+		}
+
+		// Simulate many transactions, verifying the coverage of each.
+		for i := 0; i < 2000; i++ {
+			for _, hooks := range allHooks {
+				hooks.OnTxStart(nil, nil, addresses[0])
+			}
+			simulateCallFrame(0)
+			results := &chainTypes.MessageResults{AdditionalResults: make(map[string]any)}
+			tracer.CaptureTxEndSetAdditionalResults(results)
+			assertCoverageMapsIdentical(t, reference.coverageMaps, GetCoverageTracerResults(results))
+		}
+	}
+}
+
+// newForwarderTestChain creates a TestChain with the provided tracers attached, deploying the provided amount of
+// forwarder contracts along with a contract which loops until it runs out of gas, and one which reverts. The chain is
+// closed once the test completes.
+// Returns the chain, the sending account, the forwarder addresses, the looping contract's address, and the reverting
+// contract's address.
+func newForwarderTestChain(tb testing.TB, forwarderCount int, tracers ...*chain.TestChainTracer) (*chain.TestChain, common.Address, []common.Address, common.Address, common.Address) {
+	testChain, sender := testchainutils.NewFundedTestChain(tb, tracers...)
+
+	forwarders := make([]common.Address, forwarderCount)
+	for i := range forwarders {
+		forwarders[i] = testchainutils.SendMessage(tb, testChain, sender, nil, decodeBytecode(forwarderInitBytecode)).Receipt.ContractAddress
+	}
+	loopAddress := testchainutils.SendMessage(tb, testChain, sender, nil, decodeBytecode(loopInitBytecode)).Receipt.ContractAddress
+	revertAddress := testchainutils.SendMessage(tb, testChain, sender, nil, decodeBytecode(revertInitBytecode)).Receipt.ContractAddress
+	return testChain, sender, forwarders, loopAddress, revertAddress
+}
+
+// forwarderCalldata creates calldata for a forwarder contract which calls through each of the provided addresses.
+func forwarderCalldata(addresses ...common.Address) []byte {
+	data := make([]byte, 0, len(addresses)*32)
+	for _, address := range addresses {
+		data = append(data, common.LeftPadBytes(address.Bytes(), 32)...)
+	}
+	return data
+}
+
+// TestCoverageTracerDeepCallsMatchPerFrameCoverageMaps tests that CoverageTracer records identical coverage to
+// tracking CoverageMaps for every call frame, for deep call stacks through distinct and recurring contracts, which
+// end in successful, reverted, and out-of-gas call frames.
+func TestCoverageTracerDeepCallsMatchPerFrameCoverageMaps(t *testing.T) {
+	tracer := NewCoverageTracer(true)
+	reference := newPerFrameCoverageTracer(true)
+	testChain, sender, forwarders, loopAddress, revertAddress := newForwarderTestChain(t, 8, tracer.NativeTracer(), reference.NativeTracer())
+
+	// Create call paths through distinct forwarders, a single recurring forwarder, and alternating forwarders.
+	recurringForwarders := make([]common.Address, 0)
+	alternatingForwarders := make([]common.Address, 0)
+	for i := 0; i < 16; i++ {
+		recurringForwarders = append(recurringForwarders, forwarders[0])
+		alternatingForwarders = append(alternatingForwarders, forwarders[i%2])
+	}
+	for _, callPath := range [][]common.Address{forwarders, recurringForwarders, alternatingForwarders} {
+		for _, lastAddress := range []*common.Address{nil, &loopAddress, &revertAddress} {
+			addresses := append([]common.Address{}, callPath...)
+			if lastAddress != nil {
+				addresses = append(addresses, *lastAddress)
+			}
+			results := testchainutils.SendMessageWithGasLimit(t, testChain, sender, &addresses[0], forwarderCalldata(addresses[1:]...), 1_000_000)
+			assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+			assertCoverageMapsIdentical(t, reference.coverageMaps, GetCoverageTracerResults(results))
+		}
+	}
+}
+
+// BenchmarkCoverageTracerDeepCalls measures the cost of tracing deep call stacks through distinct and recurring
+// contracts, compared to tracking CoverageMaps for every call frame.
+func BenchmarkCoverageTracerDeepCalls(b *testing.B) {
+	const depth = 64
+	for _, recurring := range []bool{false, true} {
+		for _, perFrame := range []bool{false, true} {
+			name := "Distinct"
+			if recurring {
+				name = "Recurring"
+			}
+			var tracer *chain.TestChainTracer
+			if perFrame {
+				name += "PerFrameCoverageMaps"
+				tracer = newPerFrameCoverageTracer(true).NativeTracer()
+			} else {
+				tracer = NewCoverageTracer(true).NativeTracer()
+			}
+			b.Run(name, func(b *testing.B) {
+				testChain, sender, forwarders, _, revertAddress := newForwarderTestChain(b, depth, tracer)
+				if recurring {
+					for i := range forwarders {
+						forwarders[i] = forwarders[0]
+					}
+				}
+				calldata := forwarderCalldata(append(forwarders[1:], revertAddress)...)
+				baseBlockIndex := uint64(len(testChain.CommittedBlocks()))
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					testchainutils.SendMessage(b, testChain, sender, &forwarders[0], calldata)
+					if err := testChain.RevertToBlockIndex(baseBlockIndex); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
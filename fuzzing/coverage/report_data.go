@@ -0,0 +1,207 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// HTMLReportLineState describes the coverage state of a source line in an HTML coverage report.
+type HTMLReportLineState string
+
+const (
+	// HTMLReportLineInactive describes a source line which has no source mapping, so it is never executed.
+	HTMLReportLineInactive HTMLReportLineState = "inactive"
+
+	// HTMLReportLineUncovered describes an active source line which was never executed.
+	HTMLReportLineUncovered HTMLReportLineState = "uncovered"
+
+	// HTMLReportLineCovered describes an active source line which was executed while fuzzing, whether or not it
+	// reverted.
+	HTMLReportLineCovered HTMLReportLineState = "covered"
+
+	// HTMLReportLineOutOfGas describes an active source line which was only executed in call frames which ran out of
+	// gas.
+	HTMLReportLineOutOfGas HTMLReportLineState = "outOfGas"
+
+	// HTMLReportLineSetupOnly describes an active source line which was only executed during contract deployment and
+	// chain setup, never while fuzzing.
+	HTMLReportLineSetupOnly HTMLReportLineState = "setupOnly"
+)
+
+// ReportMetadata describes the fuzzing campaign a coverage report was generated for.
+type ReportMetadata struct {
+	// GeneratedAt describes the time the report was generated.
+	GeneratedAt time.Time
+
+	// Seed describes the seed the fuzzer used to derive its random decisions.
+	Seed int64
+
+	// TargetContracts describes the names of the contracts targeted by the campaign.
+	TargetContracts []string
+
+	// SequencesTested describes the count of call sequences tested in the campaign.
+	SequencesTested uint64
+
+	// CallsTested describes the count of calls tested in the campaign.
+	CallsTested uint64
+}
+
+// HTMLReportData describes the data HTML coverage report templates are executed against. It is built from a
+// SourceAnalysis by WriteHTMLReport, and its fields are kept stable so custom templates continue to render as the
+// source analysis changes.
+type HTMLReportData struct {
+	// Metadata describes the fuzzing campaign the report was generated for.
+	Metadata ReportMetadata
+
+	// LineCount describes the count of lines across all source files, whether or not they are active.
+	LineCount int
+
+	// Totals describes the totals of the line coverage data across all source files.
+	Totals FileCoverageTotals
+
+	// Files describes the coverage data for each source file, ordered by path.
+	Files []HTMLReportFile
+}
+
+// HTMLReportFile describes the coverage data for a source file in an HTML coverage report.
+type HTMLReportFile struct {
+	// Path describes the path of the source file, as it was analyzed.
+	Path string
+
+	// RelativePath describes the path of the source file relative to the working directory, or Path if it cannot be
+	// made relative.
+	RelativePath string
+
+	// Totals describes the totals of the line coverage data for the source file.
+	Totals FileCoverageTotals
+
+	// MaxHitCount describes the greatest count of times any line within the source file was executed, whether or not
+	// it reverted.
+	MaxHitCount uint
+
+	// SourceUnavailable indicates whether the source code of the file could not be read, in which case it has no
+	// lines.
+	SourceUnavailable bool
+
+	// Lines describes the coverage data for every line of the source file, ordered by line number.
+	Lines []HTMLReportLine
+}
+
+// HTMLReportLine describes the coverage data for a source line in an HTML coverage report.
+type HTMLReportLine struct {
+	// Number describes the 1-based line number of the line.
+	Number int
+
+	// Contents describes the contents of the line, without its line ending.
+	Contents string
+
+	// State describes the coverage state of the line.
+	State HTMLReportLineState
+
+	// IsActive indicates whether the line has a source mapping, so it can be executed.
+	IsActive bool
+
+	// IsCovered indicates whether the line was executed without reverting.
+	IsCovered bool
+
+	// IsCoveredReverted indicates whether the line was executed before reverting.
+	IsCoveredReverted bool
+
+	// IsCoveredOutOfGas indicates whether the line was executed in a call frame which ran out of gas.
+	IsCoveredOutOfGas bool
+
+	// SuccessHitCount describes the count of times the line was executed without reverting.
+	SuccessHitCount uint
+
+	// RevertHitCount describes the count of times the line was executed before reverting.
+	RevertHitCount uint
+
+	// OutOfGasHitCount describes the count of times the line was executed in a call frame which ran out of gas.
+	OutOfGasHitCount uint
+
+	// HitCount describes the count of times the line was executed, whether or not it reverted.
+	HitCount uint
+
+	// HeatmapBucket describes the intensity bucket of the line in the heatmap view, relative to the most executed line
+	// in its source file. It is 0 if the line was never executed, otherwise between 1 and 5 (inclusive).
+	HeatmapBucket int
+}
+
+// reportRelativePath returns the provided path relative to the working directory, or the provided path if it cannot
+// be made relative.
+func reportRelativePath(path string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	relativePath, err := filepath.Rel(cwd, path)
+	if err != nil {
+		return path
+	}
+	return relativePath
+}
+
+// newHTMLReportData builds the data HTML coverage report templates are executed against from the provided source
+// analysis and campaign metadata.
+func newHTMLReportData(sourceAnalysis *SourceAnalysis, metadata ReportMetadata) *HTMLReportData {
+	data := &HTMLReportData{
+		Metadata: metadata,
+		Files:    make([]HTMLReportFile, 0, len(sourceAnalysis.Files)),
+	}
+	for _, sourceFile := range sourceAnalysis.SortedFiles() {
+		file := HTMLReportFile{
+			Path:              sourceFile.Path,
+			RelativePath:      reportRelativePath(sourceFile.Path),
+			MaxHitCount:       sourceFile.MaxHitCount(),
+			SourceUnavailable: sourceFile.SourceUnavailable,
+			Lines:             make([]HTMLReportLine, 0, len(sourceFile.Lines)),
+		}
+		for lineIndex, line := range sourceFile.Lines {
+			file.Lines = append(file.Lines, HTMLReportLine{
+				Number:            lineIndex + 1,
+				Contents:          strings.ReplaceAll(string(line.Contents), "\r", ""),
+				State:             htmlReportLineState(line),
+				IsActive:          line.IsActive,
+				IsCovered:         line.IsCovered,
+				IsCoveredReverted: line.IsCoveredReverted,
+				IsCoveredOutOfGas: line.IsCoveredOutOfGas,
+				SuccessHitCount:   line.SuccessHitCount,
+				RevertHitCount:    line.RevertHitCount,
+				OutOfGasHitCount:  line.OutOfGasHitCount,
+				HitCount:          line.HitCount(),
+				HeatmapBucket:     heatmapBucket(line.HitCount(), file.MaxHitCount),
+			})
+			if line.IsActive {
+				file.Totals.add(sourceFile, line)
+			}
+		}
+
+		// Update our totals across all files
+		data.LineCount += len(sourceFile.Lines)
+		data.Totals.Active += file.Totals.Active
+		data.Totals.Covered += file.Totals.Covered
+		data.Totals.RevertOnly += file.Totals.RevertOnly
+		data.Totals.OutOfGasOnly += file.Totals.OutOfGasOnly
+		data.Totals.SetupOnly += file.Totals.SetupOnly
+		data.Files = append(data.Files, file)
+	}
+	return data
+}
+
+// htmlReportLineState determines the coverage state of the provided source line in an HTML coverage report.
+func htmlReportLineState(line *SourceLineAnalysis) HTMLReportLineState {
+	switch {
+	case !line.IsActive:
+		return HTMLReportLineInactive
+	case line.IsCoveredSetupOnly:
+		return HTMLReportLineSetupOnly
+	case line.IsCovered || line.IsCoveredReverted:
+		return HTMLReportLineCovered
+	case line.IsCoveredOutOfGas:
+		return HTMLReportLineOutOfGas
+	default:
+		return HTMLReportLineUncovered
+	}
+}
@@ -3,14 +3,19 @@ package coverage
 import (
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"math"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/crytic/medusa/compilation/types"
 	"github.com/crytic/medusa/utils"
 )
 
@@ -19,88 +24,253 @@ var (
 	htmlReportTemplate []byte
 )
 
+// JSONCoverageReportVersion describes the version of the schema of the JSON coverage report. It is incremented
+// whenever the schema changes in a way which requires consumers to be updated. Reports prior to version 2 were a map of
+// source file paths to their line coverage data, and did not specify a version.
+const JSONCoverageReportVersion = 2
+
 // LineCoverageData represents coverage data for a specific line
 type LineCoverageData struct {
-	Line    int  `json:"line"`
-	Revert  uint `json:"revert"`
-	Success uint `json:"success"`
+	Line      int  `json:"line"`
+	Revert    uint `json:"revert"`
+	OutOfGas  uint `json:"outOfGas"`
+	Success   uint `json:"success"`
 	IsCovered bool `json:"isCovered"`
+	SetupOnly bool `json:"setupOnly"`
+}
+
+// FileCoverageTotals represents the totals of the line coverage data for a source file
+type FileCoverageTotals struct {
+	// Active describes the count of lines that are marked executable/active.
+	Active int `json:"active"`
+
+	// Covered describes the count of active lines that were executed, whether or not they reverted or ran out of gas.
+	// If lines only covered during setup are excluded from coverage, they are not counted.
+	Covered int `json:"covered"`
+
+	// RevertOnly describes the count of active lines that were only executed before reverting.
+	RevertOnly int `json:"revertOnly"`
+
+	// OutOfGasOnly describes the count of active lines that were only executed in call frames which ran out of gas.
+	OutOfGasOnly int `json:"outOfGasOnly"`
+
+	// SetupOnly describes the count of active lines that were only executed during contract deployment and chain
+	// setup, prior to fuzzing.
+	SetupOnly int `json:"setupOnly"`
+}
+
+// add counts the provided active line of the provided source file toward the totals.
+func (t *FileCoverageTotals) add(sourceFile *SourceFileAnalysis, line *SourceLineAnalysis) {
+	t.Active++
+	if sourceFile.isLineCounted(line) {
+		t.Covered++
+	}
+	if line.IsCoveredReverted && !line.IsCovered {
+		t.RevertOnly++
+	}
+	if line.IsCoveredOutOfGas && !line.IsCovered && !line.IsCoveredReverted {
+		t.OutOfGasOnly++
+	}
+	if line.IsCoveredSetupOnly {
+		t.SetupOnly++
+	}
+}
+
+// FileCoverageData represents coverage data for a specific source file
+type FileCoverageData struct {
+	// Path describes the normalized path of the source file.
+	Path string `json:"path"`
+
+	// Totals describes the totals of the line coverage data for the source file.
+	Totals FileCoverageTotals `json:"totals"`
+
+	// Lines describes the coverage data for each active line in the source file, ordered by line number.
+	Lines []LineCoverageData `json:"lines"`
+
+	// SourceUnavailable indicates whether the source code of the file could not be read, in which case no line
+	// coverage data is reported for it.
+	SourceUnavailable bool `json:"sourceUnavailable,omitempty"`
 }
 
 // CoverageReport represents the overall coverage report data structure
-type CoverageReport map[string][]LineCoverageData
+type CoverageReport struct {
+	// Version describes the version of the schema of the report, as defined by JSONCoverageReportVersion.
+	Version int `json:"version"`
+
+	// Files describes the coverage data for each source file, ordered by normalized path.
+	Files []FileCoverageData `json:"files"`
+}
+
+// normalizeReportPath normalizes a source file path for display in a coverage report, so that reports generated on
+// different machines are comparable. If a base path is provided, the source file path is made relative to it where
+// possible. Path separators are always forward slashes.
+func normalizeReportPath(path string, basePath string) string {
+	if basePath != "" {
+		absolutePath, pathErr := filepath.Abs(path)
+		absoluteBasePath, basePathErr := filepath.Abs(basePath)
+		if pathErr == nil && basePathErr == nil {
+			if relativePath, err := filepath.Rel(absoluteBasePath, absolutePath); err == nil {
+				path = relativePath
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
 
-// GenerateJSONCoverageData takes a source analysis and generates JSON coverage data
-func GenerateJSONCoverageData(sourceAnalysis *SourceAnalysis) ([]byte, error) {
-	report := make(CoverageReport)
+// GenerateJSONCoverageData takes a source analysis and generates JSON coverage data. Source file paths are normalized
+// relative to the provided base path, if it is non-empty. The output is deterministic for a given source analysis.
+func GenerateJSONCoverageData(sourceAnalysis *SourceAnalysis, basePath string) ([]byte, error) {
+	// Marshal the report data into JSON
+	return json.MarshalIndent(NewCoverageReport(sourceAnalysis, basePath), "", "  ")
+}
+
+// NewCoverageReport takes a source analysis and creates the coverage report data which is written as the JSON coverage
+// report. Source file paths are normalized relative to the provided base path, if it is non-empty.
+func NewCoverageReport(sourceAnalysis *SourceAnalysis, basePath string) CoverageReport {
+	report := CoverageReport{
+		Version: JSONCoverageReportVersion,
+		Files:   make([]FileCoverageData, 0, len(sourceAnalysis.Files)),
+	}
 
 	for _, sourceFile := range sourceAnalysis.SortedFiles() {
-		lineCoverageData := make([]LineCoverageData, 0)
+		fileCoverageData := FileCoverageData{
+			Path:              normalizeReportPath(sourceFile.Path, basePath),
+			Lines:             make([]LineCoverageData, 0),
+			SourceUnavailable: sourceFile.SourceUnavailable,
+		}
 
 		for lineIndex, line := range sourceFile.Lines {
 			// Only include active lines that have coverage information
 			if line.IsActive {
 				lineData := LineCoverageData{
-					Line:    lineIndex + 1, // Convert to 1-based line number
-					Revert:  line.RevertHitCount,
-					Success: line.SuccessHitCount,
-					IsCovered: line.IsCovered || line.IsCoveredReverted,
+					Line:      lineIndex + 1, // Convert to 1-based line number
+					Revert:    line.RevertHitCount,
+					OutOfGas:  line.OutOfGasHitCount,
+					Success:   line.SuccessHitCount,
+					IsCovered: line.IsCovered || line.IsCoveredReverted || line.IsCoveredOutOfGas,
+					SetupOnly: line.IsCoveredSetupOnly,
 				}
-				lineCoverageData = append(lineCoverageData, lineData)
+				fileCoverageData.Lines = append(fileCoverageData.Lines, lineData)
+
+				// Update our totals
+				fileCoverageData.Totals.add(sourceFile, line)
 			}
 		}
 
-		report[sourceFile.Path] = lineCoverageData
+		report.Files = append(report.Files, fileCoverageData)
 	}
 
-	// Marshal the data into JSON
-	return json.MarshalIndent(report, "", "  ")
+	// Sort our files by their normalized path, falling back to the original path order if two paths normalize to
+	// the same value.
+	sort.SliceStable(report.Files, func(i, j int) bool {
+		return report.Files[i].Path < report.Files[j].Path
+	})
+	return report
 }
 
-// WriteHTMLReport takes a previously performed source analysis and generates an HTML coverage report from it.
-func WriteHTMLReport(sourceAnalysis *SourceAnalysis, reportDir string) (string, error) {
-	// Define mappings onto some useful variables/functions.
-	functionMap := template.FuncMap{
-		"timeNow": time.Now,
-		"add": func(x int, y int) int {
-			return x + y
-		},
-		"relativePath": func(path string) string {
-			// Obtain a path relative to our current working directory.
-			// If we encounter an error, return the original path.
-			cwd, err := os.Getwd()
-			if err != nil {
-				return path
-			}
-			relativePath, err := filepath.Rel(cwd, path)
-			if err != nil {
-				return path
-			}
+// heatmapBucketCount describes the count of intensity buckets lines are assigned to in the HTML report's heatmap view,
+// excluding the bucket for lines which were never executed.
+const heatmapBucketCount = 5
+
+// heatmapBucket buckets the provided hit count of a line for display in the HTML report's heatmap view, relative to
+// the greatest hit count of any line in the same file. Buckets are logarithmically scaled, as hit counts commonly span
+// many orders of magnitude.
+// Returns 0 if the line was never executed, otherwise a bucket between 1 and heatmapBucketCount (inclusive).
+func heatmapBucket(hitCount uint, maxHitCount uint) int {
+	if hitCount == 0 || maxHitCount == 0 {
+		return 0
+	}
+	if hitCount >= maxHitCount {
+		return heatmapBucketCount
+	}
+	ratio := math.Log1p(float64(hitCount)) / math.Log1p(float64(maxHitCount))
+	return utils.Max(1, int(math.Ceil(ratio*heatmapBucketCount)))
+}
 
-			return relativePath
-		},
-		"percentageStr": func(x int, y int, decimals int) string {
-			// Determine our precision string
-			formatStr := "%." + strconv.Itoa(decimals) + "f"
+// formatHitCount formats the provided hit count for compact display in the HTML report, abbreviating large counts with
+// a metric suffix (e.g. 1234 is formatted as "1.2K").
+func formatHitCount(hitCount uint) string {
+	if hitCount < 1000 {
+		return strconv.FormatUint(uint64(hitCount), 10)
+	}
+	value := float64(hitCount)
+	suffixes := []string{"K", "M", "B", "T"}
+	suffixIndex := -1
+	for value >= 1000 && suffixIndex < len(suffixes)-1 {
+		value /= 1000
+		suffixIndex++
+	}
 
-			// If no lines are active and none are covered, show 0% coverage
-			if x == 0 && y == 0 {
-				return fmt.Sprintf(formatStr, float64(0))
-			}
-			return fmt.Sprintf(formatStr, (float64(x)/float64(y))*100)
-		},
-		"percentageInt": func(x int, y int) int {
-			if y == 0 {
-				return 100
-			}
-			return int(math.Round(float64(x) / float64(y) * 100))
-		},
+	// Truncate rather than round the value, so a count is never displayed as greater than it is.
+	formatted := strconv.FormatFloat(math.Floor(value*10)/10, 'f', 1, 64)
+	return strings.TrimSuffix(formatted, ".0") + suffixes[suffixIndex]
+}
+
+// htmlReportFunctions describes the functions available to HTML coverage report templates, including custom ones.
+var htmlReportFunctions = template.FuncMap{
+	"timeNow": time.Now,
+	"add": func(x int, y int) int {
+		return x + y
+	},
+	"relativePath":   reportRelativePath,
+	"heatmapBucket":  heatmapBucket,
+	"formatHitCount": formatHitCount,
+	"lineContents": func(contents []byte) string {
+		// Remove any carriage returns remaining in the line, so they are not rendered.
+		return strings.ReplaceAll(string(contents), "\r", "")
+	},
+	"percentageStr": func(x int, y int, decimals int) string {
+		// Determine our precision string
+		formatStr := "%." + strconv.Itoa(decimals) + "f"
+
+		// If no lines are active and none are covered, show 0% coverage
+		if x == 0 && y == 0 {
+			return fmt.Sprintf(formatStr, float64(0))
+		}
+		return fmt.Sprintf(formatStr, (float64(x)/float64(y))*100)
+	},
+	"percentageInt": func(x int, y int) int {
+		if y == 0 {
+			return 100
+		}
+		return int(math.Round(float64(x) / float64(y) * 100))
+	},
+}
+
+// ParseHTMLReportTemplate parses the HTML coverage report template at the provided path, with the functions available
+// to the default template. If the path is empty, the default template is parsed. Templates are executed against
+// HTMLReportData.
+// Returns the parsed template, or an error describing why the template could not be read or parsed.
+func ParseHTMLReportTemplate(templatePath string) (*template.Template, error) {
+	// Parse our default template if no custom template was provided.
+	if templatePath == "" {
+		tmpl, err := template.New("coverage_report.html").Funcs(htmlReportFunctions).Parse(string(htmlReportTemplate))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse report template: %v", err)
+		}
+		return tmpl, nil
 	}
 
+	// Otherwise, read and parse our custom template.
+	templateBytes, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read custom HTML report template '%s': %v", templatePath, err)
+	}
+	tmpl, err := template.New(filepath.Base(templatePath)).Funcs(htmlReportFunctions).Parse(string(templateBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse custom HTML report template '%s': %v", templatePath, err)
+	}
+	return tmpl, nil
+}
+
+// WriteHTMLReport takes a previously performed source analysis and generates an HTML coverage report from it, using
+// the template at the provided path, or the default template if the path is empty. The template is executed against
+// HTMLReportData built from the source analysis and the provided campaign metadata.
+func WriteHTMLReport(sourceAnalysis *SourceAnalysis, reportDir string, templatePath string, metadata ReportMetadata) (string, error) {
 	// Parse our HTML template
-	tmpl, err := template.New("coverage_report.html").Funcs(functionMap).Parse(string(htmlReportTemplate))
+	tmpl, err := ParseHTMLReportTemplate(templatePath)
 	if err != nil {
-		return "", fmt.Errorf("could not export report, failed to parse report template: %v", err)
+		return "", fmt.Errorf("could not export report, %v", err)
 	}
 
 	// If the directory doesn't exist, create it.
@@ -117,8 +287,14 @@ func WriteHTMLReport(sourceAnalysis *SourceAnalysis, reportDir string) (string,
 		return "", fmt.Errorf("could not export report, failed to open file for writing: %v", err)
 	}
 
-	// Execute the template and write it back to file.
-	err = tmpl.Execute(file, sourceAnalysis)
+	// Execute the template against our report data and write it back to file.
+	if metadata.GeneratedAt.IsZero() {
+		metadata.GeneratedAt = time.Now()
+	}
+	err = tmpl.Execute(file, newHTMLReportData(sourceAnalysis, metadata))
+	if err != nil {
+		err = fmt.Errorf("could not export report, failed to execute report template '%s': %v", tmpl.Name(), err)
+	}
 	fileCloseErr := file.Close()
 	if err == nil {
 		err = fileCloseErr
@@ -147,10 +323,11 @@ func WriteLCOVReport(sourceAnalysis *SourceAnalysis, reportDir string) (string,
 	return lcovReportPath, nil
 }
 
-// WriteJSONCoverageData writes the JSON coverage data to a file
-func WriteJSONCoverageData(sourceAnalysis *SourceAnalysis, reportDir string) (string, error) {
+// WriteJSONCoverageData writes the JSON coverage data to a file, with source file paths normalized relative to the
+// provided base path, if it is non-empty.
+func WriteJSONCoverageData(sourceAnalysis *SourceAnalysis, reportDir string, basePath string) (string, error) {
 	// Generate the JSON coverage data
-	jsonData, err := GenerateJSONCoverageData(sourceAnalysis)
+	jsonData, err := GenerateJSONCoverageData(sourceAnalysis, basePath)
 	if err != nil {
 		return "", fmt.Errorf("could not generate JSON coverage data: %v", err)
 	}
@@ -171,3 +348,118 @@ func WriteJSONCoverageData(sourceAnalysis *SourceAnalysis, reportDir string) (st
 
 	return jsonReportPath, nil
 }
+
+// ReportOptions describes the options used to generate coverage reports with GenerateReports.
+type ReportOptions struct {
+	// Formats describes the coverage report formats to generate: "html", "lcov", and "json" are supported.
+	Formats []string
+
+	// ReportDir describes the directory the coverage reports are written to.
+	ReportDir string
+
+	// FuzzingCoverageMaps describes the coverage maps achieved while fuzzing, excluding coverage achieved while
+	// deploying contracts and setting up the chain. If non-nil, lines which were covered by the coverage maps provided
+	// to GenerateReports but not by these maps are marked as only covered during setup.
+	FuzzingCoverageMaps *CoverageMaps
+
+	// ExcludeSetupOnly describes whether lines which were only covered during setup are excluded from covered line
+	// counts. This has no effect if FuzzingCoverageMaps is nil.
+	ExcludeSetupOnly bool
+
+	// BasePath describes the path which source file paths are made relative to in the JSON coverage report. If empty,
+	// source file paths are not made relative.
+	BasePath string
+
+	// ExcludePaths describes source file paths, directories, or glob patterns, relative to the working directory,
+	// whose source files are omitted from the coverage reports.
+	ExcludePaths []string
+
+	// HTMLTemplatePath describes the path of a custom template the HTML coverage report is rendered with. If empty,
+	// the default template is used.
+	HTMLTemplatePath string
+
+	// Metadata describes the fuzzing campaign the coverage reports are generated for, which is made available to the
+	// HTML coverage report template.
+	Metadata ReportMetadata
+}
+
+// isExcludedSourcePath determines whether a source file path is excluded by any of the provided exclusion paths. An
+// exclusion path excludes a source file if it matches the source file path, or any of its parent directories, relative
+// to the working directory. Exclusion paths may be glob patterns, as supported by path.Match.
+func isExcludedSourcePath(sourcePath string, excludePaths []string) bool {
+	if len(excludePaths) == 0 {
+		return false
+	}
+
+	// Check the source file path and each of its parent directories against our exclusion paths.
+	pathComponents := strings.Split(normalizeReportPath(sourcePath, "."), "/")
+	for i := range pathComponents {
+		candidatePath := strings.Join(pathComponents[:i+1], "/")
+		for _, excludePath := range excludePaths {
+			excludePath = filepath.ToSlash(filepath.Clean(excludePath))
+			if matched, err := path.Match(excludePath, candidatePath); err == nil && matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ExcludeSourceFiles returns a SourceAnalysis containing only the source files of the provided analysis which are not
+// excluded by any of the provided exclusion paths. The provided analysis is not modified.
+func ExcludeSourceFiles(sourceAnalysis *SourceAnalysis, excludePaths []string) *SourceAnalysis {
+	if len(excludePaths) == 0 {
+		return sourceAnalysis
+	}
+	filteredAnalysis := &SourceAnalysis{Files: make(map[string]*SourceFileAnalysis, len(sourceAnalysis.Files))}
+	for sourcePath, sourceFile := range sourceAnalysis.Files {
+		if !isExcludedSourcePath(sourcePath, excludePaths) {
+			filteredAnalysis.Files[sourcePath] = sourceFile
+		}
+	}
+	return filteredAnalysis
+}
+
+// GenerateReports analyzes the source coverage achieved by the provided coverage maps against the provided
+// compilations, and writes a coverage report for each format specified in the provided options. This allows reports to
+// be generated from any coverage map snapshot, such that reports for different snapshots can be compared.
+// Returns the paths of the reports which were written, and an error joining any errors encountered. A report which
+// fails to be written does not prevent the remaining reports from being written.
+func GenerateReports(compilations []types.Compilation, coverageMaps *CoverageMaps, options ReportOptions) ([]string, error) {
+	// Analyze our source coverage, marking setup-only coverage if we were provided fuzzing coverage maps.
+	var sourceAnalysis *SourceAnalysis
+	var err error
+	if options.FuzzingCoverageMaps != nil {
+		sourceAnalysis, err = AnalyzeSourceCoverageWithSetup(compilations, coverageMaps, options.FuzzingCoverageMaps, options.ExcludeSetupOnly)
+	} else {
+		sourceAnalysis, err = AnalyzeSourceCoverage(compilations, coverageMaps)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not analyze source coverage: %v", err)
+	}
+	sourceAnalysis = ExcludeSourceFiles(sourceAnalysis, options.ExcludePaths)
+
+	// Write each of our reports, collecting the paths written and any errors encountered.
+	reportPaths := make([]string, 0, len(options.Formats))
+	var reportErrs []error
+	for _, format := range options.Formats {
+		var reportPath string
+		switch format {
+		case "html":
+			reportPath, err = WriteHTMLReport(sourceAnalysis, options.ReportDir, options.HTMLTemplatePath, options.Metadata)
+		case "lcov":
+			reportPath, err = WriteLCOVReport(sourceAnalysis, options.ReportDir)
+		case "json":
+			reportPath, err = WriteJSONCoverageData(sourceAnalysis, options.ReportDir, options.BasePath)
+		default:
+			err = fmt.Errorf("unsupported coverage report type: %s", format)
+		}
+		if err != nil {
+			reportErrs = append(reportErrs, fmt.Errorf("failed to generate %s coverage report: %w", format, err))
+			continue
+		}
+		reportPaths = append(reportPaths, reportPath)
+	}
+
+	return reportPaths, errors.Join(reportErrs...)
+}
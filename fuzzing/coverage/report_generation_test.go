@@ -0,0 +1,427 @@
+package coverage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/crytic/medusa/compilation/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// newJSONReportFixture creates a SourceAnalysis with multiple source files within a "contracts" directory, whose paths
+// are not in sorted order once normalized relative to that directory. One file has a line only covered during setup,
+// which is excluded from its covered line count, and the other has a line only executed in calls which ran out of gas.
+func newJSONReportFixture() *SourceAnalysis {
+	sourceAnalysis := newSourceAnalysisFixture()
+	vaultFile := sourceAnalysis.Files["vault.sol"]
+	vaultFile.Path = filepath.Join("contracts", "vault.sol")
+	vaultFile.Lines[10].IsCoveredOutOfGas, vaultFile.Lines[10].OutOfGasHitCount = true, 4
+
+	tokenLines, tokenOffsets := parseSourceLines([]byte("contract Token {\n    function mint() public {}\n}\n"))
+	tokenLines[1].IsActive = true
+	tokenLines[1].IsCovered, tokenLines[1].IsCoveredReverted = true, true
+	tokenLines[1].SuccessHitCount, tokenLines[1].RevertHitCount = 3, 1
+	tokenLines[1].IsCoveredSetupOnly = true
+	tokenFile := &SourceFileAnalysis{
+		Path:                     filepath.Join("contracts", "lib", "token.sol"),
+		CumulativeOffsetByLine:   tokenOffsets,
+		Lines:                    tokenLines,
+		ExcludeSetupOnlyCoverage: true,
+	}
+
+	sourceAnalysis.Files = map[string]*SourceFileAnalysis{
+		vaultFile.Path: vaultFile,
+		tokenFile.Path: tokenFile,
+	}
+	return sourceAnalysis
+}
+
+// TestGenerateJSONCoverageDataGolden tests that the JSON coverage report matches the expected report byte-for-byte,
+// and that generating it repeatedly produces identical output.
+func TestGenerateJSONCoverageDataGolden(t *testing.T) {
+	expected, err := os.ReadFile(filepath.Join("testdata", "coverage_report.golden.json"))
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		jsonData, err := GenerateJSONCoverageData(newJSONReportFixture(), "contracts")
+		assert.NoError(t, err)
+		assert.Equal(t, string(expected), string(jsonData))
+	}
+}
+
+// TestGenerateJSONCoverageDataPaths tests that source file paths are normalized relative to the base path if one is
+// provided, and are otherwise reported as-is.
+func TestGenerateJSONCoverageDataPaths(t *testing.T) {
+	assert.EqualValues(t, "lib/token.sol", normalizeReportPath(filepath.Join("contracts", "lib", "token.sol"), "contracts"))
+	assert.EqualValues(t, "contracts/lib/token.sol", normalizeReportPath(filepath.Join("contracts", "lib", "token.sol"), ""))
+	assert.EqualValues(t, "../vault.sol", normalizeReportPath("vault.sol", "contracts"))
+}
+
+// TestHeatmapBucket tests that line hit counts are bucketed logarithmically relative to the most executed line.
+func TestHeatmapBucket(t *testing.T) {
+	assert.EqualValues(t, 0, heatmapBucket(0, 1000))
+	assert.EqualValues(t, 0, heatmapBucket(0, 0))
+	assert.EqualValues(t, 1, heatmapBucket(1, 1000000))
+	assert.EqualValues(t, 3, heatmapBucket(1000, 1000000))
+	assert.EqualValues(t, 5, heatmapBucket(999999, 1000000))
+	assert.EqualValues(t, heatmapBucketCount, heatmapBucket(1000000, 1000000))
+	assert.EqualValues(t, heatmapBucketCount, heatmapBucket(1, 1))
+}
+
+// TestFormatHitCount tests that large hit counts are abbreviated without being rounded up.
+func TestFormatHitCount(t *testing.T) {
+	assert.EqualValues(t, "0", formatHitCount(0))
+	assert.EqualValues(t, "999", formatHitCount(999))
+	assert.EqualValues(t, "1K", formatHitCount(1000))
+	assert.EqualValues(t, "1.2K", formatHitCount(1299))
+	assert.EqualValues(t, "999.9K", formatHitCount(999999))
+	assert.EqualValues(t, "3M", formatHitCount(3000000))
+	assert.EqualValues(t, "4.2B", formatHitCount(4200000000))
+}
+
+// TestWriteHTMLReportHitCounts tests that the HTML report renders the hit counts of each line, along with the heatmap
+// bucket of each executed line.
+func TestWriteHTMLReportHitCounts(t *testing.T) {
+	sourceAnalysis := newSourceAnalysisFixture()
+	sourceAnalysis.Files["vault.sol"].Lines[3].SuccessHitCount = 1500000
+
+	reportPath, err := WriteHTMLReport(sourceAnalysis, t.TempDir(), "", ReportMetadata{})
+	assert.NoError(t, err)
+	reportBytes, err := os.ReadFile(reportPath)
+	assert.NoError(t, err)
+	report := string(reportBytes)
+
+	// The most executed line is in the top bucket, while the line which only executed once before reverting is in
+	// the lowest bucket, and the line which never executed has no bucket.
+	assert.Contains(t, report, "√ 1.5M")
+	assert.Contains(t, report, "without reverting (1500000 hits)")
+	assert.Contains(t, report, "⟳ 1")
+	assert.Contains(t, report, "heat-bucket-5\">        balance &#43;= 1;")
+	assert.Contains(t, report, "heat-bucket-1\">        balance = 0;")
+	assert.Contains(t, report, "heat-bucket-0\">    return 1;")
+	assert.Equal(t, 1, strings.Count(report, "toggleHeatmapView(this)"))
+}
+
+// TestWriteHTMLReportCustomTemplate tests that the HTML report can be rendered with a custom template, which is
+// executed against the report data built from the source analysis, and the provided campaign metadata.
+func TestWriteHTMLReportCustomTemplate(t *testing.T) {
+	templatePath := filepath.Join(t.TempDir(), "custom.gohtml")
+	customTemplate := `seed={{.Metadata.Seed}} targets={{range .Metadata.TargetContracts}}{{.}}{{end}} covered={{.Totals.Covered}}/{{.Totals.Active}}
+{{range .Files}}{{.Path}} max={{formatHitCount .MaxHitCount}}
+{{range .Lines}}{{if .IsActive}}{{.Number}}:{{.State}}:{{.HitCount}}:{{.HeatmapBucket}}
+{{end}}{{end}}{{end}}`
+	assert.NoError(t, os.WriteFile(templatePath, []byte(customTemplate), 0644))
+
+	metadata := ReportMetadata{Seed: 7, TargetContracts: []string{"Vault"}}
+	reportPath, err := WriteHTMLReport(newSourceAnalysisFixture(), t.TempDir(), templatePath, metadata)
+	assert.NoError(t, err)
+	reportBytes, err := os.ReadFile(reportPath)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "seed=7 targets=Vault covered=2/3\nvault.sol max=2\n4:covered:2:5\n7:covered:1:4\n11:uncovered:0:0\n", string(reportBytes))
+}
+
+// TestWriteHTMLReportInvalidTemplate tests that an HTML report is not written with a custom template which is missing,
+// cannot be parsed, or cannot be executed against the report data, and that the error names the problem.
+func TestWriteHTMLReportInvalidTemplate(t *testing.T) {
+	templateDir := t.TempDir()
+	testCases := []struct {
+		name     string
+		template string
+		errorMsg string
+	}{
+		{name: "missing.gohtml", errorMsg: "failed to read custom HTML report template"},
+		{name: "unparsable.gohtml", template: ("{{range .Files}}"), errorMsg: "failed to parse custom HTML report template"},
+		{name: "unknown_function.gohtml", template: ("{{lineCount .}}"), errorMsg: "function \"lineCount\" not defined"},
+		{name: "unknown_field.gohtml", template: ("{{.SortedFiles}}"), errorMsg: "can't evaluate field SortedFiles"},
+	}
+	for _, testCase := range testCases {
+		templatePath := filepath.Join(templateDir, testCase.name)
+		if testCase.template != "" {
+			assert.NoError(t, os.WriteFile(templatePath, []byte(testCase.template), 0644))
+		}
+
+		_, err := WriteHTMLReport(newSourceAnalysisFixture(), t.TempDir(), templatePath, ReportMetadata{})
+		assert.ErrorContains(t, err, testCase.errorMsg, testCase.name)
+		assert.ErrorContains(t, err, testCase.name, testCase.name)
+	}
+}
+
+// reportsFixtureSourcePath describes the source file path of the compilation created by newReportsFixtureCompilation.
+var reportsFixtureSourcePath = filepath.Join("contracts", "counter.sol")
+
+// reportsFixtureRuntimeBytecode describes the runtime bytecode of the contract in the compilation created by
+// newReportsFixtureCompilation. It consists of two JUMPDEST instructions, at program counters 0 and 1.
+var reportsFixtureRuntimeBytecode = []byte{0x5b, 0x5b}
+
+// newReportsFixtureCompilation creates a compilation with a single source file and contract, whose runtime bytecode
+// maps the instruction at program counter 0 to the second line of the source file, and the instruction at program
+// counter 1 to the third line.
+func newReportsFixtureCompilation() types.Compilation {
+	compilation := types.NewCompilation()
+	compilation.SourcePathToArtifact[reportsFixtureSourcePath] = types.SourceArtifact{
+		Ast: map[string]any{"nodeType": "SourceUnit", "nodes": []any{}},
+		Contracts: map[string]types.CompiledContract{
+			"Counter": {
+				RuntimeBytecode: reportsFixtureRuntimeBytecode,
+				SrcMapsRuntime:  "20:5:0:-;47:5:0:-",
+				Kind:            types.ContractKindContract,
+			},
+		},
+	}
+	compilation.SourceIdToPath[0] = reportsFixtureSourcePath
+	compilation.SourceCode[reportsFixtureSourcePath] = []byte("contract Counter {\n    function a() public {}\n    function b() public {}\n}\n")
+	return *compilation
+}
+
+// newReportsFixtureCoverageMaps creates coverage maps for the contract in the compilation created by
+// newReportsFixtureCompilation, in which the provided program counters were covered once.
+func newReportsFixtureCoverageMaps(t *testing.T, pcs ...uint64) *CoverageMaps {
+	coverageMaps := NewCoverageMaps()
+	codeHash := GetContractCoverageMapHash(reportsFixtureRuntimeBytecode, false)
+	for _, pc := range pcs {
+		_, err := coverageMaps.UpdateAt(common.Address{}, codeHash, len(reportsFixtureRuntimeBytecode), pc)
+		assert.NoError(t, err)
+	}
+	return coverageMaps
+}
+
+// TestGenerateReports tests that GenerateReports writes reports reflecting the coverage maps it was provided, such
+// that reports generated for different coverage map snapshots of the same compilations can be compared.
+func TestGenerateReports(t *testing.T) {
+	compilations := []types.Compilation{newReportsFixtureCompilation()}
+
+	// Generate reports for two snapshots, each of which covered a different line.
+	snapshots := []struct {
+		pc           uint64
+		expectedLCOV string
+	}{
+		{pc: 0, expectedLCOV: "DA:2,1\nDA:3,0\n"},
+		{pc: 1, expectedLCOV: "DA:2,0\nDA:3,1\n"},
+	}
+	for _, snapshot := range snapshots {
+		reportDir := t.TempDir()
+		reportPaths, err := GenerateReports(compilations, newReportsFixtureCoverageMaps(t, snapshot.pc), ReportOptions{
+			Formats:   []string{"html", "lcov", "json"},
+			ReportDir: reportDir,
+			BasePath:  "contracts",
+		})
+		assert.NoError(t, err)
+		assert.EqualValues(t, []string{
+			filepath.Join(reportDir, "coverage_report.html"),
+			filepath.Join(reportDir, "lcov.info"),
+			filepath.Join(reportDir, "coverage.json"),
+		}, reportPaths)
+
+		// The LCOV report should only mark the covered line as hit.
+		lcovBytes, err := os.ReadFile(reportPaths[1])
+		assert.NoError(t, err)
+		assert.Contains(t, string(lcovBytes), snapshot.expectedLCOV)
+
+		// The JSON report should normalize the source path relative to the base path, and only cover one line.
+		jsonBytes, err := os.ReadFile(reportPaths[2])
+		assert.NoError(t, err)
+		var report CoverageReport
+		assert.NoError(t, json.Unmarshal(jsonBytes, &report))
+		assert.Len(t, report.Files, 1)
+		assert.EqualValues(t, "counter.sol", report.Files[0].Path)
+		assert.EqualValues(t, FileCoverageTotals{Active: 2, Covered: 1}, report.Files[0].Totals)
+		assert.EqualValues(t, snapshot.pc+2, report.Files[0].Lines[snapshot.pc].Line)
+		assert.True(t, report.Files[0].Lines[snapshot.pc].IsCovered)
+	}
+}
+
+// TestGenerateReportsSetupOnly tests that GenerateReports marks lines which were not covered by the provided fuzzing
+// coverage maps as only covered during setup, and excludes them from covered line counts if requested.
+func TestGenerateReportsSetupOnly(t *testing.T) {
+	compilations := []types.Compilation{newReportsFixtureCompilation()}
+	reportDir := t.TempDir()
+	reportPaths, err := GenerateReports(compilations, newReportsFixtureCoverageMaps(t, 0, 1), ReportOptions{
+		Formats:             []string{"json"},
+		ReportDir:           reportDir,
+		FuzzingCoverageMaps: newReportsFixtureCoverageMaps(t, 1),
+		ExcludeSetupOnly:    true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, reportPaths, 1)
+
+	jsonBytes, err := os.ReadFile(reportPaths[0])
+	assert.NoError(t, err)
+	var report CoverageReport
+	assert.NoError(t, json.Unmarshal(jsonBytes, &report))
+	assert.Len(t, report.Files, 1)
+	assert.EqualValues(t, FileCoverageTotals{Active: 2, Covered: 1, SetupOnly: 1}, report.Files[0].Totals)
+	assert.True(t, report.Files[0].Lines[0].SetupOnly)
+	assert.False(t, report.Files[0].Lines[1].SetupOnly)
+}
+
+// TestGenerateReportsOutOfGas tests that GenerateReports classifies lines which were only executed in call frames which
+// ran out of gas distinctly from successful and reverted lines, and that the LCOV report does not consider them hit.
+func TestGenerateReportsOutOfGas(t *testing.T) {
+	// Cover the first line in a call frame which ran out of gas, and the second line successfully.
+	compilations := []types.Compilation{newReportsFixtureCompilation()}
+	coverageMaps := newReportsFixtureCoverageMaps(t, 0)
+	_, err := coverageMaps.OutOfGasAll()
+	assert.NoError(t, err)
+	_, _, err = coverageMaps.Update(newReportsFixtureCoverageMaps(t, 1))
+	assert.NoError(t, err)
+
+	// The source analysis should only mark the first line as out-of-gas coverage.
+	sourceAnalysis, err := AnalyzeSourceCoverage(compilations, coverageMaps)
+	assert.NoError(t, err)
+	outOfGasLine := sourceAnalysis.File(reportsFixtureSourcePath).Line(2)
+	assert.True(t, outOfGasLine.IsCoveredOutOfGas)
+	assert.False(t, outOfGasLine.IsCovered)
+	assert.False(t, outOfGasLine.IsCoveredReverted)
+	assert.EqualValues(t, 1, outOfGasLine.OutOfGasHitCount)
+	assert.EqualValues(t, 1, outOfGasLine.HitCount())
+	successfulLine := sourceAnalysis.File(reportsFixtureSourcePath).Line(3)
+	assert.True(t, successfulLine.IsCovered)
+	assert.False(t, successfulLine.IsCoveredOutOfGas)
+
+	reportDir := t.TempDir()
+	reportPaths, err := GenerateReports(compilations, coverageMaps, ReportOptions{
+		Formats:   []string{"html", "lcov", "json"},
+		ReportDir: reportDir,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, reportPaths, 3)
+
+	// The HTML report should display the out-of-gas hit count, and highlight the out-of-gas line.
+	htmlBytes, err := os.ReadFile(reportPaths[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(htmlBytes), "⛽ 1")
+	assert.Contains(t, string(htmlBytes), "row-line-out-of-gas heat-bucket-5\" title=\"The source line was only executed in calls which ran out of gas.\">    function a() public {}")
+
+	// The LCOV report should only consider successfully executed lines hit.
+	lcovBytes, err := os.ReadFile(reportPaths[1])
+	assert.NoError(t, err)
+	assert.Contains(t, string(lcovBytes), "DA:2,0\nDA:3,1\n")
+
+	// The JSON report should count the out-of-gas line as covered, but only by out-of-gas calls.
+	jsonBytes, err := os.ReadFile(reportPaths[2])
+	assert.NoError(t, err)
+	var report CoverageReport
+	assert.NoError(t, json.Unmarshal(jsonBytes, &report))
+	assert.Len(t, report.Files, 1)
+	assert.EqualValues(t, FileCoverageTotals{Active: 2, Covered: 2, OutOfGasOnly: 1}, report.Files[0].Totals)
+	assert.EqualValues(t, LineCoverageData{Line: 2, OutOfGas: 1, IsCovered: true}, report.Files[0].Lines[0])
+}
+
+// TestGenerateReportsUnsupportedFormat tests that GenerateReports still writes the reports of supported formats when
+// an unsupported format is requested, returning an error describing the unsupported format.
+func TestGenerateReportsUnsupportedFormat(t *testing.T) {
+	compilations := []types.Compilation{newReportsFixtureCompilation()}
+	reportDir := t.TempDir()
+	reportPaths, err := GenerateReports(compilations, newReportsFixtureCoverageMaps(t, 0), ReportOptions{
+		Formats:   []string{"xml", "lcov"},
+		ReportDir: reportDir,
+	})
+	assert.ErrorContains(t, err, "unsupported coverage report type: xml")
+	assert.EqualValues(t, []string{filepath.Join(reportDir, "lcov.info")}, reportPaths)
+}
+
+// TestExcludeSourceFiles tests that source files are excluded by exclusion paths matching them or their parent
+// directories, including glob patterns, without modifying the provided source analysis.
+func TestExcludeSourceFiles(t *testing.T) {
+	sourceAnalysis := newJSONReportFixture()
+	cases := map[string][]string{
+		"contracts/lib":             {filepath.Join("contracts", "vault.sol")},
+		"contracts/lib/":            {filepath.Join("contracts", "vault.sol")},
+		"./contracts/vault.sol":     {filepath.Join("contracts", "lib", "token.sol")},
+		"contracts/*.sol":           {filepath.Join("contracts", "lib", "token.sol")},
+		"contracts/l?b/*":           {filepath.Join("contracts", "vault.sol")},
+		"contracts":                 {},
+		"contracts/vault":           {filepath.Join("contracts", "lib", "token.sol"), filepath.Join("contracts", "vault.sol")},
+		"other/contracts/vault.sol": {filepath.Join("contracts", "lib", "token.sol"), filepath.Join("contracts", "vault.sol")},
+	}
+	for excludePath, expectedPaths := range cases {
+		filteredAnalysis := ExcludeSourceFiles(sourceAnalysis, []string{excludePath})
+		filteredPaths := make([]string, 0)
+		for _, file := range filteredAnalysis.SortedFiles() {
+			filteredPaths = append(filteredPaths, file.Path)
+		}
+		assert.EqualValues(t, expectedPaths, filteredPaths, excludePath)
+	}
+	assert.Len(t, sourceAnalysis.Files, 2)
+}
+
+// TestGenerateReportsSourceRemappings tests that GenerateReports reports source files by their remapped paths, and
+// that source files whose code could not be read from their remapped paths are reported without line coverage,
+// rather than failing report generation.
+func TestGenerateReportsSourceRemappings(t *testing.T) {
+	// Write our source file to a directory which differs from the one it was compiled in.
+	sourceDirectory := t.TempDir()
+	fixtureCompilation := newReportsFixtureCompilation()
+	sourceCode := fixtureCompilation.SourceCode[reportsFixtureSourcePath]
+	assert.NoError(t, os.WriteFile(filepath.Join(sourceDirectory, "counter.sol"), sourceCode, 0644))
+	compiledPath := "/app/contracts/counter.sol"
+
+	cases := []struct {
+		name         string
+		remappings   []types.SourcePathRemapping
+		expectedPath string
+		expectedLCOV string
+	}{
+		{
+			name:         "hit",
+			remappings:   []types.SourcePathRemapping{{From: "/app/contracts", To: sourceDirectory}},
+			expectedPath: filepath.Join(sourceDirectory, "counter.sol"),
+			expectedLCOV: "DA:2,1\nDA:3,0\n",
+		},
+		{
+			name: "overlapping prefixes",
+			remappings: []types.SourcePathRemapping{
+				{From: "/app", To: filepath.Join(sourceDirectory, "missing")},
+				{From: "/app/contracts/", To: sourceDirectory},
+			},
+			expectedPath: filepath.Join(sourceDirectory, "counter.sol"),
+			expectedLCOV: "DA:2,1\nDA:3,0\n",
+		},
+		{
+			name:         "miss",
+			remappings:   []types.SourcePathRemapping{{From: "/app", To: filepath.Join(sourceDirectory, "missing")}},
+			expectedPath: filepath.Join(sourceDirectory, "missing", "contracts", "counter.sol"),
+		},
+	}
+	for _, c := range cases {
+		// Create a compilation which references the source file by the path it was compiled with, and cache its source.
+		compilation := types.NewCompilation()
+		compilation.SourcePathToArtifact[compiledPath] = fixtureCompilation.SourcePathToArtifact[reportsFixtureSourcePath]
+		compilation.SourceIdToPath[0] = compiledPath
+		compilation.SourcePathRemappings = c.remappings
+		cacheErr := compilation.CacheSourceCode()
+		assert.Equal(t, c.expectedLCOV == "", cacheErr != nil, c.name)
+
+		reportDir := t.TempDir()
+		reportPaths, err := GenerateReports([]types.Compilation{*compilation}, newReportsFixtureCoverageMaps(t, 0), ReportOptions{
+			Formats:   []string{"html", "lcov", "json"},
+			ReportDir: reportDir,
+		})
+		assert.NoError(t, err, c.name)
+		assert.Len(t, reportPaths, 3, c.name)
+
+		// Both the LCOV and JSON reports should reference the remapped path.
+		lcovBytes, err := os.ReadFile(filepath.Join(reportDir, "lcov.info"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(lcovBytes), "SF:"+c.expectedPath+"\n", c.name)
+		assert.Contains(t, string(lcovBytes), c.expectedLCOV, c.name)
+
+		jsonBytes, err := os.ReadFile(filepath.Join(reportDir, "coverage.json"))
+		assert.NoError(t, err)
+		var report CoverageReport
+		assert.NoError(t, json.Unmarshal(jsonBytes, &report))
+		assert.Len(t, report.Files, 1, c.name)
+		assert.EqualValues(t, filepath.ToSlash(c.expectedPath), report.Files[0].Path, c.name)
+		if c.expectedLCOV == "" {
+			assert.True(t, report.Files[0].SourceUnavailable, c.name)
+			assert.Empty(t, report.Files[0].Lines, c.name)
+		} else {
+			assert.False(t, report.Files[0].SourceUnavailable, c.name)
+			assert.EqualValues(t, FileCoverageTotals{Active: 2, Covered: 1}, report.Files[0].Totals, c.name)
+		}
+	}
+}
@@ -4,13 +4,18 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
 	"sort"
 
 	"github.com/crytic/medusa/compilation/types"
+	"github.com/crytic/medusa/utils"
 	"golang.org/x/exp/maps"
 )
 
 // SourceAnalysis describes source code coverage across a list of compilations, after analyzing associated CoverageMaps.
+// It is intended to be consumed by external tooling: its methods never modify the analysis, so they are safe to call
+// from multiple goroutines concurrently, provided the analysis is not modified by the caller. The exported fields and
+// methods of the analysis are stable, and will not change in a backwards incompatible way in minor releases.
 type SourceAnalysis struct {
 	// Files describes the analysis results for a given source file path.
 	Files map[string]*SourceFileAnalysis
@@ -113,6 +118,42 @@ func (s *SourceAnalysis) GenerateLCOVReport() string {
 	return buffer.String()
 }
 
+// File returns the analysis results for the source file with the provided path, or nil if the source file was not
+// analyzed.
+func (s *SourceAnalysis) File(path string) *SourceFileAnalysis {
+	return s.Files[path]
+}
+
+// Line returns coverage information for the provided 1-based line number in the source file with the provided path,
+// or nil if the source file was not analyzed or does not contain the line.
+func (s *SourceAnalysis) Line(path string, lineNumber int) *SourceLineAnalysis {
+	file := s.File(path)
+	if file == nil {
+		return nil
+	}
+	return file.Line(lineNumber)
+}
+
+// FunctionCoverage returns coverage information for every function defined across all source files, ordered by source
+// file path, then by the order the functions are defined in.
+func (s *SourceAnalysis) FunctionCoverage() []*FunctionCoverage {
+	functions := make([]*FunctionCoverage, 0)
+	for _, file := range s.SortedFiles() {
+		functions = append(functions, file.FunctionCoverage()...)
+	}
+	return functions
+}
+
+// ContractSummaries returns a coverage summary for every contract and library defined across all source files,
+// ordered by source file path, then by the order the contracts are defined in.
+func (s *SourceAnalysis) ContractSummaries() []*ContractCoverageSummary {
+	summaries := make([]*ContractCoverageSummary, 0)
+	for _, file := range s.SortedFiles() {
+		summaries = append(summaries, file.ContractSummaries()...)
+	}
+	return summaries
+}
+
 // SourceFileAnalysis describes coverage information for a given source file.
 type SourceFileAnalysis struct {
 	// Path describes the file path of the source file. This is kept here for access during report generation.
@@ -128,6 +169,17 @@ type SourceFileAnalysis struct {
 
 	// Functions is a list of functions defined in the source file
 	Functions []*types.FunctionDefinition
+
+	// Contracts is a list of contracts and libraries defined in the source file
+	Contracts []*types.ContractDefinition
+
+	// SourceUnavailable indicates whether the source code of the file could not be read, in which case the file has no
+	// lines, functions, or contracts, as coverage cannot be attributed to them without it.
+	SourceUnavailable bool
+
+	// ExcludeSetupOnlyCoverage indicates whether lines which were only covered during contract deployment and chain
+	// setup are excluded from the covered line counts of the source file.
+	ExcludeSetupOnlyCoverage bool
 }
 
 // ActiveLineCount returns the count of lines that are marked executable/active within the source file.
@@ -141,17 +193,192 @@ func (s *SourceFileAnalysis) ActiveLineCount() int {
 	return count
 }
 
-// CoveredLineCount returns the count of lines that were covered within the source file.
+// CoveredLineCount returns the count of lines that were covered within the source file. If ExcludeSetupOnlyCoverage
+// is set, lines which were only covered during setup are not counted.
 func (s *SourceFileAnalysis) CoveredLineCount() int {
 	count := 0
 	for _, line := range s.Lines {
-		if line.IsCovered || line.IsCoveredReverted {
+		if s.isLineCounted(line) {
+			count++
+		}
+	}
+	return count
+}
+
+// SetupOnlyLineCount returns the count of lines that were only covered during contract deployment and chain setup
+// within the source file.
+func (s *SourceFileAnalysis) SetupOnlyLineCount() int {
+	count := 0
+	for _, line := range s.Lines {
+		if line.IsCoveredSetupOnly {
 			count++
 		}
 	}
 	return count
 }
 
+// isLineCounted indicates whether the provided line counts toward the covered line counts of the source file.
+func (s *SourceFileAnalysis) isLineCounted(line *SourceLineAnalysis) bool {
+	if s.ExcludeSetupOnlyCoverage && line.IsCoveredSetupOnly {
+		return false
+	}
+	return line.IsCovered || line.IsCoveredReverted || line.IsCoveredOutOfGas
+}
+
+// MaxHitCount returns the greatest count of times any line within the source file was executed, whether or not it
+// reverted.
+func (s *SourceFileAnalysis) MaxHitCount() uint {
+	var maxHitCount uint
+	for _, line := range s.Lines {
+		maxHitCount = utils.Max(maxHitCount, line.HitCount())
+	}
+	return maxHitCount
+}
+
+// Line returns coverage information for the provided 1-based line number, or nil if the source file does not contain
+// the line.
+func (s *SourceFileAnalysis) Line(lineNumber int) *SourceLineAnalysis {
+	if lineNumber < 1 || lineNumber > len(s.Lines) {
+		return nil
+	}
+	return s.Lines[lineNumber-1]
+}
+
+// lineRange returns the 1-based line numbers of the first and last lines spanned by the provided source map range
+// (e.g. the "src" of an AST node) within the source file.
+func (s *SourceFileAnalysis) lineRange(src string) (int, int) {
+	byteStart := types.GetSrcMapStart(src)
+	byteEnd := byteStart + utils.Max(types.GetSrcMapLength(src)-1, 0)
+	startLine := sort.Search(len(s.CumulativeOffsetByLine), func(i int) bool {
+		return s.CumulativeOffsetByLine[i] > byteStart
+	})
+	endLine := sort.Search(len(s.CumulativeOffsetByLine), func(i int) bool {
+		return s.CumulativeOffsetByLine[i] > byteEnd
+	})
+	return startLine, endLine
+}
+
+// FunctionCoverage returns coverage information for every function defined in the source file, in the order they are
+// defined in.
+func (s *SourceFileAnalysis) FunctionCoverage() []*FunctionCoverage {
+	functions := make([]*FunctionCoverage, 0, len(s.Functions))
+	for _, fn := range s.Functions {
+		function := &FunctionCoverage{
+			Path: s.Path,
+			Name: fn.Name,
+		}
+		function.StartLine, function.EndLine = s.lineRange(fn.Src)
+
+		// Resolve the contract the function is defined in, if any.
+		for _, contract := range s.Contracts {
+			contractStart := types.GetSrcMapStart(contract.Src)
+			functionStart := types.GetSrcMapStart(fn.Src)
+			if functionStart >= contractStart && functionStart < contractStart+types.GetSrcMapLength(contract.Src) {
+				function.ContractName = contract.CanonicalName
+				break
+			}
+		}
+
+		// The function is covered if any line within its definition was covered.
+		for lineNumber := function.StartLine; lineNumber <= function.EndLine; lineNumber++ {
+			if line := s.Line(lineNumber); line != nil && line.IsActive {
+				function.IsCovered = function.IsCovered || line.IsCovered
+				function.IsCoveredReverted = function.IsCoveredReverted || line.IsCoveredReverted
+				function.IsCoveredOutOfGas = function.IsCoveredOutOfGas || line.IsCoveredOutOfGas
+			}
+		}
+		functions = append(functions, function)
+	}
+	return functions
+}
+
+// ContractSummaries returns a coverage summary for every contract and library defined in the source file, in the
+// order they are defined in.
+func (s *SourceFileAnalysis) ContractSummaries() []*ContractCoverageSummary {
+	summaries := make([]*ContractCoverageSummary, 0, len(s.Contracts))
+	functions := s.FunctionCoverage()
+	for _, contract := range s.Contracts {
+		summary := &ContractCoverageSummary{
+			Path: s.Path,
+			Name: contract.CanonicalName,
+		}
+
+		// Count the active and covered lines within the contract definition.
+		startLine, endLine := s.lineRange(contract.Src)
+		for lineNumber := startLine; lineNumber <= endLine; lineNumber++ {
+			if line := s.Line(lineNumber); line != nil && line.IsActive {
+				summary.ActiveLineCount++
+				if s.isLineCounted(line) {
+					summary.CoveredLineCount++
+				}
+			}
+		}
+
+		// Count the functions and covered functions defined in the contract.
+		for _, function := range functions {
+			if function.ContractName == summary.Name {
+				summary.FunctionCount++
+				if function.IsCovered || function.IsCoveredReverted || function.IsCoveredOutOfGas {
+					summary.CoveredFunctionCount++
+				}
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// FunctionCoverage describes coverage information for a function defined in a source file.
+type FunctionCoverage struct {
+	// Path describes the file path of the source file the function is defined in.
+	Path string
+
+	// ContractName describes the name of the contract or library the function is defined in, or an empty string if it
+	// is a free function.
+	ContractName string
+
+	// Name describes the name of the function. This is an empty string for constructors, fallback and receive
+	// functions.
+	Name string
+
+	// StartLine describes the 1-based line number of the first line of the function definition.
+	StartLine int
+
+	// EndLine describes the 1-based line number of the last line of the function definition.
+	EndLine int
+
+	// IsCovered indicates whether any line within the function definition has been executed without reverting.
+	IsCovered bool
+
+	// IsCoveredReverted indicates whether any line within the function definition has been executed before reverting.
+	IsCoveredReverted bool
+
+	// IsCoveredOutOfGas indicates whether any line within the function definition has been executed in a call frame
+	// which ran out of gas.
+	IsCoveredOutOfGas bool
+}
+
+// ContractCoverageSummary describes a summary of the coverage of a contract or library defined in a source file.
+type ContractCoverageSummary struct {
+	// Path describes the file path of the source file the contract is defined in.
+	Path string
+
+	// Name describes the name of the contract.
+	Name string
+
+	// ActiveLineCount describes the count of lines within the contract definition that are marked executable/active.
+	ActiveLineCount int
+
+	// CoveredLineCount describes the count of lines within the contract definition that were covered.
+	CoveredLineCount int
+
+	// FunctionCount describes the count of functions defined in the contract.
+	FunctionCount int
+
+	// CoveredFunctionCount describes the count of functions defined in the contract that were covered.
+	CoveredFunctionCount int
+}
+
 // SourceLineAnalysis describes coverage information for a specific source file line.
 type SourceLineAnalysis struct {
 	// IsActive indicates the given source line was executable.
@@ -177,10 +404,65 @@ type SourceLineAnalysis struct {
 
 	// IsCoveredReverted indicates whether the source line has been executed before reverting.
 	IsCoveredReverted bool
+
+	// OutOfGasHitCount describes how many times this line was executed in a call frame which ran out of gas
+	OutOfGasHitCount uint
+
+	// IsCoveredOutOfGas indicates whether the source line has been executed in a call frame which ran out of gas. Such
+	// lines were attempted, but could not complete within the gas limit, rather than being rejected by a revert.
+	IsCoveredOutOfGas bool
+
+	// IsCoveredSetupOnly indicates whether the source line was only executed while deploying contracts and setting
+	// up the chain prior to fuzzing, and never by a fuzzed call sequence. Such lines are also marked as IsCovered,
+	// IsCoveredReverted, or IsCoveredOutOfGas.
+	IsCoveredSetupOnly bool
+}
+
+// HitCount returns the count of times the source line was executed, whether or not it reverted or ran out of gas.
+func (s *SourceLineAnalysis) HitCount() uint {
+	return s.SuccessHitCount + s.RevertHitCount + s.OutOfGasHitCount
+}
+
+// AnalyzeSourceCoverageWithSetup performs the same analysis as AnalyzeSourceCoverage on the total coverage maps, and
+// additionally marks lines which were covered by the total coverage maps, but not by the provided fuzzing coverage
+// maps, as only covered during setup. If excludeSetupOnly is true, these lines are excluded from covered line counts.
+// Returns a SourceAnalysis object, or an error if one occurs.
+func AnalyzeSourceCoverageWithSetup(compilations []types.Compilation, coverageMaps *CoverageMaps, fuzzingCoverageMaps *CoverageMaps, excludeSetupOnly bool) (*SourceAnalysis, error) {
+	sourceAnalysis, err := AnalyzeSourceCoverage(compilations, coverageMaps)
+	if err != nil {
+		return nil, err
+	}
+	fuzzingSourceAnalysis, err := AnalyzeSourceCoverage(compilations, fuzzingCoverageMaps)
+	if err != nil {
+		return nil, err
+	}
+	sourceAnalysis.markSetupOnlyCoverage(fuzzingSourceAnalysis, excludeSetupOnly)
+	return sourceAnalysis, nil
+}
+
+// markSetupOnlyCoverage marks every covered line in the source analysis which is not covered in the provided source
+// analysis of fuzzing coverage as only covered during setup, and sets whether such lines are excluded from covered
+// line counts.
+func (s *SourceAnalysis) markSetupOnlyCoverage(fuzzingSourceAnalysis *SourceAnalysis, excludeSetupOnly bool) {
+	for path, file := range s.Files {
+		file.ExcludeSetupOnlyCoverage = excludeSetupOnly
+		fuzzingFile := fuzzingSourceAnalysis.File(path)
+		for i, line := range file.Lines {
+			if !line.IsCovered && !line.IsCoveredReverted && !line.IsCoveredOutOfGas {
+				continue
+			}
+			var fuzzingLine *SourceLineAnalysis
+			if fuzzingFile != nil {
+				fuzzingLine = fuzzingFile.Line(i + 1)
+			}
+			line.IsCoveredSetupOnly = fuzzingLine == nil || (!fuzzingLine.IsCovered && !fuzzingLine.IsCoveredReverted && !fuzzingLine.IsCoveredOutOfGas)
+		}
+	}
 }
 
 // AnalyzeSourceCoverage takes a list of compilations and a set of coverage maps, and performs source analysis
-// to determine source coverage information.
+// to determine source coverage information. Source files are reported by their paths after applying the source path
+// remappings of their compilation. Source files whose code was not cached are reported without any source lines.
 // Returns a SourceAnalysis object, or an error if one occurs.
 func AnalyzeSourceCoverage(compilations []types.Compilation, coverageMaps *CoverageMaps) (*SourceAnalysis, error) {
 	// Create a new source analysis object
@@ -191,13 +473,21 @@ func AnalyzeSourceCoverage(compilations []types.Compilation, coverageMaps *Cover
 	// Loop through all sources in all compilations to add them to our source file analysis container.
 	for _, compilation := range compilations {
 		for sourcePath := range compilation.SourcePathToArtifact {
-			// If we have no source code loaded for this source, skip it.
+			// If we have no source code loaded for this source, report it without any source lines.
+			remappedPath := compilation.RemappedSourcePath(sourcePath)
 			if _, ok := compilation.SourceCode[sourcePath]; !ok {
-				return nil, fmt.Errorf("could not perform source code analysis, code was not cached for '%v'", sourcePath)
+				if _, ok := sourceAnalysis.Files[remappedPath]; !ok {
+					sourceAnalysis.Files[remappedPath] = &SourceFileAnalysis{
+						Path:              remappedPath,
+						SourceUnavailable: true,
+					}
+				}
+				continue
 			}
 
 			lines, cumulativeOffset := parseSourceLines(compilation.SourceCode[sourcePath])
 			funcs := make([]*types.FunctionDefinition, 0)
+			contracts := make([]*types.ContractDefinition, 0)
 
 			var ast types.AST
 			b, err := json.Marshal(compilation.SourcePathToArtifact[sourcePath].Ast)
@@ -220,6 +510,7 @@ func AnalyzeSourceCoverage(compilations []types.Compilation, coverageMaps *Cover
 					if contract.Kind == types.ContractKindInterface {
 						continue
 					}
+					contracts = append(contracts, &contract)
 					for _, subNode := range contract.Nodes {
 						if subNode.GetNodeType() == "FunctionDefinition" {
 							fn := subNode.(types.FunctionDefinition)
@@ -231,12 +522,13 @@ func AnalyzeSourceCoverage(compilations []types.Compilation, coverageMaps *Cover
 			}
 
 			// Obtain the parsed source code lines for this source.
-			if _, ok := sourceAnalysis.Files[sourcePath]; !ok {
-				sourceAnalysis.Files[sourcePath] = &SourceFileAnalysis{
-					Path:                   sourcePath,
+			if existingFile, ok := sourceAnalysis.Files[remappedPath]; !ok || existingFile.SourceUnavailable {
+				sourceAnalysis.Files[remappedPath] = &SourceFileAnalysis{
+					Path:                   remappedPath,
 					CumulativeOffsetByLine: cumulativeOffset,
 					Lines:                  lines,
 					Functions:              funcs,
+					Contracts:              contracts,
 				}
 			}
 
@@ -326,13 +618,20 @@ func analyzeContractSourceCoverage(compilation types.Compilation, sourceAnalysis
 		// Capture the hit count of the source map element.
 		succHitCount := uint(0)
 		revertHitCount := uint(0)
+		outOfGasHitCount := uint(0)
 		if contractCoverageData != nil {
 			succHitCount = contractCoverageData.successfulCoverage.HitCount(instructionOffsetLookup[sourceMapElement.Index])
 			revertHitCount = contractCoverageData.revertedCoverage.HitCount(instructionOffsetLookup[sourceMapElement.Index])
+			outOfGasHitCount = contractCoverageData.outOfGasCoverage.HitCount(instructionOffsetLookup[sourceMapElement.Index])
 		}
 
-		// Obtain the source file this element maps to.
-		if sourceFile, ok := sourceAnalysis.Files[sourcePath]; ok {
+		// Obtain the source file this element maps to. If its source code is unavailable, coverage cannot be attributed
+		// to its lines.
+		if sourceFile, ok := sourceAnalysis.Files[compilation.RemappedSourcePath(sourcePath)]; ok {
+			if sourceFile.SourceUnavailable {
+				continue
+			}
+
 			// Mark all lines which fall within this range.
 			start := sourceMapElement.Offset
 
@@ -351,8 +650,10 @@ func analyzeContractSourceCoverage(compilation types.Compilation, sourceAnalysis
 				// Set its coverage state and increment hit counts
 				sourceLine.SuccessHitCount += succHitCount
 				sourceLine.RevertHitCount += revertHitCount
+				sourceLine.OutOfGasHitCount += outOfGasHitCount
 				sourceLine.IsCovered = sourceLine.IsCovered || sourceLine.SuccessHitCount > 0
 				sourceLine.IsCoveredReverted = sourceLine.IsCoveredReverted || sourceLine.RevertHitCount > 0
+				sourceLine.IsCoveredOutOfGas = sourceLine.IsCoveredOutOfGas || sourceLine.OutOfGasHitCount > 0
 
 			}
 		} else {
@@ -368,10 +669,8 @@ func analyzeContractSourceCoverage(compilation types.Compilation, sourceAnalysis
 // entire method definition.
 // Returns the filtered source map.
 func filterSourceMaps(compilation types.Compilation, sourceMap types.SourceMap) types.SourceMap {
-	// Create our resulting source map
-	filteredMap := make(types.SourceMap, 0)
-
-	// Loop for each source map entry and determine if it should be included.
+	// Collect the indexes of all source map entries which map to a known source file.
+	indexes := make([]int, 0, len(sourceMap))
 	for i, sourceMapElement := range sourceMap {
 		// Verify this file ID is not out of bounds for a source file index
 		if _, exists := compilation.SourceIdToPath[sourceMapElement.SourceUnitID]; !exists {
@@ -379,29 +678,67 @@ func filterSourceMaps(compilation types.Compilation, sourceMap types.SourceMap)
 			//  For now, we silently skip these cases.
 			continue
 		}
+		indexes = append(indexes, i)
+	}
 
-		// Verify this source map does not overlap another
-		encapsulatesOtherMapping := false
-		for x, sourceMapElement2 := range sourceMap {
-			if i != x && sourceMapElement.SourceUnitID == sourceMapElement2.SourceUnitID &&
-				!(sourceMapElement.Offset == sourceMapElement2.Offset && sourceMapElement.Length == sourceMapElement2.Length) {
-				if sourceMapElement2.Offset >= sourceMapElement.Offset &&
-					sourceMapElement2.Offset+sourceMapElement2.Length <= sourceMapElement.Offset+sourceMapElement.Length {
-					encapsulatesOtherMapping = true
-					break
-				}
+	// Sort the entries by source unit, then by offset, then by descending length, so that any entry another entry
+	// encapsulates appears after it in the same source unit.
+	sort.Slice(indexes, func(x, y int) bool {
+		a, b := sourceMap[indexes[x]], sourceMap[indexes[y]]
+		if a.SourceUnitID != b.SourceUnitID {
+			return a.SourceUnitID < b.SourceUnitID
+		}
+		if a.Offset != b.Offset {
+			return a.Offset < b.Offset
+		}
+		return a.Length > b.Length
+	})
+
+	// Sweep the entries in reverse, tracking the smallest end offset of any entry with a greater offset in the same
+	// source unit, and of any entry with the same offset. An entry encapsulates another distinct entry if either ends
+	// within it.
+	encapsulatesOtherMapping := make([]bool, len(sourceMap))
+	var minEndAfterOffset, minEndAtOffset int
+	for x := len(indexes) - 1; x >= 0; x-- {
+		sourceMapElement := sourceMap[indexes[x]]
+		end := sourceMapElement.Offset + sourceMapElement.Length
+
+		// If this is the last entry in its source unit or at its offset, reset what we are tracking.
+		isLastInSourceUnit := x == len(indexes)-1 || sourceMap[indexes[x+1]].SourceUnitID != sourceMapElement.SourceUnitID
+		if isLastInSourceUnit || sourceMap[indexes[x+1]].Offset != sourceMapElement.Offset {
+			if isLastInSourceUnit {
+				minEndAfterOffset = math.MaxInt
+			} else {
+				minEndAfterOffset = utils.Min(minEndAfterOffset, minEndAtOffset)
 			}
+			minEndAtOffset = end
 		}
 
-		if !encapsulatesOtherMapping {
-			filteredMap = append(filteredMap, sourceMapElement)
+		// Entries with the same offset and a greater length encapsulate the shorter ones, while identical entries do
+		// not encapsulate each other.
+		encapsulatesOtherMapping[indexes[x]] = minEndAfterOffset <= end || minEndAtOffset < end
+		minEndAtOffset = utils.Min(minEndAtOffset, end)
+	}
+
+	// Create our resulting source map, preserving the order of the original.
+	sort.Ints(indexes)
+	filteredMap := make(types.SourceMap, 0)
+	for _, i := range indexes {
+		if !encapsulatesOtherMapping[i] {
+			filteredMap = append(filteredMap, sourceMap[i])
 		}
 	}
 	return filteredMap
 }
 
-// parseSourceLines splits the provided source code into SourceLineAnalysis objects.
-// Returns the SourceLineAnalysis objects.
+// utf8ByteOrderMark describes the byte order mark which may prefix UTF-8 encoded source files.
+var utf8ByteOrderMark = []byte{0xEF, 0xBB, 0xBF}
+
+// parseSourceLines splits the provided source code into SourceLineAnalysis objects. Lines may be terminated by "\n" or
+// "\r\n", and the source may be prefixed with a UTF-8 byte order mark. Line terminators and the byte order mark are
+// accounted for in the byte offsets of each line, as source maps are offsets into the unmodified source, but are
+// excluded from each line's contents.
+// Returns the SourceLineAnalysis objects and the cumulative byte offset at which each line starts.
 func parseSourceLines(sourceCode []byte) ([]*SourceLineAnalysis, []int) {
 	// Create our lines and a variable to track where our current line start offset is.
 	var lines []*SourceLineAnalysis
@@ -414,11 +751,19 @@ func parseSourceLines(sourceCode []byte) ([]*SourceLineAnalysis, []int) {
 	// For each source code line, initialize a struct that defines its start/end offsets, set its contents.
 	for i := 0; i < len(sourceCodeLinesBytes); i++ {
 		lineEnd := lineStart + len(sourceCodeLinesBytes[i]) + 1
+
+		// Strip the byte order mark from the first line and any carriage return terminating the line from the contents.
+		contents := sourceCodeLinesBytes[i]
+		if i == 0 {
+			contents = bytes.TrimPrefix(contents, utf8ByteOrderMark)
+		}
+		contents = bytes.TrimSuffix(contents, []byte("\r"))
+
 		lines = append(lines, &SourceLineAnalysis{
 			IsActive:          false,
 			Start:             lineStart,
 			End:               lineEnd,
-			Contents:          sourceCodeLinesBytes[i],
+			Contents:          contents,
 			IsCovered:         false,
 			IsCoveredReverted: false,
 		})
@@ -0,0 +1,270 @@
+package coverage
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/crytic/medusa/compilation/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// sourceAnalysisFixtureSource describes the source code analyzed by newSourceAnalysisFixture.
+const sourceAnalysisFixtureSource = `contract Vault {
+    uint balance;
+    function deposit() public {
+        balance += 1;
+    }
+    function withdraw() public {
+        balance = 0;
+    }
+}
+function helper() pure returns (uint) {
+    return 1;
+}
+`
+
+// fixtureSrc obtains the source map range (e.g. an AST node "src") of the provided snippet within the fixture source.
+func fixtureSrc(snippet string) string {
+	return fmt.Sprintf("%d:%d:0", strings.Index(sourceAnalysisFixtureSource, snippet), len(snippet))
+}
+
+// newSourceAnalysisFixture creates a SourceAnalysis for a single source file, in which the body of deposit executed
+// successfully, the body of withdraw only executed before reverting, and the body of helper never executed.
+func newSourceAnalysisFixture() *SourceAnalysis {
+	lines, cumulativeOffset := parseSourceLines([]byte(sourceAnalysisFixtureSource))
+	for _, lineNumber := range []int{4, 7, 11} {
+		lines[lineNumber-1].IsActive = true
+	}
+	lines[3].IsCovered, lines[3].SuccessHitCount = true, 2
+	lines[6].IsCoveredReverted, lines[6].RevertHitCount = true, 1
+
+	depositSrc := fixtureSrc("function deposit() public {\n        balance += 1;\n    }")
+	withdrawSrc := fixtureSrc("function withdraw() public {\n        balance = 0;\n    }")
+	helperSrc := fixtureSrc("function helper() pure returns (uint) {\n    return 1;\n}")
+	vaultSrc := fixtureSrc(sourceAnalysisFixtureSource[:strings.Index(sourceAnalysisFixtureSource, "function helper")-1])
+	return &SourceAnalysis{
+		Files: map[string]*SourceFileAnalysis{
+			"vault.sol": {
+				Path:                   "vault.sol",
+				CumulativeOffsetByLine: cumulativeOffset,
+				Lines:                  lines,
+				Functions: []*types.FunctionDefinition{
+					{NodeType: "FunctionDefinition", Src: helperSrc, Name: "helper"},
+					{NodeType: "FunctionDefinition", Src: depositSrc, Name: "deposit"},
+					{NodeType: "FunctionDefinition", Src: withdrawSrc, Name: "withdraw"},
+				},
+				Contracts: []*types.ContractDefinition{
+					{NodeType: "ContractDefinition", Src: vaultSrc, CanonicalName: "Vault", Kind: types.ContractKindContract},
+				},
+			},
+		},
+	}
+}
+
+// TestSourceAnalysisLine tests that coverage information can be queried for a given source file and line number.
+func TestSourceAnalysisLine(t *testing.T) {
+	sourceAnalysis := newSourceAnalysisFixture()
+
+	// Lines are queried by their 1-based line number.
+	line := sourceAnalysis.Line("vault.sol", 4)
+	assert.NotNil(t, line)
+	assert.EqualValues(t, "        balance += 1;", string(line.Contents))
+	assert.True(t, line.IsActive)
+	assert.True(t, line.IsCovered)
+	assert.EqualValues(t, 2, line.SuccessHitCount)
+
+	// Lines which are not executable are not active.
+	line = sourceAnalysis.Line("vault.sol", 2)
+	assert.NotNil(t, line)
+	assert.False(t, line.IsActive)
+
+	// Lines outside the file and files which were not analyzed are not found.
+	assert.Nil(t, sourceAnalysis.Line("vault.sol", 0))
+	assert.Nil(t, sourceAnalysis.Line("vault.sol", 100))
+	assert.Nil(t, sourceAnalysis.Line("token.sol", 1))
+	assert.Nil(t, sourceAnalysis.File("token.sol"))
+}
+
+// TestSourceAnalysisFunctionCoverage tests that the coverage of each function is reported along with the contract it
+// is defined in and the lines it spans.
+func TestSourceAnalysisFunctionCoverage(t *testing.T) {
+	functions := newSourceAnalysisFixture().FunctionCoverage()
+	assert.EqualValues(t, []*FunctionCoverage{
+		{Path: "vault.sol", ContractName: "", Name: "helper", StartLine: 10, EndLine: 12},
+		{Path: "vault.sol", ContractName: "Vault", Name: "deposit", StartLine: 3, EndLine: 5, IsCovered: true},
+		{Path: "vault.sol", ContractName: "Vault", Name: "withdraw", StartLine: 6, EndLine: 8, IsCoveredReverted: true},
+	}, functions)
+}
+
+// TestSourceAnalysisContractSummaries tests that the coverage of each contract is summarized from the lines and
+// functions within its definition.
+func TestSourceAnalysisContractSummaries(t *testing.T) {
+	summaries := newSourceAnalysisFixture().ContractSummaries()
+	assert.EqualValues(t, []*ContractCoverageSummary{
+		{Path: "vault.sol", Name: "Vault", ActiveLineCount: 2, CoveredLineCount: 2, FunctionCount: 2, CoveredFunctionCount: 2},
+	}, summaries)
+}
+
+// TestParseSourceLinesLineEndings tests that source lines are parsed with byte offsets matching the unmodified source
+// regardless of line endings or a byte order mark, so source map offsets are attributed to the correct lines.
+func TestParseSourceLinesLineEndings(t *testing.T) {
+	tests := []struct {
+		name            string
+		source          string
+		expectedOffsets []int
+	}{
+		{name: "LF", source: "contract A {\n    uint x;\n}\n", expectedOffsets: []int{0, 13, 25, 27}},
+		{name: "CRLF", source: "contract A {\r\n    uint x;\r\n}\r\n", expectedOffsets: []int{0, 14, 27, 30}},
+		{name: "Mixed", source: "contract A {\r\n    uint x;\n}\r\n", expectedOffsets: []int{0, 14, 26, 29}},
+		{name: "BOM", source: "\xEF\xBB\xBFcontract A {\r\n    uint x;\r\n}\r\n", expectedOffsets: []int{0, 17, 30, 33}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lines, cumulativeOffset := parseSourceLines([]byte(test.source))
+			assert.EqualValues(t, test.expectedOffsets, cumulativeOffset)
+
+			// Line contents exclude the byte order mark and line terminators.
+			assert.Len(t, lines, 4)
+			assert.EqualValues(t, "contract A {", string(lines[0].Contents))
+			assert.EqualValues(t, "    uint x;", string(lines[1].Contents))
+			assert.EqualValues(t, "}", string(lines[2].Contents))
+			assert.EqualValues(t, "", string(lines[3].Contents))
+
+			// Each line ends where the next begins, so every byte of the source is attributed to a line.
+			for i := 0; i < len(lines)-1; i++ {
+				assert.EqualValues(t, lines[i+1].Start, lines[i].End)
+			}
+
+			// A source map range for the state variable declaration is attributed to the second line only.
+			sourceFile := &SourceFileAnalysis{Lines: lines, CumulativeOffsetByLine: cumulativeOffset}
+			declarationOffset := strings.Index(test.source, "uint x;")
+			startLine, endLine := sourceFile.lineRange(fmt.Sprintf("%d:%d:0", declarationOffset, len("uint x;")))
+			assert.EqualValues(t, 2, startLine)
+			assert.EqualValues(t, 2, endLine)
+		})
+	}
+}
+
+// filterSourceMapsReference is the original quadratic implementation of filterSourceMaps, which is used to verify the
+// filtering semantics are preserved.
+func filterSourceMapsReference(compilation types.Compilation, sourceMap types.SourceMap) types.SourceMap {
+	filteredMap := make(types.SourceMap, 0)
+	for i, sourceMapElement := range sourceMap {
+		if _, exists := compilation.SourceIdToPath[sourceMapElement.SourceUnitID]; !exists {
+			continue
+		}
+		encapsulatesOtherMapping := false
+		for x, sourceMapElement2 := range sourceMap {
+			if i != x && sourceMapElement.SourceUnitID == sourceMapElement2.SourceUnitID &&
+				!(sourceMapElement.Offset == sourceMapElement2.Offset && sourceMapElement.Length == sourceMapElement2.Length) {
+				if sourceMapElement2.Offset >= sourceMapElement.Offset &&
+					sourceMapElement2.Offset+sourceMapElement2.Length <= sourceMapElement.Offset+sourceMapElement.Length {
+					encapsulatesOtherMapping = true
+					break
+				}
+			}
+		}
+		if !encapsulatesOtherMapping {
+			filteredMap = append(filteredMap, sourceMapElement)
+		}
+	}
+	return filteredMap
+}
+
+// newRandomSourceMap creates a source map with the provided count of elements, spread across source units -1 to 2,
+// with offsets and lengths in the provided range so that duplicate and nested elements are common.
+func newRandomSourceMap(random *rand.Rand, count int, maxOffset int) types.SourceMap {
+	sourceMap := make(types.SourceMap, count)
+	for i := range sourceMap {
+		sourceMap[i] = types.SourceMapElement{
+			Index:        i,
+			Offset:       random.Intn(maxOffset),
+			Length:       random.Intn(maxOffset/4+2) - 1,
+			SourceUnitID: random.Intn(4) - 1,
+		}
+	}
+	return sourceMap
+}
+
+// newFilterSourceMapsCompilation creates a compilation with source units 0 and 1, so that elements in other source
+// units are filtered out.
+func newFilterSourceMapsCompilation() types.Compilation {
+	compilation := *types.NewCompilation()
+	compilation.SourceIdToPath[0] = "first.sol"
+	compilation.SourceIdToPath[1] = "second.sol"
+	return compilation
+}
+
+// TestFilterSourceMaps tests that source map elements which encapsulate another distinct element in the same source
+// unit are filtered out, along with elements which do not map to a known source unit.
+func TestFilterSourceMaps(t *testing.T) {
+	sourceMap := types.SourceMap{
+		{Index: 0, Offset: 0, Length: 100, SourceUnitID: 0},
+		{Index: 1, Offset: 10, Length: 5, SourceUnitID: 0},
+		{Index: 2, Offset: 10, Length: 5, SourceUnitID: 0},
+		{Index: 3, Offset: 10, Length: 10, SourceUnitID: 0},
+		{Index: 4, Offset: 0, Length: 100, SourceUnitID: 1},
+		{Index: 5, Offset: 50, Length: 5, SourceUnitID: -1},
+		{Index: 6, Offset: 30, Length: 5, SourceUnitID: 0},
+	}
+	filteredMap := filterSourceMaps(newFilterSourceMapsCompilation(), sourceMap)
+	assert.EqualValues(t, types.SourceMap{sourceMap[1], sourceMap[2], sourceMap[4], sourceMap[6]}, filteredMap)
+}
+
+// TestFilterSourceMapsMatchesReference tests that filterSourceMaps produces the same output as the original quadratic
+// implementation on randomized source maps.
+func TestFilterSourceMapsMatchesReference(t *testing.T) {
+	compilation := newFilterSourceMapsCompilation()
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		sourceMap := newRandomSourceMap(random, random.Intn(200), 1+random.Intn(100))
+		assert.EqualValues(t, filterSourceMapsReference(compilation, sourceMap), filterSourceMaps(compilation, sourceMap))
+	}
+}
+
+// BenchmarkFilterSourceMaps measures the cost of filtering a large source map, such as those produced by via-IR
+// builds, compared to the original quadratic implementation.
+func BenchmarkFilterSourceMaps(b *testing.B) {
+	compilation := newFilterSourceMapsCompilation()
+	sourceMap := newRandomSourceMap(rand.New(rand.NewSource(1)), 20000, 100000)
+	b.Run("Sweep", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			filterSourceMaps(compilation, sourceMap)
+		}
+	})
+	b.Run("Reference", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			filterSourceMapsReference(compilation, sourceMap)
+		}
+	})
+}
+
+// TestSourceAnalysisSetupOnlyCoverage tests that lines covered in the total coverage, but not in the fuzzing coverage,
+// are marked as only covered during setup, and are excluded from covered line counts if requested.
+func TestSourceAnalysisSetupOnlyCoverage(t *testing.T) {
+	// In our fuzzing coverage, the body of deposit was never executed.
+	fuzzingSourceAnalysis := newSourceAnalysisFixture()
+	fuzzingLine := fuzzingSourceAnalysis.Line("vault.sol", 4)
+	fuzzingLine.IsCovered, fuzzingLine.SuccessHitCount = false, 0
+
+	for _, excludeSetupOnly := range []bool{false, true} {
+		sourceAnalysis := newSourceAnalysisFixture()
+		sourceAnalysis.markSetupOnlyCoverage(fuzzingSourceAnalysis, excludeSetupOnly)
+		assert.True(t, sourceAnalysis.Line("vault.sol", 4).IsCoveredSetupOnly)
+		assert.False(t, sourceAnalysis.Line("vault.sol", 7).IsCoveredSetupOnly)
+		assert.False(t, sourceAnalysis.Line("vault.sol", 11).IsCoveredSetupOnly)
+		assert.EqualValues(t, 1, sourceAnalysis.File("vault.sol").SetupOnlyLineCount())
+
+		// Setup-only lines are still covered, but only count toward covered line counts if they are not excluded.
+		assert.True(t, sourceAnalysis.Line("vault.sol", 4).IsCovered)
+		expectedCoveredLineCount := 2
+		if excludeSetupOnly {
+			expectedCoveredLineCount = 1
+		}
+		assert.EqualValues(t, expectedCoveredLineCount, sourceAnalysis.CoveredLineCount())
+		assert.EqualValues(t, expectedCoveredLineCount, sourceAnalysis.ContractSummaries()[0].CoveredLineCount)
+	}
+}
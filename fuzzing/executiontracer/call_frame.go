@@ -47,6 +47,10 @@ type CallFrame struct {
 	// Potential types currently are *types.Log (events) or CallFrame (entering of a new child frame).
 	Operations []any
 
+	// OmittedOperations describes the amount of operations (events or child call frames) which were not recorded in
+	// Operations, as they exceeded the limits of the ExecutionTracer.
+	OmittedOperations int
+
 	// SelfDestructed indicates whether the call frame executed a SELFDESTRUCT operation.
 	SelfDestructed bool
 
@@ -0,0 +1,184 @@
+package executiontracer
+
+import (
+	"math/big"
+	"slices"
+
+	"github.com/crytic/medusa/chain"
+	"github.com/crytic/medusa/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	coretypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+// delegateCallTracerResultsKey describes the key to use when storing tracer results in call message results, or when
+// querying them.
+const delegateCallTracerResultsKey = "DelegateCallTracerResults"
+
+// GetDelegateCallTracerResults obtains the DelegateCall list stored by a DelegateCallTracer from message results. This
+// is nil if no delegate call was recorded by a tracer (e.g. no delegate call was made, or the DelegateCallTracer was not
+// attached during this message execution).
+func GetDelegateCallTracerResults(messageResults *types.MessageResults) []*DelegateCall {
+	// Try to obtain the results the tracer should've stored.
+	if genericResult, ok := messageResults.AdditionalResults[delegateCallTracerResultsKey]; ok {
+		if castedResult, ok := genericResult.([]*DelegateCall); ok {
+			return castedResult
+		}
+	}
+
+	// If we could not obtain them, return nil.
+	return nil
+}
+
+// RemoveDelegateCallTracerResults removes the DelegateCall list stored by a DelegateCallTracer from message results.
+func RemoveDelegateCallTracerResults(messageResults *types.MessageResults) {
+	delete(messageResults.AdditionalResults, delegateCallTracerResultsKey)
+}
+
+// DelegateCall describes a DELEGATECALL or CALLCODE made in a transaction, which executed the code of a target address
+// against the storage of the calling contract.
+type DelegateCall struct {
+	// ContextAddress describes the address whose storage the target address's code was executed against.
+	ContextAddress common.Address
+
+	// CallerCodeAddress describes the address whose code made the delegate call. This differs from ContextAddress if
+	// the calling code was itself executed through a delegate call.
+	CallerCodeAddress common.Address
+
+	// CallerSelector describes the first four bytes of the call data the calling call frame was entered with, or nil
+	// if the call data was shorter.
+	CallerSelector []byte
+
+	// TargetAddress describes the address whose code was executed by the delegate call.
+	TargetAddress common.Address
+
+	// CallType describes the opcode used to make the delegate call.
+	CallType vm.OpCode
+}
+
+// DelegateCallTracer implements tracers.Tracer to record the delegate calls made in a transaction, so that delegate
+// calls to untrusted addresses may be reported as a test failure.
+type DelegateCallTracer struct {
+	// delegateCalls describes the delegate calls recorded for the current transaction.
+	delegateCalls []*DelegateCall
+
+	// callFrameStates describes the state tracked by the tracer per call frame.
+	callFrameStates []*delegateCallTracerCallFrameState
+
+	// nativeTracer is the underlying tracer used to capture EVM execution.
+	nativeTracer *chain.TestChainTracer
+}
+
+// delegateCallTracerCallFrameState tracks state across call frames in the tracer.
+type delegateCallTracerCallFrameState struct {
+	// contextAddress describes the address whose storage is used by the call frame.
+	contextAddress common.Address
+
+	// codeAddress describes the address whose code is executed in the call frame.
+	codeAddress common.Address
+
+	// selector describes the first four bytes of the call data the call frame was entered with, or nil if the call
+	// data was shorter, or the call frame is a contract creation.
+	selector []byte
+
+	// delegateCall describes the delegate call which entered this call frame, or nil if it was not entered through a
+	// delegate call.
+	delegateCall *DelegateCall
+
+	// delegateCalls describes the delegate calls made in any child call frame which exited without reverting.
+	delegateCalls []*DelegateCall
+}
+
+// NewDelegateCallTracer returns a new DelegateCallTracer.
+func NewDelegateCallTracer() *DelegateCallTracer {
+	tracer := &DelegateCallTracer{
+		callFrameStates: make([]*delegateCallTracerCallFrameState, 0),
+	}
+	nativeTracer := &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart: tracer.OnTxStart,
+			OnEnter:   tracer.OnEnter,
+			OnExit:    tracer.OnExit,
+		},
+	}
+	tracer.nativeTracer = &chain.TestChainTracer{Tracer: nativeTracer, CaptureTxEndSetAdditionalResults: tracer.CaptureTxEndSetAdditionalResults}
+
+	return tracer
+}
+
+// NativeTracer returns the underlying TestChainTracer.
+func (t *DelegateCallTracer) NativeTracer() *chain.TestChainTracer {
+	return t.nativeTracer
+}
+
+// OnTxStart is called upon the start of transaction execution, as defined by tracers.Tracer.
+func (t *DelegateCallTracer) OnTxStart(vm *tracing.VMContext, tx *coretypes.Transaction, from common.Address) {
+	// Reset our call frame states and recorded delegate calls
+	t.delegateCalls = nil
+	t.callFrameStates = make([]*delegateCallTracerCallFrameState, 0)
+}
+
+// OnEnter initializes the tracing operation for the top of a call frame, as defined by tracers.Tracer.
+func (t *DelegateCallTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// Delegate calls execute the code of the callee using the storage of the caller.
+	callType := vm.OpCode(typ)
+	callFrameState := &delegateCallTracerCallFrameState{
+		contextAddress: to,
+		codeAddress:    to,
+	}
+	if callType != vm.CREATE && callType != vm.CREATE2 && len(input) >= 4 {
+		callFrameState.selector = slices.Clone(input[:4])
+	}
+
+	// If this call frame was entered through a delegate call, record it against the calling call frame.
+	if (callType == vm.DELEGATECALL || callType == vm.CALLCODE) && len(t.callFrameStates) > 0 {
+		callerState := t.callFrameStates[len(t.callFrameStates)-1]
+		callFrameState.contextAddress = from
+		callFrameState.delegateCall = &DelegateCall{
+			ContextAddress:    callerState.contextAddress,
+			CallerCodeAddress: callerState.codeAddress,
+			CallerSelector:    callerState.selector,
+			TargetAddress:     to,
+			CallType:          callType,
+		}
+	}
+	t.callFrameStates = append(t.callFrameStates, callFrameState)
+}
+
+// OnExit is called after a call to finalize tracing completes for the top of a call frame, as defined by tracers.Tracer.
+func (t *DelegateCallTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	// Pop the state for this call frame.
+	callFrameState := t.callFrameStates[len(t.callFrameStates)-1]
+	t.callFrameStates = t.callFrameStates[:len(t.callFrameStates)-1]
+
+	// If this call frame reverted, any changes made by delegate calls within it were reverted too.
+	if err != nil {
+		return
+	}
+
+	// If this is the top-level call frame, the delegate calls it recorded are the result for this transaction.
+	if depth == 0 {
+		t.delegateCalls = callFrameState.delegateCalls
+		return
+	}
+
+	// Otherwise, propagate the delegate call which entered this call frame, and those made within it, to the parent
+	// call frame.
+	parentCallFrameState := t.callFrameStates[len(t.callFrameStates)-1]
+	if callFrameState.delegateCall != nil {
+		parentCallFrameState.delegateCalls = append(parentCallFrameState.delegateCalls, callFrameState.delegateCall)
+	}
+	parentCallFrameState.delegateCalls = append(parentCallFrameState.delegateCalls, callFrameState.delegateCalls...)
+}
+
+// CaptureTxEndSetAdditionalResults can be used to set additional results captured from execution tracing. If this
+// tracer is used during transaction execution (block creation), the results can later be queried from the block.
+// This method will only be called on the added tracer if it implements the extended TestChainTracer interface.
+func (t *DelegateCallTracer) CaptureTxEndSetAdditionalResults(results *types.MessageResults) {
+	// Store our delegate calls in the results, only if any were recorded.
+	if len(t.delegateCalls) > 0 {
+		results.AdditionalResults[delegateCallTracerResultsKey] = t.delegateCalls
+	}
+}
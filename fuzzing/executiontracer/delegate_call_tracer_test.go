@@ -0,0 +1,80 @@
+package executiontracer
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/crytic/medusa/chain/testchainutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	// proxyRuntimeBytecode describes runtime bytecode which delegate calls the address provided as the first word of
+	// its call data.
+	proxyRuntimeBytecode = "60006000600060006000355af45000"
+
+	// revertingProxyRuntimeBytecode describes runtime bytecode which delegate calls the address provided as the first
+	// word of its call data, then reverts.
+	revertingProxyRuntimeBytecode = "60006000600060006000355af450" + "60006000fd"
+
+	// storageWriterRuntimeBytecode describes runtime bytecode which writes to the first storage slot.
+	storageWriterRuntimeBytecode = "600160005500"
+)
+
+// TestDelegateCallTracer tests that the DelegateCallTracer records delegate calls made in call frames which did not
+// revert, including those made in inner call frames, along with the call frame which made them.
+func TestDelegateCallTracer(t *testing.T) {
+	// Create a test chain with a funded sender, attaching our tracer.
+	testChain, sender := testchainutils.NewFundedTestChain(t, NewDelegateCallTracer().NativeTracer())
+
+	// Deploy our proxies, a contract to delegate call into, and a contract which calls a proxy with empty call data.
+	proxyAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, proxyRuntimeBytecode)
+	revertingProxyAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, revertingProxyRuntimeBytecode)
+	storageWriterAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, storageWriterRuntimeBytecode)
+	catcherAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, fmt.Sprintf(catcherRuntimeBytecodeFormat, proxyAddress.Bytes()))
+
+	// Calling a contract which makes no delegate calls should not record any.
+	results := testchainutils.SendMessage(t, testChain, sender, &storageWriterAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	assert.Nil(t, GetDelegateCallTracerResults(results))
+
+	// A delegate call to an address provided to the proxy should be recorded, and write to the proxy's storage.
+	results = testchainutils.SendMessage(t, testChain, sender, &proxyAddress, common.LeftPadBytes(storageWriterAddress.Bytes(), 32))
+	assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	delegateCalls := GetDelegateCallTracerResults(results)
+	assert.Len(t, delegateCalls, 1)
+	assert.EqualValues(t, &DelegateCall{
+		ContextAddress:    proxyAddress,
+		CallerCodeAddress: proxyAddress,
+		CallerSelector:    []byte{0, 0, 0, 0},
+		TargetAddress:     storageWriterAddress,
+		CallType:          vm.DELEGATECALL,
+	}, delegateCalls[0])
+	assert.EqualValues(t, common.BigToHash(big.NewInt(1)), testChain.State().GetState(proxyAddress, common.Hash{}))
+
+	// Results can be removed once they are no longer needed.
+	RemoveDelegateCallTracerResults(results)
+	assert.Nil(t, GetDelegateCallTracerResults(results))
+
+	// Delegate calls made in inner call frames should be recorded.
+	results = testchainutils.SendMessage(t, testChain, sender, &catcherAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	delegateCalls = GetDelegateCallTracerResults(results)
+	assert.Len(t, delegateCalls, 1)
+	assert.EqualValues(t, &DelegateCall{
+		ContextAddress:    proxyAddress,
+		CallerCodeAddress: proxyAddress,
+		TargetAddress:     common.Address{},
+		CallType:          vm.DELEGATECALL,
+	}, delegateCalls[0])
+
+	// Delegate calls made in call frames which reverted should not be recorded.
+	results = testchainutils.SendMessage(t, testChain, sender, &revertingProxyAddress, common.LeftPadBytes(storageWriterAddress.Bytes(), 32))
+	assert.EqualValues(t, types.ReceiptStatusFailed, results.Receipt.Status)
+	assert.Nil(t, GetDelegateCallTracerResults(results))
+	assert.EqualValues(t, common.Hash{}, testChain.State().GetState(revertingProxyAddress, common.Hash{}))
+}
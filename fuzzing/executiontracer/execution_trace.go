@@ -34,6 +34,20 @@ type ExecutionTrace struct {
 
 	// labels is a mapping that maps an address to its string representation for cleaner execution traces
 	labels map[common.Address]string
+
+	// rendered describes the rendered execution trace, set once the trace was released with Release. If set, it is
+	// used to represent the trace, as the recorded call frames are no longer available.
+	rendered *logging.LogBuffer
+
+	// artifactPath describes the path of the TraceArtifact the rendered execution trace was written to with
+	// ReleaseTo, or an empty string if it was not. If set, the trace is read back from it to represent the trace.
+	artifactPath string
+
+	// artifactOffset describes the offset of the rendered execution trace within the file at artifactPath.
+	artifactOffset int64
+
+	// artifactLength describes the length of the rendered execution trace within the file at artifactPath.
+	artifactLength int64
 }
 
 // newExecutionTrace creates and returns a new ExecutionTrace, to be used by the ExecutionTracer.
@@ -241,26 +255,18 @@ func (t *ExecutionTrace) generateCallFrameExitElements(callFrame *CallFrame) []a
 		return elements
 	}
 
-	// Try to resolve an assertion failed panic code.
-	errorMessage := abiutils.GetSolidityRevertErrorString(callFrame.ReturnError, callFrame.ReturnData)
-	if errorMessage != nil {
-		elements = append(elements, colors.RedBold, fmt.Sprintf("[revert ('%v')]", *errorMessage), colors.Reset, "\n")
-		return elements
-	}
-
-	// Try to unpack a custom Solidity error from the return values.
-	matchedCustomError, unpackedCustomErrorArgs := abiutils.GetSolidityCustomRevertError(callFrame.CodeContractAbi, callFrame.ReturnError, callFrame.ReturnData)
-	if matchedCustomError != nil {
-		customErrorArgsDisplayText, err := valuegeneration.EncodeABIArgumentsToString(matchedCustomError.Inputs, unpackedCustomErrorArgs, t.labels)
-		if err == nil {
-			elements = append(elements, colors.RedBold, fmt.Sprintf("[revert (error: %v(%v))]", matchedCustomError.Name, customErrorArgsDisplayText), colors.Reset, "\n")
-			return elements
-		}
-	}
-
-	// Check if this is a generic revert.
+	// If this is a revert, decode the revert reason. Custom errors are resolved against the called contract's ABI
+	// first, followed by all other known contract ABIs, as errors may bubble up from inner calls.
 	if errors.Is(callFrame.ReturnError, vm.ErrExecutionReverted) {
-		elements = append(elements, colors.RedBold, "[revert]", colors.Reset, "\n")
+		contractAbis := make([]*abi.ABI, 0, len(t.contractDefinitions)+1)
+		if callFrame.CodeContractAbi != nil {
+			contractAbis = append(contractAbis, callFrame.CodeContractAbi)
+		}
+		for _, contract := range t.contractDefinitions {
+			contractAbis = append(contractAbis, &contract.CompiledContract().Abi)
+		}
+		revertReason := DecodeRevertReason(callFrame.ReturnData, contractAbis, t.labels)
+		elements = append(elements, colors.RedBold, fmt.Sprintf("[%v]", revertReason), colors.Reset, "\n")
 		return elements
 	}
 
@@ -367,6 +373,11 @@ func (t *ExecutionTrace) generateElementsAndLogsForCallFrame(currentDepth int, c
 			}
 		}
 
+		// If any operations were omitted due to trace limits, add a marker for them.
+		if callFrame.OmittedOperations > 0 {
+			elements = append(elements, prefix, colors.YellowBold, fmt.Sprintf("[truncated] %d operation(s) omitted due to trace limits", callFrame.OmittedOperations), colors.Reset, "\n")
+		}
+
 		// If we self-destructed, add a message for it before our footer.
 		if callFrame.SelfDestructed {
 			elements = append(elements, prefix, colors.RedBold, "[selfdestruct]", colors.Reset, "\n")
@@ -388,6 +399,22 @@ func (t *ExecutionTrace) Log() *logging.LogBuffer {
 	// Create a buffer
 	buffer := logging.NewLogBuffer()
 
+	// If the trace was already rendered and released, use the rendered trace, reading it back from the trace artifact
+	// it was written to if needed.
+	if t.artifactPath != "" {
+		rendered, err := readTraceArtifact(t.artifactPath, t.artifactOffset, t.artifactLength)
+		if err != nil {
+			buffer.Append(colors.RedBold, fmt.Sprintf("[execution trace unavailable, it could not be read from %s: %v]", t.artifactPath, err), colors.Reset, "\n")
+			return buffer
+		}
+		buffer.Append(rendered)
+		return buffer
+	}
+	if t.rendered != nil {
+		buffer.Append(t.rendered.Elements()...)
+		return buffer
+	}
+
 	// First, add the elements that make up the overarching execution trace
 	elements, logs := t.generateElementsAndLogsForCallFrame(0, t.TopLevelCallFrame)
 	buffer.Append(elements...)
@@ -401,6 +428,36 @@ func (t *ExecutionTrace) Log() *logging.LogBuffer {
 	return buffer
 }
 
+// Release renders the execution trace and releases the recorded call frames, so the memory they consume can be freed
+// while the trace can still be logged. This is useful when many traces must be held, as the recorded call frames
+// reference call data, return data, and bytecode for every call scope. After releasing, TopLevelCallFrame is nil.
+func (t *ExecutionTrace) Release() {
+	if t.rendered == nil && t.TopLevelCallFrame != nil {
+		t.rendered = t.Log()
+	}
+	t.TopLevelCallFrame = nil
+	t.contractDefinitions = nil
+}
+
+// ReleaseTo renders the execution trace and writes it to the provided TraceArtifact, releasing the recorded call
+// frames, so the trace does not need to be held in memory, while it can still be logged by reading it back. As the
+// trace is written as plain text, it is logged without colors.
+// Returns an error if the trace could not be written, in which case it is not released.
+func (t *ExecutionTrace) ReleaseTo(artifact *TraceArtifact) error {
+	if t.artifactPath != "" {
+		return nil
+	}
+	offset, length, err := artifact.write(t.String())
+	if err != nil {
+		return err
+	}
+	t.artifactPath, t.artifactOffset, t.artifactLength = artifact.Path(), offset, length
+	t.rendered = nil
+	t.TopLevelCallFrame = nil
+	t.contractDefinitions = nil
+	return nil
+}
+
 // String returns the string representation of this execution trace
 func (t *ExecutionTrace) String() string {
 	// Internally, we just call the log function, get the list of elements and create their non-colorized string representation
@@ -0,0 +1,36 @@
+package executiontracer
+
+import (
+	"testing"
+
+	"github.com/crytic/medusa/chain/testchainutils"
+	"github.com/crytic/medusa/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExecutionTraceAddressLabels tests that addresses in an execution trace are displayed alongside the label the
+// chain has for them, and that unlabeled addresses are displayed on their own.
+func TestExecutionTraceAddressLabels(t *testing.T) {
+	// Create a test chain with a funded sender, attaching our tracer.
+	testChain, sender := testchainutils.NewFundedTestChain(t)
+	tracer := NewExecutionTracer(nil, testChain)
+	testChain.AddTracer(tracer.NativeTracer(), true, false)
+
+	// Deploy two contracts which stop immediately, labeling only the sender and the first contract.
+	labeledAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, "00")
+	unlabeledAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, "00")
+	testChain.Labels[sender] = "sender[0]"
+	testChain.Labels[labeledAddress] = "Vault"
+
+	// Calling the labeled contract should display both labels.
+	results := testchainutils.SendMessage(t, testChain, sender, &labeledAddress, nil)
+	traceMessage := tracer.GetTrace(results.Receipt.TxHash).Log().String()
+	assert.Contains(t, traceMessage, "addr=Vault ["+utils.TrimLeadingZeroesFromAddress(labeledAddress)+"]")
+	assert.Contains(t, traceMessage, "sender=sender[0] [0x10000]")
+
+	// Calling the unlabeled contract should display its address alone.
+	results = testchainutils.SendMessage(t, testChain, sender, &unlabeledAddress, nil)
+	traceMessage = tracer.GetTrace(results.Receipt.TxHash).Log().String()
+	assert.Contains(t, traceMessage, "addr="+utils.TrimLeadingZeroesFromAddress(unlabeledAddress)+",")
+	assert.NotContains(t, traceMessage, "Vault")
+}
@@ -39,6 +39,20 @@ func CallWithExecutionTrace(testChain *chain.TestChain, contractDefinitions cont
 	return executionResult, trace, nil
 }
 
+// TraceLimits describes limits on the size of the execution traces recorded by an ExecutionTracer, used to bound the
+// memory consumed when tracing calls with deep or long call stacks. Call frames and events exceeding the limits are
+// omitted from the trace, and counted by the call frame they would have been recorded in, so the omission can be
+// displayed.
+type TraceLimits struct {
+	// MaxDepth describes the maximum depth of call frames recorded, where the top level call frame has a depth of
+	// zero. A zero value indicates no limit should be enforced.
+	MaxDepth int
+
+	// MaxOperations describes the maximum amount of call frames and events recorded in a single execution trace.
+	// The top level call frame is always recorded. A zero value indicates no limit should be enforced.
+	MaxOperations int
+}
+
 // ExecutionTracer records execution information into an ExecutionTrace, containing information about each call
 // scope entered and exited.
 type ExecutionTracer struct {
@@ -58,6 +72,16 @@ type ExecutionTracer struct {
 	// currentCallFrame references the current call frame being traced.
 	currentCallFrame *CallFrame
 
+	// limits describes the limits on the size of the execution traces recorded.
+	limits TraceLimits
+
+	// operationCount describes the amount of call frames and events recorded in the current execution trace.
+	operationCount int
+
+	// omittedDepth describes the amount of nested call frames currently entered which are omitted from the current
+	// execution trace, as they exceeded the trace limits. Nothing is recorded while this is non-zero.
+	omittedDepth int
+
 	// contractDefinitions represents the contract definitions to match for execution traces.
 	contractDefinitions contracts.Contracts
 
@@ -98,6 +122,18 @@ func (t *ExecutionTracer) NativeTracer() *chain.TestChainTracer {
 
 }
 
+// SetLimits sets the limits on the size of execution traces recorded by this tracer. This should be called before
+// any transaction is traced.
+func (t *ExecutionTracer) SetLimits(limits TraceLimits) {
+	t.limits = limits
+}
+
+// ReleaseTrace removes the execution trace recorded for the provided transaction hash from the tracer, so the memory
+// it consumes can be freed once it is no longer referenced elsewhere.
+func (t *ExecutionTracer) ReleaseTrace(txHash common.Hash) {
+	delete(t.traceMap, txHash)
+}
+
 // Close sets the traceMap to nil and should be called after the execution tracer is finish being used.
 func (t *ExecutionTracer) Close() {
 	t.traceMap = nil
@@ -128,6 +164,8 @@ func (t *ExecutionTracer) OnTxStart(vm *tracing.VMContext, tx *coretypes.Transac
 	t.trace = newExecutionTrace(t.contractDefinitions, t.testChain.Labels)
 	t.currentCallFrame = nil
 	t.onNextCaptureState = nil
+	t.operationCount = 0
+	t.omittedDepth = 0
 
 	// Store our evm reference
 	t.evmContext = vm
@@ -262,14 +300,41 @@ func (t *ExecutionTracer) captureExitedCallFrame(output []byte, err error) {
 	t.currentCallFrame = t.currentCallFrame.ParentCallFrame
 }
 
+// operationLimitReached indicates whether the current execution trace recorded as many call frames and events as the
+// trace limits allow.
+func (t *ExecutionTracer) operationLimitReached() bool {
+	return t.limits.MaxOperations > 0 && t.operationCount >= t.limits.MaxOperations
+}
+
 // OnEnter initializes the tracing operation for the top of a call frame, as defined by tracers.Tracer.
 func (t *ExecutionTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// If we are within an omitted call frame, this call frame is omitted as well.
+	if t.omittedDepth > 0 {
+		t.omittedDepth++
+		return
+	}
+
+	// If this call frame exceeds our trace limits, omit it and record the omission in its parent call frame. The top
+	// level call frame is always recorded.
+	if t.currentCallFrame != nil && ((t.limits.MaxDepth > 0 && depth > t.limits.MaxDepth) || t.operationLimitReached()) {
+		t.currentCallFrame.OmittedOperations++
+		t.omittedDepth = 1
+		return
+	}
+
 	// Capture that a new call frame was entered.
+	t.operationCount++
 	t.captureEnteredCallFrame(from, to, input, (typ == byte(vm.CREATE) || typ == byte(vm.CREATE2)), value)
 }
 
 // OnExit is called after a call to finalize tracing completes for the top of a call frame, as defined by tracers.Tracer.
 func (t *ExecutionTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	// If we are exiting an omitted call frame, there is nothing to capture.
+	if t.omittedDepth > 0 {
+		t.omittedDepth--
+		return
+	}
+
 	// Capture that the call frame was exited.
 	t.captureExitedCallFrame(output, err)
 }
@@ -282,6 +347,11 @@ func (t *ExecutionTracer) OnOpcode(pc uint64, op byte, gas, cost uint64, scope t
 	}
 	t.onNextCaptureState = nil
 
+	// If we are within an omitted call frame, there is nothing to record.
+	if t.omittedDepth > 0 {
+		return
+	}
+
 	// Now that we have executed some code, we have access to the VM scope. From this, we can populate more
 	// information about our call frame. If this is a delegate or proxy call, the sender/to/code addresses should
 	// be appropriately represented in this structure. The information populated earlier on frame enter represents
@@ -306,6 +376,12 @@ func (t *ExecutionTracer) OnOpcode(pc uint64, op byte, gas, cost uint64, scope t
 		t.onNextCaptureState = append(t.onNextCaptureState, func() {
 			logs := t.evmContext.StateDB.(types.MedusaStateDB).Logs()
 			if len(logs) > 0 {
+				// If the event exceeds our trace limits, omit it and record the omission instead.
+				if t.operationLimitReached() {
+					t.currentCallFrame.OmittedOperations++
+					return
+				}
+				t.operationCount++
 				t.currentCallFrame.Operations = append(t.currentCallFrame.Operations, logs[len(logs)-1])
 			}
 		})
@@ -0,0 +1,106 @@
+package executiontracer
+
+import (
+	"math/big"
+	"runtime"
+	"testing"
+
+	"github.com/crytic/medusa/chain"
+	"github.com/crytic/medusa/chain/testchainutils"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// recursiveRuntimeBytecode describes runtime bytecode which emits an event and calls itself with all available gas,
+// recursing until it runs out of gas.
+var recursiveRuntimeBytecode = "60006000a0" + "60006000600060006000305af100"
+
+// callDepth returns the depth of the deepest call frame in the provided execution trace, following the first child
+// call frame at each level.
+func callDepth(trace *ExecutionTrace) int {
+	depth := 0
+	for callFrame := trace.TopLevelCallFrame; len(callFrame.ChildCallFrames()) > 0; depth++ {
+		callFrame = callFrame.ChildCallFrames()[0]
+	}
+	return depth
+}
+
+// TestExecutionTracerLimits tests that the ExecutionTracer omits call frames and events exceeding its trace limits
+// when tracing an artificially deep recursive call, marking the omissions in the rendered trace, and that doing so
+// bounds the memory allocated while tracing.
+func TestExecutionTracerLimits(t *testing.T) {
+	// Create a test chain with a funded sender and a recursive contract.
+	testChain, sender := testchainutils.NewFundedTestChain(t)
+	recursiveAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, recursiveRuntimeBytecode)
+	msg := &core.Message{
+		To:                &recursiveAddress,
+		From:              sender,
+		Value:             big.NewInt(0),
+		GasLimit:          testChain.BlockGasLimit,
+		GasPrice:          big.NewInt(1),
+		GasFeeCap:         big.NewInt(0),
+		GasTipCap:         big.NewInt(0),
+		SkipAccountChecks: true,
+	}
+
+	// traceCall traces our recursive call with the provided limits (or without tracing if nil), returning the last
+	// trace recorded and the average bytes allocated per call.
+	traceCall := func(limits *TraceLimits) (*ExecutionTrace, int64) {
+		const runs = 10
+		var trace *ExecutionTrace
+		var memStatsBefore, memStatsAfter runtime.MemStats
+		runtime.ReadMemStats(&memStatsBefore)
+		for i := 0; i < runs; i++ {
+			var tracers []*chain.TestChainTracer
+			var tracer *ExecutionTracer
+			if limits != nil {
+				tracer = NewExecutionTracer(nil, testChain)
+				tracer.SetLimits(*limits)
+				tracers = append(tracers, tracer.NativeTracer())
+			}
+			_, err := testChain.CallContract(msg, nil, tracers...)
+			assert.NoError(t, err)
+			if tracer != nil {
+				assert.Len(t, tracer.traceMap, 1)
+				for _, recordedTrace := range tracer.traceMap {
+					trace = recordedTrace
+				}
+			}
+		}
+		runtime.ReadMemStats(&memStatsAfter)
+		return trace, int64(memStatsAfter.TotalAlloc-memStatsBefore.TotalAlloc) / runs
+	}
+
+	// Without limits, the entire recursion should be recorded, without any truncation markers. We execute the call
+	// without tracing first, so the allocations of an untraced call are measured once any caches are warm.
+	traceCall(nil)
+	_, untracedAllocated := traceCall(nil)
+	unlimitedTrace, unlimitedAllocated := traceCall(&TraceLimits{})
+	unlimitedDepth := callDepth(unlimitedTrace)
+	assert.Greater(t, unlimitedDepth, 100)
+	assert.NotContains(t, unlimitedTrace.String(), "[truncated]")
+
+	// With a depth limit, call frames beyond the limit should be omitted, and the omission marked in the deepest call
+	// frame recorded.
+	depthLimitedTrace, depthLimitedAllocated := traceCall(&TraceLimits{MaxDepth: 5})
+	assert.EqualValues(t, 5, callDepth(depthLimitedTrace))
+	assert.Contains(t, depthLimitedTrace.String(), "[truncated] 1 operation(s) omitted due to trace limits")
+
+	// With an operation limit, only the first call frames and events should be recorded. As each call frame emits an
+	// event before entering the next, the limit is reached by the event of the tenth call frame, after which its
+	// child call frame is omitted.
+	operationLimitedTrace, operationLimitedAllocated := traceCall(&TraceLimits{MaxOperations: 20})
+	assert.EqualValues(t, 9, callDepth(operationLimitedTrace))
+	assert.Contains(t, operationLimitedTrace.String(), "[truncated] 1 operation(s) omitted due to trace limits")
+
+	// The memory allocated for the limited traces should be a small fraction of that for the unlimited trace.
+	unlimitedTracingAllocated := unlimitedAllocated - untracedAllocated
+	assert.Less(t, depthLimitedAllocated-untracedAllocated, unlimitedTracingAllocated/4)
+	assert.Less(t, operationLimitedAllocated-untracedAllocated, unlimitedTracingAllocated/4)
+
+	// Releasing a trace should discard its call frames, while retaining its rendered output.
+	renderedTrace := unlimitedTrace.String()
+	unlimitedTrace.Release()
+	assert.Nil(t, unlimitedTrace.TopLevelCallFrame)
+	assert.Equal(t, renderedTrace, unlimitedTrace.String())
+}
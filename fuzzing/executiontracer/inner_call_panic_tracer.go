@@ -0,0 +1,232 @@
+package executiontracer
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/crytic/medusa/chain"
+	"github.com/crytic/medusa/chain/types"
+	"github.com/crytic/medusa/compilation/abiutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	coretypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+// innerCallPanicTracerResultsKey describes the key to use when storing tracer results in call message results, or when
+// querying them.
+const innerCallPanicTracerResultsKey = "InnerCallPanicTracerResults"
+
+// panicOriginTracerResultsKey describes the key to use when storing the origin of a top-level call frame's panic in call
+// message results, or when querying it.
+const panicOriginTracerResultsKey = "InnerCallPanicTracerPanicOrigin"
+
+// GetInnerCallPanicTracerResults obtains the InnerCallPanic list stored by an InnerCallPanicTracer from message
+// results. This is nil if no panics were recorded by a tracer (e.g. no inner call frame panicked, or the
+// InnerCallPanicTracer was not attached during this message execution).
+func GetInnerCallPanicTracerResults(messageResults *types.MessageResults) []*InnerCallPanic {
+	// Try to obtain the results the tracer should've stored.
+	if genericResult, ok := messageResults.AdditionalResults[innerCallPanicTracerResultsKey]; ok {
+		if castedResult, ok := genericResult.([]*InnerCallPanic); ok {
+			return castedResult
+		}
+	}
+
+	// If we could not obtain them, return nil.
+	return nil
+}
+
+// GetPanicOriginTracerResults obtains the InnerCallPanic describing the call frame in which the panic of the top-level
+// call frame originated, as stored by an InnerCallPanicTracer in message results. This is nil if the top-level call
+// frame did not panic, or the InnerCallPanicTracer was not attached during this message execution.
+func GetPanicOriginTracerResults(messageResults *types.MessageResults) *InnerCallPanic {
+	if genericResult, ok := messageResults.AdditionalResults[panicOriginTracerResultsKey]; ok {
+		if castedResult, ok := genericResult.(*InnerCallPanic); ok {
+			return castedResult
+		}
+	}
+	return nil
+}
+
+// RemoveInnerCallPanicTracerResults removes the InnerCallPanic list and panic origin stored by an
+// InnerCallPanicTracer from message results.
+func RemoveInnerCallPanicTracerResults(messageResults *types.MessageResults) {
+	delete(messageResults.AdditionalResults, innerCallPanicTracerResultsKey)
+	delete(messageResults.AdditionalResults, panicOriginTracerResultsKey)
+}
+
+// InnerCallPanic describes a Solidity panic which originated in an inner call frame of a transaction (a call frame
+// entered by a contract, rather than the top-level call frame). Such panics may be caught by the calling contract
+// (e.g. through try/catch or a low-level call), leaving the top-level call frame to succeed.
+type InnerCallPanic struct {
+	// CallerAddress describes the address which entered the call frame which panicked.
+	CallerAddress common.Address
+
+	// CodeAddress describes the address whose code was executed in the call frame which panicked. For delegate calls,
+	// this is the address of the library or contract whose code was executed, not the address whose storage was used.
+	CodeAddress common.Address
+
+	// CallType describes the opcode used to enter the call frame which panicked.
+	CallType vm.OpCode
+
+	// Depth describes the depth of the call frame which panicked, where the top-level call frame has a depth of zero.
+	Depth int
+
+	// PanicCode describes the Solidity panic code the call frame exited with.
+	PanicCode uint64
+}
+
+// InnerCallPanicTracer implements tracers.Tracer to record Solidity panics which originate in inner call frames, so
+// that they may be detected even if a calling contract handled them without reverting.
+type InnerCallPanicTracer struct {
+	// panics describes the panics recorded for the current transaction, in the order their call frames exited.
+	panics []*InnerCallPanic
+
+	// panicOrigin describes the call frame in which the panic of the top-level call frame originated, or nil if the
+	// top-level call frame did not panic. If the panic was not propagated from an inner call frame, this describes the
+	// top-level call frame itself, with a depth of zero.
+	panicOrigin *InnerCallPanic
+
+	// callFrameStates describes the state tracked by the tracer per call frame.
+	callFrameStates []*innerCallPanicTracerCallFrameState
+
+	// nativeTracer is the underlying tracer used to capture EVM execution.
+	nativeTracer *chain.TestChainTracer
+}
+
+// innerCallPanicTracerCallFrameState tracks state across call frames in the tracer.
+type innerCallPanicTracerCallFrameState struct {
+	// callerAddress describes the address which entered this call frame.
+	callerAddress common.Address
+
+	// codeAddress describes the address whose code is executed in this call frame.
+	codeAddress common.Address
+
+	// callType describes the opcode used to enter this call frame.
+	callType vm.OpCode
+
+	// lastChildPanic describes the panic the most recently exited child call frame exited with, or nil if it did not
+	// panic. This is used to determine whether a panic in this call frame was propagated from the child, rather than
+	// originating in this call frame.
+	lastChildPanic *InnerCallPanic
+
+	// lastChildReturnData describes the return data of the most recently exited child call frame, if it panicked.
+	lastChildReturnData []byte
+}
+
+// NewInnerCallPanicTracer returns a new InnerCallPanicTracer.
+func NewInnerCallPanicTracer() *InnerCallPanicTracer {
+	tracer := &InnerCallPanicTracer{
+		callFrameStates: make([]*innerCallPanicTracerCallFrameState, 0),
+	}
+	nativeTracer := &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart: tracer.OnTxStart,
+			OnEnter:   tracer.OnEnter,
+			OnExit:    tracer.OnExit,
+		},
+	}
+	tracer.nativeTracer = &chain.TestChainTracer{Tracer: nativeTracer, CaptureTxEndSetAdditionalResults: tracer.CaptureTxEndSetAdditionalResults}
+
+	return tracer
+}
+
+// NativeTracer returns the underlying TestChainTracer.
+func (t *InnerCallPanicTracer) NativeTracer() *chain.TestChainTracer {
+	return t.nativeTracer
+}
+
+// OnTxStart is called upon the start of transaction execution, as defined by tracers.Tracer.
+func (t *InnerCallPanicTracer) OnTxStart(vm *tracing.VMContext, tx *coretypes.Transaction, from common.Address) {
+	// Reset our call frame states and recorded panics
+	t.panics = nil
+	t.panicOrigin = nil
+	t.callFrameStates = make([]*innerCallPanicTracerCallFrameState, 0)
+}
+
+// OnEnter initializes the tracing operation for the top of a call frame, as defined by tracers.Tracer.
+func (t *InnerCallPanicTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// Create our state tracking struct for this frame.
+	t.callFrameStates = append(t.callFrameStates, &innerCallPanicTracerCallFrameState{
+		callerAddress: from,
+		codeAddress:   to,
+		callType:      vm.OpCode(typ),
+	})
+}
+
+// OnExit is called after a call to finalize tracing completes for the top of a call frame, as defined by tracers.Tracer.
+func (t *InnerCallPanicTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	// Pop the state for this call frame.
+	callFrameState := t.callFrameStates[len(t.callFrameStates)-1]
+	t.callFrameStates = t.callFrameStates[:len(t.callFrameStates)-1]
+
+	// Determine whether this call frame panicked.
+	var panicCode *big.Int
+	if err != nil {
+		panicCode = abiutils.GetSolidityPanicCode(err, output, true)
+	}
+	panicked := panicCode != nil && panicCode.IsUint64()
+
+	// The top-level call frame's result is available to callers through the execution result, so we only track
+	// panics in inner call frames. For the top-level call frame, we only record the call frame its panic originated in.
+	if depth == 0 {
+		if panicked {
+			t.panicOrigin = callFrameState.panicOrigin(depth, panicCode.Uint64(), output)
+		}
+		return
+	}
+	parentCallFrameState := t.callFrameStates[len(t.callFrameStates)-1]
+
+	// If this call frame did not panic, there is nothing to record.
+	if !panicked {
+		parentCallFrameState.lastChildPanic = nil
+		parentCallFrameState.lastChildReturnData = nil
+		return
+	}
+
+	// Record the panic if it originated in this call frame.
+	innerCallPanic := callFrameState.panicOrigin(depth, panicCode.Uint64(), output)
+	if innerCallPanic != callFrameState.lastChildPanic {
+		t.panics = append(t.panics, innerCallPanic)
+	}
+	parentCallFrameState.lastChildPanic = innerCallPanic
+	parentCallFrameState.lastChildReturnData = output
+}
+
+// panicOrigin obtains the InnerCallPanic describing the call frame in which a panic this call frame exited with
+// originated. If the panic was propagated from the last child call frame (e.g. a contract re-throwing the revert data
+// of a call it made), it is attributed to the call frame it originated in, which was already recorded. Otherwise, a
+// new InnerCallPanic describing this call frame is returned.
+func (s *innerCallPanicTracerCallFrameState) panicOrigin(depth int, panicCode uint64, output []byte) *InnerCallPanic {
+	if s.lastChildPanic != nil && s.lastChildPanic.PanicCode == panicCode && bytes.Equal(s.lastChildReturnData, output) {
+		return s.lastChildPanic
+	}
+	return &InnerCallPanic{
+		CallerAddress: s.callerAddress,
+		CodeAddress:   s.codeAddress,
+		CallType:      s.callType,
+		Depth:         depth,
+		PanicCode:     panicCode,
+	}
+}
+
+// PanicOrigin obtains the InnerCallPanic describing the call frame in which the panic of the top-level call frame of
+// the last traced transaction or call originated. Depth is zero if the panic originated in the top-level call frame.
+// Returns nil if the top-level call frame did not panic.
+func (t *InnerCallPanicTracer) PanicOrigin() *InnerCallPanic {
+	return t.panicOrigin
+}
+
+// CaptureTxEndSetAdditionalResults can be used to set additional results captured from execution tracing. If this
+// tracer is used during transaction execution (block creation), the results can later be queried from the block.
+// This method will only be called on the added tracer if it implements the extended TestChainTracer interface.
+func (t *InnerCallPanicTracer) CaptureTxEndSetAdditionalResults(results *types.MessageResults) {
+	// Store our panics in the results, only if any were recorded.
+	if len(t.panics) > 0 {
+		results.AdditionalResults[innerCallPanicTracerResultsKey] = t.panics
+	}
+	if t.panicOrigin != nil {
+		results.AdditionalResults[panicOriginTracerResultsKey] = t.panicOrigin
+	}
+}
@@ -0,0 +1,139 @@
+package executiontracer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/crytic/medusa/chain/testchainutils"
+	"github.com/crytic/medusa/compilation/abiutils"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	// panicRuntimeBytecode describes runtime bytecode which always reverts with Panic(0x01), as a failed assertion does.
+	panicRuntimeBytecode = "634e487b7160e01b600052600160045260246000fd"
+
+	// catcherRuntimeBytecodeFormat describes runtime bytecode which calls the address formatted into it, ignoring
+	// whether the call succeeded.
+	catcherRuntimeBytecodeFormat = "60006000600060006000" + "73%x" + "5af15000"
+
+	// rethrowerRuntimeBytecodeFormat describes runtime bytecode which calls the address formatted into it, reverting
+	// with the same return data if the call failed.
+	rethrowerRuntimeBytecodeFormat = "60006000600060006000" + "73%x" + "5af1602e573d600060003e3d6000fd5b00"
+)
+
+// TestInnerCallPanicTracer tests that the InnerCallPanicTracer records panics which originate in inner call frames,
+// even if they are caught by the calling contract, and attributes propagated panics to the frame they originated in.
+func TestInnerCallPanicTracer(t *testing.T) {
+	// Create a test chain with a funded sender, attaching our tracer.
+	testChain, sender := testchainutils.NewFundedTestChain(t, NewInnerCallPanicTracer().NativeTracer())
+
+	// Deploy a contract which panics, along with contracts which catch its panic directly, after it was re-thrown by
+	// an intermediate contract, or through another catching contract. Lastly, deploy one which calls an account
+	// which does not panic.
+	panicAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, panicRuntimeBytecode)
+	catcherAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, fmt.Sprintf(catcherRuntimeBytecodeFormat, panicAddress.Bytes()))
+	rethrowerAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, fmt.Sprintf(rethrowerRuntimeBytecodeFormat, panicAddress.Bytes()))
+	rethrowCatcherAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, fmt.Sprintf(catcherRuntimeBytecodeFormat, rethrowerAddress.Bytes()))
+	nestedCatcherAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, fmt.Sprintf(catcherRuntimeBytecodeFormat, catcherAddress.Bytes()))
+	noPanicCatcherAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, fmt.Sprintf(catcherRuntimeBytecodeFormat, sender.Bytes()))
+
+	// Deployments do not panic, so nothing should have been recorded for them.
+	for _, block := range testChain.CommittedBlocks() {
+		for _, messageResults := range block.MessageResults {
+			assert.Nil(t, GetInnerCallPanicTracerResults(messageResults))
+		}
+	}
+
+	// A caught panic should be recorded, even though the transaction succeeds.
+	results := testchainutils.SendMessage(t, testChain, sender, &catcherAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	innerCallPanics := GetInnerCallPanicTracerResults(results)
+	assert.Len(t, innerCallPanics, 1)
+	assert.EqualValues(t, &InnerCallPanic{
+		CallerAddress: catcherAddress,
+		CodeAddress:   panicAddress,
+		CallType:      vm.CALL,
+		Depth:         1,
+		PanicCode:     abiutils.PanicCodeAssertFailed,
+	}, innerCallPanics[0])
+
+	// A panic which was re-thrown before being caught should only be recorded for the frame it originated in.
+	results = testchainutils.SendMessage(t, testChain, sender, &rethrowCatcherAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	innerCallPanics = GetInnerCallPanicTracerResults(results)
+	assert.Len(t, innerCallPanics, 1)
+	assert.EqualValues(t, rethrowerAddress, innerCallPanics[0].CallerAddress)
+	assert.EqualValues(t, panicAddress, innerCallPanics[0].CodeAddress)
+	assert.EqualValues(t, 2, innerCallPanics[0].Depth)
+
+	// A panic in the top-level call frame is not recorded, as it is available through the execution result.
+	results = testchainutils.SendMessage(t, testChain, sender, &panicAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusFailed, results.Receipt.Status)
+	assert.Nil(t, GetInnerCallPanicTracerResults(results))
+
+	// A panic caught deeper in the call stack should still be recorded for the frame it originated in.
+	results = testchainutils.SendMessage(t, testChain, sender, &nestedCatcherAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	innerCallPanics = GetInnerCallPanicTracerResults(results)
+	assert.Len(t, innerCallPanics, 1)
+	assert.EqualValues(t, catcherAddress, innerCallPanics[0].CallerAddress)
+	assert.EqualValues(t, 2, innerCallPanics[0].Depth)
+
+	// Results can be removed once they are no longer needed.
+	RemoveInnerCallPanicTracerResults(results)
+	assert.Nil(t, GetInnerCallPanicTracerResults(results))
+
+	// Inner calls which do not panic should not be recorded.
+	results = testchainutils.SendMessage(t, testChain, sender, &noPanicCatcherAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	assert.Nil(t, GetInnerCallPanicTracerResults(results))
+}
+
+// TestInnerCallPanicTracerPanicOrigin tests that the InnerCallPanicTracer records the call frame in which a panic of
+// the top-level call frame originated, whether it was propagated from an inner call frame or not.
+func TestInnerCallPanicTracerPanicOrigin(t *testing.T) {
+	// Create a test chain with a funded sender, attaching our tracer.
+	testChain, sender := testchainutils.NewFundedTestChain(t)
+	tracer := NewInnerCallPanicTracer()
+	testChain.AddTracer(tracer.NativeTracer(), true, false)
+
+	// Deploy a contract which panics, one which re-throws its panic, and one which catches it.
+	panicAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, panicRuntimeBytecode)
+	rethrowerAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, fmt.Sprintf(rethrowerRuntimeBytecodeFormat, panicAddress.Bytes()))
+	catcherAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, fmt.Sprintf(catcherRuntimeBytecodeFormat, panicAddress.Bytes()))
+
+	// A panic in the top-level call frame should originate in it.
+	results := testchainutils.SendMessage(t, testChain, sender, &panicAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusFailed, results.Receipt.Status)
+	assert.EqualValues(t, &InnerCallPanic{
+		CallerAddress: sender,
+		CodeAddress:   panicAddress,
+		CallType:      vm.CALL,
+		Depth:         0,
+		PanicCode:     abiutils.PanicCodeAssertFailed,
+	}, GetPanicOriginTracerResults(results))
+
+	// A panic which was re-thrown by the top-level call frame should originate in the inner call frame.
+	results = testchainutils.SendMessage(t, testChain, sender, &rethrowerAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusFailed, results.Receipt.Status)
+	panicOrigin := GetPanicOriginTracerResults(results)
+	if assert.NotNil(t, panicOrigin) {
+		assert.EqualValues(t, rethrowerAddress, panicOrigin.CallerAddress)
+		assert.EqualValues(t, panicAddress, panicOrigin.CodeAddress)
+		assert.EqualValues(t, 1, panicOrigin.Depth)
+		assert.Same(t, GetInnerCallPanicTracerResults(results)[0], panicOrigin)
+	}
+
+	// Results can be removed once they are no longer needed.
+	RemoveInnerCallPanicTracerResults(results)
+	assert.Nil(t, GetPanicOriginTracerResults(results))
+
+	// A caught panic should not be recorded as the origin of a panic, as the top-level call frame did not panic.
+	results = testchainutils.SendMessage(t, testChain, sender, &catcherAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	assert.Nil(t, GetPanicOriginTracerResults(results))
+	assert.Nil(t, tracer.PanicOrigin())
+}
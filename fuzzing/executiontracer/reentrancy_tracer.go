@@ -0,0 +1,244 @@
+package executiontracer
+
+import (
+	"math/big"
+	"slices"
+
+	"github.com/crytic/medusa/chain"
+	"github.com/crytic/medusa/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	coretypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+// reentrancyTracerResultsKey describes the key to use when storing tracer results in call message results, or when
+// querying them.
+const reentrancyTracerResultsKey = "ReentrancyTracerResults"
+
+// GetReentrancyTracerResults obtains the Reentrancy list stored by a ReentrancyTracer from message results. This is
+// nil if no reentrancy was recorded by a tracer (e.g. no state-changing reentrancy occurred, or the ReentrancyTracer
+// was not attached during this message execution).
+func GetReentrancyTracerResults(messageResults *types.MessageResults) []*Reentrancy {
+	// Try to obtain the results the tracer should've stored.
+	if genericResult, ok := messageResults.AdditionalResults[reentrancyTracerResultsKey]; ok {
+		if castedResult, ok := genericResult.([]*Reentrancy); ok {
+			return castedResult
+		}
+	}
+
+	// If we could not obtain them, return nil.
+	return nil
+}
+
+// RemoveReentrancyTracerResults removes the Reentrancy list stored by a ReentrancyTracer from message results.
+func RemoveReentrancyTracerResults(messageResults *types.MessageResults) {
+	delete(messageResults.AdditionalResults, reentrancyTracerResultsKey)
+}
+
+// Reentrancy describes a state-changing reentrancy which occurred in a transaction: a call frame re-entered a contract
+// which was already executing in an outer call frame, wrote to its storage, and the outer call frame wrote to the
+// contract's storage again after the reentrant call returned (e.g. updating a balance only after making an external
+// call, which the reentrant call could observe in its stale state).
+type Reentrancy struct {
+	// ContractAddress describes the address of the contract which was reentered, whose storage was written by both the
+	// reentrant and the outer call frame.
+	ContractAddress common.Address
+
+	// CallPath describes the call frames from the outer call frame executing in the reentered contract, to the
+	// reentrant call frame, inclusive.
+	CallPath []*ReentrancyCallFrame
+}
+
+// ReentrancyCallFrame describes a call frame in the call path of a Reentrancy.
+type ReentrancyCallFrame struct {
+	// ContextAddress describes the address whose storage is used by the call frame. For delegate calls, this is the
+	// address of the contract which made the call.
+	ContextAddress common.Address
+
+	// CodeAddress describes the address whose code was executed in the call frame. For delegate calls, this is the
+	// address of the library or contract whose code was executed.
+	CodeAddress common.Address
+
+	// CallType describes the opcode used to enter the call frame.
+	CallType vm.OpCode
+
+	// Selector describes the first four bytes of the call data the call frame was entered with, or nil if the call
+	// data was shorter, or the call frame is a contract creation.
+	Selector []byte
+}
+
+// ReentrancyTracer implements tracers.Tracer to record state-changing reentrancy into contracts, so that it may be
+// reported as a test failure.
+type ReentrancyTracer struct {
+	// reentrancies describes the reentrancy recorded for the current transaction.
+	reentrancies []*Reentrancy
+
+	// callFrameStates describes the state tracked by the tracer per call frame.
+	callFrameStates []*reentrancyTracerCallFrameState
+
+	// nativeTracer is the underlying tracer used to capture EVM execution.
+	nativeTracer *chain.TestChainTracer
+}
+
+// reentrancyTracerCallFrameState tracks state across call frames in the tracer.
+type reentrancyTracerCallFrameState struct {
+	// callFrame describes the call frame, as it would be reported in a Reentrancy call path.
+	callFrame *ReentrancyCallFrame
+
+	// storageWrites describes the addresses whose storage was written in this call frame, or in any child call frame
+	// which exited without reverting.
+	storageWrites map[common.Address]bool
+
+	// pendingReentrancy describes a reentrancy into this call frame's context address by a child call frame, which is
+	// only reported if this call frame writes to storage of the context address after the child call frame returned.
+	pendingReentrancy *Reentrancy
+
+	// reentrancies describes the reentrancy recorded in this call frame, or in any child call frame which exited
+	// without reverting.
+	reentrancies []*Reentrancy
+}
+
+// NewReentrancyTracer returns a new ReentrancyTracer.
+func NewReentrancyTracer() *ReentrancyTracer {
+	tracer := &ReentrancyTracer{
+		callFrameStates: make([]*reentrancyTracerCallFrameState, 0),
+	}
+	nativeTracer := &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart: tracer.OnTxStart,
+			OnEnter:   tracer.OnEnter,
+			OnExit:    tracer.OnExit,
+			OnOpcode:  tracer.OnOpcode,
+		},
+	}
+	tracer.nativeTracer = &chain.TestChainTracer{Tracer: nativeTracer, CaptureTxEndSetAdditionalResults: tracer.CaptureTxEndSetAdditionalResults}
+
+	return tracer
+}
+
+// NativeTracer returns the underlying TestChainTracer.
+func (t *ReentrancyTracer) NativeTracer() *chain.TestChainTracer {
+	return t.nativeTracer
+}
+
+// OnTxStart is called upon the start of transaction execution, as defined by tracers.Tracer.
+func (t *ReentrancyTracer) OnTxStart(vm *tracing.VMContext, tx *coretypes.Transaction, from common.Address) {
+	// Reset our call frame states and recorded reentrancy
+	t.reentrancies = nil
+	t.callFrameStates = make([]*reentrancyTracerCallFrameState, 0)
+}
+
+// OnEnter initializes the tracing operation for the top of a call frame, as defined by tracers.Tracer.
+func (t *ReentrancyTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// Delegate calls execute the code of the callee using the storage of the caller.
+	callType := vm.OpCode(typ)
+	contextAddress := to
+	if callType == vm.DELEGATECALL || callType == vm.CALLCODE {
+		contextAddress = from
+	}
+
+	// Contract creations are entered with init code, rather than call data with a selector.
+	var selector []byte
+	if callType != vm.CREATE && callType != vm.CREATE2 && len(input) >= 4 {
+		selector = slices.Clone(input[:4])
+	}
+
+	// Create our state tracking struct for this frame.
+	t.callFrameStates = append(t.callFrameStates, &reentrancyTracerCallFrameState{
+		callFrame: &ReentrancyCallFrame{
+			ContextAddress: contextAddress,
+			CodeAddress:    to,
+			CallType:       callType,
+			Selector:       selector,
+		},
+		storageWrites: make(map[common.Address]bool),
+	})
+}
+
+// OnExit is called after a call to finalize tracing completes for the top of a call frame, as defined by tracers.Tracer.
+func (t *ReentrancyTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	// Pop the state for this call frame.
+	callFrameState := t.callFrameStates[len(t.callFrameStates)-1]
+	t.callFrameStates = t.callFrameStates[:len(t.callFrameStates)-1]
+
+	// If this call frame reverted, its storage writes and any reentrancy within it were reverted too.
+	if err != nil {
+		return
+	}
+
+	// If this is the top-level call frame, the reentrancy it recorded is the result for this transaction.
+	if depth == 0 {
+		t.reentrancies = callFrameState.reentrancies
+		return
+	}
+
+	// Otherwise, propagate our storage writes and reentrancy to the parent call frame.
+	parentCallFrameState := t.callFrameStates[len(t.callFrameStates)-1]
+	for address := range callFrameState.storageWrites {
+		parentCallFrameState.storageWrites[address] = true
+	}
+	parentCallFrameState.reentrancies = append(parentCallFrameState.reentrancies, callFrameState.reentrancies...)
+
+	// If this call frame re-entered its context address and wrote to its storage, record a pending reentrancy with the
+	// outer call frame, to be reported if it writes to storage after this call frame returned.
+	if callFrameState.callFrame.CallType == vm.CALL && callFrameState.storageWrites[callFrameState.callFrame.ContextAddress] {
+		outerIndex := t.outerCallFrameIndex(callFrameState.callFrame.ContextAddress)
+		if outerIndex >= 0 && t.callFrameStates[outerIndex].pendingReentrancy == nil {
+			callPath := make([]*ReentrancyCallFrame, 0, len(t.callFrameStates)-outerIndex+1)
+			for _, pathCallFrameState := range t.callFrameStates[outerIndex:] {
+				callPath = append(callPath, pathCallFrameState.callFrame)
+			}
+			t.callFrameStates[outerIndex].pendingReentrancy = &Reentrancy{
+				ContractAddress: callFrameState.callFrame.ContextAddress,
+				CallPath:        append(callPath, callFrameState.callFrame),
+			}
+		}
+	}
+}
+
+// outerCallFrameIndex obtains the index of the innermost active call frame executing with the provided context
+// address, which is separated from the current call frame by a call frame executing with a different context address.
+// This excludes call frames which only reached the current call frame through calls to themselves or delegate calls.
+// Returns the index of the call frame, or -1 if no such call frame exists.
+func (t *ReentrancyTracer) outerCallFrameIndex(contextAddress common.Address) int {
+	exitedContext := false
+	for i := len(t.callFrameStates) - 1; i >= 0; i-- {
+		if t.callFrameStates[i].callFrame.ContextAddress != contextAddress {
+			exitedContext = true
+		} else if exitedContext {
+			return i
+		}
+	}
+	return -1
+}
+
+// OnOpcode records execution of an opcode within a call frame, as defined by tracers.Tracer.
+func (t *ReentrancyTracer) OnOpcode(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	// We only track storage writes.
+	if vm.OpCode(op) != vm.SSTORE {
+		return
+	}
+
+	// Record the write in the current call frame, then report any pending reentrancy into the written address by an
+	// active call frame, as the outer call frame wrote to storage after the reentrant call frame returned.
+	address := scope.Address()
+	t.callFrameStates[len(t.callFrameStates)-1].storageWrites[address] = true
+	for _, callFrameState := range t.callFrameStates {
+		if callFrameState.pendingReentrancy != nil && callFrameState.pendingReentrancy.ContractAddress == address {
+			callFrameState.reentrancies = append(callFrameState.reentrancies, callFrameState.pendingReentrancy)
+			callFrameState.pendingReentrancy = nil
+		}
+	}
+}
+
+// CaptureTxEndSetAdditionalResults can be used to set additional results captured from execution tracing. If this
+// tracer is used during transaction execution (block creation), the results can later be queried from the block.
+// This method will only be called on the added tracer if it implements the extended TestChainTracer interface.
+func (t *ReentrancyTracer) CaptureTxEndSetAdditionalResults(results *types.MessageResults) {
+	// Store our reentrancy in the results, only if any was recorded.
+	if len(t.reentrancies) > 0 {
+		results.AdditionalResults[reentrancyTracerResultsKey] = t.reentrancies
+	}
+}
@@ -0,0 +1,72 @@
+package executiontracer
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/crytic/medusa/chain/testchainutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	// vulnerableVaultRuntimeBytecode describes runtime bytecode which writes to storage, calls its caller, then writes
+	// to storage again after the call returns.
+	vulnerableVaultRuntimeBytecode = "6001600055" + "60006000600060006000335af150" + "6001600155" + "00"
+
+	// safeVaultRuntimeBytecode describes runtime bytecode which writes to storage, then calls its caller.
+	safeVaultRuntimeBytecode = "6001600055" + "60006000600060006000335af150" + "00"
+
+	// attackerRuntimeBytecodeFormat describes runtime bytecode which calls the address formatted into it the first two
+	// times it is entered, incrementing a counter in storage before each call.
+	attackerRuntimeBytecodeFormat = "600054806002111560345760010160005560006000600060006000" + "73%x" + "5af150005b00"
+)
+
+// TestReentrancyTracer tests that the ReentrancyTracer records reentrancy into a contract which writes to storage in
+// the reentrant call frame and the outer call frame, but not reentrancy where the outer call frame does not write to
+// storage after the reentrant call returned.
+func TestReentrancyTracer(t *testing.T) {
+	// Create a test chain with a funded sender, attaching our tracer.
+	testChain, sender := testchainutils.NewFundedTestChain(t, NewReentrancyTracer().NativeTracer())
+
+	// Deploy a vulnerable and a safe vault, each with an attacker which re-enters it once.
+	vulnerableVaultAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, vulnerableVaultRuntimeBytecode)
+	safeVaultAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, safeVaultRuntimeBytecode)
+	vulnerableVaultAttackerAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, fmt.Sprintf(attackerRuntimeBytecodeFormat, vulnerableVaultAddress.Bytes()))
+	safeVaultAttackerAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, fmt.Sprintf(attackerRuntimeBytecodeFormat, safeVaultAddress.Bytes()))
+
+	// Calling a vault directly does not re-enter it.
+	results := testchainutils.SendMessage(t, testChain, sender, &vulnerableVaultAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	assert.Nil(t, GetReentrancyTracerResults(results))
+
+	// Re-entering the vulnerable vault should be recorded, along with the call path from the outer call frame.
+	results = testchainutils.SendMessage(t, testChain, sender, &vulnerableVaultAttackerAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	reentrancies := GetReentrancyTracerResults(results)
+	assert.Len(t, reentrancies, 1)
+	assert.EqualValues(t, &Reentrancy{
+		ContractAddress: vulnerableVaultAddress,
+		CallPath: []*ReentrancyCallFrame{
+			{ContextAddress: vulnerableVaultAddress, CodeAddress: vulnerableVaultAddress, CallType: vm.CALL},
+			{ContextAddress: vulnerableVaultAttackerAddress, CodeAddress: vulnerableVaultAttackerAddress, CallType: vm.CALL},
+			{ContextAddress: vulnerableVaultAddress, CodeAddress: vulnerableVaultAddress, CallType: vm.CALL},
+		},
+	}, reentrancies[0])
+
+	// Results can be removed once they are no longer needed.
+	RemoveReentrancyTracerResults(results)
+	assert.Nil(t, GetReentrancyTracerResults(results))
+
+	// Re-entering the safe vault should not be recorded, as it does not write to storage after its call returns.
+	results = testchainutils.SendMessage(t, testChain, sender, &safeVaultAttackerAddress, nil)
+	assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	assert.Nil(t, GetReentrancyTracerResults(results))
+
+	// Both attackers should have re-entered their vault.
+	assert.EqualValues(t, common.BigToHash(big.NewInt(2)), testChain.State().GetState(vulnerableVaultAttackerAddress, common.Hash{}))
+	assert.EqualValues(t, common.BigToHash(big.NewInt(2)), testChain.State().GetState(safeVaultAttackerAddress, common.Hash{}))
+}
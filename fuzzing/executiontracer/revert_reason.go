@@ -0,0 +1,53 @@
+package executiontracer
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/crytic/medusa/compilation/abiutils"
+	"github.com/crytic/medusa/fuzzing/valuegeneration"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// DecodeRevertReason produces a human-readable description of the provided revert data. Error(string) messages and
+// Panic(uint256) codes are decoded directly, while custom errors are resolved by selector against the provided contract
+// ABIs (in order) and have their arguments decoded, using the provided labels to display addresses. If the revert data
+// could not be resolved, it is displayed as hex alongside its selector.
+// Returns a string describing the revert reason.
+func DecodeRevertReason(returnData []byte, contractAbis []*abi.ABI, labels map[common.Address]string) string {
+	// If there is no revert data, this is a generic revert.
+	if len(returnData) == 0 {
+		return "revert"
+	}
+
+	// Try to resolve a panic code.
+	panicCode := abiutils.GetSolidityPanicCode(vm.ErrExecutionReverted, returnData, false)
+	if panicCode != nil {
+		return abiutils.GetPanicReason(panicCode.Uint64())
+	}
+
+	// Try to resolve an error message.
+	errorMessage := abiutils.GetSolidityRevertErrorString(vm.ErrExecutionReverted, returnData)
+	if errorMessage != nil {
+		return fmt.Sprintf("revert ('%v')", *errorMessage)
+	}
+
+	// Try to unpack a custom Solidity error using each of the provided ABIs.
+	for _, contractAbi := range contractAbis {
+		matchedCustomError, unpackedCustomErrorArgs := abiutils.GetSolidityCustomRevertError(contractAbi, vm.ErrExecutionReverted, returnData)
+		if matchedCustomError != nil {
+			customErrorArgsDisplayText, err := valuegeneration.EncodeABIArgumentsToString(matchedCustomError.Inputs, unpackedCustomErrorArgs, labels)
+			if err == nil {
+				return fmt.Sprintf("revert (error: %v(%v))", matchedCustomError.Name, customErrorArgsDisplayText)
+			}
+		}
+	}
+
+	// If we could not resolve the revert data, we display it as raw data in the worst case.
+	if len(returnData) < 4 {
+		return fmt.Sprintf("revert (return_data=%v)", hex.EncodeToString(returnData))
+	}
+	return fmt.Sprintf("revert (unknown error: selector=0x%v, return_data=%v)", hex.EncodeToString(returnData[:4]), hex.EncodeToString(returnData))
+}
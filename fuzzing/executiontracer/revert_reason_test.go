@@ -0,0 +1,79 @@
+package executiontracer
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDecodeRevertReason tests decoding of Error(string) messages, Panic(uint256) codes, custom errors, and unknown
+// revert data into human-readable revert reasons.
+func TestDecodeRevertReason(t *testing.T) {
+	// Create an ABI defining a custom error.
+	contractAbi, err := abi.JSON(strings.NewReader(`[{"type":"error","name":"InsufficientBalance","inputs":[{"name":"account","type":"address"},{"name":"needed","type":"uint256"}]}]`))
+	assert.NoError(t, err)
+
+	// Encode our revert data for each case.
+	stringType, _ := abi.NewType("string", "", nil)
+	uintType, _ := abi.NewType("uint256", "", nil)
+	errorData, err := abi.Arguments{{Type: stringType}}.Pack("Hello from a revert!")
+	assert.NoError(t, err)
+	panicData, err := abi.Arguments{{Type: uintType}}.Pack(big.NewInt(0x11))
+	assert.NoError(t, err)
+	account := common.HexToAddress("0x10000")
+	customErrorData, err := contractAbi.Errors["InsufficientBalance"].Inputs.Pack(account, big.NewInt(7))
+	assert.NoError(t, err)
+	labels := map[common.Address]string{account: "Alice"}
+
+	tests := []struct {
+		// name describes the test case.
+		name string
+
+		// returnData describes the revert data to decode.
+		returnData []byte
+
+		// expected describes the expected revert reason.
+		expected string
+	}{
+		{
+			name:       "empty",
+			returnData: nil,
+			expected:   "revert",
+		},
+		{
+			name:       "error string",
+			returnData: append(common.FromHex("0x08c379a0"), errorData...),
+			expected:   "revert ('Hello from a revert!')",
+		},
+		{
+			name:       "panic",
+			returnData: append(common.FromHex("0x4e487b71"), panicData...),
+			expected:   "panic: arithmetic underflow/overflow",
+		},
+		{
+			name:       "custom error",
+			returnData: append(contractAbi.Errors["InsufficientBalance"].ID.Bytes()[:4], customErrorData...),
+			expected:   "revert (error: InsufficientBalance(Alice [0x10000], 7))",
+		},
+		{
+			name:       "unknown selector",
+			returnData: common.FromHex("0xdeadbeef0000000000000000000000000000000000000000000000000000000000000001"),
+			expected:   "revert (unknown error: selector=0xdeadbeef, return_data=deadbeef0000000000000000000000000000000000000000000000000000000000000001)",
+		},
+		{
+			name:       "short data",
+			returnData: common.FromHex("0xdead"),
+			expected:   "revert (return_data=dead)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.EqualValues(t, test.expected, DecodeRevertReason(test.returnData, []*abi.ABI{&contractAbi}, labels))
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package executiontracer
+
+import (
+	"slices"
+
+	"github.com/crytic/medusa/chain"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	coretypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+// storageWriteTracerMaxPreimageSize describes the maximum size of hashed data which is recorded as a preimage by a
+// StorageWriteTracer. Storage slots of mapping entries and dynamic arrays are derived from small preimages, so larger
+// hashed data is not recorded.
+const storageWriteTracerMaxPreimageSize = 128
+
+// StorageWrite describes a storage slot written by the transactions traced by a StorageWriteTracer.
+type StorageWrite struct {
+	// Address describes the address whose storage was written.
+	Address common.Address
+
+	// Slot describes the storage slot which was written.
+	Slot common.Hash
+
+	// Before describes the value of the storage slot before it was first written.
+	Before common.Hash
+}
+
+// StorageWriteTracer implements tracers.Tracer to record the storage slots written across all transactions it traces,
+// along with their values before they were first written, so the changes a call sequence made to storage can be
+// reported. It additionally records the preimages of data hashed during execution, from which the storage slots of
+// mapping entries and dynamic arrays may be resolved.
+type StorageWriteTracer struct {
+	// writes describes the storage slots written, in the order they were first written.
+	writes []*StorageWrite
+
+	// writeLookup describes the storage slots written, keyed by address and slot.
+	writeLookup map[common.Address]map[common.Hash]*StorageWrite
+
+	// preimages describes the data hashed during execution, keyed by its hash.
+	preimages map[common.Hash][]byte
+
+	// stateDB describes the state of the transaction currently being traced.
+	stateDB tracing.StateDB
+
+	// nativeTracer is the underlying tracer used to capture EVM execution.
+	nativeTracer *chain.TestChainTracer
+}
+
+// NewStorageWriteTracer returns a new StorageWriteTracer.
+func NewStorageWriteTracer() *StorageWriteTracer {
+	tracer := &StorageWriteTracer{
+		writeLookup: make(map[common.Address]map[common.Hash]*StorageWrite),
+		preimages:   make(map[common.Hash][]byte),
+	}
+	nativeTracer := &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart: tracer.OnTxStart,
+			OnOpcode:  tracer.OnOpcode,
+		},
+	}
+	tracer.nativeTracer = &chain.TestChainTracer{Tracer: nativeTracer, CaptureTxEndSetAdditionalResults: nil}
+
+	return tracer
+}
+
+// NativeTracer returns the underlying TestChainTracer.
+func (t *StorageWriteTracer) NativeTracer() *chain.TestChainTracer {
+	return t.nativeTracer
+}
+
+// Writes returns the storage slots written across all transactions traced, in the order they were first written.
+func (t *StorageWriteTracer) Writes() []*StorageWrite {
+	return t.writes
+}
+
+// Preimages returns the data hashed across all transactions traced, keyed by its keccak256 hash.
+func (t *StorageWriteTracer) Preimages() map[common.Hash][]byte {
+	return t.preimages
+}
+
+// OnTxStart is called upon the start of transaction execution, as defined by tracers.Tracer.
+func (t *StorageWriteTracer) OnTxStart(vm *tracing.VMContext, tx *coretypes.Transaction, from common.Address) {
+	// Store the state, so we can obtain the values of storage slots before they are written.
+	t.stateDB = vm.StateDB
+}
+
+// OnOpcode records execution of an opcode within a call frame, as defined by tracers.Tracer.
+func (t *StorageWriteTracer) OnOpcode(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	stackData := scope.StackData()
+	switch vm.OpCode(op) {
+	case vm.SSTORE:
+		// Record the first write to each storage slot, along with its value prior to it.
+		if len(stackData) < 1 {
+			return
+		}
+		address := scope.Address()
+		slot := common.Hash(stackData[len(stackData)-1].Bytes32())
+		addressWrites, ok := t.writeLookup[address]
+		if !ok {
+			addressWrites = make(map[common.Hash]*StorageWrite)
+			t.writeLookup[address] = addressWrites
+		}
+		if _, ok := addressWrites[slot]; ok {
+			return
+		}
+		write := &StorageWrite{Address: address, Slot: slot}
+		if t.stateDB != nil {
+			write.Before = t.stateDB.GetState(address, slot)
+		}
+		addressWrites[slot] = write
+		t.writes = append(t.writes, write)
+	case vm.KECCAK256:
+		// Record the preimage of hashed data small enough to derive a storage slot from.
+		if len(stackData) < 2 {
+			return
+		}
+		offset, size := stackData[len(stackData)-1], stackData[len(stackData)-2]
+		memory := scope.MemoryData()
+		if !offset.IsUint64() || !size.IsUint64() || size.Uint64() > storageWriteTracerMaxPreimageSize || offset.Uint64()+size.Uint64() > uint64(len(memory)) {
+			return
+		}
+		preimage := slices.Clone(memory[offset.Uint64() : offset.Uint64()+size.Uint64()])
+		t.preimages[crypto.Keccak256Hash(preimage)] = preimage
+	}
+}
@@ -0,0 +1,45 @@
+package executiontracer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/crytic/medusa/chain/testchainutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// slotWriterRuntimeBytecode describes runtime bytecode which writes 7 to the slot derived by hashing the word 42,
+// writes 1 to slot 0, then writes 5 to slot 1 before restoring it to 0.
+var slotWriterRuntimeBytecode = "602a600052" + "6020600020" + "60079055" + "6001600055" + "6005600155" + "6000600155" + "00"
+
+// TestStorageWriteTracer tests that the StorageWriteTracer records the storage slots written across all transactions
+// it traces, with their values before they were first written, along with the preimages of hashed data.
+func TestStorageWriteTracer(t *testing.T) {
+	// Create a test chain with a funded sender and deploy our slot writer.
+	testChain, sender := testchainutils.NewFundedTestChain(t)
+	slotWriterAddress := testchainutils.DeployRuntimeBytecode(t, testChain, sender, slotWriterRuntimeBytecode)
+
+	// Attach our tracer and call the slot writer twice.
+	tracer := NewStorageWriteTracer()
+	testChain.AddTracer(tracer.NativeTracer(), true, false)
+	for i := 0; i < 2; i++ {
+		results := testchainutils.SendMessage(t, testChain, sender, &slotWriterAddress, nil)
+		assert.EqualValues(t, types.ReceiptStatusSuccessful, results.Receipt.Status)
+	}
+
+	// Each slot should be recorded once, in the order it was first written, with its value prior to the first write.
+	preimage := common.BigToHash(big.NewInt(42)).Bytes()
+	hashedSlot := crypto.Keccak256Hash(preimage)
+	assert.EqualValues(t, []*StorageWrite{
+		{Address: slotWriterAddress, Slot: hashedSlot},
+		{Address: slotWriterAddress, Slot: common.BigToHash(big.NewInt(0))},
+		{Address: slotWriterAddress, Slot: common.BigToHash(big.NewInt(1))},
+	}, tracer.Writes())
+
+	// The hashed data should be recorded as a preimage.
+	assert.EqualValues(t, map[common.Hash][]byte{hashedSlot: preimage}, tracer.Preimages())
+	assert.EqualValues(t, common.BigToHash(big.NewInt(7)), testChain.State().GetState(slotWriterAddress, hashedSlot))
+}
@@ -0,0 +1,92 @@
+package executiontracer
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// TraceArtifact describes a file which rendered execution traces are written to as they are produced, so they do not
+// need to be held in memory. An ExecutionTrace written to a TraceArtifact is read back from the file when it is logged.
+type TraceArtifact struct {
+	// path describes the path of the file the execution traces are written to.
+	path string
+
+	// file describes the file the execution traces are written to, or nil if the TraceArtifact was closed.
+	file *os.File
+
+	// size describes the amount of bytes written to the file.
+	size int64
+
+	// lock provides thread synchronization when writing to the file.
+	lock sync.Mutex
+}
+
+// NewTraceArtifact creates a TraceArtifact which writes execution traces to a new file at the provided path, creating
+// its parent directory if it does not exist.
+// Returns the TraceArtifact, or an error if one occurs.
+func NewTraceArtifact(path string) (*TraceArtifact, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &TraceArtifact{
+		path: path,
+		file: file,
+	}, nil
+}
+
+// Path returns the path of the file the execution traces are written to.
+func (a *TraceArtifact) Path() string {
+	return a.path
+}
+
+// write appends the provided rendered execution trace to the file.
+// Returns the offset and length of the rendered execution trace within the file, or an error if one occurs.
+func (a *TraceArtifact) write(rendered string) (int64, int64, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.file == nil {
+		return 0, 0, os.ErrClosed
+	}
+	n, err := a.file.WriteString(rendered)
+	offset := a.size
+	a.size += int64(n)
+	return offset, int64(n), err
+}
+
+// Close closes the file the execution traces are written to. The execution traces written to it can still be read
+// back.
+// Returns an error if one occurs.
+func (a *TraceArtifact) Close() error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.file == nil {
+		return nil
+	}
+	err := a.file.Close()
+	a.file = nil
+	return err
+}
+
+// readTraceArtifact reads the rendered execution trace at the provided offset and length from the TraceArtifact file
+// at the provided path.
+// Returns the rendered execution trace, or an error if one occurs.
+func readTraceArtifact(path string, offset int64, length int64) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	data := make([]byte, length)
+	_, err = io.ReadFull(io.NewSectionReader(file, offset, length), data)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
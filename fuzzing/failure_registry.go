@@ -0,0 +1,89 @@
+package fuzzing
+
+import (
+	"sync"
+)
+
+// failureRegistry tracks the failures discovered by every FuzzerWorker during a fuzzing campaign, so that a failure
+// which manifests through many different call sequences is only shrunk and reported once. Failures are identified by
+// the ShrinkCallSequenceRequest.FailureID of the shrink request which reported them.
+type failureRegistry struct {
+	// discoveries describes the count of times each failure was discovered, keyed by failure identifier.
+	discoveries map[string]uint64
+
+	// claimed describes the identifiers of the failures which were claimed to be shrunk by a worker.
+	claimed map[string]struct{}
+
+	// duplicateCount describes the count of discoveries of failures which were already known.
+	duplicateCount uint64
+
+	// lock provides thread-synchronization, as failures are registered by every FuzzerWorker.
+	lock sync.Mutex
+}
+
+// newFailureRegistry creates a new, empty failureRegistry.
+func newFailureRegistry() *failureRegistry {
+	return &failureRegistry{
+		discoveries: make(map[string]uint64),
+		claimed:     make(map[string]struct{}),
+	}
+}
+
+// register records a discovery of the failure with the provided identifier, claiming it to be shrunk if it was not
+// already claimed.
+// Returns true if the failure was not previously claimed, and should be shrunk. Failures with an empty identifier
+// are never considered known.
+func (r *failureRegistry) register(failureID string) bool {
+	if failureID == "" {
+		return true
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.discoveries[failureID]++
+	if _, claimed := r.claimed[failureID]; claimed {
+		r.duplicateCount++
+		return false
+	}
+	r.claimed[failureID] = struct{}{}
+	return true
+}
+
+// recordDuplicate records a discovery of the failure with the provided identifier, which is already known and will
+// not be shrunk again, without claiming it.
+func (r *failureRegistry) recordDuplicate(failureID string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.discoveries[failureID]++
+	r.duplicateCount++
+}
+
+// unregister releases the claim on the failure with the provided identifier, which was not reported, so it is shrunk
+// again once it is rediscovered.
+func (r *failureRegistry) unregister(failureID string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.claimed, failureID)
+}
+
+// known indicates whether the failure with the provided identifier was already claimed to be shrunk.
+func (r *failureRegistry) known(failureID string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	_, claimed := r.claimed[failureID]
+	return claimed
+}
+
+// discoveryCount returns the count of times the failure with the provided identifier was discovered.
+func (r *failureRegistry) discoveryCount(failureID string) uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.discoveries[failureID]
+}
+
+// duplicates returns the count of discoveries of failures which were already known, and were not shrunk.
+func (r *failureRegistry) duplicates() uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.duplicateCount
+}
@@ -0,0 +1,40 @@
+package fuzzing
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFailureRegistry tests that only the first discovery of each failure is reported as new, including when
+// failures are registered concurrently, and that failures without an identifier are never considered known.
+func TestFailureRegistry(t *testing.T) {
+	registry := newFailureRegistry()
+
+	// Register the same failure from many goroutines at once. Exactly one should observe it as new.
+	var wg sync.WaitGroup
+	var newCount int
+	var newCountLock sync.Mutex
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if registry.register("PROPERTY-TestContract-property_a()") {
+				newCountLock.Lock()
+				newCount++
+				newCountLock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 1, newCount)
+	assert.EqualValues(t, 16, registry.discoveryCount("PROPERTY-TestContract-property_a()"))
+	assert.EqualValues(t, 15, registry.duplicates())
+
+	// A different failure is new, while failures without an identifier are always new.
+	assert.True(t, registry.register("PROPERTY-TestContract-property_b()"))
+	assert.True(t, registry.register(""))
+	assert.True(t, registry.register(""))
+	assert.EqualValues(t, 15, registry.duplicates())
+}
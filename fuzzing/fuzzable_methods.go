@@ -0,0 +1,177 @@
+package fuzzing
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	compilationTypes "github.com/crytic/medusa/compilation/types"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	fuzzingutils "github.com/crytic/medusa/fuzzing/utils"
+	"github.com/crytic/medusa/fuzzing/valuegeneration"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/exp/maps"
+)
+
+// noFuzzableMethodsPollInterval describes the interval at which a worker without any methods to call checks whether
+// methods were added, if it is configured to wait for dynamically deployed targets.
+const noFuzzableMethodsPollInterval = 100 * time.Millisecond
+
+// hasFuzzableMethods indicates whether the worker has any methods it can call to generate call sequences.
+func (fw *FuzzerWorker) hasFuzzableMethods() bool {
+	return len(fw.stateChangingMethods) > 0 || len(fw.pureMethods) > 0
+}
+
+// checkFuzzableMethods checks whether the worker has any methods to call once its chain is set up. If it does not, it
+// returns an error describing why every candidate method was excluded, unless the worker is configured to wait for
+// dynamically deployed targets, in which case the reasons are logged once and the worker tests corpus call sequences
+// until they deploy contracts which add methods. As no targets can be deployed without a corpus call sequence to
+// deploy them, an error is still returned if the corpus has none.
+// Returns an error if the worker cannot fuzz.
+func (fw *FuzzerWorker) checkFuzzableMethods() error {
+	if fw.hasFuzzableMethods() {
+		return nil
+	}
+
+	// Describe why each candidate method was excluded.
+	exclusions := fw.methodExclusions()
+	description := "no methods could be selected for fuzzing, as no deployed contract has a method which is not excluded"
+	if len(exclusions) > 0 {
+		description += ":\n\t" + strings.Join(exclusions, "\n\t")
+	}
+	if !fw.fuzzer.config.Fuzzing.Testing.WaitForDynamicTargets {
+		return fmt.Errorf("%s\nupdate the target contracts, excluded contracts, or function signature filters, or enable waitForDynamicTargets if targets are deployed while fuzzing", description)
+	}
+	if fw.fuzzer.corpus.ActiveMutableSequenceCount() == 0 {
+		return fmt.Errorf("%s\nwaitForDynamicTargets is enabled, but the corpus has no call sequences which could deploy targets", description)
+	}
+	fw.fuzzer.noFuzzableMethodsWarning.Do(func() {
+		fw.fuzzer.logger.Warn(description, "\nWorkers will test corpus call sequences until they deploy contracts which add methods to fuzz")
+	})
+	return nil
+}
+
+// awaitFuzzableMethods idles the worker for noFuzzableMethodsPollInterval if it has neither methods to call nor
+// corpus call sequences to test, so it can check again whether either was added, rather than failing to generate a
+// call sequence.
+// Returns a boolean indicating whether the worker idled.
+func (fw *FuzzerWorker) awaitFuzzableMethods() bool {
+	if fw.hasFuzzableMethods() || fw.corpusActiveMutableSequenceCount() > 0 {
+		return false
+	}
+	fw.liveness.setPhase(workerPhaseIdle)
+	select {
+	case <-fw.fuzzer.ctx.Done():
+	case <-fw.fuzzer.emergencyCtx.Done():
+	case <-time.After(noFuzzableMethodsPollInterval):
+	}
+	return true
+}
+
+// methodExclusions describes why each method of the contracts deployed on the worker's chain is not called by it, as
+// well as the interfaces in the compilation targets, whose methods are never called as they are not deployed. These
+// are sorted by contract name, then address, then method signature.
+func (fw *FuzzerWorker) methodExclusions() []string {
+	exclusions := make([]string, 0)
+
+	// Interfaces are skipped when contract definitions are created, so they cannot be deployed.
+	interfaceNames := make([]string, 0)
+	for _, compilation := range fw.fuzzer.compilations {
+		for _, source := range compilation.SourcePathToArtifact {
+			for contractName, contract := range source.Contracts {
+				if contract.Kind == compilationTypes.ContractKindInterface && !slices.Contains(interfaceNames, contractName) {
+					interfaceNames = append(interfaceNames, contractName)
+				}
+			}
+		}
+	}
+	slices.Sort(interfaceNames)
+	for _, interfaceName := range interfaceNames {
+		exclusions = append(exclusions, fmt.Sprintf("%s: interface-only contract, which cannot be deployed", interfaceName))
+	}
+
+	// Sort our deployed contracts, so the exclusions are reported in a stable order.
+	deployedContracts := fw.DeployedContracts()
+	addresses := make([]common.Address, 0, len(deployedContracts))
+	for address := range deployedContracts {
+		addresses = append(addresses, address)
+	}
+	slices.SortFunc(addresses, func(a, b common.Address) int {
+		if c := strings.Compare(deployedContracts[a].Name(), deployedContracts[b].Name()); c != 0 {
+			return c
+		}
+		return a.Cmp(b)
+	})
+
+	// Describe why each method of each deployed contract was excluded.
+	for _, address := range addresses {
+		contract := deployedContracts[address]
+		location := fmt.Sprintf("%s (%s)", contract.Name(), address.String())
+		if slices.Contains(fw.fuzzer.config.Fuzzing.Testing.ExcludeContracts, contract.Name()) {
+			exclusions = append(exclusions, fmt.Sprintf("%s: contract excluded by excludeContracts", location))
+			continue
+		}
+
+		methods := maps.Values(contract.CompiledContract().Abi.Methods)
+		if len(methods) == 0 {
+			exclusions = append(exclusions, fmt.Sprintf("%s: contract has no methods", location))
+			continue
+		}
+		slices.SortFunc(methods, func(a, b abi.Method) int {
+			return strings.Compare(a.Sig, b.Sig)
+		})
+		for _, method := range methods {
+			if fw.isFuzzableMethod(address, method) {
+				continue
+			}
+			exclusions = append(exclusions, fmt.Sprintf("%s.%s: %s", location, method.Sig, fw.fuzzer.methodExclusionReason(contract, method)))
+		}
+	}
+	return exclusions
+}
+
+// isFuzzableMethod indicates whether the provided method of the contract deployed at the provided address is called
+// by the worker.
+func (fw *FuzzerWorker) isFuzzableMethod(address common.Address, method abi.Method) bool {
+	isMethod := func(m fuzzerTypes.DeployedContractMethod) bool {
+		return m.Address == address && m.Method.Sig == method.Sig
+	}
+	return slices.ContainsFunc(fw.stateChangingMethods, isMethod) || slices.ContainsFunc(fw.pureMethods, isMethod)
+}
+
+// methodExclusionReason describes why the provided method of the provided contract definition, which is deployed but
+// not excluded, is not called directly by workers.
+func (f *Fuzzer) methodExclusionReason(contract *fuzzerTypes.Contract, method abi.Method) string {
+	testingConfig := f.config.Fuzzing.Testing
+	canonicalSig := contract.Name() + "." + method.Sig
+	switch {
+	case fuzzingutils.IsPropertyTest(method, testingConfig.PropertyTesting.TestPrefixes):
+		return "property test, which is checked rather than called in call sequences"
+	case fuzzingutils.IsOptimizationTest(method, testingConfig.OptimizationTesting.TestPrefixes):
+		return "optimization test, which is checked rather than called in call sequences"
+	case method.IsConstant() && !testingConfig.TestViewMethods:
+		return "view or pure method, and testViewMethods is disabled"
+	case len(testingConfig.TargetFunctionSignatures) > 0 && !slices.Contains(testingConfig.TargetFunctionSignatures, canonicalSig):
+		return "not listed in targetFunctionSignatures"
+	case slices.Contains(testingConfig.ExcludeFunctionSignatures, canonicalSig):
+		return "excluded by excludeFunctionSignatures"
+	case contract.HasMethodDirective(method, fuzzerTypes.MethodDirectiveIgnore):
+		return "ignored by its @custom:medusa ignore directive"
+	case f.isSpecContract(contract.Name()) && !slices.ContainsFunc(testingConfig.AssertionTesting.SpecTestPrefixes, func(prefix string) bool { return strings.HasPrefix(method.Name, prefix) }):
+		return "spec contract method which is not prefixed with one of specTestPrefixes"
+	}
+	for _, input := range method.Inputs {
+		if !valuegeneration.IsSupportedAbiType(&input.Type) {
+			return fmt.Sprintf("argument type '%s' is unsupported", input.Type.String())
+		}
+	}
+	if !slices.ContainsFunc(contract.AssertionTestMethods, func(m abi.Method) bool { return m.Sig == method.Sig }) {
+		return "not targeted by a @custom:medusa target directive, while other methods are"
+	}
+	if f.isSpecContract(contract.Name()) && !testingConfig.AssertionTesting.Enabled {
+		return "spec contract, whose methods are only called when assertion testing is enabled"
+	}
+	return "dynamically deployed contract, beyond dynamicDeploymentTargetLimit or while testAllContracts is disabled"
+}
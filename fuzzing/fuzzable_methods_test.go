@@ -0,0 +1,83 @@
+package fuzzing
+
+import (
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// runNoFuzzableMethodsTest runs the fuzzer against our pre-compiled Hardhat project, with every method of its target
+// contracts excluded from fuzzing, and provides the result of starting the fuzzer to the provided method.
+func runNoFuzzableMethodsTest(t *testing.T, configUpdates func(projectConfig *config.ProjectConfig), method func(err error, f *fuzzerTestContext)) {
+	runHardhatProjectTest(t, func(projectConfig *config.ProjectConfig) {
+		// Create our project configuration, excluding one contract entirely and the only method of the other.
+		projectConfig.Fuzzing.Testing.ExcludeContracts = []string{"FirstContract"}
+		projectConfig.Fuzzing.Testing.ExcludeFunctionSignatures = []string{"SecondContract.value()"}
+		projectConfig.Fuzzing.Testing.StopOnNoTests = false
+		configUpdates(projectConfig)
+
+		executeFuzzerTestMethodInternal(t, projectConfig, func(f *fuzzerTestContext) {
+			err := f.fuzzer.Start()
+			method(err, f)
+		})
+	})
+}
+
+// TestNoFuzzableMethods tests that the fuzzer stops with an error describing why each method was excluded, if every
+// method of its target contracts is excluded from fuzzing.
+func TestNoFuzzableMethods(t *testing.T) {
+	runNoFuzzableMethodsTest(t, func(projectConfig *config.ProjectConfig) {}, func(err error, f *fuzzerTestContext) {
+		assert.Error(t, err)
+		assert.ErrorContains(t, err, "no methods could be selected for fuzzing")
+		assert.ErrorContains(t, err, "FirstContract")
+		assert.ErrorContains(t, err, "contract excluded by excludeContracts")
+		assert.ErrorContains(t, err, "SecondContract")
+		assert.ErrorContains(t, err, "value(): excluded by excludeFunctionSignatures")
+		assert.ErrorContains(t, err, "waitForDynamicTargets")
+	})
+}
+
+// TestNoFuzzableMethodsWaitForDynamicTargetsWithoutCorpus tests that the fuzzer stops with an error if workers
+// without any methods to call are configured to wait for dynamically deployed targets, but the corpus has no call
+// sequences which could deploy them.
+func TestNoFuzzableMethodsWaitForDynamicTargetsWithoutCorpus(t *testing.T) {
+	runNoFuzzableMethodsTest(t, func(projectConfig *config.ProjectConfig) {
+		projectConfig.Fuzzing.Testing.WaitForDynamicTargets = true
+	}, func(err error, f *fuzzerTestContext) {
+		assert.Error(t, err)
+		assert.ErrorContains(t, err, "no methods could be selected for fuzzing")
+		assert.ErrorContains(t, err, "the corpus has no call sequences which could deploy targets")
+		assert.EqualValues(t, 0, f.fuzzer.metrics.SequencesTested().Uint64())
+	})
+}
+
+// TestNoFuzzableMethodsWaitForDynamicTargets tests that workers without any methods to call test corpus call
+// sequences when configured to wait for dynamically deployed targets, rather than idling or stopping the fuzzer with
+// an error.
+func TestNoFuzzableMethodsWaitForDynamicTargets(t *testing.T) {
+	// Build a corpus with every method available to be called, then test it with every method excluded.
+	runNoFuzzableMethodsTest(t, func(projectConfig *config.ProjectConfig) {
+		projectConfig.Fuzzing.TestLimit = 1_000
+		projectConfig.Fuzzing.CorpusDirectory = "corpus"
+		projectConfig.Fuzzing.Testing.ExcludeContracts = []string{}
+		projectConfig.Fuzzing.Testing.ExcludeFunctionSignatures = []string{}
+	}, func(err error, f *fuzzerTestContext) {
+		assert.NoError(t, err)
+		assertCorpusCallSequencesCollected(f, true)
+
+		// Run the fuzzer again from our corpus, with every method excluded.
+		projectConfig := f.fuzzer.config
+		projectConfig.Fuzzing.TestLimit = 0
+		projectConfig.Fuzzing.Timeout = 2
+		projectConfig.Fuzzing.Testing.ExcludeContracts = []string{"FirstContract"}
+		projectConfig.Fuzzing.Testing.ExcludeFunctionSignatures = []string{"SecondContract.value()"}
+		projectConfig.Fuzzing.Testing.WaitForDynamicTargets = true
+		executeFuzzerTestMethodInternal(t, &projectConfig, func(f *fuzzerTestContext) {
+			// Our workers should have tested call sequences from our corpus, rather than idling.
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+			assert.Greater(t, f.fuzzer.metrics.SequencesTested().Uint64(), uint64(0))
+		})
+	})
+}
@@ -13,8 +13,10 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/crytic/medusa/fuzzing/executiontracer"
@@ -36,8 +38,9 @@ import (
 	fuzzingutils "github.com/crytic/medusa/fuzzing/utils"
 	"github.com/crytic/medusa/fuzzing/valuegeneration"
 	"github.com/crytic/medusa/utils"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
 
@@ -61,6 +64,12 @@ type Fuzzer struct {
 	senders []common.Address
 	// deployer describes an account address used to deploy contracts in fuzzing campaigns.
 	deployer common.Address
+	// contractDeployers describes a mapping of contract names to the account addresses used to deploy them, for
+	// contracts which should not be deployed by the deployer.
+	contractDeployers map[string]common.Address
+	// addressLabels describes the registry of labels displayed alongside addresses in execution traces, call
+	// sequences, and test results.
+	addressLabels *AddressLabels
 
 	// compilations describes all compilations added as targets.
 	compilations []compilationTypes.Compilation
@@ -81,6 +90,10 @@ type Fuzzer struct {
 	// corpus stores a list of transaction sequences that can be used for coverage-guided fuzzing
 	corpus *corpus.Corpus
 
+	// seed describes the seed used to initialize the randomProvider, and from which the random stream of every call
+	// sequence tested is derived.
+	seed int64
+
 	// randomProvider describes the provider used to generate random values in the Fuzzer. All other random providers
 	// used by the Fuzzer's subcomponents are derived from this one.
 	randomProvider *rand.Rand
@@ -91,6 +104,33 @@ type Fuzzer struct {
 	testCasesLock sync.Mutex
 	// testCasesFinished describes test cases already reported as having been finalized.
 	testCasesFinished map[string]TestCase
+	// testCasesFinishedTimes describes the time each test case was reported as having been finalized.
+	testCasesFinishedTimes map[string]time.Time
+	// testCasesDiscoveredTimes describes the time a call sequence was first reported to violate each test case, keyed
+	// by the name of the test case, before the call sequence was shrunk.
+	testCasesDiscoveredTimes map[string]time.Time
+	// failures describes the registry of failures discovered by workers, used to avoid shrinking the same failure
+	// more than once.
+	failures *failureRegistry
+	// shrinkScheduler limits the number of workers shrinking call sequences concurrently, queueing the shrink
+	// requests beyond that limit. It is nil if fuzzing deterministically, in which case workers shrink the call
+	// sequences they discover themselves.
+	shrinkScheduler *shrinkScheduler
+	// unmatchedDeployments tracks the contract deployments which workers failed to match to a contract definition.
+	unmatchedDeployments *unmatchedDeploymentTracker
+
+	// subscriberErrors tracks the errors returned by non-critical event subscribers which were suppressed, as the
+	// subscriber error policy is to log and continue.
+	subscriberErrors *subscriberErrorTracker
+	// sequenceLengths tracks the call sequence lengths at which workers discovered new coverage and failures.
+	sequenceLengths *sequenceLengthTracker
+	// testProviders describes the registry of test providers attached to the fuzzer, which can be muted while fuzzing.
+	testProviders *testProviderRegistry
+	// parameterHints biases generated method arguments by the names of their parameters, or is nil if disabled.
+	parameterHints *parameterHints
+	// senderAccess tracks the outcomes of the calls each sender made to each method, so senders rejected by a method's
+	// access control are selected less often to call it, or is nil if disabled.
+	senderAccess *senderAccessTracker
 
 	// Events describes the event system for the Fuzzer.
 	Events FuzzerEvents
@@ -103,11 +143,64 @@ type Fuzzer struct {
 
 	// liveReportCancel is used to stop the live report generation goroutine
 	liveReportCancel chan struct{}
+	// liveReportCancelOnce ensures liveReportCancel is only closed once, as Stop may be called from multiple goroutines
+	// (e.g. when both the test limit and coverage plateau are reached).
+	liveReportCancelOnce sync.Once
+
+	// noFuzzableMethodsWarning ensures workers which are waiting for dynamically deployed targets, as they have no
+	// methods to call, only log why every candidate method was excluded once.
+	noFuzzableMethodsWarning sync.Once
+
+	// lastNewCoverageTime describes the time, in Unix nanoseconds, at which any worker last achieved new coverage, or
+	// the fuzzing campaign started if none has been achieved since. It is used to stop on a coverage plateau.
+	lastNewCoverageTime atomic.Int64
+
+	// sequenceLength describes the length of the call sequences generated by workers if an adaptive sequence length is
+	// enabled. It is initialized to the configured call sequence length, and adjusted while fuzzing.
+	sequenceLength atomic.Int64
+
+	// successfulCorpusEntries describes the amount of call sequences which achieved new coverage and whose last call
+	// did not revert. It is used to detect stuck campaigns.
+	successfulCorpusEntries atomic.Uint64
+
+	// stuckCampaignAborted indicates whether the fuzzing campaign was stopped because it was considered stuck.
+	stuckCampaignAborted atomic.Bool
+
+	// workerWatchdog monitors workers for stalls while fuzzing, or is nil if the watchdog is disabled.
+	workerWatchdog *workerWatchdog
+
+	// deterministicWorkerStates describes the state of each worker slot when fuzzing deterministically, or is nil if
+	// fuzzing is not deterministic.
+	deterministicWorkerStates []*deterministicWorkerState
+
+	// workerProcess reports the discoveries of this Fuzzer to the Fuzzer which spawned it, if it runs in a worker
+	// process. This is nil otherwise.
+	workerProcess *workerProcessClient
+
+	// workerProcessSlots describes the worker slots which worker processes are spawned for, if worker processes are
+	// enabled.
+	workerProcessSlots []*workerProcessSlot
+
+	// workerProcessDeployments maps the address of each contract deployed while setting up the test chain to its
+	// contract definition, so call sequences exchanged with worker processes can be resolved. This is nil unless worker
+	// processes are enabled, or this Fuzzer runs in one.
+	workerProcessDeployments map[common.Address]*fuzzerTypes.Contract
+
+	// deterministicWorkersFinished describes the amount of worker slots which finished testing when fuzzing
+	// deterministically.
+	deterministicWorkersFinished atomic.Int64
 }
 
 // NewFuzzer returns an instance of a new Fuzzer provided a project configuration, or an error if one is encountered
 // while initializing the code.
 func NewFuzzer(config config.ProjectConfig) (*Fuzzer, error) {
+	return newFuzzer(config, nil)
+}
+
+// newFuzzer returns an instance of a new Fuzzer provided a project configuration, or an error if one is encountered
+// while initializing the code. If compilations are provided, they are used as the compilation targets rather than
+// compiling the targets specified in the compilation config.
+func newFuzzer(config config.ProjectConfig, compilations []compilationTypes.Compilation) (*Fuzzer, error) {
 	// Disable colors if requested
 	if config.Logging.NoColor {
 		colors.DisableColor()
@@ -144,6 +237,15 @@ func NewFuzzer(config config.ProjectConfig) (*Fuzzer, error) {
 	// Get the fuzzer's custom sub-logger
 	logger := logging.GlobalLogger.NewSubLogger("module", "fuzzer")
 
+	// Parse our custom HTML coverage report template, if we have one, so template errors are reported before fuzzing.
+	if config.Fuzzing.CoverageHTMLTemplate != "" && slices.Contains(config.Fuzzing.CoverageFormats, "html") {
+		_, err = coverage.ParseHTMLReportTemplate(config.Fuzzing.CoverageHTMLTemplate)
+		if err != nil {
+			logger.Error("Invalid coverage HTML template", err)
+			return nil, err
+		}
+	}
+
 	// Parse the senders addresses from our account config.
 	senders, err := utils.HexStringsToAddresses(config.Fuzzing.SenderAddresses)
 	if err != nil {
@@ -158,20 +260,69 @@ func NewFuzzer(config config.ProjectConfig) (*Fuzzer, error) {
 		return nil, err
 	}
 
+	// Parse the per-contract deployer addresses from our account config
+	contractDeployers := make(map[string]common.Address, len(config.Fuzzing.ContractDeployers))
+	for contractName, addrStr := range config.Fuzzing.ContractDeployers {
+		contractDeployers[contractName], err = utils.HexStringToAddress(addrStr)
+		if err != nil {
+			logger.Error("Invalid deployer address for ", contractName, err)
+			return nil, err
+		}
+	}
+
+	// Parse the address aliases from our config
+	addressAliases := make(map[common.Address]string, len(config.Fuzzing.AddressLabels))
+	for addrStr, alias := range config.Fuzzing.AddressLabels {
+		addr, err := utils.HexStringToAddress(addrStr)
+		if err != nil {
+			logger.Error("Invalid address for label ", alias, err)
+			return nil, err
+		}
+		addressAliases[addr] = alias
+	}
+
+	// Create our parameter name hints, if they are enabled
+	var hints *parameterHints
+	if config.Fuzzing.ParameterNameHints.Enabled {
+		hints, err = newParameterHints(config.Fuzzing.ParameterNameHints)
+		if err != nil {
+			logger.Error("Invalid parameter name hints", err)
+			return nil, err
+		}
+	}
+
+	// Track the access each sender has to each method, if enabled
+	var senderAccess *senderAccessTracker
+	if config.Fuzzing.AccessControlRevertThreshold > 0 {
+		senderAccess = newSenderAccessTracker(uint64(config.Fuzzing.AccessControlRevertThreshold))
+	}
+
 	// Create and return our fuzzing instance.
 	fuzzer := &Fuzzer{
-		config:              config,
-		senders:             senders,
-		deployer:            deployer,
-		baseValueSet:        valuegeneration.NewValueSet(),
-		contractDefinitions: make(fuzzerTypes.Contracts, 0),
-		testCases:           make([]TestCase, 0),
-		testCasesFinished:   make(map[string]TestCase),
+		config:                   config,
+		senders:                  senders,
+		deployer:                 deployer,
+		contractDeployers:        contractDeployers,
+		addressLabels:            newAddressLabels(deployer, senders, addressAliases),
+		baseValueSet:             valuegeneration.NewValueSet(),
+		contractDefinitions:      make(fuzzerTypes.Contracts, 0),
+		testCases:                make([]TestCase, 0),
+		testCasesFinished:        make(map[string]TestCase),
+		testCasesFinishedTimes:   make(map[string]time.Time),
+		testCasesDiscoveredTimes: make(map[string]time.Time),
+		failures:                 newFailureRegistry(),
+		unmatchedDeployments:     newUnmatchedDeploymentTracker(),
+		subscriberErrors:         newSubscriberErrorTracker(),
+		sequenceLengths:          newSequenceLengthTracker(),
+		testProviders:            newTestProviderRegistry(),
+		parameterHints:           hints,
+		senderAccess:             senderAccess,
 		Hooks: FuzzerHooks{
 			NewCallSequenceGeneratorConfigFunc: defaultCallSequenceGeneratorConfigFunc,
 			NewShrinkingValueMutatorFunc:       defaultShrinkingValueMutatorFunc,
 			ChainSetupFunc:                     chainSetupFromCompilations,
 			CallSequenceTestFuncs:              make([]CallSequenceTestFunc, 0),
+			SequenceCompletedTestFuncs:         make([]SequenceCompletedTestFunc, 0),
 		},
 		logger: logger,
 	}
@@ -179,40 +330,69 @@ func NewFuzzer(config config.ProjectConfig) (*Fuzzer, error) {
 	// Add our sender and deployer addresses to the base value set for the value generator, so they will be used as
 	// address arguments in fuzzing campaigns.
 	fuzzer.baseValueSet.AddAddress(fuzzer.deployer)
+	for _, contractDeployer := range fuzzer.contractDeployers {
+		fuzzer.baseValueSet.AddAddress(contractDeployer)
+	}
 	for _, sender := range fuzzer.senders {
 		fuzzer.baseValueSet.AddAddress(sender)
 	}
 
+	// Label the contracts deployed by every worker with the name of their contract definition.
+	fuzzer.Events.WorkerCreated.SubscribeNamed("fuzzer", fuzzer.onWorkerCreated)
+
+	// Track the last time any worker achieved new coverage, so we can stop on a coverage plateau.
+	fuzzer.Events.WorkerNewCoverage.SubscribeNamed("fuzzer", fuzzer.onWorkerNewCoverage)
+
+	// Generate call sequences of the configured length until it is adjusted.
+	fuzzer.sequenceLength.Store(int64(fuzzer.config.Fuzzing.CallSequenceLength))
+
 	// If we have a compilation config
 	if fuzzer.config.Compilation != nil {
-		// Compile the targets specified in the compilation config
-		fuzzer.logger.Info("Compiling targets with ", colors.Bold, fuzzer.config.Compilation.Platform, colors.Reset)
-		start := time.Now()
-		compilations, _, err := (*fuzzer.config.Compilation).Compile()
-		if err != nil {
-			fuzzer.logger.Error("Failed to compile target", err)
-			return nil, err
+		// Compile the targets specified in the compilation config, unless we were provided them already compiled
+		if compilations == nil {
+			fuzzer.logger.Info("Compiling targets with ", colors.Bold, fuzzer.config.Compilation.Platform, colors.Reset)
+			start := time.Now()
+			compilations, _, err = (*fuzzer.config.Compilation).Compile()
+			if err != nil {
+				fuzzer.logger.Error("Failed to compile target", err)
+				return nil, err
+			}
+			fuzzer.logger.Info("Finished compiling targets in ", time.Since(start).Round(time.Second))
 		}
-		fuzzer.logger.Info("Finished compiling targets in ", time.Since(start).Round(time.Second))
 
 		// Add our compilation targets
 		fuzzer.AddCompilationTargets(compilations)
 	}
 
-	// Register any default providers if specified.
-	if fuzzer.config.Fuzzing.Testing.PropertyTesting.Enabled {
-		attachPropertyTestCaseProvider(fuzzer)
-	}
-	if fuzzer.config.Fuzzing.Testing.AssertionTesting.Enabled {
-		attachAssertionTestCaseProvider(fuzzer)
-	}
-	if fuzzer.config.Fuzzing.Testing.OptimizationTesting.Enabled {
-		attachOptimizationTestCaseProvider(fuzzer)
+	// Register any default providers if specified. If testing is disabled (exploration mode), no providers are used.
+	if fuzzer.config.Fuzzing.Testing.Enabled {
+		if fuzzer.config.Fuzzing.Testing.PropertyTesting.Enabled {
+			attachPropertyTestCaseProvider(fuzzer)
+		}
+		if fuzzer.config.Fuzzing.Testing.AssertionTesting.Enabled {
+			attachAssertionTestCaseProvider(fuzzer)
+		}
+		if fuzzer.config.Fuzzing.Testing.OptimizationTesting.Enabled {
+			attachOptimizationTestCaseProvider(fuzzer)
+		}
+		if fuzzer.config.Fuzzing.Testing.ReentrancyTesting.Enabled {
+			attachReentrancyTestCaseProvider(fuzzer)
+		}
+		if fuzzer.config.Fuzzing.Testing.UntrustedDelegateCallTesting.Enabled {
+			attachUntrustedDelegateCallTestCaseProvider(fuzzer)
+		}
+		if fuzzer.config.Fuzzing.Testing.TokenTesting.Enabled {
+			attachTokenTestCaseProvider(fuzzer)
+		}
+		if fuzzer.config.Fuzzing.Testing.ScriptedOracleTesting.Enabled {
+			attachScriptedOracleTestCaseProvider(fuzzer)
+		}
 	}
 	return fuzzer, nil
 }
 
-// ContractDefinitions exposes the contract definitions registered with the Fuzzer.
+// ContractDefinitions exposes the contract definitions registered with the Fuzzer. The returned list is a copy, so it
+// can be queried (e.g. with Contracts.ByName or Contracts.MethodsBySelector) from any goroutine.
 func (f *Fuzzer) ContractDefinitions() fuzzerTypes.Contracts {
 	return slices.Clone(f.contractDefinitions)
 }
@@ -239,11 +419,155 @@ func (f *Fuzzer) DeployerAddress() common.Address {
 	return f.deployer
 }
 
+// AddressLabels exposes the registry of labels displayed alongside addresses in execution traces, call sequences,
+// and test results.
+func (f *Fuzzer) AddressLabels() *AddressLabels {
+	return f.addressLabels
+}
+
+// onWorkerCreated is the event handler triggered when a FuzzerWorker is created by the Fuzzer. It subscribes to the
+// worker's contract added events, so that the contracts it deploys are labeled.
+func (f *Fuzzer) onWorkerCreated(event FuzzerWorkerCreatedEvent) error {
+	event.Worker.Events.ContractAdded.SubscribeNamed("fuzzer", f.onWorkerContractAdded)
+	return nil
+}
+
+// onWorkerContractAdded is the event handler triggered when a FuzzerWorker detects a new contract deployment on its
+// chain, including dynamic deployments. It labels the contract address with the name of its contract definition.
+func (f *Fuzzer) onWorkerContractAdded(event FuzzerWorkerContractAddedEvent) error {
+	if event.ContractDefinition != nil {
+		f.addressLabels.addContract(event.ContractAddress, event.ContractDefinition.Name())
+	}
+	return nil
+}
+
+// onWorkerNewCoverage is the event handler triggered when a FuzzerWorker achieves new coverage. It records the time at
+// which new coverage was last achieved.
+func (f *Fuzzer) onWorkerNewCoverage(event FuzzerWorkerNewCoverageEvent) error {
+	f.lastNewCoverageTime.Store(time.Now().UnixNano())
+
+	// Track whether the call which achieved new coverage did not revert, so we can detect stuck campaigns.
+	lastCall := event.CallSequence[len(event.CallSequence)-1]
+	if lastCall.ChainReference != nil && lastCall.ChainReference.MessageResults().ExecutionResult.Err == nil {
+		f.successfulCorpusEntries.Add(1)
+	}
+	return nil
+}
+
+// ContractDeployerAddress exposes the account address from which the contract with the provided name will be
+// deployed. This is the deployer address unless a different one was configured for the contract.
+func (f *Fuzzer) ContractDeployerAddress(contractName string) common.Address {
+	if contractDeployer, ok := f.contractDeployers[contractName]; ok {
+		return contractDeployer
+	}
+	return f.deployer
+}
+
+// isSpecContract indicates whether the contract with the provided name is a spec contract, which is deployed to
+// define tests over the target contracts, rather than to be fuzzed itself.
+func (f *Fuzzer) isSpecContract(contractName string) bool {
+	return slices.Contains(f.config.Fuzzing.Testing.SpecContracts, contractName)
+}
+
+// withoutUnsupportedMethods filters the provided methods of a contract to those whose arguments the fuzzer can
+// generate values for. A warning is logged for each excluded method, as it will never be called by the fuzzer.
+func (f *Fuzzer) withoutUnsupportedMethods(contractName string, methods []abi.Method) []abi.Method {
+	var supportedMethods []abi.Method
+	for _, method := range methods {
+		supported := true
+		for _, input := range method.Inputs {
+			if !valuegeneration.IsSupportedAbiType(&input.Type) {
+				f.logger.Warn(fmt.Sprintf("Excluding method %s.%s from fuzzing, as its argument type '%s' is unsupported", contractName, method.Sig, input.Type.String()))
+				supported = false
+				break
+			}
+		}
+		if supported {
+			supportedMethods = append(supportedMethods, method)
+		}
+	}
+	return supportedMethods
+}
+
+// validateTestMethods checks that every method of the contracts under test which is named like a property or
+// optimization test has the shape of one, for each of those testing modes which is enabled. Malformed methods are
+// not run as tests, which is easily mistaken for a passing test, so each of them is reported by a warning, or an
+// error if the config specifies to fail on malformed test methods.
+// Returns an error describing every malformed method, if configured to fail on them.
+func (f *Fuzzer) validateTestMethods() error {
+	violations := f.malformedTestMethods()
+	if len(violations) == 0 {
+		return nil
+	}
+
+	// Report the violations, failing if configured to.
+	if f.config.Fuzzing.Testing.FailOnMalformedTestMethods {
+		return fmt.Errorf("methods named like tests do not have the shape of a test, and would never be run as one:\n%s", strings.Join(violations, "\n"))
+	}
+	for _, violation := range violations {
+		f.logger.Warn("Method named like a test will not be run as one: ", violation)
+	}
+	return nil
+}
+
+// malformedTestMethods checks every method of the contracts under test against the shape of a property or
+// optimization test, for each of those testing modes which is enabled.
+// Returns a sorted list describing each method which is named like a test but does not have the shape of one.
+func (f *Fuzzer) malformedTestMethods() []string {
+	testingConfig := f.config.Fuzzing.Testing
+	if !testingConfig.Enabled {
+		return nil
+	}
+
+	// Check each method of every contract under test, collecting the requirements they violate.
+	var violations []string
+	for _, contract := range f.contractDefinitions {
+		if !f.isContractUnderTest(contract.Name()) {
+			continue
+		}
+		for _, method := range contract.CompiledContract().Abi.Methods {
+			var err error
+			if testingConfig.PropertyTesting.Enabled {
+				err = fuzzingutils.ValidatePropertyTestMethod(method, testingConfig.PropertyTesting.TestPrefixes)
+			}
+			if err == nil && testingConfig.OptimizationTesting.Enabled {
+				err = fuzzingutils.ValidateOptimizationTestMethod(method, testingConfig.OptimizationTesting.TestPrefixes)
+			}
+			if err != nil {
+				violations = append(violations, fmt.Sprintf("%s.%s: %v", contract.Name(), method.Sig, err))
+			}
+		}
+	}
+	slices.Sort(violations)
+	return violations
+}
+
 // TestCases exposes the underlying tests run during the fuzzing campaign.
 func (f *Fuzzer) TestCases() []TestCase {
 	return f.testCases
 }
 
+// traceLimits returns the limits on the size of execution traces attached to call sequences, as defined by the
+// project configuration.
+func (f *Fuzzer) traceLimits() executiontracer.TraceLimits {
+	return executiontracer.TraceLimits{
+		MaxDepth:      f.config.Fuzzing.Testing.TraceDepthLimit,
+		MaxOperations: f.config.Fuzzing.Testing.TraceOperationLimit,
+	}
+}
+
+// newTraceArtifact creates a trace artifact which the rendered execution traces of a call sequence are written to as
+// they are produced. It is created in the traces directory of the corpus, or of the system's temporary directory if no
+// corpus directory is set.
+// Returns the trace artifact, or an error if one occurs.
+func (f *Fuzzer) newTraceArtifact() (*executiontracer.TraceArtifact, error) {
+	tracesDirectory := filepath.Join(os.TempDir(), "medusa", "traces")
+	if f.config.Fuzzing.CorpusDirectory != "" {
+		tracesDirectory = filepath.Join(f.config.Fuzzing.CorpusDirectory, "traces")
+	}
+	return executiontracer.NewTraceArtifact(filepath.Join(tracesDirectory, fmt.Sprintf("%v-%v.log", time.Now().UnixNano(), uuid.New().String())))
+}
+
 // TestCasesWithStatus exposes the underlying tests with the provided status.
 func (f *Fuzzer) TestCasesWithStatus(status TestCaseStatus) []TestCase {
 	// Acquire a thread lock to avoid race conditions
@@ -256,6 +580,12 @@ func (f *Fuzzer) TestCasesWithStatus(status TestCaseStatus) []TestCase {
 	})
 }
 
+// SequenceLengthHistogram returns a histogram of the call sequence lengths at which new coverage and failures were
+// discovered by the Fuzzer's workers.
+func (f *Fuzzer) SequenceLengthHistogram() SequenceLengthHistogram {
+	return f.sequenceLengths.snapshot()
+}
+
 // RegisterTestCase registers a new TestCase with the Fuzzer.
 func (f *Fuzzer) RegisterTestCase(testCase TestCase) {
 	// Acquire a thread lock to avoid race conditions
@@ -269,6 +599,18 @@ func (f *Fuzzer) RegisterTestCase(testCase TestCase) {
 	f.testCases = append(f.testCases, testCase)
 }
 
+// reportTestCaseDiscovered records the time a call sequence was first found to violate the test case with the
+// provided name, before it is shrunk. Later reports for the same test case are ignored.
+func (f *Fuzzer) reportTestCaseDiscovered(testName string) {
+	// Acquire a thread lock to avoid race conditions
+	f.testCasesLock.Lock()
+	defer f.testCasesLock.Unlock()
+
+	if _, alreadyExists := f.testCasesDiscoveredTimes[testName]; !alreadyExists {
+		f.testCasesDiscoveredTimes[testName] = time.Now()
+	}
+}
+
 // ReportTestCaseFinished is used to report a TestCase status as finalized to the Fuzzer.
 func (f *Fuzzer) ReportTestCaseFinished(testCase TestCase) {
 	// Acquire a thread lock to avoid race conditions
@@ -282,6 +624,14 @@ func (f *Fuzzer) ReportTestCaseFinished(testCase TestCase) {
 
 	// Otherwise now mark the test case as finished.
 	f.testCasesFinished[testCase.ID()] = testCase
+	f.testCasesFinishedTimes[testCase.ID()] = time.Now()
+
+	// If we run in a worker process, report the result to the Fuzzer which spawned us.
+	if f.workerProcess != nil {
+		if err := f.workerProcess.reportTestCase(testCase); err != nil {
+			f.logger.Error("Failed to report a test case result from the worker process", err)
+		}
+	}
 
 	// We only log here if we're not configured to stop on the first test failure. This is because the fuzzer prints
 	// results on exit, so we avoid duplicate messages.
@@ -289,8 +639,9 @@ func (f *Fuzzer) ReportTestCaseFinished(testCase TestCase) {
 		f.logger.Info(testCase.LogMessage().Elements()...)
 	}
 
-	// If the config specifies, we stop after the first failed test reported.
-	if testCase.Status() == TestCaseStatusFailed && f.config.Fuzzing.Testing.StopOnFailedTest {
+	// If the config specifies, we stop after the first failed test reported. When fuzzing deterministically, each
+	// worker instead stops after the first failed test it reported.
+	if testCase.Status() == TestCaseStatusFailed && f.config.Fuzzing.Testing.StopOnFailedTest && !f.config.Fuzzing.Deterministic {
 		f.Stop()
 	}
 }
@@ -329,6 +680,16 @@ func (f *Fuzzer) AddCompilationTargets(compilations []compilationTypes.Compilati
 		f.compilations = append(f.compilations, compilations[i])
 		compilation := &f.compilations[len(f.compilations)-1]
 
+		// Obtain the NatSpec descriptions of each contract's methods, so they can be used to describe test results.
+		methodDescriptions, err := compilation.MethodDescriptions()
+		if err != nil {
+			f.logger.Warn("Failed to obtain method descriptions from the AST", err)
+		}
+		methodDirectives, err := compilation.MethodDirectives()
+		if err != nil {
+			f.logger.Warn("Failed to obtain method directives from the AST", err)
+		}
+
 		// Loop for each source
 		for sourcePath, source := range compilation.SourcePathToArtifact {
 			// Seed from the contract's AST if we did not use slither or failed to do so
@@ -347,13 +708,15 @@ func (f *Fuzzer) AddCompilationTargets(compilations []compilationTypes.Compilati
 				}
 
 				contractDefinition := fuzzerTypes.NewContract(contractName, sourcePath, &contract, compilation)
+				contractDefinition.MethodDescriptions = methodDescriptions[sourcePath][contractName]
+				contractDefinition.MethodDirectives = methodDirectives[sourcePath][contractName]
 
 				// Sort available methods by type
 				assertionTestMethods, propertyTestMethods, optimizationTestMethods := fuzzingutils.BinTestByType(&contract,
 					f.config.Fuzzing.Testing.PropertyTesting.TestPrefixes,
 					f.config.Fuzzing.Testing.OptimizationTesting.TestPrefixes,
 					f.config.Fuzzing.Testing.TestViewMethods)
-				contractDefinition.AssertionTestMethods = assertionTestMethods
+				contractDefinition.AssertionTestMethods = f.withoutUnsupportedMethods(contractName, assertionTestMethods)
 				contractDefinition.PropertyTestMethods = propertyTestMethods
 				contractDefinition.OptimizationTestMethods = optimizationTestMethods
 
@@ -366,17 +729,29 @@ func (f *Fuzzer) AddCompilationTargets(compilations []compilationTypes.Compilati
 					// Consider all methods except those in the exclude methods list
 					contractDefinition = contractDefinition.WithExcludedAssertionMethods(f.config.Fuzzing.Testing.ExcludeFunctionSignatures)
 				}
+				if f.isSpecContract(contractName) {
+					// Only consider methods of spec contracts which are named as assertion tests
+					contractDefinition = contractDefinition.WithPrefixedAssertionMethods(f.config.Fuzzing.Testing.AssertionTesting.SpecTestPrefixes)
+				}
 
 				f.contractDefinitions = append(f.contractDefinitions, contractDefinition)
 			}
 		}
 
-		// Cache all of our source code if it hasn't been already.
-		err := compilation.CacheSourceCode()
+		// Cache all of our source code if it hasn't been already, reading it from remapped source paths if configured.
+		compilation.SourcePathRemappings = f.config.Fuzzing.CoverageSourceRemappings
+		err = compilation.CacheSourceCode()
 		if err != nil {
 			f.logger.Warn("Failed to cache compilation source file data", err)
 		}
 	}
+
+	// Filter methods available for assertion testing by their NatSpec directives. This is done once every contract
+	// definition is known, as targeting methods through directives affects the methods of every contract.
+	f.applyMethodDirectives()
+
+	// Derive hashes of the strings and bytes we harvested, as they may have gained new constants from these targets.
+	f.baseValueSet.DerivePreimageHashes(f.config.Fuzzing.PreimageHashesMax)
 }
 
 // createTestChain creates a test chain with the account balance allocations specified by the config.
@@ -386,33 +761,44 @@ func (f *Fuzzer) createTestChain() (*chain.TestChain, error) {
 	genesisAlloc := make(types.GenesisAlloc)
 
 	// Fund all of our sender addresses in the genesis block
-	initBalance := new(big.Int).Div(abi.MaxInt256, big.NewInt(2)) // TODO: make this configurable
+	initBalance := config.InitialAccountBalance()
 	for _, sender := range f.senders {
 		genesisAlloc[sender] = types.Account{
 			Balance: initBalance,
 		}
 	}
 
-	// Fund our deployer address in the genesis block
+	// Fund our deployer addresses in the genesis block
 	genesisAlloc[f.deployer] = types.Account{
 		Balance: initBalance,
 	}
+	for _, contractDeployer := range f.contractDeployers {
+		genesisAlloc[contractDeployer] = types.Account{
+			Balance: initBalance,
+		}
+	}
+
+	// Resolve the addresses of our predeployed contracts up front, so that their constructor arguments may reference
+	// other predeployed contracts by name.
+	predeployedContractAddrs, err := f.predeployedContractAddresses()
+	if err != nil {
+		return nil, err
+	}
 
 	// Identify which contracts need to be predeployed to a deterministic address by iterating across the mapping
-	contractAddressOverrides := make(map[common.Hash]common.Address, len(f.config.Fuzzing.PredeployedContracts))
-	for contractName, addrStr := range f.config.Fuzzing.PredeployedContracts {
+	contractAddressOverrides := make(map[common.Hash]common.Address, len(predeployedContractAddrs))
+	for contractName, contractAddr := range predeployedContractAddrs {
 		found := false
 		// Try to find the associated compilation artifact
 		for _, contract := range f.contractDefinitions {
 			if contract.Name() == contractName {
-				// Hash the init bytecode (so that it can be easily identified in the EVM) and map it to the
-				// requested address
-				initBytecodeHash := crypto.Keccak256Hash(contract.CompiledContract().InitBytecode)
-				contractAddr, err := utils.HexStringToAddress(addrStr)
+				// Hash the deployment message data (init bytecode and constructor arguments) so that it can be easily
+				// identified in the EVM, and map it to the requested address
+				msgData, err := f.contractDeploymentData(contract, predeployedContractAddrs)
 				if err != nil {
-					return nil, fmt.Errorf("invalid address provided for a predeployed contract: %v", contract.Name())
+					return nil, err
 				}
-				contractAddressOverrides[initBytecodeHash] = contractAddr
+				contractAddressOverrides[crypto.Keccak256Hash(msgData)] = contractAddr
 				found = true
 				break
 			}
@@ -429,10 +815,16 @@ func (f *Fuzzer) createTestChain() (*chain.TestChain, error) {
 
 	// Create our test chain with our basic allocations and passed medusa's chain configuration
 	testChain, err := chain.NewTestChain(f.ctx, genesisAlloc, &f.config.Fuzzing.TestChainConfig)
+	if err != nil {
+		return nil, err
+	}
 
 	// Set our block gas limit
 	testChain.BlockGasLimit = f.config.Fuzzing.BlockGasLimit
-	return testChain, err
+
+	// Label our senders, deployer, and any other addresses labeled prior to deployment.
+	maps.Copy(testChain.Labels, f.addressLabels.Labels())
+	return testChain, nil
 }
 
 // chainSetupFromCompilations is a TestChainSetupFunc which sets up the base test chain state by deploying
@@ -446,7 +838,8 @@ func chainSetupFromCompilations(fuzzer *Fuzzer, testChain *chain.TestChain) (*ex
 		var found bool
 		for _, contract := range fuzzer.contractDefinitions {
 			// If only one contract is defined, we can infer the target contract by filtering interfaces/libraries.
-			if contract.CompiledContract().Kind == compilationTypes.ContractKindContract {
+			// Spec contracts are deployed separately, so they are never inferred as targets.
+			if contract.CompiledContract().Kind == compilationTypes.ContractKindContract && !fuzzer.isSpecContract(contract.Name()) {
 				if !found {
 					fuzzer.config.Fuzzing.TargetContracts = []string{contract.Name()}
 					found = true
@@ -464,7 +857,18 @@ func chainSetupFromCompilations(fuzzer *Fuzzer, testChain *chain.TestChain) (*ex
 	contractsToDeploy := make([]string, 0)
 	balances := make([]*config.ContractBalance, 0)
 
-	for contractName := range fuzzer.config.Fuzzing.PredeployedContracts {
+	// Resolve the addresses our predeployed contracts must be deployed at. They are deployed in a sorted order so the
+	// deployer's nonce sequence is deterministic.
+	predeployedContractAddrs, err := fuzzer.predeployedContractAddresses()
+	if err != nil {
+		return nil, err
+	}
+	predeployNames := make([]string, 0, len(predeployedContractAddrs))
+	for contractName := range predeployedContractAddrs {
+		predeployNames = append(predeployNames, contractName)
+	}
+	sort.Strings(predeployNames)
+	for _, contractName := range predeployNames {
 		contractsToDeploy = append(contractsToDeploy, contractName)
 		// Preserve index of target contract balances
 		balances = append(balances, &config.ContractBalance{Int: *big.NewInt(0)})
@@ -472,6 +876,14 @@ func chainSetupFromCompilations(fuzzer *Fuzzer, testChain *chain.TestChain) (*ex
 	contractsToDeploy = append(contractsToDeploy, fuzzer.config.Fuzzing.TargetContracts...)
 	balances = append(balances, fuzzer.config.Fuzzing.TargetContractsBalances...)
 
+	// Spec contracts are deployed last, so their constructor arguments can reference any deployed target contract.
+	// They receive no balance unless a deployment value is provided for them.
+	specContractsIndex := len(contractsToDeploy)
+	if len(balances) > specContractsIndex {
+		balances = balances[:specContractsIndex]
+	}
+	contractsToDeploy = append(contractsToDeploy, fuzzer.config.Fuzzing.Testing.SpecContracts...)
+
 	deployedContractAddr := make(map[string]common.Address)
 	// Loop for all contracts to deploy
 	for i, contractName := range contractsToDeploy {
@@ -480,30 +892,30 @@ func chainSetupFromCompilations(fuzzer *Fuzzer, testChain *chain.TestChain) (*ex
 		for _, contract := range fuzzer.contractDefinitions {
 			// If we found a contract definition that matches this definition by name, try to deploy it
 			if contract.Name() == contractName {
-				// Concatenate constructor arguments, if necessary
-				args := make([]any, 0)
-				if len(contract.CompiledContract().Abi.Constructor.Inputs) > 0 {
-					// If the contract is a predeployed contract, throw an error because they do not accept constructor
-					// args.
-					if _, ok := fuzzer.config.Fuzzing.PredeployedContracts[contractName]; ok {
-						return nil, fmt.Errorf("predeployed contracts cannot accept constructor arguments")
-					}
-					jsonArgs, ok := fuzzer.config.Fuzzing.ConstructorArgs[contractName]
-					if !ok {
-						return nil, fmt.Errorf("constructor arguments for contract %s not provided", contractName)
-					}
-					decoded, err := valuegeneration.DecodeJSONArgumentsFromMap(contract.CompiledContract().Abi.Constructor.Inputs,
-						jsonArgs, deployedContractAddr)
-					if err != nil {
-						return nil, err
-					}
-					args = decoded
+				// Construct our deployment message/tx data field, concatenating constructor arguments if necessary.
+				// Predeployed contracts resolve contract names in their constructor arguments against other
+				// predeployed contracts only, as their deployment data must be known when the test chain is created.
+				isPredeploy := i < len(predeployNames)
+				argContractAddrs := deployedContractAddr
+				if isPredeploy {
+					argContractAddrs = predeployedContractAddrs
 				}
-
-				// Construct our deployment message/tx data field
-				msgData, err := contract.CompiledContract().GetDeploymentMessageData(args)
+				msgData, err := fuzzer.contractDeploymentData(contract, argContractAddrs)
 				if err != nil {
-					return nil, fmt.Errorf("initial contract deployment failed for contract \"%v\", error: %v", contractName, err)
+					return nil, err
+				}
+
+				// Non-predeployed contracts are deployed at the address derived from the deployer's nonce. If this
+				// collides with an address reserved for a predeployed contract, the deployment would fail, so we
+				// report the conflict instead.
+				deployer := fuzzer.ContractDeployerAddress(contractName)
+				if !isPredeploy {
+					expectedAddr := crypto.CreateAddress(deployer, testChain.State().GetNonce(deployer))
+					for predeployName, predeployAddr := range predeployedContractAddrs {
+						if expectedAddr == predeployAddr {
+							return nil, fmt.Errorf("%v would be deployed at %v, which conflicts with the address of predeployed contract %v", contractName, expectedAddr.String(), predeployName)
+						}
+					}
 				}
 
 				// If our project config has a non-zero balance for this target contract, retrieve it
@@ -512,9 +924,14 @@ func chainSetupFromCompilations(fuzzer *Fuzzer, testChain *chain.TestChain) (*ex
 					contractBalance = new(big.Int).Set(&balances[i].Int)
 				}
 
+				// If our project config has a deployment value for this contract, it takes precedence
+				if deploymentValue, ok := fuzzer.config.Fuzzing.DeploymentValues[contractName]; ok && deploymentValue != nil {
+					contractBalance = new(big.Int).Set(&deploymentValue.Int)
+				}
+
 				// Create a message to represent our contract deployment (we let deployments consume the whole block
 				// gas limit rather than use tx gas limit)
-				msg := calls.NewCallMessage(fuzzer.deployer, nil, 0, contractBalance, fuzzer.config.Fuzzing.BlockGasLimit, nil, nil, nil, msgData)
+				msg := calls.NewCallMessage(deployer, nil, 0, contractBalance, fuzzer.config.Fuzzing.BlockGasLimit, nil, nil, nil, msgData)
 				msg.FillFromTestChainProperties(testChain)
 
 				// Create a new pending block we'll commit to chain
@@ -551,7 +968,7 @@ func chainSetupFromCompilations(fuzzer *Fuzzer, testChain *chain.TestChain) (*ex
 					if err != nil {
 						return nil, fmt.Errorf("failed to reset to genesis block: %v", err)
 					} else {
-						_, err = calls.ExecuteCallSequenceWithExecutionTracer(testChain, fuzzer.contractDefinitions, []*calls.CallSequenceElement{cse}, true)
+						_, err = calls.ExecuteCallSequenceWithExecutionTracer(testChain, fuzzer.contractDefinitions, []*calls.CallSequenceElement{cse}, true, fuzzer.traceLimits(), nil)
 						if err != nil {
 							return nil, fmt.Errorf("deploying %s returned a failed status: %v", contractName, block.MessageResults[0].ExecutionResult.Err)
 						}
@@ -561,9 +978,17 @@ func chainSetupFromCompilations(fuzzer *Fuzzer, testChain *chain.TestChain) (*ex
 					return cse.ExecutionTrace, fmt.Errorf("deploying %s returned a failed status: %v", contractName, block.MessageResults[0].ExecutionResult.Err)
 				}
 
+				// Verify a predeployed contract was deployed at the address it was configured for. Its address
+				// override is consumed by its first deployment, so this can only fail if its deployment data was
+				// deployed at its address earlier.
+				deployedAddr := block.MessageResults[0].Receipt.ContractAddress
+				if isPredeploy && deployedAddr != predeployedContractAddrs[contractName] {
+					return nil, fmt.Errorf("predeployed contract %v was deployed at %v rather than its configured address %v", contractName, deployedAddr.String(), predeployedContractAddrs[contractName].String())
+				}
+
 				// Record our deployed contract so the next config-specified constructor args can reference this
 				// contract by name.
-				deployedContractAddr[contractName] = block.MessageResults[0].Receipt.ContractAddress
+				deployedContractAddr[contractName] = deployedAddr
 
 				// Flag that we found a matching compiled contract definition and deployed it, then exit out of this
 				// inner loop to process the next contract to deploy in the outer loop.
@@ -574,12 +999,57 @@ func chainSetupFromCompilations(fuzzer *Fuzzer, testChain *chain.TestChain) (*ex
 
 		// If we did not find a contract corresponding to this item in the deployment order, we throw an error.
 		if !found {
+			if i >= specContractsIndex {
+				return nil, fmt.Errorf("%v was specified in the spec contracts but was not found in the compilation artifacts", contractName)
+			}
 			return nil, fmt.Errorf("%v was specified in the target contracts but was not found in the compilation artifacts", contractName)
 		}
 	}
 	return nil, nil
 }
 
+// predeployedContractAddresses parses the addresses of the predeployed contracts specified in the Fuzzer.config.
+// Returns a mapping of contract names to the address they must be deployed at, or an error if one occurs.
+func (f *Fuzzer) predeployedContractAddresses() (map[string]common.Address, error) {
+	predeployedContractAddrs := make(map[string]common.Address, len(f.config.Fuzzing.PredeployedContracts))
+	for contractName, addrStr := range f.config.Fuzzing.PredeployedContracts {
+		contractAddr, err := utils.HexStringToAddress(addrStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address provided for a predeployed contract: %v", contractName)
+		}
+		predeployedContractAddrs[contractName] = contractAddr
+	}
+	return predeployedContractAddrs, nil
+}
+
+// contractDeploymentData constructs the deployment message data for the provided contract, encoding any constructor
+// arguments specified in the Fuzzer.config. Contract names used as constructor arguments are resolved using the
+// provided mapping of contract names to deployed addresses.
+// Returns the deployment message data, or an error if one occurs.
+func (f *Fuzzer) contractDeploymentData(contract *fuzzerTypes.Contract, deployedContractAddr map[string]common.Address) ([]byte, error) {
+	// Concatenate constructor arguments, if necessary
+	args := make([]any, 0)
+	if len(contract.CompiledContract().Abi.Constructor.Inputs) > 0 {
+		jsonArgs, ok := f.config.Fuzzing.ConstructorArgs[contract.Name()]
+		if !ok {
+			return nil, fmt.Errorf("constructor arguments for contract %s not provided", contract.Name())
+		}
+		decoded, err := valuegeneration.DecodeJSONArgumentsFromMap(contract.CompiledContract().Abi.Constructor.Inputs,
+			jsonArgs, deployedContractAddr)
+		if err != nil {
+			return nil, err
+		}
+		args = decoded
+	}
+
+	// Construct our deployment message/tx data field
+	msgData, err := contract.CompiledContract().GetDeploymentMessageData(args)
+	if err != nil {
+		return nil, fmt.Errorf("initial contract deployment failed for contract \"%v\", error: %v", contract.Name(), err)
+	}
+	return msgData, nil
+}
+
 // defaultCallSequenceGeneratorConfigFunc is a NewCallSequenceGeneratorConfigFunc which creates a
 // CallSequenceGeneratorConfig with a default configuration. Returns the config or an error, if one occurs.
 func defaultCallSequenceGeneratorConfigFunc(fuzzer *Fuzzer, valueSet *valuegeneration.ValueSet, randomProvider *rand.Rand) (*CallSequenceGeneratorConfig, error) {
@@ -601,6 +1071,10 @@ func defaultCallSequenceGeneratorConfigFunc(fuzzer *Fuzzer, valueSet *valuegener
 		MutateStringGenerateNewBias:     0.7,
 		MutateIntegerProbability:        0.1,
 		MutateIntegerGenerateNewBias:    0.5,
+		MinMutatedLeaves:                fuzzer.config.Fuzzing.ValueMutation.MinMutatedLeaves,
+		MaxMutatedLeaves:                fuzzer.config.Fuzzing.ValueMutation.MaxMutatedLeaves,
+		IntegerMutationWeights:          valuegeneration.IntegerMutationWeights(fuzzer.config.Fuzzing.ValueMutation.IntegerOperatorWeights),
+		BytesMutationWeights:            valuegeneration.BytesMutationWeights(fuzzer.config.Fuzzing.ValueMutation.BytesOperatorWeights),
 		RandomValueGeneratorConfig: &valuegeneration.RandomValueGeneratorConfig{
 			GenerateRandomArrayMinSize:  0,
 			GenerateRandomArrayMaxSize:  100,
@@ -623,6 +1097,7 @@ func defaultCallSequenceGeneratorConfigFunc(fuzzer *Fuzzer, valueSet *valuegener
 		RandomMutatedCorpusTailWeight:            10,
 		RandomMutatedSpliceAtRandomWeight:        20,
 		RandomMutatedInterleaveAtRandomWeight:    10,
+		RandomPermutedSendersWeight:              50,
 		ValueGenerator:                           mutationalGenerator,
 		ValueMutator:                             mutationalGenerator,
 	}
@@ -689,6 +1164,34 @@ func (f *Fuzzer) spawnWorkersLoop(baseTestChain *chain.TestChain) error {
 			if err == nil && workerCreatedErr != nil {
 				err = workerCreatedErr
 			}
+
+			// Define a function to free our worker slot, making way for another worker. This is done once the worker
+			// exits, or once it is abandoned by the watchdog after stalling, whichever comes first.
+			releaseWorkerSlot := sync.OnceValue(func() error {
+				// Free our worker id before unblocking our channel, as a free one will be expected. If the worker was
+				// abandoned, it may still be using its random provider, so its replacement is given a new one. This
+				// does not affect reproducibility, as it is re-seeded before each call sequence.
+				availableWorkerIndexedLock.Lock()
+				if worker != nil && worker.abandoned.Load() {
+					workerSlotInfo.randomProvider = rand.New(rand.NewSource(f.seed))
+				}
+				availableWorkerSlotQueue = append(availableWorkerSlotQueue, workerSlotInfo)
+				availableWorkerIndexedLock.Unlock()
+
+				// Publish an event indicating we destroyed a worker.
+				workerDestroyedErr := f.Events.WorkerDestroyed.Publish(FuzzerWorkerDestroyedEvent{Worker: worker})
+
+				// Unblock our channel by freeing our capacity of another item, making way for another worker.
+				<-threadReserveChannel
+				return workerDestroyedErr
+			})
+			if worker != nil {
+				worker.onAbandon = func() {
+					if workerDestroyedErr := releaseWorkerSlot(); workerDestroyedErr != nil {
+						f.logger.Error("Failed to publish an event indicating an abandoned worker was destroyed", workerDestroyedErr)
+					}
+				}
+			}
 			if err == nil {
 				// Publish an event indicating we created a worker.
 				workerCreatedErr = f.Events.WorkerCreated.Publish(FuzzerWorkerCreatedEvent{Worker: worker})
@@ -697,32 +1200,35 @@ func (f *Fuzzer) spawnWorkersLoop(baseTestChain *chain.TestChain) error {
 				}
 			}
 
-			// Run the worker and check if we received a cancelled signal, or we encountered an error.
+			// Run the worker and check if we received a cancelled signal, or we encountered an error. If the worker was
+			// abandoned after stalling, a new worker has taken its place, so its results are disregarded.
 			if err == nil {
 				ctxCancelled, workerErr := worker.run(baseTestChain)
+				if worker.abandoned.Load() {
+					_ = releaseWorkerSlot()
+					return
+				}
 				if workerErr != nil {
 					err = workerErr
 				}
 
+				// Add any call sequences the worker staged for the corpus before it exited.
+				flushErr := worker.flushCorpusStagingBuffer()
+				if err == nil && flushErr != nil {
+					err = flushErr
+				}
+
 				// If we received a cancelled signal, signal our exit from the working loop.
 				if working && ctxCancelled {
 					working = false
 				}
 			}
 
-			// Free our worker id before unblocking our channel, as a free one will be expected.
-			availableWorkerIndexedLock.Lock()
-			availableWorkerSlotQueue = append(availableWorkerSlotQueue, workerSlotInfo)
-			availableWorkerIndexedLock.Unlock()
-
-			// Publish an event indicating we destroyed a worker.
-			workerDestroyedErr := f.Events.WorkerDestroyed.Publish(FuzzerWorkerDestroyedEvent{Worker: worker})
+			// Free our worker slot, making way for another worker.
+			workerDestroyedErr := releaseWorkerSlot()
 			if err == nil && workerDestroyedErr != nil {
 				err = workerDestroyedErr
 			}
-
-			// Unblock our channel by freeing our capacity of another item, making way for another worker.
-			<-threadReserveChannel
 		}(workerSlotInfo)
 	}
 
@@ -749,32 +1255,75 @@ func (f *Fuzzer) spawnWorkersLoop(baseTestChain *chain.TestChain) error {
 	return err
 }
 
-// Start begins a fuzzing operation on the provided project configuration. This operation will not return until an error
-// is encountered or the fuzzing operation has completed. Its execution can be cancelled using the Stop method.
-// Returns an error if one is encountered.
-func (f *Fuzzer) Start() error {
+// setUpCampaign prepares the Fuzzer for a fuzzing campaign, up to the point where workers would be spawned. It creates
+// the corpus, sets up the base test chain with the fuzzer's deployment and setup strategy, and registers test cases.
+// If dryRun is true, the corpus is neither replayed nor written, and problems which would prevent fuzzing are left for
+// the caller to report.
+// Returns the base test chain workers clone their chains from, or an error if one occurs.
+func (f *Fuzzer) setUpCampaign(dryRun bool) (*chain.TestChain, error) {
 	// Define our variable to catch errors
 	var err error
 
-	// While we're fuzzing, we'll want to have an initialized random provider.
-	f.randomProvider = rand.New(rand.NewSource(time.Now().UnixNano()))
+	// While we're fuzzing, we'll want to have an initialized random provider. If no seed was provided, we choose one.
+	f.seed = f.config.Fuzzing.Seed
+	if f.seed == 0 {
+		f.seed = time.Now().UnixNano()
+	}
+	f.randomProvider = rand.New(rand.NewSource(f.seed))
+	f.logger.Info("Using seed ", colors.Bold, f.seed, colors.Reset)
 
 	// Create our main and emergency running context (allows us to cancel across threads)
 	f.ctx, f.ctxCancelFunc = context.WithCancel(context.Background())
 	f.emergencyCtx, f.emergencyCtxCancelFunc = context.WithCancel(context.Background())
 
 	// If we set a timeout, create the timeout context now, as we're about to begin fuzzing.
-	if f.config.Fuzzing.Timeout > 0 {
+	if !dryRun && f.config.Fuzzing.Timeout > 0 {
 		f.logger.Info("Running with a timeout of ", colors.Bold, f.config.Fuzzing.Timeout, " seconds")
 		f.ctx, f.ctxCancelFunc = context.WithTimeout(f.ctx, time.Duration(f.config.Fuzzing.Timeout)*time.Second)
 	}
 
-	// Set up the corpus
+	// Validate the shape of test methods before doing any work, so malformed tests are reported immediately. A dry
+	// run reports them rather than failing, so every problem with the campaign is reported at once.
+	if !dryRun {
+		if err = f.validateTestMethods(); err != nil {
+			f.logger.Error("Failed to start fuzzer", err)
+			return nil, err
+		}
+
+		// Report the tests discovered in each contract, so tests which never registered (e.g. due to a misspelled
+		// prefix) are noticed. A dry run includes them in its report instead.
+		f.logTestDiscovery(f.testDiscovery())
+	}
+
+	// Set up the corpus. A dry run does not replay or write the corpus, so it uses an empty one held in memory.
 	f.logger.Info("Initializing corpus")
-	f.corpus, err = corpus.NewCorpus(f.config.Fuzzing.CorpusDirectory)
+	corpusDirectory := f.config.Fuzzing.CorpusDirectory
+	if dryRun {
+		corpusDirectory = ""
+	}
+	f.corpus, err = corpus.NewCorpus(corpusDirectory)
 	if err != nil {
 		f.logger.Error("Failed to create the corpus", err)
-		return err
+		return nil, err
+	}
+	f.corpus.SetRecordElementMetadata(f.config.Fuzzing.CorpusElementMetadata)
+	f.corpus.SetMutationTagAllowlist(f.config.Fuzzing.CorpusTagAllowlist)
+
+	// If we run in a worker process, the corpus is verified and written by the Fuzzer which spawned us.
+	f.corpus.SetReadOnly(f.workerProcess != nil || dryRun)
+
+	// If we run in a worker process, report each call replayed from the corpus before executing it, so it can be
+	// recovered if we crash.
+	if f.workerProcess != nil {
+		f.corpus.SetCallExecutionHandler(f.workerProcess.reportCall)
+	}
+
+	// Verify the corpus was recorded with the current campaign configuration, and record it with the corpus.
+	if f.workerProcess == nil && !dryRun {
+		if err = f.checkCorpusFingerprint(); err != nil {
+			f.logger.Error("Failed to verify the corpus fingerprint", err)
+			return nil, err
+		}
 	}
 
 	// Initialize our metrics and valueGenerator.
@@ -784,13 +1333,21 @@ func (f *Fuzzer) Start() error {
 	f.testCasesLock.Lock()
 	f.testCases = make([]TestCase, 0)
 	f.testCasesFinished = make(map[string]TestCase)
+	f.testCasesFinishedTimes = make(map[string]time.Time)
+	f.testCasesDiscoveredTimes = make(map[string]time.Time)
 	f.testCasesLock.Unlock()
 
 	// Create our test chain
 	baseTestChain, err := f.createTestChain()
 	if err != nil {
 		f.logger.Error("Failed to create the test chain", err)
-		return err
+		return nil, err
+	}
+
+	// If we exchange call sequences with worker processes, track the contracts deployed while setting up the chain, so
+	// the calls in those call sequences can be resolved.
+	if f.config.Fuzzing.WorkerProcesses.Enabled || f.workerProcess != nil {
+		f.trackWorkerProcessDeployments(baseTestChain)
 	}
 
 	// Set it up with our deployment/setup strategy defined by the fuzzer.
@@ -802,7 +1359,7 @@ func (f *Fuzzer) Start() error {
 		} else {
 			f.logger.Error("Failed to initialize the test chain", err)
 		}
-		return err
+		return nil, err
 	}
 	f.logger.Info("Finished setting up test chain")
 
@@ -812,13 +1369,13 @@ func (f *Fuzzer) Start() error {
 		f.logger.Info("Running call sequences in the corpus")
 	}
 	startTime := time.Now()
-	corpusActiveSequences, corpusTotalSequences, err = f.corpus.Initialize(baseTestChain, f.contractDefinitions)
+	corpusActiveSequences, corpusTotalSequences, err = f.corpus.Initialize(baseTestChain, f.contractDefinitions, fuzzerTypes.BytecodeMatchingMode(f.config.Fuzzing.Testing.ContractMatchingMode), f.config.Fuzzing.CorpusDropOutdatedCalls)
 	if corpusTotalSequences > 0 {
 		f.logger.Info("Finished running call sequences in the corpus in ", time.Since(startTime).Round(time.Second))
 	}
 	if err != nil {
 		f.logger.Error("Failed to initialize the corpus", err)
-		return err
+		return nil, err
 	}
 
 	// Log corpus health statistics, if we have any existing sequences.
@@ -828,36 +1385,87 @@ func (f *Fuzzer) Start() error {
 			"health: ", colors.Bold, int(float32(corpusActiveSequences)/float32(corpusTotalSequences)*100.0), "%", colors.Reset, ", ",
 			"sequences: ", colors.Bold, corpusTotalSequences, " (", corpusActiveSequences, " valid, ", corpusTotalSequences-corpusActiveSequences, " invalid)", colors.Reset,
 		)
+		f.logger.Info(colors.Bold, "corpus: ", colors.Reset, "loaded ", f.corpus.Stats().String())
 	}
 
-	// Log the start of our fuzzing campaign.
-	f.logger.Info("Fuzzing with ", colors.Bold, f.config.Fuzzing.Workers, colors.Reset, " workers")
+	// If we are fuzzing deterministically, partition the corpus between our workers, so they do not observe each
+	// other's progress.
+	f.deterministicWorkerStates = nil
+	if !dryRun && f.config.Fuzzing.Deterministic {
+		f.logger.Info("Fuzzing deterministically, workers will only mutate the call sequences they discover until fuzzing ends")
+		f.initDeterministicWorkerStates()
+	}
 
-	// Start our printing loop now that we're about to begin fuzzing.
-	go f.printMetricsLoop()
+	// Limit the number of workers which shrink call sequences concurrently, unless workers must shrink the call
+	// sequences they discover themselves, as we are fuzzing deterministically.
+	f.shrinkScheduler = nil
+	if f.deterministicWorkerStates == nil {
+		maxShrinkingWorkers := f.config.Fuzzing.MaxShrinkingWorkers
+		if maxShrinkingWorkers == 0 {
+			maxShrinkingWorkers = max(f.config.Fuzzing.Workers/2, 1)
+		}
+		var failures *failureRegistry
+		if f.config.Fuzzing.Testing.DeduplicateFailures {
+			failures = f.failures
+		}
+		f.shrinkScheduler = newShrinkScheduler(maxShrinkingWorkers, failures)
+	}
 
 	// Publish a fuzzer starting event.
 	err = f.Events.FuzzerStarting.Publish(FuzzerStartingEvent{Fuzzer: f})
 	if err != nil {
 		f.logger.Error("FuzzerStarting event subscriber returned an error", err)
+		return nil, err
+	}
+	return baseTestChain, nil
+}
+
+// checkTestsFound verifies a fuzzing campaign has tests to run, if testing is enabled and the project configuration
+// specifies to stop when there are none.
+// Returns an error if no tests were found but were expected.
+func (f *Fuzzer) checkTestsFound() error {
+	if !f.config.Fuzzing.Testing.Enabled || !f.config.Fuzzing.Testing.StopOnNoTests || len(f.testCases) > 0 {
+		return nil
+	}
+	if !f.config.Fuzzing.Testing.TestViewMethods {
+		return fmt.Errorf("no assertion, property, optimization, or custom tests were found to fuzz and testing view methods is disabled")
+	}
+	return fmt.Errorf("no assertion, property, optimization, or custom tests were found to fuzz")
+}
+
+// Start begins a fuzzing operation on the provided project configuration. This operation will not return until an error
+// is encountered or the fuzzing operation has completed. Its execution can be cancelled using the Stop method.
+// Returns an error if one is encountered.
+func (f *Fuzzer) Start() error {
+	// Set up our campaign, up to the point workers are spawned.
+	baseTestChain, err := f.setUpCampaign(false)
+	if err != nil {
 		return err
 	}
 
+	// Log the start of our fuzzing campaign.
+	if f.config.Fuzzing.WorkerProcesses.Enabled {
+		f.logger.Info("Fuzzing with ", colors.Bold, f.config.Fuzzing.Workers, colors.Reset, " worker processes")
+	} else {
+		f.logger.Info("Fuzzing with ", colors.Bold, f.config.Fuzzing.Workers, colors.Reset, " workers")
+	}
+
+	// Start our printing loop now that we're about to begin fuzzing.
+	go f.printMetricsLoop()
+
+	// If testing is disabled, we are only exploring to build our corpus and coverage, so no tests are expected.
+	if !f.config.Fuzzing.Testing.Enabled {
+		f.logger.Info("Testing is disabled, fuzzing in exploration mode")
+	}
+
 	// If StopOnNoTests is true and there are no test cases, then throw an error
-	if f.config.Fuzzing.Testing.StopOnNoTests && len(f.testCases) == 0 {
-		err = fmt.Errorf("no assertion, property, optimization, or custom tests were found to fuzz")
-		if !f.config.Fuzzing.Testing.TestViewMethods {
-			err = fmt.Errorf("no assertion, property, optimization, or custom tests were found to fuzz and testing view methods is disabled")
-		}
+	if err = f.checkTestsFound(); err != nil {
 		f.logger.Error("Failed to start fuzzer", err)
 		return err
 	}
 
 	// Determine coverage report directory
-	coverageReportDir := filepath.Join("crytic-export", "coverage")
-	if f.config.Fuzzing.CorpusDirectory != "" {
-		coverageReportDir = filepath.Join(f.config.Fuzzing.CorpusDirectory, "coverage")
-	}
+	coverageReportDir := f.coverageReportDirectory()
 
 	// Create coverage directory if needed
 	if err := utils.MakeDirectory(coverageReportDir); err != nil {
@@ -867,14 +1475,67 @@ func (f *Fuzzer) Start() error {
 	// Start live report worker if enabled
 	f.startLiveReportWorker(coverageReportDir)
 
-	// Run the main worker loop
-	err = f.spawnWorkersLoop(baseTestChain)
+	// If we set a coverage plateau threshold, start tracking it now, as we're about to begin fuzzing.
+	if f.config.Fuzzing.StopOnCoveragePlateau > 0 {
+		f.logger.Info("Stopping after ", colors.Bold, f.config.Fuzzing.StopOnCoveragePlateau, " seconds", colors.Reset, " without new coverage")
+		f.lastNewCoverageTime.Store(time.Now().UnixNano())
+		go f.coveragePlateauLoop()
+	}
+
+	// If we enabled an adaptive sequence length, start adjusting it now, as we're about to begin fuzzing.
+	if f.config.Fuzzing.AdaptiveSequenceLength.Enabled {
+		f.logger.Info("Adjusting the call sequence length every ", colors.Bold, f.config.Fuzzing.AdaptiveSequenceLength.AdjustmentInterval, " seconds", colors.Reset,
+			" between ", f.config.Fuzzing.AdaptiveSequenceLength.MinLength, " and ", f.config.Fuzzing.AdaptiveSequenceLength.MaxLength, " call(s)")
+		go f.adaptiveSequenceLengthLoop()
+	}
+
+	// If we enabled stuck campaign detection, start checking for it now, as we're about to begin fuzzing.
+	if f.config.Fuzzing.StuckCampaignDetection.CallThreshold > 0 {
+		go f.stuckCampaignLoop()
+	}
+
+	// If we enabled the worker watchdog, start monitoring workers for stalls now, as we're about to begin fuzzing.
+	if f.config.Fuzzing.WorkerWatchdog.StallTimeout > 0 {
+		stallTimeout := time.Duration(f.config.Fuzzing.WorkerWatchdog.StallTimeout) * time.Second
+		f.workerWatchdog = newWorkerWatchdog(stallTimeout, f.config.Fuzzing.WorkerWatchdog.RecreateWorkers, f.logger)
+		go f.workerWatchdog.run(f.ctx, time.Second)
+	}
+
+	// Unless synchronous corpus writes were requested, write the corpus to disk in the background, so workers are
+	// not stalled by slow filesystems when they add to it.
+	if !f.config.Fuzzing.SynchronousCorpusWrites {
+		f.corpus.StartBackgroundWrites(time.Duration(f.config.Fuzzing.CorpusFlushInterval) * time.Millisecond)
+	}
+
+	// Run the main worker loop, spawning worker processes rather than in-process workers if configured.
+	if f.config.Fuzzing.WorkerProcesses.Enabled {
+		err = f.spawnWorkerProcessesLoop()
+	} else {
+		err = f.spawnWorkersLoop(baseTestChain)
+	}
 	if err != nil {
 		f.logger.Error("Encountered an error in the main fuzzing loop", err)
 	}
 
 	// NOTE: After this point, we capture errors but do not return immediately, as we want to exit gracefully.
 
+	// If we fuzzed deterministically, add the call sequences discovered by each worker to the corpus.
+	if f.deterministicWorkerStates != nil {
+		mergeErr := f.mergeDeterministicWorkerStates()
+		if err == nil && mergeErr != nil {
+			err = mergeErr
+			f.logger.Error("Failed to add the call sequences discovered by workers to the corpus", err)
+		}
+	}
+
+	// Stop writing the corpus in the background, writing any changes which are still pending. We do this even if we
+	// had a previous error, as we don't want to lose corpus entries.
+	backgroundWritesErr := f.corpus.StopBackgroundWrites()
+	if err == nil && backgroundWritesErr != nil {
+		err = backgroundWritesErr
+		f.logger.Error("Failed to write the corpus to disk", err)
+	}
+
 	// If we have coverage enabled and a corpus directory set, write the corpus. We do this even if we had a
 	// previous error, as we don't want to lose corpus entries.
 	if f.config.Fuzzing.CoverageEnabled {
@@ -892,37 +1553,31 @@ func (f *Fuzzer) Start() error {
 		f.logger.Error("FuzzerStopping event subscriber returned an error", err)
 	}
 
-	// Print our results on exit.
+	// Print our results on exit, and write them to our results file if one is configured.
 	f.printExitingResults()
+	if resultsPath := f.config.Fuzzing.ResultsPath; resultsPath != "" {
+		if resultsErr := f.writeResults(resultsPath); resultsErr != nil {
+			f.logger.Error("Failed to write the campaign results", resultsErr)
+		} else {
+			f.logger.Info(fmt.Sprintf("Results saved to: %s", resultsPath), colors.Bold, colors.Reset)
+		}
+	}
+	if sarifPath := f.config.Fuzzing.SARIFPath; sarifPath != "" {
+		if sarifErr := f.writeSARIF(sarifPath); sarifErr != nil {
+			f.logger.Error("Failed to write the SARIF log", sarifErr)
+		} else {
+			f.logger.Info(fmt.Sprintf("SARIF log saved to: %s", sarifPath), colors.Bold, colors.Reset)
+		}
+	}
 
 	// Finally, generate our coverage report if we have set a valid corpus directory.
 	if err == nil && len(f.config.Fuzzing.CoverageFormats) > 0 {
-		// Write to the default directory if we have no corpus directory set.
-		coverageReportDir := filepath.Join("crytic-export", "coverage")
-		if f.config.Fuzzing.CorpusDirectory != "" {
-			coverageReportDir = filepath.Join(f.config.Fuzzing.CorpusDirectory, "coverage")
+		reportPaths, err := f.generateCoverageReports()
+		for _, reportPath := range reportPaths {
+			f.logger.Info(fmt.Sprintf("Coverage report saved to: %s", reportPath), colors.Bold, colors.Reset)
 		}
-		sourceAnalysis, err := coverage.AnalyzeSourceCoverage(f.compilations, f.corpus.CoverageMaps())
-
 		if err != nil {
-			f.logger.Error("Failed to analyze source coverage", err)
-		} else {
-			var path string
-			for _, reportType := range f.config.Fuzzing.CoverageFormats {
-				switch reportType {
-				case "html":
-					path, err = coverage.WriteHTMLReport(sourceAnalysis, coverageReportDir)
-				case "lcov":
-					path, err = coverage.WriteLCOVReport(sourceAnalysis, coverageReportDir)
-				default:
-					err = fmt.Errorf("unsupported coverage report type: %s", reportType)
-				}
-				if err != nil {
-					f.logger.Error(fmt.Sprintf("Failed to generate %s coverage report", reportType), err)
-				} else {
-					f.logger.Info(fmt.Sprintf("%s report(s) saved to: %s", reportType, path), colors.Bold, colors.Reset)
-				}
-			}
+			f.logger.Error("Failed to generate coverage reports", err)
 		}
 	}
 
@@ -937,7 +1592,9 @@ func (f *Fuzzer) Start() error {
 func (f *Fuzzer) Stop() {
 	// Stop live report worker if running
 	if f.liveReportCancel != nil {
-		close(f.liveReportCancel)
+		f.liveReportCancelOnce.Do(func() {
+			close(f.liveReportCancel)
+		})
 	}
 
 	// Call the cancel function on our main running context to try stop all working goroutines
@@ -972,14 +1629,18 @@ func (f *Fuzzer) printMetricsLoop() {
 	lastGasUsed := big.NewInt(0)
 
 	lastPrintedTime := time.Time{}
+	metricsUpdates := 0
 	for !utils.CheckContextDone(f.ctx) {
 		// Obtain our metrics
 		callsTested := f.metrics.CallsTested()
+		callsReplayed := f.metrics.CallsReplayed()
 		sequencesTested := f.metrics.SequencesTested()
+		sequencesReplayed := f.metrics.SequencesReplayed()
 		gasUsed := f.metrics.GasUsed()
 		failedSequences := f.metrics.FailedSequences()
 		workerStartupCount := f.metrics.WorkerStartupCount()
 		workersShrinking := f.metrics.WorkersShrinkingCount()
+		revertClassificationCounts := f.metrics.RevertClassificationCounts()
 
 		// Calculate time elapsed since the last update
 		secondsSinceLastUpdate := time.Since(lastPrintedTime).Seconds()
@@ -995,18 +1656,43 @@ func (f *Fuzzer) printMetricsLoop() {
 		logBuffer.Append(colors.Bold, "fuzz: ", colors.Reset)
 		logBuffer.Append("elapsed: ", colors.Bold, time.Since(startTime).Round(time.Second).String(), colors.Reset)
 		logBuffer.Append(", calls: ", colors.Bold, fmt.Sprintf("%d (%d/sec)", callsTested, uint64(float64(new(big.Int).Sub(callsTested, lastCallsTested).Uint64())/secondsSinceLastUpdate)), colors.Reset)
+		logBuffer.Append(", replayed: ", colors.Bold, fmt.Sprintf("%d calls (%d seq)", callsReplayed, sequencesReplayed), colors.Reset)
+		logBuffer.Append(", generated: ", colors.Bold, fmt.Sprintf("%d calls (%d seq)", new(big.Int).Sub(callsTested, callsReplayed), new(big.Int).Sub(sequencesTested, sequencesReplayed)), colors.Reset)
 		logBuffer.Append(", seq/s: ", colors.Bold, fmt.Sprintf("%d", uint64(float64(new(big.Int).Sub(sequencesTested, lastSequencesTested).Uint64())/secondsSinceLastUpdate)), colors.Reset)
+		logBuffer.Append(", avg seq time: ", colors.Bold, f.metrics.AverageSequenceDuration().Round(time.Microsecond).String(), colors.Reset)
 		logBuffer.Append(", coverage: ", colors.Bold, fmt.Sprintf("%d", f.corpus.CoverageMaps().UniquePCs()), colors.Reset)
-		logBuffer.Append(", corpus: ", colors.Bold, fmt.Sprintf("%d", f.corpus.ActiveMutableSequenceCount()), colors.Reset)
+		logBuffer.Append(", corpus: ", colors.Bold, fmt.Sprintf("%d", f.corpus.ActiveMutableSequenceCount()+f.deterministicDiscoveredCount()), colors.Reset)
+		if f.config.Fuzzing.AdaptiveSequenceLength.Enabled {
+			logBuffer.Append(", seq len: ", colors.Bold, fmt.Sprintf("%d", f.SequenceLength()), colors.Reset)
+		}
 		logBuffer.Append(", failures: ", colors.Bold, fmt.Sprintf("%d/%d", failedSequences, sequencesTested), colors.Reset)
 		logBuffer.Append(", gas/s: ", colors.Bold, fmt.Sprintf("%d", uint64(float64(new(big.Int).Sub(gasUsed, lastGasUsed).Uint64())/secondsSinceLastUpdate)), colors.Reset)
+		logBuffer.Append(", reverts: ", colors.Bold, formatRevertClassificationCounts(revertClassificationCounts), colors.Reset)
 		if f.logger.Level() <= zerolog.DebugLevel {
 			logBuffer.Append(", shrinking: ", colors.Bold, fmt.Sprintf("%v", workersShrinking), colors.Reset)
+			if f.shrinkScheduler != nil {
+				logBuffer.Append(", shrink queue: ", colors.Bold, fmt.Sprintf("%d", f.shrinkScheduler.queued()), colors.Reset)
+			}
 			logBuffer.Append(", mem: ", colors.Bold, fmt.Sprintf("%v/%v MB", memoryUsedMB, memoryTotalMB), colors.Reset)
 			logBuffer.Append(", resets/s: ", colors.Bold, fmt.Sprintf("%d", uint64(float64(new(big.Int).Sub(workerStartupCount, lastWorkerStartupCount).Uint64())/secondsSinceLastUpdate)), colors.Reset)
 		}
 		f.logger.Info(logBuffer.Elements()...)
 
+		// Print the composition of the corpus, which helps explain how mutation targets are selected.
+		f.logger.Info(colors.Bold, "corpus: ", colors.Reset, f.corpus.Stats().String())
+
+		// Periodically print how thoroughly each contract was exercised, so contracts the harness effectively ignores
+		// can be spotted.
+		metricsUpdates++
+		if interval := f.config.Fuzzing.ContractMetricsInterval; interval > 0 && metricsUpdates%interval == 0 {
+			if contractMetrics := f.metrics.ContractMetrics(); len(contractMetrics) > 0 {
+				contractBuffer := logging.NewLogBuffer()
+				contractBuffer.Append(colors.Bold, "contracts: ", colors.Reset)
+				appendContractMetrics(contractBuffer, contractMetrics)
+				f.logger.Info(contractBuffer.Elements()...)
+			}
+		}
+
 		// Update our delta tracking metrics
 		lastPrintedTime = time.Now()
 		lastCallsTested = callsTested
@@ -1014,10 +1700,18 @@ func (f *Fuzzer) printMetricsLoop() {
 		lastGasUsed = gasUsed
 		lastWorkerStartupCount = workerStartupCount
 
-		// If we reached our transaction threshold, halt
+		// If we reached our transaction threshold, halt. Calls replayed from the corpus are excluded if configured.
+		// When fuzzing deterministically, each worker instead enforces its own share of the threshold.
 		// TODO: We should move this logic somewhere else because it is weird that the metrics loop halts the fuzzer
 		testLimit := f.config.Fuzzing.TestLimit
-		if testLimit > 0 && (!callsTested.IsUint64() || callsTested.Uint64() >= testLimit) {
+		if f.config.Fuzzing.Deterministic {
+			testLimit = 0
+		}
+		callsCounted := callsTested
+		if !f.config.Fuzzing.CorpusReplayCountsTowardTestLimit {
+			callsCounted = new(big.Int).Sub(callsTested, callsReplayed)
+		}
+		if testLimit > 0 && (!callsCounted.IsUint64() || callsCounted.Uint64() >= testLimit) {
 			f.logger.Info("Transaction test limit reached, halting now...")
 			f.Stop()
 			break
@@ -1028,14 +1722,54 @@ func (f *Fuzzer) printMetricsLoop() {
 	}
 }
 
+// coveragePlateauLoop stops the fuzzer once no new coverage has been achieved for the configured coverage plateau
+// threshold, or returns when ctx signals a stopped operation.
+func (f *Fuzzer) coveragePlateauLoop() {
+	plateauThreshold := time.Duration(f.config.Fuzzing.StopOnCoveragePlateau) * time.Second
+	for {
+		// Determine how long remains until the threshold is reached, given the last time new coverage was achieved.
+		remaining := plateauThreshold - time.Since(time.Unix(0, f.lastNewCoverageTime.Load()))
+		if remaining <= 0 {
+			f.logger.Info("No new coverage achieved for ", plateauThreshold, ", halting now...")
+			f.Stop()
+			return
+		}
+
+		// Wait until the threshold may have been reached, then check again, as new coverage may have been achieved.
+		select {
+		case <-f.ctx.Done():
+			return
+		case <-time.After(remaining):
+		}
+	}
+}
+
+// formatRevertClassificationCounts returns a displayable string describing the total amount of failed calls, along
+// with a breakdown of the amount for each RevertClassification which occurred.
+func formatRevertClassificationCounts(counts [revertClassificationCount]uint64) string {
+	total := uint64(0)
+	breakdown := make([]string, 0)
+	for classification, count := range counts {
+		if count > 0 {
+			total += count
+			breakdown = append(breakdown, fmt.Sprintf("%v: %d", RevertClassification(classification), count))
+		}
+	}
+	if len(breakdown) == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%d (%s)", total, strings.Join(breakdown, ", "))
+}
+
 // printExitingResults prints the TestCase results prior to the fuzzer exiting.
 func (f *Fuzzer) printExitingResults() {
 	// Define the order our test cases should be sorted by when considering status.
 	testCaseDisplayOrder := map[TestCaseStatus]int{
 		TestCaseStatusNotStarted: 0,
 		TestCaseStatusPassed:     1,
-		TestCaseStatusFailed:     2,
-		TestCaseStatusRunning:    3,
+		TestCaseStatusMuted:      2,
+		TestCaseStatusFailed:     3,
+		TestCaseStatusRunning:    4,
 	}
 
 	// Sort the test cases by status and then ID.
@@ -1055,6 +1789,7 @@ func (f *Fuzzer) printExitingResults() {
 	var (
 		testCountPassed int
 		testCountFailed int
+		testCountMuted  int
 	)
 
 	// Print the results of each individual test case.
@@ -1067,11 +1802,98 @@ func (f *Fuzzer) printExitingResults() {
 			testCountPassed++
 		} else if testCase.Status() == TestCaseStatusFailed {
 			testCountFailed++
+		} else if testCase.Status() == TestCaseStatusMuted {
+			testCountMuted++
 		}
 	}
 
 	// Print our final tally of test statuses.
 	f.logger.Info("Test summary: ", colors.GreenBold, testCountPassed, colors.Reset, " test(s) passed, ", colors.RedBold, testCountFailed, colors.Reset, " test(s) failed")
+	if testCountMuted > 0 {
+		f.logger.Info(colors.Bold, testCountMuted, colors.Reset, " test(s) were not fully tested, as their test provider was muted")
+	}
+
+	// If property tests reverted when evaluated, whether they hold could not always be determined, which often indicates
+	// a bug in the test harness. List them, so this is not hidden.
+	for _, testCase := range f.testCases {
+		if propertyTestCase, ok := testCase.(*PropertyTestCase); ok {
+			if reverts, revertReason := propertyTestCase.EvaluationReverts(); reverts > 0 {
+				f.logger.Warn(colors.Bold, propertyTestCase.Name(), colors.Reset, " reverted ", colors.Bold, reverts, colors.Reset, " time(s) when evaluated, first with: ", revertReason)
+			}
+		}
+	}
+
+	// If failures were discovered again while already known, they were not shrunk again. Report how often this
+	// happened, as it indicates how easily the failures were triggered.
+	if duplicateFailures := f.failures.duplicates(); duplicateFailures > 0 {
+		f.logger.Info("Known failures were rediscovered ", colors.Bold, duplicateFailures, colors.Reset, " time(s) without being shrunk again")
+	}
+
+	// If shrink requests were dropped as the shrink queue was full, the failures they described were only reported if
+	// they were rediscovered.
+	if f.shrinkScheduler != nil {
+		if droppedShrinkRequests := f.shrinkScheduler.dropped(); droppedShrinkRequests > 0 {
+			f.logger.Warn(colors.Bold, droppedShrinkRequests, colors.Reset, " shrink request(s) were dropped as the shrink queue was full")
+		}
+	}
+
+	// If deployed contracts could not be matched to any contract definition, their methods were not fuzzed. Warn the
+	// user, listing the init bytecode hashes of the first few so they can identify which contracts were not matched.
+	if unmatchedDeployments := f.unmatchedDeployments.count(); unmatchedDeployments > 0 {
+		logBuffer := logging.NewLogBuffer()
+		logBuffer.Append(colors.Bold, unmatchedDeployments, colors.Reset, " contract deployment(s) could not be matched to a contract definition. Init bytecode hashes of the first unmatched deployments:")
+		for _, initBytecodeHash := range f.unmatchedDeployments.hashes() {
+			logBuffer.Append("\n\t", initBytecodeHash.Hex())
+		}
+		f.logger.Warn(logBuffer.Elements()...)
+	}
+
+	// If errors returned by non-critical event subscribers were suppressed, the subscribers may not have observed
+	// every event. Warn the user, summarizing the errors of each subscriber.
+	if subscriberErrorSummary := f.subscriberErrors.summary(); len(subscriberErrorSummary) > 0 {
+		logBuffer := logging.NewLogBuffer()
+		logBuffer.Append("Errors returned by non-critical event subscribers were suppressed:")
+		appendSubscriberErrorSummary(logBuffer, subscriberErrorSummary)
+		f.logger.Warn(logBuffer.Elements()...)
+	}
+
+	// Print the call sequence length by which most new coverage and failures were discovered. This helps tune the call
+	// sequence length.
+	sequenceLengthHistogram := f.sequenceLengths.snapshot()
+	if coverageLength := sequenceLengthPercentile(sequenceLengthHistogram.NewCoverage, 0.9); coverageLength > 0 {
+		f.logger.Info("90% of new coverage was found by call ", colors.Bold, fmt.Sprintf("#%d", coverageLength), colors.Reset, " of call sequences")
+	}
+	if failureLength := sequenceLengthPercentile(sequenceLengthHistogram.Failures, 0.9); failureLength > 0 {
+		f.logger.Info("90% of failures were found by call ", colors.Bold, fmt.Sprintf("#%d", failureLength), colors.Reset, " of call sequences")
+	}
+
+	// Print the methods which failed most often, and why. This helps identify harnesses stuck behind a single
+	// require statement.
+	revertMetrics := f.metrics.RevertMetrics()
+	if len(revertMetrics) > 0 {
+		logBuffer := logging.NewLogBuffer()
+		logBuffer.Append("Most frequent reverts:")
+		for i := 0; i < len(revertMetrics) && i < 10; i++ {
+			appendRevertMetric(logBuffer, revertMetrics[i])
+		}
+		f.logger.Info(logBuffer.Elements()...)
+	}
+
+	// Print the call sequences which took the longest to execute, along with the method which used the most gas in
+	// each. This helps identify slow corpus items or methods which drag down throughput.
+	if slowestSequences := f.metrics.SlowestSequences(); len(slowestSequences) > 0 {
+		logBuffer := logging.NewLogBuffer()
+		logBuffer.Append("Slowest call sequences (average ", colors.Bold, f.metrics.AverageSequenceDuration().Round(time.Microsecond).String(), colors.Reset, "):")
+		for i := 0; i < len(slowestSequences) && i < 5; i++ {
+			appendSlowSequence(logBuffer, slowestSequences[i])
+		}
+		f.logger.Info(logBuffer.Elements()...)
+	}
+
+	// Print the final composition of the corpus.
+	if f.corpus != nil {
+		f.logger.Info(colors.Bold, "corpus: ", colors.Reset, f.corpus.Stats().String())
+	}
 }
 
 // startLiveReportWorker starts a goroutine that periodically generates coverage reports
@@ -1090,14 +1912,15 @@ func (f *Fuzzer) startLiveReportWorker(coverageReportDir string) {
 			select {
 			case <-ticker.C:
 				// Generate coverage report
-				sourceAnalysis, err := coverage.AnalyzeSourceCoverage(f.compilations, f.corpus.CoverageMaps())
+				sourceAnalysis, err := coverage.AnalyzeSourceCoverageWithSetup(f.compilations, f.corpus.CoverageMaps(), f.corpus.FuzzingCoverageMaps(), f.config.Fuzzing.ExcludeSetupCoverage)
 				if err != nil {
 					f.logger.Debug("Failed to analyze coverage for live report", err)
 					continue
 				}
+				sourceAnalysis = coverage.ExcludeSourceFiles(sourceAnalysis, f.config.Fuzzing.CoverageExclusions)
 
 				// Generate and write JSON data
-				jsonData, err := coverage.GenerateJSONCoverageData(sourceAnalysis)
+				jsonData, err := coverage.GenerateJSONCoverageData(sourceAnalysis, f.config.Fuzzing.CoverageBasePath)
 				if err != nil {
 					f.logger.Debug("Failed to generate JSON coverage data for live report", err)
 					continue
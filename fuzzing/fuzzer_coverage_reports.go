@@ -0,0 +1,159 @@
+package fuzzing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/crytic/medusa/fuzzing/config"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/corpus"
+	"github.com/crytic/medusa/fuzzing/coverage"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/crytic/medusa/utils"
+)
+
+// coverageReportDirectory returns the directory coverage reports are written to: the coverage directory within the
+// corpus directory, or within the default crytic-export directory if no corpus directory is set.
+func (f *Fuzzer) coverageReportDirectory() string {
+	if f.config.Fuzzing.CorpusDirectory != "" {
+		return filepath.Join(f.config.Fuzzing.CorpusDirectory, "coverage")
+	}
+	return filepath.Join("crytic-export", "coverage")
+}
+
+// generateCoverageReports writes a coverage report for each configured format, using the coverage achieved by the
+// corpus.
+// Returns the paths of the reports which were written, and an error joining any errors encountered.
+func (f *Fuzzer) generateCoverageReports() ([]string, error) {
+	return coverage.GenerateReports(f.compilations, f.corpus.CoverageMaps(), coverage.ReportOptions{
+		Formats:             f.config.Fuzzing.CoverageFormats,
+		ReportDir:           f.coverageReportDirectory(),
+		FuzzingCoverageMaps: f.corpus.FuzzingCoverageMaps(),
+		ExcludeSetupOnly:    f.config.Fuzzing.ExcludeSetupCoverage,
+		BasePath:            f.config.Fuzzing.CoverageBasePath,
+		ExcludePaths:        f.config.Fuzzing.CoverageExclusions,
+		HTMLTemplatePath:    f.config.Fuzzing.CoverageHTMLTemplate,
+		Metadata:            f.coverageReportMetadata(),
+	})
+}
+
+// coverageReportMetadata returns the metadata describing the fuzzing campaign which coverage reports are generated
+// for. If no campaign was run, such as when reports are regenerated from an existing corpus, no calls are reported as
+// tested.
+func (f *Fuzzer) coverageReportMetadata() coverage.ReportMetadata {
+	metadata := coverage.ReportMetadata{
+		GeneratedAt:     time.Now(),
+		Seed:            f.config.Fuzzing.Seed,
+		TargetContracts: f.config.Fuzzing.TargetContracts,
+	}
+	if f.metrics != nil {
+		metadata.SequencesTested = f.metrics.SequencesTested().Uint64()
+		metadata.CallsTested = f.metrics.CallsTested().Uint64()
+	}
+	return metadata
+}
+
+// RegenerateCoverageReports writes coverage reports from the existing corpus, without fuzzing. The test chain is set
+// up as it would be for a fuzzing campaign, the corpus is replayed on it to measure coverage, and a report is written
+// for each configured format. No call sequences are generated, and no fuzzer events are published, so test case
+// providers are never invoked. This allows reports to be regenerated with different coverage report options (e.g.
+// formats or exclusions) after a fuzzing campaign.
+// Returns the paths of the reports which were written, or an error if one occurs. A report which fails to be written
+// does not prevent the remaining reports from being written.
+func (f *Fuzzer) RegenerateCoverageReports() ([]string, error) {
+	// We can only regenerate reports from a corpus stored on disk.
+	if f.config.Fuzzing.CorpusDirectory == "" {
+		return nil, errors.New("a corpus directory must be set to regenerate coverage reports from")
+	}
+	if len(f.config.Fuzzing.CoverageFormats) == 0 {
+		return nil, errors.New("at least one coverage report format must be set to regenerate coverage reports")
+	}
+
+	// Replay the corpus to measure its coverage.
+	err := f.replayCorpus()
+	if err != nil {
+		return nil, err
+	}
+
+	// Write our coverage reports.
+	if err = utils.MakeDirectory(f.coverageReportDirectory()); err != nil {
+		return nil, fmt.Errorf("failed to create coverage directory: %v", err)
+	}
+	reportPaths, err := f.generateCoverageReports()
+	for _, reportPath := range reportPaths {
+		f.logger.Info(fmt.Sprintf("Coverage report saved to: %s", reportPath), colors.Bold, colors.Reset)
+	}
+	return reportPaths, err
+}
+
+// AnalyzeCorpusSourceCoverage measures the source coverage achieved by the corpus of the provided project
+// configuration, without fuzzing. The targets are compiled and the test chain is set up exactly as they would be for a
+// fuzzing campaign, so the calls in the corpus resolve to the contracts they targeted when it was collected. This
+// allows tooling to consume coverage programmatically from a project configuration alone.
+// Returns the source analysis, or an error if one occurs.
+func AnalyzeCorpusSourceCoverage(projectConfig config.ProjectConfig) (*coverage.SourceAnalysis, error) {
+	// We can only analyze a corpus stored on disk.
+	if projectConfig.Fuzzing.CorpusDirectory == "" {
+		return nil, errors.New("a corpus directory must be set to analyze coverage from")
+	}
+
+	// Create a fuzzer to compile our targets and set up our test chain, then replay the corpus to measure its coverage.
+	fuzzer, err := NewFuzzer(projectConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err = fuzzer.replayCorpus(); err != nil {
+		return nil, err
+	}
+	return coverage.AnalyzeSourceCoverageWithSetup(fuzzer.compilations, fuzzer.corpus.CoverageMaps(), fuzzer.corpus.FuzzingCoverageMaps(), projectConfig.Fuzzing.ExcludeSetupCoverage)
+}
+
+// replayCorpus reads the corpus from the corpus directory and replays it on a test chain set up with the
+// deployment/setup strategy defined by the fuzzer, without fuzzing, so the coverage it achieves is measured. No fuzzer
+// events are published, so test case providers are never invoked.
+// Returns an error if one occurs.
+func (f *Fuzzer) replayCorpus() error {
+	// Create our running contexts, which are cancelled once we are done.
+	f.ctx, f.ctxCancelFunc = context.WithCancel(context.Background())
+	f.emergencyCtx, f.emergencyCtxCancelFunc = context.WithCancel(context.Background())
+	defer f.ctxCancelFunc()
+	defer f.emergencyCtxCancelFunc()
+
+	// Read the corpus
+	var err error
+	f.corpus, err = corpus.NewCorpus(f.config.Fuzzing.CorpusDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to read the corpus: %v", err)
+	}
+	if totalCallSequences, testResults := f.corpus.CallSequenceEntryCount(); totalCallSequences == 0 && testResults == 0 {
+		f.logger.Warn("The corpus at ", colors.Bold, f.config.Fuzzing.CorpusDirectory, colors.Reset, " contains no call sequences, coverage will only include deployment and setup coverage")
+	}
+
+	// Create our test chain and set it up with our deployment/setup strategy defined by the fuzzer.
+	baseTestChain, err := f.createTestChain()
+	if err != nil {
+		return fmt.Errorf("failed to create the test chain: %v", err)
+	}
+	defer baseTestChain.Close()
+	f.logger.Info("Setting up test chain")
+	trace, err := f.Hooks.ChainSetupFunc(f, baseTestChain)
+	if err != nil {
+		if trace != nil {
+			return fmt.Errorf("failed to initialize the test chain: %v\n%s", err, trace.Log().String())
+		}
+		return fmt.Errorf("failed to initialize the test chain: %v", err)
+	}
+
+	// Replay the corpus to measure its coverage.
+	f.logger.Info("Running call sequences in the corpus")
+	startTime := time.Now()
+	corpusActiveSequences, corpusTotalSequences, err := f.corpus.Initialize(baseTestChain, f.contractDefinitions, fuzzerTypes.BytecodeMatchingMode(f.config.Fuzzing.Testing.ContractMatchingMode), f.config.Fuzzing.CorpusDropOutdatedCalls)
+	if err != nil {
+		return fmt.Errorf("failed to initialize the corpus: %v", err)
+	}
+	f.logger.Info("Finished running ", colors.Bold, corpusActiveSequences, "/", corpusTotalSequences, colors.Reset, " valid call sequences in the corpus in ", time.Since(startTime).Round(time.Second))
+	return nil
+}
@@ -0,0 +1,220 @@
+package fuzzing
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/corpus"
+	"github.com/crytic/medusa/logging/colors"
+)
+
+// deterministicWorkerState describes the state of a worker slot when fuzzing deterministically. It persists across
+// every FuzzerWorker created at the slot's index, and isolates them from the progress of workers at other indexes, so
+// the call sequences they test only depend on the seed, the corpus, and the configuration.
+type deterministicWorkerState struct {
+	// corpusPartition describes the view of the corpus which workers at this index mutate and add to.
+	corpusPartition *corpus.Partition
+
+	// testLimit describes the share of the configured test limit which workers at this index should test.
+	testLimit uint64
+
+	// failures describes the registry of failures discovered by workers at this index, used to avoid shrinking the
+	// same failure more than once.
+	failures *failureRegistry
+
+	// senderAccess tracks the outcomes of the calls each sender made to each method by workers at this index, or is
+	// nil if tracking access is disabled.
+	senderAccess *senderAccessTracker
+
+	// failedTestCases describes the identifiers of the test cases which workers at this index found to fail.
+	failedTestCases map[string]struct{}
+
+	// failedTestCasesLock provides thread synchronization for failedTestCases.
+	failedTestCasesLock sync.Mutex
+
+	// finished indicates whether workers at this index finished testing, and are waiting for the other workers.
+	finished bool
+}
+
+// initDeterministicWorkerStates partitions the corpus between the configured amount of workers, and creates the state
+// of each worker slot, splitting the configured test limit between them. This should be called once the corpus is
+// initialized, prior to spawning workers.
+func (f *Fuzzer) initDeterministicWorkerStates() {
+	workerCount := f.config.Fuzzing.Workers
+	testLimit := f.config.Fuzzing.TestLimit
+	partitions := f.corpus.NewPartitions(workerCount, f.config.Fuzzing.CorpusReplayLimit)
+
+	f.deterministicWorkerStates = make([]*deterministicWorkerState, workerCount)
+	f.deterministicWorkersFinished.Store(0)
+	for i := 0; i < workerCount; i++ {
+		workerTestLimit := testLimit / uint64(workerCount)
+		if uint64(i) < testLimit%uint64(workerCount) {
+			workerTestLimit++
+		}
+		f.deterministicWorkerStates[i] = &deterministicWorkerState{
+			corpusPartition: partitions[i],
+			testLimit:       workerTestLimit,
+			failures:        newFailureRegistry(),
+			failedTestCases: make(map[string]struct{}),
+		}
+		if threshold := f.config.Fuzzing.AccessControlRevertThreshold; threshold > 0 {
+			f.deterministicWorkerStates[i].senderAccess = newSenderAccessTracker(uint64(threshold))
+		}
+	}
+}
+
+// mergeDeterministicWorkerStates adds the call sequences discovered by every worker slot to the corpus, in the order
+// of the worker indexes. This should be called once every worker has exited.
+// Returns an error if one occurs.
+func (f *Fuzzer) mergeDeterministicWorkerStates() error {
+	partitions := make([]*corpus.Partition, len(f.deterministicWorkerStates))
+	for i, state := range f.deterministicWorkerStates {
+		partitions[i] = state.corpusPartition
+	}
+	addedCount, err := f.corpus.MergePartitions(partitions)
+	if addedCount > 0 {
+		f.logger.Info("Added ", colors.Bold, addedCount, colors.Reset, " call sequence(s) discovered by workers to the corpus")
+	}
+	return err
+}
+
+// deterministicDiscoveredCount returns the count of call sequences discovered by workers which are pending addition
+// to the corpus, as workers only add them to the corpus when fuzzing ends if fuzzing deterministically.
+func (f *Fuzzer) deterministicDiscoveredCount() int {
+	count := 0
+	for _, state := range f.deterministicWorkerStates {
+		count += state.corpusPartition.DiscoveredCount()
+	}
+	return count
+}
+
+// newCorpusStagingBuffer creates the corpus.StagingBuffer the worker adds call sequences to the corpus with. If
+// fuzzing deterministically, call sequences are added to the corpus partition of the worker's slot instead.
+func (fw *FuzzerWorker) newCorpusStagingBuffer() *corpus.StagingBuffer {
+	if fw.deterministicState != nil {
+		return fw.deterministicState.corpusPartition.NewStagingBuffer()
+	}
+	return fw.fuzzer.corpus.NewStagingBuffer()
+}
+
+// corpusMutationTargetSequence returns a weighted random call sequence from the corpus to mutate, or from the corpus
+// partition of the worker's slot if fuzzing deterministically.
+// Returns the call sequence, or an error if one occurs.
+func (fw *FuzzerWorker) corpusMutationTargetSequence() (calls.CallSequence, error) {
+	if fw.deterministicState != nil {
+		return fw.deterministicState.corpusPartition.RandomMutationTargetSequence(fw.randomProvider)
+	}
+	return fw.fuzzer.corpus.RandomMutationTargetSequence(fw.randomProvider)
+}
+
+// corpusActiveMutableSequenceCount returns the count of call sequences the worker can mutate, which are those in the
+// corpus, or those in the corpus partition of the worker's slot if fuzzing deterministically.
+func (fw *FuzzerWorker) corpusActiveMutableSequenceCount() int {
+	if fw.deterministicState != nil {
+		return fw.deterministicState.corpusPartition.ActiveMutableSequenceCount()
+	}
+	return fw.fuzzer.corpus.ActiveMutableSequenceCount()
+}
+
+// corpusUnexecutedCallSequence returns a call sequence loaded from the corpus which no worker executed yet, along
+// with the name of the corpus file it was loaded from. If fuzzing deterministically, only call sequences distributed
+// to the corpus partition of the worker's slot are returned.
+// Returns the call sequence and its file name, or nil and an empty string if there are none left to execute.
+func (fw *FuzzerWorker) corpusUnexecutedCallSequence() (*calls.CallSequence, string) {
+	if fw.deterministicState != nil {
+		return fw.deterministicState.corpusPartition.UnexecutedCallSequenceWithFileName()
+	}
+	return fw.fuzzer.corpus.UnexecutedCallSequenceWithFileName(fw.fuzzer.config.Fuzzing.CorpusReplayLimit)
+}
+
+// senderAccessTracker returns the tracker of the outcomes of the calls each sender made to each method, or that of the
+// worker's slot if fuzzing deterministically. Returns nil if tracking access is disabled.
+func (fw *FuzzerWorker) senderAccessTracker() *senderAccessTracker {
+	if fw.deterministicState != nil {
+		return fw.deterministicState.senderAccess
+	}
+	return fw.fuzzer.senderAccess
+}
+
+// testCaseFailed indicates whether the provided TestCase failed, and should no longer be tested by the worker. If
+// fuzzing deterministically, only failures found by workers at this worker's index are considered, so the worker
+// keeps testing failures found by other workers.
+func (fw *FuzzerWorker) testCaseFailed(testCase TestCase) bool {
+	state := fw.deterministicState
+	if state == nil {
+		return testCase.Status() == TestCaseStatusFailed
+	}
+	state.failedTestCasesLock.Lock()
+	defer state.failedTestCasesLock.Unlock()
+	_, failed := state.failedTestCases[testCase.ID()]
+	return failed
+}
+
+// reportTestCaseFinished reports the provided TestCase status as finalized by this worker to the Fuzzer. If fuzzing
+// deterministically and the TestCase failed, it is recorded as failed for workers at this worker's index.
+func (fw *FuzzerWorker) reportTestCaseFinished(testCase TestCase) {
+	if state := fw.deterministicState; state != nil && testCase.Status() == TestCaseStatusFailed {
+		state.failedTestCasesLock.Lock()
+		state.failedTestCases[testCase.ID()] = struct{}{}
+		state.failedTestCasesLock.Unlock()
+	}
+	fw.fuzzer.ReportTestCaseFinished(testCase)
+}
+
+// registerFailure records a discovery of the failure with the provided identifier by this worker.
+// Returns true if the failure was not previously discovered, and should be shrunk. If fuzzing deterministically, only
+// discoveries by workers at this worker's index are considered.
+func (fw *FuzzerWorker) registerFailure(failureID string) bool {
+	isNewFailure := fw.fuzzer.failures.register(failureID)
+	if fw.deterministicState != nil {
+		return fw.deterministicState.failures.register(failureID)
+	}
+	return isNewFailure
+}
+
+// unregisterFailure releases the claim this worker made on the failure with the provided identifier when registering
+// it, as it was not reported, so it is shrunk again once rediscovered.
+func (fw *FuzzerWorker) unregisterFailure(failureID string) {
+	fw.fuzzer.failures.unregister(failureID)
+	if fw.deterministicState != nil {
+		fw.deterministicState.failures.unregister(failureID)
+	}
+}
+
+// deterministicTestingFinished indicates whether the worker finished testing when fuzzing deterministically, as
+// workers at its index tested their share of the test limit, or found a failure while configured to stop on failed
+// tests. Once every worker slot finished testing, the Fuzzer is stopped.
+// Returns false if the worker should test further call sequences, or fuzzing is not deterministic.
+func (fw *FuzzerWorker) deterministicTestingFinished() bool {
+	state := fw.deterministicState
+	if state == nil {
+		return false
+	}
+	if state.finished {
+		return true
+	}
+
+	// Determine if we tested our share of the test limit. Calls replayed from the corpus are excluded if configured.
+	callsCounted := fw.workerMetrics().callsTested
+	if !fw.fuzzer.config.Fuzzing.CorpusReplayCountsTowardTestLimit {
+		callsCounted = new(big.Int).Sub(callsCounted, fw.workerMetrics().callsReplayed)
+	}
+	testLimitReached := !callsCounted.IsUint64() || callsCounted.Uint64() >= state.testLimit
+
+	// Determine if we found a failure which should stop us.
+	state.failedTestCasesLock.Lock()
+	stoppedOnFailure := fw.fuzzer.config.Fuzzing.Testing.StopOnFailedTest && len(state.failedTestCases) > 0
+	state.failedTestCasesLock.Unlock()
+	if !testLimitReached && !stoppedOnFailure {
+		return false
+	}
+
+	// Mark this worker slot finished, stopping the fuzzer if it was the last one.
+	state.finished = true
+	if int(fw.fuzzer.deterministicWorkersFinished.Add(1)) == len(fw.fuzzer.deterministicWorkerStates) {
+		fw.fuzzer.logger.Info("Every worker finished testing, halting now...")
+		fw.fuzzer.Stop()
+	}
+	return true
+}
@@ -0,0 +1,227 @@
+package fuzzing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DryRunReport describes what a fuzzing campaign would fuzz, as resolved by Fuzzer.DryRun after compiling the project
+// and setting up its deployments, without fuzzing.
+type DryRunReport struct {
+	// Contracts describes the contracts deployed on the chain a worker would fuzz, and the methods it would call on
+	// each of them.
+	Contracts []DryRunContract `json:"contracts"`
+
+	// Tests describes the test cases registered for the campaign.
+	Tests []DryRunTest `json:"tests"`
+
+	// TestDiscovery describes the tests discovered in each contract under test, by test type.
+	TestDiscovery []ContractTestDiscovery `json:"testDiscovery"`
+
+	// Accounts describes the accounts used to deploy contracts and send calls, and their funding.
+	Accounts []DryRunAccount `json:"accounts"`
+
+	// Warnings describes problems with the campaign which would cause it to fuzz less than expected, or fail to start.
+	Warnings []string `json:"warnings"`
+}
+
+// DryRunContract describes a contract deployed on the chain a worker would fuzz.
+type DryRunContract struct {
+	// Name describes the name of the contract definition the deployment was matched to.
+	Name string `json:"name"`
+
+	// Address describes the address the contract was deployed to.
+	Address common.Address `json:"address"`
+
+	// StateChangingMethods describes the signatures of the state-changing methods a worker would call on the contract.
+	StateChangingMethods []string `json:"stateChangingMethods"`
+
+	// ViewMethods describes the signatures of the view and pure methods a worker would call on the contract.
+	ViewMethods []string `json:"viewMethods"`
+}
+
+// DryRunTest describes a test case registered for a campaign.
+type DryRunTest struct {
+	// ID describes the unique identifier of the test case.
+	ID string `json:"id"`
+
+	// Name describes the human-readable name of the test case.
+	Name string `json:"name"`
+}
+
+// DryRunAccount describes an account used in a campaign.
+type DryRunAccount struct {
+	// Role describes what the account is used for, either "sender", "deployer", or "deployer of <contract>".
+	Role string `json:"role"`
+
+	// Address describes the address of the account.
+	Address common.Address `json:"address"`
+
+	// Balance describes the balance of the account in wei once deployments have been set up, as a base-10 string.
+	Balance string `json:"balance"`
+}
+
+// DryRun sets up the campaign for the compiled targets exactly as Start would, up to the point where workers are
+// spawned, and sets up the chain of a single worker. It then reports what the worker would fuzz, without fuzzing.
+// Problems which would prevent Start from fuzzing, such as malformed test methods, are reported as warnings rather
+// than errors, so every problem is reported at once.
+// Returns the report, or an error if the campaign could not be set up.
+func (f *Fuzzer) DryRun() (*DryRunReport, error) {
+	// Set up our campaign, up to the point workers are spawned.
+	baseTestChain, err := f.setUpCampaign(true)
+	if err != nil {
+		return nil, err
+	}
+	defer baseTestChain.Close()
+	defer f.ctxCancelFunc()
+	defer f.emergencyCtxCancelFunc()
+
+	// Create a worker and set up its chain, as it would be before it begins fuzzing.
+	worker, err := newFuzzerWorker(f, 0, f.randomProvider)
+	if err != nil {
+		return nil, err
+	}
+	err = f.Events.WorkerCreated.Publish(FuzzerWorkerCreatedEvent{Worker: worker})
+	if err != nil {
+		return nil, fmt.Errorf("error returned by an event handler when a worker created event was emitted: %w", err)
+	}
+	err = worker.setUpChain(baseTestChain)
+	if err != nil {
+		return nil, err
+	}
+	defer worker.chain.Close()
+
+	// Build our report from the state of the worker.
+	report := &DryRunReport{
+		Contracts: make([]DryRunContract, 0),
+		Tests:     make([]DryRunTest, 0),
+		Accounts:  make([]DryRunAccount, 0),
+		Warnings:  make([]string, 0),
+	}
+	for address, contract := range worker.DeployedContracts() {
+		report.Contracts = append(report.Contracts, DryRunContract{
+			Name:                 contract.Name(),
+			Address:              address,
+			StateChangingMethods: dryRunMethodSignatures(worker.stateChangingMethods, address),
+			ViewMethods:          dryRunMethodSignatures(worker.pureMethods, address),
+		})
+	}
+	slices.SortFunc(report.Contracts, func(a, b DryRunContract) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return a.Address.Cmp(b.Address)
+	})
+	for _, testCase := range f.TestCases() {
+		report.Tests = append(report.Tests, DryRunTest{ID: testCase.ID(), Name: testCase.Name()})
+	}
+	report.TestDiscovery = f.testDiscovery()
+
+	// Report our accounts and their funding.
+	addAccount := func(role string, address common.Address) {
+		balance := worker.chain.State().GetBalance(address).ToBig()
+		report.Accounts = append(report.Accounts, DryRunAccount{Role: role, Address: address, Balance: balance.String()})
+	}
+	for _, sender := range f.senders {
+		addAccount("sender", sender)
+	}
+	addAccount("deployer", f.deployer)
+	contractNames := make([]string, 0, len(f.contractDeployers))
+	for contractName := range f.contractDeployers {
+		contractNames = append(contractNames, contractName)
+	}
+	slices.Sort(contractNames)
+	for _, contractName := range contractNames {
+		addAccount("deployer of "+contractName, f.contractDeployers[contractName])
+	}
+
+	// Report any problems with the campaign.
+	for _, violation := range f.malformedTestMethods() {
+		report.Warnings = append(report.Warnings, "method named like a test will not be run as one: "+violation)
+	}
+	if unmatchedDeployments := f.unmatchedDeployments.count(); unmatchedDeployments > 0 {
+		hashes := make([]string, 0)
+		for _, initBytecodeHash := range f.unmatchedDeployments.hashes() {
+			hashes = append(hashes, initBytecodeHash.Hex())
+		}
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d contract deployment(s) could not be matched to a contract definition, with init bytecode hashes: %s", unmatchedDeployments, strings.Join(hashes, ", ")))
+	}
+	if len(worker.stateChangingMethods) == 0 {
+		report.Warnings = append(report.Warnings, "no state-changing methods would be fuzzed, check the target and excluded contracts")
+	}
+	if err = f.checkTestsFound(); err != nil {
+		report.Warnings = append(report.Warnings, err.Error())
+	} else if f.config.Fuzzing.Testing.Enabled && testDiscoveryTotal(report.TestDiscovery, f.config.Fuzzing.Testing) == 0 {
+		report.Warnings = append(report.Warnings, noTestsDiscoveredWarning)
+	}
+
+	// Publish an event indicating we destroyed the worker.
+	err = f.Events.WorkerDestroyed.Publish(FuzzerWorkerDestroyedEvent{Worker: worker})
+	if err != nil {
+		return nil, fmt.Errorf("error returned by an event handler when a worker destroyed event was emitted: %w", err)
+	}
+	return report, nil
+}
+
+// dryRunMethodSignatures returns the signatures of the methods in the provided list which are deployed at the given
+// address, sorted so reports are stable across runs.
+func dryRunMethodSignatures(methods []fuzzerTypes.DeployedContractMethod, address common.Address) []string {
+	signatures := make([]string, 0)
+	for _, method := range methods {
+		if method.Address == address {
+			signatures = append(signatures, method.Method.Sig)
+		}
+	}
+	slices.Sort(signatures)
+	return signatures
+}
+
+// Log returns a logging.LogBuffer describing the report, to be displayed to the user.
+func (r *DryRunReport) Log() *logging.LogBuffer {
+	buffer := logging.NewLogBuffer()
+	buffer.Append(colors.Bold, "Contracts:", colors.Reset, "\n")
+	for _, contract := range r.Contracts {
+		buffer.Append(colors.Bold, contract.Name, colors.Reset, fmt.Sprintf(" (%s):\n", contract.Address.String()))
+		for _, method := range contract.StateChangingMethods {
+			buffer.Append("\t", method, "\n")
+		}
+		for _, method := range contract.ViewMethods {
+			buffer.Append("\t", method, " (view)\n")
+		}
+	}
+	buffer.Append(colors.Bold, "Tests:", colors.Reset, "\n")
+	for _, test := range r.Tests {
+		buffer.Append("\t", test.Name, "\n")
+	}
+	buffer.Append(colors.Bold, "Discovered tests:", colors.Reset)
+	appendTestDiscovery(buffer, r.TestDiscovery)
+	buffer.Append("\n", colors.Bold, "Accounts:", colors.Reset, "\n")
+	for _, account := range r.Accounts {
+		buffer.Append(fmt.Sprintf("\t%s (%s): %s wei\n", account.Address.String(), account.Role, account.Balance))
+	}
+	if len(r.Warnings) > 0 {
+		buffer.Append(colors.Bold, "Warnings:", colors.Reset, "\n")
+		for _, warning := range r.Warnings {
+			buffer.Append("\t", warning, "\n")
+		}
+	}
+	return buffer
+}
+
+// WriteToFile writes the report to the provided file path as JSON.
+// Returns an error if one occurred.
+func (r *DryRunReport) WriteToFile(path string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
@@ -0,0 +1,106 @@
+package fuzzing
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFuzzerDryRun runs a dry run against a prebuilt Hardhat project and ensures the JSON report it writes lists the
+// deployed contracts, the methods which would be fuzzed on them, the registered tests, and the funded accounts,
+// without fuzzing.
+func TestFuzzerDryRun(t *testing.T) {
+	runDryRunTest(t, func(projectConfig *config.ProjectConfig) {}, func(report map[string]any, f *fuzzerTestContext) {
+		// Both contracts should be listed, with their state-changing methods.
+		contracts := report["contracts"].([]any)
+		assert.Len(t, contracts, 2)
+		for i, name := range []string{"FirstContract", "SecondContract"} {
+			contract := contracts[i].(map[string]any)
+			assert.EqualValues(t, name, contract["name"])
+			assert.NotEmpty(t, contract["address"])
+			assert.EqualValues(t, []any{"value()"}, contract["stateChangingMethods"])
+			assert.EqualValues(t, []any{}, contract["viewMethods"])
+		}
+
+		// Each test registered with the fuzzer should be listed.
+		tests := report["tests"].([]any)
+		assert.NotEmpty(t, tests)
+		assert.Len(t, tests, len(f.fuzzer.TestCases()))
+		for i, testCase := range f.fuzzer.TestCases() {
+			assert.EqualValues(t, testCase.ID(), tests[i].(map[string]any)["id"])
+		}
+
+		// The tests discovered in each contract should be listed by type.
+		testDiscovery := report["testDiscovery"].([]any)
+		assert.Len(t, testDiscovery, 2)
+		for i, name := range []string{"FirstContract", "SecondContract"} {
+			contract := testDiscovery[i].(map[string]any)
+			assert.EqualValues(t, name, contract["contract"])
+			assert.EqualValues(t, []any{"value()"}, contract["stateChangingAssertionTests"])
+			assert.EqualValues(t, []any{}, contract["propertyTests"])
+		}
+
+		// Every sender and the deployer should be listed, and funded.
+		accounts := report["accounts"].([]any)
+		assert.Len(t, accounts, len(f.fuzzer.senders)+1)
+		for _, account := range accounts {
+			assert.NotEqualValues(t, "0", account.(map[string]any)["balance"])
+		}
+		assert.EqualValues(t, []any{}, report["warnings"])
+
+		// No calls should have been fuzzed.
+		assert.Zero(t, f.fuzzer.metrics.CallsTested().Uint64())
+	})
+}
+
+// TestFuzzerDryRunWarnings runs a dry run against a prebuilt Hardhat project with every contract excluded from
+// fuzzing, and ensures the report warns that nothing would be fuzzed, rather than failing.
+func TestFuzzerDryRunWarnings(t *testing.T) {
+	runDryRunTest(t, func(projectConfig *config.ProjectConfig) {
+		projectConfig.Fuzzing.Testing.ExcludeContracts = []string{"FirstContract", "SecondContract"}
+		projectConfig.Fuzzing.Testing.StopOnNoTests = true
+	}, func(report map[string]any, f *fuzzerTestContext) {
+		// The contracts should still be listed as deployed, but without any methods to fuzz.
+		contracts := report["contracts"].([]any)
+		assert.Len(t, contracts, 2)
+		for _, contract := range contracts {
+			assert.EqualValues(t, []any{}, contract.(map[string]any)["stateChangingMethods"])
+		}
+
+		// We should be warned that no methods or tests would be fuzzed.
+		warnings := report["warnings"].([]any)
+		assert.Len(t, warnings, 2)
+		assert.Contains(t, warnings[0], "no state-changing methods would be fuzzed")
+		assert.Contains(t, warnings[1], "no assertion, property, optimization, or custom tests were found")
+	})
+}
+
+// runDryRunTest runs a dry run against a prebuilt Hardhat project, using the project configuration updated by the
+// provided function, then writes its report and provides it to the provided method, parsed from JSON.
+func runDryRunTest(t *testing.T, configUpdates func(projectConfig *config.ProjectConfig), method func(report map[string]any, f *fuzzerTestContext)) {
+	runHardhatProjectTest(t, func(projectConfig *config.ProjectConfig) {
+		// Create our project configuration
+		projectConfig.Fuzzing.CorpusDirectory = "corpus"
+		configUpdates(projectConfig)
+
+		executeFuzzerTestMethodInternal(t, projectConfig, func(f *fuzzerTestContext) {
+			// Run the dry run and write its report
+			report, err := f.fuzzer.DryRun()
+			assert.NoError(t, err)
+			assert.NoError(t, report.WriteToFile("dry_run.json"))
+
+			// The corpus should not have been written.
+			assert.NoDirExists(t, "corpus")
+
+			// Parse the report as a generic JSON object, so we test the fields it is written with.
+			b, err := os.ReadFile("dry_run.json")
+			assert.NoError(t, err)
+			var parsedReport map[string]any
+			assert.NoError(t, json.Unmarshal(b, &parsedReport))
+			method(parsedReport, f)
+		})
+	})
+}
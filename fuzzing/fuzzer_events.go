@@ -2,6 +2,7 @@ package fuzzing
 
 import (
 	"github.com/crytic/medusa/events"
+	"github.com/crytic/medusa/fuzzing/calls"
 )
 
 // FuzzerEvents defines event emitters for a Fuzzer.
@@ -19,6 +20,58 @@ type FuzzerEvents struct {
 	// WorkerDestroyed emits events when the Fuzzer destroys an existing FuzzerWorker during the fuzzing
 	// campaign. This can occur even if a fuzzing campaign is not stopping, if a worker has reached resource limits.
 	WorkerDestroyed events.EventEmitter[FuzzerWorkerDestroyedEvent]
+
+	// TestFailureResolved emits events when a FuzzerWorker finished shrinking the call sequence which caused a test
+	// failure, and reported the result. If failure deduplication is enabled, this is emitted once per failure. Events
+	// are published on the goroutine of the FuzzerWorker which shrunk the call sequence, so handlers must be
+	// thread-safe.
+	TestFailureResolved events.EventEmitter[FuzzerTestFailureResolvedEvent]
+
+	// The following emitters aggregate the events emitted by every FuzzerWorker the Fuzzer creates, including workers
+	// created to replace those which reached resource limits. Each event is published on the goroutine of the
+	// FuzzerWorker which emitted it, before it is delivered to handlers subscribed to the worker's own
+	// FuzzerWorkerEvents (e.g. when handling WorkerCreated). Events emitted by the same worker are published in order,
+	// but events emitted by different workers may interleave, so handlers must be thread-safe. Handlers should be
+	// subscribed before the Fuzzer is started.
+
+	// WorkerContractAdded emits the FuzzerWorkerEvents.ContractAdded events of every FuzzerWorker.
+	WorkerContractAdded events.EventEmitter[FuzzerWorkerContractAddedEvent]
+
+	// WorkerContractDeleted emits the FuzzerWorkerEvents.ContractDeleted events of every FuzzerWorker.
+	WorkerContractDeleted events.EventEmitter[FuzzerWorkerContractDeletedEvent]
+
+	// WorkerChainCreated emits the FuzzerWorkerEvents.FuzzerWorkerChainCreated events of every FuzzerWorker.
+	WorkerChainCreated events.EventEmitter[FuzzerWorkerChainCreatedEvent]
+
+	// WorkerChainSetup emits the FuzzerWorkerEvents.FuzzerWorkerChainSetup events of every FuzzerWorker.
+	WorkerChainSetup events.EventEmitter[FuzzerWorkerChainSetupEvent]
+
+	// WorkerCallSequenceTesting emits the FuzzerWorkerEvents.CallSequenceTesting events of every FuzzerWorker.
+	WorkerCallSequenceTesting events.EventEmitter[FuzzerWorkerCallSequenceTestingEvent]
+
+	// WorkerCallSequenceTested emits the FuzzerWorkerEvents.CallSequenceTested events of every FuzzerWorker.
+	WorkerCallSequenceTested events.EventEmitter[FuzzerWorkerCallSequenceTestedEvent]
+
+	// WorkerNewCoverage emits the FuzzerWorkerEvents.NewCoverage events of every FuzzerWorker.
+	WorkerNewCoverage events.EventEmitter[FuzzerWorkerNewCoverageEvent]
+
+	// WorkerTestingComplete emits the FuzzerWorkerEvents.TestingComplete events of every FuzzerWorker.
+	WorkerTestingComplete events.EventEmitter[FuzzerWorkerTestingCompleteEvent]
+}
+
+// forwardWorkerEvents subscribes the aggregate worker event emitters in FuzzerEvents to the events of the provided
+// FuzzerWorker, so that its events are published to their subscribers. Global event handlers are not called again for
+// forwarded events, as they were already called when the worker published them. This is called for every
+// FuzzerWorker the Fuzzer creates, before any of its events are emitted.
+func (e *FuzzerEvents) forwardWorkerEvents(worker *FuzzerWorker) {
+	worker.Events.ContractAdded.Subscribe(e.WorkerContractAdded.PublishToSubscribers)
+	worker.Events.ContractDeleted.Subscribe(e.WorkerContractDeleted.PublishToSubscribers)
+	worker.Events.FuzzerWorkerChainCreated.Subscribe(e.WorkerChainCreated.PublishToSubscribers)
+	worker.Events.FuzzerWorkerChainSetup.Subscribe(e.WorkerChainSetup.PublishToSubscribers)
+	worker.Events.CallSequenceTesting.Subscribe(e.WorkerCallSequenceTesting.PublishToSubscribers)
+	worker.Events.CallSequenceTested.Subscribe(e.WorkerCallSequenceTested.PublishToSubscribers)
+	worker.Events.NewCoverage.Subscribe(e.WorkerNewCoverage.PublishToSubscribers)
+	worker.Events.TestingComplete.Subscribe(e.WorkerTestingComplete.PublishToSubscribers)
 }
 
 // FuzzerStartingEvent describes an event where a fuzzing.Fuzzer has initialized all state variables and is about to
@@ -48,3 +101,32 @@ type FuzzerWorkerDestroyedEvent struct {
 	// Worker represents the instance of the fuzzing.FuzzerWorker for which the event occurred.
 	Worker *FuzzerWorker
 }
+
+// FuzzerTestFailureResolvedEvent describes an event where a fuzzing.FuzzerWorker finished shrinking the call sequence
+// which caused a test failure, and the test case provider which requested it reported the result.
+type FuzzerTestFailureResolvedEvent struct {
+	// Worker represents the instance of the fuzzing.FuzzerWorker which shrunk the call sequence.
+	Worker *FuzzerWorker
+
+	// TestName describes the name of the test case which failed.
+	TestName string
+
+	// FailureID identifies the failure, as used to deduplicate failures discovered by multiple workers.
+	FailureID string
+
+	// CallSequence describes the final, shrunken call sequence which causes the failure.
+	CallSequence calls.CallSequence
+
+	// ShrinkLimitReached indicates whether shrinking ended because the shrink limit was reached before every pass of
+	// shrinking completed, rather than because the call sequence could not be shrunk further. If so, the call sequence
+	// may not be minimal.
+	ShrinkLimitReached bool
+
+	// ShrinkingInterrupted indicates whether shrinking ended because fuzzing was stopped before every pass of
+	// shrinking completed. If so, the call sequence may not be minimal.
+	ShrinkingInterrupted bool
+
+	// ReproducerPath describes the path of the corpus file which records the call sequence, so it can be replayed.
+	// This is empty if the call sequence was not written to disk (e.g. no corpus directory was configured).
+	ReproducerPath string
+}
@@ -0,0 +1,68 @@
+package fuzzing
+
+import (
+	"testing"
+
+	"github.com/crytic/medusa/events"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFuzzerEventsForwardWorkerEvents tests that the events of every FuzzerWorker forwarded to FuzzerEvents,
+// including a worker replacing another in the same slot, are observed by a single fuzzer-level subscription, in the
+// order each worker emitted them, and before handlers subscribed to the worker's own events. Global event handlers
+// should only be called once for each event, rather than again when it is forwarded.
+func TestFuzzerEventsForwardWorkerEvents(t *testing.T) {
+	// Create our workers, including a replacement for the first, as if it were recycled.
+	fuzzer := &Fuzzer{}
+	workers := []*FuzzerWorker{{workerIndex: 0}, {workerIndex: 1}, {workerIndex: 0}}
+
+	// Count the events of our workers observed by a global event handler. Global event handlers remain subscribed
+	// for the lifetime of the program, so events of other workers are ignored.
+	globalObserved := make(map[*FuzzerWorker]int)
+	events.SubscribeAny(func(event FuzzerWorkerCallSequenceTestedEvent) error {
+		for _, worker := range workers {
+			if event.Worker == worker {
+				globalObserved[worker]++
+			}
+		}
+		return nil
+	})
+
+	// Subscribe to the aggregate events once, recording the worker and order of each event.
+	observed := make([]*FuzzerWorker, 0)
+	observedCoverage := make(map[*FuzzerWorker]int)
+	fuzzer.Events.WorkerCallSequenceTested.Subscribe(func(event FuzzerWorkerCallSequenceTestedEvent) error {
+		observed = append(observed, event.Worker)
+		return nil
+	})
+	fuzzer.Events.WorkerNewCoverage.Subscribe(func(event FuzzerWorkerNewCoverageEvent) error {
+		observedCoverage[event.Worker]++
+		return nil
+	})
+
+	for _, worker := range workers {
+		fuzzer.Events.forwardWorkerEvents(worker)
+
+		// Handlers subscribed to the worker itself should be invoked after the aggregate handlers.
+		worker := worker
+		worker.Events.CallSequenceTested.Subscribe(func(event FuzzerWorkerCallSequenceTestedEvent) error {
+			assert.Same(t, worker, observed[len(observed)-1])
+			return nil
+		})
+	}
+
+	// Emit events from each worker, verifying each is observed with the worker which emitted it.
+	for i, worker := range workers {
+		for j := 0; j <= i; j++ {
+			err := worker.Events.CallSequenceTested.Publish(FuzzerWorkerCallSequenceTestedEvent{Worker: worker})
+			assert.NoError(t, err)
+			err = worker.Events.NewCoverage.Publish(FuzzerWorkerNewCoverageEvent{Worker: worker})
+			assert.NoError(t, err)
+		}
+	}
+	assert.EqualValues(t, []*FuzzerWorker{workers[0], workers[1], workers[1], workers[2], workers[2], workers[2]}, observed)
+	for i, worker := range workers {
+		assert.EqualValues(t, i+1, observedCoverage[worker])
+		assert.EqualValues(t, i+1, globalObserved[worker])
+	}
+}
@@ -1,6 +1,7 @@
 package fuzzing
 
 import (
+	"math/big"
 	"math/rand"
 
 	"github.com/crytic/medusa/fuzzing/executiontracer"
@@ -30,6 +31,10 @@ type FuzzerHooks struct {
 	// CallSequenceTestFuncs describes a list of functions to be called upon by a FuzzerWorker after every call
 	// in a call sequence. These must not commit to state
 	CallSequenceTestFuncs []CallSequenceTestFunc
+
+	// SequenceCompletedTestFuncs describes a list of functions to be called upon by a FuzzerWorker once after a call
+	// sequence has finished executing, before chain state is reverted. These must not commit to state.
+	SequenceCompletedTestFuncs []SequenceCompletedTestFunc
 }
 
 // NewShrinkingValueMutatorFunc describes the function used to set up a value mutator used to shrink call
@@ -55,6 +60,14 @@ type TestChainSetupFunc func(fuzzer *Fuzzer, testChain *chain.TestChain) (*execu
 // current call sequence from being further generated and tested.
 type CallSequenceTestFunc func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error)
 
+// SequenceCompletedTestFunc defines a method called once after a fuzzing.FuzzerWorker has finished executing a
+// types.CallSequence during a fuzzing campaign, with the chain state left as it was after the final call. This suits
+// checks which only make sense at the end of a call sequence, rather than after every call. It is not called if the
+// sequence was cut short, e.g. by a CallSequenceTestFunc requesting it be shrunk. Like a CallSequenceTestFunc, it
+// returns a ShrinkCallSequenceRequest set. As each ShrinkCallSequenceRequest.VerifierFunction is called only once a
+// candidate shrunken sequence has finished executing, verifiers can simply repeat the same check.
+type SequenceCompletedTestFunc func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error)
+
 // ShrinkCallSequenceRequest is a structure signifying a request for a shrunken call sequence from the FuzzerWorker.
 type ShrinkCallSequenceRequest struct {
 	// TestName represents the name of the test case that is having a call sequence that is being shrunk.
@@ -65,10 +78,30 @@ type ShrinkCallSequenceRequest struct {
 	// VerifierFunction is a method is called upon by a FuzzerWorker to check if a shrunken call sequence satisfies
 	// the needs of an original method.
 	VerifierFunction func(worker *FuzzerWorker, callSequence calls.CallSequence) (bool, error)
+	// DeterministicVerifier indicates whether the result of VerifierFunction depends only on the execution path of the
+	// shrunken call sequence. If so, a shrunken call sequence whose calls produce the same coverage as those of the
+	// best shrunken call sequence found so far is accepted without invoking VerifierFunction, as it would be accepted
+	// again. This should only be set if VerifierFunction does not depend on values which do not affect coverage.
+	DeterministicVerifier bool
 	// FinishedCallback is a method called upon when the shrink request has concluded. It provides the finalized
 	// shrunken call sequence.
 	FinishedCallback func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error
 	// RecordResultInCorpus indicates whether the shrunken call sequence should be recorded in the corpus. If so, when
 	// the shrinking operation is completed, the sequence will be added to the corpus if it doesn't already exist.
 	RecordResultInCorpus bool
+	// CorpusWeightMultiplier describes a positive multiplier for the weight of the shrunken call sequence when it is
+	// recorded in the corpus. If set, the recorded call sequence is also used as a mutation target, with its weight
+	// scaled by this multiplier (e.g. so call sequences achieving higher optimization values are mutated more often).
+	// If nil, the call sequence is recorded only to be replayed.
+	CorpusWeightMultiplier *big.Int
+	// ReplayID identifies the tested call sequence the CallSequenceToShrink was derived from, so it can be regenerated
+	// with CallSequenceGenerator.ReconstructSequence. It is nil if the call sequence was replayed from the corpus.
+	ReplayID *ReplayID
+	// FailureID identifies the failure which caused the shrink request (e.g. the violated test, and where it was
+	// violated). If failure deduplication is enabled, a request is not honored if a request with the same FailureID
+	// was already honored, as the failure is already known. If empty, the request is always honored.
+	FailureID string
+	// TestProviderID identifies the TestProvider whose test function made the request. This is set when the test
+	// function was wrapped by a TestProvider, and the request is not honored while that TestProvider is muted.
+	TestProviderID string
 }
@@ -1,6 +1,20 @@
 package fuzzing
 
-import "math/big"
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
 
 // FuzzerMetrics represents a struct tracking metrics for a Fuzzer run.
 type FuzzerMetrics struct {
@@ -20,6 +34,12 @@ type fuzzerWorkerMetrics struct {
 	// callsTested is the amount of transactions/calls the fuzzer executed and ran tests against.
 	callsTested *big.Int
 
+	// sequencesReplayed is the amount of sequences tested which were replayed from the corpus, rather than generated.
+	sequencesReplayed *big.Int
+
+	// callsReplayed is the amount of calls tested which were replayed from the corpus, rather than generated.
+	callsReplayed *big.Int
+
 	// gasUsed is the amount of gas the fuzzer executed and ran tests against.
 	gasUsed *big.Int
 
@@ -28,6 +48,182 @@ type fuzzerWorkerMetrics struct {
 
 	// shrinking indicates whether the fuzzer worker is currently shrinking.
 	shrinking bool
+
+	// shrinkVerificationsSkipped is the amount of shrunken call sequences which were accepted without invoking the
+	// verifier of their shrink request, as they followed the same execution path as the best shrunken call sequence.
+	shrinkVerificationsSkipped *big.Int
+
+	// reverts tracks the amount of failed calls the fuzzer executed, by target method and revert classification.
+	reverts *revertMetrics
+
+	// sequenceDurations tracks the wall-clock time taken to execute and test call sequences.
+	sequenceDurations *sequenceDurationMetrics
+
+	// contracts tracks how thoroughly each contract was exercised.
+	contracts *contractMetrics
+}
+
+// RevertClassification describes the class of failure which caused a call to revert.
+type RevertClassification uint8
+
+const (
+	// RevertClassificationRevert describes a revert without any revert data (e.g. `revert()` or `require(cond)`).
+	RevertClassificationRevert RevertClassification = iota
+	// RevertClassificationRequire describes a revert with an Error(string) message (e.g. `require(cond, "msg")`).
+	RevertClassificationRequire
+	// RevertClassificationCustomError describes a revert with a custom Solidity error.
+	RevertClassificationCustomError
+	// RevertClassificationPanic describes a revert with a Panic(uint256) code.
+	RevertClassificationPanic
+	// RevertClassificationOutOfGas describes a call which ran out of gas.
+	RevertClassificationOutOfGas
+	// RevertClassificationInvalidOpcode describes a call which executed an invalid opcode.
+	RevertClassificationInvalidOpcode
+	// RevertClassificationOther describes a call which failed for any other reason.
+	RevertClassificationOther
+)
+
+// revertClassificationCount describes the amount of RevertClassification values.
+const revertClassificationCount = int(RevertClassificationOther) + 1
+
+// String returns a displayable string representing the RevertClassification.
+func (c RevertClassification) String() string {
+	switch c {
+	case RevertClassificationRevert:
+		return "revert"
+	case RevertClassificationRequire:
+		return "require"
+	case RevertClassificationCustomError:
+		return "custom error"
+	case RevertClassificationPanic:
+		return "panic"
+	case RevertClassificationOutOfGas:
+		return "out of gas"
+	case RevertClassificationInvalidOpcode:
+		return "invalid opcode"
+	default:
+		return "other"
+	}
+}
+
+// MarshalText encodes the RevertClassification as its displayable string.
+func (c RevertClassification) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText decodes the RevertClassification from its displayable string.
+func (c *RevertClassification) UnmarshalText(text []byte) error {
+	for classification := RevertClassification(0); int(classification) < revertClassificationCount; classification++ {
+		if classification.String() == string(text) {
+			*c = classification
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown revert classification '%s'", text)
+}
+
+var (
+	// revertErrorSelector describes the selector for Error(string) revert data.
+	revertErrorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+	// revertPanicSelector describes the selector for Panic(uint256) revert data.
+	revertPanicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// classifyRevert classifies the failure of a call given its VM error and return data, without allocating.
+// Returns the RevertClassification, and the panic code if it is RevertClassificationPanic.
+func classifyRevert(returnError error, returnData []byte) (RevertClassification, uint64) {
+	// Classify explicit reverts by their revert data.
+	if errors.Is(returnError, vm.ErrExecutionReverted) {
+		switch {
+		case len(returnData) == 0:
+			return RevertClassificationRevert, 0
+		case len(returnData) < 4:
+			return RevertClassificationOther, 0
+		case bytes.Equal(returnData[:4], revertErrorSelector):
+			return RevertClassificationRequire, 0
+		case len(returnData) == 4+32 && bytes.Equal(returnData[:4], revertPanicSelector):
+			return RevertClassificationPanic, binary.BigEndian.Uint64(returnData[4+24:])
+		default:
+			return RevertClassificationCustomError, 0
+		}
+	}
+
+	// Classify any other VM errors.
+	if utils.IsOutOfGasError(returnError) {
+		return RevertClassificationOutOfGas, 0
+	}
+	if _, ok := returnError.(*vm.ErrInvalidOpCode); ok {
+		return RevertClassificationInvalidOpcode, 0
+	}
+	return RevertClassificationOther, 0
+}
+
+// revertMetricsKey describes a unique combination of target method and revert classification tracked by
+// revertMetrics.
+type revertMetricsKey struct {
+	// contract describes the contract definition targeted by the failed call. This may be nil if it was unresolved.
+	contract *fuzzerTypes.Contract
+
+	// methodID describes the selector of the method targeted by the failed call.
+	methodID [4]byte
+
+	// classification describes the class of failure.
+	classification RevertClassification
+
+	// panicCode describes the panic code if the classification is RevertClassificationPanic.
+	panicCode uint64
+
+	// calldataProbe describes the kind of calldata probe which malformed the failed call's data, or an empty value if
+	// the call data was cleanly encoded.
+	calldataProbe calls.CalldataProbeKind
+}
+
+// revertMetrics tracks the amount of failed calls by target method and revert classification for a single
+// FuzzerWorker. It is safe for concurrent use.
+type revertMetrics struct {
+	// lock is used to synchronize access to counts, as metrics are read while the worker is executing.
+	lock sync.Mutex
+
+	// counts describes the amount of failed calls for each target method and revert classification.
+	counts map[revertMetricsKey]uint64
+}
+
+// add records a failed call to the provided contract and method, given its VM error and return data, and the
+// calldata probe which malformed its call data, if any.
+func (r *revertMetrics) add(contract *fuzzerTypes.Contract, callData []byte, calldataProbe *calls.CalldataProbe, returnError error, returnData []byte) {
+	key := revertMetricsKey{contract: contract}
+	if calldataProbe != nil {
+		key.calldataProbe = calldataProbe.Kind
+	}
+	copy(key.methodID[:], callData)
+	key.classification, key.panicCode = classifyRevert(returnError, returnData)
+
+	r.lock.Lock()
+	r.counts[key]++
+	r.lock.Unlock()
+}
+
+// RevertMetric describes the amount of failed calls for a given target method and revert classification.
+type RevertMetric struct {
+	// Contract describes the name of the contract targeted by the failed calls.
+	Contract string `json:"contract"`
+
+	// Method describes the signature of the method targeted by the failed calls.
+	Method string `json:"method"`
+
+	// Classification describes the class of failure.
+	Classification RevertClassification `json:"classification"`
+
+	// PanicCode describes the panic code if the Classification is RevertClassificationPanic.
+	PanicCode uint64 `json:"panicCode"`
+
+	// CalldataProbe describes the kind of calldata probe which malformed the failed calls' data, or an empty value if
+	// their call data was cleanly encoded.
+	CalldataProbe calls.CalldataProbeKind `json:"calldataProbe,omitempty"`
+
+	// Count describes the amount of failed calls.
+	Count uint64 `json:"count"`
 }
 
 // newFuzzerMetrics obtains a new FuzzerMetrics struct for a given number of workers specified by workerCount.
@@ -41,8 +237,14 @@ func newFuzzerMetrics(workerCount int) *FuzzerMetrics {
 		metrics.workerMetrics[i].sequencesTested = big.NewInt(0)
 		metrics.workerMetrics[i].failedSequences = big.NewInt(0)
 		metrics.workerMetrics[i].callsTested = big.NewInt(0)
+		metrics.workerMetrics[i].sequencesReplayed = big.NewInt(0)
+		metrics.workerMetrics[i].callsReplayed = big.NewInt(0)
 		metrics.workerMetrics[i].workerStartupCount = big.NewInt(0)
 		metrics.workerMetrics[i].gasUsed = big.NewInt(0)
+		metrics.workerMetrics[i].shrinkVerificationsSkipped = big.NewInt(0)
+		metrics.workerMetrics[i].reverts = &revertMetrics{counts: make(map[revertMetricsKey]uint64)}
+		metrics.workerMetrics[i].sequenceDurations = &sequenceDurationMetrics{slowest: make([]SlowSequence, 0)}
+		metrics.workerMetrics[i].contracts = newContractMetrics()
 	}
 	return &metrics
 }
@@ -74,6 +276,32 @@ func (m *FuzzerMetrics) CallsTested() *big.Int {
 	return transactionsTested
 }
 
+// SequencesReplayed returns the amount of sequences of transactions the fuzzer replayed from the corpus and ran tests
+// against. This is a subset of SequencesTested.
+func (m *FuzzerMetrics) SequencesReplayed() *big.Int {
+	sequencesReplayed := big.NewInt(0)
+	for _, workerMetrics := range m.workerMetrics {
+		sequencesReplayed.Add(sequencesReplayed, workerMetrics.sequencesReplayed)
+	}
+	return sequencesReplayed
+}
+
+// CallsReplayed returns the amount of transactions/calls the fuzzer replayed from the corpus and ran tests against.
+// This is a subset of CallsTested.
+func (m *FuzzerMetrics) CallsReplayed() *big.Int {
+	callsReplayed := big.NewInt(0)
+	for _, workerMetrics := range m.workerMetrics {
+		callsReplayed.Add(callsReplayed, workerMetrics.callsReplayed)
+	}
+	return callsReplayed
+}
+
+// CallsGenerated returns the amount of transactions/calls the fuzzer generated (rather than replayed from the corpus)
+// and ran tests against.
+func (m *FuzzerMetrics) CallsGenerated() *big.Int {
+	return new(big.Int).Sub(m.CallsTested(), m.CallsReplayed())
+}
+
 func (m *FuzzerMetrics) GasUsed() *big.Int {
 	gasUsed := big.NewInt(0)
 	for _, workerMetrics := range m.workerMetrics {
@@ -92,6 +320,16 @@ func (m *FuzzerMetrics) WorkerStartupCount() *big.Int {
 	return workerStartupCount
 }
 
+// ShrinkVerificationsSkipped returns the amount of shrunken call sequences which were accepted without invoking the
+// verifier of their deterministic shrink request, across all workers.
+func (m *FuzzerMetrics) ShrinkVerificationsSkipped() *big.Int {
+	shrinkVerificationsSkipped := big.NewInt(0)
+	for _, workerMetrics := range m.workerMetrics {
+		shrinkVerificationsSkipped.Add(shrinkVerificationsSkipped, workerMetrics.shrinkVerificationsSkipped)
+	}
+	return shrinkVerificationsSkipped
+}
+
 // WorkersShrinkingCount returns the amount of workers currently performing shrinking operations.
 func (m *FuzzerMetrics) WorkersShrinkingCount() uint64 {
 	shrinkingCount := uint64(0)
@@ -102,3 +340,109 @@ func (m *FuzzerMetrics) WorkersShrinkingCount() uint64 {
 	}
 	return shrinkingCount
 }
+
+// RevertClassificationCounts returns the amount of failed calls the fuzzer executed for each RevertClassification,
+// across all workers.
+func (m *FuzzerMetrics) RevertClassificationCounts() [revertClassificationCount]uint64 {
+	var counts [revertClassificationCount]uint64
+	for _, workerMetrics := range m.workerMetrics {
+		workerMetrics.reverts.lock.Lock()
+		for key, count := range workerMetrics.reverts.counts {
+			counts[key.classification] += count
+		}
+		workerMetrics.reverts.lock.Unlock()
+	}
+	return counts
+}
+
+// RevertMetrics returns the amount of failed calls the fuzzer executed for each target method and revert
+// classification, across all workers. The results are sorted by descending count.
+func (m *FuzzerMetrics) RevertMetrics() []RevertMetric {
+	// Aggregate the counts from all workers.
+	counts := make(map[revertMetricsKey]uint64)
+	for _, workerMetrics := range m.workerMetrics {
+		workerMetrics.reverts.lock.Lock()
+		for key, count := range workerMetrics.reverts.counts {
+			counts[key] += count
+		}
+		workerMetrics.reverts.lock.Unlock()
+	}
+
+	// Resolve each key into a displayable metric. Different contract definitions may share a name, so we merge
+	// metrics which resolve to the same values.
+	resolvedCounts := make(map[RevertMetric]uint64)
+	for key, count := range counts {
+		metric := RevertMetric{
+			Contract:       "<unresolved contract>",
+			Method:         "<unresolved method>",
+			Classification: key.classification,
+			PanicCode:      key.panicCode,
+			CalldataProbe:  key.calldataProbe,
+		}
+		if key.contract != nil {
+			metric.Contract = key.contract.Name()
+			if method, err := key.contract.CompiledContract().Abi.MethodById(key.methodID[:]); err == nil {
+				metric.Method = method.Sig
+			}
+		}
+		resolvedCounts[metric] += count
+	}
+	metrics := make([]RevertMetric, 0, len(resolvedCounts))
+	for metric, count := range resolvedCounts {
+		metric.Count = count
+		metrics = append(metrics, metric)
+	}
+
+	// Sort by descending count, then by name.
+	sort.Slice(metrics, func(i, j int) bool {
+		if metrics[i].Count != metrics[j].Count {
+			return metrics[i].Count > metrics[j].Count
+		}
+		if metrics[i].Contract != metrics[j].Contract {
+			return metrics[i].Contract < metrics[j].Contract
+		}
+		if metrics[i].Method != metrics[j].Method {
+			return metrics[i].Method < metrics[j].Method
+		}
+		if metrics[i].Classification != metrics[j].Classification {
+			return metrics[i].Classification < metrics[j].Classification
+		}
+		if metrics[i].PanicCode != metrics[j].PanicCode {
+			return metrics[i].PanicCode < metrics[j].PanicCode
+		}
+		return metrics[i].CalldataProbe < metrics[j].CalldataProbe
+	})
+	return metrics
+}
+
+// AverageSequenceDuration returns the exponentially weighted average of the wall-clock time taken to execute and test
+// a call sequence, averaged across all workers which tested a call sequence. Recent call sequences are weighted most.
+func (m *FuzzerMetrics) AverageSequenceDuration() time.Duration {
+	total, workers := 0.0, 0
+	for _, workerMetrics := range m.workerMetrics {
+		workerMetrics.sequenceDurations.lock.Lock()
+		if workerMetrics.sequenceDurations.samples > 0 {
+			total += workerMetrics.sequenceDurations.average
+			workers++
+		}
+		workerMetrics.sequenceDurations.lock.Unlock()
+	}
+	if workers == 0 {
+		return 0
+	}
+	return time.Duration(total / float64(workers))
+}
+
+// SlowestSequences returns the call sequences which took the longest to execute and test across all workers, sorted
+// by descending duration.
+func (m *FuzzerMetrics) SlowestSequences() []SlowSequence {
+	slowest := make([]SlowSequence, 0)
+	for _, workerMetrics := range m.workerMetrics {
+		workerMetrics.sequenceDurations.lock.Lock()
+		for _, slowSequence := range workerMetrics.sequenceDurations.slowest {
+			slowest = insertSlowSequence(slowest, slowSequence)
+		}
+		workerMetrics.sequenceDurations.lock.Unlock()
+	}
+	return slowest
+}
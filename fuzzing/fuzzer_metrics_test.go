@@ -0,0 +1,99 @@
+package fuzzing
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClassifyRevert tests the classification of failed calls from their VM error and return data.
+func TestClassifyRevert(t *testing.T) {
+	tests := []struct {
+		// name describes the test case.
+		name string
+
+		// returnError describes the VM error of the failed call.
+		returnError error
+
+		// returnData describes the return data of the failed call.
+		returnData []byte
+
+		// expectedClassification describes the expected classification of the failure.
+		expectedClassification RevertClassification
+
+		// expectedPanicCode describes the expected panic code of the failure.
+		expectedPanicCode uint64
+	}{
+		{
+			name:                   "revert without data",
+			returnError:            vm.ErrExecutionReverted,
+			expectedClassification: RevertClassificationRevert,
+		},
+		{
+			name:                   "require with message",
+			returnError:            vm.ErrExecutionReverted,
+			returnData:             common.FromHex("0x08c379a0000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000036d73670000000000000000000000000000000000000000000000000000000000"),
+			expectedClassification: RevertClassificationRequire,
+		},
+		{
+			name:                   "custom error",
+			returnError:            vm.ErrExecutionReverted,
+			returnData:             common.FromHex("0xdeadbeef0000000000000000000000000000000000000000000000000000000000000001"),
+			expectedClassification: RevertClassificationCustomError,
+		},
+		{
+			name:                   "panic",
+			returnError:            vm.ErrExecutionReverted,
+			returnData:             common.FromHex("0x4e487b710000000000000000000000000000000000000000000000000000000000000011"),
+			expectedClassification: RevertClassificationPanic,
+			expectedPanicCode:      0x11,
+		},
+		{
+			name:                   "out of gas",
+			returnError:            vm.ErrOutOfGas,
+			expectedClassification: RevertClassificationOutOfGas,
+		},
+		{
+			name:                   "invalid opcode",
+			returnError:            &vm.ErrInvalidOpCode{},
+			expectedClassification: RevertClassificationInvalidOpcode,
+		},
+		{
+			name:                   "other",
+			returnError:            vm.ErrWriteProtection,
+			expectedClassification: RevertClassificationOther,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			classification, panicCode := classifyRevert(test.returnError, test.returnData)
+			assert.EqualValues(t, test.expectedClassification, classification)
+			assert.EqualValues(t, test.expectedPanicCode, panicCode)
+		})
+	}
+
+	// Classification should not allocate, as it is performed for every failed call.
+	allocations := testing.AllocsPerRun(100, func() {
+		for _, test := range tests {
+			classifyRevert(test.returnError, test.returnData)
+		}
+	})
+	assert.Zero(t, allocations)
+}
+
+// TestRevertClassificationText tests that each RevertClassification is encoded as its displayable string and decoded
+// back, so it can be read from the results file.
+func TestRevertClassificationText(t *testing.T) {
+	for classification := RevertClassification(0); int(classification) < revertClassificationCount; classification++ {
+		text, err := classification.MarshalText()
+		assert.NoError(t, err)
+		var decoded RevertClassification
+		assert.NoError(t, decoded.UnmarshalText(text))
+		assert.EqualValues(t, classification, decoded)
+	}
+	var decoded RevertClassification
+	assert.Error(t, decoded.UnmarshalText([]byte("unknown")))
+}
@@ -0,0 +1,169 @@
+package fuzzing
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/corpus"
+	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// CampaignResults describes the results of a fuzzing campaign. They are written as JSON to the results file when the
+// Fuzzer stops (see config.FuzzingConfig.ResultsPath), so they can be consumed by other tooling.
+type CampaignResults struct {
+	// Tests describes the result of each test case, sorted by ID.
+	Tests []TestCaseResult `json:"tests"`
+
+	// TestDiscovery describes the tests discovered in each contract under test, by test type.
+	TestDiscovery []ContractTestDiscovery `json:"testDiscovery"`
+
+	// RevertClassifications describes the amount of failed calls for each class of failure, across all methods.
+	RevertClassifications map[RevertClassification]uint64 `json:"revertClassifications"`
+
+	// Reverts describes the amount of failed calls for each target method and class of failure, sorted by descending
+	// count.
+	Reverts []RevertMetric `json:"reverts"`
+
+	// SequenceLengths describes the call sequence lengths at which new coverage and failures were discovered.
+	SequenceLengths SequenceLengthHistogram `json:"sequenceLengths"`
+
+	// AverageSequenceDuration describes the average wall-clock time taken to execute and test a call sequence.
+	AverageSequenceDuration time.Duration `json:"averageSequenceDuration"`
+
+	// SlowestSequences describes the call sequences which took the longest to execute and test, sorted by descending
+	// duration.
+	SlowestSequences []SlowSequence `json:"slowestSequences"`
+
+	// Contracts describes how thoroughly each contract was exercised, sorted by contract name.
+	Contracts []ContractMetric `json:"contracts"`
+
+	// Corpus describes the composition of the corpus, or nil if the corpus was not yet created.
+	Corpus *corpus.CorpusStats `json:"corpus,omitempty"`
+}
+
+// TestCaseResult describes the result of a single test case in CampaignResults.
+type TestCaseResult struct {
+	// ID describes the unique identifier of the test case.
+	ID string `json:"id"`
+
+	// Name describes the human-readable name of the test case.
+	Name string `json:"name"`
+
+	// Contract describes the name of the contract whose method is tested by the test case, or an empty string if the
+	// test case does not test a contract method.
+	Contract string `json:"contract,omitempty"`
+
+	// SourcePath describes the path of the source file which defines Contract, or an empty string if the test case
+	// does not test a contract method.
+	SourcePath string `json:"sourcePath,omitempty"`
+
+	// Signature describes the signature of the method tested by the test case, or an empty string if the test case
+	// does not test a contract method.
+	Signature string `json:"signature,omitempty"`
+
+	// Description describes the tested method by its NatSpec notice, or by its Signature if it was not documented.
+	// It is an empty string if the test case does not test a contract method.
+	Description string `json:"description,omitempty"`
+
+	// Status describes the status of the test case when the results were obtained.
+	Status TestCaseStatus `json:"status"`
+
+	// Message describes the printable result of the test case, including the call sequence which caused it to fail,
+	// if any.
+	Message string `json:"message"`
+}
+
+// methodTestCase describes a TestCase which tests a contract method, so its result can be described by the method.
+type methodTestCase interface {
+	// TargetMethod describes the contract and method tested by the test case.
+	TargetMethod() (*fuzzerTypes.Contract, abi.Method)
+}
+
+// newTestCaseResult creates a TestCaseResult describing the provided TestCase as it stands when called.
+func newTestCaseResult(testCase TestCase) TestCaseResult {
+	result := TestCaseResult{
+		ID:      testCase.ID(),
+		Name:    testCase.Name(),
+		Status:  testCase.Status(),
+		Message: testCase.Message(),
+	}
+	if methodTestCase, ok := testCase.(methodTestCase); ok {
+		contract, method := methodTestCase.TargetMethod()
+		result.Contract = contract.Name()
+		result.SourcePath = contract.SourcePath()
+		result.Signature = method.Sig
+		result.Description = contract.MethodDescription(method)
+	}
+	return result
+}
+
+// Results obtains the results of the fuzzing campaign as they stand when called.
+func (f *Fuzzer) Results() *CampaignResults {
+	results := &CampaignResults{
+		Tests:                   make([]TestCaseResult, 0),
+		TestDiscovery:           f.testDiscovery(),
+		RevertClassifications:   make(map[RevertClassification]uint64),
+		Reverts:                 f.metrics.RevertMetrics(),
+		SequenceLengths:         f.sequenceLengths.snapshot(),
+		AverageSequenceDuration: f.metrics.AverageSequenceDuration(),
+		SlowestSequences:        f.metrics.SlowestSequences(),
+		Contracts:               f.metrics.ContractMetrics(),
+	}
+
+	// Record the result of each test case.
+	f.testCasesLock.Lock()
+	for _, testCase := range f.testCases {
+		results.Tests = append(results.Tests, newTestCaseResult(testCase))
+	}
+	f.testCasesLock.Unlock()
+	sort.Slice(results.Tests, func(i, j int) bool {
+		return results.Tests[i].ID < results.Tests[j].ID
+	})
+
+	// Record the failed calls by their class of failure, omitting classes which never occurred.
+	for classification, count := range f.metrics.RevertClassificationCounts() {
+		if count > 0 {
+			results.RevertClassifications[RevertClassification(classification)] = count
+		}
+	}
+
+	// Record the composition of the corpus, if it was created.
+	if f.corpus != nil {
+		corpusStats := f.corpus.Stats()
+		results.Corpus = &corpusStats
+	}
+	return results
+}
+
+// writeResults writes the results of the fuzzing campaign as JSON to the provided file path, creating its parent
+// directory if needed.
+// Returns an error if one occurs.
+func (f *Fuzzer) writeResults(path string) error {
+	return writeJSONFile(path, f.Results())
+}
+
+// writeSARIF writes the test results of the fuzzing campaign as a SARIF log to the provided file path, creating its
+// parent directory if needed.
+// Returns an error if one occurs.
+func (f *Fuzzer) writeSARIF(path string) error {
+	return writeJSONFile(path, newSARIFLog(f.Results()))
+}
+
+// writeJSONFile writes the provided value as indented JSON to the provided file path, creating its parent directory
+// if needed.
+// Returns an error if one occurs.
+func writeJSONFile(path string, value any) error {
+	b, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = utils.MakeDirectory(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
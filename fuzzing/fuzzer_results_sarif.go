@@ -0,0 +1,145 @@
+package fuzzing
+
+// sarifVersion describes the version of the SARIF format SARIF logs are written in.
+const sarifVersion = "2.1.0"
+
+// sarifSchema describes the JSON schema of the SARIF format SARIF logs are written in.
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// SARIFLog describes the test results of a fuzzing campaign in the Static Analysis Results Interchange Format
+// (SARIF), so they can be displayed by tools which support it (e.g. code scanning dashboards). Only the subset of the
+// format needed to describe test results is supported.
+type SARIFLog struct {
+	// Version describes the version of the SARIF format the log is written in.
+	Version string `json:"version"`
+
+	// Schema describes the JSON schema of the SARIF format the log is written in.
+	Schema string `json:"$schema"`
+
+	// Runs describes each run of a tool described by the log. A fuzzing campaign is described by a single run.
+	Runs []SARIFRun `json:"runs"`
+}
+
+// SARIFRun describes a single run of a tool in a SARIFLog.
+type SARIFRun struct {
+	// Tool describes the tool which produced the results.
+	Tool SARIFTool `json:"tool"`
+
+	// Results describes each failed test case.
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the tool which produced the results of a SARIFRun.
+type SARIFTool struct {
+	// Driver describes the tool component which produced the results.
+	Driver SARIFToolDriver `json:"driver"`
+}
+
+// SARIFToolDriver describes the tool component which produced the results of a SARIFRun, and the rules it checked.
+type SARIFToolDriver struct {
+	// Name describes the name of the tool.
+	Name string `json:"name"`
+
+	// InformationURI describes where information about the tool can be found.
+	InformationURI string `json:"informationUri"`
+
+	// Rules describes each test case which was checked.
+	Rules []SARIFRule `json:"rules"`
+}
+
+// SARIFRule describes a test case in a SARIFLog.
+type SARIFRule struct {
+	// ID describes the unique identifier of the test case.
+	ID string `json:"id"`
+
+	// Name describes the human-readable name of the test case.
+	Name string `json:"name"`
+
+	// ShortDescription describes the test case by the NatSpec notice of its test method, if it was documented, or by
+	// its name otherwise.
+	ShortDescription SARIFMessage `json:"shortDescription"`
+}
+
+// SARIFResult describes a failed test case in a SARIFLog.
+type SARIFResult struct {
+	// RuleID describes the identifier of the SARIFRule describing the failed test case.
+	RuleID string `json:"ruleId"`
+
+	// Level describes the severity of the result.
+	Level string `json:"level"`
+
+	// Message describes the failure, including the call sequence which caused it.
+	Message SARIFMessage `json:"message"`
+
+	// Locations describes the source file which defines the failed test method, if the test case tests a contract
+	// method.
+	Locations []SARIFLocation `json:"locations,omitempty"`
+}
+
+// SARIFMessage describes a text message in a SARIFLog.
+type SARIFMessage struct {
+	// Text describes the plain text of the message.
+	Text string `json:"text"`
+}
+
+// SARIFLocation describes the location of a SARIFResult.
+type SARIFLocation struct {
+	// PhysicalLocation describes the file the result is located in.
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation describes the file a SARIFResult is located in.
+type SARIFPhysicalLocation struct {
+	// ArtifactLocation describes the path of the file.
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+}
+
+// SARIFArtifactLocation describes the path of a file in a SARIFLog.
+type SARIFArtifactLocation struct {
+	// URI describes the path of the file, relative to the working directory of the campaign if it was not absolute.
+	URI string `json:"uri"`
+}
+
+// newSARIFLog creates a SARIFLog describing the test results of the provided CampaignResults. Each test case is
+// described by a rule, and each failed test case by a result.
+func newSARIFLog(results *CampaignResults) *SARIFLog {
+	driver := SARIFToolDriver{
+		Name:           "medusa",
+		InformationURI: "https://github.com/crytic/medusa",
+		Rules:          make([]SARIFRule, 0, len(results.Tests)),
+	}
+	sarifResults := make([]SARIFResult, 0)
+	for _, testCase := range results.Tests {
+		// Describe the test case by its method's NatSpec, if it tests a method.
+		description := testCase.Name
+		if testCase.Description != "" && testCase.Description != testCase.Signature {
+			description = testCase.Description
+		}
+		driver.Rules = append(driver.Rules, SARIFRule{
+			ID:               testCase.ID,
+			Name:             testCase.Name,
+			ShortDescription: SARIFMessage{Text: description},
+		})
+		if testCase.Status != TestCaseStatusFailed {
+			continue
+		}
+
+		// Describe the failure, locating it at the source file of the tested method, if any.
+		result := SARIFResult{
+			RuleID:  testCase.ID,
+			Level:   "error",
+			Message: SARIFMessage{Text: testCase.Message},
+		}
+		if testCase.SourcePath != "" {
+			result.Locations = []SARIFLocation{{
+				PhysicalLocation: SARIFPhysicalLocation{ArtifactLocation: SARIFArtifactLocation{URI: testCase.SourcePath}},
+			}}
+		}
+		sarifResults = append(sarifResults, result)
+	}
+	return &SARIFLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []SARIFRun{{Tool: SARIFTool{Driver: driver}, Results: sarifResults}},
+	}
+}
@@ -0,0 +1,157 @@
+package fuzzing
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFuzzerResults runs a short campaign on our pre-compiled Hardhat project with a results file configured, and
+// ensures the results written describe each test case and the failed calls, matching the results of the Fuzzer.
+func TestFuzzerResults(t *testing.T) {
+	runHardhatProjectTest(t, func(projectConfig *config.ProjectConfig) {
+		projectConfig.Fuzzing.TestLimit = 500
+		projectConfig.Fuzzing.CallSequenceLength = 10
+		projectConfig.Fuzzing.ResultsPath = filepath.Join("results", "results.json")
+		projectConfig.Fuzzing.SARIFPath = filepath.Join("results", "results.sarif")
+		executeFuzzerTestMethodInternal(t, projectConfig, func(f *fuzzerTestContext) {
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The results file should have been written, creating its directory, and match the results of the Fuzzer.
+			b, err := os.ReadFile(projectConfig.Fuzzing.ResultsPath)
+			assert.NoError(t, err)
+			var results CampaignResults
+			assert.NoError(t, json.Unmarshal(b, &results))
+			assert.EqualValues(t, *f.fuzzer.Results(), results)
+
+			// Both assertion tests should be described, along with the method they test. As the methods are not
+			// documented, they are described by their signature.
+			assert.Len(t, results.Tests, 2)
+			contracts := make([]string, 0)
+			for _, testCase := range results.Tests {
+				assert.NotEmpty(t, testCase.ID)
+				assert.Contains(t, testCase.Name, "value()")
+				assert.EqualValues(t, TestCaseStatusPassed, testCase.Status)
+				assert.NotEmpty(t, testCase.Message)
+				assert.NotEmpty(t, testCase.SourcePath)
+				assert.EqualValues(t, "value()", testCase.Signature)
+				assert.EqualValues(t, "value()", testCase.Description)
+				contracts = append(contracts, testCase.Contract)
+			}
+			assert.ElementsMatch(t, []string{"FirstContract", "SecondContract"}, contracts)
+
+			// The only method of each contract should be described as discovered by assertion testing. It is not
+			// declared as a view method, so it is described as state-changing.
+			assert.Len(t, results.TestDiscovery, 2)
+			for i, discovery := range results.TestDiscovery {
+				assert.EqualValues(t, []string{"FirstContract", "SecondContract"}[i], discovery.Contract)
+				assert.EqualValues(t, []string{"value()"}, discovery.StateChangingAssertionTests)
+				assert.Empty(t, discovery.ViewAssertionTests)
+				assert.Empty(t, discovery.PropertyTests)
+			}
+
+			// The call sequence lengths at which new coverage was discovered should be described.
+			assert.EqualValues(t, f.fuzzer.SequenceLengthHistogram(), results.SequenceLengths)
+			assert.NotEmpty(t, results.SequenceLengths.NewCoverage)
+
+			// The slowest call sequences should be described, sorted by descending duration.
+			assert.Positive(t, results.AverageSequenceDuration)
+			assert.NotEmpty(t, results.SlowestSequences)
+			for i, slowSequence := range results.SlowestSequences {
+				assert.Positive(t, slowSequence.Calls)
+				if i > 0 {
+					assert.GreaterOrEqual(t, results.SlowestSequences[i-1].Duration, slowSequence.Duration)
+				}
+			}
+
+			// Both contracts should be described, with their only method called.
+			assert.Len(t, results.Contracts, 2)
+			for i, contractMetric := range results.Contracts {
+				assert.EqualValues(t, []string{"FirstContract", "SecondContract"}[i], contractMetric.Contract)
+				assert.EqualValues(t, 1, contractMetric.MethodsTotal)
+				assert.EqualValues(t, 1, contractMetric.MethodsCalled)
+				assert.Positive(t, contractMetric.Calls)
+			}
+
+			// The composition of the corpus should be described, including the call sequences which achieved coverage.
+			assert.NotNil(t, results.Corpus)
+			assert.Positive(t, results.Corpus.TotalSequences)
+			assert.EqualValues(t, results.Corpus.TotalSequences, results.Corpus.MutableSequences)
+			assert.Positive(t, results.Corpus.TotalWeight.Sign())
+
+			// The SARIF log should describe both tests as rules, without any results as neither failed.
+			b, err = os.ReadFile(projectConfig.Fuzzing.SARIFPath)
+			assert.NoError(t, err)
+			var sarifLog SARIFLog
+			assert.NoError(t, json.Unmarshal(b, &sarifLog))
+			assert.EqualValues(t, *newSARIFLog(&results), sarifLog)
+			assert.Len(t, sarifLog.Runs, 1)
+			assert.Len(t, sarifLog.Runs[0].Tool.Driver.Rules, 2)
+			assert.Empty(t, sarifLog.Runs[0].Results)
+		})
+	})
+}
+
+// TestNewSARIFLog tests that the SARIF log of campaign results describes each test case as a rule, using the NatSpec
+// description of its method if it was documented, and each failed test case as a result located at its source file.
+func TestNewSARIFLog(t *testing.T) {
+	results := &CampaignResults{
+		Tests: []TestCaseResult{
+			{
+				ID:          "PROPERTY-TestContract-documented()",
+				Name:        "Property Test: TestContract.documented()",
+				Contract:    "TestContract",
+				SourcePath:  "contracts/TestContract.sol",
+				Signature:   "documented()",
+				Description: "Total supply never exceeds the cap",
+				Status:      TestCaseStatusFailed,
+				Message:     "documented failure",
+			},
+			{
+				ID:          "PROPERTY-TestContract-undocumented()",
+				Name:        "Property Test: TestContract.undocumented()",
+				Contract:    "TestContract",
+				SourcePath:  "contracts/TestContract.sol",
+				Signature:   "undocumented()",
+				Description: "undocumented()",
+				Status:      TestCaseStatusPassed,
+				Message:     "passed",
+			},
+			{
+				ID:      "SCRIPTED-oracle",
+				Name:    "Scripted Oracle: oracle",
+				Status:  TestCaseStatusFailed,
+				Message: "oracle failure",
+			},
+		},
+	}
+	sarifLog := newSARIFLog(results)
+	assert.EqualValues(t, "2.1.0", sarifLog.Version)
+	assert.Len(t, sarifLog.Runs, 1)
+	run := sarifLog.Runs[0]
+
+	// Each test case should be described by a rule.
+	assert.EqualValues(t, []SARIFRule{
+		{ID: "PROPERTY-TestContract-documented()", Name: "Property Test: TestContract.documented()", ShortDescription: SARIFMessage{Text: "Total supply never exceeds the cap"}},
+		{ID: "PROPERTY-TestContract-undocumented()", Name: "Property Test: TestContract.undocumented()", ShortDescription: SARIFMessage{Text: "Property Test: TestContract.undocumented()"}},
+		{ID: "SCRIPTED-oracle", Name: "Scripted Oracle: oracle", ShortDescription: SARIFMessage{Text: "Scripted Oracle: oracle"}},
+	}, run.Tool.Driver.Rules)
+
+	// Only failed test cases should be described by a result, located at their source file if they test a method.
+	assert.EqualValues(t, []SARIFResult{
+		{
+			RuleID:  "PROPERTY-TestContract-documented()",
+			Level:   "error",
+			Message: SARIFMessage{Text: "documented failure"},
+			Locations: []SARIFLocation{{
+				PhysicalLocation: SARIFPhysicalLocation{ArtifactLocation: SARIFArtifactLocation{URI: "contracts/TestContract.sol"}},
+			}},
+		},
+		{RuleID: "SCRIPTED-oracle", Level: "error", Message: SARIFMessage{Text: "oracle failure"}},
+	}, run.Results)
+}
@@ -2,19 +2,35 @@ package fuzzing
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"math/big"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/crytic/medusa/compilation"
+	"github.com/crytic/medusa/compilation/platforms"
 	"github.com/crytic/medusa/utils"
+	"github.com/crytic/medusa/utils/testutils"
 
 	"github.com/crytic/medusa/chain"
+	testChainConfig "github.com/crytic/medusa/chain/config"
 	"github.com/crytic/medusa/events"
 	"github.com/crytic/medusa/fuzzing/calls"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/corpus"
+	"github.com/crytic/medusa/fuzzing/coverage"
 	"github.com/crytic/medusa/fuzzing/executiontracer"
 	"github.com/crytic/medusa/fuzzing/valuegeneration"
+	"github.com/crytic/medusa/logging"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/crytic/medusa/fuzzing/config"
 	"github.com/stretchr/testify/assert"
@@ -152,6 +168,367 @@ func TestAssertionMode(t *testing.T) {
 	}
 }
 
+// TestAssertionsInViewMethodsAtCadence runs a test to ensure assertions in view methods which only fail after several
+// state-changing calls are detected when view methods are checked at a fixed cadence, and that the failing view method
+// call is appended to the reported call sequence.
+func TestAssertionsInViewMethodsAtCadence(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/assertions/assert_view_method_cadence.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.TestLimit = 10_000
+			config.Fuzzing.Testing.TestViewMethods = true
+			config.Fuzzing.Testing.AssertionTesting.CheckViewMethodsEvery = 1
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check for failed assertion tests, and verify the failing call sequence ends with the view method.
+			assertFailedTestsExpected(f, true)
+			for _, testCase := range f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed) {
+				assertionTestCase, ok := testCase.(*AssertionTestCase)
+				assert.True(t, ok)
+				callSequence := *assertionTestCase.CallSequence()
+				assert.Len(t, callSequence, 6)
+				lastCallMethod, err := callSequence[len(callSequence)-1].Method()
+				assert.NoError(t, err)
+				assert.EqualValues(t, "checkCounter", lastCallMethod.Name)
+			}
+		},
+	})
+}
+
+// TestAssertionsInInnerCalls runs tests to ensure that assertion failures in inner calls which are caught by the
+// calling contract are only reported if inner call panic detection is enabled, and are not reported if the contract
+// which panicked is excluded.
+func TestAssertionsInInnerCalls(t *testing.T) {
+	tests := []struct {
+		detectInnerCallPanics bool
+		excludeContracts      []string
+		expectFailure         bool
+	}{
+		{detectInnerCallPanics: false, expectFailure: false},
+		{detectInnerCallPanics: true, expectFailure: true},
+		{detectInnerCallPanics: true, excludeContracts: []string{"InnerContract"}, expectFailure: false},
+	}
+	for _, test := range tests {
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: "testdata/contracts/assertions/assert_inner_call_try_catch.sol",
+			configUpdates: func(config *config.ProjectConfig) {
+				config.Fuzzing.TargetContracts = []string{"TestContract"}
+				config.Fuzzing.TestLimit = 1_000
+				config.Fuzzing.Testing.AssertionTesting.DetectInnerCallPanics = test.detectInnerCallPanics
+				config.Fuzzing.Testing.ExcludeContracts = test.excludeContracts
+				config.Fuzzing.Testing.PropertyTesting.Enabled = false
+				config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+				config.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				// Start the fuzzer
+				err := f.fuzzer.Start()
+				assert.NoError(t, err)
+
+				// Check for failed assertion tests, and verify any failures were attributed to the inner contract.
+				assertFailedTestsExpected(f, test.expectFailure)
+				for _, testCase := range f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed) {
+					assertionTestCase, ok := testCase.(*AssertionTestCase)
+					assert.True(t, ok)
+					assert.NotNil(t, assertionTestCase.InnerCallPanic())
+					assert.EqualValues(t, "InnerContract", assertionTestCase.innerCallContract.Name())
+				}
+			},
+		})
+	}
+}
+
+// TestAssertionContractScope runs tests to ensure that when assertion testing is scoped to specific contracts, an
+// assertion failure originating in an included contract is reported, while an identical one originating in an excluded
+// dependency is not, even though it caused the tested method to fail.
+func TestAssertionContractScope(t *testing.T) {
+	tests := []struct {
+		includeContracts []string
+		excludeContracts []string
+	}{
+		{includeContracts: []string{"TestContract"}},
+		{excludeContracts: []string{"DependencyContract"}},
+	}
+	for _, test := range tests {
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: "testdata/contracts/assertions/assert_dependency_scope.sol",
+			configUpdates: func(config *config.ProjectConfig) {
+				config.Fuzzing.TargetContracts = []string{"TestContract"}
+				config.Fuzzing.TestLimit = 1_000
+				config.Fuzzing.Testing.StopOnFailedTest = false
+				config.Fuzzing.Testing.AssertionTesting.IncludeContracts = test.includeContracts
+				config.Fuzzing.Testing.AssertionTesting.ExcludeContracts = test.excludeContracts
+				config.Fuzzing.Testing.PropertyTesting.Enabled = false
+				config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+				config.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				// Start the fuzzer
+				err := f.fuzzer.Start()
+				assert.NoError(t, err)
+
+				// Only the assertion in the tested contract's own code should have been reported.
+				failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+				assert.Len(t, failedTestCases, 1)
+				for _, testCase := range failedTestCases {
+					assertionTestCase, ok := testCase.(*AssertionTestCase)
+					assert.True(t, ok)
+					assert.EqualValues(t, "checkOwnValue", assertionTestCase.targetMethod.Name)
+				}
+			},
+		})
+	}
+}
+
+// TestReentrancyDetection runs tests to ensure that state-changing reentrancy into a target contract is only reported
+// if reentrancy testing is enabled, and is not reported if the re-entered contract allows reentrancy.
+func TestReentrancyDetection(t *testing.T) {
+	tests := []struct {
+		enabled           bool
+		allowedReentrancy []string
+		expectFailure     bool
+	}{
+		{enabled: false, expectFailure: false},
+		{enabled: true, expectFailure: true},
+		{enabled: true, allowedReentrancy: []string{"Vault"}, expectFailure: false},
+		{enabled: true, allowedReentrancy: []string{"Vault.withdraw()"}, expectFailure: false},
+	}
+	for _, test := range tests {
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: "testdata/contracts/reentrancy/reentrancy_withdraw.sol",
+			configUpdates: func(pkgConfig *config.ProjectConfig) {
+				pkgConfig.Fuzzing.TargetContracts = []string{"Vault", "SafeVault", "Attacker"}
+				pkgConfig.Fuzzing.DeploymentValues = map[string]*config.ContractBalance{
+					"Vault":     {Int: *big.NewInt(1e18)},
+					"SafeVault": {Int: *big.NewInt(1e18)},
+					"Attacker":  {Int: *big.NewInt(1e18)},
+				}
+				pkgConfig.Fuzzing.ConstructorArgs = map[string]map[string]any{
+					"Attacker": {
+						"_vault":     "DeployedContract:Vault",
+						"_safeVault": "DeployedContract:SafeVault",
+					},
+				}
+				pkgConfig.Fuzzing.TestLimit = 1_000
+				pkgConfig.Fuzzing.Testing.StopOnNoTests = false
+				pkgConfig.Fuzzing.Testing.ReentrancyTesting.Enabled = test.enabled
+				pkgConfig.Fuzzing.Testing.ReentrancyTesting.AllowedReentrancy = test.allowedReentrancy
+				pkgConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+				pkgConfig.Fuzzing.Testing.PropertyTesting.Enabled = false
+				pkgConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+				pkgConfig.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				// Start the fuzzer
+				err := f.fuzzer.Start()
+				assert.NoError(t, err)
+
+				// Check for failed reentrancy tests, and verify any failures were for the vulnerable vault, re-entered
+				// through the attacker.
+				assertFailedTestsExpected(f, test.expectFailure)
+				for _, testCase := range f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed) {
+					reentrancyTestCase, ok := testCase.(*ReentrancyTestCase)
+					assert.True(t, ok)
+					assert.EqualValues(t, "Vault", reentrancyTestCase.targetContract.Name())
+					assert.Len(t, reentrancyTestCase.Reentrancy().CallPath, 3)
+					assert.Len(t, *reentrancyTestCase.CallSequence(), 1)
+				}
+			},
+		})
+	}
+}
+
+// TestUntrustedDelegateCallDetection runs tests to ensure that delegate calls to addresses provided by the caller are
+// only reported if untrusted delegate call testing is enabled, and are not reported if the delegate call is allowed.
+func TestUntrustedDelegateCallDetection(t *testing.T) {
+	tests := []struct {
+		enabled              bool
+		allowedDelegateCalls []string
+		expectFailure        bool
+	}{
+		{enabled: false, expectFailure: false},
+		{enabled: true, expectFailure: true},
+		{enabled: true, allowedDelegateCalls: []string{"Proxy"}, expectFailure: false},
+		{enabled: true, allowedDelegateCalls: []string{"Proxy.execute(address,uint256)"}, expectFailure: false},
+	}
+	for _, test := range tests {
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: "testdata/contracts/delegate_calls/untrusted_delegate_call.sol",
+			configUpdates: func(pkgConfig *config.ProjectConfig) {
+				pkgConfig.Fuzzing.TargetContracts = []string{"Implementation", "Proxy", "SafeProxy"}
+				pkgConfig.Fuzzing.ConstructorArgs = map[string]map[string]any{
+					"SafeProxy": {
+						"_implementation": "DeployedContract:Implementation",
+					},
+				}
+				pkgConfig.Fuzzing.TestLimit = 1_000
+				pkgConfig.Fuzzing.Testing.StopOnNoTests = false
+				pkgConfig.Fuzzing.Testing.UntrustedDelegateCallTesting.Enabled = test.enabled
+				pkgConfig.Fuzzing.Testing.UntrustedDelegateCallTesting.AllowedDelegateCalls = test.allowedDelegateCalls
+				pkgConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+				pkgConfig.Fuzzing.Testing.PropertyTesting.Enabled = false
+				pkgConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+				pkgConfig.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				// Start the fuzzer
+				err := f.fuzzer.Start()
+				assert.NoError(t, err)
+
+				// Check for failed untrusted delegate call tests, and verify any failures were for the proxy which
+				// delegate calls an address provided by its caller.
+				assertFailedTestsExpected(f, test.expectFailure)
+				for _, testCase := range f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed) {
+					delegateCallTestCase, ok := testCase.(*UntrustedDelegateCallTestCase)
+					assert.True(t, ok)
+					assert.EqualValues(t, "Proxy", delegateCallTestCase.targetContract.Name())
+					assert.Len(t, *delegateCallTestCase.CallSequence(), 1)
+					assert.NotNil(t, (*delegateCallTestCase.CallSequence())[0].ExecutionTrace)
+				}
+			},
+		})
+	}
+}
+
+// TestTokenInvariants runs tests to ensure that the ERC20 and ERC721 invariant tests pass for conforming tokens, and
+// fail with a shrunken call sequence for buggy tokens, only for the invariants they violate.
+func TestTokenInvariants(t *testing.T) {
+	tests := []struct {
+		filePath             string
+		erc20Contracts       []string
+		erc721Contracts      []string
+		transferFeeTolerance float64
+		expectedFailures     []tokenInvariant
+	}{
+		{filePath: "testdata/contracts/tokens/erc20_tokens.sol", erc20Contracts: []string{"ConformingToken"}},
+		{filePath: "testdata/contracts/tokens/erc20_tokens.sol", erc20Contracts: []string{"FeeToken"}, transferFeeTolerance: 0.01},
+		{filePath: "testdata/contracts/tokens/erc20_tokens.sol", erc20Contracts: []string{"FeeToken"}, expectedFailures: []tokenInvariant{tokenInvariantConservation}},
+		{filePath: "testdata/contracts/tokens/erc20_tokens.sol", erc20Contracts: []string{"BuggyToken"}, expectedFailures: []tokenInvariant{tokenInvariantSupply, tokenInvariantConservation}},
+		{filePath: "testdata/contracts/tokens/erc721_tokens.sol", erc721Contracts: []string{"ConformingNFT"}},
+		{filePath: "testdata/contracts/tokens/erc721_tokens.sol", erc721Contracts: []string{"BuggyNFT"}, expectedFailures: []tokenInvariant{tokenInvariantOwnership, tokenInvariantBalance}},
+	}
+	for _, test := range tests {
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: test.filePath,
+			configUpdates: func(pkgConfig *config.ProjectConfig) {
+				pkgConfig.Fuzzing.TargetContracts = append(slices.Clone(test.erc20Contracts), test.erc721Contracts...)
+				pkgConfig.Fuzzing.TestLimit = 20_000
+				pkgConfig.Fuzzing.Testing.StopOnNoTests = false
+				pkgConfig.Fuzzing.Testing.TokenTesting.Enabled = true
+				pkgConfig.Fuzzing.Testing.TokenTesting.ERC20Contracts = test.erc20Contracts
+				pkgConfig.Fuzzing.Testing.TokenTesting.ERC721Contracts = test.erc721Contracts
+				pkgConfig.Fuzzing.Testing.TokenTesting.TransferFeeTolerance = test.transferFeeTolerance
+				pkgConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+				pkgConfig.Fuzzing.Testing.PropertyTesting.Enabled = false
+				pkgConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+				pkgConfig.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				// Start the fuzzer
+				err := f.fuzzer.Start()
+				assert.NoError(t, err)
+
+				// Check that exactly the expected invariants failed, each with a call sequence shrunk to the mint and
+				// transfer (or burn) which violated it.
+				failedInvariants := make([]tokenInvariant, 0)
+				for _, testCase := range f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed) {
+					tokenTestCase, ok := testCase.(*TokenTestCase)
+					assert.True(t, ok)
+					assert.NotEmpty(t, tokenTestCase.Violation())
+					assert.LessOrEqual(t, len(*tokenTestCase.CallSequence()), 2)
+					failedInvariants = append(failedInvariants, tokenTestCase.invariant)
+				}
+				assert.ElementsMatch(t, test.expectedFailures, failedInvariants)
+			},
+		})
+	}
+}
+
+// TestScriptedOracles runs a test to ensure that scripted oracles are evaluated after each call against a token, that a
+// script which holds passes, and that a script which is violated fails with a shrunken call sequence and the message
+// it reported.
+func TestScriptedOracles(t *testing.T) {
+	passingScript, err := filepath.Abs("testdata/scripted_oracles/supply_covers_balances.star")
+	assert.NoError(t, err)
+	failingScript, err := filepath.Abs("testdata/scripted_oracles/sender_balance_limit.star")
+	assert.NoError(t, err)
+
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/tokens/erc20_tokens.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"ConformingToken"}
+			config.Fuzzing.TestLimit = 20_000
+			config.Fuzzing.Testing.ScriptedOracleTesting.Enabled = true
+			config.Fuzzing.Testing.ScriptedOracleTesting.Scripts = []string{passingScript, failingScript}
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Only the balance limit should have failed, with a call sequence shrunk to the calls which accumulated
+			// the first sender's balance, and the message reported by its script.
+			testCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+			assert.Len(t, testCases, 1)
+			for _, testCase := range testCases {
+				scriptedOracleTestCase, ok := testCase.(*ScriptedOracleTestCase)
+				assert.True(t, ok)
+				assert.EqualValues(t, "SCRIPTED-ORACLE-sender-balance-limit", scriptedOracleTestCase.ID())
+				assert.Contains(t, scriptedOracleTestCase.FailureMessage(), "balance of 0x0000000000000000000000000000000000010000")
+				assert.LessOrEqual(t, len(*scriptedOracleTestCase.CallSequence()), 3)
+			}
+			passedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusPassed)
+			assert.Len(t, passedTestCases, 1)
+		},
+	})
+}
+
+// TestStopOnCoveragePlateau runs a test to ensure the fuzzer stops once no new coverage has been achieved for the
+// configured threshold, before its timeout is reached, when fuzzing a contract whose coverage is quickly saturated.
+func TestStopOnCoveragePlateau(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/coverage_plateau/saturable_coverage.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.Workers = 2
+			config.Fuzzing.TestLimit = 0
+			config.Fuzzing.Timeout = 60
+			config.Fuzzing.StopOnCoveragePlateau = 2
+			config.Fuzzing.CoverageEnabled = true
+			config.Fuzzing.Testing.StopOnNoTests = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer, measuring how long it ran for.
+			start := time.Now()
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+			elapsed := time.Since(start)
+
+			// The fuzzer should have stopped on the coverage plateau, well before its timeout.
+			assert.GreaterOrEqual(t, elapsed, 2*time.Second)
+			assert.Less(t, elapsed, 30*time.Second)
+			assert.Greater(t, f.fuzzer.corpus.CoverageMaps().UniquePCs(), uint64(0))
+			assert.Greater(t, f.fuzzer.metrics.CallsTested().Uint64(), uint64(0))
+		},
+	})
+}
+
 // TestAssertionsNotRequire runs a test to ensure require and revert statements are not mistaken for assert statements.
 // It runs tests against a contract which immediately makes these statements and expects to find no errors before
 // timing out.
@@ -199,6 +576,75 @@ func TestAssertionsAndProperties(t *testing.T) {
 	})
 }
 
+// TestFuzzerTestProviderToggling runs a test to ensure that test providers can be muted while fuzzing, such that a
+// muted test provider stops testing while others continue, and its test cases are reported as muted.
+func TestFuzzerTestProviderToggling(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/assertions/assert_and_property_test.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.Workers = 1
+			config.Fuzzing.TestLimit = 1_000
+			config.Fuzzing.Testing.StopOnFailedTest = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// The built-in test providers should be registered.
+			providerIDs := make([]string, 0)
+			for _, provider := range f.fuzzer.TestProviders() {
+				providerIDs = append(providerIDs, provider.ID())
+			}
+			assert.Contains(t, providerIDs, "assertion")
+			assert.Contains(t, providerIDs, "property")
+
+			// Mute the property test provider prior to fuzzing.
+			assert.NoError(t, f.fuzzer.SetTestProviderEnabled("property", false))
+
+			// Register two custom test providers. The first is muted by the second midway through the campaign.
+			var mutedInvocations, activeInvocations, mutedInvocationsAtToggle int
+			mutedProvider := f.fuzzer.RegisterTestProvider("custom-muted")
+			activeProvider := f.fuzzer.RegisterTestProvider("custom-active")
+			f.fuzzer.Hooks.CallSequenceTestFuncs = append(f.fuzzer.Hooks.CallSequenceTestFuncs,
+				mutedProvider.CallSequenceTestFunc(func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+					mutedInvocations++
+					return nil, nil
+				}),
+				activeProvider.CallSequenceTestFunc(func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+					activeInvocations++
+					if activeInvocations == 100 {
+						mutedInvocationsAtToggle = mutedInvocations
+						return nil, worker.Fuzzer().SetTestProviderEnabled("custom-muted", false)
+					}
+					return nil, nil
+				}),
+			)
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The muted custom provider should not have been invoked after it was muted, while the other continued.
+			assert.Greater(t, mutedInvocationsAtToggle, 0)
+			assert.EqualValues(t, mutedInvocationsAtToggle, mutedInvocations)
+			assert.Greater(t, activeInvocations, 100)
+
+			// The assertion test should fail, while the property test should be reported as muted.
+			for _, testCase := range f.fuzzer.TestCases() {
+				switch testCase := testCase.(type) {
+				case *AssertionTestCase:
+					if testCase.targetMethod.Name == "failing_assert_method" {
+						assert.EqualValues(t, TestCaseStatusFailed, testCase.Status())
+					}
+				case *PropertyTestCase:
+					assert.EqualValues(t, TestCaseStatusMuted, testCase.Status())
+				}
+			}
+			assert.EqualValues(t, 1, len(f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)))
+		},
+	})
+}
+
 // TestOptimizationMode runs a test to ensure that optimization mode works as expected
 func TestOptimizationMode(t *testing.T) {
 	filePaths := []string{
@@ -231,6 +677,49 @@ func TestOptimizationMode(t *testing.T) {
 	}
 }
 
+// TestMalformedTestMethods runs a test to ensure methods named like property or optimization tests which do not have
+// the shape of one prevent the fuzzer from starting, or are reported as warnings if configured to.
+func TestMalformedTestMethods(t *testing.T) {
+	for _, failOnMalformedTestMethods := range []bool{true, false} {
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: "testdata/contracts/test_methods/malformed_test_methods.sol",
+			configUpdates: func(config *config.ProjectConfig) {
+				config.Fuzzing.TargetContracts = []string{"TestContract"}
+				config.Fuzzing.Workers = 1
+				config.Fuzzing.TestLimit = 1_000
+				config.Fuzzing.Testing.FailOnMalformedTestMethods = failOnMalformedTestMethods
+				config.Fuzzing.Testing.AssertionTesting.Enabled = false
+				config.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				// Start the fuzzer
+				err := f.fuzzer.Start()
+				if !failOnMalformedTestMethods {
+					// The fuzzer should run, only testing the well-formed tests.
+					assert.NoError(t, err)
+					assert.Len(t, f.fuzzer.TestCases(), 2)
+					return
+				}
+
+				// The fuzzer should refuse to start, naming every malformed method and the requirement it violates.
+				assert.Error(t, err)
+				expectedViolations := []string{
+					"TestContract.property_no_return(): property tests must return exactly one bool, but it returns nothing",
+					"TestContract.property_returns_uint(): property tests must return exactly one bool, but it returns (uint256)",
+					"TestContract.property_takes_argument(uint256): property tests must take no arguments, but it takes 1",
+					"TestContract.property_returns_two_bools(): property tests must return exactly one bool, but it returns (bool,bool)",
+					"TestContract.optimize_returns_uint(): optimization tests must return exactly one int256, but it returns (uint256)",
+				}
+				for _, expectedViolation := range expectedViolations {
+					assert.ErrorContains(t, err, expectedViolation)
+				}
+				assert.NotContains(t, err.Error(), "property_valid")
+				assert.NotContains(t, err.Error(), "optimize_valid")
+			},
+		})
+	}
+}
+
 // TestChainBehaviour runs tests to ensure the chain behaves as expected.
 func TestChainBehaviour(t *testing.T) {
 	// Run a test to simulate out of gas errors to make sure its handled well by the Chain and does not panic.
@@ -257,6 +746,87 @@ func TestChainBehaviour(t *testing.T) {
 	})
 }
 
+// TestChainEmptyBlocks runs a test to ensure empty blocks are mined between calls when enabled, so a property which
+// depends on blocks being produced between calls fails, and that shrinking reduces the amount of empty blocks mined.
+func TestChainEmptyBlocks(t *testing.T) {
+	for _, emptyBlockProbability := range []float32{0.2, 0} {
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: "testdata/contracts/chain/empty_blocks.sol",
+			configUpdates: func(config *config.ProjectConfig) {
+				config.Fuzzing.TargetContracts = []string{"TestContract"}
+				config.Fuzzing.Workers = 1
+				config.Fuzzing.TestLimit = 5_000
+				config.Fuzzing.Seed = 1234
+				config.Fuzzing.EmptyBlockProbability = emptyBlockProbability
+				config.Fuzzing.MaxEmptyBlocks = 20
+				config.Fuzzing.Testing.AssertionTesting.Enabled = false
+				config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+				config.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				// Start the fuzzer
+				err := f.fuzzer.Start()
+				assert.NoError(t, err)
+
+				// The property should only fail if empty blocks were mined.
+				assertFailedTestsExpected(f, emptyBlockProbability > 0)
+				if emptyBlockProbability == 0 {
+					return
+				}
+
+				// The shrunken call sequence should only mine the four empty blocks needed before its last call.
+				failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+				assert.NotEmpty(t, failedTestCases)
+				failingSequence := *failedTestCases[0].CallSequence()
+				assert.Len(t, failingSequence, 2)
+				assert.EqualValues(t, 0, failingSequence[0].EmptyBlocks)
+				assert.EqualValues(t, 4, failingSequence[1].EmptyBlocks)
+			},
+		})
+	}
+}
+
+// TestChainGeneratedPrevrandao runs a test to ensure the prevrandao value of each created block is generated when
+// enabled, so a property which depends on a specific prevrandao value fails, and that the generated value is stored
+// with the failing call sequence so it is reproduced.
+func TestChainGeneratedPrevrandao(t *testing.T) {
+	for _, generatePrevrandao := range []bool{true, false} {
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: "testdata/contracts/chain/prevrandao.sol",
+			configUpdates: func(config *config.ProjectConfig) {
+				config.Fuzzing.TargetContracts = []string{"TestContract"}
+				config.Fuzzing.Workers = 1
+				config.Fuzzing.TestLimit = 5_000
+				config.Fuzzing.Seed = 1234
+				config.Fuzzing.GeneratePrevrandao = generatePrevrandao
+				config.Fuzzing.TestChainConfig.PrevrandaoMode = testChainConfig.PrevrandaoModeFixed
+				config.Fuzzing.Testing.AssertionTesting.Enabled = false
+				config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+				config.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				// Start the fuzzer
+				err := f.fuzzer.Start()
+				assert.NoError(t, err)
+
+				// The property should only fail if prevrandao values were generated.
+				assertFailedTestsExpected(f, generatePrevrandao)
+				if !generatePrevrandao {
+					return
+				}
+
+				// The shrunken call sequence should carry the prevrandao value which satisfied the property.
+				failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+				assert.NotEmpty(t, failedTestCases)
+				failingSequence := *failedTestCases[0].CallSequence()
+				assert.Len(t, failingSequence, 1)
+				assert.NotNil(t, failingSequence[0].Prevrandao)
+				assert.EqualValues(t, 42, failingSequence[0].Prevrandao[common.HashLength-1])
+			},
+		})
+	}
+}
+
 // TestCheatCodes runs tests to ensure that vm extensions ("cheat codes") are working as intended.
 func TestCheatCodes(t *testing.T) {
 	filePaths := []string{
@@ -434,41 +1004,232 @@ func TestDeploymentsInnerDeployments(t *testing.T) {
 	})
 }
 
-// TestDeploymentsInternalLibrary runs a test to ensure internal libraries behave correctly.
-func TestDeploymentsInternalLibrary(t *testing.T) {
+// TestResolveDeployedContractMethods runs a test to ensure the methods of statically and dynamically deployed contracts
+// can be resolved from event handlers, both through the contract definitions registered with the fuzzer and through
+// the contracts deployed on each worker's chain.
+func TestResolveDeployedContractMethods(t *testing.T) {
 	runFuzzerTest(t, &fuzzerSolcFileTest{
-		filePath: "testdata/contracts/deployments/internal_library.sol",
+		filePath: "testdata/contracts/deployments/inner_deployment_on_construction.sol",
 		configUpdates: func(config *config.ProjectConfig) {
-			config.Fuzzing.TargetContracts = []string{"TestInternalLibrary"}
-			config.Fuzzing.TestLimit = 100 // this test should expose a failure quickly.
+			config.Fuzzing.TargetContracts = []string{"InnerDeploymentFactory"}
+			config.Fuzzing.TestLimit = 1_000
+			config.Fuzzing.Testing.StopOnFailedTest = false
+			config.Fuzzing.Testing.TestAllContracts = true // track dynamically deployed contracts
 			config.Fuzzing.Testing.AssertionTesting.Enabled = false
 			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
 			config.Slither.UseSlither = false
 		},
 		method: func(f *fuzzerTestContext) {
+			// Both contract definitions declare the same method, so it should resolve to both.
+			contractDefinitions := f.fuzzer.ContractDefinitions()
+			assert.Len(t, contractDefinitions.ByName("InnerDeployment"), 1)
+			assert.Len(t, contractDefinitions.ByName("InnerDeploymentFactory"), 1)
+			dummySelector := contractDefinitions.ByName("InnerDeployment")[0].CompiledContract().Abi.Methods["dummyFunction"].ID
+			assert.Len(t, contractDefinitions.MethodsBySelector(dummySelector), 2)
+
+			// Resolve the method of each contract as the workers detect their deployment.
+			var resolvedLock sync.Mutex
+			dynamicDeployments := make(map[string]bool)
+			unknownResolved := false
+			f.fuzzer.Events.WorkerCreated.Subscribe(func(event FuzzerWorkerCreatedEvent) error {
+				event.Worker.Events.ContractAdded.Subscribe(func(event FuzzerWorkerContractAddedEvent) error {
+					resolvedLock.Lock()
+					defer resolvedLock.Unlock()
+					if method := event.Worker.ResolveMethod(event.ContractAddress, dummySelector); method != nil {
+						assert.EqualValues(t, event.ContractAddress, method.Address)
+						assert.EqualValues(t, "dummyFunction(uint256)", method.Method.Sig)
+						dynamicDeployments[method.Contract.Name()] = event.DynamicDeployment
+					}
+					unknownResolved = unknownResolved || event.Worker.ResolveMethod(event.ContractAddress, []byte{0xde, 0xad, 0xbe, 0xef}) != nil
+					return nil
+				})
+				return nil
+			})
+
 			// Start the fuzzer
 			err := f.fuzzer.Start()
 			assert.NoError(t, err)
 
-			// Check for any failed tests and verify coverage was captured
-			assertFailedTestsExpected(f, false)
-			assertCorpusCallSequencesCollected(f, true)
+			// The factory is deployed statically, while the contract it deploys is deployed dynamically.
+			factoryDynamic, factoryResolved := dynamicDeployments["InnerDeploymentFactory"]
+			assert.True(t, factoryResolved)
+			assert.False(t, factoryDynamic)
+			innerDynamic, innerResolved := dynamicDeployments["InnerDeployment"]
+			assert.True(t, innerResolved)
+			assert.True(t, innerDynamic)
+			assert.False(t, unknownResolved)
 		},
 	})
 }
 
-// TestDeploymentsWithPredeploy runs a test to ensure that predeployed contracts are instantiated correctly.
-func TestDeploymentsWithPredeploy(t *testing.T) {
+// TestDeploymentsDynamicDeploymentTargetLimit runs a test to ensure the number of dynamically deployed contracts
+// registered as fuzzing targets is bounded by the dynamic deployment target limit, while all children remain tracked.
+func TestDeploymentsDynamicDeploymentTargetLimit(t *testing.T) {
 	runFuzzerTest(t, &fuzzerSolcFileTest{
-		filePath: "testdata/contracts/deployments/predeploy_contract.sol",
-		configUpdates: func(pkgConfig *config.ProjectConfig) {
-			pkgConfig.Fuzzing.TargetContracts = []string{"TestContract"}
-			pkgConfig.Fuzzing.TargetContractsBalances = []*config.ContractBalance{{Int: *big.NewInt(1)}}
-			pkgConfig.Fuzzing.TestLimit = 1000 // this test should expose a failure immediately
-			pkgConfig.Fuzzing.Testing.PropertyTesting.Enabled = false
-			pkgConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
-			pkgConfig.Fuzzing.PredeployedContracts = map[string]string{"PredeployContract": "0x1234"}
-			pkgConfig.Slither.UseSlither = false
+		filePath: "testdata/contracts/deployments/factory_many_children.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"ChildFactory"}
+			config.Fuzzing.TestLimit = 1_000
+			config.Fuzzing.Testing.TestAllContracts = true // track dynamically deployed contracts
+			config.Fuzzing.Testing.DynamicDeploymentTargetLimit = 3
+			config.Fuzzing.Testing.StopOnNoTests = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Record the largest number of target methods and tracked contracts any worker had
+			var statsLock sync.Mutex
+			maxTargetMethods, maxDeployedContracts := 0, 0
+			f.fuzzer.Hooks.CallSequenceTestFuncs = append(f.fuzzer.Hooks.CallSequenceTestFuncs, func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+				statsLock.Lock()
+				defer statsLock.Unlock()
+				maxTargetMethods = max(maxTargetMethods, len(worker.stateChangingMethods))
+				maxDeployedContracts = max(maxDeployedContracts, len(worker.deployedContracts))
+				return nil, nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The factory has one method and each targeted child has two, so at most three children should be targeted.
+			assert.LessOrEqual(t, maxTargetMethods, 1+3*2)
+
+			// All children should still be tracked, beyond the limit.
+			assert.Greater(t, maxDeployedContracts, 1+3)
+		},
+	})
+}
+
+// TestDeploymentsWaitForDynamicTargets runs a test to ensure workers without any methods to call, which wait for
+// dynamically deployed targets, deploy them by testing corpus call sequences, then fuzz the deployed targets.
+func TestDeploymentsWaitForDynamicTargets(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/deployments/wait_for_dynamic_targets.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"ChildFactory"}
+			config.Fuzzing.CorpusDirectory = "corpus"
+			config.Fuzzing.TestLimit = 1_000
+			config.Fuzzing.Testing.TestAllContracts = true // test dynamically deployed contracts
+			config.Fuzzing.Testing.ExcludeFunctionSignatures = []string{"Child.fail()"}
+			config.Fuzzing.Testing.StopOnNoTests = false
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer to populate our corpus with call sequences which deploy children, without calling them.
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+			assertCorpusCallSequencesCollected(f, true)
+			assertFailedTestsExpected(f, false)
+
+			// Exclude the factory instead, so workers have no methods to call until our corpus deploys a child, whose
+			// failing method should then be called.
+			f.fuzzer.config.Fuzzing.Testing.ExcludeFunctionSignatures = []string{"ChildFactory.deployChild()"}
+			f.fuzzer.config.Fuzzing.Testing.WaitForDynamicTargets = true
+			err = f.fuzzer.Start()
+			assert.NoError(t, err)
+			assertFailedTestsExpected(f, true)
+		},
+	})
+}
+
+// TestDeploymentsContractAddedCreationContext runs a test to ensure the contract added events emitted by workers
+// describe the creation context of both setup-time and dynamic (factory) deployments.
+func TestDeploymentsContractAddedCreationContext(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/deployments/inner_deployment.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"InnerDeploymentFactory"}
+			config.Fuzzing.TestLimit = 1_000
+			config.Fuzzing.Testing.TestAllContracts = true // track dynamically deployed contracts
+			config.Fuzzing.Testing.StopOnFailedTest = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Record all contract added events emitted by our workers
+			var addedEventsLock sync.Mutex
+			addedEvents := make([]FuzzerWorkerContractAddedEvent, 0)
+			f.fuzzer.Events.WorkerCreated.Subscribe(func(event FuzzerWorkerCreatedEvent) error {
+				event.Worker.Events.ContractAdded.Subscribe(func(event FuzzerWorkerContractAddedEvent) error {
+					addedEventsLock.Lock()
+					defer addedEventsLock.Unlock()
+					addedEvents = append(addedEvents, event)
+					return nil
+				})
+				return nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Verify the creation context of each deployment. The factory was deployed during setup by our deployer,
+			// while its children were deployed dynamically by the factory while fuzzing.
+			factoryAddresses := make(map[common.Address]bool)
+			childCount := 0
+			for _, event := range addedEvents {
+				assert.NotEqualValues(t, common.Hash{}, event.InitBytecodeHash)
+				assert.NotEqualValues(t, common.Hash{}, event.TransactionHash)
+				if event.ContractDefinition.Name() == "InnerDeploymentFactory" {
+					assert.False(t, event.DynamicDeployment)
+					assert.EqualValues(t, f.fuzzer.DeployerAddress(), event.Creator)
+					factoryAddresses[event.ContractAddress] = true
+				}
+			}
+			for _, event := range addedEvents {
+				if event.ContractDefinition.Name() == "InnerDeployment" {
+					assert.True(t, event.DynamicDeployment)
+					assert.True(t, factoryAddresses[event.Creator])
+					childCount++
+				}
+			}
+			assert.NotEmpty(t, factoryAddresses)
+			assert.Greater(t, childCount, 0)
+		},
+	})
+}
+
+// TestDeploymentsInternalLibrary runs a test to ensure internal libraries behave correctly.
+func TestDeploymentsInternalLibrary(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/deployments/internal_library.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestInternalLibrary"}
+			config.Fuzzing.TestLimit = 100 // this test should expose a failure quickly.
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check for any failed tests and verify coverage was captured
+			assertFailedTestsExpected(f, false)
+			assertCorpusCallSequencesCollected(f, true)
+		},
+	})
+}
+
+// TestDeploymentsWithPredeploy runs a test to ensure that predeployed contracts are instantiated correctly.
+func TestDeploymentsWithPredeploy(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/deployments/predeploy_contract.sol",
+		configUpdates: func(pkgConfig *config.ProjectConfig) {
+			pkgConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+			pkgConfig.Fuzzing.TargetContractsBalances = []*config.ContractBalance{{Int: *big.NewInt(1)}}
+			pkgConfig.Fuzzing.TestLimit = 1000 // this test should expose a failure immediately
+			pkgConfig.Fuzzing.Testing.PropertyTesting.Enabled = false
+			pkgConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			pkgConfig.Fuzzing.PredeployedContracts = map[string]string{"PredeployContract": "0x1234"}
+			pkgConfig.Slither.UseSlither = false
 		},
 		method: func(f *fuzzerTestContext) {
 			// Start the fuzzer
@@ -482,6 +1243,144 @@ func TestDeploymentsWithPredeploy(t *testing.T) {
 	})
 }
 
+// TestDeploymentsWithPredeployConstructorArgs runs a test to ensure that predeployed contracts with constructor
+// arguments are instantiated at their configured address, and are fuzzed like any other deployed contract.
+func TestDeploymentsWithPredeployConstructorArgs(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/deployments/predeploy_fixed_address.sol",
+		configUpdates: func(pkgConfig *config.ProjectConfig) {
+			pkgConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+			pkgConfig.Fuzzing.TestLimit = 10_000 // this test should expose a failure quickly
+			pkgConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			pkgConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			pkgConfig.Fuzzing.PredeployedContracts = map[string]string{"PredeployToken": "0x1234"}
+			pkgConfig.Fuzzing.ConstructorArgs = map[string]map[string]any{
+				"PredeployToken": {"initialSupply": "100"},
+			}
+			pkgConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Record the addresses our contracts were matched at by the workers
+			var addedContractsLock sync.Mutex
+			addedContracts := make(map[string]common.Address)
+			f.fuzzer.Events.WorkerCreated.Subscribe(func(event FuzzerWorkerCreatedEvent) error {
+				event.Worker.Events.ContractAdded.Subscribe(func(event FuzzerWorkerContractAddedEvent) error {
+					addedContractsLock.Lock()
+					defer addedContractsLock.Unlock()
+					if event.ContractDefinition != nil {
+						addedContracts[event.ContractDefinition.Name()] = event.ContractAddress
+					}
+					return nil
+				})
+				return nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The predeployed contract should have been added at its configured address
+			assert.EqualValues(t, common.HexToAddress("0x1234"), addedContracts["PredeployToken"])
+
+			// The property depends on the predeployed contract's constructor-initialized state, so it should fail
+			assertFailedTestsExpected(f, true)
+			assertCorpusCallSequencesCollected(f, true)
+		},
+	})
+}
+
+// TestDeploymentsWithPredeployAddressConflict runs a test to ensure that a predeployed contract address which
+// conflicts with the address a target contract would be deployed at is reported as an error.
+func TestDeploymentsWithPredeployAddressConflict(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/deployments/predeploy_contract.sol",
+		configUpdates: func(pkgConfig *config.ProjectConfig) {
+			// The predeployed contract consumes the deployer's first nonce, so the target contract would be deployed
+			// at the address derived from the second.
+			deployer := common.HexToAddress(pkgConfig.Fuzzing.DeployerAddress)
+			conflictingAddr := crypto.CreateAddress(deployer, 1)
+
+			pkgConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+			pkgConfig.Fuzzing.TestLimit = 1
+			pkgConfig.Fuzzing.PredeployedContracts = map[string]string{"PredeployContract": conflictingAddr.String()}
+			pkgConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer, which should fail to set up the test chain
+			err := f.fuzzer.Start()
+			assert.ErrorContains(t, err, "conflicts with the address of predeployed contract PredeployContract")
+		},
+	})
+}
+
+// TestDeploymentsWithContractDeployers runs a test to ensure that contracts are deployed by the deployer configured
+// for them, falling back to the default deployer otherwise.
+func TestDeploymentsWithContractDeployers(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/deployments/per_contract_deployer.sol",
+		configUpdates: func(pkgConfig *config.ProjectConfig) {
+			pkgConfig.Fuzzing.TargetContracts = []string{"AdminVault", "UserVault", "TestContract"}
+			pkgConfig.Fuzzing.ContractDeployers = map[string]string{
+				"AdminVault": "0x40000",
+				"UserVault":  "0x50000",
+			}
+			pkgConfig.Fuzzing.ConstructorArgs = map[string]map[string]any{
+				"TestContract": {
+					"_adminVault": "DeployedContract:AdminVault",
+					"_userVault":  "DeployedContract:UserVault",
+				},
+			}
+			pkgConfig.Fuzzing.TestLimit = 1_000
+			pkgConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			pkgConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			pkgConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Verify the deployer resolved for each contract
+			assert.EqualValues(t, common.HexToAddress("0x40000"), f.fuzzer.ContractDeployerAddress("AdminVault"))
+			assert.EqualValues(t, common.HexToAddress("0x50000"), f.fuzzer.ContractDeployerAddress("UserVault"))
+			assert.EqualValues(t, f.fuzzer.DeployerAddress(), f.fuzzer.ContractDeployerAddress("TestContract"))
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The ownership properties should hold, as each contract observed its configured deployer
+			assertFailedTestsExpected(f, false)
+
+			// A contract must not be predeployed at the address of a per-contract deployer.
+			f.fuzzer.config.Fuzzing.PredeployedContracts = map[string]string{"TestContract": "0x40000"}
+			assert.ErrorContains(t, f.fuzzer.config.Validate(), "also the deployer address of AdminVault")
+		},
+	})
+}
+
+// TestDeploymentsWithDeploymentValues runs a test to ensure that deployment values are sent to payable constructors
+// and are reflected in the deployed contract's balance.
+func TestDeploymentsWithDeploymentValues(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/deployments/deployment_value.sol",
+		configUpdates: func(pkgConfig *config.ProjectConfig) {
+			pkgConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+			pkgConfig.Fuzzing.DeploymentValues = map[string]*config.ContractBalance{
+				"TestContract": {Int: *big.NewInt(1e18)},
+			}
+			pkgConfig.Fuzzing.TestLimit = 1_000
+			pkgConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			pkgConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			pkgConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The constructor should not have reverted, and the endowment should be reflected in the contract balance
+			assertFailedTestsExpected(f, false)
+		},
+	})
+}
+
 // TestDeploymentsWithPayableConstructor runs a test to ensure that we can send ether to payable constructors
 func TestDeploymentsWithPayableConstructors(t *testing.T) {
 	runFuzzerTest(t, &fuzzerSolcFileTest{
@@ -556,80 +1455,571 @@ func TestDeploymentsSelfDestruct(t *testing.T) {
 	}
 }
 
-// TestExecutionTraces runs tests to ensure that execution traces capture information
-// regarding assertion failures, revert reasons, etc.
-func TestExecutionTraces(t *testing.T) {
-	expectedMessagesPerTest := map[string][]string{
-		"testdata/contracts/execution_tracing/call_and_deployment_args.sol": {"Hello from deployment args!", "Hello from call args!"},
-		"testdata/contracts/execution_tracing/cheatcodes.sol":               {"StdCheats.toString(bool)(true)"},
-		"testdata/contracts/execution_tracing/event_emission.sol":           {"TestEvent", "TestIndexedEvent", "TestMixedEvent", "Hello from event args!", "Hello from library event args!"},
-		"testdata/contracts/execution_tracing/proxy_call.sol":               {"TestContract -> InnerDeploymentContract.setXY", "Hello from proxy call args!"},
-		"testdata/contracts/execution_tracing/revert_custom_error.sol":      {"CustomError", "Hello from a custom error!"},
-		"testdata/contracts/execution_tracing/revert_reasons.sol":           {"RevertingContract was called and reverted."},
-		"testdata/contracts/execution_tracing/self_destruct.sol":            {"[selfdestruct]", "[panic: assertion failed]"},
-	}
-	for filePath, expectedTraceMessages := range expectedMessagesPerTest {
-		runFuzzerTest(t, &fuzzerSolcFileTest{
-			filePath: filePath,
-			configUpdates: func(config *config.ProjectConfig) {
-				config.Fuzzing.TargetContracts = []string{"TestContract"}
-				config.Fuzzing.Testing.PropertyTesting.Enabled = false
-				config.Fuzzing.Testing.OptimizationTesting.Enabled = false
-				config.Slither.UseSlither = false
-			},
-			method: func(f *fuzzerTestContext) {
-				// Start the fuzzer
-				err := f.fuzzer.Start()
-				assert.NoError(t, err)
-
-				// Check for failed assertion tests.
-				failedTestCase := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
-				assert.NotEmpty(t, failedTestCase, "expected to have failed test cases")
-
-				// Obtain our first failed test case, get the message, and verify it contains our assertion failed.
-				failingSequence := *failedTestCase[0].CallSequence()
-				assert.NotEmpty(t, failingSequence, "expected to have calls in the call sequence failing an assertion test")
+// TestDeploymentsSelfDestructTargetRegeneration runs a test to ensure that once a contract is destroyed within a call
+// sequence, no later elements of the same sequence which were derived from the corpus continue to target it.
+func TestDeploymentsSelfDestructTargetRegeneration(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/deployments/destroyable_target.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.TestLimit = 5_000
+			config.Fuzzing.Testing.StopOnNoTests = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.TestAllContracts = true
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// After each call, check whether it targeted a contract which was destroyed earlier in the same sequence.
+			var statsLock sync.Mutex
+			selfDestructCount := 0
+			deadTargetCallCount := 0
+			f.fuzzer.Hooks.CallSequenceTestFuncs = append(f.fuzzer.Hooks.CallSequenceTestFuncs, func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+				statsLock.Lock()
+				defer statsLock.Unlock()
+
+				// Collect the contracts destroyed prior to the last call.
+				destroyedContracts := make(map[common.Address]bool)
+				for _, element := range callSequence[:len(callSequence)-1] {
+					for _, deploymentChange := range element.ChainReference.MessageResults().ContractDeploymentChanges {
+						if deploymentChange.SelfDestructed {
+							destroyedContracts[deploymentChange.Contract.Address] = true
+						}
+					}
+				}
 
-				// Obtain the last call
-				lastCall := failingSequence[len(failingSequence)-1]
-				assert.NotNilf(t, lastCall.ExecutionTrace, "expected to have an execution trace attached to call sequence for this test")
+				// Check whether the last call targeted any of them.
+				lastElement := callSequence[len(callSequence)-1]
+				if lastElement.Call.To != nil && destroyedContracts[*lastElement.Call.To] {
+					deadTargetCallCount++
+				}
+				for _, deploymentChange := range lastElement.ChainReference.MessageResults().ContractDeploymentChanges {
+					if deploymentChange.SelfDestructed {
+						selfDestructCount++
+					}
+				}
+				return nil, nil
+			})
 
-				// Get the execution trace message
-				executionTraceMsg := lastCall.ExecutionTrace.Log().String()
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
 
-				// Verify it contains all expected strings
-				for _, expectedTraceMessage := range expectedTraceMessages {
-					assert.Contains(t, executionTraceMsg, expectedTraceMessage)
-				}
-			},
-		})
-	}
+			// We should have destroyed the contract, but never targeted it again within the same sequence.
+			assert.Greater(t, selfDestructCount, 0, "no SELFDESTRUCT operations were detected, when they should have been.")
+			assert.EqualValues(t, 0, deadTargetCallCount, "calls were generated against a contract destroyed earlier in the sequence.")
+		},
+	})
 }
 
-// TestLabelCheatCode tests the vm.label cheatcode.
-func TestLabelCheatCode(t *testing.T) {
-	// These are the expected messages in the execution trace
-	expectedTraceMessages := []string{
-		"ProxyContract.testVMLabel()()",
-		"addr=ProxyContract [0xA647ff3c36cFab592509E13860ab8c4F28781a66]",
-		"sender=MySender [0x10000]",
-		"ProxyContract -> ImplementationContract.emitEvent(address)(ProxyContract [0xA647ff3c36cFab592509E13860ab8c4F28781a66])",
-		"code=ImplementationContract [0x54919A19522Ce7c842E25735a9cFEcef1c0a06dA]",
-		"[event] TestEvent(RandomAddress [0x20000])",
-		"[return (ProxyContract [0xA647ff3c36cFab592509E13860ab8c4F28781a66])]",
-	}
+// TestAttachExecutionTraces runs a test to ensure that execution traces can be attached to specific elements of an
+// arbitrary call sequence on demand, without modifying the chain or tracing other elements.
+func TestAttachExecutionTraces(t *testing.T) {
 	runFuzzerTest(t, &fuzzerSolcFileTest{
-		filePath: "testdata/contracts/cheat_codes/utils/label.sol",
+		filePath: "testdata/contracts/execution_tracing/trace_attachment.sol",
 		configUpdates: func(config *config.ProjectConfig) {
 			config.Fuzzing.TargetContracts = []string{"TestContract"}
-			// Only allow for one sender for proper testing of this unit test
-			config.Fuzzing.SenderAddresses = []string{"0x10000"}
+			config.Fuzzing.Workers = 1
+			config.Fuzzing.TestLimit = 1
+			config.Fuzzing.Testing.StopOnNoTests = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
 			config.Fuzzing.Testing.PropertyTesting.Enabled = false
 			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
 			config.Slither.UseSlither = false
 		},
 		method: func(f *fuzzerTestContext) {
-			// Start the fuzzer
+			// Once the worker's chain is set up, attach a trace to the middle element of a three-call sequence.
+			traceAttached := false
+			f.fuzzer.Events.WorkerCreated.Subscribe(func(event FuzzerWorkerCreatedEvent) error {
+				event.Worker.Events.FuzzerWorkerChainSetup.Subscribe(func(event FuzzerWorkerChainSetupEvent) error {
+					// Find our test contract.
+					var testContractAddress common.Address
+					var testContract *fuzzerTypes.Contract
+					for contractAddress, contract := range event.Worker.deployedContracts {
+						if contract.Name() == "TestContract" {
+							testContractAddress, testContract = contractAddress, contract
+						}
+					}
+					assert.NotNil(t, testContract)
+					setXMethod := testContract.CompiledContract().Abi.Methods["setX"]
+
+					// Create our call sequence.
+					sender := f.fuzzer.senders[0]
+					callSequence := make(calls.CallSequence, 0)
+					for i := 0; i < 3; i++ {
+						msg := calls.NewCallMessageWithAbiValueData(sender, &testContractAddress, 0, big.NewInt(0), f.fuzzer.config.Fuzzing.TransactionGasLimit, nil, nil, nil, &calls.CallMessageDataAbiValues{
+							Method:      &setXMethod,
+							InputValues: []any{big.NewInt(int64(i + 1))},
+						})
+						msg.FillFromTestChainProperties(event.Chain)
+						msg.Nonce += uint64(i)
+						callSequence = append(callSequence, calls.NewCallSequenceElement(testContract, msg, 0, 0))
+					}
+
+					// Attach a trace to the middle element, and verify the chain was reverted afterwards.
+					headBlockNumber := event.Chain.HeadBlockNumber()
+					err := calls.AttachExecutionTraces(event.Chain, f.fuzzer.contractDefinitions, callSequence, []int{1})
+					assert.NoError(t, err)
+					assert.EqualValues(t, headBlockNumber, event.Chain.HeadBlockNumber())
+
+					// Only the middle element should have a trace, with a decoded call into the inner contract.
+					assert.Nil(t, callSequence[0].ExecutionTrace)
+					assert.Nil(t, callSequence[2].ExecutionTrace)
+					trace := callSequence[1].ExecutionTrace
+					if assert.NotNil(t, trace) && assert.NotNil(t, trace.TopLevelCallFrame) {
+						assert.EqualValues(t, testContractAddress, trace.TopLevelCallFrame.ToAddress)
+						assert.EqualValues(t, "TestContract", trace.TopLevelCallFrame.ToContractName)
+						childCallFrames := trace.TopLevelCallFrame.ChildCallFrames()
+						if assert.Len(t, childCallFrames, 1) {
+							assert.EqualValues(t, "InnerContract", childCallFrames[0].ToContractName)
+						}
+						assert.Contains(t, trace.String(), "TestContract.setX(uint256)(2)")
+						assert.Contains(t, trace.String(), "InnerContract.setY(uint256)(4)")
+					}
+					traceAttached = true
+					return nil
+				})
+				return nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+			assert.True(t, traceAttached, "execution traces were never attached")
+		},
+	})
+}
+
+// TestRevertClassificationMetrics runs a test to ensure that failed calls are classified by their cause and aggregated
+// per method in the fuzzer metrics.
+func TestRevertClassificationMetrics(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/metrics/revert_classification.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.TestLimit = 1_000
+			config.Fuzzing.TransactionGasLimit = 1_000_000
+			config.Fuzzing.ResultsPath = "results.json"
+			config.Fuzzing.Testing.StopOnNoTests = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Each method should have failed, with only the classification it was designed to exhibit.
+			expectedClassifications := map[string]RevertClassification{
+				"failRevert()":             RevertClassificationRevert,
+				"failRequire()":            RevertClassificationRequire,
+				"failCustomError(uint256)": RevertClassificationCustomError,
+				"failPanic()":              RevertClassificationPanic,
+				"failOutOfGas()":           RevertClassificationOutOfGas,
+				"failInvalidOpcode()":      RevertClassificationInvalidOpcode,
+			}
+			methodCounts := make(map[string]uint64)
+			for _, revertMetric := range f.fuzzer.metrics.RevertMetrics() {
+				assert.EqualValues(t, "TestContract", revertMetric.Contract)
+				assert.EqualValues(t, expectedClassifications[revertMetric.Method], revertMetric.Classification, "unexpected classification for %v", revertMetric.Method)
+				if revertMetric.Classification == RevertClassificationPanic {
+					assert.EqualValues(t, 0x11, revertMetric.PanicCode)
+				}
+				methodCounts[revertMetric.Method] += revertMetric.Count
+			}
+			for method := range expectedClassifications {
+				assert.Greater(t, methodCounts[method], uint64(0), "no failed calls were recorded for %v", method)
+			}
+
+			// The aggregated counts per classification should account for every failed call, since every call fails.
+			total := uint64(0)
+			for _, count := range f.fuzzer.metrics.RevertClassificationCounts() {
+				total += count
+			}
+			assert.EqualValues(t, f.fuzzer.metrics.CallsTested().Uint64(), total)
+
+			// The breakdown should have been written to the results file.
+			b, err := os.ReadFile("results.json")
+			assert.NoError(t, err)
+			var results struct {
+				RevertClassifications map[string]uint64 `json:"revertClassifications"`
+				Reverts               []RevertMetric    `json:"reverts"`
+			}
+			assert.NoError(t, json.Unmarshal(b, &results))
+			assert.EqualValues(t, f.fuzzer.metrics.RevertMetrics(), results.Reverts)
+			resultsTotal := uint64(0)
+			for _, count := range results.RevertClassifications {
+				resultsTotal += count
+			}
+			assert.EqualValues(t, total, resultsTotal)
+			assert.Positive(t, results.RevertClassifications["panic"])
+		},
+	})
+}
+
+// TestSlowSequenceMetrics runs a test to ensure the duration of each call sequence is tracked, and that the slowest
+// call sequences are attributed to the slow method which used the most gas in them.
+func TestSlowSequenceMetrics(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/metrics/slow_method.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.TestLimit = 2_000
+			config.Fuzzing.Testing.StopOnNoTests = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The average duration should have been tracked, and the leaderboard should be sorted and bounded.
+			assert.Greater(t, f.fuzzer.metrics.AverageSequenceDuration(), time.Duration(0))
+			slowestSequences := f.fuzzer.metrics.SlowestSequences()
+			assert.NotEmpty(t, slowestSequences)
+			assert.LessOrEqual(t, len(slowestSequences), slowSequenceLeaderboardSize)
+			for i := 1; i < len(slowestSequences); i++ {
+				assert.GreaterOrEqual(t, slowestSequences[i-1].Duration, slowestSequences[i].Duration)
+			}
+
+			// The slowest call sequence should be attributed to the slow method, and identified by its replay ID, as
+			// no corpus was replayed.
+			slowest := slowestSequences[0]
+			assert.EqualValues(t, "TestContract", slowest.Contract)
+			assert.EqualValues(t, "slowWrite(uint256)", slowest.Method)
+			assert.Greater(t, slowest.MethodGasUsed, uint64(0))
+			assert.Empty(t, slowest.CorpusFile)
+			_, err = ParseReplayID(slowest.ReplayID)
+			assert.NoError(t, err)
+		},
+	})
+}
+
+// TestContractMetrics runs a test to ensure calls and corpus sequences are aggregated per contract in the fuzzer
+// metrics, and that contracts excluded from call generation are reported without any methods to call.
+func TestContractMetrics(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/metrics/contract_metrics.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"HelperContract", "TestContract"}
+			config.Fuzzing.ConstructorArgs = map[string]map[string]any{
+				"TestContract": {"_helper": "DeployedContract:HelperContract"},
+			}
+			config.Fuzzing.TestLimit = 1_000
+			config.Fuzzing.Testing.ExcludeContracts = []string{"HelperContract"}
+			config.Fuzzing.Testing.StopOnNoTests = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Both contracts should be reported, sorted by name.
+			contractMetrics := f.fuzzer.metrics.ContractMetrics()
+			assert.Len(t, contractMetrics, 2)
+			helperMetric, testMetric := contractMetrics[0], contractMetrics[1]
+			assert.EqualValues(t, "HelperContract", helperMetric.Contract)
+			assert.EqualValues(t, "TestContract", testMetric.Contract)
+
+			// The excluded contract should have no methods to call, and should never have been called directly.
+			assert.EqualValues(t, 0, helperMetric.MethodsTotal)
+			assert.EqualValues(t, 0, helperMetric.MethodsCalled)
+			assert.EqualValues(t, 0, helperMetric.Calls)
+			assert.EqualValues(t, 0, helperMetric.CorpusSequences)
+
+			// Every call should have targeted the fuzzed contract, covering both of its methods, and every call
+			// sequence added to the corpus should have called it.
+			assert.EqualValues(t, 2, testMetric.MethodsTotal)
+			assert.EqualValues(t, 2, testMetric.MethodsCalled)
+			assert.EqualValues(t, f.fuzzer.metrics.CallsTested().Uint64(), testMetric.Calls)
+			assert.Greater(t, testMetric.CorpusSequences, uint64(0))
+		},
+	})
+}
+
+// TestStuckCampaignDetection runs a test to ensure campaigns in which every call reverts are reported as stuck, listing
+// the most reverting methods, and are stopped if configured to.
+func TestStuckCampaignDetection(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/metrics/always_reverting.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.TestLimit = 10_000_000
+			config.Fuzzing.StuckCampaignDetection.CallThreshold = 5_000
+			config.Fuzzing.StuckCampaignDetection.AbortCampaign = true
+			config.Fuzzing.Testing.StopOnNoTests = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Capture the fuzzer's logs, so we can verify the warning.
+			var logs strings.Builder
+			f.fuzzer.logger.AddWriter(&logs, logging.UNSTRUCTURED, false)
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The campaign should have been stopped as stuck, long before the test limit was reached.
+			assert.True(t, f.fuzzer.StuckCampaignAborted())
+			assert.Less(t, f.fuzzer.metrics.CallsTested().Uint64(), f.fuzzer.config.Fuzzing.TestLimit)
+
+			// The warning should name the reverting methods along with their revert classification.
+			assert.Contains(t, logs.String(), "Fuzzing campaign appears to be stuck")
+			assert.Contains(t, logs.String(), "TestContract.deposit(uint256): ")
+			assert.Contains(t, logs.String(), "TestContract.withdraw(): ")
+			assert.Contains(t, logs.String(), " require")
+		},
+	})
+}
+
+// TestOutOfGasCoverage ensures that lines only executed in calls which ran out of gas are classified as out-of-gas
+// coverage in the source analysis, rather than as successful or reverted coverage, and that such calls are counted as
+// out-of-gas failures.
+func TestOutOfGasCoverage(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/metrics/out_of_gas_loop.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.TestLimit = 1_000
+			config.Fuzzing.TransactionGasLimit = 1_000_000
+			config.Fuzzing.Testing.StopOnNoTests = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Calls which ran out of gas should be counted distinctly.
+			assert.Positive(t, f.fuzzer.metrics.RevertClassificationCounts()[RevertClassificationOutOfGas])
+
+			// Analyze our coverage.
+			sourceAnalysis, err := coverage.AnalyzeSourceCoverage(f.fuzzer.compilations, f.fuzzer.corpus.CoverageMaps())
+			assert.NoError(t, err)
+			findLine := func(contents string) *coverage.SourceLineAnalysis {
+				for _, file := range sourceAnalysis.Files {
+					for _, line := range file.Lines {
+						if strings.TrimSpace(string(line.Contents)) == contents {
+							return line
+						}
+					}
+				}
+				return nil
+			}
+
+			// The loop body is only executed in calls which ran out of gas, while the method which always succeeds
+			// is covered successfully.
+			outOfGasLine := findLine("total += i;")
+			assert.NotNil(t, outOfGasLine)
+			assert.True(t, outOfGasLine.IsCoveredOutOfGas)
+			assert.False(t, outOfGasLine.IsCovered)
+			assert.False(t, outOfGasLine.IsCoveredReverted)
+			assert.Positive(t, outOfGasLine.OutOfGasHitCount)
+			successfulLine := findLine("total += 1;")
+			assert.NotNil(t, successfulLine)
+			assert.True(t, successfulLine.IsCovered)
+			assert.False(t, successfulLine.IsCoveredOutOfGas)
+		},
+	})
+}
+
+// TestTestCaseDescriptions runs a test to ensure that test results are described using the NatSpec of their test
+// methods (including inherited ones), falling back to the method signature for undocumented methods.
+func TestTestCaseDescriptions(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/natspec/test_descriptions.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.TestLimit = 10_000
+			config.Fuzzing.ResultsPath = "results.json"
+			config.Fuzzing.SARIFPath = "results.sarif"
+			config.Fuzzing.Testing.StopOnFailedTest = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Each property should have failed, and be described by its NatSpec if it has one.
+			expectedDescriptions := map[string]string{
+				"Property Test: TestContract.property_documented()":   "Test for method \"TestContract.property_documented()\" (X never reaches the forbidden value) failed",
+				"Property Test: TestContract.property_inherited()":    "Test for method \"TestContract.property_inherited()\" (Inherited properties keep their description) failed",
+				"Property Test: TestContract.property_undocumented()": "Test for method \"TestContract.property_undocumented()\" failed",
+			}
+			failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+			assert.Len(t, failedTestCases, len(expectedDescriptions))
+			for _, testCase := range failedTestCases {
+				expectedDescription, ok := expectedDescriptions[testCase.Name()]
+				if assert.True(t, ok, "unexpected failed test case %v", testCase.Name()) {
+					assert.Contains(t, testCase.Message(), expectedDescription)
+				}
+			}
+
+			// The descriptions should also have been written to the results file and SARIF log, alongside the
+			// signatures.
+			expectedResultDescriptions := map[string]string{
+				"property_documented()":   "X never reaches the forbidden value",
+				"property_inherited()":    "Inherited properties keep their description",
+				"property_undocumented()": "property_undocumented()",
+			}
+			b, err := os.ReadFile("results.json")
+			assert.NoError(t, err)
+			var results CampaignResults
+			assert.NoError(t, json.Unmarshal(b, &results))
+			assert.Len(t, results.Tests, len(expectedResultDescriptions))
+			for _, testCase := range results.Tests {
+				assert.EqualValues(t, "TestContract", testCase.Contract)
+				assert.EqualValues(t, expectedResultDescriptions[testCase.Signature], testCase.Description)
+			}
+			b, err = os.ReadFile("results.sarif")
+			assert.NoError(t, err)
+			var sarifLog SARIFLog
+			assert.NoError(t, json.Unmarshal(b, &sarifLog))
+			assert.Len(t, sarifLog.Runs[0].Results, len(expectedResultDescriptions))
+			sarifDescriptions := make([]string, 0)
+			for _, rule := range sarifLog.Runs[0].Tool.Driver.Rules {
+				sarifDescriptions = append(sarifDescriptions, rule.ShortDescription.Text)
+			}
+			assert.Contains(t, sarifDescriptions, "X never reaches the forbidden value")
+			assert.Contains(t, sarifDescriptions, "Inherited properties keep their description")
+			assert.Contains(t, sarifDescriptions, "Property Test: TestContract.property_undocumented()")
+		},
+	})
+}
+
+// TestExecutionTraces runs tests to ensure that execution traces capture information
+// regarding assertion failures, revert reasons, etc.
+func TestExecutionTraces(t *testing.T) {
+	expectedMessagesPerTest := map[string][]string{
+		"testdata/contracts/execution_tracing/call_and_deployment_args.sol": {"Hello from deployment args!", "Hello from call args!"},
+		"testdata/contracts/execution_tracing/cheatcodes.sol":               {"StdCheats.toString(bool)(true)"},
+		"testdata/contracts/execution_tracing/event_emission.sol":           {"TestEvent", "TestIndexedEvent", "TestMixedEvent", "Hello from event args!", "Hello from library event args!"},
+		"testdata/contracts/execution_tracing/proxy_call.sol":               {"TestContract -> InnerDeploymentContract.setXY", "Hello from proxy call args!"},
+		"testdata/contracts/execution_tracing/revert_custom_error.sol":      {"CustomError", "Hello from a custom error!"},
+		"testdata/contracts/execution_tracing/revert_reasons.sol":           {"RevertingContract was called and reverted."},
+		"testdata/contracts/execution_tracing/self_destruct.sol":            {"[selfdestruct]", "[panic: assertion failed]"},
+	}
+	for filePath, expectedTraceMessages := range expectedMessagesPerTest {
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: filePath,
+			configUpdates: func(config *config.ProjectConfig) {
+				config.Fuzzing.TargetContracts = []string{"TestContract"}
+				config.Fuzzing.Testing.PropertyTesting.Enabled = false
+				config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+				config.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				// Start the fuzzer
+				err := f.fuzzer.Start()
+				assert.NoError(t, err)
+
+				// Check for failed assertion tests.
+				failedTestCase := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+				assert.NotEmpty(t, failedTestCase, "expected to have failed test cases")
+
+				// Obtain our first failed test case, get the message, and verify it contains our assertion failed.
+				failingSequence := *failedTestCase[0].CallSequence()
+				assert.NotEmpty(t, failingSequence, "expected to have calls in the call sequence failing an assertion test")
+
+				// Obtain the last call
+				lastCall := failingSequence[len(failingSequence)-1]
+				assert.NotNilf(t, lastCall.ExecutionTrace, "expected to have an execution trace attached to call sequence for this test")
+
+				// Get the execution trace message
+				executionTraceMsg := lastCall.ExecutionTrace.Log().String()
+
+				// Verify it contains all expected strings
+				for _, expectedTraceMessage := range expectedTraceMessages {
+					assert.Contains(t, executionTraceMsg, expectedTraceMessage)
+				}
+			},
+		})
+	}
+}
+
+// TestExecutionTraceLimits runs a test to ensure the execution traces attached to every call of a failing call sequence
+// are truncated at the configured depth limit when tracing a deeply recursive call, and are released once rendered.
+func TestExecutionTraceLimits(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/execution_tracing/deep_recursion.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.Testing.TraceAll = true
+			config.Fuzzing.Testing.TraceDepthLimit = 10
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check for failed assertion tests.
+			failedTestCase := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+			assert.NotEmpty(t, failedTestCase, "expected to have failed test cases")
+			failingSequence := *failedTestCase[0].CallSequence()
+			assert.NotEmpty(t, failingSequence, "expected to have calls in the call sequence failing an assertion test")
+
+			// Every call should have a rendered trace attached, with its call frames released.
+			for _, element := range failingSequence {
+				if assert.NotNil(t, element.ExecutionTrace) {
+					assert.Nil(t, element.ExecutionTrace.TopLevelCallFrame)
+				}
+			}
+
+			// The trace of the deep recursion should stop at the depth limit, marking the omitted call.
+			executionTraceMsg := failingSequence[len(failingSequence)-1].ExecutionTrace.Log().String()
+			assert.Contains(t, executionTraceMsg, "TestContract.deepRecursion()()")
+			assert.Contains(t, executionTraceMsg, "[truncated] 1 operation(s) omitted due to trace limits")
+			assert.Equal(t, 10, strings.Count(executionTraceMsg, "Recursed("))
+		},
+	})
+}
+
+// TestLabelCheatCode tests the vm.label cheatcode.
+func TestLabelCheatCode(t *testing.T) {
+	// These are the expected messages in the execution trace
+	expectedTraceMessages := []string{
+		"ProxyContract.testVMLabel()()",
+		"addr=ProxyContract [0xA647ff3c36cFab592509E13860ab8c4F28781a66]",
+		"sender=MySender [0x10000]",
+		"ProxyContract -> ImplementationContract.emitEvent(address)(ProxyContract [0xA647ff3c36cFab592509E13860ab8c4F28781a66])",
+		"code=ImplementationContract [0x54919A19522Ce7c842E25735a9cFEcef1c0a06dA]",
+		"[event] TestEvent(RandomAddress [0x20000])",
+		"[return (ProxyContract [0xA647ff3c36cFab592509E13860ab8c4F28781a66])]",
+	}
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/cheat_codes/utils/label.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			// Only allow for one sender for proper testing of this unit test
+			config.Fuzzing.SenderAddresses = []string{"0x10000"}
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
 			err := f.fuzzer.Start()
 			assert.NoError(t, err)
 
@@ -729,10 +2119,64 @@ func TestDeploymentsWithArgs(t *testing.T) {
 	})
 }
 
-// TestValueGenerationGenerateAllTypes runs a test to ensure various types of fuzzer inputs can be generated.
-func TestValueGenerationGenerateAllTypes(t *testing.T) {
+// TestDeploymentsWithSpecContract runs a test to ensure a spec contract is deployed after the target contracts, with
+// constructor arguments referencing them, and that its property tests are registered while its other methods are
+// never called and its address is not used as a value.
+func TestDeploymentsWithSpecContract(t *testing.T) {
 	runFuzzerTest(t, &fuzzerSolcFileTest{
-		filePath: "testdata/contracts/value_generation/generate_all_types.sol",
+		filePath: "testdata/contracts/deployments/spec_contract.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"SimpleToken"}
+			config.Fuzzing.Testing.SpecContracts = []string{"TokenSpec"}
+			config.Fuzzing.ConstructorArgs = map[string]map[string]any{
+				"TokenSpec": {
+					"_token": "DeployedContract:SimpleToken",
+				},
+			}
+			config.Fuzzing.Testing.StopOnFailedTest = false
+			config.Fuzzing.TestLimit = 10_000
+			config.Fuzzing.Testing.TestViewMethods = true
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Verify the spec contract's address is never added to a worker's value set.
+			f.fuzzer.Events.WorkerCreated.Subscribe(func(event FuzzerWorkerCreatedEvent) error {
+				event.Worker.Events.ContractAdded.Subscribe(func(event FuzzerWorkerContractAddedEvent) error {
+					if event.ContractDefinition != nil && event.ContractDefinition.Name() == "TokenSpec" {
+						assert.False(t, event.Worker.ValueSet().ContainsAddress(event.ContractAddress))
+					}
+					return nil
+				})
+				return nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Only the supply cap property should fail, as the spec contract's methods which are not tests are never
+			// called. Only its prefixed method should be an assertion test, alongside the target's assertion tests.
+			failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+			specTestCases := 0
+			for _, testCase := range f.fuzzer.TestCases() {
+				if strings.Contains(testCase.Name(), "TokenSpec.") {
+					assert.NotContains(t, testCase.Name(), "breakSpec")
+					specTestCases++
+				}
+			}
+			assert.EqualValues(t, 4, specTestCases)
+			if assert.Len(t, failedTestCases, 1) {
+				assert.Contains(t, failedTestCases[0].Name(), "TokenSpec.property_supply_is_capped()")
+			}
+		},
+	})
+}
+
+// TestValueGenerationGenerateAllTypes runs a test to ensure various types of fuzzer inputs can be generated.
+func TestValueGenerationGenerateAllTypes(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/value_generation/generate_all_types.sol",
 		configUpdates: func(config *config.ProjectConfig) {
 			config.Fuzzing.TargetContracts = []string{"GenerateAllTypes"}
 			config.Fuzzing.TestLimit = 10_000
@@ -752,6 +2196,117 @@ func TestValueGenerationGenerateAllTypes(t *testing.T) {
 	})
 }
 
+// TestCallSequenceReconstruction runs a test to ensure call sequences tested during fuzzing with a fixed seed can be
+// regenerated from their replay ID, with identical method selectors and arguments.
+func TestCallSequenceReconstruction(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/value_generation/generate_all_types.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"GenerateAllTypes"}
+			config.Fuzzing.Seed = 1234
+			config.Fuzzing.Workers = 2
+			config.Fuzzing.TestLimit = 1_000
+			config.Fuzzing.CallSequenceLength = 10
+			config.Fuzzing.CoverageEnabled = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Record the call data of the latest sequence tested by each worker.
+			var testedLock sync.Mutex
+			testedCallData := make(map[int][][]byte)
+			reconstructedCount := 0
+			f.fuzzer.Hooks.CallSequenceTestFuncs = append(f.fuzzer.Hooks.CallSequenceTestFuncs, func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+				callData := make([][]byte, len(callSequence))
+				for i, element := range callSequence {
+					callData[i] = slices.Clone(element.Call.Data)
+				}
+				testedLock.Lock()
+				testedCallData[worker.WorkerIndex()] = callData
+				testedLock.Unlock()
+				return nil, nil
+			})
+
+			// After each sequence is tested, regenerate it from its logged replay ID and compare the calls.
+			f.fuzzer.Events.WorkerCreated.Subscribe(func(event FuzzerWorkerCreatedEvent) error {
+				event.Worker.Events.CallSequenceTested.Subscribe(func(event FuzzerWorkerCallSequenceTestedEvent) error {
+					replayID, err := ParseReplayID(event.Worker.ReplayID().String())
+					assert.NoError(t, err)
+					assert.EqualValues(t, 1234, replayID.Seed)
+					reconstructedSequence, err := event.Worker.sequenceGenerator.ReconstructSequence(replayID)
+					assert.NoError(t, err)
+
+					// The tested sequence may have been cut short if fuzzing ended, so we compare its calls only.
+					testedLock.Lock()
+					defer testedLock.Unlock()
+					callData := testedCallData[event.Worker.WorkerIndex()]
+					assert.GreaterOrEqual(t, len(reconstructedSequence), len(callData))
+					for i := 0; i < len(callData) && i < len(reconstructedSequence); i++ {
+						assert.EqualValues(t, callData[i], reconstructedSequence[i].Call.Data)
+					}
+					reconstructedCount++
+					return nil
+				})
+				return nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Verify sequences were reconstructed
+			assert.Greater(t, reconstructedCount, 0)
+		},
+	})
+}
+
+// TestFuzzerWorkerEventsAggregated runs a test to ensure a single subscription to the Fuzzer's aggregate worker events
+// observes the events of every FuzzerWorker, including workers created to replace those which reached their reset limit.
+func TestFuzzerWorkerEventsAggregated(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/value_generation/generate_all_types.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"GenerateAllTypes"}
+			config.Fuzzing.Workers = 2
+			config.Fuzzing.WorkerResetLimit = 10
+			config.Fuzzing.TestLimit = 500
+			config.Fuzzing.CallSequenceLength = 5
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Record the workers which tested call sequences, and those which set up their chains.
+			var workersLock sync.Mutex
+			testedWorkers := make(map[*FuzzerWorker]struct{})
+			setupWorkers := make(map[*FuzzerWorker]struct{})
+			f.fuzzer.Events.WorkerChainSetup.Subscribe(func(event FuzzerWorkerChainSetupEvent) error {
+				workersLock.Lock()
+				defer workersLock.Unlock()
+				setupWorkers[event.Worker] = struct{}{}
+				return nil
+			})
+			f.fuzzer.Events.WorkerCallSequenceTested.Subscribe(func(event FuzzerWorkerCallSequenceTestedEvent) error {
+				workersLock.Lock()
+				defer workersLock.Unlock()
+				testedWorkers[event.Worker] = struct{}{}
+				return nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Workers should have been recycled, and the events of every worker should have been observed.
+			assert.Greater(t, len(testedWorkers), f.fuzzer.config.Fuzzing.Workers)
+			for worker := range testedWorkers {
+				assert.Contains(t, setupWorkers, worker)
+			}
+		},
+	})
+}
+
 // TestValueGenerationSolving runs a series of tests to test the value generator can solve expected problems.
 func TestValueGenerationSolving(t *testing.T) {
 	filePaths := []string{
@@ -786,6 +2341,288 @@ func TestValueGenerationSolving(t *testing.T) {
 	}
 }
 
+// TestValueGenerationCallTargets runs a test to ensure the value generator steers call targets toward deployed
+// contracts, and generates call data for their methods, so methods reachable only through an executor are covered.
+func TestValueGenerationCallTargets(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/value_generation/match_call_target.sol",
+		configUpdates: func(pkgConfig *config.ProjectConfig) {
+			pkgConfig.Fuzzing.TargetContracts = []string{"Executor", "TestContract"}
+			pkgConfig.Fuzzing.ConstructorArgs = map[string]map[string]any{
+				"TestContract": {
+					"_executor": "DeployedContract:Executor",
+				},
+			}
+			pkgConfig.Fuzzing.TestLimit = 10_000
+			pkgConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			pkgConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			pkgConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The property should fail, as the executor was called with our contract and call data for its method.
+			assertFailedTestsExpected(f, true)
+			assertCorpusCallSequencesCollected(f, true)
+		},
+	})
+}
+
+// TestValueGenerationFunctionReferences runs a test to ensure the value generator produces external function
+// references to deployed contract methods, so methods reachable only through a callback are covered.
+func TestValueGenerationFunctionReferences(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/value_generation/match_function_reference.sol",
+		configUpdates: func(pkgConfig *config.ProjectConfig) {
+			pkgConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+			pkgConfig.Fuzzing.TestLimit = 10_000
+			pkgConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			pkgConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			pkgConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The property should fail, as our method was invoked with a reference to the contract's own method.
+			assertFailedTestsExpected(f, true)
+		},
+	})
+}
+
+// TestValueGenerationChainContextValues runs a test to ensure the value generator produces arguments relative to the
+// chain's current timestamp when chain context values are enabled, and rarely does so otherwise, using the same seed.
+func TestValueGenerationChainContextValues(t *testing.T) {
+	for _, chainContextValues := range []bool{true, false} {
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: "testdata/contracts/value_generation/match_chain_timestamp.sol",
+			configUpdates: func(pkgConfig *config.ProjectConfig) {
+				pkgConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+				pkgConfig.Fuzzing.TestLimit = 5_000
+				pkgConfig.Fuzzing.Workers = 1
+				pkgConfig.Fuzzing.Seed = 1234
+				pkgConfig.Fuzzing.MaxBlockNumberDelay = 60
+				pkgConfig.Fuzzing.MaxBlockTimestampDelay = 60
+				pkgConfig.Fuzzing.ChainContextValues = chainContextValues
+				pkgConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+				pkgConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+				pkgConfig.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				// Start the fuzzer
+				err := f.fuzzer.Start()
+				assert.NoError(t, err)
+
+				// The property should only fail if arguments were generated relative to the current timestamp.
+				assertFailedTestsExpected(f, chainContextValues)
+			},
+		})
+	}
+}
+
+// TestValueGenerationOffByOneOperators runs a test to ensure the off-by-one and other value mutation operators reduce
+// the amount of calls needed to provide an argument which is exactly one greater than another, compared to only
+// applying arithmetic with values from the value set, using the same seeds.
+func TestValueGenerationOffByOneOperators(t *testing.T) {
+	defaultWeights := config.IntegerMutationWeightsConfig{Arithmetic: 50, BitFlip: 10, ByteSwap: 5, Endianness: 5, SignFlip: 10, OffByOne: 20}
+	arithmeticWeights := config.IntegerMutationWeightsConfig{Arithmetic: 1}
+
+	// Sum the amount of calls tested before the property failed across several seeds, for each set of weights.
+	callsToFailure := make(map[config.IntegerMutationWeightsConfig]uint64)
+	for _, weights := range []config.IntegerMutationWeightsConfig{defaultWeights, arithmeticWeights} {
+		for _, seed := range []int64{1234, 5678, 9012} {
+			runFuzzerTest(t, &fuzzerSolcFileTest{
+				filePath: "testdata/contracts/value_generation/match_off_by_one_xy.sol",
+				configUpdates: func(pkgConfig *config.ProjectConfig) {
+					pkgConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+					pkgConfig.Fuzzing.TestLimit = 100_000
+					pkgConfig.Fuzzing.Workers = 1
+					pkgConfig.Fuzzing.Seed = seed
+					pkgConfig.Fuzzing.ValueMutation.IntegerOperatorWeights = weights
+					pkgConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+					pkgConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+					pkgConfig.Slither.UseSlither = false
+				},
+				method: func(f *fuzzerTestContext) {
+					// Start the fuzzer
+					err := f.fuzzer.Start()
+					assert.NoError(t, err)
+
+					// The property should fail when the fuzzer stops, so the calls tested describe the time to cover it.
+					assertFailedTestsExpected(f, true)
+					callsToFailure[weights] += f.fuzzer.metrics.CallsTested().Uint64()
+				},
+			})
+		}
+	}
+	assert.Less(t, callsToFailure[defaultWeights], callsToFailure[arithmeticWeights])
+}
+
+// TestValueGenerationPreimageHashes runs a test to ensure the value generator produces bytes32 arguments which are
+// hashes of string constants in the source when preimage hashes are derived, so a role identifier can be provided to
+// reach a guarded path, and does not do so otherwise, using the same seed.
+func TestValueGenerationPreimageHashes(t *testing.T) {
+	for _, preimageHashesMax := range []int{1024, 0} {
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: "testdata/contracts/value_generation/match_role_hash.sol",
+			configUpdates: func(pkgConfig *config.ProjectConfig) {
+				pkgConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+				pkgConfig.Fuzzing.TestLimit = 5_000
+				pkgConfig.Fuzzing.Workers = 1
+				pkgConfig.Fuzzing.Seed = 1234
+				pkgConfig.Fuzzing.PreimageHashesMax = preimageHashesMax
+				pkgConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+				pkgConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+				pkgConfig.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				// Start the fuzzer
+				err := f.fuzzer.Start()
+				assert.NoError(t, err)
+
+				// The property should only fail if the hash of the role name was generated as an argument.
+				assertFailedTestsExpected(f, preimageHashesMax > 0)
+			},
+		})
+	}
+}
+
+// TestCalldataProbes runs a test to ensure calldata probes malform generated call data when enabled, so a contract
+// which reads past its declared arguments is caught, and that the probe is retained in the shrunken call sequence.
+func TestCalldataProbes(t *testing.T) {
+	for _, calldataProbeProbability := range []float32{0.2, 0} {
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: "testdata/contracts/value_generation/calldata_probe_trailing_data.sol",
+			configUpdates: func(pkgConfig *config.ProjectConfig) {
+				pkgConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+				pkgConfig.Fuzzing.TestLimit = 5_000
+				pkgConfig.Fuzzing.Workers = 1
+				pkgConfig.Fuzzing.Seed = 1234
+				pkgConfig.Fuzzing.CalldataProbeProbability = calldataProbeProbability
+				pkgConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+				pkgConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+				pkgConfig.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				// Start the fuzzer
+				err := f.fuzzer.Start()
+				assert.NoError(t, err)
+
+				// The property should only fail if call data was malformed.
+				assertFailedTestsExpected(f, calldataProbeProbability > 0)
+				if calldataProbeProbability == 0 {
+					return
+				}
+
+				// The last call of the shrunken call sequence must have retained its extended call data.
+				failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+				assert.NotEmpty(t, failedTestCases)
+				failingSequence := *failedTestCases[0].CallSequence()
+				assert.NotEmpty(t, failingSequence)
+				lastCall := failingSequence[len(failingSequence)-1]
+				assert.NotNil(t, lastCall.CalldataProbe)
+				assert.EqualValues(t, calls.CalldataProbeExtended, lastCall.CalldataProbe.Kind)
+			},
+		})
+	}
+}
+
+// TestOutputBindings runs a test to ensure arguments of generated calls are bound to the output of the prior call when
+// enabled, so a contract which requires an identifier returned by a prior call is exercised, and that the shrunken call
+// sequence still passes the returned identifier along.
+func TestOutputBindings(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/value_generation/output_binding_open_close.sol",
+		configUpdates: func(pkgConfig *config.ProjectConfig) {
+			pkgConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+			pkgConfig.Fuzzing.TestLimit = 5_000
+			pkgConfig.Fuzzing.Workers = 1
+			pkgConfig.Fuzzing.Seed = 1234
+			pkgConfig.Fuzzing.OutputBindingProbability = 0.5
+			pkgConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			pkgConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			pkgConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The property should fail, as a position can be closed with the identifier it was opened with.
+			assertFailedTestsExpected(f, true)
+			failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+			assert.NotEmpty(t, failedTestCases)
+
+			// The shrunken call sequence should open a position, then close it with the identifier returned.
+			failingSequence := *failedTestCases[0].CallSequence()
+			if assert.Len(t, failingSequence, 2) {
+				openReturnValues, err := failingSequence[0].DecodedReturnValues()
+				assert.NoError(t, err)
+				assert.EqualValues(t, "closePosition", failingSequence[1].Call.DataAbiValues.Method.Name)
+				assert.EqualValues(t, openReturnValues[0], failingSequence[1].Call.DataAbiValues.InputValues[0])
+			}
+		},
+	})
+}
+
+// TestSenderAccessDiscovery runs a test to ensure senders which are repeatedly rejected by a method's access control
+// are selected less often to call it, so the owner of a contract ends up issuing most calls to its owner-only methods.
+func TestSenderAccessDiscovery(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/value_generation/sender_access_ownable.sol",
+		configUpdates: func(pkgConfig *config.ProjectConfig) {
+			pkgConfig.Fuzzing.TargetContracts = []string{"TestContract"}
+			pkgConfig.Fuzzing.TestLimit = 10_000
+			pkgConfig.Fuzzing.Workers = 1
+			pkgConfig.Fuzzing.Seed = 1234
+			pkgConfig.Fuzzing.AccessControlRevertThreshold = 20
+			pkgConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			pkgConfig.Fuzzing.Testing.PropertyTesting.Enabled = false
+			pkgConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			pkgConfig.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The contract was deployed by the deployer, which is also a sender, so it is the owner.
+			owner := f.fuzzer.deployer
+			assert.Contains(t, f.fuzzer.senders, owner)
+
+			// Count the calls each sender made to each method, across every deployment of the contract.
+			callCounts := make(map[string]map[common.Address]uint64)
+			for key, senderStats := range f.fuzzer.senderAccess.stats {
+				if _, ok := callCounts[key.signature]; !ok {
+					callCounts[key.signature] = make(map[common.Address]uint64)
+				}
+				for sender := range senderStats {
+					callCount, _ := f.fuzzer.senderAccess.callCount(key, sender)
+					callCounts[key.signature][sender] += callCount
+				}
+			}
+
+			// The owner should issue most calls to the owner-only methods, whether they are rejected with a message or
+			// a custom error, while calls to the unrestricted method should remain spread across senders.
+			for _, signature := range []string{"setValue(uint256)", "setOtherValue(uint256)", "setPublicValue(uint256)"} {
+				var total uint64
+				for _, callCount := range callCounts[signature] {
+					total += callCount
+				}
+				assert.NotZero(t, total)
+				if signature == "setPublicValue(uint256)" {
+					assert.Less(t, callCounts[signature][owner]*2, total)
+				} else {
+					assert.Greater(t, callCounts[signature][owner]*2, total)
+				}
+			}
+		},
+	})
+}
+
 // TestASTValueExtraction runs a test to ensure appropriate AST values can be mined out of a compiled source's AST.
 func TestASTValueExtraction(t *testing.T) {
 	// Define our expected values to be mined.
@@ -857,16 +2694,695 @@ func TestASTValueExtraction(t *testing.T) {
 	})
 }
 
-// TestVMCorrectness runs tests to ensure block properties are reported consistently within the EVM, as it's configured
-// by the chain.TestChain.
-func TestVMCorrectness(t *testing.T) {
-	// Test block numbers work as expected.
+// TestVMCorrectness runs tests to ensure block properties are reported consistently within the EVM, as it's configured
+// by the chain.TestChain.
+func TestVMCorrectness(t *testing.T) {
+	// Test block numbers work as expected.
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/vm_tests/block_number_increasing.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.MaxBlockTimestampDelay = 1 // this contract require calls every block
+			config.Fuzzing.MaxBlockNumberDelay = 1    // this contract require calls every block
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check for any failed tests and verify coverage was captured
+			assertFailedTestsExpected(f, true)
+			assertCorpusCallSequencesCollected(f, true)
+		},
+	})
+
+	// Test timestamps behave as expected.
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/vm_tests/block_number_increasing.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.MaxBlockTimestampDelay = 1 // this contract require calls every block
+			config.Fuzzing.MaxBlockNumberDelay = 1    // this contract require calls every block
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check for any failed tests and verify coverage was captured
+			assertFailedTestsExpected(f, true)
+			assertCorpusCallSequencesCollected(f, true)
+		},
+	})
+
+	// Test block hashes are reported consistently.
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/vm_tests/block_hash_store_check.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.TestLimit = 1_000          // this test should expose a failure quickly.
+			config.Fuzzing.MaxBlockTimestampDelay = 1 // this contract require calls every block
+			config.Fuzzing.MaxBlockNumberDelay = 1    // this contract require calls every block
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check for any failed tests and verify coverage was captured
+			assertFailedTestsExpected(f, false)
+			assertCorpusCallSequencesCollected(f, true)
+		},
+	})
+}
+
+// TestCorpusReplayability will test whether the corpus, when replayed, will end up with the same coverage.
+// Additionally, check if the second run is solved with sequences executed being less or equal to the total corpus
+// call sequences. This should occur as the corpus call sequences should be executed unmodified first (including
+// the sequence which previously failed the on-chain test), prior to generating any new fuzzed sequences.
+func TestCorpusReplayability(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/value_generation/match_uints_xy.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.CorpusDirectory = "corpus"
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Setup checks for event emissions
+			expectEventEmitted(f, &f.fuzzer.Events.FuzzerStarting)
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Make sure we have some coverage
+			assertCorpusCallSequencesCollected(f, true)
+
+			// Cache current coverage maps
+			originalCoverage := f.fuzzer.corpus.CoverageMaps()
+			originalTotalCallSequences, originalTotalTestResults := f.fuzzer.corpus.CallSequenceEntryCount()
+			originalCorpusSequenceCount := originalTotalCallSequences + originalTotalTestResults
+
+			// Next, set the fuzzer worker count to one, this allows us to count the call sequences executed before
+			// solving a problem. We will verify the problem is solved with less or equal sequences tested, than
+			// corpus call sequence items (as the failing test corpus items should be replayed by the call sequence
+			// generator prior to it generating any new sequences).
+			f.fuzzer.config.Fuzzing.Workers = 1
+			err = f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Check to see if we have some coverage
+			assertCorpusCallSequencesCollected(f, true)
+			newCoverage := f.fuzzer.corpus.CoverageMaps()
+
+			// Check to see if original and new coverage are the same (disregarding hit count)
+			successCovIncreased, revertCovIncreased, err := originalCoverage.Update(newCoverage)
+			assert.False(t, successCovIncreased)
+			assert.False(t, revertCovIncreased)
+			assert.NoError(t, err)
+
+			successCovIncreased, revertCovIncreased, err = newCoverage.Update(originalCoverage)
+			assert.False(t, successCovIncreased)
+			assert.False(t, revertCovIncreased)
+			assert.NoError(t, err)
+
+			// Verify that the fuzzer finished after fewer sequences than there are in the corpus
+			assert.LessOrEqual(t, f.fuzzer.metrics.SequencesTested().Uint64(), uint64(originalCorpusSequenceCount))
+		},
+	})
+}
+
+// TestExplorationMode runs a test to ensure that when testing is disabled, the fuzzer builds a coverage-guided corpus
+// without evaluating any tests or producing any shrink requests.
+func TestExplorationMode(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/exploration/exploration_branches.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.TestLimit = 5_000
+			config.Fuzzing.Testing.Enabled = false
+			config.Fuzzing.Testing.StopOnFailedTest = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = true
+			config.Fuzzing.Testing.PropertyTesting.Enabled = true
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Register a test function which would request shrinking of every call sequence, if invoked.
+			var testFuncCallsLock sync.Mutex
+			testFuncCalls := 0
+			f.fuzzer.Hooks.CallSequenceTestFuncs = append(f.fuzzer.Hooks.CallSequenceTestFuncs, func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+				testFuncCallsLock.Lock()
+				defer testFuncCallsLock.Unlock()
+				testFuncCalls++
+				return []ShrinkCallSequenceRequest{{VerifierFunction: func(worker *FuzzerWorker, callSequence calls.CallSequence) (bool, error) {
+					return true, nil
+				}}}, nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// No tests should have been registered or evaluated, and nothing should have failed or been shrunk.
+			assert.Empty(t, f.fuzzer.TestCases())
+			assert.Zero(t, testFuncCalls)
+			assert.Zero(t, f.fuzzer.metrics.FailedSequences().Uint64())
+			assert.Zero(t, f.fuzzer.metrics.WorkersShrinkingCount())
+
+			// Our corpus should still have grown, covering multiple branches.
+			assertCorpusCallSequencesCollected(f, true)
+			assert.Greater(t, f.fuzzer.corpus.ActiveMutableSequenceCount(), 1)
+
+			// Exploration mode cannot be combined with stopping on failed tests.
+			f.fuzzer.config.Fuzzing.Testing.StopOnFailedTest = true
+			assert.Error(t, f.fuzzer.config.Validate())
+		},
+	})
+}
+
+// TestSequenceCompletedTestFuncs runs a test to ensure that sequence completed test functions are called once for each
+// call sequence executed to completion, and that their shrink requests are verified at the end of each shrunken
+// sequence.
+func TestSequenceCompletedTestFuncs(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/hooks/sequence_completed.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.Workers = 1
+			config.Fuzzing.TestLimit = 10_000
+			config.Fuzzing.CallSequenceLength = 10
+			config.Fuzzing.CoverageEnabled = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Track the calls executed and the sequence completed test function invocations for each sequence.
+			callsExecuted, sequenceCompletedCalls, sequencesCompleted := 0, 0, 0
+			f.fuzzer.Hooks.CallSequenceTestFuncs = append(f.fuzzer.Hooks.CallSequenceTestFuncs, func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+				callsExecuted++
+				return nil, nil
+			})
+
+			// Define an example provider which checks that the counter is below three at the end of a sequence.
+			counterBelowThree := func(worker *FuzzerWorker) bool {
+				for address, contract := range worker.deployedContracts {
+					if contract.Name() == "TestContract" {
+						return worker.chain.State().GetState(address, common.Hash{}).Big().Cmp(big.NewInt(3)) < 0
+					}
+				}
+				return true
+			}
+			failed := false
+			var shrunkenSequence calls.CallSequence
+			f.fuzzer.Hooks.SequenceCompletedTestFuncs = append(f.fuzzer.Hooks.SequenceCompletedTestFuncs, func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+				// The hook should be called after every call in the sequence was executed.
+				sequenceCompletedCalls++
+				assert.Len(t, callSequence, callsExecuted)
+				if failed || counterBelowThree(worker) {
+					return nil, nil
+				}
+
+				// Request the sequence be shrunk, re-running the check at the end of each shrunken sequence.
+				failed = true
+				return []ShrinkCallSequenceRequest{{
+					TestName:             "counter below three",
+					CallSequenceToShrink: callSequence,
+					VerifierFunction: func(worker *FuzzerWorker, callSequence calls.CallSequence) (bool, error) {
+						return !counterBelowThree(worker), nil
+					},
+					FinishedCallback: func(worker *FuzzerWorker, callSequence calls.CallSequence, verboseTracing bool) error {
+						shrunkenSequence = callSequence
+						worker.Fuzzer().Stop()
+						return nil
+					},
+				}}, nil
+			})
+
+			// Verify the hook was called once for each sequence executed to completion.
+			f.fuzzer.Events.WorkerCreated.Subscribe(func(event FuzzerWorkerCreatedEvent) error {
+				event.Worker.Events.CallSequenceTesting.Subscribe(func(event FuzzerWorkerCallSequenceTestingEvent) error {
+					callsExecuted, sequenceCompletedCalls = 0, 0
+					return nil
+				})
+				event.Worker.Events.CallSequenceTested.Subscribe(func(event FuzzerWorkerCallSequenceTestedEvent) error {
+					if callsExecuted == f.fuzzer.config.Fuzzing.CallSequenceLength {
+						assert.EqualValues(t, 1, sequenceCompletedCalls)
+						sequencesCompleted++
+					}
+					return nil
+				})
+				return nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The hook's shrink request should have been honored, shrinking the sequence to three increments.
+			assert.Greater(t, sequencesCompleted, 0)
+			assert.True(t, failed)
+			assert.Len(t, shrunkenSequence, 3)
+			for _, element := range shrunkenSequence {
+				assert.EqualValues(t, "increment", element.Call.DataAbiValues.Method.Name)
+			}
+		},
+	})
+}
+
+// TestPropertyTestsCheckedAtSequenceEnd runs a test to ensure that property tests can be checked only once each call
+// sequence has completed, and that a failing call sequence is shrunk by checking the property at the end of each
+// shrunken sequence.
+func TestPropertyTestsCheckedAtSequenceEnd(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/hooks/property_sequence_end.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.Workers = 1
+			config.Fuzzing.TestLimit = 10_000
+			config.Fuzzing.CallSequenceLength = 10
+			config.Fuzzing.Testing.PropertyTesting.CheckAtSequenceEnd = true
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The property test should have failed, with its call sequence shrunk to three increments.
+			assertFailedTestsExpected(f, true)
+			for _, testCase := range f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed) {
+				propertyTestCase, ok := testCase.(*PropertyTestCase)
+				assert.True(t, ok)
+				assert.Len(t, *propertyTestCase.CallSequence(), 3)
+				for _, element := range *propertyTestCase.CallSequence() {
+					assert.EqualValues(t, "increment", element.Call.DataAbiValues.Method.Name)
+				}
+			}
+		},
+	})
+}
+
+// TestPropertyTestsCheckedAtSequenceEndHooks tests that the property test provider checks property tests after every
+// call by default, and only once each call sequence has completed if configured to.
+func TestPropertyTestsCheckedAtSequenceEndHooks(t *testing.T) {
+	runHardhatProjectTest(t, func(_ *config.ProjectConfig) {
+		// Create a fuzzer with property testing only, obtaining the count of each kind of test function it adds.
+		countTestFuncs := func(checkAtSequenceEnd bool) (int, int) {
+			projectConfig := newHardhatProjectConfig(t)
+			projectConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			projectConfig.Fuzzing.Testing.PropertyTesting.CheckAtSequenceEnd = checkAtSequenceEnd
+			fuzzer, err := NewFuzzer(*projectConfig)
+			assert.NoError(t, err)
+			return len(fuzzer.Hooks.CallSequenceTestFuncs), len(fuzzer.Hooks.SequenceCompletedTestFuncs)
+		}
+		callSequenceTestFuncs, sequenceCompletedTestFuncs := countTestFuncs(false)
+		sequenceEndCallSequenceTestFuncs, sequenceEndSequenceCompletedTestFuncs := countTestFuncs(true)
+		assert.EqualValues(t, callSequenceTestFuncs-1, sequenceEndCallSequenceTestFuncs)
+		assert.EqualValues(t, sequenceCompletedTestFuncs+1, sequenceEndSequenceCompletedTestFuncs)
+	})
+}
+
+// TestShrinkingRetainsSetupCalls runs a test to ensure that shrinking never removes call sequence elements marked as
+// setup calls, while other calls are removed, and that the arguments of frozen setup calls are left unchanged.
+func TestShrinkingRetainsSetupCalls(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/hooks/setup_calls.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.Workers = 1
+			config.Fuzzing.TestLimit = 10_000
+			config.Fuzzing.CallSequenceLength = 10
+			config.Fuzzing.ShrinkLimit = 500
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Once a sequence of five calls was executed, mark its first call as a setup call, and its second as a
+			// frozen setup call, then request it be shrunk. Every shrunken sequence is accepted, so all other calls
+			// are removable noise.
+			requested := false
+			var originalSequence, shrunkenSequence calls.CallSequence
+			f.fuzzer.Hooks.CallSequenceTestFuncs = append(f.fuzzer.Hooks.CallSequenceTestFuncs, func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+				if requested || len(callSequence) < 5 {
+					return nil, nil
+				}
+				requested = true
+				sequence, err := callSequence.Clone()
+				if err != nil {
+					return nil, err
+				}
+				sequence[0].Setup = true
+				sequence[1].Setup, sequence[1].Frozen = true, true
+				originalSequence, err = sequence.Clone()
+				if err != nil {
+					return nil, err
+				}
+				return []ShrinkCallSequenceRequest{{
+					TestName:             "setup calls",
+					CallSequenceToShrink: sequence,
+					VerifierFunction: func(worker *FuzzerWorker, callSequence calls.CallSequence) (bool, error) {
+						return true, nil
+					},
+					FinishedCallback: func(worker *FuzzerWorker, callSequence calls.CallSequence, verboseTracing bool) error {
+						shrunkenSequence = callSequence
+						worker.Fuzzer().Stop()
+						return nil
+					},
+				}}, nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Only the setup calls should remain, with the frozen call unchanged.
+			assert.True(t, requested)
+			assert.Len(t, shrunkenSequence, 2)
+			assert.True(t, shrunkenSequence[0].Setup)
+			assert.False(t, shrunkenSequence[0].Frozen)
+			assert.EqualValues(t, originalSequence[0].Call.DataAbiValues.Method.Name, shrunkenSequence[0].Call.DataAbiValues.Method.Name)
+			assert.True(t, shrunkenSequence[1].Setup)
+			assert.True(t, shrunkenSequence[1].Frozen)
+			assert.EqualValues(t, originalSequence[1].Call.Data, shrunkenSequence[1].Call.Data)
+		},
+	})
+}
+
+// TestSetupCallDirectives runs a test to ensure that generated calls to methods annotated with the setup or frozen
+// NatSpec directives are marked as setup or frozen calls, while calls to other methods are not.
+func TestSetupCallDirectives(t *testing.T) {
+	runHardhatProjectTest(t, func(projectConfig *config.ProjectConfig) {
+		projectConfig.Fuzzing.Workers = 1
+		projectConfig.Fuzzing.TestLimit = 500
+		projectConfig.Fuzzing.Testing.StopOnNoTests = false
+		executeFuzzerTestMethodInternal(t, projectConfig, func(f *fuzzerTestContext) {
+			// Annotate the method of our first contract as a setup call, and that of our second as a frozen call, as
+			// their NatSpec directives would.
+			expectedFlags := map[string]bool{"FirstContract": false, "SecondContract": true}
+			for _, contract := range f.fuzzer.ContractDefinitions() {
+				frozen, ok := expectedFlags[contract.Name()]
+				if !ok {
+					continue
+				}
+				directive := fuzzerTypes.MethodDirectiveSetup
+				if frozen {
+					directive = fuzzerTypes.MethodDirectiveFrozen
+				}
+				selector := hex.EncodeToString(contract.CompiledContract().Abi.Methods["value"].ID)
+				contract.MethodDirectives = map[string][]string{selector: {directive}}
+			}
+
+			// Check the flags of every call executed.
+			callsChecked := 0
+			f.fuzzer.Hooks.CallSequenceTestFuncs = append(f.fuzzer.Hooks.CallSequenceTestFuncs, func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+				element := callSequence[len(callSequence)-1]
+				frozen, ok := expectedFlags[element.Contract.Name()]
+				assert.True(t, ok)
+				assert.True(t, element.Setup)
+				assert.EqualValues(t, frozen, element.Frozen)
+				callsChecked++
+				return nil, nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+			assert.Positive(t, callsChecked)
+		})
+	})
+}
+
+// TestConfirmFailures runs a test to ensure that when failure confirmation is enabled, a failure is replayed before it
+// is shrunk, and one which does not reproduce is discarded, or still reported if configured to.
+func TestConfirmFailures(t *testing.T) {
+	tests := []struct {
+		confirmFailures           bool
+		reportUnconfirmedFailures bool
+		reproducible              bool
+		expectReported            bool
+	}{
+		{confirmFailures: false, reproducible: false, expectReported: true},
+		{confirmFailures: true, reproducible: true, expectReported: true},
+		{confirmFailures: true, reproducible: false, expectReported: false},
+		{confirmFailures: true, reportUnconfirmedFailures: true, reproducible: false, expectReported: true},
+	}
+	for _, test := range tests {
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: "testdata/contracts/hooks/sequence_completed.sol",
+			configUpdates: func(config *config.ProjectConfig) {
+				config.Fuzzing.TargetContracts = []string{"TestContract"}
+				config.Fuzzing.Workers = 1
+				config.Fuzzing.TestLimit = 1_000
+				config.Fuzzing.ShrinkLimit = 10
+				config.Fuzzing.Testing.StopOnFailedTest = false
+				config.Fuzzing.Testing.ConfirmFailures = test.confirmFailures
+				config.Fuzzing.Testing.ReportUnconfirmedFailures = test.reportUnconfirmedFailures
+				config.Fuzzing.Testing.AssertionTesting.Enabled = false
+				config.Fuzzing.Testing.PropertyTesting.Enabled = false
+				config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+				config.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				// Request a single call sequence be shrunk, using a verifier which, unless the failure is reproducible,
+				// rejects the first call sequence it verifies, then accepts every other. The first verification is the
+				// confirmation of the failure, if confirmation is enabled.
+				requested := false
+				verifications, reports := 0, 0
+				f.fuzzer.Hooks.CallSequenceTestFuncs = append(f.fuzzer.Hooks.CallSequenceTestFuncs, func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+					if requested {
+						return nil, nil
+					}
+					requested = true
+					return []ShrinkCallSequenceRequest{{
+						TestName:             "nondeterministic failure",
+						CallSequenceToShrink: callSequence,
+						VerifierFunction: func(worker *FuzzerWorker, callSequence calls.CallSequence) (bool, error) {
+							verifications++
+							return test.reproducible || verifications > 1, nil
+						},
+						FinishedCallback: func(worker *FuzzerWorker, callSequence calls.CallSequence, verboseTracing bool) error {
+							reports++
+							return nil
+						},
+					}}, nil
+				})
+
+				// Start the fuzzer
+				err := f.fuzzer.Start()
+				assert.NoError(t, err)
+
+				// The failure should have been reported only if expected. If it was discarded, the failure should only
+				// have been verified once, when it was confirmed.
+				assert.True(t, requested)
+				if test.expectReported {
+					assert.EqualValues(t, 1, reports)
+					assert.Greater(t, verifications, 1)
+				} else {
+					assert.EqualValues(t, 0, reports)
+					assert.EqualValues(t, 1, verifications)
+				}
+			},
+		})
+	}
+}
+
+// TestWorkerWatchdog tests that a worker which stalls in an event handler is detected by the worker watchdog, and is
+// abandoned and recreated in its slot while the rest of the campaign continues.
+func TestWorkerWatchdog(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/hooks/sequence_completed.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.Workers = 2
+			config.Fuzzing.Timeout = 5
+			config.Fuzzing.TestLimit = 0
+			config.Fuzzing.WorkerWatchdog.StallTimeout = 1
+			config.Fuzzing.WorkerWatchdog.RecreateWorkers = true
+			config.Fuzzing.Testing.StopOnFailedTest = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Block the first worker created in the first call sequence it tests, until the test completes. Track the
+			// workers created and destroyed at its index.
+			unblock := make(chan struct{})
+			defer close(unblock)
+			var lock sync.Mutex
+			var stalledWorker *FuzzerWorker
+			createdAtIndex, stalledWorkerDestroyed := 0, false
+			f.fuzzer.Events.WorkerCreated.Subscribe(func(event FuzzerWorkerCreatedEvent) error {
+				lock.Lock()
+				defer lock.Unlock()
+				if stalledWorker == nil {
+					stalledWorker = event.Worker
+					event.Worker.Events.CallSequenceTesting.Subscribe(func(event FuzzerWorkerCallSequenceTestingEvent) error {
+						<-unblock
+						return nil
+					})
+				}
+				if event.Worker.WorkerIndex() == stalledWorker.WorkerIndex() {
+					createdAtIndex++
+				}
+				return nil
+			})
+			f.fuzzer.Events.WorkerDestroyed.Subscribe(func(event FuzzerWorkerDestroyedEvent) error {
+				lock.Lock()
+				defer lock.Unlock()
+				stalledWorkerDestroyed = stalledWorkerDestroyed || event.Worker == stalledWorker
+				return nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The stalled worker should have been abandoned and destroyed, and a new worker created in its slot.
+			lock.Lock()
+			defer lock.Unlock()
+			assert.NotNil(t, stalledWorker)
+			assert.True(t, stalledWorker.abandoned.Load())
+			assert.True(t, stalledWorkerDestroyed)
+			assert.Greater(t, createdAtIndex, 1)
+		},
+	})
+}
+
+// TestCorpusReplayBudget runs a test to ensure that corpus replay can be excluded from the test limit or bounded by its
+// own limit, so new call sequences are generated even when the test limit is smaller than the corpus.
+func TestCorpusReplayBudget(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/value_generation/generate_all_types.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"GenerateAllTypes"}
+			config.Fuzzing.CorpusDirectory = "corpus"
+			config.Fuzzing.TestLimit = 5_000
+			config.Fuzzing.CallSequenceLength = 10
+			config.Fuzzing.Testing.StopOnNoTests = false
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer to populate our corpus, which no calls should be replayed from yet.
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+			assertCorpusCallSequencesCollected(f, true)
+			assert.Zero(t, f.fuzzer.metrics.CallsReplayed().Uint64())
+			corpusSequenceCount, _ := f.fuzzer.corpus.CallSequenceEntryCount()
+
+			// Use a test limit smaller than our corpus, excluding replayed calls from it. The whole corpus should be
+			// replayed, with the test limit being reached by generated calls alone.
+			f.fuzzer.config.Fuzzing.TestLimit = 10
+			f.fuzzer.config.Fuzzing.CorpusReplayCountsTowardTestLimit = false
+			err = f.fuzzer.Start()
+			assert.NoError(t, err)
+			assert.EqualValues(t, corpusSequenceCount, f.fuzzer.metrics.SequencesReplayed().Uint64())
+			assert.Greater(t, f.fuzzer.metrics.CallsReplayed().Uint64(), f.fuzzer.config.Fuzzing.TestLimit)
+			assert.GreaterOrEqual(t, f.fuzzer.metrics.CallsGenerated().Uint64(), f.fuzzer.config.Fuzzing.TestLimit)
+			assert.Zero(t, f.fuzzer.corpus.DeferredCallSequenceCount())
+
+			// Count replayed calls toward the test limit again, but bound the replay by its own limit. Replay should
+			// stop once the limit is reached (exceeding it by at most one sequence), deferring the remaining corpus
+			// and generating new sequences.
+			f.fuzzer.config.Fuzzing.CorpusReplayCountsTowardTestLimit = true
+			f.fuzzer.config.Fuzzing.CorpusReplayLimit = 5
+			err = f.fuzzer.Start()
+			assert.NoError(t, err)
+			assert.LessOrEqual(t, f.fuzzer.metrics.CallsReplayed().Uint64(), f.fuzzer.config.Fuzzing.CorpusReplayLimit+uint64(f.fuzzer.config.Fuzzing.CallSequenceLength))
+			assert.Greater(t, f.fuzzer.corpus.DeferredCallSequenceCount(), 0)
+			assert.Greater(t, f.fuzzer.metrics.CallsGenerated().Uint64(), uint64(0))
+		},
+	})
+}
+
+// TestCorpusCoverageDelta runs a test to ensure that call sequences added to the corpus record the new coverage they
+// achieved, both in their corpus metadata and in the worker events emitted for them.
+func TestCorpusCoverageDelta(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/value_generation/match_uints_xy.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.CorpusDirectory = "corpus"
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Record every new coverage event emitted by our workers.
+			newCoverageEvents := make([]FuzzerWorkerNewCoverageEvent, 0)
+			newCoverageEventsLock := sync.Mutex{}
+			f.fuzzer.Events.WorkerCreated.Subscribe(func(event FuzzerWorkerCreatedEvent) error {
+				event.Worker.Events.NewCoverage.Subscribe(func(event FuzzerWorkerNewCoverageEvent) error {
+					newCoverageEventsLock.Lock()
+					defer newCoverageEventsLock.Unlock()
+					newCoverageEvents = append(newCoverageEvents, event)
+					return nil
+				})
+				return nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+			assertCorpusCallSequencesCollected(f, true)
+
+			// Every corpus entry should have been reported by exactly one event, with a non-empty delta.
+			callSequenceCount, _ := f.fuzzer.corpus.CallSequenceEntryCount()
+			assert.Len(t, newCoverageEvents, callSequenceCount)
+			for _, event := range newCoverageEvents {
+				assert.False(t, event.CoverageDelta.Empty())
+				assert.NotEmpty(t, event.CallSequence)
+			}
+
+			// Every corpus entry should have metadata describing its non-empty delta, resolved to our contract. Some
+			// new coverage (e.g. in compiler-generated code) may not map to source lines, but some entries must.
+			metadata := f.fuzzer.corpus.CallSequenceMetadata()
+			assert.Len(t, metadata, callSequenceCount)
+			sourceLinesResolved := false
+			for _, entryMetadata := range metadata {
+				assert.False(t, entryMetadata.CoverageDelta.Empty())
+				resolved := false
+				for _, contractDelta := range entryMetadata.CoverageDelta.Contracts {
+					resolved = resolved || contractDelta.ContractName == "TestContract"
+				}
+				assert.True(t, resolved)
+
+				sourceLines, err := entryMetadata.CoverageDelta.FormatSourceLines(f.fuzzer.compilations)
+				assert.NoError(t, err)
+				sourceLinesResolved = sourceLinesResolved || sourceLines != ""
+			}
+			assert.True(t, sourceLinesResolved)
+		},
+	})
+}
+
+// TestFailureDeduplication ensures that a property violated through many distinct call sequences, across many
+// workers, is only shrunk once when failure deduplication is enabled.
+func TestFailureDeduplication(t *testing.T) {
 	runFuzzerTest(t, &fuzzerSolcFileTest{
-		filePath: "testdata/contracts/vm_tests/block_number_increasing.sol",
+		filePath: "testdata/contracts/assertions/property_violated_twice.sol",
 		configUpdates: func(config *config.ProjectConfig) {
 			config.Fuzzing.TargetContracts = []string{"TestContract"}
-			config.Fuzzing.MaxBlockTimestampDelay = 1 // this contract require calls every block
-			config.Fuzzing.MaxBlockNumberDelay = 1    // this contract require calls every block
+			config.Fuzzing.Workers = 10
+			config.Fuzzing.TestLimit = 10_000
+			config.Fuzzing.Testing.StopOnFailedTest = false
+			config.Fuzzing.Testing.DeduplicateFailures = true
 			config.Fuzzing.Testing.AssertionTesting.Enabled = false
 			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
 			config.Slither.UseSlither = false
@@ -876,108 +3392,195 @@ func TestVMCorrectness(t *testing.T) {
 			err := f.fuzzer.Start()
 			assert.NoError(t, err)
 
-			// Check for any failed tests and verify coverage was captured
-			assertFailedTestsExpected(f, true)
-			assertCorpusCallSequencesCollected(f, true)
+			// The property should have failed, and been discovered at least once.
+			failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+			assert.Len(t, failedTestCases, 1)
+			assert.Positive(t, f.fuzzer.failures.discoveryCount(failedTestCases[0].ID()))
+
+			// Every discovery after the first should have been counted rather than shrunk, so the failure was only
+			// shrunk once.
+			assert.EqualValues(t, 1, f.fuzzer.metrics.FailedSequences().Uint64())
 		},
 	})
+}
 
-	// Test timestamps behave as expected.
+// TestTestFailureResolvedEvents runs a multi-worker campaign with a known failing property, ensuring a single event is
+// emitted for the deduplicated failure, which describes the shrunken call sequence recorded in the corpus.
+func TestTestFailureResolvedEvents(t *testing.T) {
 	runFuzzerTest(t, &fuzzerSolcFileTest{
-		filePath: "testdata/contracts/vm_tests/block_number_increasing.sol",
+		filePath: "testdata/contracts/assertions/property_violated_twice.sol",
 		configUpdates: func(config *config.ProjectConfig) {
 			config.Fuzzing.TargetContracts = []string{"TestContract"}
-			config.Fuzzing.MaxBlockTimestampDelay = 1 // this contract require calls every block
-			config.Fuzzing.MaxBlockNumberDelay = 1    // this contract require calls every block
+			config.Fuzzing.Workers = 4
+			config.Fuzzing.TestLimit = 10_000
+			config.Fuzzing.CorpusDirectory = "corpus"
+			config.Fuzzing.Testing.StopOnFailedTest = false
+			config.Fuzzing.Testing.DeduplicateFailures = true
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
 		},
 		method: func(f *fuzzerTestContext) {
+			// Record every test failure resolved event.
+			var resolvedEventsLock sync.Mutex
+			resolvedEvents := make([]FuzzerTestFailureResolvedEvent, 0)
+			f.fuzzer.Events.TestFailureResolved.Subscribe(func(event FuzzerTestFailureResolvedEvent) error {
+				resolvedEventsLock.Lock()
+				defer resolvedEventsLock.Unlock()
+				resolvedEvents = append(resolvedEvents, event)
+				return nil
+			})
+
 			// Start the fuzzer
 			err := f.fuzzer.Start()
 			assert.NoError(t, err)
 
-			// Check for any failed tests and verify coverage was captured
-			assertFailedTestsExpected(f, true)
-			assertCorpusCallSequencesCollected(f, true)
+			// The property should have failed, and been resolved exactly once.
+			failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+			assert.Len(t, failedTestCases, 1)
+			assert.Len(t, resolvedEvents, 1)
+			event := resolvedEvents[0]
+			assert.EqualValues(t, failedTestCases[0].ID(), event.FailureID)
+			assert.NotEmpty(t, event.CallSequence)
+
+			// The event should describe the call sequence reported by the test case.
+			eventSequenceHash, err := event.CallSequence.Hash()
+			assert.NoError(t, err)
+			testCaseSequenceHash, err := failedTestCases[0].CallSequence().Hash()
+			assert.NoError(t, err)
+			assert.EqualValues(t, testCaseSequenceHash, eventSequenceHash)
+
+			// The reproducer written to the corpus should record the same call sequence.
+			assert.NotEmpty(t, event.ReproducerPath)
+			reproducerData, err := os.ReadFile(event.ReproducerPath)
+			assert.NoError(t, err)
+			var reproducerSequence calls.CallSequence
+			err = json.Unmarshal(reproducerData, &reproducerSequence)
+			assert.NoError(t, err)
+			reproducerSequenceHash, err := reproducerSequence.Hash()
+			assert.NoError(t, err)
+			assert.EqualValues(t, eventSequenceHash, reproducerSequenceHash)
 		},
 	})
+}
 
-	// Test block hashes are reported consistently.
+// TestDeterministicFuzzing runs the same multi-worker campaign twice while fuzzing deterministically, ensuring both
+// runs add the same call sequences to the corpus and find the same failures.
+func TestDeterministicFuzzing(t *testing.T) {
 	runFuzzerTest(t, &fuzzerSolcFileTest{
-		filePath: "testdata/contracts/vm_tests/block_hash_store_check.sol",
+		filePath: "testdata/contracts/value_generation/match_uints_xy.sol",
 		configUpdates: func(config *config.ProjectConfig) {
 			config.Fuzzing.TargetContracts = []string{"TestContract"}
-			config.Fuzzing.TestLimit = 1_000          // this test should expose a failure quickly.
-			config.Fuzzing.MaxBlockTimestampDelay = 1 // this contract require calls every block
-			config.Fuzzing.MaxBlockNumberDelay = 1    // this contract require calls every block
+			config.Fuzzing.Workers = 2
+			config.Fuzzing.Seed = 1234
+			config.Fuzzing.TestLimit = 10_000
+			config.Fuzzing.Deterministic = true
+			config.Fuzzing.Testing.StopOnFailedTest = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
 			config.Slither.UseSlither = false
 		},
 		method: func(f *fuzzerTestContext) {
-			// Start the fuzzer
-			err := f.fuzzer.Start()
-			assert.NoError(t, err)
+			// Record the hash of every call sequence workers add to the corpus.
+			corpusHashes := make(map[common.Hash]struct{})
+			corpusHashesLock := sync.Mutex{}
+			f.fuzzer.Events.WorkerCreated.Subscribe(func(event FuzzerWorkerCreatedEvent) error {
+				event.Worker.Events.NewCoverage.Subscribe(func(event FuzzerWorkerNewCoverageEvent) error {
+					hash, err := event.CallSequence.Hash()
+					if err != nil {
+						return err
+					}
+					corpusHashesLock.Lock()
+					defer corpusHashesLock.Unlock()
+					corpusHashes[hash] = struct{}{}
+					return nil
+				})
+				return nil
+			})
 
-			// Check for any failed tests and verify coverage was captured
-			assertFailedTestsExpected(f, false)
-			assertCorpusCallSequencesCollected(f, true)
+			// Run the campaign twice, recording the call sequences added to the corpus and the failures found.
+			runCorpusHashes := make([]map[common.Hash]struct{}, 0)
+			runCorpusCounts := make([]int, 0)
+			runFailures := make([][]string, 0)
+			for i := 0; i < 2; i++ {
+				corpusHashes = make(map[common.Hash]struct{})
+				err := f.fuzzer.Start()
+				assert.NoError(t, err)
+				assertCorpusCallSequencesCollected(f, true)
+
+				callSequenceCount, _ := f.fuzzer.corpus.CallSequenceEntryCount()
+				failures := make([]string, 0)
+				for _, testCase := range f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed) {
+					failures = append(failures, testCase.ID())
+				}
+				slices.Sort(failures)
+				runCorpusHashes = append(runCorpusHashes, corpusHashes)
+				runCorpusCounts = append(runCorpusCounts, callSequenceCount)
+				runFailures = append(runFailures, failures)
+			}
+
+			// Both runs should have produced the same corpus and failures.
+			assert.EqualValues(t, runCorpusCounts[0], runCorpusCounts[1])
+			assert.EqualValues(t, runCorpusHashes[0], runCorpusHashes[1])
+			assert.EqualValues(t, runFailures[0], runFailures[1])
 		},
 	})
 }
 
-// TestCorpusReplayability will test whether the corpus, when replayed, will end up with the same coverage.
-// Additionally, check if the second run is solved with sequences executed being less or equal to the total corpus
-// call sequences. This should occur as the corpus call sequences should be executed unmodified first (including
-// the sequence which previously failed the on-chain test), prior to generating any new fuzzed sequences.
-func TestCorpusReplayability(t *testing.T) {
+// TestSetupOnlyCoverage ensures that lines only covered while deploying contracts, such as a branch in a constructor
+// the fuzzer can never re-trigger, are classified as setup-only in the source analysis, and that setup-only lines can
+// be excluded from covered line counts.
+func TestSetupOnlyCoverage(t *testing.T) {
 	runFuzzerTest(t, &fuzzerSolcFileTest{
-		filePath: "testdata/contracts/value_generation/match_uints_xy.sol",
+		filePath: "testdata/contracts/deployments/setup_only_coverage.sol",
 		configUpdates: func(config *config.ProjectConfig) {
 			config.Fuzzing.TargetContracts = []string{"TestContract"}
-			config.Fuzzing.CorpusDirectory = "corpus"
+			config.Fuzzing.TestLimit = 1_000
 			config.Fuzzing.Testing.AssertionTesting.Enabled = false
 			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
 			config.Slither.UseSlither = false
 		},
 		method: func(f *fuzzerTestContext) {
-			// Setup checks for event emissions
-			expectEventEmitted(f, &f.fuzzer.Events.FuzzerStarting)
-
 			// Start the fuzzer
 			err := f.fuzzer.Start()
 			assert.NoError(t, err)
 
-			// Make sure we have some coverage
-			assertCorpusCallSequencesCollected(f, true)
-
-			// Cache current coverage maps
-			originalCoverage := f.fuzzer.corpus.CoverageMaps()
-			originalTotalCallSequences, originalTotalTestResults := f.fuzzer.corpus.CallSequenceEntryCount()
-			originalCorpusSequenceCount := originalTotalCallSequences + originalTotalTestResults
-
-			// Next, set the fuzzer worker count to one, this allows us to count the call sequences executed before
-			// solving a problem. We will verify the problem is solved with less or equal sequences tested, than
-			// corpus call sequence items (as the failing test corpus items should be replayed by the call sequence
-			// generator prior to it generating any new sequences).
-			f.fuzzer.config.Fuzzing.Workers = 1
-			err = f.fuzzer.Start()
+			// Analyze our coverage, marking setup-only lines.
+			sourceAnalysis, err := coverage.AnalyzeSourceCoverageWithSetup(f.fuzzer.compilations, f.fuzzer.corpus.CoverageMaps(), f.fuzzer.corpus.FuzzingCoverageMaps(), false)
 			assert.NoError(t, err)
+			findLine := func(contents string) *coverage.SourceLineAnalysis {
+				for _, file := range sourceAnalysis.Files {
+					for _, line := range file.Lines {
+						if strings.TrimSpace(string(line.Contents)) == contents {
+							return line
+						}
+					}
+				}
+				return nil
+			}
 
-			// Check to see if we have some coverage
-			assertCorpusCallSequencesCollected(f, true)
-			newCoverage := f.fuzzer.corpus.CoverageMaps()
-
-			// Check to see if original and new coverage are the same (disregarding hit count)
-			successCovIncreased, revertCovIncreased, err := originalCoverage.Update(newCoverage)
-			assert.False(t, successCovIncreased)
-			assert.False(t, revertCovIncreased)
-			assert.NoError(t, err)
+			// The branch taken only in the constructor is covered during setup only, while the branch condition and
+			// the method called by the fuzzer are covered while fuzzing.
+			setupOnlyLine := findLine("value = 42;")
+			assert.NotNil(t, setupOnlyLine)
+			assert.True(t, setupOnlyLine.IsCovered)
+			assert.True(t, setupOnlyLine.IsCoveredSetupOnly)
+			for _, contents := range []string{"if (!initialized) {", "value = newValue + 1;"} {
+				fuzzedLine := findLine(contents)
+				assert.NotNil(t, fuzzedLine)
+				assert.True(t, fuzzedLine.IsCovered)
+				assert.False(t, fuzzedLine.IsCoveredSetupOnly)
+			}
 
-			successCovIncreased, revertCovIncreased, err = newCoverage.Update(originalCoverage)
-			assert.False(t, successCovIncreased)
-			assert.False(t, revertCovIncreased)
+			// If setup-only lines are excluded, they no longer count toward covered lines.
+			coveredLineCount := sourceAnalysis.CoveredLineCount()
+			sourceAnalysis, err = coverage.AnalyzeSourceCoverageWithSetup(f.fuzzer.compilations, f.fuzzer.corpus.CoverageMaps(), f.fuzzer.corpus.FuzzingCoverageMaps(), true)
 			assert.NoError(t, err)
-
-			// Verify that the fuzzer finished after fewer sequences than there are in the corpus
-			assert.LessOrEqual(t, f.fuzzer.metrics.SequencesTested().Uint64(), uint64(originalCorpusSequenceCount))
+			setupOnlyLineCount := 0
+			for _, file := range sourceAnalysis.Files {
+				setupOnlyLineCount += file.SetupOnlyLineCount()
+			}
+			assert.Positive(t, setupOnlyLineCount)
+			assert.EqualValues(t, coveredLineCount-setupOnlyLineCount, sourceAnalysis.CoveredLineCount())
 		},
 	})
 }
@@ -1073,3 +3676,420 @@ func TestExcludeFunctionSignatures(t *testing.T) {
 			}
 		}})
 }
+
+// TestIgnoreMethodDirectives tests that methods annotated with the `@custom:medusa ignore` NatSpec directive are never
+// called directly, while remaining callable by other methods.
+func TestIgnoreMethodDirectives(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/filtering/ignore_directives.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.TestLimit = 10_000
+			config.Fuzzing.Testing.StopOnFailedTest = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// The ignored methods should not be available for assertion testing.
+			for _, contract := range f.fuzzer.ContractDefinitions() {
+				for _, method := range contract.AssertionTestMethods {
+					assert.NotContains(t, []string{"setValue", "reset"}, method.Name)
+				}
+			}
+
+			// Record any call sequence elements which directly call an ignored method
+			var ignoredCallsLock sync.Mutex
+			ignoredCalls := 0
+			f.fuzzer.Hooks.CallSequenceTestFuncs = append(f.fuzzer.Hooks.CallSequenceTestFuncs, func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+				ignoredCallsLock.Lock()
+				defer ignoredCallsLock.Unlock()
+				for _, element := range callSequence {
+					if method, err := element.Method(); err == nil && method != nil && (method.Name == "setValue" || method.Name == "reset") {
+						ignoredCalls++
+					}
+				}
+				return nil, nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The ignored methods should never have been called directly, but the assertion in the ignored method
+			// should still be reached through the method calling it.
+			assert.EqualValues(t, 0, ignoredCalls)
+			assertFailedTestsExpected(f, true)
+		}})
+}
+
+// TestTargetMethodDirectives tests that annotating methods with the `@custom:medusa target` NatSpec directive restricts
+// the methods called directly to them, and that configured target function signatures take precedence.
+func TestTargetMethodDirectives(t *testing.T) {
+	for _, configuredTargets := range [][]string{nil, {"TestContract.g()"}} {
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: "testdata/contracts/filtering/target_directives.sol",
+			configUpdates: func(config *config.ProjectConfig) {
+				config.Fuzzing.TargetContracts = []string{"TestContract"}
+				config.Fuzzing.Testing.TargetFunctionSignatures = configuredTargets
+				config.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				expectedMethods := []string{"f", "h"}
+				if len(configuredTargets) > 0 {
+					expectedMethods = []string{"g"}
+				}
+				for _, contract := range f.fuzzer.ContractDefinitions() {
+					var methodNames []string
+					for _, method := range contract.AssertionTestMethods {
+						methodNames = append(methodNames, method.Name)
+					}
+					assert.ElementsMatch(t, expectedMethods, methodNames)
+					assert.Len(t, contract.PropertyTestMethods, 1)
+				}
+			}})
+	}
+}
+
+// TestExcludeContracts tests whether excluded contracts are deployed but never called directly nor tested
+func TestExcludeContracts(t *testing.T) {
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/filtering/exclude_contracts.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"MockOracle", "TestContract"}
+			config.Fuzzing.ConstructorArgs = map[string]map[string]any{
+				"TestContract": {"_oracle": "DeployedContract:MockOracle"},
+			}
+			config.Fuzzing.TestLimit = 1_000
+			config.Fuzzing.Testing.ExcludeContracts = []string{"MockOracle"}
+			config.Fuzzing.Testing.AssertionTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Record any call sequence elements which directly target the excluded contract
+			var excludedCallsLock sync.Mutex
+			excludedCalls := 0
+			f.fuzzer.Hooks.CallSequenceTestFuncs = append(f.fuzzer.Hooks.CallSequenceTestFuncs, func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+				excludedCallsLock.Lock()
+				defer excludedCallsLock.Unlock()
+				for _, element := range callSequence {
+					if element.Contract != nil && element.Contract.Name() == "MockOracle" {
+						excludedCalls++
+					}
+				}
+				return nil, nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The excluded contract should never have been called directly
+			assert.EqualValues(t, 0, excludedCalls)
+
+			// Only the property of the non-excluded contract should have been registered, and it should hold
+			for _, testCase := range f.fuzzer.TestCases() {
+				assert.NotContains(t, testCase.Name(), "MockOracle")
+			}
+			assert.NotEmpty(t, f.fuzzer.TestCases())
+			assertFailedTestsExpected(f, false)
+			assertCorpusCallSequencesCollected(f, true)
+		}})
+}
+
+// TestHardhatBuildInfoDeploymentAndCoverage tests that contracts loaded from Hardhat build-info files can be deployed,
+// matched by the fuzzer workers, and used to produce source coverage reports.
+func TestHardhatBuildInfoDeploymentAndCoverage(t *testing.T) {
+	runHardhatProjectTest(t, func(projectConfig *config.ProjectConfig) {
+		// Create our project configuration
+		projectConfig.Fuzzing.TestLimit = 1_000
+		projectConfig.Fuzzing.CorpusDirectory = "corpus"
+		projectConfig.Fuzzing.CoverageFormats = []string{"lcov"}
+		projectConfig.Fuzzing.Testing.StopOnNoTests = false
+
+		executeFuzzerTestMethodInternal(t, projectConfig, func(f *fuzzerTestContext) {
+			// Count the contracts matched against our definitions by the workers
+			var matchedContractsLock sync.Mutex
+			matchedContracts := make(map[string]bool)
+			f.fuzzer.Events.WorkerCreated.Subscribe(func(event FuzzerWorkerCreatedEvent) error {
+				event.Worker.Events.ContractAdded.Subscribe(func(event FuzzerWorkerContractAddedEvent) error {
+					matchedContractsLock.Lock()
+					defer matchedContractsLock.Unlock()
+					matchedContracts[event.ContractDefinition.Name()] = true
+					return nil
+				})
+				return nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Both contracts, from separate build-info files, should have been deployed and matched.
+			assert.True(t, matchedContracts["FirstContract"])
+			assert.True(t, matchedContracts["SecondContract"])
+
+			// Make sure we have some coverage
+			assertCorpusCallSequencesCollected(f, true)
+
+			// The coverage report should map coverage back to the source code cached from the build-info files.
+			lcovReport, err := os.ReadFile(filepath.Join("corpus", "coverage", "lcov.info"))
+			assert.NoError(t, err)
+			assert.Contains(t, string(lcovReport), "SF:contracts/FirstContract.sol")
+			assert.Contains(t, string(lcovReport), "SF:contracts/SecondContract.sol")
+			assert.NotContains(t, string(lcovReport), "DA:6,0\n")
+		})
+	})
+}
+
+// TestRegenerateCoverageReports tests that coverage reports can be regenerated from an existing corpus without
+// fuzzing, with the report formats and exclusions overridden.
+func TestRegenerateCoverageReports(t *testing.T) {
+	runHardhatProjectTest(t, func(projectConfig *config.ProjectConfig) {
+		// Create our project configuration, which does not generate any coverage reports when fuzzing.
+		projectConfig.Fuzzing.TestLimit = 1_000
+		projectConfig.Fuzzing.CorpusDirectory = "corpus"
+		projectConfig.Fuzzing.CoverageFormats = []string{}
+		projectConfig.Fuzzing.Testing.StopOnNoTests = false
+
+		// Run a short fuzzing campaign to build our corpus.
+		executeFuzzerTestMethodInternal(t, projectConfig, func(f *fuzzerTestContext) {
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+			assertCorpusCallSequencesCollected(f, true)
+		})
+		_, err := os.Stat(filepath.Join("corpus", "coverage", "lcov.info"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+
+		// Regenerate our coverage reports in every format, excluding one of our source files.
+		projectConfig.Fuzzing.CoverageFormats = []string{"html", "lcov", "json"}
+		projectConfig.Fuzzing.CoverageExclusions = []string{"contracts/Second*.sol"}
+		projectConfig.Fuzzing.Testing.Enabled = false
+		projectConfig.Fuzzing.Testing.StopOnFailedTest = false
+		fuzzer, err := NewFuzzer(*projectConfig)
+		assert.NoError(t, err)
+		reportPaths, err := fuzzer.RegenerateCoverageReports()
+		assert.NoError(t, err)
+		assert.Len(t, reportPaths, 3)
+
+		// No tests should have been run while regenerating our reports.
+		assert.Empty(t, fuzzer.TestCases())
+
+		// Each report should show coverage of the included source file only.
+		htmlReport, err := os.ReadFile(filepath.Join("corpus", "coverage", "coverage_report.html"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(htmlReport), "FirstContract.sol")
+		assert.NotContains(t, string(htmlReport), "SecondContract.sol")
+
+		lcovReport, err := os.ReadFile(filepath.Join("corpus", "coverage", "lcov.info"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(lcovReport), "SF:contracts/FirstContract.sol")
+		assert.NotContains(t, string(lcovReport), "SecondContract.sol")
+		assert.NotContains(t, string(lcovReport), "LH:0\n")
+
+		jsonReportData, err := os.ReadFile(filepath.Join("corpus", "coverage", "coverage.json"))
+		assert.NoError(t, err)
+		var jsonReport coverage.CoverageReport
+		assert.NoError(t, json.Unmarshal(jsonReportData, &jsonReport))
+		assert.Len(t, jsonReport.Files, 1)
+		if len(jsonReport.Files) == 1 {
+			assert.True(t, strings.HasSuffix(jsonReport.Files[0].Path, "contracts/FirstContract.sol"))
+			assert.Greater(t, jsonReport.Files[0].Totals.Covered, 0)
+		}
+	})
+}
+
+// TestAnalyzeCorpusSourceCoverage tests that the source coverage of an existing corpus can be measured from a project
+// configuration alone, or by replaying the corpus on a caller-supplied test chain, and that both measure the coverage
+// achieved by the fuzzer which collected the corpus.
+func TestAnalyzeCorpusSourceCoverage(t *testing.T) {
+	runHardhatProjectTest(t, func(projectConfig *config.ProjectConfig) {
+		projectConfig.Fuzzing.TestLimit = 1_000
+		projectConfig.Fuzzing.CorpusDirectory = "corpus"
+		projectConfig.Fuzzing.Testing.StopOnNoTests = false
+
+		// A corpus directory is required to analyze coverage from.
+		noCorpusConfig := *projectConfig
+		noCorpusConfig.Fuzzing.CorpusDirectory = ""
+		_, err := AnalyzeCorpusSourceCoverage(noCorpusConfig)
+		assert.Error(t, err)
+
+		// Run a short fuzzing campaign to build our corpus, recording the coverage it achieved.
+		var expectedAnalysis *coverage.SourceAnalysis
+		executeFuzzerTestMethodInternal(t, projectConfig, func(f *fuzzerTestContext) {
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+			assertCorpusCallSequencesCollected(f, true)
+			expectedAnalysis, err = coverage.AnalyzeSourceCoverage(f.fuzzer.compilations, f.fuzzer.corpus.CoverageMaps())
+			assert.NoError(t, err)
+		})
+
+		// Both of our source files should be covered.
+		assert.Positive(t, expectedAnalysis.CoveredLineCount())
+		for _, file := range expectedAnalysis.SortedFiles() {
+			assert.Positive(t, file.CoveredLineCount(), file.Path)
+		}
+
+		// Analyzing the corpus from our project configuration should measure the same coverage.
+		sourceAnalysis, err := AnalyzeCorpusSourceCoverage(*projectConfig)
+		assert.NoError(t, err)
+		assert.EqualValues(t, expectedAnalysis.CoveredLineCount(), sourceAnalysis.CoveredLineCount())
+		for _, file := range expectedAnalysis.SortedFiles() {
+			assert.EqualValues(t, file.CoveredLineCount(), sourceAnalysis.File(file.Path).CoveredLineCount(), file.Path)
+		}
+
+		// Replaying the corpus on a test chain we set up ourselves should measure the same coverage.
+		fuzzer, err := NewFuzzer(*projectConfig)
+		assert.NoError(t, err)
+		baseTestChain, err := fuzzer.createTestChain()
+		assert.NoError(t, err)
+		defer baseTestChain.Close()
+		_, err = fuzzer.Hooks.ChainSetupFunc(fuzzer, baseTestChain)
+		assert.NoError(t, err)
+		sourceAnalysis, err = corpus.AnalyzeSourceCoverage(projectConfig.Fuzzing.CorpusDirectory, baseTestChain, fuzzer.compilations)
+		assert.NoError(t, err)
+		assert.EqualValues(t, expectedAnalysis.CoveredLineCount(), sourceAnalysis.CoveredLineCount())
+	})
+}
+
+// TestSolcStandardJSONCampaign tests that a fuzzing campaign can be run against contracts compiled from a solc
+// standard JSON input, with coverage enabled.
+func TestSolcStandardJSONCampaign(t *testing.T) {
+	// Copy our standard JSON input and its sources to our testing directory
+	directory := testutils.CopyToTestDirectory(t, "../compilation/platforms/testdata/solc_standard_json/")
+
+	// Run the test in our temporary test directory to avoid artifact pollution.
+	testutils.ExecuteInDirectory(t, directory, func() {
+		// Create a solc standard JSON platform config and wrap it in a compilation config
+		compilationConfig, err := compilation.NewCompilationConfigFromPlatformConfig(platforms.NewSolcStandardJSONCompilationConfig("standard_input.json"))
+		assert.NoError(t, err)
+
+		// Create our project configuration
+		projectConfig := getFuzzerTestingProjectConfig(t, compilationConfig)
+		projectConfig.Fuzzing.TargetContracts = []string{"StandardJSONContract"}
+		projectConfig.Fuzzing.CorpusDirectory = "corpus"
+		projectConfig.Fuzzing.CoverageFormats = []string{"lcov"}
+		projectConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+		projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+		projectConfig.Slither.UseSlither = false
+
+		executeFuzzerTestMethodInternal(t, projectConfig, func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The property test should have been solved.
+			assertFailedTestsExpected(f, true)
+
+			// Make sure we have some coverage, and a report was generated from it.
+			assertCorpusCallSequencesCollected(f, true)
+			lcovReport, err := os.ReadFile(filepath.Join("corpus", "coverage", "lcov.info"))
+			assert.NoError(t, err)
+			assert.Contains(t, string(lcovReport), "SF:contracts/StandardJSONContract.sol")
+		})
+	})
+}
+
+// TestStorageDiffsReported tests that the report of a failed property test describes the storage changes made by the
+// shrunken call sequence, naming the changed variables using the storage layout of the contract.
+func TestStorageDiffsReported(t *testing.T) {
+	// Copy our standard JSON input and its sources to our testing directory
+	directory := testutils.CopyToTestDirectory(t, "testdata/contracts/storage_diffs/")
+
+	// Run the test in our temporary test directory to avoid artifact pollution.
+	testutils.ExecuteInDirectory(t, directory, func() {
+		// Create a solc standard JSON platform config, as it provides the storage layout of each contract.
+		compilationConfig, err := compilation.NewCompilationConfigFromPlatformConfig(platforms.NewSolcStandardJSONCompilationConfig("standard_input.json"))
+		assert.NoError(t, err)
+
+		// Create our project configuration
+		projectConfig := getFuzzerTestingProjectConfig(t, compilationConfig)
+		projectConfig.Fuzzing.TargetContracts = []string{"StorageDiffContract"}
+		projectConfig.Fuzzing.Testing.StorageDiffs = true
+		projectConfig.Fuzzing.Testing.AssertionTesting.Enabled = false
+		projectConfig.Fuzzing.Testing.OptimizationTesting.Enabled = false
+		projectConfig.Slither.UseSlither = false
+
+		executeFuzzerTestMethodInternal(t, projectConfig, func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The property test should have failed, with the changed owner and counter named in its report.
+			failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+			assert.Len(t, failedTestCases, 1)
+			for _, testCase := range failedTestCases {
+				propertyTestCase, ok := testCase.(*PropertyTestCase)
+				assert.True(t, ok)
+				if !ok {
+					continue
+				}
+				assert.Len(t, propertyTestCase.storageDiff, 1)
+				variables := make(map[string]StorageChange)
+				for _, contractDiff := range propertyTestCase.storageDiff {
+					for _, change := range contractDiff.Changes {
+						variables[change.Variable] = change
+					}
+				}
+				assert.Contains(t, variables, "owner")
+				assert.Contains(t, variables, "counter")
+				assert.EqualValues(t, common.HexToAddress("0x1").String(), variables["owner"].Before)
+				assert.EqualValues(t, "0", variables["counter"].Before)
+				assert.EqualValues(t, "1", variables["counter"].After)
+
+				message := testCase.Message()
+				assert.Contains(t, message, "[Storage Changes]")
+				assert.Contains(t, message, "StorageDiffContract")
+			}
+		})
+	})
+}
+
+// TestPropertyTestRevertHandling runs a property test which overflows, and so reverts rather than returning a result,
+// for large state values. It ensures the revert is handled as configured: failing the test, or not, while recording the
+// decoded revert reason either way.
+func TestPropertyTestRevertHandling(t *testing.T) {
+	for _, revertHandling := range []string{"fail", "warn", "ignore"} {
+		revertHandling := revertHandling
+		runFuzzerTest(t, &fuzzerSolcFileTest{
+			filePath: "testdata/contracts/assertions/property_reverts.sol",
+			configUpdates: func(config *config.ProjectConfig) {
+				config.Fuzzing.TargetContracts = []string{"TestContract"}
+				config.Fuzzing.TestLimit = 10_000
+				config.Fuzzing.Testing.StopOnFailedTest = false
+				config.Fuzzing.Testing.PropertyTesting.RevertHandling = revertHandling
+				config.Fuzzing.Testing.AssertionTesting.Enabled = false
+				config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+				config.Slither.UseSlither = false
+			},
+			method: func(f *fuzzerTestContext) {
+				// Start the fuzzer
+				err := f.fuzzer.Start()
+				assert.NoError(t, err)
+
+				// The property never returns false, so it should only fail if reverts are handled as failures, in
+				// which case the revert reason should be reported.
+				failedTestCases := f.fuzzer.TestCasesWithStatus(TestCaseStatusFailed)
+				if revertHandling == "fail" {
+					assert.Len(t, failedTestCases, 1)
+					for _, testCase := range failedTestCases {
+						assert.Contains(t, testCase.Message(), "The property test reverted rather than returning a result: panic: arithmetic underflow/overflow")
+					}
+				} else {
+					assert.Empty(t, failedTestCases)
+				}
+
+				// Either way, the revert should have been recorded with its decoded reason, so it is listed in the
+				// campaign summary.
+				testCases := f.fuzzer.TestCases()
+				assert.Len(t, testCases, 1)
+				propertyTestCase, ok := testCases[0].(*PropertyTestCase)
+				assert.True(t, ok)
+				if ok {
+					reverts, revertReason := propertyTestCase.EvaluationReverts()
+					assert.Positive(t, reverts)
+					assert.EqualValues(t, "panic: arithmetic underflow/overflow", revertReason)
+				}
+			},
+		})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"testing"
 
 	"github.com/crytic/medusa/compilation"
+	"github.com/crytic/medusa/compilation/platforms"
 	"github.com/crytic/medusa/events"
 	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/crytic/medusa/utils/testutils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -51,7 +53,7 @@ func executeFuzzerTestMethodInternal(t *testing.T, config *config.ProjectConfig,
 }
 
 // getFuzzerTestingProjectConfig creates a default project configuration used for testing the Fuzzer.
-func getFuzzerTestingProjectConfig(t *testing.T, compilationConfig *compilation.CompilationConfig) *config.ProjectConfig {
+func getFuzzerTestingProjectConfig(t testing.TB, compilationConfig *compilation.CompilationConfig) *config.ProjectConfig {
 	projectConfig, err := config.GetDefaultProjectConfig("")
 	assert.NoError(t, err)
 	projectConfig.Compilation = compilationConfig
@@ -65,6 +67,59 @@ func getFuzzerTestingProjectConfig(t *testing.T, compilationConfig *compilation.
 	return projectConfig
 }
 
+// runHardhatProjectTest copies our pre-compiled Hardhat project to a testing directory, and runs the provided method
+// within it, with a project configuration created by newHardhatProjectConfig. The project is used to test the Fuzzer
+// without requiring a compiler.
+func runHardhatProjectTest(t testing.TB, method func(projectConfig *config.ProjectConfig)) {
+	// Copy our Hardhat project, which has already been compiled, to our testing directory
+	projectDirectory := testutils.CopyToTestDirectory(t, "../compilation/platforms/testdata/hardhat/build_info_project/")
+
+	// Run the test in our temporary test directory to avoid artifact pollution.
+	testutils.ExecuteInDirectory(t, projectDirectory, func() {
+		method(newHardhatProjectConfig(t))
+	})
+}
+
+// newHardhatProjectConfig creates a project configuration used for testing the Fuzzer against our pre-compiled Hardhat
+// project, which must be in the current working directory. It targets both contracts of the project, and does not use
+// slither.
+func newHardhatProjectConfig(t testing.TB) *config.ProjectConfig {
+	// Create a hardhat platform config and wrap it in a compilation config
+	compilationConfig, err := compilation.NewCompilationConfigFromPlatformConfig(platforms.NewHardhatCompilationConfig("."))
+	assert.NoError(t, err)
+
+	// Create our project configuration
+	projectConfig := getFuzzerTestingProjectConfig(t, compilationConfig)
+	projectConfig.Fuzzing.TargetContracts = []string{"FirstContract", "SecondContract"}
+	projectConfig.Slither.UseSlither = false
+	return projectConfig
+}
+
+// runHardhatWorkerTest runs the provided method with a FuzzerWorker set up against our pre-compiled Hardhat project, as
+// it would be before it begins fuzzing. The project configuration is created by newHardhatProjectConfig, and updated
+// by the provided function prior to creating the Fuzzer.
+func runHardhatWorkerTest(t testing.TB, configUpdates func(projectConfig *config.ProjectConfig), method func(worker *FuzzerWorker)) {
+	runHardhatProjectTest(t, func(projectConfig *config.ProjectConfig) {
+		configUpdates(projectConfig)
+		fuzzer, err := NewFuzzer(*projectConfig)
+		assert.NoError(t, err)
+
+		// Set up a worker, as it would be before it begins fuzzing.
+		baseTestChain, err := fuzzer.setUpCampaign(true)
+		assert.NoError(t, err)
+		defer baseTestChain.Close()
+		defer fuzzer.ctxCancelFunc()
+		defer fuzzer.emergencyCtxCancelFunc()
+		worker, err := newFuzzerWorker(fuzzer, 0, fuzzer.randomProvider)
+		assert.NoError(t, err)
+		assert.NoError(t, worker.setUpChain(baseTestChain))
+		defer worker.closeChain()
+		worker.testingBaseBlockIndex = uint64(len(worker.chain.CommittedBlocks()))
+
+		method(worker)
+	})
+}
+
 // assertFailedTestsExpected will check to see whether there are any failed tests. If `expectFailure` is false, then
 // there should be no failed tests
 func assertFailedTestsExpected(f *fuzzerTestContext, expectFailure bool) {
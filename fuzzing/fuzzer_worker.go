@@ -4,15 +4,22 @@ import (
 	"fmt"
 	"math/big"
 	"math/rand"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/crytic/medusa/logging/colors"
 
 	"github.com/crytic/medusa/chain"
 	"github.com/crytic/medusa/fuzzing/calls"
 	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/corpus"
 	"github.com/crytic/medusa/fuzzing/coverage"
+	"github.com/crytic/medusa/fuzzing/executiontracer"
 	"github.com/crytic/medusa/fuzzing/valuegeneration"
 	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"golang.org/x/exp/maps"
 )
@@ -29,6 +36,12 @@ type FuzzerWorker struct {
 	chain *chain.TestChain
 	// coverageTracer describes the tracer used to collect coverage maps during fuzzing campaigns.
 	coverageTracer *coverage.CoverageTracer
+	// corpusStagingBuffer collects the call sequences this worker adds to the corpus, so they are added in batches at
+	// call sequence boundaries rather than contending for the corpus on every addition.
+	corpusStagingBuffer *corpus.StagingBuffer
+	// deterministicState describes the state of this worker's slot when fuzzing deterministically, or is nil if
+	// fuzzing is not deterministic.
+	deterministicState *deterministicWorkerState
 
 	// testingBaseBlockIndex refers to the block index within the test chain at which all contracts for testing have been deployed,
 	// prior to any fuzzing activity. This block number is reverted to after testing each call sequence to reset state.
@@ -37,6 +50,10 @@ type FuzzerWorker struct {
 	// deployedContracts describes a mapping of deployed contractDefinitions and the addresses they were deployed to.
 	deployedContracts map[common.Address]*fuzzerTypes.Contract
 
+	// deployedContractsLock provides thread synchronization for deployedContracts, so it can be queried through
+	// exported methods from other goroutines while the worker updates it.
+	deployedContractsLock sync.RWMutex
+
 	// stateChangingMethods is a list of contract functions which are suspected of changing contract state
 	// (non-read-only). A sequence of calls is generated by the FuzzerWorker, targeting stateChangingMethods
 	// before executing tests.
@@ -45,13 +62,37 @@ type FuzzerWorker struct {
 	// pureMethods is a list of contract functions which are side-effect free with respect to the EVM (view and/or pure in terms of Solidity mutability).
 	pureMethods []fuzzerTypes.DeployedContractMethod
 
+	// stateChangingMethodPositions describes the indexes of each deployed contract's methods in stateChangingMethods,
+	// so they can be removed without rebuilding the list.
+	stateChangingMethodPositions map[common.Address][]int
+
+	// pureMethodPositions describes the indexes of each deployed contract's methods in pureMethods, so they can be
+	// removed without rebuilding the list.
+	pureMethodPositions map[common.Address][]int
+
+	// dynamicDeploymentTargetCounts describes the number of dynamically deployed contracts registered as fuzzing
+	// targets for each contract definition. It is used to enforce the dynamic deployment target limit.
+	dynamicDeploymentTargetCounts map[*fuzzerTypes.Contract]int
+
+	// dynamicDeploymentTargets describes the contract definitions of dynamically deployed contracts which were
+	// registered as fuzzing targets, by address, counting towards dynamicDeploymentTargetCounts.
+	dynamicDeploymentTargets map[common.Address]*fuzzerTypes.Contract
+
 	// shrinkCallSequenceRequests is a list of ShrinkCallSequenceRequest that will be handled in the next iteration of
-	// the fuzzing loop. In the future we can generalize this to any type of "request" that must be handled immediately
-	// before the execution of the next call sequence.
+	// the fuzzing loop, where they are shrunk or queued on the Fuzzer's shrinkScheduler. In the future we can
+	// generalize this to any type of "request" that must be handled immediately before the execution of the next call
+	// sequence.
 	shrinkCallSequenceRequests []ShrinkCallSequenceRequest
 
-	// randomProvider provides random data as inputs to decisions throughout the worker.
+	// randomProvider provides random data as inputs to decisions throughout the worker. It is re-seeded from the
+	// replayID prior to testing each call sequence.
 	randomProvider *rand.Rand
+
+	// workerGeneration describes the number of times a worker was created at this worker's index, including this one.
+	workerGeneration uint64
+
+	// replayID describes the ReplayID of the call sequence currently being tested by the worker.
+	replayID ReplayID
 	// sequenceGenerator creates entirely new or mutated call sequences based on corpus call sequences, for use in
 	// fuzzing campaigns.
 	sequenceGenerator *CallSequenceGenerator
@@ -64,6 +105,29 @@ type FuzzerWorker struct {
 	// FuzzerWorker. It is the value set shared with the underlying valueGenerator.
 	valueSet *valuegeneration.ValueSet
 
+	// liveness describes the progress of the worker, as monitored by the Fuzzer's workerWatchdog.
+	liveness workerHeartbeat
+
+	// abandoned indicates whether the worker was abandoned by the workerWatchdog after it stalled, in which case it
+	// exits without contributing further results once it makes progress again.
+	abandoned atomic.Bool
+
+	// chainClosed indicates whether the worker's chain was closed, which is done once the worker exits, or once it is
+	// abandoned, whichever comes first.
+	chainClosed atomic.Bool
+
+	// onAbandon describes a function invoked when the worker is abandoned, which frees its worker slot so a new worker
+	// can be created in its place.
+	onAbandon func()
+
+	// activeShrink describes the shrink request the worker is currently running, and the resources it holds, so they
+	// can be released if the worker is abandoned while running it. It is nil while no shrink request is running.
+	activeShrink *activeShrinkRequest
+
+	// activeShrinkLock provides thread-synchronization for activeShrink, as it is released by the workerWatchdog if
+	// the worker is abandoned.
+	activeShrinkLock sync.Mutex
+
 	// Events describes the event system for the FuzzerWorker.
 	Events FuzzerWorkerEvents
 }
@@ -89,18 +153,29 @@ func newFuzzerWorker(fuzzer *Fuzzer, workerIndex int, randomProvider *rand.Rand)
 
 	// Create a new worker with the data provided.
 	worker := &FuzzerWorker{
-		workerIndex:                workerIndex,
-		fuzzer:                     fuzzer,
-		deployedContracts:          make(map[common.Address]*fuzzerTypes.Contract),
-		stateChangingMethods:       make([]fuzzerTypes.DeployedContractMethod, 0),
-		pureMethods:                make([]fuzzerTypes.DeployedContractMethod, 0),
-		shrinkCallSequenceRequests: make([]ShrinkCallSequenceRequest, 0),
-		coverageTracer:             nil,
-		randomProvider:             randomProvider,
-		valueSet:                   valueSet,
+		workerIndex:                   workerIndex,
+		fuzzer:                        fuzzer,
+		deployedContracts:             make(map[common.Address]*fuzzerTypes.Contract),
+		stateChangingMethods:          make([]fuzzerTypes.DeployedContractMethod, 0),
+		pureMethods:                   make([]fuzzerTypes.DeployedContractMethod, 0),
+		stateChangingMethodPositions:  make(map[common.Address][]int),
+		pureMethodPositions:           make(map[common.Address][]int),
+		dynamicDeploymentTargetCounts: make(map[*fuzzerTypes.Contract]int),
+		dynamicDeploymentTargets:      make(map[common.Address]*fuzzerTypes.Contract),
+		shrinkCallSequenceRequests:    make([]ShrinkCallSequenceRequest, 0),
+		coverageTracer:                nil,
+		randomProvider:                randomProvider,
+		valueSet:                      valueSet,
 	}
 	worker.sequenceGenerator = NewCallSequenceGenerator(worker, callSequenceGenConfig)
 	worker.shrinkingValueMutator = shrinkingValueMutator
+	if fuzzer.deterministicWorkerStates != nil {
+		worker.deterministicState = fuzzer.deterministicWorkerStates[workerIndex]
+	}
+	worker.corpusStagingBuffer = worker.newCorpusStagingBuffer()
+
+	// Forward the worker's events to the fuzzer's aggregate worker event emitters.
+	fuzzer.Events.forwardWorkerEvents(worker)
 
 	return worker, nil
 }
@@ -110,6 +185,48 @@ func (fw *FuzzerWorker) WorkerIndex() int {
 	return fw.workerIndex
 }
 
+// heartbeat returns the workerHeartbeat describing the progress of this FuzzerWorker.
+func (fw *FuzzerWorker) heartbeat() *workerHeartbeat {
+	return &fw.liveness
+}
+
+// abandon stops this FuzzerWorker from contributing to the fuzzing campaign after it stalled. Its worker slot is freed,
+// so a new worker is created in its place while it remains stalled, as are the shrinking slot and failure held by the
+// shrink request it was running, if any, so the failure can be shrunk by another worker once it is rediscovered. The
+// worker's chain is closed, as the worker may never make progress again, and it is signalled to exit once it does.
+func (fw *FuzzerWorker) abandon() {
+	fw.abandoned.Store(true)
+	fw.releaseActiveShrink()
+	fw.closeChain()
+	if fw.onAbandon != nil {
+		fw.onAbandon()
+	}
+}
+
+// closeChain closes the worker's chain, releasing its resources, if it was set up and was not already closed.
+func (fw *FuzzerWorker) closeChain() {
+	if fw.chain != nil && fw.chainClosed.CompareAndSwap(false, true) {
+		fw.chain.Close()
+	}
+}
+
+// ReplayID returns the ReplayID of the call sequence currently being tested by this FuzzerWorker.
+func (fw *FuzzerWorker) ReplayID() ReplayID {
+	return fw.replayID
+}
+
+// isolateRandomProvider replaces the state of the worker's random provider with a random stream derived from the
+// provided seed, until the returned function is called to restore it. The random provider is shared with the worker's
+// value generator and mutators, so this allows random values to be generated while a call sequence is being tested,
+// without altering the random stream derived from its ReplayID, which must remain reproducible.
+func (fw *FuzzerWorker) isolateRandomProvider(seed int64) func() {
+	originalRandomProvider := *fw.randomProvider
+	*fw.randomProvider = *rand.New(rand.NewSource(seed))
+	return func() {
+		*fw.randomProvider = originalRandomProvider
+	}
+}
+
 // workerMetrics returns the fuzzerWorkerMetrics for this specific worker.
 func (fw *FuzzerWorker) workerMetrics() *fuzzerWorkerMetrics {
 	return &fw.fuzzer.metrics.workerMetrics[fw.workerIndex]
@@ -129,17 +246,70 @@ func (fw *FuzzerWorker) Chain() *chain.TestChain {
 // to the fuzzer.
 func (fw *FuzzerWorker) DeployedContracts() map[common.Address]*fuzzerTypes.Contract {
 	// Return a clone of the map, as we don't want external usage of this to break it.
+	fw.deployedContractsLock.RLock()
+	defer fw.deployedContractsLock.RUnlock()
 	return maps.Clone(fw.deployedContracts)
 }
 
 // DeployedContract obtains a contract deployed at the given address. If it does not exist, it returns nil.
 func (fw *FuzzerWorker) DeployedContract(address common.Address) *fuzzerTypes.Contract {
+	fw.deployedContractsLock.RLock()
+	defer fw.deployedContractsLock.RUnlock()
 	if contractDefinition, ok := fw.deployedContracts[address]; ok {
 		return contractDefinition
 	}
 	return nil
 }
 
+// ResolveMethod resolves the method with the provided selector of the contract deployed at the provided address,
+// using the contract definitions matched to deployments on the worker's chain, including dynamic deployments tracked
+// by the worker. This is safe to call from event handlers running on other goroutines.
+// Returns the deployed contract method, or nil if no contract is known to be deployed at the address, or its contract
+// definition declares no method with the selector.
+func (fw *FuzzerWorker) ResolveMethod(address common.Address, selector []byte) *fuzzerTypes.DeployedContractMethod {
+	contractDefinition := fw.DeployedContract(address)
+	if contractDefinition == nil {
+		return nil
+	}
+	method := contractDefinition.MethodBySelector(selector)
+	if method == nil {
+		return nil
+	}
+	return &fuzzerTypes.DeployedContractMethod{
+		Address:  address,
+		Contract: contractDefinition,
+		Method:   *method,
+	}
+}
+
+// resolveContract resolves the contract definition for the provided address, using the contracts tracked by the
+// worker, or the code deployed on its chain if the contract is not tracked (e.g. dynamic deployments when not testing
+// all contracts).
+// Returns the contract definition, or nil if it could not be resolved.
+func (fw *FuzzerWorker) resolveContract(address common.Address) *fuzzerTypes.Contract {
+	if contractDefinition := fw.DeployedContract(address); contractDefinition != nil {
+		return contractDefinition
+	}
+	runtimeBytecode := fw.chain.State().GetCode(address)
+	if len(runtimeBytecode) == 0 {
+		return nil
+	}
+	matchingMode := fuzzerTypes.BytecodeMatchingMode(fw.fuzzer.config.Fuzzing.Testing.ContractMatchingMode)
+	return fw.fuzzer.contractDefinitions.MatchBytecodeWithMode(nil, runtimeBytecode, matchingMode)
+}
+
+// resolveContractMethod resolves the contract definition for the code deployed at the provided address, along with
+// the method the provided selector describes.
+// Returns the contract definition and method, either of which is nil if it could not be resolved (e.g. the selector
+// is nil, as a fallback or receive function was entered).
+func (fw *FuzzerWorker) resolveContractMethod(codeAddress common.Address, selector []byte) (*fuzzerTypes.Contract, *abi.Method) {
+	contract := fw.resolveContract(codeAddress)
+	if contract == nil || selector == nil {
+		return contract, nil
+	}
+	return contract, contract.MethodBySelector(selector)
+}
+
 // ValueSet obtains the value set used to power the value generator for this worker.
 func (fw *FuzzerWorker) ValueSet() *valuegeneration.ValueSet {
 	return fw.valueSet
@@ -162,6 +332,42 @@ func (fw *FuzzerWorker) getNewCorpusCallSequenceWeight() *big.Int {
 	return new(big.Int).Add(fw.workerMetrics().sequencesTested, big.NewInt(1))
 }
 
+// flushCorpusStagingBuffer adds the call sequences this worker staged for the corpus to it, writing them to disk.
+// Returns an error if one occurs.
+func (fw *FuzzerWorker) flushCorpusStagingBuffer() error {
+	_, err := fw.corpusStagingBuffer.Flush(true)
+	return err
+}
+
+// checkSequenceCoverageAndUpdate checks if the most recent call executed in the provided call sequence achieved new
+// coverage, staging the call sequence to be added to the corpus if so. If it was staged, a NewCoverage event is emitted.
+// The shrinking flag indicates whether the call sequence is a shrunken call sequence being verified, in which case
+// the call sequence length which achieved the coverage is not recorded, as it was not chosen by fuzzing.
+// Returns an error if one occurs.
+func (fw *FuzzerWorker) checkSequenceCoverageAndUpdate(callSequence calls.CallSequence, shrinking bool) error {
+	// If we detect coverage changes, add this sequence with weight as 1 + sequences tested (to avoid zero weights)
+	coverageDelta, err := fw.corpusStagingBuffer.CheckSequenceCoverageAndUpdate(callSequence, fw.getNewCorpusCallSequenceWeight())
+	if err != nil || coverageDelta == nil {
+		return err
+	}
+	if !shrinking {
+		fw.fuzzer.sequenceLengths.recordNewCoverage(len(callSequence))
+	}
+	fw.recordContractCorpusSequence(callSequence)
+
+	// Emit an event indicating we achieved new coverage.
+	err = fw.Events.NewCoverage.Publish(FuzzerWorkerNewCoverageEvent{
+		Worker:        fw,
+		CallSequence:  callSequence,
+		CoverageDelta: coverageDelta,
+		Shrinking:     shrinking,
+	})
+	if err != nil {
+		return fmt.Errorf("error returned by an event handler when a worker emitted a new coverage event: %w", err)
+	}
+	return nil
+}
+
 // onChainContractDeploymentAddedEvent is the event callback used when the chain detects a new contract deployment.
 // It attempts bytecode matching and updates the list of deployed contracts the worker should use for fuzz testing.
 func (fw *FuzzerWorker) onChainContractDeploymentAddedEvent(event chain.ContractDeploymentsAddedEvent) error {
@@ -172,13 +378,27 @@ func (fw *FuzzerWorker) onChainContractDeploymentAddedEvent(event chain.Contract
 		return nil
 	}
 
-	// Add the contract address to our value set so our generator can use it in calls.
-	fw.valueSet.AddAddress(event.Contract.Address)
-
 	// Try to match it to a known contract definition
-	matchedDefinition := fw.fuzzer.contractDefinitions.MatchBytecode(event.Contract.InitBytecode, event.Contract.RuntimeBytecode)
+	matchingMode := fuzzerTypes.BytecodeMatchingMode(fw.fuzzer.config.Fuzzing.Testing.ContractMatchingMode)
+	matchedDefinition := fw.fuzzer.contractDefinitions.MatchBytecodeWithMode(event.Contract.InitBytecode, event.Contract.RuntimeBytecode, matchingMode)
+
+	// Add the contract address to our value set so our generator can use it in calls. Spec contracts only exist to
+	// define tests over other contracts, so their addresses are not used as values.
+	isSpecContract := matchedDefinition != nil && !event.DynamicDeployment && fw.fuzzer.isSpecContract(matchedDefinition.Name())
+	if !isSpecContract {
+		fw.valueSet.AddAddress(event.Contract.Address)
+
+		// Add references to the contract's methods, so they can be used as external function arguments.
+		if matchedDefinition != nil {
+			for _, method := range matchedDefinition.CompiledContract().Abi.Methods {
+				fw.valueSet.AddFunction(event.Contract.Address, method.ID)
+			}
+		}
+	}
+
 	// If we didn't match any deployment, report it.
 	if matchedDefinition == nil {
+		fw.fuzzer.unmatchedDeployments.record(event.Contract.InitBytecode)
 		if fw.fuzzer.config.Fuzzing.Testing.StopOnFailedContractMatching {
 			return fmt.Errorf("could not match bytecode of a deployed contract to any contract definition known to the fuzzer")
 		} else {
@@ -186,20 +406,55 @@ func (fw *FuzzerWorker) onChainContractDeploymentAddedEvent(event chain.Contract
 		}
 	}
 
+	// If this contract was already registered, remove its previously added methods before re-adding it. If it was
+	// registered as a dynamically deployed target, it no longer counts towards the target limit until it is re-added.
+	if _, previouslyRegistered := fw.deployedContracts[event.Contract.Address]; previouslyRegistered {
+		fw.removeContractMethods(event.Contract.Address)
+		fw.removeDynamicDeploymentTarget(event.Contract.Address)
+	}
+
 	// Set our deployed contract address in our deployed contract lookup, so we can reference it later.
+	fw.deployedContractsLock.Lock()
 	fw.deployedContracts[event.Contract.Address] = matchedDefinition
-
-	// Update our methods
-	fw.updateMethods()
+	fw.deployedContractsLock.Unlock()
+
+	// Add the contract's methods as fuzzing targets. Dynamically deployed contracts are only registered as targets
+	// until the limit for their contract definition is reached, so factories deploying many identical children do not
+	// dilute method selection. Contracts beyond the limit remain tracked, but are not called directly.
+	// Spec contracts are only called to run their assertion tests (their methods named with a spec test prefix), so
+	// their methods are only added if assertion testing is enabled.
+	if isSpecContract {
+		if fw.fuzzer.config.Fuzzing.Testing.AssertionTesting.Enabled {
+			fw.addContractMethods(event.Contract.Address, matchedDefinition)
+		}
+	} else if !event.DynamicDeployment {
+		fw.addContractMethods(event.Contract.Address, matchedDefinition)
+	} else if fw.addDynamicDeploymentTarget(event.Contract.Address, matchedDefinition) {
+		fw.addContractMethods(event.Contract.Address, matchedDefinition)
+	}
 
 	// Emit an event indicating the worker detected a new contract deployment on its chain.
 	err := fw.Events.ContractAdded.Publish(FuzzerWorkerContractAddedEvent{
 		Worker:             fw,
 		ContractAddress:    event.Contract.Address,
 		ContractDefinition: matchedDefinition,
+		Creator:            event.Creator,
+		InitBytecodeHash:   event.InitBytecodeHash,
+		BlockNumber:        event.BlockNumber,
+		TransactionIndex:   event.TransactionIndex,
+		TransactionHash:    event.TransactionHash,
+		DynamicDeployment:  event.DynamicDeployment,
 	})
 	if err != nil {
-		return fmt.Errorf("error returned by an event handler when a worker emitted a deployed contract added event: %v", err)
+		return fmt.Errorf("error returned by an event handler when a worker emitted a deployed contract added event: %w", err)
+	}
+
+	// Display the label the contract was given in the Fuzzer's registry, unless it was already labeled on our chain
+	// (e.g. using a cheat code).
+	if _, labeled := event.Chain.Labels[event.Contract.Address]; !labeled {
+		if label := fw.fuzzer.addressLabels.Label(event.Contract.Address); label != "" {
+			event.Chain.Labels[event.Contract.Address] = label
+		}
 	}
 	return nil
 }
@@ -217,11 +472,14 @@ func (fw *FuzzerWorker) onChainContractDeploymentRemovedEvent(event chain.Contra
 		return nil
 	}
 
-	// Remove the contract from our deployed contracts mapping the worker maintains.
+	// Remove the contract from our deployed contracts mapping the worker maintains, along with its methods.
+	fw.deployedContractsLock.Lock()
 	delete(fw.deployedContracts, event.Contract.Address)
+	fw.deployedContractsLock.Unlock()
+	fw.removeContractMethods(event.Contract.Address)
 
-	// Update our methods
-	fw.updateMethods()
+	// If this was a dynamically deployed fuzzing target, it no longer counts towards the target limit.
+	fw.removeDynamicDeploymentTarget(event.Contract.Address)
 
 	// Emit an event indicating the worker detected the removal of a previously deployed contract on its chain.
 	err := fw.Events.ContractDeleted.Publish(FuzzerWorkerContractDeletedEvent{
@@ -230,38 +488,112 @@ func (fw *FuzzerWorker) onChainContractDeploymentRemovedEvent(event chain.Contra
 		ContractDefinition: contractDefinition,
 	})
 	if err != nil {
-		return fmt.Errorf("error returned by an event handler when a worker emitted a deployed contract deleted event: %v", err)
+		return fmt.Errorf("error returned by an event handler when a worker emitted a deployed contract deleted event: %w", err)
 	}
 	return nil
 }
 
-// updateMethods updates the list of methods used by the worker by re-evaluating them
-// from the deployedContracts lookup.
-func (fw *FuzzerWorker) updateMethods() {
-	// Clear our list of methods
-	fw.stateChangingMethods = make([]fuzzerTypes.DeployedContractMethod, 0)
-	fw.pureMethods = make([]fuzzerTypes.DeployedContractMethod, 0)
-
-	// Loop through each deployed contract
-	for contractAddress, contractDefinition := range fw.deployedContracts {
-		// If we deployed the contract, also enumerate property tests and state changing methods.
-		for _, method := range contractDefinition.AssertionTestMethods {
-			// Any non-constant method should be tracked as a state changing method.
-			if method.IsConstant() {
-				// Only track the pure/view method if testing view methods is enabled
-				if fw.fuzzer.config.Fuzzing.Testing.TestViewMethods {
-					fw.pureMethods = append(fw.pureMethods, fuzzerTypes.DeployedContractMethod{Address: contractAddress, Contract: contractDefinition, Method: method})
-				}
-			} else {
-				fw.stateChangingMethods = append(fw.stateChangingMethods, fuzzerTypes.DeployedContractMethod{Address: contractAddress, Contract: contractDefinition, Method: method})
+// addDynamicDeploymentTarget registers the dynamically deployed contract at the provided address as a fuzzing target,
+// unless the limit of dynamically deployed targets for the provided contract definition was reached.
+// Returns a boolean indicating whether the contract was registered as a fuzzing target.
+func (fw *FuzzerWorker) addDynamicDeploymentTarget(contractAddress common.Address, contractDefinition *fuzzerTypes.Contract) bool {
+	dynamicDeploymentTargetLimit := fw.fuzzer.config.Fuzzing.Testing.DynamicDeploymentTargetLimit
+	if dynamicDeploymentTargetLimit > 0 && fw.dynamicDeploymentTargetCounts[contractDefinition] >= dynamicDeploymentTargetLimit {
+		return false
+	}
+	fw.dynamicDeploymentTargetCounts[contractDefinition]++
+	fw.dynamicDeploymentTargets[contractAddress] = contractDefinition
+	return true
+}
+
+// removeDynamicDeploymentTarget unregisters the dynamically deployed contract at the provided address as a fuzzing
+// target, so it no longer counts towards the limit of targets for its contract definition. If it was not registered,
+// this does nothing.
+func (fw *FuzzerWorker) removeDynamicDeploymentTarget(contractAddress common.Address) {
+	contractDefinition, registered := fw.dynamicDeploymentTargets[contractAddress]
+	if !registered {
+		return
+	}
+	delete(fw.dynamicDeploymentTargets, contractAddress)
+	fw.dynamicDeploymentTargetCounts[contractDefinition]--
+	if fw.dynamicDeploymentTargetCounts[contractDefinition] <= 0 {
+		delete(fw.dynamicDeploymentTargetCounts, contractDefinition)
+	}
+}
+
+// addContractMethods adds the methods of the provided contract deployed at the given address to the list of methods
+// used by the worker to generate calls.
+func (fw *FuzzerWorker) addContractMethods(contractAddress common.Address, contractDefinition *fuzzerTypes.Contract) {
+	// Excluded contracts remain deployed so other contracts can interact with them, but are never called directly.
+	if slices.Contains(fw.fuzzer.config.Fuzzing.Testing.ExcludeContracts, contractDefinition.Name()) {
+		fw.workerMetrics().contracts.addContract(contractDefinition, nil)
+		return
+	}
+
+	addedMethods := make([]fuzzerTypes.DeployedContractMethod, 0, len(contractDefinition.AssertionTestMethods))
+	for _, method := range contractDefinition.AssertionTestMethods {
+		deployedMethod := fuzzerTypes.DeployedContractMethod{Address: contractAddress, Contract: contractDefinition, Method: method}
+
+		// Any non-constant method should be tracked as a state changing method.
+		if method.IsConstant() {
+			// Only track the pure/view method if testing view methods is enabled
+			if fw.fuzzer.config.Fuzzing.Testing.TestViewMethods {
+				addDeployedContractMethod(&fw.pureMethods, fw.pureMethodPositions, deployedMethod)
+				addedMethods = append(addedMethods, deployedMethod)
 			}
+		} else {
+			addDeployedContractMethod(&fw.stateChangingMethods, fw.stateChangingMethodPositions, deployedMethod)
+			addedMethods = append(addedMethods, deployedMethod)
 		}
 	}
+
+	// Record the methods we added in our metrics, so coverage of them can be reported per contract.
+	fw.workerMetrics().contracts.addContract(contractDefinition, addedMethods)
+}
+
+// removeContractMethods removes the methods of the contract deployed at the given address from the list of methods
+// used by the worker to generate calls. This only affects the removed contract's methods, and does not preserve the
+// order of the remaining methods, as methods are selected randomly.
+func (fw *FuzzerWorker) removeContractMethods(contractAddress common.Address) {
+	removeDeployedContractMethods(&fw.stateChangingMethods, fw.stateChangingMethodPositions, contractAddress)
+	removeDeployedContractMethods(&fw.pureMethods, fw.pureMethodPositions, contractAddress)
+}
+
+// addDeployedContractMethod appends a method to the provided list of methods, recording its index in the provided
+// lookup of method positions by contract address.
+func addDeployedContractMethod(methods *[]fuzzerTypes.DeployedContractMethod, positions map[common.Address][]int, method fuzzerTypes.DeployedContractMethod) {
+	positions[method.Address] = append(positions[method.Address], len(*methods))
+	*methods = append(*methods, method)
+}
+
+// removeDeployedContractMethods removes all methods of the contract at the provided address from the provided list
+// of methods. Each removed method is replaced by the last method in the list, so only the positions of moved methods
+// need to be updated in the provided lookup of method positions by contract address.
+func removeDeployedContractMethods(methods *[]fuzzerTypes.DeployedContractMethod, positions map[common.Address][]int, contractAddress common.Address) {
+	// Remove methods from the highest index down, so the last method in the list is never one still to be removed.
+	removedPositions := positions[contractAddress]
+	delete(positions, contractAddress)
+	slices.Sort(removedPositions)
+	for i := len(removedPositions) - 1; i >= 0; i-- {
+		removedPosition := removedPositions[i]
+		lastPosition := len(*methods) - 1
+
+		// Move the last method into the removed method's position, updating its recorded position.
+		if removedPosition != lastPosition {
+			movedMethod := (*methods)[lastPosition]
+			(*methods)[removedPosition] = movedMethod
+			movedPositions := positions[movedMethod.Address]
+			movedPositions[slices.Index(movedPositions, lastPosition)] = removedPosition
+		}
+		(*methods)[lastPosition] = fuzzerTypes.DeployedContractMethod{}
+		*methods = (*methods)[:lastPosition]
+	}
 }
 
 // testNextCallSequence tests a call message sequence against the underlying FuzzerWorker's Chain and calls every
-// CallSequenceTestFunc registered with the parent Fuzzer to update any test results. If any call message in the
-// sequence is nil, a call message will be created in its place, targeting a state changing method of a contract
+// CallSequenceTestFunc registered with the parent Fuzzer to update any test results. Once the sequence has finished
+// executing, every SequenceCompletedTestFunc registered with the parent Fuzzer is called as well. If any call message
+// in the sequence is nil, a call message will be created in its place, targeting a state changing method of a contract
 // deployed in the Chain.
 // Returns any requests for call sequence shrinking or an error if one occurs.
 func (fw *FuzzerWorker) testNextCallSequence() ([]ShrinkCallSequenceRequest, error) {
@@ -271,26 +603,48 @@ func (fw *FuzzerWorker) testNextCallSequence() ([]ShrinkCallSequenceRequest, err
 	// After testing the sequence, we'll want to rollback changes to reset our testing state.
 	var err error
 	defer func() {
-		// Reset the value set back to the original
-		fw.valueSet = originalValueSet
+		// Reset the value set back to the original. This is done in place, as the value set is shared with our
+		// value generator and mutators.
+		fw.valueSet.CopyFrom(originalValueSet)
 		if err == nil {
 			err = fw.chain.RevertToBlockIndex(fw.testingBaseBlockIndex)
 		}
 	}()
 
+	// Derive the random stream for this sequence from its replay ID, so it can be regenerated later. We record the
+	// time we started, so the duration of the sequence can be tracked.
+	fw.randomProvider.Seed(fw.replayID.randomSeed())
+	startTime := time.Now()
+
 	// Initialize a new sequence within our sequence generator.
 	var isNewSequence bool
 	isNewSequence, err = fw.sequenceGenerator.InitializeNextSequence()
 	if err != nil {
 		return nil, err
 	}
+	if !isNewSequence {
+		fw.workerMetrics().sequencesReplayed.Add(fw.workerMetrics().sequencesReplayed, big.NewInt(1))
+	}
 
 	// Define our shrink requests we'll collect during execution.
 	shrinkCallSequenceRequests := make([]ShrinkCallSequenceRequest, 0)
 
 	// Our "fetch next call" method will generate new calls as needed, if we are generating a new sequence.
+	// Each call made is recorded as progress for the watchdog, and if we were abandoned, we stop executing calls.
 	fetchElementFunc := func(currentIndex int) (*calls.CallSequenceElement, error) {
-		return fw.sequenceGenerator.PopSequenceElement()
+		fw.liveness.beat()
+		if fw.abandoned.Load() {
+			return nil, nil
+		}
+		fw.liveness.setPhase(workerPhaseGeneration)
+		defer fw.liveness.setPhase(workerPhaseExecution)
+		element, err := fw.sequenceGenerator.PopSequenceElement()
+
+		// If we run in a worker process, report the call before executing it, so it can be recovered if we crash.
+		if err == nil && element != nil && fw.fuzzer.workerProcess != nil {
+			err = fw.fuzzer.workerProcess.reportCall(currentIndex, element)
+		}
+		return element, err
 	}
 
 	// Our "post execution check function" method will check coverage and call all testing functions. If one returns a
@@ -298,6 +652,12 @@ func (fw *FuzzerWorker) testNextCallSequence() ([]ShrinkCallSequenceRequest, err
 	// request. Additionally, the execution check function will also attempt to add any return data to the value set for
 	// this call sequence. Note that the value set is reset after each call sequence (see the defer section above)
 	executionCheckFunc := func(currentlyExecutedSequence calls.CallSequence) (bool, error) {
+		// If we were abandoned while the call was executing, a new worker has taken our place, sharing our metrics and
+		// test state, so we stop executing calls without recording any results.
+		if fw.abandoned.Load() {
+			return true, nil
+		}
+
 		// Get the last call sequence element that was executed
 		latestCallSequenceElement := currentlyExecutedSequence[len(currentlyExecutedSequence)-1]
 		// Get the decoded return values and add it to the base value set
@@ -308,26 +668,36 @@ func (fw *FuzzerWorker) testNextCallSequence() ([]ShrinkCallSequenceRequest, err
 		}
 
 		// Check for updates to coverage and corpus.
-		// If we detect coverage changes, add this sequence with weight as 1 + sequences tested (to avoid zero weights)
-		err = fw.fuzzer.corpus.CheckSequenceCoverageAndUpdate(currentlyExecutedSequence, fw.getNewCorpusCallSequenceWeight(), true)
+		err = fw.checkSequenceCoverageAndUpdate(currentlyExecutedSequence, false)
 		if err != nil {
 			return true, err
 		}
 
 		// Loop through each test function, signal our worker tested a call, and collect any requests to shrink
-		// this call sequence.
-		for _, callSequenceTestFunc := range fw.fuzzer.Hooks.CallSequenceTestFuncs {
-			newShrinkRequests, err := callSequenceTestFunc(fw, currentlyExecutedSequence)
-			if err != nil {
-				return true, err
+		// this call sequence. If testing is disabled (exploration mode), we skip testing entirely.
+		if fw.fuzzer.config.Fuzzing.Testing.Enabled {
+			for _, callSequenceTestFunc := range fw.fuzzer.Hooks.CallSequenceTestFuncs {
+				newShrinkRequests, err := callSequenceTestFunc(fw, currentlyExecutedSequence)
+				if err != nil {
+					return true, err
+				}
+				shrinkCallSequenceRequests = append(shrinkCallSequenceRequests, newShrinkRequests...)
 			}
-			shrinkCallSequenceRequests = append(shrinkCallSequenceRequests, newShrinkRequests...)
 		}
 
 		// Update our metrics
 		fw.workerMetrics().callsTested.Add(fw.workerMetrics().callsTested, big.NewInt(1))
+		if !isNewSequence {
+			fw.workerMetrics().callsReplayed.Add(fw.workerMetrics().callsReplayed, big.NewInt(1))
+		}
 		lastCallSequenceElement := currentlyExecutedSequence[len(currentlyExecutedSequence)-1]
-		fw.workerMetrics().gasUsed.Add(fw.workerMetrics().gasUsed, new(big.Int).SetUint64(lastCallSequenceElement.ChainReference.Block.MessageResults[lastCallSequenceElement.ChainReference.TransactionIndex].Receipt.GasUsed))
+		lastMessageResults := lastCallSequenceElement.ChainReference.MessageResults()
+		fw.workerMetrics().gasUsed.Add(fw.workerMetrics().gasUsed, new(big.Int).SetUint64(lastMessageResults.Receipt.GasUsed))
+		fw.recordContractCall(currentlyExecutedSequence)
+		fw.recordSenderAccess(currentlyExecutedSequence)
+		if lastMessageResults.ExecutionResult.Err != nil {
+			fw.workerMetrics().reverts.add(lastCallSequenceElement.Contract, lastCallSequenceElement.Call.Data, lastCallSequenceElement.CalldataProbe, lastMessageResults.ExecutionResult.Err, lastMessageResults.ExecutionResult.ReturnData)
+		}
 
 		// If our fuzzer context or the emergency context is cancelled, exit out immediately without results.
 		if utils.CheckContextDone(fw.fuzzer.ctx) {
@@ -339,33 +709,94 @@ func (fw *FuzzerWorker) testNextCallSequence() ([]ShrinkCallSequenceRequest, err
 	}
 
 	// Execute our call sequence.
-	_, err = calls.ExecuteCallSequenceIteratively(fw.chain, fetchElementFunc, executionCheckFunc)
+	var executedSequence calls.CallSequence
+	executedSequence, err = calls.ExecuteCallSequenceIteratively(fw.chain, fetchElementFunc, executionCheckFunc)
 
 	// If we encountered an error, report it.
 	if err != nil {
 		return nil, err
 	}
 
-	// If our fuzzer context is done, exit out immediately without results.
-	if utils.CheckContextDone(fw.fuzzer.ctx) {
+	// If our fuzzer context is done, or we were abandoned, exit out immediately without results.
+	if utils.CheckContextDone(fw.fuzzer.ctx) || fw.abandoned.Load() {
 		return nil, nil
 	}
 
-	// If this was not a new call sequence, indicate not to save the shrunken result to the corpus again.
+	// If the sequence was executed to completion without violating any test, loop through each sequence completed
+	// test function and collect any requests to shrink this call sequence. This is done prior to reverting the chain,
+	// so the chain state reflects the end of the sequence.
+	if fw.fuzzer.config.Fuzzing.Testing.Enabled && len(shrinkCallSequenceRequests) == 0 && len(executedSequence) > 0 {
+		for _, sequenceCompletedTestFunc := range fw.fuzzer.Hooks.SequenceCompletedTestFuncs {
+			var newShrinkRequests []ShrinkCallSequenceRequest
+			newShrinkRequests, err = sequenceCompletedTestFunc(fw, executedSequence)
+			if err != nil {
+				return nil, err
+			}
+			shrinkCallSequenceRequests = append(shrinkCallSequenceRequests, newShrinkRequests...)
+		}
+	}
+
+	// Record how long the sequence took to execute and test.
+	fw.recordSequenceDuration(executedSequence, time.Since(startTime))
+
+	// If this was not a new call sequence, indicate not to save the shrunken result to the corpus again. Otherwise,
+	// record the replay ID the sequence can be regenerated with.
 	if !isNewSequence {
 		for i := 0; i < len(fw.shrinkCallSequenceRequests); i++ {
 			shrinkCallSequenceRequests[i].RecordResultInCorpus = false
 		}
+	} else {
+		for i := 0; i < len(shrinkCallSequenceRequests); i++ {
+			replayID := fw.replayID
+			shrinkCallSequenceRequests[i].ReplayID = &replayID
+		}
+	}
+
+	// Record when each test was first violated, as shrinking the call sequence which violated it may take a while.
+	for _, shrinkRequest := range shrinkCallSequenceRequests {
+		fw.fuzzer.reportTestCaseDiscovered(shrinkRequest.TestName)
 	}
 
 	// Return our results accordingly.
 	return shrinkCallSequenceRequests, nil
 }
 
+// recordSequenceDuration records the wall-clock time taken to execute and test the provided call sequence, which was
+// just tested under the worker's current ReplayID, in the worker's metrics. If the call sequence was replayed from
+// the corpus and exceeded the configured threshold, it is deprioritized for use in mutations.
+func (fw *FuzzerWorker) recordSequenceDuration(sequence calls.CallSequence, duration time.Duration) {
+	// Determine the corpus file the sequence was replayed from, if any.
+	corpusFileName := ""
+	if fw.sequenceGenerator.replayingCorpusSequence {
+		corpusFileName = fw.sequenceGenerator.corpusFileName
+	}
+
+	// Update our metrics, describing the sequence if it is among the slowest recorded.
+	if fw.workerMetrics().sequenceDurations.record(duration) {
+		slowSequence := SlowSequence{
+			CorpusFile: corpusFileName,
+			Duration:   duration,
+			Calls:      len(sequence),
+		}
+		if corpusFileName == "" {
+			slowSequence.ReplayID = fw.replayID.String()
+		}
+		slowSequence.Contract, slowSequence.Method, slowSequence.MethodGasUsed = dominantSequenceMethod(sequence)
+		fw.workerMetrics().sequenceDurations.addSlowSequence(slowSequence)
+	}
+
+	// Deprioritize slow corpus call sequences, if configured to.
+	threshold := time.Duration(fw.fuzzer.config.Fuzzing.CorpusSlowReplayThreshold) * time.Millisecond
+	if corpusFileName != "" && threshold > 0 && fw.fuzzer.corpus.DeprioritizeSlowCallSequence(corpusFileName, duration, threshold) {
+		fw.fuzzer.logger.Debug("[Worker ", fw.workerIndex, "] Corpus item ", colors.Bold, corpusFileName, colors.Reset,
+			" took ", duration.Round(time.Millisecond), " to replay and was deprioritized for mutations")
+	}
+}
+
 // testShrunkenCallSequence tests a provided shrunken call sequence to verify it continues to satisfy the provided
 // shrink verifier. Chain state is reverted to the testing base prior to returning.
 // Returns a boolean indicating if the shrunken call sequence is valid for a given shrink request, or an error if one occurred.
-func (fw *FuzzerWorker) testShrunkenCallSequence(possibleShrunkSequence calls.CallSequence, shrinkRequest ShrinkCallSequenceRequest) (bool, error) {
+func (fw *FuzzerWorker) testShrunkenCallSequence(possibleShrunkSequence calls.CallSequence, shrinkRequest ShrinkCallSequenceRequest, verificationCache *shrinkVerificationCache) (bool, error) {
 	// After testing the sequence, we'll want to rollback changes to reset our testing state.
 	var err error
 	defer func() {
@@ -376,22 +807,45 @@ func (fw *FuzzerWorker) testShrunkenCallSequence(possibleShrunkSequence calls.Ca
 
 	// Our "fetch next call method" method will simply fetch and fix the call message in case any fields are not correct due to shrinking.
 	fetchElementFunc := func(currentIndex int) (*calls.CallSequenceElement, error) {
-		// If we are at the end of our sequence, return nil indicating we should stop executing.
-		if currentIndex >= len(possibleShrunkSequence) {
+		// Record each call made as progress for the watchdog, so long shrinking is not considered stalled.
+		fw.liveness.beat()
+
+		// If we are at the end of our sequence, or were abandoned, return nil indicating we should stop executing.
+		if currentIndex >= len(possibleShrunkSequence) || fw.abandoned.Load() {
 			return nil, nil
 		}
 
 		possibleShrunkSequence[currentIndex].Call.FillFromTestChainProperties(fw.chain)
+
+		// If we run in a worker process, report the call before executing it, so it can be recovered if we crash.
+		if fw.fuzzer.workerProcess != nil {
+			if err := fw.fuzzer.workerProcess.reportCall(currentIndex, possibleShrunkSequence[currentIndex]); err != nil {
+				return nil, err
+			}
+		}
 		return possibleShrunkSequence[currentIndex], nil
 	}
 
 	// Our "post-execution check" method will check coverage and call all testing functions. If one returns a
 	// request for a shrunk call sequence, we exit our call sequence execution immediately to go fulfill the shrink
 	// request.
+	var sequenceCoverage []common.Hash
+	if verificationCache != nil {
+		sequenceCoverage = make([]common.Hash, 0, len(possibleShrunkSequence))
+	}
 	executionCheckFunc := func(currentlyExecutedSequence calls.CallSequence) (bool, error) {
+		// If we may reuse the verifier's results, record the coverage of the last call before the corpus consumes it.
+		if sequenceCoverage != nil {
+			if coverageHash, ok := callCoverageHash(currentlyExecutedSequence[len(currentlyExecutedSequence)-1]); ok {
+				sequenceCoverage = append(sequenceCoverage, coverageHash)
+			} else {
+				sequenceCoverage = nil
+			}
+		}
+
 		// Check for updates to coverage and corpus (using only the section of the sequence we tested so far).
 		// If we detect coverage changes, add this sequence.
-		seqErr := fw.fuzzer.corpus.CheckSequenceCoverageAndUpdate(currentlyExecutedSequence, fw.getNewCorpusCallSequenceWeight(), true)
+		seqErr := fw.checkSequenceCoverageAndUpdate(currentlyExecutedSequence, true)
 		if seqErr != nil {
 			return true, seqErr
 		}
@@ -407,7 +861,8 @@ func (fw *FuzzerWorker) testShrunkenCallSequence(possibleShrunkSequence calls.Ca
 	}
 
 	// Execute our call sequence.
-	_, err = calls.ExecuteCallSequenceIteratively(fw.chain, fetchElementFunc, executionCheckFunc)
+	var executedSequence calls.CallSequence
+	executedSequence, err = calls.ExecuteCallSequenceIteratively(fw.chain, fetchElementFunc, executionCheckFunc)
 	if err != nil {
 		return false, err
 	}
@@ -419,6 +874,19 @@ func (fw *FuzzerWorker) testShrunkenCallSequence(possibleShrunkSequence calls.Ca
 		return false, nil
 	}
 
+	// If any call was aborted due to the transaction timeout, the shrunken sequence was not executed in full, so it
+	// cannot be verified.
+	if len(executedSequence) != len(possibleShrunkSequence) {
+		return false, nil
+	}
+
+	// If the shrunken sequence followed the same execution path as the best one, our deterministic verifier would
+	// accept it again, so we skip it.
+	if verificationCache.matchesBest(sequenceCoverage) {
+		fw.workerMetrics().shrinkVerificationsSkipped.Add(fw.workerMetrics().shrinkVerificationsSkipped, big.NewInt(1))
+		return true, nil
+	}
+
 	// Check if our verifier signalled that we met our conditions
 	validShrunkSequence := false
 	if len(possibleShrunkSequence) > 0 {
@@ -427,9 +895,84 @@ func (fw *FuzzerWorker) testShrunkenCallSequence(possibleShrunkSequence calls.Ca
 			return false, err
 		}
 	}
+	if validShrunkSequence {
+		verificationCache.setBest(sequenceCoverage)
+	}
 	return validShrunkSequence, nil
 }
 
+// confirmFailure replays the call sequence which caused the provided shrink request from the base testing state, to
+// confirm the failure reproduces before it is shrunk. If it does not, an unreproducible failure is logged along with
+// the receipts of the calls from both executions. Shrink requests without calls cannot be replayed, and are always
+// considered confirmed.
+// Returns a boolean indicating whether the failure was reproduced, or an error if one occurred.
+func (fw *FuzzerWorker) confirmFailure(shrinkRequest ShrinkCallSequenceRequest) (bool, error) {
+	if len(shrinkRequest.CallSequenceToShrink) == 0 {
+		return true, nil
+	}
+
+	// Replay a copy of the call sequence, so the results of its original execution are retained.
+	replayedSequence, err := shrinkRequest.CallSequenceToShrink.Clone()
+	if err != nil {
+		return false, err
+	}
+	reproduced, err := fw.testShrunkenCallSequence(replayedSequence, shrinkRequest, nil)
+	if err != nil {
+		return false, err
+	}
+
+	// If the failure reproduced, or we are shutting down, there is nothing to report.
+	if reproduced || utils.CheckContextDone(fw.fuzzer.emergencyCtx) {
+		return reproduced, nil
+	}
+
+	// Log a diagnostic describing the receipts of both executions, so the difference between them can be examined.
+	outcome := "discarding it"
+	if fw.fuzzer.config.Fuzzing.Testing.ReportUnconfirmedFailures {
+		outcome = "reporting it regardless"
+	}
+	fw.fuzzer.logger.Warn("[Worker ", fw.workerIndex, "] ", colors.RedBold, "Unreproducible failure", colors.Reset, " for ",
+		colors.Bold, shrinkRequest.TestName, colors.Reset, ": the call sequence which caused it did not cause it again when replayed, ",
+		outcome, ". This may indicate state leaked between call sequences.",
+		"\n[Original Execution]", callSequenceReceiptsString(shrinkRequest.CallSequenceToShrink),
+		"\n[Replayed Execution]", callSequenceReceiptsString(replayedSequence))
+	return false, nil
+}
+
+// callSequenceReceiptsString obtains a string describing the receipt of each call in the provided call sequence,
+// one per line.
+func callSequenceReceiptsString(callSequence calls.CallSequence) string {
+	var text string
+	for i, element := range callSequence {
+		if element.ChainReference == nil || element.ChainReference.MessageResults().Receipt == nil {
+			text += fmt.Sprintf("\n%d) not executed", i+1)
+			continue
+		}
+		receipt := element.ChainReference.MessageResults().Receipt
+		text += fmt.Sprintf("\n%d) tx: %v, status: %d, gas used: %d, logs: %d", i+1, receipt.TxHash, receipt.Status, receipt.GasUsed, len(receipt.Logs))
+	}
+	return text
+}
+
+// executeWithExecutionTraces executes the provided call sequence on the worker's chain, attaching execution traces to
+// its calls as described by calls.ExecuteCallSequenceWithExecutionTracer. If verboseTracing is enabled, the rendered
+// trace of each call is written to a new trace artifact as soon as the call executes, so the traces of long call
+// sequences are not held in memory.
+// Returns an error if one occurs.
+func (fw *FuzzerWorker) executeWithExecutionTraces(callSequence calls.CallSequence, verboseTracing bool) error {
+	var traceArtifact *executiontracer.TraceArtifact
+	if verboseTracing {
+		var err error
+		traceArtifact, err = fw.fuzzer.newTraceArtifact()
+		if err != nil {
+			return fmt.Errorf("could not create a trace artifact: %v", err)
+		}
+		defer traceArtifact.Close()
+	}
+	_, err := calls.ExecuteCallSequenceWithExecutionTracer(fw.chain, fw.fuzzer.contractDefinitions, callSequence, verboseTracing, fw.fuzzer.traceLimits(), traceArtifact)
+	return err
+}
+
 // shrinkCallSequence takes a provided call sequence and attempts to shrink it by looking for redundant
 // calls which can be removed, and values which can be minimized, while continuing to satisfy the provided shrink
 // verifier.
@@ -442,15 +985,30 @@ func (fw *FuzzerWorker) testShrunkenCallSequence(possibleShrunkSequence calls.Ca
 func (fw *FuzzerWorker) shrinkCallSequence(shrinkRequest ShrinkCallSequenceRequest) (calls.CallSequence, error) {
 	// Define a variable to track our most optimized sequence across all optimization iterations.
 	optimizedSequence := shrinkRequest.CallSequenceToShrink
+	verificationCache := newShrinkVerificationCache(shrinkRequest)
 
-	// Obtain our shrink limits and begin shrinking.
+	// Obtain our shrink limits and begin shrinking. Each pass only checks whether shrinking ended while it still has
+	// calls to shrink, so we track whether a pass was cut short, and whether this was caused by the shrink limit or by
+	// fuzzing being stopped (or this worker being abandoned). If shrinking is disabled, the call sequence is reported
+	// as it was discovered.
 	shrinkIteration := uint64(0)
 	shrinkLimit := fw.fuzzer.config.Fuzzing.ShrinkLimit
+	shrinkLimitReached := shrinkLimit == 0
+	shrinkingInterrupted := false
 	shrinkingEnded := func() bool {
-		return shrinkIteration >= shrinkLimit || utils.CheckContextDone(fw.fuzzer.emergencyCtx)
+		if utils.CheckContextDone(fw.fuzzer.emergencyCtx) || fw.abandoned.Load() {
+			shrinkingInterrupted = true
+			return true
+		}
+		if shrinkIteration >= shrinkLimit {
+			shrinkLimitReached = true
+			return true
+		}
+		return false
 	}
 	if shrinkLimit > 0 {
-		// The first pass of shrinking is greedy towards trying to remove any unnecessary calls.
+		// The first pass of shrinking is greedy towards trying to remove any unnecessary calls. Setup calls are never
+		// removed, as the rest of the sequence structurally depends on them.
 		// For each call in the sequence, the following removal strategies are used:
 		// 1) Plain removal (lower block/time gap between surrounding blocks, maintain properties of max delay)
 		// 2) Add block/time delay to previous call (retain original block/time, possibly exceed max delays)
@@ -461,6 +1019,10 @@ func (fw *FuzzerWorker) shrinkCallSequence(shrinkRequest ShrinkCallSequenceReque
 
 		for removalStrategy := 0; removalStrategy < 2 && !shrinkingEnded(); removalStrategy++ {
 			for i := len(optimizedSequence) - 1; i >= 0 && !shrinkingEnded(); i-- {
+				if optimizedSequence[i].Setup {
+					continue
+				}
+
 				// Recreate our current optimized sequence without the item at this index
 				possibleShrunkSequence, err := optimizedSequence.Clone()
 				removedCall := possibleShrunkSequence[i]
@@ -478,10 +1040,21 @@ func (fw *FuzzerWorker) shrinkCallSequence(shrinkRequest ShrinkCallSequenceReque
 						possibleShrunkSequence[i-1].BlockNumberDelay += removedCall.BlockNumberDelay
 						possibleShrunkSequence[i-1].BlockTimestampDelay += removedCall.BlockTimestampDelay
 					}
+
+					// Retain the empty blocks mined before the removed call by mining them before the next call.
+					if i < len(possibleShrunkSequence) {
+						possibleShrunkSequence[i].EmptyBlocks += removedCall.EmptyBlocks
+					}
+				}
+
+				// The call which followed the removed call can no longer be bound to its output, so it retains the
+				// argument value it was last executed with instead.
+				if i < len(possibleShrunkSequence) {
+					possibleShrunkSequence[i].OutputBinding = nil
 				}
 
 				// Test the shrunken sequence.
-				validShrunkSequence, err := fw.testShrunkenCallSequence(possibleShrunkSequence, shrinkRequest)
+				validShrunkSequence, err := fw.testShrunkenCallSequence(possibleShrunkSequence, shrinkRequest, verificationCache)
 				shrinkIteration++
 				if err != nil {
 					return nil, err
@@ -494,28 +1067,101 @@ func (fw *FuzzerWorker) shrinkCallSequence(shrinkRequest ShrinkCallSequenceReque
 			}
 		}
 
-		// The second pass of shrinking attempts to shrink values for each call in our call sequence.
-		// This is performed exhaustively in a round-robin fashion for each call, until the shrink limit is hit.
-		for !shrinkingEnded() {
+		// The next pass of shrinking attempts to restore the clean encoding of any calls with malformed call data, so
+		// only the calldata probes needed to reproduce the result remain.
+		for i := len(optimizedSequence) - 1; i >= 0 && !shrinkingEnded(); i-- {
+			if optimizedSequence[i].Frozen || optimizedSequence[i].CalldataProbe == nil || optimizedSequence[i].Call.DataAbiValues == nil {
+				continue
+			}
+
+			// Recreate our current optimized sequence without the calldata probe at this index.
+			possibleShrunkSequence, err := optimizedSequence.Clone()
+			if err != nil {
+				return nil, err
+			}
+			possibleShrunkSequence[i].CalldataProbe = nil
+			possibleShrunkSequence[i].WithDataAbiValues(possibleShrunkSequence[i].Call.DataAbiValues)
+
+			// Test the shrunken sequence.
+			validShrunkSequence, err := fw.testShrunkenCallSequence(possibleShrunkSequence, shrinkRequest, verificationCache)
+			shrinkIteration++
+			if err != nil {
+				return nil, err
+			}
+
+			// If the current sequence satisfied our conditions, set it as our optimized sequence.
+			if validShrunkSequence {
+				optimizedSequence = possibleShrunkSequence
+			}
+		}
+
+		// The next pass of shrinking attempts to remove the empty blocks mined before each call.
+		for i := len(optimizedSequence) - 1; i >= 0 && !shrinkingEnded(); i-- {
+			if optimizedSequence[i].Frozen || optimizedSequence[i].EmptyBlocks == 0 {
+				continue
+			}
+
+			// Recreate our current optimized sequence without the empty blocks at this index.
+			possibleShrunkSequence, err := optimizedSequence.Clone()
+			if err != nil {
+				return nil, err
+			}
+			possibleShrunkSequence[i].EmptyBlocks = 0
+
+			// Test the shrunken sequence.
+			validShrunkSequence, err := fw.testShrunkenCallSequence(possibleShrunkSequence, shrinkRequest, verificationCache)
+			shrinkIteration++
+			if err != nil {
+				return nil, err
+			}
+
+			// If the current sequence satisfied our conditions, set it as our optimized sequence.
+			if validShrunkSequence {
+				optimizedSequence = possibleShrunkSequence
+			}
+		}
+
+		// The final pass of shrinking attempts to shrink values for each call in our call sequence, including the
+		// amount of empty blocks mined before it. Frozen calls, and calls without arguments or empty blocks, are left
+		// unchanged. This is performed exhaustively in a round-robin fashion for each call, until the shrink limit is
+		// hit, so it only ends early if no call has values to shrink.
+		hasShrinkableValues := func(element *calls.CallSequenceElement) bool {
+			if element.Frozen {
+				return false
+			}
+			return element.EmptyBlocks > 0 || (element.Call.DataAbiValues != nil && len(element.Call.DataAbiValues.InputValues) > 0)
+		}
+		shrinkableValues := slices.ContainsFunc(optimizedSequence, hasShrinkableValues)
+		for shrinkableValues && !shrinkingEnded() {
 			for i := len(optimizedSequence) - 1; i >= 0 && !shrinkingEnded(); i-- {
+				if !hasShrinkableValues(optimizedSequence[i]) {
+					continue
+				}
+
 				// Clone the optimized sequence.
 				possibleShrunkSequence, _ := optimizedSequence.Clone()
 
-				// Loop for each argument in the currently indexed call to mutate it.
-				abiValuesMsgData := possibleShrunkSequence[i].Call.DataAbiValues
-				for j := 0; j < len(abiValuesMsgData.InputValues); j++ {
-					mutatedInput, err := valuegeneration.MutateAbiValue(fw.sequenceGenerator.config.ValueGenerator, fw.shrinkingValueMutator, &abiValuesMsgData.Method.Inputs[j].Type, abiValuesMsgData.InputValues[j])
-					if err != nil {
-						return nil, fmt.Errorf("error when shrinking call sequence input argument: %v", err)
+				// If empty blocks are mined before the call, we alternate between reducing their amount and shrinking the
+				// call's arguments, so a failure to shrink one does not prevent the other from being shrunk.
+				if possibleShrunkSequence[i].EmptyBlocks > 0 && fw.randomProvider.Intn(2) == 0 {
+					possibleShrunkSequence[i].EmptyBlocks = fw.randomProvider.Uint64() % possibleShrunkSequence[i].EmptyBlocks
+				} else {
+					// Loop for each argument in the currently indexed call to mutate it.
+					abiValuesMsgData := possibleShrunkSequence[i].Call.DataAbiValues
+					for j := 0; j < len(abiValuesMsgData.InputValues); j++ {
+						mutatedInput, err := valuegeneration.MutateAbiValue(fw.sequenceGenerator.config.ValueGenerator, fw.shrinkingValueMutator, &abiValuesMsgData.Method.Inputs[j].Type, abiValuesMsgData.InputValues[j])
+						if err != nil {
+							return nil, fmt.Errorf("error when shrinking call sequence input argument: %v", err)
+						}
+						abiValuesMsgData.InputValues[j] = mutatedInput
 					}
-					abiValuesMsgData.InputValues[j] = mutatedInput
-				}
 
-				// Re-encode the message's calldata
-				possibleShrunkSequence[i].Call.WithDataAbiValues(abiValuesMsgData)
+					// Re-encode the message's calldata
+					possibleShrunkSequence[i].WithDataAbiValues(abiValuesMsgData)
+				}
 
 				// Test the shrunken sequence.
-				validShrunkSequence, err := fw.testShrunkenCallSequence(possibleShrunkSequence, shrinkRequest)
+				validShrunkSequence, err := fw.testShrunkenCallSequence(possibleShrunkSequence, shrinkRequest, verificationCache)
 				shrinkIteration++
 				if err != nil {
 					return nil, err
@@ -530,9 +1176,20 @@ func (fw *FuzzerWorker) shrinkCallSequence(shrinkRequest ShrinkCallSequenceReque
 		fw.workerMetrics().shrinking = false
 	}
 
+	// If we were abandoned while shrinking, a new worker has taken our place, and the failure is shrunk by another
+	// worker once it is rediscovered, so we do not report it.
+	if fw.abandoned.Load() {
+		return optimizedSequence, nil
+	}
+
 	// If the shrink request wanted the sequence recorded in the corpus, do so now.
 	if shrinkRequest.RecordResultInCorpus {
-		err := fw.fuzzer.corpus.AddTestResultCallSequence(optimizedSequence, fw.getNewCorpusCallSequenceWeight(), true)
+		var err error
+		if shrinkRequest.CorpusWeightMultiplier != nil {
+			err = fw.corpusStagingBuffer.AddWeightedTestResultCallSequence(optimizedSequence, fw.getNewCorpusCallSequenceWeight(), shrinkRequest.CorpusWeightMultiplier)
+		} else {
+			err = fw.corpusStagingBuffer.AddTestResultCallSequence(optimizedSequence, fw.getNewCorpusCallSequenceWeight())
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -544,6 +1201,12 @@ func (fw *FuzzerWorker) shrinkCallSequence(shrinkRequest ShrinkCallSequenceReque
 		return nil, err
 	}
 
+	// Log the replay ID of the sequence which was shrunk, so it can be regenerated when debugging.
+	if shrinkRequest.ReplayID != nil {
+		fw.fuzzer.logger.Info("[Worker ", fw.workerIndex, "] Call sequence for ", colors.GreenBold, shrinkRequest.TestName,
+			colors.Reset, " was generated with replay ID ", colors.Bold, shrinkRequest.ReplayID.String(), colors.Reset)
+	}
+
 	// Shrinking is complete. If our config specified we want all result sequences to have execution traces attached,
 	// attach them now to each element in the sequence. Otherwise, call sequences will only have traces that the
 	// test providers choose to attach themselves.
@@ -556,30 +1219,75 @@ func (fw *FuzzerWorker) shrinkCallSequence(shrinkRequest ShrinkCallSequenceReque
 	if err = fw.chain.RevertToBlockIndex(fw.testingBaseBlockIndex); err != nil {
 		return nil, err
 	}
-	return optimizedSequence, err
+
+	// If the shrink request was made for a test failure, emit an event indicating it was resolved.
+	if shrinkRequest.FailureID != "" {
+		err = fw.publishTestFailureResolved(shrinkRequest, optimizedSequence, shrinkLimitReached, shrinkingInterrupted)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return optimizedSequence, nil
 }
 
-// run takes a base Chain in a setup state ready for testing, clones it, and begins executing fuzzed transaction calls
-// and asserting properties are upheld. This runs until Fuzzer.ctx or Fuzzer.emergencyCtx cancels the operation.
-// Returns a boolean indicating whether Fuzzer.ctx or Fuzzer.emergencyCtx has indicated we cancel the operation, and an
-// error if one occurred.
-func (fw *FuzzerWorker) run(baseTestChain *chain.TestChain) (bool, error) {
+// publishTestFailureResolved emits an event indicating the test failure which caused the provided shrink request was
+// resolved to the provided shrunken call sequence. If the call sequence was recorded in the corpus, it is first
+// written to disk, even if the corpus is written in the background, so the event can describe where it was written.
+// Returns an error if one occurs.
+func (fw *FuzzerWorker) publishTestFailureResolved(shrinkRequest ShrinkCallSequenceRequest, shrunkenCallSequence calls.CallSequence, shrinkLimitReached bool, shrinkingInterrupted bool) error {
+	// Write the recorded call sequence to disk and obtain its path.
+	reproducerPath := ""
+	if shrinkRequest.RecordResultInCorpus {
+		err := fw.flushCorpusStagingBuffer()
+		if err != nil {
+			return err
+		}
+		err = fw.fuzzer.corpus.Flush()
+		if err != nil {
+			return err
+		}
+		reproducerPath, err = fw.fuzzer.corpus.TestResultCallSequenceFilePath(shrunkenCallSequence)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := fw.fuzzer.Events.TestFailureResolved.Publish(FuzzerTestFailureResolvedEvent{
+		Worker:               fw,
+		TestName:             shrinkRequest.TestName,
+		FailureID:            shrinkRequest.FailureID,
+		CallSequence:         shrunkenCallSequence,
+		ShrinkLimitReached:   shrinkLimitReached,
+		ShrinkingInterrupted: shrinkingInterrupted,
+		ReproducerPath:       reproducerPath,
+	})
+	if err != nil {
+		return fmt.Errorf("error returned by an event handler when a worker emitted a test failure resolved event: %w", err)
+	}
+	return nil
+}
+
+// setUpChain takes a base Chain in a setup state ready for testing and clones it as the worker's chain, tracking the
+// contracts deployed on it and the methods it will call on them. The caller is responsible for closing the chain if
+// no error is returned.
+// Returns an error if one occurred.
+func (fw *FuzzerWorker) setUpChain(baseTestChain *chain.TestChain) error {
 	// Clone our chain, attaching our necessary components for fuzzing post-genesis, prior to all blocks being copied.
 	// This means any tracers added or events subscribed to within this inner function are done so prior to chain
 	// setup (initial contract deployments), so data regarding that can be tracked as well.
 	var err error
 	fw.chain, err = baseTestChain.Clone(func(initializedChain *chain.TestChain) error {
 		// Subscribe our chain event handlers
-		initializedChain.Events.ContractDeploymentAddedEventEmitter.Subscribe(fw.onChainContractDeploymentAddedEvent)
-		initializedChain.Events.ContractDeploymentRemovedEventEmitter.Subscribe(fw.onChainContractDeploymentRemovedEvent)
+		initializedChain.Events.ContractDeploymentAddedEventEmitter.SubscribeNamed("fuzzer worker", fw.onChainContractDeploymentAddedEvent)
+		initializedChain.Events.ContractDeploymentRemovedEventEmitter.SubscribeNamed("fuzzer worker", fw.onChainContractDeploymentRemovedEvent)
 
 		if err != nil {
-			return fmt.Errorf("error returned by an event handler when emitting a worker chain created event: %v", err)
+			return fmt.Errorf("error returned by an event handler when emitting a worker chain created event: %w", err)
 		}
 
 		// If we have coverage-guided fuzzing enabled, create a tracer to collect coverage and connect it to the chain.
 		if fw.fuzzer.config.Fuzzing.CoverageEnabled {
-			fw.coverageTracer = coverage.NewCoverageTracer()
+			fw.coverageTracer = coverage.NewCoverageTracer(fw.fuzzer.config.Fuzzing.InitCoverageEnabled)
 			initializedChain.AddTracer(fw.coverageTracer.NativeTracer(), true, false)
 		}
 
@@ -596,28 +1304,57 @@ func (fw *FuzzerWorker) run(baseTestChain *chain.TestChain) (bool, error) {
 
 	// If we encountered an error during cloning, return it.
 	if err != nil {
-		return false, err
+		return err
 	}
 
-	// Defer the closing of the test chain object
-	defer fw.chain.Close()
-
 	// Emit an event indicating the worker has set up its chain.
 	err = fw.Events.FuzzerWorkerChainSetup.Publish(FuzzerWorkerChainSetupEvent{
 		Worker: fw,
 		Chain:  fw.chain,
 	})
 	if err != nil {
-		return false, fmt.Errorf("error returned by an event handler when emitting a worker chain setup event: %v", err)
+		fw.chain.Close()
+		return fmt.Errorf("error returned by an event handler when emitting a worker chain setup event: %w", err)
+	}
+	return nil
+}
+
+// run takes a base Chain in a setup state ready for testing, clones it, and begins executing fuzzed transaction calls
+// and asserting properties are upheld. This runs until Fuzzer.ctx or Fuzzer.emergencyCtx cancels the operation.
+// Returns a boolean indicating whether Fuzzer.ctx or Fuzzer.emergencyCtx has indicated we cancel the operation, and an
+// error if one occurred.
+func (fw *FuzzerWorker) run(baseTestChain *chain.TestChain) (bool, error) {
+	// Clone our chain and set it up for fuzzing.
+	err := fw.setUpChain(baseTestChain)
+	if err != nil {
+		return false, err
+	}
+
+	// Defer the closing of the test chain object
+	defer fw.closeChain()
+
+	// If the fuzzer monitors its workers for stalls, register this worker for as long as it is running.
+	if fw.fuzzer.workerWatchdog != nil {
+		fw.liveness.beat()
+		fw.fuzzer.workerWatchdog.watch(fw)
+		defer fw.fuzzer.workerWatchdog.unwatch(fw)
 	}
 
 	// Increase our generation metric as we successfully generated a test node
 	fw.workerMetrics().workerStartupCount.Add(fw.workerMetrics().workerStartupCount, big.NewInt(1))
+	fw.workerGeneration = fw.workerMetrics().workerStartupCount.Uint64()
 
 	// Save the current block index as all contracts have been deployed at this point, and we'll want to revert
 	// to this state between testing.
 	fw.testingBaseBlockIndex = uint64(len(fw.chain.CommittedBlocks()))
 
+	// Now that the initial deployments are complete, verify we have methods to call, so we can describe why none were
+	// selected rather than failing to generate a call sequence.
+	err = fw.checkFuzzableMethods()
+	if err != nil {
+		return false, err
+	}
+
 	// Enter the main fuzzing loop. In the main fuzzing loop, we will always handle shrink requests first.
 	// While there are no shrink requests, we will execute call sequence restricted by our memory database size based
 	// on our config variable. When the limit is reached, we exit this method gracefully, which will cause the fuzzer
@@ -626,6 +1363,13 @@ func (fw *FuzzerWorker) run(baseTestChain *chain.TestChain) (bool, error) {
 	sequencesTested := 0
 	fuzzingComplete := false
 	for sequencesTested <= fw.fuzzer.config.Fuzzing.WorkerResetLimit {
+		// Record our progress for the watchdog, and exit if we were abandoned after stalling, as a new worker has taken
+		// our place.
+		fw.liveness.beat()
+		if fw.abandoned.Load() {
+			return false, nil
+		}
+
 		// Immediately exit if the emergency context is triggered
 		if utils.CheckContextDone(fw.fuzzer.emergencyCtx) {
 			return true, nil
@@ -641,44 +1385,67 @@ func (fw *FuzzerWorker) run(baseTestChain *chain.TestChain) (bool, error) {
 				Worker: fw,
 			})
 			if err != nil {
-				return true, fmt.Errorf("error returned by an event handler when a worker emitted an event indicating testing is complete: %v", err)
+				return true, fmt.Errorf("error returned by an event handler when a worker emitted an event indicating testing is complete: %w", err)
 			}
 		}
 
-		// Run all shrink requests
-		for _, shrinkCallSequenceRequest := range fw.shrinkCallSequenceRequests {
-			// Immediately exit if we are shutting down
-			if utils.CheckContextDone(fw.fuzzer.emergencyCtx) {
-				return true, nil
-			}
-			_, err = fw.shrinkCallSequence(shrinkCallSequenceRequest)
-			if err != nil {
-				return false, err
-			}
+		// Run our shrink requests, or queue them until a shrinking slot is free.
+		err = fw.runShrinkRequests(fuzzingComplete)
+		if err != nil {
+			return false, err
+		}
+		if utils.CheckContextDone(fw.fuzzer.emergencyCtx) {
+			return true, nil
 		}
 
-		// Clean up the shrink requests
-		fw.shrinkCallSequenceRequests = nil
+		// If we are fuzzing deterministically and finished testing, wait for the other workers to finish, rather than
+		// testing further call sequences.
+		if !fuzzingComplete && fw.deterministicTestingFinished() {
+			fw.liveness.setPhase(workerPhaseIdle)
+			select {
+			case <-fw.fuzzer.ctx.Done():
+			case <-fw.fuzzer.emergencyCtx.Done():
+			}
+			continue
+		}
 
 		// If we have cancelled fuzzing, return now
 		if fuzzingComplete {
 			return true, nil
 		}
 
+		// If we have no methods to call, as we are waiting for dynamically deployed targets, check again later.
+		if fw.awaitFuzzableMethods() {
+			continue
+		}
+
 		// Emit an event indicating the worker is about to test a new call sequence.
+		fw.liveness.setPhase(workerPhaseGeneration)
 		err := fw.Events.CallSequenceTesting.Publish(FuzzerWorkerCallSequenceTestingEvent{
 			Worker: fw,
 		})
 		if err != nil {
-			return false, fmt.Errorf("error returned by an event handler when a worker emitted an event indicating testing of a new call sequence is starting: %v", err)
+			return false, fmt.Errorf("error returned by an event handler when a worker emitted an event indicating testing of a new call sequence is starting: %w", err)
 		}
 
-		// Test a new sequence
+		// Test a new sequence, identified by its replay ID.
+		fw.replayID = ReplayID{
+			Seed:             fw.fuzzer.seed,
+			WorkerIndex:      fw.workerIndex,
+			WorkerGeneration: fw.workerGeneration,
+			SequenceIndex:    uint64(sequencesTested),
+		}
 		shrinkRequests, err := fw.testNextCallSequence()
 		if err != nil {
 			return false, err
 		}
 
+		// If we were abandoned while testing the call sequence, a new worker has taken our place, so we exit without
+		// reporting its results.
+		if fw.abandoned.Load() {
+			return false, nil
+		}
+
 		// Add any new shrink requests to our list
 		fw.shrinkCallSequenceRequests = append(fw.shrinkCallSequenceRequests, shrinkRequests...)
 
@@ -687,7 +1454,13 @@ func (fw *FuzzerWorker) run(baseTestChain *chain.TestChain) (bool, error) {
 			Worker: fw,
 		})
 		if err != nil {
-			return false, fmt.Errorf("error returned by an event handler when a worker emitted an event indicating testing of a new call sequence has concluded: %v", err)
+			return false, fmt.Errorf("error returned by an event handler when a worker emitted an event indicating testing of a new call sequence has concluded: %w", err)
+		}
+
+		// Add the call sequences staged for the corpus while testing this call sequence (or shrinking prior ones).
+		err = fw.flushCorpusStagingBuffer()
+		if err != nil {
+			return false, err
 		}
 
 		// Update our sequences tested metrics
@@ -695,6 +1468,15 @@ func (fw *FuzzerWorker) run(baseTestChain *chain.TestChain) (bool, error) {
 		sequencesTested++
 	}
 
+	// Queue any shrink requests made by the last call sequence we tested, so they are shrunk by another worker. If
+	// fuzzing completed in the meantime, other workers may have already exited, so we shrink every queued request.
+	if fw.fuzzer.shrinkScheduler != nil {
+		err = fw.runShrinkRequests(utils.CheckContextDone(fw.fuzzer.ctx))
+		if err != nil {
+			return false, err
+		}
+	}
+
 	// We have not cancelled fuzzing operations, but this worker exited, signalling for it to be regenerated.
 	return false, nil
 }
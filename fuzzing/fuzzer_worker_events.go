@@ -3,7 +3,9 @@ package fuzzing
 import (
 	"github.com/crytic/medusa/chain"
 	"github.com/crytic/medusa/events"
+	"github.com/crytic/medusa/fuzzing/calls"
 	"github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/coverage"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -32,6 +34,10 @@ type FuzzerWorkerEvents struct {
 	// new call sequence.
 	CallSequenceTested events.EventEmitter[FuzzerWorkerCallSequenceTestedEvent]
 
+	// NewCoverage emits events when the FuzzerWorker has executed a call sequence which achieved new coverage and was
+	// added to the corpus.
+	NewCoverage events.EventEmitter[FuzzerWorkerNewCoverageEvent]
+
 	// TestingComplete emits events when the FuzzerWorker has completed testing of call sequences and is about to exit
 	// the fuzzing loop.
 	TestingComplete events.EventEmitter[FuzzerWorkerTestingCompleteEvent]
@@ -49,6 +55,26 @@ type FuzzerWorkerContractAddedEvent struct {
 	// ContractDefinition describes the compiled contract artifact definition which the fuzzing.Fuzzer matched to the
 	// deployed bytecode. If this could not be resolved, a nil value is provided.
 	ContractDefinition *contracts.Contract
+
+	// Creator describes the address of the account or contract which created the contract.
+	Creator common.Address
+
+	// InitBytecodeHash describes the keccak256 hash of the init bytecode used to create the contract. For deployments
+	// made by a transaction, this includes any constructor arguments.
+	InitBytecodeHash common.Hash
+
+	// BlockNumber describes the number of the block containing the transaction which created the contract.
+	BlockNumber uint64
+
+	// TransactionIndex describes the index of the transaction which created the contract, within its block.
+	TransactionIndex uint
+
+	// TransactionHash describes the hash of the transaction which created the contract.
+	TransactionHash common.Hash
+
+	// DynamicDeployment describes whether the contract was deployed dynamically by another contract (e.g. by a
+	// factory), rather than by a transaction.
+	DynamicDeployment bool
 }
 
 // FuzzerWorkerContractDeletedEvent describes an event where a fuzzing.FuzzerWorker detects a previously reported
@@ -99,6 +125,23 @@ type FuzzerWorkerCallSequenceTestedEvent struct {
 	Worker *FuzzerWorker
 }
 
+// FuzzerWorkerNewCoverageEvent describes an event where a fuzzing.FuzzerWorker has executed a call sequence which
+// achieved new coverage and was added to the corpus.
+type FuzzerWorkerNewCoverageEvent struct {
+	// Worker represents the instance of the fuzzing.FuzzerWorker for which the event occurred.
+	Worker *FuzzerWorker
+
+	// CallSequence describes the call sequence which was added to the corpus.
+	CallSequence calls.CallSequence
+
+	// CoverageDelta describes the coverage markers which were newly achieved by the call sequence.
+	CoverageDelta *coverage.CoverageDelta
+
+	// Shrinking indicates whether the coverage was achieved while verifying a shrunken call sequence, rather than by a
+	// call sequence the worker was fuzzing with.
+	Shrinking bool
+}
+
 // FuzzerWorkerTestingCompleteEvent describes an event where a fuzzing.FuzzerWorker has completed testing of call sequences
 // and is about to exit the fuzzing loop.
 type FuzzerWorkerTestingCompleteEvent struct {
@@ -1,14 +1,37 @@
 package fuzzing
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
 	"math/big"
+	"math/rand"
+	"strconv"
+	"strings"
 
 	"github.com/crytic/medusa/fuzzing/calls"
 	"github.com/crytic/medusa/fuzzing/contracts"
 	"github.com/crytic/medusa/fuzzing/valuegeneration"
 	"github.com/crytic/medusa/utils"
 	"github.com/crytic/medusa/utils/randomutils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// callTargetSteeringProbability describes the probability that an address parameter which is immediately followed
+	// by a bytes parameter (a call target) is generated as the address of a deployed contract.
+	callTargetSteeringProbability = 0.9
+
+	// callTargetCallDataProbability describes the probability that a bytes parameter which immediately follows a call
+	// target parameter is generated as call data for a method of the contract at the call target address.
+	callTargetCallDataProbability = 0.5
+
+	// overBalanceValueProbability describes the probability that a call to a payable method intentionally sends more
+	// value than its sender can afford, rather than a value constrained to the sender's balance.
+	overBalanceValueProbability = 0.01
 )
 
 // CallSequenceGenerator generates call sequences iteratively per element, for use in fuzzing campaigns. It is attached
@@ -34,11 +57,77 @@ type CallSequenceGenerator struct {
 	// to its fetching by PopSequenceElement.
 	prefetchModifyCallFunc PrefetchModifyCallFunc
 
+	// replayingCorpusSequence indicates whether the baseSequence is an un-executed corpus call sequence, which should
+	// be replayed verbatim rather than modified prior to being fetched by PopSequenceElement.
+	replayingCorpusSequence bool
+
+	// corpusFileName describes the name of the corpus file the baseSequence was loaded from, if
+	// replayingCorpusSequence is true.
+	corpusFileName string
+
 	// mutationStrategyChooser is a weighted random selector of functions that prepare the CallSequenceGenerator with
 	// a baseSequence derived from corpus entries.
 	mutationStrategyChooser *randomutils.WeightedRandomChooser[CallSequenceGeneratorMutationStrategy]
 }
 
+// ReplayID uniquely identifies a call sequence tested by a FuzzerWorker in a fuzzing campaign. The random stream used
+// to generate each call sequence is derived from its ReplayID, so the sequence can later be regenerated using
+// CallSequenceGenerator.ReconstructSequence.
+type ReplayID struct {
+	// Seed describes the seed of the fuzzing campaign.
+	Seed int64
+
+	// WorkerIndex describes the index of the FuzzerWorker which tested the call sequence.
+	WorkerIndex int
+
+	// WorkerGeneration describes the number of times the FuzzerWorker at WorkerIndex was created, including the
+	// instance which tested the call sequence.
+	WorkerGeneration uint64
+
+	// SequenceIndex describes the number of call sequences the FuzzerWorker tested prior to this one.
+	SequenceIndex uint64
+}
+
+// ParseReplayID parses a ReplayID from its string representation, as produced by ReplayID.String.
+// Returns the parsed ReplayID, or an error if one occurs.
+func ParseReplayID(replayID string) (ReplayID, error) {
+	var id ReplayID
+	parts := strings.Split(replayID, ":")
+	if len(parts) != 4 {
+		return id, fmt.Errorf("could not parse replay ID '%s', expected four colon-separated components", replayID)
+	}
+	var err error
+	if id.Seed, err = strconv.ParseInt(parts[0], 10, 64); err != nil {
+		return id, fmt.Errorf("could not parse replay ID '%s', invalid seed: %v", replayID, err)
+	}
+	if id.WorkerIndex, err = strconv.Atoi(parts[1]); err != nil || id.WorkerIndex < 0 {
+		return id, fmt.Errorf("could not parse replay ID '%s', invalid worker index", replayID)
+	}
+	if id.WorkerGeneration, err = strconv.ParseUint(parts[2], 10, 64); err != nil {
+		return id, fmt.Errorf("could not parse replay ID '%s', invalid worker generation: %v", replayID, err)
+	}
+	if id.SequenceIndex, err = strconv.ParseUint(parts[3], 10, 64); err != nil {
+		return id, fmt.Errorf("could not parse replay ID '%s', invalid sequence index: %v", replayID, err)
+	}
+	return id, nil
+}
+
+// String returns a string representation of the ReplayID, which can be parsed with ParseReplayID.
+func (r ReplayID) String() string {
+	return fmt.Sprintf("%d:%d:%d:%d", r.Seed, r.WorkerIndex, r.WorkerGeneration, r.SequenceIndex)
+}
+
+// randomSeed derives the seed for the random stream used to generate the call sequence identified by the ReplayID.
+func (r ReplayID) randomSeed() int64 {
+	b := make([]byte, 32)
+	binary.BigEndian.PutUint64(b[0:], uint64(r.Seed))
+	binary.BigEndian.PutUint64(b[8:], uint64(r.WorkerIndex))
+	binary.BigEndian.PutUint64(b[16:], r.WorkerGeneration)
+	binary.BigEndian.PutUint64(b[24:], r.SequenceIndex)
+	hash := sha256.Sum256(b)
+	return int64(binary.BigEndian.Uint64(hash[:8]))
+}
+
 // CallSequenceGeneratorConfig defines the configuration for a CallSequenceGenerator to be created and used by a
 // FuzzerWorker to generate call sequences in a fuzzing campaign.
 type CallSequenceGeneratorConfig struct {
@@ -86,6 +175,11 @@ type CallSequenceGeneratorConfig struct {
 	// number of calls from each.
 	RandomMutatedInterleaveAtRandomWeight uint64
 
+	// RandomPermutedSendersWeight defines the weight that the CallSequenceGenerator should use the call sequence
+	// generation strategy of taking the head of a corpus sequence (without mutations) and consistently replacing each
+	// of its senders with another sender, so the same operations are performed by different accounts.
+	RandomPermutedSendersWeight uint64
+
 	// ValueGenerator defines the value provider to use when generating new values for call sequences. This is used both
 	// for ABI call data generation, and generation of additional values such as the "value" field of a
 	// transaction/call.
@@ -179,6 +273,13 @@ func NewCallSequenceGenerator(worker *FuzzerWorker, config *CallSequenceGenerato
 			},
 			new(big.Int).SetUint64(config.RandomMutatedInterleaveAtRandomWeight),
 		),
+		randomutils.NewWeightedRandomChoice(
+			CallSequenceGeneratorMutationStrategy{
+				CallSequenceGeneratorFunc: callSeqGenFuncPermuteSenders,
+				PrefetchModifyCallFunc:    nil,
+			},
+			new(big.Int).SetUint64(config.RandomPermutedSendersWeight),
+		),
 	)
 
 	return generator
@@ -189,17 +290,44 @@ func NewCallSequenceGenerator(worker *FuzzerWorker, config *CallSequenceGenerato
 // Returns a boolean indicating whether the initialized sequence is a newly generated sequence (rather than an
 // unmodified one loaded from the corpus), or an error if one occurred.
 func (g *CallSequenceGenerator) InitializeNextSequence() (bool, error) {
-	// Reset the state of our generator.
-	g.baseSequence = make(calls.CallSequence, g.worker.fuzzer.config.Fuzzing.CallSequenceLength)
+	return g.initializeSequence(true)
+}
+
+// initializeSequence prepares the CallSequenceGenerator for generating a new sequence. If replayUnexecuted is true,
+// any previously un-executed corpus call sequence is prepared first, rather than generating a new sequence.
+// Returns a boolean indicating whether the initialized sequence is a newly generated sequence (rather than an
+// unmodified one loaded from the corpus), or an error if one occurred.
+func (g *CallSequenceGenerator) initializeSequence(replayUnexecuted bool) (bool, error) {
+	// Reset the state of our generator. Corpus call sequences are replayed in full, even if they are longer than the
+	// length of generated call sequences.
+	g.baseSequence = make(calls.CallSequence, g.worker.fuzzer.SequenceLength())
 	g.fetchIndex = 0
 	g.prefetchModifyCallFunc = nil
+	g.replayingCorpusSequence = false
+	g.corpusFileName = ""
 
 	// Check if there are any previously un-executed corpus call sequences. If there are, the fuzzer should execute
 	// those first.
-	unexecutedSequence := g.worker.fuzzer.corpus.UnexecutedCallSequence()
-	if unexecutedSequence != nil {
-		g.baseSequence = *unexecutedSequence
-		return false, nil
+	if replayUnexecuted {
+		unexecutedSequence, fileName := g.worker.corpusUnexecutedCallSequence()
+		if unexecutedSequence != nil {
+			g.baseSequence = *unexecutedSequence
+			g.replayingCorpusSequence = true
+			g.corpusFileName = fileName
+			return false, nil
+		}
+	}
+
+	// If the worker has no methods to call, as it is waiting for dynamically deployed targets, it cannot generate
+	// calls. Instead, we mutate a corpus call sequence in full, as it may deploy the targets the worker is waiting for.
+	if !g.worker.hasFuzzableMethods() {
+		corpusSequence, err := g.worker.corpusMutationTargetSequence()
+		if err != nil {
+			return true, fmt.Errorf("could not obtain a corpus call sequence while waiting for dynamically deployed targets: %v", err)
+		}
+		g.baseSequence = corpusSequence
+		g.prefetchModifyCallFunc = prefetchModifyCallFuncMutate
+		return true, nil
 	}
 
 	// We'll decide whether to create a new call sequence or mutating existing corpus call sequences. Any entries we
@@ -207,14 +335,14 @@ func (g *CallSequenceGenerator) InitializeNextSequence() (bool, error) {
 
 	// If this provider has no corpus mutation methods or corpus call sequences, we return a call sequence with
 	// nil elements to signal that we want an entirely new sequence.
-	if g.mutationStrategyChooser.ChoiceCount() == 0 || g.worker.fuzzer.corpus.ActiveMutableSequenceCount() == 0 {
+	if g.mutationStrategyChooser.ChoiceCount() == 0 || g.worker.corpusActiveMutableSequenceCount() == 0 {
 		return true, nil
 	}
 
 	// Determine whether we will generate a corpus based mutated sequence.
 	if g.worker.randomProvider.Float32() > g.config.NewSequenceProbability {
 		// Get a random mutator function.
-		corpusMutationFunc, err := g.mutationStrategyChooser.Choose()
+		corpusMutationFunc, err := g.mutationStrategyChooser.ChooseWithRand(g.worker.randomProvider)
 		if err != nil {
 			return true, fmt.Errorf("could not generate a corpus mutation derived call sequence due to an error obtaining a mutation method: %v", err)
 		}
@@ -232,6 +360,52 @@ func (g *CallSequenceGenerator) InitializeNextSequence() (bool, error) {
 	return true, nil
 }
 
+// ReconstructSequence regenerates the call sequence which was tested under the provided ReplayID. The parent
+// FuzzerWorker's random provider is re-seeded from the ReplayID and the sequence is generated and executed exactly as
+// it would be while fuzzing, so return values and deployments observed by earlier calls influence later ones in the
+// same way. The chain and value set are restored prior to returning.
+//
+// Reconstruction is only faithful if the fuzzer was configured with the same seed and its corpus contains the same
+// call sequences it did when the sequence was originally tested. Because execution is not stopped at the first test
+// failure, the originally tested sequence is a prefix of the reconstructed one.
+// Returns the reconstructed call sequence, or an error if one occurs.
+func (g *CallSequenceGenerator) ReconstructSequence(replayID ReplayID) (calls.CallSequence, error) {
+	// Re-derive the random stream used for this sequence and snapshot our value set so it can be restored.
+	g.worker.randomProvider.Seed(replayID.randomSeed())
+	originalValueSet := g.worker.valueSet.Clone()
+	defer g.worker.valueSet.CopyFrom(originalValueSet)
+
+	// Un-executed corpus sequences are never reconstructed, as they are replayed from the corpus rather than generated.
+	_, err := g.initializeSequence(false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute the sequence, adding return values to the value set as the FuzzerWorker does while fuzzing.
+	fetchElementFunc := func(currentIndex int) (*calls.CallSequenceElement, error) {
+		return g.PopSequenceElement()
+	}
+	executionCheckFunc := func(currentlyExecutedSequence calls.CallSequence) (bool, error) {
+		latestCallSequenceElement := currentlyExecutedSequence[len(currentlyExecutedSequence)-1]
+		decodedReturnValues, err := latestCallSequenceElement.DecodedReturnValues()
+		if decodedReturnValues != nil && err == nil {
+			g.worker.valueSet.Add(decodedReturnValues)
+		}
+		return false, nil
+	}
+	sequence, err := calls.ExecuteCallSequenceIteratively(g.worker.chain, fetchElementFunc, executionCheckFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revert to our testing base so the worker's state is left untouched.
+	err = g.worker.chain.RevertToBlockIndex(g.worker.testingBaseBlockIndex)
+	if err != nil {
+		return nil, err
+	}
+	return sequence, nil
+}
+
 // PopSequenceElement obtains the next element for our call sequence requested by InitializeNextSequence. If there are no elements
 // left to return, this method returns nil. If an error occurs, it is returned instead.
 func (g *CallSequenceGenerator) PopSequenceElement() (*calls.CallSequenceElement, error) {
@@ -243,9 +417,27 @@ func (g *CallSequenceGenerator) PopSequenceElement() (*calls.CallSequenceElement
 	// Obtain our base call element
 	element := g.baseSequence[g.fetchIndex]
 
+	// If the element was derived from the corpus but targets a contract which is no longer deployed (e.g. it was
+	// destroyed earlier in this sequence), we discard it and generate a new call against a live target instead.
+	// Un-executed corpus sequences are replayed verbatim for determinism, so we leave those untouched.
+	if element != nil && !g.replayingCorpusSequence && !g.isDeployedTarget(element) {
+		element = nil
+	}
+
+	// If we have no methods to generate a new call with, as we are waiting for dynamically deployed targets, we drop
+	// the element from our base sequence and move on to the next one instead.
+	if element == nil && !g.worker.hasFuzzableMethods() {
+		g.baseSequence = slices.Delete(g.baseSequence, g.fetchIndex, g.fetchIndex+1)
+		return g.PopSequenceElement()
+	}
+
+	// Refresh the values derived from the current chain state, so they can be used to generate or mutate this call.
+	g.worker.refreshChainContextValues()
+
 	// If it is nil, we generate an entirely new call. Otherwise, we apply pre-execution modifications.
 	var err error
-	if element == nil {
+	isNewElement := element == nil
+	if isNewElement {
 		element, err = g.generateNewElement()
 		if err != nil {
 			return nil, err
@@ -265,12 +457,85 @@ func (g *CallSequenceGenerator) PopSequenceElement() (*calls.CallSequenceElement
 	// Update the element with the current nonce for the associated chain.
 	element.Call.FillFromTestChainProperties(g.worker.chain)
 
+	// Corpus-derived calls may send more value than their sender can currently afford (e.g. if it spent its balance
+	// earlier in this sequence), so we constrain their value as we do for newly generated calls. Un-executed corpus
+	// sequences are replayed verbatim.
+	if !isNewElement && !g.replayingCorpusSequence {
+		element.Call.ClampValue(g.worker.chain, g.maxTransactionValue())
+	}
+
 	// Update our base sequence, advance our position, and return the processed element from this round.
 	g.baseSequence[g.fetchIndex] = element
 	g.fetchIndex++
 	return element, nil
 }
 
+// isDeployedTarget checks whether the provided call sequence element targets a contract which is currently deployed
+// to the CallSequenceGenerator's parent FuzzerWorker chain. Elements which do not target an address (contract
+// creations) are always considered to have a deployed target.
+// Returns a boolean indicating whether the element's target is currently deployed.
+func (g *CallSequenceGenerator) isDeployedTarget(element *calls.CallSequenceElement) bool {
+	if element.Call == nil || element.Call.To == nil {
+		return true
+	}
+	_, deployed := g.worker.deployedContracts[*element.Call.To]
+	return deployed
+}
+
+// generateBlockDelays generates the block number and timestamp delays for a new call sequence element, as described
+// by the configured block delay mode.
+// Returns the block number delay and block timestamp delay.
+func (g *CallSequenceGenerator) generateBlockDelays() (uint64, uint64) {
+	maxBlockNumberDelay := g.worker.fuzzer.config.Fuzzing.MaxBlockNumberDelay
+	maxBlockTimestampDelay := g.worker.fuzzer.config.Fuzzing.MaxBlockTimestampDelay
+	if g.worker.fuzzer.config.Fuzzing.BlockDelayMode == "correlated" {
+		return correlatedBlockDelays(g.worker.randomProvider, maxBlockNumberDelay, maxBlockTimestampDelay)
+	}
+
+	// Otherwise, we use the legacy behavior, generating both delays independently.
+	blockNumberDelay := uint64(0)
+	blockTimestampDelay := uint64(0)
+	if maxBlockNumberDelay > 0 {
+		blockNumberDelay = g.config.ValueGenerator.GenerateInteger(false, 64).Uint64() % (maxBlockNumberDelay + 1)
+	}
+	if maxBlockTimestampDelay > 0 {
+		blockTimestampDelay = g.config.ValueGenerator.GenerateInteger(false, 64).Uint64() % (maxBlockTimestampDelay + 1)
+	}
+
+	// For each block we jump, we need a unique time stamp for chain semantics, so if our block number jump is too small,
+	// while our timestamp jump is larger, we cap it.
+	if blockNumberDelay > blockTimestampDelay {
+		if blockTimestampDelay == 0 {
+			blockNumberDelay = 0
+		} else {
+			blockNumberDelay %= blockTimestampDelay
+		}
+	}
+	return blockNumberDelay, blockTimestampDelay
+}
+
+// correlatedBlockDelays generates a block number and timestamp delay jointly, such that the timestamp advances by at
+// least one second per block, as chain semantics require. The block number delay is drawn uniformly from
+// [0, min(maxBlockNumberDelay, maxBlockTimestampDelay)]. If it is zero, the timestamp delay is drawn uniformly from
+// [0, maxBlockTimestampDelay]. Otherwise, the timestamp delay is the block number delay plus some additional time,
+// drawn uniformly from [0, blockNumberDelay] half of the time, so many blocks passing quickly is as likely as not,
+// and from [0, maxBlockTimestampDelay - blockNumberDelay] otherwise. Both ranges are capped so the timestamp delay
+// never exceeds maxBlockTimestampDelay.
+// Returns the block number delay and block timestamp delay.
+func correlatedBlockDelays(randomProvider *rand.Rand, maxBlockNumberDelay uint64, maxBlockTimestampDelay uint64) (uint64, uint64) {
+	blockNumberDelay := randomProvider.Uint64() % (min(maxBlockNumberDelay, maxBlockTimestampDelay) + 1)
+	if blockNumberDelay == 0 {
+		return 0, randomProvider.Uint64() % (maxBlockTimestampDelay + 1)
+	}
+
+	// Determine how much additional time may pass beyond one second per block.
+	maxAdditionalTime := maxBlockTimestampDelay - blockNumberDelay
+	if randomProvider.Intn(2) == 0 {
+		maxAdditionalTime = min(maxAdditionalTime, blockNumberDelay)
+	}
+	return blockNumberDelay, blockNumberDelay + randomProvider.Uint64()%(maxAdditionalTime+1)
+}
+
 // generateNewElement generates a new call sequence element which targets a method in a contract
 // deployed to the CallSequenceGenerator's parent FuzzerWorker chain, with fuzzed call data.
 // Returns the call sequence element, or an error if one was encountered.
@@ -295,15 +560,13 @@ func (g *CallSequenceGenerator) generateNewElement() (*calls.CallSequenceElement
 		selectedMethod = &g.worker.stateChangingMethods[g.worker.randomProvider.Intn(len(g.worker.stateChangingMethods))]
 	}
 
-	// Select a random sender
-	selectedSender := g.worker.fuzzer.senders[g.worker.randomProvider.Intn(len(g.worker.fuzzer.senders))]
+	// Select a random sender, preferring those which are not rejected by the method's access control
+	selectedSender := g.worker.selectSender(selectedMethod)
 
 	// Generate fuzzed parameters for the function call
-	args := make([]any, len(selectedMethod.Method.Inputs))
-	for i := 0; i < len(args); i++ {
-		// Create our fuzzed parameters.
-		input := selectedMethod.Method.Inputs[i]
-		args[i] = valuegeneration.GenerateAbiValue(g.config.ValueGenerator, &input.Type)
+	args, err := g.generateMethodArguments(&selectedMethod.Method)
+	if err != nil {
+		return nil, err
 	}
 
 	// If this is a payable function, generate value to send
@@ -325,28 +588,197 @@ func (g *CallSequenceGenerator) generateNewElement() (*calls.CallSequenceElement
 		msg.SkipAccountChecks = true
 	}
 
+	// Constrain the value to what the sender can afford, so the call is not rejected before reaching the contract.
+	// Occasionally, we intentionally send more than the sender's balance to exercise that path instead.
+	if value.Sign() > 0 {
+		msg.FillFromTestChainProperties(g.worker.chain)
+		if g.worker.randomProvider.Float32() < overBalanceValueProbability {
+			msg.Value = new(big.Int).Add(msg.SpendableBalance(g.worker.chain), value)
+		} else {
+			msg.ClampValue(g.worker.chain, g.maxTransactionValue())
+		}
+	}
+
 	// Determine our delay values for this element
-	blockNumberDelay := uint64(0)
-	blockTimestampDelay := uint64(0)
-	if g.worker.fuzzer.config.Fuzzing.MaxBlockNumberDelay > 0 {
-		blockNumberDelay = g.config.ValueGenerator.GenerateInteger(false, 64).Uint64() % (g.worker.fuzzer.config.Fuzzing.MaxBlockNumberDelay + 1)
+	blockNumberDelay, blockTimestampDelay := g.generateBlockDelays()
+
+	// Create our call sequence element, occasionally malforming its call data to probe how it is decoded,
+	// occasionally mining empty blocks before it, occasionally binding an argument to the output of the prior call, and
+	// generating the prevrandao value of its block if configured. Calls to methods marked as setup or frozen through
+	// their NatSpec directives are marked so shrinking retains them.
+	element := calls.NewCallSequenceElement(selectedMethod.Contract, msg, blockNumberDelay, blockTimestampDelay)
+	if selectedMethod.Contract != nil {
+		element.Frozen = selectedMethod.Contract.HasMethodDirective(selectedMethod.Method, contracts.MethodDirectiveFrozen)
+		element.Setup = element.Frozen || selectedMethod.Contract.HasMethodDirective(selectedMethod.Method, contracts.MethodDirectiveSetup)
+	}
+	if g.worker.fuzzer.config.Fuzzing.MaxEmptyBlocks > 0 && g.worker.randomProvider.Float32() < g.worker.fuzzer.config.Fuzzing.EmptyBlockProbability {
+		element.EmptyBlocks = 1 + g.worker.randomProvider.Uint64()%g.worker.fuzzer.config.Fuzzing.MaxEmptyBlocks
+	}
+	if g.worker.fuzzer.config.Fuzzing.GeneratePrevrandao {
+		var prevrandao common.Hash
+		g.worker.randomProvider.Read(prevrandao[:])
+		element.Prevrandao = &prevrandao
 	}
-	if g.worker.fuzzer.config.Fuzzing.MaxBlockTimestampDelay > 0 {
-		blockTimestampDelay = g.config.ValueGenerator.GenerateInteger(false, 64).Uint64() % (g.worker.fuzzer.config.Fuzzing.MaxBlockTimestampDelay + 1)
+	if outputBindingProbability := g.worker.fuzzer.config.Fuzzing.OutputBindingProbability; outputBindingProbability > 0 && g.worker.randomProvider.Float32() < outputBindingProbability {
+		element.OutputBinding = g.generateOutputBinding(&selectedMethod.Method)
 	}
+	if calldataProbeProbability := g.worker.fuzzer.config.Fuzzing.CalldataProbeProbability; calldataProbeProbability > 0 && g.worker.randomProvider.Float32() < calldataProbeProbability {
+		element.CalldataProbe = newCalldataProbe(g.worker.randomProvider, &selectedMethod.Method, msg.Data)
+		msg.Data = element.CalldataProbe.Apply(msg.Data)
+	}
+	return element, nil
+}
 
-	// For each block we jump, we need a unique time stamp for chain semantics, so if our block number jump is too small,
-	// while our timestamp jump is larger, we cap it.
-	if blockNumberDelay > blockTimestampDelay {
-		if blockTimestampDelay == 0 {
-			blockNumberDelay = 0
-		} else {
-			blockNumberDelay %= blockTimestampDelay
+// generateOutputBinding binds a random argument of a call to the provided method to a return value of the call
+// fetched prior to it in the current sequence, such that both share the same type. The binding is resolved when the
+// call is executed.
+// Returns the generated binding, or nil if there is no prior call, or none of its return values can be bound.
+func (g *CallSequenceGenerator) generateOutputBinding(method *abi.Method) *calls.CallOutputBinding {
+	// Obtain the method of the prior call, if there is one.
+	if g.fetchIndex == 0 || g.baseSequence[g.fetchIndex-1] == nil {
+		return nil
+	}
+	priorMethod, err := g.baseSequence[g.fetchIndex-1].Method()
+	if err != nil || priorMethod == nil {
+		return nil
+	}
+
+	// Collect every pair of argument and prior return value which share a type, and select one.
+	bindings := make([]calls.CallOutputBinding, 0)
+	for argumentIndex, input := range method.Inputs {
+		for outputIndex, output := range priorMethod.Outputs {
+			if input.Type.String() == output.Type.String() {
+				bindings = append(bindings, calls.CallOutputBinding{ArgumentIndex: argumentIndex, OutputIndex: outputIndex})
+			}
+		}
+	}
+	if len(bindings) == 0 {
+		return nil
+	}
+	return &bindings[g.worker.randomProvider.Intn(len(bindings))]
+}
+
+// maxTransactionValue returns the configured maximum value which generated calls may send, or nil if there is none.
+func (g *CallSequenceGenerator) maxTransactionValue() *big.Int {
+	if g.worker.fuzzer.config.Fuzzing.MaxTransactionValue == nil {
+		return nil
+	}
+	return &g.worker.fuzzer.config.Fuzzing.MaxTransactionValue.Int
+}
+
+// generateMethodArguments generates fuzzed arguments for a call to the provided method. Arguments are generated
+// independently, except for an address parameter immediately followed by a bytes parameter, which is treated as a call
+// target and the call data to send to it (e.g. in executors or multicalls). The address of such a pair is steered
+// toward deployed contracts, and the bytes are sometimes generated as ABI-encoded call data for a method of the
+// contract at that address, so the inner call reaches contract code rather than an externally owned account.
+// Returns the generated arguments, or an error if one occurs.
+func (g *CallSequenceGenerator) generateMethodArguments(method *abi.Method) ([]any, error) {
+	// Generate each argument independently first. If enabled, parameter names are used as hints to bias the
+	// generated values.
+	args := make([]any, len(method.Inputs))
+	hintContext := g.parameterHintContext()
+	for i := 0; i < len(args); i++ {
+		if hintContext != nil {
+			if value, hinted := g.worker.fuzzer.parameterHints.generate(hintContext, &method.Inputs[i]); hinted {
+				args[i] = value
+				continue
+			}
+		}
+		args[i] = valuegeneration.GenerateAbiValue(g.config.ValueGenerator, &method.Inputs[i].Type)
+	}
+
+	// Correlate the values of any call target and call data parameter pairs.
+	var callTargets []common.Address
+	for i := 0; i+1 < len(args); i++ {
+		if method.Inputs[i].Type.T != abi.AddressTy || method.Inputs[i+1].Type.T != abi.BytesTy {
+			continue
+		}
+
+		// Steer the call target toward a deployed contract.
+		if callTargets == nil {
+			callTargets = g.callTargetAddresses()
+		}
+		if len(callTargets) > 0 && g.worker.randomProvider.Float32() < callTargetSteeringProbability {
+			args[i] = callTargets[g.worker.randomProvider.Intn(len(callTargets))]
+		}
+
+		// If the call target is a known contract, generate call data for one of its methods.
+		contract, isContract := g.worker.deployedContracts[args[i].(common.Address)]
+		if !isContract || g.worker.randomProvider.Float32() >= callTargetCallDataProbability {
+			continue
+		}
+		callData, err := g.generateCallData(contract)
+		if err != nil {
+			return nil, err
+		}
+		if callData != nil {
+			args[i+1] = callData
+		}
+	}
+	return args, nil
+}
+
+// parameterHintContext creates the context used to generate method arguments from parameter name hints, relative to
+// the head of the CallSequenceGenerator's parent FuzzerWorker chain.
+// Returns the context, or nil if parameter name hints are disabled.
+func (g *CallSequenceGenerator) parameterHintContext() *parameterHintContext {
+	if g.worker.fuzzer.parameterHints == nil {
+		return nil
+	}
+	var contextTimestamps []*big.Int
+	if g.worker.fuzzer.config.Fuzzing.ChainContextValues {
+		contextTimestamps = chainContextTimestamps(g.worker.chain.Head().Header.Time)
+	}
+	return &parameterHintContext{
+		randomProvider:    g.worker.randomProvider,
+		timestamp:         g.worker.chain.Head().Header.Time,
+		contextTimestamps: contextTimestamps,
+		maxTimestampDelay: g.worker.fuzzer.config.Fuzzing.MaxBlockTimestampDelay,
+		addresses: func() []common.Address {
+			return append(slices.Clone(g.worker.fuzzer.senders), g.callTargetAddresses()...)
+		},
+	}
+}
+
+// callTargetAddresses obtains the addresses of contracts deployed to the CallSequenceGenerator's parent FuzzerWorker
+// chain which call target parameters should be steered toward. Spec contracts are excluded, as they only exist to
+// define tests over other contracts. The addresses are sorted so generation is deterministic for a given random seed.
+func (g *CallSequenceGenerator) callTargetAddresses() []common.Address {
+	addresses := make([]common.Address, 0, len(g.worker.deployedContracts))
+	for address, contract := range g.worker.deployedContracts {
+		if !g.worker.fuzzer.isSpecContract(contract.Name()) {
+			addresses = append(addresses, address)
 		}
 	}
+	slices.SortFunc(addresses, func(a, b common.Address) int {
+		return a.Cmp(b)
+	})
+	return addresses
+}
+
+// generateCallData generates ABI-encoded call data for a random method of the provided contract, with fuzzed
+// arguments.
+// Returns the call data, nil if the contract has no methods, or an error if one occurs.
+func (g *CallSequenceGenerator) generateCallData(contract *contracts.Contract) ([]byte, error) {
+	// Select a random method, sorting them so generation is deterministic for a given random seed.
+	contractAbi := contract.CompiledContract().Abi
+	if len(contractAbi.Methods) == 0 {
+		return nil, nil
+	}
+	methodNames := maps.Keys(contractAbi.Methods)
+	slices.Sort(methodNames)
+	method := contractAbi.Methods[methodNames[g.worker.randomProvider.Intn(len(methodNames))]]
 
-	// Return our call sequence element.
-	return calls.NewCallSequenceElement(selectedMethod.Contract, msg, blockNumberDelay, blockTimestampDelay), nil
+	// Generate its arguments and encode them with its selector.
+	args, err := g.generateMethodArguments(&method)
+	if err != nil {
+		return nil, err
+	}
+	encodedArgs, err := method.Inputs.Pack(args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate call data for method '%s' of contract '%s': %v", method.Sig, contract.Name(), err)
+	}
+	return append(slices.Clone(method.ID), encodedArgs...), nil
 }
 
 // callSeqGenFuncCorpusHead is a CallSequenceGeneratorFunc which prepares a CallSequenceGenerator to generate a sequence
@@ -354,7 +786,7 @@ func (g *CallSequenceGenerator) generateNewElement() (*calls.CallSequenceElement
 // Returns an error if one occurs.
 func callSeqGenFuncCorpusHead(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence) error {
 	// Obtain a call sequence from the corpus
-	corpusSequence, err := sequenceGenerator.worker.fuzzer.corpus.RandomMutationTargetSequence()
+	corpusSequence, err := sequenceGenerator.worker.corpusMutationTargetSequence()
 	if err != nil {
 		return fmt.Errorf("could not obtain corpus call sequence for head mutation: %v", err)
 	}
@@ -371,7 +803,7 @@ func callSeqGenFuncCorpusHead(sequenceGenerator *CallSequenceGenerator, sequence
 // Returns an error if one occurs.
 func callSeqGenFuncCorpusTail(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence) error {
 	// Obtain a call sequence from the corpus
-	corpusSequence, err := sequenceGenerator.worker.fuzzer.corpus.RandomMutationTargetSequence()
+	corpusSequence, err := sequenceGenerator.worker.corpusMutationTargetSequence()
 	if err != nil {
 		return fmt.Errorf("could not obtain corpus call sequence for tail mutation: %v", err)
 	}
@@ -380,6 +812,7 @@ func callSeqGenFuncCorpusTail(sequenceGenerator *CallSequenceGenerator, sequence
 	maxLength := utils.Min(len(sequence), len(corpusSequence))
 	targetLength := sequenceGenerator.worker.randomProvider.Intn(maxLength) + 1
 	copy(sequence[len(sequence)-targetLength:], corpusSequence[len(corpusSequence)-targetLength:])
+	sequenceGenerator.preserveFragmentDelays(corpusSequence[:len(corpusSequence)-targetLength], sequence[len(sequence)-targetLength])
 
 	return nil
 }
@@ -390,11 +823,11 @@ func callSeqGenFuncCorpusTail(sequenceGenerator *CallSequenceGenerator, sequence
 // Returns an error if one occurs.
 func callSeqGenFuncSpliceAtRandom(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence) error {
 	// Obtain two corpus call sequence entries
-	headSequence, err := sequenceGenerator.worker.fuzzer.corpus.RandomMutationTargetSequence()
+	headSequence, err := sequenceGenerator.worker.corpusMutationTargetSequence()
 	if err != nil {
 		return fmt.Errorf("could not obtain head corpus call sequence for splice-at-random corpus mutation: %v", err)
 	}
-	tailSequence, err := sequenceGenerator.worker.fuzzer.corpus.RandomMutationTargetSequence()
+	tailSequence, err := sequenceGenerator.worker.corpusMutationTargetSequence()
 	if err != nil {
 		return fmt.Errorf("could not obtain tail corpus call sequence for splice-at-random corpus mutation: %v", err)
 	}
@@ -412,6 +845,9 @@ func callSeqGenFuncSpliceAtRandom(sequenceGenerator *CallSequenceGenerator, sequ
 
 	// Copy the tail of the second corpus sequence to our destination sequence (after the head sequence portion).
 	copy(sequence[headSequenceLength:], tailSequence[len(tailSequence)-tailSequenceLength:])
+	if tailSequenceLength > 0 {
+		sequenceGenerator.preserveFragmentDelays(tailSequence[:len(tailSequence)-tailSequenceLength], sequence[headSequenceLength])
+	}
 
 	return nil
 }
@@ -422,11 +858,11 @@ func callSeqGenFuncSpliceAtRandom(sequenceGenerator *CallSequenceGenerator, sequ
 // Returns an error if one occurs.
 func callSeqGenFuncInterleaveAtRandom(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence) error {
 	// Obtain two corpus call sequence entries
-	firstSequence, err := sequenceGenerator.worker.fuzzer.corpus.RandomMutationTargetSequence()
+	firstSequence, err := sequenceGenerator.worker.corpusMutationTargetSequence()
 	if err != nil {
 		return fmt.Errorf("could not obtain first corpus call sequence for interleave-at-random corpus mutation: %v", err)
 	}
-	secondSequence, err := sequenceGenerator.worker.fuzzer.corpus.RandomMutationTargetSequence()
+	secondSequence, err := sequenceGenerator.worker.corpusMutationTargetSequence()
 	if err != nil {
 		return fmt.Errorf("could not obtain second corpus call sequence for interleave-at-random corpus mutation: %v", err)
 	}
@@ -458,6 +894,104 @@ func callSeqGenFuncInterleaveAtRandom(sequenceGenerator *CallSequenceGenerator,
 	return nil
 }
 
+// preserveFragmentDelays adjusts the provided element, the first of a fragment sliced from a corpus call sequence, to
+// carry the block delays of the calls dropped before it in that sequence, if the fuzzer is configured to preserve
+// fragment delays.
+func (g *CallSequenceGenerator) preserveFragmentDelays(droppedPrefix calls.CallSequence, element *calls.CallSequenceElement) {
+	if !g.worker.fuzzer.config.Fuzzing.PreserveFragmentDelays {
+		return
+	}
+	carryDroppedPrefixDelays(droppedPrefix, element, g.worker.fuzzer.config.Fuzzing.MaxBlockNumberDelay, g.worker.fuzzer.config.Fuzzing.MaxBlockTimestampDelay)
+}
+
+// carryDroppedPrefixDelays adjusts the block delays of the provided element so it advances the block number and
+// timestamp as far as the provided dropped prefix did, followed by its own delays, approximating the schedule it
+// executed with before the prefix was dropped. The resulting delays are capped by the provided maxima. Elements are
+// expected to be clones, as they are modified in place.
+func carryDroppedPrefixDelays(droppedPrefix calls.CallSequence, element *calls.CallSequenceElement, maxBlockNumberDelay uint64, maxBlockTimestampDelay uint64) {
+	if len(droppedPrefix) == 0 || element == nil {
+		return
+	}
+
+	// Sum how far the dropped prefix advanced the block number and timestamp. This mirrors call sequence execution:
+	// the first call always creates a block, as do calls with a block number delay, each advancing the block number
+	// and timestamp by at least one.
+	var numberDelay, timestampDelay uint64
+	for i, prefixElement := range droppedPrefix {
+		if prefixElement == nil {
+			continue
+		}
+		numberDelay += prefixElement.EmptyBlocks
+		timestampDelay += prefixElement.EmptyBlocks * calls.EmptyBlockTimestampDelay
+		if i == 0 || prefixElement.BlockNumberDelay > 0 {
+			elementNumberDelay, elementTimestampDelay := utils.Max(prefixElement.BlockNumberDelay, 1), utils.Max(prefixElement.BlockTimestampDelay, 1)
+			numberDelay += utils.Min(elementNumberDelay, elementTimestampDelay)
+			timestampDelay += elementTimestampDelay
+		}
+	}
+
+	// The element creates a new block carrying the prefix's delays, followed by its own if it created one itself.
+	if element.BlockNumberDelay > 0 {
+		numberDelay += element.BlockNumberDelay
+		timestampDelay += element.BlockTimestampDelay
+	}
+	if maxBlockNumberDelay > 0 {
+		numberDelay = utils.Min(numberDelay, maxBlockNumberDelay)
+	}
+	if maxBlockTimestampDelay > 0 {
+		timestampDelay = utils.Min(timestampDelay, maxBlockTimestampDelay)
+	}
+	element.BlockNumberDelay = utils.Max(numberDelay, 1)
+	element.BlockTimestampDelay = utils.Max(timestampDelay, 1)
+}
+
+// callSeqGenFuncPermuteSenders is a CallSequenceGeneratorFunc which prepares a CallSequenceGenerator to generate a
+// sequence whose head is based off of an existing corpus call sequence, with its senders consistently replaced by a
+// random permutation of the configured senders.
+// Returns an error if one occurs.
+func callSeqGenFuncPermuteSenders(sequenceGenerator *CallSequenceGenerator, sequence calls.CallSequence) error {
+	// Obtain a call sequence from the corpus
+	corpusSequence, err := sequenceGenerator.worker.corpusMutationTargetSequence()
+	if err != nil {
+		return fmt.Errorf("could not obtain corpus call sequence for sender permutation: %v", err)
+	}
+
+	// Determine the length of the slice to be copied in the head, then permute its senders.
+	maxLength := utils.Min(len(sequence), len(corpusSequence))
+	copy(sequence, corpusSequence[:maxLength])
+	permuteCallSequenceSenders(sequence[:maxLength], sequenceGenerator.worker.fuzzer.senders, sequenceGenerator.worker.randomProvider)
+
+	return nil
+}
+
+// permuteCallSequenceSenders chooses a random permutation of the provided senders and applies it to the provided call
+// sequence, so every call sent by a given sender is sent by the same replacement sender. If there is more than one
+// sender, the permutation never leaves every sender in place. Calls whose sender is not one of the provided senders
+// are left untouched.
+// Returns the permutation applied, mapping each sender to its replacement.
+func permuteCallSequenceSenders(sequence calls.CallSequence, senders []common.Address, randomProvider *rand.Rand) map[common.Address]common.Address {
+	// Choose a permutation of our senders, ensuring it changes at least one sender if it can.
+	permutation := randomProvider.Perm(len(senders))
+	if len(senders) > 1 && slices.IsSorted(permutation) {
+		permutation[0], permutation[1] = permutation[1], permutation[0]
+	}
+	replacements := make(map[common.Address]common.Address, len(senders))
+	for i, sender := range senders {
+		replacements[sender] = senders[permutation[i]]
+	}
+
+	// Replace the sender of each call.
+	for _, element := range sequence {
+		if element == nil || element.Call == nil {
+			continue
+		}
+		if replacement, ok := replacements[element.Call.From]; ok {
+			element.Call.From = replacement
+		}
+	}
+	return replacements
+}
+
 // prefetchModifyCallFuncMutate is a PrefetchModifyCallFunc, called by a CallSequenceGenerator to apply mutations
 // to a call sequence element, prior to it being fetched.
 // Returns an error if one occurs.
@@ -467,17 +1001,15 @@ func prefetchModifyCallFuncMutate(sequenceGenerator *CallSequenceGenerator, elem
 		return nil
 	}
 
-	// Loop for each input value and mutate it
+	// Mutate the input values, with the amount of leaf values mutated across them selected by the value mutator.
 	abiValuesMsgData := element.Call.DataAbiValues
-	for i := 0; i < len(abiValuesMsgData.InputValues); i++ {
-		mutatedInput, err := valuegeneration.MutateAbiValue(sequenceGenerator.config.ValueGenerator, sequenceGenerator.config.ValueMutator, &abiValuesMsgData.Method.Inputs[i].Type, abiValuesMsgData.InputValues[i])
-		if err != nil {
-			return fmt.Errorf("error when mutating call sequence input argument: %v", err)
-		}
-		abiValuesMsgData.InputValues[i] = mutatedInput
+	mutatedInputs, err := valuegeneration.MutateAbiValues(sequenceGenerator.config.ValueGenerator, sequenceGenerator.config.ValueMutator, abiValuesMsgData.Method.Inputs, abiValuesMsgData.InputValues)
+	if err != nil {
+		return fmt.Errorf("error when mutating call sequence input argument: %v", err)
 	}
+	abiValuesMsgData.InputValues = mutatedInputs
 	// Re-encode the message's calldata
-	element.Call.WithDataAbiValues(abiValuesMsgData)
+	element.WithDataAbiValues(abiValuesMsgData)
 
 	return nil
 }
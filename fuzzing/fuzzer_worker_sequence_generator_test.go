@@ -0,0 +1,263 @@
+package fuzzing
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/crytic/medusa/chain/testchainutils"
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/config"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/valuegeneration"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseReplayID tests that replay IDs can be parsed from their string representation, and that invalid replay IDs
+// are rejected.
+func TestParseReplayID(t *testing.T) {
+	// Round trip a replay ID with a negative seed
+	replayID := ReplayID{Seed: -1234, WorkerIndex: 3, WorkerGeneration: 7, SequenceIndex: 42}
+	assert.EqualValues(t, "-1234:3:7:42", replayID.String())
+	parsedReplayID, err := ParseReplayID(replayID.String())
+	assert.NoError(t, err)
+	assert.EqualValues(t, replayID, parsedReplayID)
+
+	// Verify invalid replay IDs are rejected
+	for _, invalidReplayID := range []string{"", "1:2:3", "1:2:3:4:5", "a:2:3:4", "1:-2:3:4", "1:2:-3:4", "1:2:3:x"} {
+		_, err = ParseReplayID(invalidReplayID)
+		assert.Error(t, err, invalidReplayID)
+	}
+}
+
+// TestReplayIDRandomSeed tests that the random seed derived from a replay ID is stable, and differs when any of its
+// components differ.
+func TestReplayIDRandomSeed(t *testing.T) {
+	replayID := ReplayID{Seed: 1234, WorkerIndex: 1, WorkerGeneration: 2, SequenceIndex: 3}
+	assert.EqualValues(t, replayID.randomSeed(), replayID.randomSeed())
+
+	variants := []ReplayID{
+		{Seed: 1235, WorkerIndex: 1, WorkerGeneration: 2, SequenceIndex: 3},
+		{Seed: 1234, WorkerIndex: 2, WorkerGeneration: 2, SequenceIndex: 3},
+		{Seed: 1234, WorkerIndex: 1, WorkerGeneration: 3, SequenceIndex: 3},
+		{Seed: 1234, WorkerIndex: 1, WorkerGeneration: 2, SequenceIndex: 4},
+	}
+	for _, variant := range variants {
+		assert.NotEqualValues(t, replayID.randomSeed(), variant.randomSeed(), variant.String())
+	}
+}
+
+// TestGeneratedValuesAffordable tests that calls generated for payable methods rarely send more value than their sender
+// can afford, even if the sender's balance is far below the range of generated values, and that the configured
+// maximum transaction value is respected.
+func TestGeneratedValuesAffordable(t *testing.T) {
+	// Create a test chain with a sender which can only afford a small fraction of generated values, and a contract with
+	// a payable method which accepts any value.
+	target := common.HexToAddress("0x20000")
+	genesisAlloc := types.GenesisAlloc{
+		testchainutils.FundedSender: types.Account{Balance: big.NewInt(1_000_000_000_000_000_000)},
+		target:                      types.Account{Code: []byte{0x00}},
+	}
+	testChain, sender := testchainutils.NewFundedTestChainWithAlloc(t, genesisAlloc, nil)
+
+	// Create a worker which can generate calls to the payable method.
+	projectConfig, err := config.GetDefaultProjectConfig("")
+	assert.NoError(t, err)
+	testChain.BlockGasLimit = projectConfig.Fuzzing.BlockGasLimit
+	contract := fuzzerTypes.NewContract("PayableContract", "", nil, nil)
+	method := abi.NewMethod("deposit", "deposit", abi.Function, "payable", false, true, nil, nil)
+	randomProvider := rand.New(rand.NewSource(1))
+	worker := &FuzzerWorker{
+		fuzzer:               &Fuzzer{config: *projectConfig, senders: []common.Address{sender}},
+		chain:                testChain,
+		randomProvider:       randomProvider,
+		stateChangingMethods: []fuzzerTypes.DeployedContractMethod{{Address: target, Contract: contract, Method: method}},
+	}
+	generator := NewCallSequenceGenerator(worker, &CallSequenceGeneratorConfig{
+		ValueGenerator: valuegeneration.NewRandomValueGenerator(&valuegeneration.RandomValueGeneratorConfig{}, randomProvider),
+	})
+
+	// Generate and execute calls, counting those rejected for insufficient funds. Each call is executed from the same
+	// state, so the sender's balance is not drained.
+	countInsufficientFunds := func(maxValue *big.Int) int {
+		count := 0
+		for i := 0; i < 1_000; i++ {
+			element, err := generator.generateNewElement()
+			assert.NoError(t, err)
+			element.Call.FillFromTestChainProperties(testChain)
+			affordable := element.Call.Value.Cmp(element.Call.SpendableBalance(testChain)) <= 0
+			if maxValue != nil && affordable {
+				assert.LessOrEqual(t, element.Call.Value.Cmp(maxValue), 0)
+			}
+
+			_, err = testChain.PendingBlockCreate()
+			assert.NoError(t, err)
+			err = testChain.PendingBlockAddTx(element.Call.ToCoreMessage())
+			if errors.Is(err, core.ErrInsufficientFunds) {
+				count++
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, testChain.PendingBlockDiscard())
+		}
+		return count
+	}
+
+	// Only calls which intentionally exceed the sender's balance should be rejected.
+	assert.Less(t, countInsufficientFunds(nil), 30)
+	maxValue := big.NewInt(1_000)
+	worker.fuzzer.config.Fuzzing.MaxTransactionValue = &config.ContractBalance{Int: *maxValue}
+	assert.Less(t, countInsufficientFunds(maxValue), 30)
+}
+
+// TestPermuteCallSequenceSenders tests that the sender permutation applied to a call sequence is consistent across
+// the whole sequence, is a permutation of the configured senders which changes at least one of them, and leaves calls
+// from other senders untouched.
+func TestPermuteCallSequenceSenders(t *testing.T) {
+	senders := []common.Address{common.HexToAddress("0x10000"), common.HexToAddress("0x20000"), common.HexToAddress("0x30000")}
+	otherSender := common.HexToAddress("0x40000")
+	recipient := common.HexToAddress("0x50000")
+	originalSenders := []common.Address{senders[0], senders[1], otherSender, senders[0], senders[2], senders[1], senders[0]}
+
+	randomProvider := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		// Create a call sequence with repeated senders, including one which is not configured.
+		sequence := make(calls.CallSequence, 0, len(originalSenders))
+		for _, sender := range originalSenders {
+			msg := calls.NewCallMessage(sender, &recipient, 0, big.NewInt(0), 100_000, nil, nil, nil, nil)
+			sequence = append(sequence, calls.NewCallSequenceElement(nil, msg, 0, 0))
+		}
+		replacements := permuteCallSequenceSenders(sequence, senders, randomProvider)
+
+		// Every configured sender must be replaced by a distinct configured sender, and at least one must change.
+		assert.Len(t, replacements, len(senders))
+		replaced := make(map[common.Address]bool)
+		changed := false
+		for _, sender := range senders {
+			replacement, ok := replacements[sender]
+			assert.True(t, ok)
+			assert.Contains(t, senders, replacement)
+			replaced[replacement] = true
+			changed = changed || replacement != sender
+		}
+		assert.Len(t, replaced, len(senders))
+		assert.True(t, changed)
+
+		// Every call must be sent by the replacement of its original sender, if it had one.
+		for j, element := range sequence {
+			if originalSenders[j] == otherSender {
+				assert.EqualValues(t, otherSender, element.Call.From)
+			} else {
+				assert.EqualValues(t, replacements[originalSenders[j]], element.Call.From)
+			}
+		}
+	}
+
+	// A single sender has no other sender to be replaced with.
+	msg := calls.NewCallMessage(senders[0], &recipient, 0, big.NewInt(0), 100_000, nil, nil, nil, nil)
+	sequence := calls.CallSequence{calls.NewCallSequenceElement(nil, msg, 0, 0)}
+	permuteCallSequenceSenders(sequence, senders[:1], randomProvider)
+	assert.EqualValues(t, senders[0], sequence[0].Call.From)
+}
+
+// TestCarryDroppedPrefixDelays tests that the first call of a fragment sliced from a call sequence executes at the
+// same block number and timestamp relative to the start of the sequence as it originally did when its dropped
+// prefix's delays are carried, while it does not when executed verbatim.
+func TestCarryDroppedPrefixDelays(t *testing.T) {
+	sender := testchainutils.FundedSender
+	recipient := common.HexToAddress("0x20000")
+
+	// Describe a call sequence whose tail depends on time having passed during its head, including calls which share
+	// a block and mine empty blocks, as the block number delay, block timestamp delay, and empty blocks of each call.
+	sequenceDelays := [][3]uint64{
+		{0, 100, 0},
+		{0, 500, 0},
+		{3, 1_000, 2},
+		{0, 0, 0},
+		{2, 50, 0},
+	}
+	newSequence := func(start int) calls.CallSequence {
+		sequence := make(calls.CallSequence, 0, len(sequenceDelays)-start)
+		for _, delays := range sequenceDelays[start:] {
+			msg := calls.NewCallMessage(sender, &recipient, 0, big.NewInt(0), 100_000, nil, nil, nil, nil)
+			element := calls.NewCallSequenceElement(nil, msg, delays[0], delays[1])
+			element.EmptyBlocks = delays[2]
+			sequence = append(sequence, element)
+		}
+		return sequence
+	}
+
+	// executeSequence executes the provided call sequence on a new test chain, returning the block number and
+	// timestamp each of its calls executed at, relative to the genesis block.
+	executeSequence := func(sequence calls.CallSequence) [][2]uint64 {
+		testChain, _ := testchainutils.NewFundedTestChain(t)
+		fetchElementFunc := func(currentIndex int) (*calls.CallSequenceElement, error) {
+			if currentIndex >= len(sequence) {
+				return nil, nil
+			}
+			sequence[currentIndex].Call.FillFromTestChainProperties(testChain)
+			return sequence[currentIndex], nil
+		}
+		executedSequence, err := calls.ExecuteCallSequenceIteratively(testChain, fetchElementFunc, nil)
+		assert.NoError(t, err)
+		assert.Len(t, executedSequence, len(sequence))
+		genesis := testChain.GenesisDefinition()
+		blocks := make([][2]uint64, 0, len(executedSequence))
+		for _, element := range executedSequence {
+			blocks = append(blocks, [2]uint64{element.ExecutedBlock.BlockNumber, element.ExecutedBlock.BlockTimestamp - genesis.Timestamp})
+		}
+		return blocks
+	}
+	sequence := newSequence(0)
+	originalBlocks := executeSequence(sequence)
+
+	for tailStart := 1; tailStart < len(sequence); tailStart++ {
+		// Executed verbatim, the tail does not execute at the block it originally did.
+		verbatimBlocks := executeSequence(newSequence(tailStart))
+		assert.NotEqualValues(t, originalBlocks[tailStart], verbatimBlocks[0], "tail starting at %d", tailStart)
+
+		// Carrying the delays of the dropped prefix, the tail executes at the original block number and timestamp.
+		tail := newSequence(tailStart)
+		carryDroppedPrefixDelays(sequence[:tailStart], tail[0], 0, 0)
+		adjustedBlocks := executeSequence(tail)
+		assert.EqualValues(t, originalBlocks[tailStart:], adjustedBlocks, "tail starting at %d", tailStart)
+
+		// The carried delays should be capped by the provided maxima.
+		tail = newSequence(tailStart)
+		carryDroppedPrefixDelays(sequence[:tailStart], tail[0], 1, 10)
+		assert.EqualValues(t, 1, tail[0].BlockNumberDelay)
+		assert.EqualValues(t, 10, tail[0].BlockTimestampDelay)
+	}
+}
+
+// TestCorrelatedBlockDelays tests that block number and timestamp delays generated jointly always advance the
+// timestamp by at least one second per block, remain within their maximums, and frequently describe many blocks
+// passing quickly, which the legacy capping of block number delays rarely generated.
+func TestCorrelatedBlockDelays(t *testing.T) {
+	randomProvider := rand.New(rand.NewSource(1))
+	maxDelays := [][2]uint64{{60480, 604800}, {100, 10}, {10, 100}, {0, 5}, {5, 0}, {1, 1}}
+	for _, maxDelay := range maxDelays {
+		maxBlockNumberDelay, maxBlockTimestampDelay := maxDelay[0], maxDelay[1]
+		for i := 0; i < 10_000; i++ {
+			blockNumberDelay, blockTimestampDelay := correlatedBlockDelays(randomProvider, maxBlockNumberDelay, maxBlockTimestampDelay)
+			assert.LessOrEqual(t, blockNumberDelay, maxBlockNumberDelay)
+			assert.LessOrEqual(t, blockTimestampDelay, maxBlockTimestampDelay)
+			assert.LessOrEqual(t, blockNumberDelay, blockTimestampDelay)
+		}
+	}
+
+	// Using the default maximums, many blocks passing at one to two seconds per block should be common.
+	quickSchedules := 0
+	for i := 0; i < 10_000; i++ {
+		blockNumberDelay, blockTimestampDelay := correlatedBlockDelays(randomProvider, 60480, 604800)
+		if blockNumberDelay >= 10_000 && blockTimestampDelay <= 2*blockNumberDelay {
+			quickSchedules++
+		}
+	}
+	assert.Greater(t, quickSchedules, 1_000)
+}
@@ -0,0 +1,223 @@
+package fuzzing
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/crytic/medusa/compilation/types"
+	"github.com/crytic/medusa/fuzzing/config"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSyntheticContract creates a contract definition with the provided number of state changing and view methods,
+// without any underlying compilation.
+func newSyntheticContract(name string, stateChangingCount int, viewCount int) *fuzzerTypes.Contract {
+	contract := fuzzerTypes.NewContract(name, "", nil, nil)
+	for i := 0; i < stateChangingCount; i++ {
+		methodName := fmt.Sprintf("stateChanging%d", i)
+		contract.AssertionTestMethods = append(contract.AssertionTestMethods, abi.NewMethod(methodName, methodName, abi.Function, "nonpayable", false, false, nil, nil))
+	}
+	for i := 0; i < viewCount; i++ {
+		methodName := fmt.Sprintf("view%d", i)
+		contract.AssertionTestMethods = append(contract.AssertionTestMethods, abi.NewMethod(methodName, methodName, abi.Function, "view", false, false, nil, nil))
+	}
+	return contract
+}
+
+// newMethodTrackingTestWorker creates a FuzzerWorker which can only be used to track the methods of deployed
+// contracts.
+func newMethodTrackingTestWorker(t testing.TB) *FuzzerWorker {
+	projectConfig, err := config.GetDefaultProjectConfig("crytic-compile")
+	assert.NoError(t, err)
+	projectConfig.Fuzzing.Testing.TestViewMethods = true
+	return &FuzzerWorker{
+		fuzzer:                       &Fuzzer{config: *projectConfig, metrics: newFuzzerMetrics(1)},
+		deployedContracts:            make(map[common.Address]*fuzzerTypes.Contract),
+		stateChangingMethods:         make([]fuzzerTypes.DeployedContractMethod, 0),
+		pureMethods:                  make([]fuzzerTypes.DeployedContractMethod, 0),
+		stateChangingMethodPositions: make(map[common.Address][]int),
+		pureMethodPositions:          make(map[common.Address][]int),
+	}
+}
+
+// rebuildDeployedContractMethods enumerates the methods of all contracts deployed in the provided worker from
+// scratch, sorted so they can be compared with the methods tracked incrementally by the worker.
+func rebuildDeployedContractMethods(worker *FuzzerWorker) ([]string, []string) {
+	stateChangingMethods, pureMethods := make([]string, 0), make([]string, 0)
+	for contractAddress, contract := range worker.deployedContracts {
+		for _, method := range contract.AssertionTestMethods {
+			methodId := fmt.Sprintf("%s.%s", contractAddress.String(), method.Name)
+			if method.IsConstant() {
+				pureMethods = append(pureMethods, methodId)
+			} else {
+				stateChangingMethods = append(stateChangingMethods, methodId)
+			}
+		}
+	}
+	slices.Sort(stateChangingMethods)
+	slices.Sort(pureMethods)
+	return stateChangingMethods, pureMethods
+}
+
+// trackedDeployedContractMethods returns the methods tracked by the provided worker, sorted so they can be compared
+// with the methods enumerated by rebuildDeployedContractMethods. It also verifies that the recorded positions of
+// each method are consistent with the tracked method lists.
+func trackedDeployedContractMethods(t *testing.T, worker *FuzzerWorker) ([]string, []string) {
+	sortedMethodIds := func(methods []fuzzerTypes.DeployedContractMethod, positions map[common.Address][]int) []string {
+		positionCount := 0
+		for contractAddress, contractPositions := range positions {
+			for _, position := range contractPositions {
+				assert.EqualValues(t, contractAddress, methods[position].Address)
+			}
+			positionCount += len(contractPositions)
+		}
+		assert.EqualValues(t, len(methods), positionCount)
+
+		methodIds := make([]string, 0)
+		for _, method := range methods {
+			methodIds = append(methodIds, fmt.Sprintf("%s.%s", method.Address.String(), method.Method.Name))
+		}
+		slices.Sort(methodIds)
+		return methodIds
+	}
+	return sortedMethodIds(worker.stateChangingMethods, worker.stateChangingMethodPositions), sortedMethodIds(worker.pureMethods, worker.pureMethodPositions)
+}
+
+// TestFuzzerWorkerIncrementalMethodUpdates interleaves the addition and removal of deployed contracts and verifies the
+// methods tracked incrementally by the worker always match those enumerated from scratch.
+func TestFuzzerWorkerIncrementalMethodUpdates(t *testing.T) {
+	worker := newMethodTrackingTestWorker(t)
+	randomProvider := rand.New(rand.NewSource(1))
+	contracts := []*fuzzerTypes.Contract{
+		newSyntheticContract("A", 3, 1),
+		newSyntheticContract("B", 1, 0),
+		newSyntheticContract("C", 0, 2),
+		newSyntheticContract("D", 5, 5),
+	}
+
+	for i := 0; i < 1_000; i++ {
+		// Either add a contract at a random address, or remove a random deployed contract.
+		contractAddress := common.BigToAddress(big.NewInt(int64(randomProvider.Intn(16) + 1)))
+		if _, deployed := worker.deployedContracts[contractAddress]; deployed {
+			delete(worker.deployedContracts, contractAddress)
+			worker.removeContractMethods(contractAddress)
+		} else {
+			contract := contracts[randomProvider.Intn(len(contracts))]
+			worker.deployedContracts[contractAddress] = contract
+			worker.addContractMethods(contractAddress, contract)
+		}
+
+		// Verify the tracked methods match those rebuilt from scratch.
+		expectedStateChanging, expectedPure := rebuildDeployedContractMethods(worker)
+		trackedStateChanging, trackedPure := trackedDeployedContractMethods(t, worker)
+		assert.EqualValues(t, expectedStateChanging, trackedStateChanging)
+		assert.EqualValues(t, expectedPure, trackedPure)
+	}
+}
+
+// BenchmarkFuzzerWorkerIncrementalMethodUpdates measures the cost of adding and removing a deployed contract while
+// many other contracts with many methods are already deployed.
+func BenchmarkFuzzerWorkerIncrementalMethodUpdates(b *testing.B) {
+	worker := newMethodTrackingTestWorker(b)
+	contract := newSyntheticContract("Child", 30, 10)
+	for i := 0; i < 1_000; i++ {
+		contractAddress := common.BigToAddress(big.NewInt(int64(i + 1)))
+		worker.deployedContracts[contractAddress] = contract
+		worker.addContractMethods(contractAddress, contract)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		contractAddress := common.HexToAddress("0xffffffff")
+		worker.deployedContracts[contractAddress] = contract
+		worker.addContractMethods(contractAddress, contract)
+		delete(worker.deployedContracts, contractAddress)
+		worker.removeContractMethods(contractAddress)
+	}
+}
+
+// TestFuzzerWorkerResolveMethod tests that methods of contracts deployed on a worker's chain are resolved by address
+// and selector, and that unknown addresses and selectors are not resolved.
+func TestFuzzerWorkerResolveMethod(t *testing.T) {
+	worker := newMethodTrackingTestWorker(t)
+	parsedAbi, err := abi.JSON(strings.NewReader(`[{"type": "function", "name": "deposit", "stateMutability": "payable", "inputs": [], "outputs": []}]`))
+	assert.NoError(t, err)
+	contract := fuzzerTypes.NewContract("Vault", "vault.sol", &types.CompiledContract{Abi: parsedAbi}, nil)
+	contractAddress := common.BigToAddress(big.NewInt(1))
+	worker.deployedContracts[contractAddress] = contract
+
+	// The method should be resolved for the deployed contract.
+	depositSelector := parsedAbi.Methods["deposit"].ID
+	resolvedMethod := worker.ResolveMethod(contractAddress, depositSelector)
+	assert.NotNil(t, resolvedMethod)
+	assert.EqualValues(t, contractAddress, resolvedMethod.Address)
+	assert.Same(t, contract, resolvedMethod.Contract)
+	assert.EqualValues(t, "deposit()", resolvedMethod.Method.Sig)
+
+	// Unknown selectors and addresses should not be resolved.
+	assert.Nil(t, worker.ResolveMethod(contractAddress, []byte{0xde, 0xad, 0xbe, 0xef}))
+	assert.Nil(t, worker.ResolveMethod(contractAddress, nil))
+	assert.Nil(t, worker.ResolveMethod(common.BigToAddress(big.NewInt(2)), depositSelector))
+}
+
+// TestFuzzerWorkerDynamicDeploymentTargets tests that dynamically deployed contracts only count towards the target
+// limit of their contract definition while they are registered, including when a deployment is registered again.
+func TestFuzzerWorkerDynamicDeploymentTargets(t *testing.T) {
+	worker := newMethodTrackingTestWorker(t)
+	worker.fuzzer.config.Fuzzing.Testing.DynamicDeploymentTargetLimit = 2
+	worker.dynamicDeploymentTargetCounts = make(map[*fuzzerTypes.Contract]int)
+	worker.dynamicDeploymentTargets = make(map[common.Address]*fuzzerTypes.Contract)
+	child := newSyntheticContract("Child", 1, 0)
+	addresses := []common.Address{common.BigToAddress(big.NewInt(1)), common.BigToAddress(big.NewInt(2)), common.BigToAddress(big.NewInt(3))}
+
+	// Registering the same deployment again should not count towards the limit twice.
+	for i := 0; i < 3; i++ {
+		worker.removeDynamicDeploymentTarget(addresses[0])
+		assert.True(t, worker.addDynamicDeploymentTarget(addresses[0], child))
+	}
+	assert.EqualValues(t, 1, worker.dynamicDeploymentTargetCounts[child])
+
+	// Deployments beyond the limit should not be registered, until another is removed.
+	assert.True(t, worker.addDynamicDeploymentTarget(addresses[1], child))
+	assert.False(t, worker.addDynamicDeploymentTarget(addresses[2], child))
+	worker.removeDynamicDeploymentTarget(addresses[0])
+	assert.True(t, worker.addDynamicDeploymentTarget(addresses[2], child))
+
+	// Removing every deployment should leave nothing tracked.
+	for _, address := range addresses {
+		worker.removeDynamicDeploymentTarget(address)
+	}
+	assert.Empty(t, worker.dynamicDeploymentTargets)
+	assert.Empty(t, worker.dynamicDeploymentTargetCounts)
+}
+
+// TestFuzzerWorkerIsolateRandomProvider ensures values drawn from a worker's random provider while it is isolated are
+// derived from the provided seed, and do not alter the random stream which resumes once it is restored.
+func TestFuzzerWorkerIsolateRandomProvider(t *testing.T) {
+	worker := newMethodTrackingTestWorker(t)
+	worker.randomProvider = rand.New(rand.NewSource(1))
+	expected := rand.New(rand.NewSource(1))
+
+	// Draw a value prior to isolating the random provider, so its stream is not at its start.
+	assert.EqualValues(t, expected.Int63(), worker.randomProvider.Int63())
+
+	// Values drawn while isolated should be derived from the provided seed alone.
+	isolatedExpected := rand.New(rand.NewSource(2))
+	restoreRandomProvider := worker.isolateRandomProvider(2)
+	for i := 0; i < 10; i++ {
+		assert.EqualValues(t, isolatedExpected.Int63(), worker.randomProvider.Int63())
+	}
+	restoreRandomProvider()
+
+	// Once restored, the original stream should resume where it left off.
+	for i := 0; i < 10; i++ {
+		assert.EqualValues(t, expected.Int63(), worker.randomProvider.Int63())
+	}
+}
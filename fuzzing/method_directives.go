@@ -0,0 +1,64 @@
+package fuzzing
+
+import (
+	"encoding/hex"
+	"fmt"
+	"slices"
+
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/logging/colors"
+)
+
+// knownMethodDirectives describes every directive which may be provided through the `@custom:medusa` NatSpec tag of a
+// method.
+var knownMethodDirectives = []string{
+	fuzzerTypes.MethodDirectiveIgnore,
+	fuzzerTypes.MethodDirectiveTarget,
+	fuzzerTypes.MethodDirectiveSetup,
+	fuzzerTypes.MethodDirectiveFrozen,
+}
+
+// applyMethodDirectives filters the assertion test methods of each contract definition using the directives provided
+// through their `@custom:medusa` NatSpec tags, warning about any directive which is not known. Methods providing
+// fuzzerTypes.MethodDirectiveIgnore are never called directly. If any method provides
+// fuzzerTypes.MethodDirectiveTarget, only methods providing it are called directly. Configured method lists take
+// precedence over directives: directive targets are not used if target function signatures are configured, and
+// methods in the configured targets are never removed.
+func (f *Fuzzer) applyMethodDirectives() {
+	// Warn about unknown directives, and determine whether any assertion test method is targeted through a directive.
+	targeted := false
+	for _, contractDefinition := range f.contractDefinitions {
+		for selector, directives := range contractDefinition.MethodDirectives {
+			for _, directive := range directives {
+				if !slices.Contains(knownMethodDirectives, directive) {
+					f.logger.Warn("Unknown directive ", colors.Bold, directive, colors.Reset, " in the @custom:medusa NatSpec tag of ", methodDirectiveLocation(contractDefinition, selector), ", it will be ignored")
+				}
+			}
+		}
+		for _, method := range contractDefinition.AssertionTestMethods {
+			targeted = targeted || contractDefinition.HasMethodDirective(method, fuzzerTypes.MethodDirectiveTarget)
+		}
+	}
+	if len(f.config.Fuzzing.Testing.TargetFunctionSignatures) > 0 {
+		if targeted {
+			f.logger.Warn("Target function signatures are configured, so methods targeted through the @custom:medusa NatSpec tag will not be exclusively targeted")
+		}
+		targeted = false
+	}
+
+	// Filter the assertion test methods of each contract definition.
+	for _, contractDefinition := range f.contractDefinitions {
+		contractDefinition.WithAnnotatedAssertionMethods(targeted, f.config.Fuzzing.Testing.TargetFunctionSignatures)
+	}
+}
+
+// methodDirectiveLocation describes the method with the provided hex-encoded selector in the provided contract
+// definition, for use in log messages.
+func methodDirectiveLocation(contractDefinition *fuzzerTypes.Contract, selector string) string {
+	if selectorBytes, err := hex.DecodeString(selector); err == nil {
+		if method, err := contractDefinition.CompiledContract().Abi.MethodById(selectorBytes); err == nil {
+			return fmt.Sprintf("%s.%s", contractDefinition.Name(), method.Sig)
+		}
+	}
+	return fmt.Sprintf("%s (selector 0x%s)", contractDefinition.Name(), selector)
+}
@@ -0,0 +1,303 @@
+package fuzzing
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"sync"
+
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/exp/slices"
+)
+
+// parameterHintContext describes the state which parameter hints generate values relative to.
+type parameterHintContext struct {
+	// randomProvider provides random data as inputs to decisions made while generating values.
+	randomProvider *rand.Rand
+
+	// timestamp describes the timestamp of the head of the chain values are generated for.
+	timestamp uint64
+
+	// contextTimestamps describes the chain context timestamps relative to timestamp, which generated timestamps are
+	// sometimes selected from. This is empty if chain context values are disabled.
+	contextTimestamps []*big.Int
+
+	// maxTimestampDelay describes the maximum distance from timestamp which generated timestamps should fall within.
+	maxTimestampDelay uint64
+
+	// addresses obtains the known addresses which generated addresses should be selected from.
+	addresses func() []common.Address
+}
+
+// parameterHintKind describes a kind of value which a parameter name may hint should be generated for it.
+type parameterHintKind struct {
+	// defaultPatterns describes the regular expressions which parameter names are matched against by default, to
+	// determine whether the hint applies to them. Patterns are matched case-insensitively.
+	defaultPatterns []string
+
+	// appliesTo indicates whether the hint can generate values for parameters of the provided type.
+	appliesTo func(inputType *abi.Type) bool
+
+	// generate generates a value of the provided type for a parameter the hint applies to.
+	generate func(ctx *parameterHintContext, inputType *abi.Type) any
+}
+
+// parameterHintKinds defines the kinds of parameter hints which are supported, keyed by the name used to refer to them
+// in the project configuration.
+var parameterHintKinds = map[string]parameterHintKind{
+	"timestamp": {
+		defaultPatterns: []string{`deadline`, `expir`, `timestamp`, `valid(until|after)`},
+		appliesTo:       isIntegerAbiType,
+		generate:        generateTimestampHintValue,
+	},
+	"address": {
+		defaultPatterns: []string{`recipient`, `receiver`, `beneficiary`, `owner`, `spender`, `account`, `^_?(to|from)$`},
+		appliesTo: func(inputType *abi.Type) bool {
+			return inputType.T == abi.AddressTy
+		},
+		generate: generateAddressHintValue,
+	},
+	"signature": {
+		defaultPatterns: []string{`signature`, `^_?sig$`},
+		appliesTo: func(inputType *abi.Type) bool {
+			return inputType.T == abi.BytesTy
+		},
+		generate: generateSignatureHintValue,
+	},
+	"amount": {
+		defaultPatterns: []string{`amount`, `^_?(value|wad|qty|quantity|shares|assets)$`},
+		appliesTo: func(inputType *abi.Type) bool {
+			return inputType.T == abi.UintTy
+		},
+		generate: generateAmountHintValue,
+	},
+	"salt": {
+		defaultPatterns: []string{`salt`},
+		appliesTo: func(inputType *abi.Type) bool {
+			return inputType.T == abi.UintTy || inputType.T == abi.FixedBytesTy
+		},
+		generate: generateSaltHintValue,
+	},
+}
+
+// parameterHintKindOrder defines the order in which parameter hint kinds are matched against parameter names, so that
+// matching is deterministic when multiple hints match a parameter name.
+var parameterHintKindOrder = []string{"timestamp", "address", "signature", "amount", "salt"}
+
+// parameterHint describes a parameter hint kind and the compiled patterns which parameter names are matched against to
+// determine whether it applies to them.
+type parameterHint struct {
+	// kind describes the kind of parameter hint.
+	kind parameterHintKind
+
+	// patterns describes the regular expressions which parameter names are matched against.
+	patterns []*regexp.Regexp
+}
+
+// parameterHints biases the values generated for method arguments by the names of their parameters, e.g. generating
+// timestamps near the chain head for a parameter named "deadline".
+type parameterHints struct {
+	// hints describes the enabled parameter hints, in the order they are matched against parameter names.
+	hints []parameterHint
+
+	// probability describes the probability that a matching hint is used to generate a value.
+	probability float32
+
+	// matches caches the hint matched for a given parameter name and type, or nil if none matched. It is shared by
+	// every FuzzerWorker, so access is thread-safe.
+	matches sync.Map
+}
+
+// newParameterHints creates parameterHints from the provided configuration.
+// Returns the parameterHints, or an error if the configuration refers to an unknown hint kind or contains an invalid
+// pattern.
+func newParameterHints(hintsConfig config.ParameterNameHintsConfig) (*parameterHints, error) {
+	// Verify all hint kinds referenced by the configuration are known.
+	for kindName := range hintsConfig.Patterns {
+		if _, ok := parameterHintKinds[kindName]; !ok {
+			return nil, fmt.Errorf("unknown parameter name hint kind '%s'", kindName)
+		}
+	}
+	for _, kindName := range hintsConfig.DisabledHints {
+		if _, ok := parameterHintKinds[kindName]; !ok {
+			return nil, fmt.Errorf("unknown parameter name hint kind '%s'", kindName)
+		}
+	}
+
+	// Compile the default and additional patterns of each enabled hint kind.
+	hints := &parameterHints{
+		hints:       make([]parameterHint, 0, len(parameterHintKindOrder)),
+		probability: hintsConfig.Probability,
+	}
+	for _, kindName := range parameterHintKindOrder {
+		if slices.Contains(hintsConfig.DisabledHints, kindName) {
+			continue
+		}
+		kind := parameterHintKinds[kindName]
+		hint := parameterHint{kind: kind}
+		for _, pattern := range append(slices.Clone(kind.defaultPatterns), hintsConfig.Patterns[kindName]...) {
+			compiledPattern, err := regexp.Compile("(?i)" + pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern '%s' for parameter name hint kind '%s': %v", pattern, kindName, err)
+			}
+			hint.patterns = append(hint.patterns, compiledPattern)
+		}
+		hints.hints = append(hints.hints, hint)
+	}
+	return hints, nil
+}
+
+// match obtains the first hint which applies to the provided parameter, or nil if none do.
+func (h *parameterHints) match(input *abi.Argument) *parameterHint {
+	// Check our cache first, as matching is performed for every argument generated.
+	cacheKey := input.Name + ":" + input.Type.String()
+	if cached, ok := h.matches.Load(cacheKey); ok {
+		return cached.(*parameterHint)
+	}
+
+	var matched *parameterHint
+	if input.Name != "" {
+		for i := 0; i < len(h.hints) && matched == nil; i++ {
+			if !h.hints[i].kind.appliesTo(&input.Type) {
+				continue
+			}
+			for _, pattern := range h.hints[i].patterns {
+				if pattern.MatchString(input.Name) {
+					matched = &h.hints[i]
+					break
+				}
+			}
+		}
+	}
+	h.matches.Store(cacheKey, matched)
+	return matched
+}
+
+// generate generates a value for the provided parameter using the hint which applies to it, if any.
+// Returns the generated value and a boolean indicating whether a hint was used. If false, the value should be
+// generated as usual.
+func (h *parameterHints) generate(ctx *parameterHintContext, input *abi.Argument) (any, bool) {
+	hint := h.match(input)
+	if hint == nil || ctx.randomProvider.Float32() >= h.probability {
+		return nil, false
+	}
+	value := hint.kind.generate(ctx, &input.Type)
+	return value, value != nil
+}
+
+// isIntegerAbiType indicates whether the provided ABI type is a signed or unsigned integer.
+func isIntegerAbiType(inputType *abi.Type) bool {
+	return inputType.T == abi.UintTy || inputType.T == abi.IntTy
+}
+
+// integerAbiValue converts the provided integer into the Go type used to represent values of the provided integer ABI
+// type, constraining it to the bit length of the type.
+func integerAbiValue(inputType *abi.Type, value *big.Int) any {
+	signed := inputType.T == abi.IntTy
+	value = utils.ConstrainIntegerToBitLength(value, signed, inputType.Size)
+	if signed {
+		switch inputType.Size {
+		case 64:
+			return value.Int64()
+		case 32:
+			return int32(value.Int64())
+		case 16:
+			return int16(value.Int64())
+		case 8:
+			return int8(value.Int64())
+		}
+		return value
+	}
+	switch inputType.Size {
+	case 64:
+		return value.Uint64()
+	case 32:
+		return uint32(value.Uint64())
+	case 16:
+		return uint16(value.Uint64())
+	case 8:
+		return uint8(value.Uint64())
+	}
+	return value
+}
+
+// contextTimestampHintProbability defines the probability that a timestamp hint selects one of the chain context
+// timestamps, if any are provided, rather than generating a timestamp near the chain head's timestamp.
+const contextTimestampHintProbability = 0.5
+
+// generateTimestampHintValue generates a timestamp within the maximum timestamp delay of the chain head's timestamp,
+// or selects one of the chain context timestamps.
+func generateTimestampHintValue(ctx *parameterHintContext, inputType *abi.Type) any {
+	// Sometimes select a chain context timestamp instead.
+	if len(ctx.contextTimestamps) > 0 && ctx.randomProvider.Float32() < contextTimestampHintProbability {
+		timestamp := ctx.contextTimestamps[ctx.randomProvider.Intn(len(ctx.contextTimestamps))]
+		return integerAbiValue(inputType, new(big.Int).Set(timestamp))
+	}
+
+	// Select an offset in [-maxTimestampDelay, maxTimestampDelay] from the chain head's timestamp.
+	maxTimestampDelay := new(big.Int).SetUint64(ctx.maxTimestampDelay)
+	offsetRange := new(big.Int).Add(new(big.Int).Lsh(maxTimestampDelay, 1), big.NewInt(1))
+	offset := new(big.Int).Rand(ctx.randomProvider, offsetRange)
+	offset.Sub(offset, maxTimestampDelay)
+
+	// Timestamps cannot be negative.
+	timestamp := offset.Add(offset, new(big.Int).SetUint64(ctx.timestamp))
+	if timestamp.Sign() < 0 {
+		timestamp.SetUint64(0)
+	}
+	return integerAbiValue(inputType, timestamp)
+}
+
+// generateAddressHintValue selects a random known address, or returns nil if no addresses are known.
+func generateAddressHintValue(ctx *parameterHintContext, inputType *abi.Type) any {
+	addresses := ctx.addresses()
+	if len(addresses) == 0 {
+		return nil
+	}
+	return addresses[ctx.randomProvider.Intn(len(addresses))]
+}
+
+// generateSignatureHintValue generates a random 65-byte ECDSA signature blob, with a recovery identifier of 27 or 28.
+func generateSignatureHintValue(ctx *parameterHintContext, inputType *abi.Type) any {
+	signature := make([]byte, 65)
+	ctx.randomProvider.Read(signature[:64])
+	signature[64] = byte(27 + ctx.randomProvider.Intn(2))
+	return signature
+}
+
+// amountHintDecimals defines the decimal places which generated amounts are scaled by, matching common token
+// denominations.
+var amountHintDecimals = []int64{0, 6, 8, 18}
+
+// generateAmountHintValue generates a human-scale amount of a token with a commonly used number of decimals.
+func generateAmountHintValue(ctx *parameterHintContext, inputType *abi.Type) any {
+	amount := big.NewInt(ctx.randomProvider.Int63n(1001))
+	decimals := amountHintDecimals[ctx.randomProvider.Intn(len(amountHintDecimals))]
+	amount.Mul(amount, new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil))
+	return integerAbiValue(inputType, amount)
+}
+
+// saltHintValueCount defines the count of distinct values generated for salts, kept small so that salts are frequently
+// reused (e.g. to exercise colliding CREATE2 deployments).
+const saltHintValueCount = 4
+
+// generateSaltHintValue generates one of a small set of salts.
+func generateSaltHintValue(ctx *parameterHintContext, inputType *abi.Type) any {
+	salt := big.NewInt(int64(ctx.randomProvider.Intn(saltHintValueCount)))
+	if inputType.T != abi.FixedBytesTy {
+		return integerAbiValue(inputType, salt)
+	}
+
+	// Fixed bytes must be represented as an array type, which must be created through reflection.
+	array := reflect.Indirect(reflect.New(inputType.GetType()))
+	saltBytes := salt.Bytes()
+	for i := 0; i < len(saltBytes) && i < array.Len(); i++ {
+		array.Index(array.Len() - len(saltBytes) + i).Set(reflect.ValueOf(saltBytes[i]))
+	}
+	return array.Interface()
+}
@@ -0,0 +1,120 @@
+package fuzzing
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/crytic/medusa/fuzzing/valuegeneration"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// newParameterHintsTestArgument creates an ABI argument with the provided name and type.
+func newParameterHintsTestArgument(t *testing.T, name string, typeName string) abi.Argument {
+	argumentType, err := abi.NewType(typeName, "", nil)
+	assert.NoError(t, err)
+	return abi.Argument{Name: name, Type: argumentType}
+}
+
+// TestParameterHintsDeadline tests that with parameter name hints enabled, arguments for a parameter named
+// "deadline" are overwhelmingly generated near the chain head's timestamp, while they rarely are otherwise.
+func TestParameterHintsDeadline(t *testing.T) {
+	projectConfig, err := config.GetDefaultProjectConfig("")
+	assert.NoError(t, err)
+	hints, err := newParameterHints(projectConfig.Fuzzing.ParameterNameHints)
+	assert.NoError(t, err)
+
+	// Generate arguments as the CallSequenceGenerator would, counting those within the maximum timestamp delay of the
+	// chain head's timestamp.
+	randomProvider := rand.New(rand.NewSource(1))
+	valueGenerator := valuegeneration.NewRandomValueGenerator(&valuegeneration.RandomValueGeneratorConfig{}, randomProvider)
+	hintContext := &parameterHintContext{
+		randomProvider:    randomProvider,
+		timestamp:         1_700_000_000,
+		maxTimestampDelay: projectConfig.Fuzzing.MaxBlockTimestampDelay,
+		addresses: func() []common.Address {
+			return nil
+		},
+	}
+	countNearTimestamp := func(hints *parameterHints, argument abi.Argument) int {
+		minTimestamp := big.NewInt(int64(hintContext.timestamp - hintContext.maxTimestampDelay))
+		maxTimestamp := big.NewInt(int64(hintContext.timestamp + hintContext.maxTimestampDelay))
+		count := 0
+		for i := 0; i < 1000; i++ {
+			value, hinted := hints.generate(hintContext, &argument)
+			if !hinted {
+				value = valuegeneration.GenerateAbiValue(valueGenerator, &argument.Type)
+			}
+			timestamp := value.(*big.Int)
+			if timestamp.Cmp(minTimestamp) >= 0 && timestamp.Cmp(maxTimestamp) <= 0 {
+				count++
+			}
+		}
+		return count
+	}
+
+	assert.Greater(t, countNearTimestamp(hints, newParameterHintsTestArgument(t, "deadline", "uint256")), 700)
+	assert.Less(t, countNearTimestamp(hints, newParameterHintsTestArgument(t, "count", "uint256")), 50)
+
+	// Timestamps should also be generated for smaller integer types.
+	argument := newParameterHintsTestArgument(t, "_expiry", "uint64")
+	for i := 0; i < 100; i++ {
+		if value, hinted := hints.generate(hintContext, &argument); hinted {
+			assert.InDelta(t, hintContext.timestamp, value.(uint64), float64(hintContext.maxTimestampDelay))
+		}
+	}
+}
+
+// TestParameterHintsMatching tests that parameter hints are only matched for parameters of applicable types, that
+// hints can be disabled or extended through the configuration, and that invalid configurations are rejected.
+func TestParameterHintsMatching(t *testing.T) {
+	hintsConfig := config.ParameterNameHintsConfig{
+		Enabled:       true,
+		Probability:   1,
+		Patterns:      map[string][]string{"timestamp": {`^when$`}},
+		DisabledHints: []string{"salt"},
+	}
+	hints, err := newParameterHints(hintsConfig)
+	assert.NoError(t, err)
+
+	// Names should be matched case-insensitively, and only for applicable types.
+	deadline := newParameterHintsTestArgument(t, "Deadline", "uint256")
+	assert.Same(t, &hints.hints[0], hints.match(&deadline))
+	deadlineString := newParameterHintsTestArgument(t, "deadline", "string")
+	assert.Nil(t, hints.match(&deadlineString))
+	unnamed := newParameterHintsTestArgument(t, "", "uint256")
+	assert.Nil(t, hints.match(&unnamed))
+
+	// Additional patterns should extend the defaults, and disabled hints should never match.
+	when := newParameterHintsTestArgument(t, "when", "uint256")
+	assert.NotNil(t, hints.match(&when))
+	salt := newParameterHintsTestArgument(t, "salt", "bytes32")
+	assert.Nil(t, hints.match(&salt))
+
+	// Signatures should be 65 bytes long with a valid recovery identifier.
+	signature := newParameterHintsTestArgument(t, "signature", "bytes")
+	hintContext := &parameterHintContext{randomProvider: rand.New(rand.NewSource(1))}
+	value, hinted := hints.generate(hintContext, &signature)
+	assert.True(t, hinted)
+	assert.Len(t, value, 65)
+	assert.Contains(t, []byte{27, 28}, value.([]byte)[64])
+
+	// Address hints should not be applied if there are no known addresses.
+	recipient := newParameterHintsTestArgument(t, "recipient", "address")
+	hintContext.addresses = func() []common.Address {
+		return nil
+	}
+	_, hinted = hints.generate(hintContext, &recipient)
+	assert.False(t, hinted)
+
+	// Unknown hint kinds and invalid patterns should be rejected.
+	_, err = newParameterHints(config.ParameterNameHintsConfig{DisabledHints: []string{"unknown"}})
+	assert.Error(t, err)
+	_, err = newParameterHints(config.ParameterNameHintsConfig{Patterns: map[string][]string{"unknown": {`x`}}})
+	assert.Error(t, err)
+	_, err = newParameterHints(config.ParameterNameHintsConfig{Patterns: map[string][]string{"amount": {`(`}}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,463 @@
+package fuzzing
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/valuegeneration"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	coreTypes "github.com/ethereum/go-ethereum/core/types"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// scriptedOracleCheckFunctionName describes the name of the function a scripted oracle must define, which is called
+// to check each call made in a call sequence.
+const scriptedOracleCheckFunctionName = "check"
+
+// scriptedOracleWorkerThreadLocal describes the key of the Starlark thread local which stores the FuzzerWorker whose
+// chain a scripted oracle is checking a call against.
+const scriptedOracleWorkerThreadLocal = "worker"
+
+// scriptedOraclePredeclared describes the restricted API of functions made available to scripted oracles. Each reads
+// the chain of the FuzzerWorker stored in the thread local of the Starlark thread calling it.
+var scriptedOraclePredeclared = starlark.StringDict{
+	"contract_address": starlark.NewBuiltin("contract_address", scriptedOracleContractAddress),
+	"senders":          starlark.NewBuiltin("senders", scriptedOracleSenders),
+	"balance":          starlark.NewBuiltin("balance", scriptedOracleBalance),
+	"storage":          starlark.NewBuiltin("storage", scriptedOracleStorage),
+	"view":             starlark.NewBuiltin("view", scriptedOracleView),
+}
+
+// scriptedOracle describes a Starlark script which checks each call made in a call sequence.
+type scriptedOracle struct {
+	// name describes the name of the oracle, derived from the file name of its script.
+	name string
+
+	// checkFunc describes the function defined by the script, which is called to check each call.
+	checkFunc *starlark.Function
+
+	// maxExecutionSteps describes the maximum amount of Starlark computation steps checkFunc may execute to check a
+	// single call. If zero, it is not limited in steps.
+	maxExecutionSteps uint64
+
+	// timeout describes the maximum wall-clock time checkFunc may spend checking a single call. If zero, it is not
+	// limited in time.
+	timeout time.Duration
+}
+
+// loadScriptedOracle loads the Starlark script at the provided path, which must define a check function taking a
+// single argument. The script is frozen after it is loaded, so it can be used by multiple workers concurrently.
+// Returns the scripted oracle, or an error if one occurs.
+func loadScriptedOracle(path string, maxExecutionSteps uint64, timeout time.Duration) (*scriptedOracle, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read scripted oracle '%s': %v", path, err)
+	}
+
+	// Execute the script to obtain its globals. Top-level statements are limited by the same budget as checks.
+	thread := &starlark.Thread{Name: path}
+	if maxExecutionSteps > 0 {
+		thread.SetMaxExecutionSteps(maxExecutionSteps)
+	}
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, path, source, scriptedOraclePredeclared)
+	if err != nil {
+		return nil, fmt.Errorf("could not load scripted oracle '%s': %v", path, err)
+	}
+	globals.Freeze()
+
+	// Obtain the check function.
+	checkFunc, ok := globals[scriptedOracleCheckFunctionName].(*starlark.Function)
+	if !ok || checkFunc.NumParams() != 1 {
+		return nil, fmt.Errorf("scripted oracle '%s' must define a '%s(call)' function", path, scriptedOracleCheckFunctionName)
+	}
+	return &scriptedOracle{
+		name:              strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		checkFunc:         checkFunc,
+		maxExecutionSteps: maxExecutionSteps,
+		timeout:           timeout,
+	}, nil
+}
+
+// check checks the last call of the provided call sequence, which was executed on the provided worker's chain.
+// Returns a boolean indicating whether the call passed, a message the script provided to describe the result, or an
+// error if the script could not check the call (e.g. it exceeded its budget).
+func (o *scriptedOracle) check(worker *FuzzerWorker, callSequence calls.CallSequence) (bool, string, error) {
+	// If we have an empty call sequence, there is no call to check.
+	if len(callSequence) == 0 {
+		return true, "", nil
+	}
+	lastCall := callSequence[len(callSequence)-1]
+
+	// Create a thread to call the check function with, limited by our budget.
+	thread := &starlark.Thread{Name: o.name}
+	thread.SetLocal(scriptedOracleWorkerThreadLocal, worker)
+	if o.maxExecutionSteps > 0 {
+		thread.SetMaxExecutionSteps(o.maxExecutionSteps)
+	}
+	if o.timeout > 0 {
+		timer := time.AfterFunc(o.timeout, func() {
+			thread.Cancel(fmt.Sprintf("exceeded timeout of %v", o.timeout))
+		})
+		defer timer.Stop()
+	}
+
+	// Call the check function and interpret its result.
+	result, err := starlark.Call(thread, o.checkFunc, starlark.Tuple{newScriptedOracleCall(lastCall)}, nil)
+	if err != nil {
+		return false, "", fmt.Errorf("scripted oracle '%s' could not check call: %v", o.name, err)
+	}
+	switch result := result.(type) {
+	case starlark.NoneType:
+		return true, "", nil
+	case starlark.Bool:
+		return bool(result), "", nil
+	case starlark.Tuple:
+		if len(result) == 2 {
+			passed, passedOk := result[0].(starlark.Bool)
+			message, messageOk := starlark.AsString(result[1])
+			if passedOk && messageOk {
+				return bool(passed), message, nil
+			}
+		}
+	}
+	return false, "", fmt.Errorf("scripted oracle '%s' must return a bool, or a tuple of a bool and a message, but returned %s", o.name, result.Type())
+}
+
+// newScriptedOracleCall creates the Starlark value describing the provided call sequence element, which is provided
+// to the check function of a scripted oracle.
+func newScriptedOracleCall(element *calls.CallSequenceElement) starlark.Value {
+	var to, contract, method starlark.Value = starlark.None, starlark.None, starlark.None
+	if element.Call.To != nil {
+		to = starlark.String(element.Call.To.String())
+	}
+	if element.Contract != nil {
+		contract = starlark.String(element.Contract.Name())
+	}
+	args := starlark.NewList(nil)
+	if element.Call.DataAbiValues != nil {
+		method = starlark.String(element.Call.DataAbiValues.Method.Sig)
+		for _, value := range element.Call.DataAbiValues.InputValues {
+			_ = args.Append(toScriptedOracleValue(reflect.ValueOf(value)))
+		}
+	}
+	value := big.NewInt(0)
+	if element.Call.Value != nil {
+		value = element.Call.Value
+	}
+	success := false
+	if element.ChainReference != nil {
+		success = element.ChainReference.MessageResults().Receipt.Status == coreTypes.ReceiptStatusSuccessful
+	}
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"sender":   starlark.String(element.Call.From.String()),
+		"to":       to,
+		"contract": contract,
+		"method":   method,
+		"args":     args,
+		"value":    starlark.MakeBigInt(value),
+		"success":  starlark.Bool(success),
+	})
+}
+
+// toScriptedOracleValue converts the provided go-ethereum ABI value into a Starlark value. Integers are converted to
+// Starlark integers, addresses to hex strings, byte arrays and slices to bytes, arrays and slices to lists, and
+// tuples to Starlark tuples of their fields.
+func toScriptedOracleValue(value reflect.Value) starlark.Value {
+	if !value.IsValid() {
+		return starlark.None
+	}
+	switch v := value.Interface().(type) {
+	case *big.Int:
+		if v == nil {
+			return starlark.None
+		}
+		return starlark.MakeBigInt(v)
+	case common.Address:
+		return starlark.String(v.String())
+	case []byte:
+		return starlark.Bytes(v)
+	}
+	switch value.Kind() {
+	case reflect.Bool:
+		return starlark.Bool(value.Bool())
+	case reflect.String:
+		return starlark.String(value.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return starlark.MakeInt64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return starlark.MakeUint64(value.Uint())
+	case reflect.Interface, reflect.Pointer:
+		return toScriptedOracleValue(value.Elem())
+	case reflect.Array, reflect.Slice:
+		// Fixed byte arrays are converted to bytes, rather than lists of integers.
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, value.Len())
+			reflect.Copy(reflect.ValueOf(b), value)
+			return starlark.Bytes(b)
+		}
+		elements := make([]starlark.Value, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			elements[i] = toScriptedOracleValue(value.Index(i))
+		}
+		return starlark.NewList(elements)
+	case reflect.Struct:
+		fields := make(starlark.Tuple, 0, value.NumField())
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).IsExported() {
+				fields = append(fields, toScriptedOracleValue(value.Field(i)))
+			}
+		}
+		return fields
+	default:
+		return starlark.String(fmt.Sprint(value.Interface()))
+	}
+}
+
+// fromScriptedOracleValue converts the provided Starlark value into a generic JSON value (e.g. []any, map[string]any,
+// etc), so it can be decoded into a go-ethereum ABI value with valuegeneration.DecodeJSONArgumentsFromSlice.
+// Returns the converted value, or an error if the value cannot be converted.
+func fromScriptedOracleValue(value starlark.Value) (any, error) {
+	switch v := value.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(v), nil
+	case starlark.Int:
+		return v.String(), nil
+	case starlark.String:
+		return string(v), nil
+	case starlark.Bytes:
+		return hexutil.Encode([]byte(v)), nil
+	case starlark.Indexable:
+		elements := make([]any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			element, err := fromScriptedOracleValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = element
+		}
+		return elements, nil
+	case *starlark.Dict:
+		object := make(map[string]any, v.Len())
+		for _, item := range v.Items() {
+			key, ok := starlark.AsString(item[0])
+			if !ok {
+				return nil, fmt.Errorf("dict keys must be strings, got %s", item[0].Type())
+			}
+			element, err := fromScriptedOracleValue(item[1])
+			if err != nil {
+				return nil, err
+			}
+			object[key] = element
+		}
+		return object, nil
+	default:
+		return nil, fmt.Errorf("unsupported value of type %s", value.Type())
+	}
+}
+
+// scriptedOracleThreadWorker obtains the FuzzerWorker stored in the thread local of the provided Starlark thread.
+// Returns the worker, or an error if the thread is not checking a call.
+func scriptedOracleThreadWorker(thread *starlark.Thread, builtin *starlark.Builtin) (*FuzzerWorker, error) {
+	worker, ok := thread.Local(scriptedOracleWorkerThreadLocal).(*FuzzerWorker)
+	if !ok {
+		return nil, fmt.Errorf("%s: can only be called while checking a call", builtin.Name())
+	}
+	return worker, nil
+}
+
+// unpackScriptedOracleAddress parses the provided hex string as an address for the provided builtin.
+// Returns the address, or an error if the string is not a well-formed address.
+func unpackScriptedOracleAddress(builtin *starlark.Builtin, address string) (common.Address, error) {
+	if !common.IsHexAddress(address) {
+		return common.Address{}, fmt.Errorf("%s: invalid address '%s'", builtin.Name(), address)
+	}
+	return common.HexToAddress(address), nil
+}
+
+// scriptedOracleContractAddress implements the contract_address(name) function of the scripted oracle API, which
+// returns the address of the deployed contract with the provided name.
+func scriptedOracleContractAddress(thread *starlark.Thread, builtin *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	if err := starlark.UnpackPositionalArgs(builtin.Name(), args, kwargs, 1, &name); err != nil {
+		return nil, err
+	}
+	worker, err := scriptedOracleThreadWorker(thread, builtin)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort matching addresses so the same deployment is returned if the contract was deployed more than once.
+	addresses := make([]common.Address, 0)
+	for address, contract := range worker.deployedContracts {
+		if contract.Name() == name {
+			addresses = append(addresses, address)
+		}
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("%s: no deployed contract named '%s'", builtin.Name(), name)
+	}
+	slices.SortFunc(addresses, func(a, b common.Address) int {
+		return a.Cmp(b)
+	})
+	return starlark.String(addresses[0].String()), nil
+}
+
+// scriptedOracleSenders implements the senders() function of the scripted oracle API, which returns the addresses
+// which send calls.
+func scriptedOracleSenders(thread *starlark.Thread, builtin *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(builtin.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+	worker, err := scriptedOracleThreadWorker(thread, builtin)
+	if err != nil {
+		return nil, err
+	}
+	senders := make([]starlark.Value, 0, len(worker.fuzzer.senders))
+	for _, sender := range worker.fuzzer.senders {
+		senders = append(senders, starlark.String(sender.String()))
+	}
+	return starlark.NewList(senders), nil
+}
+
+// scriptedOracleBalance implements the balance(address) function of the scripted oracle API, which returns the
+// balance of the provided address, in wei.
+func scriptedOracleBalance(thread *starlark.Thread, builtin *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var addressString string
+	if err := starlark.UnpackPositionalArgs(builtin.Name(), args, kwargs, 1, &addressString); err != nil {
+		return nil, err
+	}
+	worker, err := scriptedOracleThreadWorker(thread, builtin)
+	if err != nil {
+		return nil, err
+	}
+	address, err := unpackScriptedOracleAddress(builtin, addressString)
+	if err != nil {
+		return nil, err
+	}
+	return starlark.MakeBigInt(worker.chain.State().GetBalance(address).ToBig()), nil
+}
+
+// scriptedOracleStorage implements the storage(address, slot) function of the scripted oracle API, which returns the
+// value of the provided storage slot of the provided address, as an integer.
+func scriptedOracleStorage(thread *starlark.Thread, builtin *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var addressString string
+	var slot starlark.Int
+	if err := starlark.UnpackPositionalArgs(builtin.Name(), args, kwargs, 2, &addressString, &slot); err != nil {
+		return nil, err
+	}
+	worker, err := scriptedOracleThreadWorker(thread, builtin)
+	if err != nil {
+		return nil, err
+	}
+	address, err := unpackScriptedOracleAddress(builtin, addressString)
+	if err != nil {
+		return nil, err
+	}
+	slotBig := slot.BigInt()
+	if slotBig.Sign() < 0 || slotBig.BitLen() > 256 {
+		return nil, fmt.Errorf("%s: invalid storage slot %v", builtin.Name(), slotBig)
+	}
+	return starlark.MakeBigInt(worker.chain.State().GetState(address, common.BigToHash(slotBig)).Big()), nil
+}
+
+// scriptedOracleView implements the view(address, method, *args) function of the scripted oracle API, which calls a
+// method of the contract deployed at the provided address against the current chain state, without changing it. The
+// method is provided as a signature, or a method name if it is unambiguous.
+// Returns the value returned by the method, a tuple of values if it returned several, or None if the call failed.
+func scriptedOracleView(thread *starlark.Thread, builtin *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if len(args) < 2 || len(kwargs) > 0 {
+		return nil, fmt.Errorf("%s: expected an address, a method, and its arguments", builtin.Name())
+	}
+	addressString, ok := starlark.AsString(args[0])
+	if !ok {
+		return nil, fmt.Errorf("%s: address must be a string, got %s", builtin.Name(), args[0].Type())
+	}
+	methodName, ok := starlark.AsString(args[1])
+	if !ok {
+		return nil, fmt.Errorf("%s: method must be a string, got %s", builtin.Name(), args[1].Type())
+	}
+	worker, err := scriptedOracleThreadWorker(thread, builtin)
+	if err != nil {
+		return nil, err
+	}
+	address, err := unpackScriptedOracleAddress(builtin, addressString)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the method of the contract deployed at the address.
+	contract := worker.resolveContract(address)
+	if contract == nil {
+		return nil, fmt.Errorf("%s: no known contract is deployed at %s", builtin.Name(), address)
+	}
+	matchingMethods := make([]abi.Method, 0)
+	for _, contractMethod := range contract.CompiledContract().Abi.Methods {
+		if contractMethod.Sig == methodName || contractMethod.RawName == methodName {
+			matchingMethods = append(matchingMethods, contractMethod)
+		}
+	}
+	if len(matchingMethods) == 0 {
+		return nil, fmt.Errorf("%s: contract '%s' has no method '%s'", builtin.Name(), contract.Name(), methodName)
+	}
+	if len(matchingMethods) > 1 {
+		return nil, fmt.Errorf("%s: method name '%s' of contract '%s' is ambiguous, provide its signature", builtin.Name(), methodName, contract.Name())
+	}
+	method := matchingMethods[0]
+
+	// Convert and encode the arguments.
+	jsonArgs := make([]any, 0, len(args)-2)
+	for _, arg := range args[2:] {
+		jsonArg, err := fromScriptedOracleValue(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", builtin.Name(), err)
+		}
+		jsonArgs = append(jsonArgs, jsonArg)
+	}
+	methodArgs, err := valuegeneration.DecodeJSONArgumentsFromSlice(method.Inputs, jsonArgs, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid arguments for method '%s': %v", builtin.Name(), method.Sig, err)
+	}
+	encodedArgs, err := method.Inputs.Pack(methodArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid arguments for method '%s': %v", builtin.Name(), method.Sig, err)
+	}
+
+	// Call the method from our first sender, as done for property tests.
+	data := append(slices.Clone(method.ID), encodedArgs...)
+	msg := calls.NewCallMessage(worker.Fuzzer().senders[0], &address, 0, big.NewInt(0), worker.fuzzer.config.Fuzzing.TransactionGasLimit, nil, nil, nil, data)
+	msg.FillFromTestChainProperties(worker.chain)
+	executionResult, err := worker.Chain().CallContract(msg.ToCoreMessage(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to call method '%s': %v", builtin.Name(), method.Sig, err)
+	}
+	if executionResult.Failed() {
+		return starlark.None, nil
+	}
+
+	// Decode the returned values.
+	returnValues, err := method.Outputs.Unpack(executionResult.Return())
+	if err != nil {
+		return starlark.None, nil
+	}
+	if len(returnValues) == 1 {
+		return toScriptedOracleValue(reflect.ValueOf(returnValues[0])), nil
+	}
+	results := make(starlark.Tuple, len(returnValues))
+	for i, returnValue := range returnValues {
+		results[i] = toScriptedOracleValue(reflect.ValueOf(returnValue))
+	}
+	return results, nil
+}
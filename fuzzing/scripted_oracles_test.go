@@ -0,0 +1,78 @@
+package fuzzing
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestScriptedOracleCheck tests that scripted oracles are loaded only if they define a check function, that the
+// results they return are interpreted as passing or failing with a message, and that scripts which exceed their step
+// or time budget are aborted and reported as failing.
+func TestScriptedOracleCheck(t *testing.T) {
+	// Create a call to check, with arguments.
+	sender := common.HexToAddress("0x10000")
+	recipient := common.HexToAddress("0x20000")
+	msg := calls.NewCallMessage(sender, &recipient, 0, big.NewInt(7), 100_000, nil, nil, nil, nil)
+	callSequence := calls.CallSequence{calls.NewCallSequenceElement(nil, msg, 0, 0)}
+
+	// writeScript writes a script with the provided source and returns its path.
+	writeScript := func(name string, source string) string {
+		path := filepath.Join(t.TempDir(), name)
+		assert.NoError(t, os.WriteFile(path, []byte(source), 0644))
+		return path
+	}
+
+	tests := []struct {
+		// source describes the source of the script.
+		source string
+		// maxExecutionSteps describes the step budget of the script.
+		maxExecutionSteps uint64
+		// timeout describes the time budget of the script.
+		timeout time.Duration
+		// expectedPassed describes whether the call is expected to pass the script.
+		expectedPassed bool
+		// expectedMessage describes the message the script is expected to report.
+		expectedMessage string
+		// expectedError describes whether checking the call is expected to return an error.
+		expectedError bool
+	}{
+		{source: "def check(call):\n    return call.sender == \"" + sender.String() + "\" and call.value == 7", expectedPassed: true},
+		{source: "def check(call):\n    return None", expectedPassed: true},
+		{source: "def check(call):\n    return False, \"sent to %s\" % call.to", expectedMessage: "sent to " + recipient.String()},
+		{source: "def check(call):\n    return 1", expectedError: true},
+		{source: "def check(call):\n    return call.success or call.method != None"},
+		{source: "def check(call):\n    for i in range(1000000):\n        pass\n    return True", maxExecutionSteps: 1000, expectedError: true},
+		{source: "def check(call):\n    for i in range(1000000000):\n        pass\n    return True", timeout: 10 * time.Millisecond, expectedError: true},
+	}
+	for _, test := range tests {
+		oracle, err := loadScriptedOracle(writeScript("oracle.star", test.source), test.maxExecutionSteps, test.timeout)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "oracle", oracle.name)
+		passed, message, err := oracle.check(nil, callSequence)
+		if test.expectedError {
+			assert.Error(t, err, test.source)
+
+			// The test provider should report scripts which could not check a call as failing, with the error.
+			passed, message = checkScriptedOracle(nil, &ScriptedOracleTestCase{oracle: oracle}, callSequence)
+			assert.False(t, passed, test.source)
+			assert.EqualValues(t, err.Error(), message, test.source)
+			continue
+		}
+		assert.NoError(t, err, test.source)
+		assert.EqualValues(t, test.expectedPassed, passed, test.source)
+		assert.EqualValues(t, test.expectedMessage, message, test.source)
+	}
+
+	// Scripts must define a check function taking the call, and must be valid.
+	for _, source := range []string{"def other(call):\n    return True", "def check():\n    return True", "def check(call)"} {
+		_, err := loadScriptedOracle(writeScript("invalid.star", source), 0, 0)
+		assert.Error(t, err, source)
+	}
+}
@@ -0,0 +1,257 @@
+package fuzzing
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/crytic/medusa/compilation/abiutils"
+	"github.com/crytic/medusa/fuzzing/calls"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// restrictedSenderWeight describes the selection weight of a sender which is restricted from calling a method,
+// relative to a weight of 1 for every unrestricted sender. It is non-zero so restricted senders are still retried
+// occasionally, in case they are granted access while fuzzing.
+const restrictedSenderWeight = 0.05
+
+var (
+	// accessControlErrorSelectors describes the selectors of custom errors commonly used to reject callers which lack
+	// access to a method.
+	accessControlErrorSelectors = [][]byte{
+		crypto.Keccak256([]byte("OwnableUnauthorizedAccount(address)"))[:4],
+		crypto.Keccak256([]byte("AccessControlUnauthorizedAccount(address,bytes32)"))[:4],
+		crypto.Keccak256([]byte("AccessManagedUnauthorized(address)"))[:4],
+		crypto.Keccak256([]byte("Unauthorized()"))[:4],
+		crypto.Keccak256([]byte("NotOwner()"))[:4],
+		crypto.Keccak256([]byte("OnlyOwner()"))[:4],
+	}
+
+	// accessControlErrorMessages describes lowercase substrings of Error(string) messages commonly used to reject
+	// callers which lack access to a method.
+	accessControlErrorMessages = []string{
+		"ownable:",
+		"accesscontrol:",
+		"caller is not the owner",
+		"not owner",
+		"only owner",
+		"onlyowner",
+		"unauthorized",
+		"not authorized",
+		"missing role",
+	}
+)
+
+// isAccessControlRevert determines whether a call failed because its sender lacks access to the method it called,
+// given its VM error and return data.
+func isAccessControlRevert(returnError error, returnData []byte) bool {
+	if !errors.Is(returnError, vm.ErrExecutionReverted) || len(returnData) < 4 {
+		return false
+	}
+
+	// Check for Error(string) messages which look like an access check.
+	if bytes.Equal(returnData[:4], revertErrorSelector) {
+		message := abiutils.GetSolidityRevertErrorString(returnError, returnData)
+		if message == nil {
+			return false
+		}
+		lowerMessage := strings.ToLower(*message)
+		for _, accessControlMessage := range accessControlErrorMessages {
+			if strings.Contains(lowerMessage, accessControlMessage) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Check for custom errors which look like an access check.
+	for _, selector := range accessControlErrorSelectors {
+		if bytes.Equal(returnData[:4], selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// senderAccessKey describes a method on a deployed contract, for which senderAccessTracker tracks call outcomes by
+// sender.
+type senderAccessKey struct {
+	// address describes the address of the contract the method is deployed on.
+	address common.Address
+
+	// signature describes the signature of the method.
+	signature string
+}
+
+// senderAccessStats describes the outcomes of the calls a single sender made to a single method.
+type senderAccessStats struct {
+	// successes describes the count of calls which succeeded.
+	successes atomic.Uint64
+
+	// reverts describes the count of calls which failed, for any reason.
+	reverts atomic.Uint64
+
+	// accessControlReverts describes the count of consecutive calls which failed with an access control revert,
+	// since the last call which did not.
+	accessControlReverts atomic.Uint64
+}
+
+// senderAccessTracker tracks the outcomes of the calls each sender makes to each method across every FuzzerWorker, so
+// senders which are repeatedly rejected by a method's access control (e.g. an `onlyOwner` modifier) are selected less
+// often to call it.
+type senderAccessTracker struct {
+	// threshold describes the count of consecutive access control reverts after which a sender is restricted from
+	// calling a method.
+	threshold uint64
+
+	// stats describes the call outcomes of each method, by sender.
+	stats map[senderAccessKey]map[common.Address]*senderAccessStats
+
+	// lock provides thread-synchronization, as calls are recorded and senders are selected by every FuzzerWorker.
+	lock sync.RWMutex
+}
+
+// newSenderAccessTracker creates a new, empty senderAccessTracker which restricts a sender from calling a method
+// after the provided count of consecutive access control reverts.
+func newSenderAccessTracker(threshold uint64) *senderAccessTracker {
+	return &senderAccessTracker{
+		threshold: threshold,
+		stats:     make(map[senderAccessKey]map[common.Address]*senderAccessStats),
+	}
+}
+
+// entry obtains the call outcomes of the provided sender for the provided method, creating them if they do not exist.
+func (t *senderAccessTracker) entry(key senderAccessKey, sender common.Address) *senderAccessStats {
+	t.lock.RLock()
+	stats := t.stats[key][sender]
+	t.lock.RUnlock()
+	if stats != nil {
+		return stats
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	senderStats, ok := t.stats[key]
+	if !ok {
+		senderStats = make(map[common.Address]*senderAccessStats)
+		t.stats[key] = senderStats
+	}
+	if stats, ok = senderStats[sender]; !ok {
+		stats = &senderAccessStats{}
+		senderStats[sender] = stats
+	}
+	return stats
+}
+
+// record records the outcome of a call the provided sender made to the provided method, given its VM error and
+// return data.
+func (t *senderAccessTracker) record(key senderAccessKey, sender common.Address, returnError error, returnData []byte) {
+	stats := t.entry(key, sender)
+	if returnError == nil {
+		stats.successes.Add(1)
+		stats.accessControlReverts.Store(0)
+		return
+	}
+	stats.reverts.Add(1)
+	if isAccessControlRevert(returnError, returnData) {
+		stats.accessControlReverts.Add(1)
+	} else {
+		stats.accessControlReverts.Store(0)
+	}
+}
+
+// restricted determines whether the provided sender is restricted from calling the provided method, as its recent
+// calls to it were repeatedly rejected by access control.
+func (t *senderAccessTracker) restricted(key senderAccessKey, sender common.Address) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	stats := t.stats[key][sender]
+	return stats != nil && stats.accessControlReverts.Load() >= t.threshold
+}
+
+// selectSender selects a sender to call the provided method from the provided list, using the provided random
+// provider. Senders restricted from calling the method are selected with restrictedSenderWeight relative to every
+// other sender. If no sender is restricted, or every sender is, the selection is uniform.
+// Returns the index of the selected sender.
+func (t *senderAccessTracker) selectSender(key senderAccessKey, senders []common.Address, randomProvider *rand.Rand) int {
+	// Determine which senders are restricted, if any.
+	t.lock.RLock()
+	senderStats := t.stats[key]
+	restrictedCount := 0
+	var restricted []bool
+	if senderStats != nil {
+		restricted = make([]bool, len(senders))
+		for i, sender := range senders {
+			if stats := senderStats[sender]; stats != nil && stats.accessControlReverts.Load() >= t.threshold {
+				restricted[i] = true
+				restrictedCount++
+			}
+		}
+	}
+	t.lock.RUnlock()
+
+	// If the selection is uniform, select a sender as we would without tracking access.
+	if restrictedCount == 0 || restrictedCount == len(senders) {
+		return randomProvider.Intn(len(senders))
+	}
+
+	// Otherwise select a sender by weight.
+	totalWeight := float64(len(senders)-restrictedCount) + float64(restrictedCount)*restrictedSenderWeight
+	target := randomProvider.Float64() * totalWeight
+	for i := range senders {
+		weight := 1.0
+		if restricted[i] {
+			weight = restrictedSenderWeight
+		}
+		if target < weight {
+			return i
+		}
+		target -= weight
+	}
+	return len(senders) - 1
+}
+
+// callCount returns the count of calls the provided sender made to the provided method, and how many of them
+// succeeded.
+func (t *senderAccessTracker) callCount(key senderAccessKey, sender common.Address) (uint64, uint64) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	stats := t.stats[key][sender]
+	if stats == nil {
+		return 0, 0
+	}
+	successes := stats.successes.Load()
+	return successes + stats.reverts.Load(), successes
+}
+
+// selectSender selects a sender to call the provided method. Senders whose recent calls to the method were repeatedly
+// rejected by access control are selected less often, if tracking access is enabled.
+// Returns the address of the selected sender.
+func (fw *FuzzerWorker) selectSender(method *fuzzerTypes.DeployedContractMethod) common.Address {
+	senders := fw.fuzzer.senders
+	tracker := fw.senderAccessTracker()
+	if tracker == nil {
+		return senders[fw.randomProvider.Intn(len(senders))]
+	}
+	key := senderAccessKey{address: method.Address, signature: method.Method.Sig}
+	return senders[tracker.selectSender(key, senders, fw.randomProvider)]
+}
+
+// recordSenderAccess records the outcome of the last call executed in the provided call sequence, if tracking access
+// is enabled and the call was generated from a method's ABI.
+func (fw *FuzzerWorker) recordSenderAccess(callSequence calls.CallSequence) {
+	tracker := fw.senderAccessTracker()
+	element := callSequence[len(callSequence)-1]
+	if tracker == nil || element.Call.To == nil || element.Call.DataAbiValues == nil || element.Call.DataAbiValues.Method == nil {
+		return
+	}
+	key := senderAccessKey{address: *element.Call.To, signature: element.Call.DataAbiValues.Method.Sig}
+	executionResult := element.ChainReference.MessageResults().ExecutionResult
+	tracker.record(key, element.Call.From, executionResult.Err, executionResult.ReturnData)
+}
@@ -0,0 +1,131 @@
+package fuzzing
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// encodeErrorString encodes the provided message as Error(string) revert data.
+func encodeErrorString(t *testing.T, message string) []byte {
+	stringType, err := abi.NewType("string", "", nil)
+	assert.NoError(t, err)
+	encodedMessage, err := abi.Arguments{{Type: stringType}}.Pack(message)
+	assert.NoError(t, err)
+	return append(append([]byte{}, revertErrorSelector...), encodedMessage...)
+}
+
+// TestIsAccessControlRevert tests the detection of failed calls which were rejected by access control, from their VM
+// error and return data.
+func TestIsAccessControlRevert(t *testing.T) {
+	ownableUnauthorizedAccount := append(crypto.Keccak256([]byte("OwnableUnauthorizedAccount(address)"))[:4], common.LeftPadBytes([]byte{0x01}, 32)...)
+
+	tests := []struct {
+		// name describes the test case.
+		name string
+
+		// returnError describes the VM error of the failed call.
+		returnError error
+
+		// returnData describes the return data of the failed call.
+		returnData []byte
+
+		// expected describes whether the failure is expected to be detected as an access control revert.
+		expected bool
+	}{
+		{
+			name:        "ownable message",
+			returnError: vm.ErrExecutionReverted,
+			returnData:  encodeErrorString(t, "Ownable: caller is not the owner"),
+			expected:    true,
+		},
+		{
+			name:        "access control message",
+			returnError: vm.ErrExecutionReverted,
+			returnData:  encodeErrorString(t, "AccessControl: account 0x0000000000000000000000000000000000010000 is missing role 0x00"),
+			expected:    true,
+		},
+		{
+			name:        "unrelated message",
+			returnError: vm.ErrExecutionReverted,
+			returnData:  encodeErrorString(t, "insufficient balance"),
+			expected:    false,
+		},
+		{
+			name:        "ownable custom error",
+			returnError: vm.ErrExecutionReverted,
+			returnData:  ownableUnauthorizedAccount,
+			expected:    true,
+		},
+		{
+			name:        "unrelated custom error",
+			returnError: vm.ErrExecutionReverted,
+			returnData:  common.FromHex("0xdeadbeef0000000000000000000000000000000000000000000000000000000000000001"),
+			expected:    false,
+		},
+		{
+			name:        "revert without data",
+			returnError: vm.ErrExecutionReverted,
+			expected:    false,
+		},
+		{
+			name:        "out of gas",
+			returnError: vm.ErrOutOfGas,
+			returnData:  ownableUnauthorizedAccount,
+			expected:    false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.EqualValues(t, test.expected, isAccessControlRevert(test.returnError, test.returnData))
+		})
+	}
+}
+
+// TestSenderAccessTracker tests that a sender is restricted from calling a method once enough consecutive calls from
+// it were rejected by access control, that restricted senders are selected rarely but not never, and that a call
+// which is not rejected lifts the restriction.
+func TestSenderAccessTracker(t *testing.T) {
+	tracker := newSenderAccessTracker(3)
+	key := senderAccessKey{address: common.HexToAddress("0x1234"), signature: "setValue(uint256)"}
+	owner, other := common.HexToAddress("0x10000"), common.HexToAddress("0x20000")
+	senders := []common.Address{owner, other}
+	accessControlRevert := encodeErrorString(t, "Ownable: caller is not the owner")
+
+	// Record access control reverts from the other sender, up to the threshold, interleaved with successes from the
+	// owner. Unrelated reverts should reset the count of consecutive access control reverts.
+	tracker.record(key, other, vm.ErrExecutionReverted, accessControlRevert)
+	tracker.record(key, other, vm.ErrExecutionReverted, nil)
+	for i := 0; i < 3; i++ {
+		assert.False(t, tracker.restricted(key, other))
+		tracker.record(key, other, vm.ErrExecutionReverted, accessControlRevert)
+		tracker.record(key, owner, nil, nil)
+	}
+	assert.True(t, tracker.restricted(key, other))
+	assert.False(t, tracker.restricted(key, owner))
+	callCount, successCount := tracker.callCount(key, other)
+	assert.EqualValues(t, 5, callCount)
+	assert.EqualValues(t, 0, successCount)
+
+	// The restricted sender should be selected rarely, but still be retried.
+	randomProvider := rand.New(rand.NewSource(1234))
+	selections := make([]int, len(senders))
+	for i := 0; i < 10_000; i++ {
+		selections[tracker.selectSender(key, senders, randomProvider)]++
+	}
+	assert.Greater(t, selections[0], 9_000)
+	assert.Greater(t, selections[1], 0)
+
+	// Other methods are unaffected.
+	otherKey := senderAccessKey{address: key.address, signature: "otherMethod()"}
+	assert.False(t, tracker.restricted(otherKey, other))
+
+	// A success lifts the restriction, as the sender may have been granted access.
+	tracker.record(key, other, nil, nil)
+	assert.False(t, tracker.restricted(key, other))
+}
@@ -0,0 +1,158 @@
+package fuzzing
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+)
+
+const (
+	// slowSequenceLeaderboardSize describes the maximum amount of call sequences tracked as the slowest sequences.
+	slowSequenceLeaderboardSize = 10
+
+	// sequenceDurationSmoothing describes the smoothing factor of the exponentially weighted average of call sequence
+	// durations, which describes how much weight the most recent call sequence has.
+	sequenceDurationSmoothing = 0.05
+)
+
+// SlowSequence describes a call sequence which took a long time to execute during a fuzzing campaign.
+type SlowSequence struct {
+	// CorpusFile describes the name of the corpus file the call sequence was replayed from, if it was replayed.
+	CorpusFile string `json:"corpusFile,omitempty"`
+
+	// ReplayID describes the ReplayID of the call sequence, if it was generated rather than replayed from the corpus.
+	ReplayID string `json:"replayId,omitempty"`
+
+	// Duration describes the wall-clock time taken to execute and test the call sequence.
+	Duration time.Duration `json:"duration"`
+
+	// Calls describes the amount of calls executed in the call sequence.
+	Calls int `json:"calls"`
+
+	// Contract describes the name of the contract targeted by the method which used the most gas in the call sequence.
+	Contract string `json:"contract"`
+
+	// Method describes the signature of the method which used the most gas in the call sequence.
+	Method string `json:"method"`
+
+	// MethodGasUsed describes the gas used by all calls to Method in the call sequence.
+	MethodGasUsed uint64 `json:"methodGasUsed"`
+}
+
+// sequenceDurationMetrics tracks the wall-clock time taken to execute call sequences for a single FuzzerWorker. It is
+// safe for concurrent use.
+type sequenceDurationMetrics struct {
+	// lock is used to synchronize access to the metrics, as they are read while the worker is executing.
+	lock sync.Mutex
+
+	// average describes the exponentially weighted average of call sequence durations.
+	average float64
+
+	// samples describes the amount of call sequence durations recorded.
+	samples uint64
+
+	// slowest describes the slowest call sequences recorded, sorted by descending duration.
+	slowest []SlowSequence
+}
+
+// record records the duration of a call sequence, updating the average duration. Returns a boolean indicating whether
+// the call sequence is among the slowest recorded, in which case it should be added with addSlowSequence.
+func (m *sequenceDurationMetrics) record(duration time.Duration) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// Update our average, starting from the first duration recorded.
+	if m.samples == 0 {
+		m.average = float64(duration)
+	} else {
+		m.average += sequenceDurationSmoothing * (float64(duration) - m.average)
+	}
+	m.samples++
+
+	// Determine whether the call sequence would make it onto our leaderboard.
+	return len(m.slowest) < slowSequenceLeaderboardSize || duration > m.slowest[len(m.slowest)-1].Duration
+}
+
+// addSlowSequence adds the provided call sequence to the slowest call sequences recorded, if it is among them.
+func (m *sequenceDurationMetrics) addSlowSequence(slowSequence SlowSequence) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.slowest = insertSlowSequence(m.slowest, slowSequence)
+}
+
+// insertSlowSequence inserts the provided call sequence into the provided leaderboard of call sequences, sorted by
+// descending duration, keeping at most slowSequenceLeaderboardSize call sequences. Returns the updated leaderboard.
+func insertSlowSequence(leaderboard []SlowSequence, slowSequence SlowSequence) []SlowSequence {
+	index := sort.Search(len(leaderboard), func(i int) bool {
+		return leaderboard[i].Duration < slowSequence.Duration
+	})
+	if index >= slowSequenceLeaderboardSize {
+		return leaderboard
+	}
+	leaderboard = append(leaderboard, SlowSequence{})
+	copy(leaderboard[index+1:], leaderboard[index:])
+	leaderboard[index] = slowSequence
+	if len(leaderboard) > slowSequenceLeaderboardSize {
+		leaderboard = leaderboard[:slowSequenceLeaderboardSize]
+	}
+	return leaderboard
+}
+
+// dominantSequenceMethod determines the method which used the most gas across all calls to it in the provided
+// executed call sequence.
+// Returns the name of the contract targeted by the method, its signature, and the gas used by calls to it. If no
+// calls used any gas, empty names and a zero gas value are returned.
+func dominantSequenceMethod(sequence calls.CallSequence) (string, string, uint64) {
+	// Define a key describing a method of a given contract.
+	type methodKey struct {
+		contract *fuzzerTypes.Contract
+		methodID [4]byte
+	}
+
+	// Sum the gas used by calls to each method, tracking the method which used the most.
+	gasUsed := make(map[methodKey]uint64)
+	var dominantKey methodKey
+	var dominantElement *calls.CallSequenceElement
+	for _, element := range sequence {
+		if element == nil || element.ChainReference == nil {
+			continue
+		}
+		key := methodKey{contract: element.Contract}
+		copy(key.methodID[:], element.Call.Data)
+		gasUsed[key] += element.ChainReference.MessageResults().Receipt.GasUsed
+		if dominantElement == nil || gasUsed[key] > gasUsed[dominantKey] {
+			dominantKey, dominantElement = key, element
+		}
+	}
+	if dominantElement == nil || gasUsed[dominantKey] == 0 {
+		return "", "", 0
+	}
+
+	// Resolve the names of the method and the contract it targets.
+	contractName, methodName := "<unresolved contract>", "<unresolved method>"
+	if dominantElement.Contract != nil {
+		contractName = dominantElement.Contract.Name()
+	}
+	if method, err := dominantElement.Method(); err == nil && method != nil {
+		methodName = method.Sig
+	}
+	return contractName, methodName, gasUsed[dominantKey]
+}
+
+// appendSlowSequence appends a displayable line describing the provided SlowSequence to the provided log buffer.
+func appendSlowSequence(buffer *logging.LogBuffer, slowSequence SlowSequence) {
+	source := fmt.Sprintf("replay ID %s", slowSequence.ReplayID)
+	if slowSequence.CorpusFile != "" {
+		source = fmt.Sprintf("corpus file %s", slowSequence.CorpusFile)
+	}
+	buffer.Append(fmt.Sprintf("\n - %s: ", source), colors.Bold, slowSequence.Duration.Round(time.Millisecond).String(), colors.Reset, fmt.Sprintf(" for %d call(s)", slowSequence.Calls))
+	if slowSequence.Method != "" {
+		buffer.Append(fmt.Sprintf(", most gas used by %s.%s (%d gas)", slowSequence.Contract, slowSequence.Method, slowSequence.MethodGasUsed))
+	}
+}
@@ -0,0 +1,62 @@
+package fuzzing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSequenceDurationMetrics tests that call sequence durations are averaged with the most recent durations weighted
+// most, and that only the slowest call sequences are kept, sorted by descending duration.
+func TestSequenceDurationMetrics(t *testing.T) {
+	metrics := &sequenceDurationMetrics{slowest: make([]SlowSequence, 0)}
+
+	// The first duration recorded should set the average, while later ones should only move it towards them.
+	assert.True(t, metrics.record(100*time.Millisecond))
+	assert.EqualValues(t, 100*time.Millisecond, time.Duration(metrics.average))
+	metrics.record(200 * time.Millisecond)
+	assert.Greater(t, time.Duration(metrics.average), 100*time.Millisecond)
+	assert.Less(t, time.Duration(metrics.average), 150*time.Millisecond)
+
+	// Fill the leaderboard with increasing durations, then add more than it can hold.
+	for i := 1; i <= 2*slowSequenceLeaderboardSize; i++ {
+		duration := time.Duration(i) * time.Millisecond
+		if metrics.record(duration) {
+			metrics.addSlowSequence(SlowSequence{Duration: duration})
+		}
+	}
+	assert.Len(t, metrics.slowest, slowSequenceLeaderboardSize)
+	for i, slowSequence := range metrics.slowest {
+		assert.EqualValues(t, time.Duration(2*slowSequenceLeaderboardSize-i)*time.Millisecond, slowSequence.Duration)
+	}
+
+	// A call sequence faster than every call sequence on a full leaderboard should not make it onto the leaderboard.
+	assert.False(t, metrics.record(time.Millisecond))
+	metrics.addSlowSequence(SlowSequence{Duration: time.Millisecond})
+	assert.Len(t, metrics.slowest, slowSequenceLeaderboardSize)
+	assert.EqualValues(t, time.Duration(slowSequenceLeaderboardSize+1)*time.Millisecond, metrics.slowest[slowSequenceLeaderboardSize-1].Duration)
+}
+
+// TestSlowestSequencesAcrossWorkers tests that the slowest call sequences of all workers are merged into a single
+// leaderboard, and that the average duration only considers workers which tested a call sequence.
+func TestSlowestSequencesAcrossWorkers(t *testing.T) {
+	metrics := newFuzzerMetrics(3)
+	for workerIndex, durations := range [][]time.Duration{{5 * time.Millisecond, 30 * time.Millisecond}, {20 * time.Millisecond}} {
+		for _, duration := range durations {
+			sequenceDurations := metrics.workerMetrics[workerIndex].sequenceDurations
+			if sequenceDurations.record(duration) {
+				sequenceDurations.addSlowSequence(SlowSequence{Duration: duration, ReplayID: "worker"})
+			}
+		}
+	}
+
+	slowest := metrics.SlowestSequences()
+	assert.Len(t, slowest, 3)
+	assert.EqualValues(t, []time.Duration{30 * time.Millisecond, 20 * time.Millisecond, 5 * time.Millisecond},
+		[]time.Duration{slowest[0].Duration, slowest[1].Duration, slowest[2].Duration})
+
+	// The third worker tested no call sequences, so it should not pull the average down.
+	assert.Greater(t, metrics.AverageSequenceDuration(), 5*time.Millisecond)
+	assert.EqualValues(t, 0, newFuzzerMetrics(2).AverageSequenceDuration())
+}
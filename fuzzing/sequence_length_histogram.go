@@ -0,0 +1,116 @@
+package fuzzing
+
+import (
+	"slices"
+	"sync"
+)
+
+// SequenceLengthHistogram describes how many times new coverage and failures were discovered at each call sequence
+// length during a fuzzing campaign. This can be used to tune the call sequence length used by the fuzzer.
+type SequenceLengthHistogram struct {
+	// NewCoverage describes the count of times new coverage was discovered by a call sequence of each length, where
+	// the count at index i describes call sequences of length i+1.
+	NewCoverage []uint64 `json:"newCoverage"`
+
+	// Failures describes the count of times a failure was discovered by a call sequence of each length, where the
+	// count at index i describes call sequences of length i+1.
+	Failures []uint64 `json:"failures"`
+}
+
+// sequenceLengthPercentile returns the smallest call sequence length by which the provided fraction of the discoveries
+// described by counts were made, where counts is a histogram as described by SequenceLengthHistogram.
+// Returns zero if no discoveries were made.
+func sequenceLengthPercentile(counts []uint64, fraction float64) int {
+	// Determine the total count of discoveries.
+	var total uint64
+	for _, count := range counts {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+
+	// Find the first length at which the cumulative count reaches the requested fraction of the total.
+	var cumulative uint64
+	for i, count := range counts {
+		cumulative += count
+		if float64(cumulative) >= fraction*float64(total) {
+			return i + 1
+		}
+	}
+	return len(counts)
+}
+
+// sequenceLengthTracker tracks the call sequence lengths at which every FuzzerWorker discovered new coverage and
+// failures during a fuzzing campaign.
+type sequenceLengthTracker struct {
+	// histogram describes the counts recorded so far.
+	histogram SequenceLengthHistogram
+
+	// recentDiscoveries describes the count of new coverage and failures discovered at each call sequence length since
+	// the last time they were taken with takeRecentDiscoveries, where the count at index i describes call sequences of
+	// length i+1.
+	recentDiscoveries []uint64
+
+	// lock provides thread-synchronization, as discoveries are recorded by every FuzzerWorker.
+	lock sync.Mutex
+}
+
+// newSequenceLengthTracker creates a new, empty sequenceLengthTracker.
+func newSequenceLengthTracker() *sequenceLengthTracker {
+	return &sequenceLengthTracker{
+		histogram: SequenceLengthHistogram{
+			NewCoverage: make([]uint64, 0),
+			Failures:    make([]uint64, 0),
+		},
+	}
+}
+
+// incrementSequenceLengthCount increments the count at the provided call sequence length in the provided histogram
+// counts, growing them as needed. Returns the updated counts.
+func incrementSequenceLengthCount(counts []uint64, length int) []uint64 {
+	if length <= 0 {
+		return counts
+	}
+	if len(counts) < length {
+		counts = append(counts, make([]uint64, length-len(counts))...)
+	}
+	counts[length-1]++
+	return counts
+}
+
+// recordNewCoverage records that new coverage was discovered by a call sequence of the provided length.
+func (t *sequenceLengthTracker) recordNewCoverage(length int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.histogram.NewCoverage = incrementSequenceLengthCount(t.histogram.NewCoverage, length)
+	t.recentDiscoveries = incrementSequenceLengthCount(t.recentDiscoveries, length)
+}
+
+// recordFailure records that a failure was discovered by a call sequence of the provided length.
+func (t *sequenceLengthTracker) recordFailure(length int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.histogram.Failures = incrementSequenceLengthCount(t.histogram.Failures, length)
+	t.recentDiscoveries = incrementSequenceLengthCount(t.recentDiscoveries, length)
+}
+
+// snapshot returns a copy of the histogram recorded so far.
+func (t *sequenceLengthTracker) snapshot() SequenceLengthHistogram {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return SequenceLengthHistogram{
+		NewCoverage: slices.Clone(t.histogram.NewCoverage),
+		Failures:    slices.Clone(t.histogram.Failures),
+	}
+}
+
+// takeRecentDiscoveries returns the count of new coverage and failures discovered at each call sequence length since
+// the last call to this method, and resets them.
+func (t *sequenceLengthTracker) takeRecentDiscoveries() []uint64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	recentDiscoveries := t.recentDiscoveries
+	t.recentDiscoveries = nil
+	return recentDiscoveries
+}
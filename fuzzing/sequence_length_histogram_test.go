@@ -0,0 +1,63 @@
+package fuzzing
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSequenceLengthTracker tests that discoveries recorded from many goroutines are counted in the bucket for their
+// call sequence length, and that snapshots are not affected by later discoveries.
+func TestSequenceLengthTracker(t *testing.T) {
+	tracker := newSequenceLengthTracker()
+	assert.Empty(t, tracker.snapshot().NewCoverage)
+	assert.Empty(t, tracker.snapshot().Failures)
+
+	// Record new coverage at lengths 1 and 3 from many goroutines, and a failure at length 2.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tracker.recordNewCoverage(1)
+			tracker.recordNewCoverage(3)
+		}()
+	}
+	wg.Wait()
+	tracker.recordFailure(2)
+
+	// Invalid lengths should be ignored.
+	tracker.recordNewCoverage(0)
+
+	histogram := tracker.snapshot()
+	assert.EqualValues(t, []uint64{8, 0, 8}, histogram.NewCoverage)
+	assert.EqualValues(t, []uint64{0, 1}, histogram.Failures)
+
+	// Later discoveries should not affect an earlier snapshot.
+	tracker.recordNewCoverage(1)
+	assert.EqualValues(t, []uint64{8, 0, 8}, histogram.NewCoverage)
+	assert.EqualValues(t, []uint64{9, 0, 8}, tracker.snapshot().NewCoverage)
+
+	// The histogram should be serializable for use in results.
+	b, err := json.Marshal(histogram)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"newCoverage":[8,0,8],"failures":[0,1]}`, string(b))
+}
+
+// TestSequenceLengthPercentile tests that the smallest call sequence length by which a fraction of discoveries were
+// made is computed correctly.
+func TestSequenceLengthPercentile(t *testing.T) {
+	// No discoveries should have no percentile.
+	assert.EqualValues(t, 0, sequenceLengthPercentile(nil, 0.9))
+	assert.EqualValues(t, 0, sequenceLengthPercentile([]uint64{0, 0}, 0.9))
+
+	// 17 of 20 discoveries were made by length 3, while the 18th was made at length 17.
+	counts := make([]uint64, 20)
+	counts[0], counts[2], counts[16], counts[19] = 10, 7, 1, 2
+	assert.EqualValues(t, 1, sequenceLengthPercentile(counts, 0.5))
+	assert.EqualValues(t, 3, sequenceLengthPercentile(counts, 0.85))
+	assert.EqualValues(t, 17, sequenceLengthPercentile(counts, 0.9))
+	assert.EqualValues(t, 20, sequenceLengthPercentile(counts, 1))
+}
@@ -0,0 +1,264 @@
+package fuzzing
+
+import (
+	"sync"
+
+	"github.com/crytic/medusa/utils"
+)
+
+// shrinkScheduler limits the number of FuzzerWorker instances which shrink call sequences concurrently, so that a burst
+// of failures does not leave most workers shrinking rather than fuzzing. Shrink requests are queued on the scheduler
+// and taken by the next worker which finds a free shrinking slot, while the worker which made them continues fuzzing.
+// This is possible as a ShrinkCallSequenceRequest is self-contained, and can be shrunk by any worker.
+//
+// If failures are deduplicated, requests for a failure which is already queued, being shrunk, or known are dropped
+// when they are queued, and recorded as duplicate discoveries, so a failure which is hit repeatedly while waiting for
+// a slot is only confirmed and shrunk once.
+type shrinkScheduler struct {
+	// limit describes the maximum number of shrink requests which may be shrunk concurrently.
+	limit int
+
+	// active describes the number of shrink requests currently being shrunk.
+	active int
+
+	// queue describes the shrink requests awaiting a free shrinking slot, in the order they were queued.
+	queue []ShrinkCallSequenceRequest
+
+	// queueLimit describes the maximum number of shrink requests which may be queued. Further requests are dropped
+	// until the queue has room. As the failures they describe are not reported, they are rediscovered by later call
+	// sequences.
+	queueLimit int
+
+	// failures describes the registry used to deduplicate shrink requests by their failure identifier when they are
+	// queued, or nil if failures are not deduplicated.
+	failures *failureRegistry
+
+	// pendingFailures describes the identifiers of the failures whose shrink requests are queued or being shrunk.
+	pendingFailures map[string]struct{}
+
+	// droppedCount describes the number of shrink requests dropped as the queue was full.
+	droppedCount uint64
+
+	// lock provides thread-synchronization, as shrink requests are queued and taken by every FuzzerWorker.
+	lock sync.Mutex
+}
+
+// defaultShrinkQueueLimit describes the maximum number of shrink requests a shrinkScheduler queues.
+const defaultShrinkQueueLimit = 1024
+
+// newShrinkScheduler creates a new shrinkScheduler, which allows the provided number of shrink requests to be shrunk
+// concurrently. If a failureRegistry is provided, shrink requests are deduplicated by their failure identifier when
+// they are queued.
+func newShrinkScheduler(limit int, failures *failureRegistry) *shrinkScheduler {
+	return &shrinkScheduler{
+		limit:           limit,
+		queue:           make([]ShrinkCallSequenceRequest, 0),
+		queueLimit:      defaultShrinkQueueLimit,
+		failures:        failures,
+		pendingFailures: make(map[string]struct{}),
+	}
+}
+
+// enqueue adds the provided shrink requests to the queue of requests awaiting a free shrinking slot. Requests for
+// failures which are already pending or known are dropped if failures are deduplicated, as are requests made while
+// the queue is full.
+func (s *shrinkScheduler) enqueue(shrinkRequests ...ShrinkCallSequenceRequest) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, shrinkRequest := range shrinkRequests {
+		// If this failure is already queued, being shrunk, or known, record the discovery and drop the request.
+		deduplicate := s.failures != nil && shrinkRequest.FailureID != ""
+		if deduplicate {
+			_, pending := s.pendingFailures[shrinkRequest.FailureID]
+			if pending || s.failures.known(shrinkRequest.FailureID) {
+				s.failures.recordDuplicate(shrinkRequest.FailureID)
+				continue
+			}
+		}
+
+		// If the queue is full, drop the request.
+		if len(s.queue) >= s.queueLimit {
+			s.droppedCount++
+			continue
+		}
+		if deduplicate {
+			s.pendingFailures[shrinkRequest.FailureID] = struct{}{}
+		}
+		s.queue = append(s.queue, shrinkRequest)
+	}
+}
+
+// next takes the next queued shrink request and occupies a shrinking slot for it, which must be freed with release
+// once it was shrunk. Its failure remains pending until then. If ignoreLimit is true, a request is taken even if every shrinking slot is occupied.
+// Returns the shrink request, and a boolean indicating whether one was taken. No request is taken if the queue is
+// empty, or every shrinking slot is occupied and the limit was not ignored.
+func (s *shrinkScheduler) next(ignoreLimit bool) (ShrinkCallSequenceRequest, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.queue) == 0 || (!ignoreLimit && s.active >= s.limit) {
+		return ShrinkCallSequenceRequest{}, false
+	}
+	shrinkRequest := s.queue[0]
+	s.queue = s.queue[1:]
+	s.active++
+	return shrinkRequest, true
+}
+
+// release frees the shrinking slot occupied by the provided shrink request, which was taken with next.
+func (s *shrinkScheduler) release(shrinkRequest ShrinkCallSequenceRequest) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.active--
+	delete(s.pendingFailures, shrinkRequest.FailureID)
+}
+
+// queued returns the number of shrink requests awaiting a free shrinking slot.
+func (s *shrinkScheduler) queued() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.queue)
+}
+
+// dropped returns the number of shrink requests dropped as the queue was full.
+func (s *shrinkScheduler) dropped() uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.droppedCount
+}
+
+// runShrinkRequests handles the shrink requests made by the worker since it last ran them. Unless fuzzing
+// deterministically, they are queued on the Fuzzer's shrinkScheduler, and the worker shrinks at most one queued request
+// (made by any worker) if a shrinking slot is free, so it continues fuzzing between shrink requests. If fuzzing is
+// complete, every queued request is shrunk regardless of the limit, as there is no further fuzzing to make room for.
+// Returns an error if one occurred.
+func (fw *FuzzerWorker) runShrinkRequests(fuzzingComplete bool) error {
+	shrinkRequests := fw.shrinkCallSequenceRequests
+	fw.shrinkCallSequenceRequests = nil
+
+	// Workers fuzzing deterministically must shrink the failures they discovered themselves, so the results of
+	// shrinking do not depend on the timing of other workers.
+	scheduler := fw.fuzzer.shrinkScheduler
+	if scheduler == nil {
+		if len(shrinkRequests) > 0 {
+			fw.liveness.setPhase(workerPhaseShrinking)
+		}
+		for _, shrinkRequest := range shrinkRequests {
+			if utils.CheckContextDone(fw.fuzzer.emergencyCtx) || fw.abandoned.Load() {
+				return nil
+			}
+			fw.beginShrinkRequest(shrinkRequest, false)
+			err := fw.runShrinkRequest(shrinkRequest)
+			fw.releaseActiveShrink()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Queue our shrink requests, then shrink the next queued request if a slot is free, or every queued request if
+	// fuzzing is complete.
+	scheduler.enqueue(shrinkRequests...)
+	for !utils.CheckContextDone(fw.fuzzer.emergencyCtx) && !fw.abandoned.Load() {
+		shrinkRequest, ok := scheduler.next(fuzzingComplete)
+		if !ok {
+			break
+		}
+		fw.liveness.setPhase(workerPhaseShrinking)
+		fw.beginShrinkRequest(shrinkRequest, true)
+		err := fw.runShrinkRequest(shrinkRequest)
+		fw.releaseActiveShrink()
+		if err != nil || !fuzzingComplete {
+			return err
+		}
+	}
+	return nil
+}
+
+// runShrinkRequest confirms, deduplicates and shrinks the call sequence of the provided shrink request, reporting the
+// result. Requests made by muted test providers, unconfirmed failures (unless configured to report them) and known
+// failures (if configured to deduplicate them) are not shrunk.
+// Returns an error if one occurred.
+func (fw *FuzzerWorker) runShrinkRequest(shrinkRequest ShrinkCallSequenceRequest) error {
+	// If the test provider which made this request was muted, we do not honor it.
+	if !fw.fuzzer.testProviderEnabled(shrinkRequest.TestProviderID) {
+		return nil
+	}
+
+	// If configured, confirm the failure reproduces before shrinking it. Unconfirmed failures are discarded unless
+	// we were configured to report them regardless.
+	if fw.fuzzer.config.Fuzzing.Testing.ConfirmFailures {
+		confirmed, err := fw.confirmFailure(shrinkRequest)
+		if err != nil {
+			return err
+		}
+		if !confirmed && !fw.fuzzer.config.Fuzzing.Testing.ReportUnconfirmedFailures {
+			return nil
+		}
+	}
+
+	// Record the length of the call sequence which discovered this failure, prior to shrinking.
+	fw.fuzzer.sequenceLengths.recordFailure(len(shrinkRequest.CallSequenceToShrink))
+
+	// If this failure was already discovered by any worker, we skip shrinking it again.
+	if fw.fuzzer.config.Fuzzing.Testing.DeduplicateFailures && !fw.claimActiveShrinkFailure() {
+		return nil
+	}
+	_, err := fw.shrinkCallSequence(shrinkRequest)
+	return err
+}
+
+// activeShrinkRequest describes a shrink request being run by a FuzzerWorker, and the resources it holds.
+type activeShrinkRequest struct {
+	// shrinkRequest describes the shrink request being run.
+	shrinkRequest ShrinkCallSequenceRequest
+
+	// scheduled indicates whether the shrink request occupies a shrinking slot of the Fuzzer's shrinkScheduler.
+	scheduled bool
+
+	// claimed indicates whether the failure described by the shrink request was claimed in the failure registry.
+	claimed bool
+}
+
+// beginShrinkRequest records the provided shrink request as the one being run by the worker, indicating whether it
+// occupies a shrinking slot, so it can be released with releaseActiveShrink.
+func (fw *FuzzerWorker) beginShrinkRequest(shrinkRequest ShrinkCallSequenceRequest, scheduled bool) {
+	fw.activeShrinkLock.Lock()
+	defer fw.activeShrinkLock.Unlock()
+	fw.activeShrink = &activeShrinkRequest{
+		shrinkRequest: shrinkRequest,
+		scheduled:     scheduled,
+	}
+}
+
+// claimActiveShrinkFailure registers a discovery of the failure described by the shrink request being run by the
+// worker, claiming it to be shrunk, so it can be released if the worker is abandoned.
+// Returns true if the failure was claimed, and should be shrunk.
+func (fw *FuzzerWorker) claimActiveShrinkFailure() bool {
+	fw.activeShrinkLock.Lock()
+	defer fw.activeShrinkLock.Unlock()
+	if fw.activeShrink == nil || !fw.registerFailure(fw.activeShrink.shrinkRequest.FailureID) {
+		return false
+	}
+	fw.activeShrink.claimed = true
+	return true
+}
+
+// releaseActiveShrink releases the resources held by the shrink request being run by the worker, if any. Its
+// shrinking slot is freed, and if the worker was abandoned, the failure it claimed is released, as it was not reported
+// and must be shrunk by another worker once it is rediscovered. This is safe to call more than once.
+func (fw *FuzzerWorker) releaseActiveShrink() {
+	fw.activeShrinkLock.Lock()
+	defer fw.activeShrinkLock.Unlock()
+	activeShrink := fw.activeShrink
+	if activeShrink == nil {
+		return
+	}
+	fw.activeShrink = nil
+	if activeShrink.scheduled {
+		fw.fuzzer.shrinkScheduler.release(activeShrink.shrinkRequest)
+	}
+	if activeShrink.claimed && fw.abandoned.Load() {
+		fw.unregisterFailure(activeShrink.shrinkRequest.FailureID)
+	}
+}
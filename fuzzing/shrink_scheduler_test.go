@@ -0,0 +1,251 @@
+package fuzzing
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestShrinkSchedulerSerializesShrinking runs the fuzzer against our pre-compiled Hardhat project, simulating three
+// failures discovered at once while only one worker may shrink at a time. It ensures the failures are shrunk one at a
+// time, while the other workers continue fuzzing.
+func TestShrinkSchedulerSerializesShrinking(t *testing.T) {
+	runHardhatProjectTest(t, func(projectConfig *config.ProjectConfig) {
+		// Create our project configuration, allowing only one of our workers to shrink at a time.
+		projectConfig.Fuzzing.Timeout = 60
+		projectConfig.Fuzzing.CallSequenceLength = 10
+		projectConfig.Fuzzing.ShrinkLimit = 20
+		projectConfig.Fuzzing.MaxShrinkingWorkers = 1
+		projectConfig.Fuzzing.Testing.StopOnNoTests = false
+
+		executeFuzzerTestMethodInternal(t, projectConfig, func(f *fuzzerTestContext) {
+			// Track the failures being shrunk, the most failures shrunk at once, and the call sequences tested while
+			// any failure was being shrunk.
+			var lock sync.Mutex
+			shrinking := make(map[string]bool)
+			shrunk := make([]string, 0)
+			maxShrinkingAtOnce := 0
+			sequencesTestedWhileShrinking := 0
+
+			// Count the call sequences each worker tests while a failure is being shrunk.
+			f.fuzzer.Events.WorkerCreated.Subscribe(func(event FuzzerWorkerCreatedEvent) error {
+				event.Worker.Events.CallSequenceTested.Subscribe(func(event FuzzerWorkerCallSequenceTestedEvent) error {
+					lock.Lock()
+					defer lock.Unlock()
+					if len(shrinking) > 0 {
+						sequencesTestedWhileShrinking++
+					}
+					return nil
+				})
+				return nil
+			})
+
+			// Report three failures at once for the first sufficiently long call sequence tested. Each verification
+			// takes some time, so the failures would be shrunk at the same time if they were not serialized.
+			var failed atomic.Bool
+			f.fuzzer.Hooks.CallSequenceTestFuncs = append(f.fuzzer.Hooks.CallSequenceTestFuncs, func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+				if len(callSequence) < 5 || !failed.CompareAndSwap(false, true) {
+					return nil, nil
+				}
+				shrinkRequests := make([]ShrinkCallSequenceRequest, 0)
+				for i := 0; i < 3; i++ {
+					failureID := fmt.Sprintf("failure-%d", i)
+					callSequenceToShrink, err := callSequence.Clone()
+					if err != nil {
+						return nil, err
+					}
+					shrinkRequests = append(shrinkRequests, ShrinkCallSequenceRequest{
+						TestName:             failureID,
+						CallSequenceToShrink: callSequenceToShrink,
+						VerifierFunction: func(worker *FuzzerWorker, callSequence calls.CallSequence) (bool, error) {
+							lock.Lock()
+							shrinking[failureID] = true
+							maxShrinkingAtOnce = max(maxShrinkingAtOnce, len(shrinking))
+							lock.Unlock()
+							time.Sleep(20 * time.Millisecond)
+							return true, nil
+						},
+						FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error {
+							lock.Lock()
+							defer lock.Unlock()
+							delete(shrinking, failureID)
+							shrunk = append(shrunk, failureID)
+							if len(shrunk) == 3 {
+								worker.Fuzzer().Stop()
+							}
+							return nil
+						},
+						FailureID: failureID,
+					})
+				}
+				return shrinkRequests, nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// Every failure should have been shrunk, one at a time, while other call sequences were still tested.
+			assert.ElementsMatch(t, []string{"failure-0", "failure-1", "failure-2"}, shrunk)
+			assert.EqualValues(t, 1, maxShrinkingAtOnce)
+			assert.Positive(t, sequencesTestedWhileShrinking)
+			assert.Zero(t, f.fuzzer.shrinkScheduler.queued())
+		})
+	})
+}
+
+// TestShrinkScheduler tests that the shrink scheduler provides queued shrink requests in order, only while a shrinking
+// slot is free, unless the limit is ignored.
+func TestShrinkScheduler(t *testing.T) {
+	scheduler := newShrinkScheduler(1, nil)
+	_, ok := scheduler.next(false)
+	assert.False(t, ok)
+
+	scheduler.enqueue(ShrinkCallSequenceRequest{TestName: "a"}, ShrinkCallSequenceRequest{TestName: "b"})
+	scheduler.enqueue(ShrinkCallSequenceRequest{TestName: "c"})
+	assert.EqualValues(t, 3, scheduler.queued())
+
+	// Only one request should be provided until its slot is released.
+	shrinkRequest, ok := scheduler.next(false)
+	assert.True(t, ok)
+	assert.EqualValues(t, "a", shrinkRequest.TestName)
+	_, ok = scheduler.next(false)
+	assert.False(t, ok)
+	scheduler.release(shrinkRequest)
+	shrinkRequest, ok = scheduler.next(false)
+	assert.True(t, ok)
+	assert.EqualValues(t, "b", shrinkRequest.TestName)
+
+	// Ignoring the limit should provide the remaining request even though a slot is occupied.
+	shrinkRequest, ok = scheduler.next(true)
+	assert.True(t, ok)
+	assert.EqualValues(t, "c", shrinkRequest.TestName)
+	_, ok = scheduler.next(true)
+	assert.False(t, ok)
+	assert.Zero(t, scheduler.queued())
+}
+
+// TestShrinkSchedulerDeduplicatesFailures tests that the shrink scheduler drops requests for failures which are already
+// queued, being shrunk, or known, recording them as duplicate discoveries, and that it bounds its queue.
+func TestShrinkSchedulerDeduplicatesFailures(t *testing.T) {
+	failures := newFailureRegistry()
+	scheduler := newShrinkScheduler(1, failures)
+
+	// Requests for a queued failure should be dropped, while requests without a failure identifier are never dropped.
+	scheduler.enqueue(ShrinkCallSequenceRequest{TestName: "a", FailureID: "a"}, ShrinkCallSequenceRequest{TestName: "a", FailureID: "a"})
+	scheduler.enqueue(ShrinkCallSequenceRequest{TestName: "b"}, ShrinkCallSequenceRequest{TestName: "b"})
+	assert.EqualValues(t, 3, scheduler.queued())
+	assert.EqualValues(t, 1, failures.discoveryCount("a"))
+	assert.EqualValues(t, 1, failures.duplicates())
+
+	// Requests for a failure being shrunk should be dropped.
+	shrinkRequest, ok := scheduler.next(false)
+	assert.True(t, ok)
+	assert.EqualValues(t, "a", shrinkRequest.FailureID)
+	assert.True(t, failures.register(shrinkRequest.FailureID))
+	scheduler.enqueue(ShrinkCallSequenceRequest{TestName: "a", FailureID: "a"})
+	assert.EqualValues(t, 2, scheduler.queued())
+
+	// Requests for a known failure should be dropped once it was shrunk.
+	scheduler.release(shrinkRequest)
+	scheduler.enqueue(ShrinkCallSequenceRequest{TestName: "a", FailureID: "a"})
+	assert.EqualValues(t, 2, scheduler.queued())
+	assert.EqualValues(t, 4, failures.discoveryCount("a"))
+	assert.EqualValues(t, 3, failures.duplicates())
+
+	// Requests made while the queue is full should be dropped.
+	scheduler.queueLimit = 3
+	scheduler.enqueue(ShrinkCallSequenceRequest{TestName: "c", FailureID: "c"}, ShrinkCallSequenceRequest{TestName: "d", FailureID: "d"})
+	assert.EqualValues(t, 3, scheduler.queued())
+	assert.EqualValues(t, 1, scheduler.dropped())
+	assert.Zero(t, failures.discoveryCount("d"))
+}
+
+// TestShrinkSchedulerRepeatedFailure runs the fuzzer against our pre-compiled Hardhat project, simulating a single
+// failure discovered by every call sequence tested while only one worker may shrink at a time, and shrinking takes
+// long. It ensures the failure is only confirmed, recorded and shrunk once, while its other discoveries are counted.
+func TestShrinkSchedulerRepeatedFailure(t *testing.T) {
+	runHardhatProjectTest(t, func(projectConfig *config.ProjectConfig) {
+		// Create our project configuration, allowing only one of our workers to shrink at a time, and confirming
+		// failures before they are shrunk.
+		projectConfig.Fuzzing.Timeout = 60
+		projectConfig.Fuzzing.CallSequenceLength = 10
+		projectConfig.Fuzzing.ShrinkLimit = 20
+		projectConfig.Fuzzing.MaxShrinkingWorkers = 1
+		projectConfig.Fuzzing.Testing.ConfirmFailures = true
+		projectConfig.Fuzzing.Testing.DeduplicateFailures = true
+		projectConfig.Fuzzing.Testing.StopOnNoTests = false
+
+		executeFuzzerTestMethodInternal(t, projectConfig, func(f *fuzzerTestContext) {
+			// Track the times the failure was confirmed and shrunk, and the most shrink requests queued at once.
+			var lock sync.Mutex
+			confirmations := 0
+			shrunk := 0
+			maxQueued := 0
+
+			// Report the same failure for every sufficiently long call sequence tested, until it was shrunk.
+			var resolved atomic.Bool
+			f.fuzzer.Hooks.CallSequenceTestFuncs = append(f.fuzzer.Hooks.CallSequenceTestFuncs, func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+				lock.Lock()
+				maxQueued = max(maxQueued, f.fuzzer.shrinkScheduler.queued())
+				lock.Unlock()
+				if len(callSequence) < 5 || resolved.Load() {
+					return nil, nil
+				}
+				callSequenceToShrink, err := callSequence.Clone()
+				if err != nil {
+					return nil, err
+				}
+				var verified atomic.Bool
+				return []ShrinkCallSequenceRequest{{
+					TestName:             "failure",
+					CallSequenceToShrink: callSequenceToShrink,
+					VerifierFunction: func(worker *FuzzerWorker, callSequence calls.CallSequence) (bool, error) {
+						// The first verification of a request confirms the failure. Each verification takes some
+						// time, so the failure is hit repeatedly while its slot is held.
+						if verified.CompareAndSwap(false, true) {
+							lock.Lock()
+							confirmations++
+							lock.Unlock()
+						}
+						time.Sleep(5 * time.Millisecond)
+						return true, nil
+					},
+					FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error {
+						lock.Lock()
+						defer lock.Unlock()
+						shrunk++
+						resolved.Store(true)
+						worker.Fuzzer().Stop()
+						return nil
+					},
+					FailureID: "failure",
+				}}, nil
+			})
+
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// The failure should have been confirmed, recorded and shrunk once, while its other discoveries were
+			// counted as duplicates without ever being queued alongside it.
+			assert.EqualValues(t, 1, confirmations)
+			assert.EqualValues(t, 1, shrunk)
+			assert.LessOrEqual(t, maxQueued, 1)
+			histogram := f.fuzzer.SequenceLengthHistogram()
+			var recordedFailures uint64
+			for _, count := range histogram.Failures {
+				recordedFailures += count
+			}
+			assert.EqualValues(t, 1, recordedFailures)
+			assert.Greater(t, f.fuzzer.failures.discoveryCount("failure"), uint64(1))
+			assert.EqualValues(t, f.fuzzer.failures.discoveryCount("failure")-1, f.fuzzer.failures.duplicates())
+		})
+	})
+}
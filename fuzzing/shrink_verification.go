@@ -0,0 +1,56 @@
+package fuzzing
+
+import (
+	"slices"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/coverage"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// shrinkVerificationCache tracks the coverage of the best shrunken call sequence found for a ShrinkCallSequenceRequest
+// with a deterministic verifier, so shrunken call sequences which follow the same execution path can be accepted
+// without invoking the verifier again.
+type shrinkVerificationCache struct {
+	// bestCoverage describes the coverage hash of each call in the best shrunken call sequence found so far, or nil
+	// if no shrunken call sequence was verified yet.
+	bestCoverage []common.Hash
+}
+
+// newShrinkVerificationCache creates a shrinkVerificationCache for the provided shrink request. If the request's
+// verifier is not deterministic, nil is returned, as its results cannot be reused.
+func newShrinkVerificationCache(shrinkRequest ShrinkCallSequenceRequest) *shrinkVerificationCache {
+	if !shrinkRequest.DeterministicVerifier {
+		return nil
+	}
+	return &shrinkVerificationCache{}
+}
+
+// callCoverageHash returns the coverage hash of the provided executed call sequence element, which must be obtained
+// before its coverage is consumed by the corpus.
+// Returns the coverage hash, and a boolean indicating whether coverage was collected for the call.
+func callCoverageHash(element *calls.CallSequenceElement) (common.Hash, bool) {
+	if element.ChainReference == nil {
+		return common.Hash{}, false
+	}
+	coverageMaps := coverage.GetCoverageTracerResults(element.ChainReference.MessageResults())
+	if coverageMaps == nil {
+		return common.Hash{}, false
+	}
+	return coverageMaps.Hash(), true
+}
+
+// matchesBest indicates whether the provided coverage hashes of an executed shrunken call sequence match those of
+// the best shrunken call sequence, in which case the verifier would accept it too. A nil cache never matches.
+func (c *shrinkVerificationCache) matchesBest(sequenceCoverage []common.Hash) bool {
+	return c != nil && c.bestCoverage != nil && sequenceCoverage != nil && slices.Equal(c.bestCoverage, sequenceCoverage)
+}
+
+// setBest records the provided coverage hashes of an executed shrunken call sequence which the verifier accepted, as
+// it becomes the best shrunken call sequence. If coverage was not collected for every call, the best coverage is
+// considered unknown.
+func (c *shrinkVerificationCache) setBest(sequenceCoverage []common.Hash) {
+	if c != nil {
+		c.bestCoverage = sequenceCoverage
+	}
+}
@@ -0,0 +1,194 @@
+package fuzzing
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/config"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// shrinkTestViewCalls describes the amount of calls the verifier used in shrinking tests executes, to emulate a
+// property whose verification is expensive.
+const shrinkTestViewCalls = 5
+
+// runShrinkTest sets up a worker against our pre-compiled Hardhat project, and shrinks a call sequence of calls to
+// both of its contracts, with empty blocks mined before each call. The verifier accepts any call sequence which calls
+// SecondContract, after executing several calls to it. The provided method is called with the shrunken call sequence,
+// the amount of times the verifier was invoked, and the test failure resolved event emitted once shrinking ended.
+// If run as a benchmark, only shrinking is timed.
+func runShrinkTest(t testing.TB, deterministicVerifier bool, shrinkLimit uint64, method func(f *Fuzzer, shrunkenSequence calls.CallSequence, verifications int, resolvedEvent FuzzerTestFailureResolvedEvent)) {
+	runHardhatWorkerTest(t, func(projectConfig *config.ProjectConfig) {
+		projectConfig.Fuzzing.CorpusDirectory = "corpus"
+		projectConfig.Fuzzing.ShrinkLimit = shrinkLimit
+	}, func(worker *FuzzerWorker) {
+		fuzzer := worker.fuzzer
+
+		// Create a call sequence calling each contract in turn.
+		contractMethods := make(map[string]fuzzerTypes.DeployedContractMethod)
+		for _, contractMethod := range worker.stateChangingMethods {
+			contractMethods[contractMethod.Contract.Name()] = contractMethod
+		}
+		callSequence := make(calls.CallSequence, 0)
+		for i := 0; i < 10; i++ {
+			contractMethod := contractMethods["FirstContract"]
+			if i%3 == 2 {
+				contractMethod = contractMethods["SecondContract"]
+			}
+			msg := calls.NewCallMessageWithAbiValueData(fuzzer.senders[0], &contractMethod.Address, 0, big.NewInt(0), fuzzer.config.Fuzzing.TransactionGasLimit, nil, nil, nil, &calls.CallMessageDataAbiValues{
+				Method:      &contractMethod.Method,
+				InputValues: []any{},
+			})
+			msg.FillFromTestChainProperties(worker.chain)
+			element := calls.NewCallSequenceElement(contractMethod.Contract, msg, 1, 1)
+			element.EmptyBlocks = 3
+			callSequence = append(callSequence, element)
+		}
+
+		// Record the test failure resolved event emitted once shrinking ends.
+		var resolvedEvent FuzzerTestFailureResolvedEvent
+		fuzzer.Events.TestFailureResolved.Subscribe(func(event FuzzerTestFailureResolvedEvent) error {
+			resolvedEvent = event
+			return nil
+		})
+
+		// Shrink the call sequence with a verifier which executes several calls to check it.
+		if b, ok := t.(*testing.B); ok {
+			b.StartTimer()
+		}
+		verifications := 0
+		secondContract := contractMethods["SecondContract"]
+		shrunkenSequence, err := worker.shrinkCallSequence(ShrinkCallSequenceRequest{
+			TestName:             "calls SecondContract",
+			CallSequenceToShrink: callSequence,
+			VerifierFunction: func(worker *FuzzerWorker, callSequence calls.CallSequence) (bool, error) {
+				verifications++
+				data, err := secondContract.Contract.CompiledContract().Abi.Pack(secondContract.Method.Name)
+				if err != nil {
+					return false, err
+				}
+				for i := 0; i < shrinkTestViewCalls; i++ {
+					msg := calls.NewCallMessage(worker.Fuzzer().senders[0], &secondContract.Address, 0, big.NewInt(0), worker.fuzzer.config.Fuzzing.TransactionGasLimit, nil, nil, nil, data)
+					msg.FillFromTestChainProperties(worker.chain)
+					if _, err = worker.Chain().CallContract(msg.ToCoreMessage(), nil); err != nil {
+						return false, err
+					}
+				}
+				for _, element := range callSequence {
+					if element.Call.To != nil && *element.Call.To == secondContract.Address {
+						return true, nil
+					}
+				}
+				return false, nil
+			},
+			FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error {
+				return nil
+			},
+			FailureID:             "calls SecondContract",
+			DeterministicVerifier: deterministicVerifier,
+		})
+		if b, ok := t.(*testing.B); ok {
+			b.StopTimer()
+		}
+		assert.NoError(t, err)
+		method(fuzzer, shrunkenSequence, verifications, resolvedEvent)
+	})
+}
+
+// TestShrinkDeterministicVerifier tests that shrunken call sequences which follow the same execution path as the best
+// shrunken call sequence are accepted without invoking a deterministic verifier, and that doing so never changes the
+// resulting shrunken call sequence.
+func TestShrinkDeterministicVerifier(t *testing.T) {
+	var expectedSequence calls.CallSequence
+	var expectedVerifications int
+	runShrinkTest(t, false, 500, func(f *Fuzzer, shrunkenSequence calls.CallSequence, verifications int, resolvedEvent FuzzerTestFailureResolvedEvent) {
+		expectedSequence, expectedVerifications = shrunkenSequence, verifications
+		assert.Zero(t, f.metrics.ShrinkVerificationsSkipped().Uint64())
+	})
+
+	// Our call sequence should have been shrunk to a single call to SecondContract, without any empty blocks.
+	assert.Len(t, expectedSequence, 1)
+	assert.EqualValues(t, "SecondContract", expectedSequence[0].Contract.Name())
+	assert.Zero(t, expectedSequence[0].EmptyBlocks)
+
+	runShrinkTest(t, true, 500, func(f *Fuzzer, shrunkenSequence calls.CallSequence, verifications int, resolvedEvent FuzzerTestFailureResolvedEvent) {
+		// The shrunken call sequence should be unchanged, while fewer verifications were needed to obtain it.
+		assert.Len(t, shrunkenSequence, len(expectedSequence))
+		for i := range shrunkenSequence {
+			assert.EqualValues(t, expectedSequence[i].Contract.Name(), shrunkenSequence[i].Contract.Name())
+			assert.EqualValues(t, expectedSequence[i].Call.Data, shrunkenSequence[i].Call.Data)
+			assert.EqualValues(t, expectedSequence[i].EmptyBlocks, shrunkenSequence[i].EmptyBlocks)
+			assert.EqualValues(t, expectedSequence[i].BlockNumberDelay, shrunkenSequence[i].BlockNumberDelay)
+		}
+		skipped := f.metrics.ShrinkVerificationsSkipped().Uint64()
+		assert.Greater(t, skipped, uint64(0))
+		assert.EqualValues(t, expectedVerifications, verifications+int(skipped))
+	})
+}
+
+// TestShrinkLimitReached tests that a test failure is only reported as having reached the shrink limit if shrinking
+// was cut short by it, rather than converging on a call sequence which cannot be shrunk further.
+func TestShrinkLimitReached(t *testing.T) {
+	// With a generous limit, shrinking converges on a single call without arguments or empty blocks, below the limit.
+	runShrinkTest(t, false, 500, func(f *Fuzzer, shrunkenSequence calls.CallSequence, verifications int, resolvedEvent FuzzerTestFailureResolvedEvent) {
+		assert.Len(t, shrunkenSequence, 1)
+		assert.Less(t, verifications, 500)
+		assert.EqualValues(t, "calls SecondContract", resolvedEvent.FailureID)
+		assert.False(t, resolvedEvent.ShrinkLimitReached)
+		assert.False(t, resolvedEvent.ShrinkingInterrupted)
+	})
+
+	// With a limit too low to remove every unnecessary call, shrinking is cut short.
+	runShrinkTest(t, false, 3, func(f *Fuzzer, shrunkenSequence calls.CallSequence, verifications int, resolvedEvent FuzzerTestFailureResolvedEvent) {
+		assert.Greater(t, len(shrunkenSequence), 1)
+		assert.EqualValues(t, 3, verifications)
+		assert.True(t, resolvedEvent.ShrinkLimitReached)
+		assert.False(t, resolvedEvent.ShrinkingInterrupted)
+	})
+}
+
+// TestShrinkVerificationCache tests that a shrunken call sequence is only matched against the best one when the
+// verifier is deterministic, and the coverage of every call of both call sequences is known and equal.
+func TestShrinkVerificationCache(t *testing.T) {
+	first, second := []common.Hash{{0x01}, {0x02}}, []common.Hash{{0x01}, {0x03}}
+
+	// A nil cache, as used for non-deterministic verifiers, never matches.
+	var nilCache *shrinkVerificationCache
+	nilCache.setBest(first)
+	assert.False(t, nilCache.matchesBest(first))
+	assert.Nil(t, newShrinkVerificationCache(ShrinkCallSequenceRequest{}))
+
+	// Until a shrunken call sequence was accepted, or if its coverage is unknown, nothing matches.
+	cache := newShrinkVerificationCache(ShrinkCallSequenceRequest{DeterministicVerifier: true})
+	assert.False(t, cache.matchesBest(first))
+	assert.False(t, cache.matchesBest(nil))
+	cache.setBest(first)
+	assert.True(t, cache.matchesBest(first))
+	assert.False(t, cache.matchesBest(second))
+	assert.False(t, cache.matchesBest(first[:1]))
+	assert.False(t, cache.matchesBest(nil))
+	cache.setBest(nil)
+	assert.False(t, cache.matchesBest(first))
+}
+
+// BenchmarkShrinkVerifier measures the cost of shrinking a call sequence with a verifier which executes several calls,
+// with and without the verifier being marked deterministic.
+func BenchmarkShrinkVerifier(b *testing.B) {
+	for _, deterministicVerifier := range []bool{false, true} {
+		name := "nondeterministic"
+		if deterministicVerifier {
+			name = "deterministic"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.StopTimer()
+			for i := 0; i < b.N; i++ {
+				runShrinkTest(b, deterministicVerifier, 500, func(f *Fuzzer, shrunkenSequence calls.CallSequence, verifications int, resolvedEvent FuzzerTestFailureResolvedEvent) {
+					b.ReportMetric(float64(verifications), "verifications/op")
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package fuzzing
+
+import (
+	"fmt"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/executiontracer"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StorageDiff describes the changes a call sequence made to the storage of each contract it wrote to, in the order
+// each contract was first written to.
+type StorageDiff []*ContractStorageDiff
+
+// ContractStorageDiff describes the changes a call sequence made to the storage of a single contract.
+type ContractStorageDiff struct {
+	// Address describes the address of the contract whose storage changed.
+	Address common.Address
+
+	// Contract describes the definition of the contract deployed at the address, or nil if it is not known.
+	Contract *fuzzerTypes.Contract
+
+	// Changes describes the changes made to the contract's storage, in the order their storage slots were first
+	// written.
+	Changes []StorageChange
+}
+
+// StorageChange describes a change made to a variable, or to a storage slot which could not be attributed to a
+// variable, by a call sequence.
+type StorageChange struct {
+	// Slot describes the storage slot which changed.
+	Slot common.Hash
+
+	// Variable describes the name of the variable which changed, or an empty string if the storage slot could not be
+	// attributed to a variable.
+	Variable string
+
+	// Before describes the displayable value of the variable or storage slot before the call sequence executed.
+	Before string
+
+	// After describes the displayable value of the variable or storage slot after the call sequence executed.
+	After string
+}
+
+// recordStorageDiff replays the provided call sequence from the chain's current head, recording the changes it makes
+// to storage, then reverts the chain to the testing base block. Changed storage slots are attributed to the variables
+// stored within them, using the storage layout of the contract definition deployed at each address, if available.
+// Returns the recorded StorageDiff, or nil if storage diffs are not enabled, or an error if one occurs.
+func (fw *FuzzerWorker) recordStorageDiff(callSequence calls.CallSequence) (StorageDiff, error) {
+	if !fw.fuzzer.config.Fuzzing.Testing.StorageDiffs || len(callSequence) == 0 {
+		return nil, nil
+	}
+
+	// Replay the call sequence with our storage write tracer attached.
+	storageWriteTracer := executiontracer.NewStorageWriteTracer()
+	fetchElementFunc := func(currentIndex int) (*calls.CallSequenceElement, error) {
+		if currentIndex < len(callSequence) {
+			return callSequence[currentIndex], nil
+		}
+		return nil, nil
+	}
+	_, err := calls.ExecuteCallSequenceIteratively(fw.chain, fetchElementFunc, nil, storageWriteTracer.NativeTracer())
+	if err != nil {
+		return nil, err
+	}
+
+	// Compare the value of each written storage slot to its value before it was first written, then revert our
+	// changes.
+	state := fw.chain.State()
+	storageDiff := newStorageDiff(storageWriteTracer.Writes(), storageWriteTracer.Preimages(), state.GetState, fw.DeployedContract)
+	if err = fw.chain.RevertToBlockIndex(fw.testingBaseBlockIndex); err != nil {
+		return nil, err
+	}
+	return storageDiff, nil
+}
+
+// newStorageDiff creates a StorageDiff from the provided storage writes, obtaining the value of each written storage
+// slot after execution with the provided getState function. Storage slots whose value did not change are omitted.
+// Changed storage slots are attributed to variables using the storage layout of the contract definition obtained for
+// the written address with the provided getContract function, and the provided preimages of data hashed during
+// execution. Storage slots which cannot be attributed to a variable are reported as raw values.
+func newStorageDiff(writes []*executiontracer.StorageWrite, preimages map[common.Hash][]byte, getState func(common.Address, common.Hash) common.Hash, getContract func(common.Address) *fuzzerTypes.Contract) StorageDiff {
+	var storageDiff StorageDiff
+	contractDiffs := make(map[common.Address]*ContractStorageDiff)
+	for _, write := range writes {
+		after := getState(write.Address, write.Slot)
+		if after == write.Before {
+			continue
+		}
+
+		// Obtain the diff for the contract, creating it if this is the first change to its storage.
+		contractDiff, ok := contractDiffs[write.Address]
+		if !ok {
+			contractDiff = &ContractStorageDiff{
+				Address:  write.Address,
+				Contract: getContract(write.Address),
+			}
+			contractDiffs[write.Address] = contractDiff
+			storageDiff = append(storageDiff, contractDiff)
+		}
+
+		// Attribute the change to the variables stored within the slot whose value changed.
+		var variableChanges []StorageChange
+		if contractDiff.Contract != nil && contractDiff.Contract.CompiledContract().StorageLayout != nil {
+			for _, variable := range contractDiff.Contract.CompiledContract().StorageLayout.SlotVariables(write.Slot, preimages) {
+				beforeValue, afterValue := variable.FormatValue(write.Before), variable.FormatValue(after)
+				if beforeValue != afterValue {
+					variableChanges = append(variableChanges, StorageChange{
+						Slot:     write.Slot,
+						Variable: variable.Name,
+						Before:   beforeValue,
+						After:    afterValue,
+					})
+				}
+			}
+		}
+
+		// If the change could not be attributed to any variable, report the raw value of the slot.
+		if len(variableChanges) == 0 {
+			variableChanges = append(variableChanges, StorageChange{
+				Slot:   write.Slot,
+				Before: write.Before.Hex(),
+				After:  after.Hex(),
+			})
+		}
+		contractDiff.Changes = append(contractDiff.Changes, variableChanges...)
+	}
+	return storageDiff
+}
+
+// Log returns a logging.LogBuffer describing the StorageDiff, with a table of the values of each changed variable
+// before and after the call sequence executed, for each contract.
+func (d StorageDiff) Log() *logging.LogBuffer {
+	buffer := logging.NewLogBuffer()
+	if len(d) == 0 {
+		buffer.Append("No storage was changed by the call sequence.\n")
+		return buffer
+	}
+	for _, contractDiff := range d {
+		// Describe the contract, using its name if it is known.
+		if contractDiff.Contract != nil {
+			buffer.Append(colors.Bold, contractDiff.Contract.Name(), colors.Reset, fmt.Sprintf(" (%s):\n", contractDiff.Address.String()))
+		} else {
+			buffer.Append(colors.Bold, contractDiff.Address.String(), colors.Reset, ":\n")
+		}
+
+		// Determine the width of our variable and before value columns.
+		variableWidth, beforeWidth := len("variable"), len("before")
+		for _, change := range contractDiff.Changes {
+			variableWidth = max(variableWidth, len(change.displayVariable()))
+			beforeWidth = max(beforeWidth, len(change.Before))
+		}
+
+		// Append our header, followed by a row for each change.
+		buffer.Append(colors.Bold, fmt.Sprintf("\t%-*s  %-*s  %s", variableWidth, "variable", beforeWidth, "before", "after"), colors.Reset, "\n")
+		for _, change := range contractDiff.Changes {
+			buffer.Append(fmt.Sprintf("\t%-*s  %-*s  %s\n", variableWidth, change.displayVariable(), beforeWidth, change.Before, change.After))
+		}
+	}
+	return buffer
+}
+
+// displayVariable returns the name of the changed variable, or a description of the storage slot if the change could
+// not be attributed to a variable.
+func (c StorageChange) displayVariable() string {
+	if c.Variable != "" {
+		return c.Variable
+	}
+	return "slot " + c.Slot.Hex()
+}
@@ -0,0 +1,83 @@
+package fuzzing
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/crytic/medusa/compilation/types"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/executiontracer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewStorageDiff tests that a StorageDiff omits storage slots whose value did not change, attributes changed
+// storage slots to variables where the storage layout of the contract is known, and reports them as raw values
+// otherwise.
+func TestNewStorageDiff(t *testing.T) {
+	// Create a contract with a storage layout declaring an owner address and counter.
+	layout := &types.StorageLayout{
+		Storage: []types.StorageLayoutVariable{
+			{Label: "owner", Slot: "0", Type: "t_address"},
+			{Label: "counter", Slot: "1", Type: "t_uint256"},
+		},
+		Types: map[string]types.StorageLayoutType{
+			"t_address": {Encoding: "inplace", Label: "address", NumberOfBytes: "20"},
+			"t_uint256": {Encoding: "inplace", Label: "uint256", NumberOfBytes: "32"},
+		},
+	}
+	contract := fuzzerTypes.NewContract("Owned", "Owned.sol", &types.CompiledContract{StorageLayout: layout}, nil)
+	contractAddress := common.HexToAddress("0x1000")
+	unknownAddress := common.HexToAddress("0x2000")
+
+	// Record writes to the owner, counter, an unused slot, and a slot of a contract which is not known.
+	oldOwner, newOwner := common.HexToAddress("0x1"), common.HexToAddress("0x10000")
+	slot := func(index int64) common.Hash {
+		return common.BigToHash(big.NewInt(index))
+	}
+	writes := []*executiontracer.StorageWrite{
+		{Address: contractAddress, Slot: slot(0), Before: common.BytesToHash(oldOwner.Bytes())},
+		{Address: unknownAddress, Slot: slot(3), Before: slot(0)},
+		{Address: contractAddress, Slot: slot(1), Before: slot(0)},
+		{Address: contractAddress, Slot: slot(2), Before: slot(5)},
+	}
+	afterValues := map[common.Address]map[common.Hash]common.Hash{
+		contractAddress: {slot(0): common.BytesToHash(newOwner.Bytes()), slot(1): slot(1), slot(2): slot(5)},
+		unknownAddress:  {slot(3): slot(7)},
+	}
+	getState := func(address common.Address, slot common.Hash) common.Hash {
+		return afterValues[address][slot]
+	}
+	getContract := func(address common.Address) *fuzzerTypes.Contract {
+		if address == contractAddress {
+			return contract
+		}
+		return nil
+	}
+
+	// The unused slot should be omitted, as its value did not change.
+	storageDiff := newStorageDiff(writes, nil, getState, getContract)
+	assert.EqualValues(t, StorageDiff{
+		{
+			Address:  contractAddress,
+			Contract: contract,
+			Changes: []StorageChange{
+				{Slot: slot(0), Variable: "owner", Before: oldOwner.String(), After: newOwner.String()},
+				{Slot: slot(1), Variable: "counter", Before: "0", After: "1"},
+			},
+		},
+		{
+			Address: unknownAddress,
+			Changes: []StorageChange{
+				{Slot: slot(3), Before: slot(0).Hex(), After: slot(7).Hex()},
+			},
+		},
+	}, storageDiff)
+
+	// The rendered diff should name the contract and its changed variables, and the raw slot of the unknown contract.
+	message := storageDiff.Log().String()
+	assert.Contains(t, message, "Owned")
+	assert.Contains(t, message, "owner")
+	assert.Contains(t, message, "counter")
+	assert.Contains(t, message, "slot "+slot(3).Hex())
+}
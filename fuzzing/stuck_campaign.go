@@ -0,0 +1,118 @@
+package fuzzing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/crytic/medusa/utils"
+)
+
+// stuckCampaignReportedMethodCount describes the maximum amount of method revert metrics listed when reporting a
+// stuck campaign.
+const stuckCampaignReportedMethodCount = 5
+
+// isCampaignStuck determines whether a fuzzing campaign is stuck, given the amount of calls tested, the amount of those
+// which reverted, the amount of call sequences which did not revert that were added to the corpus, and the fraction
+// of calls which must not revert for the campaign to not be considered stuck.
+func isCampaignStuck(callsTested uint64, callsReverted uint64, successfulCorpusEntries uint64, successRateThreshold float64) bool {
+	if callsTested == 0 || successfulCorpusEntries > 0 {
+		return false
+	}
+	successRate := float64(callsTested-callsReverted) / float64(callsTested)
+	return successRate < successRateThreshold
+}
+
+// stuckCampaignWarning creates a log buffer which describes a stuck campaign given the amount of calls tested, the
+// amount of those which reverted, and the revert metrics for each method, sorted by descending count.
+func stuckCampaignWarning(callsTested uint64, callsReverted uint64, revertMetrics []RevertMetric) *logging.LogBuffer {
+	buffer := logging.NewLogBuffer()
+	successRate := 100 * float64(callsTested-callsReverted) / float64(callsTested)
+	buffer.Append(colors.RedBold, "Fuzzing campaign appears to be stuck: ", colors.Reset,
+		"only ", colors.Bold, fmt.Sprintf("%.2f%%", successRate), colors.Reset, fmt.Sprintf(" of %d calls did not revert,", callsTested),
+		" and no call sequence which did not revert was added to the corpus. Verify that the target contracts are deployed",
+		" and set up correctly (e.g. constructor arguments, setup calls, or sender balances).")
+	if isOutOfGasDominant(revertMetrics) {
+		buffer.Append(" Most failed calls ran out of gas, so consider raising the ", colors.Bold, "transactionGasLimit", colors.Reset, ".")
+	}
+	if len(revertMetrics) > 0 {
+		buffer.Append("\nMost reverting methods:")
+		for i := 0; i < len(revertMetrics) && i < stuckCampaignReportedMethodCount; i++ {
+			appendRevertMetric(buffer, revertMetrics[i])
+		}
+	}
+	return buffer
+}
+
+// isOutOfGasDominant determines whether the majority of the failed calls described by the provided revert metrics ran
+// out of gas.
+func isOutOfGasDominant(revertMetrics []RevertMetric) bool {
+	totalCount, outOfGasCount := uint64(0), uint64(0)
+	for _, revertMetric := range revertMetrics {
+		totalCount += revertMetric.Count
+		if revertMetric.Classification == RevertClassificationOutOfGas {
+			outOfGasCount += revertMetric.Count
+		}
+	}
+	return totalCount > 0 && 2*outOfGasCount > totalCount
+}
+
+// appendRevertMetric appends a displayable line describing the provided RevertMetric to the provided log buffer.
+func appendRevertMetric(buffer *logging.LogBuffer, revertMetric RevertMetric) {
+	classification := revertMetric.Classification.String()
+	if revertMetric.Classification == RevertClassificationPanic {
+		classification = fmt.Sprintf("%s (0x%x)", classification, revertMetric.PanicCode)
+	}
+	method := revertMetric.Method
+	if revertMetric.CalldataProbe != "" {
+		method = fmt.Sprintf("%s [%s calldata]", method, revertMetric.CalldataProbe)
+	}
+	buffer.Append(fmt.Sprintf("\n - %s.%s: ", revertMetric.Contract, method), colors.Bold, fmt.Sprintf("%d %s", revertMetric.Count, classification), colors.Reset)
+}
+
+// StuckCampaignAborted indicates whether the fuzzing campaign was stopped because it was considered stuck, as nearly
+// every call it tested reverted.
+func (f *Fuzzer) StuckCampaignAborted() bool {
+	return f.stuckCampaignAborted.Load()
+}
+
+// stuckCampaignLoop waits until the configured amount of calls were tested, then checks whether the fuzzing campaign
+// is stuck, reporting it, and stopping the fuzzer if configured to. It returns once the check was made, or when ctx
+// signals a stopped operation.
+func (f *Fuzzer) stuckCampaignLoop() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Wait until the configured amount of calls were tested.
+		callsTested := f.metrics.CallsTested()
+		if !callsTested.IsUint64() || callsTested.Uint64() < f.config.Fuzzing.StuckCampaignDetection.CallThreshold {
+			continue
+		}
+
+		// Determine how many calls reverted, and whether that means the campaign is stuck.
+		callsReverted := uint64(0)
+		for _, count := range f.metrics.RevertClassificationCounts() {
+			callsReverted += count
+		}
+		callsReverted = utils.Min(callsReverted, callsTested.Uint64())
+		if !isCampaignStuck(callsTested.Uint64(), callsReverted, f.successfulCorpusEntries.Load(), f.config.Fuzzing.StuckCampaignDetection.SuccessRateThreshold) {
+			return
+		}
+
+		// Report the stuck campaign, and stop it if configured to.
+		f.logger.Warn(stuckCampaignWarning(callsTested.Uint64(), callsReverted, f.metrics.RevertMetrics()).Elements()...)
+		if f.config.Fuzzing.StuckCampaignDetection.AbortCampaign {
+			f.logger.Info("Fuzzing campaign is stuck, halting now...")
+			f.stuckCampaignAborted.Store(true)
+			f.Stop()
+		}
+		return
+	}
+}
@@ -0,0 +1,73 @@
+package fuzzing
+
+import (
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIsCampaignStuck tests that campaigns are only considered stuck if the rate of calls which did not revert is below
+// the threshold, and no call sequence which did not revert was added to the corpus.
+func TestIsCampaignStuck(t *testing.T) {
+	// Every call reverting, with nothing added to the corpus, is stuck.
+	assert.True(t, isCampaignStuck(50_000, 50_000, 0, 0.01))
+	assert.True(t, isCampaignStuck(50_000, 49_600, 0, 0.01))
+
+	// Meeting the success rate threshold, or adding a successful call sequence to the corpus, is not stuck.
+	assert.False(t, isCampaignStuck(50_000, 49_500, 0, 0.01))
+	assert.False(t, isCampaignStuck(50_000, 50_000, 1, 0.01))
+	assert.False(t, isCampaignStuck(50_000, 0, 0, 0.01))
+
+	// A threshold of zero never considers a campaign stuck, and no calls tested is never stuck.
+	assert.False(t, isCampaignStuck(50_000, 50_000, 0, 0))
+	assert.False(t, isCampaignStuck(0, 0, 0, 0.01))
+}
+
+// TestStuckCampaignWarning tests that the warning reported for a stuck campaign describes the success rate, and lists
+// the most reverting methods along with the class of failure which caused them to revert.
+func TestStuckCampaignWarning(t *testing.T) {
+	revertMetrics := []RevertMetric{
+		{Contract: "TestContract", Method: "deposit(uint256)", Classification: RevertClassificationRequire, Count: 600},
+		{Contract: "TestContract", Method: "withdraw()", Classification: RevertClassificationPanic, PanicCode: 0x11, Count: 300},
+		{Contract: "TestContract", Method: "a()", Classification: RevertClassificationRevert, CalldataProbe: calls.CalldataProbeTruncated, Count: 40},
+		{Contract: "TestContract", Method: "b()", Classification: RevertClassificationRevert, Count: 30},
+		{Contract: "TestContract", Method: "c()", Classification: RevertClassificationRevert, Count: 20},
+		{Contract: "TestContract", Method: "d()", Classification: RevertClassificationRevert, Count: 10},
+	}
+	warning := stuckCampaignWarning(1_000, 1_000, revertMetrics).String()
+	assert.Contains(t, warning, "Fuzzing campaign appears to be stuck")
+	assert.Contains(t, warning, "only 0.00% of 1000 calls did not revert")
+	assert.Contains(t, warning, "TestContract.deposit(uint256): 600 require")
+	assert.Contains(t, warning, "TestContract.withdraw(): 300 panic (0x11)")
+
+	// Failures of calls with malformed call data are labeled as such.
+	assert.Contains(t, warning, "TestContract.a() [truncated calldata]: 40 revert")
+
+	// Only the most reverting methods are listed.
+	assert.Contains(t, warning, "TestContract.c()")
+	assert.NotContains(t, warning, "TestContract.d()")
+
+	// Out-of-gas failures are a minority here, so raising the gas limit is not suggested.
+	assert.NotContains(t, warning, "transactionGasLimit")
+}
+
+// TestStuckCampaignWarningOutOfGas tests that the warning reported for a stuck campaign suggests raising the
+// transaction gas limit if most failed calls ran out of gas.
+func TestStuckCampaignWarningOutOfGas(t *testing.T) {
+	revertMetrics := []RevertMetric{
+		{Contract: "TestContract", Method: "loop(uint256)", Classification: RevertClassificationOutOfGas, Count: 700},
+		{Contract: "TestContract", Method: "deposit(uint256)", Classification: RevertClassificationRequire, Count: 300},
+	}
+	warning := stuckCampaignWarning(1_000, 1_000, revertMetrics).String()
+	assert.Contains(t, warning, "Most failed calls ran out of gas, so consider raising the transactionGasLimit")
+	assert.Contains(t, warning, "TestContract.loop(uint256): 700 out of gas")
+
+	// An even split is not considered dominant.
+	assert.False(t, isOutOfGasDominant([]RevertMetric{
+		{Classification: RevertClassificationOutOfGas, Count: 500},
+		{Classification: RevertClassificationRequire, Count: 500},
+	}))
+	assert.False(t, isOutOfGasDominant(nil))
+}
@@ -0,0 +1,105 @@
+package fuzzing
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/crytic/medusa/events"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+)
+
+// subscriberErrorPolicyLogAndContinue describes the subscriber error policy under which errors returned by
+// non-critical event subscribers are logged and suppressed, rather than returned.
+const subscriberErrorPolicyLogAndContinue = "logAndContinue"
+
+// SubscribeNonCritical adds the provided EventHandler to the provided EventEmitter under the provided subscriber name,
+// as a non-critical subscriber. If the fuzzer's subscriber error policy is to log and continue, errors returned by the
+// EventHandler are logged and suppressed, rather than stopping the worker or fuzzer which published the event, and
+// are summarized when the campaign ends. Otherwise, they are returned, as they would be by any other subscriber.
+// Note: Subscribers whose failure would leave the campaign in an inconsistent state should not use this method.
+func SubscribeNonCritical[T any](fuzzer *Fuzzer, emitter *events.EventEmitter[T], name string, callback events.EventHandler[T]) {
+	emitter.SubscribeNamed(name, func(event T) error {
+		err := callback(event)
+		if err == nil || fuzzer.config.Fuzzing.SubscriberErrorPolicy != subscriberErrorPolicyLogAndContinue {
+			return err
+		}
+
+		// Identify the subscriber which failed, unless the error already does, and suppress it.
+		var subscriberErr *events.SubscriberError
+		if !errors.As(err, &subscriberErr) {
+			subscriberErr = &events.SubscriberError{Subscriber: name, EventType: emitter.EventType().String(), Err: err}
+		}
+		if fuzzer.subscriberErrors.record(subscriberErr) {
+			fuzzer.logger.Warn("Suppressed an error returned by a non-critical event subscriber, further errors it returns will be summarized when fuzzing stops: ", subscriberErr)
+		}
+		return nil
+	})
+}
+
+// suppressedSubscriberErrors summarizes the errors which were suppressed for a single non-critical event subscriber.
+type suppressedSubscriberErrors struct {
+	// subscriber describes the name of the subscriber which returned the errors.
+	subscriber string
+
+	// count describes the count of errors which were suppressed.
+	count uint64
+
+	// firstError describes the first error which was suppressed.
+	firstError string
+}
+
+// subscriberErrorTracker tracks the errors returned by non-critical event subscribers which were suppressed during a
+// fuzzing campaign, so they can be summarized when it ends.
+type subscriberErrorTracker struct {
+	// errors describes the errors which were suppressed, for each subscriber name.
+	errors map[string]*suppressedSubscriberErrors
+
+	// lock provides thread-synchronization, as errors are suppressed by every FuzzerWorker.
+	lock sync.Mutex
+}
+
+// newSubscriberErrorTracker creates a new, empty subscriberErrorTracker.
+func newSubscriberErrorTracker() *subscriberErrorTracker {
+	return &subscriberErrorTracker{
+		errors: make(map[string]*suppressedSubscriberErrors),
+	}
+}
+
+// record records the provided error, which was returned by a non-critical subscriber and suppressed.
+// Returns a boolean indicating whether it was the first error suppressed for its subscriber.
+func (t *subscriberErrorTracker) record(err *events.SubscriberError) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	suppressed, ok := t.errors[err.Subscriber]
+	if !ok {
+		suppressed = &suppressedSubscriberErrors{subscriber: err.Subscriber, firstError: err.Error()}
+		t.errors[err.Subscriber] = suppressed
+	}
+	suppressed.count++
+	return !ok
+}
+
+// summary returns the errors which were suppressed for each subscriber, sorted by subscriber name.
+func (t *subscriberErrorTracker) summary() []suppressedSubscriberErrors {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	summary := make([]suppressedSubscriberErrors, 0, len(t.errors))
+	for _, suppressed := range t.errors {
+		summary = append(summary, *suppressed)
+	}
+	slices.SortFunc(summary, func(a, b suppressedSubscriberErrors) int {
+		return strings.Compare(a.subscriber, b.subscriber)
+	})
+	return summary
+}
+
+// appendSubscriberErrorSummary appends the provided summary of suppressed subscriber errors to the provided log
+// buffer, with a line for each subscriber.
+func appendSubscriberErrorSummary(buffer *logging.LogBuffer, summary []suppressedSubscriberErrors) {
+	for _, suppressed := range summary {
+		buffer.Append("\n\t", colors.Bold, suppressed.subscriber, colors.Reset, ": ", suppressed.count, " error(s), first: ", suppressed.firstError)
+	}
+}
@@ -0,0 +1,89 @@
+package fuzzing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/crytic/medusa/events"
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// runFailingSubscriberTest runs the fuzzer against our pre-compiled Hardhat project with the provided subscriber error
+// policy, subscribing an event handler which always fails to the contract added events of each worker, either as a
+// critical or non-critical subscriber, and provides the result of starting the fuzzer to the provided method.
+func runFailingSubscriberTest(t *testing.T, subscriberErrorPolicy string, critical bool, method func(err error, f *fuzzerTestContext)) {
+	runHardhatProjectTest(t, func(projectConfig *config.ProjectConfig) {
+		// Create our project configuration
+		projectConfig.Fuzzing.TestLimit = 100
+		projectConfig.Fuzzing.SubscriberErrorPolicy = subscriberErrorPolicy
+		projectConfig.Fuzzing.Testing.StopOnNoTests = false
+
+		executeFuzzerTestMethodInternal(t, projectConfig, func(f *fuzzerTestContext) {
+			// Subscribe our failing event handler to each worker's contract added events.
+			failingHandler := func(event FuzzerWorkerContractAddedEvent) error {
+				return errors.New("contract could not be tracked")
+			}
+			f.fuzzer.Events.WorkerCreated.Subscribe(func(event FuzzerWorkerCreatedEvent) error {
+				if critical {
+					event.Worker.Events.ContractAdded.SubscribeNamed("failing subscriber", failingHandler)
+				} else {
+					SubscribeNonCritical(f.fuzzer, &event.Worker.Events.ContractAdded, "failing subscriber", failingHandler)
+				}
+				return nil
+			})
+			err := f.fuzzer.Start()
+			method(err, f)
+		})
+	})
+}
+
+// TestSubscriberErrorsFailFast tests that an error returned by a non-critical subscriber stops the fuzzer with an
+// error identifying the subscriber and the event it was handling, if the subscriber error policy is to fail fast.
+func TestSubscriberErrorsFailFast(t *testing.T) {
+	runFailingSubscriberTest(t, "failFast", false, func(err error, f *fuzzerTestContext) {
+		assert.Error(t, err)
+		assert.ErrorContains(t, err, "deployed contract added event")
+		assert.ErrorContains(t, err, "event handler \"failing subscriber\" failed to handle fuzzing.FuzzerWorkerContractAddedEvent event: contract could not be tracked")
+		assert.Empty(t, f.fuzzer.subscriberErrors.summary())
+	})
+}
+
+// TestSubscriberErrorsLogAndContinue tests that errors returned by a non-critical subscriber are suppressed and
+// summarized if the subscriber error policy is to log and continue, while those returned by a critical subscriber
+// still stop the fuzzer.
+func TestSubscriberErrorsLogAndContinue(t *testing.T) {
+	runFailingSubscriberTest(t, "logAndContinue", false, func(err error, f *fuzzerTestContext) {
+		// The fuzzer should have fuzzed until its test limit, suppressing an error for each contract added.
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, f.fuzzer.metrics.CallsTested().Uint64(), uint64(100))
+		summary := f.fuzzer.subscriberErrors.summary()
+		assert.Len(t, summary, 1)
+		assert.EqualValues(t, "failing subscriber", summary[0].subscriber)
+		assert.GreaterOrEqual(t, summary[0].count, uint64(2))
+		assert.Contains(t, summary[0].firstError, "contract could not be tracked")
+	})
+	runFailingSubscriberTest(t, "logAndContinue", true, func(err error, f *fuzzerTestContext) {
+		assert.Error(t, err)
+		assert.ErrorContains(t, err, "event handler \"failing subscriber\"")
+		assert.Empty(t, f.fuzzer.subscriberErrors.summary())
+	})
+}
+
+// TestSubscriberErrorTracker tests that suppressed subscriber errors are counted for each subscriber, retaining the
+// first error of each.
+func TestSubscriberErrorTracker(t *testing.T) {
+	tracker := newSubscriberErrorTracker()
+	assert.Empty(t, tracker.summary())
+	assert.True(t, tracker.record(&events.SubscriberError{Subscriber: "b", EventType: "event", Err: errors.New("first")}))
+	assert.False(t, tracker.record(&events.SubscriberError{Subscriber: "b", EventType: "event", Err: errors.New("second")}))
+	assert.True(t, tracker.record(&events.SubscriberError{Subscriber: "a", EventType: "event", Err: errors.New("other")}))
+
+	summary := tracker.summary()
+	assert.Len(t, summary, 2)
+	assert.EqualValues(t, "a", summary[0].subscriber)
+	assert.EqualValues(t, 1, summary[0].count)
+	assert.EqualValues(t, "b", summary[1].subscriber)
+	assert.EqualValues(t, 2, summary[1].count)
+	assert.Contains(t, summary[1].firstError, "first")
+}
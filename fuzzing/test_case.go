@@ -1,8 +1,15 @@
 package fuzzing
 
 import (
+	"fmt"
+
+	"github.com/crytic/medusa/chain"
 	"github.com/crytic/medusa/fuzzing/calls"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
 	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // TestCaseStatus defines the status of a TestCase as a string-represented enum.
@@ -18,6 +25,9 @@ const (
 	TestCaseStatusPassed TestCaseStatus = "PASSED"
 	// TestCaseStatusFailed describes a test status where testing has concluded and the test failed.
 	TestCaseStatusFailed TestCaseStatus = "FAILED"
+	// TestCaseStatusMuted describes a test status where testing has concluded without the test failing, but its test
+	// provider was muted, so the test was not fully tested.
+	TestCaseStatusMuted TestCaseStatus = "MUTED"
 )
 
 // TestCase describes a test which is being conducted by a test provider attached to the Fuzzer.
@@ -43,3 +53,27 @@ type TestCase interface {
 	// TestResult instances (even if the CallSequence differs or has not been shrunk).
 	ID() string
 }
+
+// formatTestMethod returns a displayable string describing a test method in a TestCase result, for use in log
+// messages. If the method has a NatSpec description, it is included alongside the method signature.
+func formatTestMethod(contract *fuzzerTypes.Contract, method abi.Method) string {
+	description := contract.MethodDescription(method)
+	if description == method.Sig {
+		return fmt.Sprintf("\"%s.%s\"", contract.Name(), method.Sig)
+	}
+	return fmt.Sprintf("\"%s.%s\" (%s)", contract.Name(), method.Sig, description)
+}
+
+// formatTestAddress returns a displayable string describing an address in a TestCase result, for use in log messages.
+// The address is labeled using the labels known to the chain when the last call in the provided call sequence was
+// executed, if any.
+func formatTestAddress(callSequence *calls.CallSequence, address common.Address) string {
+	var labels map[common.Address]string
+	if callSequence != nil && len(*callSequence) > 0 {
+		lastCall := (*callSequence)[len(*callSequence)-1]
+		if lastCall.ChainReference != nil {
+			labels = chain.GetLabels(lastCall.ChainReference.MessageResults())
+		}
+	}
+	return utils.AttachLabelToAddress(address, labels[address])
+}
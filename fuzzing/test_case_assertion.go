@@ -6,8 +6,10 @@ import (
 	"github.com/crytic/medusa/logging/colors"
 	"strings"
 
+	"github.com/crytic/medusa/compilation/abiutils"
 	"github.com/crytic/medusa/fuzzing/calls"
 	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/executiontracer"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 )
 
@@ -21,6 +23,14 @@ type AssertionTestCase struct {
 	targetMethod abi.Method
 	// callSequence describes the call sequence that broke the assertion
 	callSequence *calls.CallSequence
+	// innerCallPanic describes the panic in an inner call frame which broke the assertion. This is nil if the
+	// assertion was broken by the top-level call itself.
+	innerCallPanic *executiontracer.InnerCallPanic
+	// innerCallContract describes the contract whose code panicked in the inner call frame, or nil if it could not be
+	// resolved or the assertion was broken by the top-level call itself.
+	innerCallContract *fuzzerTypes.Contract
+	// storageDiff describes the changes the call sequence made to storage. This is nil if storage diffs are not enabled.
+	storageDiff StorageDiff
 }
 
 // Status describes the TestCaseStatus used to define the current state of the test.
@@ -34,6 +44,12 @@ func (t *AssertionTestCase) CallSequence() *calls.CallSequence {
 	return t.callSequence
 }
 
+// InnerCallPanic describes the panic in an inner call frame which broke the assertion, if the failure was detected
+// in an inner call frame rather than the top-level call. This is nil otherwise.
+func (t *AssertionTestCase) InnerCallPanic() *executiontracer.InnerCallPanic {
+	return t.innerCallPanic
+}
+
 // Name describes the name of the test case.
 func (t *AssertionTestCase) Name() string {
 	return fmt.Sprintf("Assertion Test: %s.%s", t.targetContract.Name(), t.targetMethod.Sig)
@@ -46,9 +62,24 @@ func (t *AssertionTestCase) LogMessage() *logging.LogBuffer {
 	buffer := logging.NewLogBuffer()
 	if t.Status() == TestCaseStatusFailed {
 		buffer.Append(colors.RedBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
-		buffer.Append(fmt.Sprintf("Test for method \"%s.%s\" resulted in an assertion failure after the following call sequence:\n", t.targetContract.Name(), t.targetMethod.Sig))
+		buffer.Append(fmt.Sprintf("Test for method %s resulted in an assertion failure after the following call sequence:\n", formatTestMethod(t.targetContract, t.targetMethod)))
+		if t.innerCallPanic != nil {
+			innerCallContractName := "<unresolved contract>"
+			if t.innerCallContract != nil {
+				innerCallContractName = t.innerCallContract.Name()
+			}
+			buffer.Append(fmt.Sprintf("The failure (%s) occurred in an inner %v to %s (%v) at call depth %d, made by %v.\n",
+				abiutils.GetPanicReason(t.innerCallPanic.PanicCode), t.innerCallPanic.CallType, innerCallContractName,
+				formatTestAddress(t.callSequence, t.innerCallPanic.CodeAddress), t.innerCallPanic.Depth, formatTestAddress(t.callSequence, t.innerCallPanic.CallerAddress)))
+		}
 		buffer.Append(colors.Bold, "[Call Sequence]", colors.Reset, "\n")
 		buffer.Append(t.CallSequence().Log().Elements()...)
+
+		// If the storage changes were recorded then add them to the message
+		if t.storageDiff != nil {
+			buffer.Append(colors.Bold, "[Storage Changes]", colors.Reset, "\n")
+			buffer.Append(t.storageDiff.Log().Elements()...)
+		}
 		return buffer
 	}
 
@@ -62,6 +93,11 @@ func (t *AssertionTestCase) Message() string {
 	return t.LogMessage().String()
 }
 
+// TargetMethod describes the contract and method tested by the test case.
+func (t *AssertionTestCase) TargetMethod() (*fuzzerTypes.Contract, abi.Method) {
+	return t.targetContract, t.targetMethod
+}
+
 // ID obtains a unique identifier for a test result.
 func (t *AssertionTestCase) ID() string {
 	return strings.Replace(fmt.Sprintf("ASSERTION-%s-%s", t.targetContract.Name(), t.targetMethod.Sig), "_", "-", -1)
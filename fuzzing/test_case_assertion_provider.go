@@ -1,6 +1,7 @@
 package fuzzing
 
 import (
+	"fmt"
 	"math/big"
 	"sync"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/crytic/medusa/fuzzing/calls"
 	"github.com/crytic/medusa/fuzzing/config"
 	"github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/executiontracer"
 
 	"golang.org/x/exp/slices"
 )
@@ -19,6 +21,9 @@ type AssertionTestCaseProvider struct {
 	// fuzzer describes the Fuzzer which this provider is attached to.
 	fuzzer *Fuzzer
 
+	// testProvider describes the registration of this provider with the Fuzzer, used to mute it while fuzzing.
+	testProvider *TestProvider
+
 	// testCases is a map of contract-method IDs to assertion test cases.GetContractMethodID
 	testCases map[contracts.ContractMethodID]*AssertionTestCase
 
@@ -30,32 +35,36 @@ type AssertionTestCaseProvider struct {
 func attachAssertionTestCaseProvider(fuzzer *Fuzzer) *AssertionTestCaseProvider {
 	// Create a test case provider
 	t := &AssertionTestCaseProvider{
-		fuzzer: fuzzer,
+		fuzzer:       fuzzer,
+		testProvider: fuzzer.RegisterTestProvider("assertion"),
 	}
 
 	// Subscribe the provider to relevant events the fuzzer emits.
-	fuzzer.Events.FuzzerStarting.Subscribe(t.onFuzzerStarting)
-	fuzzer.Events.FuzzerStopping.Subscribe(t.onFuzzerStopping)
-	fuzzer.Events.WorkerCreated.Subscribe(t.onWorkerCreated)
+	fuzzer.Events.FuzzerStarting.SubscribeNamed("assertion test provider", t.onFuzzerStarting)
+	fuzzer.Events.FuzzerStopping.SubscribeNamed("assertion test provider", t.onFuzzerStopping)
+	fuzzer.Events.WorkerCreated.SubscribeNamed("assertion test provider", t.onWorkerCreated)
 
 	// Add the provider's call sequence test function to the fuzzer.
-	fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, t.callSequencePostCallTest)
+	fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, t.testProvider.CallSequenceTestFunc(t.callSequencePostCallTest))
 	return t
 }
 
-// checkAssertionFailures checks the results of the last call for assertion failures.
-// Returns the method ID, a boolean indicating if an assertion test failed, or an error if one occurs.
-func (t *AssertionTestCaseProvider) checkAssertionFailures(callSequence calls.CallSequence) (*contracts.ContractMethodID, bool, error) {
+// checkAssertionFailures checks the results of the last call for assertion failures. If inner call panic detection is
+// enabled and the last call did not fail itself, panics which originated in its inner call frames are checked too.
+// Failures caused by panics which originated in contracts assertion testing is not scoped to are ignored.
+// Returns the method ID, a boolean indicating if an assertion test failed, the inner call panic which caused the
+// failure (nil if the failure was not caused by an inner call frame), or an error if one occurs.
+func (t *AssertionTestCaseProvider) checkAssertionFailures(worker *FuzzerWorker, callSequence calls.CallSequence) (*contracts.ContractMethodID, bool, *executiontracer.InnerCallPanic, error) {
 	// If we have an empty call sequence, we cannot have an assertion failure
 	if len(callSequence) == 0 {
-		return nil, false, nil
+		return nil, false, nil, nil
 	}
 
 	// Obtain the contract and method from the last call made in our sequence
 	lastCall := callSequence[len(callSequence)-1]
 	lastCallMethod, err := lastCall.Method()
 	if err != nil {
-		return nil, false, err
+		return nil, false, nil, err
 	}
 	methodId := contracts.GetContractMethodID(lastCall.Contract, lastCallMethod)
 
@@ -64,14 +73,109 @@ func (t *AssertionTestCaseProvider) checkAssertionFailures(callSequence calls.Ca
 	// Solidity >0.8.0 introduced asserts failing as reverts but with special return data. But we indicate we also
 	// want to be backwards compatible with older Solidity which simply hit an invalid opcode and did not actually
 	// have a panic code.
+	assertionTestingConfig := t.fuzzer.config.Fuzzing.Testing.AssertionTesting
+	lastMessageResults := lastCall.ChainReference.MessageResults()
+
+	// The inner call panics recorded for the last call are only needed for this check, so we remove them once we are
+	// done, rather than retaining them in memory for the lifetime of the chain.
+	defer executiontracer.RemoveInnerCallPanicTracerResults(lastMessageResults)
+	lastExecutionResult := lastMessageResults.ExecutionResult
+	panicCode := abiutils.GetSolidityPanicCode(lastExecutionResult.Err, lastExecutionResult.ReturnData, true)
+	if panicCode != nil && encounteredAssertionFailure(panicCode.Uint64(), assertionTestingConfig.PanicCodeConfig) &&
+		t.panicInScope(worker, executiontracer.GetPanicOriginTracerResults(lastMessageResults)) {
+		return &methodId, true, nil, nil
+	}
+
+	// If inner call panic detection is enabled, check whether any inner call frame panicked with an enabled panic
+	// code, even if the panic was handled by the contract which made the call.
+	if assertionTestingConfig.DetectInnerCallPanics {
+		for _, innerCallPanic := range executiontracer.GetInnerCallPanicTracerResults(lastMessageResults) {
+			if !encounteredAssertionFailure(innerCallPanic.PanicCode, assertionTestingConfig.PanicCodeConfig) {
+				continue
+			}
+
+			// Panics originating in excluded contracts (e.g. libraries which are expected to panic) are ignored.
+			innerCallContract := t.resolveInnerCallPanicContract(worker, innerCallPanic)
+			if innerCallContract != nil && slices.Contains(t.fuzzer.config.Fuzzing.Testing.ExcludeContracts, innerCallContract.Name()) {
+				continue
+			}
+			if !t.panicInScope(worker, innerCallPanic) {
+				continue
+			}
+			return &methodId, true, innerCallPanic, nil
+		}
+	}
+
+	return &methodId, false, nil, nil
+}
+
+// failureID obtains an identifier for the assertion failure of the provided test case caused by the last call in the
+// provided call sequence. Failures of the same test case are distinguished by the contract which panicked and the
+// panic code, so that different assertions violated through the same tested method are each shrunk and reported.
+func (t *AssertionTestCaseProvider) failureID(worker *FuzzerWorker, testCase *AssertionTestCase, callSequence calls.CallSequence, innerCallPanic *executiontracer.InnerCallPanic) string {
+	// If the failure was caused by a panic in an inner call frame, it is identified by the contract which panicked.
+	if innerCallPanic != nil {
+		contractName := innerCallPanic.CodeAddress.String()
+		if innerCallContract := t.resolveInnerCallPanicContract(worker, innerCallPanic); innerCallContract != nil {
+			contractName = innerCallContract.Name()
+		}
+		return fmt.Sprintf("%s@%s:0x%x", testCase.ID(), contractName, innerCallPanic.PanicCode)
+	}
+
+	// Otherwise, it was caused by a panic in the tested contract itself.
+	lastCall := callSequence[len(callSequence)-1]
 	lastExecutionResult := lastCall.ChainReference.MessageResults().ExecutionResult
 	panicCode := abiutils.GetSolidityPanicCode(lastExecutionResult.Err, lastExecutionResult.ReturnData, true)
-	failure := false
-	if panicCode != nil {
-		failure = encounteredAssertionFailure(panicCode.Uint64(), t.fuzzer.config.Fuzzing.Testing.AssertionTesting.PanicCodeConfig)
+	return fmt.Sprintf("%s@%s:0x%x", testCase.ID(), lastCall.Contract.Name(), panicCode)
+}
+
+// resolveInnerCallPanicContract resolves the contract definition for the code executed in the call frame which caused
+// the provided InnerCallPanic, using the contracts tracked by the provided FuzzerWorker, or the code deployed on its
+// chain if the contract is not tracked (e.g. dynamic deployments when not testing all contracts).
+// Returns the contract definition, or nil if it could not be resolved.
+func (t *AssertionTestCaseProvider) resolveInnerCallPanicContract(worker *FuzzerWorker, innerCallPanic *executiontracer.InnerCallPanic) *contracts.Contract {
+	if contractDefinition := worker.DeployedContract(innerCallPanic.CodeAddress); contractDefinition != nil {
+		return contractDefinition
 	}
+	runtimeBytecode := worker.chain.State().GetCode(innerCallPanic.CodeAddress)
+	if len(runtimeBytecode) == 0 {
+		return nil
+	}
+	matchingMode := contracts.BytecodeMatchingMode(t.fuzzer.config.Fuzzing.Testing.ContractMatchingMode)
+	return t.fuzzer.contractDefinitions.MatchBytecodeWithMode(nil, runtimeBytecode, matchingMode)
+}
 
-	return &methodId, failure, nil
+// contractScoped indicates whether assertion testing is scoped to specific contracts, through
+// AssertionTestingConfig.IncludeContracts or AssertionTestingConfig.ExcludeContracts.
+func (t *AssertionTestCaseProvider) contractScoped() bool {
+	assertionTestingConfig := t.fuzzer.config.Fuzzing.Testing.AssertionTesting
+	return len(assertionTestingConfig.IncludeContracts) > 0 || len(assertionTestingConfig.ExcludeContracts) > 0
+}
+
+// panicInScope determines whether an assertion failure caused by a panic which originated in the call frame described
+// by the provided InnerCallPanic should be reported, given the contracts assertion testing is scoped to. If the
+// contract in whose code the panic originated cannot be resolved, or the origin is nil as it is unknown,
+// AssertionTestingConfig.ReportUnresolvedContracts decides.
+func (t *AssertionTestCaseProvider) panicInScope(worker *FuzzerWorker, panicOrigin *executiontracer.InnerCallPanic) bool {
+	if !t.contractScoped() {
+		return true
+	}
+
+	// Resolve the contract the panic originated in.
+	assertionTestingConfig := t.fuzzer.config.Fuzzing.Testing.AssertionTesting
+	var originContract *contracts.Contract
+	if panicOrigin != nil {
+		originContract = t.resolveInnerCallPanicContract(worker, panicOrigin)
+	}
+	if originContract == nil {
+		return assertionTestingConfig.ReportUnresolvedContracts
+	}
+
+	// Check the contract against our included and excluded contracts.
+	if len(assertionTestingConfig.IncludeContracts) > 0 && !slices.Contains(assertionTestingConfig.IncludeContracts, originContract.Name()) {
+		return false
+	}
+	return !slices.Contains(assertionTestingConfig.ExcludeContracts, originContract.Name())
 }
 
 // onFuzzerStarting is the event handler triggered when the Fuzzer is starting a fuzzing campaign. It creates test cases
@@ -82,8 +186,14 @@ func (t *AssertionTestCaseProvider) onFuzzerStarting(event FuzzerStartingEvent)
 
 	// Create a test case for every test method.
 	for _, contract := range t.fuzzer.ContractDefinitions() {
-		// If we're not testing all contracts, verify the current contract is one we specified in our target contracts
-		if !t.fuzzer.config.Fuzzing.Testing.TestAllContracts && !slices.Contains(t.fuzzer.config.Fuzzing.TargetContracts, contract.Name()) {
+		// If we're not testing all contracts, verify the current contract is one we specified in our target or spec
+		// contracts.
+		if !t.fuzzer.config.Fuzzing.Testing.TestAllContracts && !slices.Contains(t.fuzzer.config.Fuzzing.TargetContracts, contract.Name()) && !t.fuzzer.isSpecContract(contract.Name()) {
+			continue
+		}
+
+		// Excluded contracts are never tested.
+		if slices.Contains(t.fuzzer.config.Fuzzing.Testing.ExcludeContracts, contract.Name()) {
 			continue
 		}
 
@@ -113,10 +223,15 @@ func (t *AssertionTestCaseProvider) onFuzzerStarting(event FuzzerStartingEvent)
 // have been destroyed. It clears state tracked for each FuzzerWorker and sets test cases in "running" states to
 // "passed".
 func (t *AssertionTestCaseProvider) onFuzzerStopping(event FuzzerStoppingEvent) error {
-	// Loop through each test case and set any tests with a running status to a passed status.
+	// Loop through each test case and set any tests with a running status to a passed status, or a muted status if
+	// this provider was muted.
 	for _, testCase := range t.testCases {
 		if testCase.status == TestCaseStatusRunning {
-			testCase.status = TestCaseStatusPassed
+			if t.testProvider.Enabled() {
+				testCase.status = TestCaseStatusPassed
+			} else {
+				testCase.status = TestCaseStatusMuted
+			}
 		}
 	}
 	return nil
@@ -126,7 +241,18 @@ func (t *AssertionTestCaseProvider) onFuzzerStopping(event FuzzerStoppingEvent)
 // for that worker index is refreshed and subscribes to relevant worker events.
 func (t *AssertionTestCaseProvider) onWorkerCreated(event FuzzerWorkerCreatedEvent) error {
 	// Subscribe to relevant worker events.
-	event.Worker.Events.ContractAdded.Subscribe(t.onWorkerDeployedContractAdded)
+	event.Worker.Events.ContractAdded.SubscribeNamed("assertion test provider", t.onWorkerDeployedContractAdded)
+	if t.fuzzer.config.Fuzzing.Testing.AssertionTesting.DetectInnerCallPanics || t.contractScoped() {
+		event.Worker.Events.FuzzerWorkerChainCreated.SubscribeNamed("assertion test provider", t.onWorkerChainCreated)
+	}
+	return nil
+}
+
+// onWorkerChainCreated is the event handler triggered when a FuzzerWorker has created its chain. If inner call panic
+// detection is enabled, or assertion testing is scoped to specific contracts, it attaches a tracer to the chain to
+// record panics which occur in inner call frames, and the call frames panics originate in.
+func (t *AssertionTestCaseProvider) onWorkerChainCreated(event FuzzerWorkerChainCreatedEvent) error {
+	event.Chain.AddTracer(executiontracer.NewInnerCallPanicTracer().NativeTracer(), true, false)
 	return nil
 }
 
@@ -166,11 +292,20 @@ func (t *AssertionTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorke
 	shrinkRequests := make([]ShrinkCallSequenceRequest, 0)
 
 	// Obtain the method ID for the last call and check if it encountered assertion failures.
-	methodId, testFailed, err := t.checkAssertionFailures(callSequence)
+	methodId, testFailed, innerCallPanic, err := t.checkAssertionFailures(worker, callSequence)
 	if err != nil {
 		return nil, err
 	}
 
+	// If the last call did not fail a test, explicitly check view methods to test if the call sequence has reached
+	// the configured cadence, as view methods are otherwise only rarely called.
+	if !testFailed {
+		shrinkRequests, err = t.checkViewMethods(worker, callSequence)
+		if err != nil || len(shrinkRequests) > 0 {
+			return shrinkRequests, err
+		}
+	}
+
 	// Obtain the test case for this method we're targeting for assertion testing.
 	t.testCasesLock.Lock()
 	testCase, testCaseExists := t.testCases[*methodId]
@@ -182,50 +317,142 @@ func (t *AssertionTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorke
 	}
 
 	// If the test case already failed, skip it
-	if testCase.Status() == TestCaseStatusFailed {
+	if worker.testCaseFailed(testCase) {
 		return shrinkRequests, nil
 	}
 
 	// If we failed a test, we update our state immediately. We provide a shrink verifier which will update
 	// the call sequence for each shrunken sequence provided that fails the test.
 	if testFailed {
-		// Create a request to shrink this call sequence.
-		shrinkRequest := ShrinkCallSequenceRequest{
-			TestName:             testCase.Name(),
-			CallSequenceToShrink: callSequence,
-			VerifierFunction: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence) (bool, error) {
-				// Obtain the method ID for the last call and check if it encountered assertion failures.
-				shrunkSeqMethodId, shrunkSeqTestFailed, err := t.checkAssertionFailures(shrunkenCallSequence)
+		shrinkRequest := t.newShrinkRequest(testCase, *methodId, callSequence, t.failureID(worker, testCase, callSequence, innerCallPanic))
+		shrinkRequests = append(shrinkRequests, shrinkRequest)
+	}
+
+	return shrinkRequests, nil
+}
+
+// newShrinkRequest creates a request to shrink the provided call sequence, whose last call failed the provided test
+// case. Shrunken call sequences are verified to fail the test case through the same method, identified by methodId.
+func (t *AssertionTestCaseProvider) newShrinkRequest(testCase *AssertionTestCase, methodId contracts.ContractMethodID, callSequence calls.CallSequence, failureID string) ShrinkCallSequenceRequest {
+	return ShrinkCallSequenceRequest{
+		TestName:             testCase.Name(),
+		CallSequenceToShrink: callSequence,
+		VerifierFunction: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence) (bool, error) {
+			// Obtain the method ID for the last call and check if it encountered assertion failures.
+			shrunkSeqMethodId, shrunkSeqTestFailed, _, err := t.checkAssertionFailures(worker, shrunkenCallSequence)
+			if err != nil {
+				return false, err
+			}
+
+			// If we encountered assertion failures on the same method, this shrunk sequence is satisfactory.
+			return shrunkSeqTestFailed && methodId == *shrunkSeqMethodId, nil
+		},
+		FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error {
+			// Record the changes the call sequence made to storage, if enabled.
+			storageDiff, err := worker.recordStorageDiff(shrunkenCallSequence)
+			if err != nil {
+				return err
+			}
+
+			// When we're finished shrinking, attach an execution trace to the last call. If verboseTracing is true, attach to all calls.
+			var innerCallPanic *executiontracer.InnerCallPanic
+			var innerCallContract *contracts.Contract
+			if len(shrunkenCallSequence) > 0 {
+				err = worker.executeWithExecutionTraces(shrunkenCallSequence, verboseTracing)
 				if err != nil {
-					return false, err
+					return err
 				}
 
-				// If we encountered assertion failures on the same method, this shrunk sequence is satisfactory.
-				return shrunkSeqTestFailed && *methodId == *shrunkSeqMethodId, nil
-			},
-			FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error {
-				// When we're finished shrinking, attach an execution trace to the last call. If verboseTracing is true, attach to all calls.
-				if len(shrunkenCallSequence) > 0 {
-					_, err = calls.ExecuteCallSequenceWithExecutionTracer(worker.chain, worker.fuzzer.contractDefinitions, shrunkenCallSequence, verboseTracing)
-					if err != nil {
-						return err
-					}
+				// If the failure was caused by a panic in an inner call frame, record it so it can be reported.
+				_, _, innerCallPanic, err = t.checkAssertionFailures(worker, shrunkenCallSequence)
+				if err != nil {
+					return err
 				}
+				if innerCallPanic != nil {
+					innerCallContract = t.resolveInnerCallPanicContract(worker, innerCallPanic)
+				}
+			}
 
-				// Update our test state and report it finalized.
-				testCase.status = TestCaseStatusFailed
-				testCase.callSequence = &shrunkenCallSequence
-				worker.workerMetrics().failedSequences.Add(worker.workerMetrics().failedSequences, big.NewInt(1))
-				worker.Fuzzer().ReportTestCaseFinished(testCase)
-				return nil
-			},
-			RecordResultInCorpus: true,
-		}
+			// Update our test state and report it finalized.
+			testCase.status = TestCaseStatusFailed
+			testCase.callSequence = &shrunkenCallSequence
+			testCase.innerCallPanic = innerCallPanic
+			testCase.innerCallContract = innerCallContract
+			testCase.storageDiff = storageDiff
+			worker.workerMetrics().failedSequences.Add(worker.workerMetrics().failedSequences, big.NewInt(1))
+			worker.reportTestCaseFinished(testCase)
+			return nil
+		},
+		RecordResultInCorpus: true,
+		FailureID:            failureID,
+	}
+}
 
-		// Add our shrink request to our list.
-		shrinkRequests = append(shrinkRequests, shrinkRequest)
+// checkViewMethods calls every view method to test deployed on the worker's chain, without committing the calls, if
+// the provided call sequence length is a multiple of AssertionTestingConfig.CheckViewMethodsEvery. View methods are
+// otherwise only called when randomly selected, so assertions in them which only fail in certain states are rarely
+// evaluated in those states.
+// Returns a request to shrink the provided call sequence, followed by a call to the failing view method, for each
+// view method which failed its test, or an error if one occurs.
+func (t *AssertionTestCaseProvider) checkViewMethods(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+	shrinkRequests := make([]ShrinkCallSequenceRequest, 0)
+	assertionTestingConfig := t.fuzzer.config.Fuzzing.Testing.AssertionTesting
+	if assertionTestingConfig.CheckViewMethodsEvery == 0 || len(callSequence)%assertionTestingConfig.CheckViewMethodsEvery != 0 {
+		return shrinkRequests, nil
 	}
 
+	// Generate the calls to view methods from a random stream derived from the tested call sequence and its length,
+	// so the calls generated for the rest of the call sequence do not depend on them, and it can still be
+	// reconstructed from its ReplayID.
+	restoreRandomProvider := worker.isolateRandomProvider(worker.replayID.randomSeed() + int64(len(callSequence)))
+	defer restoreRandomProvider()
+
+	for _, viewMethod := range worker.pureMethods {
+		// Obtain the test case for this method, skipping it if it is not tested or already failed.
+		methodId := contracts.GetContractMethodID(viewMethod.Contract, &viewMethod.Method)
+		t.testCasesLock.Lock()
+		testCase, testCaseExists := t.testCases[methodId]
+		t.testCasesLock.Unlock()
+		if !testCaseExists || worker.testCaseFailed(testCase) {
+			continue
+		}
+
+		// Generate arguments for the view method and create a call targeting it.
+		viewMethod := viewMethod
+		args, err := worker.sequenceGenerator.generateMethodArguments(&viewMethod.Method)
+		if err != nil {
+			return nil, err
+		}
+		senders := worker.Fuzzer().senders
+		msg := calls.NewCallMessageWithAbiValueData(senders[worker.randomProvider.Intn(len(senders))], &viewMethod.Address, 0, big.NewInt(0), t.fuzzer.config.Fuzzing.TransactionGasLimit, nil, nil, nil, &calls.CallMessageDataAbiValues{
+			Method:      &viewMethod.Method,
+			InputValues: args,
+		})
+		msg.FillFromTestChainProperties(worker.chain)
+
+		// Execute the call without committing it, and check whether it encountered an enabled panic code which
+		// originated in a contract assertion testing is scoped to.
+		panicTracer := executiontracer.NewInnerCallPanicTracer()
+		executionResult, err := worker.Chain().CallContract(msg.ToCoreMessage(), nil, panicTracer.NativeTracer())
+		if err != nil {
+			return nil, fmt.Errorf("failed to call view method to test: %v", err)
+		}
+		panicCode := abiutils.GetSolidityPanicCode(executionResult.Err, executionResult.ReturnData, true)
+		if panicCode == nil || !encounteredAssertionFailure(panicCode.Uint64(), assertionTestingConfig.PanicCodeConfig) {
+			continue
+		}
+		if !t.panicInScope(worker, panicTracer.PanicOrigin()) {
+			continue
+		}
+
+		// Append the failing call to a copy of the call sequence, so it can be shrunk and reported like any other
+		// failing call.
+		failingCallSequence := make(calls.CallSequence, len(callSequence), len(callSequence)+1)
+		copy(failingCallSequence, callSequence)
+		failingCallSequence = append(failingCallSequence, calls.NewCallSequenceElement(viewMethod.Contract, msg, 0, 0))
+		failureID := fmt.Sprintf("%s@%s:0x%x", testCase.ID(), viewMethod.Contract.Name(), panicCode)
+		shrinkRequests = append(shrinkRequests, t.newShrinkRequest(testCase, methodId, failingCallSequence, failureID))
+	}
 	return shrinkRequests, nil
 }
 
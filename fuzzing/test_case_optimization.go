@@ -27,6 +27,9 @@ type OptimizationTestCase struct {
 	shrinkCallSequenceRequest *ShrinkCallSequenceRequest
 	// value is used to store the maximum value returned by the test method
 	value *big.Int
+	// valueRank describes the rank of the maximum value among all values the test method was found to increase to,
+	// where the first value found has a rank of 1.
+	valueRank uint64
 	// optimizationTestTrace describes the execution trace when running the callSequence
 	optimizationTestTrace *executiontracer.ExecutionTrace
 }
@@ -52,8 +55,8 @@ func (t *OptimizationTestCase) Name() string {
 func (t *OptimizationTestCase) LogMessage() *logging.LogBuffer {
 	buffer := logging.NewLogBuffer()
 
-	// If the test case never started, just log the status and name of the test case
-	if t.Status() == TestCaseStatusNotStarted {
+	// If the test case never started or was muted, just log the status and name of the test case
+	if t.Status() == TestCaseStatusNotStarted || t.Status() == TestCaseStatusMuted {
 		buffer.Append(colors.GreenBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
 		return buffer
 	}
@@ -70,14 +73,14 @@ func (t *OptimizationTestCase) LogMessage() *logging.LogBuffer {
 
 	// Notify the user we failed to find anything
 	if t.status == TestCaseStatusFailed {
-		buffer.Append(fmt.Sprintf("Test for method \"%s.%s\" failed to identify a value greater than the minimum"+
-			" value of an int256: ", t.targetContract.Name(), t.targetMethod.Sig))
+		buffer.Append(fmt.Sprintf("Test for method %s failed to identify a value greater than the minimum"+
+			" value of an int256: ", formatTestMethod(t.targetContract, t.targetMethod)))
 		// We do not have a call sequence or execution trace for this test, so return early
 		return buffer
 	}
 
 	// We are guaranteed to now handle only successful test cases
-	buffer.Append(fmt.Sprintf("Test for method \"%s.%s\" resulted in the maximum value: ", t.targetContract.Name(), t.targetMethod.Sig))
+	buffer.Append(fmt.Sprintf("Test for method %s resulted in the maximum value: ", formatTestMethod(t.targetContract, t.targetMethod)))
 	buffer.Append(colors.Bold, t.value, colors.Reset, "\n")
 	buffer.Append(colors.Bold, "[Call Sequence]", colors.Reset, "\n")
 	buffer.Append(t.CallSequence().Log().Elements()...)
@@ -96,6 +99,11 @@ func (t *OptimizationTestCase) Message() string {
 	return t.LogMessage().String()
 }
 
+// TargetMethod describes the contract and method tested by the test case.
+func (t *OptimizationTestCase) TargetMethod() (*contracts.Contract, abi.Method) {
+	return t.targetContract, t.targetMethod
+}
+
 // ID obtains a unique identifier for a test result.
 func (t *OptimizationTestCase) ID() string {
 	return strings.Replace(fmt.Sprintf("OPTIMIZATION-%s-%s", t.targetContract.Name(), t.targetMethod.Sig), "_", "-", -1)
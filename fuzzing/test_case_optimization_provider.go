@@ -22,6 +22,9 @@ type OptimizationTestCaseProvider struct {
 	// fuzzer describes the Fuzzer which this provider is attached to.
 	fuzzer *Fuzzer
 
+	// testProvider describes the registration of this provider with the Fuzzer, used to mute it while fuzzing.
+	testProvider *TestProvider
+
 	// shrinkingRequested describes whether the optimization provider has already requested a worker to complete the
 	// provider's outstanding shrink requests. If the requests have already gone through, other workers can continue
 	// their operations.
@@ -61,16 +64,17 @@ func attachOptimizationTestCaseProvider(fuzzer *Fuzzer) *OptimizationTestCasePro
 
 	// Create a test case provider
 	t := &OptimizationTestCaseProvider{
-		fuzzer: fuzzer,
+		fuzzer:       fuzzer,
+		testProvider: fuzzer.RegisterTestProvider("optimization"),
 	}
 
 	// Subscribe the provider to relevant events the fuzzer emits.
-	fuzzer.Events.FuzzerStarting.Subscribe(t.onFuzzerStarting)
-	fuzzer.Events.FuzzerStopping.Subscribe(t.onFuzzerStopping)
-	fuzzer.Events.WorkerCreated.Subscribe(t.onWorkerCreated)
+	fuzzer.Events.FuzzerStarting.SubscribeNamed("optimization test provider", t.onFuzzerStarting)
+	fuzzer.Events.FuzzerStopping.SubscribeNamed("optimization test provider", t.onFuzzerStopping)
+	fuzzer.Events.WorkerCreated.SubscribeNamed("optimization test provider", t.onWorkerCreated)
 
 	// Add the provider's call sequence test function to the fuzzer.
-	fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, t.callSequencePostCallTest)
+	fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, t.testProvider.CallSequenceTestFunc(t.callSequencePostCallTest))
 	return t
 }
 
@@ -138,8 +142,14 @@ func (t *OptimizationTestCaseProvider) onFuzzerStarting(event FuzzerStartingEven
 
 	// Create a test case for every optimization test method.
 	for _, contract := range t.fuzzer.ContractDefinitions() {
-		// If we're not testing all contracts, verify the current contract is one we specified in our target contracts
-		if !t.fuzzer.config.Fuzzing.Testing.TestAllContracts && !slices.Contains(t.fuzzer.config.Fuzzing.TargetContracts, contract.Name()) {
+		// If we're not testing all contracts, verify the current contract is one we specified in our target or spec
+		// contracts.
+		if !t.fuzzer.config.Fuzzing.Testing.TestAllContracts && !slices.Contains(t.fuzzer.config.Fuzzing.TargetContracts, contract.Name()) && !t.fuzzer.isSpecContract(contract.Name()) {
+			continue
+		}
+
+		// Excluded contracts are never tested.
+		if slices.Contains(t.fuzzer.config.Fuzzing.Testing.ExcludeContracts, contract.Name()) {
 			continue
 		}
 
@@ -172,6 +182,15 @@ func (t *OptimizationTestCaseProvider) onFuzzerStarting(event FuzzerStartingEven
 func (t *OptimizationTestCaseProvider) onFuzzerStopping(event FuzzerStoppingEvent) error {
 	// Clear our optimization test methods
 	t.workerStates = nil
+
+	// If this provider was muted, set any tests with a running status to a muted status.
+	if !t.testProvider.Enabled() {
+		for _, testCase := range t.testCases {
+			if testCase.status == TestCaseStatusRunning {
+				testCase.status = TestCaseStatusMuted
+			}
+		}
+	}
 	return nil
 }
 
@@ -185,9 +204,9 @@ func (t *OptimizationTestCaseProvider) onWorkerCreated(event FuzzerWorkerCreated
 	}
 
 	// Subscribe to relevant worker events.
-	event.Worker.Events.ContractAdded.Subscribe(t.onWorkerDeployedContractAdded)
-	event.Worker.Events.ContractDeleted.Subscribe(t.onWorkerDeployedContractDeleted)
-	event.Worker.Events.TestingComplete.Subscribe(t.onWorkerTestingComplete)
+	event.Worker.Events.ContractAdded.SubscribeNamed("optimization test provider", t.onWorkerDeployedContractAdded)
+	event.Worker.Events.ContractDeleted.SubscribeNamed("optimization test provider", t.onWorkerDeployedContractDeleted)
+	event.Worker.Events.TestingComplete.SubscribeNamed("optimization test provider", t.onWorkerTestingComplete)
 	return nil
 }
 
@@ -211,6 +230,7 @@ func (t *OptimizationTestCaseProvider) onWorkerTestingComplete(event FuzzerWorke
 	for _, testCase := range t.testCases {
 		// We have a shrink request, let's send it to the fuzzer worker
 		if testCase.shrinkCallSequenceRequest != nil {
+			testCase.shrinkCallSequenceRequest.TestProviderID = t.testProvider.ID()
 			event.Worker.shrinkCallSequenceRequests = append(event.Worker.shrinkCallSequenceRequests, *testCase.shrinkCallSequenceRequest)
 			testCase.shrinkCallSequenceRequest = nil
 		}
@@ -317,6 +337,7 @@ func (t *OptimizationTestCaseProvider) callSequencePostCallTest(worker *FuzzerWo
 		if newValue.Cmp(testCase.value) == 1 {
 			// Update the test case's value and call sequence
 			testCase.value = newValue
+			testCase.valueRank++
 			testCase.callSequence = &callSequence
 
 			// Create a request to shrink this call sequence.
@@ -347,7 +368,7 @@ func (t *OptimizationTestCaseProvider) callSequencePostCallTest(worker *FuzzerWo
 				FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error {
 					// When we're finished shrinking, attach an execution trace to the last call. If verboseTracing is true, attach to all calls.
 					if len(shrunkenCallSequence) > 0 {
-						_, err = calls.ExecuteCallSequenceWithExecutionTracer(worker.chain, worker.fuzzer.contractDefinitions, shrunkenCallSequence, verboseTracing)
+						err = worker.executeWithExecutionTraces(shrunkenCallSequence, verboseTracing)
 						if err != nil {
 							return err
 						}
@@ -370,6 +391,9 @@ func (t *OptimizationTestCaseProvider) callSequencePostCallTest(worker *FuzzerWo
 					return nil
 				},
 				RecordResultInCorpus: true,
+				// Weight the recorded sequence by the rank of the value it achieved, so sequences which pushed the
+				// value further are favored as mutation targets.
+				CorpusWeightMultiplier: new(big.Int).SetUint64(testCase.valueRank),
 			}
 
 			// Update the shrink request attached to this test case
@@ -9,6 +9,7 @@ import (
 	"github.com/crytic/medusa/logging/colors"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"strings"
+	"sync"
 )
 
 // PropertyTestCase describes a test being run by a PropertyTestCaseProvider.
@@ -23,6 +24,23 @@ type PropertyTestCase struct {
 	callSequence *calls.CallSequence
 	// propertyTestTrace describes the execution trace when running the callSequence
 	propertyTestTrace *executiontracer.ExecutionTrace
+	// propertyTestOutOfGas indicates whether the property test method failed because it ran out of gas, rather than
+	// returning false or reverting
+	propertyTestOutOfGas bool
+	// propertyTestRevertReason describes the decoded reason the property test method reverted, if it failed because
+	// it reverted, rather than returning false.
+	propertyTestRevertReason string
+	// evaluationReverts describes the count of times the property test method reverted when evaluated, rather than
+	// returning a result.
+	evaluationReverts uint64
+	// evaluationRevertReason describes the decoded reason the property test method reverted the first time it
+	// reverted when evaluated.
+	evaluationRevertReason string
+	// evaluationRevertsLock is used for thread-synchronization when updating evaluationReverts and
+	// evaluationRevertReason.
+	evaluationRevertsLock sync.Mutex
+	// storageDiff describes the changes the call sequence made to storage. This is nil if storage diffs are not enabled.
+	storageDiff StorageDiff
 }
 
 // Status describes the TestCaseStatus used to define the current state of the test.
@@ -48,10 +66,21 @@ func (t *PropertyTestCase) LogMessage() *logging.LogBuffer {
 	buffer := logging.NewLogBuffer()
 	if t.Status() == TestCaseStatusFailed {
 		buffer.Append(colors.RedBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
-		buffer.Append(fmt.Sprintf("Test for method \"%s.%s\" failed after the following call sequence:\n", t.targetContract.Name(), t.targetMethod.Sig))
+		buffer.Append(fmt.Sprintf("Test for method %s failed after the following call sequence:\n", formatTestMethod(t.targetContract, t.targetMethod)))
+		if t.propertyTestOutOfGas {
+			buffer.Append(colors.Bold, "The property test ran out of gas. Consider raising the transactionGasLimit if this is unexpected.", colors.Reset, "\n")
+		} else if t.propertyTestRevertReason != "" {
+			buffer.Append(colors.Bold, "The property test reverted rather than returning a result: ", t.propertyTestRevertReason, colors.Reset, "\n")
+		}
 		buffer.Append(colors.Bold, "[Call Sequence]", colors.Reset, "\n")
 		buffer.Append(t.CallSequence().Log().Elements()...)
 
+		// If the storage changes were recorded then add them to the message
+		if t.storageDiff != nil {
+			buffer.Append(colors.Bold, "[Storage Changes]", colors.Reset, "\n")
+			buffer.Append(t.storageDiff.Log().Elements()...)
+		}
+
 		// If an execution trace is attached then add it to the message
 		if t.propertyTestTrace != nil {
 			buffer.Append(colors.Bold, "[Property Test Execution Trace]", colors.Reset, "\n")
@@ -64,12 +93,39 @@ func (t *PropertyTestCase) LogMessage() *logging.LogBuffer {
 	return buffer
 }
 
+// EvaluationReverts returns the count of times the property test method reverted when evaluated, rather than returning
+// a result, and the decoded reason it reverted the first time.
+func (t *PropertyTestCase) EvaluationReverts() (uint64, string) {
+	t.evaluationRevertsLock.Lock()
+	defer t.evaluationRevertsLock.Unlock()
+	return t.evaluationReverts, t.evaluationRevertReason
+}
+
+// recordEvaluationRevert records that the property test method reverted when evaluated, with the provided decoded
+// revert reason.
+// Returns a boolean indicating whether this was the first time it reverted.
+func (t *PropertyTestCase) recordEvaluationRevert(revertReason string) bool {
+	t.evaluationRevertsLock.Lock()
+	defer t.evaluationRevertsLock.Unlock()
+	t.evaluationReverts++
+	if t.evaluationReverts == 1 {
+		t.evaluationRevertReason = revertReason
+		return true
+	}
+	return false
+}
+
 // Message obtains a text-based printable message which describes the result of the PropertyTestCase.
 func (t *PropertyTestCase) Message() string {
 	// Internally, we just call log message and convert it to a string. This can be useful for 3rd party apps
 	return t.LogMessage().String()
 }
 
+// TargetMethod describes the contract and method tested by the test case.
+func (t *PropertyTestCase) TargetMethod() (*fuzzerTypes.Contract, abi.Method) {
+	return t.targetContract, t.targetMethod
+}
+
 // ID obtains a unique identifier for a test result.
 func (t *PropertyTestCase) ID() string {
 	return strings.Replace(fmt.Sprintf("PROPERTY-%s-%s", t.targetContract.Name(), t.targetMethod.Sig), "_", "-", -1)
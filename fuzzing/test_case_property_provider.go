@@ -8,6 +8,9 @@ import (
 	"github.com/crytic/medusa/fuzzing/calls"
 	"github.com/crytic/medusa/fuzzing/contracts"
 	"github.com/crytic/medusa/fuzzing/executiontracer"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/core"
 	"golang.org/x/exp/slices"
 )
@@ -15,12 +18,16 @@ import (
 // PropertyTestCaseProvider is a provider for on-chain property tests.
 // Property tests are represented as publicly-accessible view functions which have a name prefix specified by a
 // config.FuzzingConfig. They take no input arguments and return a boolean indicating whether the test passed.
-// If a call to any on-chain property test returns false, the test signals a failed status. If no failure is found
-// before the fuzzing campaign ends, the test signals a passed status.
+// If a call to any on-chain property test returns false, the test signals a failed status. If a call reverts, it is
+// handled as configured: as a failure, with a warning, or ignored. If no failure is found before the fuzzing campaign
+// ends, the test signals a passed status.
 type PropertyTestCaseProvider struct {
 	// fuzzer describes the Fuzzer which this provider is attached to.
 	fuzzer *Fuzzer
 
+	// testProvider describes the registration of this provider with the Fuzzer, used to mute it while fuzzing.
+	testProvider *TestProvider
+
 	// testCases is a map of contract-method IDs to property test cases.GetContractMethodID
 	testCases map[contracts.ContractMethodID]*PropertyTestCase
 
@@ -53,30 +60,52 @@ func attachPropertyTestCaseProvider(fuzzer *Fuzzer) *PropertyTestCaseProvider {
 
 	// Create a test case provider
 	t := &PropertyTestCaseProvider{
-		fuzzer: fuzzer,
+		fuzzer:       fuzzer,
+		testProvider: fuzzer.RegisterTestProvider("property"),
 	}
 
 	// Subscribe the provider to relevant events the fuzzer emits.
-	fuzzer.Events.FuzzerStarting.Subscribe(t.onFuzzerStarting)
-	fuzzer.Events.FuzzerStopping.Subscribe(t.onFuzzerStopping)
-	fuzzer.Events.WorkerCreated.Subscribe(t.onWorkerCreated)
-
-	// Add the provider's call sequence test function to the fuzzer.
-	fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, t.callSequencePostCallTest)
+	fuzzer.Events.FuzzerStarting.SubscribeNamed("property test provider", t.onFuzzerStarting)
+	fuzzer.Events.FuzzerStopping.SubscribeNamed("property test provider", t.onFuzzerStopping)
+	fuzzer.Events.WorkerCreated.SubscribeNamed("property test provider", t.onWorkerCreated)
+
+	// Add the provider's call sequence test function to the fuzzer. If property tests are only checked at the end of
+	// call sequences, it is called once each sequence completes instead of after every call. Either way, shrunken
+	// sequences are verified once they have completed.
+	if fuzzer.config.Fuzzing.Testing.PropertyTesting.CheckAtSequenceEnd {
+		fuzzer.Hooks.SequenceCompletedTestFuncs = append(fuzzer.Hooks.SequenceCompletedTestFuncs, t.testProvider.SequenceCompletedTestFunc(t.callSequencePostCallTest))
+	} else {
+		fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, t.testProvider.CallSequenceTestFunc(t.callSequencePostCallTest))
+	}
 	return t
 }
 
-// checkPropertyTestFailed executes a given property test method to see if it returns a failed status. This is used to
-// facilitate testing of property test methods after every call the Fuzzer makes when testing call sequences.
+// propertyTestResult describes the result of evaluating a property test method.
+type propertyTestResult int
+
+const (
+	// propertyTestResultPassed indicates the property test method returned true.
+	propertyTestResultPassed propertyTestResult = iota
+
+	// propertyTestResultFailed indicates the property test method returned false.
+	propertyTestResultFailed
+
+	// propertyTestResultReverted indicates the property test method reverted or ran out of gas, rather than returning
+	// a result, so whether the property holds could not be evaluated.
+	propertyTestResultReverted
+)
+
+// evaluatePropertyTest executes a given property test method to obtain its result. This is used to facilitate testing
+// of property test methods after every call the Fuzzer makes when testing call sequences.
 // A boolean indicating whether an execution trace should be captured and returned is provided to the method.
-// Returns a boolean indicating if the property test failed, an optional execution trace for the property test call,
-// or an error if one occurred.
-func (t *PropertyTestCaseProvider) checkPropertyTestFailed(worker *FuzzerWorker, propertyTestMethod *contracts.DeployedContractMethod, trace bool) (bool, *executiontracer.ExecutionTrace, error) {
+// Returns the result of the property test, the decoded revert reason if it reverted, an optional execution trace for
+// the property test call, or an error if one occurred.
+func (t *PropertyTestCaseProvider) evaluatePropertyTest(worker *FuzzerWorker, propertyTestMethod *contracts.DeployedContractMethod, trace bool) (propertyTestResult, string, *executiontracer.ExecutionTrace, error) {
 	// Generate our ABI input data for the call. In this case, property test methods take no arguments, so the
 	// variadic argument list here is empty.
 	data, err := propertyTestMethod.Contract.CompiledContract().Abi.Pack(propertyTestMethod.Method.Name)
 	if err != nil {
-		return false, nil, err
+		return propertyTestResultPassed, "", nil, err
 	}
 
 	// Create a call targeting our property test method
@@ -93,33 +122,61 @@ func (t *PropertyTestCaseProvider) checkPropertyTestFailed(worker *FuzzerWorker,
 		executionResult, err = worker.Chain().CallContract(msg.ToCoreMessage(), nil)
 	}
 	if err != nil {
-		return false, nil, fmt.Errorf("failed to call property test method: %v", err)
+		return propertyTestResultPassed, "", nil, fmt.Errorf("failed to call property test method: %v", err)
 	}
 
-	// If our property test method call failed, we flag a failed test.
+	// If our property test method call failed, it could not be evaluated. Decode the reason it reverted.
 	if executionResult.Failed() {
-		return true, executionTrace, nil
+		revertReason := "out of gas"
+		if !utils.IsOutOfGasError(executionResult.Err) {
+			contractAbis := []*abi.ABI{&propertyTestMethod.Contract.CompiledContract().Abi}
+			revertReason = executiontracer.DecodeRevertReason(executionResult.Revert(), contractAbis, worker.fuzzer.addressLabels.Labels())
+		}
+		return propertyTestResultReverted, revertReason, executionTrace, nil
 	}
 
 	// Decode our ABI outputs
 	retVals, err := propertyTestMethod.Method.Outputs.Unpack(executionResult.Return())
 	if err != nil {
-		return false, nil, fmt.Errorf("failed to decode property test method return value: %v", err)
+		return propertyTestResultPassed, "", nil, fmt.Errorf("failed to decode property test method return value: %v", err)
 	}
 
 	// We should have one return value.
 	if len(retVals) != 1 {
-		return false, nil, fmt.Errorf("detected an unexpected number of return values from property test '%s'", propertyTestMethod.Method.Name)
+		return propertyTestResultPassed, "", nil, fmt.Errorf("detected an unexpected number of return values from property test '%s'", propertyTestMethod.Method.Name)
 	}
 
 	// The one return value should be a bool
 	propertyTestMethodPassed, ok := retVals[0].(bool)
 	if !ok {
-		return false, nil, fmt.Errorf("failed to parse property test method success status from return value '%s'", propertyTestMethod.Method.Name)
+		return propertyTestResultPassed, "", nil, fmt.Errorf("failed to parse property test method success status from return value '%s'", propertyTestMethod.Method.Name)
 	}
 
 	// Return our property test results
-	return !propertyTestMethodPassed, executionTrace, nil
+	if !propertyTestMethodPassed {
+		return propertyTestResultFailed, "", executionTrace, nil
+	}
+	return propertyTestResultPassed, "", executionTrace, nil
+}
+
+// isFailure determines whether the provided property test result fails the property test. Property test methods which
+// return false always fail, while those which revert only fail if reverts are configured to be handled as failures.
+func (t *PropertyTestCaseProvider) isFailure(result propertyTestResult) bool {
+	if result == propertyTestResultReverted {
+		return t.fuzzer.config.Fuzzing.Testing.PropertyTesting.RevertHandling == "fail"
+	}
+	return result == propertyTestResultFailed
+}
+
+// recordPropertyTestReverted records that the property test method of the provided test case reverted when it was
+// evaluated, with the provided revert reason. If reverts are configured to be handled with warnings, the first revert
+// of each property test method is reported as a warning.
+func (t *PropertyTestCaseProvider) recordPropertyTestReverted(testCase *PropertyTestCase, revertReason string) {
+	firstRevert := testCase.recordEvaluationRevert(revertReason)
+	if firstRevert && t.fuzzer.config.Fuzzing.Testing.PropertyTesting.RevertHandling == "warn" {
+		t.fuzzer.logger.Warn("Property test ", colors.Bold, testCase.targetContract.Name(), ".", testCase.targetMethod.Sig, colors.Reset,
+			" reverted when evaluated, so whether it holds is unknown: ", revertReason)
+	}
 }
 
 // onFuzzerStarting is the event handler triggered when the Fuzzer is starting a fuzzing campaign. It creates test cases
@@ -131,8 +188,14 @@ func (t *PropertyTestCaseProvider) onFuzzerStarting(event FuzzerStartingEvent) e
 
 	// Create a test case for every property test method.
 	for _, contract := range t.fuzzer.ContractDefinitions() {
-		// If we're not testing all contracts, verify the current contract is one we specified in our target contracts.
-		if !t.fuzzer.config.Fuzzing.Testing.TestAllContracts && !slices.Contains(t.fuzzer.config.Fuzzing.TargetContracts, contract.Name()) {
+		// If we're not testing all contracts, verify the current contract is one we specified in our target or spec
+		// contracts.
+		if !t.fuzzer.config.Fuzzing.Testing.TestAllContracts && !slices.Contains(t.fuzzer.config.Fuzzing.TargetContracts, contract.Name()) && !t.fuzzer.isSpecContract(contract.Name()) {
+			continue
+		}
+
+		// Excluded contracts are never tested.
+		if slices.Contains(t.fuzzer.config.Fuzzing.Testing.ExcludeContracts, contract.Name()) {
 			continue
 		}
 
@@ -165,10 +228,15 @@ func (t *PropertyTestCaseProvider) onFuzzerStopping(event FuzzerStoppingEvent) e
 	// Clear our property test methods
 	t.workerStates = nil
 
-	// Loop through each test case and set any tests with a running status to a passed status.
+	// Loop through each test case and set any tests with a running status to a passed status, or a muted status if
+	// this provider was muted.
 	for _, testCase := range t.testCases {
 		if testCase.status == TestCaseStatusRunning {
-			testCase.status = TestCaseStatusPassed
+			if t.testProvider.Enabled() {
+				testCase.status = TestCaseStatusPassed
+			} else {
+				testCase.status = TestCaseStatusMuted
+			}
 		}
 	}
 	return nil
@@ -184,8 +252,8 @@ func (t *PropertyTestCaseProvider) onWorkerCreated(event FuzzerWorkerCreatedEven
 	}
 
 	// Subscribe to relevant worker events.
-	event.Worker.Events.ContractAdded.Subscribe(t.onWorkerDeployedContractAdded)
-	event.Worker.Events.ContractDeleted.Subscribe(t.onWorkerDeployedContractDeleted)
+	event.Worker.Events.ContractAdded.SubscribeNamed("property test provider", t.onWorkerDeployedContractAdded)
+	event.Worker.Events.ContractDeleted.SubscribeNamed("property test provider", t.onWorkerDeployedContractDeleted)
 	return nil
 }
 
@@ -214,7 +282,7 @@ func (t *PropertyTestCaseProvider) onWorkerDeployedContractAdded(event FuzzerWor
 			if propertyTestCase.Status() == TestCaseStatusNotStarted {
 				propertyTestCase.status = TestCaseStatusRunning
 			}
-			if propertyTestCase.Status() != TestCaseStatusFailed {
+			if !event.Worker.testCaseFailed(propertyTestCase) {
 				// Create our property test method reference.
 				workerState := &t.workerStates[event.Worker.WorkerIndex()]
 				workerState.propertyTestMethodsLock.Lock()
@@ -262,8 +330,9 @@ func (t *PropertyTestCaseProvider) onWorkerDeployedContractDeleted(event FuzzerW
 }
 
 // callSequencePostCallTest provides is a CallSequenceTestFunc that performs post-call testing logic for the attached Fuzzer
-// and any underlying FuzzerWorker. It is called after every call made in a call sequence. It checks whether property
-// test invariants are upheld after each call the Fuzzer makes when testing a call sequence.
+// and any underlying FuzzerWorker. It is called after every call made in a call sequence, or once the call sequence has
+// completed if property tests are only checked at the end of sequences, in which case it also serves as a
+// SequenceCompletedTestFunc. It checks whether property test invariants are upheld by the current chain state.
 func (t *PropertyTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
 	// Create a list of shrink call sequence verifiers, which we populate for each failed property test we want a call
 	// sequence shrunk for.
@@ -280,20 +349,23 @@ func (t *PropertyTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker
 		t.testCasesLock.Unlock()
 
 		// If the test case already failed, skip it
-		if testCase.Status() == TestCaseStatusFailed {
+		if worker.testCaseFailed(testCase) {
 			continue
 		}
 
 		// Test our property test method (create a local copy to avoid loop overwriting the method)
 		workerPropertyTestMethod := workerPropertyTestMethod
-		failedPropertyTest, _, err := t.checkPropertyTestFailed(worker, &workerPropertyTestMethod, false)
+		result, revertReason, _, err := t.evaluatePropertyTest(worker, &workerPropertyTestMethod, false)
 		if err != nil {
 			return nil, err
 		}
+		if result == propertyTestResultReverted {
+			t.recordPropertyTestReverted(testCase, revertReason)
+		}
 
 		// If we failed a test, we update our state immediately. We provide a shrink verifier which will update
 		// the call sequence for each shrunken sequence provided that fails the property test.
-		if failedPropertyTest {
+		if t.isFailure(result) {
 			// Create a request to shrink this call sequence.
 			shrinkRequest := ShrinkCallSequenceRequest{
 				TestName:             testCase.Name(),
@@ -309,24 +381,30 @@ func (t *PropertyTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker
 
 					// Then the shrink verifier simply ensures the previously failed property test fails
 					// for the shrunk sequence as well.
-					shrunkenSequenceFailedTest, _, err := t.checkPropertyTestFailed(worker, &workerPropertyTestMethod, false)
-					return shrunkenSequenceFailedTest, err
+					shrunkenSequenceResult, _, _, err := t.evaluatePropertyTest(worker, &workerPropertyTestMethod, false)
+					return t.isFailure(shrunkenSequenceResult), err
 				},
 				FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error {
+					// Record the changes the call sequence made to storage, if enabled.
+					storageDiff, err := worker.recordStorageDiff(shrunkenCallSequence)
+					if err != nil {
+						return err
+					}
+
 					// When we're finished shrinking, attach an execution trace to the last call. If verboseTracing is true, attach to all calls.
 					if len(shrunkenCallSequence) > 0 {
-						_, err = calls.ExecuteCallSequenceWithExecutionTracer(worker.chain, worker.fuzzer.contractDefinitions, shrunkenCallSequence, verboseTracing)
+						err = worker.executeWithExecutionTraces(shrunkenCallSequence, verboseTracing)
 						if err != nil {
 							return err
 						}
 					}
 
 					// Execute the property test a final time, this time obtaining an execution trace
-					shrunkenSequenceFailedTest, executionTrace, err := t.checkPropertyTestFailed(worker, &workerPropertyTestMethod, true)
+					shrunkenSequenceResult, shrunkenSequenceRevertReason, executionTrace, err := t.evaluatePropertyTest(worker, &workerPropertyTestMethod, true)
 					if err != nil {
 						return err
 					}
-					if !shrunkenSequenceFailedTest {
+					if !t.isFailure(shrunkenSequenceResult) {
 						return fmt.Errorf("property test provider did not fail property test on final shrunken sequence")
 					}
 
@@ -334,11 +412,16 @@ func (t *PropertyTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker
 					testCase.status = TestCaseStatusFailed
 					testCase.callSequence = &shrunkenCallSequence
 					testCase.propertyTestTrace = executionTrace
+					testCase.propertyTestRevertReason = shrunkenSequenceRevertReason
+					testCase.propertyTestOutOfGas = executionTrace != nil && executionTrace.TopLevelCallFrame != nil &&
+						utils.IsOutOfGasError(executionTrace.TopLevelCallFrame.ReturnError)
+					testCase.storageDiff = storageDiff
 					worker.workerMetrics().failedSequences.Add(worker.workerMetrics().failedSequences, big.NewInt(1))
-					worker.Fuzzer().ReportTestCaseFinished(testCase)
+					worker.reportTestCaseFinished(testCase)
 					return nil
 				},
 				RecordResultInCorpus: true,
+				FailureID:            testCase.ID(),
 			}
 
 			// Add our shrink request to our list.
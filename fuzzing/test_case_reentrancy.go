@@ -0,0 +1,76 @@
+package fuzzing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/executiontracer"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+)
+
+// ReentrancyTestCase describes a test being run by a ReentrancyTestCaseProvider.
+type ReentrancyTestCase struct {
+	// status describes the status of the test case
+	status TestCaseStatus
+	// targetContract describes the target contract which should not be re-entered
+	targetContract *fuzzerTypes.Contract
+	// callSequence describes the call sequence that caused the reentrancy
+	callSequence *calls.CallSequence
+	// reentrancy describes the reentrancy which caused the test to fail
+	reentrancy *executiontracer.Reentrancy
+	// reentrancyCallPath describes a displayable call frame for each element in the reentrancy's call path
+	reentrancyCallPath []string
+}
+
+// Status describes the TestCaseStatus used to define the current state of the test.
+func (t *ReentrancyTestCase) Status() TestCaseStatus {
+	return t.status
+}
+
+// CallSequence describes the types.CallSequence of calls sent to the EVM which resulted in this TestCase result.
+// This should be nil if the result is not related to the CallSequence.
+func (t *ReentrancyTestCase) CallSequence() *calls.CallSequence {
+	return t.callSequence
+}
+
+// Reentrancy describes the reentrancy which caused the test to fail. This is nil if the test has not failed.
+func (t *ReentrancyTestCase) Reentrancy() *executiontracer.Reentrancy {
+	return t.reentrancy
+}
+
+// Name describes the name of the test case.
+func (t *ReentrancyTestCase) Name() string {
+	return fmt.Sprintf("Reentrancy Test: %s", t.targetContract.Name())
+}
+
+// LogMessage obtains a buffer that represents the result of the ReentrancyTestCase. This buffer can be passed to a
+// logger for console or file logging.
+func (t *ReentrancyTestCase) LogMessage() *logging.LogBuffer {
+	// If the test failed, return a failure message.
+	buffer := logging.NewLogBuffer()
+	if t.Status() == TestCaseStatusFailed {
+		buffer.Append(colors.RedBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
+		buffer.Append(fmt.Sprintf("Test for contract \"%s\" resulted in a state-changing reentrancy after the following call sequence:\n", t.targetContract.Name()))
+		buffer.Append(fmt.Sprintf("The contract (%v) was re-entered through the call path: %s\n", formatTestAddress(t.callSequence, t.reentrancy.ContractAddress), strings.Join(t.reentrancyCallPath, " -> ")))
+		buffer.Append(colors.Bold, "[Call Sequence]", colors.Reset, "\n")
+		buffer.Append(t.CallSequence().Log().Elements()...)
+		return buffer
+	}
+
+	buffer.Append(colors.GreenBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset)
+	return buffer
+}
+
+// Message obtains a text-based printable message which describes the result of the ReentrancyTestCase.
+func (t *ReentrancyTestCase) Message() string {
+	// Internally, we just call log message and convert it to a string. This can be useful for 3rd party apps
+	return t.LogMessage().String()
+}
+
+// ID obtains a unique identifier for a test result.
+func (t *ReentrancyTestCase) ID() string {
+	return strings.Replace(fmt.Sprintf("REENTRANCY-%s", t.targetContract.Name()), "_", "-", -1)
+}
@@ -0,0 +1,261 @@
+package fuzzing
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/executiontracer"
+	"github.com/crytic/medusa/utils"
+
+	"golang.org/x/exp/slices"
+)
+
+// ReentrancyTestCaseProvider is a ReentrancyTestCase provider which spawns test cases for every contract and ensures
+// that none of them are subject to a state-changing reentrancy (a call re-entering the contract and writing to its
+// storage, while an outer call into the contract writes to its storage again after the re-entering call returned).
+type ReentrancyTestCaseProvider struct {
+	// fuzzer describes the Fuzzer which this provider is attached to.
+	fuzzer *Fuzzer
+
+	// testProvider describes the registration of this provider with the Fuzzer, used to mute it while fuzzing.
+	testProvider *TestProvider
+
+	// testCases is a map of contract names to reentrancy test cases.
+	testCases map[string]*ReentrancyTestCase
+
+	// testCasesLock is used for thread-synchronization when updating testCases
+	testCasesLock sync.Mutex
+}
+
+// attachReentrancyTestCaseProvider attaches a new ReentrancyTestCaseProvider to the Fuzzer and returns it.
+func attachReentrancyTestCaseProvider(fuzzer *Fuzzer) *ReentrancyTestCaseProvider {
+	// Create a test case provider
+	t := &ReentrancyTestCaseProvider{
+		fuzzer:       fuzzer,
+		testProvider: fuzzer.RegisterTestProvider("reentrancy"),
+	}
+
+	// Subscribe the provider to relevant events the fuzzer emits.
+	fuzzer.Events.FuzzerStarting.SubscribeNamed("reentrancy test provider", t.onFuzzerStarting)
+	fuzzer.Events.FuzzerStopping.SubscribeNamed("reentrancy test provider", t.onFuzzerStopping)
+	fuzzer.Events.WorkerCreated.SubscribeNamed("reentrancy test provider", t.onWorkerCreated)
+
+	// Add the provider's call sequence test function to the fuzzer.
+	fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, t.testProvider.CallSequenceTestFunc(t.callSequencePostCallTest))
+	return t
+}
+
+// checkReentrancy checks the results of the last call for a state-changing reentrancy into a contract being tested,
+// which is not allowed by the configuration.
+// Returns the test case for the re-entered contract and the reentrancy, or nil values if no such reentrancy occurred.
+func (t *ReentrancyTestCaseProvider) checkReentrancy(worker *FuzzerWorker, callSequence calls.CallSequence) (*ReentrancyTestCase, *executiontracer.Reentrancy) {
+	// If we have an empty call sequence, we cannot have a reentrancy
+	if len(callSequence) == 0 {
+		return nil, nil
+	}
+
+	// Check each reentrancy recorded for the last call made in our sequence.
+	lastCall := callSequence[len(callSequence)-1]
+	for _, reentrancy := range executiontracer.GetReentrancyTracerResults(lastCall.ChainReference.MessageResults()) {
+		// Verify we have a test case for the re-entered contract.
+		contract := worker.resolveContract(reentrancy.ContractAddress)
+		if contract == nil {
+			continue
+		}
+		t.testCasesLock.Lock()
+		testCase, testCaseExists := t.testCases[contract.Name()]
+		t.testCasesLock.Unlock()
+		if !testCaseExists {
+			continue
+		}
+
+		// Verify the reentrancy is not allowed through the re-entered function, or the function it re-entered.
+		if t.isAllowedReentrancy(worker, reentrancy) {
+			continue
+		}
+		return testCase, reentrancy
+	}
+	return nil, nil
+}
+
+// isAllowedReentrancy determines whether the provided reentrancy is allowed by the configured list of contracts and
+// function signatures where reentrancy is expected.
+// Returns a boolean indicating whether the reentrancy is allowed.
+func (t *ReentrancyTestCaseProvider) isAllowedReentrancy(worker *FuzzerWorker, reentrancy *executiontracer.Reentrancy) bool {
+	allowedReentrancy := t.fuzzer.config.Fuzzing.Testing.ReentrancyTesting.AllowedReentrancy
+	for _, callFrame := range []*executiontracer.ReentrancyCallFrame{reentrancy.CallPath[0], reentrancy.CallPath[len(reentrancy.CallPath)-1]} {
+		contract, method := worker.resolveContractMethod(callFrame.CodeAddress, callFrame.Selector)
+		if contract == nil {
+			continue
+		}
+		if slices.Contains(allowedReentrancy, contract.Name()) {
+			return true
+		}
+		if method != nil && slices.Contains(allowedReentrancy, contract.Name()+"."+method.Sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatCallPath obtains a displayable string for each call frame in the call path of the provided reentrancy.
+func (t *ReentrancyTestCaseProvider) formatCallPath(worker *FuzzerWorker, reentrancy *executiontracer.Reentrancy) []string {
+	callPath := make([]string, 0, len(reentrancy.CallPath))
+	for _, callFrame := range reentrancy.CallPath {
+		contract, method := worker.resolveContractMethod(callFrame.CodeAddress, callFrame.Selector)
+		description := "<unresolved contract>"
+		if contract != nil {
+			description = contract.Name()
+			if method != nil {
+				description += "." + method.Sig
+			}
+		}
+		callPath = append(callPath, fmt.Sprintf("%s (%v)", description, utils.AttachLabelToAddress(callFrame.CodeAddress, worker.chain.Labels[callFrame.CodeAddress])))
+	}
+	return callPath
+}
+
+// onFuzzerStarting is the event handler triggered when the Fuzzer is starting a fuzzing campaign. It creates test cases
+// in a "not started" state for every contract to test discovered in the contract definitions known to the Fuzzer.
+func (t *ReentrancyTestCaseProvider) onFuzzerStarting(event FuzzerStartingEvent) error {
+	// Reset our state
+	t.testCases = make(map[string]*ReentrancyTestCase)
+
+	// Create a test case for every contract.
+	for _, contract := range t.fuzzer.ContractDefinitions() {
+		// If we're not testing all contracts, verify the current contract is one we specified in our target or spec
+		// contracts.
+		if !t.fuzzer.config.Fuzzing.Testing.TestAllContracts && !slices.Contains(t.fuzzer.config.Fuzzing.TargetContracts, contract.Name()) && !t.fuzzer.isSpecContract(contract.Name()) {
+			continue
+		}
+
+		// Excluded contracts are never tested, and contracts where any reentrancy is allowed need not be.
+		if slices.Contains(t.fuzzer.config.Fuzzing.Testing.ExcludeContracts, contract.Name()) || slices.Contains(t.fuzzer.config.Fuzzing.Testing.ReentrancyTesting.AllowedReentrancy, contract.Name()) {
+			continue
+		}
+
+		// Create our test case
+		testCase := &ReentrancyTestCase{
+			status:         TestCaseStatusNotStarted,
+			targetContract: contract,
+			callSequence:   nil,
+		}
+
+		// Add to our test cases and register them with the fuzzer
+		t.testCases[contract.Name()] = testCase
+		t.fuzzer.RegisterTestCase(testCase)
+	}
+	return nil
+}
+
+// onFuzzerStopping is the event handler triggered when the Fuzzer is stopping the fuzzing campaign and all workers
+// have been destroyed. It sets test cases in "running" states to "passed".
+func (t *ReentrancyTestCaseProvider) onFuzzerStopping(event FuzzerStoppingEvent) error {
+	// Loop through each test case and set any tests with a running status to a passed status, or a muted status if
+	// this provider was muted.
+	for _, testCase := range t.testCases {
+		if testCase.status == TestCaseStatusRunning {
+			if t.testProvider.Enabled() {
+				testCase.status = TestCaseStatusPassed
+			} else {
+				testCase.status = TestCaseStatusMuted
+			}
+		}
+	}
+	return nil
+}
+
+// onWorkerCreated is the event handler triggered when a FuzzerWorker is created by the Fuzzer. It subscribes to
+// relevant worker events.
+func (t *ReentrancyTestCaseProvider) onWorkerCreated(event FuzzerWorkerCreatedEvent) error {
+	// Subscribe to relevant worker events.
+	SubscribeNonCritical(t.fuzzer, &event.Worker.Events.ContractAdded, "reentrancy test provider", t.onWorkerDeployedContractAdded)
+	SubscribeNonCritical(t.fuzzer, &event.Worker.Events.FuzzerWorkerChainCreated, "reentrancy test provider", t.onWorkerChainCreated)
+	return nil
+}
+
+// onWorkerChainCreated is the event handler triggered when a FuzzerWorker has created its chain. It attaches a tracer
+// to the chain to record state-changing reentrancy.
+func (t *ReentrancyTestCaseProvider) onWorkerChainCreated(event FuzzerWorkerChainCreatedEvent) error {
+	event.Chain.AddTracer(executiontracer.NewReentrancyTracer().NativeTracer(), true, false)
+	return nil
+}
+
+// onWorkerDeployedContractAdded is the event handler triggered when a FuzzerWorker detects a new contract deployment
+// on its underlying chain. Any test case previously made for the deployed contract which is in a "not started" state
+// is put into a "running" state, as it is now potentially reachable for testing.
+func (t *ReentrancyTestCaseProvider) onWorkerDeployedContractAdded(event FuzzerWorkerContractAddedEvent) error {
+	// If we don't have a contract definition, we can't run tests against the contract.
+	if event.ContractDefinition == nil {
+		return nil
+	}
+
+	// If we have a test case for this contract in a not-started state, we can signal a running state now.
+	t.testCasesLock.Lock()
+	testCase, testCaseExists := t.testCases[event.ContractDefinition.Name()]
+	t.testCasesLock.Unlock()
+	if testCaseExists && testCase.Status() == TestCaseStatusNotStarted {
+		testCase.status = TestCaseStatusRunning
+	}
+	return nil
+}
+
+// callSequencePostCallTest provides is a CallSequenceTestFunc that performs post-call testing logic for the attached
+// Fuzzer and any underlying FuzzerWorker. It is called after every call made in a call sequence. It checks whether the
+// call caused a state-changing reentrancy into a contract being tested.
+func (t *ReentrancyTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+	// Create a list of shrink call sequence verifiers, which we populate for each failed test we want a call sequence
+	// shrunk for.
+	shrinkRequests := make([]ShrinkCallSequenceRequest, 0)
+
+	// Check if the last call caused a reentrancy into a contract being tested, which has not yet failed.
+	testCase, reentrancy := t.checkReentrancy(worker, callSequence)
+	if reentrancy == nil || worker.testCaseFailed(testCase) {
+		return shrinkRequests, nil
+	}
+
+	// If we failed a test, we update our state immediately. We provide a shrink verifier which will update
+	// the call sequence for each shrunken sequence provided that fails the test.
+	shrinkRequest := ShrinkCallSequenceRequest{
+		TestName:             testCase.Name(),
+		CallSequenceToShrink: callSequence,
+		VerifierFunction: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence) (bool, error) {
+			// If the last call re-entered the same contract, this shrunk sequence is satisfactory.
+			shrunkSeqTestCase, _ := t.checkReentrancy(worker, shrunkenCallSequence)
+			return shrunkSeqTestCase == testCase, nil
+		},
+		FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error {
+			// When we're finished shrinking, attach an execution trace to the last call. If verboseTracing is true,
+			// attach to all calls.
+			if len(shrunkenCallSequence) > 0 {
+				err := worker.executeWithExecutionTraces(shrunkenCallSequence, verboseTracing)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Obtain the reentrancy caused by the shrunken sequence, so its call path can be reported.
+			shrunkSeqTestCase, shrunkSeqReentrancy := t.checkReentrancy(worker, shrunkenCallSequence)
+			if shrunkSeqTestCase != testCase {
+				return fmt.Errorf("reentrancy test provider did not detect reentrancy on final shrunken sequence")
+			}
+
+			// Update our test state and report it finalized.
+			testCase.status = TestCaseStatusFailed
+			testCase.callSequence = &shrunkenCallSequence
+			testCase.reentrancy = shrunkSeqReentrancy
+			testCase.reentrancyCallPath = t.formatCallPath(worker, shrunkSeqReentrancy)
+			worker.workerMetrics().failedSequences.Add(worker.workerMetrics().failedSequences, big.NewInt(1))
+			worker.reportTestCaseFinished(testCase)
+			return nil
+		},
+		RecordResultInCorpus: true,
+		FailureID:            testCase.ID(),
+	}
+
+	// Add our shrink request to our list.
+	shrinkRequests = append(shrinkRequests, shrinkRequest)
+	return shrinkRequests, nil
+}
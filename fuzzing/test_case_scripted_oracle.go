@@ -0,0 +1,76 @@
+package fuzzing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+)
+
+// ScriptedOracleTestCase describes a test being run by a ScriptedOracleTestCaseProvider, which checks calls against a
+// single scripted oracle.
+type ScriptedOracleTestCase struct {
+	// status describes the status of the test case
+	status TestCaseStatus
+	// oracle describes the scripted oracle which checks calls
+	oracle *scriptedOracle
+	// callSequence describes the call sequence whose last call failed the scripted oracle
+	callSequence *calls.CallSequence
+	// failureMessage describes the message the scripted oracle provided when the call failed it
+	failureMessage string
+}
+
+// Status describes the TestCaseStatus used to define the current state of the test.
+func (t *ScriptedOracleTestCase) Status() TestCaseStatus {
+	return t.status
+}
+
+// CallSequence describes the types.CallSequence of calls sent to the EVM which resulted in this TestCase result.
+// This should be nil if the result is not related to the CallSequence.
+func (t *ScriptedOracleTestCase) CallSequence() *calls.CallSequence {
+	return t.callSequence
+}
+
+// FailureMessage describes the message the scripted oracle provided when a call failed it. This is empty if the test
+// has not failed, or the scripted oracle provided no message.
+func (t *ScriptedOracleTestCase) FailureMessage() string {
+	return t.failureMessage
+}
+
+// Name describes the name of the test case.
+func (t *ScriptedOracleTestCase) Name() string {
+	return fmt.Sprintf("Scripted Oracle Test: %s", t.oracle.name)
+}
+
+// LogMessage obtains a buffer that represents the result of the ScriptedOracleTestCase. This buffer can be passed to
+// a logger for console or file logging.
+func (t *ScriptedOracleTestCase) LogMessage() *logging.LogBuffer {
+	// If the test failed, return a failure message.
+	buffer := logging.NewLogBuffer()
+	if t.Status() == TestCaseStatusFailed {
+		buffer.Append(colors.RedBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
+		buffer.Append(fmt.Sprintf("Test for scripted oracle \"%s\" failed after the following call sequence:\n", t.oracle.name))
+		if t.failureMessage != "" {
+			buffer.Append(fmt.Sprintf("The scripted oracle reported: %s\n", t.failureMessage))
+		}
+		buffer.Append(colors.Bold, "[Call Sequence]", colors.Reset, "\n")
+		buffer.Append(t.CallSequence().Log().Elements()...)
+		return buffer
+	}
+
+	buffer.Append(colors.GreenBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset)
+	return buffer
+}
+
+// Message obtains a text-based printable message which describes the result of the ScriptedOracleTestCase.
+func (t *ScriptedOracleTestCase) Message() string {
+	// Internally, we just call log message and convert it to a string. This can be useful for 3rd party apps
+	return t.LogMessage().String()
+}
+
+// ID obtains a unique identifier for a test result.
+func (t *ScriptedOracleTestCase) ID() string {
+	return strings.Replace(fmt.Sprintf("SCRIPTED-ORACLE-%s", t.oracle.name), "_", "-", -1)
+}
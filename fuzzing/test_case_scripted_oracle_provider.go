@@ -0,0 +1,169 @@
+package fuzzing
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+)
+
+// ScriptedOracleTestCaseProvider is a ScriptedOracleTestCase provider which spawns a test case for every configured
+// scripted oracle, and checks every call made in a call sequence against them. Scripted oracles are Starlark scripts
+// which may inspect the call and read the chain state after it through a restricted API, allowing throwaway oracles
+// to be expressed without writing Solidity properties.
+type ScriptedOracleTestCaseProvider struct {
+	// fuzzer describes the Fuzzer which this provider is attached to.
+	fuzzer *Fuzzer
+
+	// testProvider describes the registration of this provider with the Fuzzer, used to mute it while fuzzing.
+	testProvider *TestProvider
+
+	// testCases is a list of scripted oracle test cases, in the order their scripts were configured.
+	testCases []*ScriptedOracleTestCase
+}
+
+// attachScriptedOracleTestCaseProvider attaches a new ScriptedOracleTestCaseProvider to the Fuzzer and returns it.
+func attachScriptedOracleTestCaseProvider(fuzzer *Fuzzer) *ScriptedOracleTestCaseProvider {
+	// Create a test case provider
+	t := &ScriptedOracleTestCaseProvider{
+		fuzzer:       fuzzer,
+		testProvider: fuzzer.RegisterTestProvider("scripted-oracle"),
+	}
+
+	// Subscribe the provider to relevant events the fuzzer emits.
+	fuzzer.Events.FuzzerStarting.SubscribeNamed("scripted oracle test provider", t.onFuzzerStarting)
+	fuzzer.Events.FuzzerStopping.SubscribeNamed("scripted oracle test provider", t.onFuzzerStopping)
+
+	// Add the provider's call sequence test function to the fuzzer.
+	fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, t.testProvider.CallSequenceTestFunc(t.callSequencePostCallTest))
+	return t
+}
+
+// onFuzzerStarting is the event handler triggered when the Fuzzer is starting a fuzzing campaign. It loads every
+// configured scripted oracle and creates a test case for each, in a "running" state, as calls can be checked against
+// them as soon as fuzzing begins.
+func (t *ScriptedOracleTestCaseProvider) onFuzzerStarting(event FuzzerStartingEvent) error {
+	// Reset our state
+	t.testCases = make([]*ScriptedOracleTestCase, 0)
+
+	// Load each script and create a test case for it, ensuring their names are unique so they can be identified.
+	testingConfig := t.fuzzer.config.Fuzzing.Testing.ScriptedOracleTesting
+	names := make(map[string]string)
+	for _, path := range testingConfig.Scripts {
+		oracle, err := loadScriptedOracle(path, testingConfig.MaxExecutionSteps, time.Duration(testingConfig.Timeout)*time.Millisecond)
+		if err != nil {
+			return err
+		}
+		if existingPath, exists := names[oracle.name]; exists {
+			return fmt.Errorf("scripted oracles '%s' and '%s' must have distinct file names", existingPath, path)
+		}
+		names[oracle.name] = path
+
+		// Create our test case
+		testCase := &ScriptedOracleTestCase{
+			status:       TestCaseStatusRunning,
+			oracle:       oracle,
+			callSequence: nil,
+		}
+
+		// Add to our test cases and register them with the fuzzer
+		t.testCases = append(t.testCases, testCase)
+		t.fuzzer.RegisterTestCase(testCase)
+	}
+	return nil
+}
+
+// onFuzzerStopping is the event handler triggered when the Fuzzer is stopping the fuzzing campaign and all workers
+// have been destroyed. It sets test cases in "running" states to "passed".
+func (t *ScriptedOracleTestCaseProvider) onFuzzerStopping(event FuzzerStoppingEvent) error {
+	// Loop through each test case and set any tests with a running status to a passed status, or a muted status if
+	// this provider was muted.
+	for _, testCase := range t.testCases {
+		if testCase.status == TestCaseStatusRunning {
+			if t.testProvider.Enabled() {
+				testCase.status = TestCaseStatusPassed
+			} else {
+				testCase.status = TestCaseStatusMuted
+			}
+		}
+	}
+	return nil
+}
+
+// callSequencePostCallTest provides is a CallSequenceTestFunc that performs post-call testing logic for the attached
+// Fuzzer and any underlying FuzzerWorker. It is called after every call made in a call sequence. It checks the call
+// against every scripted oracle which has not yet failed.
+func (t *ScriptedOracleTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+	// Create a list of shrink call sequence verifiers, which we populate for each failed test we want a call sequence
+	// shrunk for.
+	shrinkRequests := make([]ShrinkCallSequenceRequest, 0)
+
+	for _, testCase := range t.testCases {
+		// If the test already failed, skip it.
+		if worker.testCaseFailed(testCase) {
+			continue
+		}
+
+		// Check the last call against the scripted oracle.
+		if passed, _ := checkScriptedOracle(worker, testCase, callSequence); passed {
+			continue
+		}
+
+		// If we failed a test, we update our state immediately. We provide a shrink verifier which will update
+		// the call sequence for each shrunken sequence provided that fails the test.
+		testCase := testCase
+		shrinkRequest := ShrinkCallSequenceRequest{
+			TestName:             testCase.Name(),
+			CallSequenceToShrink: callSequence,
+			VerifierFunction: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence) (bool, error) {
+				// If the last call of the shrunken sequence fails the scripted oracle, it is satisfactory.
+				passed, _ := checkScriptedOracle(worker, testCase, shrunkenCallSequence)
+				return !passed, nil
+			},
+			FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error {
+				// When we're finished shrinking, attach an execution trace to the last call. If verboseTracing is
+				// true, attach to all calls.
+				if len(shrunkenCallSequence) > 0 {
+					err := worker.executeWithExecutionTraces(shrunkenCallSequence, verboseTracing)
+					if err != nil {
+						return err
+					}
+				}
+
+				// Obtain the message the scripted oracle provides for the shrunken sequence, so it can be reported.
+				passed, message := checkScriptedOracle(worker, testCase, shrunkenCallSequence)
+				if passed {
+					return fmt.Errorf("scripted oracle test provider did not detect a failure on final shrunken sequence")
+				}
+
+				// Update our test state and report it finalized.
+				testCase.status = TestCaseStatusFailed
+				testCase.callSequence = &shrunkenCallSequence
+				testCase.failureMessage = message
+				worker.workerMetrics().failedSequences.Add(worker.workerMetrics().failedSequences, big.NewInt(1))
+				worker.reportTestCaseFinished(testCase)
+				return nil
+			},
+			RecordResultInCorpus: true,
+			FailureID:            testCase.ID(),
+		}
+
+		// Add our shrink request to our list.
+		shrinkRequests = append(shrinkRequests, shrinkRequest)
+	}
+	return shrinkRequests, nil
+}
+
+// checkScriptedOracle checks the last call of the provided call sequence against the scripted oracle of the provided
+// test case. If the script could not check the call (e.g. it raised an error, or exceeded its budget), the call is
+// considered to have failed, with the error as its message, so a faulty script is reported as a test failure rather
+// than stopping the fuzzing campaign.
+// Returns a boolean indicating whether the call passed, and the message describing the result.
+func checkScriptedOracle(worker *FuzzerWorker, testCase *ScriptedOracleTestCase, callSequence calls.CallSequence) (bool, string) {
+	passed, message, err := testCase.oracle.check(worker, callSequence)
+	if err != nil {
+		return false, err.Error()
+	}
+	return passed, message
+}
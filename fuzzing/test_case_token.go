@@ -0,0 +1,81 @@
+package fuzzing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TokenTestCase describes a test being run by a TokenTestCaseProvider, which checks a single invariant of a token
+// standard for a token contract.
+type TokenTestCase struct {
+	// status describes the status of the test case
+	status TestCaseStatus
+	// targetContract describes the token contract whose invariant is tested
+	targetContract *fuzzerTypes.Contract
+	// standard describes the token standard the target contract is tested against
+	standard tokenStandard
+	// invariant describes the invariant of the token standard which is tested
+	invariant tokenInvariant
+	// callSequence describes the call sequence that violated the invariant
+	callSequence *calls.CallSequence
+	// tokenAddress describes the address of the token deployment which violated the invariant
+	tokenAddress common.Address
+	// violation describes how the invariant was violated
+	violation string
+}
+
+// Status describes the TestCaseStatus used to define the current state of the test.
+func (t *TokenTestCase) Status() TestCaseStatus {
+	return t.status
+}
+
+// CallSequence describes the types.CallSequence of calls sent to the EVM which resulted in this TestCase result.
+// This should be nil if the result is not related to the CallSequence.
+func (t *TokenTestCase) CallSequence() *calls.CallSequence {
+	return t.callSequence
+}
+
+// Violation describes how the invariant was violated. This is empty if the test has not failed.
+func (t *TokenTestCase) Violation() string {
+	return t.violation
+}
+
+// Name describes the name of the test case.
+func (t *TokenTestCase) Name() string {
+	return fmt.Sprintf("%s Test: %s (%s)", t.standard, t.targetContract.Name(), t.invariant)
+}
+
+// LogMessage obtains a buffer that represents the result of the TokenTestCase. This buffer can be passed to a logger
+// for console or file logging.
+func (t *TokenTestCase) LogMessage() *logging.LogBuffer {
+	// If the test failed, return a failure message.
+	buffer := logging.NewLogBuffer()
+	if t.Status() == TestCaseStatusFailed {
+		buffer.Append(colors.RedBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
+		buffer.Append(fmt.Sprintf("Test for %s contract \"%s\" violated its %s invariant after the following call sequence:\n", t.standard, t.targetContract.Name(), t.invariant))
+		buffer.Append(fmt.Sprintf("The token (%v) was found to be inconsistent: %s\n", formatTestAddress(t.callSequence, t.tokenAddress), t.violation))
+		buffer.Append(colors.Bold, "[Call Sequence]", colors.Reset, "\n")
+		buffer.Append(t.CallSequence().Log().Elements()...)
+		return buffer
+	}
+
+	buffer.Append(colors.GreenBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset)
+	return buffer
+}
+
+// Message obtains a text-based printable message which describes the result of the TokenTestCase.
+func (t *TokenTestCase) Message() string {
+	// Internally, we just call log message and convert it to a string. This can be useful for 3rd party apps
+	return t.LogMessage().String()
+}
+
+// ID obtains a unique identifier for a test result.
+func (t *TokenTestCase) ID() string {
+	return strings.Replace(fmt.Sprintf("%s-%s-%s", t.standard, t.targetContract.Name(), strings.ToUpper(string(t.invariant))), "_", "-", -1)
+}
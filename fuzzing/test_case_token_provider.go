@@ -0,0 +1,340 @@
+package fuzzing
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TokenTestCaseProvider is a TokenTestCase provider which spawns test cases for the invariants of every contract
+// configured to be tested as an ERC20 or ERC721 token. It tracks the balances and owners of each token deployed
+// during setup from the Transfer events it emits, and ensures they remain consistent with the token's state after
+// every call in a call sequence.
+type TokenTestCaseProvider struct {
+	// fuzzer describes the Fuzzer which this provider is attached to.
+	fuzzer *Fuzzer
+
+	// testProvider describes the registration of this provider with the Fuzzer, used to mute it while fuzzing.
+	testProvider *TestProvider
+
+	// tokenStandards is a map of contract names to the token standard they should be tested against.
+	tokenStandards map[string]tokenStandard
+
+	// mintAddresses describes the addresses which Transfer events are emitted from when tokens are minted.
+	mintAddresses map[common.Address]struct{}
+
+	// burnAddresses describes the addresses which Transfer events are emitted to when tokens are burned.
+	burnAddresses map[common.Address]struct{}
+
+	// feeTolerance describes the fraction of the value of an ERC20 transfer which its sender and recipient may
+	// additionally lose.
+	feeTolerance *big.Rat
+
+	// testCases is a map of contract names and invariants to token test cases.
+	testCases map[tokenTestCaseKey]*TokenTestCase
+
+	// testCasesLock is used for thread-synchronization when updating testCases
+	testCasesLock sync.Mutex
+
+	// workerStates is a slice where each element stores state for a given worker index.
+	workerStates []tokenTestCaseProviderWorkerState
+}
+
+// tokenTestCaseKey describes the key a TokenTestCase is stored under by a TokenTestCaseProvider.
+type tokenTestCaseKey struct {
+	// contractName describes the name of the token contract tested.
+	contractName string
+	// invariant describes the invariant tested.
+	invariant tokenInvariant
+}
+
+// tokenTestCaseProviderWorkerState represents the state for an individual worker maintained by TokenTestCaseProvider.
+type tokenTestCaseProviderWorkerState struct {
+	// baseTracker describes the state of the tested tokens once the worker's chain was set up, which every call
+	// sequence begins from.
+	baseTracker *tokenTracker
+
+	// tracker describes the state of the tested tokens throughout the call sequence being tested, or nil if it could
+	// not be tracked.
+	tracker *tokenTracker
+}
+
+// attachTokenTestCaseProvider attaches a new TokenTestCaseProvider to the Fuzzer and returns it.
+func attachTokenTestCaseProvider(fuzzer *Fuzzer) *TokenTestCaseProvider {
+	// Parse our mint and burn addresses, which always include the zero address. These were validated with the project
+	// configuration.
+	tokenConfig := fuzzer.config.Fuzzing.Testing.TokenTesting
+	mintAddresses, _ := utils.HexStringsToAddresses(tokenConfig.MintAddresses)
+	burnAddresses, _ := utils.HexStringsToAddresses(tokenConfig.BurnAddresses)
+
+	// Create a test case provider
+	t := &TokenTestCaseProvider{
+		fuzzer:         fuzzer,
+		testProvider:   fuzzer.RegisterTestProvider("token"),
+		tokenStandards: make(map[string]tokenStandard),
+		mintAddresses:  map[common.Address]struct{}{{}: {}},
+		burnAddresses:  map[common.Address]struct{}{{}: {}},
+	}
+
+	// Parse our fee tolerance from its shortest decimal representation, so tolerances like 0.29 are not rounded down
+	// by their binary representation.
+	t.feeTolerance, _ = new(big.Rat).SetString(strconv.FormatFloat(tokenConfig.TransferFeeTolerance, 'f', -1, 64))
+
+	// Map our token contracts to their standards, and add our mint and burn addresses.
+	for _, contractName := range tokenConfig.ERC20Contracts {
+		t.tokenStandards[contractName] = tokenStandardERC20
+	}
+	for _, contractName := range tokenConfig.ERC721Contracts {
+		t.tokenStandards[contractName] = tokenStandardERC721
+	}
+	for _, address := range mintAddresses {
+		t.mintAddresses[address] = struct{}{}
+	}
+	for _, address := range burnAddresses {
+		t.burnAddresses[address] = struct{}{}
+	}
+
+	// Subscribe the provider to relevant events the fuzzer emits.
+	fuzzer.Events.FuzzerStarting.SubscribeNamed("token test provider", t.onFuzzerStarting)
+	fuzzer.Events.FuzzerStopping.SubscribeNamed("token test provider", t.onFuzzerStopping)
+	fuzzer.Events.WorkerCreated.SubscribeNamed("token test provider", t.onWorkerCreated)
+
+	// Add the provider's call sequence test function to the fuzzer.
+	fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, t.testProvider.CallSequenceTestFunc(t.callSequencePostCallTest))
+	return t
+}
+
+// replayCallSequence re-executes the provided call sequence from the worker's base testing state, tracking the state
+// of the tested tokens throughout it.
+// Returns the violations of token invariants caused by the last call in the sequence, or an error if one occurs.
+func (t *TokenTestCaseProvider) replayCallSequence(worker *FuzzerWorker, callSequence calls.CallSequence) ([]tokenViolation, error) {
+	// Revert to our base testing state, and begin tracking from it.
+	err := worker.chain.RevertToBlockIndex(worker.testingBaseBlockIndex)
+	if err != nil {
+		return nil, err
+	}
+	tracker := t.workerStates[worker.WorkerIndex()].baseTracker.clone()
+
+	// Execute our sequence, checking our invariants after each call.
+	var violations []tokenViolation
+	fetchElementFunc := func(currentIndex int) (*calls.CallSequenceElement, error) {
+		if currentIndex < len(callSequence) {
+			return callSequence[currentIndex], nil
+		}
+		return nil, nil
+	}
+	executionCheckFunc := func(currentlyExecutedSequence calls.CallSequence) (bool, error) {
+		violations, err = t.checkCall(worker, tracker, currentlyExecutedSequence[len(currentlyExecutedSequence)-1])
+		return false, err
+	}
+	executedSequence, err := calls.ExecuteCallSequenceIteratively(worker.chain, fetchElementFunc, executionCheckFunc)
+	if err != nil || len(executedSequence) != len(callSequence) {
+		return nil, err
+	}
+	return violations, nil
+}
+
+// findViolation obtains the violation of the invariant tested by the provided test case from a list of violations.
+// Returns the violation, or nil if it was not found.
+func (t *TokenTestCaseProvider) findViolation(testCase *TokenTestCase, violations []tokenViolation) *tokenViolation {
+	for i := range violations {
+		if violations[i].invariant == testCase.invariant && violations[i].token.contract.Name() == testCase.targetContract.Name() {
+			return &violations[i]
+		}
+	}
+	return nil
+}
+
+// onFuzzerStarting is the event handler triggered when the Fuzzer is starting a fuzzing campaign. It creates test cases
+// in a "not started" state for every invariant of every configured token contract discovered in the contract
+// definitions known to the Fuzzer.
+func (t *TokenTestCaseProvider) onFuzzerStarting(event FuzzerStartingEvent) error {
+	// Reset our state
+	t.testCases = make(map[tokenTestCaseKey]*TokenTestCase)
+	t.workerStates = make([]tokenTestCaseProviderWorkerState, t.fuzzer.Config().Fuzzing.Workers)
+
+	// Create a test case for every invariant of every token contract.
+	for _, contract := range t.fuzzer.ContractDefinitions() {
+		standard, isToken := t.tokenStandards[contract.Name()]
+		if !isToken {
+			continue
+		}
+		for _, invariant := range tokenStandardInvariants[standard] {
+			// Create our test case
+			testCase := &TokenTestCase{
+				status:         TestCaseStatusNotStarted,
+				targetContract: contract,
+				standard:       standard,
+				invariant:      invariant,
+				callSequence:   nil,
+			}
+
+			// Add to our test cases and register them with the fuzzer
+			t.testCases[tokenTestCaseKey{contractName: contract.Name(), invariant: invariant}] = testCase
+			t.fuzzer.RegisterTestCase(testCase)
+		}
+	}
+	return nil
+}
+
+// onFuzzerStopping is the event handler triggered when the Fuzzer is stopping the fuzzing campaign and all workers
+// have been destroyed. It sets test cases in "running" states to "passed".
+func (t *TokenTestCaseProvider) onFuzzerStopping(event FuzzerStoppingEvent) error {
+	// Loop through each test case and set any tests with a running status to a passed status, or a muted status if
+	// this provider was muted.
+	for _, testCase := range t.testCases {
+		if testCase.status == TestCaseStatusRunning {
+			if t.testProvider.Enabled() {
+				testCase.status = TestCaseStatusPassed
+			} else {
+				testCase.status = TestCaseStatusMuted
+			}
+		}
+	}
+	return nil
+}
+
+// onWorkerCreated is the event handler triggered when a FuzzerWorker is created by the Fuzzer. It ensures state tracked
+// for that worker index is refreshed and subscribes to relevant worker events.
+func (t *TokenTestCaseProvider) onWorkerCreated(event FuzzerWorkerCreatedEvent) error {
+	// Create a new state for this worker.
+	t.workerStates[event.Worker.WorkerIndex()] = tokenTestCaseProviderWorkerState{}
+
+	// Subscribe to relevant worker events.
+	SubscribeNonCritical(t.fuzzer, &event.Worker.Events.ContractAdded, "token test provider", t.onWorkerDeployedContractAdded)
+	SubscribeNonCritical(t.fuzzer, &event.Worker.Events.FuzzerWorkerChainSetup, "token test provider", t.onWorkerChainSetup)
+	return nil
+}
+
+// onWorkerChainSetup is the event handler triggered when a FuzzerWorker has set up its chain. It begins tracking the
+// state of the tested tokens deployed during setup.
+func (t *TokenTestCaseProvider) onWorkerChainSetup(event FuzzerWorkerChainSetupEvent) error {
+	baseTracker, err := t.newTokenTracker(event.Worker)
+	if err != nil {
+		return fmt.Errorf("failed to track the state of tested tokens: %v", err)
+	}
+	t.workerStates[event.Worker.WorkerIndex()] = tokenTestCaseProviderWorkerState{
+		baseTracker: baseTracker,
+	}
+	return nil
+}
+
+// onWorkerDeployedContractAdded is the event handler triggered when a FuzzerWorker detects a new contract deployment
+// on its underlying chain. Any test case previously made for the deployed contract which is in a "not started" state
+// is put into a "running" state, as it is now potentially reachable for testing.
+func (t *TokenTestCaseProvider) onWorkerDeployedContractAdded(event FuzzerWorkerContractAddedEvent) error {
+	// If we don't have a contract definition, we can't run tests against the contract.
+	if event.ContractDefinition == nil {
+		return nil
+	}
+
+	// If we have test cases for this contract in a not-started state, we can signal a running state now.
+	t.testCasesLock.Lock()
+	defer t.testCasesLock.Unlock()
+	for _, invariant := range tokenStandardInvariants[t.tokenStandards[event.ContractDefinition.Name()]] {
+		testCase, testCaseExists := t.testCases[tokenTestCaseKey{contractName: event.ContractDefinition.Name(), invariant: invariant}]
+		if testCaseExists && testCase.Status() == TestCaseStatusNotStarted {
+			testCase.status = TestCaseStatusRunning
+		}
+	}
+	return nil
+}
+
+// callSequencePostCallTest provides is a CallSequenceTestFunc that performs post-call testing logic for the attached
+// Fuzzer and any underlying FuzzerWorker. It is called after every call made in a call sequence. It updates the tracked
+// state of the tested tokens with the results of the call, and checks whether the call violated any of their
+// invariants.
+func (t *TokenTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+	// Create a list of shrink call sequence verifiers, which we populate for each failed test we want a call sequence
+	// shrunk for.
+	shrinkRequests := make([]ShrinkCallSequenceRequest, 0)
+
+	// If we have no tested tokens, there is nothing to check.
+	workerState := &t.workerStates[worker.WorkerIndex()]
+	if len(callSequence) == 0 || workerState.baseTracker == nil || len(workerState.baseTracker.tokens) == 0 {
+		return shrinkRequests, nil
+	}
+
+	// If this is the first call in the sequence, we begin tracking from our base state. Otherwise, the tracked state
+	// must have been updated for the previous call (e.g. it is not if this provider was muted throughout it), or the
+	// remainder of the sequence cannot be checked.
+	if len(callSequence) == 1 {
+		workerState.tracker = workerState.baseTracker.clone()
+	} else if workerState.tracker == nil || workerState.tracker.lastElement != callSequence[len(callSequence)-2] {
+		workerState.tracker = nil
+		return shrinkRequests, nil
+	}
+
+	// Update our tracked state and check our invariants.
+	violations, err := t.checkCall(worker, workerState.tracker, callSequence[len(callSequence)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a shrink request for each violated invariant whose test has not yet failed.
+	for _, violation := range violations {
+		t.testCasesLock.Lock()
+		testCase, testCaseExists := t.testCases[tokenTestCaseKey{contractName: violation.token.contract.Name(), invariant: violation.invariant}]
+		t.testCasesLock.Unlock()
+		if !testCaseExists || worker.testCaseFailed(testCase) {
+			continue
+		}
+
+		// If we failed a test, we update our state immediately. We provide a shrink verifier which will update
+		// the call sequence for each shrunken sequence provided that fails the test.
+		shrinkRequest := ShrinkCallSequenceRequest{
+			TestName:             testCase.Name(),
+			CallSequenceToShrink: callSequence,
+			VerifierFunction: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence) (bool, error) {
+				// If the last call violated the same invariant, this shrunk sequence is satisfactory.
+				shrunkSeqViolations, err := t.replayCallSequence(worker, shrunkenCallSequence)
+				if err != nil {
+					return false, err
+				}
+				return t.findViolation(testCase, shrunkSeqViolations) != nil, nil
+			},
+			FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error {
+				// Obtain the violation caused by the shrunken sequence, so it can be reported.
+				shrunkSeqViolations, err := t.replayCallSequence(worker, shrunkenCallSequence)
+				if err != nil {
+					return err
+				}
+				shrunkSeqViolation := t.findViolation(testCase, shrunkSeqViolations)
+				if shrunkSeqViolation == nil {
+					return fmt.Errorf("token test provider did not detect an invariant violation on final shrunken sequence")
+				}
+
+				// When we're finished shrinking, attach an execution trace to the last call. If verboseTracing is
+				// true, attach to all calls.
+				err = worker.chain.RevertToBlockIndex(worker.testingBaseBlockIndex)
+				if err != nil {
+					return err
+				}
+				err = worker.executeWithExecutionTraces(shrunkenCallSequence, verboseTracing)
+				if err != nil {
+					return err
+				}
+
+				// Update our test state and report it finalized.
+				testCase.status = TestCaseStatusFailed
+				testCase.callSequence = &shrunkenCallSequence
+				testCase.tokenAddress = shrunkSeqViolation.token.address
+				testCase.violation = shrunkSeqViolation.description
+				worker.workerMetrics().failedSequences.Add(worker.workerMetrics().failedSequences, big.NewInt(1))
+				worker.reportTestCaseFinished(testCase)
+				return nil
+			},
+			RecordResultInCorpus: true,
+			FailureID:            testCase.ID(),
+		}
+
+		// Add our shrink request to our list.
+		shrinkRequests = append(shrinkRequests, shrinkRequest)
+	}
+	return shrinkRequests, nil
+}
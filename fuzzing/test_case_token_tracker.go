@@ -0,0 +1,528 @@
+package fuzzing
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	coreTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// tokenStandard describes the token standard which a contract tested by a TokenTestCaseProvider implements.
+type tokenStandard string
+
+const (
+	// tokenStandardERC20 describes a fungible token implementing ERC20.
+	tokenStandardERC20 tokenStandard = "ERC20"
+	// tokenStandardERC721 describes a non-fungible token implementing ERC721.
+	tokenStandardERC721 tokenStandard = "ERC721"
+)
+
+// tokenInvariant describes an invariant of a token standard which is tested by a TokenTestCaseProvider.
+type tokenInvariant string
+
+const (
+	// tokenInvariantSupply describes the ERC20 invariant where the total supply equals the sum of tracked balances.
+	tokenInvariantSupply tokenInvariant = "supply"
+	// tokenInvariantConservation describes the ERC20 invariant where balances only change as described by Transfer
+	// events.
+	tokenInvariantConservation tokenInvariant = "conservation"
+	// tokenInvariantOwnership describes the ERC721 invariant where each token is owned by the recipient of its last
+	// Transfer event, and is only transferred by its owner.
+	tokenInvariantOwnership tokenInvariant = "ownership"
+	// tokenInvariantBalance describes the ERC721 invariant where balances equal the count of tokens owned.
+	tokenInvariantBalance tokenInvariant = "balance"
+)
+
+// tokenStandardInvariants describes the invariants tested for each token standard.
+var tokenStandardInvariants = map[tokenStandard][]tokenInvariant{
+	tokenStandardERC20:  {tokenInvariantSupply, tokenInvariantConservation},
+	tokenStandardERC721: {tokenInvariantOwnership, tokenInvariantBalance},
+}
+
+// tokenTransferEventID describes the topic of the Transfer event, which is shared by ERC20 and ERC721. ERC20 events
+// carry the transferred value as data, while ERC721 events index the transferred token ID as a third topic.
+var tokenTransferEventID = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// tokenABI describes the methods called on token contracts to obtain their state. Token contracts are not required to
+// declare these methods in their own ABI, as they may be inherited from an interface the compiler omits.
+var tokenABI = func() abi.ABI {
+	parsedABI, err := abi.JSON(strings.NewReader(`[
+		{"type":"function","name":"totalSupply","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"ownerOf","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"address"}]}
+	]`))
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse token ABI: %v", err))
+	}
+	return parsedABI
+}()
+
+// tokenTransfer describes a Transfer event emitted by a token contract.
+type tokenTransfer struct {
+	// from describes the address the value or token was transferred from.
+	from common.Address
+	// to describes the address the value or token was transferred to.
+	to common.Address
+	// value describes the value transferred by an ERC20 token, or the ID of the token transferred by an ERC721 token.
+	value *big.Int
+}
+
+// tokenViolation describes a violation of a token invariant caused by a call.
+type tokenViolation struct {
+	// token describes the token whose invariant was violated.
+	token *trackedToken
+	// invariant describes the invariant which was violated.
+	invariant tokenInvariant
+	// description describes a human-readable explanation of the violation.
+	description string
+}
+
+// trackedToken describes the state of a deployed token contract, as tracked by a TokenTestCaseProvider from the
+// Transfer events it emitted.
+type trackedToken struct {
+	// standard describes the token standard which the token implements.
+	standard tokenStandard
+	// address describes the address the token is deployed at.
+	address common.Address
+	// contract describes the contract definition of the token.
+	contract *fuzzerTypes.Contract
+	// balances describes the balance of each tracked holder of an ERC20 token, observed after the last call.
+	balances map[common.Address]*big.Int
+	// owners describes the owner of each token ID of an ERC721 token, as described by Transfer events. Burned tokens
+	// are not included.
+	owners map[common.Hash]common.Address
+}
+
+// clone creates a copy of the trackedToken which can be updated independently.
+func (t *trackedToken) clone() *trackedToken {
+	clone := &trackedToken{
+		standard: t.standard,
+		address:  t.address,
+		contract: t.contract,
+		balances: make(map[common.Address]*big.Int, len(t.balances)),
+		owners:   make(map[common.Hash]common.Address, len(t.owners)),
+	}
+	for holder, balance := range t.balances {
+		clone.balances[holder] = new(big.Int).Set(balance)
+	}
+	for tokenID, owner := range t.owners {
+		clone.owners[tokenID] = owner
+	}
+	return clone
+}
+
+// tokenTracker describes the state of every tested token deployed on a worker's chain, as tracked across the calls of a
+// call sequence.
+type tokenTracker struct {
+	// tokens describes the tested tokens, ordered by address.
+	tokens []*trackedToken
+	// lastElement describes the call sequence element which the tracked state was last updated for, or nil if the
+	// state describes the chain prior to any call.
+	lastElement *calls.CallSequenceElement
+}
+
+// clone creates a copy of the tokenTracker which can be updated independently.
+func (t *tokenTracker) clone() *tokenTracker {
+	clone := &tokenTracker{
+		tokens:      make([]*trackedToken, len(t.tokens)),
+		lastElement: t.lastElement,
+	}
+	for i, token := range t.tokens {
+		clone.tokens[i] = token.clone()
+	}
+	return clone
+}
+
+// newTokenTracker creates a tokenTracker for the tested tokens deployed on the provided worker's chain, tracking their
+// state from the Transfer events emitted in every committed block. This should be called once the worker's chain is
+// set up, prior to testing.
+// Returns the tokenTracker, or an error if one occurs.
+func (t *TokenTestCaseProvider) newTokenTracker(worker *FuzzerWorker) (*tokenTracker, error) {
+	// Determine the tested tokens among the deployed contracts, ordered by address so they are always checked in the
+	// same order.
+	tracker := &tokenTracker{tokens: make([]*trackedToken, 0)}
+	deployedContracts := worker.DeployedContracts()
+	for address, contract := range deployedContracts {
+		standard, isToken := t.tokenStandards[contract.Name()]
+		if !isToken {
+			continue
+		}
+		tracker.tokens = append(tracker.tokens, &trackedToken{
+			standard: standard,
+			address:  address,
+			contract: contract,
+			balances: make(map[common.Address]*big.Int),
+			owners:   make(map[common.Hash]common.Address),
+		})
+	}
+	slices.SortFunc(tracker.tokens, func(a, b *trackedToken) int {
+		return bytes.Compare(a.address[:], b.address[:])
+	})
+
+	// Holders of ERC20 tokens are tracked from the accounts and contracts known prior to any Transfer events, as
+	// tokens may be minted to them without one.
+	initialHolders := make([]common.Address, 0, len(worker.fuzzer.senders)+len(deployedContracts)+1)
+	initialHolders = append(initialHolders, worker.fuzzer.senders...)
+	initialHolders = append(initialHolders, worker.fuzzer.deployer)
+	for address := range deployedContracts {
+		initialHolders = append(initialHolders, address)
+	}
+
+	for _, token := range tracker.tokens {
+		if token.standard == tokenStandardERC20 {
+			for _, holder := range initialHolders {
+				t.trackHolder(token, holder)
+			}
+		}
+
+		// Apply the Transfer events emitted during setup to our tracked state.
+		for _, block := range worker.chain.CommittedBlocks() {
+			for _, messageResults := range block.MessageResults {
+				for _, transfer := range t.tokenTransfers(token, messageResults.Receipt) {
+					if token.standard == tokenStandardERC20 {
+						t.trackHolder(token, transfer.from)
+						t.trackHolder(token, transfer.to)
+					} else {
+						t.applyERC721Transfer(token, transfer)
+					}
+				}
+			}
+		}
+
+		// Observe the balances of ERC20 holders.
+		for holder := range token.balances {
+			balance, ok, err := t.callTokenUint(worker, token, "balanceOf", holder)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				token.balances[holder] = balance
+			}
+		}
+	}
+	return tracker, nil
+}
+
+// isMintAddress indicates whether Transfer events from the provided address describe tokens being minted.
+func (t *TokenTestCaseProvider) isMintAddress(address common.Address) bool {
+	_, isMintAddress := t.mintAddresses[address]
+	return isMintAddress
+}
+
+// isBurnAddress indicates whether Transfer events to the provided address describe tokens being burned.
+func (t *TokenTestCaseProvider) isBurnAddress(address common.Address) bool {
+	_, isBurnAddress := t.burnAddresses[address]
+	return isBurnAddress
+}
+
+// trackHolder begins tracking the balance of the provided holder of an ERC20 token, if it is not already tracked.
+// Mint and burn addresses are never tracked. The balance of a newly tracked holder is unknown until it is observed, so
+// it is treated as zero.
+// Returns a boolean indicating whether the holder was newly tracked.
+func (t *TokenTestCaseProvider) trackHolder(token *trackedToken, holder common.Address) bool {
+	if t.isMintAddress(holder) || t.isBurnAddress(holder) {
+		return false
+	}
+	if _, tracked := token.balances[holder]; tracked {
+		return false
+	}
+	token.balances[holder] = big.NewInt(0)
+	return true
+}
+
+// applyERC721Transfer updates the owner of the token transferred by the provided Transfer event of an ERC721 token.
+// Returns a description of the ownership violation the transfer caused, or an empty string if there was none.
+func (t *TokenTestCaseProvider) applyERC721Transfer(token *trackedToken, transfer tokenTransfer) string {
+	tokenID := common.BigToHash(transfer.value)
+	owner, owned := token.owners[tokenID]
+
+	// Verify the token was transferred by its owner, or minted if it had none.
+	var violation string
+	if t.isMintAddress(transfer.from) {
+		if owned {
+			violation = fmt.Sprintf("token %v was minted while owned by %v", transfer.value, owner)
+		}
+	} else if !owned {
+		violation = fmt.Sprintf("token %v was transferred from %v while it had no owner", transfer.value, transfer.from)
+	} else if owner != transfer.from {
+		violation = fmt.Sprintf("token %v was transferred from %v while owned by %v", transfer.value, transfer.from, owner)
+	}
+
+	// Update the owner of the token.
+	if t.isBurnAddress(transfer.to) {
+		delete(token.owners, tokenID)
+	} else {
+		token.owners[tokenID] = transfer.to
+	}
+	return violation
+}
+
+// tokenTransfers obtains the Transfer events emitted by the provided token in a transaction. Events which do not match
+// the layout of the token's standard are ignored.
+func (t *TokenTestCaseProvider) tokenTransfers(token *trackedToken, receipt *coreTypes.Receipt) []tokenTransfer {
+	transfers := make([]tokenTransfer, 0)
+	if receipt == nil {
+		return transfers
+	}
+	for _, log := range receipt.Logs {
+		// Receipt logs are collected by transaction hash, which is shared by identical calls made by different senders
+		// in the same block, so we skip logs emitted by other transactions.
+		if log.TxIndex != receipt.TransactionIndex {
+			continue
+		}
+		if log.Address != token.address || len(log.Topics) == 0 || log.Topics[0] != tokenTransferEventID {
+			continue
+		}
+		transfer := tokenTransfer{}
+		if token.standard == tokenStandardERC20 && len(log.Topics) == 3 && len(log.Data) == 32 {
+			transfer.value = new(big.Int).SetBytes(log.Data)
+		} else if token.standard == tokenStandardERC721 && len(log.Topics) == 4 {
+			transfer.value = log.Topics[3].Big()
+		} else {
+			continue
+		}
+		transfer.from = common.BytesToAddress(log.Topics[1][:])
+		transfer.to = common.BytesToAddress(log.Topics[2][:])
+		transfers = append(transfers, transfer)
+	}
+	return transfers
+}
+
+// callToken calls a method of the token ABI on the provided token against the current chain state.
+// Returns the decoded return value, a boolean indicating whether the call succeeded and returned a value of the
+// expected type, or an error if one occurs.
+func (t *TokenTestCaseProvider) callToken(worker *FuzzerWorker, token *trackedToken, methodName string, args ...any) (any, bool, error) {
+	data, err := tokenABI.Pack(methodName, args...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Call the method from our first sender, as done for property tests.
+	msg := calls.NewCallMessage(worker.Fuzzer().senders[0], &token.address, 0, big.NewInt(0), worker.fuzzer.config.Fuzzing.TransactionGasLimit, nil, nil, nil, data)
+	msg.FillFromTestChainProperties(worker.chain)
+	executionResult, err := worker.Chain().CallContract(msg.ToCoreMessage(), nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to call token method '%s': %v", methodName, err)
+	}
+	if executionResult.Failed() {
+		return nil, false, nil
+	}
+
+	// Decode our return value. Malformed return data is treated as a failed call.
+	retVals, err := tokenABI.Methods[methodName].Outputs.Unpack(executionResult.Return())
+	if err != nil || len(retVals) != 1 {
+		return nil, false, nil
+	}
+	return retVals[0], true, nil
+}
+
+// callTokenUint calls a method of the token ABI which returns a uint256 on the provided token.
+// Returns the decoded return value, a boolean indicating whether the call succeeded, or an error if one occurs.
+func (t *TokenTestCaseProvider) callTokenUint(worker *FuzzerWorker, token *trackedToken, methodName string, args ...any) (*big.Int, bool, error) {
+	retVal, ok, err := t.callToken(worker, token, methodName, args...)
+	if !ok || err != nil {
+		return nil, false, err
+	}
+	value, ok := retVal.(*big.Int)
+	return value, ok, nil
+}
+
+// checkCall updates the provided tokenTracker with the results of the provided call, which must be the call executed
+// after the one the tracker was last updated for, and checks the invariants of every tracked token.
+// Returns the violations of token invariants caused by the call, at most one per token and invariant, or an error if
+// one occurs.
+func (t *TokenTestCaseProvider) checkCall(worker *FuzzerWorker, tracker *tokenTracker, element *calls.CallSequenceElement) ([]tokenViolation, error) {
+	tracker.lastElement = element
+	violations := make([]tokenViolation, 0)
+	for _, token := range tracker.tokens {
+		// Obtain the Transfer events the token emitted during this call.
+		transfers := t.tokenTransfers(token, element.ChainReference.MessageResults().Receipt)
+
+		// Check the invariants of the token's standard.
+		var tokenViolations []tokenViolation
+		var err error
+		if token.standard == tokenStandardERC20 {
+			tokenViolations, err = t.checkERC20Call(worker, token, transfers)
+		} else {
+			tokenViolations, err = t.checkERC721Call(worker, token, transfers)
+		}
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, tokenViolations...)
+	}
+	return violations, nil
+}
+
+// checkERC20Call updates the tracked state of an ERC20 token with the Transfer events it emitted during a call, and
+// checks its invariants against its state after the call.
+// Returns the violations of the token's invariants, or an error if one occurs.
+func (t *TokenTestCaseProvider) checkERC20Call(worker *FuzzerWorker, token *trackedToken, transfers []tokenTransfer) ([]tokenViolation, error) {
+	violations := make([]tokenViolation, 0)
+
+	// Determine the balance change each Transfer event describes for its sender and recipient, along with the value
+	// they transferred, which bounds the fees they may be charged. Holders we were not yet tracking are tracked from
+	// now on, but as their balance prior to the call is unknown, their balance change cannot be checked.
+	expectedChanges := make(map[common.Address]*big.Int)
+	transferredValues := make(map[common.Address]*big.Int)
+	newHolders := make(map[common.Address]struct{})
+	recordTransfer := func(holder common.Address, value *big.Int, change *big.Int) {
+		if t.trackHolder(token, holder) {
+			newHolders[holder] = struct{}{}
+		}
+		if _, tracked := token.balances[holder]; !tracked {
+			return
+		}
+		if expectedChanges[holder] == nil {
+			expectedChanges[holder] = big.NewInt(0)
+			transferredValues[holder] = big.NewInt(0)
+		}
+		expectedChanges[holder].Add(expectedChanges[holder], change)
+		transferredValues[holder].Add(transferredValues[holder], value)
+	}
+	for _, transfer := range transfers {
+		recordTransfer(transfer.from, transfer.value, new(big.Int).Neg(transfer.value))
+		recordTransfer(transfer.to, transfer.value, transfer.value)
+	}
+
+	// Check the balance change of each tracked holder, in a consistent order.
+	holders := make([]common.Address, 0, len(token.balances))
+	for holder := range token.balances {
+		holders = append(holders, holder)
+	}
+	slices.SortFunc(holders, func(a, b common.Address) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	balanceSum := big.NewInt(0)
+	for _, holder := range holders {
+		balance, ok, err := t.callTokenUint(worker, token, "balanceOf", holder)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			violations = append(violations, tokenViolation{token: token, invariant: tokenInvariantSupply, description: fmt.Sprintf("balanceOf(%v) failed", holder)})
+			return violations, nil
+		}
+		balanceSum.Add(balanceSum, balance)
+
+		// Balances may decrease beyond what Transfer events describe by the configured fraction of the value they
+		// transferred, but may never increase beyond it.
+		previousBalance := token.balances[holder]
+		token.balances[holder] = balance
+		if _, isNewHolder := newHolders[holder]; isNewHolder || len(violations) > 0 {
+			continue
+		}
+		change := new(big.Int).Sub(balance, previousBalance)
+		expectedChange := big.NewInt(0)
+		allowedFee := big.NewInt(0)
+		if expectedChanges[holder] != nil {
+			expectedChange = expectedChanges[holder]
+			allowedFee = t.transferFeeTolerance(transferredValues[holder])
+		}
+		if change.Cmp(expectedChange) > 0 || change.Cmp(new(big.Int).Sub(expectedChange, allowedFee)) < 0 {
+			violations = append(violations, tokenViolation{
+				token:       token,
+				invariant:   tokenInvariantConservation,
+				description: fmt.Sprintf("balance of %v changed by %v, while Transfer events describe a change of %v", holder, change, expectedChange),
+			})
+		}
+	}
+
+	// Check the total supply equals the sum of tracked balances.
+	totalSupply, ok, err := t.callTokenUint(worker, token, "totalSupply")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		violations = append(violations, tokenViolation{token: token, invariant: tokenInvariantSupply, description: "totalSupply() failed"})
+	} else if totalSupply.Cmp(balanceSum) != 0 {
+		violations = append(violations, tokenViolation{
+			token:       token,
+			invariant:   tokenInvariantSupply,
+			description: fmt.Sprintf("total supply of %v does not equal the sum of tracked balances, %v", totalSupply, balanceSum),
+		})
+	}
+	return violations, nil
+}
+
+// transferFeeTolerance obtains the fee a holder may be charged for transferring the provided value, as configured.
+func (t *TokenTestCaseProvider) transferFeeTolerance(value *big.Int) *big.Int {
+	allowedFee := new(big.Rat).Mul(new(big.Rat).SetInt(value), t.feeTolerance)
+	return new(big.Int).Quo(allowedFee.Num(), allowedFee.Denom())
+}
+
+// checkERC721Call updates the tracked state of an ERC721 token with the Transfer events it emitted during a call, and
+// checks its invariants for the tokens and holders involved in the call against its state after the call.
+// Returns the violations of the token's invariants, or an error if one occurs.
+func (t *TokenTestCaseProvider) checkERC721Call(worker *FuzzerWorker, token *trackedToken, transfers []tokenTransfer) ([]tokenViolation, error) {
+	violations := make([]tokenViolation, 0)
+	addViolation := func(invariant tokenInvariant, description string) {
+		for _, violation := range violations {
+			if violation.invariant == invariant {
+				return
+			}
+		}
+		violations = append(violations, tokenViolation{token: token, invariant: invariant, description: description})
+	}
+
+	// Apply each transfer, recording the tokens and holders involved in the order they were first seen.
+	tokenIDs := make([]common.Hash, 0)
+	holders := make([]common.Address, 0)
+	for _, transfer := range transfers {
+		if violation := t.applyERC721Transfer(token, transfer); violation != "" {
+			addViolation(tokenInvariantOwnership, violation)
+		}
+		if tokenID := common.BigToHash(transfer.value); !slices.Contains(tokenIDs, tokenID) {
+			tokenIDs = append(tokenIDs, tokenID)
+		}
+		for _, holder := range []common.Address{transfer.from, transfer.to} {
+			if !t.isMintAddress(holder) && !t.isBurnAddress(holder) && !slices.Contains(holders, holder) {
+				holders = append(holders, holder)
+			}
+		}
+	}
+
+	// Verify the owner of each token involved matches the owner described by Transfer events. Burned tokens should
+	// either revert or report the zero address as their owner.
+	for _, tokenID := range tokenIDs {
+		expectedOwner, owned := token.owners[tokenID]
+		retVal, ok, err := t.callToken(worker, token, "ownerOf", tokenID.Big())
+		if err != nil {
+			return nil, err
+		}
+		owner, _ := retVal.(common.Address)
+		if owned && (!ok || owner != expectedOwner) {
+			addViolation(tokenInvariantOwnership, fmt.Sprintf("ownerOf(%v) returned %v, while Transfer events describe it is owned by %v", tokenID.Big(), owner, expectedOwner))
+		} else if !owned && ok && owner != (common.Address{}) {
+			addViolation(tokenInvariantOwnership, fmt.Sprintf("ownerOf(%v) returned %v, while Transfer events describe it was burned", tokenID.Big(), owner))
+		}
+	}
+
+	// Verify the balance of each holder involved matches the count of tokens they own.
+	for _, holder := range holders {
+		expectedBalance := int64(0)
+		for _, owner := range token.owners {
+			if owner == holder {
+				expectedBalance++
+			}
+		}
+		balance, ok, err := t.callTokenUint(worker, token, "balanceOf", holder)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			addViolation(tokenInvariantBalance, fmt.Sprintf("balanceOf(%v) failed", holder))
+		} else if balance.Cmp(big.NewInt(expectedBalance)) != 0 {
+			addViolation(tokenInvariantBalance, fmt.Sprintf("balanceOf(%v) returned %v, while Transfer events describe it owns %v token(s)", holder, balance, expectedBalance))
+		}
+	}
+	return violations, nil
+}
@@ -0,0 +1,77 @@
+package fuzzing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/executiontracer"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+)
+
+// UntrustedDelegateCallTestCase describes a test being run by a UntrustedDelegateCallTestCaseProvider.
+type UntrustedDelegateCallTestCase struct {
+	// status describes the status of the test case
+	status TestCaseStatus
+	// targetContract describes the target contract which should not delegate call untrusted addresses
+	targetContract *fuzzerTypes.Contract
+	// callSequence describes the call sequence that caused the untrusted delegate call
+	callSequence *calls.CallSequence
+	// delegateCall describes the untrusted delegate call which caused the test to fail
+	delegateCall *executiontracer.DelegateCall
+	// delegateCallCaller describes a displayable description of the function which made the untrusted delegate call
+	delegateCallCaller string
+}
+
+// Status describes the TestCaseStatus used to define the current state of the test.
+func (t *UntrustedDelegateCallTestCase) Status() TestCaseStatus {
+	return t.status
+}
+
+// CallSequence describes the types.CallSequence of calls sent to the EVM which resulted in this TestCase result.
+// This should be nil if the result is not related to the CallSequence.
+func (t *UntrustedDelegateCallTestCase) CallSequence() *calls.CallSequence {
+	return t.callSequence
+}
+
+// DelegateCall describes the untrusted delegate call which caused the test to fail. This is nil if the test has not
+// failed.
+func (t *UntrustedDelegateCallTestCase) DelegateCall() *executiontracer.DelegateCall {
+	return t.delegateCall
+}
+
+// Name describes the name of the test case.
+func (t *UntrustedDelegateCallTestCase) Name() string {
+	return fmt.Sprintf("Untrusted Delegate Call Test: %s", t.targetContract.Name())
+}
+
+// LogMessage obtains a buffer that represents the result of the UntrustedDelegateCallTestCase. This buffer can be
+// passed to a logger for console or file logging.
+func (t *UntrustedDelegateCallTestCase) LogMessage() *logging.LogBuffer {
+	// If the test failed, return a failure message.
+	buffer := logging.NewLogBuffer()
+	if t.Status() == TestCaseStatusFailed {
+		buffer.Append(colors.RedBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset, "\n")
+		buffer.Append(fmt.Sprintf("Test for contract \"%s\" resulted in a delegate call to an untrusted address after the following call sequence:\n", t.targetContract.Name()))
+		buffer.Append(fmt.Sprintf("The contract (%v) executed the code of %v, which was provided as an argument of the last call, using %s in %s\n", formatTestAddress(t.callSequence, t.delegateCall.ContextAddress), formatTestAddress(t.callSequence, t.delegateCall.TargetAddress), t.delegateCall.CallType, t.delegateCallCaller))
+		buffer.Append(colors.Bold, "[Call Sequence]", colors.Reset, "\n")
+		buffer.Append(t.CallSequence().Log().Elements()...)
+		return buffer
+	}
+
+	buffer.Append(colors.GreenBold, fmt.Sprintf("[%s] ", t.Status()), colors.Bold, t.Name(), colors.Reset)
+	return buffer
+}
+
+// Message obtains a text-based printable message which describes the result of the UntrustedDelegateCallTestCase.
+func (t *UntrustedDelegateCallTestCase) Message() string {
+	// Internally, we just call log message and convert it to a string. This can be useful for 3rd party apps
+	return t.LogMessage().String()
+}
+
+// ID obtains a unique identifier for a test result.
+func (t *UntrustedDelegateCallTestCase) ID() string {
+	return strings.Replace(fmt.Sprintf("UNTRUSTED-DELEGATECALL-%s", t.targetContract.Name()), "_", "-", -1)
+}
@@ -0,0 +1,334 @@
+package fuzzing
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sync"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/executiontracer"
+	"github.com/crytic/medusa/utils"
+	"github.com/ethereum/go-ethereum/common"
+
+	"golang.org/x/exp/slices"
+)
+
+// UntrustedDelegateCallTestCaseProvider is a UntrustedDelegateCallTestCase provider which spawns test cases for every
+// contract and ensures that none of them delegate call an untrusted address, which could execute arbitrary code
+// against their storage. An address is considered untrusted if it was provided as an address argument of the call
+// which caused the delegate call, and was not deployed during setup. This is a coarse approximation of whether the
+// address is controlled by the caller, which does not require the fuzzer to produce a malicious implementation.
+type UntrustedDelegateCallTestCaseProvider struct {
+	// fuzzer describes the Fuzzer which this provider is attached to.
+	fuzzer *Fuzzer
+
+	// testProvider describes the registration of this provider with the Fuzzer, used to mute it while fuzzing.
+	testProvider *TestProvider
+
+	// testCases is a map of contract names to untrusted delegate call test cases.
+	testCases map[string]*UntrustedDelegateCallTestCase
+
+	// testCasesLock is used for thread-synchronization when updating testCases
+	testCasesLock sync.Mutex
+}
+
+// attachUntrustedDelegateCallTestCaseProvider attaches a new UntrustedDelegateCallTestCaseProvider to the Fuzzer and
+// returns it.
+func attachUntrustedDelegateCallTestCaseProvider(fuzzer *Fuzzer) *UntrustedDelegateCallTestCaseProvider {
+	// Create a test case provider
+	t := &UntrustedDelegateCallTestCaseProvider{
+		fuzzer:       fuzzer,
+		testProvider: fuzzer.RegisterTestProvider("untrusted-delegatecall"),
+	}
+
+	// Subscribe the provider to relevant events the fuzzer emits.
+	fuzzer.Events.FuzzerStarting.SubscribeNamed("untrusted delegate call test provider", t.onFuzzerStarting)
+	fuzzer.Events.FuzzerStopping.SubscribeNamed("untrusted delegate call test provider", t.onFuzzerStopping)
+	fuzzer.Events.WorkerCreated.SubscribeNamed("untrusted delegate call test provider", t.onWorkerCreated)
+
+	// Add the provider's call sequence test function to the fuzzer.
+	fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, t.testProvider.CallSequenceTestFunc(t.callSequencePostCallTest))
+	return t
+}
+
+// checkDelegateCalls checks the results of the last call for a delegate call made against the storage of a contract
+// being tested, to an untrusted address, which is not allowed by the configuration.
+// Returns the test case for the contract and the delegate call, or nil values if no such delegate call occurred. Returns
+// an error if one occurs.
+func (t *UntrustedDelegateCallTestCaseProvider) checkDelegateCalls(worker *FuzzerWorker, callSequence calls.CallSequence) (*UntrustedDelegateCallTestCase, *executiontracer.DelegateCall, error) {
+	// If we have an empty call sequence, we cannot have a delegate call
+	if len(callSequence) == 0 {
+		return nil, nil, nil
+	}
+
+	// Obtain the delegate calls recorded for the last call made in our sequence. If it did not make any, or had no
+	// address arguments they could have targeted, we can stop early.
+	lastCall := callSequence[len(callSequence)-1]
+	delegateCalls := executiontracer.GetDelegateCallTracerResults(lastCall.ChainReference.MessageResults())
+	if len(delegateCalls) == 0 || lastCall.Call.DataAbiValues == nil {
+		return nil, nil, nil
+	}
+	argumentAddresses := abiValueAddresses(lastCall.Call.DataAbiValues.InputValues)
+
+	for _, delegateCall := range delegateCalls {
+		// Verify the delegate call targeted an address provided as an argument.
+		if !argumentAddresses[delegateCall.TargetAddress] {
+			continue
+		}
+
+		// Verify we have a test case for the contract whose storage was used.
+		contract := worker.resolveContract(delegateCall.ContextAddress)
+		if contract == nil {
+			continue
+		}
+		t.testCasesLock.Lock()
+		testCase, testCaseExists := t.testCases[contract.Name()]
+		t.testCasesLock.Unlock()
+		if !testCaseExists {
+			continue
+		}
+
+		// Verify the delegate call was not made by an allowed function.
+		if t.isAllowedDelegateCall(worker, delegateCall) {
+			continue
+		}
+
+		// Verify the target address was not deployed during setup, as the deployer controls those.
+		setupDeployment, err := t.isSetupDeployment(worker, delegateCall.TargetAddress)
+		if err != nil {
+			return nil, nil, err
+		}
+		if setupDeployment {
+			continue
+		}
+		return testCase, delegateCall, nil
+	}
+	return nil, nil, nil
+}
+
+// isAllowedDelegateCall determines whether the provided delegate call is allowed by the configured list of function
+// signatures where delegate calls to addresses provided by the caller are expected.
+// Returns a boolean indicating whether the delegate call is allowed.
+func (t *UntrustedDelegateCallTestCaseProvider) isAllowedDelegateCall(worker *FuzzerWorker, delegateCall *executiontracer.DelegateCall) bool {
+	contract, method := worker.resolveContractMethod(delegateCall.CallerCodeAddress, delegateCall.CallerSelector)
+	if contract == nil || method == nil {
+		return false
+	}
+	return slices.Contains(t.fuzzer.config.Fuzzing.Testing.UntrustedDelegateCallTesting.AllowedDelegateCalls, contract.Name()+"."+method.Sig)
+}
+
+// isSetupDeployment determines whether code was deployed to the provided address when the worker's chain was set up,
+// prior to any call sequence being tested.
+// Returns a boolean indicating whether the address was deployed during setup, or an error if one occurs.
+func (t *UntrustedDelegateCallTestCaseProvider) isSetupDeployment(worker *FuzzerWorker, address common.Address) (bool, error) {
+	setupBlock := worker.chain.CommittedBlocks()[worker.testingBaseBlockIndex-1]
+	setupState, err := worker.chain.StateAfterBlockNumber(setupBlock.Header.Number.Uint64())
+	if err != nil {
+		return false, err
+	}
+	return len(setupState.GetCode(address)) > 0, nil
+}
+
+// formatCaller obtains a displayable string for the function which made the provided delegate call.
+func (t *UntrustedDelegateCallTestCaseProvider) formatCaller(worker *FuzzerWorker, delegateCall *executiontracer.DelegateCall) string {
+	contract, method := worker.resolveContractMethod(delegateCall.CallerCodeAddress, delegateCall.CallerSelector)
+	description := "<unresolved contract>"
+	if contract != nil {
+		description = contract.Name()
+		if method != nil {
+			description += "." + method.Sig
+		}
+	}
+	return fmt.Sprintf("%s (%v)", description, utils.AttachLabelToAddress(delegateCall.CallerCodeAddress, worker.chain.Labels[delegateCall.CallerCodeAddress]))
+}
+
+// abiValueAddresses obtains the addresses contained in the provided ABI values, including those nested in arrays,
+// slices, and tuples.
+// Returns a lookup of the addresses found.
+func abiValueAddresses(values []any) map[common.Address]bool {
+	addresses := make(map[common.Address]bool)
+	var collect func(value reflect.Value)
+	collect = func(value reflect.Value) {
+		if !value.IsValid() {
+			return
+		}
+		if address, ok := value.Interface().(common.Address); ok {
+			addresses[address] = true
+			return
+		}
+		switch value.Kind() {
+		case reflect.Interface:
+			collect(value.Elem())
+		case reflect.Array, reflect.Slice:
+			// Byte arrays and slices cannot contain addresses.
+			if value.Type().Elem().Kind() == reflect.Uint8 {
+				return
+			}
+			for i := 0; i < value.Len(); i++ {
+				collect(value.Index(i))
+			}
+		case reflect.Struct:
+			for i := 0; i < value.NumField(); i++ {
+				if value.Type().Field(i).IsExported() {
+					collect(value.Field(i))
+				}
+			}
+		}
+	}
+	for _, value := range values {
+		collect(reflect.ValueOf(value))
+	}
+	return addresses
+}
+
+// onFuzzerStarting is the event handler triggered when the Fuzzer is starting a fuzzing campaign. It creates test cases
+// in a "not started" state for every contract to test discovered in the contract definitions known to the Fuzzer.
+func (t *UntrustedDelegateCallTestCaseProvider) onFuzzerStarting(event FuzzerStartingEvent) error {
+	// Reset our state
+	t.testCases = make(map[string]*UntrustedDelegateCallTestCase)
+
+	// Create a test case for every contract.
+	for _, contract := range t.fuzzer.ContractDefinitions() {
+		// If we're not testing all contracts, verify the current contract is one we specified in our target or spec
+		// contracts.
+		if !t.fuzzer.config.Fuzzing.Testing.TestAllContracts && !slices.Contains(t.fuzzer.config.Fuzzing.TargetContracts, contract.Name()) && !t.fuzzer.isSpecContract(contract.Name()) {
+			continue
+		}
+
+		// Excluded contracts are never tested, and contracts where any delegate call is allowed need not be.
+		if slices.Contains(t.fuzzer.config.Fuzzing.Testing.ExcludeContracts, contract.Name()) || slices.Contains(t.fuzzer.config.Fuzzing.Testing.UntrustedDelegateCallTesting.AllowedDelegateCalls, contract.Name()) {
+			continue
+		}
+
+		// Create our test case
+		testCase := &UntrustedDelegateCallTestCase{
+			status:         TestCaseStatusNotStarted,
+			targetContract: contract,
+			callSequence:   nil,
+		}
+
+		// Add to our test cases and register them with the fuzzer
+		t.testCases[contract.Name()] = testCase
+		t.fuzzer.RegisterTestCase(testCase)
+	}
+	return nil
+}
+
+// onFuzzerStopping is the event handler triggered when the Fuzzer is stopping the fuzzing campaign and all workers
+// have been destroyed. It sets test cases in "running" states to "passed".
+func (t *UntrustedDelegateCallTestCaseProvider) onFuzzerStopping(event FuzzerStoppingEvent) error {
+	// Loop through each test case and set any tests with a running status to a passed status, or a muted status if
+	// this provider was muted.
+	for _, testCase := range t.testCases {
+		if testCase.status == TestCaseStatusRunning {
+			if t.testProvider.Enabled() {
+				testCase.status = TestCaseStatusPassed
+			} else {
+				testCase.status = TestCaseStatusMuted
+			}
+		}
+	}
+	return nil
+}
+
+// onWorkerCreated is the event handler triggered when a FuzzerWorker is created by the Fuzzer. It subscribes to
+// relevant worker events.
+func (t *UntrustedDelegateCallTestCaseProvider) onWorkerCreated(event FuzzerWorkerCreatedEvent) error {
+	// Subscribe to relevant worker events.
+	SubscribeNonCritical(t.fuzzer, &event.Worker.Events.ContractAdded, "untrusted delegate call test provider", t.onWorkerDeployedContractAdded)
+	SubscribeNonCritical(t.fuzzer, &event.Worker.Events.FuzzerWorkerChainCreated, "untrusted delegate call test provider", t.onWorkerChainCreated)
+	return nil
+}
+
+// onWorkerChainCreated is the event handler triggered when a FuzzerWorker has created its chain. It attaches a tracer
+// to the chain to record delegate calls.
+func (t *UntrustedDelegateCallTestCaseProvider) onWorkerChainCreated(event FuzzerWorkerChainCreatedEvent) error {
+	event.Chain.AddTracer(executiontracer.NewDelegateCallTracer().NativeTracer(), true, false)
+	return nil
+}
+
+// onWorkerDeployedContractAdded is the event handler triggered when a FuzzerWorker detects a new contract deployment
+// on its underlying chain. Any test case previously made for the deployed contract which is in a "not started" state
+// is put into a "running" state, as it is now potentially reachable for testing.
+func (t *UntrustedDelegateCallTestCaseProvider) onWorkerDeployedContractAdded(event FuzzerWorkerContractAddedEvent) error {
+	// If we don't have a contract definition, we can't run tests against the contract.
+	if event.ContractDefinition == nil {
+		return nil
+	}
+
+	// If we have a test case for this contract in a not-started state, we can signal a running state now.
+	t.testCasesLock.Lock()
+	testCase, testCaseExists := t.testCases[event.ContractDefinition.Name()]
+	t.testCasesLock.Unlock()
+	if testCaseExists && testCase.Status() == TestCaseStatusNotStarted {
+		testCase.status = TestCaseStatusRunning
+	}
+	return nil
+}
+
+// callSequencePostCallTest provides is a CallSequenceTestFunc that performs post-call testing logic for the attached
+// Fuzzer and any underlying FuzzerWorker. It is called after every call made in a call sequence. It checks whether the
+// call caused a contract being tested to delegate call an untrusted address.
+func (t *UntrustedDelegateCallTestCaseProvider) callSequencePostCallTest(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+	// Create a list of shrink call sequence verifiers, which we populate for each failed test we want a call sequence
+	// shrunk for.
+	shrinkRequests := make([]ShrinkCallSequenceRequest, 0)
+
+	// Check if the last call caused a contract being tested, which has not yet failed, to delegate call an untrusted
+	// address.
+	testCase, delegateCall, err := t.checkDelegateCalls(worker, callSequence)
+	if err != nil {
+		return nil, err
+	}
+	if delegateCall == nil || worker.testCaseFailed(testCase) {
+		return shrinkRequests, nil
+	}
+
+	// If we failed a test, we update our state immediately. We provide a shrink verifier which will update
+	// the call sequence for each shrunken sequence provided that fails the test.
+	shrinkRequest := ShrinkCallSequenceRequest{
+		TestName:             testCase.Name(),
+		CallSequenceToShrink: callSequence,
+		VerifierFunction: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence) (bool, error) {
+			// If the last call made an untrusted delegate call against the same contract, this shrunk sequence is
+			// satisfactory.
+			shrunkSeqTestCase, _, err := t.checkDelegateCalls(worker, shrunkenCallSequence)
+			return shrunkSeqTestCase == testCase, err
+		},
+		FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error {
+			// When we're finished shrinking, attach an execution trace to the last call. If verboseTracing is true,
+			// attach to all calls.
+			if len(shrunkenCallSequence) > 0 {
+				err := worker.executeWithExecutionTraces(shrunkenCallSequence, verboseTracing)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Obtain the delegate call made by the shrunken sequence, so it can be reported.
+			shrunkSeqTestCase, shrunkSeqDelegateCall, err := t.checkDelegateCalls(worker, shrunkenCallSequence)
+			if err != nil {
+				return err
+			}
+			if shrunkSeqTestCase != testCase {
+				return fmt.Errorf("untrusted delegate call test provider did not detect an untrusted delegate call on final shrunken sequence")
+			}
+
+			// Update our test state and report it finalized.
+			testCase.status = TestCaseStatusFailed
+			testCase.callSequence = &shrunkenCallSequence
+			testCase.delegateCall = shrunkSeqDelegateCall
+			testCase.delegateCallCaller = t.formatCaller(worker, shrunkSeqDelegateCall)
+			worker.workerMetrics().failedSequences.Add(worker.workerMetrics().failedSequences, big.NewInt(1))
+			worker.reportTestCaseFinished(testCase)
+			return nil
+		},
+		RecordResultInCorpus: true,
+		FailureID:            testCase.ID(),
+	}
+
+	// Add our shrink request to our list.
+	shrinkRequests = append(shrinkRequests, shrinkRequest)
+	return shrinkRequests, nil
+}
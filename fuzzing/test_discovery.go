@@ -0,0 +1,154 @@
+package fuzzing
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/crytic/medusa/fuzzing/config"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// ContractTestDiscovery describes the tests discovered in a contract definition, as they are registered by the test
+// providers once the contract is deployed.
+type ContractTestDiscovery struct {
+	// Contract describes the name of the contract definition.
+	Contract string `json:"contract"`
+
+	// PropertyTests describes the signatures of the methods discovered as property tests.
+	PropertyTests []string `json:"propertyTests"`
+
+	// StateChangingAssertionTests describes the signatures of the state-changing methods which are called and tested
+	// for assertion failures.
+	StateChangingAssertionTests []string `json:"stateChangingAssertionTests"`
+
+	// ViewAssertionTests describes the signatures of the view and pure methods which are called and tested for
+	// assertion failures.
+	ViewAssertionTests []string `json:"viewAssertionTests"`
+
+	// OptimizationTests describes the signatures of the methods discovered as optimization tests.
+	OptimizationTests []string `json:"optimizationTests"`
+}
+
+// discoverTests summarizes the tests discovered in the provided contract definitions, whose methods were sorted by
+// test type when their compilation targets were added.
+// Returns a summary for each contract definition, sorted by contract name.
+func discoverTests(contractDefinitions []*fuzzerTypes.Contract) []ContractTestDiscovery {
+	signatures := func(methods []abi.Method, filter func(method abi.Method) bool) []string {
+		sigs := make([]string, 0)
+		for _, method := range methods {
+			if filter == nil || filter(method) {
+				sigs = append(sigs, method.Sig)
+			}
+		}
+		slices.Sort(sigs)
+		return sigs
+	}
+
+	discovery := make([]ContractTestDiscovery, 0, len(contractDefinitions))
+	for _, contract := range contractDefinitions {
+		discovery = append(discovery, ContractTestDiscovery{
+			Contract:                    contract.Name(),
+			PropertyTests:               signatures(contract.PropertyTestMethods, nil),
+			StateChangingAssertionTests: signatures(contract.AssertionTestMethods, func(method abi.Method) bool { return !method.IsConstant() }),
+			ViewAssertionTests:          signatures(contract.AssertionTestMethods, abi.Method.IsConstant),
+			OptimizationTests:           signatures(contract.OptimizationTestMethods, nil),
+		})
+	}
+	slices.SortFunc(discovery, func(a, b ContractTestDiscovery) int {
+		return strings.Compare(a.Contract, b.Contract)
+	})
+	return discovery
+}
+
+// testDiscoveryTotal returns the amount of tests in the provided test discovery summaries which are run, given the
+// testing modes enabled in the provided testing configuration.
+func testDiscoveryTotal(discovery []ContractTestDiscovery, testingConfig config.TestingConfig) int {
+	if !testingConfig.Enabled {
+		return 0
+	}
+	total := 0
+	for _, contract := range discovery {
+		if testingConfig.PropertyTesting.Enabled {
+			total += len(contract.PropertyTests)
+		}
+		if testingConfig.AssertionTesting.Enabled {
+			total += len(contract.StateChangingAssertionTests) + len(contract.ViewAssertionTests)
+		}
+		if testingConfig.OptimizationTesting.Enabled {
+			total += len(contract.OptimizationTests)
+		}
+	}
+	return total
+}
+
+// testDiscovery summarizes the tests discovered in the contracts under test, which are those the fuzzer targets or
+// uses as spec contracts, unless every contract is tested, without those excluded from testing.
+func (f *Fuzzer) testDiscovery() []ContractTestDiscovery {
+	contractsUnderTest := make([]*fuzzerTypes.Contract, 0)
+	for _, contract := range f.contractDefinitions {
+		if f.isContractUnderTest(contract.Name()) {
+			contractsUnderTest = append(contractsUnderTest, contract)
+		}
+	}
+	return discoverTests(contractsUnderTest)
+}
+
+// isContractUnderTest indicates whether the contract with the provided name has its tests discovered, as it is
+// targeted or used as a spec contract, or every contract is tested, and it is not excluded from testing.
+func (f *Fuzzer) isContractUnderTest(contractName string) bool {
+	testingConfig := f.config.Fuzzing.Testing
+	if !testingConfig.TestAllContracts && !slices.Contains(f.config.Fuzzing.TargetContracts, contractName) && !f.isSpecContract(contractName) {
+		return false
+	}
+	return !slices.Contains(testingConfig.ExcludeContracts, contractName)
+}
+
+// noTestsDiscoveredWarning describes the warning reported when testing is enabled, but no tests were discovered.
+const noTestsDiscoveredWarning = "no tests discovered, check the test prefixes and enabled testing modes"
+
+// logTestDiscovery logs the provided test discovery summaries as a table, along with the names of the tests discovered
+// in each contract, warning if testing is enabled but no tests were discovered.
+func (f *Fuzzer) logTestDiscovery(discovery []ContractTestDiscovery) {
+	if len(discovery) > 0 {
+		buffer := logging.NewLogBuffer()
+		buffer.Append("Discovered tests:")
+		appendTestDiscovery(buffer, discovery)
+		f.logger.Info(buffer.Elements()...)
+	}
+	if f.config.Fuzzing.Testing.Enabled && testDiscoveryTotal(discovery, f.config.Fuzzing.Testing) == 0 {
+		f.logger.Warn(noTestsDiscoveredWarning)
+	}
+}
+
+// appendTestDiscovery appends the provided test discovery summaries to the provided log buffer, as a table with a row
+// for each contract, followed by the names of the tests discovered in it.
+func appendTestDiscovery(buffer *logging.LogBuffer, discovery []ContractTestDiscovery) {
+	// Determine the width of our contract name column.
+	nameWidth := len("contract")
+	for _, contract := range discovery {
+		nameWidth = max(nameWidth, len(contract.Contract))
+	}
+
+	// Append our header, followed by a row for each contract, and the tests discovered in it.
+	buffer.Append(colors.Bold, fmt.Sprintf("\n\t%-*s  %-10s  %-21s  %s", nameWidth, "contract", "properties", "assertions (mut/view)", "optimizations"), colors.Reset)
+	for _, contract := range discovery {
+		assertions := fmt.Sprintf("%d/%d", len(contract.StateChangingAssertionTests), len(contract.ViewAssertionTests))
+		buffer.Append(fmt.Sprintf("\n\t%-*s  %-10d  %-21s  %d", nameWidth, contract.Contract, len(contract.PropertyTests), assertions, len(contract.OptimizationTests)))
+		for _, tests := range []struct {
+			kind       string
+			signatures []string
+		}{
+			{"properties", contract.PropertyTests},
+			{"assertions", append(slices.Clone(contract.StateChangingAssertionTests), contract.ViewAssertionTests...)},
+			{"optimizations", contract.OptimizationTests},
+		} {
+			if len(tests.signatures) > 0 {
+				buffer.Append(fmt.Sprintf("\n\t  %s: %s", tests.kind, strings.Join(tests.signatures, ", ")))
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package fuzzing
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crytic/medusa/compilation/types"
+	"github.com/crytic/medusa/fuzzing/config"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	fuzzingutils "github.com/crytic/medusa/fuzzing/utils"
+	"github.com/crytic/medusa/logging"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/assert"
+)
+
+// testDiscoveryFixtureAbi describes a contract with tests named using correct and misspelled prefixes.
+const testDiscoveryFixtureAbi = `[
+	{"type": "function", "name": "property_solvent", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "bool"}]},
+	{"type": "function", "name": "propery_typo", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "bool"}]},
+	{"type": "function", "name": "optimize_profit", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "int256"}]},
+	{"type": "function", "name": "optimise_typo", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "int256"}]},
+	{"type": "function", "name": "deposit", "stateMutability": "nonpayable", "inputs": [{"name": "amount", "type": "uint256"}], "outputs": []},
+	{"type": "function", "name": "balance", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "uint256"}]}
+]`
+
+// newTestDiscoveryFixture creates a contract definition from testDiscoveryFixtureAbi, with its methods sorted by test
+// type the same way they are when compilation targets are added to the fuzzer.
+func newTestDiscoveryFixture(t *testing.T, name string, testingConfig config.TestingConfig) *fuzzerTypes.Contract {
+	parsedAbi, err := abi.JSON(strings.NewReader(testDiscoveryFixtureAbi))
+	assert.NoError(t, err)
+	compiledContract := &types.CompiledContract{Abi: parsedAbi}
+	contract := fuzzerTypes.NewContract(name, "", compiledContract, nil)
+	contract.AssertionTestMethods, contract.PropertyTestMethods, contract.OptimizationTestMethods = fuzzingutils.BinTestByType(compiledContract,
+		testingConfig.PropertyTesting.TestPrefixes, testingConfig.OptimizationTesting.TestPrefixes, testingConfig.TestViewMethods)
+	return contract
+}
+
+// TestDiscoverTests tests that tests are only discovered for methods named with the configured prefixes, that
+// assertion-tested methods are split by mutability, and that the total only counts tests of enabled testing modes.
+func TestDiscoverTests(t *testing.T) {
+	projectConfig, err := config.GetDefaultProjectConfig("")
+	assert.NoError(t, err)
+	testingConfig := projectConfig.Fuzzing.Testing
+	testingConfig.AssertionTesting.Enabled = true
+	testingConfig.TestViewMethods = false
+
+	// Misspelled tests should not be discovered, and view methods should not be tested unless configured to.
+	discovery := discoverTests([]*fuzzerTypes.Contract{
+		newTestDiscoveryFixture(t, "Vault", testingConfig),
+		fuzzerTypes.NewContract("Empty", "", &types.CompiledContract{}, nil),
+	})
+	assert.EqualValues(t, []ContractTestDiscovery{
+		{
+			Contract:                    "Empty",
+			PropertyTests:               []string{},
+			StateChangingAssertionTests: []string{},
+			ViewAssertionTests:          []string{},
+			OptimizationTests:           []string{},
+		},
+		{
+			Contract:                    "Vault",
+			PropertyTests:               []string{"property_solvent()"},
+			StateChangingAssertionTests: []string{"deposit(uint256)"},
+			ViewAssertionTests:          []string{},
+			OptimizationTests:           []string{"optimize_profit()"},
+		},
+	}, discovery)
+	assert.EqualValues(t, 3, testDiscoveryTotal(discovery, testingConfig))
+
+	// Testing view methods should discover view methods, including misspelled tests, as assertion tests.
+	testingConfig.TestViewMethods = true
+	discovery = discoverTests([]*fuzzerTypes.Contract{newTestDiscoveryFixture(t, "Vault", testingConfig)})
+	assert.EqualValues(t, []string{"balance()", "optimise_typo()", "propery_typo()"}, discovery[0].ViewAssertionTests)
+	assert.EqualValues(t, 6, testDiscoveryTotal(discovery, testingConfig))
+
+	// Only tests of enabled testing modes should be counted.
+	testingConfig.AssertionTesting.Enabled = false
+	assert.EqualValues(t, 2, testDiscoveryTotal(discovery, testingConfig))
+	testingConfig.PropertyTesting.Enabled = false
+	testingConfig.OptimizationTesting.Enabled = false
+	assert.Zero(t, testDiscoveryTotal(discovery, testingConfig))
+	testingConfig.PropertyTesting.Enabled = true
+	testingConfig.Enabled = false
+	assert.Zero(t, testDiscoveryTotal(discovery, testingConfig))
+
+	// The discovered tests should be listed by name in the logged table.
+	buffer := logging.NewLogBuffer()
+	appendTestDiscovery(buffer, discovery)
+	assert.Contains(t, buffer.String(), "Vault")
+	assert.Contains(t, buffer.String(), "property_solvent()")
+	assert.Contains(t, buffer.String(), "1/3")
+}
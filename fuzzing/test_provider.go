@@ -0,0 +1,138 @@
+package fuzzing
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/logging/colors"
+)
+
+// TestProvider describes a test provider registered with a Fuzzer, such as the property or assertion test case
+// providers, which can be muted or unmuted while fuzzing. A muted test provider's test functions are not invoked, so
+// it produces no shrink requests, and its test cases which have not failed are reported as muted once fuzzing stops.
+type TestProvider struct {
+	// id describes the stable identifier of the test provider.
+	id string
+
+	// enabled describes whether the test provider's test functions are invoked.
+	enabled atomic.Bool
+}
+
+// ID returns the stable identifier of the TestProvider.
+func (p *TestProvider) ID() string {
+	return p.id
+}
+
+// Enabled returns whether the TestProvider's test functions are invoked, or whether it is muted.
+func (p *TestProvider) Enabled() bool {
+	return p.enabled.Load()
+}
+
+// testFunc wraps the provided test function, so that it is only invoked while the TestProvider is enabled, and any
+// shrink requests it returns are attributed to the TestProvider.
+func (p *TestProvider) testFunc(testFunc func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error)) func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+	return func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+		if !p.Enabled() {
+			return nil, nil
+		}
+		shrinkRequests, err := testFunc(worker, callSequence)
+		for i := 0; i < len(shrinkRequests); i++ {
+			shrinkRequests[i].TestProviderID = p.id
+		}
+		return shrinkRequests, err
+	}
+}
+
+// CallSequenceTestFunc wraps the provided CallSequenceTestFunc, so that it is only invoked while the TestProvider is
+// enabled. The wrapped function should be added to FuzzerHooks.CallSequenceTestFuncs.
+func (p *TestProvider) CallSequenceTestFunc(testFunc CallSequenceTestFunc) CallSequenceTestFunc {
+	return p.testFunc(testFunc)
+}
+
+// SequenceCompletedTestFunc wraps the provided SequenceCompletedTestFunc, so that it is only invoked while the
+// TestProvider is enabled. The wrapped function should be added to FuzzerHooks.SequenceCompletedTestFuncs.
+func (p *TestProvider) SequenceCompletedTestFunc(testFunc SequenceCompletedTestFunc) SequenceCompletedTestFunc {
+	return p.testFunc(testFunc)
+}
+
+// testProviderRegistry tracks the test providers registered with a Fuzzer, by identifier.
+type testProviderRegistry struct {
+	// providers describes the registered test providers, keyed by identifier.
+	providers map[string]*TestProvider
+
+	// lock provides thread-synchronization, as test providers may be listed or toggled while fuzzing.
+	lock sync.Mutex
+}
+
+// newTestProviderRegistry creates a new, empty testProviderRegistry.
+func newTestProviderRegistry() *testProviderRegistry {
+	return &testProviderRegistry{
+		providers: make(map[string]*TestProvider),
+	}
+}
+
+// RegisterTestProvider registers an enabled test provider with the provided identifier, so it can be listed and
+// toggled with TestProviders and SetTestProviderEnabled. The test functions of the test provider should be wrapped
+// with TestProvider.CallSequenceTestFunc or TestProvider.SequenceCompletedTestFunc before being added to the
+// FuzzerHooks. If a test provider with the identifier is already registered, it is returned instead.
+func (f *Fuzzer) RegisterTestProvider(id string) *TestProvider {
+	f.testProviders.lock.Lock()
+	defer f.testProviders.lock.Unlock()
+	if provider, ok := f.testProviders.providers[id]; ok {
+		return provider
+	}
+	provider := &TestProvider{id: id}
+	provider.enabled.Store(true)
+	f.testProviders.providers[id] = provider
+	return provider
+}
+
+// TestProviders returns the test providers registered with the Fuzzer, sorted by identifier.
+func (f *Fuzzer) TestProviders() []*TestProvider {
+	f.testProviders.lock.Lock()
+	defer f.testProviders.lock.Unlock()
+	providers := make([]*TestProvider, 0, len(f.testProviders.providers))
+	for _, provider := range f.testProviders.providers {
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].id < providers[j].id
+	})
+	return providers
+}
+
+// SetTestProviderEnabled enables or mutes the test provider with the provided identifier. This can be called while
+// fuzzing, taking effect for any subsequent calls tested, and for any shrink requests the test provider made which
+// have not yet been honored.
+// Returns an error if no test provider with the identifier is registered.
+func (f *Fuzzer) SetTestProviderEnabled(id string, enabled bool) error {
+	f.testProviders.lock.Lock()
+	provider, ok := f.testProviders.providers[id]
+	f.testProviders.lock.Unlock()
+	if !ok {
+		return fmt.Errorf("could not toggle test provider, no test provider is registered with the identifier '%s'", id)
+	}
+
+	// Only log the toggle if the test provider's state changed.
+	if provider.enabled.Swap(enabled) != enabled {
+		if enabled {
+			f.logger.Info("Unmuted test provider ", colors.Bold, id, colors.Reset)
+		} else {
+			f.logger.Info("Muted test provider ", colors.Bold, id, colors.Reset)
+		}
+	}
+	return nil
+}
+
+// testProviderEnabled returns whether the test provider with the provided identifier is enabled. Unregistered
+// identifiers, such as the empty identifier of shrink requests made by test functions which are not attributed to a
+// test provider, are always considered enabled.
+func (f *Fuzzer) testProviderEnabled(id string) bool {
+	f.testProviders.lock.Lock()
+	provider, ok := f.testProviders.providers[id]
+	f.testProviders.lock.Unlock()
+	return !ok || provider.Enabled()
+}
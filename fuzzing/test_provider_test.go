@@ -0,0 +1,67 @@
+package fuzzing
+
+import (
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTestProviderToggling tests that test providers can be listed and toggled by identifier, that a muted test
+// provider's test functions are not invoked, and that shrink requests are attributed to the test provider which made
+// them.
+func TestTestProviderToggling(t *testing.T) {
+	fuzzer := &Fuzzer{
+		logger:        logging.GlobalLogger.NewSubLogger("module", "fuzzer"),
+		testProviders: newTestProviderRegistry(),
+	}
+
+	// Register two test providers, each with a test function which counts its invocations and requests shrinking.
+	invocations := make(map[string]int)
+	testFuncs := make(map[string]CallSequenceTestFunc)
+	for _, id := range []string{"b", "a"} {
+		id := id
+		testFuncs[id] = fuzzer.RegisterTestProvider(id).CallSequenceTestFunc(func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+			invocations[id]++
+			return []ShrinkCallSequenceRequest{{TestName: id}}, nil
+		})
+	}
+
+	// Registering an existing identifier returns the existing test provider.
+	providers := fuzzer.TestProviders()
+	assert.Len(t, providers, 2)
+	assert.Same(t, providers[0], fuzzer.RegisterTestProvider("a"))
+	assert.EqualValues(t, "a", providers[0].ID())
+	assert.EqualValues(t, "b", providers[1].ID())
+	assert.True(t, providers[0].Enabled())
+	assert.True(t, providers[1].Enabled())
+
+	// Shrink requests should be attributed to the test provider which made them.
+	shrinkRequests, err := testFuncs["a"](nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, shrinkRequests, 1)
+	assert.EqualValues(t, "a", shrinkRequests[0].TestProviderID)
+
+	// Muting a test provider should stop its test functions from being invoked, without affecting others.
+	assert.NoError(t, fuzzer.SetTestProviderEnabled("a", false))
+	assert.False(t, providers[0].Enabled())
+	assert.False(t, fuzzer.testProviderEnabled("a"))
+	shrinkRequests, err = testFuncs["a"](nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, shrinkRequests)
+	shrinkRequests, err = testFuncs["b"](nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, shrinkRequests, 1)
+	assert.EqualValues(t, map[string]int{"a": 1, "b": 1}, invocations)
+
+	// Unmuting a test provider should resume invoking its test functions.
+	assert.NoError(t, fuzzer.SetTestProviderEnabled("a", true))
+	_, err = testFuncs["a"](nil, nil)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, invocations["a"])
+
+	// Unknown identifiers cannot be toggled, and shrink requests not attributed to a test provider are always honored.
+	assert.Error(t, fuzzer.SetTestProviderEnabled("c", false))
+	assert.True(t, fuzzer.testProviderEnabled(""))
+}
@@ -0,0 +1,29 @@
+// This contract is a dependency of TestContract, and fails an assertion for even values.
+contract DependencyContract {
+    function checkValue(uint value) public pure returns (uint) {
+        // ASSERTION: We fail for any even value.
+        assert(value % 2 == 1);
+        return value;
+    }
+}
+
+// This contract ensures assertion testing can be scoped to specific contracts, such that an assertion failure in its own
+// code is reported, while an identical assertion failure propagated from its dependency is not.
+contract TestContract {
+    DependencyContract dependency;
+
+    constructor() {
+        dependency = new DependencyContract();
+    }
+
+    function checkOwnValue(uint value) public pure returns (uint) {
+        // ASSERTION: We fail for any even value.
+        assert(value % 2 == 1);
+        return value;
+    }
+
+    function checkDependencyValue(uint value) public view returns (uint) {
+        // The assertion failure in the dependency propagates, so this call fails too.
+        return dependency.checkValue(value);
+    }
+}
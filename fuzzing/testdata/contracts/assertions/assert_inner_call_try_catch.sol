@@ -0,0 +1,31 @@
+// This contract is called by TestContract, and fails an assertion for even values.
+contract InnerContract {
+    function checkValue(uint value) public pure returns (uint) {
+        // ASSERTION: We fail for any even value.
+        assert(value % 2 == 1);
+        return value;
+    }
+}
+
+// This contract ensures the fuzzer can detect assertion failures in inner calls which are handled by the caller, such
+// that the top-level call never fails itself.
+contract TestContract {
+    InnerContract inner;
+
+    constructor() {
+        inner = new InnerContract();
+    }
+
+    function callInnerWithTryCatch(uint value) public {
+        // The assertion failure in the inner call is caught, so this call succeeds.
+        try inner.checkValue(value) returns (uint) {
+        } catch {
+        }
+    }
+
+    function callInnerWithLowLevelCall(uint value) public {
+        // The assertion failure in the inner call is ignored, so this call succeeds.
+        (bool success, ) = address(inner).call(abi.encodeWithSelector(InnerContract.checkValue.selector, value));
+        success;
+    }
+}
@@ -0,0 +1,14 @@
+// This contract has a view method with an assertion which only fails once the state-changing method has been called
+// several times. View methods are rarely selected when generating call sequences, so this is used to test that view
+// methods are explicitly checked at the configured cadence.
+contract TestContract {
+    uint counter;
+
+    function increment() public {
+        counter++;
+    }
+
+    function checkCounter() public view {
+        assert(counter < 5);
+    }
+}
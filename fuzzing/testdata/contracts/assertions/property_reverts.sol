@@ -0,0 +1,14 @@
+// This contract has a property which never returns false, but overflows, and so reverts rather than returning a result,
+// once its state value is large. This is used to test that property tests which revert when evaluated are handled as
+// configured.
+contract TestContract {
+    uint256 value;
+
+    function setValue(uint256 newValue) public {
+        value = newValue;
+    }
+
+    function property_doubled_value_not_smaller() public view returns (bool) {
+        return value * 2 >= value;
+    }
+}
@@ -0,0 +1,17 @@
+// This contract has a property which can be violated by two distinct methods, so the same failure is discovered through
+// many different call sequences. This is used to test that a failure which is already known is only shrunk once.
+contract TestContract {
+    bool violated;
+
+    function violateFirst() public {
+        violated = true;
+    }
+
+    function violateSecond(uint value) public {
+        violated = value > 0;
+    }
+
+    function property_never_violated() public view returns (bool) {
+        return !violated;
+    }
+}
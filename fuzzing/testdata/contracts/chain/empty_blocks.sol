@@ -0,0 +1,26 @@
+// This contract verifies the fuzzer can mine empty blocks between calls, by requiring several blocks to have been
+// produced between two calls. Blocks skipped by a block number delay are never produced, so their hashes are zero.
+contract TestContract {
+    uint256 lastBlock;
+    bool reached;
+
+    function poke() public {
+        if (lastBlock != 0 && block.number > lastBlock + 4) {
+            bool produced = true;
+            for (uint256 n = lastBlock + 1; n <= lastBlock + 4; n++) {
+                if (blockhash(n) == bytes32(0)) {
+                    produced = false;
+                }
+            }
+            if (produced) {
+                reached = true;
+            }
+        }
+        lastBlock = block.number;
+    }
+
+    function property_no_empty_blocks_between_calls() public view returns (bool) {
+        // ASSERTION: at least four blocks should never be produced between two calls.
+        return !reached;
+    }
+}
@@ -0,0 +1,16 @@
+// This contract verifies the fuzzer can generate the prevrandao value of the blocks it creates, by gating a state
+// change on a property of block.prevrandao which the chain's fixed prevrandao value does not satisfy.
+contract TestContract {
+    bool reached;
+
+    function draw() public {
+        if (uint8(block.prevrandao) == 42) {
+            reached = true;
+        }
+    }
+
+    function property_prevrandao_never_drawn() public view returns (bool) {
+        // ASSERTION: a block with a prevrandao value ending in 42 should never be drawn from.
+        return !reached;
+    }
+}
@@ -0,0 +1,13 @@
+// This contract's coverage is saturated once each of its functions has been called, after which no new coverage can
+// be achieved.
+contract TestContract {
+    uint x;
+
+    function set(uint value) public {
+        x = value;
+    }
+
+    function reset() public {
+        x = 0;
+    }
+}
@@ -0,0 +1,37 @@
+// This proxy delegate calls an implementation address provided by its caller, which can write to its storage.
+contract Proxy {
+    address owner;
+
+    constructor() {
+        owner = msg.sender;
+    }
+
+    function execute(address implementation, uint value) public {
+        (bool success, ) = implementation.delegatecall(abi.encodeWithSignature("set(uint256)", value));
+        require(success);
+    }
+}
+
+// This proxy only delegate calls the implementation it was deployed with.
+contract SafeProxy {
+    address implementation;
+    uint x;
+
+    constructor(address _implementation) {
+        implementation = _implementation;
+    }
+
+    function execute(address recipient, uint value) public {
+        (bool success, ) = implementation.delegatecall(abi.encodeWithSignature("set(uint256)", value));
+        require(success && recipient != address(0));
+    }
+}
+
+// This implementation writes to the first storage slot of the contract which delegate calls it.
+contract Implementation {
+    uint x;
+
+    function set(uint value) public {
+        x = value;
+    }
+}
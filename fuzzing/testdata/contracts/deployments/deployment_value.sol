@@ -0,0 +1,10 @@
+// This contract requires an initial endowment to be deployed.
+contract TestContract {
+    constructor() payable {
+        require(msg.value > 0, "an endowment is required");
+    }
+
+    function property_endowment_received() public view returns (bool) {
+        return address(this).balance == 1 ether;
+    }
+}
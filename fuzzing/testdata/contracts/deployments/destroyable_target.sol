@@ -0,0 +1,33 @@
+// TestContract deploys a Destroyable contract upon construction. Destroyable provides a method which will trigger a
+// selfdestruct, along with methods which modify its state. This is used to test that calls are not generated against a
+// contract after it was destroyed within the same call sequence.
+contract Destroyable {
+    uint x;
+
+    function poke(uint value) public {
+        if (value % 3 == 0) {
+            x += value;
+        } else {
+            x = value;
+        }
+    }
+
+    function destroy() public {
+        selfdestruct(payable(address(0)));
+    }
+}
+
+contract TestContract {
+    Destroyable d;
+    uint y;
+
+    constructor() {
+        d = new Destroyable();
+    }
+
+    function bump(uint value) public {
+        if (value > 100) {
+            y = value;
+        }
+    }
+}
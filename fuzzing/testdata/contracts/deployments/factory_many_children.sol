@@ -0,0 +1,22 @@
+// This contract is deployed many times by its factory.
+contract Child {
+    uint256 x;
+
+    function poke(uint256 value) public {
+        x = value;
+    }
+
+    function bump() public {
+        x++;
+    }
+}
+
+// ChildFactory deploys many identical children each time it is called, to ensure the number of children registered
+// as fuzzing targets remains bounded.
+contract ChildFactory {
+    function deployChildren() public {
+        for (uint256 i = 0; i < 10; i++) {
+            new Child();
+        }
+    }
+}
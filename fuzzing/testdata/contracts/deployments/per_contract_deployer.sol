@@ -0,0 +1,40 @@
+// This contract records its deployer as its owner.
+contract Ownable {
+    address public owner;
+
+    constructor() {
+        owner = msg.sender;
+    }
+}
+
+// This contract is deployed by an admin account.
+contract AdminVault is Ownable {}
+
+// This contract is deployed by an unprivileged account.
+contract UserVault is Ownable {}
+
+// This contract ensures each vault observed its configured deployer as msg.sender.
+contract TestContract {
+    AdminVault adminVault;
+    UserVault userVault;
+    address deployer;
+
+    constructor(address _adminVault, address _userVault) {
+        deployer = msg.sender;
+        adminVault = AdminVault(_adminVault);
+        userVault = UserVault(_userVault);
+    }
+
+    function property_admin_vault_owner() public view returns (bool) {
+        return adminVault.owner() == address(0x40000);
+    }
+
+    function property_user_vault_owner() public view returns (bool) {
+        return userVault.owner() == address(0x50000);
+    }
+
+    function property_default_deployer() public view returns (bool) {
+        // This contract was not given a deployer, so it is deployed by the default deployer.
+        return deployer == address(0x30000);
+    }
+}
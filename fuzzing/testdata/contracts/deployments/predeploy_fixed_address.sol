@@ -0,0 +1,29 @@
+// This contract is predeployed at a hard-coded address with a constructor-initialized supply.
+contract PredeployToken {
+    uint256 public totalSupply;
+
+    constructor(uint256 initialSupply) {
+        totalSupply = initialSupply;
+    }
+
+    function burn(uint256 amount) public {
+        if (amount <= totalSupply) {
+            totalSupply -= amount;
+        }
+    }
+}
+
+// This contract consumes the predeployed contract through its hard-coded address.
+contract TestContract {
+    PredeployToken token = PredeployToken(address(0x1234));
+
+    function burnHalf() public {
+        token.burn(token.totalSupply() / 2 + 1);
+    }
+
+    function property_supply_not_depleted() public view returns (bool) {
+        // The supply was initialized through the predeployed contract's constructor, so this only fails once the
+        // fuzzer has burned all of it through the predeployed address.
+        return token.totalSupply() > 0;
+    }
+}
@@ -0,0 +1,27 @@
+// This contract executes a branch while it is constructed which the fuzzer can never re-trigger, as the branch is only
+// taken before the contract is initialized. This is used to test that coverage achieved only during setup is reported
+// separately from coverage achieved while fuzzing.
+contract TestContract {
+    bool initialized;
+    uint value;
+
+    constructor() {
+        initialize();
+    }
+
+    function initialize() internal {
+        if (!initialized) {
+            initialized = true;
+            value = 42;
+        }
+    }
+
+    function setValue(uint newValue) public {
+        initialize();
+        value = newValue + 1;
+    }
+
+    function property_value_is_not_zero() public view returns (bool) {
+        return value != 0;
+    }
+}
@@ -0,0 +1,53 @@
+// This contract is a simple token with a supply cap which is not enforced correctly.
+contract SimpleToken {
+    uint public constant MAX_SUPPLY = 1_000_000;
+
+    mapping(address => uint) public balanceOf;
+    uint public totalSupply;
+
+    function mint(uint amount) public {
+        // BUG: The supply cap is checked before the amount is added, so it can be exceeded.
+        require(totalSupply <= MAX_SUPPLY);
+        balanceOf[msg.sender] += amount;
+        totalSupply += amount;
+    }
+
+    function transfer(address to, uint amount) public {
+        balanceOf[msg.sender] -= amount;
+        balanceOf[to] += amount;
+    }
+}
+
+// This contract defines properties over a separately deployed SimpleToken, which it references by address.
+contract TokenSpec {
+    SimpleToken token;
+    bool broken;
+
+    constructor(address _token) {
+        token = SimpleToken(_token);
+    }
+
+    function breakSpec() public {
+        // This should never be called, as spec contract methods are not fuzzed directly.
+        broken = true;
+    }
+
+    function assert_balance_within_supply(address holder) public view {
+        // This should never fail, as no holder can own more than the total supply.
+        assert(token.balanceOf(holder) <= token.totalSupply());
+    }
+
+    function property_never_broken() public view returns (bool) {
+        return !broken;
+    }
+
+    function property_token_is_referenced() public view returns (bool) {
+        // This should never fail, as the token's address is provided through the constructor.
+        return address(token).code.length > 0;
+    }
+
+    function property_supply_is_capped() public view returns (bool) {
+        // PROPERTY: The token's total supply should never exceed its cap. This fails once a large amount is minted.
+        return token.totalSupply() <= token.MAX_SUPPLY();
+    }
+}
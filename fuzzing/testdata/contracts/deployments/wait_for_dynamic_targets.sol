@@ -0,0 +1,15 @@
+// Child is deployed by its factory, and fails an assertion when its method is called.
+contract Child {
+    function fail() public {
+        // ASSERTION: Fail immediately.
+        assert(false);
+    }
+}
+
+// ChildFactory deploys a Child each time it is called, to ensure workers waiting for dynamically deployed targets
+// deploy them by testing corpus call sequences which call the factory, even when it is excluded from fuzzing.
+contract ChildFactory {
+    function deployChild() public returns (address) {
+        return address(new Child());
+    }
+}
@@ -0,0 +1,17 @@
+// This test ensures the execution traces of deeply recursive calls are truncated according to the trace limits.
+contract TestContract {
+    event Recursed(uint depth);
+
+    function recurse(uint depth) public {
+        emit Recursed(depth);
+        if (depth > 0) {
+            this.recurse(depth - 1);
+        }
+    }
+
+    function deepRecursion() public {
+        // ASSERTION: a deeply recursive call should never complete
+        this.recurse(200);
+        assert(false);
+    }
+}
@@ -0,0 +1,23 @@
+// This contract is used to test attaching execution traces to specific elements of an arbitrary call sequence. Each
+// call to TestContract.setX makes an inner call to InnerContract.setY.
+contract InnerContract {
+    uint y;
+
+    function setY(uint value) public {
+        y = value;
+    }
+}
+
+contract TestContract {
+    InnerContract inner;
+    uint x;
+
+    constructor() {
+        inner = new InnerContract();
+    }
+
+    function setX(uint value) public {
+        x = value;
+        inner.setY(value * 2);
+    }
+}
@@ -0,0 +1,29 @@
+// This contract provides a method with multiple branches for the fuzzer to cover, alongside an assertion and property
+// which always fail. This is used to test that exploration mode builds a corpus without evaluating any tests.
+contract TestContract {
+    uint branchesCovered;
+
+    function coverBranches(uint value) public {
+        if (value % 2 == 0) {
+            branchesCovered |= 1;
+        } else {
+            branchesCovered |= 2;
+        }
+
+        if (value > 1000) {
+            branchesCovered |= 4;
+        } else if (value < 10) {
+            branchesCovered |= 8;
+        } else {
+            branchesCovered |= 16;
+        }
+    }
+
+    function failAssertion() public {
+        assert(false);
+    }
+
+    function property_always_fails() public view returns (bool) {
+        return false;
+    }
+}
@@ -0,0 +1,32 @@
+// This contract is a mock helper which is deployed, but should never be called directly by the fuzzer.
+contract MockOracle {
+    uint256 public price = 100;
+
+    function setPrice(uint256 newPrice) public {
+        price = newPrice;
+    }
+
+    function property_never_tested() public view returns (bool) {
+        // This property would fail immediately if it were registered as a test.
+        return false;
+    }
+}
+
+// This contract interacts with the excluded mock helper and is fuzzed as usual.
+contract TestContract {
+    MockOracle oracle;
+    uint256 lastPrice;
+
+    constructor(address _oracle) {
+        oracle = MockOracle(_oracle);
+    }
+
+    function updatePrice(uint256 newPrice) public {
+        oracle.setPrice(newPrice);
+        lastPrice = oracle.price();
+    }
+
+    function property_price_consistent() public view returns (bool) {
+        return lastPrice == 0 || lastPrice == oracle.price();
+    }
+}
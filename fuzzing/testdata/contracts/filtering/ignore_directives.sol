@@ -0,0 +1,21 @@
+// This contract ensures that methods annotated to be ignored are never called directly, but remain callable by other
+// methods.
+contract TestContract {
+    uint256 value;
+
+    /// @notice Sets the value, which may never be seven.
+    /// @custom:medusa ignore
+    function setValue(uint256 newValue) public {
+        value = newValue;
+        assert(newValue != 7);
+    }
+
+    function callSetValue(uint256 newValue) public {
+        setValue(newValue);
+    }
+
+    /// @custom:medusa ignore unknown
+    function reset() public {
+        value = 0;
+    }
+}
@@ -0,0 +1,23 @@
+// This contract ensures that annotating methods as targets restricts the methods called directly to them.
+contract TestContract {
+    uint256 odd_counter = 1;
+    uint256 even_counter = 2;
+
+    /// @custom:medusa target
+    function f() public {
+        odd_counter += 1;
+    }
+
+    function g() public {
+        even_counter += 2;
+    }
+
+    /// @custom:medusa target
+    function h() public {
+        odd_counter += 3;
+    }
+
+    function property_a() public view returns (bool) {
+        return (odd_counter != 100);
+    }
+}
@@ -0,0 +1,17 @@
+// This contract provides a counter which can be incremented or reset, along with a property test which checks that it
+// is below three. This is used to test property tests which are only checked once a call sequence has completed.
+contract TestContract {
+    uint counter;
+
+    function increment() public {
+        counter++;
+    }
+
+    function reset() public {
+        counter = 0;
+    }
+
+    function property_counter_below_three() public view returns (bool) {
+        return counter < 3;
+    }
+}
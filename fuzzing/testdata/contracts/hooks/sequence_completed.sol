@@ -0,0 +1,13 @@
+// This contract provides a counter which can be incremented or reset. This is used to test hooks which check an
+// invariant only once a call sequence has completed (e.g. "the counter is below three at the end of any sequence").
+contract TestContract {
+    uint counter;
+
+    function increment() public {
+        counter++;
+    }
+
+    function reset() public {
+        counter = 0;
+    }
+}
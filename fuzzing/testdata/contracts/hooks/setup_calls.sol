@@ -0,0 +1,14 @@
+// This contract provides a value which is configured once, alongside a counter which can be incremented. This is used
+// to test that call sequence elements marked as setup calls are retained when shrinking, while other calls are removed.
+contract TestContract {
+    uint value;
+    uint counter;
+
+    function setValue(uint x) public {
+        value = x;
+    }
+
+    function increment(uint x) public {
+        counter += x;
+    }
+}
@@ -0,0 +1,16 @@
+// This contract was never initialized, so every call to it reverts. This is used to test that campaigns in which every
+// call reverts are detected as stuck.
+contract TestContract {
+    bool initialized;
+    uint value;
+
+    function deposit(uint amount) public {
+        require(initialized, "not initialized");
+        value += amount;
+    }
+
+    function withdraw() public {
+        require(initialized, "not initialized");
+        value = 0;
+    }
+}
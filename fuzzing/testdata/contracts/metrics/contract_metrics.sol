@@ -0,0 +1,30 @@
+// This contract is a helper which is deployed, but excluded from being called directly by the fuzzer.
+contract HelperContract {
+    uint256 value;
+
+    function setValue(uint256 newValue) public {
+        value = newValue;
+    }
+}
+
+// This contract calls into the excluded helper and is fuzzed as usual.
+contract TestContract {
+    HelperContract helper;
+    uint256 x;
+    uint256 y;
+
+    constructor(address _helper) {
+        helper = HelperContract(_helper);
+    }
+
+    function setX(uint256 value) public {
+        x = value;
+        helper.setValue(value);
+    }
+
+    function setY(uint256 value) public {
+        if (value % 2 == 0) {
+            y = value;
+        }
+    }
+}
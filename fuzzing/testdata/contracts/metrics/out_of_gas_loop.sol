@@ -0,0 +1,15 @@
+// This contract has a method which loops until it runs out of gas, and one which always succeeds. This is used to test
+// that lines only executed in calls which ran out of gas are classified apart from successful and reverted lines.
+contract TestContract {
+    uint total;
+
+    function exhaust() public {
+        for (uint i = 0; i < 1_000_000; i++) {
+            total += i;
+        }
+    }
+
+    function increment() public {
+        total += 1;
+    }
+}
@@ -0,0 +1,37 @@
+// This contract provides methods which always fail, each with a different class of failure. This is used to test
+// that failed calls are classified and aggregated correctly.
+contract TestContract {
+    error CustomError(uint value);
+
+    uint zero;
+    uint counter;
+
+    function failRevert() public {
+        revert();
+    }
+
+    function failRequire() public {
+        require(zero > 0, "zero must be positive");
+    }
+
+    function failCustomError(uint value) public {
+        revert CustomError(value);
+    }
+
+    function failPanic() public {
+        // This will underflow, triggering a Panic(0x11).
+        counter = zero - 1;
+    }
+
+    function failOutOfGas() public {
+        while (true) {
+            counter++;
+        }
+    }
+
+    function failInvalidOpcode() public {
+        assembly {
+            invalid()
+        }
+    }
+}
@@ -0,0 +1,22 @@
+// This contract has a method which performs many storage writes, making it far slower to execute than the others.
+// This is used to test that the slowest call sequences are tracked along with the method which used the most gas.
+contract TestContract {
+    mapping(uint => uint) values;
+    uint total;
+
+    function slowWrite(uint seed) public {
+        for (uint i = 0; i < 300; i++) {
+            values[i] = seed + i;
+        }
+    }
+
+    function increment() public {
+        total += 1;
+    }
+
+    function decrement() public {
+        if (total > 0) {
+            total -= 1;
+        }
+    }
+}
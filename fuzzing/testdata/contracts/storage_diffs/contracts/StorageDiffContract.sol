@@ -0,0 +1,22 @@
+// This contract is used to test that failure reports describe the storage changes made by the shrunken call sequence.
+contract StorageDiffContract {
+    address owner;
+    uint256 counter;
+
+    constructor() {
+        owner = address(0x1);
+    }
+
+    function takeOwnership() public {
+        owner = msg.sender;
+    }
+
+    function increment() public {
+        counter++;
+    }
+
+    function property_owner_unchanged_or_unused() public view returns (bool) {
+        // PROPERTY: the owner should never change once the counter is incremented.
+        return owner == address(0x1) || counter == 0;
+    }
+}
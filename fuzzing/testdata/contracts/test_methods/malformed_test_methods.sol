@@ -0,0 +1,37 @@
+// This contract contains methods named like property and optimization tests which do not have the shape of one, to
+// verify they are reported when the fuzzer starts.
+contract TestContract {
+    uint256 x;
+
+    function setX(uint256 value) public {
+        x = value;
+    }
+
+    function property_valid() public view returns (bool) {
+        return x != 7;
+    }
+
+    function property_no_return() public {
+        x = 1;
+    }
+
+    function property_returns_uint() public view returns (uint256) {
+        return x;
+    }
+
+    function property_takes_argument(uint256 y) public view returns (bool) {
+        return x != y;
+    }
+
+    function property_returns_two_bools() public view returns (bool, bool) {
+        return (true, x != 7);
+    }
+
+    function optimize_valid() public view returns (int256) {
+        return int256(x);
+    }
+
+    function optimize_returns_uint() public view returns (uint256) {
+        return x;
+    }
+}
@@ -0,0 +1,89 @@
+// This token mints to anyone who asks, burns from its holders, and transfers balances correctly.
+contract ConformingToken {
+    event Transfer(address indexed from, address indexed to, uint256 value);
+
+    mapping(address => uint256) public balanceOf;
+    uint256 public totalSupply;
+
+    constructor() {
+        mint(1_000_000);
+    }
+
+    function mint(uint256 amount) public {
+        require(amount <= 1_000_000);
+        balanceOf[msg.sender] += amount;
+        totalSupply += amount;
+        emit Transfer(address(0), msg.sender, amount);
+    }
+
+    function burn(uint256 amount) public {
+        balanceOf[msg.sender] -= amount;
+        totalSupply -= amount;
+        emit Transfer(msg.sender, address(0), amount);
+    }
+
+    function transfer(address to, uint256 amount) public returns (bool) {
+        balanceOf[msg.sender] -= amount;
+        balanceOf[to] += amount;
+        emit Transfer(msg.sender, to, amount);
+        return true;
+    }
+}
+
+// This token burns a 1% fee from the value of each transfer without emitting a Transfer event for it, which is only
+// consistent if fees are tolerated.
+contract FeeToken {
+    event Transfer(address indexed from, address indexed to, uint256 value);
+
+    mapping(address => uint256) public balanceOf;
+    uint256 public totalSupply;
+
+    constructor() {
+        mint(1_000_000);
+    }
+
+    function mint(uint256 amount) public {
+        require(amount <= 1_000_000);
+        balanceOf[msg.sender] += amount;
+        totalSupply += amount;
+        emit Transfer(address(0), msg.sender, amount);
+    }
+
+    function transfer(address to, uint256 amount) public returns (bool) {
+        uint256 fee = amount / 100;
+        balanceOf[msg.sender] -= amount;
+        balanceOf[to] += amount - fee;
+        totalSupply -= fee;
+        emit Transfer(msg.sender, to, amount);
+        return true;
+    }
+}
+
+// This token caches balances before updating them when transferring, so transferring to yourself mints tokens.
+contract BuggyToken {
+    event Transfer(address indexed from, address indexed to, uint256 value);
+
+    mapping(address => uint256) public balanceOf;
+    uint256 public totalSupply;
+
+    constructor() {
+        mint(1_000_000);
+    }
+
+    function mint(uint256 amount) public {
+        require(amount <= 1_000_000);
+        balanceOf[msg.sender] += amount;
+        totalSupply += amount;
+        emit Transfer(address(0), msg.sender, amount);
+    }
+
+    function transfer(address to, uint256 amount) public returns (bool) {
+        uint256 fromBalance = balanceOf[msg.sender];
+        uint256 toBalance = balanceOf[to];
+        require(fromBalance >= amount);
+        balanceOf[msg.sender] = fromBalance - amount;
+        balanceOf[to] = toBalance + amount;
+        emit Transfer(msg.sender, to, amount);
+        return true;
+    }
+}
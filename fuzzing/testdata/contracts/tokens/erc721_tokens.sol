@@ -0,0 +1,70 @@
+// This token mints any unowned token to its caller, and lets owners transfer and burn their tokens.
+contract ConformingNFT {
+    event Transfer(address indexed from, address indexed to, uint256 indexed tokenId);
+
+    mapping(uint256 => address) owners;
+    mapping(address => uint256) public balanceOf;
+
+    function ownerOf(uint256 tokenId) public view returns (address) {
+        address owner = owners[tokenId];
+        require(owner != address(0));
+        return owner;
+    }
+
+    function mint(uint256 tokenId) public {
+        require(owners[tokenId] == address(0));
+        owners[tokenId] = msg.sender;
+        balanceOf[msg.sender] += 1;
+        emit Transfer(address(0), msg.sender, tokenId);
+    }
+
+    function transfer(address to, uint256 tokenId) public {
+        require(owners[tokenId] == msg.sender && to != address(0));
+        owners[tokenId] = to;
+        balanceOf[msg.sender] -= 1;
+        balanceOf[to] += 1;
+        emit Transfer(msg.sender, to, tokenId);
+    }
+
+    function burn(uint256 tokenId) public {
+        require(owners[tokenId] == msg.sender);
+        delete owners[tokenId];
+        balanceOf[msg.sender] -= 1;
+        emit Transfer(msg.sender, address(0), tokenId);
+    }
+}
+
+// This token does not clear the owner of burned tokens, and does not decrease the balance of the sender when
+// transferring.
+contract BuggyNFT {
+    event Transfer(address indexed from, address indexed to, uint256 indexed tokenId);
+
+    mapping(uint256 => address) owners;
+    mapping(address => uint256) public balanceOf;
+
+    function ownerOf(uint256 tokenId) public view returns (address) {
+        address owner = owners[tokenId];
+        require(owner != address(0));
+        return owner;
+    }
+
+    function mint(uint256 tokenId) public {
+        require(owners[tokenId] == address(0));
+        owners[tokenId] = msg.sender;
+        balanceOf[msg.sender] += 1;
+        emit Transfer(address(0), msg.sender, tokenId);
+    }
+
+    function transfer(address to, uint256 tokenId) public {
+        require(owners[tokenId] == msg.sender && to != address(0));
+        owners[tokenId] = to;
+        balanceOf[to] += 1;
+        emit Transfer(msg.sender, to, tokenId);
+    }
+
+    function burn(uint256 tokenId) public {
+        require(owners[tokenId] == msg.sender);
+        balanceOf[msg.sender] -= 1;
+        emit Transfer(msg.sender, address(0), tokenId);
+    }
+}
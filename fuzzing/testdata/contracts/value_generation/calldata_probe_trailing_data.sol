@@ -0,0 +1,18 @@
+// This contract verifies the fuzzer can malform call data, by reading a trailing word past the declared arguments with
+// inline assembly, which is only non-zero if the call data was extended beyond its clean ABI encoding.
+contract TestContract {
+    uint256 trailing;
+
+    function setLimit(uint256 limit) public {
+        uint256 extra;
+        assembly {
+            extra := calldataload(36)
+        }
+        trailing = extra;
+    }
+
+    function property_no_trailing_data() public view returns (bool) {
+        // ASSERTION: call data should never carry data past its declared arguments.
+        return trailing == 0;
+    }
+}
@@ -0,0 +1,31 @@
+// This contract forwards arbitrary calls to a target, as multicalls and governance executors do.
+contract Executor {
+    function execute(address target, bytes calldata data) public returns (bytes memory) {
+        (bool success, bytes memory result) = target.call(data);
+        require(success);
+        return result;
+    }
+}
+
+// This contract verifies the fuzzer can reach its methods through the executor, by generating the executor's call
+// target and call data together.
+contract TestContract {
+    address executor;
+    bool reached;
+
+    constructor(address _executor) {
+        executor = _executor;
+    }
+
+    function reach(uint256 value) public {
+        // Only calls forwarded by the executor count.
+        if (msg.sender == executor) {
+            reached = true;
+        }
+    }
+
+    function property_never_reached_through_executor() public view returns (bool) {
+        // ASSERTION: reach should never be called through the executor.
+        return !reached;
+    }
+}
@@ -0,0 +1,26 @@
+// This contract verifies the fuzzer can generate arguments relative to the chain's current time, by requiring an
+// argument to fall within an hour after the current block timestamp.
+interface CheatCodes {
+    function warp(uint256) external;
+}
+
+contract TestContract {
+    bool reached;
+
+    constructor() {
+        // Move the chain to a timestamp which does not appear as a literal in the source, so it cannot be mined from it.
+        CheatCodes cheats = CheatCodes(0x7109709ECfa91a80626fF3989D68f67F5b1DD12D);
+        cheats.warp(block.timestamp + uint256(keccak256("timestamp")) % 1e12);
+    }
+
+    function submit(uint256 t) public {
+        require(t > block.timestamp, "expired");
+        require(t - block.timestamp <= 1 hours, "too late");
+        reached = true;
+    }
+
+    function property_never_submitted() public view returns (bool) {
+        // ASSERTION: an argument within an hour of the current block timestamp should never be submitted.
+        return !reached;
+    }
+}
@@ -0,0 +1,21 @@
+// This contract verifies the fuzzer can generate external function references to deployed contract methods, by
+// requiring a method to be reached through a callback it invokes itself.
+contract TestContract {
+    bool reached;
+
+    function invoke(function(uint256) external callback, uint256 value) public {
+        callback(value);
+    }
+
+    function reach(uint256 value) public {
+        // Only calls made through a callback count.
+        if (msg.sender == address(this)) {
+            reached = true;
+        }
+    }
+
+    function property_never_reached_through_callback() public view returns (bool) {
+        // ASSERTION: reach should never be called through a callback.
+        return !reached;
+    }
+}
@@ -0,0 +1,16 @@
+// This contract verifies the fuzzer can provide an argument which is exactly one greater than another, as commonly
+// required to pass bounds checks which are off by one.
+contract TestContract {
+    bool matched;
+
+    function setXY(uint x, uint y) public {
+        if (x > 1000000 && x == y + 1) {
+            matched = true;
+        }
+    }
+
+    function property_never_off_by_one() public view returns (bool) {
+        // ASSERTION: x should never be exactly one greater than y
+        return !matched;
+    }
+}
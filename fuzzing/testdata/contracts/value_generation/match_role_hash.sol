@@ -0,0 +1,23 @@
+// This contract verifies the fuzzer can generate bytes32 arguments which are hashes of constants in the source, by
+// requiring an AccessControl-style role identifier to be provided to reach the violating path.
+contract TestContract {
+    bytes32 public constant TREASURER_ROLE = keccak256("TREASURER_ROLE");
+
+    mapping(bytes32 => mapping(address => bool)) roles;
+    bool withdrawn;
+
+    function grantRole(bytes32 role, address account) public {
+        require(role == TREASURER_ROLE, "unknown role");
+        roles[role][account] = true;
+    }
+
+    function withdraw() public {
+        require(roles[TREASURER_ROLE][msg.sender], "missing role");
+        withdrawn = true;
+    }
+
+    function property_never_withdrawn() public view returns (bool) {
+        // ASSERTION: only a caller granted the treasurer role can withdraw, which should never happen.
+        return !withdrawn;
+    }
+}
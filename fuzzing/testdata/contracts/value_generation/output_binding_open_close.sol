@@ -0,0 +1,25 @@
+// This contract verifies the fuzzer can bind an argument of a call to the output of the call prior to it, by only
+// allowing a position to be closed with the unpredictable identifier returned when it was opened.
+contract TestContract {
+    mapping(uint256 => bool) open;
+    uint256 openedCount;
+    bool closedPosition;
+
+    function openPosition() public returns (uint256) {
+        uint256 id = uint256(keccak256(abi.encode(openedCount++, block.timestamp, msg.sender)));
+        open[id] = true;
+        return id;
+    }
+
+    function closePosition(uint256 id) public {
+        if (open[id]) {
+            open[id] = false;
+            closedPosition = true;
+        }
+    }
+
+    function property_never_closed() public view returns (bool) {
+        // ASSERTION: a position should never be closed, which requires its valid identifier.
+        return !closedPosition;
+    }
+}
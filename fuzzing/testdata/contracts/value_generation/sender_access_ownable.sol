@@ -0,0 +1,34 @@
+// This contract restricts some of its methods to its owner, who deployed it, so the fuzzer should learn to send most
+// calls to them from the owner.
+contract TestContract {
+    error OwnableUnauthorizedAccount(address account);
+
+    address owner;
+    uint256 value;
+    uint256 otherValue;
+    uint256 publicValue;
+
+    constructor() {
+        owner = msg.sender;
+    }
+
+    modifier onlyOwner() {
+        require(msg.sender == owner, "Ownable: caller is not the owner");
+        _;
+    }
+
+    function setValue(uint256 newValue) public onlyOwner {
+        value = newValue;
+    }
+
+    function setOtherValue(uint256 newValue) public {
+        if (msg.sender != owner) {
+            revert OwnableUnauthorizedAccount(msg.sender);
+        }
+        otherValue = newValue;
+    }
+
+    function setPublicValue(uint256 newValue) public {
+        publicValue = newValue;
+    }
+}
@@ -0,0 +1,61 @@
+package fuzzing
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/exp/slices"
+)
+
+// unmatchedDeploymentLimit describes the maximum count of distinct init bytecode hashes an unmatchedDeploymentTracker
+// records, so that the warning listing them remains readable.
+const unmatchedDeploymentLimit = 5
+
+// unmatchedDeploymentTracker tracks the contract deployments which every FuzzerWorker failed to match to a contract
+// definition during a fuzzing campaign, so users can diagnose which contracts are not being matched.
+type unmatchedDeploymentTracker struct {
+	// deploymentCount describes the count of deployments which could not be matched.
+	deploymentCount uint64
+
+	// initBytecodeHashes describes the distinct hashes of the init bytecode of the first deployments which could not
+	// be matched, up to unmatchedDeploymentLimit.
+	initBytecodeHashes []common.Hash
+
+	// lock provides thread-synchronization, as unmatched deployments are recorded by every FuzzerWorker.
+	lock sync.Mutex
+}
+
+// newUnmatchedDeploymentTracker creates a new, empty unmatchedDeploymentTracker.
+func newUnmatchedDeploymentTracker() *unmatchedDeploymentTracker {
+	return &unmatchedDeploymentTracker{
+		initBytecodeHashes: make([]common.Hash, 0),
+	}
+}
+
+// record records a deployment with the provided init bytecode which could not be matched to a contract definition.
+func (t *unmatchedDeploymentTracker) record(initBytecode []byte) {
+	initBytecodeHash := crypto.Keccak256Hash(initBytecode)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.deploymentCount++
+	if len(t.initBytecodeHashes) < unmatchedDeploymentLimit && !slices.Contains(t.initBytecodeHashes, initBytecodeHash) {
+		t.initBytecodeHashes = append(t.initBytecodeHashes, initBytecodeHash)
+	}
+}
+
+// count returns the count of deployments which could not be matched to a contract definition.
+func (t *unmatchedDeploymentTracker) count() uint64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.deploymentCount
+}
+
+// hashes returns the distinct hashes of the init bytecode of the first deployments which could not be matched to a
+// contract definition.
+func (t *unmatchedDeploymentTracker) hashes() []common.Hash {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return slices.Clone(t.initBytecodeHashes)
+}
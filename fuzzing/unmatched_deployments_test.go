@@ -0,0 +1,29 @@
+package fuzzing
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUnmatchedDeploymentTracker tests that every unmatched deployment is counted, while only the distinct init
+// bytecode hashes of the first unmatched deployments are recorded.
+func TestUnmatchedDeploymentTracker(t *testing.T) {
+	tracker := newUnmatchedDeploymentTracker()
+	assert.EqualValues(t, 0, tracker.count())
+	assert.Empty(t, tracker.hashes())
+
+	// Record the same deployment twice, followed by more distinct deployments than are recorded.
+	tracker.record([]byte{0x00})
+	tracker.record([]byte{0x00})
+	for i := 1; i <= unmatchedDeploymentLimit; i++ {
+		tracker.record([]byte{byte(i)})
+	}
+
+	assert.EqualValues(t, unmatchedDeploymentLimit+2, tracker.count())
+	hashes := tracker.hashes()
+	assert.Len(t, hashes, unmatchedDeploymentLimit)
+	assert.Equal(t, crypto.Keccak256Hash([]byte{0x00}), hashes[0])
+	assert.Equal(t, crypto.Keccak256Hash([]byte{0x01}), hashes[1])
+}
@@ -1,10 +1,13 @@
 package utils
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 
 	compilationTypes "github.com/crytic/medusa/compilation/types"
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"golang.org/x/exp/maps"
 )
 
 // IsOptimizationTest checks whether the method is an optimization test given potential naming prefixes it must conform to
@@ -37,9 +40,71 @@ func IsPropertyTest(method abi.Method, prefixes []string) bool {
 	return false
 }
 
+// hasTestPrefix checks whether the method's name starts with any of the provided prefixes.
+func hasTestPrefix(method abi.Method, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(method.Name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeOutputs returns a displayable description of the provided method outputs, e.g. "(uint256,bool)", or
+// "nothing" if there are none.
+func describeOutputs(outputs abi.Arguments) string {
+	if len(outputs) == 0 {
+		return "nothing"
+	}
+	types := make([]string, len(outputs))
+	for i, output := range outputs {
+		types[i] = output.Type.String()
+	}
+	return fmt.Sprintf("(%s)", strings.Join(types, ","))
+}
+
+// ValidatePropertyTestMethod checks whether a method whose name matches one of the provided property test prefixes
+// has the shape of a property test: it must take no arguments and return exactly one bool. Property tests may be
+// view or state-changing, as any state changes they make are discarded.
+// Returns an error describing the requirement the method violates, or nil if it does not match a prefix or has the
+// expected shape.
+func ValidatePropertyTestMethod(method abi.Method, prefixes []string) error {
+	if !hasTestPrefix(method, prefixes) {
+		return nil
+	}
+	if len(method.Inputs) > 0 {
+		return fmt.Errorf("property tests must take no arguments, but it takes %d", len(method.Inputs))
+	}
+	if len(method.Outputs) != 1 || method.Outputs[0].Type.T != abi.BoolTy {
+		return fmt.Errorf("property tests must return exactly one bool, but it returns %s", describeOutputs(method.Outputs))
+	}
+	return nil
+}
+
+// ValidateOptimizationTestMethod checks whether a method whose name matches one of the provided optimization test
+// prefixes has the shape of an optimization test: it must take no arguments and return exactly one int256.
+// Returns an error describing the requirement the method violates, or nil if it does not match a prefix or has the
+// expected shape.
+func ValidateOptimizationTestMethod(method abi.Method, prefixes []string) error {
+	if !hasTestPrefix(method, prefixes) {
+		return nil
+	}
+	if len(method.Inputs) > 0 {
+		return fmt.Errorf("optimization tests must take no arguments, but it takes %d", len(method.Inputs))
+	}
+	if len(method.Outputs) != 1 || method.Outputs[0].Type.T != abi.IntTy || method.Outputs[0].Type.Size != 256 {
+		return fmt.Errorf("optimization tests must return exactly one int256, but it returns %s", describeOutputs(method.Outputs))
+	}
+	return nil
+}
+
 // BinTestByType sorts a contract's methods by whether they are assertion, property, or optimization tests.
 func BinTestByType(contract *compilationTypes.CompiledContract, propertyTestPrefixes, optimizationTestPrefixes []string, testViewMethods bool) (assertionTests, propertyTests, optimizationTests []abi.Method) {
-	for _, method := range contract.Abi.Methods {
+	// Iterate over methods in a sorted order, so the resulting lists are reproducible.
+	methodNames := maps.Keys(contract.Abi.Methods)
+	slices.Sort(methodNames)
+	for _, methodName := range methodNames {
+		method := contract.Abi.Methods[methodName]
 		if IsPropertyTest(method, propertyTestPrefixes) {
 			propertyTests = append(propertyTests, method)
 		} else if IsOptimizationTest(method, optimizationTestPrefixes) {
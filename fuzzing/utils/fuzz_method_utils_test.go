@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateTestMethods tests that methods named like property or optimization tests are validated to take no
+// arguments and return exactly one value of the expected type, and that other methods are ignored.
+func TestValidateTestMethods(t *testing.T) {
+	parsedAbi, err := abi.JSON(strings.NewReader(`[
+		{"type": "function", "name": "property_valid", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "bool"}]},
+		{"type": "function", "name": "property_state_changing", "stateMutability": "nonpayable", "inputs": [], "outputs": [{"name": "", "type": "bool"}]},
+		{"type": "function", "name": "property_no_return", "stateMutability": "nonpayable", "inputs": [], "outputs": []},
+		{"type": "function", "name": "property_returns_uint", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "uint256"}]},
+		{"type": "function", "name": "property_takes_argument", "stateMutability": "view", "inputs": [{"name": "y", "type": "uint256"}], "outputs": [{"name": "", "type": "bool"}]},
+		{"type": "function", "name": "property_returns_two_bools", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "bool"}, {"name": "", "type": "bool"}]},
+		{"type": "function", "name": "optimize_valid", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "int256"}]},
+		{"type": "function", "name": "optimize_returns_int128", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "int128"}]},
+		{"type": "function", "name": "optimize_takes_argument", "stateMutability": "view", "inputs": [{"name": "y", "type": "int256"}], "outputs": [{"name": "", "type": "int256"}]},
+		{"type": "function", "name": "setX", "stateMutability": "nonpayable", "inputs": [{"name": "x", "type": "uint256"}], "outputs": []}
+	]`))
+	assert.NoError(t, err)
+
+	propertyPrefixes := []string{"property_"}
+	optimizationPrefixes := []string{"optimize_"}
+	cases := []struct {
+		method                string
+		propertyViolation     string
+		optimizationViolation string
+	}{
+		{method: "property_valid"},
+		{method: "property_state_changing"},
+		{method: "property_no_return", propertyViolation: "property tests must return exactly one bool, but it returns nothing"},
+		{method: "property_returns_uint", propertyViolation: "property tests must return exactly one bool, but it returns (uint256)"},
+		{method: "property_takes_argument", propertyViolation: "property tests must take no arguments, but it takes 1"},
+		{method: "property_returns_two_bools", propertyViolation: "property tests must return exactly one bool, but it returns (bool,bool)"},
+		{method: "optimize_valid"},
+		{method: "optimize_returns_int128", optimizationViolation: "optimization tests must return exactly one int256, but it returns (int128)"},
+		{method: "optimize_takes_argument", optimizationViolation: "optimization tests must take no arguments, but it takes 1"},
+		{method: "setX"},
+	}
+	for _, c := range cases {
+		method := parsedAbi.Methods[c.method]
+
+		err := ValidatePropertyTestMethod(method, propertyPrefixes)
+		if c.propertyViolation == "" {
+			assert.NoError(t, err, c.method)
+		} else {
+			assert.EqualError(t, err, c.propertyViolation, c.method)
+		}
+
+		err = ValidateOptimizationTestMethod(method, optimizationPrefixes)
+		if c.optimizationViolation == "" {
+			assert.NoError(t, err, c.method)
+		} else {
+			assert.EqualError(t, err, c.optimizationViolation, c.method)
+		}
+
+		// Valid methods must be binned as tests of their type, while malformed ones never are.
+		assert.Equal(t, c.propertyViolation == "" && strings.HasPrefix(c.method, "property_"), IsPropertyTest(method, propertyPrefixes), c.method)
+		assert.Equal(t, c.optimizationViolation == "" && strings.HasPrefix(c.method, "optimize_"), IsOptimizationTest(method, optimizationPrefixes), c.method)
+	}
+}
@@ -66,6 +66,9 @@ func GenerateAbiValue(generator ValueGenerator, inputType *abi.Type) any {
 			array.Index(i).Set(bytes.Index(i))
 		}
 		return array.Interface()
+	case abi.FunctionTy:
+		// External function references are encoded as the address of a contract followed by a function selector.
+		return generator.GenerateFunction()
 	case abi.ArrayTy:
 		// Read notes for fixed bytes to understand the need to create this array through reflection.
 		array := reflect.Indirect(reflect.New(inputType.GetType()))
@@ -92,10 +95,11 @@ func GenerateAbiValue(generator ValueGenerator, inputType *abi.Type) any {
 		}
 		return st.Interface()
 	default:
-		// Unexpected types will result in a panic as we should support these values as soon as possible:
+		// Unexpected types will result in a panic. Methods taking arguments of these types are excluded from fuzzing
+		// at startup (see IsSupportedAbiType), so this should never be reached:
 		// - Mappings cannot be used in public/external methods and must reference storage, so we shouldn't ever
 		//	 see cases of it unless Solidity was updated in the future.
-		// - FixedPoint types are currently unsupported.
+		// - FixedPoint types are parsed as integers of the same bit width, as they share an encoding.
 
 		err := fmt.Errorf("attempt to generate function argument of unsupported type: '%s'", inputType.String())
 		logging.GlobalLogger.Panic("Failed to generate abi value", err)
@@ -103,9 +107,119 @@ func GenerateAbiValue(generator ValueGenerator, inputType *abi.Type) any {
 	}
 }
 
+// IsSupportedAbiType indicates whether values of the provided abi.Type can be generated and mutated. Methods taking
+// arguments of unsupported types cannot be fuzzed.
+func IsSupportedAbiType(inputType *abi.Type) bool {
+	switch inputType.T {
+	case abi.AddressTy, abi.UintTy, abi.IntTy, abi.BoolTy, abi.StringTy, abi.BytesTy, abi.FixedBytesTy, abi.FunctionTy:
+		return true
+	case abi.ArrayTy, abi.SliceTy:
+		return IsSupportedAbiType(inputType.Elem)
+	case abi.TupleTy:
+		for _, elem := range inputType.TupleElems {
+			if !IsSupportedAbiType(elem) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// isCompositeAbiType indicates whether the provided abi.Type is composed of other values (a tuple or array), rather
+// than being a leaf value.
+func isCompositeAbiType(inputType *abi.Type) bool {
+	return inputType.T == abi.ArrayTy || inputType.T == abi.SliceTy || inputType.T == abi.TupleTy
+}
+
+// countAbiValueLeaves counts the leaf values (values which are not tuples or arrays) within the provided ABI packable
+// value of the provided abi.Type, recursing into tuple components and array elements.
+func countAbiValueLeaves(inputType *abi.Type, value any) int {
+	switch inputType.T {
+	case abi.ArrayTy, abi.SliceTy:
+		count := 0
+		array := reflect.ValueOf(value)
+		for i := 0; i < array.Len(); i++ {
+			count += countAbiValueLeaves(inputType.Elem, array.Index(i).Interface())
+		}
+		return count
+	case abi.TupleTy:
+		// Note: We create a copy, as fields of existing tuples may not be addressable.
+		count := 0
+		tuple := reflectionutils.CopyReflectedType(reflect.ValueOf(value))
+		for i := 0; i < len(inputType.TupleElems); i++ {
+			count += countAbiValueLeaves(inputType.TupleElems[i], reflectionutils.GetField(tuple.Field(i)))
+		}
+		return count
+	default:
+		return 1
+	}
+}
+
+// abiValueLeafSelection describes which leaf values within an ABI value should be mutated, in the order they are
+// visited when recursing into tuple components and array elements.
+type abiValueLeafSelection struct {
+	// selected describes whether each leaf value should be mutated.
+	selected []bool
+
+	// index describes the index of the next leaf value to be visited.
+	index int
+}
+
+// next visits the next leaf value.
+// Returns a boolean indicating whether it should be mutated. Leaf values beyond those selected are not mutated.
+func (s *abiValueLeafSelection) next() bool {
+	selected := s.index < len(s.selected) && s.selected[s.index]
+	s.index++
+	return selected
+}
+
 // MutateAbiValue takes an ABI packable input value, alongside its type definition and a value generator, to mutate
-// existing ABI input values.
+// existing ABI input values. Tuples and arrays are mutated by recursing into their components and elements, mutating
+// only a subset of their leaf values selected by the ValueMutator, so that the rest are preserved.
 func MutateAbiValue(generator ValueGenerator, mutator ValueMutator, inputType *abi.Type, value any) (any, error) {
+	// Select the leaf values to mutate. A leaf value provided directly is always mutated.
+	selection := &abiValueLeafSelection{selected: []bool{true}}
+	if isCompositeAbiType(inputType) {
+		selection.selected = mutator.SelectLeavesToMutate(countAbiValueLeaves(inputType, value))
+	}
+	return mutateAbiValue(generator, mutator, inputType, value, selection)
+}
+
+// MutateAbiValues takes the ABI packable argument values of a call, alongside their argument definitions and a value
+// generator, to mutate them. The arguments are mutated as the components of a single tuple, so that the subset of
+// leaf values selected by the ValueMutator, and thereby the intensity of the mutation, spans all of them.
+// Returns the mutated argument values, or an error if one occurs.
+func MutateAbiValues(generator ValueGenerator, mutator ValueMutator, arguments abi.Arguments, values []any) ([]any, error) {
+	// Select the leaf values to mutate across all arguments.
+	leafCount := 0
+	for i := 0; i < len(values); i++ {
+		leafCount += countAbiValueLeaves(&arguments[i].Type, values[i])
+	}
+	selection := &abiValueLeafSelection{selected: mutator.SelectLeavesToMutate(leafCount)}
+
+	// Mutate each argument, visiting its leaf values in turn.
+	mutatedValues := make([]any, len(values))
+	for i := 0; i < len(values); i++ {
+		mutatedValue, err := mutateAbiValue(generator, mutator, &arguments[i].Type, values[i], selection)
+		if err != nil {
+			return nil, err
+		}
+		mutatedValues[i] = mutatedValue
+	}
+	return mutatedValues, nil
+}
+
+// mutateAbiValue takes an ABI packable input value, alongside its type definition and a value generator, to mutate
+// the leaf values within it which are selected by the provided abiValueLeafSelection, recursing into tuple components
+// and array elements.
+func mutateAbiValue(generator ValueGenerator, mutator ValueMutator, inputType *abi.Type, value any, selection *abiValueLeafSelection) (any, error) {
+	// If this is a leaf value which was not selected, it is preserved as-is.
+	if !isCompositeAbiType(inputType) && !selection.next() {
+		return value, nil
+	}
+
 	// Switch on the type of value and mutate it recursively.
 	switch inputType.T {
 	case abi.AddressTy:
@@ -207,6 +321,10 @@ func MutateAbiValue(generator ValueGenerator, mutator ValueMutator, inputType *a
 			return nil, fmt.Errorf("could not mutate fixed-sized bytes input as the mutated value returned was not of the correct length. expected %v, got %v", inputType.Size, mutatedValueAsArrayLen)
 		}
 		return mutatedValueAsArray, nil
+	case abi.FunctionTy:
+		// External function references are only meaningful as a whole, as altering either the address or selector
+		// would likely reference a function which does not exist. Instead, we select a new reference.
+		return generator.GenerateFunction(), nil
 	case abi.ArrayTy:
 		// Look through our array, recursively mutate each element, and set the result in the array.
 		// Note: We create a copy, as existing arrays may not be assignable.
@@ -215,7 +333,11 @@ func MutateAbiValue(generator ValueGenerator, mutator ValueMutator, inputType *a
 		// Mutate our array structure first
 		mutatedValues := mutator.MutateArray(reflectionutils.GetReflectedArrayValues(array), true)
 
-		// Create a new array of the appropriate size
+		// Verify the array structure mutation respected our fixed length, then create a new array of the appropriate
+		// size.
+		if len(mutatedValues) != array.Len() {
+			return nil, fmt.Errorf("could not mutate array input as the mutated value returned was not of the correct length. expected %v, got %v", array.Len(), len(mutatedValues))
+		}
 		array = reflect.New(reflect.ArrayOf(array.Len(), array.Type().Elem())).Elem()
 
 		// Next mutate each element in the array.
@@ -228,7 +350,7 @@ func MutateAbiValue(generator ValueGenerator, mutator ValueMutator, inputType *a
 				generatedElement := GenerateAbiValue(generator, inputType.Elem)
 				reflectedElement.Set(reflect.ValueOf(generatedElement))
 			} else {
-				mutatedElement, err := MutateAbiValue(generator, mutator, inputType.Elem, mutatedValues[i])
+				mutatedElement, err := mutateAbiValue(generator, mutator, inputType.Elem, mutatedValues[i], selection)
 				if err != nil {
 					return nil, fmt.Errorf("could not mutate array input as the value generator encountered an error: %v", err)
 				}
@@ -258,7 +380,7 @@ func MutateAbiValue(generator ValueGenerator, mutator ValueMutator, inputType *a
 				generatedElement := GenerateAbiValue(generator, inputType.Elem)
 				reflectedElement.Set(reflect.ValueOf(generatedElement))
 			} else {
-				mutatedElement, err := MutateAbiValue(generator, mutator, inputType.Elem, mutatedValues[i])
+				mutatedElement, err := mutateAbiValue(generator, mutator, inputType.Elem, mutatedValues[i], selection)
 				if err != nil {
 					return nil, fmt.Errorf("could not mutate slice input as the value generator encountered an error: %v", err)
 				}
@@ -273,7 +395,7 @@ func MutateAbiValue(generator ValueGenerator, mutator ValueMutator, inputType *a
 		for i := 0; i < len(inputType.TupleElems); i++ {
 			field := tuple.Field(i)
 			fieldValue := reflectionutils.GetField(field)
-			mutatedValue, err := MutateAbiValue(generator, mutator, inputType.TupleElems[i], fieldValue)
+			mutatedValue, err := mutateAbiValue(generator, mutator, inputType.TupleElems[i], fieldValue, selection)
 			if err != nil {
 				return nil, fmt.Errorf("could not mutate struct/tuple input as the value generator encountered an error: %v", err)
 			}
@@ -455,7 +577,7 @@ func encodeABIArgumentToString(inputType *abi.Type, value any, overrides map[com
 		}
 		// Convert the fixed byte array to a hex string
 		return hex.EncodeToString(b), nil
-	case abi.FixedBytesTy:
+	case abi.FixedBytesTy, abi.FunctionTy:
 		// TODO: Error checking to ensure `value` is of the correct type.
 		b := reflectionutils.ArrayToSlice(reflect.ValueOf(value)).([]byte)
 		// Convert the byte array to a hex string
@@ -625,7 +747,7 @@ func encodeJSONArgument(inputType *abi.Type, value any) (any, error) {
 			return nil, fmt.Errorf("could not encode dynamic-sized bytes as the value provided is not of the correct type")
 		}
 		return hex.EncodeToString(b), nil
-	case abi.FixedBytesTy:
+	case abi.FixedBytesTy, abi.FunctionTy:
 		// TODO: Error checking to ensure `value` is of the correct type.
 		b := reflectionutils.ArrayToSlice(reflect.ValueOf(value)).([]byte)
 		return hex.EncodeToString(b), nil
@@ -814,7 +936,7 @@ func decodeJSONArgument(inputType *abi.Type, value any, deployedContractAddr map
 			return nil, err
 		}
 		v = decodedBytes
-	case abi.FixedBytesTy:
+	case abi.FixedBytesTy, abi.FunctionTy:
 		str, ok := value.(string)
 		if !ok {
 			return nil, fmt.Errorf("%s value should be added as string in JSON", inputType)
@@ -2,12 +2,15 @@ package valuegeneration
 
 import (
 	"fmt"
+	"math/big"
 	"math/rand"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/crytic/medusa/utils/reflectionutils"
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -67,6 +70,19 @@ func getTestABIArguments() abi.Arguments {
 			},
 			Indexed: false,
 		},
+		{
+			Name: "testFunction",
+			Type: abi.Type{
+				Elem:          nil,
+				Size:          24,
+				T:             abi.FunctionTy,
+				TupleRawName:  "",
+				TupleElems:    nil,
+				TupleRawNames: nil,
+				TupleType:     nil,
+			},
+			Indexed: false,
+		},
 	}
 
 	// Append all fixed byte sizes
@@ -295,6 +311,241 @@ func TestABIGenerationAndMutation(t *testing.T) {
 	}
 }
 
+// TestAbiValuesExoticTypes runs tests to ensure values of less common ABI types, such as external function references
+// and fixed-point types (which are parsed as integers of the same bit width), can be generated, mutated, and packed,
+// and that types which cannot be generated are reported as unsupported rather than causing a panic.
+func TestAbiValuesExoticTypes(t *testing.T) {
+	// Create a value generator with a known external function reference in its value set.
+	valueSet := NewValueSet()
+	knownAddress := common.HexToAddress("0x1234")
+	knownSelector := []byte{0xde, 0xad, 0xbe, 0xef}
+	valueSet.AddFunction(knownAddress, knownSelector)
+	mutationalGenerator := NewMutationalValueGenerator(&MutationalValueGeneratorConfig{
+		MinMutationRounds:           0,
+		MaxMutationRounds:           1,
+		GenerateRandomAddressBias:   0,
+		MutateIntegerProbability:    0.8,
+		MutateFixedBytesProbability: 0.8,
+		RandomValueGeneratorConfig: &RandomValueGeneratorConfig{
+			GenerateRandomArrayMinSize: 0,
+			GenerateRandomArrayMaxSize: 5,
+		},
+	}, valueSet, rand.New(rand.NewSource(time.Now().UnixNano())))
+
+	// Function references should be selected from the value set, and encode the address followed by the selector.
+	function := mutationalGenerator.GenerateFunction()
+	assert.EqualValues(t, knownAddress.Bytes(), function[:common.AddressLength])
+	assert.EqualValues(t, knownSelector, function[common.AddressLength:])
+
+	// Each supported type should be generated, mutated, and packed without error.
+	supportedTypes := []abi.Argument{
+		{Type: mustNewAbiType(t, "function", nil)},
+		{Type: mustNewAbiType(t, "function[]", nil)},
+		{Type: mustNewAbiType(t, "function[2][]", nil)},
+		{Type: mustNewAbiType(t, "tuple", []abi.ArgumentMarshaling{{Name: "callback", Type: "function"}, {Name: "amount", Type: "uint256"}})},
+		{Type: mustNewAbiType(t, "int8", nil)},     // fixed8x1
+		{Type: mustNewAbiType(t, "uint24", nil)},   // ufixed24x2
+		{Type: mustNewAbiType(t, "int128[]", nil)}, // fixed[]
+	}
+	for _, arg := range supportedTypes {
+		assert.True(t, IsSupportedAbiType(&arg.Type), arg.Type.String())
+		for i := 0; i < 5; i++ {
+			value := GenerateAbiValue(mutationalGenerator, &arg.Type)
+			mutatedValue, err := MutateAbiValue(mutationalGenerator, mutationalGenerator, &arg.Type, value)
+			assert.NoError(t, err)
+			assert.EqualValues(t, reflect.ValueOf(value).Type().String(), reflect.ValueOf(mutatedValue).Type().String())
+			_, err = abi.Arguments{arg}.Pack(mutatedValue)
+			assert.NoError(t, err, arg.Type.String())
+		}
+	}
+
+	// Types we cannot generate values for should be reported as unsupported, including when nested.
+	fixedPointType := abi.Type{T: abi.FixedPointTy, Size: 128}
+	unsupportedTypes := []abi.Type{
+		fixedPointType,
+		{T: abi.HashTy},
+		{T: abi.SliceTy, Elem: &fixedPointType},
+		{T: abi.ArrayTy, Size: 2, Elem: &fixedPointType},
+		{T: abi.TupleTy, TupleElems: []*abi.Type{{T: abi.BoolTy}, &fixedPointType}},
+	}
+	for _, unsupportedType := range unsupportedTypes {
+		assert.False(t, IsSupportedAbiType(&unsupportedType))
+	}
+}
+
+// mustNewAbiType creates an abi.Type from the provided type string and tuple components, failing the test if an error
+// occurs.
+func mustNewAbiType(t *testing.T, typ string, components []abi.ArgumentMarshaling) abi.Type {
+	abiType, err := abi.NewType(typ, "", components)
+	assert.NoError(t, err)
+	return abiType
+}
+
+// fixedLeafSelectionMutator is a ValueMutator which selects a fixed set of leaf values to mutate, and deterministically
+// alters every leaf value it mutates, so tests can verify exactly which leaf values were mutated.
+type fixedLeafSelectionMutator struct {
+	*RandomValueGenerator
+
+	// selected describes whether each leaf value should be mutated.
+	selected []bool
+}
+
+// SelectLeavesToMutate returns the fixed set of leaf values to mutate.
+func (m *fixedLeafSelectionMutator) SelectLeavesToMutate(leafCount int) []bool {
+	return m.selected
+}
+
+// MutateAddress flips the first byte of the provided address.
+func (m *fixedLeafSelectionMutator) MutateAddress(addr common.Address) common.Address {
+	addr[0] ^= 0xff
+	return addr
+}
+
+// MutateBool negates the provided boolean.
+func (m *fixedLeafSelectionMutator) MutateBool(bl bool) bool {
+	return !bl
+}
+
+// MutateFixedBytes flips the first byte of the provided fixed-sized bytes.
+func (m *fixedLeafSelectionMutator) MutateFixedBytes(b []byte) []byte {
+	mutated := append([]byte(nil), b...)
+	mutated[0] ^= 0xff
+	return mutated
+}
+
+// MutateString appends a character to the provided string.
+func (m *fixedLeafSelectionMutator) MutateString(s string) string {
+	return s + "!"
+}
+
+// MutateInteger flips the lowest bit of the provided integer.
+func (m *fixedLeafSelectionMutator) MutateInteger(i *big.Int, signed bool, bitLength int) *big.Int {
+	return new(big.Int).Xor(i, big.NewInt(1))
+}
+
+// flattenAbiValueLeaves obtains the leaf values within the provided ABI packable value of the provided abi.Type, in the
+// order they are visited when mutating it.
+func flattenAbiValueLeaves(inputType *abi.Type, value any) []any {
+	switch inputType.T {
+	case abi.ArrayTy, abi.SliceTy:
+		leaves := make([]any, 0)
+		array := reflect.ValueOf(value)
+		for i := 0; i < array.Len(); i++ {
+			leaves = append(leaves, flattenAbiValueLeaves(inputType.Elem, array.Index(i).Interface())...)
+		}
+		return leaves
+	case abi.TupleTy:
+		leaves := make([]any, 0)
+		tuple := reflectionutils.CopyReflectedType(reflect.ValueOf(value))
+		for i := 0; i < len(inputType.TupleElems); i++ {
+			leaves = append(leaves, flattenAbiValueLeaves(inputType.TupleElems[i], reflectionutils.GetField(tuple.Field(i)))...)
+		}
+		return leaves
+	default:
+		return []any{value}
+	}
+}
+
+// TestMutateAbiValueCompositeLeaves runs tests to ensure that mutating tuples and nested arrays recurses into their
+// components and elements, mutating only the leaf values selected by the ValueMutator and preserving the rest, and
+// that the mutated values can still be packed.
+func TestMutateAbiValueCompositeLeaves(t *testing.T) {
+	valueGenerator := NewRandomValueGenerator(&RandomValueGeneratorConfig{
+		GenerateRandomArrayMinSize:  2,
+		GenerateRandomArrayMaxSize:  2,
+		GenerateRandomStringMinSize: 1,
+		GenerateRandomStringMaxSize: 10,
+	}, rand.New(rand.NewSource(time.Now().UnixNano())))
+
+	// Define a struct with mixed field types, and a slice of fixed-size arrays, alongside the leaf values to mutate.
+	tests := []struct {
+		arg      abi.Argument
+		selected []bool
+	}{
+		{
+			arg: abi.Argument{Type: mustNewAbiType(t, "tuple", []abi.ArgumentMarshaling{
+				{Name: "amount", Type: "uint256"},
+				{Name: "owner", Type: "address"},
+				{Name: "enabled", Type: "bool"},
+				{Name: "name", Type: "string"},
+				{Name: "id", Type: "bytes32"},
+				{Name: "small", Type: "uint8"},
+			})},
+			selected: []bool{true, false, true, false, false, true},
+		},
+		{
+			arg:      abi.Argument{Type: mustNewAbiType(t, "uint256[3][]", nil)},
+			selected: []bool{false, true, false, false, true, false},
+		},
+	}
+	for _, test := range tests {
+		value := GenerateAbiValue(valueGenerator, &test.arg.Type)
+		assert.EqualValues(t, len(test.selected), countAbiValueLeaves(&test.arg.Type, value))
+
+		// Mutate the value, and ensure only the selected leaf values changed, and the type and array lengths are
+		// preserved.
+		mutator := &fixedLeafSelectionMutator{RandomValueGenerator: valueGenerator, selected: test.selected}
+		mutatedValue, err := MutateAbiValue(valueGenerator, mutator, &test.arg.Type, value)
+		assert.NoError(t, err)
+		assert.EqualValues(t, reflect.TypeOf(value), reflect.TypeOf(mutatedValue))
+		leaves := flattenAbiValueLeaves(&test.arg.Type, value)
+		mutatedLeaves := flattenAbiValueLeaves(&test.arg.Type, mutatedValue)
+		assert.Len(t, mutatedLeaves, len(leaves))
+		for i := range leaves {
+			assert.EqualValues(t, test.selected[i], !reflect.DeepEqual(leaves[i], mutatedLeaves[i]), "leaf %d of %v", i, test.arg.Type.String())
+		}
+
+		// Ensure the mutated value can be packed.
+		_, err = abi.Arguments{test.arg}.Pack(mutatedValue)
+		assert.NoError(t, err)
+	}
+}
+
+// TestMutateAbiValues runs a test to ensure the arguments of a call are mutated as the components of a single tuple,
+// such that the leaf values selected by the ValueMutator span all arguments.
+func TestMutateAbiValues(t *testing.T) {
+	valueGenerator := NewRandomValueGenerator(&RandomValueGeneratorConfig{
+		GenerateRandomArrayMinSize:  2,
+		GenerateRandomArrayMaxSize:  2,
+		GenerateRandomStringMinSize: 1,
+		GenerateRandomStringMaxSize: 10,
+	}, rand.New(rand.NewSource(time.Now().UnixNano())))
+
+	// Define arguments with a leaf value each, other than a struct with two leaf values.
+	args := abi.Arguments{
+		{Name: "amount", Type: mustNewAbiType(t, "uint256", nil)},
+		{Name: "config", Type: mustNewAbiType(t, "tuple", []abi.ArgumentMarshaling{
+			{Name: "small", Type: "uint8"},
+			{Name: "enabled", Type: "bool"},
+		})},
+		{Name: "owner", Type: mustNewAbiType(t, "address", nil)},
+	}
+	values := make([]any, len(args))
+	for i := range args {
+		values[i] = GenerateAbiValue(valueGenerator, &args[i].Type)
+	}
+
+	// Mutate the arguments, and ensure only the selected leaf values changed.
+	selected := []bool{false, true, false, true}
+	mutator := &fixedLeafSelectionMutator{RandomValueGenerator: valueGenerator, selected: selected}
+	mutatedValues, err := MutateAbiValues(valueGenerator, mutator, args, values)
+	assert.NoError(t, err)
+	assert.Len(t, mutatedValues, len(values))
+	leaves, mutatedLeaves := make([]any, 0), make([]any, 0)
+	for i := range args {
+		leaves = append(leaves, flattenAbiValueLeaves(&args[i].Type, values[i])...)
+		mutatedLeaves = append(mutatedLeaves, flattenAbiValueLeaves(&args[i].Type, mutatedValues[i])...)
+	}
+	assert.Len(t, mutatedLeaves, len(selected))
+	for i := range leaves {
+		assert.EqualValues(t, selected[i], !reflect.DeepEqual(leaves[i], mutatedLeaves[i]), "leaf %d", i)
+	}
+
+	// Ensure the mutated arguments can be packed.
+	_, err = args.Pack(mutatedValues...)
+	assert.NoError(t, err)
+}
+
 // TestEncodeABIArgumentToString runs tests to ensure that  a provided go-ethereum ABI packable input value of a given
 // type is encoded to string in the specific format, depending on the input's type.
 func TestEncodeABIArgumentToString(t *testing.T) {
@@ -23,6 +23,10 @@ type ValueGenerator interface {
 	// GenerateFixedBytes generates/selects a fixed-sized byte array to use when populating inputs.
 	GenerateFixedBytes(length int) []byte
 
+	// GenerateFunction generates/selects an external function reference (an address followed by a function selector)
+	// to use when populating inputs.
+	GenerateFunction() [24]byte
+
 	// GenerateString generates/selects a dynamic-sized string to use when populating inputs.
 	GenerateString() string
 
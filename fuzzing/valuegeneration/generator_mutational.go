@@ -2,10 +2,12 @@ package valuegeneration
 
 import (
 	"github.com/crytic/medusa/utils"
+	"github.com/crytic/medusa/utils/randomutils"
 	"github.com/ethereum/go-ethereum/common"
 	"golang.org/x/exp/slices"
 	"math/big"
 	"math/rand"
+	"sync"
 )
 
 // MutationalValueGenerator represents a ValueGenerator and ValueMutator for function inputs and call arguments. It
@@ -18,6 +20,14 @@ type MutationalValueGenerator struct {
 	// operations.
 	valueSet *ValueSet
 
+	// integerMutationOperatorChooser selects the index of the integerMutationOperators entry used for each round of
+	// integer mutation, weighted by the configured IntegerMutationWeights.
+	integerMutationOperatorChooser *randomutils.WeightedRandomChooser[int]
+
+	// bytesMutationOperatorChooser selects the index of the bytesMutationOperators entry used for each round of
+	// byte array mutation, weighted by the configured BytesMutationWeights.
+	bytesMutationOperatorChooser *randomutils.WeightedRandomChooser[int]
+
 	// RandomValueGenerator is included to inherit from the random generator
 	*RandomValueGenerator
 }
@@ -33,6 +43,19 @@ type MutationalValueGeneratorConfig struct {
 	// an existing value.
 	MaxMutationRounds int
 
+	// MinMutatedLeaves describes the minimum amount of leaf values (values which are not tuples or arrays) selected
+	// for mutation within a tuple or array input, or within a call's arguments. It is capped at the amount of leaf
+	// values available, and at least one leaf value is always selected.
+	MinMutatedLeaves int
+	// MaxMutatedLeaves describes the maximum amount of leaf values (values which are not tuples or arrays) selected
+	// for mutation within a tuple or array input, or within a call's arguments. A zero value places no limit.
+	MaxMutatedLeaves int
+
+	// IntegerMutationWeights describes the weights of the operators applied in each round of integer mutation.
+	IntegerMutationWeights IntegerMutationWeights
+	// BytesMutationWeights describes the weights of the operators applied in each round of byte array mutation.
+	BytesMutationWeights BytesMutationWeights
+
 	// GenerateRandomIntegerBias defines the probability in which an address generated by the value generator is
 	// entirely random, rather than selected from the MutationalValueGenerator's ValueSet.
 	// range is [0.0, 1.0].
@@ -82,6 +105,59 @@ type MutationalValueGeneratorConfig struct {
 	*RandomValueGeneratorConfig
 }
 
+// IntegerMutationWeights describes the weights of the operators a MutationalValueGenerator selects from in each round
+// of integer mutation. If no operator has a positive weight, DefaultIntegerMutationWeights are used instead.
+type IntegerMutationWeights struct {
+	// Arithmetic describes the weight of adding, subtracting, multiplying, dividing, or modulo dividing the integer
+	// by a value from the ValueSet or a bound of its type.
+	Arithmetic uint64
+	// BitFlip describes the weight of flipping a single random bit of the integer.
+	BitFlip uint64
+	// ByteSwap describes the weight of swapping two random bytes of the integer.
+	ByteSwap uint64
+	// Endianness describes the weight of reversing the order of the bytes of the integer.
+	Endianness uint64
+	// SignFlip describes the weight of negating the integer. Unsigned integers wrap around to their two's complement.
+	SignFlip uint64
+	// OffByOne describes the weight of adding or subtracting one from the integer.
+	OffByOne uint64
+}
+
+// DefaultIntegerMutationWeights returns the IntegerMutationWeights used when none are configured.
+func DefaultIntegerMutationWeights() IntegerMutationWeights {
+	return IntegerMutationWeights{
+		Arithmetic: 50,
+		BitFlip:    10,
+		ByteSwap:   5,
+		Endianness: 5,
+		SignFlip:   10,
+		OffByOne:   20,
+	}
+}
+
+// BytesMutationWeights describes the weights of the operators a MutationalValueGenerator selects from in each round
+// of byte array mutation. If no operator has a positive weight, DefaultBytesMutationWeights are used instead.
+type BytesMutationWeights struct {
+	// RandomByte describes the weight of replacing, inserting, or removing a random byte.
+	RandomByte uint64
+	// BitFlip describes the weight of flipping a single random bit.
+	BitFlip uint64
+	// ByteSwap describes the weight of swapping two random bytes.
+	ByteSwap uint64
+	// InterestingBytes describes the weight of copying a byte array from the ValueSet over a random position.
+	InterestingBytes uint64
+}
+
+// DefaultBytesMutationWeights returns the BytesMutationWeights used when none are configured.
+func DefaultBytesMutationWeights() BytesMutationWeights {
+	return BytesMutationWeights{
+		RandomByte:       30,
+		BitFlip:          10,
+		ByteSwap:         10,
+		InterestingBytes: 20,
+	}
+}
+
 // NewMutationalValueGenerator creates a new MutationalValueGenerator using a provided ValueSet to seed base-values for
 // mutation.
 func NewMutationalValueGenerator(config *MutationalValueGeneratorConfig, valueSet *ValueSet, randomProvider *rand.Rand) *MutationalValueGenerator {
@@ -92,6 +168,24 @@ func NewMutationalValueGenerator(config *MutationalValueGeneratorConfig, valueSe
 		RandomValueGenerator: NewRandomValueGenerator(config.RandomValueGeneratorConfig, randomProvider),
 	}
 
+	// Create our mutation operator choosers, using the default weights if none are positive.
+	integerWeights := config.IntegerMutationWeights
+	if integerWeights == (IntegerMutationWeights{}) {
+		integerWeights = DefaultIntegerMutationWeights()
+	}
+	generator.integerMutationOperatorChooser = randomutils.NewWeightedRandomChooserWithRand[int](randomProvider, &sync.Mutex{})
+	for i, operator := range integerMutationOperators {
+		generator.integerMutationOperatorChooser.AddChoices(randomutils.NewWeightedRandomChoice(i, new(big.Int).SetUint64(operator.weight(integerWeights))))
+	}
+	bytesWeights := config.BytesMutationWeights
+	if bytesWeights == (BytesMutationWeights{}) {
+		bytesWeights = DefaultBytesMutationWeights()
+	}
+	generator.bytesMutationOperatorChooser = randomutils.NewWeightedRandomChooserWithRand[int](randomProvider, &sync.Mutex{})
+	for i, operator := range bytesMutationOperators {
+		generator.bytesMutationOperatorChooser.AddChoices(randomutils.NewWeightedRandomChoice(i, new(big.Int).SetUint64(operator.weight(bytesWeights))))
+	}
+
 	// Ensure some initial values this mutator will depend on for basic mutations to the set.
 	generator.valueSet.AddInteger(big.NewInt(0))
 	generator.valueSet.AddInteger(big.NewInt(1))
@@ -109,10 +203,10 @@ func (g *MutationalValueGenerator) getMutationParams(inputsLen int) (int, int) {
 	return inputIdx, mutationCount
 }
 
-// integerMutationMethods define methods which take a big integer and a set of inputs and
-// transform the integer with a random input and operation. This is used in a loop to create
-// mutated integer values.
-var integerMutationMethods = []func(*MutationalValueGenerator, *big.Int, ...*big.Int) *big.Int{
+// integerArithmeticMethods define methods which take a big integer and a set of inputs and
+// transform the integer with a random input and operation. These are applied by the arithmetic integer mutation
+// operator.
+var integerArithmeticMethods = []func(*MutationalValueGenerator, *big.Int, ...*big.Int) *big.Int{
 	func(g *MutationalValueGenerator, x *big.Int, inputs ...*big.Int) *big.Int {
 		// Add a random input
 		return big.NewInt(0).Add(x, inputs[g.randomProvider.Intn(len(inputs))])
@@ -143,6 +237,83 @@ var integerMutationMethods = []func(*MutationalValueGenerator, *big.Int, ...*big
 	},
 }
 
+// integerMutationOperators define the operators which may be selected, by their weight in the configured
+// IntegerMutationWeights, to transform an integer of the provided signedness and bit length in a round of mutation.
+// The returned integer is constrained to the bounds of its type by the caller.
+var integerMutationOperators = []struct {
+	// weight obtains the weight of the operator from the provided IntegerMutationWeights.
+	weight func(weights IntegerMutationWeights) uint64
+
+	// mutate applies the operator to the provided integer, optionally using the provided inputs.
+	mutate func(g *MutationalValueGenerator, x *big.Int, signed bool, bitLength int, inputs ...*big.Int) *big.Int
+}{
+	// Apply a random arithmetic operation with a random input
+	{
+		weight: func(weights IntegerMutationWeights) uint64 { return weights.Arithmetic },
+		mutate: func(g *MutationalValueGenerator, x *big.Int, signed bool, bitLength int, inputs ...*big.Int) *big.Int {
+			return integerArithmeticMethods[g.randomProvider.Intn(len(integerArithmeticMethods))](g, x, inputs...)
+		},
+	},
+	// Flip a random bit
+	{
+		weight: func(weights IntegerMutationWeights) uint64 { return weights.BitFlip },
+		mutate: func(g *MutationalValueGenerator, x *big.Int, signed bool, bitLength int, inputs ...*big.Int) *big.Int {
+			b := integerToWord(x, bitLength)
+			i := g.randomProvider.Intn(len(b))
+			b[i] = b[i] ^ (1 << g.randomProvider.Intn(8))
+			return wordToInteger(b, signed, bitLength)
+		},
+	},
+	// Swap two random bytes
+	{
+		weight: func(weights IntegerMutationWeights) uint64 { return weights.ByteSwap },
+		mutate: func(g *MutationalValueGenerator, x *big.Int, signed bool, bitLength int, inputs ...*big.Int) *big.Int {
+			b := integerToWord(x, bitLength)
+			i, j := g.randomProvider.Intn(len(b)), g.randomProvider.Intn(len(b))
+			b[i], b[j] = b[j], b[i]
+			return wordToInteger(b, signed, bitLength)
+		},
+	},
+	// Reverse the byte order
+	{
+		weight: func(weights IntegerMutationWeights) uint64 { return weights.Endianness },
+		mutate: func(g *MutationalValueGenerator, x *big.Int, signed bool, bitLength int, inputs ...*big.Int) *big.Int {
+			b := integerToWord(x, bitLength)
+			slices.Reverse(b)
+			return wordToInteger(b, signed, bitLength)
+		},
+	},
+	// Flip the sign
+	{
+		weight: func(weights IntegerMutationWeights) uint64 { return weights.SignFlip },
+		mutate: func(g *MutationalValueGenerator, x *big.Int, signed bool, bitLength int, inputs ...*big.Int) *big.Int {
+			return new(big.Int).Neg(x)
+		},
+	},
+	// Add or subtract one
+	{
+		weight: func(weights IntegerMutationWeights) uint64 { return weights.OffByOne },
+		mutate: func(g *MutationalValueGenerator, x *big.Int, signed bool, bitLength int, inputs ...*big.Int) *big.Int {
+			if g.randomProvider.Intn(2) == 0 {
+				return new(big.Int).Add(x, big.NewInt(1))
+			}
+			return new(big.Int).Sub(x, big.NewInt(1))
+		},
+	},
+}
+
+// integerToWord obtains the big-endian, two's complement representation of the provided integer, using one byte for
+// every eight bits of the provided bit length.
+func integerToWord(x *big.Int, bitLength int) []byte {
+	return utils.ConstrainIntegerToBitLength(new(big.Int).Set(x), false, bitLength).FillBytes(make([]byte, (bitLength+7)/8))
+}
+
+// wordToInteger obtains the integer of the provided signedness and bit length represented by the provided big-endian,
+// two's complement representation, as obtained from integerToWord.
+func wordToInteger(b []byte, signed bool, bitLength int) *big.Int {
+	return utils.ConstrainIntegerToBitLength(new(big.Int).SetBytes(b), signed, bitLength)
+}
+
 // mutateIntegerInternal takes an integer input and returns either a random new integer, or a mutated value based off the input.
 // If a nil input is provided, this method uses an existing base value set value as the starting point for mutation.
 func (g *MutationalValueGenerator) mutateIntegerInternal(i *big.Int, signed bool, bitLength int) *big.Int {
@@ -178,8 +349,12 @@ func (g *MutationalValueGenerator) mutateIntegerInternal(i *big.Int, signed bool
 
 	// Perform the appropriate number of mutations.
 	for i := 0; i < mutationCount; i++ {
-		// Mutate input
-		input = integerMutationMethods[g.randomProvider.Intn(len(integerMutationMethods))](g, input, inputs...)
+		// Mutate input with an operator selected by its weight.
+		operatorIdx, err := g.integerMutationOperatorChooser.ChooseWithRand(g.randomProvider)
+		if err != nil {
+			break
+		}
+		input = integerMutationOperators[*operatorIdx].mutate(g, input, signed, bitLength, inputs...)
 
 		// Correct value boundaries (underflow/overflow)
 		input = utils.ConstrainIntegerToBounds(input, min, max)
@@ -187,9 +362,9 @@ func (g *MutationalValueGenerator) mutateIntegerInternal(i *big.Int, signed bool
 	return input
 }
 
-// bytesMutationMethods define methods which take an initial bytes and a set of inputs to transform the input. The
-// transformed input is returned. This is used in a loop to mutate byte slices.
-var bytesMutationMethods = []func(*MutationalValueGenerator, []byte, ...[]byte) []byte{
+// bytesRandomByteMethods define methods which take an initial bytes and a set of inputs to transform the input with a
+// random byte. The transformed input is returned. These are applied by the random byte mutation operator.
+var bytesRandomByteMethods = []func(*MutationalValueGenerator, []byte, ...[]byte) []byte{
 	// Replace a random index with a random byte
 	func(g *MutationalValueGenerator, b []byte, inputs ...[]byte) []byte {
 		// Generate a random byte and replace an existing byte in our array with it. If our array has no bytes, we add
@@ -202,17 +377,6 @@ var bytesMutationMethods = []func(*MutationalValueGenerator, []byte, ...[]byte)
 		}
 		return b
 	},
-	// Flip a random bit in it.
-	func(g *MutationalValueGenerator, b []byte, inputs ...[]byte) []byte {
-		// If we have bytes in our array, flip a random bit in a random byte. Otherwise, we add a random byte.
-		if len(b) > 0 {
-			i := g.randomProvider.Intn(len(b))
-			b[i] = b[i] ^ (1 << (g.randomProvider.Intn(8)))
-		} else {
-			b = append(b, byte(g.randomProvider.Intn(256)))
-		}
-		return b
-	},
 	// Add a random byte at a random position
 	func(g *MutationalValueGenerator, b []byte, inputs ...[]byte) []byte {
 		// Generate a random byte to insert
@@ -244,6 +408,72 @@ var bytesMutationMethods = []func(*MutationalValueGenerator, []byte, ...[]byte)
 	},
 }
 
+// bytesMutationOperators define the operators which may be selected, by their weight in the configured
+// BytesMutationWeights, to transform a byte array in a round of mutation, optionally using a set of inputs. The
+// transformed input is returned.
+var bytesMutationOperators = []struct {
+	// weight obtains the weight of the operator from the provided BytesMutationWeights.
+	weight func(weights BytesMutationWeights) uint64
+
+	// mutate applies the operator to the provided byte array, optionally using the provided inputs.
+	mutate func(g *MutationalValueGenerator, b []byte, inputs ...[]byte) []byte
+}{
+	// Replace, insert, or remove a random byte
+	{
+		weight: func(weights BytesMutationWeights) uint64 { return weights.RandomByte },
+		mutate: func(g *MutationalValueGenerator, b []byte, inputs ...[]byte) []byte {
+			return bytesRandomByteMethods[g.randomProvider.Intn(len(bytesRandomByteMethods))](g, b, inputs...)
+		},
+	},
+	// Flip a random bit
+	{
+		weight: func(weights BytesMutationWeights) uint64 { return weights.BitFlip },
+		mutate: func(g *MutationalValueGenerator, b []byte, inputs ...[]byte) []byte {
+			// If we have bytes in our array, flip a random bit in a random byte. Otherwise, we add a random byte.
+			if len(b) > 0 {
+				i := g.randomProvider.Intn(len(b))
+				b[i] = b[i] ^ (1 << (g.randomProvider.Intn(8)))
+			} else {
+				b = append(b, byte(g.randomProvider.Intn(256)))
+			}
+			return b
+		},
+	},
+	// Swap two random bytes
+	{
+		weight: func(weights BytesMutationWeights) uint64 { return weights.ByteSwap },
+		mutate: func(g *MutationalValueGenerator, b []byte, inputs ...[]byte) []byte {
+			// If we have fewer than two bytes, there is nothing to swap.
+			if len(b) < 2 {
+				return b
+			}
+			i, j := g.randomProvider.Intn(len(b)), g.randomProvider.Intn(len(b))
+			b[i], b[j] = b[j], b[i]
+			return b
+		},
+	},
+	// Copy an input over a random position
+	{
+		weight: func(weights BytesMutationWeights) uint64 { return weights.InterestingBytes },
+		mutate: func(g *MutationalValueGenerator, b []byte, inputs ...[]byte) []byte {
+			// If we have no inputs, do nothing.
+			if len(inputs) == 0 {
+				return b
+			}
+
+			// Overwrite the bytes from a random position with the input, extending our array if the input does not
+			// fit within it.
+			input := inputs[g.randomProvider.Intn(len(inputs))]
+			i := g.randomProvider.Intn(len(b) + 1)
+			if end := i + len(input); end > len(b) {
+				b = append(b, make([]byte, end-len(b))...)
+			}
+			copy(b[i:], input)
+			return b
+		},
+	},
+}
+
 // mutateBytesInternal takes a byte array and a length. This function returns either a fixed length byte array (based on
 // the provided length) or a byte slice. The returned byte array/slice is either randomly generated or mutated using
 // the provided input.
@@ -270,9 +500,13 @@ func (g *MutationalValueGenerator) mutateBytesInternal(b []byte, length int) []b
 		input = slices.Clone(inputs[inputIdx])
 	}
 
-	// Mutate the data for our desired number of rounds
+	// Mutate the data for our desired number of rounds, with operators selected by their weight.
 	for i := 0; i < mutationCount; i++ {
-		input = bytesMutationMethods[g.randomProvider.Intn(len(bytesMutationMethods))](g, input, inputs...)
+		operatorIdx, err := g.bytesMutationOperatorChooser.ChooseWithRand(g.randomProvider)
+		if err != nil {
+			break
+		}
+		input = bytesMutationOperators[*operatorIdx].mutate(g, input, inputs...)
 	}
 
 	// If we want a fixed-byte array and the mutated input is smaller than the requested length, pad the array
@@ -422,6 +656,13 @@ func (g *MutationalValueGenerator) MutateArray(value []any, fixedLength bool) []
 	return value
 }
 
+// SelectLeavesToMutate takes the amount of leaf values within a tuple or array input, or within a call's arguments,
+// and returns a boolean for each indicating whether it should be mutated. A random subset of leaf values is selected,
+// within the configured MinMutatedLeaves and MaxMutatedLeaves, so a mutated value remains similar to the original.
+func (g *MutationalValueGenerator) SelectLeavesToMutate(leafCount int) []bool {
+	return selectRandomLeavesInRange(g.randomProvider, leafCount, g.config.MinMutatedLeaves, g.config.MaxMutatedLeaves)
+}
+
 // MutateBool takes a boolean input and returns a mutated value based off the input.
 func (g *MutationalValueGenerator) MutateBool(bl bool) bool {
 	// Determine whether to perform mutations against this input or just return it as-is.
@@ -463,6 +704,25 @@ func (g *MutationalValueGenerator) GenerateFixedBytes(length int) []byte {
 	return g.mutateBytesInternal(nil, length)
 }
 
+// GenerateFunction obtains an existing external function reference from its underlying value set, so that calls
+// through it reach a deployed contract method, or generates a random one.
+func (g *MutationalValueGenerator) GenerateFunction() [24]byte {
+	// If our bias directs us to, use the random generator instead
+	randomGeneratorDecision := g.randomProvider.Float32()
+	if randomGeneratorDecision < g.config.GenerateRandomAddressBias {
+		return g.RandomValueGenerator.GenerateFunction()
+	}
+
+	// Obtain our function references from our value set. If we have none, generate a random one instead.
+	functions := g.valueSet.Functions()
+	if len(functions) == 0 {
+		return g.RandomValueGenerator.GenerateFunction()
+	}
+
+	// Select a random function reference from our set.
+	return functions[g.randomProvider.Intn(len(functions))]
+}
+
 // GenerateString generates strings and returns them.
 func (g *MutationalValueGenerator) GenerateString() string {
 	return g.mutateStringInternal(nil)
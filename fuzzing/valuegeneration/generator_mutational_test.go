@@ -0,0 +1,265 @@
+package valuegeneration
+
+import (
+	"bytes"
+	"math/big"
+	"math/bits"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/crytic/medusa/utils"
+	"github.com/crytic/medusa/utils/randomutils"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestMutationalValueGenerator creates a MutationalValueGenerator with the provided seed and mutation operator
+// weights, which always mutates provided values rather than generating random ones.
+func newTestMutationalValueGenerator(seed int64, integerWeights IntegerMutationWeights, bytesWeights BytesMutationWeights) *MutationalValueGenerator {
+	valueSet := NewValueSet()
+	valueSet.AddBytes([]byte{0xde, 0xad, 0xbe, 0xef})
+	return NewMutationalValueGenerator(&MutationalValueGeneratorConfig{
+		MinMutationRounds:      1,
+		MaxMutationRounds:      1,
+		IntegerMutationWeights: integerWeights,
+		BytesMutationWeights:   bytesWeights,
+		RandomValueGeneratorConfig: &RandomValueGeneratorConfig{
+			GenerateRandomBytesMaxSize: 10,
+		},
+	}, valueSet, rand.New(rand.NewSource(seed)))
+}
+
+// TestMutationOperatorDistribution runs a test to ensure every integer and byte array mutation operator is selected
+// with the default weights, at a rate proportional to its weight.
+func TestMutationOperatorDistribution(t *testing.T) {
+	const draws = 20_000
+	g := newTestMutationalValueGenerator(1234, IntegerMutationWeights{}, BytesMutationWeights{})
+	defaultIntegerWeights, defaultBytesWeights := DefaultIntegerMutationWeights(), DefaultBytesMutationWeights()
+
+	// Obtain the weight of each operator of each chooser.
+	integerWeights, bytesWeights := make([]uint64, 0), make([]uint64, 0)
+	for _, operator := range integerMutationOperators {
+		integerWeights = append(integerWeights, operator.weight(defaultIntegerWeights))
+	}
+	for _, operator := range bytesMutationOperators {
+		bytesWeights = append(bytesWeights, operator.weight(defaultBytesWeights))
+	}
+	choosers := []struct {
+		name    string
+		chooser *randomutils.WeightedRandomChooser[int]
+		weights []uint64
+	}{
+		{name: "integer", chooser: g.integerMutationOperatorChooser, weights: integerWeights},
+		{name: "bytes", chooser: g.bytesMutationOperatorChooser, weights: bytesWeights},
+	}
+
+	// Ensure each operator is selected about as often as its share of the total weight.
+	for _, chooser := range choosers {
+		totalWeight := uint64(0)
+		for _, weight := range chooser.weights {
+			totalWeight += weight
+		}
+		counts := make([]int, len(chooser.weights))
+		for i := 0; i < draws; i++ {
+			operatorIdx, err := chooser.chooser.ChooseWithRand(g.randomProvider)
+			assert.NoError(t, err)
+			counts[*operatorIdx]++
+		}
+		for i, count := range counts {
+			expected := float64(draws) * float64(chooser.weights[i]) / float64(totalWeight)
+			assert.Positive(t, count, "%s operator %d was not selected", chooser.name, i)
+			assert.InDelta(t, expected, float64(count), expected*0.15, "%s operator %d", chooser.name, i)
+		}
+	}
+}
+
+// TestIntegerMutationOperators runs a test to ensure each integer mutation operator is exercised when it is the only
+// one with a positive weight, transforming integers as expected for signed and unsigned types.
+func TestIntegerMutationOperators(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights IntegerMutationWeights
+		verify  func(x, mutated []byte, xInt, mutatedInt *big.Int, signed bool, bitLength int) bool
+	}{
+		{
+			name:    "arithmetic",
+			weights: IntegerMutationWeights{Arithmetic: 1},
+			verify: func(x, mutated []byte, xInt, mutatedInt *big.Int, signed bool, bitLength int) bool {
+				return true
+			},
+		},
+		{
+			name:    "bitFlip",
+			weights: IntegerMutationWeights{BitFlip: 1},
+			verify: func(x, mutated []byte, xInt, mutatedInt *big.Int, signed bool, bitLength int) bool {
+				flippedBits := 0
+				for i := range x {
+					flippedBits += bits.OnesCount8(x[i] ^ mutated[i])
+				}
+				return flippedBits == 1
+			},
+		},
+		{
+			name:    "byteSwap",
+			weights: IntegerMutationWeights{ByteSwap: 1},
+			verify: func(x, mutated []byte, xInt, mutatedInt *big.Int, signed bool, bitLength int) bool {
+				x, mutated = slices.Clone(x), slices.Clone(mutated)
+				slices.Sort(x)
+				slices.Sort(mutated)
+				return bytes.Equal(x, mutated)
+			},
+		},
+		{
+			name:    "endianness",
+			weights: IntegerMutationWeights{Endianness: 1},
+			verify: func(x, mutated []byte, xInt, mutatedInt *big.Int, signed bool, bitLength int) bool {
+				x = slices.Clone(x)
+				slices.Reverse(x)
+				return bytes.Equal(x, mutated)
+			},
+		},
+		{
+			name:    "signFlip",
+			weights: IntegerMutationWeights{SignFlip: 1},
+			verify: func(x, mutated []byte, xInt, mutatedInt *big.Int, signed bool, bitLength int) bool {
+				return utils.ConstrainIntegerToBitLength(new(big.Int).Neg(xInt), signed, bitLength).Cmp(mutatedInt) == 0
+			},
+		},
+		{
+			name:    "offByOne",
+			weights: IntegerMutationWeights{OffByOne: 1},
+			verify: func(x, mutated []byte, xInt, mutatedInt *big.Int, signed bool, bitLength int) bool {
+				return new(big.Int).Sub(mutatedInt, xInt).CmpAbs(big.NewInt(1)) == 0
+			},
+		},
+	}
+
+	inputs := []struct {
+		x         *big.Int
+		signed    bool
+		bitLength int
+	}{
+		{x: new(big.Int).SetBytes([]byte{0x12, 0x34, 0x56, 0x78, 0x9a}), signed: false, bitLength: 256},
+		{x: big.NewInt(-123456789), signed: true, bitLength: 64},
+	}
+	for _, test := range tests {
+		g := newTestMutationalValueGenerator(1234, test.weights, BytesMutationWeights{})
+		for _, input := range inputs {
+			mutations := 0
+			for i := 0; i < 100; i++ {
+				mutated := g.mutateIntegerInternal(input.x, input.signed, input.bitLength)
+				min, max := utils.GetIntegerConstraints(input.signed, input.bitLength)
+				assert.True(t, mutated.Cmp(min) >= 0 && mutated.Cmp(max) <= 0, "%s mutated value out of bounds", test.name)
+
+				// Mutations may be performed for zero rounds, leaving the input unchanged.
+				if mutated.Cmp(input.x) == 0 {
+					continue
+				}
+				mutations++
+				x, mutatedWord := integerToWord(input.x, input.bitLength), integerToWord(mutated, input.bitLength)
+				assert.True(t, test.verify(x, mutatedWord, input.x, mutated, input.signed, input.bitLength), "%s mutated %v to %v", test.name, input.x, mutated)
+			}
+			assert.Positive(t, mutations, "%s was not exercised", test.name)
+		}
+	}
+}
+
+// TestBytesMutationOperators runs a test to ensure each byte array mutation operator is exercised when it is the only
+// one with a positive weight, transforming byte arrays as expected.
+func TestBytesMutationOperators(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights BytesMutationWeights
+		verify  func(b, mutated []byte) bool
+	}{
+		{
+			name:    "randomByte",
+			weights: BytesMutationWeights{RandomByte: 1},
+			verify: func(b, mutated []byte) bool {
+				return utils.Abs(len(mutated)-len(b)) <= 1
+			},
+		},
+		{
+			name:    "bitFlip",
+			weights: BytesMutationWeights{BitFlip: 1},
+			verify: func(b, mutated []byte) bool {
+				if len(b) != len(mutated) {
+					return false
+				}
+				flippedBits := 0
+				for i := range b {
+					flippedBits += bits.OnesCount8(b[i] ^ mutated[i])
+				}
+				return flippedBits == 1
+			},
+		},
+		{
+			name:    "byteSwap",
+			weights: BytesMutationWeights{ByteSwap: 1},
+			verify: func(b, mutated []byte) bool {
+				b, mutated = slices.Clone(b), slices.Clone(mutated)
+				slices.Sort(b)
+				slices.Sort(mutated)
+				return bytes.Equal(b, mutated)
+			},
+		},
+		{
+			name:    "interestingBytes",
+			weights: BytesMutationWeights{InterestingBytes: 1},
+			verify: func(b, mutated []byte) bool {
+				return bytes.Contains(mutated, []byte{0xde, 0xad, 0xbe, 0xef}) && bytes.HasPrefix(b, mutated[:bytes.Index(mutated, []byte{0xde, 0xad, 0xbe, 0xef})])
+			},
+		},
+	}
+
+	b := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+	for _, test := range tests {
+		g := newTestMutationalValueGenerator(1234, IntegerMutationWeights{}, test.weights)
+		mutations := 0
+		for i := 0; i < 100; i++ {
+			// Mutations may be performed for zero rounds, leaving the input unchanged.
+			mutated := g.mutateBytesInternal(b, 0)
+			if bytes.Equal(mutated, b) {
+				continue
+			}
+			mutations++
+			assert.True(t, test.verify(b, mutated), "%s mutated %x to %x", test.name, b, mutated)
+
+			// Fixed-size byte arrays should retain their size.
+			assert.Len(t, g.mutateBytesInternal(b, len(b)), len(b))
+		}
+		assert.Positive(t, mutations, "%s was not exercised", test.name)
+	}
+}
+
+// TestSelectLeavesToMutateRange runs a test to ensure the amount of leaf values selected for mutation is within the
+// configured minimum and maximum, capped at the amount of leaf values, and never empty.
+func TestSelectLeavesToMutateRange(t *testing.T) {
+	tests := []struct {
+		minLeaves, maxLeaves, leafCount int
+		expectedMin, expectedMax        int
+	}{
+		{minLeaves: 0, maxLeaves: 0, leafCount: 6, expectedMin: 1, expectedMax: 6},
+		{minLeaves: 1, maxLeaves: 2, leafCount: 6, expectedMin: 1, expectedMax: 2},
+		{minLeaves: 3, maxLeaves: 0, leafCount: 6, expectedMin: 3, expectedMax: 6},
+		{minLeaves: 10, maxLeaves: 0, leafCount: 6, expectedMin: 6, expectedMax: 6},
+		{minLeaves: 4, maxLeaves: 4, leafCount: 2, expectedMin: 2, expectedMax: 2},
+		{minLeaves: 1, maxLeaves: 0, leafCount: 0, expectedMin: 0, expectedMax: 0},
+	}
+	for _, test := range tests {
+		g := newTestMutationalValueGenerator(1234, IntegerMutationWeights{}, BytesMutationWeights{})
+		g.config.MinMutatedLeaves, g.config.MaxMutatedLeaves = test.minLeaves, test.maxLeaves
+		for i := 0; i < 100; i++ {
+			selected := g.SelectLeavesToMutate(test.leafCount)
+			assert.Len(t, selected, test.leafCount)
+			selectedCount := 0
+			for _, s := range selected {
+				if s {
+					selectedCount++
+				}
+			}
+			assert.GreaterOrEqual(t, selectedCount, test.expectedMin)
+			assert.LessOrEqual(t, selectedCount, test.expectedMax)
+		}
+	}
+}
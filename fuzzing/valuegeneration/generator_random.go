@@ -72,6 +72,17 @@ func (g *RandomValueGenerator) MutateArray(value []any, fixedLength bool) []any
 	return value
 }
 
+// SelectLeavesToMutate takes the amount of leaf values within a tuple or array input, and returns a boolean for each
+// indicating whether it should be mutated.
+func (g *RandomValueGenerator) SelectLeavesToMutate(leafCount int) []bool {
+	// This value generator does not apply mutations, so we select every leaf value.
+	selected := make([]bool, leafCount)
+	for i := range selected {
+		selected[i] = true
+	}
+	return selected
+}
+
 // GenerateBool generates a random bool to use when populating inputs.
 func (g *RandomValueGenerator) GenerateBool() bool {
 	return g.randomProvider.Uint32()%2 == 0
@@ -104,6 +115,13 @@ func (g *RandomValueGenerator) GenerateFixedBytes(length int) []byte {
 	return b
 }
 
+// GenerateFunction generates a random external function reference to use when populating inputs.
+func (g *RandomValueGenerator) GenerateFunction() [24]byte {
+	var function [24]byte
+	g.randomProvider.Read(function[:])
+	return function
+}
+
 // MutateFixedBytes takes a fixed-sized byte array input and returns a mutated value based off the input.
 func (g *RandomValueGenerator) MutateFixedBytes(b []byte) []byte {
 	// This value generator does not apply mutations.
@@ -3,6 +3,7 @@ package valuegeneration
 import (
 	"github.com/ethereum/go-ethereum/common"
 	"math/big"
+	"math/rand"
 )
 
 // ValueMutator represents an interface for a provider used to mutate function inputs and call arguments for use
@@ -30,4 +31,34 @@ type ValueMutator interface {
 
 	// MutateInteger takes an integer input and returns a mutated value based off the input.
 	MutateInteger(i *big.Int, signed bool, bitLength int) *big.Int
+
+	// SelectLeavesToMutate takes the amount of leaf values (values which are not tuples or arrays) within a tuple or
+	// array input, and returns a boolean for each, in the order they are visited, indicating whether it should be
+	// mutated. Leaf values which are not selected are preserved as-is.
+	SelectLeavesToMutate(leafCount int) []bool
+}
+
+// selectRandomLeaves selects a random, non-empty subset of the provided amount of leaf values to mutate, as described
+// by ValueMutator.SelectLeavesToMutate.
+func selectRandomLeaves(randomProvider *rand.Rand, leafCount int) []bool {
+	return selectRandomLeavesInRange(randomProvider, leafCount, 1, 0)
+}
+
+// selectRandomLeavesInRange selects a random, non-empty subset of the provided amount of leaf values to mutate, as
+// described by ValueMutator.SelectLeavesToMutate, whose size is within the provided minimum and maximum. Both are
+// capped at the amount of leaf values, and a zero maximum places no limit.
+func selectRandomLeavesInRange(randomProvider *rand.Rand, leafCount int, minCount int, maxCount int) []bool {
+	selected := make([]bool, leafCount)
+	if leafCount == 0 {
+		return selected
+	}
+	if maxCount <= 0 || maxCount > leafCount {
+		maxCount = leafCount
+	}
+	minCount = max(min(minCount, maxCount), 1)
+	selectedCount := minCount + randomProvider.Intn(maxCount-minCount+1)
+	for _, i := range randomProvider.Perm(leafCount)[:selectedCount] {
+		selected[i] = true
+	}
+	return selected
 }
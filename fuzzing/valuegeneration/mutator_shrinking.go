@@ -57,6 +57,13 @@ func (g *ShrinkingValueMutator) MutateArray(value []any, fixedLength bool) []any
 	return value
 }
 
+// SelectLeavesToMutate takes the amount of leaf values within a tuple or array input, and returns a boolean for each
+// indicating whether it should be shrunk. A random, non-empty subset of leaf values is selected, so individual
+// components and elements are shrunk independently.
+func (g *ShrinkingValueMutator) SelectLeavesToMutate(leafCount int) []bool {
+	return selectRandomLeaves(g.randomProvider, leafCount)
+}
+
 // MutateBool takes a boolean input and returns a mutated value based off the input.
 // This type is not mutated by the ShrinkingValueMutator.
 func (g *ShrinkingValueMutator) MutateBool(bl bool) bool {
@@ -1,11 +1,14 @@
 package valuegeneration
 
 import (
+	"bytes"
 	"encoding/hex"
 	"github.com/crytic/medusa/utils/reflectionutils"
 	"hash"
 	"math/big"
 	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"golang.org/x/crypto/sha3"
@@ -13,15 +16,45 @@ import (
 )
 
 // ValueSet represents potential values of significance within the source code to be used in fuzz tests.
+//
+// Alongside each set, a sorted list of its values is maintained as values are added or removed, so lists can be
+// obtained when generating values without sorting them each time, while random selections made from them remain
+// reproducible. The lists are replaced rather than modified when the set changes, so any list previously obtained
+// from the ValueSet (or shared with a clone of it) is left unchanged.
 type ValueSet struct {
 	// addresses represents a set of common.Address to use in fuzz tests. A mapping is used to avoid duplicates.
 	addresses map[common.Address]any
+	// addressList represents the sorted list of addresses in the set.
+	addressList []common.Address
 	// integers represents a set of integers to use in fuzz tests. A mapping is used to avoid duplicates.
 	integers map[string]*big.Int
+	// chainContextIntegers represents a set of integers derived from the state of the chain being fuzzed (e.g. the
+	// current block number and timestamp), to use in fuzz tests. Unlike integers, the set is replaced whenever it is
+	// refreshed, rather than accumulated. A mapping is used to avoid duplicates.
+	chainContextIntegers map[string]*big.Int
+	// chainContextIntegerList represents the sorted list of chain context integers in the set.
+	chainContextIntegerList []*big.Int
+	// integerList represents the sorted list of integers and chain context integers in the set, without duplicates.
+	integerList []*big.Int
 	// strings represents a set of strings to use in fuzz tests. A mapping is used to avoid duplicates.
 	strings map[string]any
+	// stringList represents the sorted list of strings in the set.
+	stringList []string
 	// bytes represents a set of bytes to use in fuzz tests. A mapping is used to avoid duplicates.
 	bytes map[string][]byte
+	// preimageHashes represents a bounded set of keccak256 hashes of the strings and bytes in the set, and of
+	// well-known preimages such as role identifiers, to use in fuzz tests. Unlike bytes, the set is replaced whenever
+	// it is derived, rather than accumulated. A mapping is used to avoid duplicates.
+	preimageHashes map[string][]byte
+	// preimageHashList represents the sorted list of preimage hashes in the set.
+	preimageHashList [][]byte
+	// byteList represents the sorted list of bytes and preimage hashes in the set, without duplicates.
+	byteList [][]byte
+	// functions represents a set of external function references (an address followed by a function selector) to
+	// use in fuzz tests. A mapping is used to avoid duplicates.
+	functions map[[24]byte]any
+	// functionList represents the sorted list of external function references in the set.
+	functionList [][24]byte
 	// hashProvider represents a hash provider used to create keys for some data.
 	hashProvider hash.Hash
 }
@@ -29,41 +62,85 @@ type ValueSet struct {
 // NewValueSet initializes a new ValueSet object for use with a Fuzzer.
 func NewValueSet() *ValueSet {
 	baseValueSet := &ValueSet{
-		addresses:    make(map[common.Address]any, 0),
-		integers:     make(map[string]*big.Int, 0),
-		strings:      make(map[string]any, 0),
-		bytes:        make(map[string][]byte, 0),
-		hashProvider: sha3.NewLegacyKeccak256(),
+		addresses:            make(map[common.Address]any, 0),
+		integers:             make(map[string]*big.Int, 0),
+		chainContextIntegers: make(map[string]*big.Int, 0),
+		strings:              make(map[string]any, 0),
+		bytes:                make(map[string][]byte, 0),
+		preimageHashes:       make(map[string][]byte, 0),
+		functions:            make(map[[24]byte]any, 0),
+		hashProvider:         sha3.NewLegacyKeccak256(),
 	}
 	return baseValueSet
 }
 
 // Clone creates a copy of the current ValueSet.
 func (vs *ValueSet) Clone() *ValueSet {
-	baseValueSet := &ValueSet{
-		addresses:    maps.Clone(vs.addresses),
-		integers:     maps.Clone(vs.integers),
-		strings:      maps.Clone(vs.strings),
-		bytes:        maps.Clone(vs.bytes),
-		hashProvider: sha3.NewLegacyKeccak256(),
-	}
+	baseValueSet := NewValueSet()
+	baseValueSet.CopyFrom(vs)
 	return baseValueSet
 }
 
-// Addresses returns a list of addresses contained within the set.
-func (vs *ValueSet) Addresses() []common.Address {
-	res := make([]common.Address, len(vs.addresses))
-	count := 0
-	for k := range vs.addresses {
-		res[count] = k
-		count++
+// CopyFrom replaces the contents of the ValueSet with a copy of the contents of the provided ValueSet. This allows
+// a ValueSet shared with other components to be restored to a previous state captured with Clone. The sorted lists
+// of values are shared rather than copied, as they are never modified.
+func (vs *ValueSet) CopyFrom(other *ValueSet) {
+	vs.addresses = maps.Clone(other.addresses)
+	vs.addressList = other.addressList
+	vs.integers = maps.Clone(other.integers)
+	vs.chainContextIntegers = maps.Clone(other.chainContextIntegers)
+	vs.chainContextIntegerList = other.chainContextIntegerList
+	vs.integerList = other.integerList
+	vs.strings = maps.Clone(other.strings)
+	vs.stringList = other.stringList
+	vs.bytes = maps.Clone(other.bytes)
+	vs.preimageHashes = maps.Clone(other.preimageHashes)
+	vs.preimageHashList = other.preimageHashList
+	vs.byteList = other.byteList
+	vs.functions = maps.Clone(other.functions)
+	vs.functionList = other.functionList
+}
+
+// insertSorted returns a copy of the provided sorted list with the provided value inserted in order. A copy is
+// returned, so lists previously obtained from a ValueSet are left unchanged.
+func insertSorted[T any](list []T, value T, compare func(a, b T) int) []T {
+	i, _ := slices.BinarySearchFunc(list, value, compare)
+	return slices.Insert(slices.Clip(list), i, value)
+}
+
+// removeSorted returns a copy of the provided sorted list with the provided value removed, if it is contained in it.
+// A copy is returned, so lists previously obtained from a ValueSet are left unchanged.
+func removeSorted[T any](list []T, value T, compare func(a, b T) int) []T {
+	i, found := slices.BinarySearchFunc(list, value, compare)
+	if !found {
+		return list
 	}
-	return res
+	return slices.Concat(list[:i], list[i+1:])
+}
+
+// compareAddresses compares two addresses, for use in sorting.
+func compareAddresses(a, b common.Address) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+// compareFunctions compares two external function references, for use in sorting.
+func compareFunctions(a, b [24]byte) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+// Addresses returns a list of addresses contained within the set. The list is sorted so that random selections
+// made from it are reproducible, and must not be modified.
+func (vs *ValueSet) Addresses() []common.Address {
+	return slices.Clip(vs.addressList)
 }
 
 // AddAddress adds an address item to the ValueSet.
 func (vs *ValueSet) AddAddress(a common.Address) {
+	if _, exists := vs.addresses[a]; exists {
+		return
+	}
 	vs.addresses[a] = nil
+	vs.addressList = insertSorted(vs.addressList, a, compareAddresses)
 }
 
 // ContainsAddress checks if an address is contained in the ValueSet.
@@ -74,26 +151,32 @@ func (vs *ValueSet) ContainsAddress(a common.Address) bool {
 
 // RemoveAddress removes an address item from the ValueSet.
 func (vs *ValueSet) RemoveAddress(a common.Address) {
+	if _, exists := vs.addresses[a]; !exists {
+		return
+	}
 	delete(vs.addresses, a)
+	vs.addressList = removeSorted(vs.addressList, a, compareAddresses)
 }
 
-// Integers returns a list of integers contained within the set.
+// Integers returns a list of integers contained within the set, including its chain context integers. The list is
+// sorted so that random selections made from it are reproducible, and must not be modified.
 func (vs *ValueSet) Integers() []*big.Int {
-	res := make([]*big.Int, len(vs.integers))
-	count := 0
-	for _, v := range vs.integers {
-		res[count] = v
-		count++
-	}
-	return res
+	return slices.Clip(vs.integerList)
 }
 
 // AddInteger adds an integer item to the ValueSet.
 func (vs *ValueSet) AddInteger(b *big.Int) {
-	vs.integers[b.String()] = b
+	key := b.String()
+	if _, exists := vs.integers[key]; exists {
+		return
+	}
+	vs.integers[key] = b
+	if _, exists := vs.chainContextIntegers[key]; !exists {
+		vs.integerList = insertSorted(vs.integerList, b, (*big.Int).Cmp)
+	}
 }
 
-// ContainsInteger checks if an integer is contained in the ValueSet.
+// ContainsInteger checks if an integer was added to the ValueSet. Chain context integers are not considered.
 func (vs *ValueSet) ContainsInteger(b *big.Int) bool {
 	_, contains := vs.integers[b.String()]
 	return contains
@@ -101,23 +184,63 @@ func (vs *ValueSet) ContainsInteger(b *big.Int) bool {
 
 // RemoveInteger removes an integer item from the ValueSet.
 func (vs *ValueSet) RemoveInteger(b *big.Int) {
-	delete(vs.integers, b.String())
+	key := b.String()
+	existing, exists := vs.integers[key]
+	if !exists {
+		return
+	}
+	delete(vs.integers, key)
+	if _, exists = vs.chainContextIntegers[key]; !exists {
+		vs.integerList = removeSorted(vs.integerList, existing, (*big.Int).Cmp)
+	}
 }
 
-// Strings returns a list of strings contained within the set.
-func (vs *ValueSet) Strings() []string {
-	res := make([]string, len(vs.strings))
-	count := 0
-	for k := range vs.strings {
-		res[count] = k
-		count++
+// ChainContextIntegers returns a list of the chain context integers contained within the set. The list is sorted so
+// that random selections made from it are reproducible, and must not be modified.
+func (vs *ValueSet) ChainContextIntegers() []*big.Int {
+	return slices.Clip(vs.chainContextIntegerList)
+}
+
+// SetChainContextIntegers replaces the chain context integers contained within the set with the provided integers.
+// Chain context integers are derived from the state of the chain being fuzzed (e.g. the current block number and
+// timestamp), so previously set values are discarded rather than accumulated as the chain progresses.
+func (vs *ValueSet) SetChainContextIntegers(integers []*big.Int) {
+	// Remove the previous chain context integers from our list of integers, unless they were otherwise added.
+	for key, b := range vs.chainContextIntegers {
+		if _, exists := vs.integers[key]; !exists {
+			vs.integerList = removeSorted(vs.integerList, b, (*big.Int).Cmp)
+		}
+	}
+
+	// Add the new chain context integers, adding those not otherwise added to our list of integers.
+	vs.chainContextIntegers = make(map[string]*big.Int, len(integers))
+	vs.chainContextIntegerList = make([]*big.Int, 0, len(integers))
+	for _, b := range integers {
+		key := b.String()
+		if _, exists := vs.chainContextIntegers[key]; exists {
+			continue
+		}
+		vs.chainContextIntegers[key] = b
+		vs.chainContextIntegerList = insertSorted(vs.chainContextIntegerList, b, (*big.Int).Cmp)
+		if _, exists := vs.integers[key]; !exists {
+			vs.integerList = insertSorted(vs.integerList, b, (*big.Int).Cmp)
+		}
 	}
-	return res
+}
+
+// Strings returns a list of strings contained within the set. The list is sorted so that random selections made
+// from it are reproducible, and must not be modified.
+func (vs *ValueSet) Strings() []string {
+	return slices.Clip(vs.stringList)
 }
 
 // AddString adds a string item to the ValueSet.
 func (vs *ValueSet) AddString(s string) {
+	if _, exists := vs.strings[s]; exists {
+		return
+	}
 	vs.strings[s] = nil
+	vs.stringList = insertSorted(vs.stringList, s, strings.Compare)
 }
 
 // ContainsString checks if a string is contained in the ValueSet.
@@ -128,18 +251,17 @@ func (vs *ValueSet) ContainsString(s string) bool {
 
 // RemoveString removes a string item from the ValueSet.
 func (vs *ValueSet) RemoveString(s string) {
+	if _, exists := vs.strings[s]; !exists {
+		return
+	}
 	delete(vs.strings, s)
+	vs.stringList = removeSorted(vs.stringList, s, strings.Compare)
 }
 
-// Bytes returns a list of bytes contained within the set.
+// Bytes returns a list of bytes contained within the set, including its preimage hashes. The list is sorted so that
+// random selections made from it are reproducible, and must not be modified.
 func (vs *ValueSet) Bytes() [][]byte {
-	res := make([][]byte, len(vs.bytes))
-	count := 0
-	for _, v := range vs.bytes {
-		res[count] = v
-		count++
-	}
-	return res
+	return slices.Clip(vs.byteList)
 }
 
 // AddBytes adds a byte sequence to the ValueSet.
@@ -150,7 +272,13 @@ func (vs *ValueSet) AddBytes(b []byte) {
 	vs.hashProvider.Reset()
 
 	// Add our hash to our "set" (map)
+	if _, exists := vs.bytes[hashStr]; exists {
+		return
+	}
 	vs.bytes[hashStr] = b
+	if _, exists := vs.preimageHashes[hashStr]; !exists {
+		vs.byteList = insertSorted(vs.byteList, b, bytes.Compare)
+	}
 }
 
 // ContainsBytes checks if a byte sequence is contained in the ValueSet.
@@ -172,7 +300,56 @@ func (vs *ValueSet) RemoveBytes(b []byte) {
 	hashStr := hex.EncodeToString(vs.hashProvider.Sum(nil))
 	vs.hashProvider.Reset()
 
+	existing, exists := vs.bytes[hashStr]
+	if !exists {
+		return
+	}
 	delete(vs.bytes, hashStr)
+	if _, exists = vs.preimageHashes[hashStr]; !exists {
+		vs.byteList = removeSorted(vs.byteList, existing, bytes.Compare)
+	}
+}
+
+// Functions returns a list of external function references contained within the set. The list is sorted so that
+// random selections made from it are reproducible, and must not be modified.
+func (vs *ValueSet) Functions() [][24]byte {
+	return slices.Clip(vs.functionList)
+}
+
+// AddFunction adds an external function reference, described by the address of a contract and the selector of one of
+// its methods, to the ValueSet.
+func (vs *ValueSet) AddFunction(address common.Address, selector []byte) {
+	function := newFunctionReference(address, selector)
+	if _, exists := vs.functions[function]; exists {
+		return
+	}
+	vs.functions[function] = nil
+	vs.functionList = insertSorted(vs.functionList, function, compareFunctions)
+}
+
+// ContainsFunction checks if an external function reference is contained in the ValueSet.
+func (vs *ValueSet) ContainsFunction(address common.Address, selector []byte) bool {
+	_, contains := vs.functions[newFunctionReference(address, selector)]
+	return contains
+}
+
+// RemoveFunction removes an external function reference from the ValueSet.
+func (vs *ValueSet) RemoveFunction(address common.Address, selector []byte) {
+	function := newFunctionReference(address, selector)
+	if _, exists := vs.functions[function]; !exists {
+		return
+	}
+	delete(vs.functions, function)
+	vs.functionList = removeSorted(vs.functionList, function, compareFunctions)
+}
+
+// newFunctionReference creates an ABI encodable external function reference from the provided address and function
+// selector.
+func newFunctionReference(address common.Address, selector []byte) [24]byte {
+	var function [24]byte
+	copy(function[:common.AddressLength], address.Bytes())
+	copy(function[common.AddressLength:], selector)
+	return function
 }
 
 // Add adds one or more values. Note the values must be a primitive type (signed/unsigned integer, address, string,
@@ -0,0 +1,94 @@
+package valuegeneration
+
+import (
+	"bytes"
+	"encoding/hex"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/exp/maps"
+)
+
+// wellKnownPreimages describes strings commonly hashed to derive identifiers compared against bytes32 arguments, such
+// as AccessControl role identifiers, EIP-712 type hashes, and EIP-1967 storage slot names.
+var wellKnownPreimages = []string{
+	"ADMIN_ROLE",
+	"BURNER_ROLE",
+	"CANCELLER_ROLE",
+	"EXECUTOR_ROLE",
+	"GOVERNOR_ROLE",
+	"GUARDIAN_ROLE",
+	"KEEPER_ROLE",
+	"MANAGER_ROLE",
+	"MINTER_ROLE",
+	"OPERATOR_ROLE",
+	"ORACLE_ROLE",
+	"PAUSER_ROLE",
+	"PROPOSER_ROLE",
+	"SNAPSHOT_ROLE",
+	"TIMELOCK_ADMIN_ROLE",
+	"UPGRADER_ROLE",
+	"URI_SETTER_ROLE",
+	"EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)",
+	"Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)",
+	"eip1967.proxy.implementation",
+	"eip1967.proxy.admin",
+	"eip1967.proxy.beacon",
+}
+
+// DerivePreimageHashes replaces the preimage hashes contained within the set with the keccak256 hashes of the strings
+// and bytes it contains, followed by those of well-known preimages such as role identifiers, so bytes32 arguments
+// compared against hashes of guessable values can be generated. At most maxHashes hashes are derived, preferring
+// those of the strings and bytes contained within the set. It should be called again whenever new strings or bytes
+// are added to the set, so their hashes are derived. If maxHashes is zero or negative, no hashes are derived.
+func (vs *ValueSet) DerivePreimageHashes(maxHashes int) {
+	// Remove our previous preimage hashes from our list of bytes, unless they were otherwise added.
+	for key, preimageHash := range vs.preimageHashes {
+		if _, exists := vs.bytes[key]; !exists {
+			vs.byteList = removeSorted(vs.byteList, preimageHash, bytes.Compare)
+		}
+	}
+	vs.preimageHashes = make(map[string][]byte)
+	vs.preimageHashList = nil
+	if maxHashes <= 0 {
+		return
+	}
+
+	// Collect our preimages, in a stable order, so the same hashes are derived when the set is bounded.
+	preimages := make([][]byte, 0, len(vs.strings)+len(vs.bytes)+len(wellKnownPreimages))
+	for _, s := range vs.Strings() {
+		preimages = append(preimages, []byte(s))
+	}
+	byteValues := maps.Values(vs.bytes)
+	slices.SortFunc(byteValues, func(a, b []byte) int {
+		return slices.Compare(a, b)
+	})
+	preimages = append(preimages, byteValues...)
+	for _, s := range wellKnownPreimages {
+		preimages = append(preimages, []byte(s))
+	}
+
+	// Hash each preimage, keying each hash as the bytes set is keyed, so a hash which is also contained in it is not
+	// returned twice.
+	for _, preimage := range preimages {
+		if len(vs.preimageHashes) >= maxHashes {
+			break
+		}
+		preimageHash := crypto.Keccak256(preimage)
+		key := hex.EncodeToString(crypto.Keccak256(preimageHash))
+		if _, exists := vs.preimageHashes[key]; exists {
+			continue
+		}
+		vs.preimageHashes[key] = preimageHash
+		vs.preimageHashList = insertSorted(vs.preimageHashList, preimageHash, bytes.Compare)
+		if _, exists := vs.bytes[key]; !exists {
+			vs.byteList = insertSorted(vs.byteList, preimageHash, bytes.Compare)
+		}
+	}
+}
+
+// PreimageHashes returns a list of the preimage hashes contained within the set. The list is sorted so that random
+// selections made from it are reproducible, and must not be modified.
+func (vs *ValueSet) PreimageHashes() [][]byte {
+	return slices.Clip(vs.preimageHashList)
+}
@@ -0,0 +1,71 @@
+package valuegeneration
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDerivePreimageHashes tests that a ValueSet derives the hashes of the strings and bytes it contains and of
+// well-known preimages, preferring those it contains when bounded, and replaces them when derived again.
+func TestDerivePreimageHashes(t *testing.T) {
+	valueSet := NewValueSet()
+	valueSet.AddString("TREASURER_ROLE")
+	valueSet.AddBytes([]byte{0x01, 0x02})
+
+	// The hashes of our string, our bytes, and well-known role identifiers should be derived.
+	valueSet.DerivePreimageHashes(1024)
+	assert.Len(t, valueSet.PreimageHashes(), 2+len(wellKnownPreimages))
+	assert.Contains(t, valueSet.Bytes(), crypto.Keccak256([]byte("TREASURER_ROLE")))
+	assert.Contains(t, valueSet.Bytes(), crypto.Keccak256([]byte{0x01, 0x02}))
+	assert.Contains(t, valueSet.Bytes(), crypto.Keccak256([]byte("MINTER_ROLE")))
+
+	// When bounded, the hashes of the values we contain should be preferred.
+	valueSet.DerivePreimageHashes(1)
+	assert.EqualValues(t, [][]byte{crypto.Keccak256([]byte("TREASURER_ROLE"))}, valueSet.PreimageHashes())
+
+	// Newly added values should have their hashes derived once derived again, and clones should retain them.
+	valueSet.AddString("AUDITOR_ROLE")
+	valueSet.DerivePreimageHashes(1024)
+	assert.Contains(t, valueSet.Clone().Bytes(), crypto.Keccak256([]byte("AUDITOR_ROLE")))
+
+	// Hashes should not be derived if disabled.
+	valueSet.DerivePreimageHashes(0)
+	assert.Empty(t, valueSet.PreimageHashes())
+	assert.Len(t, valueSet.Bytes(), 1)
+}
+
+// TestValueSetSortedLists tests that the lists of values obtained from a ValueSet remain sorted and free of duplicates
+// as values are added and removed, that lists obtained previously are left unchanged, and that a ValueSet restored
+// from a clone returns the lists it had when cloned.
+func TestValueSetSortedLists(t *testing.T) {
+	valueSet := NewValueSet()
+	for _, i := range []int64{5, 1, 3, 1} {
+		valueSet.AddInteger(big.NewInt(i))
+	}
+	valueSet.SetChainContextIntegers([]*big.Int{big.NewInt(4), big.NewInt(3)})
+	valueSet.AddString("b")
+	valueSet.AddString("a")
+	assert.EqualValues(t, []*big.Int{big.NewInt(1), big.NewInt(3), big.NewInt(4), big.NewInt(5)}, valueSet.Integers())
+	assert.EqualValues(t, []*big.Int{big.NewInt(3), big.NewInt(4)}, valueSet.ChainContextIntegers())
+	assert.EqualValues(t, []string{"a", "b"}, valueSet.Strings())
+
+	// Changes to the set should not alter previously obtained lists, nor the lists of a clone.
+	clone := valueSet.Clone()
+	integers := valueSet.Integers()
+	valueSet.AddInteger(big.NewInt(2))
+	valueSet.RemoveInteger(big.NewInt(3))
+	valueSet.SetChainContextIntegers([]*big.Int{big.NewInt(6)})
+	valueSet.RemoveString("a")
+	assert.EqualValues(t, []*big.Int{big.NewInt(1), big.NewInt(3), big.NewInt(4), big.NewInt(5)}, integers)
+	assert.EqualValues(t, []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(5), big.NewInt(6)}, valueSet.Integers())
+	assert.EqualValues(t, []string{"b"}, valueSet.Strings())
+	assert.EqualValues(t, integers, clone.Integers())
+
+	// Restoring the set from its clone should restore its lists.
+	valueSet.CopyFrom(clone)
+	assert.EqualValues(t, integers, valueSet.Integers())
+	assert.EqualValues(t, []string{"a", "b"}, valueSet.Strings())
+}
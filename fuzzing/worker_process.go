@@ -0,0 +1,1003 @@
+package fuzzing
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/crytic/medusa/chain"
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/config"
+	fuzzerTypes "github.com/crytic/medusa/fuzzing/contracts"
+	"github.com/crytic/medusa/fuzzing/coverage"
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+	"github.com/crytic/medusa/utils"
+	"github.com/crytic/medusa/utils/randomutils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// workerProcessEnvironmentVariable describes the environment variable set for worker processes spawned by a Fuzzer,
+// so the executable can detect that it should run a worker rather than its usual entry point.
+const workerProcessEnvironmentVariable = "MEDUSA_WORKER_PROCESS"
+
+// workerProcessOutputTailSize describes the amount of trailing bytes of a worker process' output which are kept, so
+// they can be reported if the worker process crashes.
+const workerProcessOutputTailSize = 8192
+
+// workerProcessMetricsInterval describes how often a worker process reports its metrics to the Fuzzer which spawned it.
+const workerProcessMetricsInterval = time.Second
+
+// workerProcessMessageType describes the type of a workerProcessMessage as a string-represented enum.
+type workerProcessMessageType string
+
+const (
+	// workerProcessMessageReady describes a message sent once a worker process set up its test chain and replayed the
+	// corpus, and is about to begin fuzzing.
+	workerProcessMessageReady workerProcessMessageType = "ready"
+	// workerProcessMessageCall describes a message sent before a worker process executes each call of a call sequence,
+	// so the call sequence can be reported if the worker process crashes while executing it.
+	workerProcessMessageCall workerProcessMessageType = "call"
+	// workerProcessMessageCoverage describes a message sent when a worker process achieves new coverage.
+	workerProcessMessageCoverage workerProcessMessageType = "coverage"
+	// workerProcessMessageTestCase describes a message sent when a worker process reports a test case result.
+	workerProcessMessageTestCase workerProcessMessageType = "testCase"
+	// workerProcessMessageMetrics describes a message periodically sent by a worker process, describing its metrics.
+	workerProcessMessageMetrics workerProcessMessageType = "metrics"
+)
+
+// workerProcessInitialization describes the campaign a worker process should fuzz. It is sent by the Fuzzer to the
+// standard input of each worker process it spawns, followed by a newline. The worker process stops fuzzing once its
+// standard input is closed.
+type workerProcessInitialization struct {
+	// Config describes the project configuration the worker process should fuzz with.
+	Config config.ProjectConfig `json:"config"`
+
+	// WorkerIndex describes the index of the worker slot the worker process was spawned for.
+	WorkerIndex int `json:"workerIndex"`
+}
+
+// workerProcessMessage describes a message sent by a worker process to the Fuzzer which spawned it. Messages are
+// written to the standard output of the worker process as newline-delimited JSON, while its logs are written to its
+// standard error.
+type workerProcessMessage struct {
+	// Type describes the type of the message, which determines the fields set.
+	Type workerProcessMessageType `json:"type"`
+
+	// CallIndex describes the index of the call within the call sequence being executed, for call messages.
+	CallIndex int `json:"callIndex,omitempty"`
+
+	// Call describes the encoded calls.CallSequenceElement about to be executed, for call messages.
+	Call json.RawMessage `json:"call,omitempty"`
+
+	// CallContract describes the name of the contract definition targeted by the call, for call messages.
+	CallContract string `json:"callContract,omitempty"`
+
+	// CallSequence describes the call sequence which achieved new coverage, for coverage messages.
+	CallSequence calls.CallSequence `json:"callSequence,omitempty"`
+
+	// CallSequenceContracts describes the name of the contract definition targeted by each call of the call sequence,
+	// for coverage messages.
+	CallSequenceContracts []string `json:"callSequenceContracts,omitempty"`
+
+	// CoverageDelta describes the new coverage achieved by the call sequence, for coverage messages.
+	CoverageDelta *coverage.CoverageDelta `json:"coverageDelta,omitempty"`
+
+	// Successful indicates whether the last call of the call sequence did not revert, for coverage messages.
+	Successful bool `json:"successful,omitempty"`
+
+	// Shrinking indicates whether the coverage was achieved while verifying a shrunken call sequence, for coverage
+	// messages.
+	Shrinking bool `json:"shrinking,omitempty"`
+
+	// TestCase describes the test case result reported, for test case messages.
+	TestCase *workerProcessTestCase `json:"testCase,omitempty"`
+
+	// Metrics describes the metrics of the worker process, for metrics messages.
+	Metrics *workerProcessMetrics `json:"metrics,omitempty"`
+}
+
+// workerProcessCorpusEntry describes a call sequence which achieved new coverage in a worker process. It is sent by
+// the Fuzzer which spawned the worker processes to each of the others, as newline-delimited JSON following the
+// workerProcessInitialization, so they can mutate it and do not report the same coverage again.
+type workerProcessCorpusEntry struct {
+	// CallSequence describes the call sequence which achieved new coverage.
+	CallSequence calls.CallSequence `json:"callSequence"`
+
+	// CallSequenceContracts describes the name of the contract definition targeted by each call of the call sequence.
+	CallSequenceContracts []string `json:"callSequenceContracts"`
+
+	// CoverageDelta describes the new coverage achieved by the call sequence.
+	CoverageDelta *coverage.CoverageDelta `json:"coverageDelta"`
+}
+
+// workerProcessMetrics describes the metrics of a worker process, totalled across every FuzzerWorker it created.
+type workerProcessMetrics struct {
+	// SequencesTested describes the amount of call sequences tested.
+	SequencesTested *big.Int `json:"sequencesTested"`
+
+	// FailedSequences describes the amount of call sequences tested which failed a test.
+	FailedSequences *big.Int `json:"failedSequences"`
+
+	// CallsTested describes the amount of calls tested.
+	CallsTested *big.Int `json:"callsTested"`
+
+	// SequencesReplayed describes the amount of call sequences tested which were replayed from the corpus.
+	SequencesReplayed *big.Int `json:"sequencesReplayed"`
+
+	// CallsReplayed describes the amount of calls tested which were replayed from the corpus.
+	CallsReplayed *big.Int `json:"callsReplayed"`
+
+	// GasUsed describes the amount of gas used by the calls tested.
+	GasUsed *big.Int `json:"gasUsed"`
+
+	// WorkerStartupCount describes the amount of times a FuzzerWorker was created.
+	WorkerStartupCount *big.Int `json:"workerStartupCount"`
+}
+
+// add returns the sum of the workerProcessMetrics and the provided workerProcessMetrics. Metrics which are not set are
+// treated as zero.
+func (m workerProcessMetrics) add(other workerProcessMetrics) workerProcessMetrics {
+	sum := func(a *big.Int, b *big.Int) *big.Int {
+		result := big.NewInt(0)
+		if a != nil {
+			result.Add(result, a)
+		}
+		if b != nil {
+			result.Add(result, b)
+		}
+		return result
+	}
+	return workerProcessMetrics{
+		SequencesTested:    sum(m.SequencesTested, other.SequencesTested),
+		FailedSequences:    sum(m.FailedSequences, other.FailedSequences),
+		CallsTested:        sum(m.CallsTested, other.CallsTested),
+		SequencesReplayed:  sum(m.SequencesReplayed, other.SequencesReplayed),
+		CallsReplayed:      sum(m.CallsReplayed, other.CallsReplayed),
+		GasUsed:            sum(m.GasUsed, other.GasUsed),
+		WorkerStartupCount: sum(m.WorkerStartupCount, other.WorkerStartupCount),
+	}
+}
+
+// workerProcessTestCase implements TestCase for a test case result reported by a worker process. It describes the
+// result as rendered by the worker process, as the test case providers of the Fuzzer which spawned it do not observe
+// the worker process' execution.
+type workerProcessTestCase struct {
+	// TestID describes the unique identifier of the test case.
+	TestID string `json:"id"`
+
+	// TestName describes the name of the test case.
+	TestName string `json:"name"`
+
+	// TestStatus describes the status of the test case.
+	TestStatus TestCaseStatus `json:"status"`
+
+	// TestMessage describes the printable result of the test case.
+	TestMessage string `json:"message"`
+
+	// TestCallSequence describes the call sequence which caused the test case to fail, or nil if it did not fail.
+	TestCallSequence *calls.CallSequence `json:"callSequence,omitempty"`
+
+	// TestCallSequenceContracts describes the name of the contract definition targeted by each call of
+	// TestCallSequence.
+	TestCallSequenceContracts []string `json:"callSequenceContracts,omitempty"`
+}
+
+// Status describes the TestCaseStatus used to define the current state of the test.
+func (t *workerProcessTestCase) Status() TestCaseStatus {
+	return t.TestStatus
+}
+
+// CallSequence describes the types.CallSequence of calls sent to the EVM which resulted in this TestCase result.
+// This should be nil if the result is not related to the CallSequence.
+func (t *workerProcessTestCase) CallSequence() *calls.CallSequence {
+	return t.TestCallSequence
+}
+
+// Name describes the name of the test case.
+func (t *workerProcessTestCase) Name() string {
+	return t.TestName
+}
+
+// LogMessage obtains a buffer that represents the result of the test case, as rendered by the worker process.
+func (t *workerProcessTestCase) LogMessage() *logging.LogBuffer {
+	buffer := logging.NewLogBuffer()
+	buffer.Append(t.TestMessage)
+	return buffer
+}
+
+// Message obtains a text-based printable message which describes the result of the test case.
+func (t *workerProcessTestCase) Message() string {
+	return t.TestMessage
+}
+
+// ID obtains a unique identifier for a test result.
+func (t *workerProcessTestCase) ID() string {
+	return t.TestID
+}
+
+// workerProcessTestCaseStatusRank ranks a TestCaseStatus by how conclusive it is, so the most conclusive result
+// reported for a test case across worker processes is kept.
+func workerProcessTestCaseStatusRank(status TestCaseStatus) int {
+	switch status {
+	case TestCaseStatusFailed:
+		return 3
+	case TestCaseStatusPassed, TestCaseStatusMuted:
+		return 2
+	case TestCaseStatusRunning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// workerProcessOutput implements io.Writer to keep the trailing output of a worker process, so it can be reported if
+// the worker process crashes. It is safe for concurrent use.
+type workerProcessOutput struct {
+	// data describes the trailing output kept.
+	data []byte
+
+	// limit describes the maximum amount of trailing bytes to keep.
+	limit int
+
+	// lock provides thread synchronization when writing or reading the output.
+	lock sync.Mutex
+}
+
+// Write appends the provided data to the output, discarding the oldest output beyond the limit.
+func (o *workerProcessOutput) Write(p []byte) (int, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.data = append(o.data, p...)
+	if len(o.data) > o.limit {
+		o.data = append([]byte(nil), o.data[len(o.data)-o.limit:]...)
+	}
+	return len(p), nil
+}
+
+// String returns the trailing output kept.
+func (o *workerProcessOutput) String() string {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return string(o.data)
+}
+
+// IsWorkerProcess indicates whether the current process was spawned by a Fuzzer to run a worker, in which case
+// RunWorkerProcess should be called in place of the executable's usual entry point.
+func IsWorkerProcess() bool {
+	return os.Getenv(workerProcessEnvironmentVariable) != ""
+}
+
+// RunWorkerProcess runs the worker the current process was spawned for by a Fuzzer with worker processes enabled,
+// reporting its discoveries back to the Fuzzer until it signals the worker process to stop.
+// Returns the exit code the process should exit with.
+func RunWorkerProcess() int {
+	return runWorkerProcess(nil)
+}
+
+// runWorkerProcess implements RunWorkerProcess, invoking the provided function (if any) with the Fuzzer created for
+// the worker process before it starts fuzzing.
+// Returns the exit code the process should exit with.
+func runWorkerProcess(fuzzerCreated func(fuzzer *Fuzzer)) int {
+	// Our standard output is reserved for messages to the Fuzzer which spawned us, so anything else which would be
+	// written to it (e.g. our logs) is written to our standard error instead.
+	messageOutput := os.Stdout
+	os.Stdout = os.Stderr
+
+	// Interrupts sent to the terminal are handled by the Fuzzer which spawned us, which will signal us to stop.
+	signal.Ignore(os.Interrupt)
+
+	// Read the campaign we should fuzz.
+	input := bufio.NewReader(os.Stdin)
+	initializationData, err := input.ReadBytes('\n')
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to read the worker process initialization message:", err)
+		return 1
+	}
+	var initialization workerProcessInitialization
+	if err = json.Unmarshal(initializationData, &initialization); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to decode the worker process initialization message:", err)
+		return 1
+	}
+
+	// Create our fuzzer with a single in-process worker, leaving decisions about when to stop fuzzing, and the
+	// writing of the corpus and reports, to the Fuzzer which spawned us.
+	fuzzer, err := NewFuzzer(workerProcessConfig(initialization.Config))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to create the worker process fuzzer:", err)
+		return 1
+	}
+	client := &workerProcessClient{
+		fuzzer:      fuzzer,
+		workerIndex: initialization.WorkerIndex,
+		encoder:     json.NewEncoder(messageOutput),
+	}
+	fuzzer.workerProcess = client
+	fuzzer.Events.FuzzerStarting.SubscribeNamed("worker process client", client.onFuzzerStarting)
+	fuzzer.Events.WorkerNewCoverage.SubscribeNamed("worker process client", client.onWorkerNewCoverage)
+	if fuzzerCreated != nil {
+		fuzzerCreated(fuzzer)
+	}
+
+	// Receive the call sequences which achieved new coverage in other worker processes, and stop fuzzing once our
+	// standard input is closed.
+	go func() {
+		client.receiveCorpusEntries(input)
+		client.stopped.Store(true)
+		fuzzer.Stop()
+	}()
+
+	// Fuzz, then report the final result of every test case, and our final metrics.
+	err = fuzzer.Start()
+	for _, testCase := range fuzzer.TestCases() {
+		if reportErr := client.reportTestCase(testCase); reportErr != nil && err == nil {
+			err = reportErr
+		}
+	}
+	if reportErr := client.reportMetrics(); reportErr != nil && err == nil {
+		err = reportErr
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "worker process failed:", err)
+		return 1
+	}
+	return 0
+}
+
+// workerProcessConfig returns the project configuration a worker process fuzzes with, derived from the provided
+// project configuration of the Fuzzer which spawned it.
+func workerProcessConfig(projectConfig config.ProjectConfig) config.ProjectConfig {
+	projectConfig.Fuzzing.Workers = 1
+	projectConfig.Fuzzing.WorkerProcesses.Enabled = false
+	projectConfig.Fuzzing.TestLimit = 0
+	projectConfig.Fuzzing.Timeout = 0
+	projectConfig.Fuzzing.StopOnCoveragePlateau = 0
+	projectConfig.Fuzzing.Testing.StopOnFailedTest = false
+	projectConfig.Fuzzing.LiveReport = false
+	projectConfig.Fuzzing.CoverageFormats = nil
+	projectConfig.Logging.LogDirectory = ""
+	projectConfig.Logging.NoColor = true
+	return projectConfig
+}
+
+// workerProcessClient reports the discoveries of the Fuzzer running in a worker process to the Fuzzer which spawned
+// it.
+type workerProcessClient struct {
+	// fuzzer describes the Fuzzer running in the worker process.
+	fuzzer *Fuzzer
+
+	// workerIndex describes the index of the worker slot the worker process was spawned for.
+	workerIndex int
+
+	// encoder describes the encoder used to write messages to the Fuzzer which spawned the worker process.
+	encoder *json.Encoder
+
+	// encoderLock provides thread synchronization when writing messages.
+	encoderLock sync.Mutex
+
+	// stopped indicates whether the Fuzzer which spawned the worker process signaled it to stop.
+	stopped atomic.Bool
+}
+
+// send writes the provided message to the Fuzzer which spawned the worker process. If it cannot be written, the
+// Fuzzer which spawned the worker process is gone, so fuzzing is terminated.
+// Returns an error if one occurs.
+func (c *workerProcessClient) send(message workerProcessMessage) error {
+	c.encoderLock.Lock()
+	defer c.encoderLock.Unlock()
+	if err := c.encoder.Encode(message); err != nil {
+		c.fuzzer.Terminate()
+		return fmt.Errorf("failed to send a %s message to the fuzzer which spawned the worker process: %v", message.Type, err)
+	}
+	return nil
+}
+
+// reportCall reports the call of a call sequence about to be executed at the provided index.
+// Returns an error if one occurs.
+func (c *workerProcessClient) reportCall(callIndex int, element *calls.CallSequenceElement) error {
+	encodedElement, err := json.Marshal(element)
+	if err != nil {
+		return err
+	}
+	return c.send(workerProcessMessage{Type: workerProcessMessageCall, CallIndex: callIndex, Call: encodedElement, CallContract: workerProcessCallContract(element)})
+}
+
+// receiveCorpusEntries adds the call sequences which achieved new coverage in other worker processes, sent by the
+// Fuzzer which spawned the worker process, to the corpus of the worker process until the provided input is closed.
+// Call sequences which cannot be decoded or resolved are logged and skipped.
+func (c *workerProcessClient) receiveCorpusEntries(input io.Reader) {
+	decoder := json.NewDecoder(input)
+	for {
+		var entry workerProcessCorpusEntry
+		if err := decoder.Decode(&entry); err != nil {
+			if !errors.Is(err, io.EOF) {
+				c.fuzzer.logger.Error("Failed to decode a call sequence from another worker process", err)
+				_, _ = io.Copy(io.Discard, input)
+			}
+			return
+		}
+		err := c.fuzzer.resolveWorkerProcessCallSequence(entry.CallSequence, entry.CallSequenceContracts)
+		if err == nil && entry.CoverageDelta != nil {
+			_, err = c.fuzzer.corpus.AddCallSequenceWithCoverageDelta(entry.CallSequence, entry.CoverageDelta, c.fuzzer.workerProcessCorpusWeight(), false)
+		}
+		if err != nil {
+			c.fuzzer.logger.Error("Failed to add a call sequence from another worker process to the corpus", err)
+		}
+	}
+}
+
+// reportTestCase reports the current result of the provided test case.
+// Returns an error if one occurs.
+func (c *workerProcessClient) reportTestCase(testCase TestCase) error {
+	reportedTestCase := &workerProcessTestCase{
+		TestID:      testCase.ID(),
+		TestName:    testCase.Name(),
+		TestStatus:  testCase.Status(),
+		TestMessage: testCase.Message(),
+	}
+	if testCase.Status() == TestCaseStatusFailed {
+		reportedTestCase.TestCallSequence = testCase.CallSequence()
+		if reportedTestCase.TestCallSequence != nil {
+			reportedTestCase.TestCallSequenceContracts = workerProcessCallSequenceContracts(*reportedTestCase.TestCallSequence)
+		}
+	}
+	return c.send(workerProcessMessage{Type: workerProcessMessageTestCase, TestCase: reportedTestCase})
+}
+
+// reportMetrics reports the current metrics of the worker process.
+// Returns an error if one occurs.
+func (c *workerProcessClient) reportMetrics() error {
+	metrics := c.fuzzer.metrics
+	if metrics == nil {
+		return nil
+	}
+	return c.send(workerProcessMessage{
+		Type: workerProcessMessageMetrics,
+		Metrics: &workerProcessMetrics{
+			SequencesTested:    metrics.SequencesTested(),
+			FailedSequences:    metrics.FailedSequences(),
+			CallsTested:        metrics.CallsTested(),
+			SequencesReplayed:  metrics.SequencesReplayed(),
+			CallsReplayed:      metrics.CallsReplayed(),
+			GasUsed:            metrics.GasUsed(),
+			WorkerStartupCount: metrics.WorkerStartupCount(),
+		},
+	})
+}
+
+// onFuzzerStarting is the event handler triggered when the Fuzzer in the worker process is about to begin fuzzing. It
+// signals the Fuzzer which spawned the worker process that it is ready, and begins reporting its metrics.
+func (c *workerProcessClient) onFuzzerStarting(event FuzzerStartingEvent) error {
+	if err := c.send(workerProcessMessage{Type: workerProcessMessageReady}); err != nil {
+		return err
+	}
+
+	// If we were signaled to stop before the fuzzer could be stopped, stop it now.
+	if c.stopped.Load() {
+		event.Fuzzer.Stop()
+	}
+
+	go func() {
+		for !utils.CheckContextDone(event.Fuzzer.ctx) {
+			time.Sleep(workerProcessMetricsInterval)
+			if err := c.reportMetrics(); err != nil {
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// onWorkerNewCoverage is the event handler triggered when the worker in the worker process achieves new coverage. It
+// reports the call sequence and its new coverage.
+func (c *workerProcessClient) onWorkerNewCoverage(event FuzzerWorkerNewCoverageEvent) error {
+	lastCall := event.CallSequence[len(event.CallSequence)-1]
+	successful := lastCall.ChainReference != nil && lastCall.ChainReference.MessageResults().ExecutionResult.Err == nil
+	return c.send(workerProcessMessage{
+		Type:                  workerProcessMessageCoverage,
+		CallSequence:          event.CallSequence,
+		CallSequenceContracts: workerProcessCallSequenceContracts(event.CallSequence),
+		CoverageDelta:         event.CoverageDelta,
+		Successful:            successful,
+		Shrinking:             event.Shrinking,
+	})
+}
+
+// workerProcessCallContract returns the name of the contract definition targeted by the provided call, or an empty
+// string if it targets none.
+func workerProcessCallContract(element *calls.CallSequenceElement) string {
+	if element == nil || element.Contract == nil {
+		return ""
+	}
+	return element.Contract.Name()
+}
+
+// workerProcessCallSequenceContracts returns the name of the contract definition targeted by each call of the provided
+// call sequence, as reported alongside call sequences exchanged with worker processes.
+func workerProcessCallSequenceContracts(callSequence calls.CallSequence) []string {
+	contractNames := make([]string, len(callSequence))
+	for i, element := range callSequence {
+		contractNames[i] = workerProcessCallContract(element)
+	}
+	return contractNames
+}
+
+// workerProcessSlot tracks the worker processes spawned for a worker slot over the course of a fuzzing campaign.
+type workerProcessSlot struct {
+	// index describes the index of the worker slot.
+	index int
+
+	// randomProvider describes the provider used to derive the seed of each worker process spawned for the slot.
+	randomProvider *rand.Rand
+
+	// baseMetrics describes the metrics of the worker processes previously spawned for the slot.
+	baseMetrics workerProcessMetrics
+
+	// processMetrics describes the metrics last reported by the current worker process.
+	processMetrics workerProcessMetrics
+
+	// currentCallSequence describes the encoded calls of the call sequence the current worker process is executing.
+	currentCallSequence []json.RawMessage
+
+	// currentCallContracts describes the name of the contract definition targeted by each call of
+	// currentCallSequence.
+	currentCallContracts []string
+
+	// input describes the encoder used to send call sequences which achieved new coverage in other worker processes to
+	// the current worker process, or nil if it is not ready to receive them.
+	input *json.Encoder
+
+	// inputLock provides thread synchronization when sending to the current worker process.
+	inputLock sync.Mutex
+}
+
+// setInput sets the encoder used to send call sequences to the current worker process, or nil if it can no longer
+// receive them.
+func (s *workerProcessSlot) setInput(input *json.Encoder) {
+	s.inputLock.Lock()
+	defer s.inputLock.Unlock()
+	s.input = input
+}
+
+// sendCorpusEntry sends the provided call sequence which achieved new coverage in another worker process to the
+// current worker process, if it is ready to receive it.
+// Returns an error if one occurs.
+func (s *workerProcessSlot) sendCorpusEntry(entry workerProcessCorpusEntry) error {
+	s.inputLock.Lock()
+	defer s.inputLock.Unlock()
+	if s.input == nil {
+		return nil
+	}
+	return s.input.Encode(entry)
+}
+
+// updateMetrics records the provided metrics reported by the current worker process, updating the provided metrics
+// of the worker slot to the total across every worker process spawned for it.
+func (s *workerProcessSlot) updateMetrics(slotMetrics *fuzzerWorkerMetrics, processMetrics workerProcessMetrics) {
+	s.processMetrics = processMetrics
+	total := s.baseMetrics.add(processMetrics)
+	slotMetrics.sequencesTested = total.SequencesTested
+	slotMetrics.failedSequences = total.FailedSequences
+	slotMetrics.callsTested = total.CallsTested
+	slotMetrics.sequencesReplayed = total.SequencesReplayed
+	slotMetrics.callsReplayed = total.CallsReplayed
+	slotMetrics.gasUsed = total.GasUsed
+	slotMetrics.workerStartupCount = total.WorkerStartupCount
+}
+
+// processExited records that the current worker process exited, so the metrics it last reported are kept as the
+// slot's base metrics for the next worker process.
+func (s *workerProcessSlot) processExited() {
+	s.baseMetrics = s.baseMetrics.add(s.processMetrics)
+	s.processMetrics = workerProcessMetrics{}
+	s.currentCallSequence = nil
+	s.currentCallContracts = nil
+}
+
+// spawnWorkerProcessesLoop spawns a config-defined amount of worker processes to carry out the fuzzing campaign,
+// respawning worker processes which crash. Worker processes are started one at a time, so they do not compile the
+// project concurrently. This function exits once every worker process exited after Fuzzer.ctx was cancelled.
+// Returns an error if one occurs.
+func (f *Fuzzer) spawnWorkerProcessesLoop() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to resolve the executable to spawn worker processes with: %v", err)
+	}
+
+	var (
+		waitGroup   sync.WaitGroup
+		errLock     sync.Mutex
+		startupLock sync.Mutex
+		respawns    atomic.Int64
+	)
+	f.workerProcessSlots = make([]*workerProcessSlot, f.config.Fuzzing.Workers)
+	for i := range f.workerProcessSlots {
+		f.workerProcessSlots[i] = &workerProcessSlot{
+			index:          i,
+			randomProvider: randomutils.ForkRandomProvider(f.randomProvider),
+		}
+	}
+	for _, slot := range f.workerProcessSlots {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for !utils.CheckContextDone(f.ctx) {
+				crashed, processErr := f.runWorkerProcess(executable, slot, &startupLock)
+				if processErr != nil {
+					errLock.Lock()
+					if err == nil {
+						err = processErr
+					}
+					errLock.Unlock()
+					f.Terminate()
+					return
+				}
+				if !crashed || utils.CheckContextDone(f.ctx) {
+					return
+				}
+
+				// Respawn the crashed worker process, unless we exceeded our respawn limit.
+				if respawns.Add(1) > int64(f.config.Fuzzing.WorkerProcesses.MaxRespawns) {
+					f.logger.Error(fmt.Sprintf("Worker process %d will not be respawned, as the maximum of %d respawns was reached", slot.index, f.config.Fuzzing.WorkerProcesses.MaxRespawns))
+					return
+				}
+				f.logger.Warn("Respawning worker process ", colors.Bold, slot.index, colors.Reset)
+			}
+		}()
+	}
+	waitGroup.Wait()
+	return err
+}
+
+// runWorkerProcess spawns a worker process for the provided worker slot, handling its messages until it exits. The
+// provided startup lock is held until the worker process is ready to begin fuzzing. The worker process is signaled to
+// stop once Fuzzer.ctx is cancelled, and is killed once Fuzzer.emergencyCtx is cancelled.
+// Returns a boolean indicating whether the worker process crashed, or an error if one occurs.
+func (f *Fuzzer) runWorkerProcess(executable string, slot *workerProcessSlot, startupLock *sync.Mutex) (bool, error) {
+	// Hold our startup lock until the worker process is ready.
+	startupLock.Lock()
+	startupLocked := true
+	releaseStartupLock := func() {
+		if startupLocked {
+			startupLocked = false
+			startupLock.Unlock()
+		}
+	}
+	defer releaseStartupLock()
+	if utils.CheckContextDone(f.ctx) {
+		return false, nil
+	}
+
+	// Spawn the worker process, keeping the tail of its logs in case it crashes.
+	output := &workerProcessOutput{limit: workerProcessOutputTailSize}
+	cmd := exec.CommandContext(f.emergencyCtx, executable)
+	cmd.Env = append(os.Environ(), workerProcessEnvironmentVariable+"=1")
+	cmd.Stderr = output
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return false, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return false, err
+	}
+	if err = cmd.Start(); err != nil {
+		return false, fmt.Errorf("failed to spawn worker process %d: %v", slot.index, err)
+	}
+
+	// Send the worker process the campaign it should fuzz, with a seed of its own.
+	initialization := workerProcessInitialization{Config: f.config, WorkerIndex: slot.index}
+	initialization.Config.Fuzzing.Seed = slot.randomProvider.Int63()
+	initializationData, err := json.Marshal(initialization)
+	if err == nil {
+		_, err = stdin.Write(append(initializationData, '\n'))
+	}
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return false, fmt.Errorf("failed to initialize worker process %d: %v", slot.index, err)
+	}
+
+	// Signal the worker process to stop once fuzzing stops, by closing its standard input.
+	exited := make(chan struct{})
+	go func() {
+		select {
+		case <-f.ctx.Done():
+		case <-exited:
+		}
+		slot.setInput(nil)
+		_ = stdin.Close()
+	}()
+
+	// Handle messages until the worker process exits.
+	ready := false
+	decoder := json.NewDecoder(stdout)
+	for {
+		var message workerProcessMessage
+		if err = decoder.Decode(&message); err != nil {
+			break
+		}
+		if message.Type == workerProcessMessageReady {
+			// Once the worker process initialized its corpus, it can receive the call sequences which achieve new
+			// coverage in other worker processes.
+			ready = true
+			if !utils.CheckContextDone(f.ctx) {
+				slot.setInput(json.NewEncoder(stdin))
+			}
+			releaseStartupLock()
+			continue
+		}
+		if err = f.handleWorkerProcessMessage(slot, message); err != nil {
+			f.logger.Error(fmt.Sprintf("Failed to handle a %s message from worker process %d", message.Type, slot.index), err)
+		}
+	}
+	slot.setInput(nil)
+	waitErr := cmd.Wait()
+	close(exited)
+	releaseStartupLock()
+	crashedCallSequence, crashedCallContracts := slot.currentCallSequence, slot.currentCallContracts
+	slot.processExited()
+
+	// If the worker process exited cleanly, or we killed it, it did not crash.
+	if waitErr == nil || utils.CheckContextDone(f.emergencyCtx) {
+		return false, nil
+	}
+
+	// If the worker process failed before it was ready (e.g. while replaying the corpus), it will not succeed if
+	// respawned.
+	f.reportWorkerProcessCrash(slot.index, waitErr, crashedCallSequence, crashedCallContracts, output.String())
+	if !ready {
+		return false, fmt.Errorf("worker process %d failed before it began fuzzing: %v", slot.index, waitErr)
+	}
+	return true, nil
+}
+
+// handleWorkerProcessMessage handles the provided message from the worker process spawned for the provided worker
+// slot.
+// Returns an error if one occurs.
+func (f *Fuzzer) handleWorkerProcessMessage(slot *workerProcessSlot, message workerProcessMessage) error {
+	switch message.Type {
+	case workerProcessMessageCall:
+		// Track the call sequence being executed, a call index of zero indicating a new call sequence.
+		if message.CallIndex == 0 {
+			slot.currentCallSequence = nil
+			slot.currentCallContracts = nil
+		}
+		slot.currentCallSequence = append(slot.currentCallSequence, message.Call)
+		slot.currentCallContracts = append(slot.currentCallContracts, message.CallContract)
+	case workerProcessMessageCoverage:
+		// Add the call sequence which achieved new coverage to our corpus, with its coverage.
+		if len(message.CallSequence) == 0 {
+			return errors.New("the call sequence which achieved new coverage is empty")
+		}
+		if err := f.resolveWorkerProcessCallSequence(message.CallSequence, message.CallSequenceContracts); err != nil {
+			return err
+		}
+		f.lastNewCoverageTime.Store(time.Now().UnixNano())
+		if message.Successful {
+			f.successfulCorpusEntries.Add(1)
+		}
+		if !message.Shrinking {
+			f.sequenceLengths.recordNewCoverage(len(message.CallSequence))
+		}
+		added, err := f.corpus.AddCallSequenceWithCoverageDelta(message.CallSequence, message.CoverageDelta, f.workerProcessCorpusWeight(), false)
+		if err != nil || !added {
+			return err
+		}
+
+		// Send the call sequence to the other worker processes, so they can mutate it.
+		entry := workerProcessCorpusEntry{
+			CallSequence:          message.CallSequence,
+			CallSequenceContracts: message.CallSequenceContracts,
+			CoverageDelta:         message.CoverageDelta,
+		}
+		for _, otherSlot := range f.workerProcessSlots {
+			if otherSlot == slot {
+				continue
+			}
+			if err = otherSlot.sendCorpusEntry(entry); err != nil {
+				f.logger.Error(fmt.Sprintf("Failed to send a call sequence to worker process %d", otherSlot.index), err)
+			}
+		}
+	case workerProcessMessageTestCase:
+		if message.TestCase == nil {
+			return errors.New("the message does not describe a test case")
+		}
+		if message.TestCase.TestCallSequence != nil {
+			if err := f.resolveWorkerProcessCallSequence(*message.TestCase.TestCallSequence, message.TestCase.TestCallSequenceContracts); err != nil {
+				return err
+			}
+		}
+		f.reportWorkerProcessTestCase(message.TestCase)
+	case workerProcessMessageMetrics:
+		if message.Metrics == nil {
+			return errors.New("the message does not describe metrics")
+		}
+		slot.updateMetrics(&f.metrics.workerMetrics[slot.index], *message.Metrics)
+	default:
+		return errors.New("the message type is unknown")
+	}
+	return nil
+}
+
+// workerProcessCorpusWeight returns the weight that a call sequence reported by a worker process should have in the
+// corpus' weighted random chooser.
+func (f *Fuzzer) workerProcessCorpusWeight() *big.Int {
+	return new(big.Int).Add(f.metrics.SequencesTested(), big.NewInt(1))
+}
+
+// trackWorkerProcessDeployments records the contract definition of each contract deployed to the provided test chain
+// while it is set up, so the calls of call sequences exchanged with worker processes can be resolved by the address
+// they target.
+func (f *Fuzzer) trackWorkerProcessDeployments(testChain *chain.TestChain) {
+	f.workerProcessDeployments = make(map[common.Address]*fuzzerTypes.Contract)
+	matchingMode := fuzzerTypes.BytecodeMatchingMode(f.config.Fuzzing.Testing.ContractMatchingMode)
+	testChain.Events.ContractDeploymentAddedEventEmitter.SubscribeNamed("worker process deployments", func(event chain.ContractDeploymentsAddedEvent) error {
+		if matchedContract := f.contractDefinitions.MatchBytecodeWithMode(event.Contract.InitBytecode, event.Contract.RuntimeBytecode, matchingMode); matchedContract != nil {
+			f.workerProcessDeployments[event.Contract.Address] = matchedContract
+		}
+		return nil
+	})
+	testChain.Events.ContractDeploymentRemovedEventEmitter.SubscribeNamed("worker process deployments", func(event chain.ContractDeploymentsRemovedEvent) error {
+		delete(f.workerProcessDeployments, event.Contract.Address)
+		return nil
+	})
+}
+
+// resolveWorkerProcessCallSequence resolves the contract definition targeted by each call in a call sequence exchanged
+// with a worker process, along with the ABI values of the call. Calls to contracts deployed while setting up the test
+// chain are resolved by the address they target. Contracts deployed while fuzzing may be deployed to different
+// addresses in each process, so calls to them are resolved using the provided contract names, which were reported
+// alongside the call sequence.
+// Returns an error if a call could not be resolved.
+func (f *Fuzzer) resolveWorkerProcessCallSequence(callSequence calls.CallSequence, contractNames []string) error {
+	for i, element := range callSequence {
+		if element == nil || element.Call == nil {
+			continue
+		}
+
+		// Determine the candidate contract definitions the call targets.
+		element.Contract = nil
+		var candidates fuzzerTypes.Contracts
+		if element.Call.To != nil {
+			if deployedContract, ok := f.workerProcessDeployments[*element.Call.To]; ok {
+				candidates = fuzzerTypes.Contracts{deployedContract}
+			}
+		}
+		if candidates == nil && i < len(contractNames) && contractNames[i] != "" {
+			candidates = f.contractDefinitions.ByName(contractNames[i])
+		}
+
+		// Resolve the call against the first candidate whose ABI declares the method called. Calls without ABI values
+		// (e.g. contract creations) need no resolution.
+		if element.Call.DataAbiValues == nil {
+			if len(candidates) > 0 {
+				element.Contract = candidates[0]
+			}
+			continue
+		}
+		err := fmt.Errorf("the contract it targets is unknown")
+		for _, contract := range candidates {
+			if err = element.Call.DataAbiValues.Resolve(contract.CompiledContract().Abi); err == nil {
+				element.Contract = contract
+				break
+			}
+		}
+		if element.Contract == nil {
+			return fmt.Errorf("failed to resolve call %d of the call sequence: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+// reportWorkerProcessTestCase records the provided test case result reported by a worker process, replacing the
+// registered test case with the same ID, unless a more conclusive result was already recorded for it. Failed test
+// cases are recorded in the corpus, and reported to the Fuzzer as finished.
+func (f *Fuzzer) reportWorkerProcessTestCase(testCase *workerProcessTestCase) {
+	f.testCasesLock.Lock()
+	found := false
+	for i, existingTestCase := range f.testCases {
+		if existingTestCase.ID() != testCase.ID() {
+			continue
+		}
+		found = true
+		existingRank, rank := workerProcessTestCaseStatusRank(existingTestCase.Status()), workerProcessTestCaseStatusRank(testCase.Status())
+		if rank > existingRank || (rank == existingRank && testCase.Status() != TestCaseStatusFailed) {
+			f.testCases[i] = testCase
+		}
+		break
+	}
+	if !found {
+		f.testCases = append(f.testCases, testCase)
+	}
+	f.testCasesLock.Unlock()
+
+	if testCase.Status() == TestCaseStatusFailed {
+		if callSequence := testCase.CallSequence(); callSequence != nil {
+			if err := f.corpus.AddTestResultCallSequence(*callSequence, f.workerProcessCorpusWeight(), false); err != nil {
+				f.logger.Error("Failed to record a call sequence which failed a test in the corpus", err)
+			}
+		}
+		f.ReportTestCaseFinished(testCase)
+	}
+}
+
+// reportWorkerProcessCrash logs the crash of the worker process spawned for the provided worker slot, along with the
+// call sequence it was executing (and the name of the contract definition targeted by each call) and the tail of its
+// output. The call sequence is written to the corpus as a reproducer candidate, if a corpus directory is set.
+func (f *Fuzzer) reportWorkerProcessCrash(slotIndex int, exitErr error, callSequence []json.RawMessage, callContracts []string, output string) {
+	buffer := logging.NewLogBuffer()
+	buffer.Append(colors.RedBold, fmt.Sprintf("Worker process %d crashed (%v)", slotIndex, exitErr), colors.Reset, "\n")
+	if len(callSequence) > 0 {
+		buffer.Append(fmt.Sprintf("It crashed while executing a call sequence with %d call(s):\n", len(callSequence)))
+		for i, encodedCall := range callSequence {
+			callContract := ""
+			if i < len(callContracts) {
+				callContract = callContracts[i]
+			}
+			buffer.Append(fmt.Sprintf("%d) %s\n", i+1, f.describeWorkerProcessCall(encodedCall, callContract)))
+		}
+		reproducerPath, err := f.writeWorkerProcessCrash(slotIndex, callSequence)
+		if err != nil {
+			f.logger.Error("Failed to write the call sequence a worker process crashed while executing", err)
+		} else if reproducerPath != "" {
+			buffer.Append("The call sequence was written to ", colors.Bold, reproducerPath, colors.Reset, " as a reproducer candidate.\n")
+		}
+	}
+	buffer.Append(colors.Bold, "[Worker Process Output]", colors.Reset, "\n", output)
+	f.logger.Error(buffer.Elements()...)
+}
+
+// describeWorkerProcessCall returns a displayable description of an encoded call reported by a worker process, along
+// with the name of the contract definition it targets.
+func (f *Fuzzer) describeWorkerProcessCall(encodedCall json.RawMessage, callContract string) string {
+	var element calls.CallSequenceElement
+	if err := json.Unmarshal(encodedCall, &element); err != nil || element.Call == nil {
+		return "<undecodable call>"
+	}
+	target := "<contract creation>"
+	if element.Call.To != nil {
+		target = utils.AttachLabelToAddress(*element.Call.To, f.addressLabels.Label(*element.Call.To))
+	}
+	method := "<unresolved method>"
+	if err := f.resolveWorkerProcessCallSequence(calls.CallSequence{&element}, []string{callContract}); err == nil && element.Call.DataAbiValues != nil {
+		method = fmt.Sprintf("%s.%s", element.Contract.Name(), element.Call.DataAbiValues.Method.Sig)
+	}
+	return fmt.Sprintf("%s to %s (from %s)", method, target, utils.AttachLabelToAddress(element.Call.From, f.addressLabels.Label(element.Call.From)))
+}
+
+// writeWorkerProcessCrash writes the encoded call sequence a worker process crashed while executing to the crashes
+// directory of the corpus, in the versioned schema of corpus call sequences (see calls.CallSequenceJSON). The calls
+// were already encoded by the worker process, so they are written as they were reported.
+// Returns the path of the file written, or an empty string if no corpus directory is set. Returns an error if one
+// occurs.
+func (f *Fuzzer) writeWorkerProcessCrash(slotIndex int, callSequence []json.RawMessage) (string, error) {
+	if f.config.Fuzzing.CorpusDirectory == "" {
+		return "", nil
+	}
+	data, err := json.MarshalIndent(struct {
+		Version int               `json:"version"`
+		Calls   []json.RawMessage `json:"calls"`
+	}{calls.CallSequenceSchemaVersion, callSequence}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	crashesDirectory := filepath.Join(f.config.Fuzzing.CorpusDirectory, "crashes")
+	if err = utils.MakeDirectory(crashesDirectory); err != nil {
+		return "", err
+	}
+	filePath := filepath.Join(crashesDirectory, strconv.FormatInt(time.Now().UnixNano(), 10)+"-worker-"+strconv.Itoa(slotIndex)+".json")
+	return filePath, os.WriteFile(filePath, data, 0644)
+}
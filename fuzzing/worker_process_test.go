@@ -0,0 +1,240 @@
+package fuzzing
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// testWorkerProcessCrashMarkerEnvironmentVariable describes an environment variable which, when set to a file path,
+// causes the first worker process which creates the file to crash upon executing its first call.
+const testWorkerProcessCrashMarkerEnvironmentVariable = "MEDUSA_TEST_WORKER_PROCESS_CRASH_MARKER"
+
+// testWorkerProcessCorpusMarkerEnvironmentVariable describes an environment variable which, when set to a directory,
+// causes each worker process to create a file named after its worker index in it once its corpus contains a call
+// sequence which achieved new coverage in another worker process.
+const testWorkerProcessCorpusMarkerEnvironmentVariable = "MEDUSA_TEST_WORKER_PROCESS_CORPUS_MARKER"
+
+// TestMain runs the tests of this package, unless the test binary was spawned as a worker process by a Fuzzer under
+// test, in which case it runs the worker instead.
+func TestMain(m *testing.M) {
+	if IsWorkerProcess() {
+		os.Exit(runWorkerProcess(configureTestWorkerProcess))
+	}
+	os.Exit(m.Run())
+}
+
+// configureTestWorkerProcess configures the Fuzzer of a worker process spawned by a test, causing it to crash, or to
+// record the receipt of call sequences from other worker processes, if requested by the test.
+func configureTestWorkerProcess(fuzzer *Fuzzer) {
+	if corpusMarker := os.Getenv(testWorkerProcessCorpusMarkerEnvironmentVariable); corpusMarker != "" {
+		// Count the call sequences which achieved new coverage in this worker process. Any others in its corpus were
+		// received from other worker processes.
+		var discovered atomic.Int64
+		fuzzer.Events.WorkerNewCoverage.Subscribe(func(event FuzzerWorkerNewCoverageEvent) error {
+			discovered.Add(1)
+			return nil
+		})
+		fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+			if int64(worker.fuzzer.corpus.ActiveMutableSequenceCount()) > discovered.Load() {
+				file, err := os.Create(filepath.Join(corpusMarker, strconv.Itoa(worker.fuzzer.workerProcess.workerIndex)))
+				if err != nil {
+					return nil, err
+				}
+				_ = file.Close()
+			}
+			return nil, nil
+		})
+	}
+
+	crashMarker := os.Getenv(testWorkerProcessCrashMarkerEnvironmentVariable)
+	if crashMarker == "" {
+		return
+	}
+	fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, func(worker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+		// Only the first worker process to create the marker crashes, so respawned worker processes do not.
+		if file, err := os.OpenFile(crashMarker, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644); err == nil {
+			_ = file.Close()
+			panic("deliberate worker process crash")
+		}
+		return nil, nil
+	})
+}
+
+// TestWorkerProcessCrashRespawned runs a fuzzing campaign with worker processes enabled, deliberately crashing a
+// worker process mid-campaign, and ensures the campaign survives, respawning the worker process, and records the call
+// sequence it crashed while executing as a reproducer candidate.
+func TestWorkerProcessCrashRespawned(t *testing.T) {
+	crashMarker := filepath.Join(t.TempDir(), "crashed")
+	t.Setenv(testWorkerProcessCrashMarkerEnvironmentVariable, crashMarker)
+	runFuzzerTest(t, &fuzzerSolcFileTest{
+		filePath: "testdata/contracts/assertions/assert_immediate.sol",
+		configUpdates: func(config *config.ProjectConfig) {
+			config.Fuzzing.TargetContracts = []string{"TestContract"}
+			config.Fuzzing.Workers = 2
+			config.Fuzzing.CorpusDirectory = "corpus"
+			config.Fuzzing.WorkerProcesses.Enabled = true
+			config.Fuzzing.Testing.PropertyTesting.Enabled = false
+			config.Fuzzing.Testing.OptimizationTesting.Enabled = false
+			config.Slither.UseSlither = false
+		},
+		method: func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+
+			// A worker process should have crashed, and the respawned worker processes should have found the failure.
+			assert.FileExists(t, crashMarker)
+			assertFailedTestsExpected(f, true)
+
+			// The call sequence the worker process crashed while executing should have been written to the corpus.
+			crashFiles, err := filepath.Glob(filepath.Join("corpus", "crashes", "*.json"))
+			assert.NoError(t, err)
+			assert.Len(t, crashFiles, 1)
+			for _, crashFile := range crashFiles {
+				b, err := os.ReadFile(crashFile)
+				assert.NoError(t, err)
+				var crashedCallSequence calls.CallSequence
+				assert.NoError(t, json.Unmarshal(b, &crashedCallSequence))
+				assert.Len(t, crashedCallSequence, 1)
+			}
+		},
+	})
+}
+
+// TestWorkerProcessCorpusShared runs a fuzzing campaign with worker processes enabled against our pre-compiled Hardhat
+// project, and ensures the call sequences which achieve new coverage in one worker process are sent to the others, and
+// that the calls of call sequences reported by worker processes are resolved against the contract they target.
+func TestWorkerProcessCorpusShared(t *testing.T) {
+	corpusMarker := t.TempDir()
+	t.Setenv(testWorkerProcessCorpusMarkerEnvironmentVariable, corpusMarker)
+
+	runHardhatProjectTest(t, func(projectConfig *config.ProjectConfig) {
+		// Create our project configuration, with two worker processes.
+		projectConfig.Fuzzing.Workers = 2
+		projectConfig.Fuzzing.TestLimit = 5_000
+		projectConfig.Fuzzing.CorpusDirectory = "corpus"
+		projectConfig.Fuzzing.WorkerProcesses.Enabled = true
+		projectConfig.Fuzzing.Testing.StopOnNoTests = false
+
+		executeFuzzerTestMethodInternal(t, projectConfig, func(f *fuzzerTestContext) {
+			// Start the fuzzer
+			err := f.fuzzer.Start()
+			assert.NoError(t, err)
+			assertCorpusCallSequencesCollected(f, true)
+
+			// A worker process should have received a call sequence which achieved new coverage in the other.
+			markers, err := os.ReadDir(corpusMarker)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, markers)
+
+			// Both contracts declare the same method, so calls to each must be resolved by the address they target.
+			// Calls to contracts deployed while fuzzing are resolved by the contract name reported with them. Each call
+			// sequence is encoded and decoded, as it would be when exchanged with a worker process.
+			decodeCallSequence := func(callSequence calls.CallSequence) calls.CallSequence {
+				b, err := json.Marshal(callSequence)
+				assert.NoError(t, err)
+				var decodedCallSequence calls.CallSequence
+				assert.NoError(t, json.Unmarshal(b, &decodedCallSequence))
+				return decodedCallSequence
+			}
+			assert.Len(t, f.fuzzer.workerProcessDeployments, 2)
+			for address, contract := range f.fuzzer.workerProcessDeployments {
+				method := contract.CompiledContract().Abi.Methods["value"]
+				callSequence := decodeCallSequence(calls.CallSequence{
+					{Call: calls.NewCallMessageWithAbiValueData(common.Address{}, &address, 0, big.NewInt(0), 0, nil, nil, nil, &calls.CallMessageDataAbiValues{Method: &method, InputValues: []any{}})},
+					{Call: calls.NewCallMessageWithAbiValueData(common.Address{}, &common.Address{}, 0, big.NewInt(0), 0, nil, nil, nil, &calls.CallMessageDataAbiValues{Method: &method, InputValues: []any{}})},
+				})
+				assert.NoError(t, f.fuzzer.resolveWorkerProcessCallSequence(callSequence, []string{"", contract.Name()}))
+				assert.EqualValues(t, contract.Name(), callSequence[0].Contract.Name())
+				assert.EqualValues(t, contract.Name(), callSequence[1].Contract.Name())
+
+				// A call to an unknown contract cannot be resolved.
+				assert.Error(t, f.fuzzer.resolveWorkerProcessCallSequence(callSequence[1:], nil))
+			}
+
+			// Coverage achieved while shrinking should not be recorded in the sequence length histogram, while coverage
+			// achieved by a fuzzed call sequence should be.
+			for address, contract := range f.fuzzer.workerProcessDeployments {
+				method := contract.CompiledContract().Abi.Methods["value"]
+				newCoverageMessage := func(shrinking bool) workerProcessMessage {
+					return workerProcessMessage{
+						Type: workerProcessMessageCoverage,
+						CallSequence: decodeCallSequence(calls.CallSequence{
+							{Call: calls.NewCallMessageWithAbiValueData(common.Address{}, &address, 0, big.NewInt(0), 0, nil, nil, nil, &calls.CallMessageDataAbiValues{Method: &method, InputValues: []any{}})},
+						}),
+						CallSequenceContracts: []string{""},
+						Shrinking:             shrinking,
+					}
+				}
+				countNewCoverage := func() uint64 {
+					var total uint64
+					for _, count := range f.fuzzer.SequenceLengthHistogram().NewCoverage {
+						total += count
+					}
+					return total
+				}
+				slot := &workerProcessSlot{}
+				recorded := countNewCoverage()
+				assert.NoError(t, f.fuzzer.handleWorkerProcessMessage(slot, newCoverageMessage(true)))
+				assert.EqualValues(t, recorded, countNewCoverage())
+				assert.NoError(t, f.fuzzer.handleWorkerProcessMessage(slot, newCoverageMessage(false)))
+				assert.EqualValues(t, recorded+1, countNewCoverage())
+				break
+			}
+		})
+	})
+}
+
+// TestWorkerProcessSlotMetrics tests that the metrics of a worker slot total the metrics reported by every worker
+// process spawned for it, including those which exited.
+func TestWorkerProcessSlotMetrics(t *testing.T) {
+	metrics := newFuzzerMetrics(1)
+	slot := &workerProcessSlot{}
+
+	// Report metrics from a worker process, then update them before it exits.
+	slot.updateMetrics(&metrics.workerMetrics[0], workerProcessMetrics{SequencesTested: big.NewInt(5), CallsTested: big.NewInt(50)})
+	slot.updateMetrics(&metrics.workerMetrics[0], workerProcessMetrics{SequencesTested: big.NewInt(7), CallsTested: big.NewInt(70)})
+	slot.currentCallSequence = []json.RawMessage{json.RawMessage("{}")}
+	slot.processExited()
+	assert.Nil(t, slot.currentCallSequence)
+	assert.EqualValues(t, big.NewInt(7), metrics.SequencesTested())
+
+	// Metrics reported by the respawned worker process should add to those of the exited one.
+	slot.updateMetrics(&metrics.workerMetrics[0], workerProcessMetrics{SequencesTested: big.NewInt(2), CallsTested: big.NewInt(20), FailedSequences: big.NewInt(1)})
+	assert.EqualValues(t, big.NewInt(9), metrics.SequencesTested())
+	assert.EqualValues(t, big.NewInt(90), metrics.CallsTested())
+	assert.EqualValues(t, big.NewInt(1), metrics.FailedSequences())
+	assert.EqualValues(t, big.NewInt(0), metrics.GasUsed())
+}
+
+// TestWorkerProcessOutput tests that only the trailing output of a worker process is kept.
+func TestWorkerProcessOutput(t *testing.T) {
+	output := &workerProcessOutput{limit: 8}
+	_, err := output.Write([]byte("hello "))
+	assert.NoError(t, err)
+	assert.EqualValues(t, "hello ", output.String())
+	n, err := output.Write([]byte("world"))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, n)
+	assert.EqualValues(t, "lo world", output.String())
+}
+
+// TestWorkerProcessTestCaseStatusRank tests that worker process test case results are ranked by how conclusive they
+// are.
+func TestWorkerProcessTestCaseStatusRank(t *testing.T) {
+	statuses := []TestCaseStatus{TestCaseStatusNotStarted, TestCaseStatusRunning, TestCaseStatusPassed, TestCaseStatusFailed}
+	for i := 1; i < len(statuses); i++ {
+		assert.Greater(t, workerProcessTestCaseStatusRank(statuses[i]), workerProcessTestCaseStatusRank(statuses[i-1]))
+	}
+	assert.EqualValues(t, workerProcessTestCaseStatusRank(TestCaseStatusPassed), workerProcessTestCaseStatusRank(TestCaseStatusMuted))
+}
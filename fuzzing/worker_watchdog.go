@@ -0,0 +1,189 @@
+package fuzzing
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/crytic/medusa/logging"
+	"github.com/crytic/medusa/logging/colors"
+)
+
+// workerPhase describes the activity a FuzzerWorker was last known to be performing.
+type workerPhase int32
+
+const (
+	// workerPhaseIdle indicates the worker is not testing call sequences, e.g. as it is waiting for other workers to
+	// finish. Idle workers are never considered stalled.
+	workerPhaseIdle workerPhase = iota
+	// workerPhaseGeneration indicates the worker is generating the next call of a call sequence.
+	workerPhaseGeneration
+	// workerPhaseExecution indicates the worker is executing and testing a call of a call sequence.
+	workerPhaseExecution
+	// workerPhaseShrinking indicates the worker is shrinking a call sequence which failed a test.
+	workerPhaseShrinking
+)
+
+// String returns a displayable name for the workerPhase.
+func (p workerPhase) String() string {
+	switch p {
+	case workerPhaseGeneration:
+		return "generation"
+	case workerPhaseExecution:
+		return "execution"
+	case workerPhaseShrinking:
+		return "shrinking"
+	default:
+		return "idle"
+	}
+}
+
+// workerHeartbeat tracks the progress of a single FuzzerWorker, so it can be monitored by a workerWatchdog. It is safe
+// for concurrent use.
+type workerHeartbeat struct {
+	// lastBeat describes the time of the last heartbeat, in nanoseconds since the Unix epoch.
+	lastBeat atomic.Int64
+
+	// phase describes the workerPhase the worker was last known to be in.
+	phase atomic.Int32
+}
+
+// beat records that the worker made progress at the current time.
+func (h *workerHeartbeat) beat() {
+	h.lastBeat.Store(time.Now().UnixNano())
+}
+
+// setPhase records the workerPhase the worker entered.
+func (h *workerHeartbeat) setPhase(phase workerPhase) {
+	h.phase.Store(int32(phase))
+}
+
+// currentPhase returns the workerPhase the worker was last known to be in.
+func (h *workerHeartbeat) currentPhase() workerPhase {
+	return workerPhase(h.phase.Load())
+}
+
+// sinceLastBeat returns the time elapsed between the last heartbeat and the provided time.
+func (h *workerHeartbeat) sinceLastBeat(now time.Time) time.Duration {
+	return now.Sub(time.Unix(0, h.lastBeat.Load()))
+}
+
+// watchdogWorker describes a worker which can be monitored by a workerWatchdog.
+type watchdogWorker interface {
+	// WorkerIndex returns the index of the worker in relation to its parent Fuzzer.
+	WorkerIndex() int
+
+	// heartbeat returns the workerHeartbeat describing the progress of the worker.
+	heartbeat() *workerHeartbeat
+
+	// abandon stops the worker from contributing to the fuzzing campaign and releases its resources, so it can be
+	// recreated while it remains stalled.
+	abandon()
+}
+
+// Verify that FuzzerWorker can be monitored by a workerWatchdog.
+var _ watchdogWorker = (*FuzzerWorker)(nil)
+
+// workerWatchdog monitors the heartbeats of workers, reporting workers which stopped making progress, and optionally
+// abandoning them so they are recreated. It is safe for concurrent use.
+type workerWatchdog struct {
+	// stallTimeout describes the time a worker may go without a heartbeat before it is considered stalled.
+	stallTimeout time.Duration
+
+	// recreateWorkers describes whether stalled workers are abandoned so they are recreated, rather than only reported.
+	recreateWorkers bool
+
+	// logger describes the logger stalled workers are reported to.
+	logger *logging.Logger
+
+	// lock is used to synchronize access to workers, as workers are added and removed while they are monitored.
+	lock sync.Mutex
+
+	// workers describes the monitored workers, mapped to whether they were reported as stalled since their last
+	// heartbeat.
+	workers map[watchdogWorker]bool
+}
+
+// newWorkerWatchdog returns a new workerWatchdog which considers workers stalled after the provided timeout, reporting
+// them to the provided logger, and abandoning them if recreateWorkers is true.
+func newWorkerWatchdog(stallTimeout time.Duration, recreateWorkers bool, logger *logging.Logger) *workerWatchdog {
+	return &workerWatchdog{
+		stallTimeout:    stallTimeout,
+		recreateWorkers: recreateWorkers,
+		logger:          logger,
+		workers:         make(map[watchdogWorker]bool),
+	}
+}
+
+// watch begins monitoring the provided worker. The worker should have recorded a heartbeat prior to this call.
+func (w *workerWatchdog) watch(worker watchdogWorker) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.workers[worker] = false
+}
+
+// unwatch stops monitoring the provided worker, if it is monitored.
+func (w *workerWatchdog) unwatch(worker watchdogWorker) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	delete(w.workers, worker)
+}
+
+// check determines which monitored workers have not recorded a heartbeat within the stall timeout, as of the provided
+// time. Each stalled worker is reported once per stall, and if the watchdog recreates workers, it is abandoned and no
+// longer monitored.
+// Returns the workers which were newly considered stalled, sorted by worker index.
+func (w *workerWatchdog) check(now time.Time) []watchdogWorker {
+	// Collect the workers which newly stalled, resetting the reported state of those which made progress since.
+	w.lock.Lock()
+	stalledWorkers := make([]watchdogWorker, 0)
+	for worker, reported := range w.workers {
+		heartbeat := worker.heartbeat()
+		if heartbeat.currentPhase() == workerPhaseIdle || heartbeat.sinceLastBeat(now) < w.stallTimeout {
+			w.workers[worker] = false
+			continue
+		}
+		if reported {
+			continue
+		}
+		w.workers[worker] = true
+		if w.recreateWorkers {
+			delete(w.workers, worker)
+		}
+		stalledWorkers = append(stalledWorkers, worker)
+	}
+	w.lock.Unlock()
+	sort.Slice(stalledWorkers, func(i, j int) bool {
+		return stalledWorkers[i].WorkerIndex() < stalledWorkers[j].WorkerIndex()
+	})
+
+	// Report each stalled worker, abandoning it if we recreate workers. This is done without holding the lock, as
+	// abandoning a worker may cause a new one to be watched.
+	for _, worker := range stalledWorkers {
+		heartbeat := worker.heartbeat()
+		w.logger.Warn("Worker ", colors.Bold, worker.WorkerIndex(), colors.Reset, " has not made progress for ",
+			colors.Bold, heartbeat.sinceLastBeat(now).Round(time.Second).String(), colors.Reset,
+			", and was last known to be in the ", colors.Bold, heartbeat.currentPhase().String(), colors.Reset, " phase")
+		if w.recreateWorkers {
+			w.logger.Info("Abandoning stalled worker ", colors.Bold, worker.WorkerIndex(), colors.Reset, " and creating a new worker in its place")
+			worker.abandon()
+		}
+	}
+	return stalledWorkers
+}
+
+// run checks the monitored workers for stalls at the provided interval, until ctx signals a stopped operation.
+func (w *workerWatchdog) run(ctx context.Context, checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			w.check(now)
+		}
+	}
+}
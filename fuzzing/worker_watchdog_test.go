@@ -0,0 +1,338 @@
+package fuzzing
+
+import (
+	"context"
+	"math/big"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crytic/medusa/chain"
+	"github.com/crytic/medusa/fuzzing/calls"
+	"github.com/crytic/medusa/fuzzing/config"
+	"github.com/crytic/medusa/logging"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	coreTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockWatchdogWorker describes a worker monitored by a workerWatchdog in tests, whose loop records a heartbeat for
+// each iteration, and can be artificially blocked.
+type mockWatchdogWorker struct {
+	// index describes the index of the worker.
+	index int
+
+	// liveness describes the progress of the worker.
+	liveness workerHeartbeat
+
+	// blocked describes a channel which blocks the loop of the worker while it is open, if non-nil.
+	blocked chan struct{}
+
+	// abandoned indicates whether the worker was abandoned.
+	abandoned atomic.Bool
+
+	// onAbandon describes a function invoked when the worker is abandoned.
+	onAbandon func()
+
+	// exited describes a channel which is closed once the loop of the worker exits.
+	exited chan struct{}
+}
+
+// newMockWatchdogWorker creates a mockWatchdogWorker with the provided index, whose loop blocks while the provided
+// channel is open, if it is non-nil.
+func newMockWatchdogWorker(index int, blocked chan struct{}) *mockWatchdogWorker {
+	worker := &mockWatchdogWorker{index: index, blocked: blocked, exited: make(chan struct{})}
+	worker.liveness.beat()
+	return worker
+}
+
+func (w *mockWatchdogWorker) WorkerIndex() int {
+	return w.index
+}
+
+func (w *mockWatchdogWorker) heartbeat() *workerHeartbeat {
+	return &w.liveness
+}
+
+func (w *mockWatchdogWorker) abandon() {
+	w.abandoned.Store(true)
+	if w.onAbandon != nil {
+		w.onAbandon()
+	}
+}
+
+// run runs the loop of the worker until it is abandoned or the provided channel is closed, recording a heartbeat at
+// the start of each iteration, as FuzzerWorker.run does.
+func (w *mockWatchdogWorker) run(stop chan struct{}) {
+	defer close(w.exited)
+	for {
+		w.liveness.beat()
+		if w.abandoned.Load() {
+			return
+		}
+		w.liveness.setPhase(workerPhaseExecution)
+		if w.blocked != nil {
+			<-w.blocked
+		}
+		select {
+		case <-stop:
+			return
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+// TestWorkerWatchdogDetectsStalledWorker tests that a worker whose loop is blocked is reported as stalled once per
+// stall, along with the phase it was last known to be in, while workers making progress or idling are not.
+func TestWorkerWatchdogDetectsStalledWorker(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	blocked := make(chan struct{})
+	watchdog := newWorkerWatchdog(100*time.Millisecond, false, logging.NewLogger(zerolog.Disabled))
+
+	// Run a healthy worker and a blocked worker, and watch an idle worker which never makes progress.
+	healthyWorker := newMockWatchdogWorker(0, nil)
+	blockedWorker := newMockWatchdogWorker(1, blocked)
+	idleWorker := newMockWatchdogWorker(2, nil)
+	for _, worker := range []*mockWatchdogWorker{healthyWorker, blockedWorker, idleWorker} {
+		watchdog.watch(worker)
+	}
+	go healthyWorker.run(stop)
+	go blockedWorker.run(stop)
+
+	// Only the blocked worker should be considered stalled, in the phase it blocked in, and only be reported once.
+	assert.Empty(t, watchdog.check(time.Now()))
+	time.Sleep(200 * time.Millisecond)
+	assert.EqualValues(t, []watchdogWorker{blockedWorker}, watchdog.check(time.Now()))
+	assert.EqualValues(t, workerPhaseExecution, blockedWorker.liveness.currentPhase())
+	assert.Empty(t, watchdog.check(time.Now()))
+	assert.False(t, blockedWorker.abandoned.Load())
+
+	// Once the blocked worker makes progress again, it should no longer be considered stalled.
+	close(blocked)
+	assert.Eventually(t, func() bool {
+		return blockedWorker.liveness.sinceLastBeat(time.Now()) < 100*time.Millisecond
+	}, time.Second, time.Millisecond)
+	assert.Empty(t, watchdog.check(time.Now()))
+}
+
+// TestWorkerWatchdogRecreatesStalledWorker tests that a stalled worker is abandoned and recreated when the watchdog is
+// configured to recreate workers, and that the abandoned worker exits once it makes progress again.
+func TestWorkerWatchdogRecreatesStalledWorker(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	blocked := make(chan struct{})
+	watchdog := newWorkerWatchdog(50*time.Millisecond, true, logging.NewLogger(zerolog.Disabled))
+
+	// Run a blocked worker which is recreated in its slot when it is abandoned, as the fuzzer does.
+	var recreatedWorker atomic.Pointer[mockWatchdogWorker]
+	blockedWorker := newMockWatchdogWorker(0, blocked)
+	blockedWorker.onAbandon = func() {
+		worker := newMockWatchdogWorker(blockedWorker.index, nil)
+		recreatedWorker.Store(worker)
+		watchdog.watch(worker)
+		go worker.run(stop)
+	}
+	watchdog.watch(blockedWorker)
+	go blockedWorker.run(stop)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watchdog.run(ctx, 10*time.Millisecond)
+
+	// The blocked worker should be detected and abandoned, and a new worker created in its place.
+	assert.Eventually(t, func() bool {
+		return recreatedWorker.Load() != nil
+	}, 5*time.Second, time.Millisecond)
+	assert.True(t, blockedWorker.abandoned.Load())
+
+	// The recreated worker should keep making progress, and not be considered stalled.
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, watchdog.check(time.Now()))
+	assert.False(t, recreatedWorker.Load().abandoned.Load())
+
+	// Once unblocked, the abandoned worker should exit rather than continue.
+	close(blocked)
+	select {
+	case <-blockedWorker.exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("abandoned worker did not exit once unblocked")
+	}
+}
+
+// TestWorkerWatchdogLongShrinking runs a worker against our pre-compiled Hardhat project, shrinking a call sequence
+// with a slow verifier, so shrinking takes far longer than the stall timeout. It ensures the worker is not considered
+// stalled while shrinking, as each call it executes is recorded as progress.
+func TestWorkerWatchdogLongShrinking(t *testing.T) {
+	runHardhatWorkerTest(t, func(projectConfig *config.ProjectConfig) {
+		projectConfig.Fuzzing.ShrinkLimit = 500
+	}, func(worker *FuzzerWorker) {
+		fuzzer := worker.fuzzer
+
+		// Create a call sequence of calls to the first method found.
+		contractMethod := worker.stateChangingMethods[0]
+		callSequence := make(calls.CallSequence, 0)
+		for i := 0; i < 10; i++ {
+			msg := calls.NewCallMessageWithAbiValueData(fuzzer.senders[0], &contractMethod.Address, 0, big.NewInt(0), fuzzer.config.Fuzzing.TransactionGasLimit, nil, nil, nil, &calls.CallMessageDataAbiValues{
+				Method:      &contractMethod.Method,
+				InputValues: []any{},
+			})
+			msg.FillFromTestChainProperties(worker.chain)
+			callSequence = append(callSequence, calls.NewCallSequenceElement(contractMethod.Contract, msg, 1, 1))
+		}
+
+		// Watch the worker as it shrinks the call sequence with a verifier which takes a large share of the stall
+		// timeout, and only accepts the full call sequence, so every call is executed by each verification.
+		watchdog := newWorkerWatchdog(100*time.Millisecond, false, logging.NewLogger(zerolog.Disabled))
+		worker.liveness.beat()
+		worker.liveness.setPhase(workerPhaseShrinking)
+		watchdog.watch(worker)
+		stalled := 0
+		startTime := time.Now()
+		_, err := worker.shrinkCallSequence(ShrinkCallSequenceRequest{
+			TestName:             "slow verifier",
+			CallSequenceToShrink: callSequence,
+			VerifierFunction: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence) (bool, error) {
+				time.Sleep(40 * time.Millisecond)
+				stalled += len(watchdog.check(time.Now()))
+				return len(shrunkenCallSequence) == len(callSequence), nil
+			},
+			FinishedCallback: func(worker *FuzzerWorker, shrunkenCallSequence calls.CallSequence, verboseTracing bool) error {
+				return nil
+			},
+		})
+		assert.NoError(t, err)
+
+		// Shrinking should have taken longer than the stall timeout, without the worker being considered stalled.
+		assert.Greater(t, time.Since(startTime), 500*time.Millisecond)
+		assert.Zero(t, stalled)
+	})
+}
+
+// TestFuzzerWorkerAbandonReleasesShrink tests that abandoning a worker while it runs a shrink request frees the
+// shrinking slot it occupies, and releases the failure it claimed so it is shrunk again once rediscovered, while a
+// shrink request which completes normally keeps its failure claimed.
+func TestFuzzerWorkerAbandonReleasesShrink(t *testing.T) {
+	worker := newMethodTrackingTestWorker(t)
+	failures := newFailureRegistry()
+	worker.fuzzer.failures = failures
+	worker.fuzzer.shrinkScheduler = newShrinkScheduler(1, failures)
+	scheduler := worker.fuzzer.shrinkScheduler
+
+	// A shrink request which completes normally should free its slot, while its failure remains claimed.
+	scheduler.enqueue(ShrinkCallSequenceRequest{TestName: "a", FailureID: "a"})
+	shrinkRequest, ok := scheduler.next(false)
+	assert.True(t, ok)
+	worker.beginShrinkRequest(shrinkRequest, true)
+	assert.True(t, worker.claimActiveShrinkFailure())
+	worker.releaseActiveShrink()
+	assert.True(t, failures.known("a"))
+
+	// A shrink request whose worker is abandoned should free its slot and release its failure, once.
+	scheduler.enqueue(ShrinkCallSequenceRequest{TestName: "b", FailureID: "b"})
+	shrinkRequest, ok = scheduler.next(false)
+	assert.True(t, ok)
+	worker.beginShrinkRequest(shrinkRequest, true)
+	assert.True(t, worker.claimActiveShrinkFailure())
+	worker.abandon()
+	worker.releaseActiveShrink()
+	assert.False(t, failures.known("b"))
+	assert.True(t, failures.known("a"))
+
+	// The failure should be queued and shrunk again once it is rediscovered, as the slot was freed exactly once.
+	scheduler.enqueue(ShrinkCallSequenceRequest{TestName: "b", FailureID: "b"})
+	shrinkRequest, ok = scheduler.next(false)
+	assert.True(t, ok)
+	assert.EqualValues(t, "b", shrinkRequest.FailureID)
+	_, ok = scheduler.next(false)
+	assert.False(t, ok)
+}
+
+// TestFuzzerWorkerAbandonedDuringCall runs a worker against our pre-compiled Hardhat project, whose first call blocks
+// until the worker is abandoned and a new worker tests call sequences in its place. It ensures the chain of the
+// abandoned worker is closed once it is abandoned, and that once its call completes, the worker does not record any
+// results while the new worker, which shares its worker index, is testing. This test should also be run with the race
+// detector enabled.
+func TestFuzzerWorkerAbandonedDuringCall(t *testing.T) {
+	runHardhatWorkerTest(t, func(projectConfig *config.ProjectConfig) {
+		projectConfig.Fuzzing.CallSequenceLength = 10
+	}, func(worker *FuzzerWorker) {
+		fuzzer := worker.fuzzer
+
+		// Create the worker which takes the place of our worker once it is abandoned, with its own random provider, as
+		// the fuzzer does.
+		replacement, err := newFuzzerWorker(fuzzer, worker.WorkerIndex(), rand.New(rand.NewSource(fuzzer.seed)))
+		assert.NoError(t, err)
+		assert.NoError(t, replacement.setUpChain(worker.chain))
+		defer replacement.closeChain()
+		replacement.testingBaseBlockIndex = uint64(len(replacement.chain.CommittedBlocks()))
+
+		// Count the calls tested by each worker.
+		var callsTested, abandonedCallsTested atomic.Int64
+		fuzzer.Hooks.CallSequenceTestFuncs = append(fuzzer.Hooks.CallSequenceTestFuncs, func(testingWorker *FuzzerWorker, callSequence calls.CallSequence) ([]ShrinkCallSequenceRequest, error) {
+			if testingWorker == worker {
+				abandonedCallsTested.Add(1)
+			} else {
+				callsTested.Add(1)
+			}
+			return nil, nil
+		})
+
+		// Block the first call executed by our worker until it is unblocked.
+		blocked, unblocked := make(chan struct{}), make(chan struct{})
+		var blockOnce sync.Once
+		worker.chain.AddTracer(&chain.TestChainTracer{Tracer: &tracers.Tracer{Hooks: &tracing.Hooks{
+			OnTxEnd: func(receipt *coreTypes.Receipt, err error) {
+				blockOnce.Do(func() {
+					close(blocked)
+					<-unblocked
+				})
+			},
+		}}}, true, false)
+
+		// Test a call sequence with our worker, and abandon it once its first call is blocked, as the watchdog would.
+		var shrinkRequests []ShrinkCallSequenceRequest
+		workerErr := make(chan error, 1)
+		go func() {
+			var err error
+			shrinkRequests, err = worker.testNextCallSequence()
+			workerErr <- err
+		}()
+		<-blocked
+		worker.abandon()
+		assert.True(t, worker.chainClosed.Load())
+
+		// Test call sequences with the new worker, unblocking the call of our abandoned worker in the meantime.
+		stop := make(chan struct{})
+		replacementErr := make(chan error, 1)
+		go func() {
+			for {
+				select {
+				case <-stop:
+					replacementErr <- nil
+					return
+				default:
+				}
+				if _, err := replacement.testNextCallSequence(); err != nil {
+					replacementErr <- err
+					return
+				}
+			}
+		}()
+		close(unblocked)
+		assert.NoError(t, <-workerErr)
+		assert.Eventually(t, func() bool {
+			return callsTested.Load() > 100
+		}, 10*time.Second, time.Millisecond)
+		close(stop)
+		assert.NoError(t, <-replacementErr)
+
+		// The abandoned worker should not have tested its blocked call, nor any call after it.
+		assert.Empty(t, shrinkRequests)
+		assert.Zero(t, abandonedCallsTested.Load())
+		assert.False(t, replacement.chainClosed.Load())
+	})
+}
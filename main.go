@@ -4,10 +4,16 @@ import (
 	"fmt"
 	"github.com/crytic/medusa/cmd"
 	"github.com/crytic/medusa/cmd/exitcodes"
+	"github.com/crytic/medusa/fuzzing"
 	"os"
 )
 
 func main() {
+	// If we were spawned by a fuzzer to run one of its workers in a separate process, run the worker instead.
+	if fuzzing.IsWorkerProcess() {
+		os.Exit(fuzzing.RunWorkerProcess())
+	}
+
 	// Run our root CLI command, which contains all underlying command logic and will handle parsing/invocation.
 	err := cmd.Execute()
 
@@ -2,6 +2,9 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -26,3 +29,9 @@ func CopyChainConfig(config *params.ChainConfig) (*params.ChainConfig, error) {
 	// Return it.
 	return chainConfig, nil
 }
+
+// IsOutOfGasError determines whether the provided VM error indicates that a call frame ran out of gas, either during
+// execution or while storing the code of a newly created contract.
+func IsOutOfGasError(err error) bool {
+	return errors.Is(err, vm.ErrOutOfGas) || errors.Is(err, vm.ErrCodeStoreOutOfGas)
+}
@@ -6,7 +6,6 @@ import (
 	"math/rand"
 	"sync"
 	"time"
-	"unsafe"
 )
 
 // WeightedRandomChoice describes a weighted, randomly selectable object for use with a WeightedRandomChooser.
@@ -58,6 +57,20 @@ func NewWeightedRandomChooserWithRand[T any](randomProvider *rand.Rand, randomPr
 	}
 }
 
+// Clone creates a copy of the WeightedRandomChooser with the same choices and weights, and a new random provider.
+// Choices added to, or weights updated in, the copy do not affect the original, and vice versa.
+func (c *WeightedRandomChooser[T]) Clone() *WeightedRandomChooser[T] {
+	c.randomProviderLock.Lock()
+	defer c.randomProviderLock.Unlock()
+
+	clone := NewWeightedRandomChooser[T]()
+	for _, choice := range c.choices {
+		clone.choices = append(clone.choices, NewWeightedRandomChoice(choice.Data, choice.weight))
+	}
+	clone.totalWeight = new(big.Int).Set(c.totalWeight)
+	return clone
+}
+
 // ChoiceCount returns the count of choices added to this provider.
 func (c *WeightedRandomChooser[T]) ChoiceCount() int {
 	return len(c.choices)
@@ -78,8 +91,55 @@ func (c *WeightedRandomChooser[T]) AddChoices(choices ...*WeightedRandomChoice[T
 	c.choices = append(c.choices, choices...)
 }
 
+// TotalWeight returns the sum of the weights of all choices added to this provider.
+func (c *WeightedRandomChooser[T]) TotalWeight() *big.Int {
+	c.randomProviderLock.Lock()
+	defer c.randomProviderLock.Unlock()
+	return new(big.Int).Set(c.totalWeight)
+}
+
+// Weights returns the weights of all choices added to this provider, in the order they were added.
+func (c *WeightedRandomChooser[T]) Weights() []*big.Int {
+	c.randomProviderLock.Lock()
+	defer c.randomProviderLock.Unlock()
+
+	// Weights are replaced rather than modified when updated, so the copy can safely share them.
+	weights := make([]*big.Int, len(c.choices))
+	for i, choice := range c.choices {
+		weights[i] = choice.weight
+	}
+	return weights
+}
+
+// ChoiceWeight returns the weight of the provided choice, which must have been added to this provider.
+func (c *WeightedRandomChooser[T]) ChoiceWeight(choice *WeightedRandomChoice[T]) *big.Int {
+	c.randomProviderLock.Lock()
+	defer c.randomProviderLock.Unlock()
+	return new(big.Int).Set(choice.weight)
+}
+
+// SetChoiceWeight updates the weight of the provided choice, which must have been added to this provider, so its
+// likelihood of being selected changes for future random selections.
+func (c *WeightedRandomChooser[T]) SetChoiceWeight(choice *WeightedRandomChoice[T], weight *big.Int) {
+	// Acquire our lock during the duration of this method.
+	c.randomProviderLock.Lock()
+	defer c.randomProviderLock.Unlock()
+
+	// Replace the choice's weight in our total weight.
+	c.totalWeight = new(big.Int).Sub(c.totalWeight, choice.weight)
+	choice.weight = new(big.Int).Set(weight)
+	c.totalWeight = new(big.Int).Add(c.totalWeight, choice.weight)
+}
+
 // Choose selects a random weighted item from the WeightedRandomChooser, or returns an error if one occurs.
 func (c *WeightedRandomChooser[T]) Choose() (*T, error) {
+	return c.ChooseWithRand(c.randomProvider)
+}
+
+// ChooseWithRand selects a random weighted item from the WeightedRandomChooser using the provided random provider
+// rather than the one the WeightedRandomChooser was created with. This allows callers with their own seeded random
+// provider to make reproducible selections. Returns the selected item, or an error if one occurs.
+func (c *WeightedRandomChooser[T]) ChooseWithRand(randomProvider *rand.Rand) (*T, error) {
 	// If we have no choices or 0 total weight, return nil.
 	if len(c.choices) == 0 || c.totalWeight.Cmp(big.NewInt(0)) == 0 {
 		return nil, fmt.Errorf("could not return a weighted random choice because no choices exist with non-zero weights")
@@ -90,35 +150,39 @@ func (c *WeightedRandomChooser[T]) Choose() (*T, error) {
 	defer c.randomProviderLock.Unlock()
 
 	// We'll want to randomly select a position in our total weight that will determine which item to return.
-	// If our total weight fits in an int64 and int is an int64 on this architecture, this is a quick calculation.
-	// If it's a larger number, we calculate the position with a bit more work.
+	// If our total weight fits in an int64, this is a quick calculation. If it's a larger number, we calculate the
+	// position with a bit more work.
 	var selectedWeightPosition *big.Int
-	if c.totalWeight.IsInt64() && unsafe.Sizeof(0) == 64 {
-		selectedWeightPosition = big.NewInt(int64(c.randomProvider.Intn(int(c.totalWeight.Int64()))))
+	if c.totalWeight.IsInt64() {
+		selectedWeightPosition = big.NewInt(randomProvider.Int63n(c.totalWeight.Int64()))
 	} else {
 		// Next we'll determine how many bits/bytes are needed to represent our random value
 		bitLength := c.totalWeight.BitLen()
 		byteLength := bitLength / 8
-		unusedBits := bitLength % 8
-		if unusedBits != 0 {
+		usedTopBits := bitLength % 8
+		if usedTopBits != 0 {
 			byteLength += 1
 		}
 
-		// Generate the number of bytes needed.
+		// Generate random values with the bit length of our total weight until one is in range, so every position in
+		// [0, total weight) is equally likely. As the total weight uses its top bit, each attempt is in range with a
+		// probability of at least one half.
 		randomData := make([]byte, byteLength)
-		_, err := c.randomProvider.Read(randomData)
-		if err != nil {
-			return nil, err
+		for {
+			_, err := randomProvider.Read(randomData)
+			if err != nil {
+				return nil, err
+			}
+
+			// If we have unused bits, we'll want to mask/clear them out (big.Int uses big endian for byte parsing)
+			if usedTopBits != 0 {
+				randomData[0] = randomData[0] & (byte(0xFF) >> (8 - usedTopBits))
+			}
+			selectedWeightPosition = new(big.Int).SetBytes(randomData)
+			if selectedWeightPosition.Cmp(c.totalWeight) < 0 {
+				break
+			}
 		}
-
-		// If we have unused bits, we'll want to mask/clear them out (big.Int uses big endian for byte parsing)
-		randomData[0] = randomData[0] & (byte(0xFF) >> unusedBits)
-
-		// We use these bytes to get an index in [0, total weight] to use to return an item.
-		// TODO: this may be the correct bit size but have too many bits set to actually be in range, so we perform
-		//  modulus division to wrap around. This isn't fully uniform in distribution, we should consider revisiting this.
-		selectedWeightPosition = new(big.Int).SetBytes(randomData)
-		selectedWeightPosition = new(big.Int).Mod(selectedWeightPosition, c.totalWeight)
 	}
 
 	// Loop for each item
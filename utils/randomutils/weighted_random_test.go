@@ -0,0 +1,78 @@
+package randomutils
+
+import (
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWeightedRandomChooserDistribution tests that ChooseWithRand selects choices in proportion to their weights,
+// whether the total weight fits in an int64 or not. Positions across the entire total weight must be selectable, so
+// choices placed after a small leading choice are selected most of the time.
+func TestWeightedRandomChooserDistribution(t *testing.T) {
+	// Define our test cases, where each has a small leading choice followed by a heavy one.
+	testCases := []struct {
+		name    string
+		light   *big.Int
+		heavy   *big.Int
+		minimum float64
+		maximum float64
+	}{
+		{
+			// A total weight of 127 uses 7 bits of its top byte.
+			name:    "int64 total weight",
+			light:   big.NewInt(2),
+			heavy:   big.NewInt(125),
+			minimum: 0.95,
+			maximum: 1.0,
+		},
+		{
+			// A total weight of 2^70 - 1 uses 6 bits of its top byte, and does not fit in an int64.
+			name:    "big total weight",
+			light:   new(big.Int).Lsh(big.NewInt(1), 66),
+			heavy:   new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 70), new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 66), big.NewInt(1))),
+			minimum: 0.9,
+			maximum: 0.97,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			chooser := NewWeightedRandomChooserWithRand[string](rand.New(rand.NewSource(1)), &sync.Mutex{})
+			chooser.AddChoices(
+				NewWeightedRandomChoice("light", testCase.light),
+				NewWeightedRandomChoice("heavy", testCase.heavy),
+			)
+
+			// Select our choices many times with a seeded random provider, counting how often the heavy one is chosen.
+			const selections = 10_000
+			randomProvider := rand.New(rand.NewSource(2))
+			heavySelections := 0
+			for i := 0; i < selections; i++ {
+				choice, err := chooser.ChooseWithRand(randomProvider)
+				assert.NoError(t, err)
+				if *choice == "heavy" {
+					heavySelections++
+				}
+			}
+			ratio := float64(heavySelections) / selections
+			assert.GreaterOrEqual(t, ratio, testCase.minimum)
+			assert.LessOrEqual(t, ratio, testCase.maximum)
+		})
+	}
+}
+
+// TestWeightedRandomChooserNoWeight tests that ChooseWithRand returns an error if no choices with non-zero weights
+// exist.
+func TestWeightedRandomChooserNoWeight(t *testing.T) {
+	chooser := NewWeightedRandomChooser[string]()
+	_, err := chooser.Choose()
+	assert.Error(t, err)
+
+	chooser.AddChoices(NewWeightedRandomChoice("empty", big.NewInt(0)))
+	_, err = chooser.Choose()
+	assert.Error(t, err)
+}
@@ -10,7 +10,7 @@ import (
 
 // CopyToTestDirectory copies files or directories from the provided filePath (relative to ./tests/contracts/) to an
 // ephemeral directory used for unit tests.
-func CopyToTestDirectory(t *testing.T, filePath string) string {
+func CopyToTestDirectory(t testing.TB, filePath string) string {
 	// Construct our file path relative to our working directory
 	cwd, err := os.Getwd()
 	assert.NoError(t, err)
@@ -41,7 +41,7 @@ func CopyToTestDirectory(t *testing.T, filePath string) string {
 // ExecuteInDirectory executes the given method in a given test directory. It changes the current working directory
 // to the directory specified, runs the provided method, then restores the working directory. This wraps tests so
 // any file artifacts generated do not end up in the codebase directories.
-func ExecuteInDirectory(t *testing.T, testPath string, method func()) {
+func ExecuteInDirectory(t testing.TB, testPath string, method func()) {
 	// Backup our old working directory
 	cwd, err := os.Getwd()
 	assert.NoError(t, err)